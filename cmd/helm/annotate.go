@@ -0,0 +1,141 @@
+/*
+Copyright 2017 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"k8s.io/helm/pkg/helm"
+)
+
+const annotateDesc = `
+This command changes the annotations of a release. Annotations are metadata
+recorded with a revision, such as the commit, pipeline or approver of a
+deployment. They do not affect the release's resources, and changing them
+does not create a new revision.
+
+Set an annotation with KEY=VALUE, and remove one with KEY-:
+
+    $ helm annotate happy-panda approved-by=alice ci.example.com/pipeline-
+
+The latest revision is annotated unless '--revision' is set. Annotations can
+also be recorded by 'helm install' and 'helm upgrade' with '--annotation', and
+are shown by 'helm status' and 'helm history'.
+`
+
+type annotateCmd struct {
+	name     string
+	set      map[string]string
+	remove   []string
+	revision int32
+
+	out    io.Writer
+	client helm.Interface
+}
+
+func newAnnotateCmd(c helm.Interface, out io.Writer) *cobra.Command {
+	annotate := &annotateCmd{
+		out:    out,
+		client: c,
+	}
+
+	cmd := &cobra.Command{
+		Use:               "annotate [flags] RELEASE_NAME KEY=VALUE|KEY- ...",
+		Short:             "set or remove annotations on a release",
+		Long:              annotateDesc,
+		PersistentPreRunE: setupConnection,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) == 0 {
+				return errReleaseRequired
+			}
+			if len(args) == 1 {
+				return errors.New("at least one KEY=VALUE or KEY- is required")
+			}
+			annotate.name = args[0]
+			var err error
+			if annotate.set, annotate.remove, err = parseAnnotationChanges(args[1:]); err != nil {
+				return err
+			}
+			annotate.client = ensureHelmClient(annotate.client)
+			return annotate.run()
+		},
+	}
+
+	cmd.Flags().Int32Var(&annotate.revision, "revision", 0, "the revision to annotate. Defaults to the latest revision")
+
+	return cmd
+}
+
+func (a *annotateCmd) run() error {
+	res, err := a.client.AnnotateRelease(a.name, a.set, a.remove, helm.AnnotateVersion(a.revision))
+	if err != nil {
+		return prettyError(err)
+	}
+	rel := res.GetRelease()
+	fmt.Fprintf(a.out, "release %q revision %d annotated\n", rel.Name, rel.Version)
+	for _, line := range annotationLines(rel.Info.Annotations) {
+		fmt.Fprintf(a.out, "  %s\n", line)
+	}
+	return nil
+}
+
+// parseAnnotations parses the KEY=VALUE arguments of '--annotation' flags.
+func parseAnnotations(args []string) (map[string]string, error) {
+	annotations := map[string]string{}
+	for _, arg := range args {
+		parts := strings.SplitN(arg, "=", 2)
+		if len(parts) != 2 || parts[0] == "" {
+			return nil, fmt.Errorf("invalid annotation %q: expected KEY=VALUE", arg)
+		}
+		annotations[parts[0]] = parts[1]
+	}
+	return annotations, nil
+}
+
+// parseAnnotationChanges parses the arguments of 'helm annotate': KEY=VALUE
+// sets an annotation and KEY- removes it.
+func parseAnnotationChanges(args []string) (map[string]string, []string, error) {
+	var set, remove []string
+	for _, arg := range args {
+		if !strings.Contains(arg, "=") && strings.HasSuffix(arg, "-") && len(arg) > 1 {
+			remove = append(remove, strings.TrimSuffix(arg, "-"))
+			continue
+		}
+		set = append(set, arg)
+	}
+	annotations, err := parseAnnotations(set)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%s, or KEY- to remove it", err)
+	}
+	return annotations, remove, nil
+}
+
+// annotationLines returns the annotations as sorted "key=value" lines.
+func annotationLines(annotations map[string]string) []string {
+	lines := make([]string, 0, len(annotations))
+	for k, v := range annotations {
+		lines = append(lines, k+"="+v)
+	}
+	sort.Strings(lines)
+	return lines
+}
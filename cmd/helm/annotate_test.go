@@ -0,0 +1,75 @@
+/*
+Copyright 2017 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"io"
+	"reflect"
+	"testing"
+
+	"github.com/spf13/cobra"
+)
+
+func TestAnnotate(t *testing.T) {
+	tests := []releaseCase{
+		{
+			name:     "set and remove",
+			args:     []string{"aeneas", "approved-by=alice", "git-commit=4f2c1e0", "pipeline-"},
+			expected: "release \"aeneas\" revision 2 annotated\n  approved-by=alice\n  git-commit=4f2c1e0\n",
+		},
+		{
+			name:     "value with equals sign",
+			args:     []string{"aeneas", "query=a=b"},
+			expected: "release \"aeneas\" revision 2 annotated\n  approved-by=bob\n  pipeline=41\n  query=a=b\n",
+		},
+		{
+			name: "without changes",
+			args: []string{"aeneas"},
+			err:  true,
+		},
+		{
+			name: "invalid change",
+			args: []string{"aeneas", "approved"},
+			err:  true,
+		},
+		{
+			name: "without release",
+			args: []string{},
+			err:  true,
+		},
+	}
+	runReleaseCases(t, tests, func(c *fakeReleaseClient, out io.Writer) *cobra.Command {
+		return newAnnotateCmd(c, out)
+	})
+}
+
+func TestParseAnnotations(t *testing.T) {
+	got, err := parseAnnotations([]string{"git-commit=4f2c1e0", "note=", "url=http://ci/?a=b"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expect := map[string]string{"git-commit": "4f2c1e0", "note": "", "url": "http://ci/?a=b"}
+	if !reflect.DeepEqual(got, expect) {
+		t.Errorf("expected %v, got %v", expect, got)
+	}
+
+	for _, arg := range []string{"git-commit", "=4f2c1e0"} {
+		if _, err := parseAnnotations([]string{arg}); err == nil {
+			t.Errorf("expected an error for %q", arg)
+		}
+	}
+}
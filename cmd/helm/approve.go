@@ -0,0 +1,76 @@
+/*
+Copyright 2017 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/spf13/cobra"
+
+	"k8s.io/helm/pkg/helm"
+)
+
+const approveDesc = `
+This command lets an upgrade that was run with '--require-approval' go ahead.
+Such an upgrade runs its pre-upgrade hooks, records the new revision as
+AWAITING_APPROVAL and waits. Once approved, it applies the new revision as
+usual; 'helm upgrade' returns when it is done.
+
+	$ helm approve happy-panda
+
+Use 'helm reject' to abort the upgrade instead.
+`
+
+type approveCmd struct {
+	name string
+
+	out    io.Writer
+	client helm.Interface
+}
+
+func newApproveCmd(c helm.Interface, out io.Writer) *cobra.Command {
+	approve := &approveCmd{
+		out:    out,
+		client: c,
+	}
+
+	cmd := &cobra.Command{
+		Use:               "approve [flags] RELEASE_NAME",
+		Short:             "approve an upgrade that awaits approval",
+		Long:              approveDesc,
+		PersistentPreRunE: setupConnection,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) == 0 {
+				return errReleaseRequired
+			}
+			approve.name = args[0]
+			approve.client = ensureHelmClient(approve.client)
+			return approve.run()
+		},
+	}
+	return cmd
+}
+
+func (a *approveCmd) run() error {
+	res, err := a.client.ApproveRelease(a.name)
+	if err != nil {
+		return prettyError(err)
+	}
+	fmt.Fprintf(a.out, "Approved the upgrade of %s to revision %d\n", res.Release.Name, res.Release.Version)
+	return nil
+}
@@ -32,14 +32,34 @@ It removes all of the resources associated with the last release of the chart.
 
 Use the '--dry-run' flag to see which releases will be deleted without actually
 deleting them.
+
+Resources are deleted in the reverse of the order they are installed in. The
+'--propagation-policy' flag controls what happens to the objects that
+Kubernetes created on their behalf, such as the pods of a deployment:
+'Foreground' deletes them before their owner, 'Background' deletes the owner
+first and lets Kubernetes remove them afterwards, and 'Orphan' leaves them
+running. With '--wait', Helm waits until the resources are gone, and
+'Foreground' is the default.
+
+Resources created by hooks are not part of the release, and are left behind
+unless a hook deletes them itself. With '--delete-hook-resources', Helm also
+deletes the hook resources of every revision of the release that still exist,
+such as completed Jobs, and lists them. Those with the 'keep' resource policy
+are kept.
 `
 
 type deleteCmd struct {
 	name         string
 	dryRun       bool
 	disableHooks bool
+	runHooks     bool
+	skipHooks    skipHooks
+	as           impersonation
 	purge        bool
 	timeout      int64
+	wait         bool
+	propagation  string
+	deleteHooks  bool
 
 	out    io.Writer
 	client helm.Interface
@@ -79,18 +99,35 @@ func newDeleteCmd(c helm.Interface, out io.Writer) *cobra.Command {
 	f := cmd.Flags()
 	f.BoolVar(&del.dryRun, "dry-run", false, "simulate a delete")
 	f.BoolVar(&del.disableHooks, "no-hooks", false, "prevent hooks from running during deletion")
+	f.BoolVar(&del.runHooks, "run-hooks", false, "run hooks during deletion even if Tiller skips them by default. --no-hooks takes precedence")
+	del.skipHooks.addFlags(f, "deletion")
+	del.as.addFlags(f, "deletion")
 	f.BoolVar(&del.purge, "purge", false, "remove the release from the store and make its name free for later use")
 	f.Int64Var(&del.timeout, "timeout", 300, "time in seconds to wait for any individual kubernetes operation (like Jobs for hooks)")
+	f.BoolVar(&del.wait, "wait", false, "if set, will wait until the release's resources are gone before marking the release as deleted. It will wait for as long as --timeout")
+	f.StringVar(&del.propagation, "propagation-policy", "", "how to delete the objects that depend on the release's resources. One of 'Foreground', 'Background' or 'Orphan'. Defaults to 'Foreground' with --wait")
+	f.BoolVar(&del.deleteHooks, "delete-hook-resources", false, "also delete the resources left behind by the hooks of every revision of the release, except those with the keep resource policy")
 
 	return cmd
 }
 
 func (d *deleteCmd) run() error {
+	identity, err := d.as.identity(d.client)
+	if err != nil {
+		return err
+	}
 	opts := []helm.DeleteOption{
 		helm.DeleteDryRun(d.dryRun),
 		helm.DeleteDisableHooks(d.disableHooks),
+		helm.DeleteEnableHooks(d.runHooks),
+		helm.DeleteSkipHooks(d.skipHooks.names),
+		helm.DeleteSkipHookWeights(d.skipHooks.int32Weights()),
 		helm.DeletePurge(d.purge),
 		helm.DeleteTimeout(d.timeout),
+		helm.DeleteWait(d.wait),
+		helm.DeletePropagationPolicy(d.propagation),
+		helm.DeleteImpersonation(identity),
+		helm.DeleteHookResources(d.deleteHooks),
 	}
 	res, err := d.client.DeleteRelease(d.name, opts...)
 	if res != nil && res.Info != "" {
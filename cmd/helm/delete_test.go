@@ -47,6 +47,13 @@ func TestDelete(t *testing.T) {
 			expected: "",
 			resp:     releaseMock(&releaseOptions{name: "aeneas"}),
 		},
+		{
+			name:     "delete running hooks",
+			args:     []string{"aeneas"},
+			flags:    []string{"--run-hooks"},
+			expected: "",
+			resp:     releaseMock(&releaseOptions{name: "aeneas"}),
+		},
 		{
 			name:     "purge",
 			args:     []string{"aeneas"},
@@ -54,6 +61,33 @@ func TestDelete(t *testing.T) {
 			expected: "",
 			resp:     releaseMock(&releaseOptions{name: "aeneas"}),
 		},
+		{
+			name:     "delete with propagation policy and wait",
+			args:     []string{"aeneas"},
+			flags:    []string{"--propagation-policy", "Orphan", "--wait"},
+			expected: "",
+			resp:     releaseMock(&releaseOptions{name: "aeneas"}),
+		},
+		{
+			name:     "delete with hook resources",
+			args:     []string{"aeneas"},
+			flags:    []string{"--delete-hook-resources"},
+			expected: "",
+			resp:     releaseMock(&releaseOptions{name: "aeneas"}),
+		},
+		{
+			name:     "delete as a service account",
+			args:     []string{"aeneas"},
+			flags:    []string{"--as", "system:serviceaccount:ci:deployer", "--as-group", "deployers"},
+			expected: "",
+			resp:     releaseMock(&releaseOptions{name: "aeneas"}),
+		},
+		{
+			name:  "delete as a group without a user",
+			args:  []string{"aeneas"},
+			flags: []string{"--as-group", "deployers"},
+			err:   true,
+		},
 		{
 			name: "delete without release",
 			args: []string{},
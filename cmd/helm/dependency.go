@@ -64,17 +64,24 @@ a URL.
 
 Starting from 2.2.0, repository can be defined as the path to the directory of
 the dependency charts stored locally. The path should start with a prefix of
-"file://". For example,
+"file://", or be a path starting with "/", "./" or "../". Relative paths are
+relative to the chart. For example,
 
     # requirements.yaml
     dependencies:
     - name: nginx
       version: "1.2.3"
       repository: "file://../dependency_chart/nginx"
+    - name: common
+      version: "~0.4.0"
+      repository: "../common"
 
 If the dependency chart is retrieved locally, it is not required to have the
-repository added to helm by "helm add repo". Version matching is also supported
-for this case.
+repository added to helm by "helm add repo". Its version must satisfy the
+version range. The lock file records the range rather than the version, and
+the chart is archived from its directory again on every 'helm dependency
+update' or 'helm dependency build', so changes to it are picked up without
+publishing it.
 `
 
 const dependencyListDesc = `
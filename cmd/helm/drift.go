@@ -0,0 +1,248 @@
+/*
+Copyright 2017 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/ghodss/yaml"
+	"github.com/gosuri/uitable"
+	"github.com/spf13/cobra"
+
+	"k8s.io/helm/pkg/chartutil"
+	"k8s.io/helm/pkg/helm"
+	"k8s.io/helm/pkg/proto/hapi/services"
+	"k8s.io/helm/pkg/strvals"
+)
+
+const driftDesc = `
+This command compares the resources of a release, as stored by Tiller, to
+their live counterparts in the cluster, and shows where they differ:
+
+- MODIFIED resources have fields, set by the chart, with other live values,
+  e.g. after a 'kubectl edit'. Fields that the chart does not set, and the
+  status of resources, are not compared.
+- MISSING resources are in the release, but do not exist.
+- EXTRA resources were removed from the chart by an earlier upgrade, but
+  still exist, e.g. because they were kept with 'helm upgrade --keep-removed'.
+
+The latest revision is compared, unless --revision is given. Nothing in the
+cluster or in the release is changed.
+
+Given a chart, it shows what upgrading the release to the chart would change
+instead. The chart is rendered as 'helm upgrade' would render it, with the same
+values flags, such as --reuse-values, and its resources are compared to the
+cluster:
+
+- MODIFIED resources have fields that the upgrade would set to other values.
+  Fields that the chart stops setting are not shown.
+- ADDED resources are new in the chart, and would be created.
+- MISSING resources are in the release and the chart, but do not exist, and
+  would be created again.
+- REMOVED resources are in the release, but not in the chart, and would be
+  deleted. Resources with the keep resource policy, or that another release
+  declares, would be left in place and are not shown.
+
+	$ helm drift --reuse-values --version 2.0.0 my-release stable/mariadb
+
+No hooks are run, and the release is not upgraded.
+`
+
+type driftCmd struct {
+	name         string
+	revision     int32
+	chart        string
+	valueFiles   valueFiles
+	values       []string
+	stringValues []string
+	resetValues  bool
+	reuseValues  bool
+	verify       bool
+	keyring      string
+	version      string
+	repoURL      string
+	certFile     string
+	keyFile      string
+	caFile       string
+	devel        bool
+
+	out    io.Writer
+	client helm.Interface
+}
+
+func newDriftCmd(c helm.Interface, out io.Writer) *cobra.Command {
+	drift := &driftCmd{
+		out:    out,
+		client: c,
+	}
+
+	cmd := &cobra.Command{
+		Use:               "drift [flags] RELEASE_NAME [CHART]",
+		Short:             "show how the resources of a release differ from the cluster",
+		Long:              driftDesc,
+		PersistentPreRunE: setupConnection,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) == 0 {
+				return errReleaseRequired
+			}
+			drift.name = args[0]
+			if len(args) > 1 {
+				drift.chart = args[1]
+			}
+			if drift.version == "" && drift.devel {
+				debug("setting version to >0.0.0-a")
+				drift.version = ">0.0.0-a"
+			}
+			drift.client = ensureHelmClient(drift.client)
+			return drift.run()
+		},
+	}
+
+	f := cmd.Flags()
+	f.Int32Var(&drift.revision, "revision", 0, "if set, compare the named release with revision")
+	f.VarP(&drift.valueFiles, "values", "f", "with a chart, specify values in a YAML file or a URL (can specify multiple)")
+	f.StringArrayVar(&drift.values, "set", []string{}, "with a chart, set values on the command line (can specify multiple or separate values with commas: key1=val1,key2=val2)")
+	f.StringArrayVar(&drift.stringValues, "set-string", []string{}, "with a chart, set STRING values on the command line (can specify multiple or separate values with commas: key1=val1,key2=val2)")
+	f.BoolVar(&drift.resetValues, "reset-values", false, "with a chart, reset the values to the ones built into the chart, as 'helm upgrade --reset-values' would")
+	f.BoolVar(&drift.reuseValues, "reuse-values", false, "with a chart, reuse the last release's values, and merge in any new values, as 'helm upgrade --reuse-values' would. If '--reset-values' is specified, this is ignored.")
+	f.BoolVar(&drift.verify, "verify", false, "verify the provenance of the chart before comparing it")
+	f.StringVar(&drift.keyring, "keyring", defaultKeyring(), "path to the keyring that contains public signing keys")
+	f.StringVar(&drift.version, "version", "", "specify the exact chart version to compare. If this is not specified, the latest version is used")
+	f.StringVar(&drift.repoURL, "repo", "", "chart repository url where to locate the requested chart")
+	f.StringVar(&drift.certFile, "cert-file", "", "identify HTTPS client using this SSL certificate file")
+	f.StringVar(&drift.keyFile, "key-file", "", "identify HTTPS client using this SSL key file")
+	f.StringVar(&drift.caFile, "ca-file", "", "verify certificates of HTTPS-enabled servers using this CA bundle")
+	f.BoolVar(&drift.devel, "devel", false, "use development versions, too. Equivalent to version '>0.0.0-a'. If --version is set, this is ignored.")
+
+	return cmd
+}
+
+func (d *driftCmd) run() error {
+	if d.chart != "" {
+		return d.runUpgrade()
+	}
+	res, err := d.client.ReleaseDrift(d.name, helm.DriftVersion(d.revision))
+	if err != nil {
+		return prettyError(err)
+	}
+	if !res.Drifted {
+		fmt.Fprintf(d.out, "Release %s (revision %d) matches the cluster\n", res.Name, res.Version)
+		return nil
+	}
+	fmt.Fprintln(d.out, formatDrift(res.Resources, false))
+	fmt.Fprintf(d.out, "Release %s (revision %d) has drifted from the cluster\n", res.Name, res.Version)
+	return nil
+}
+
+// runUpgrade shows what upgrading the release to the chart would change.
+func (d *driftCmd) runUpgrade() error {
+	if d.revision != 0 {
+		return errors.New("--revision cannot be used with a chart: upgrades start from the latest revision")
+	}
+	chartPath, err := locateChartPath(d.repoURL, d.chart, d.version, d.verify, d.keyring, d.certFile, d.keyFile, d.caFile)
+	if err != nil {
+		return err
+	}
+	ch, err := chartutil.Load(chartPath)
+	if err != nil {
+		return prettyError(err)
+	}
+	rawVals, err := d.vals()
+	if err != nil {
+		return err
+	}
+
+	res, err := d.client.ReleaseDrift(d.name,
+		helm.DriftChart(ch, rawVals),
+		helm.DriftResetValues(d.resetValues),
+		helm.DriftReuseValues(d.reuseValues))
+	if err != nil {
+		return prettyError(err)
+	}
+	target := ch.Metadata.Name + "-" + ch.Metadata.Version
+	if !res.Drifted {
+		fmt.Fprintf(d.out, "Upgrading release %s (revision %d) to %s would change nothing in the cluster\n", res.Name, res.Version, target)
+		return nil
+	}
+	fmt.Fprintln(d.out, formatDrift(res.Resources, true))
+	fmt.Fprintf(d.out, "Upgrading release %s (revision %d) to %s would change the resources above\n", res.Name, res.Version, target)
+	return nil
+}
+
+func (d *driftCmd) vals() ([]byte, error) {
+	base := map[string]interface{}{}
+
+	// User specified a values files via -f/--values
+	for _, filePath := range d.valueFiles {
+		currentMap := map[string]interface{}{}
+		bytes, err := readFile(filePath)
+		if err != nil {
+			return []byte{}, err
+		}
+
+		if err := yaml.Unmarshal(bytes, &currentMap); err != nil {
+			return []byte{}, fmt.Errorf("failed to parse %s: %s", filePath, err)
+		}
+		// Merge with the previous map
+		base = mergeValues(base, currentMap)
+	}
+
+	// User specified a value via --set
+	for _, value := range d.values {
+		if err := strvals.ParseInto(value, base); err != nil {
+			return []byte{}, fmt.Errorf("failed parsing --set data: %s", err)
+		}
+	}
+
+	// User specified a value via --set-string
+	for _, value := range d.stringValues {
+		if err := strvals.ParseIntoString(value, base); err != nil {
+			return []byte{}, fmt.Errorf("failed parsing --set-string data: %s", err)
+		}
+	}
+
+	return yaml.Marshal(base)
+}
+
+// formatDrift lists the resources that are not in sync, each followed by the
+// fields that differ. For an upgrade, fields are shown as the change from the
+// live value to the value the upgrade sets.
+func formatDrift(resources []*services.ResourceDrift, upgrade bool) string {
+	tbl := uitable.New()
+	tbl.MaxColWidth = 80
+	tbl.AddRow("RESOURCE", "NAMESPACE", "STATUS")
+	for _, r := range resources {
+		if r.Status == services.ResourceDrift_IN_SYNC {
+			continue
+		}
+		tbl.AddRow(r.Kind+"/"+r.Name, r.Namespace, r.Status.String())
+		for _, f := range r.Fields {
+			actual := f.Actual
+			if actual == "" {
+				actual = "<unset>"
+			}
+			if upgrade {
+				tbl.AddRow("  "+f.Path, "", fmt.Sprintf("%s -> %s", actual, f.Expected))
+				continue
+			}
+			tbl.AddRow("  "+f.Path, "", fmt.Sprintf("expected %s, got %s", f.Expected, actual))
+		}
+	}
+	return tbl.String()
+}
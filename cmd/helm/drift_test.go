@@ -0,0 +1,77 @@
+/*
+Copyright 2017 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"io"
+	"testing"
+
+	"github.com/spf13/cobra"
+)
+
+func TestDriftCmd(t *testing.T) {
+	tests := []releaseCase{
+		{
+			name: "drifted release",
+			args: []string{"aeneas"},
+			resp: releaseMock(&releaseOptions{name: "aeneas"}),
+			expected: "RESOURCE *\tNAMESPACE\tSTATUS *\n" +
+				"ConfigMap/settings\tdefault *\tMODIFIED *\n" +
+				"  data.mode *\t *\texpected \"fast\", got \"slow\"\n" +
+				"  data.level *\t *\texpected \"3\", got <unset> *\n" +
+				"Secret/creds *\tdefault *\tMISSING *\n" +
+				"Release aeneas \\(revision 2\\) has drifted from the cluster\n$",
+		},
+		{
+			name:     "release in sync",
+			args:     []string{"aeneas"},
+			flags:    []string{"--revision", "1"},
+			expected: "Release aeneas \\(revision 2\\) matches the cluster\n",
+		},
+		{
+			name:  "upgrade to a chart",
+			args:  []string{"aeneas", "testdata/testcharts/alpine"},
+			flags: []string{"--reuse-values", "--set", "name=value"},
+			resp:  releaseMock(&releaseOptions{name: "aeneas"}),
+			expected: "RESOURCE *\tNAMESPACE\tSTATUS *\n" +
+				"ConfigMap/settings\tdefault *\tMODIFIED *\n" +
+				"  data.mode *\t *\t\"slow\" -> \"fast\" *\n" +
+				"  data.level *\t *\t<unset> -> \"3\" *\n" +
+				"Secret/creds *\tdefault *\tMISSING *\n" +
+				"Upgrading release aeneas \\(revision 2\\) to alpine-0.1.0 would change the resources above\n$",
+		},
+		{
+			name:     "upgrade that changes nothing",
+			args:     []string{"aeneas", "testdata/testcharts/alpine"},
+			expected: "Upgrading release aeneas \\(revision 2\\) to alpine-0.1.0 would change nothing in the cluster\n",
+		},
+		{
+			name:  "upgrade from an older revision",
+			args:  []string{"aeneas", "testdata/testcharts/alpine"},
+			flags: []string{"--revision", "1"},
+			err:   true,
+		},
+		{
+			name: "drift without release",
+			args: []string{},
+			err:  true,
+		},
+	}
+	runReleaseCases(t, tests, func(c *fakeReleaseClient, out io.Writer) *cobra.Command {
+		return newDriftCmd(c, out)
+	})
+}
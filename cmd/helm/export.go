@@ -0,0 +1,119 @@
+/*
+Copyright 2017 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+
+	"k8s.io/helm/pkg/helm"
+)
+
+const exportDesc = `
+This command writes the resources of a release, as Tiller installed them, into
+a directory that kustomize can build: a file for each resource, and a
+kustomization.yaml that lists them in the order Tiller installs them and sets
+the namespace of the release.
+
+	$ helm export my-release
+	$ kubectl apply -k my-release
+
+The export is meant to help migrating a release away from Helm. It is one way:
+Helm cannot turn the directory back into a release, and the release is left as
+it is. Hooks are not exported, as kustomize has nothing like them; they are
+listed in kustomization.yaml so that they can be run some other way.
+Annotations that only Helm acts on, such as 'helm.sh/resource-policy', are
+removed. Labels are kept, as selectors may depend on them.
+
+The deployed revision is exported, unless --revision is given. The directory is
+named after the release, unless --output-dir is given, and must not exist yet
+or be empty.
+`
+
+type exportCmd struct {
+	name      string
+	revision  int32
+	outputDir string
+
+	out    io.Writer
+	client helm.Interface
+}
+
+func newExportCmd(c helm.Interface, out io.Writer) *cobra.Command {
+	export := &exportCmd{
+		out:    out,
+		client: c,
+	}
+
+	cmd := &cobra.Command{
+		Use:               "export [flags] RELEASE_NAME",
+		Short:             "write the resources of a release as a kustomize base",
+		Long:              exportDesc,
+		PersistentPreRunE: setupConnection,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) == 0 {
+				return errReleaseRequired
+			}
+			export.name = args[0]
+			export.client = ensureHelmClient(export.client)
+			return export.run()
+		},
+	}
+
+	f := cmd.Flags()
+	f.Int32Var(&export.revision, "revision", 0, "if set, export the named release with revision")
+	f.StringVar(&export.outputDir, "output-dir", "", "directory to write the base to. Defaults to the release name")
+
+	return cmd
+}
+
+func (e *exportCmd) run() error {
+	dir := e.outputDir
+	if dir == "" {
+		dir = e.name
+	}
+	if entries, err := ioutil.ReadDir(dir); err == nil && len(entries) > 0 {
+		return fmt.Errorf("%s is not empty", dir)
+	}
+
+	res, err := e.client.ExportRelease(e.name, helm.ExportVersion(e.revision))
+	if err != nil {
+		return prettyError(err)
+	}
+
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+	for _, f := range res.Files {
+		if err := ioutil.WriteFile(filepath.Join(dir, f.Name), []byte(f.Content), 0644); err != nil {
+			return err
+		}
+	}
+	fmt.Fprintf(e.out, "Wrote %d files to %s\n", len(res.Files), dir)
+	if len(res.OmittedHooks) > 0 {
+		fmt.Fprintf(e.out, "These hooks were not exported:\n")
+		for _, h := range res.OmittedHooks {
+			fmt.Fprintf(e.out, "  %s\n", h)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,80 @@
+/*
+Copyright 2017 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/spf13/cobra"
+)
+
+func TestExportCmd(t *testing.T) {
+	tmp, err := ioutil.TempDir("", "helm-export-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tmp)
+
+	base := filepath.Join(tmp, "base")
+	full := filepath.Join(tmp, "full")
+	if err := os.MkdirAll(full, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(filepath.Join(full, "other.yaml"), nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []releaseCase{
+		{
+			name:     "export release",
+			args:     []string{"aeneas"},
+			flags:    []string{"--output-dir", base},
+			resp:     releaseMock(&releaseOptions{name: "aeneas"}),
+			expected: "Wrote 2 files to .*base\nThese hooks were not exported:\n  pre-install Job/migrate\n",
+		},
+		{
+			name:  "export into a directory that is not empty",
+			args:  []string{"aeneas"},
+			flags: []string{"--output-dir", full},
+			resp:  releaseMock(&releaseOptions{name: "aeneas"}),
+			err:   true,
+		},
+		{
+			name: "export without release",
+			args: []string{},
+			err:  true,
+		},
+	}
+	runReleaseCases(t, tests, func(c *fakeReleaseClient, out io.Writer) *cobra.Command {
+		return newExportCmd(c, out)
+	})
+
+	b, err := ioutil.ReadFile(filepath.Join(base, "configmap-settings.yaml"))
+	if err != nil {
+		t.Fatalf("Expected the resource to be written: %s", err)
+	}
+	if string(b) != "kind: ConfigMap\nmetadata:\n  name: settings\n" {
+		t.Errorf("Unexpected content %q", b)
+	}
+	if _, err := os.Stat(filepath.Join(base, "kustomization.yaml")); err != nil {
+		t.Errorf("Expected kustomization.yaml to be written: %s", err)
+	}
+}
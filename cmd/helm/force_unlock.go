@@ -0,0 +1,94 @@
+/*
+Copyright 2017 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/spf13/cobra"
+
+	"k8s.io/helm/pkg/helm"
+)
+
+const forceUnlockDesc = `
+This command clears the lock that Tiller holds on a release while an operation
+runs on it. Use it to recover a release that can no longer be upgraded, rolled
+back or deleted because the operation that locked it will never finish.
+
+Clearing the lock does not stop an operation that is still running. Running
+another operation at the same time can leave the release in an inconsistent
+state, so check 'helm status' and 'helm history' first. To confirm, repeat the
+release name with '--confirm':
+
+    $ helm force-unlock happy-panda --confirm happy-panda
+`
+
+type forceUnlockCmd struct {
+	name    string
+	confirm string
+
+	out    io.Writer
+	client helm.Interface
+}
+
+func newForceUnlockCmd(c helm.Interface, out io.Writer) *cobra.Command {
+	unlock := &forceUnlockCmd{
+		out:    out,
+		client: c,
+	}
+
+	cmd := &cobra.Command{
+		Use:               "force-unlock [flags] RELEASE_NAME",
+		Short:             "clear the lock held on a release",
+		Long:              forceUnlockDesc,
+		PersistentPreRunE: setupConnection,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) == 0 {
+				return errReleaseRequired
+			}
+			unlock.name = args[0]
+			if unlock.confirm != unlock.name {
+				return errors.New("to clear the lock, repeat the release name with --confirm")
+			}
+			unlock.client = ensureHelmClient(unlock.client)
+			return unlock.run()
+		},
+	}
+
+	f := cmd.Flags()
+	f.StringVar(&unlock.confirm, "confirm", "", "the name of the release, to confirm that its lock should be cleared")
+
+	return cmd
+}
+
+func (u *forceUnlockCmd) run() error {
+	res, err := u.client.ForceUnlock(u.name, u.confirm)
+	if err != nil {
+		return prettyError(err)
+	}
+	if res.Warning != "" {
+		fmt.Fprintf(u.out, "WARNING: %s\n", res.Warning)
+	}
+	if res.WasLocked {
+		fmt.Fprintf(u.out, "release %q unlocked\n", u.name)
+	} else {
+		fmt.Fprintf(u.out, "release %q was not locked\n", u.name)
+	}
+	return nil
+}
@@ -0,0 +1,54 @@
+/*
+Copyright 2017 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"io"
+	"testing"
+
+	"github.com/spf13/cobra"
+)
+
+func TestForceUnlock(t *testing.T) {
+	tests := []releaseCase{
+		{
+			name:     "confirmed",
+			args:     []string{"aeneas"},
+			flags:    []string{"--confirm", "aeneas"},
+			expected: "release \"aeneas\" unlocked\n",
+		},
+		{
+			name: "not confirmed",
+			args: []string{"aeneas"},
+			err:  true,
+		},
+		{
+			name:  "confirmed with the wrong name",
+			args:  []string{"aeneas"},
+			flags: []string{"--confirm", "dido"},
+			err:   true,
+		},
+		{
+			name: "without release",
+			args: []string{},
+			err:  true,
+		},
+	}
+	runReleaseCases(t, tests, func(c *fakeReleaseClient, out io.Writer) *cobra.Command {
+		return newForceUnlockCmd(c, out)
+	})
+}
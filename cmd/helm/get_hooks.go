@@ -19,16 +19,29 @@ package main
 import (
 	"fmt"
 	"io"
+	"strings"
+	"time"
 
+	"github.com/gosuri/uitable"
 	"github.com/spf13/cobra"
 
 	"k8s.io/helm/pkg/helm"
+	"k8s.io/helm/pkg/proto/hapi/release"
+	"k8s.io/helm/pkg/timeconv"
 )
 
 const getHooksHelp = `
 This command downloads hooks for a given release.
 
 Hooks are formatted in YAML and separated by the YAML '---\n' separator.
+
+With '--results', the hooks that the operations on the revision ran or
+skipped are listed instead, in the order they ran, with when they started, how
+long they took, how many attempts they needed and how they ended. The error of
+each hook that failed follows the list, with the end of the logs of its pods
+if Tiller was started with '--wait-log-bytes', and so does the error of each
+failed attempt of hooks that were retried. Without '--revision', the
+latest revision is used, even if it failed.
 `
 
 type getHooksCmd struct {
@@ -36,6 +49,7 @@ type getHooksCmd struct {
 	out     io.Writer
 	client  helm.Interface
 	version int32
+	results bool
 }
 
 func newGetHooksCmd(client helm.Interface, out io.Writer) *cobra.Command {
@@ -57,10 +71,14 @@ func newGetHooksCmd(client helm.Interface, out io.Writer) *cobra.Command {
 		},
 	}
 	cmd.Flags().Int32Var(&ghc.version, "revision", 0, "get the named release with revision")
+	cmd.Flags().BoolVar(&ghc.results, "results", false, "list the hooks that ran and how they ended, instead of their manifests")
 	return cmd
 }
 
 func (g *getHooksCmd) run() error {
+	if g.results {
+		return g.runResults()
+	}
 	res, err := g.client.ReleaseContent(g.release, helm.ContentReleaseVersion(g.version))
 	if err != nil {
 		fmt.Fprintln(g.out, g.release)
@@ -72,3 +90,57 @@ func (g *getHooksCmd) run() error {
 	}
 	return nil
 }
+
+func (g *getHooksCmd) runResults() error {
+	res, err := g.client.ReleaseHooks(g.release, helm.HooksVersion(g.version))
+	if err != nil {
+		return prettyError(err)
+	}
+	fmt.Fprintln(g.out, formatHookExecutions(res.Executions))
+	return nil
+}
+
+func formatHookExecutions(executions []*release.HookExecution) string {
+	if len(executions) == 0 {
+		return "No hooks ran."
+	}
+	tbl := uitable.New()
+	tbl.AddRow("EVENT", "WEIGHT", "NAME", "KIND", "STARTED", "DURATION", "ATTEMPTS", "PHASE")
+	var failed []string
+	for _, e := range executions {
+		started, duration := "-", "-"
+		if e.StartedAt != nil {
+			started = timeconv.String(e.StartedAt)
+			if e.CompletedAt != nil {
+				d := timeconv.Time(e.CompletedAt).Sub(timeconv.Time(e.StartedAt))
+				duration = (d / time.Millisecond * time.Millisecond).String()
+			}
+		}
+		tbl.AddRow(hookEventName(e.Event), e.Weight, e.Name, e.Kind, started, duration, e.Attempts, e.Phase)
+		for _, a := range e.FailedAttempts {
+			failed = append(failed, hookFailure(fmt.Sprintf("%s hook %s attempt %d failed", hookEventName(e.Event), e.Name, a.Number), a.Error, a.Log))
+		}
+		if e.Phase == release.HookExecution_FAILED {
+			failed = append(failed, hookFailure(fmt.Sprintf("%s hook %s failed", hookEventName(e.Event), e.Name), e.Error, e.Log))
+		}
+	}
+	if len(failed) == 0 {
+		return tbl.String()
+	}
+	return tbl.String() + "\n\n" + strings.Join(failed, "\n\n")
+}
+
+// hookFailure describes how a hook failed, with the logs of its pods.
+func hookFailure(what, err, log string) string {
+	msg := what + ": " + err
+	if log != "" {
+		msg += "\n" + strings.TrimSuffix(log, "\n")
+	}
+	return msg
+}
+
+// hookEventName returns the name that hooks are annotated with for event,
+// e.g. "pre-install".
+func hookEventName(event release.Hook_Event) string {
+	return strings.Replace(strings.ToLower(event.String()), "_", "-", -1)
+}
@@ -19,10 +19,37 @@ package main
 import (
 	"io"
 	"testing"
+	"time"
 
 	"github.com/spf13/cobra"
+
+	"k8s.io/helm/pkg/proto/hapi/release"
+	"k8s.io/helm/pkg/timeconv"
 )
 
+func hookExecutionsMock() *release.Release {
+	rel := releaseMock(&releaseOptions{name: "aeneas"})
+	started := time.Unix(1500000000, 0)
+	rel.HookExecutions = []*release.HookExecution{
+		{Name: "skip-me", Kind: "Job", Event: release.Hook_PRE_INSTALL, Weight: -5, Phase: release.HookExecution_SKIPPED},
+		{
+			Name:        "migrate",
+			Kind:        "Job",
+			Event:       release.Hook_POST_INSTALL,
+			StartedAt:   timeconv.Timestamp(started),
+			CompletedAt: timeconv.Timestamp(started.Add(1500 * time.Millisecond)),
+			Attempts:    2,
+			Phase:       release.HookExecution_FAILED,
+			Error:       "Job failed: BackoffLimitExceeded",
+			Log:         "==> default/migrate-1/migrate <==\nmigration 42 failed\n",
+			FailedAttempts: []*release.HookAttempt{
+				{Number: 1, Error: "Job failed: DeadlineExceeded"},
+			},
+		},
+	}
+	return rel
+}
+
 func TestGetHooks(t *testing.T) {
 	tests := []releaseCase{
 		{
@@ -31,6 +58,20 @@ func TestGetHooks(t *testing.T) {
 			expected: mockHookTemplate,
 			resp:     releaseMock(&releaseOptions{name: "aeneas"}),
 		},
+		{
+			name:     "get hooks results without executions",
+			args:     []string{"aeneas"},
+			flags:    []string{"--results"},
+			expected: "No hooks ran.",
+			resp:     releaseMock(&releaseOptions{name: "aeneas"}),
+		},
+		{
+			name:     "get hooks results",
+			args:     []string{"aeneas"},
+			flags:    []string{"--results"},
+			expected: `EVENT       \tWEIGHT\tNAME   \tKIND\tSTARTED                 \tDURATION\tATTEMPTS\tPHASE\s*\npre-install \t-5    \tskip-me\tJob \t-                       \t-       \t0       \tSKIPPED\s*\npost-install\t0     \tmigrate\tJob \t.+\t1.5s    \t2       \tFAILED\s*\n\npost-install hook migrate attempt 1 failed: Job failed: DeadlineExceeded\n\npost-install hook migrate failed: Job failed: BackoffLimitExceeded\n==> default/migrate-1/migrate <==\nmigration 42 failed\n`,
+			resp:     hookExecutionsMock(),
+		},
 		{
 			name: "get hooks without args",
 			args: []string{},
@@ -0,0 +1,178 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/ghodss/yaml"
+	"github.com/spf13/cobra"
+
+	"k8s.io/helm/pkg/chartutil"
+	"k8s.io/helm/pkg/helm"
+	"k8s.io/helm/pkg/strvals"
+)
+
+const getLabelsHelp = `
+This command shows the labels and annotations that mark the resources of a
+release, for selecting them with tools such as kubectl.
+
+Tiller applies resources exactly as the chart renders them, without labels or
+annotations of its own. The common ones are what every resource of the release
+carries, and the common labels can be used as a selector:
+
+    $ kubectl get all -l $(helm get labels --selector happy-panda)
+
+Given a chart, it previews them for a release that is not installed yet: the
+chart is rendered as 'helm install' would render it for the release name and
+'--namespace', with the same values flags, and nothing is installed.
+
+    $ helm get labels --namespace team-a happy-panda stable/mariadb
+
+Hooks are not taken into account. Without '--revision', the latest revision
+is used.
+`
+
+type getLabelsCmd struct {
+	release      string
+	chart        string
+	namespace    string
+	valueFiles   valueFiles
+	values       []string
+	stringValues []string
+	chartVersion string
+	out          io.Writer
+	client       helm.Interface
+	version      int32
+	selector     bool
+}
+
+func newGetLabelsCmd(client helm.Interface, out io.Writer) *cobra.Command {
+	glc := &getLabelsCmd{
+		out:    out,
+		client: client,
+	}
+	cmd := &cobra.Command{
+		Use:   "labels [flags] RELEASE_NAME [CHART]",
+		Short: "show the labels and annotations that mark the resources of a named release",
+		Long:  getLabelsHelp,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) == 0 {
+				return errReleaseRequired
+			}
+			glc.release = args[0]
+			if len(args) > 1 {
+				glc.chart = args[1]
+			}
+			glc.client = ensureHelmClient(glc.client)
+			return glc.run()
+		},
+	}
+	f := cmd.Flags()
+	f.Int32Var(&glc.version, "revision", 0, "get the named release with revision")
+	f.BoolVar(&glc.selector, "selector", false, "only print the common labels, as a label selector")
+	f.StringVar(&glc.namespace, "namespace", "", "with a chart, namespace to preview the release in")
+	f.VarP(&glc.valueFiles, "values", "f", "with a chart, specify values in a YAML file or a URL (can specify multiple)")
+	f.StringArrayVar(&glc.values, "set", []string{}, "with a chart, set values on the command line (can specify multiple or separate values with commas: key1=val1,key2=val2)")
+	f.StringArrayVar(&glc.stringValues, "set-string", []string{}, "with a chart, set STRING values on the command line (can specify multiple or separate values with commas: key1=val1,key2=val2)")
+	f.StringVar(&glc.chartVersion, "version", "", "with a chart, specify the exact chart version to preview. If this is not specified, the latest version is used")
+	return cmd
+}
+
+func (g *getLabelsCmd) run() error {
+	opts := []helm.LabelsOption{helm.LabelsVersion(g.version)}
+	if g.chart != "" {
+		if g.version != 0 {
+			return errors.New("--revision cannot be used with a chart: the chart is previewed as a new release")
+		}
+		chartPath, err := locateChartPath("", g.chart, g.chartVersion, false, defaultKeyring(), "", "", "")
+		if err != nil {
+			return err
+		}
+		ch, err := chartutil.Load(chartPath)
+		if err != nil {
+			return prettyError(err)
+		}
+		rawVals, err := g.vals()
+		if err != nil {
+			return err
+		}
+		opts = append(opts, helm.LabelsChart(ch, rawVals, g.namespace))
+	}
+	res, err := g.client.ReleaseLabels(g.release, opts...)
+	if err != nil {
+		return prettyError(err)
+	}
+	if g.selector {
+		fmt.Fprintln(g.out, formatStringMap(res.CommonLabels))
+		return nil
+	}
+	fmt.Fprintf(g.out, "RESOURCES: %d\n", res.Resources)
+	fmt.Fprintf(g.out, "COMMON LABELS: %s\n", formatStringMap(res.CommonLabels))
+	fmt.Fprintf(g.out, "COMMON ANNOTATIONS: %s\n", formatStringMap(res.CommonAnnotations))
+	return nil
+}
+
+func (g *getLabelsCmd) vals() ([]byte, error) {
+	base := map[string]interface{}{}
+
+	// User specified a values files via -f/--values
+	for _, filePath := range g.valueFiles {
+		currentMap := map[string]interface{}{}
+		bytes, err := readFile(filePath)
+		if err != nil {
+			return []byte{}, err
+		}
+
+		if err := yaml.Unmarshal(bytes, &currentMap); err != nil {
+			return []byte{}, fmt.Errorf("failed to parse %s: %s", filePath, err)
+		}
+		// Merge with the previous map
+		base = mergeValues(base, currentMap)
+	}
+
+	// User specified a value via --set
+	for _, value := range g.values {
+		if err := strvals.ParseInto(value, base); err != nil {
+			return []byte{}, fmt.Errorf("failed parsing --set data: %s", err)
+		}
+	}
+
+	// User specified a value via --set-string
+	for _, value := range g.stringValues {
+		if err := strvals.ParseIntoString(value, base); err != nil {
+			return []byte{}, fmt.Errorf("failed parsing --set-string data: %s", err)
+		}
+	}
+
+	return yaml.Marshal(base)
+}
+
+// formatStringMap returns m as comma-separated key=value pairs, sorted by
+// key.
+func formatStringMap(m map[string]string) string {
+	pairs := make([]string, 0, len(m))
+	for k, v := range m {
+		pairs = append(pairs, k+"="+v)
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, ",")
+}
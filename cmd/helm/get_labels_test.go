@@ -0,0 +1,63 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"io"
+	"testing"
+
+	"github.com/spf13/cobra"
+)
+
+func TestGetLabels(t *testing.T) {
+	tests := []releaseCase{
+		{
+			name:     "get labels with release",
+			args:     []string{"aeneas"},
+			expected: "RESOURCES: 2\nCOMMON LABELS: heritage=Tiller,release=aeneas\nCOMMON ANNOTATIONS: \n",
+			resp:     releaseMock(&releaseOptions{name: "aeneas"}),
+		},
+		{
+			name:     "preview labels with a chart",
+			args:     []string{"aeneas", "testdata/testcharts/alpine"},
+			flags:    []string{"--namespace", "team-a", "--set", "foo=bar"},
+			expected: "RESOURCES: 2\nCOMMON LABELS: heritage=Tiller,release=aeneas\nCOMMON ANNOTATIONS: \n",
+			resp:     releaseMock(&releaseOptions{name: "aeneas"}),
+		},
+		{
+			name:  "preview labels of a revision",
+			args:  []string{"aeneas", "testdata/testcharts/alpine"},
+			flags: []string{"--revision", "2"},
+			err:   true,
+		},
+		{
+			name:     "get labels as a selector",
+			args:     []string{"aeneas"},
+			flags:    []string{"--selector"},
+			expected: "heritage=Tiller,release=aeneas\n",
+			resp:     releaseMock(&releaseOptions{name: "aeneas"}),
+		},
+		{
+			name: "get labels without args",
+			args: []string{},
+			err:  true,
+		},
+	}
+	runReleaseCases(t, tests, func(c *fakeReleaseClient, out io.Writer) *cobra.Command {
+		return newGetLabelsCmd(c, out)
+	})
+}
@@ -21,6 +21,8 @@ import (
 	"io"
 
 	"github.com/spf13/cobra"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
 
 	"k8s.io/helm/pkg/helm"
 )
@@ -67,10 +69,20 @@ func newGetManifestCmd(client helm.Interface, out io.Writer) *cobra.Command {
 
 // getManifest implements 'helm get manifest'
 func (g *getManifestCmd) run() error {
-	res, err := g.client.ReleaseContent(g.release, helm.ContentReleaseVersion(g.version))
+	res, err := g.client.ReleaseManifest(g.release, helm.ContentReleaseVersion(g.version))
+	if grpc.Code(err) == codes.Unimplemented {
+		// Older Tillers can only return the whole release.
+		debug("server does not support GetManifest, falling back to GetReleaseContent")
+		content, err := g.client.ReleaseContent(g.release, helm.ContentReleaseVersion(g.version))
+		if err != nil {
+			return prettyError(err)
+		}
+		fmt.Fprintln(g.out, content.Release.Manifest)
+		return nil
+	}
 	if err != nil {
 		return prettyError(err)
 	}
-	fmt.Fprintln(g.out, res.Release.Manifest)
+	fmt.Fprintln(g.out, res.Manifest)
 	return nil
 }
@@ -28,6 +28,11 @@ import (
 
 var getValuesHelp = `
 This command downloads a values file for a given release.
+
+With '--all', the values are merged with the chart's default values. If Tiller
+stores the values each revision was rendered with (see 'tiller
+--store-computed-values'), that snapshot is shown instead, so the output does
+not depend on later changes to how defaults are merged.
 `
 
 type getValuesCmd struct {
@@ -69,8 +74,13 @@ func (g *getValuesCmd) run() error {
 		return prettyError(err)
 	}
 
-	// If the user wants all values, compute the values and return.
+	// If the user wants all values, prefer the snapshot Tiller stored for the
+	// revision, or compute the values and return.
 	if g.allValues {
+		if computed := res.Release.ComputedValues; computed != nil {
+			fmt.Fprintln(g.out, computed.Raw)
+			return nil
+		}
 		cfg, err := chartutil.CoalesceValues(res.Release.Chart, res.Release.Config)
 		if err != nil {
 			return err
@@ -21,8 +21,17 @@ import (
 	"testing"
 
 	"github.com/spf13/cobra"
+
+	"k8s.io/helm/pkg/proto/hapi/chart"
+	"k8s.io/helm/pkg/proto/hapi/release"
 )
 
+func computedReleaseMock(name, computed string) *release.Release {
+	rel := releaseMock(&releaseOptions{name: name})
+	rel.ComputedValues = &chart.Config{Raw: computed}
+	return rel
+}
+
 func TestGetValuesCmd(t *testing.T) {
 	tests := []releaseCase{
 		{
@@ -31,6 +40,20 @@ func TestGetValuesCmd(t *testing.T) {
 			args:     []string{"thomas-guide"},
 			expected: "name: \"value\"",
 		},
+		{
+			name:     "get all values without a snapshot",
+			resp:     releaseMock(&releaseOptions{name: "thomas-guide"}),
+			args:     []string{"thomas-guide"},
+			flags:    []string{"--all"},
+			expected: "name: value",
+		},
+		{
+			name:     "get all values from the stored snapshot",
+			resp:     computedReleaseMock("thomas-guide", "name: value\nreplicas: 3\n"),
+			args:     []string{"thomas-guide"},
+			flags:    []string{"--all"},
+			expected: "name: value\nreplicas: 3",
+		},
 		{
 			name: "get values requires release name arg",
 			err:  true,
@@ -125,16 +125,28 @@ func newRootCmd(out io.Writer) *cobra.Command {
 		newVerifyCmd(out),
 
 		// release commands
+		addFlagsTLS(newAnnotateCmd(nil, out)),
+		addFlagsTLS(newApproveCmd(nil, out)),
 		addFlagsTLS(newDeleteCmd(nil, out)),
+		addFlagsTLS(newDriftCmd(nil, out)),
+		addFlagsTLS(newExportCmd(nil, out)),
+		addFlagsTLS(newForceUnlockCmd(nil, out)),
 		addFlagsTLS(newGetCmd(nil, out)),
 		addFlagsTLS(newHistoryCmd(nil, out)),
 		addFlagsTLS(newInstallCmd(nil, out)),
+		addFlagsTLS(newInvalidateDiscoveryCmd(nil, out)),
 		addFlagsTLS(newListCmd(nil, out)),
+		addFlagsTLS(newRejectCmd(nil, out)),
+		addFlagsTLS(newRestartCmd(nil, out)),
+		addFlagsTLS(newRestoreCmd(nil, out)),
+		addFlagsTLS(newResumeCmd(nil, out)),
 		addFlagsTLS(newRollbackCmd(nil, out)),
+		addFlagsTLS(newSnapshotCmd(nil, out)),
 		addFlagsTLS(newStatusCmd(nil, out)),
 		addFlagsTLS(newUpgradeCmd(nil, out)),
 
 		addFlagsTLS(newReleaseTestCmd(nil, out)),
+		addFlagsTLS(newMigrationStatusCmd(nil, out)),
 		addFlagsTLS(newResetCmd(nil, out)),
 		addFlagsTLS(newVersionCmd(nil, out)),
 
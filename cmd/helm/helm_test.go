@@ -126,6 +126,14 @@ type fakeReleaseClient struct {
 	rels      []*release.Release
 	responses map[string]release.TestRun_Status
 	err       error
+	// version is returned by GetVersion, if set.
+	version *rls.GetVersionResponse
+	// migration is returned by MigrationStatus, if set.
+	migration *rls.MigrationStatusResponse
+	// rollback is returned by RollbackRelease, if set.
+	rollback *rls.RollbackReleaseResponse
+	// waitLogs are streamed by WatchWaitLogs.
+	waitLogs []string
 }
 
 var _ helm.Interface = &fakeReleaseClient{}
@@ -158,15 +166,20 @@ func (c *fakeReleaseClient) DeleteRelease(rlsName string, opts ...helm.DeleteOpt
 func (c *fakeReleaseClient) ReleaseStatus(rlsName string, opts ...helm.StatusOption) (*rls.GetReleaseStatusResponse, error) {
 	if c.rels[0] != nil {
 		return &rls.GetReleaseStatusResponse{
-			Name:      c.rels[0].Name,
-			Info:      c.rels[0].Info,
-			Namespace: c.rels[0].Namespace,
+			Name:       c.rels[0].Name,
+			Info:       c.rels[0].Info,
+			Namespace:  c.rels[0].Namespace,
+			Namespaces: c.rels[0].Namespaces,
+			Cluster:    c.rels[0].Cluster,
 		}, nil
 	}
 	return nil, fmt.Errorf("No such release: %s", rlsName)
 }
 
 func (c *fakeReleaseClient) GetVersion(opts ...helm.VersionOption) (*rls.GetVersionResponse, error) {
+	if c.version != nil {
+		return c.version, nil
+	}
 	return &rls.GetVersionResponse{
 		Version: &version.Version{
 			SemVer: "1.2.3-fakeclient+testonly",
@@ -174,6 +187,148 @@ func (c *fakeReleaseClient) GetVersion(opts ...helm.VersionOption) (*rls.GetVers
 	}, nil
 }
 
+func (c *fakeReleaseClient) GetHealth(opts ...helm.HealthOption) (*rls.GetHealthResponse, error) {
+	return &rls.GetHealthResponse{Status: rls.DependencyHealth_OK}, nil
+}
+
+func (c *fakeReleaseClient) MigrationStatus(opts ...helm.MigrationStatusOption) (*rls.MigrationStatusResponse, error) {
+	if c.migration != nil {
+		return c.migration, nil
+	}
+	return &rls.MigrationStatusResponse{}, nil
+}
+
+func (c *fakeReleaseClient) ForceUnlock(rlsName, confirm string, opts ...helm.ForceUnlockOption) (*rls.ForceUnlockResponse, error) {
+	if confirm != rlsName {
+		return nil, fmt.Errorf("force unlock of %s not confirmed", rlsName)
+	}
+	return &rls.ForceUnlockResponse{WasLocked: true}, nil
+}
+
+func (c *fakeReleaseClient) BatchInstall(releases []*rls.InstallReleaseRequest, opts ...helm.BatchInstallOption) (*rls.BatchInstallResponse, error) {
+	return nil, nil
+}
+
+func (c *fakeReleaseClient) RestoreRelease(rlsName string, opts ...helm.RestoreOption) (*rls.RestoreReleaseResponse, error) {
+	return &rls.RestoreReleaseResponse{Release: releaseMock(&releaseOptions{name: rlsName, version: 3})}, nil
+}
+
+func (c *fakeReleaseClient) ResumeRelease(rlsName string, opts ...helm.ResumeOption) (*rls.ResumeReleaseResponse, error) {
+	return &rls.ResumeReleaseResponse{Release: releaseMock(&releaseOptions{name: rlsName, version: 4})}, nil
+}
+
+func (c *fakeReleaseClient) SnapshotRelease(rlsName string, opts ...helm.SnapshotOption) (*rls.SnapshotReleaseResponse, error) {
+	rel := releaseMock(&releaseOptions{name: rlsName})
+	rel.Snapshot = &release.Snapshot{Taken: rel.Info.LastDeployed, Missing: []string{"Service/web"}}
+	return &rls.SnapshotReleaseResponse{Release: rel}, nil
+}
+
+func (c *fakeReleaseClient) ReleaseHooks(rlsName string, opts ...helm.HooksOption) (resp *rls.GetHooksResponse, err error) {
+	if len(c.rels) > 0 {
+		resp = &rls.GetHooksResponse{
+			Name:       c.rels[0].Name,
+			Version:    c.rels[0].Version,
+			Executions: c.rels[0].HookExecutions,
+		}
+	}
+	return resp, c.err
+}
+
+func (c *fakeReleaseClient) ReleaseLabels(rlsName string, opts ...helm.LabelsOption) (resp *rls.GetReleaseLabelsResponse, err error) {
+	if len(c.rels) > 0 {
+		resp = &rls.GetReleaseLabelsResponse{
+			Name:         c.rels[0].Name,
+			Version:      c.rels[0].Version,
+			CommonLabels: map[string]string{"heritage": "Tiller", "release": c.rels[0].Name},
+			Resources:    2,
+		}
+	}
+	return resp, c.err
+}
+
+func (c *fakeReleaseClient) InvalidateDiscoveryCache(opts ...helm.DiscoveryCacheOption) (*rls.InvalidateDiscoveryCacheResponse, error) {
+	return &rls.InvalidateDiscoveryCacheResponse{Entries: 1}, c.err
+}
+
+func (c *fakeReleaseClient) InspectChart(chStr string, opts ...helm.InspectOption) (*rls.InspectChartResponse, error) {
+	return nil, nil
+}
+
+func (c *fakeReleaseClient) InspectChartFromChart(chart *chart.Chart, opts ...helm.InspectOption) (*rls.InspectChartResponse, error) {
+	return nil, nil
+}
+
+func (c *fakeReleaseClient) AnnotateRelease(rlsName string, annotations map[string]string, remove []string, opts ...helm.AnnotateOption) (*rls.AnnotateReleaseResponse, error) {
+	rel := releaseMock(&releaseOptions{name: rlsName, version: 2})
+	rel.Info.Annotations = map[string]string{"approved-by": "bob", "pipeline": "41"}
+	for k, v := range annotations {
+		rel.Info.Annotations[k] = v
+	}
+	for _, k := range remove {
+		delete(rel.Info.Annotations, k)
+	}
+	return &rls.AnnotateReleaseResponse{Release: rel}, nil
+}
+
+func (c *fakeReleaseClient) RestartRelease(rlsName string, opts ...helm.RestartOption) (*rls.RestartReleaseResponse, error) {
+	return &rls.RestartReleaseResponse{
+		Release:   releaseMock(&releaseOptions{name: rlsName, version: 2}),
+		Restarted: []string{"Deployment/web", "StatefulSet/db"},
+	}, nil
+}
+
+func (c *fakeReleaseClient) ApproveRelease(rlsName string, opts ...helm.ApproveOption) (*rls.ApproveReleaseResponse, error) {
+	rel := releaseMock(&releaseOptions{name: rlsName, version: 2, statusCode: release.Status_AWAITING_APPROVAL})
+	return &rls.ApproveReleaseResponse{Release: rel}, nil
+}
+
+func (c *fakeReleaseClient) RejectRelease(rlsName, reason string, opts ...helm.RejectOption) (*rls.RejectReleaseResponse, error) {
+	rel := releaseMock(&releaseOptions{name: rlsName, version: 2, statusCode: release.Status_AWAITING_APPROVAL})
+	return &rls.RejectReleaseResponse{Release: rel}, nil
+}
+
+func (c *fakeReleaseClient) ExportRelease(rlsName string, opts ...helm.ExportOption) (*rls.ExportReleaseResponse, error) {
+	for _, r := range c.rels {
+		if r == nil || r.Name != rlsName {
+			continue
+		}
+		return &rls.ExportReleaseResponse{
+			Files: []*rls.ExportedFile{
+				{Name: "kustomization.yaml", Content: "kind: Kustomization\nresources:\n- configmap-settings.yaml\n"},
+				{Name: "configmap-settings.yaml", Content: "kind: ConfigMap\nmetadata:\n  name: settings\n"},
+			},
+			OmittedHooks: []string{"pre-install Job/migrate"},
+		}, nil
+	}
+	return nil, fmt.Errorf("release: %q not found", rlsName)
+}
+
+func (c *fakeReleaseClient) ReleaseDrift(rlsName string, opts ...helm.DriftOption) (*rls.GetReleaseDriftResponse, error) {
+	res := &rls.GetReleaseDriftResponse{Name: rlsName, Version: 2}
+	for _, r := range c.rels {
+		if r == nil || r.Name != rlsName {
+			continue
+		}
+		res.Resources = []*rls.ResourceDrift{
+			{
+				ApiVersion: "v1",
+				Kind:       "ConfigMap",
+				Name:       "settings",
+				Namespace:  "default",
+				Status:     rls.ResourceDrift_MODIFIED,
+				Fields: []*rls.FieldDrift{
+					{Path: "data.mode", Expected: `"fast"`, Actual: `"slow"`},
+					{Path: "data.level", Expected: `"3"`},
+				},
+			},
+			{ApiVersion: "v1", Kind: "Secret", Name: "creds", Namespace: "default", Status: rls.ResourceDrift_MISSING},
+			{ApiVersion: "v1", Kind: "Service", Name: "web", Namespace: "default"},
+		}
+		res.Drifted = true
+	}
+	return res, nil
+}
+
 func (c *fakeReleaseClient) UpdateRelease(rlsName string, chStr string, opts ...helm.UpdateOption) (*rls.UpdateReleaseResponse, error) {
 	return nil, nil
 }
@@ -183,7 +338,14 @@ func (c *fakeReleaseClient) UpdateReleaseFromChart(rlsName string, chart *chart.
 }
 
 func (c *fakeReleaseClient) RollbackRelease(rlsName string, opts ...helm.RollbackOption) (*rls.RollbackReleaseResponse, error) {
-	return nil, nil
+	return c.rollback, nil
+}
+
+func (c *fakeReleaseClient) ReleaseManifest(rlsName string, opts ...helm.ContentOption) (*rls.GetManifestResponse, error) {
+	if len(c.rels) > 0 {
+		return &rls.GetManifestResponse{Version: c.rels[0].Version, Manifest: c.rels[0].Manifest}, nil
+	}
+	return nil, fmt.Errorf("No such release: %s", rlsName)
 }
 
 func (c *fakeReleaseClient) ReleaseContent(rlsName string, opts ...helm.ContentOption) (resp *rls.GetReleaseContentResponse, err error) {
@@ -223,6 +385,17 @@ func (c *fakeReleaseClient) RunReleaseTest(rlsName string, opts ...helm.ReleaseT
 	return results, errc
 }
 
+func (c *fakeReleaseClient) WatchWaitLogs(rlsName string) (<-chan *rls.WatchWaitLogsResponse, <-chan error) {
+	logs := make(chan *rls.WatchWaitLogsResponse, len(c.waitLogs))
+	for _, l := range c.waitLogs {
+		logs <- &rls.WatchWaitLogsResponse{Logs: l}
+	}
+	close(logs)
+	errc := make(chan error)
+	close(errc)
+	return logs, errc
+}
+
 func (c *fakeReleaseClient) Option(opt ...helm.Option) helm.Interface {
 	return c
 }
@@ -19,6 +19,7 @@ package main
 import (
 	"fmt"
 	"io"
+	"strings"
 
 	"github.com/gosuri/uitable"
 	"github.com/spf13/cobra"
@@ -94,7 +95,16 @@ func (cmd *historyCmd) run() error {
 func formatHistory(rls []*release.Release) string {
 	tbl := uitable.New()
 	tbl.MaxColWidth = 60
-	tbl.AddRow("REVISION", "UPDATED", "STATUS", "CHART", "DESCRIPTION")
+	// Annotations are only shown if a revision has any.
+	annotated := false
+	for _, r := range rls {
+		annotated = annotated || len(r.Info.Annotations) > 0
+	}
+	if annotated {
+		tbl.AddRow("REVISION", "UPDATED", "STATUS", "CHART", "DESCRIPTION", "ANNOTATIONS")
+	} else {
+		tbl.AddRow("REVISION", "UPDATED", "STATUS", "CHART", "DESCRIPTION")
+	}
 	for i := len(rls) - 1; i >= 0; i-- {
 		r := rls[i]
 		c := formatChartname(r.Chart)
@@ -102,7 +112,11 @@ func formatHistory(rls []*release.Release) string {
 		s := r.Info.Status.Code.String()
 		v := r.Version
 		d := r.Info.Description
-		tbl.AddRow(v, t, s, c, d)
+		if annotated {
+			tbl.AddRow(v, t, s, c, d, strings.Join(annotationLines(r.Info.Annotations), ", "))
+		} else {
+			tbl.AddRow(v, t, s, c, d)
+		}
 	}
 	return tbl.String()
 }
@@ -62,6 +62,17 @@ func TestHistoryCmd(t *testing.T) {
 			},
 			xout: "REVISION\tUPDATED                 \tSTATUS    \tCHART           \tDESCRIPTION \n3       \t(.*)\tSUPERSEDED\tfoo-0.1.0-beta.1\tRelease mock\n4       \t(.*)\tDEPLOYED  \tfoo-0.1.0-beta.1\tRelease mock\n",
 		},
+		{
+			cmds: "helm history RELEASE_NAME",
+			desc: "get history with annotations",
+			args: []string{"angry-bird"},
+			resp: func() []*rpb.Release {
+				r := mk("angry-bird", 2, rpb.Status_DEPLOYED)
+				r.Info.Annotations = map[string]string{"git-commit": "4f2c1e0", "approved-by": "alice"}
+				return []*rpb.Release{r, mk("angry-bird", 1, rpb.Status_SUPERSEDED)}
+			}(),
+			xout: "REVISION\tUPDATED                 \tSTATUS    \tCHART           \tDESCRIPTION \tANNOTATIONS                          \n1       \t(.*)\tSUPERSEDED\tfoo-0.1.0-beta.1\tRelease mock\t                                     \n2       \t(.*)\tDEPLOYED  \tfoo-0.1.0-beta.1\tRelease mock\tapproved-by=alice, git-commit=4f2c1e0\n",
+		},
 	}
 
 	var buf bytes.Buffer
@@ -0,0 +1,55 @@
+/*
+Copyright 2017 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"errors"
+
+	"github.com/spf13/pflag"
+
+	"k8s.io/helm/pkg/helm"
+	"k8s.io/helm/pkg/proto/hapi/release"
+	"k8s.io/helm/pkg/version"
+)
+
+// impersonation holds the flags that make Tiller act as another identity
+// towards the Kubernetes API. It is shared by the commands that change the
+// resources of a release.
+type impersonation struct {
+	user   string
+	groups []string
+}
+
+func (i *impersonation) addFlags(f *pflag.FlagSet, operation string) {
+	f.StringVar(&i.user, "as", "", "user for Tiller to act as towards Kubernetes during "+operation+", such as system:serviceaccount:NAMESPACE:NAME for a service account. Tiller must be allowed to impersonate it. The identity is not checked against the caller")
+	f.StringArrayVar(&i.groups, "as-group", []string{}, "group for Tiller to act as during "+operation+", with --as (can specify multiple)")
+}
+
+// identity returns the identity in the form the API expects, or nil if none
+// is set. It fails if Tiller, which c talks to, would ignore the identity.
+func (i *impersonation) identity(c helm.Interface) (*release.Impersonation, error) {
+	if i.user == "" {
+		if len(i.groups) > 0 {
+			return nil, errors.New("--as-group requires --as")
+		}
+		return nil, nil
+	}
+	if err := checkServerFeature(c, version.FeatureImpersonation, "impersonation"); err != nil {
+		return nil, err
+	}
+	return &release.Impersonation{User: i.user, Groups: i.groups}, nil
+}
@@ -21,7 +21,6 @@ import (
 	"errors"
 	"fmt"
 	"io"
-	"io/ioutil"
 	"os"
 	"path/filepath"
 	"strings"
@@ -41,6 +40,7 @@ import (
 	"k8s.io/helm/pkg/proto/hapi/release"
 	"k8s.io/helm/pkg/repo"
 	"k8s.io/helm/pkg/strvals"
+	"k8s.io/helm/pkg/version"
 )
 
 const installDesc = `
@@ -64,20 +64,116 @@ contained a key called 'Test', the value set in override.yaml would take precede
 
 	$ helm install -f myvalues.yaml -f override.yaml ./redis
 
+A values file may also be given as a URL. It is downloaded before anything is
+sent to Tiller, using the same getters as 'helm fetch', so http(s) works out of
+the box and other schemes (such as s3://) can be added with downloader plugins.
+Remote files take their place in the '-f' order like any local file:
+
+	$ helm install -f https://example.com/values/prod.yaml -f override.yaml ./redis
+
 You can specify the '--set' flag multiple times. The priority will be given to the
 last (right-most) set specified. For example, if both 'bar' and 'newbar' values are
 set for a key called 'foo', the 'newbar' value would take precedence:
 
 	$ helm install --set foo=bar --set foo=newbar ./redis
 
+The '--set' flag turns values such as 'true' and '1' into booleans and
+numbers. The '--set-string' flag takes the same syntax and keeps every value a
+string. Its values take precedence over those of '--set':
+
+	$ helm install --set-string image.tag=1.10 ./redis
+
+A chart may hold values for each environment it is deployed to, in files named
+values-<profile>.yaml next to its values.yaml. The '--profile' flag merges the
+values of one of them over the chart's defaults, and under the values given
+with '-f' and '--set'. A chart without a file for the profile fails the
+install, unless '--allow-missing-profile' is set:
+
+	$ helm install --profile prod ./redis
+
 
 To check the generated manifests of a release without installing the chart,
 the '--debug' and '--dry-run' flags can be combined. This will still require a
 round-trip to the Tiller server.
 
+The '--server-dry-run' flag goes one step further: the rendered manifests are
+sent to the API server as a server-side dry-run, and the objects are printed as
+the server would store them, with defaults applied and admission controllers
+run. Nothing is persisted. This requires Kubernetes 1.13 or later, and hooks
+are not included.
+
+A dry run also lists the hooks the install would run. To show which hooks would
+replace a resource left behind by an earlier run, it asks the cluster,
+read-only, whether the resources of hooks with the 'before-hook-creation'
+delete policy exist. '--skip-hook-lookup' leaves the cluster alone.
+
 If --verify is set, the chart MUST have a provenance file, and the provenenace
 fall MUST pass all verification steps.
 
+The '--verify-images' flag makes Tiller check, before installing anything, that
+the registries have every container image the release uses, so that a missing
+image fails the install instead of leaving pods in ImagePullBackOff. Tiller logs
+in with the image pull secrets of the pods and their service accounts, so it
+needs network access to the registries.
+
+The '--verify-references' flag makes Tiller check, before installing anything,
+that the ServiceAccounts, Secrets and ConfigMaps that the pods of the release
+refer to are part of the release or already exist, and lists those that do not.
+References marked optional are not checked.
+
+The '--strict-values' flag makes Tiller reject values whose keys match nothing
+in the default values or values schemas of the chart and its subcharts, such
+as a misspelled key that would otherwise be ignored. All such keys are listed
+at once. Charts that read keys of the user's choosing may need their defaults
+to hold an empty table for them.
+
+'--as' makes Tiller act as another user towards Kubernetes while it installs
+the release, so that RBAC rules and audit logs apply to that user instead of
+to Tiller. Give a service account as 'system:serviceaccount:NAMESPACE:NAME',
+and the user's groups with '--as-group'. Tiller's own service account must be
+allowed to impersonate them; if it is not, the install fails before anything
+is created. The identity is recorded with the revision. Tiller does not check
+that the caller may act as the identity; only Tiller's own permissions limit it.
+
+'--flag' sets a feature flag that templates can branch on, for example
+'--flag canary' for '{{ if .Release.Flags.canary }}'. Flags are not values:
+they are recorded with the revision, but only apply to this install, and are
+not kept by later upgrades, even with '--reuse-values'. Flags that are not
+set are false.
+
+NOTES.txt is rendered with the rest of the chart, before anything exists in
+the cluster. '--defer-notes' renders it again once the resources are created
+and, with '--wait', ready, with '.Live' holding their live state, so that the
+notes can print values that only the cluster knows, such as an address it
+assigned. If the live state cannot be read, the notes rendered first are kept.
+
+Before installing anything, Tiller checks the names and labels of the rendered
+resources against the limits of Kubernetes, such as 63 characters for label
+values, and lists those that would be rejected. If they are too long because
+of the release name, '--truncate-name' lets Tiller shorten the release name
+instead: it is cut to fit and ends in a hash of the full name, so the same
+name always becomes the same shortened name, and names that only differ at
+the end stay apart.
+
+Resources are created one at a time. If some cannot be created, the failed
+revision records which ones were, and '--on-failure' decides what happens
+next. With 'keep', the default, the resources stay as they are and 'helm
+resume' creates the rest later. With 'revert', the resources that were
+created are deleted at once.
+
+A Tiller that manages several clusters installs the release in the one named
+by '--cluster', instead of its own. The release stays in that cluster: later
+upgrades, rollbacks and deletes find it there without the flag.
+
+'--replace' installs over a release of the same name whose last revision
+failed or was deleted, without deleting it first. The new revision is added to
+its history, and its resources are reconciled with the chart. A release that
+is deployed, or in any other state, is never replaced: upgrade it instead.
+
+'--system' marks the release as a system release, for platform infrastructure
+that most users need not see. 'helm list' leaves system releases out unless
+'--include-system' is given. Upgrades and rollbacks keep the mark.
+
 There are four different ways you can express the chart you want to install:
 
 1. By chart reference: helm install stable/mariadb
@@ -100,24 +196,44 @@ charts in a repository, use 'helm search'.
 `
 
 type installCmd struct {
-	name         string
-	namespace    string
-	valueFiles   valueFiles
-	chartPath    string
-	dryRun       bool
-	disableHooks bool
-	replace      bool
-	verify       bool
-	keyring      string
-	out          io.Writer
-	client       helm.Interface
-	values       []string
-	nameTemplate string
-	version      string
-	timeout      int64
-	wait         bool
-	repoURL      string
-	devel        bool
+	name          string
+	namespace     string
+	valueFiles    valueFiles
+	profile       string
+	allowMissing  bool
+	chartPath     string
+	dryRun        bool
+	serverDryRun  bool
+	verifyImages  bool
+	verifyRefs    bool
+	strictValues  bool
+	as            impersonation
+	annotations   []string
+	flags         []string
+	disableHooks  bool
+	runHooks      bool
+	skipHooks     skipHooks
+	skipLookup    bool
+	replace       bool
+	verify        bool
+	keyring       string
+	out           io.Writer
+	client        helm.Interface
+	values        []string
+	stringValues  []string
+	nameTemplate  string
+	truncateName  bool
+	cluster       string
+	onFailure     string
+	system        bool
+	version       string
+	timeout       int64
+	timeoutBudget int64
+	wait          bool
+	waitLogBytes  int64
+	deferNotes    bool
+	repoURL       string
+	devel         bool
 
 	certFile string
 	keyFile  string
@@ -175,19 +291,39 @@ func newInstallCmd(c helm.Interface, out io.Writer) *cobra.Command {
 	}
 
 	f := cmd.Flags()
-	f.VarP(&inst.valueFiles, "values", "f", "specify values in a YAML file (can specify multiple)")
+	f.VarP(&inst.valueFiles, "values", "f", "specify values in a YAML file or a URL (can specify multiple)")
+	f.StringVar(&inst.profile, "profile", "", "merge the chart's values-<profile>.yaml over its defaults, under the values given with -f and --set")
+	f.BoolVar(&inst.allowMissing, "allow-missing-profile", false, "install with the chart's defaults if it has no values file for --profile, instead of failing")
 	f.StringVarP(&inst.name, "name", "n", "", "release name. If unspecified, it will autogenerate one for you")
 	f.StringVar(&inst.namespace, "namespace", "", "namespace to install the release into")
 	f.BoolVar(&inst.dryRun, "dry-run", false, "simulate an install")
+	f.BoolVar(&inst.serverDryRun, "server-dry-run", false, "simulate an install and print the resources with server defaults applied. Implies --dry-run")
+	f.BoolVar(&inst.verifyImages, "verify-images", false, "check that Tiller can find every container image of the release in its registry before installing anything")
+	f.BoolVar(&inst.verifyRefs, "verify-references", false, "check that the ServiceAccounts, Secrets and ConfigMaps that the pods of the release refer to exist, or are part of the release, before installing anything")
+	inst.as.addFlags(f, "the install")
+	f.BoolVar(&inst.strictValues, "strict-values", false, "fail if the supplied values have keys that match nothing in the defaults or values schemas of the chart and its subcharts")
+	f.StringArrayVar(&inst.flags, "flag", []string{}, "set a feature flag that templates read as .Release.Flags.NAME, as NAME or NAME=true|false. Flags are not values, and only apply to this install (can specify multiple)")
+	f.StringArrayVar(&inst.annotations, "annotation", []string{}, "record an annotation, such as the commit or pipeline that deployed the release, as KEY=VALUE (can specify multiple)")
 	f.BoolVar(&inst.disableHooks, "no-hooks", false, "prevent hooks from running during install")
-	f.BoolVar(&inst.replace, "replace", false, "re-use the given name, even if that name is already used. This is unsafe in production")
+	f.BoolVar(&inst.runHooks, "run-hooks", false, "run hooks during install even if Tiller skips them by default. --no-hooks takes precedence")
+	inst.skipHooks.addFlags(f, "install")
+	f.BoolVar(&inst.skipLookup, "skip-hook-lookup", false, "with --dry-run, do not ask the cluster which hooks would replace an existing resource")
+	f.BoolVar(&inst.replace, "replace", false, "re-use the name of a release that failed or was deleted, replacing its resources. Releases in any other state are never replaced")
 	f.StringArrayVar(&inst.values, "set", []string{}, "set values on the command line (can specify multiple or separate values with commas: key1=val1,key2=val2)")
+	f.StringArrayVar(&inst.stringValues, "set-string", []string{}, "set STRING values on the command line (can specify multiple or separate values with commas: key1=val1,key2=val2)")
 	f.StringVar(&inst.nameTemplate, "name-template", "", "specify template used to name the release")
+	f.BoolVar(&inst.truncateName, "truncate-name", false, "shorten the release name, ending it in a hash of the full name, if the resources would otherwise get names too long for Kubernetes")
+	f.StringVar(&inst.cluster, "cluster", "", "name of the cluster, out of those Tiller is configured with, to install the release in. Defaults to Tiller's own cluster")
+	f.StringVar(&inst.onFailure, "on-failure", "keep", "what to do if some resources cannot be applied: 'keep' the applied ones and the failed revision to resume, or 'revert' by deleting the ones that were applied")
+	f.BoolVar(&inst.system, "system", false, "mark the release as a system release, which 'helm list' leaves out unless --include-system is given")
 	f.BoolVar(&inst.verify, "verify", false, "verify the package before installing it")
 	f.StringVar(&inst.keyring, "keyring", defaultKeyring(), "location of public keys used for verification")
 	f.StringVar(&inst.version, "version", "", "specify the exact chart version to install. If this is not specified, the latest version is installed")
 	f.Int64Var(&inst.timeout, "timeout", 300, "time in seconds to wait for any individual kubernetes operation (like Jobs for hooks)")
+	f.Int64Var(&inst.timeoutBudget, "timeout-budget", 0, "if set, time in seconds that the whole install, hooks included, may take. The hooks and the wait for the resources each get the time that is left instead of --timeout")
+	f.Int64Var(&inst.waitLogBytes, "wait-log-bytes", 0, "with --wait, print the end of the logs of the containers that are not ready while waiting, in at most this many bytes at a time, and add them to the error if the wait times out. 0 disables it")
 	f.BoolVar(&inst.wait, "wait", false, "if set, will wait until all Pods, PVCs, Services, and minimum number of Pods of a Deployment are in a ready state before marking the release as successful. It will wait for as long as --timeout")
+	f.BoolVar(&inst.deferNotes, "defer-notes", false, "render NOTES.txt again once the resources are created and, with --wait, ready, so that the notes can read their live state from .Live")
 	f.StringVar(&inst.repoURL, "repo", "", "chart repository url where to locate the requested chart")
 	f.StringVar(&inst.certFile, "cert-file", "", "identify HTTPS client using this SSL certificate file")
 	f.StringVar(&inst.keyFile, "key-file", "", "identify HTTPS client using this SSL key file")
@@ -200,14 +336,34 @@ func newInstallCmd(c helm.Interface, out io.Writer) *cobra.Command {
 func (i *installCmd) run() error {
 	debug("CHART PATH: %s\n", i.chartPath)
 
+	if i.serverDryRun {
+		i.dryRun = true
+		if err := checkServerFeature(i.client, version.FeatureServerDryRun, "server-side dry runs"); err != nil {
+			return err
+		}
+	}
+
 	if i.namespace == "" {
 		i.namespace = defaultNamespace()
 	}
 
+	identity, err := i.as.identity(i.client)
+	if err != nil {
+		return err
+	}
+
 	rawVals, err := i.vals()
 	if err != nil {
 		return err
 	}
+	annotations, err := parseAnnotations(i.annotations)
+	if err != nil {
+		return err
+	}
+	flags, err := parseFeatureFlags(i.flags)
+	if err != nil {
+		return err
+	}
 
 	// If template is specified, try to run the template.
 	if i.nameTemplate != "" {
@@ -236,16 +392,44 @@ func (i *installCmd) run() error {
 		return fmt.Errorf("cannot load requirements: %v", err)
 	}
 
+	stopLogs := func() {}
+	if i.wait && i.waitLogBytes > 0 && !i.dryRun {
+		if i.name == "" {
+			return errors.New("--wait-log-bytes requires a release name, so that the logs of its wait can be followed")
+		}
+		stopLogs = followWaitLogs(i.client, i.name, i.out)
+	}
 	res, err := i.client.InstallReleaseFromChart(
 		chartRequested,
 		i.namespace,
 		helm.ValueOverrides(rawVals),
+		helm.InstallProfile(i.profile),
+		helm.InstallAllowMissingProfile(i.allowMissing),
 		helm.ReleaseName(i.name),
+		helm.InstallTruncateName(i.truncateName),
+		helm.InstallCluster(i.cluster),
+		helm.InstallOnFailure(i.onFailure),
+		helm.InstallSystem(i.system),
 		helm.InstallDryRun(i.dryRun),
+		helm.InstallServerDryRun(i.serverDryRun),
+		helm.InstallVerifyImages(i.verifyImages),
+		helm.InstallVerifyReferences(i.verifyRefs),
+		helm.InstallStrictValues(i.strictValues),
+		helm.InstallImpersonation(identity),
+		helm.InstallAnnotations(annotations),
+		helm.InstallFlags(flags),
 		helm.InstallReuseName(i.replace),
 		helm.InstallDisableHooks(i.disableHooks),
+		helm.InstallEnableHooks(i.runHooks),
+		helm.InstallSkipHooks(i.skipHooks.names),
+		helm.InstallSkipHookWeights(i.skipHooks.int32Weights()),
+		helm.InstallSkipHookLookup(i.skipLookup),
 		helm.InstallTimeout(i.timeout),
-		helm.InstallWait(i.wait))
+		helm.InstallTimeoutBudget(i.timeoutBudget),
+		helm.InstallWait(i.wait),
+		helm.InstallWaitLogBytes(i.waitLogBytes),
+		helm.InstallDeferNotes(i.deferNotes))
+	stopLogs()
 	if err != nil {
 		return prettyError(err)
 	}
@@ -258,6 +442,7 @@ func (i *installCmd) run() error {
 
 	// If this is a dry run, we can't display status.
 	if i.dryRun {
+		fmt.Fprintln(i.out, formatHookPreviews(res.Hooks, i.disableHooks))
 		return nil
 	}
 
@@ -308,7 +493,7 @@ func (i *installCmd) vals() ([]byte, error) {
 	// User specified a values files via -f/--values
 	for _, filePath := range i.valueFiles {
 		currentMap := map[string]interface{}{}
-		bytes, err := ioutil.ReadFile(filePath)
+		bytes, err := readFile(filePath)
 		if err != nil {
 			return []byte{}, err
 		}
@@ -327,6 +512,13 @@ func (i *installCmd) vals() ([]byte, error) {
 		}
 	}
 
+	// User specified a value via --set-string
+	for _, value := range i.stringValues {
+		if err := strvals.ParseIntoString(value, base); err != nil {
+			return []byte{}, fmt.Errorf("failed parsing --set-string data: %s", err)
+		}
+	}
+
 	return yaml.Marshal(base)
 }
 
@@ -337,7 +529,7 @@ func (i *installCmd) printRelease(rel *release.Release) {
 	}
 	// TODO: Switch to text/template like everything else.
 	fmt.Fprintf(i.out, "NAME:   %s\n", rel.Name)
-	if settings.Debug {
+	if settings.Debug || i.serverDryRun {
 		printRelease(i.out, rel)
 	}
 }
@@ -44,6 +44,30 @@ func TestInstall(t *testing.T) {
 			expected: "juno",
 			resp:     releaseMock(&releaseOptions{name: "juno"}),
 		},
+		// Install, running hooks
+		{
+			name:     "install running hooks",
+			args:     []string{"testdata/testcharts/alpine"},
+			flags:    strings.Split("--name aeneas --run-hooks", " "),
+			expected: "juno",
+			resp:     releaseMock(&releaseOptions{name: "juno"}),
+		},
+		// Install, server dry run
+		{
+			name:     "install with server dry run",
+			args:     []string{"testdata/testcharts/alpine"},
+			flags:    strings.Split("--name aeneas --server-dry-run", " "),
+			expected: "(?s)NAME:   aeneas\nREVISION: 1\n.*MANIFEST:",
+			resp:     releaseMock(&releaseOptions{name: "aeneas"}),
+		},
+		// Install, values profile
+		{
+			name:     "install with a values profile",
+			args:     []string{"testdata/testcharts/alpine"},
+			flags:    strings.Split("--name zeus --profile prod --allow-missing-profile", " "),
+			expected: "zeus",
+			resp:     releaseMock(&releaseOptions{name: "zeus"}),
+		},
 		// Install, values from cli
 		{
 			name:     "install with values",
@@ -60,6 +84,14 @@ func TestInstall(t *testing.T) {
 			resp:     releaseMock(&releaseOptions{name: "virgil"}),
 			expected: "virgil",
 		},
+		// Install, string values from cli
+		{
+			name:     "install with string values",
+			args:     []string{"testdata/testcharts/alpine"},
+			flags:    strings.Split("--set-string tag=1.0,enabled=true", " "),
+			resp:     releaseMock(&releaseOptions{name: "virgil"}),
+			expected: "virgil",
+		},
 		// Install, values from yaml
 		{
 			name:     "install with values",
@@ -226,6 +258,22 @@ func TestNameTemplate(t *testing.T) {
 	}
 }
 
+func TestInstallValsSetString(t *testing.T) {
+	i := &installCmd{
+		values:       []string{"image.tag=1,replicas=2,enabled=false"},
+		stringValues: []string{"image.tag=1.10,enabled=true,zip=01234"},
+	}
+	b, err := i.vals()
+	if err != nil {
+		t.Fatal(err)
+	}
+	// --set-string overrides --set, and its values stay strings.
+	expect := "enabled: \"true\"\nimage:\n  tag: \"1.10\"\nreplicas: 2\nzip: \"01234\"\n"
+	if string(b) != expect {
+		t.Errorf("Expected %q, got %q", expect, b)
+	}
+}
+
 func TestMergeValues(t *testing.T) {
 	nestedMap := map[string]interface{}{
 		"foo": "bar",
@@ -0,0 +1,82 @@
+/*
+Copyright 2017 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/spf13/cobra"
+
+	"k8s.io/helm/pkg/helm"
+)
+
+const invalidateDiscoveryDesc = `
+This command makes Tiller discover the API groups and versions of a cluster
+again. Tiller started with '--discovery-cache-ttl' reuses what it discovered,
+and its Kubernetes client caches the API for minutes in any case, so kinds
+added since, e.g. by CustomResourceDefinitions created outside of Helm, may be
+unknown to it until the cache expires. Releases that add
+CustomResourceDefinitions invalidate the cache themselves.
+
+    $ kubectl apply -f crontab-crd.yaml
+    $ helm invalidate-discovery
+    $ helm install ./crontabs
+`
+
+type invalidateDiscoveryCmd struct {
+	cluster string
+
+	out    io.Writer
+	client helm.Interface
+}
+
+func newInvalidateDiscoveryCmd(c helm.Interface, out io.Writer) *cobra.Command {
+	inv := &invalidateDiscoveryCmd{
+		out:    out,
+		client: c,
+	}
+
+	cmd := &cobra.Command{
+		Use:               "invalidate-discovery [flags]",
+		Short:             "make Tiller discover the API of a cluster again",
+		Long:              invalidateDiscoveryDesc,
+		PersistentPreRunE: setupConnection,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			inv.client = ensureHelmClient(inv.client)
+			return inv.run()
+		},
+	}
+
+	f := cmd.Flags()
+	f.StringVar(&inv.cluster, "cluster", "", "name of the cluster, out of those Tiller is configured with. Defaults to Tiller's own cluster")
+
+	return cmd
+}
+
+func (i *invalidateDiscoveryCmd) run() error {
+	res, err := i.client.InvalidateDiscoveryCache(helm.DiscoveryCacheCluster(i.cluster))
+	if err != nil {
+		return prettyError(err)
+	}
+	cluster := "Tiller's own cluster"
+	if i.cluster != "" {
+		cluster = fmt.Sprintf("cluster %q", i.cluster)
+	}
+	fmt.Fprintf(i.out, "API discovery of %s invalidated (%d cached entries dropped)\n", cluster, res.Entries)
+	return nil
+}
@@ -21,15 +21,20 @@ import (
 	"fmt"
 	"io"
 	"io/ioutil"
+	"net/url"
 	"os"
 	"path/filepath"
+	"regexp"
 	"strings"
 
+	"github.com/Masterminds/semver"
 	"github.com/spf13/cobra"
 
 	"k8s.io/helm/pkg/chartutil"
+	"k8s.io/helm/pkg/getter"
 	"k8s.io/helm/pkg/lint"
 	"k8s.io/helm/pkg/lint/support"
+	"k8s.io/helm/pkg/openapi"
 )
 
 var longLintHelp = `
@@ -39,12 +44,39 @@ the chart is well-formed.
 If the linter encounters things that will cause the chart to fail installation,
 it will emit [ERROR] messages. If it encounters issues that break with convention
 or recommendation, it will emit [WARNING] messages.
+
+With '--openapi-schema', the objects that the templates render with the
+default values are also validated against the OpenAPI spec of a Kubernetes
+version, without connecting to a cluster. Values of the wrong type, unknown
+fields and missing required fields are reported as errors. The spec is given
+as a Kubernetes version, such as 'v1.8.0', whose published spec is fetched
+once and cached in $HELM_HOME/cache/openapi, or as the path or URL of a
+swagger.json file:
+
+	$ helm lint --openapi-schema v1.8.0 mychart
+
+Objects of API groups that the spec does not define, such as custom
+resources, are not validated.
+
+Templates see the version of this Helm as .Capabilities.HelmVersion.Version.
+Charts that branch on it can be checked against another version with
+'--helm-version':
+
+	$ helm lint --helm-version v2.6.0 mychart
 `
 
+// openapiSpecURL is where the OpenAPI spec of a Kubernetes release is
+// published.
+const openapiSpecURL = "https://raw.githubusercontent.com/kubernetes/kubernetes/%s/api/openapi-spec/swagger.json"
+
+var kubeVersionRegexp = regexp.MustCompile(`^v?\d+\.\d+\.\d+$`)
+
 type lintCmd struct {
-	strict bool
-	paths  []string
-	out    io.Writer
+	strict      bool
+	openapi     string
+	helmVersion string
+	paths       []string
+	out         io.Writer
 }
 
 func newLintCmd(out io.Writer) *cobra.Command {
@@ -65,6 +97,8 @@ func newLintCmd(out io.Writer) *cobra.Command {
 	}
 
 	cmd.Flags().BoolVar(&l.strict, "strict", false, "fail on lint warnings")
+	cmd.Flags().StringVar(&l.openapi, "openapi-schema", "", "validate rendered objects against the OpenAPI spec of a Kubernetes version, such as v1.8.0, or in a swagger.json file or URL")
+	cmd.Flags().StringVar(&l.helmVersion, "helm-version", "", "render the templates with this Helm version, such as v2.6.0, as .Capabilities.HelmVersion.Version. Defaults to the version of this Helm")
 
 	return cmd
 }
@@ -79,10 +113,24 @@ func (l *lintCmd) run() error {
 		lowestTolerance = support.ErrorSev
 	}
 
+	var schema *openapi.Schema
+	if l.openapi != "" {
+		var err error
+		if schema, err = loadOpenAPISchema(l.openapi); err != nil {
+			return fmt.Errorf("cannot load the OpenAPI spec %s: %s", l.openapi, err)
+		}
+	}
+
+	if l.helmVersion != "" {
+		if _, err := semver.NewVersion(l.helmVersion); err != nil {
+			return fmt.Errorf("invalid --helm-version %q: %s", l.helmVersion, err)
+		}
+	}
+
 	var total int
 	var failures int
 	for _, path := range l.paths {
-		if linter, err := lintChart(path); err != nil {
+		if linter, err := lintChart(path, schema, l.helmVersion); err != nil {
 			fmt.Println("==> Skipping", path)
 			fmt.Println(err)
 		} else {
@@ -114,7 +162,7 @@ func (l *lintCmd) run() error {
 	return nil
 }
 
-func lintChart(path string) (support.Linter, error) {
+func lintChart(path string, schema *openapi.Schema, helmVersion string) (support.Linter, error) {
 	var chartPath string
 	linter := support.Linter{}
 
@@ -146,5 +194,58 @@ func lintChart(path string) (support.Linter, error) {
 		return linter, errLintNoChart
 	}
 
-	return lint.All(chartPath), nil
+	return lint.AllWithHelmVersion(chartPath, schema, helmVersion), nil
+}
+
+// loadOpenAPISchema loads the OpenAPI spec of a Kubernetes version, which is
+// fetched once and cached, or in a file or at a URL.
+func loadOpenAPISchema(source string) (*openapi.Schema, error) {
+	if !kubeVersionRegexp.MatchString(source) {
+		data, err := fetchOpenAPISpec(source)
+		if err != nil {
+			return nil, err
+		}
+		return openapi.Load(data)
+	}
+
+	version := "v" + strings.TrimPrefix(source, "v")
+	cached := filepath.Join(settings.Home.Cache(), "openapi", version+".json")
+	if data, err := ioutil.ReadFile(cached); err == nil {
+		return openapi.Load(data)
+	}
+	data, err := fetchOpenAPISpec(fmt.Sprintf(openapiSpecURL, version))
+	if err != nil {
+		return nil, err
+	}
+	schema, err := openapi.Load(data)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(filepath.Dir(cached), 0755); err != nil {
+		return nil, err
+	}
+	return schema, ioutil.WriteFile(cached, data, 0644)
+}
+
+// fetchOpenAPISpec reads the spec in the file or at the URL source.
+func fetchOpenAPISpec(source string) ([]byte, error) {
+	u, err := url.Parse(source)
+	if err != nil || u.Scheme == "" || len(u.Scheme) == 1 {
+		// Not a URL (or a Windows drive letter); read it from disk.
+		return ioutil.ReadFile(source)
+	}
+	newGetter, err := getter.All(settings).ByScheme(u.Scheme)
+	if err != nil {
+		return nil, err
+	}
+	g, err := newGetter(source, "", "", "")
+	if err != nil {
+		return nil, err
+	}
+	debug("fetching OpenAPI spec %s", redactURL(u))
+	buf, err := g.Get(source)
+	if err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
 }
@@ -17,21 +17,58 @@ limitations under the License.
 package main
 
 import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
 	"testing"
 )
 
 var (
 	archivedChartPath = "testdata/testcharts/compressedchart-0.1.0.tgz"
 	chartDirPath      = "testdata/testcharts/decompressedchart/"
+	openapiSpecPath   = "../../pkg/openapi/testdata/swagger.json"
 )
 
 func TestLintChart(t *testing.T) {
-	if _, err := lintChart(chartDirPath); err != nil {
+	if _, err := lintChart(chartDirPath, nil, ""); err != nil {
 		t.Errorf("%s", err)
 	}
 
-	if _, err := lintChart(archivedChartPath); err != nil {
+	if _, err := lintChart(archivedChartPath, nil, ""); err != nil {
 		t.Errorf("%s", err)
 	}
 
 }
+
+func TestLoadOpenAPISchema(t *testing.T) {
+	if _, err := loadOpenAPISchema(openapiSpecPath); err != nil {
+		t.Errorf("Failed to load the spec from a file: %s", err)
+	}
+
+	thome, err := tempHelmHome(t)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(thome.String())
+	old := settings.Home
+	settings.Home = thome
+	defer func() { settings.Home = old }()
+
+	// A cached spec is used without fetching it.
+	data, err := ioutil.ReadFile(openapiSpecPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cached := filepath.Join(thome.Cache(), "openapi", "v1.8.0.json")
+	if err := os.MkdirAll(filepath.Dir(cached), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(cached, data, 0644); err != nil {
+		t.Fatal(err)
+	}
+	for _, version := range []string{"v1.8.0", "1.8.0"} {
+		if _, err := loadOpenAPISchema(version); err != nil {
+			t.Errorf("Failed to load the cached spec of %s: %s", version, err)
+		}
+	}
+}
@@ -48,6 +48,13 @@ Only items that match the filter will be returned.
 	NAME            	UPDATED                 	CHART
 	maudlin-arachnid	Mon May  9 16:07:08 2016	alpine-0.1.0
 
+Releases of all namespaces are listed, together with their namespace. Use the
+'--namespace' flag to only list the releases of one namespace.
+
+Releases installed with 'helm install --system', such as platform
+infrastructure, are left out. Use the '--include-system' flag to list them as
+well.
+
 If no results are found, 'helm list' will exit 0, but with no output (or in
 the case of no '-q' flag, only headers).
 
@@ -66,11 +73,13 @@ type listCmd struct {
 	sortDesc   bool
 	out        io.Writer
 	all        bool
+	awaiting   bool
 	deleted    bool
 	deleting   bool
 	deployed   bool
 	failed     bool
 	namespace  string
+	system     bool
 	superseded bool
 	client     helm.Interface
 }
@@ -105,11 +114,13 @@ func newListCmd(client helm.Interface, out io.Writer) *cobra.Command {
 	f.IntVarP(&list.limit, "max", "m", 256, "maximum number of releases to fetch")
 	f.StringVarP(&list.offset, "offset", "o", "", "next release name in the list, used to offset from start value")
 	f.BoolVar(&list.all, "all", false, "show all releases, not just the ones marked DEPLOYED")
+	f.BoolVar(&list.awaiting, "awaiting-approval", false, "show upgrades that are awaiting approval")
 	f.BoolVar(&list.deleted, "deleted", false, "show deleted releases")
 	f.BoolVar(&list.deleting, "deleting", false, "show releases that are currently being deleted")
 	f.BoolVar(&list.deployed, "deployed", false, "show deployed releases. If no other is specified, this will be automatically enabled")
 	f.BoolVar(&list.failed, "failed", false, "show failed releases")
 	f.StringVar(&list.namespace, "namespace", "", "show releases within a specific namespace")
+	f.BoolVar(&list.system, "include-system", false, "show system releases as well")
 
 	// TODO: Do we want this as a feature of 'helm list'?
 	//f.BoolVar(&list.superseded, "history", true, "show historical releases")
@@ -138,6 +149,7 @@ func (l *listCmd) run() error {
 		helm.ReleaseListOrder(int32(sortOrder)),
 		helm.ReleaseListStatuses(stats),
 		helm.ReleaseListNamespace(l.namespace),
+		helm.ReleaseListIncludeSystem(l.system),
 	)
 
 	if err != nil {
@@ -173,6 +185,7 @@ func (l *listCmd) statusCodes() []release.Status_Code {
 			release.Status_DELETED,
 			release.Status_DELETING,
 			release.Status_FAILED,
+			release.Status_AWAITING_APPROVAL,
 		}
 	}
 	status := []release.Status_Code{}
@@ -191,6 +204,9 @@ func (l *listCmd) statusCodes() []release.Status_Code {
 	if l.superseded {
 		status = append(status, release.Status_SUPERSEDED)
 	}
+	if l.awaiting {
+		status = append(status, release.Status_AWAITING_APPROVAL)
+	}
 
 	// Default case.
 	if len(status) == 0 {
@@ -87,6 +87,15 @@ func TestListCmd(t *testing.T) {
 			// See note on previous test.
 			expected: "thomas-guide\natlas-guide",
 		},
+		{
+			name: "with a release awaiting approval",
+			args: []string{"--awaiting-approval", "-q"},
+			resp: []*release.Release{
+				releaseMock(&releaseOptions{name: "thomas-guide", statusCode: release.Status_AWAITING_APPROVAL}),
+			},
+			// See note on previous test.
+			expected: "thomas-guide",
+		},
 		{
 			name: "namespace defined, multiple flags",
 			args: []string{"--all", "-q", "--namespace test123"},
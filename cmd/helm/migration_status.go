@@ -0,0 +1,85 @@
+/*
+Copyright 2017 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/spf13/cobra"
+
+	"k8s.io/helm/pkg/helm"
+)
+
+const migrationStatusDesc = `
+This command shows the progress of moving Tiller's release records to another
+storage driver, when Tiller was started with '--storage-migrate-from'.
+
+Until every record is copied, Tiller writes releases to both drivers and reads
+them from the new one, falling back to the old one. Once every record is
+copied, Tiller deletes the records of the old driver and no longer uses it.
+`
+
+type migrationStatusCmd struct {
+	out    io.Writer
+	client helm.Interface
+}
+
+func newMigrationStatusCmd(c helm.Interface, out io.Writer) *cobra.Command {
+	ms := &migrationStatusCmd{
+		out:    out,
+		client: c,
+	}
+
+	cmd := &cobra.Command{
+		Use:               "migration-status",
+		Short:             "show the progress of a storage migration in Tiller",
+		Long:              migrationStatusDesc,
+		PersistentPreRunE: setupConnection,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ms.client = ensureHelmClient(ms.client)
+			return ms.run()
+		},
+	}
+	return cmd
+}
+
+func (m *migrationStatusCmd) run() error {
+	res, err := m.client.MigrationStatus()
+	if err != nil {
+		return prettyError(err)
+	}
+	if !res.Migrating {
+		fmt.Fprintln(m.out, "Tiller is not migrating its storage")
+		return nil
+	}
+	fmt.Fprintf(m.out, "FROM: %s\n", res.From)
+	fmt.Fprintf(m.out, "TO: %s\n", res.To)
+	if res.Complete {
+		fmt.Fprintf(m.out, "STATUS: COMPLETE\n")
+	} else {
+		fmt.Fprintf(m.out, "STATUS: IN PROGRESS\n")
+	}
+	fmt.Fprintf(m.out, "MIGRATED: %d/%d\n", res.Migrated, res.Total)
+	if res.Failed > 0 {
+		fmt.Fprintf(m.out, "FAILED: %d\n", res.Failed)
+	}
+	if res.Error != "" {
+		fmt.Fprintf(m.out, "LAST ERROR: %s\n", res.Error)
+	}
+	return nil
+}
@@ -0,0 +1,65 @@
+/*
+Copyright 2017 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"bytes"
+	"testing"
+
+	rls "k8s.io/helm/pkg/proto/hapi/services"
+)
+
+func TestMigrationStatus(t *testing.T) {
+	tests := []struct {
+		name      string
+		migration *rls.MigrationStatusResponse
+		expected  string
+	}{
+		{
+			name:     "not migrating",
+			expected: "Tiller is not migrating its storage\n",
+		},
+		{
+			name: "in progress",
+			migration: &rls.MigrationStatusResponse{
+				Migrating: true, From: "ConfigMap", To: "ConfigMap",
+				Total: 8, Migrated: 5, Failed: 1, Error: `release "rls-a.v1": quota exceeded`,
+			},
+			expected: "FROM: ConfigMap\nTO: ConfigMap\nSTATUS: IN PROGRESS\nMIGRATED: 5/8\nFAILED: 1\n" +
+				"LAST ERROR: release \"rls-a.v1\": quota exceeded\n",
+		},
+		{
+			name: "complete",
+			migration: &rls.MigrationStatusResponse{
+				Migrating: true, From: "ConfigMap", To: "Memory",
+				Total: 8, Migrated: 8, Complete: true,
+			},
+			expected: "FROM: ConfigMap\nTO: Memory\nSTATUS: COMPLETE\nMIGRATED: 8/8\n",
+		},
+	}
+	for _, tt := range tests {
+		b := new(bytes.Buffer)
+		cmd := newMigrationStatusCmd(&fakeReleaseClient{migration: tt.migration}, b)
+		if err := cmd.RunE(cmd, nil); err != nil {
+			t.Errorf("%q: %s", tt.name, err)
+			continue
+		}
+		if b.String() != tt.expected {
+			t.Errorf("%q: expected\n%q\ngot\n%q", tt.name, tt.expected, b.String())
+		}
+	}
+}
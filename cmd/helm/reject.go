@@ -0,0 +1,81 @@
+/*
+Copyright 2017 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/spf13/cobra"
+
+	"k8s.io/helm/pkg/helm"
+)
+
+const rejectDesc = `
+This command aborts an upgrade that was run with '--require-approval' and
+awaits approval. Only the pre-upgrade hooks of such an upgrade have run; the
+new revision is recorded as FAILED and the deployed revision stays in place.
+
+	$ helm reject happy-panda --reason "change freeze"
+
+A revision that was left AWAITING_APPROVAL because Tiller restarted while its
+upgrade waited can only be rejected.
+`
+
+type rejectCmd struct {
+	name   string
+	reason string
+
+	out    io.Writer
+	client helm.Interface
+}
+
+func newRejectCmd(c helm.Interface, out io.Writer) *cobra.Command {
+	reject := &rejectCmd{
+		out:    out,
+		client: c,
+	}
+
+	cmd := &cobra.Command{
+		Use:               "reject [flags] RELEASE_NAME",
+		Short:             "reject an upgrade that awaits approval",
+		Long:              rejectDesc,
+		PersistentPreRunE: setupConnection,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) == 0 {
+				return errReleaseRequired
+			}
+			reject.name = args[0]
+			reject.client = ensureHelmClient(reject.client)
+			return reject.run()
+		},
+	}
+
+	f := cmd.Flags()
+	f.StringVar(&reject.reason, "reason", "", "the reason for the rejection, recorded in the description of the revision")
+
+	return cmd
+}
+
+func (r *rejectCmd) run() error {
+	res, err := r.client.RejectRelease(r.name, r.reason)
+	if err != nil {
+		return prettyError(err)
+	}
+	fmt.Fprintf(r.out, "Rejected the upgrade of %s to revision %d\n", res.Release.Name, res.Release.Version)
+	return nil
+}
@@ -0,0 +1,56 @@
+/*
+Copyright 2017 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// parseFeatureFlags parses the NAME or NAME=BOOL arguments of '--flag' flags.
+// A flag given without a value is true.
+func parseFeatureFlags(args []string) (map[string]bool, error) {
+	flags := map[string]bool{}
+	for _, arg := range args {
+		parts := strings.SplitN(arg, "=", 2)
+		if parts[0] == "" {
+			return nil, fmt.Errorf("invalid feature flag %q: expected NAME or NAME=true|false", arg)
+		}
+		on := true
+		if len(parts) == 2 {
+			var err error
+			if on, err = strconv.ParseBool(parts[1]); err != nil {
+				return nil, fmt.Errorf("invalid feature flag %q: expected NAME or NAME=true|false", arg)
+			}
+		}
+		flags[parts[0]] = on
+	}
+	return flags, nil
+}
+
+// formatFeatureFlags returns the flags as sorted, comma-separated
+// "name=bool" pairs.
+func formatFeatureFlags(flags map[string]bool) string {
+	pairs := make([]string, 0, len(flags))
+	for k, v := range flags {
+		pairs = append(pairs, k+"="+strconv.FormatBool(v))
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, ", ")
+}
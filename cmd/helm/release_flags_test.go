@@ -0,0 +1,42 @@
+/*
+Copyright 2017 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseFeatureFlags(t *testing.T) {
+	got, err := parseFeatureFlags([]string{"canary", "new_ui=false", "beta=1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expect := map[string]bool{"canary": true, "new_ui": false, "beta": true}
+	if !reflect.DeepEqual(got, expect) {
+		t.Errorf("expected %v, got %v", expect, got)
+	}
+	if s := formatFeatureFlags(got); s != "beta=true, canary=true, new_ui=false" {
+		t.Errorf("unexpected formatting %q", s)
+	}
+
+	for _, arg := range []string{"=true", "canary=maybe"} {
+		if _, err := parseFeatureFlags([]string{arg}); err == nil {
+			t.Errorf("expected an error for %q", arg)
+		}
+	}
+}
@@ -0,0 +1,91 @@
+/*
+Copyright 2017 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/spf13/cobra"
+
+	"k8s.io/helm/pkg/helm"
+)
+
+const restartDesc = `
+This command restarts the pods of a release without changing its chart or
+values, e.g. so that they pick up a rotated secret. It works like
+'kubectl rollout restart': the pod templates of the release's Deployments,
+StatefulSets and DaemonSets get a 'helm.sh/restartedAt' annotation, and their
+pods are replaced according to each workload's update strategy. Other
+resources are not touched.
+
+No new revision is created. The restart is recorded in the description of the
+deployed revision, as shown by 'helm status' and 'helm history'.
+`
+
+type restartCmd struct {
+	name    string
+	timeout int64
+	wait    bool
+
+	out    io.Writer
+	client helm.Interface
+}
+
+func newRestartCmd(c helm.Interface, out io.Writer) *cobra.Command {
+	restart := &restartCmd{
+		out:    out,
+		client: c,
+	}
+
+	cmd := &cobra.Command{
+		Use:               "restart [flags] RELEASE_NAME",
+		Short:             "restart the pods of a release",
+		Long:              restartDesc,
+		PersistentPreRunE: setupConnection,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) == 0 {
+				return errReleaseRequired
+			}
+			restart.name = args[0]
+			restart.client = ensureHelmClient(restart.client)
+			return restart.run()
+		},
+	}
+
+	f := cmd.Flags()
+	f.Int64Var(&restart.timeout, "timeout", 300, "time in seconds to wait for any individual Kubernetes operation")
+	f.BoolVar(&restart.wait, "wait", false, "if set, will wait until the restarted workloads are in a ready state. It will wait for as long as --timeout")
+
+	return cmd
+}
+
+func (r *restartCmd) run() error {
+	res, err := r.client.RestartRelease(
+		r.name,
+		helm.RestartTimeout(r.timeout),
+		helm.RestartWait(r.wait),
+	)
+	if err != nil {
+		return prettyError(err)
+	}
+	for _, w := range res.Restarted {
+		fmt.Fprintf(r.out, "%s restarted\n", w)
+	}
+	fmt.Fprintf(r.out, "Restarted %s (revision %d)\n", res.Release.Name, res.Release.Version)
+	return nil
+}
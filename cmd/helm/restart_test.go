@@ -0,0 +1,48 @@
+/*
+Copyright 2017 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"io"
+	"testing"
+
+	"github.com/spf13/cobra"
+)
+
+func TestRestartCmd(t *testing.T) {
+	tests := []releaseCase{
+		{
+			name:     "restart a release",
+			args:     []string{"aeneas"},
+			expected: "Deployment/web restarted\nStatefulSet/db restarted\nRestarted aeneas \\(revision 2\\)\n",
+		},
+		{
+			name:     "restart a release with wait",
+			args:     []string{"aeneas"},
+			flags:    []string{"--wait", "--timeout", "120"},
+			expected: "Deployment/web restarted\nStatefulSet/db restarted\nRestarted aeneas \\(revision 2\\)\n",
+		},
+		{
+			name: "restart without release",
+			args: []string{},
+			err:  true,
+		},
+	}
+	runReleaseCases(t, tests, func(c *fakeReleaseClient, out io.Writer) *cobra.Command {
+		return newRestartCmd(c, out)
+	})
+}
@@ -0,0 +1,91 @@
+/*
+Copyright 2017 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/spf13/cobra"
+
+	"k8s.io/helm/pkg/helm"
+)
+
+const restoreDesc = `
+This command re-installs a deleted release.
+
+'helm delete' without '--purge' keeps the record of the deleted release. The
+release is restored from that record, with the chart, values and manifest of
+its last revision, and recorded as a new revision. Resources that survived the
+deletion because of the 'keep' resource policy are adopted again.
+
+Deleted releases can be listed with 'helm list --deleted'. Tiller may purge
+their records after a retention period, after which they cannot be restored.
+`
+
+type restoreCmd struct {
+	name         string
+	disableHooks bool
+	timeout      int64
+	wait         bool
+
+	out    io.Writer
+	client helm.Interface
+}
+
+func newRestoreCmd(c helm.Interface, out io.Writer) *cobra.Command {
+	restore := &restoreCmd{
+		out:    out,
+		client: c,
+	}
+
+	cmd := &cobra.Command{
+		Use:               "restore [flags] RELEASE_NAME",
+		Short:             "re-install a deleted release",
+		Long:              restoreDesc,
+		PersistentPreRunE: setupConnection,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) == 0 {
+				return errReleaseRequired
+			}
+			restore.name = args[0]
+			restore.client = ensureHelmClient(restore.client)
+			return restore.run()
+		},
+	}
+
+	f := cmd.Flags()
+	f.BoolVar(&restore.disableHooks, "no-hooks", false, "prevent hooks from running during restore")
+	f.Int64Var(&restore.timeout, "timeout", 300, "time in seconds to wait for any individual Kubernetes operation (like Jobs for hooks)")
+	f.BoolVar(&restore.wait, "wait", false, "if set, will wait until all Pods, PVCs, Services, and minimum number of Pods of a Deployment are in a ready state before marking the release as successful. It will wait for as long as --timeout")
+
+	return cmd
+}
+
+func (r *restoreCmd) run() error {
+	res, err := r.client.RestoreRelease(
+		r.name,
+		helm.RestoreDisableHooks(r.disableHooks),
+		helm.RestoreTimeout(r.timeout),
+		helm.RestoreWait(r.wait),
+	)
+	if err != nil {
+		return prettyError(err)
+	}
+	fmt.Fprintf(r.out, "Restored %s as revision %d\n", res.Release.Name, res.Release.Version)
+	return nil
+}
@@ -0,0 +1,92 @@
+/*
+Copyright 2017 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/spf13/cobra"
+
+	"k8s.io/helm/pkg/helm"
+)
+
+const resumeDesc = `
+This command applies the resources that a failed install or upgrade did not
+get to.
+
+Tiller applies the resources of a release one at a time. When some cannot be
+applied, the failed revision records which ones were. Once the cause is fixed,
+'helm resume' applies the rest of that revision, leaving the resources that
+were applied as they are, and records the result as a new revision. If the
+resume fails as well, it can be resumed in turn.
+
+Only the latest revision of a release can be resumed, and only if it failed
+while its resources were applied; 'helm status' lists what was applied. A
+revision that failed in a hook or while waiting for its resources to be ready
+applied them all, and has nothing to resume. Resuming does not run hooks.
+`
+
+type resumeCmd struct {
+	name    string
+	timeout int64
+	wait    bool
+
+	out    io.Writer
+	client helm.Interface
+}
+
+func newResumeCmd(c helm.Interface, out io.Writer) *cobra.Command {
+	resume := &resumeCmd{
+		out:    out,
+		client: c,
+	}
+
+	cmd := &cobra.Command{
+		Use:               "resume [flags] RELEASE_NAME",
+		Short:             "apply the rest of a release that failed part way",
+		Long:              resumeDesc,
+		PersistentPreRunE: setupConnection,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) == 0 {
+				return errReleaseRequired
+			}
+			resume.name = args[0]
+			resume.client = ensureHelmClient(resume.client)
+			return resume.run()
+		},
+	}
+
+	f := cmd.Flags()
+	f.Int64Var(&resume.timeout, "timeout", 300, "time in seconds to wait for any individual Kubernetes operation")
+	f.BoolVar(&resume.wait, "wait", false, "if set, will wait until all Pods, PVCs, Services, and minimum number of Pods of a Deployment are in a ready state before marking the release as successful. It will wait for as long as --timeout")
+
+	return cmd
+}
+
+func (r *resumeCmd) run() error {
+	res, err := r.client.ResumeRelease(
+		r.name,
+		helm.ResumeTimeout(r.timeout),
+		helm.ResumeWait(r.wait),
+	)
+	if err != nil {
+		return prettyError(err)
+	}
+	fmt.Fprintf(r.out, "Resumed %s as revision %d\n", res.Release.Name, res.Release.Version)
+	return nil
+}
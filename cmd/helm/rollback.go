@@ -17,13 +17,20 @@ limitations under the License.
 package main
 
 import (
+	"errors"
 	"fmt"
 	"io"
 	"strconv"
+	"strings"
+	"time"
 
+	"github.com/ghodss/yaml"
+	"github.com/gosuri/uitable"
 	"github.com/spf13/cobra"
 
 	"k8s.io/helm/pkg/helm"
+	"k8s.io/helm/pkg/proto/hapi/services"
+	"k8s.io/helm/pkg/strvals"
 )
 
 const rollbackDesc = `
@@ -32,19 +39,93 @@ This command rolls back a release to a previous revision.
 The first argument of the rollback command is the name of a release, and the
 second is a revision (version) number. To see revision numbers, run 
 'helm history RELEASE'.
+
+Instead of a revision number, '--deployed-at' targets the revision that was
+deployed at a point in time: the last one deployed before it, which has since
+been superseded. Revisions that failed are skipped, even once they were
+resumed or reverted. The time is given in RFC 3339 format, or in the format of
+'helm history' in the local time zone:
+
+	$ helm rollback my-release --deployed-at 2017-07-14T15:00:00Z
+	$ helm rollback my-release --deployed-at "Fri Jul 14 17:00:00 2017"
+
+If the current revision is a failed upgrade, '--partial' reverts only the
+resources that the upgrade did not apply successfully, leaving the resources
+it did update in place. The resulting revision is a mix of the two revisions
+and does not match either chart exactly, so a full upgrade or rollback should
+follow once the failure has been fixed.
+
+'--from-snapshot' applies the snapshot of the target revision, taken with
+'helm snapshot', instead of its manifest. This restores the resources as they
+were when the snapshot was taken, including changes made outside of Helm. It
+cannot be combined with '--partial' or '--re-render'.
+
+With '--dry-run', nothing is changed and no hooks are run. Instead, the
+pre-rollback and post-rollback hooks of the target revision are listed in the
+order they would run, with their weights and declared delete policies. Hooks
+excluded with '--skip-hook' or '--skip-hook-weight' are marked as skipped.
+
+To show which hooks would replace a resource left behind by an earlier run,
+the dry run asks the cluster, read-only, whether the resources of hooks with
+the 'before-hook-creation' delete policy exist. Those that do are marked as
+replacing an existing resource. '--skip-hook-lookup' leaves the cluster alone.
+
+The hooks of the target revision run again. Hooks annotated with
+'helm.sh/hook-destructive: "true"', such as a pre-rollback hook that wipes a
+cache, are skipped unless '--allow-destructive-hooks' is set, so that a
+rollback does not do more damage than the failure it is meant to fix. The
+skipped hooks are listed, and the dry run marks them as destructive.
+
+Pods deleted by '--recreate-pods' or '--force', and resources that the target
+revision does not have, get the termination grace period of their own spec to
+shut down. '--grace-period' overrides it, and '--propagation-policy' sets how
+the objects that depend on deleted resources are removed ('Foreground',
+'Background' or 'Orphan'). With '--wait', the grace period counts against
+'--timeout', so it should be well below it.
+
+'--recreate-on-selector-change' deletes and recreates the workloads whose label
+selector differs in the target revision, leaving their pods running for the
+recreated workloads to adopt; see 'helm upgrade --help'.
+
+By default, the manifests that the target revision was rendered to are reused
+as they are. '--re-render' renders its chart again instead, so that templates
+see the current capabilities of the cluster and the rollback as an upgrade.
+Values given with '--values' and '--set' are then merged over the values of
+the target revision, and the new revision records the merged values:
+
+	$ helm rollback --re-render --set image.tag=1.2.4 my-release 3
+
+'--values' and '--set' require '--re-render', which cannot be combined with
+'--partial'.
 `
 
 type rollbackCmd struct {
-	name         string
-	revision     int32
-	dryRun       bool
-	recreate     bool
-	force        bool
-	disableHooks bool
-	out          io.Writer
-	client       helm.Interface
-	timeout      int64
-	wait         bool
+	name           string
+	revision       int32
+	deployedAt     string
+	dryRun         bool
+	recreate       bool
+	force          bool
+	gracePeriod    int64
+	propagation    string
+	selectorChange bool
+	disableHooks   bool
+	runHooks       bool
+	skipHooks      skipHooks
+	as             impersonation
+	skipHookLookup bool
+	destructive    bool
+	partial        bool
+	fromSnapshot   bool
+	reRender       bool
+	valueFiles     valueFiles
+	values         []string
+	stringValues   []string
+	out            io.Writer
+	client         helm.Interface
+	timeout        int64
+	timeoutBudget  int64
+	wait           bool
 }
 
 func newRollbackCmd(c helm.Interface, out io.Writer) *cobra.Command {
@@ -59,6 +140,14 @@ func newRollbackCmd(c helm.Interface, out io.Writer) *cobra.Command {
 		Long:              rollbackDesc,
 		PersistentPreRunE: setupConnection,
 		RunE: func(cmd *cobra.Command, args []string) error {
+			if rollback.deployedAt != "" {
+				if err := checkArgsLength(len(args), "release name"); err != nil {
+					return err
+				}
+				rollback.name = args[0]
+				rollback.client = ensureHelmClient(rollback.client)
+				return rollback.run()
+			}
 			if err := checkArgsLength(len(args), "release name", "revision number"); err != nil {
 				return err
 			}
@@ -80,28 +169,176 @@ func newRollbackCmd(c helm.Interface, out io.Writer) *cobra.Command {
 	f.BoolVar(&rollback.dryRun, "dry-run", false, "simulate a rollback")
 	f.BoolVar(&rollback.recreate, "recreate-pods", false, "performs pods restart for the resource if applicable")
 	f.BoolVar(&rollback.force, "force", false, "force resource update through delete/recreate if needed")
+	f.Int64Var(&rollback.gracePeriod, "grace-period", 0, "time in seconds that deleted pods get to shut down. If 0, each pod's own terminationGracePeriodSeconds is used")
+	f.StringVar(&rollback.propagation, "propagation-policy", "", "how to delete the objects that depend on deleted resources. One of 'Foreground', 'Background' or 'Orphan'")
+	f.BoolVar(&rollback.selectorChange, "recreate-on-selector-change", false, "delete and recreate workloads whose label selector changed, leaving their pods running for the new workloads to adopt")
 	f.BoolVar(&rollback.disableHooks, "no-hooks", false, "prevent hooks from running during rollback")
+	f.BoolVar(&rollback.runHooks, "run-hooks", false, "run hooks during rollback even if Tiller skips them by default. --no-hooks takes precedence")
+	rollback.skipHooks.addFlags(f, "rollback")
+	rollback.as.addFlags(f, "the rollback")
+	f.BoolVar(&rollback.skipHookLookup, "skip-hook-lookup", false, "with --dry-run, do not ask the cluster which hooks would replace an existing resource")
+	f.BoolVar(&rollback.destructive, "allow-destructive-hooks", false, "run the hooks annotated as destructive, which are skipped otherwise")
+	f.StringVar(&rollback.deployedAt, "deployed-at", "", "roll back to the revision that was deployed at this time, instead of a revision number")
+	f.BoolVar(&rollback.partial, "partial", false, "only revert the resources that a failed upgrade did not apply successfully")
+	f.BoolVar(&rollback.fromSnapshot, "from-snapshot", false, "apply the snapshot of the revision, taken with 'helm snapshot', instead of its manifest")
+	f.BoolVar(&rollback.reRender, "re-render", false, "render the chart of the revision again instead of reusing its manifests")
+	f.VarP(&rollback.valueFiles, "values", "f", "specify values in a YAML file or a URL to merge over those of the revision (can specify multiple). Requires --re-render")
+	f.StringArrayVar(&rollback.values, "set", []string{}, "set values on the command line to merge over those of the revision (can specify multiple or separate values with commas: key1=val1,key2=val2). Requires --re-render")
+	f.StringArrayVar(&rollback.stringValues, "set-string", []string{}, "set STRING values on the command line to merge over those of the revision (can specify multiple or separate values with commas: key1=val1,key2=val2). Requires --re-render")
 	f.Int64Var(&rollback.timeout, "timeout", 300, "time in seconds to wait for any individual kubernetes operation (like Jobs for hooks)")
+	f.Int64Var(&rollback.timeoutBudget, "timeout-budget", 0, "if set, time in seconds that the whole rollback, hooks included, may take. The hooks and the wait for the resources each get the time that is left instead of --timeout")
 	f.BoolVar(&rollback.wait, "wait", false, "if set, will wait until all Pods, PVCs, Services, and minimum number of Pods of a Deployment are in a ready state before marking the release as successful. It will wait for as long as --timeout")
 
 	return cmd
 }
 
 func (r *rollbackCmd) run() error {
-	_, err := r.client.RollbackRelease(
+	warnGracePeriod(r.out, r.gracePeriod, r.timeout, r.wait)
+	if !r.reRender && (len(r.valueFiles) > 0 || len(r.values) > 0 || len(r.stringValues) > 0) {
+		return errors.New("--values, --set and --set-string require --re-render")
+	}
+	rawVals, err := r.vals()
+	if err != nil {
+		return err
+	}
+	deployedAt, err := parseDeployedAt(r.deployedAt)
+	if err != nil {
+		return err
+	}
+	identity, err := r.as.identity(r.client)
+	if err != nil {
+		return err
+	}
+
+	res, err := r.client.RollbackRelease(
 		r.name,
 		helm.RollbackDryRun(r.dryRun),
 		helm.RollbackRecreate(r.recreate),
 		helm.RollbackForce(r.force),
+		helm.RollbackGracePeriod(r.gracePeriod),
+		helm.RollbackPropagationPolicy(r.propagation),
+		helm.RollbackRecreateOnSelectorChange(r.selectorChange),
 		helm.RollbackDisableHooks(r.disableHooks),
+		helm.RollbackEnableHooks(r.runHooks),
+		helm.RollbackSkipHooks(r.skipHooks.names),
+		helm.RollbackSkipHookWeights(r.skipHooks.int32Weights()),
+		helm.RollbackSkipHookLookup(r.skipHookLookup),
+		helm.RollbackAllowDestructiveHooks(r.destructive),
+		helm.RollbackImpersonation(identity),
+		helm.RollbackPartial(r.partial),
+		helm.RollbackFromSnapshot(r.fromSnapshot),
+		helm.RollbackReRender(r.reRender),
+		helm.RollbackValueOverrides(rawVals),
 		helm.RollbackVersion(r.revision),
+		helm.RollbackDeployedAt(deployedAt),
 		helm.RollbackTimeout(r.timeout),
+		helm.RollbackTimeoutBudget(r.timeoutBudget),
 		helm.RollbackWait(r.wait))
 	if err != nil {
 		return prettyError(err)
 	}
 
+	if r.dryRun {
+		var hooks []*services.HookPreview
+		if res != nil {
+			hooks = res.Hooks
+		}
+		fmt.Fprintln(r.out, formatHookPreviews(hooks, r.disableHooks))
+		fmt.Fprintf(r.out, "Rollback dry run complete. Nothing was changed.\n")
+		return nil
+	}
+
+	if res != nil && len(res.SkippedDestructiveHooks) > 0 {
+		fmt.Fprintf(r.out, "Skipped destructive hooks: %s (see --allow-destructive-hooks)\n", strings.Join(res.SkippedDestructiveHooks, ", "))
+	}
 	fmt.Fprintf(r.out, "Rollback was a success! Happy Helming!\n")
 
 	return nil
 }
+
+// parseDeployedAt parses the time given with --deployed-at, in RFC 3339 format
+// or in the local time format of 'helm history'. It returns the zero time if
+// none is given.
+func parseDeployedAt(s string) (time.Time, error) {
+	if s == "" {
+		return time.Time{}, nil
+	}
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t, nil
+	}
+	t, err := time.ParseInLocation(time.ANSIC, s, time.Local)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid --deployed-at time %q: use RFC 3339, e.g. 2017-07-14T15:00:00Z, or the format of 'helm history'", s)
+	}
+	return t, nil
+}
+
+// vals merges the values given with --values, --set and --set-string. It
+// returns nothing if there are none.
+func (r *rollbackCmd) vals() ([]byte, error) {
+	if len(r.valueFiles) == 0 && len(r.values) == 0 && len(r.stringValues) == 0 {
+		return nil, nil
+	}
+	base := map[string]interface{}{}
+
+	// User specified a values files via -f/--values
+	for _, filePath := range r.valueFiles {
+		currentMap := map[string]interface{}{}
+		bytes, err := readFile(filePath)
+		if err != nil {
+			return []byte{}, err
+		}
+
+		if err := yaml.Unmarshal(bytes, &currentMap); err != nil {
+			return []byte{}, fmt.Errorf("failed to parse %s: %s", filePath, err)
+		}
+		// Merge with the previous map
+		base = mergeValues(base, currentMap)
+	}
+
+	// User specified a value via --set
+	for _, value := range r.values {
+		if err := strvals.ParseInto(value, base); err != nil {
+			return []byte{}, fmt.Errorf("failed parsing --set data: %s", err)
+		}
+	}
+
+	// User specified a value via --set-string
+	for _, value := range r.stringValues {
+		if err := strvals.ParseIntoString(value, base); err != nil {
+			return []byte{}, fmt.Errorf("failed parsing --set-string data: %s", err)
+		}
+	}
+
+	return yaml.Marshal(base)
+}
+
+// formatHookPreviews formats the hooks an operation would run as a table.
+func formatHookPreviews(hooks []*services.HookPreview, disabled bool) string {
+	if disabled {
+		return "Hooks are disabled; no hooks would run."
+	}
+	if len(hooks) == 0 {
+		return "No hooks would run."
+	}
+	tbl := uitable.New()
+	tbl.AddRow("EVENT", "WEIGHT", "NAME", "KIND", "DELETE POLICIES", "PATH")
+	for _, h := range hooks {
+		name := h.Name
+		if h.Destructive {
+			name += " (destructive)"
+		}
+		if h.Skipped {
+			name += " (skipped)"
+		}
+		if h.ReplacesExisting {
+			name += " (replaces existing)"
+		}
+		policies := strings.Join(h.DeletePolicies, ",")
+		if policies == "" {
+			policies = "none"
+		}
+		tbl.AddRow(h.Event, h.Weight, name, h.Kind, policies, h.Path)
+	}
+	return tbl.String()
+}
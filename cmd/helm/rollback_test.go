@@ -17,10 +17,16 @@ limitations under the License.
 package main
 
 import (
+	"bytes"
 	"io"
+	"regexp"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/spf13/cobra"
+
+	"k8s.io/helm/pkg/proto/hapi/services"
 )
 
 func TestRollbackCmd(t *testing.T) {
@@ -43,11 +49,77 @@ func TestRollbackCmd(t *testing.T) {
 			flags:    []string{"--wait"},
 			expected: "Rollback was a success! Happy Helming!",
 		},
+		{
+			name:     "rollback a release with grace period and propagation policy",
+			args:     []string{"funny-honey", "1"},
+			flags:    []string{"--recreate-pods", "--grace-period", "30", "--propagation-policy", "Background"},
+			expected: "Rollback was a success! Happy Helming!",
+		},
+		{
+			name:     "rollback a release with a grace period longer than the wait",
+			args:     []string{"funny-honey", "1"},
+			flags:    []string{"--wait", "--timeout", "60", "--grace-period", "90"},
+			expected: "(?s)WARNING: --grace-period \\(90s\\) is not shorter than --timeout \\(60s\\).*Rollback was a success",
+		},
+		{
+			name:     "rollback a release recreating workloads whose selector changed",
+			args:     []string{"funny-honey", "1"},
+			flags:    []string{"--recreate-on-selector-change"},
+			expected: "Rollback was a success! Happy Helming!",
+		},
+		{
+			name:     "rollback a release dry run",
+			args:     []string{"funny-honey", "1"},
+			flags:    []string{"--dry-run"},
+			expected: "(?s)No hooks would run.*Rollback dry run complete",
+		},
+		{
+			name:     "rollback a release dry run without hooks",
+			args:     []string{"funny-honey", "1"},
+			flags:    []string{"--dry-run", "--no-hooks"},
+			expected: "Hooks are disabled",
+		},
+		{
+			name:     "rollback a release re-rendering it with overridden values",
+			args:     []string{"funny-honey", "1"},
+			flags:    []string{"--re-render", "--set", "image.tag=1.2.4"},
+			expected: "Rollback was a success! Happy Helming!",
+		},
+		{
+			name:  "rollback a release with overridden values without re-rendering",
+			args:  []string{"funny-honey", "1"},
+			flags: []string{"--set", "image.tag=1.2.4"},
+			err:   true,
+		},
+		{
+			name:  "rollback a release with overridden string values without re-rendering",
+			args:  []string{"funny-honey", "1"},
+			flags: []string{"--set-string", "image.tag=1.10"},
+			err:   true,
+		},
 		{
 			name: "rollback a release without revision",
 			args: []string{"funny-honey"},
 			err:  true,
 		},
+		{
+			name:     "rollback a release to the revision deployed at a time",
+			args:     []string{"funny-honey"},
+			flags:    []string{"--deployed-at", "2017-07-14T15:00:00Z"},
+			expected: "Rollback was a success! Happy Helming!",
+		},
+		{
+			name:  "rollback a release to an invalid time",
+			args:  []string{"funny-honey"},
+			flags: []string{"--deployed-at", "yesterday"},
+			err:   true,
+		},
+		{
+			name:     "rollback a release to the snapshot of a revision",
+			args:     []string{"funny-honey", "1"},
+			flags:    []string{"--from-snapshot"},
+			expected: "Rollback was a success! Happy Helming!",
+		},
 	}
 
 	cmd := func(c *fakeReleaseClient, out io.Writer) *cobra.Command {
@@ -57,3 +129,55 @@ func TestRollbackCmd(t *testing.T) {
 	runReleaseCases(t, tests, cmd)
 
 }
+
+func TestRollbackCmdSkippedDestructiveHooks(t *testing.T) {
+	var buf bytes.Buffer
+	c := &fakeReleaseClient{
+		rollback: &services.RollbackReleaseResponse{SkippedDestructiveHooks: []string{"wipe-cache", "reset-db"}},
+	}
+	cmd := newRollbackCmd(c, &buf)
+	if err := cmd.RunE(cmd, []string{"funny-honey", "1"}); err != nil {
+		t.Fatal(err)
+	}
+	expect := "Skipped destructive hooks: wipe-cache, reset-db (see --allow-destructive-hooks)\nRollback was a success!"
+	if !strings.Contains(buf.String(), expect) {
+		t.Errorf("Expected %q, got %q", expect, buf.String())
+	}
+}
+
+func TestFormatHookPreviews(t *testing.T) {
+	hooks := []*services.HookPreview{
+		{Name: "backup", Kind: "Job", Path: "templates/backup.yaml", Event: "pre-rollback", Weight: -1},
+		{Name: "restore", Kind: "Job", Path: "templates/restore.yaml", Event: "pre-rollback", Weight: 5, DeletePolicies: []string{"hook-succeeded"}},
+		{Name: "notify", Kind: "ConfigMap", Path: "templates/notify.yaml", Event: "post-rollback", Skipped: true},
+		{Name: "wipe-cache", Kind: "Job", Path: "templates/wipe-cache.yaml", Event: "post-rollback", Weight: 2, Skipped: true, Destructive: true},
+		{Name: "migrate", Kind: "Job", Path: "templates/migrate.yaml", Event: "post-rollback", Weight: 1, DeletePolicies: []string{"before-hook-creation"}, ReplacesExisting: true},
+	}
+	out := formatHookPreviews(hooks, false)
+	for _, expect := range []string{
+		`EVENT\s+WEIGHT\s+NAME\s+KIND\s+DELETE POLICIES\s+PATH`,
+		`pre-rollback\s+-1\s+backup\s+Job\s+none\s+templates/backup.yaml`,
+		`pre-rollback\s+5\s+restore\s+Job\s+hook-succeeded\s+templates/restore.yaml`,
+		`post-rollback\s+0\s+notify \(skipped\)\s+ConfigMap`,
+		`post-rollback\s+1\s+migrate \(replaces existing\)\s+Job\s+before-hook-creation`,
+		`post-rollback\s+2\s+wipe-cache \(destructive\) \(skipped\)\s+Job`,
+	} {
+		if !regexp.MustCompile(expect).MatchString(out) {
+			t.Errorf("expected output to match %q, got\n%s", expect, out)
+		}
+	}
+}
+
+func TestParseDeployedAt(t *testing.T) {
+	got, err := parseDeployedAt("2017-07-14T17:00:00+02:00")
+	if err != nil || !got.Equal(time.Date(2017, 7, 14, 15, 0, 0, 0, time.UTC)) {
+		t.Errorf("Expected an RFC 3339 time to be parsed, got %s (%v)", got, err)
+	}
+	got, err = parseDeployedAt("Fri Jul 14 15:00:00 2017")
+	if err != nil || !got.Equal(time.Date(2017, 7, 14, 15, 0, 0, 0, time.Local)) {
+		t.Errorf("Expected a 'helm history' time to be parsed as local time, got %s (%v)", got, err)
+	}
+	if got, err := parseDeployedAt(""); err != nil || !got.IsZero() {
+		t.Errorf("Expected no time, got %s (%v)", got, err)
+	}
+}
@@ -0,0 +1,42 @@
+/*
+Copyright 2017 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"github.com/spf13/pflag"
+)
+
+// skipHooks holds the flags that skip individual hooks. It is shared by the
+// commands that run hooks.
+type skipHooks struct {
+	names   []string
+	weights []int
+}
+
+func (s *skipHooks) addFlags(f *pflag.FlagSet, operation string) {
+	f.StringArrayVar(&s.names, "skip-hook", []string{}, "skip the hook with this name during "+operation+" (can specify multiple)")
+	f.IntSliceVar(&s.weights, "skip-hook-weight", []int{}, "skip the hooks with this weight during "+operation+" (can specify multiple or separate values with commas: 5,10)")
+}
+
+// int32Weights returns the weights in the form the API expects.
+func (s *skipHooks) int32Weights() []int32 {
+	weights := make([]int32, len(s.weights))
+	for i, w := range s.weights {
+		weights[i] = int32(w)
+	}
+	return weights
+}
@@ -0,0 +1,86 @@
+/*
+Copyright 2017 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"k8s.io/helm/pkg/helm"
+)
+
+const snapshotDesc = `
+This command records the live state of the resources of a release in its
+current revision.
+
+Run it before an upgrade that might go wrong. The snapshot holds the resources
+as they are in the cluster, including changes made outside of Helm, such as a
+Deployment scaled with 'kubectl scale'. 'helm rollback --from-snapshot' to the
+revision applies the snapshot instead of the manifest of the revision, and so
+restores them.
+
+The fields that the API server manages, such as the status, the uid and the
+resource version, and the cluster IP of Services, are left out of the
+snapshot. Hooks are not part of it. Taking another snapshot of the same
+revision replaces the first one.
+`
+
+type snapshotCmd struct {
+	name string
+
+	out    io.Writer
+	client helm.Interface
+}
+
+func newSnapshotCmd(c helm.Interface, out io.Writer) *cobra.Command {
+	snapshot := &snapshotCmd{
+		out:    out,
+		client: c,
+	}
+
+	cmd := &cobra.Command{
+		Use:               "snapshot [flags] RELEASE_NAME",
+		Short:             "record the live state of the resources of a release",
+		Long:              snapshotDesc,
+		PersistentPreRunE: setupConnection,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) == 0 {
+				return errReleaseRequired
+			}
+			snapshot.name = args[0]
+			snapshot.client = ensureHelmClient(snapshot.client)
+			return snapshot.run()
+		},
+	}
+
+	return cmd
+}
+
+func (s *snapshotCmd) run() error {
+	res, err := s.client.SnapshotRelease(s.name)
+	if err != nil {
+		return prettyError(err)
+	}
+	fmt.Fprintf(s.out, "Took a snapshot of %s at revision %d\n", res.Release.Name, res.Release.Version)
+	if missing := res.Release.Snapshot.Missing; len(missing) > 0 {
+		fmt.Fprintf(s.out, "These resources do not exist, and are left out: %s\n", strings.Join(missing, ", "))
+	}
+	return nil
+}
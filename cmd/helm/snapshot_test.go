@@ -0,0 +1,42 @@
+/*
+Copyright 2017 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"io"
+	"testing"
+
+	"github.com/spf13/cobra"
+)
+
+func TestSnapshotCmd(t *testing.T) {
+	tests := []releaseCase{
+		{
+			name:     "snapshot a release",
+			args:     []string{"aeneas"},
+			expected: "Took a snapshot of aeneas at revision 1\nThese resources do not exist, and are left out: Service/web\n",
+		},
+		{
+			name: "snapshot without release",
+			args: []string{},
+			err:  true,
+		},
+	}
+	runReleaseCases(t, tests, func(c *fakeReleaseClient, out io.Writer) *cobra.Command {
+		return newSnapshotCmd(c, out)
+	})
+}
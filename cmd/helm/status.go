@@ -20,6 +20,7 @@ import (
 	"fmt"
 	"io"
 	"regexp"
+	"strings"
 	"text/tabwriter"
 
 	"github.com/gosuri/uitable"
@@ -95,8 +96,38 @@ func PrintStatus(out io.Writer, res *services.GetReleaseStatusResponse) {
 		fmt.Fprintf(out, "LAST DEPLOYED: %s\n", timeconv.String(res.Info.LastDeployed))
 	}
 	fmt.Fprintf(out, "NAMESPACE: %s\n", res.Namespace)
+	if len(res.Namespaces) > 1 {
+		fmt.Fprintf(out, "OTHER NAMESPACES: %s\n", strings.Join(res.Namespaces[1:], ", "))
+	}
+	if res.Cluster != "" {
+		fmt.Fprintf(out, "CLUSTER: %s\n", res.Cluster)
+	}
 	fmt.Fprintf(out, "STATUS: %s\n", res.Info.Status.Code)
+	if imp := res.Info.Impersonation; imp != nil {
+		fmt.Fprintf(out, "ACTED AS: %s\n", imp.User)
+		if len(imp.Groups) > 0 {
+			fmt.Fprintf(out, "ACTED AS GROUPS: %s\n", strings.Join(imp.Groups, ", "))
+		}
+	}
+	if len(res.Info.Flags) > 0 {
+		fmt.Fprintf(out, "FLAGS: %s\n", formatFeatureFlags(res.Info.Flags))
+	}
+	if len(res.Info.Annotations) > 0 {
+		fmt.Fprintf(out, "ANNOTATIONS:\n")
+		for _, line := range annotationLines(res.Info.Annotations) {
+			fmt.Fprintf(out, "  %s\n", line)
+		}
+	}
 	fmt.Fprintf(out, "\n")
+	if len(res.Info.ResourceStatuses) > 0 {
+		fmt.Fprintf(out, "APPLIED BEFORE THE FAILURE:\n%s\n\n", formatResourceStatuses(res.Info.ResourceStatuses))
+	}
+	if len(res.Info.ValuesMutations) > 0 {
+		fmt.Fprintf(out, "VALUES MUTATED BY:\n%s\n\n", formatValuesMutations(res.Info.ValuesMutations))
+	}
+	if len(res.Info.ContainerInjections) > 0 {
+		fmt.Fprintf(out, "CONTAINERS INJECTED:\n%s\n\n", formatContainerInjections(res.Info.ContainerInjections))
+	}
 	if len(res.Info.Status.Resources) > 0 {
 		re := regexp.MustCompile("  +")
 
@@ -117,6 +148,46 @@ func PrintStatus(out io.Writer, res *services.GetReleaseStatusResponse) {
 	}
 }
 
+// formatResourceStatuses lists the resources that a revision that failed part
+// way attempted to apply. Resources it did not get to are not listed.
+func formatResourceStatuses(statuses []*release.ResourceStatus) string {
+	tbl := uitable.New()
+	tbl.MaxColWidth = 80
+	tbl.AddRow("RESOURCE", "STATUS", "ERROR")
+	for _, st := range statuses {
+		code := st.Code.String()
+		if st.Recreated {
+			code += " (re-created)"
+		}
+		tbl.AddRow(st.Kind+"/"+st.Name, code, st.Error)
+	}
+	return tbl.String()
+}
+
+// formatValuesMutations lists the values mutators that Tiller ran on the
+// values of a revision, in order, with the values each one changed.
+func formatValuesMutations(mutations []*release.ValuesMutation) string {
+	tbl := uitable.New()
+	tbl.MaxColWidth = 80
+	tbl.AddRow("MUTATOR", "CHANGED")
+	for _, m := range mutations {
+		tbl.AddRow(m.Mutator, strings.Join(m.Changed, ", "))
+	}
+	return tbl.String()
+}
+
+// formatContainerInjections lists the containers that Tiller's injection
+// policies added to the workloads of a revision.
+func formatContainerInjections(injections []*release.ContainerInjection) string {
+	tbl := uitable.New()
+	tbl.MaxColWidth = 80
+	tbl.AddRow("POLICY", "RESOURCE", "CONTAINERS")
+	for _, in := range injections {
+		tbl.AddRow(in.Policy, in.Kind+"/"+in.Name, strings.Join(in.Containers, ", "))
+	}
+	return tbl.String()
+}
+
 func formatTestResults(results []*release.TestRun) string {
 	tbl := uitable.New()
 	tbl.MaxColWidth = 50
@@ -73,6 +73,122 @@ func TestStatusCmd(t *testing.T) {
 				Resources: "resource A\nresource B\n",
 			}),
 		},
+		{
+			name:     "get status of an annotated release",
+			args:     []string{"flummoxed-chickadee"},
+			expected: outputWithStatus("DEPLOYED\nANNOTATIONS:\n  approved-by=alice\n  git-commit=4f2c1e0\n\n"),
+			rel: func() *release.Release {
+				r := releaseMockWithStatus(&release.Status{Code: release.Status_DEPLOYED})
+				r.Info.Annotations = map[string]string{"git-commit": "4f2c1e0", "approved-by": "alice"}
+				return r
+			}(),
+		},
+		{
+			name:     "get status of a release deployed with feature flags",
+			args:     []string{"flummoxed-chickadee"},
+			expected: outputWithStatus("DEPLOYED\nFLAGS: canary=true, new_ui=false\n\n"),
+			rel: func() *release.Release {
+				r := releaseMockWithStatus(&release.Status{Code: release.Status_DEPLOYED})
+				r.Info.Flags = map[string]bool{"new_ui": false, "canary": true}
+				return r
+			}(),
+		},
+		{
+			name:     "get status of a release deployed as a service account",
+			args:     []string{"flummoxed-chickadee"},
+			expected: outputWithStatus("DEPLOYED\nACTED AS: system:serviceaccount:ci:deployer\nACTED AS GROUPS: deployers\n\n"),
+			rel: func() *release.Release {
+				r := releaseMockWithStatus(&release.Status{Code: release.Status_DEPLOYED})
+				r.Info.Impersonation = &release.Impersonation{User: "system:serviceaccount:ci:deployer", Groups: []string{"deployers"}}
+				return r
+			}(),
+		},
+		{
+			name:     "get status of a release in several namespaces",
+			args:     []string{"flummoxed-chickadee"},
+			expected: fmt.Sprintf("LAST DEPLOYED: %s\nNAMESPACE: default\nOTHER NAMESPACES: logging, monitoring\nSTATUS: DEPLOYED\n\n", dateString),
+			rel: func() *release.Release {
+				r := releaseMockWithStatus(&release.Status{Code: release.Status_DEPLOYED})
+				r.Namespace = "default"
+				r.Namespaces = []string{"default", "logging", "monitoring"}
+				return r
+			}(),
+		},
+		{
+			name:     "get status of a release in another cluster",
+			args:     []string{"flummoxed-chickadee"},
+			expected: fmt.Sprintf("LAST DEPLOYED: %s\nNAMESPACE: default\nCLUSTER: spoke-1\nSTATUS: DEPLOYED\n\n", dateString),
+			rel: func() *release.Release {
+				r := releaseMockWithStatus(&release.Status{Code: release.Status_DEPLOYED})
+				r.Namespace = "default"
+				r.Cluster = "spoke-1"
+				return r
+			}(),
+		},
+		{
+			name: "get status of a release that failed part way",
+			args: []string{"flummoxed-chickadee"},
+			expected: outputWithStatus("FAILED\n\nAPPLIED BEFORE THE FAILURE:\n" +
+				"RESOURCE          \tSTATUS \tERROR                        \n" +
+				"ConfigMap/settings\tAPPLIED\t                             \n" +
+				"Service/web       \tFAILED \tspec.ports: Invalid value: 0\n\n"),
+			rel: func() *release.Release {
+				r := releaseMockWithStatus(&release.Status{Code: release.Status_FAILED})
+				r.Info.ResourceStatuses = []*release.ResourceStatus{
+					{Kind: "ConfigMap", Name: "settings", Code: release.ResourceStatus_APPLIED},
+					{Kind: "Service", Name: "web", Code: release.ResourceStatus_FAILED, Error: "spec.ports: Invalid value: 0"},
+				}
+				return r
+			}(),
+		},
+		{
+			name: "get status of a release that re-created a resource before failing",
+			args: []string{"flummoxed-chickadee"},
+			expected: outputWithStatus("FAILED\n\nAPPLIED BEFORE THE FAILURE:\n" +
+				"RESOURCE          \tSTATUS                \tERROR                        \n" +
+				"ConfigMap/settings\tAPPLIED (re-created)\t                             \n" +
+				"Service/web       \tFAILED                \tspec.ports: Invalid value: 0\n\n"),
+			rel: func() *release.Release {
+				r := releaseMockWithStatus(&release.Status{Code: release.Status_FAILED})
+				r.Info.ResourceStatuses = []*release.ResourceStatus{
+					{Kind: "ConfigMap", Name: "settings", Code: release.ResourceStatus_APPLIED, Recreated: true},
+					{Kind: "Service", Name: "web", Code: release.ResourceStatus_FAILED, Error: "spec.ports: Invalid value: 0"},
+				}
+				return r
+			}(),
+		},
+		{
+			name: "get status of a release with mutated values",
+			args: []string{"flummoxed-chickadee"},
+			expected: outputWithStatus("DEPLOYED\n\nVALUES MUTATED BY:\n" +
+				"MUTATOR\tCHANGED         \n" +
+				"ipam   \tname, network.ip\n" +
+				"audit  \t                \n\n"),
+			rel: func() *release.Release {
+				r := releaseMockWithStatus(&release.Status{Code: release.Status_DEPLOYED})
+				r.Info.ValuesMutations = []*release.ValuesMutation{
+					{Mutator: "ipam", Changed: []string{"name", "network.ip"}},
+					{Mutator: "audit"},
+				}
+				return r
+			}(),
+		},
+		{
+			name: "get status of a release with injected containers",
+			args: []string{"flummoxed-chickadee"},
+			expected: outputWithStatus("DEPLOYED\n\nCONTAINERS INJECTED:\n" +
+				"POLICY \tRESOURCE      \tCONTAINERS       \n" +
+				"logging\tDeployment/web\tlog-shipper      \n" +
+				"mesh   \tDeployment/web\tproxy-init, proxy\n\n"),
+			rel: func() *release.Release {
+				r := releaseMockWithStatus(&release.Status{Code: release.Status_DEPLOYED})
+				r.Info.ContainerInjections = []*release.ContainerInjection{
+					{Policy: "logging", Kind: "Deployment", Name: "web", Containers: []string{"log-shipper"}},
+					{Policy: "mesh", Kind: "Deployment", Name: "web", Containers: []string{"proxy-init", "proxy"}},
+				}
+				return r
+			}(),
+		},
 		{
 			name: "get status of a deployed release with test suite",
 			args: []string{"flummoxed-chickadee"},
@@ -19,7 +19,6 @@ package main
 import (
 	"fmt"
 	"io"
-	"io/ioutil"
 	"strings"
 
 	"github.com/ghodss/yaml"
@@ -29,6 +28,7 @@ import (
 	"k8s.io/helm/pkg/helm"
 	"k8s.io/helm/pkg/storage/driver"
 	"k8s.io/helm/pkg/strvals"
+	"k8s.io/helm/pkg/version"
 )
 
 const upgradeDesc = `
@@ -53,30 +53,134 @@ last (right-most) set specified. For example, if both 'bar' and 'newbar' values
 set for a key called 'foo', the 'newbar' value would take precedence:
 
 	$ helm upgrade --set foo=bar --set foo=newbar redis ./redis
+
+The '--set' flag turns values such as 'true' and '1' into booleans and
+numbers. The '--set-string' flag takes the same syntax and keeps every value a
+string. Its values take precedence over those of '--set':
+
+	$ helm upgrade --set-string image.tag=1.10 redis ./redis
+
+The '--profile' flag merges the values of the chart's values-<profile>.yaml file
+over its defaults, and under the values given with '-f' and '--set', as for
+'helm install'. Without it, an upgrade uses the profile of the previous
+revision, as found in the new chart, unless '--reset-values' is set.
+
+Resources that were removed from the chart since the last release are deleted,
+except for resources with the 'helm.sh/resource-policy: keep' annotation and
+resources that another deployed release also declares. The '--keep-removed'
+flag leaves all removed resources in the cluster.
+
+The '--verify-images' flag makes Tiller check, before upgrading anything, that
+the registries have every container image the release uses. Tiller logs in with
+the image pull secrets of the pods and their service accounts. The
+'--verify-references' flag makes it check that the ServiceAccounts, Secrets and
+ConfigMaps that the pods refer to are part of the release or already exist.
+The '--strict-values' flag makes it reject values whose keys match nothing in
+the default values or values schemas of the chart and its subcharts, listing
+all of them.
+
+'--as' and '--as-group' make Tiller act as another user, such as
+'system:serviceaccount:NAMESPACE:NAME', towards Kubernetes while it upgrades
+the release, as with 'helm install'.
+
+Feature flags set with '--flag' apply to this upgrade only: those of earlier
+revisions are not carried over, even with '--reuse-values'. '--defer-notes'
+renders NOTES.txt again once the upgrade is done, as with 'helm install'.
+
+The '--require-approval' flag makes the upgrade stop after the pre-upgrade
+hooks, until someone runs 'helm approve' or 'helm reject' on the release.
+Nothing else is changed in the cluster while the upgrade waits. If it is not
+approved within '--approval-timeout' seconds, the upgrade is rejected.
+
+Pods deleted by '--recreate-pods' or '--force', and resources removed from
+the chart, get the termination grace period of their own spec to shut down.
+'--grace-period' overrides it, and '--propagation-policy' sets how the objects
+that depend on deleted resources are removed ('Foreground', 'Background' or
+'Orphan'). With '--wait', the grace period counts against '--timeout', so it
+should be well below it.
+
+A workload whose label selector changed cannot be patched, as the selector is
+immutable. '--recreate-on-selector-change' deletes such a workload, leaving its
+pods running, and creates it again so that it adopts them. Unlike '--force',
+it only applies when the selector or the pod labels are the sole reason the
+patch was rejected. Tiller logs each workload it recreates.
+
+Resources are applied one at a time. If some cannot be applied, the failed
+revision records which ones were, and '--on-failure' decides what happens
+next. With 'keep', the default, the resources stay as they are and 'helm
+resume' applies the rest later. With 'revert', the whole release goes back to
+the previous revision at once, as 'helm rollback' would; the result is
+recorded as a new revision.
+
+A release stays in the cluster it was installed in. '--cluster' makes sure
+that it is the expected one, for a Tiller that manages several clusters, and
+names the cluster to install the release in with '--install'.
+
+With '--install', '--system' marks a newly installed release as a system
+release. An upgrade keeps whether the release is one.
+
+A release annotated with 'helm.sh/protected-resources', a comma-separated list
+of 'Kind/name' patterns such as 'StatefulSet/db,PersistentVolumeClaim/*',
+cannot be upgraded in a way that adds, changes or deletes the resources that
+match them. The upgrade is aborted before anything is applied, naming the
+resources it would change. Review them, for example with '--dry-run --debug',
+and upgrade with '--allow-protected-changes' to apply them. Set the annotation
+with 'helm annotate'; later upgrades keep it.
+
+A dry run lists the hooks the upgrade would run. To show which hooks would
+replace a resource left behind by an earlier run, it asks the cluster,
+read-only, whether the resources of hooks with the 'before-hook-creation'
+delete policy exist. '--skip-hook-lookup' leaves the cluster alone.
 `
 
 type upgradeCmd struct {
-	release      string
-	chart        string
-	out          io.Writer
-	client       helm.Interface
-	dryRun       bool
-	recreate     bool
-	force        bool
-	disableHooks bool
-	valueFiles   valueFiles
-	values       []string
-	verify       bool
-	keyring      string
-	install      bool
-	namespace    string
-	version      string
-	timeout      int64
-	resetValues  bool
-	reuseValues  bool
-	wait         bool
-	repoURL      string
-	devel        bool
+	release        string
+	chart          string
+	out            io.Writer
+	client         helm.Interface
+	dryRun         bool
+	serverDryRun   bool
+	verifyImages   bool
+	verifyRefs     bool
+	strictValues   bool
+	as             impersonation
+	protected      bool
+	annotations    []string
+	flags          []string
+	approval       bool
+	approvalWait   int64
+	gracePeriod    int64
+	propagation    string
+	selectorChange bool
+	onFailure      string
+	recreate       bool
+	force          bool
+	keepRemoved    bool
+	disableHooks   bool
+	runHooks       bool
+	skipHooks      skipHooks
+	skipLookup     bool
+	valueFiles     valueFiles
+	profile        string
+	allowMissing   bool
+	values         []string
+	stringValues   []string
+	verify         bool
+	keyring        string
+	install        bool
+	namespace      string
+	cluster        string
+	system         bool
+	version        string
+	timeout        int64
+	timeoutBudget  int64
+	resetValues    bool
+	reuseValues    bool
+	wait           bool
+	waitLogBytes   int64
+	deferNotes     bool
+	repoURL        string
+	devel          bool
 
 	certFile string
 	keyFile  string
@@ -114,22 +218,48 @@ func newUpgradeCmd(client helm.Interface, out io.Writer) *cobra.Command {
 	}
 
 	f := cmd.Flags()
-	f.VarP(&upgrade.valueFiles, "values", "f", "specify values in a YAML file (can specify multiple)")
+	f.VarP(&upgrade.valueFiles, "values", "f", "specify values in a YAML file or a URL (can specify multiple)")
+	f.StringVar(&upgrade.profile, "profile", "", "merge the chart's values-<profile>.yaml over its defaults, under the values given with -f and --set")
+	f.BoolVar(&upgrade.allowMissing, "allow-missing-profile", false, "upgrade with the chart's defaults if it has no values file for --profile, instead of failing")
 	f.BoolVar(&upgrade.dryRun, "dry-run", false, "simulate an upgrade")
+	f.BoolVar(&upgrade.serverDryRun, "server-dry-run", false, "simulate an upgrade and print the resources with server defaults applied. Implies --dry-run")
+	f.BoolVar(&upgrade.verifyImages, "verify-images", false, "check that Tiller can find every container image of the release in its registry before upgrading anything")
+	f.BoolVar(&upgrade.verifyRefs, "verify-references", false, "check that the ServiceAccounts, Secrets and ConfigMaps that the pods of the release refer to exist, or are part of the release, before upgrading anything")
+	upgrade.as.addFlags(f, "the upgrade")
+	f.BoolVar(&upgrade.strictValues, "strict-values", false, "fail if the supplied values have keys that match nothing in the defaults or values schemas of the chart and its subcharts")
+	f.BoolVar(&upgrade.protected, "allow-protected-changes", false, "apply the upgrade even if it changes resources that the helm.sh/protected-resources annotation of the release protects")
+	f.StringArrayVar(&upgrade.flags, "flag", []string{}, "set a feature flag that templates read as .Release.Flags.NAME, as NAME or NAME=true|false. Flags are not values, and only apply to this upgrade (can specify multiple)")
+	f.StringArrayVar(&upgrade.annotations, "annotation", []string{}, "record an annotation, such as the commit or pipeline that deployed the revision, as KEY=VALUE (can specify multiple)")
+	f.BoolVar(&upgrade.approval, "require-approval", false, "wait after the pre-upgrade hooks until the upgrade is approved with 'helm approve'")
+	f.Int64Var(&upgrade.approvalWait, "approval-timeout", 3600, "time in seconds to wait for approval before the upgrade is rejected (only used if --require-approval is set)")
 	f.BoolVar(&upgrade.recreate, "recreate-pods", false, "performs pods restart for the resource if applicable")
 	f.BoolVar(&upgrade.force, "force", false, "force resource update through delete/recreate if needed")
+	f.Int64Var(&upgrade.gracePeriod, "grace-period", 0, "time in seconds that deleted pods get to shut down. If 0, each pod's own terminationGracePeriodSeconds is used")
+	f.StringVar(&upgrade.propagation, "propagation-policy", "", "how to delete the objects that depend on deleted resources. One of 'Foreground', 'Background' or 'Orphan'")
+	f.BoolVar(&upgrade.selectorChange, "recreate-on-selector-change", false, "delete and recreate workloads whose label selector changed, leaving their pods running for the new workloads to adopt")
+	f.StringVar(&upgrade.onFailure, "on-failure", "keep", "what to do if some resources cannot be applied: 'keep' the applied ones and the failed revision to resume, or 'revert' the release to the previous revision")
+	f.BoolVar(&upgrade.keepRemoved, "keep-removed", false, "leave resources that were removed from the chart in the cluster instead of deleting them")
 	f.StringArrayVar(&upgrade.values, "set", []string{}, "set values on the command line (can specify multiple or separate values with commas: key1=val1,key2=val2)")
+	f.StringArrayVar(&upgrade.stringValues, "set-string", []string{}, "set STRING values on the command line (can specify multiple or separate values with commas: key1=val1,key2=val2)")
 	f.BoolVar(&upgrade.disableHooks, "disable-hooks", false, "disable pre/post upgrade hooks. DEPRECATED. Use no-hooks")
 	f.BoolVar(&upgrade.disableHooks, "no-hooks", false, "disable pre/post upgrade hooks")
+	f.BoolVar(&upgrade.runHooks, "run-hooks", false, "run pre/post upgrade hooks even if Tiller skips them by default. --no-hooks takes precedence")
+	upgrade.skipHooks.addFlags(f, "upgrade")
+	f.BoolVar(&upgrade.skipLookup, "skip-hook-lookup", false, "with --dry-run, do not ask the cluster which hooks would replace an existing resource")
 	f.BoolVar(&upgrade.verify, "verify", false, "verify the provenance of the chart before upgrading")
 	f.StringVar(&upgrade.keyring, "keyring", defaultKeyring(), "path to the keyring that contains public signing keys")
 	f.BoolVarP(&upgrade.install, "install", "i", false, "if a release by this name doesn't already exist, run an install")
 	f.StringVar(&upgrade.namespace, "namespace", "default", "namespace to install the release into (only used if --install is set)")
+	f.StringVar(&upgrade.cluster, "cluster", "", "fail unless the release is in this cluster, out of those Tiller is configured with. With --install, the cluster to install the release in")
+	f.BoolVar(&upgrade.system, "system", false, "mark the release as a system release if it is installed (only used if --install is set)")
 	f.StringVar(&upgrade.version, "version", "", "specify the exact chart version to use. If this is not specified, the latest version is used")
 	f.Int64Var(&upgrade.timeout, "timeout", 300, "time in seconds to wait for any individual kubernetes operation (like Jobs for hooks)")
+	f.Int64Var(&upgrade.timeoutBudget, "timeout-budget", 0, "if set, time in seconds that the whole upgrade, hooks included, may take. The hooks and the wait for the resources each get the time that is left instead of --timeout")
 	f.BoolVar(&upgrade.resetValues, "reset-values", false, "when upgrading, reset the values to the ones built into the chart")
 	f.BoolVar(&upgrade.reuseValues, "reuse-values", false, "when upgrading, reuse the last release's values, and merge in any new values. If '--reset-values' is specified, this is ignored.")
+	f.Int64Var(&upgrade.waitLogBytes, "wait-log-bytes", 0, "with --wait, print the end of the logs of the containers that are not ready while waiting, in at most this many bytes at a time, and add them to the error if the wait times out. 0 disables it")
 	f.BoolVar(&upgrade.wait, "wait", false, "if set, will wait until all Pods, PVCs, Services, and minimum number of Pods of a Deployment are in a ready state before marking the release as successful. It will wait for as long as --timeout")
+	f.BoolVar(&upgrade.deferNotes, "defer-notes", false, "render NOTES.txt again once the resources are updated and, with --wait, ready, so that the notes can read their live state from .Live")
 	f.StringVar(&upgrade.repoURL, "repo", "", "chart repository url where to locate the requested chart")
 	f.StringVar(&upgrade.certFile, "cert-file", "", "identify HTTPS client using this SSL certificate file")
 	f.StringVar(&upgrade.keyFile, "key-file", "", "identify HTTPS client using this SSL key file")
@@ -142,6 +272,13 @@ func newUpgradeCmd(client helm.Interface, out io.Writer) *cobra.Command {
 }
 
 func (u *upgradeCmd) run() error {
+	if u.serverDryRun {
+		u.dryRun = true
+		if err := checkServerFeature(u.client, version.FeatureServerDryRun, "server-side dry runs"); err != nil {
+			return err
+		}
+	}
+
 	chartPath, err := locateChartPath(u.repoURL, u.chart, u.version, u.verify, u.keyring, u.certFile, u.keyFile, u.caFile)
 	if err != nil {
 		return err
@@ -158,19 +295,38 @@ func (u *upgradeCmd) run() error {
 		if err != nil && strings.Contains(err.Error(), driver.ErrReleaseNotFound(u.release).Error()) {
 			fmt.Fprintf(u.out, "Release %q does not exist. Installing it now.\n", u.release)
 			ic := &installCmd{
-				chartPath:    chartPath,
-				client:       u.client,
-				out:          u.out,
-				name:         u.release,
-				valueFiles:   u.valueFiles,
-				dryRun:       u.dryRun,
-				verify:       u.verify,
-				disableHooks: u.disableHooks,
-				keyring:      u.keyring,
-				values:       u.values,
-				namespace:    u.namespace,
-				timeout:      u.timeout,
-				wait:         u.wait,
+				chartPath:     chartPath,
+				client:        u.client,
+				out:           u.out,
+				name:          u.release,
+				valueFiles:    u.valueFiles,
+				profile:       u.profile,
+				allowMissing:  u.allowMissing,
+				dryRun:        u.dryRun,
+				serverDryRun:  u.serverDryRun,
+				verifyImages:  u.verifyImages,
+				verifyRefs:    u.verifyRefs,
+				strictValues:  u.strictValues,
+				as:            u.as,
+				annotations:   u.annotations,
+				flags:         u.flags,
+				verify:        u.verify,
+				disableHooks:  u.disableHooks,
+				runHooks:      u.runHooks,
+				skipHooks:     u.skipHooks,
+				skipLookup:    u.skipLookup,
+				keyring:       u.keyring,
+				values:        u.values,
+				stringValues:  u.stringValues,
+				namespace:     u.namespace,
+				cluster:       u.cluster,
+				onFailure:     u.onFailure,
+				system:        u.system,
+				timeout:       u.timeout,
+				timeoutBudget: u.timeoutBudget,
+				wait:          u.wait,
+				waitLogBytes:  u.waitLogBytes,
+				deferNotes:    u.deferNotes,
 			}
 			return ic.run()
 		}
@@ -180,6 +336,18 @@ func (u *upgradeCmd) run() error {
 	if err != nil {
 		return err
 	}
+	annotations, err := parseAnnotations(u.annotations)
+	if err != nil {
+		return err
+	}
+	flags, err := parseFeatureFlags(u.flags)
+	if err != nil {
+		return err
+	}
+	identity, err := u.as.identity(u.client)
+	if err != nil {
+		return err
+	}
 
 	// Check chart requirements to make sure all dependencies are present in /charts
 	if ch, err := chartutil.Load(chartPath); err == nil {
@@ -194,25 +362,62 @@ func (u *upgradeCmd) run() error {
 		return prettyError(err)
 	}
 
+	warnGracePeriod(u.out, u.gracePeriod, u.timeout, u.wait)
+	if u.approval && !u.dryRun {
+		fmt.Fprintf(u.out, "Waiting for the upgrade of %q to be approved with 'helm approve %s'\n", u.release, u.release)
+	}
+	stopLogs := func() {}
+	if u.wait && u.waitLogBytes > 0 && !u.dryRun {
+		stopLogs = followWaitLogs(u.client, u.release, u.out)
+	}
 	resp, err := u.client.UpdateRelease(
 		u.release,
 		chartPath,
 		helm.UpdateValueOverrides(rawVals),
+		helm.UpgradeProfile(u.profile),
+		helm.UpgradeAllowMissingProfile(u.allowMissing),
 		helm.UpgradeDryRun(u.dryRun),
+		helm.UpgradeServerDryRun(u.serverDryRun),
+		helm.UpgradeVerifyImages(u.verifyImages),
+		helm.UpgradeVerifyReferences(u.verifyRefs),
+		helm.UpgradeAnnotations(annotations),
+		helm.UpgradeFlags(flags),
+		helm.UpgradeRequireApproval(u.approval),
+		helm.UpgradeApprovalTimeout(u.approvalWait),
 		helm.UpgradeRecreate(u.recreate),
 		helm.UpgradeForce(u.force),
+		helm.UpgradeGracePeriod(u.gracePeriod),
+		helm.UpgradePropagationPolicy(u.propagation),
+		helm.UpgradeRecreateOnSelectorChange(u.selectorChange),
+		helm.UpgradeOnFailure(u.onFailure),
+		helm.UpgradeKeepRemoved(u.keepRemoved),
 		helm.UpgradeDisableHooks(u.disableHooks),
+		helm.UpgradeEnableHooks(u.runHooks),
+		helm.UpgradeCluster(u.cluster),
+		helm.UpgradeSkipHooks(u.skipHooks.names),
+		helm.UpgradeSkipHookWeights(u.skipHooks.int32Weights()),
+		helm.UpgradeSkipHookLookup(u.skipLookup),
 		helm.UpgradeTimeout(u.timeout),
+		helm.UpgradeTimeoutBudget(u.timeoutBudget),
+		helm.UpgradeAllowProtectedChanges(u.protected),
+		helm.UpgradeStrictValues(u.strictValues),
+		helm.UpgradeImpersonation(identity),
 		helm.ResetValues(u.resetValues),
 		helm.ReuseValues(u.reuseValues),
-		helm.UpgradeWait(u.wait))
+		helm.UpgradeWait(u.wait),
+		helm.UpgradeWaitLogBytes(u.waitLogBytes),
+		helm.UpgradeDeferNotes(u.deferNotes))
+	stopLogs()
 	if err != nil {
 		return fmt.Errorf("UPGRADE FAILED: %v", prettyError(err))
 	}
 
-	if settings.Debug {
+	if settings.Debug || u.serverDryRun {
 		printRelease(u.out, resp.Release)
 	}
+	if u.dryRun {
+		fmt.Fprintln(u.out, formatHookPreviews(resp.Hooks, u.disableHooks))
+	}
 
 	fmt.Fprintf(u.out, "Release %q has been upgraded. Happy Helming!\n", u.release)
 
@@ -232,7 +437,7 @@ func (u *upgradeCmd) vals() ([]byte, error) {
 	// User specified a values files via -f/--values
 	for _, filePath := range u.valueFiles {
 		currentMap := map[string]interface{}{}
-		bytes, err := ioutil.ReadFile(filePath)
+		bytes, err := readFile(filePath)
 		if err != nil {
 			return []byte{}, err
 		}
@@ -251,5 +456,20 @@ func (u *upgradeCmd) vals() ([]byte, error) {
 		}
 	}
 
+	// User specified a value via --set-string
+	for _, value := range u.stringValues {
+		if err := strvals.ParseIntoString(value, base); err != nil {
+			return []byte{}, fmt.Errorf("failed parsing --set-string data: %s", err)
+		}
+	}
+
 	return yaml.Marshal(base)
 }
+
+// warnGracePeriod warns that waiting for the resources may time out while
+// deleted pods are still shutting down.
+func warnGracePeriod(out io.Writer, gracePeriod, timeout int64, wait bool) {
+	if wait && gracePeriod > 0 && gracePeriod >= timeout {
+		fmt.Fprintf(out, "WARNING: --grace-period (%ds) is not shorter than --timeout (%ds); the wait may time out before deleted pods have shut down\n", gracePeriod, timeout)
+	}
+}
@@ -138,6 +138,41 @@ func TestUpgradeCmd(t *testing.T) {
 			resp:     releaseMock(&releaseOptions{name: "crazy-bunny", version: 2, chart: ch2}),
 			expected: "Release \"crazy-bunny\" has been upgraded. Happy Helming!\n",
 		},
+		{
+			name:     "upgrade a release with grace period and propagation policy",
+			args:     []string{"crazy-bunny", chartPath},
+			flags:    []string{"--recreate-pods", "--grace-period", "30", "--propagation-policy", "Foreground"},
+			resp:     releaseMock(&releaseOptions{name: "crazy-bunny", version: 2, chart: ch2}),
+			expected: "Release \"crazy-bunny\" has been upgraded. Happy Helming!\n",
+		},
+		{
+			name:     "upgrade a release with a grace period longer than the wait",
+			args:     []string{"crazy-bunny", chartPath},
+			flags:    []string{"--wait", "--timeout", "30", "--grace-period", "30"},
+			resp:     releaseMock(&releaseOptions{name: "crazy-bunny", version: 2, chart: ch2}),
+			expected: "WARNING: --grace-period \\(30s\\) is not shorter than --timeout \\(30s\\)",
+		},
+		{
+			name:     "upgrade a release recreating workloads whose selector changed",
+			args:     []string{"crazy-bunny", chartPath},
+			flags:    []string{"--recreate-on-selector-change"},
+			resp:     releaseMock(&releaseOptions{name: "crazy-bunny", version: 2, chart: ch2}),
+			expected: "Release \"crazy-bunny\" has been upgraded. Happy Helming!\n",
+		},
+		{
+			name:     "upgrade a release with a values profile",
+			args:     []string{"crazy-bunny", chartPath},
+			flags:    []string{"--profile", "prod"},
+			resp:     releaseMock(&releaseOptions{name: "crazy-bunny", version: 2, chart: ch2}),
+			expected: "Release \"crazy-bunny\" has been upgraded. Happy Helming!\n",
+		},
+		{
+			name:     "upgrade a release with approval",
+			args:     []string{"crazy-bunny", chartPath},
+			flags:    []string{"--require-approval", "--approval-timeout", "600"},
+			resp:     releaseMock(&releaseOptions{name: "crazy-bunny", version: 2, chart: ch2}),
+			expected: "Waiting for the upgrade of \"crazy-bunny\" to be approved with 'helm approve crazy-bunny'\nRelease \"crazy-bunny\" has been upgraded. Happy Helming!\n",
+		},
 		{
 			name: "upgrade a release with missing dependencies",
 			args: []string{"bonkers-bunny", missingDepsPath},
@@ -0,0 +1,96 @@
+/*
+Copyright 2017 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"k8s.io/helm/pkg/getter"
+)
+
+// maxRemoteValuesSize is the largest values file that will be accepted from a URL.
+const maxRemoteValuesSize = 5 * 1024 * 1024
+
+// readFile loads a values file from the local filesystem or, if filePath is a
+// URL with a scheme handled by one of the registered getters, downloads it.
+//
+// Remote files are rejected if they are too large or do not look like text,
+// and any credentials in the URL are redacted from errors and debug output.
+func readFile(filePath string) ([]byte, error) {
+	u, err := url.Parse(filePath)
+	if err != nil || u.Scheme == "" || len(u.Scheme) == 1 {
+		// Not a URL (or a Windows drive letter); read it from disk.
+		return ioutil.ReadFile(filePath)
+	}
+
+	redacted := redactURL(u)
+	newGetter, err := getter.All(settings).ByScheme(u.Scheme)
+	if err != nil {
+		return nil, fmt.Errorf("cannot fetch values file %s: %s", redacted, err)
+	}
+	g, err := newGetter(filePath, "", "", "")
+	if err != nil {
+		return nil, fmt.Errorf("cannot fetch values file %s: %s", redacted, redactError(err, filePath, redacted))
+	}
+
+	debug("fetching values file %s", redacted)
+	var buf *bytes.Buffer
+	if lg, ok := g.(getter.LimitedGetter); ok {
+		// Read one byte past the limit to tell a file of exactly the
+		// limit from a larger one.
+		buf, err = lg.GetLimited(filePath, maxRemoteValuesSize+1)
+	} else {
+		buf, err = g.Get(filePath)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch values file %s: %s", redacted, redactError(err, filePath, redacted))
+	}
+	if buf.Len() > maxRemoteValuesSize {
+		return nil, fmt.Errorf("values file %s is larger than %d bytes", redacted, maxRemoteValuesSize)
+	}
+
+	data := buf.Bytes()
+	if ct := http.DetectContentType(data); !strings.HasPrefix(ct, "text/plain") {
+		return nil, fmt.Errorf("values file %s has unexpected content type %q", redacted, ct)
+	}
+	return data, nil
+}
+
+// redactURL returns the URL as a string without the parts that commonly carry
+// credentials. The user information, which may be a token given as the
+// username, is replaced and the query, which may hold a signature or an API
+// key, is dropped.
+func redactURL(u *url.URL) string {
+	r := *u
+	if r.User != nil {
+		r.User = url.User("xxxxx")
+	}
+	r.RawQuery = ""
+	r.ForceQuery = false
+	return r.String()
+}
+
+// redactError removes the raw URL from an error message, since errors from
+// the HTTP client echo the URL they were given.
+func redactError(err error, raw, redacted string) string {
+	return strings.Replace(err.Error(), raw, redacted, -1)
+}
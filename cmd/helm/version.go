@@ -20,6 +20,7 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"strings"
 
 	"github.com/spf13/cobra"
 	"google.golang.org/grpc"
@@ -44,6 +45,9 @@ Server: &version.Version{SemVer:"v2.0.0", GitCommit:"b0c113dfb9f612a9add796549da
 - GitTreeState is "clean" if there are no local code changes when this binary was
   built, and "dirty" if the binary was built from locally modified code.
 
+Tiller also reports the optional features it supports, such as
+"server-dry-run" on Kubernetes 1.13 or later, and its storage driver.
+
 To print just the client version, use '--client'. To print just the server version,
 use '--server'.
 `
@@ -108,9 +112,30 @@ func (v *versionCmd) run() error {
 		return errors.New("cannot connect to Tiller")
 	}
 	fmt.Fprintf(v.out, "Server: %s\n", formatVersion(resp.Version, v.short))
+	// Older servers do not report their features or storage driver.
+	if !v.short && resp.StorageDriver != "" {
+		fmt.Fprintf(v.out, "Server storage driver: %s\n", resp.StorageDriver)
+		fmt.Fprintf(v.out, "Server features: %s\n", strings.Join(resp.Features, ", "))
+	}
 	return nil
 }
 
+// checkServerFeature returns an error if Tiller reports that it does not
+// support the named feature, which what describes. Servers too old to report
+// their features are assumed to support it, and any error in asking is left
+// for the request that needs the feature to surface.
+func checkServerFeature(c helm.Interface, feature, what string) error {
+	resp, err := c.GetVersion()
+	if err != nil {
+		debug("cannot get the features of Tiller: %s", err)
+		return nil
+	}
+	if resp.StorageDriver == "" || version.HasFeature(resp.Features, feature) {
+		return nil
+	}
+	return fmt.Errorf("Tiller does not support %s (feature %q)", what, feature)
+}
+
 func formatVersion(v *pb.Version, short bool) string {
 	if short {
 		return fmt.Sprintf("%s+g%s", v.SemVer, v.GitCommit[:7])
@@ -20,6 +20,8 @@ import (
 	"strings"
 	"testing"
 
+	rls "k8s.io/helm/pkg/proto/hapi/services"
+	pb "k8s.io/helm/pkg/proto/hapi/version"
 	"k8s.io/helm/pkg/version"
 )
 
@@ -61,3 +63,62 @@ func TestVersion(t *testing.T) {
 		}
 	}
 }
+
+func TestVersionFeatures(t *testing.T) {
+	settings.TillerHost = "fake-localhost"
+	b := new(bytes.Buffer)
+	c := &fakeReleaseClient{version: &rls.GetVersionResponse{
+		Version:       &pb.Version{SemVer: "1.2.3-fakeclient+testonly"},
+		Features:      []string{version.FeatureServerDryRun, version.FeatureReleaseEvents},
+		StorageDriver: "ConfigMap",
+	}}
+
+	cmd := newVersionCmd(c, b)
+	args := []string{"-s"}
+	cmd.ParseFlags(args)
+	if err := cmd.RunE(cmd, args); err != nil {
+		t.Fatal(err)
+	}
+	for _, expect := range []string{"Server storage driver: ConfigMap\n", "Server features: server-dry-run, release-events\n"} {
+		if !strings.Contains(b.String(), expect) {
+			t.Errorf("Expected %q to contain %q", b.String(), expect)
+		}
+	}
+}
+
+func TestCheckServerFeature(t *testing.T) {
+	tests := []struct {
+		name    string
+		version *rls.GetVersionResponse
+		err     bool
+	}{
+		{
+			name: "old server",
+			// The fake client reports a version without features.
+		},
+		{
+			name:    "supported",
+			version: &rls.GetVersionResponse{Features: []string{version.FeatureServerDryRun}, StorageDriver: "Memory"},
+		},
+		{
+			name:    "unsupported",
+			version: &rls.GetVersionResponse{Features: []string{version.FeatureReleaseEvents}, StorageDriver: "Memory"},
+			err:     true,
+		},
+		{
+			name:    "no features",
+			version: &rls.GetVersionResponse{StorageDriver: "Memory"},
+			err:     true,
+		},
+	}
+	for _, tt := range tests {
+		c := &fakeReleaseClient{version: tt.version}
+		err := checkServerFeature(c, version.FeatureServerDryRun, "server-side dry runs")
+		if tt.err && (err == nil || err.Error() != `Tiller does not support server-side dry runs (feature "server-dry-run")`) {
+			t.Errorf("%s: expected an error, got %v", tt.name, err)
+		}
+		if !tt.err && err != nil {
+			t.Errorf("%s: unexpected error: %s", tt.name, err)
+		}
+	}
+}
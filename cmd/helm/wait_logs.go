@@ -0,0 +1,56 @@
+/*
+Copyright 2017 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"fmt"
+	"io"
+	"sync"
+
+	"k8s.io/helm/pkg/helm"
+)
+
+// followWaitLogs prints the logs that Tiller streams while an operation on
+// the named release waits, see --wait-log-bytes, to out as they come. The
+// returned stop ends the printing once the operation returned, and waits for
+// the logs that are being printed.
+func followWaitLogs(client helm.Interface, name string, out io.Writer) (stop func()) {
+	logs, errc := client.WatchWaitLogs(name)
+	var mu sync.Mutex
+	stopped := false
+	go func() {
+		// logs is nil if Tiller could not be reached.
+		if logs != nil {
+			for l := range logs {
+				mu.Lock()
+				if !stopped {
+					fmt.Fprintf(out, "Waiting for %s; logs of the containers that are not ready:\n%s", name, l.Logs)
+				}
+				mu.Unlock()
+			}
+		}
+		// A Tiller that cannot stream logs leaves the operation as it is.
+		if err := <-errc; err != nil {
+			debug("cannot follow the logs of the wait: %s", err)
+		}
+	}()
+	return func() {
+		mu.Lock()
+		stopped = true
+		mu.Unlock()
+	}
+}
@@ -0,0 +1,47 @@
+/*
+Copyright 2017 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+// chanWriter sends what is written to it on the channel.
+type chanWriter chan string
+
+func (w chanWriter) Write(p []byte) (int, error) {
+	w <- string(p)
+	return len(p), nil
+}
+
+func TestFollowWaitLogs(t *testing.T) {
+	c := &fakeReleaseClient{waitLogs: []string{"==> default/web-1/app <==\nstarting\n"}}
+	out := make(chanWriter, 1)
+	stop := followWaitLogs(c, "aeneas", out)
+	defer stop()
+
+	select {
+	case got := <-out:
+		expect := "Waiting for aeneas; logs of the containers that are not ready:\n==> default/web-1/app <==\nstarting\n"
+		if got != expect {
+			t.Errorf("Expected %q, got %q", expect, got)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Expected the logs to be printed")
+	}
+}
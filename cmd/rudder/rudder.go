@@ -90,7 +90,7 @@ func (r *ReleaseModuleServiceServer) DeleteRelease(ctx context.Context, in *rudd
 		return resp, fmt.Errorf("Could not get apiVersions from Kubernetes: %v", err)
 	}
 
-	kept, errs := tiller.DeleteRelease(rel, vs, kubeClient)
+	kept, errs := tiller.DeleteRelease(rel, vs, kubeClient, in.PropagationPolicy, in.Timeout, in.Wait)
 	rel.Manifest = kept
 
 	allErrors := ""
@@ -112,7 +112,15 @@ func (r *ReleaseModuleServiceServer) RollbackRelease(ctx context.Context, in *ru
 	grpclog.Print("rollback")
 	c := bytes.NewBufferString(in.Current.Manifest)
 	t := bytes.NewBufferString(in.Target.Manifest)
-	err := kubeClient.Update(in.Target.Namespace, c, t, in.Force, in.Recreate, in.Timeout, in.Wait)
+	err := kubeClient.UpdateWithOptions(in.Target.Namespace, c, t, kube.UpdateOptions{
+		Force:                    in.Force,
+		Recreate:                 in.Recreate,
+		Timeout:                  in.Timeout,
+		Wait:                     in.Wait,
+		GracePeriod:              in.GracePeriod,
+		PropagationPolicy:        in.PropagationPolicy,
+		RecreateOnSelectorChange: in.RecreateOnSelectorChange,
+	})
 	return &rudderAPI.RollbackReleaseResponse{}, err
 }
 
@@ -121,7 +129,15 @@ func (r *ReleaseModuleServiceServer) UpgradeRelease(ctx context.Context, in *rud
 	grpclog.Print("upgrade")
 	c := bytes.NewBufferString(in.Current.Manifest)
 	t := bytes.NewBufferString(in.Target.Manifest)
-	err := kubeClient.Update(in.Target.Namespace, c, t, in.Force, in.Recreate, in.Timeout, in.Wait)
+	err := kubeClient.UpdateWithOptions(in.Target.Namespace, c, t, kube.UpdateOptions{
+		Force:                    in.Force,
+		Recreate:                 in.Recreate,
+		Timeout:                  in.Timeout,
+		Wait:                     in.Wait,
+		GracePeriod:              in.GracePeriod,
+		PropagationPolicy:        in.PropagationPolicy,
+		RecreateOnSelectorChange: in.RecreateOnSelectorChange,
+	})
 	// upgrade response object should be changed to include status
 	return &rudderAPI.UpgradeReleaseResponse{}, err
 }
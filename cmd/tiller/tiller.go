@@ -18,6 +18,7 @@ package main // import "k8s.io/helm/cmd/tiller"
 
 import (
 	"crypto/tls"
+	"errors"
 	"fmt"
 	"io/ioutil"
 	"log"
@@ -26,6 +27,7 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+	"time"
 
 	goprom "github.com/grpc-ecosystem/go-grpc-prometheus"
 	"github.com/spf13/cobra"
@@ -33,8 +35,12 @@ import (
 
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials"
+	"k8s.io/client-go/tools/clientcmd"
 
+	"k8s.io/helm/pkg/chartutil"
+	"k8s.io/helm/pkg/engine"
 	"k8s.io/helm/pkg/kube"
+	"k8s.io/helm/pkg/logging"
 	"k8s.io/helm/pkg/proto/hapi/services"
 	"k8s.io/helm/pkg/storage"
 	"k8s.io/helm/pkg/storage/driver"
@@ -55,9 +61,18 @@ const (
 	tlsCertsEnvVar = "TILLER_TLS_CERTS"
 )
 
+// deletedReleasePurgeInterval is how often deleted releases are checked for an
+// expired retention period.
+const deletedReleasePurgeInterval = time.Minute
+
+// storageMigrationRetryInterval is how long a failed storage migration waits
+// before it is retried.
+const storageMigrationRetryInterval = time.Minute
+
 const (
 	storageMemory    = "memory"
 	storageConfigMap = "configmap"
+	storageS3        = "s3"
 )
 
 // rootServer is the root gRPC server.
@@ -72,13 +87,55 @@ var env = environment.New()
 
 var logger *log.Logger
 
+// structuredLogger is the leveled logger shared by Tiller's components.
+var structuredLogger logging.Logger
+
 var (
 	grpcAddr             = ":44134"
 	probeAddr            = ":44135"
 	traceAddr            = ":44136"
 	enableTracing        = false
 	store                = storageConfigMap
+	storageNamespace     = ""
+	migrateFrom          = ""
+	migrateFromNamespace = ""
+	storageWriteAttempts = storage.DefaultWriteAttempts
+	s3Bucket             = ""
+	s3Region             = ""
+	s3Endpoint           = ""
+	s3Prefix             = ""
 	remoteReleaseModules = false
+	readinessGates       []string
+	readyThresholds      []string
+	waitForWebhooks      = false
+	hpaStabilization     time.Duration
+	waitLogBytes         int64
+	patchStrategy        = ""
+	failOnMissing        = false
+	applyConcurrency     = 1
+	fieldManager         = kube.DefaultFieldManager
+	emitEvents           = false
+	eventQPS             float32
+	releaseNamePattern   = ""
+	releaseNameMaxLength int
+	deletedRetention     time.Duration
+	lockTimeout          time.Duration
+	discoveryCacheTTL    time.Duration
+	templateEnv          []string
+	templateEnvStrict    = false
+	templateTimeout      time.Duration
+	templateIncludeDepth int
+	storeComputedValues  = false
+	allowedNamespaces    []string
+	clusterConfigs       []string
+	disableHooks         []string
+	maxValuesDepth       int
+	maxValuesSize        int
+	valuesMutators       []string
+	valuesMutatorTimeout time.Duration
+	injectionPolicies    = ""
+	logFormat            = "text"
+	logLevel             = "info"
 )
 
 var (
@@ -98,9 +155,48 @@ By default, Tiller listens for gRPC connections on port 44134.
 
 func addFlags(flags *pflag.FlagSet) {
 	flags.StringVarP(&grpcAddr, "listen", "l", ":44134", "address:port to listen on")
-	flags.StringVar(&store, "storage", storageConfigMap, "storage driver to use. One of 'configmap' or 'memory'")
+	flags.StringVar(&store, "storage", storageConfigMap, "storage driver to use. One of 'configmap', 'memory' or 's3'")
+	flags.StringVar(&storageNamespace, "storage-namespace", "", "namespace to store release records in. Defaults to the namespace Tiller runs in")
+	flags.IntVar(&storageWriteAttempts, "storage-write-attempts", storage.DefaultWriteAttempts, "how often a release record is written when the write conflicts with another writer, e.g. on a ResourceQuota of the storage namespace")
+	flags.StringVar(&s3Bucket, "storage-s3-bucket", "", "S3 bucket to store release records in with --storage=s3. Credentials are read from AWS_ACCESS_KEY_ID, AWS_SECRET_ACCESS_KEY and AWS_SESSION_TOKEN")
+	flags.StringVar(&s3Region, "storage-s3-region", os.Getenv("AWS_REGION"), "region of the S3 bucket. Defaults to AWS_REGION")
+	flags.StringVar(&s3Endpoint, "storage-s3-endpoint", "", "URL of a store with the S3 API other than Amazon S3, e.g. MinIO")
+	flags.StringVar(&s3Prefix, "storage-s3-prefix", "tiller/", "prefix of the keys of the release records in the S3 bucket")
+	flags.StringVar(&migrateFrom, "storage-migrate-from", "", "storage driver to move release records from to --storage while serving requests. Only 'configmap' is supported")
+	flags.StringVar(&migrateFromNamespace, "storage-migrate-from-namespace", "", "namespace of the release records moved with --storage-migrate-from. Defaults to the namespace Tiller runs in")
 	flags.BoolVar(&enableTracing, "trace", false, "enable rpc tracing")
 	flags.BoolVar(&remoteReleaseModules, "experimental-release", false, "enable experimental release modules")
+	flags.StringArrayVar(&readinessGates, "readiness-gate", []string{}, "when waiting, also wait for resources of a kind to report a condition as true, e.g. 'certmanager.k8s.io/v1alpha1/Certificate=Ready' (can specify multiple)")
+	flags.StringArrayVar(&readyThresholds, "ready-threshold", []string{}, "when waiting, consider workloads of a kind ready once this share of their desired pods is, e.g. 'DaemonSet=0.9' or 'DaemonSet=90%' (can specify multiple)")
+	flags.BoolVar(&waitForWebhooks, "wait-for-webhooks", false, "when installing, pause after each admission webhook configuration until its service has ready endpoints")
+	flags.DurationVar(&hpaStabilization, "wait-for-hpa-stabilization", 0, "when waiting, also wait until each HorizontalPodAutoscaler has had its desired number of replicas, unchanged, for this long. 0 disables the check")
+	flags.Int64Var(&waitLogBytes, "wait-log-bytes", 0, "when a wait times out, add the end of the logs of the containers that are not ready to the error, in at most this many bytes. 0 disables it")
+	flags.StringVar(&patchStrategy, "patch-strategy", "", "how upgrades patch resources that have no helm.sh/patch-strategy annotation: 'strategic', 'merge' or 'three-way'. Defaults to 'strategic', with JSON merge patches for custom resources")
+	flags.IntVar(&applyConcurrency, "apply-concurrency", 1, "how many resources of the same kind installs and upgrades create or update at once. Resources of different kinds are still applied in install order")
+	flags.BoolVar(&failOnMissing, "fail-on-missing-resources", false, "fail upgrades and rollbacks that find a resource of the release deleted outside of Helm, instead of creating it again")
+	flags.StringVar(&fieldManager, "field-manager", kube.DefaultFieldManager, "name Tiller uses for itself when reporting conflicting updates to resources")
+	flags.BoolVar(&emitEvents, "emit-events", false, "emit a Kubernetes Event in the release namespace whenever a release changes status")
+	flags.Float32Var(&eventQPS, "event-qps", 1, "average number of events per second that --emit-events may create. Events over the limit are dropped")
+	flags.StringArrayVar(&templateEnv, "template-env", []string{}, "name of an environment variable of Tiller that templates may read with the 'env' function (can specify multiple)")
+	flags.BoolVar(&templateEnvStrict, "template-env-strict", false, "fail to render templates that read an environment variable not given with --template-env, instead of reading it as empty")
+	flags.DurationVar(&templateTimeout, "template-timeout", time.Minute, "how long rendering the templates of a release may take before it fails. 0 means no limit")
+	flags.IntVar(&templateIncludeDepth, "template-max-include-depth", engine.DefaultMaxIncludeDepth, "how deeply calls to the 'include' template function may nest before rendering fails")
+	flags.BoolVar(&storeComputedValues, "store-computed-values", false, "store the values each release revision was rendered with, including the chart's defaults")
+	flags.StringArrayVar(&allowedNamespaces, "allowed-namespace", []string{}, "namespace, other than its own, that a release may put resources in (can specify multiple). By default, any namespace may be used")
+	flags.StringArrayVar(&clusterConfigs, "cluster", []string{}, "cluster, other than its own, that releases may be installed in, as NAME=KUBECONFIG or NAME=KUBECONFIG:CONTEXT. Without a context, the current context of the kubeconfig is used (can specify multiple)")
+	flags.StringArrayVar(&disableHooks, "disable-hooks", []string{}, "operation whose hooks are skipped unless a request enables them, one of 'install', 'upgrade', 'rollback' or 'uninstall' (can specify multiple)")
+	flags.IntVar(&maxValuesDepth, "max-values-depth", chartutil.DefaultMaxValuesDepth, "how deeply the values of a release may nest. 0 means no limit")
+	flags.IntVar(&maxValuesSize, "max-values-size", chartutil.DefaultMaxValuesSize, "limit, in bytes, of the supplied values and the chart's values files of a release together. 0 means no limit")
+	flags.StringArrayVar(&valuesMutators, "values-mutator", []string{}, "command that changes the values of releases before they are rendered, as NAME=COMMAND. The command reads the values as JSON on stdin and writes the values to use to stdout. Mutators run in the order given (can specify multiple)")
+	flags.DurationVar(&valuesMutatorTimeout, "values-mutator-timeout", 30*time.Second, "how long a --values-mutator command may run before it is killed and the operation fails. 0 means no limit")
+	flags.StringVar(&injectionPolicies, "injection-policies", "", "path to a YAML list of policies that add init containers and containers to the workloads of releases whose Pod templates match a label selector. Workloads opt out with the helm.sh/skip-injection annotation")
+	flags.DurationVar(&discoveryCacheTTL, "discovery-cache-ttl", 0, "how long to reuse the API versions discovered in a cluster instead of discovering them for every operation. Releases that add CustomResourceDefinitions invalidate the cache. 0 disables the cache")
+	flags.DurationVar(&deletedRetention, "deleted-release-retention", 0, "how long to keep the records of deleted releases, so that they can be restored, before purging them. 0 keeps them forever")
+	flags.DurationVar(&lockTimeout, "lock-timeout", tiller.DefaultLockTimeout, "how long an operation on a release waits for another operation on the same release to finish before it fails")
+	flags.IntVar(&releaseNameMaxLength, "release-name-max-length", 0, "maximum length of new release names, up to 63. Defaults to 53, which leaves charts 10 characters for suffixes")
+	flags.StringVar(&releaseNamePattern, "release-name-pattern", "", "pattern for the names of releases installed without one, e.g. '{chart}-{timestamp}'. Supports {chart}, {timestamp} and {moniker}. Defaults to a random name")
+	flags.StringVar(&logFormat, "log-format", "text", "log output format. One of 'text' or 'json'")
+	flags.StringVar(&logLevel, "log-level", "info", "minimum level to log. One of 'debug', 'info', 'warn' or 'error'")
 
 	flags.BoolVar(&tlsEnable, "tls", tlsEnableEnvVarDefault(), "enable TLS")
 	flags.BoolVar(&tlsVerify, "tls-verify", tlsVerifyEnvVarDefault(), "enable TLS and verify remote certificate")
@@ -114,6 +210,16 @@ func initLog() {
 		log.SetFlags(log.Lshortfile)
 	}
 	logger = newLogger("main")
+
+	format, err := logging.ParseFormat(logFormat)
+	if err != nil {
+		logger.Fatal(err)
+	}
+	level, err := logging.ParseLevel(logLevel)
+	if err != nil {
+		logger.Fatal(err)
+	}
+	structuredLogger = logging.New(os.Stderr, format, level)
 }
 
 func main() {
@@ -140,26 +246,87 @@ func newLogger(prefix string) *log.Logger {
 	return log.New(os.Stderr, prefix, log.Flags())
 }
 
+// componentLog returns a printf-style function that logs at info level with
+// the component name as a field.
+func componentLog(component string) func(string, ...interface{}) {
+	return logging.Printf(structuredLogger.With("component", component))
+}
+
 func start(c *cobra.Command, args []string) {
 	clientset, err := kube.New(nil).ClientSet()
 	if err != nil {
 		logger.Fatalf("Cannot initialize Kubernetes connection: %s", err)
 	}
 
+	var d driver.Driver
 	switch store {
 	case storageMemory:
-		env.Releases = storage.Init(driver.NewMemory())
+		d = driver.NewInstrumented(driver.NewMemory())
 	case storageConfigMap:
-		cfgmaps := driver.NewConfigMaps(clientset.Core().ConfigMaps(namespace()))
-		cfgmaps.Log = newLogger("storage/driver").Printf
+		ns := namespace()
+		if storageNamespace == "" {
+			storageNamespace = ns
+		}
+		cfgmaps := driver.NewConfigMaps(clientset.Core().ConfigMaps(storageNamespace))
+		cfgmaps.Log = componentLog("storage/driver")
+		if storageNamespace != ns && migrateFrom == "" {
+			// Releases recorded before the storage namespace was changed
+			// are still looked up in Tiller's namespace.
+			cfgmaps.Legacy = clientset.Core().ConfigMaps(ns)
+		}
+		d = driver.NewInstrumented(cfgmaps)
+	case storageS3:
+		s3, err := driver.NewS3Store(driver.S3Config{
+			Bucket:          s3Bucket,
+			Region:          s3Region,
+			Endpoint:        s3Endpoint,
+			AccessKeyID:     os.Getenv("AWS_ACCESS_KEY_ID"),
+			SecretAccessKey: os.Getenv("AWS_SECRET_ACCESS_KEY"),
+			SessionToken:    os.Getenv("AWS_SESSION_TOKEN"),
+		})
+		if err != nil {
+			logger.Fatalf("Cannot initialize S3 storage: %s", err)
+		}
+		objs := driver.NewObjects(s3, s3Prefix)
+		objs.Log = componentLog("storage/driver")
+		d = driver.NewInstrumented(objs)
+	}
 
-		env.Releases = storage.Init(cfgmaps)
-		env.Releases.Log = newLogger("storage").Printf
+	if migrateFrom != "" {
+		if migrateFrom != storageConfigMap {
+			logger.Fatalf("Invalid --storage-migrate-from %q: only %q is supported", migrateFrom, storageConfigMap)
+		}
+		if store == storageMemory {
+			logger.Fatalf("Invalid --storage-migrate-from: release records cannot be migrated to %q storage, which does not outlive Tiller", storageMemory)
+		}
+		if migrateFromNamespace == "" {
+			migrateFromNamespace = namespace()
+		}
+		if store == storageConfigMap && migrateFromNamespace == storageNamespace {
+			logger.Fatalf("Invalid --storage-migrate-from: release records are already stored in ConfigMaps in namespace %q", storageNamespace)
+		}
+		old := driver.NewConfigMaps(clientset.Core().ConfigMaps(migrateFromNamespace))
+		old.Log = componentLog("storage/driver")
+		m := driver.NewMigrating(driver.NewInstrumented(old), d)
+		m.Log = componentLog("storage/migration")
+		d = m
+		go migrateStorage(m)
 	}
 
-	kubeClient := kube.New(nil)
-	kubeClient.Log = newLogger("kube").Printf
-	env.KubeClient = kubeClient
+	env.Releases = storage.Init(d)
+	env.Releases.WriteAttempts = storageWriteAttempts
+	if store != storageMemory {
+		env.Releases.Log = componentLog("storage")
+	}
+
+	env.KubeClient = newKubeClient(nil)
+
+	if e, ok := env.EngineYard[environment.GoTplEngine].(*engine.Engine); ok {
+		e.EnvAllowlist = templateEnv
+		e.StrictEnv = templateEnvStrict
+		e.Timeout = templateTimeout
+		e.MaxIncludeDepth = templateIncludeDepth
+	}
 
 	if tlsEnable || tlsVerify {
 		opts := tlsutil.Options{CertFile: certFile, KeyFile: keyFile}
@@ -197,7 +364,51 @@ func start(c *cobra.Command, args []string) {
 	probeErrCh := make(chan error)
 	go func() {
 		svc := tiller.NewReleaseServer(env, clientset, remoteReleaseModules)
-		svc.Log = newLogger("tiller").Printf
+		svc.SetLogger(structuredLogger.With("component", "tiller"))
+		if emitEvents {
+			svc.EnableEvents(eventQPS)
+		}
+		if releaseNamePattern != "" {
+			svc.SetNameGenerator(tiller.PatternNameGenerator{Pattern: releaseNamePattern})
+		}
+		if releaseNameMaxLength != 0 {
+			if err := svc.SetReleaseNameMaxLength(releaseNameMaxLength); err != nil {
+				logger.Fatalf("Invalid --release-name-max-length: %s", err)
+			}
+		}
+		svc.SetLockTimeout(lockTimeout)
+		svc.StoreComputedValues(storeComputedValues)
+		svc.SetDiscoveryCacheTTL(discoveryCacheTTL)
+		if len(allowedNamespaces) > 0 {
+			svc.SetAllowedNamespaces(allowedNamespaces)
+		}
+		svc.SetValuesLimits(chartutil.ValuesLimits{MaxDepth: maxValuesDepth, MaxSize: maxValuesSize})
+		if err := svc.DisableHooksByDefault(disableHooks); err != nil {
+			logger.Fatalf("Invalid --disable-hooks: %s", err)
+		}
+		for _, m := range valuesMutators {
+			name, command, err := parseValuesMutator(m)
+			if err == nil {
+				err = svc.AddValuesMutator(name, tiller.ExecValuesMutator{Command: command, Timeout: valuesMutatorTimeout})
+			}
+			if err != nil {
+				logger.Fatalf("Invalid --values-mutator %q: %s", m, err)
+			}
+		}
+		if injectionPolicies != "" {
+			if err := setInjectionPolicies(svc, injectionPolicies); err != nil {
+				logger.Fatalf("Invalid --injection-policies %q: %s", injectionPolicies, err)
+			}
+		}
+		for _, c := range clusterConfigs {
+			if err := addCluster(svc, c); err != nil {
+				logger.Fatalf("Invalid --cluster %q: %s", c, err)
+			}
+		}
+		if deletedRetention > 0 {
+			svc.SetDeletedReleaseRetention(deletedRetention)
+			go purgeExpiredReleases(svc)
+		}
 		services.RegisterReleaseServiceServer(rootServer, svc)
 		if err := rootServer.Serve(lstn); err != nil {
 			srvErrCh <- err
@@ -224,6 +435,126 @@ func start(c *cobra.Command, args []string) {
 	}
 }
 
+// newKubeClient returns a Kubernetes client for config, or for the cluster
+// Tiller runs in if config is nil, with the options given to Tiller.
+func newKubeClient(config clientcmd.ClientConfig) *kube.Client {
+	kubeClient := kube.New(config)
+	kubeClient.Log = componentLog("kube")
+	for _, g := range readinessGates {
+		gate, err := kube.ParseReadinessGate(g)
+		if err != nil {
+			logger.Fatal(err)
+		}
+		kubeClient.ReadinessGates = append(kubeClient.ReadinessGates, gate)
+	}
+	for _, t := range readyThresholds {
+		threshold, err := kube.ParseReadyThreshold(t)
+		if err != nil {
+			logger.Fatal(err)
+		}
+		kubeClient.ReadyThresholds = append(kubeClient.ReadyThresholds, threshold)
+	}
+	kubeClient.WaitForWebhooks = waitForWebhooks
+	kubeClient.HPAStabilization = hpaStabilization
+	kubeClient.WaitLogBytes = waitLogBytes
+	if err := kube.ValidPatchStrategy(patchStrategy); err != nil {
+		logger.Fatalf("Invalid --patch-strategy: %s", err)
+	}
+	kubeClient.PatchStrategy = patchStrategy
+	kubeClient.FailOnMissing = failOnMissing
+	if applyConcurrency < 1 {
+		logger.Fatalf("Invalid --apply-concurrency %d: must be at least 1", applyConcurrency)
+	}
+	kubeClient.ApplyConcurrency = applyConcurrency
+	kubeClient.FieldManager = fieldManager
+	return kubeClient
+}
+
+// setInjectionPolicies gives svc the injection policies in the file at path.
+func setInjectionPolicies(svc *tiller.ReleaseServer, path string) error {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	policies, err := tiller.LoadInjectionPolicies(data)
+	if err != nil {
+		return err
+	}
+	return svc.SetInjectionPolicies(policies)
+}
+
+// addCluster configures svc with the cluster in c, given as
+// NAME=KUBECONFIG[:CONTEXT]. The kubeconfig is loaded right away, so that a
+// cluster that cannot be used stops Tiller from starting.
+func addCluster(svc *tiller.ReleaseServer, c string) error {
+	name, path, context, err := parseClusterConfig(c)
+	if err != nil {
+		return err
+	}
+	config := kube.GetConfigFromFile(path, context)
+	if _, err := config.ClientConfig(); err != nil {
+		return err
+	}
+	kubeClient := newKubeClient(config)
+	clientset, err := kubeClient.ClientSet()
+	if err != nil {
+		return err
+	}
+	if err := svc.AddCluster(name, kubeClient, clientset); err != nil {
+		return err
+	}
+	logger.Printf("Releases may be installed in cluster %q (%s)", name, path)
+	return nil
+}
+
+// parseClusterConfig splits NAME=KUBECONFIG[:CONTEXT].
+func parseClusterConfig(c string) (name, path, context string, err error) {
+	i := strings.Index(c, "=")
+	if i <= 0 || i == len(c)-1 {
+		return "", "", "", errors.New("expected NAME=KUBECONFIG or NAME=KUBECONFIG:CONTEXT")
+	}
+	name, path = c[:i], c[i+1:]
+	if j := strings.LastIndex(path, ":"); j >= 0 {
+		path, context = path[:j], path[j+1:]
+		if path == "" || context == "" {
+			return "", "", "", errors.New("expected NAME=KUBECONFIG or NAME=KUBECONFIG:CONTEXT")
+		}
+	}
+	return name, path, context, nil
+}
+
+// parseValuesMutator splits NAME=COMMAND.
+func parseValuesMutator(m string) (name, command string, err error) {
+	i := strings.Index(m, "=")
+	if i <= 0 || i == len(m)-1 {
+		return "", "", errors.New("expected NAME=COMMAND")
+	}
+	return m[:i], m[i+1:], nil
+}
+
+// migrateStorage copies the release records of a migration until all of them
+// are copied.
+func migrateStorage(m *driver.Migrating) {
+	for {
+		err := m.Migrate()
+		if err == nil {
+			return
+		}
+		logger.Printf("Migrating the release records failed: %s; retrying in %s", err, storageMigrationRetryInterval)
+		time.Sleep(storageMigrationRetryInterval)
+	}
+}
+
+// purgeExpiredReleases periodically purges the deleted releases whose
+// retention period is over.
+func purgeExpiredReleases(svc *tiller.ReleaseServer) {
+	for range time.Tick(deletedReleasePurgeInterval) {
+		if _, err := svc.PurgeExpiredReleases(); err != nil {
+			logger.Printf("Purging expired releases failed: %s", err)
+		}
+	}
+}
+
 // namespace returns the namespace of tiller
 func namespace() string {
 	if ns := os.Getenv("TILLER_NAMESPACE"); ns != "" {
@@ -45,3 +45,64 @@ func TestInit(t *testing.T) {
 		t.Fatalf("Template engine GoTplEngine returned nil.")
 	}
 }
+
+func TestParseClusterConfig(t *testing.T) {
+	tests := []struct {
+		in                  string
+		name, path, context string
+		err                 bool
+	}{
+		{in: "spoke-1=/etc/tiller/spoke-1.yaml", name: "spoke-1", path: "/etc/tiller/spoke-1.yaml"},
+		{in: "spoke-1=/etc/tiller/all.yaml:spoke-1-admin", name: "spoke-1", path: "/etc/tiller/all.yaml", context: "spoke-1-admin"},
+		{in: "spoke-1", err: true},
+		{in: "=/etc/tiller/spoke-1.yaml", err: true},
+		{in: "spoke-1=", err: true},
+		{in: "spoke-1=/etc/tiller/all.yaml:", err: true},
+	}
+	for _, tt := range tests {
+		name, path, context, err := parseClusterConfig(tt.in)
+		if tt.err {
+			if err == nil {
+				t.Errorf("%q: expected an error", tt.in)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%q: %s", tt.in, err)
+			continue
+		}
+		if name != tt.name || path != tt.path || context != tt.context {
+			t.Errorf("%q: expected %q, %q and %q, got %q, %q and %q", tt.in, tt.name, tt.path, tt.context, name, path, context)
+		}
+	}
+}
+
+func TestParseValuesMutator(t *testing.T) {
+	tests := []struct {
+		in            string
+		name, command string
+		err           bool
+	}{
+		{in: "ipam=/usr/local/bin/allocate-ip", name: "ipam", command: "/usr/local/bin/allocate-ip"},
+		{in: "ipam=/opt/bin/a=b", name: "ipam", command: "/opt/bin/a=b"},
+		{in: "ipam", err: true},
+		{in: "=/usr/local/bin/allocate-ip", err: true},
+		{in: "ipam=", err: true},
+	}
+	for _, tt := range tests {
+		name, command, err := parseValuesMutator(tt.in)
+		if tt.err {
+			if err == nil {
+				t.Errorf("%q: expected an error", tt.in)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%q: %s", tt.in, err)
+			continue
+		}
+		if name != tt.name || command != tt.command {
+			t.Errorf("%q: expected %q and %q, got %q and %q", tt.in, tt.name, tt.command, name, command)
+		}
+	}
+}
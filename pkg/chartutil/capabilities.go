@@ -33,6 +33,29 @@ type Capabilities struct {
 	//
 	// This always comes from pkg/version.GetVersionProto().
 	TillerVersion *tversion.Version
+	// HelmVersion is the version of Helm that renders the chart, as the
+	// Version RPC reports it. Charts can branch on it to use template
+	// functions of newer versions:
+	//
+	//	{{ if semverCompare ">=2.8" .Capabilities.HelmVersion.Version }}
+	HelmVersion *HelmVersion
+}
+
+// HelmVersion describes a version of Helm.
+type HelmVersion struct {
+	// Version is the semantic version, e.g. "v2.7.0".
+	Version      string
+	GitCommit    string
+	GitTreeState string
+}
+
+// NewHelmVersion returns the HelmVersion that v describes.
+func NewHelmVersion(v *tversion.Version) *HelmVersion {
+	return &HelmVersion{
+		Version:      v.SemVer,
+		GitCommit:    v.GitCommit,
+		GitTreeState: v.GitTreeState,
+	}
 }
 
 // VersionSet is a set of Kubernetes API versions.
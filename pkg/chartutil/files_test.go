@@ -16,8 +16,11 @@ limitations under the License.
 package chartutil
 
 import (
+	"bytes"
+	"encoding/base64"
 	"testing"
 
+	"github.com/ghodss/yaml"
 	"github.com/golang/protobuf/ptypes/any"
 	"github.com/stretchr/testify/assert"
 )
@@ -87,6 +90,40 @@ func TestToSecret(t *testing.T) {
 	as.Equal("captain.txt: VGhlIENhcHRhaW4=\nstowaway.txt: TGVnYXR0\n", out)
 }
 
+// binaryData holds every byte value, including NUL and bytes that are not
+// valid UTF-8.
+func binaryData() []byte {
+	b := make([]byte, 512)
+	for i := range b {
+		b[i] = byte(i)
+	}
+	return b
+}
+
+func TestBinaryFiles(t *testing.T) {
+	data := binaryData()
+	f := NewFiles([]*any.Any{{TypeUrl: "files/ca.der", Value: data}})
+
+	if !bytes.Equal(f.GetBytes("files/ca.der"), data) {
+		t.Error("Expected GetBytes to return the file unchanged")
+	}
+	if !bytes.Equal([]byte(f.Get("files/ca.der")), data) {
+		t.Error("Expected Get to return the file unchanged")
+	}
+
+	var secret map[string]string
+	if err := yaml.Unmarshal([]byte(f.Glob("files/*").AsSecrets()), &secret); err != nil {
+		t.Fatal(err)
+	}
+	decoded, err := base64.StdEncoding.DecodeString(secret["ca.der"])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(decoded, data) {
+		t.Error("Expected AsSecrets to encode the file unchanged")
+	}
+}
+
 func TestLines(t *testing.T) {
 	as := assert.New(t)
 
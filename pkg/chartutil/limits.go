@@ -0,0 +1,151 @@
+/*
+Copyright 2017 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package chartutil
+
+import (
+	"fmt"
+
+	"k8s.io/helm/pkg/proto/hapi/chart"
+)
+
+const (
+	// DefaultMaxValuesDepth is how deeply values may nest by default. Charts
+	// rarely nest values more than ten levels deep.
+	DefaultMaxValuesDepth = 100
+	// DefaultMaxValuesSize is the default limit, in bytes, of the serialized
+	// values of a release.
+	DefaultMaxValuesSize = 5 * 1024 * 1024
+)
+
+// ValuesLimits bounds the values that are coalesced for a chart, so that
+// crafted values cannot exhaust the memory of a shared server.
+type ValuesLimits struct {
+	// MaxDepth is how deeply maps and lists may nest. Zero means no limit.
+	MaxDepth int
+	// MaxSize is the limit, in bytes, of the supplied values and of the
+	// values files of the chart and its subcharts taken together. Zero means
+	// no limit.
+	MaxSize int
+}
+
+// DefaultValuesLimits returns limits that normal charts stay well within.
+func DefaultValuesLimits() ValuesLimits {
+	return ValuesLimits{MaxDepth: DefaultMaxValuesDepth, MaxSize: DefaultMaxValuesSize}
+}
+
+// Check returns an error if vals, or the values of chrt and its subcharts,
+// exceed the limits. The size is checked before anything is parsed.
+func (l ValuesLimits) Check(chrt *chart.Chart, vals *chart.Config) error {
+	if l.MaxSize > 0 {
+		size := chartValuesSize(chrt)
+		if vals != nil {
+			size += len(vals.Raw)
+		}
+		if size > l.MaxSize {
+			return fmt.Errorf("values are %d bytes, more than the limit of %d bytes", size, l.MaxSize)
+		}
+	}
+	if l.MaxDepth <= 0 {
+		return nil
+	}
+	if vals != nil {
+		if err := l.checkDepth("supplied values", vals.Raw); err != nil {
+			return err
+		}
+	}
+	return l.checkChartDepth(chrt)
+}
+
+// chartValuesSize returns the size of the values files of chrt and its
+// subcharts.
+func chartValuesSize(chrt *chart.Chart) int {
+	if chrt == nil {
+		return 0
+	}
+	size := 0
+	if chrt.Values != nil {
+		size += len(chrt.Values.Raw)
+	}
+	for _, dep := range chrt.Dependencies {
+		size += chartValuesSize(dep)
+	}
+	return size
+}
+
+func (l ValuesLimits) checkChartDepth(chrt *chart.Chart) error {
+	if chrt == nil {
+		return nil
+	}
+	if chrt.Values != nil && chrt.Metadata != nil {
+		if err := l.checkDepth(fmt.Sprintf("values of chart %q", chrt.Metadata.Name), chrt.Values.Raw); err != nil {
+			return err
+		}
+	}
+	for _, dep := range chrt.Dependencies {
+		if err := l.checkChartDepth(dep); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// checkDepth parses the values in raw, described by what, and returns an
+// error naming the first key that nests too deeply.
+func (l ValuesLimits) checkDepth(what, raw string) error {
+	if raw == "" {
+		return nil
+	}
+	vals, err := ReadValues([]byte(raw))
+	if err != nil {
+		// Coalescing reports the values that cannot be parsed.
+		return nil
+	}
+	if path, ok := tooDeep(map[string]interface{}(vals), "", 1, l.MaxDepth); ok {
+		return fmt.Errorf("%s nest more than %d levels deep at %s", what, l.MaxDepth, path)
+	}
+	return nil
+}
+
+// tooDeep returns the path of the first value in v below depth max, where v
+// is at depth, and whether there is one.
+func tooDeep(v interface{}, path string, depth, max int) (string, bool) {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		if depth > max {
+			return path, true
+		}
+		for k, child := range v {
+			p := k
+			if path != "" {
+				p = path + "." + k
+			}
+			if found, ok := tooDeep(child, p, depth+1, max); ok {
+				return found, true
+			}
+		}
+	case []interface{}:
+		if depth > max {
+			return path, true
+		}
+		for i, child := range v {
+			if found, ok := tooDeep(child, fmt.Sprintf("%s[%d]", path, i), depth+1, max); ok {
+				return found, true
+			}
+		}
+	}
+	return "", false
+}
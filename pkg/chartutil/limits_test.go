@@ -0,0 +1,69 @@
+/*
+Copyright 2017 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package chartutil
+
+import (
+	"strings"
+	"testing"
+
+	"k8s.io/helm/pkg/proto/hapi/chart"
+)
+
+func TestValuesLimits(t *testing.T) {
+	sub := &chart.Chart{
+		Metadata: &chart.Metadata{Name: "sub"},
+		Values:   &chart.Config{Raw: "a:\n  b:\n    c:\n      d: 1\n"},
+	}
+	chrt := &chart.Chart{
+		Metadata:     &chart.Metadata{Name: "top"},
+		Values:       &chart.Config{Raw: "name: top\n"},
+		Dependencies: []*chart.Chart{sub},
+	}
+
+	tests := []struct {
+		name   string
+		limits ValuesLimits
+		vals   string
+		err    string
+	}{
+		{"within the limits", ValuesLimits{MaxDepth: 4, MaxSize: 100}, "x: [1, [2]]\n", ""},
+		{"no limits", ValuesLimits{}, "x:\n  w:\n    z:\n      u:\n        v: 1\n", ""},
+		{"too large", ValuesLimits{MaxSize: 40}, "x: 12345\n", "values are 45 bytes, more than the limit of 40 bytes"},
+		{"supplied values too deep", ValuesLimits{MaxDepth: 2}, "x:\n  w:\n    z: 1\n", "supplied values nest more than 2 levels deep at x.w"},
+		{"lists count", ValuesLimits{MaxDepth: 2}, "x: [[1]]\n", "supplied values nest more than 2 levels deep at x[0]"},
+		{"subchart values too deep", ValuesLimits{MaxDepth: 3}, "", `values of chart "sub" nest more than 3 levels deep at a.b.c`},
+	}
+	for _, tt := range tests {
+		err := tt.limits.Check(chrt, &chart.Config{Raw: tt.vals})
+		switch {
+		case tt.err == "" && err != nil:
+			t.Errorf("%s: unexpected error: %s", tt.name, err)
+		case tt.err != "" && (err == nil || !strings.Contains(err.Error(), tt.err)):
+			t.Errorf("%s: expected error %q, got %v", tt.name, tt.err, err)
+		}
+	}
+}
+
+func TestDefaultValuesLimits(t *testing.T) {
+	c, err := Load("testdata/frobnitz")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := DefaultValuesLimits().Check(c, &chart.Config{Raw: "name: value\n"}); err != nil {
+		t.Errorf("Expected a normal chart to be within the default limits, got %s", err)
+	}
+}
@@ -0,0 +1,99 @@
+/*
+Copyright 2017 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package chartutil
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"k8s.io/helm/pkg/proto/hapi/chart"
+)
+
+// validProfile matches the names of values profiles.
+var validProfile = regexp.MustCompile(`^[A-Za-z0-9]([A-Za-z0-9_.-]*[A-Za-z0-9])?$`)
+
+// ProfileFile returns the name of the file holding the values of the named
+// profile, e.g. "values-prod.yaml".
+func ProfileFile(profile string) string {
+	return "values-" + profile + ".yaml"
+}
+
+// Profiles lists the names of the values profiles of a chart, i.e. of the
+// values-<profile>.yaml files at its top level.
+func Profiles(chrt *chart.Chart) []string {
+	profiles := []string{}
+	for _, f := range chrt.Files {
+		name := f.TypeUrl
+		if !strings.HasPrefix(name, "values-") || !strings.HasSuffix(name, ".yaml") {
+			continue
+		}
+		if p := strings.TrimSuffix(strings.TrimPrefix(name, "values-"), ".yaml"); validProfile.MatchString(p) {
+			profiles = append(profiles, p)
+		}
+	}
+	sort.Strings(profiles)
+	return profiles
+}
+
+// ApplyProfile merges the values of the named profile of chrt, from its
+// values-<profile>.yaml file, into vals. Values in vals take precedence, so
+// the profile overrides the chart's defaults and is overridden by vals.
+//
+// A chart without a file for the profile is an error, so that a misspelled
+// profile is noticed, unless allowMissing is set; vals is then returned
+// unchanged.
+func ApplyProfile(chrt *chart.Chart, profile string, vals *chart.Config, allowMissing bool) (*chart.Config, error) {
+	if !validProfile.MatchString(profile) {
+		return vals, fmt.Errorf("invalid values profile %q", profile)
+	}
+	var data []byte
+	file := ProfileFile(profile)
+	for _, f := range chrt.Files {
+		if f.TypeUrl == file {
+			data = f.Value
+			break
+		}
+	}
+	if data == nil {
+		if allowMissing {
+			return vals, nil
+		}
+		msg := fmt.Sprintf("chart %s has no values profile %q (no %s)", chrt.Metadata.Name, profile, file)
+		if profiles := Profiles(chrt); len(profiles) > 0 {
+			msg += "; its profiles are " + strings.Join(profiles, ", ")
+		}
+		return vals, fmt.Errorf("%s", msg)
+	}
+
+	pv, err := ReadValues(data)
+	if err != nil {
+		return vals, fmt.Errorf("cannot parse %s of chart %s: %s", file, chrt.Metadata.Name, err)
+	}
+	uv := Values{}
+	if vals != nil {
+		if uv, err = ReadValues([]byte(vals.Raw)); err != nil {
+			return vals, err
+		}
+	}
+	merged, err := Values(coalesceTables(uv, pv)).YAML()
+	if err != nil {
+		return vals, err
+	}
+	return &chart.Config{Raw: merged}, nil
+}
@@ -0,0 +1,119 @@
+/*
+Copyright 2017 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package chartutil
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/golang/protobuf/ptypes/any"
+
+	"k8s.io/helm/pkg/proto/hapi/chart"
+)
+
+func profileChart() *chart.Chart {
+	return &chart.Chart{
+		Metadata: &chart.Metadata{Name: "web"},
+		Values:   &chart.Config{Raw: "replicas: 1\nimage: {tag: stable, pullPolicy: IfNotPresent}\n"},
+		Files: []*any.Any{
+			{TypeUrl: "values-prod.yaml", Value: []byte("replicas: 3\nimage: {tag: \"1.2\"}\nhosts: [prod.example.com]\n")},
+			{TypeUrl: "values-dev.yaml", Value: []byte("replicas: 1\n")},
+			{TypeUrl: "values-.yaml", Value: []byte("ignored: true\n")},
+			{TypeUrl: "config/values-staging.yaml", Value: []byte("ignored: true\n")},
+			{TypeUrl: "README.md", Value: []byte("# web\n")},
+		},
+	}
+}
+
+func TestProfiles(t *testing.T) {
+	expect := []string{"dev", "prod"}
+	if got := Profiles(profileChart()); !reflect.DeepEqual(got, expect) {
+		t.Errorf("Expected profiles %v, got %v", expect, got)
+	}
+}
+
+func TestApplyProfile(t *testing.T) {
+	c := profileChart()
+	vals, err := ApplyProfile(c, "prod", &chart.Config{Raw: "image: {tag: \"1.3\"}\nhosts: [canary.example.com]\n"}, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The passed-in values override the profile, which overrides the chart's
+	// defaults.
+	cvals, err := CoalesceValues(c, vals)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expect := map[string]interface{}{
+		"replicas": float64(3),
+		"image":    map[string]interface{}{"tag": "1.3", "pullPolicy": "IfNotPresent"},
+		"hosts":    []interface{}{"canary.example.com"},
+	}
+	if !reflect.DeepEqual(cvals.AsMap(), expect) {
+		t.Errorf("Expected values\n%v\ngot\n%v", expect, cvals.AsMap())
+	}
+}
+
+func TestApplyProfileWithoutValues(t *testing.T) {
+	vals, err := ApplyProfile(profileChart(), "dev", nil, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if vals.Raw != "replicas: 1\n" {
+		t.Errorf("Expected the profile's values, got %q", vals.Raw)
+	}
+}
+
+func TestApplyProfileErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		profile string
+		expect  string
+	}{
+		{"missing", "prd", `chart web has no values profile "prd" (no values-prd.yaml); its profiles are dev, prod`},
+		{"path", "../prod", `invalid values profile "../prod"`},
+		{"empty", "", `invalid values profile ""`},
+	}
+	for _, tt := range tests {
+		vals := &chart.Config{Raw: "replicas: 2\n"}
+		got, err := ApplyProfile(profileChart(), tt.profile, vals, false)
+		if err == nil || err.Error() != tt.expect {
+			t.Errorf("%s: expected error %q, got %v", tt.name, tt.expect, err)
+		}
+		if got != vals {
+			t.Errorf("%s: expected the values to be returned unchanged", tt.name)
+		}
+	}
+
+	c := profileChart()
+	c.Files = nil
+	if _, err := ApplyProfile(c, "prod", nil, false); err == nil || err.Error() != `chart web has no values profile "prod" (no values-prod.yaml)` {
+		t.Errorf("Expected an error for a chart without profiles, got %v", err)
+	}
+}
+
+func TestApplyProfileAllowMissing(t *testing.T) {
+	vals := &chart.Config{Raw: "replicas: 2\n"}
+	got, err := ApplyProfile(profileChart(), "staging", vals, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != vals {
+		t.Errorf("Expected the values to be returned unchanged, got %v", got)
+	}
+}
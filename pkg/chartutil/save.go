@@ -24,6 +24,8 @@ import (
 	"io/ioutil"
 	"os"
 	"path/filepath"
+	"sort"
+	"time"
 
 	"github.com/ghodss/yaml"
 
@@ -120,7 +122,8 @@ func Save(c *chart.Chart, outDir string) (string, error) {
 		return "", err
 	}
 
-	// Wrap in gzip writer
+	// Wrap in gzip writer. The header carries no name or modification time,
+	// so the archive only depends on the chart contents.
 	zipper := gzip.NewWriter(f)
 	zipper.Header.Extra = headerBytes
 	zipper.Header.Comment = "Helm"
@@ -143,57 +146,88 @@ func Save(c *chart.Chart, outDir string) (string, error) {
 	return filename, err
 }
 
+// archiveModTime is the modification time recorded for every file in a chart
+// archive. Using a fixed time, along with fixed ownership and a sorted file
+// order, makes archives of the same chart byte-for-byte identical.
+var archiveModTime = time.Unix(0, 0)
+
+// tarEntry is a single file to be written to a chart archive.
+type tarEntry struct {
+	name string
+	body []byte
+}
+
+type tarEntriesByName []tarEntry
+
+func (t tarEntriesByName) Len() int           { return len(t) }
+func (t tarEntriesByName) Swap(i, j int)      { t[i], t[j] = t[j], t[i] }
+func (t tarEntriesByName) Less(i, j int) bool { return t[i].name < t[j].name }
+
 func writeTarContents(out *tar.Writer, c *chart.Chart, prefix string) error {
+	entries, err := tarContents(c, prefix)
+	if err != nil {
+		return err
+	}
+
+	// Dependencies are loaded in no particular order, so sort the entries to
+	// keep the archive stable.
+	sort.Sort(tarEntriesByName(entries))
+	for _, e := range entries {
+		if err := writeToTar(out, e.name, e.body); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// tarContents returns the files of a chart and its dependencies.
+func tarContents(c *chart.Chart, prefix string) ([]tarEntry, error) {
 	base := filepath.Join(prefix, c.Metadata.Name)
 
 	// Save Chart.yaml
 	cdata, err := yaml.Marshal(c.Metadata)
 	if err != nil {
-		return err
-	}
-	if err := writeToTar(out, base+"/Chart.yaml", cdata); err != nil {
-		return err
+		return nil, err
 	}
+	entries := []tarEntry{{base + "/Chart.yaml", cdata}}
 
 	// Save values.yaml
 	if c.Values != nil && len(c.Values.Raw) > 0 {
-		if err := writeToTar(out, base+"/values.yaml", []byte(c.Values.Raw)); err != nil {
-			return err
-		}
+		entries = append(entries, tarEntry{base + "/values.yaml", []byte(c.Values.Raw)})
 	}
 
 	// Save templates
 	for _, f := range c.Templates {
-		n := filepath.Join(base, f.Name)
-		if err := writeToTar(out, n, f.Data); err != nil {
-			return err
-		}
+		entries = append(entries, tarEntry{filepath.Join(base, f.Name), f.Data})
 	}
 
 	// Save files
 	for _, f := range c.Files {
-		n := filepath.Join(base, f.TypeUrl)
-		if err := writeToTar(out, n, f.Value); err != nil {
-			return err
-		}
+		entries = append(entries, tarEntry{filepath.Join(base, f.TypeUrl), f.Value})
 	}
 
 	// Save dependencies
 	for _, dep := range c.Dependencies {
-		if err := writeTarContents(out, dep, base+"/charts"); err != nil {
-			return err
+		deps, err := tarContents(dep, base+"/charts")
+		if err != nil {
+			return nil, err
 		}
+		entries = append(entries, deps...)
 	}
-	return nil
+	return entries, nil
 }
 
 // writeToTar writes a single file to a tar archive.
 func writeToTar(out *tar.Writer, name string, body []byte) error {
 	// TODO: Do we need to create dummy parent directory names if none exist?
 	h := &tar.Header{
-		Name: name,
-		Mode: 0755,
-		Size: int64(len(body)),
+		Name:     name,
+		Mode:     0755,
+		Size:     int64(len(body)),
+		ModTime:  archiveModTime,
+		Typeflag: tar.TypeReg,
+		Uid:      0,
+		Gid:      0,
 	}
 	if err := out.WriteHeader(h); err != nil {
 		return err
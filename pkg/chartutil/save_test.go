@@ -17,11 +17,19 @@ limitations under the License.
 package chartutil
 
 import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"io"
 	"io/ioutil"
 	"os"
+	"sort"
 	"strings"
 	"testing"
 
+	"github.com/golang/protobuf/ptypes/any"
+
 	"k8s.io/helm/pkg/proto/hapi/chart"
 )
 
@@ -66,6 +74,40 @@ func TestSave(t *testing.T) {
 	}
 }
 
+func TestSaveBinaryFiles(t *testing.T) {
+	tmp, err := ioutil.TempDir("", "helm-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tmp)
+
+	data := binaryData()
+	c := &chart.Chart{
+		Metadata: &chart.Metadata{
+			Name:    "ahab",
+			Version: "1.2.3",
+		},
+		Files: []*any.Any{
+			{TypeUrl: "files/ca.der", Value: data},
+		},
+	}
+
+	where, err := Save(c, tmp)
+	if err != nil {
+		t.Fatalf("Failed to save: %s", err)
+	}
+	c2, err := LoadFile(where)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(c2.Files) != 1 || c2.Files[0].TypeUrl != "files/ca.der" {
+		t.Fatalf("Expected files/ca.der in the archive, got %v", c2.Files)
+	}
+	if !bytes.Equal(c2.Files[0].Value, data) {
+		t.Error("Expected the binary file to survive the archive unchanged")
+	}
+}
+
 func TestSaveDir(t *testing.T) {
 	tmp, err := ioutil.TempDir("", "helm-")
 	if err != nil {
@@ -99,3 +141,84 @@ func TestSaveDir(t *testing.T) {
 		t.Fatal("Values data did not match")
 	}
 }
+
+func TestSaveReproducible(t *testing.T) {
+	dep := func(name string) *chart.Chart {
+		return &chart.Chart{
+			Metadata: &chart.Metadata{Name: name, Version: "0.1.0"},
+			Templates: []*chart.Template{
+				{Name: "templates/service.yaml", Data: []byte("kind: Service")},
+			},
+		}
+	}
+	newChart := func(deps ...*chart.Chart) *chart.Chart {
+		return &chart.Chart{
+			Metadata: &chart.Metadata{Name: "ahab", Version: "1.2.3"},
+			Values:   &chart.Config{Raw: "ship: Pequod"},
+			Templates: []*chart.Template{
+				{Name: "templates/whale.yaml", Data: []byte("kind: ConfigMap")},
+			},
+			Dependencies: deps,
+		}
+	}
+
+	// Dependencies are loaded in map order, so the same chart may come back
+	// with its dependencies in a different order.
+	charts := []*chart.Chart{
+		newChart(dep("starbuck"), dep("stubb")),
+		newChart(dep("stubb"), dep("starbuck")),
+	}
+
+	var archives [][]byte
+	for _, c := range charts {
+		tmp, err := ioutil.TempDir("", "helm-")
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer os.RemoveAll(tmp)
+
+		where, err := Save(c, tmp)
+		if err != nil {
+			t.Fatalf("Failed to save: %s", err)
+		}
+		b, err := ioutil.ReadFile(where)
+		if err != nil {
+			t.Fatal(err)
+		}
+		archives = append(archives, b)
+	}
+
+	if !bytes.Equal(archives[0], archives[1]) {
+		t.Error("Expected identical archives for the same chart")
+	}
+	if sha256.Sum256(archives[0]) != sha256.Sum256(archives[1]) {
+		t.Error("Expected identical digests for the same chart")
+	}
+
+	zr, err := gzip.NewReader(bytes.NewReader(archives[0]))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !zr.Header.ModTime.IsZero() || zr.Header.Name != "" {
+		t.Errorf("Expected an empty gzip name and modification time, got %q %s", zr.Header.Name, zr.Header.ModTime)
+	}
+
+	var names []string
+	tr := tar.NewReader(zr)
+	for {
+		h, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		if h.ModTime.Unix() != 0 || h.Uid != 0 || h.Gid != 0 {
+			t.Errorf("%s: expected zero mtime, uid and gid, got %s, %d, %d", h.Name, h.ModTime, h.Uid, h.Gid)
+		}
+		names = append(names, h.Name)
+	}
+	if !sort.StringsAreSorted(names) {
+		t.Errorf("Expected sorted entries, got %v", names)
+	}
+}
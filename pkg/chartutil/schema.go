@@ -0,0 +1,212 @@
+/*
+Copyright 2017 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package chartutil
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+
+	"k8s.io/helm/pkg/proto/hapi/chart"
+)
+
+// SchemaFile is the name of the file holding the JSON Schema of a chart's
+// values.
+const SchemaFile = "values.schema.json"
+
+// The merge strategies that the "mergeStrategy" keyword of a values schema
+// can give an array. They decide how an array in the passed-in values is
+// combined with the array of the chart's values under the same key.
+const (
+	// MergeReplace uses the passed-in array in place of the chart's. This is
+	// the default.
+	MergeReplace = "replace"
+	// MergeAppend appends the passed-in array to the chart's.
+	MergeAppend = "append"
+	// MergeByKey merges the tables of the passed-in array into those of the
+	// chart's array that have the same value for the field named by the
+	// "mergeKey" keyword, and appends the others.
+	MergeByKey = "merge-by-key"
+)
+
+// ApplySchemaDefaults fills in the values that are not set with the defaults
+// of the chart's values schema, if it has one. Each dependency's schema is
+// applied to the values of that dependency.
+//
+// Only the "default", "properties" and "items" keywords are used. A default
+// applies where a value is missing, but not where it is set to null. Defaults of nested properties create
+// their parent tables as needed. Defaults under "items" apply to each table
+// of an array.
+func ApplySchemaDefaults(chrt *chart.Chart, vals Values) error {
+	schema, err := readSchema(chrt)
+	if err != nil {
+		return err
+	}
+	if schema != nil {
+		applyDefaults(schema, vals)
+	}
+	for _, subchart := range chrt.Dependencies {
+		sv, ok := vals[subchart.Metadata.Name].(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if err := ApplySchemaDefaults(subchart, sv); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// readSchema returns the values schema of chrt, or nil if it has none.
+func readSchema(chrt *chart.Chart) (map[string]interface{}, error) {
+	for _, f := range chrt.Files {
+		if f.TypeUrl != SchemaFile {
+			continue
+		}
+		var schema map[string]interface{}
+		if err := json.Unmarshal(f.Value, &schema); err != nil {
+			return nil, fmt.Errorf("cannot parse %s of chart %s: %s", SchemaFile, chrt.Metadata.Name, err)
+		}
+		return schema, nil
+	}
+	return nil, nil
+}
+
+// applyDefaults fills in the missing properties of v that schema gives a
+// default for, and recurses into the tables and arrays of v.
+func applyDefaults(schema, v map[string]interface{}) {
+	props, _ := schema["properties"].(map[string]interface{})
+	for name, p := range props {
+		ps, ok := p.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		val, set := v[name]
+		if set && val == nil {
+			// A property set to null was removed on purpose, e.g. with
+			// --set name=null, and is left unset.
+			continue
+		}
+		if !set {
+			def, ok := ps["default"]
+			if !ok {
+				// Without a default of its own, a table is only created if
+				// nested defaults fill it.
+				t := map[string]interface{}{}
+				applyDefaults(ps, t)
+				if len(t) > 0 {
+					v[name] = t
+				}
+				continue
+			}
+			// Copy the default, so that values filled in from it do not
+			// share tables or arrays with the schema.
+			val = DeepCopy(def)
+			v[name] = val
+		}
+		switch val := val.(type) {
+		case map[string]interface{}:
+			applyDefaults(ps, val)
+		case []interface{}:
+			items, ok := ps["items"].(map[string]interface{})
+			if !ok {
+				continue
+			}
+			for _, item := range val {
+				if t, ok := item.(map[string]interface{}); ok {
+					applyDefaults(items, t)
+				}
+			}
+		}
+	}
+}
+
+// propertySchema returns the schema of the named property of the tables that
+// schema describes, or nil if it has none.
+func propertySchema(schema map[string]interface{}, name string) map[string]interface{} {
+	props, _ := schema["properties"].(map[string]interface{})
+	ps, _ := props[name].(map[string]interface{})
+	return ps
+}
+
+// mergeArrays combines the array dst of passed-in values with the array src
+// of a chart's values, following the merge strategy that schema, the schema
+// of the array, gives it. name is the key of the array, for warnings.
+//
+// Merged tables are merged into the tables of dst, so dst must not be used
+// afterwards.
+func mergeArrays(dst, src []interface{}, schema map[string]interface{}, name string) []interface{} {
+	strategy, _ := schema["mergeStrategy"].(string)
+	switch strategy {
+	case "", MergeReplace:
+		return dst
+	case MergeAppend:
+		merged := make([]interface{}, 0, len(src)+len(dst))
+		merged = append(merged, src...)
+		return append(merged, dst...)
+	case MergeByKey:
+		key, _ := schema["mergeKey"].(string)
+		if key == "" {
+			log.Printf("warning: replacing %s: merge strategy %q needs a mergeKey.", name, strategy)
+			return dst
+		}
+		items, _ := schema["items"].(map[string]interface{})
+		return mergeByKey(dst, src, key, items)
+	}
+	log.Printf("warning: replacing %s: unknown merge strategy %q.", name, strategy)
+	return dst
+}
+
+// mergeByKey merges each table of dst that has a value for key into the last
+// table of src with the same value, and appends the other elements of dst.
+// items is the schema of the elements.
+func mergeByKey(dst, src []interface{}, key string, items map[string]interface{}) []interface{} {
+	merged := make([]interface{}, 0, len(src)+len(dst))
+	index := map[string]int{}
+	for _, item := range src {
+		if id, ok := mergeID(item, key); ok {
+			index[id] = len(merged)
+		}
+		merged = append(merged, item)
+	}
+	for _, item := range dst {
+		id, ok := mergeID(item, key)
+		if i, found := index[id]; ok && found {
+			if st, ok := merged[i].(map[string]interface{}); ok {
+				merged[i] = coalesceTablesSchema(item.(map[string]interface{}), st, items)
+				continue
+			}
+		}
+		merged = append(merged, item)
+	}
+	return merged
+}
+
+// mergeID identifies an element of an array that is merged by key: a table
+// whose value for key is a scalar. ok is false for other elements.
+func mergeID(item interface{}, key string) (id string, ok bool) {
+	t, ok := item.(map[string]interface{})
+	if !ok {
+		return "", false
+	}
+	switch v := t[key].(type) {
+	case string, bool, float64, int, int64:
+		// The type is part of the ID, so that "1" and 1 differ.
+		return fmt.Sprintf("%T:%v", v, v), true
+	}
+	return "", false
+}
@@ -0,0 +1,383 @@
+/*
+Copyright 2017 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package chartutil
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/golang/protobuf/ptypes/any"
+
+	"k8s.io/helm/pkg/proto/hapi/chart"
+)
+
+const testSchema = `{
+  "type": "object",
+  "properties": {
+    "replicas": {"type": "integer", "default": 1},
+    "image": {
+      "type": "object",
+      "properties": {
+        "repository": {"type": "string", "default": "nginx"},
+        "tag": {"type": "string", "default": "stable"},
+        "pullPolicy": {"type": "string"}
+      }
+    },
+    "resources": {
+      "type": "object",
+      "properties": {
+        "limits": {
+          "type": "object",
+          "properties": {
+            "cpu": {"type": "string", "default": "100m"}
+          }
+        }
+      }
+    },
+    "labels": {"type": "object"},
+    "args": {"type": "array", "default": ["--verbose"]},
+    "ports": {
+      "type": "array",
+      "items": {
+        "type": "object",
+        "properties": {
+          "protocol": {"type": "string", "default": "TCP"}
+        }
+      }
+    }
+  }
+}`
+
+func schemaChart(name, values, schema string, deps ...*chart.Chart) *chart.Chart {
+	c := &chart.Chart{
+		Metadata:     &chart.Metadata{Name: name},
+		Values:       &chart.Config{Raw: values},
+		Dependencies: deps,
+	}
+	if schema != "" {
+		c.Files = []*any.Any{{TypeUrl: SchemaFile, Value: []byte(schema)}}
+	}
+	return c
+}
+
+func TestApplySchemaDefaults(t *testing.T) {
+	c := schemaChart("web", "image:\n  tag: \"1.13\"\n", testSchema)
+	vals, err := CoalesceValues(c, &chart.Config{Raw: `
+replicas: 3
+ports:
+- port: 80
+- port: 53
+  protocol: UDP
+`})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expect := map[string]interface{}{
+		// Passed-in values and chart values win over schema defaults.
+		"replicas": float64(3),
+		"image": map[string]interface{}{
+			"repository": "nginx",
+			"tag":        "1.13",
+		},
+		// Parent tables are created for nested defaults.
+		"resources": map[string]interface{}{
+			"limits": map[string]interface{}{"cpu": "100m"},
+		},
+		"args": []interface{}{"--verbose"},
+		// Item defaults apply to each table of an array.
+		"ports": []interface{}{
+			map[string]interface{}{"port": float64(80), "protocol": "TCP"},
+			map[string]interface{}{"port": float64(53), "protocol": "UDP"},
+		},
+	}
+	if !reflect.DeepEqual(vals.AsMap(), expect) {
+		t.Errorf("Expected values\n%v\ngot\n%v", expect, vals.AsMap())
+	}
+}
+
+func TestApplySchemaDefaultsCopiesDefaults(t *testing.T) {
+	c := schemaChart("web", "", testSchema)
+	first, err := CoalesceValues(c, &chart.Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	first["args"].([]interface{})[0] = "--quiet"
+
+	second, err := CoalesceValues(c, &chart.Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := second["args"].([]interface{})[0]; got != "--verbose" {
+		t.Errorf("Expected defaults not to be shared between values, got %v", got)
+	}
+}
+
+func TestApplySchemaDefaultsSubcharts(t *testing.T) {
+	db := schemaChart("db", "user: postgres\n", `{"properties": {"port": {"default": 5432}, "user": {"default": "root"}}}`)
+	c := schemaChart("app", "db:\n  port: 5433\n", `{"properties": {"debug": {"default": false}}}`, db)
+
+	vals, err := CoalesceValues(c, &chart.Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expect := map[string]interface{}{
+		"debug": false,
+		"db": map[string]interface{}{
+			"global": map[string]interface{}{},
+			"port":   float64(5433),
+			"user":   "postgres",
+		},
+	}
+	if !reflect.DeepEqual(vals.AsMap(), expect) {
+		t.Errorf("Expected values\n%v\ngot\n%v", expect, vals.AsMap())
+	}
+}
+
+func TestApplySchemaDefaultsNull(t *testing.T) {
+	c := schemaChart("web", "image:\n  tag: \"1.13\"\n", testSchema)
+	vals, err := CoalesceValues(c, &chart.Config{Raw: `
+replicas: null
+image:
+  repository: null
+ports:
+- port: 80
+  protocol: null
+`})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expect := map[string]interface{}{
+		"replicas": nil,
+		"image":    map[string]interface{}{"repository": nil, "tag": "1.13"},
+		"resources": map[string]interface{}{
+			"limits": map[string]interface{}{"cpu": "100m"},
+		},
+		"args": []interface{}{"--verbose"},
+		"ports": []interface{}{
+			map[string]interface{}{"port": float64(80), "protocol": nil},
+		},
+	}
+	if !reflect.DeepEqual(map[string]interface{}(vals), expect) {
+		t.Errorf("Expected values set to null to stay unset, got %v", vals)
+	}
+}
+
+func TestApplySchemaDefaultsErrors(t *testing.T) {
+	c := schemaChart("web", "", "{not json")
+	_, err := CoalesceValues(c, &chart.Config{})
+	if err == nil || !strings.Contains(err.Error(), "cannot parse values.schema.json of chart web") {
+		t.Errorf("Expected a parse error, got %v", err)
+	}
+
+	vals := Values{"replicas": float64(2)}
+	if err := ApplySchemaDefaults(schemaChart("web", "", ""), vals); err != nil {
+		t.Fatal(err)
+	}
+	if len(vals) != 1 {
+		t.Errorf("Expected a chart without schema to leave the values alone, got %v", vals)
+	}
+}
+
+const mergeSchema = `{
+  "type": "object",
+  "properties": {
+    "args": {"type": "array"},
+    "hosts": {"type": "array", "mergeStrategy": "replace"},
+    "env": {"type": "array", "mergeStrategy": "append"},
+    "pod": {
+      "type": "object",
+      "properties": {
+        "tolerations": {"type": "array", "mergeStrategy": "append"}
+      }
+    },
+    "containers": {
+      "type": "array",
+      "mergeStrategy": "merge-by-key",
+      "mergeKey": "name",
+      "items": {
+        "type": "object",
+        "properties": {
+          "ports": {"type": "array", "mergeStrategy": "append"}
+        }
+      }
+    },
+    "volumes": {"type": "array", "mergeStrategy": "merge-by-key"},
+    "mounts": {"type": "array", "mergeStrategy": "prepend"}
+  }
+}`
+
+const mergeValues = `
+args: [--verbose]
+hosts: [a.example.com]
+env:
+- name: LOG_LEVEL
+  value: info
+pod:
+  tolerations:
+  - key: dedicated
+containers:
+- name: app
+  image: app:1.0
+  resources:
+    cpu: 100m
+    memory: 64Mi
+  ports: [80]
+- name: sidecar
+  image: proxy:1.0
+volumes: [data]
+mounts: [/data]
+`
+
+func TestMergeStrategies(t *testing.T) {
+	tests := []struct {
+		name   string
+		key    string
+		vals   string
+		expect interface{}
+	}{
+		{
+			name:   "arrays are replaced by default",
+			key:    "args",
+			vals:   "args: [--quiet]",
+			expect: []interface{}{"--quiet"},
+		},
+		{
+			name:   "replace",
+			key:    "hosts",
+			vals:   "hosts: [b.example.com]",
+			expect: []interface{}{"b.example.com"},
+		},
+		{
+			name: "append",
+			key:  "env",
+			vals: "env: [{name: REGION, value: east}]",
+			expect: []interface{}{
+				map[string]interface{}{"name": "LOG_LEVEL", "value": "info"},
+				map[string]interface{}{"name": "REGION", "value": "east"},
+			},
+		},
+		{
+			name: "append in a nested table",
+			key:  "pod",
+			vals: "pod: {tolerations: [{key: gpu}]}",
+			expect: map[string]interface{}{
+				"tolerations": []interface{}{
+					map[string]interface{}{"key": "dedicated"},
+					map[string]interface{}{"key": "gpu"},
+				},
+			},
+		},
+		{
+			name: "merge by key",
+			key:  "containers",
+			vals: `
+containers:
+- name: app
+  image: app:2.0
+  resources: {memory: 128Mi}
+  ports: [443]
+- name: debug
+  image: busybox
+- image: unnamed
+`,
+			expect: []interface{}{
+				// Matching tables are deep merged, in the chart's order, and
+				// their arrays merged as the items schema says.
+				map[string]interface{}{
+					"name":      "app",
+					"image":     "app:2.0",
+					"resources": map[string]interface{}{"cpu": "100m", "memory": "128Mi"},
+					"ports":     []interface{}{float64(80), float64(443)},
+				},
+				map[string]interface{}{"name": "sidecar", "image": "proxy:1.0"},
+				// The others are appended.
+				map[string]interface{}{"name": "debug", "image": "busybox"},
+				map[string]interface{}{"image": "unnamed"},
+			},
+		},
+		{
+			name:   "merge by key without a key",
+			key:    "volumes",
+			vals:   "volumes: [logs]",
+			expect: []interface{}{"logs"},
+		},
+		{
+			name:   "unknown strategy",
+			key:    "mounts",
+			vals:   "mounts: [/logs]",
+			expect: []interface{}{"/logs"},
+		},
+		{
+			name:   "array not passed in",
+			key:    "env",
+			vals:   "",
+			expect: []interface{}{map[string]interface{}{"name": "LOG_LEVEL", "value": "info"}},
+		},
+	}
+	for _, tt := range tests {
+		c := schemaChart("web", mergeValues, mergeSchema)
+		vals, err := CoalesceValues(c, &chart.Config{Raw: tt.vals})
+		if err != nil {
+			t.Errorf("%s: %s", tt.name, err)
+			continue
+		}
+		if !reflect.DeepEqual(vals[tt.key], tt.expect) {
+			t.Errorf("%s: expected %s\n%v\ngot\n%v", tt.name, tt.key, tt.expect, vals[tt.key])
+		}
+	}
+}
+
+func TestMergeStrategiesOfSubcharts(t *testing.T) {
+	// A subchart's schema decides how its arrays are merged, whether the
+	// parent chart or the user sets them.
+	sub := schemaChart("db", "flags: [--fsync]\n", `{"properties": {"flags": {"mergeStrategy": "append"}}}`)
+	c := schemaChart("web", "db:\n  flags: [--wal]\n", "", sub)
+	vals, err := CoalesceValues(c, &chart.Config{Raw: "db:\n  flags: [--debug]\n"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The parent has no schema, so its flags are replaced by the user's.
+	expect := []interface{}{"--fsync", "--debug"}
+	if got := vals["db"].(map[string]interface{})["flags"]; !reflect.DeepEqual(got, expect) {
+		t.Errorf("Expected %v, got %v", expect, got)
+	}
+}
+
+func TestMergeByKeyIDs(t *testing.T) {
+	// Keys of different types do not match.
+	src := []interface{}{
+		map[string]interface{}{"id": "1", "from": "chart"},
+		map[string]interface{}{"id": float64(2), "from": "chart"},
+	}
+	dst := []interface{}{
+		map[string]interface{}{"id": float64(1), "from": "user"},
+		map[string]interface{}{"id": float64(2), "from": "user"},
+		map[string]interface{}{"id": []interface{}{"x"}},
+	}
+	expect := []interface{}{
+		map[string]interface{}{"id": "1", "from": "chart"},
+		map[string]interface{}{"id": float64(2), "from": "user"},
+		map[string]interface{}{"id": float64(1), "from": "user"},
+		map[string]interface{}{"id": []interface{}{"x"}},
+	}
+	if got := mergeByKey(dst, src, "id", nil); !reflect.DeepEqual(got, expect) {
+		t.Errorf("Expected %v, got %v", expect, got)
+	}
+}
@@ -0,0 +1,67 @@
+/*
+Copyright 2017 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package chartutil
+
+import (
+	"fmt"
+
+	"k8s.io/helm/pkg/proto/hapi/chart"
+)
+
+// TestValuesFile is the name of the file in which a chart may ship the values
+// its tests run with.
+const TestValuesFile = "values-test.yaml"
+
+// HasTestValues reports whether chrt ships test values.
+func HasTestValues(chrt *chart.Chart) bool {
+	return testValuesData(chrt) != nil
+}
+
+func testValuesData(chrt *chart.Chart) []byte {
+	for _, f := range chrt.Files {
+		if f.TypeUrl == TestValuesFile {
+			return f.Value
+		}
+	}
+	return nil
+}
+
+// ApplyTestValues merges the test values of chrt, from its values-test.yaml
+// file, over vals. Unlike a values profile, the test values take precedence,
+// so tests can be configured regardless of how the chart was installed.
+// A chart without test values returns vals unchanged.
+func ApplyTestValues(chrt *chart.Chart, vals *chart.Config) (*chart.Config, error) {
+	data := testValuesData(chrt)
+	if data == nil {
+		return vals, nil
+	}
+	tv, err := ReadValues(data)
+	if err != nil {
+		return vals, fmt.Errorf("cannot parse %s of chart %s: %s", TestValuesFile, chrt.Metadata.Name, err)
+	}
+	uv := Values{}
+	if vals != nil {
+		if uv, err = ReadValues([]byte(vals.Raw)); err != nil {
+			return vals, err
+		}
+	}
+	merged, err := Values(coalesceTables(tv, uv)).YAML()
+	if err != nil {
+		return vals, err
+	}
+	return &chart.Config{Raw: merged}, nil
+}
@@ -0,0 +1,51 @@
+/*
+Copyright 2017 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package chartutil
+
+import (
+	"testing"
+
+	"github.com/golang/protobuf/ptypes/any"
+
+	"k8s.io/helm/pkg/proto/hapi/chart"
+)
+
+func TestApplyTestValues(t *testing.T) {
+	c := &chart.Chart{Metadata: &chart.Metadata{Name: "web"}}
+	vals := &chart.Config{Raw: "url: http://web\nreplicas: 3\n"}
+	if HasTestValues(c) {
+		t.Error("Expected a chart without values-test.yaml to have no test values")
+	}
+	if got, err := ApplyTestValues(c, vals); err != nil || got != vals {
+		t.Errorf("Expected the values to be left alone, got %v (%v)", got, err)
+	}
+
+	c.Files = []*any.Any{{TypeUrl: "values-test.yaml", Value: []byte("url: http://web/healthz\n")}}
+	got, err := ApplyTestValues(c, vals)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The test values override the values they are applied to.
+	if expect := "replicas: 3\nurl: http://web/healthz\n"; got.Raw != expect {
+		t.Errorf("Expected %q, got %q", expect, got.Raw)
+	}
+
+	c.Files[0].Value = []byte("url: [")
+	if _, err := ApplyTestValues(c, vals); err == nil {
+		t.Error("Expected invalid test values to be rejected")
+	}
+}
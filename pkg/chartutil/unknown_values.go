@@ -0,0 +1,178 @@
+/*
+Copyright 2017 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package chartutil
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"k8s.io/helm/pkg/proto/hapi/chart"
+)
+
+// CheckUnknownValues returns an error listing the keys of vals that
+// UnknownValues reports, or nil if there are none.
+func CheckUnknownValues(chrt *chart.Chart, vals *chart.Config) error {
+	unknown, err := UnknownValues(chrt, vals)
+	if err != nil || len(unknown) == 0 {
+		return err
+	}
+	return fmt.Errorf("values not known to chart %s or its subcharts: %s", chrt.Metadata.Name, strings.Join(unknown, ", "))
+}
+
+// UnknownValues returns the keys of vals, as sorted dotted paths such as
+// "image.tga", that match nothing in the default values or the values schemas
+// of chrt and its subcharts. A key is known if the defaults or the schema of
+// the chart it is meant for have it at the same path. The values of a
+// subchart, given directly or under "passthrough", are checked against the
+// subchart's defaults and those its parent sets for it.
+//
+// Below a key whose default is not a table, or is an empty table, and below a
+// schema property without "properties", any key is taken as known, as charts
+// read such values as a whole or with keys of the user's choosing. Globals are
+// not checked, as any chart may read them.
+func UnknownValues(chrt *chart.Chart, vals *chart.Config) ([]string, error) {
+	if vals == nil {
+		return nil, nil
+	}
+	v, err := ReadValues([]byte(vals.Raw))
+	if err != nil {
+		return nil, err
+	}
+	unknown, err := unknownChartValues(chrt, v, nil, "")
+	sort.Strings(unknown)
+	return unknown, err
+}
+
+// unknownChartValues returns the keys of v, prefixed with prefix, that are
+// unknown to chrt. parent are the tables the parent charts set for chrt.
+func unknownChartValues(chrt *chart.Chart, v map[string]interface{}, parent []map[string]interface{}, prefix string) ([]string, error) {
+	defaults := parent
+	if chrt.Values != nil && chrt.Values.Raw != "" {
+		own, err := ReadValues([]byte(chrt.Values.Raw))
+		if err != nil {
+			return nil, fmt.Errorf("error reading default values of chart %s: %s", chrt.Metadata.Name, err)
+		}
+		defaults = append(defaults, own)
+	}
+	var schemas []map[string]interface{}
+	schema, err := readSchema(chrt)
+	if err != nil {
+		return nil, err
+	}
+	if schema != nil {
+		schemas = append(schemas, schema)
+	}
+	deps := map[string]*chart.Chart{}
+	for _, d := range chrt.Dependencies {
+		deps[d.Metadata.Name] = d
+	}
+
+	var unknown []string
+	own := map[string]interface{}{}
+	for key, val := range v {
+		switch {
+		case key == GlobalKey:
+		case key == PassthroughKey && istable(val):
+			for name, t := range val.(map[string]interface{}) {
+				dep, ok := deps[name]
+				if !ok {
+					unknown = append(unknown, prefix+PassthroughKey+"."+name)
+					continue
+				}
+				if !istable(t) {
+					continue
+				}
+				keys, err := unknownChartValues(dep, t.(map[string]interface{}), subchartTables(defaults, name), prefix+PassthroughKey+"."+name+".")
+				if err != nil {
+					return nil, err
+				}
+				unknown = append(unknown, keys...)
+			}
+		case deps[key] != nil:
+			if !istable(val) {
+				continue
+			}
+			keys, err := unknownChartValues(deps[key], val.(map[string]interface{}), subchartTables(defaults, key), prefix+key+".")
+			if err != nil {
+				return nil, err
+			}
+			unknown = append(unknown, keys...)
+		default:
+			own[key] = val
+		}
+	}
+	return append(unknown, unknownKeys(own, defaults, schemas, prefix)...), nil
+}
+
+// subchartTables returns the tables that defaults set for the named subchart,
+// directly or under "passthrough".
+func subchartTables(defaults []map[string]interface{}, name string) []map[string]interface{} {
+	var tables []map[string]interface{}
+	for _, d := range defaults {
+		if t, ok := d[name].(map[string]interface{}); ok {
+			tables = append(tables, t)
+		}
+		if pt, ok := d[PassthroughKey].(map[string]interface{}); ok {
+			if t, ok := pt[name].(map[string]interface{}); ok {
+				tables = append(tables, t)
+			}
+		}
+	}
+	return tables
+}
+
+// unknownKeys returns the keys of v, prefixed with prefix, that are in
+// neither of defaults nor in the properties of schemas.
+func unknownKeys(v map[string]interface{}, defaults, schemas []map[string]interface{}, prefix string) []string {
+	var unknown []string
+	for key, val := range v {
+		known, open := false, false
+		var subDefaults, subSchemas []map[string]interface{}
+		for _, d := range defaults {
+			dv, ok := d[key]
+			if !ok {
+				continue
+			}
+			known = true
+			if t, ok := dv.(map[string]interface{}); ok && len(t) > 0 {
+				subDefaults = append(subDefaults, t)
+			} else {
+				open = true
+			}
+		}
+		for _, s := range schemas {
+			ps := propertySchema(s, key)
+			if ps == nil {
+				continue
+			}
+			known = true
+			if _, ok := ps["properties"].(map[string]interface{}); ok {
+				subSchemas = append(subSchemas, ps)
+			} else {
+				open = true
+			}
+		}
+		switch {
+		case !known:
+			unknown = append(unknown, prefix+key)
+		case !open && istable(val):
+			unknown = append(unknown, unknownKeys(val.(map[string]interface{}), subDefaults, subSchemas, prefix+key+".")...)
+		}
+	}
+	return unknown
+}
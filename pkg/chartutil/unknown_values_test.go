@@ -0,0 +1,106 @@
+/*
+Copyright 2017 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package chartutil
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/golang/protobuf/ptypes/any"
+
+	"k8s.io/helm/pkg/proto/hapi/chart"
+)
+
+func TestUnknownValues(t *testing.T) {
+	db := &chart.Chart{
+		Metadata: &chart.Metadata{Name: "db"},
+		Values:   &chart.Config{Raw: "image:\n  tag: \"9.6\"\npersistence:\n  size: 8Gi\n"},
+	}
+	app := &chart.Chart{
+		Metadata: &chart.Metadata{Name: "app"},
+		Values: &chart.Config{Raw: `image:
+  repository: example/app
+  tag: v1
+podAnnotations: {}
+resources:
+db:
+  metrics: true
+`},
+		Files: []*any.Any{
+			{TypeUrl: SchemaFile, Value: []byte(`{"properties": {"ingress": {"properties": {"host": {"type": "string"}}}, "env": {"type": "object"}}}`)},
+		},
+		Dependencies: []*chart.Chart{db},
+	}
+
+	vals := &chart.Config{Raw: `image:
+  tga: v2
+  tag: v2
+imagePullPolicy: Always
+podAnnotations:
+  example.com/team: web
+resources:
+  limits:
+    cpu: 1
+ingress:
+  host: example.com
+  hots: example.com
+env:
+  ANY_NAME: value
+global:
+  anything: true
+db:
+  metrics: false
+  image:
+    tag: "10"
+  persistance:
+    size: 1Gi
+passthrough:
+  db:
+    persistence:
+      size: 2Gi
+      class: fast
+  cache:
+    enabled: true
+`}
+	unknown, err := UnknownValues(app, vals)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expect := []string{
+		"db.persistance",
+		"image.tga",
+		"imagePullPolicy",
+		"ingress.hots",
+		"passthrough.cache",
+		"passthrough.db.persistence.class",
+	}
+	if !reflect.DeepEqual(unknown, expect) {
+		t.Errorf("Expected %v, got %v", expect, unknown)
+	}
+
+	err = CheckUnknownValues(app, vals)
+	if err == nil || !strings.HasSuffix(err.Error(), strings.Join(expect, ", ")) {
+		t.Errorf("Expected all unknown values to be reported, got %v", err)
+	}
+	if err := CheckUnknownValues(app, &chart.Config{Raw: "image:\n  tag: v2\n"}); err != nil {
+		t.Errorf("Expected known values to pass, got %s", err)
+	}
+	if err := CheckUnknownValues(app, nil); err != nil {
+		t.Errorf("Expected no values to pass, got %s", err)
+	}
+}
@@ -37,6 +37,10 @@ type ErrNoValue error
 // GlobalKey is the name of the Values key that is used for storing global vars.
 const GlobalKey = "global"
 
+// PassthroughKey is the name of the Values key whose tables, named after
+// subcharts, are merged into the values of those subcharts.
+const PassthroughKey = "passthrough"
+
 // Values represents a collection of chart values.
 type Values map[string]interface{}
 
@@ -131,15 +135,44 @@ func ReadValuesFile(filename string) (Values, error) {
 	return ReadValues(data)
 }
 
+// OverrideValues merges override over base. Values in override take
+// precedence; maps are merged key by key. Either may be nil.
+func OverrideValues(base, override *chart.Config) (*chart.Config, error) {
+	bv, ov := Values{}, Values{}
+	var err error
+	if base != nil {
+		if bv, err = ReadValues([]byte(base.Raw)); err != nil {
+			return nil, err
+		}
+	}
+	if override != nil {
+		if ov, err = ReadValues([]byte(override.Raw)); err != nil {
+			return nil, err
+		}
+	}
+	merged, err := Values(coalesceTables(ov, bv)).YAML()
+	if err != nil {
+		return nil, err
+	}
+	return &chart.Config{Raw: merged}, nil
+}
+
 // CoalesceValues coalesces all of the values in a chart (and its subcharts).
 //
 // Values are coalesced together using the following rules:
 //
 //	- Values in a higher level chart always override values in a lower-level
 //		dependency chart
-//	- Scalar values and arrays are replaced, maps are merged
+//	- Scalar values and arrays are replaced, maps are merged. The values
+//		schema of a chart may have an array appended to or merged instead;
+//		see MergeAppend and MergeByKey.
 //	- A chart has access to all of the variables for it, as well as all of
 //		the values destined for its dependencies.
+//	- A table under "passthrough" named after a dependency is merged into
+//		the values of that dependency. Values set for the dependency directly
+//		override it, as do passed-in values over the chart's own values.
+//	- Values that are still missing are filled in from the defaults of the
+//		charts' values schemas; see ApplySchemaDefaults.
 func CoalesceValues(chrt *chart.Chart, vals *chart.Config) (Values, error) {
 	cvals := Values{}
 	// Parse values if not nil. We merge these at the top level because
@@ -149,15 +182,22 @@ func CoalesceValues(chrt *chart.Chart, vals *chart.Config) (Values, error) {
 		if err != nil {
 			return cvals, err
 		}
+		// This coalesces the dependencies too. They are not coalesced a
+		// second time, as appending arrays to each other twice would
+		// duplicate their elements.
 		cvals, err = coalesce(chrt, evals)
 		if err != nil {
 			return cvals, err
 		}
+	} else {
+		var err error
+		if cvals, err = coalesceDeps(chrt, cvals); err != nil {
+			return cvals, err
+		}
 	}
-
-	var err error
-	cvals, err = coalesceDeps(chrt, cvals)
-	return cvals, err
+	// Schema defaults only fill in what neither the passed-in values nor the
+	// charts' values set.
+	return cvals, ApplySchemaDefaults(chrt, cvals)
 }
 
 // coalesce coalesces the dest values and the chart values, giving priority to the dest values.
@@ -169,8 +209,7 @@ func coalesce(ch *chart.Chart, dest map[string]interface{}) (map[string]interfac
 	if err != nil {
 		return dest, err
 	}
-	coalesceDeps(ch, dest)
-	return dest, nil
+	return coalesceDeps(ch, dest)
 }
 
 // coalesceDeps coalesces the dependencies of the given chart.
@@ -260,10 +299,35 @@ func copyMap(src map[string]interface{}) map[string]interface{} {
 	return dest
 }
 
+// DeepCopy returns a copy of v in which tables and lists, at any depth, are
+// copied as well, so that changing the copy leaves v unchanged. Values are
+// copied as Values; scalars are returned as they are.
+func DeepCopy(v interface{}) interface{} {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		c := make(map[string]interface{}, len(v))
+		for k, item := range v {
+			c[k] = DeepCopy(item)
+		}
+		return c
+	case Values:
+		return Values(DeepCopy(map[string]interface{}(v)).(map[string]interface{}))
+	case []interface{}:
+		c := make([]interface{}, len(v))
+		for i, item := range v {
+			c[i] = DeepCopy(item)
+		}
+		return c
+	}
+	return v
+}
+
 // coalesceValues builds up a values map for a particular chart.
 //
 // Values in v will override the values in the chart.
 func coalesceValues(c *chart.Chart, v map[string]interface{}) (map[string]interface{}, error) {
+	coalescePassthrough(c, v)
+
 	// If there are no values in the chart, we just return the given values
 	if c.Values == nil || c.Values.Raw == "" {
 		return v, nil
@@ -276,11 +340,21 @@ func coalesceValues(c *chart.Chart, v map[string]interface{}) (map[string]interf
 		// did not parse.
 		return v, fmt.Errorf("error reading default values (%s): %s", c.Values.Raw, err)
 	}
+	coalescePassthrough(c, nv)
+
+	schema, err := readSchema(c)
+	if err != nil {
+		return v, err
+	}
 
 	for key, val := range nv {
 		if _, ok := v[key]; !ok {
 			// If the key is not in v, copy it from nv.
 			v[key] = val
+		} else if dest, ok := v[key].([]interface{}); ok {
+			if src, ok := val.([]interface{}); ok {
+				v[key] = mergeArrays(dest, src, propertySchema(schema, key), key)
+			}
 		} else if dest, ok := v[key].(map[string]interface{}); ok {
 			// if v[key] is a table, merge nv's val table into v[key].
 			src, ok := val.(map[string]interface{})
@@ -290,16 +364,63 @@ func coalesceValues(c *chart.Chart, v map[string]interface{}) (map[string]interf
 			}
 			// Because v has higher precedence than nv, dest values override src
 			// values.
-			coalesceTables(dest, src)
+			coalesceTablesSchema(dest, src, propertySchema(schema, key))
 		}
 	}
 	return v, nil
 }
 
+// coalescePassthrough merges the tables under the passthrough key of v into
+// the values of the dependencies of c that they are named after. Values in v
+// that target a dependency directly take precedence.
+//
+// It is applied to the passed-in values and to the chart's own values before
+// they are merged, so that passed-in values, whether direct or passthrough,
+// override the chart's values of either kind.
+func coalescePassthrough(c *chart.Chart, v map[string]interface{}) {
+	pt, ok := v[PassthroughKey]
+	if !ok {
+		return
+	}
+	ptmap, ok := pt.(map[string]interface{})
+	if !ok {
+		log.Printf("warning: skipping %s because it is not a table.", PassthroughKey)
+		return
+	}
+	for _, subchart := range c.Dependencies {
+		name := subchart.Metadata.Name
+		src, ok := ptmap[name]
+		if !ok {
+			continue
+		}
+		if !istable(src) {
+			log.Printf("warning: skipped %s value for %s: Not a table.", PassthroughKey, name)
+			continue
+		}
+		// Copy the table, so that coalescing the subchart does not change the
+		// passthrough values the chart itself sees.
+		src = DeepCopy(src)
+		if dest, ok := v[name]; !ok {
+			v[name] = src
+		} else if istable(dest) {
+			coalesceTables(dest.(map[string]interface{}), src.(map[string]interface{}))
+		} else {
+			log.Printf("warning: skipped %s value for %s: destination is not a table.", PassthroughKey, name)
+		}
+	}
+}
+
 // coalesceTables merges a source map into a destination map.
 //
 // dest is considered authoritative.
 func coalesceTables(dst, src map[string]interface{}) map[string]interface{} {
+	return coalesceTablesSchema(dst, src, nil)
+}
+
+// coalesceTablesSchema merges a source map into a destination map like
+// coalesceTables, merging the arrays that schema, the values schema of the
+// maps, gives a merge strategy. schema may be nil.
+func coalesceTablesSchema(dst, src, schema map[string]interface{}) map[string]interface{} {
 	// Because dest has higher precedence than src, dest values override src
 	// values.
 	for key, val := range src {
@@ -307,7 +428,7 @@ func coalesceTables(dst, src map[string]interface{}) map[string]interface{} {
 			if innerdst, ok := dst[key]; !ok {
 				dst[key] = val
 			} else if istable(innerdst) {
-				coalesceTables(innerdst.(map[string]interface{}), val.(map[string]interface{}))
+				coalesceTablesSchema(innerdst.(map[string]interface{}), val.(map[string]interface{}), propertySchema(schema, key))
 			} else {
 				log.Printf("warning: cannot overwrite table with non table for %s (%v)", key, val)
 			}
@@ -318,6 +439,10 @@ func coalesceTables(dst, src map[string]interface{}) map[string]interface{} {
 		} else if !ok { // <- ok is still in scope from preceding conditional.
 			dst[key] = val
 			continue
+		} else if da, ok := dv.([]interface{}); ok {
+			if sa, ok := val.([]interface{}); ok {
+				dst[key] = mergeArrays(da, sa, propertySchema(schema, key), key)
+			}
 		}
 	}
 	return dst
@@ -332,6 +457,12 @@ type ReleaseOptions struct {
 	IsUpgrade bool
 	IsInstall bool
 	Revision  int
+	// PreviousValues are the values the last revision was rendered with,
+	// exposed to an upgrade as .Release.PreviousValues.
+	PreviousValues Values
+	// Flags are the feature flags of the operation, exposed as
+	// .Release.Flags. Flags that are not set are false.
+	Flags map[string]bool
 }
 
 // ToRenderValues composes the struct from the data coming from the Releases, Charts and Values files
@@ -349,6 +480,14 @@ func ToRenderValues(chrt *chart.Chart, chrtVals *chart.Config, options ReleaseOp
 //
 // This takes both ReleaseOptions and Capabilities to merge into the render values.
 func ToRenderValuesCaps(chrt *chart.Chart, chrtVals *chart.Config, options ReleaseOptions, caps *Capabilities) (Values, error) {
+	previous := options.PreviousValues
+	if previous == nil {
+		previous = Values{}
+	}
+	flags := options.Flags
+	if flags == nil {
+		flags = map[string]bool{}
+	}
 
 	top := map[string]interface{}{
 		"Release": map[string]interface{}{
@@ -359,10 +498,19 @@ func ToRenderValuesCaps(chrt *chart.Chart, chrtVals *chart.Config, options Relea
 			"IsInstall": options.IsInstall,
 			"Revision":  options.Revision,
 			"Service":   "Tiller",
+			// PreviousValues is empty unless Tiller renders an upgrade.
+			"PreviousValues": previous,
+			"Flags":          flags,
 		},
 		"Chart":        chrt.Metadata,
 		"Files":        NewFiles(chrt.Files),
 		"Capabilities": caps,
+		// LoadBalancers maps LoadBalancer Service names to their external
+		// address. Tiller fills it in when it re-renders NOTES after a wait.
+		"LoadBalancers": map[string]interface{}{},
+		// Live maps "Kind/name" to the live state of the release's resources.
+		// Tiller fills it in when it re-renders deferred NOTES.
+		"Live": map[string]interface{}{},
 	}
 
 	vals, err := CoalesceValues(chrt, chrtVals)
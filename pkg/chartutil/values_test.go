@@ -109,6 +109,7 @@ where:
 		Namespace: "al Basrah",
 		IsInstall: true,
 		Revision:  5,
+		Flags:     map[string]bool{"canary": true},
 	}
 
 	caps := &Capabilities{
@@ -139,6 +140,12 @@ where:
 	if !relmap["IsInstall"].(bool) {
 		t.Errorf("Expected install to be true.")
 	}
+	if prev := relmap["PreviousValues"].(Values); len(prev) != 0 {
+		t.Errorf("Expected no previous values, got %v", prev)
+	}
+	if flags := relmap["Flags"].(map[string]bool); !flags["canary"] || flags["dark-mode"] {
+		t.Errorf("Expected only the canary flag to be set, got %v", flags)
+	}
 	if data := res["Files"].(Files)["scheherazade/shahryar.txt"]; string(data) != "1,001 Nights" {
 		t.Errorf("Expected file '1,001 Nights', got %q", string(data))
 	}
@@ -151,6 +158,12 @@ where:
 	if res["Capabilities"].(*Capabilities).KubeVersion.Major != "1" {
 		t.Error("Expected Capabilities to have a Kube version")
 	}
+	if lbs, ok := res["LoadBalancers"].(map[string]interface{}); !ok || len(lbs) != 0 {
+		t.Errorf("Expected LoadBalancers to be empty, got %v", res["LoadBalancers"])
+	}
+	if live, ok := res["Live"].(map[string]interface{}); !ok || len(live) != 0 {
+		t.Errorf("Expected Live to be empty, got %v", res["Live"])
+	}
 
 	var vals Values
 	vals = res["Values"].(Values)
@@ -345,6 +358,152 @@ func TestCoalesceValues(t *testing.T) {
 	}
 }
 
+func TestCoalesceValuesPassthrough(t *testing.T) {
+	metrics := &chart.Chart{
+		Metadata: &chart.Metadata{Name: "metrics"},
+		Values:   &chart.Config{Raw: "interval: 30s\n"},
+	}
+	db := &chart.Chart{
+		Metadata: &chart.Metadata{Name: "db"},
+		Values: &chart.Config{Raw: `
+name: db
+port: 5432
+user: postgres
+password: ""
+tls:
+  enabled: false
+  mode: require
+`},
+		Dependencies: []*chart.Chart{metrics},
+	}
+	parent := &chart.Chart{
+		Metadata: &chart.Metadata{Name: "platform"},
+		Values: &chart.Config{Raw: `
+db:
+  port: 5433
+passthrough:
+  db:
+    port: 1111
+    user: platform
+    tls:
+      enabled: true
+  unknown:
+    ignored: true
+`},
+		Dependencies: []*chart.Chart{db},
+	}
+	vals := &chart.Config{Raw: `
+db:
+  password: s3cret
+passthrough:
+  db:
+    user: app
+    password: overridden
+    port: 6000
+    passthrough:
+      metrics:
+        interval: 10s
+`}
+
+	v, err := CoalesceValues(parent, vals)
+	if err != nil {
+		t.Fatal(err)
+	}
+	j, _ := json.MarshalIndent(v, "", "  ")
+	t.Logf("Coalesced Values: %s", string(j))
+
+	tests := []struct {
+		tpl    string
+		expect string
+	}{
+		// Passed-in passthrough values override the parent's direct values.
+		{"{{.db.port}}", "6000"},
+		// Passed-in passthrough values override the parent's passthrough values.
+		{"{{.db.user}}", "app"},
+		// Direct values override passthrough values from the same source.
+		{"{{.db.password}}", "s3cret"},
+		// Passthrough values override the subchart's defaults, table by table.
+		{"{{.db.tls.enabled}}", "true"},
+		{"{{.db.tls.mode}}", "require"},
+		{"{{.db.name}}", "db"},
+		// A subchart can pass values through to its own subcharts.
+		{"{{.db.metrics.interval}}", "10s"},
+		// The chart still sees its passthrough values, without the subchart's
+		// defaults merged in.
+		{"{{.passthrough.db.user}}", "app"},
+		{"{{.passthrough.db.tls.mode}}", "<no value>"},
+		// Tables named after no subchart are left alone.
+		{"{{.unknown}}", "<no value>"},
+		{"{{.passthrough.unknown.ignored}}", "true"},
+	}
+
+	for _, tt := range tests {
+		if o, err := ttpl(tt.tpl, v); err != nil || o != tt.expect {
+			t.Errorf("Expected %q to expand to %q, got %q", tt.tpl, tt.expect, o)
+		}
+	}
+}
+
+func TestCoalesceValuesPassthroughConflicts(t *testing.T) {
+	db := &chart.Chart{
+		Metadata: &chart.Metadata{Name: "db"},
+		Values:   &chart.Config{Raw: "port: 5432\nauth:\n  user: postgres\n"},
+	}
+	parent := &chart.Chart{
+		Metadata:     &chart.Metadata{Name: "platform"},
+		Dependencies: []*chart.Chart{db},
+	}
+
+	tests := []struct {
+		name   string
+		vals   string
+		tpl    string
+		expect string
+	}{
+		{
+			name:   "direct override wins over passthrough",
+			vals:   "db:\n  port: 6000\npassthrough:\n  db:\n    port: 7000\n",
+			tpl:    "{{.db.port}}",
+			expect: "6000",
+		},
+		{
+			name:   "passthrough tables are merged with direct tables",
+			vals:   "db:\n  auth:\n    password: s3cret\npassthrough:\n  db:\n    auth:\n      user: app\n",
+			tpl:    "{{.db.auth.user}}/{{.db.auth.password}}",
+			expect: "app/s3cret",
+		},
+		{
+			name:   "a direct scalar is not replaced by a passthrough table",
+			vals:   "db:\n  auth: none\npassthrough:\n  db:\n    auth:\n      user: app\n",
+			tpl:    "{{.db.auth}}",
+			expect: "none",
+		},
+		{
+			name:   "a passthrough scalar for a subchart is ignored",
+			vals:   "passthrough:\n  db: disabled\n",
+			tpl:    "{{.db.port}}",
+			expect: "5432",
+		},
+		{
+			name:   "a passthrough scalar is ignored",
+			vals:   "passthrough: none\n",
+			tpl:    "{{.db.port}}",
+			expect: "5432",
+		},
+	}
+
+	for _, tt := range tests {
+		v, err := CoalesceValues(parent, &chart.Config{Raw: tt.vals})
+		if err != nil {
+			t.Errorf("%s: %s", tt.name, err)
+			continue
+		}
+		if o, err := ttpl(tt.tpl, v); err != nil || o != tt.expect {
+			t.Errorf("%s: expected %q to expand to %q, got %q", tt.name, tt.tpl, tt.expect, o)
+		}
+	}
+}
+
 func TestCoalesceTables(t *testing.T) {
 	dst := map[string]interface{}{
 		"name": "Ishmael",
@@ -407,6 +566,46 @@ func TestCoalesceTables(t *testing.T) {
 		t.Errorf("Expected boat string, got %v", dst["boat"])
 	}
 }
+
+func TestOverrideValues(t *testing.T) {
+	base := &chart.Config{Raw: "image:\n  repo: web\n  tag: 1.0.0\nreplicas: 2\n"}
+	override := &chart.Config{Raw: "image:\n  tag: 1.0.1\n"}
+
+	merged, err := OverrideValues(base, override)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expect := "image:\n  repo: web\n  tag: 1.0.1\nreplicas: 2\n"
+	if merged.Raw != expect {
+		t.Errorf("Expected %q, got %q", expect, merged.Raw)
+	}
+
+	if merged, err = OverrideValues(base, nil); err != nil {
+		t.Fatal(err)
+	} else if merged.Raw != base.Raw {
+		t.Errorf("Expected %q, got %q", base.Raw, merged.Raw)
+	}
+
+	if _, err := OverrideValues(base, &chart.Config{Raw: "- not a map"}); err == nil {
+		t.Error("Expected an error for values that are not a map")
+	}
+}
+
+func TestDeepCopy(t *testing.T) {
+	v := Values{"a": map[string]interface{}{"b": []interface{}{map[string]interface{}{"c": 1}}}}
+	c, ok := DeepCopy(v).(Values)
+	if !ok {
+		t.Fatalf("Expected a copy of Values to be Values, got %T", DeepCopy(v))
+	}
+	c["a"].(map[string]interface{})["b"].([]interface{})[0].(map[string]interface{})["c"] = 2
+	if v["a"].(map[string]interface{})["b"].([]interface{})[0].(map[string]interface{})["c"] != 1 {
+		t.Error("Expected changing the copy to leave the original unchanged")
+	}
+	if DeepCopy("scalar") != "scalar" {
+		t.Error("Expected scalars to be returned as they are")
+	}
+}
+
 func TestPathValue(t *testing.T) {
 	doc := `
 title: "Moby Dick"
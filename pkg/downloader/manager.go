@@ -222,15 +222,16 @@ func (m *Manager) downloadAll(deps []*chartutil.Dependency) error {
 			return err
 		}
 
-		if strings.HasPrefix(dep.Repository, "file://") {
-			if m.Debug {
-				fmt.Fprintf(m.Out, "Archiving %s from repo %s\n", dep.Name, dep.Repository)
-			}
+		if resolver.IsLocalRepository(dep.Repository) {
+			// The lock keeps the version range of a local chart, so that it is
+			// archived afresh from its directory on every build.
 			ver, err := tarFromLocalDir(m.ChartPath, dep.Name, dep.Repository, dep.Version)
 			if err != nil {
 				return err
 			}
-			dep.Version = ver
+			if m.Debug {
+				fmt.Fprintf(m.Out, "Archived %s %s from %s\n", dep.Name, ver, dep.Repository)
+			}
 			continue
 		}
 
@@ -296,7 +297,7 @@ func (m *Manager) hasAllRepos(deps []*chartutil.Dependency) error {
 	missing := []string{}
 	for _, dd := range deps {
 		// If repo is from local path, continue
-		if strings.HasPrefix(dd.Repository, "file://") {
+		if resolver.IsLocalRepository(dd.Repository) {
 			continue
 		}
 
@@ -335,7 +336,7 @@ func (m *Manager) getRepoNames(deps []*chartutil.Dependency) (map[string]string,
 	missing := []string{}
 	for _, dd := range deps {
 		// if dep chart is from local path, verify the path is valid
-		if strings.HasPrefix(dd.Repository, "file://") {
+		if resolver.IsLocalRepository(dd.Repository) {
 			if _, err := resolver.GetLocalPath(dd.Repository, m.ChartPath); err != nil {
 				return nil, err
 			}
@@ -543,7 +544,7 @@ func writeLock(chartpath string, lock *chartutil.RequirementsLock) error {
 func tarFromLocalDir(chartpath string, name string, repo string, version string) (string, error) {
 	destPath := filepath.Join(chartpath, "charts")
 
-	if !strings.HasPrefix(repo, "file://") {
+	if !resolver.IsLocalRepository(repo) {
 		return "", fmt.Errorf("wrong format: chart %s repository %s", name, repo)
 	}
 
@@ -17,6 +17,9 @@ package downloader
 
 import (
 	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
 	"reflect"
 	"testing"
 
@@ -156,3 +159,62 @@ func TestGetRepoNames(t *testing.T) {
 		}
 	}
 }
+
+func TestUpdateLocalDependency(t *testing.T) {
+	dir, err := ioutil.TempDir("", "helm-local-dep-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	writeFile := func(name, content string) {
+		p := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(p), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := ioutil.WriteFile(p, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	writeFile("parent/Chart.yaml", "name: parent\nversion: 0.1.0\n")
+	writeFile("parent/requirements.yaml", "dependencies:\n- name: child\n  version: ~0.1.0\n  repository: ../child\n")
+	writeFile("child/Chart.yaml", "name: child\nversion: 0.1.0\n")
+
+	m := &Manager{
+		Out:        bytes.NewBuffer(nil),
+		ChartPath:  filepath.Join(dir, "parent"),
+		HelmHome:   helmpath.Home("testdata/helmhome"),
+		SkipUpdate: true,
+	}
+	archives := func() []string {
+		files, err := filepath.Glob(filepath.Join(dir, "parent", "charts", "*.tgz"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		for i, f := range files {
+			files[i] = filepath.Base(f)
+		}
+		return files
+	}
+
+	if err := m.Update(); err != nil {
+		t.Fatal(err)
+	}
+	if got := archives(); !reflect.DeepEqual(got, []string{"child-0.1.0.tgz"}) {
+		t.Errorf("expected child-0.1.0.tgz, got %v", got)
+	}
+
+	// Changes to the local chart are picked up by the next update or build.
+	writeFile("child/Chart.yaml", "name: child\nversion: 0.1.1\n")
+	if err := m.Build(); err != nil {
+		t.Fatal(err)
+	}
+	if got := archives(); !reflect.DeepEqual(got, []string{"child-0.1.1.tgz"}) {
+		t.Errorf("expected child-0.1.1.tgz, got %v", got)
+	}
+
+	writeFile("child/Chart.yaml", "name: child\nversion: 1.0.0\n")
+	if err := m.Update(); err == nil {
+		t.Error("expected an error for a local chart that does not satisfy the version range")
+	}
+}
@@ -18,10 +18,14 @@ package engine
 
 import (
 	"bytes"
+	"crypto/rand"
+	"encoding/base64"
 	"fmt"
+	"os"
 	"path"
 	"strings"
 	"text/template"
+	"time"
 
 	"github.com/Masterminds/sprig"
 
@@ -37,6 +41,21 @@ type Engine struct {
 	// If strict is enabled, template rendering will fail if a template references
 	// a value that was not passed in.
 	Strict bool
+	// EnvAllowlist names the environment variables that the "env" template
+	// function may read. Other variables read as empty, or fail the render if
+	// StrictEnv is set.
+	EnvAllowlist []string
+	// StrictEnv makes the "env" template function fail for variables that are
+	// not in EnvAllowlist.
+	StrictEnv bool
+	// Timeout limits how long a render may take. A render that takes longer
+	// fails, and stops the next time a template writes output, includes a
+	// template or loops. Zero means no limit.
+	Timeout time.Duration
+	// MaxIncludeDepth limits how deeply "include" calls and {{template}}
+	// actions may nest, so that a template that includes itself fails
+	// instead of exhausting the stack. Zero means DefaultMaxIncludeDepth.
+	MaxIncludeDepth int
 }
 
 // New creates a new Go template Engine instance.
@@ -45,7 +64,8 @@ type Engine struct {
 // first invocation of Render.
 //
 // The FuncMap sets all of the Sprig functions except for those that provide
-// access to the underlying OS (env, expandenv).
+// access to the underlying OS (env, expandenv). In their place, "env" only
+// reads the variables listed in EnvAllowlist.
 func New() *Engine {
 	f := FuncMap()
 	return &Engine{
@@ -65,6 +85,12 @@ func New() *Engine {
 //	   included in the FuncMap is a placeholder.
 //      - "required": This is late-bound in Engine.Render(). The version
 //	   included in thhe FuncMap is a placeholder.
+//	- "env": This is late-bound in Engine.Render(). The version included in
+//	   the FuncMap always returns an empty string.
+//	- "randAlphaNumOnce": This is late-bound in Engine.Render(). The version
+//	   included in the FuncMap always returns an empty string.
+//	- "tpl": This is late-bound in Engine.Render(). The version included in
+//	   the FuncMap is a placeholder.
 func FuncMap() template.FuncMap {
 	f := sprig.TxtFuncMap()
 	delete(f, "env")
@@ -77,12 +103,17 @@ func FuncMap() template.FuncMap {
 		"fromYaml": chartutil.FromYaml,
 		"toJson":   chartutil.ToJson,
 		"fromJson": chartutil.FromJson,
+		"b64enc":   b64enc,
 
 		// This is a placeholder for the "include" function, which is
 		// late-bound to a template. By declaring it here, we preserve the
 		// integrity of the linter.
 		"include":  func(string, interface{}) string { return "not implemented" },
 		"required": func(string, interface{}) interface{} { return "not implemented" },
+		"tpl":      func(string, interface{}) string { return "not implemented" },
+		"env":      func(string) string { return "" },
+
+		"randAlphaNumOnce": func(string, int) string { return "" },
 	}
 
 	for k, v := range extra {
@@ -92,6 +123,19 @@ func FuncMap() template.FuncMap {
 	return f
 }
 
+// b64enc base64-encodes a string or, unlike the Sprig function it replaces,
+// the raw bytes of a file from .Files.GetBytes or .Files.Glob.
+func b64enc(v interface{}) string {
+	switch v := v.(type) {
+	case []byte:
+		return base64.StdEncoding.EncodeToString(v)
+	case string:
+		return base64.StdEncoding.EncodeToString([]byte(v))
+	default:
+		return base64.StdEncoding.EncodeToString([]byte(fmt.Sprint(v)))
+	}
+}
+
 // Render takes a chart, optional values, and value overrides, and attempts to render the Go templates.
 //
 // Render can be called repeatedly on the same engine.
@@ -114,7 +158,31 @@ func FuncMap() template.FuncMap {
 func (e *Engine) Render(chrt *chart.Chart, values chartutil.Values) (map[string]string, error) {
 	// Render the charts
 	tmap := allTemplates(chrt, values)
-	return e.render(tmap)
+	return e.render(tmap, newGenerator(nil), templatedValues(chrt, values))
+}
+
+// RenderGenerated renders like Render, and keeps the values that templates
+// generate with functions such as randAlphaNumOnce.
+//
+// Such a function called with a key that is in generated returns the value
+// stored under that key instead of generating a new one. When rendering
+// succeeds, generated is updated to hold the values of exactly the keys that
+// the templates used, so that it can be stored and passed to the next render
+// of the same release.
+func (e *Engine) RenderGenerated(chrt *chart.Chart, values chartutil.Values, generated map[string]string) (map[string]string, error) {
+	tmap := allTemplates(chrt, values)
+	g := newGenerator(generated)
+	rendered, err := e.render(tmap, g, templatedValues(chrt, values))
+	if err != nil {
+		return rendered, err
+	}
+	for k := range generated {
+		delete(generated, k)
+	}
+	for k, v := range g.used {
+		generated[k] = v
+	}
+	return rendered, nil
 }
 
 // renderable is an object that can be rendered.
@@ -129,8 +197,9 @@ type renderable struct {
 
 // alterFuncMap takes the Engine's FuncMap and adds context-specific functions.
 //
-// The resulting FuncMap is only valid for the passed-in template.
-func (e *Engine) alterFuncMap(t *template.Template) template.FuncMap {
+// The resulting FuncMap is only valid for the passed-in template, and the
+// render guarded by guard.
+func (e *Engine) alterFuncMap(t *template.Template, g *generator, guard *renderGuard) template.FuncMap {
 	// Clone the func map because we are adding context-specific functions.
 	var funcMap template.FuncMap = map[string]interface{}{}
 	for k, v := range e.FuncMap {
@@ -139,13 +208,31 @@ func (e *Engine) alterFuncMap(t *template.Template) template.FuncMap {
 
 	// Add the 'include' function here so we can close over t.
 	funcMap["include"] = func(name string, data interface{}) (string, error) {
+		if err := guard.enter(name); err != nil {
+			return "", err
+		}
+		defer guard.leave()
 		buf := bytes.NewBuffer(nil)
-		if err := t.ExecuteTemplate(buf, name, data); err != nil {
+		if err := t.ExecuteTemplate(guardedWriter{buf, guard}, name, data); err != nil {
 			return "", err
 		}
 		return buf.String(), nil
 	}
 
+	// Add the 'tpl' function here so that it can include the templates of t.
+	funcMap["tpl"] = func(text string, data interface{}) (string, error) {
+		if err := guard.enter("tpl"); err != nil {
+			return "", err
+		}
+		defer guard.leave()
+		return executeString(t, guard, "tpl", text, data)
+	}
+
+	// Add the check that guardTemplates adds to every {{range}}.
+	funcMap[guardCheckFunc] = func() (string, error) {
+		return "", guard.check()
+	}
+
 	// Add the 'required' function here
 	funcMap["required"] = func(warn string, val interface{}) (interface{}, error) {
 		if val == nil {
@@ -158,11 +245,33 @@ func (e *Engine) alterFuncMap(t *template.Template) template.FuncMap {
 		return val, nil
 	}
 
+	// Add the 'env' function here, restricted to the allowlisted variables.
+	funcMap["env"] = e.env
+
+	// Add the functions that generate values once per release.
+	funcMap["randAlphaNumOnce"] = g.randAlphaNumOnce
+
 	return funcMap
 }
 
-// render takes a map of templates/values and renders them.
-func (e *Engine) render(tpls map[string]renderable) (map[string]string, error) {
+// env returns the value of the named environment variable if it is allowlisted.
+func (e *Engine) env(name string) (string, error) {
+	for _, allowed := range e.EnvAllowlist {
+		if name == allowed {
+			return os.Getenv(name), nil
+		}
+	}
+	if e.StrictEnv {
+		return "", fmt.Errorf("environment variable %q is not allowed", name)
+	}
+	return "", nil
+}
+
+// render takes a map of templates/values and renders them. Generated values
+// are taken from, and recorded in, g. If top, the render values of the
+// top-level chart, is not nil, the strings of its values are rendered first;
+// see resolveValues.
+func (e *Engine) render(tpls map[string]renderable, g *generator, top chartutil.Values) (map[string]string, error) {
 	// Basically, what we do here is start with an empty parent template and then
 	// build up a list of templates -- one for each file. Once all of the templates
 	// have been parsed, we loop through again and execute every template.
@@ -179,7 +288,9 @@ func (e *Engine) render(tpls map[string]renderable) (map[string]string, error) {
 		t.Option("missingkey=zero")
 	}
 
-	funcMap := e.alterFuncMap(t)
+	guard, cancel := newRenderGuard(e.Timeout, e.MaxIncludeDepth)
+	defer cancel()
+	funcMap := e.alterFuncMap(t, g, guard)
 
 	files := []string{}
 	for fname, r := range tpls {
@@ -189,6 +300,27 @@ func (e *Engine) render(tpls map[string]renderable) (map[string]string, error) {
 		}
 		files = append(files, fname)
 	}
+	guardTemplates(t)
+
+	return guard.run(func() (map[string]string, error) {
+		return e.execute(t, tpls, files, guard, top)
+	})
+}
+
+// execute renders the parsed templates of files, after resolving the values
+// as render describes.
+func (e *Engine) execute(t *template.Template, tpls map[string]renderable, files []string, guard *renderGuard, top chartutil.Values) (map[string]string, error) {
+	if top != nil {
+		if err := e.resolveValues(t, tpls, guard, top); err != nil {
+			return map[string]string{}, err
+		}
+	}
+
+	// Computed values are resolved for every chart before any template is
+	// executed, so that subcharts see what their parents computed.
+	if err := e.resolveComputed(t, tpls, guard); err != nil {
+		return map[string]string{}, err
+	}
 
 	rendered := make(map[string]string, len(files))
 	var buf bytes.Buffer
@@ -201,8 +333,9 @@ func (e *Engine) render(tpls map[string]renderable) (map[string]string, error) {
 		// At render time, add information about the template that is being rendered.
 		vals := tpls[file].vals
 		vals["Template"] = map[string]interface{}{"Name": file, "BasePath": tpls[file].basePath}
-		if err := t.ExecuteTemplate(&buf, file, vals); err != nil {
-			return map[string]string{}, fmt.Errorf("render error in %q: %s", file, err)
+		guard.setStage(fmt.Sprintf("render error in %q", file))
+		if err := t.ExecuteTemplate(guardedWriter{&buf, guard}, file, vals); err != nil {
+			return map[string]string{}, fmt.Errorf("render error in %q: %s", file, guard.cause(err))
 		}
 
 		// Work around the issue where Go will emit "<no value>" even if Options(missing=zero)
@@ -215,6 +348,143 @@ func (e *Engine) render(tpls map[string]renderable) (map[string]string, error) {
 	return rendered, nil
 }
 
+// generator backs the template functions that generate a value once per
+// release, such as randAlphaNumOnce. It is created for a single render.
+type generator struct {
+	// previous are the values generated by earlier renders, by key.
+	previous map[string]string
+	// used are the values returned during this render, by key.
+	used map[string]string
+}
+
+func newGenerator(previous map[string]string) *generator {
+	return &generator{previous: previous, used: map[string]string{}}
+}
+
+// randAlphaNumOnce returns a random alphanumeric string of the given length.
+// The value is generated the first time key is used, and returned again for
+// the same key afterwards, even if a different length is asked for.
+func (g *generator) randAlphaNumOnce(key string, length int) (string, error) {
+	if v, ok := g.used[key]; ok {
+		return v, nil
+	}
+	if v, ok := g.previous[key]; ok {
+		g.used[key] = v
+		return v, nil
+	}
+	if key == "" {
+		return "", fmt.Errorf("randAlphaNumOnce: key must not be empty")
+	}
+	if length < 1 {
+		return "", fmt.Errorf("randAlphaNumOnce %q: length must be positive", key)
+	}
+	v, err := randAlphaNum(length)
+	if err != nil {
+		return "", fmt.Errorf("randAlphaNumOnce %q: %s", key, err)
+	}
+	g.used[key] = v
+	return v, nil
+}
+
+const alphaNum = "ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz0123456789"
+
+// randAlphaNum returns a random alphanumeric string from a cryptographically
+// secure source, as generated values are commonly passwords.
+func randAlphaNum(length int) (string, error) {
+	// Bytes at or above the largest multiple of len(alphaNum) are discarded
+	// so that every character is equally likely.
+	limit := 256 - 256%len(alphaNum)
+	out := make([]byte, 0, length)
+	buf := make([]byte, length)
+	for len(out) < length {
+		if _, err := rand.Read(buf); err != nil {
+			return "", err
+		}
+		for _, b := range buf {
+			if int(b) < limit && len(out) < length {
+				out = append(out, alphaNum[int(b)%len(alphaNum)])
+			}
+		}
+	}
+	return string(out), nil
+}
+
+// computedTemplate is the partial in which a chart may compute values for
+// itself and its subcharts. It is executed with the chart's own values, and its
+// output is parsed as YAML and exposed to templates as {{.Computed}}.
+const computedTemplate = "templates/_computed.yaml"
+
+// resolveComputed executes the computed template of every chart and sets the
+// "Computed" value for each renderable.
+//
+// Charts are resolved parent first: a chart's computed template can read the
+// values computed by its ancestors, and the resulting values are merged with
+// those of the ancestors. As with globals, values computed by a parent take
+// precedence over values a subchart computes under the same key.
+func (e *Engine) resolveComputed(t *template.Template, tpls map[string]renderable, guard *renderGuard) error {
+	resolved := map[string]chartutil.Values{}
+
+	var resolve func(chartPath string) (chartutil.Values, error)
+	resolve = func(chartPath string) (chartutil.Values, error) {
+		if vs, ok := resolved[chartPath]; ok {
+			return vs, nil
+		}
+
+		inherited := chartutil.Values{}
+		if parent := parentChartPath(chartPath); parent != "" {
+			var err error
+			if inherited, err = resolve(parent); err != nil {
+				return nil, err
+			}
+		}
+
+		computed := chartutil.Values{}
+		name := path.Join(chartPath, computedTemplate)
+		if r, ok := tpls[name]; ok {
+			r.vals["Computed"] = inherited
+			r.vals["Template"] = map[string]interface{}{"Name": name, "BasePath": r.basePath}
+			var buf bytes.Buffer
+			guard.setStage(fmt.Sprintf("render error in %q", name))
+			if err := t.ExecuteTemplate(guardedWriter{&buf, guard}, name, r.vals); err != nil {
+				return nil, fmt.Errorf("render error in %q: %s", name, guard.cause(err))
+			}
+			own, err := chartutil.ReadValues(buf.Bytes())
+			if err != nil {
+				return nil, fmt.Errorf("computed values in %q: %s", name, err)
+			}
+			for k, v := range own {
+				computed[k] = v
+			}
+		}
+		for k, v := range inherited {
+			computed[k] = v
+		}
+
+		resolved[chartPath] = computed
+		return computed, nil
+	}
+
+	for _, r := range tpls {
+		computed, err := resolve(path.Dir(r.basePath))
+		if err != nil {
+			return err
+		}
+		r.vals["Computed"] = computed
+	}
+	return nil
+}
+
+// parentChartPath returns the path of the chart that contains the chart at
+// chartPath, or the empty string for the top-level chart.
+func parentChartPath(chartPath string) string {
+	parts := strings.Split(chartPath, "/")
+	n := len(parts)
+	if n < 3 || parts[n-2] != "charts" {
+		return ""
+	}
+	return path.Join(parts[:n-2]...)
+}
+
 // allTemplates returns all templates for a chart and its dependencies.
 //
 // As it goes, it also prepares the values in a scope-sensitive manner.
@@ -247,11 +517,13 @@ func recAllTpls(c *chart.Chart, templates map[string]renderable, parentVals char
 		}
 
 		cvals = map[string]interface{}{
-			"Values":       newVals,
-			"Release":      parentVals["Release"],
-			"Chart":        c.Metadata,
-			"Files":        chartutil.NewFiles(c.Files),
-			"Capabilities": parentVals["Capabilities"],
+			"Values":        newVals,
+			"Release":       parentVals["Release"],
+			"Chart":         c.Metadata,
+			"Files":         chartutil.NewFiles(c.Files),
+			"Capabilities":  parentVals["Capabilities"],
+			"LoadBalancers": parentVals["LoadBalancers"],
+			"Live":          parentVals["Live"],
 		}
 	}
 
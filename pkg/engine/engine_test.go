@@ -17,21 +17,28 @@ limitations under the License.
 package engine
 
 import (
+	"bytes"
+	"encoding/base64"
 	"fmt"
+	"os"
+	"strings"
 	"sync"
 	"testing"
+	"time"
 
 	"k8s.io/helm/pkg/chartutil"
 	"k8s.io/helm/pkg/proto/hapi/chart"
 
+	"github.com/ghodss/yaml"
 	"github.com/golang/protobuf/ptypes/any"
 )
 
 func TestEngine(t *testing.T) {
 	e := New()
 
-	// Forbidden because they allow access to the host OS.
-	forbidden := []string{"env", "expandenv"}
+	// Forbidden because they allow access to the host OS. "env" is replaced
+	// by a placeholder; see TestFuncMap.
+	forbidden := []string{"expandenv"}
 	for _, f := range forbidden {
 		if _, ok := e.FuncMap[f]; ok {
 			t.Errorf("Forbidden function %s exists in FuncMap.", f)
@@ -41,13 +48,20 @@ func TestEngine(t *testing.T) {
 
 func TestFuncMap(t *testing.T) {
 	fns := FuncMap()
-	forbidden := []string{"env", "expandenv"}
+	forbidden := []string{"expandenv"}
 	for _, f := range forbidden {
 		if _, ok := fns[f]; ok {
 			t.Errorf("Forbidden function %s exists in FuncMap.", f)
 		}
 	}
 
+	// The env placeholder must not read the environment.
+	os.Setenv("HELM_ENGINE_FUNCMAP", "leaked")
+	defer os.Unsetenv("HELM_ENGINE_FUNCMAP")
+	if env, ok := fns["env"].(func(string) string); !ok || env("HELM_ENGINE_FUNCMAP") != "" {
+		t.Error("Expected the env placeholder to return an empty string")
+	}
+
 	// Test for Engine-specific template functions.
 	expect := []string{"include", "required", "toYaml", "fromYaml", "toToml", "toJson", "fromJson"}
 	for _, f := range expect {
@@ -123,7 +137,7 @@ func TestRenderInternals(t *testing.T) {
 		"three": {tpl: `{{template "two" dict "Value" "three"}}`, vals: vals},
 	}
 
-	out, err := e.render(tpls)
+	out, err := e.render(tpls, newGenerator(nil), nil)
 	if err != nil {
 		t.Fatalf("Failed template rendering: %s", err)
 	}
@@ -156,7 +170,7 @@ func TestParallelRenderInternals(t *testing.T) {
 			tt := fmt.Sprintf("expect-%d", i)
 			v := chartutil.Values{"val": tt}
 			tpls := map[string]renderable{fname: {tpl: `{{.val}}`, vals: v}}
-			out, err := e.render(tpls)
+			out, err := e.render(tpls, newGenerator(nil), nil)
 			if err != nil {
 				t.Errorf("Failed to render %s: %s", tt, err)
 			}
@@ -329,6 +343,61 @@ global:
 	}
 }
 
+func TestRenderBinaryFiles(t *testing.T) {
+	data := make([]byte, 512)
+	for i := range data {
+		data[i] = byte(i)
+	}
+	secret := `apiVersion: v1
+kind: Secret
+metadata:
+  name: certs
+data:
+{{ (.Files.Glob "files/*").AsSecrets | indent 2 }}
+  get: {{ .Files.Get "files/ca.der" | b64enc }}
+  getBytes: {{ .Files.GetBytes "files/ca.der" | b64enc }}
+`
+	c := &chart.Chart{
+		Metadata: &chart.Metadata{Name: "certs"},
+		Templates: []*chart.Template{
+			{Name: "templates/secret.yaml", Data: []byte(secret)},
+		},
+		Values: &chart.Config{Raw: ``},
+		Files: []*any.Any{
+			{TypeUrl: "files/ca.der", Value: data},
+			{TypeUrl: "README.md", Value: []byte("not included")},
+		},
+	}
+	vals := chartutil.Values{
+		"Values": map[string]interface{}{},
+		"Files":  chartutil.NewFiles(c.Files),
+	}
+
+	out, err := New().Render(c, vals)
+	if err != nil {
+		t.Fatalf("Failed to render templates: %s", err)
+	}
+	var rendered struct {
+		Data map[string]string `json:"data"`
+	}
+	if err := yaml.Unmarshal([]byte(out["certs/templates/secret.yaml"]), &rendered); err != nil {
+		t.Fatal(err)
+	}
+	if len(rendered.Data) != 3 {
+		t.Errorf("Expected 3 keys, got %v", rendered.Data)
+	}
+	for _, key := range []string{"ca.der", "get", "getBytes"} {
+		decoded, err := base64.StdEncoding.DecodeString(rendered.Data[key])
+		if err != nil {
+			t.Errorf("%s: %s", key, err)
+			continue
+		}
+		if !bytes.Equal(decoded, data) {
+			t.Errorf("%s: expected the file to survive rendering unchanged", key)
+		}
+	}
+}
+
 func TestRenderBuiltinValues(t *testing.T) {
 	inner := &chart.Chart{
 		Metadata: &chart.Metadata{Name: "Latium"},
@@ -381,6 +450,168 @@ func TestRenderBuiltinValues(t *testing.T) {
 
 }
 
+func TestRenderComputedValues(t *testing.T) {
+	subchart := func(name string) *chart.Chart {
+		return &chart.Chart{
+			Metadata: &chart.Metadata{Name: name},
+			Templates: []*chart.Template{
+				{Name: "templates/secret", Data: []byte(`password: {{ .Computed.password }}`)},
+			},
+			Values: &chart.Config{Raw: ``},
+		}
+	}
+
+	outer := &chart.Chart{
+		Metadata: &chart.Metadata{Name: "outer"},
+		Templates: []*chart.Template{
+			{Name: "templates/_computed.yaml", Data: []byte(`password: {{ randAlphaNum 16 | quote }}`)},
+			{Name: "templates/secret", Data: []byte(`password: {{ .Computed.password }}`)},
+		},
+		Values:       &chart.Config{Raw: ``},
+		Dependencies: []*chart.Chart{subchart("frontend"), subchart("backend")},
+	}
+
+	inject := chartutil.Values{
+		"Values":  &chart.Config{Raw: ""},
+		"Chart":   outer.Metadata,
+		"Release": chartutil.Values{"Name": "computed"},
+	}
+
+	out, err := New().Render(outer, inject)
+	if err != nil {
+		t.Fatalf("failed to render templates: %s", err)
+	}
+
+	if _, ok := out["outer/templates/_computed.yaml"]; ok {
+		t.Error("expected the computed template not to be rendered as a manifest")
+	}
+
+	expect := out["outer/templates/secret"]
+	if len(expect) != len("password: ")+16 {
+		t.Fatalf("unexpected computed password: %q", expect)
+	}
+	for _, file := range []string{"outer/charts/frontend/templates/secret", "outer/charts/backend/templates/secret"} {
+		if out[file] != expect {
+			t.Errorf("Expected %q in %s, got %q", expect, file, out[file])
+		}
+	}
+}
+
+func TestRenderComputedValuesPrecedence(t *testing.T) {
+	inner := &chart.Chart{
+		Metadata: &chart.Metadata{Name: "inner"},
+		Templates: []*chart.Template{
+			{Name: "templates/_computed.yaml", Data: []byte("host: inner\nurl: http://{{ .Computed.host }}")},
+			{Name: "templates/cfg", Data: []byte(`{{ .Computed.host }} {{ .Computed.url }}`)},
+		},
+		Values: &chart.Config{Raw: ``},
+	}
+	middle := &chart.Chart{
+		Metadata:     &chart.Metadata{Name: "middle"},
+		Values:       &chart.Config{Raw: ``},
+		Dependencies: []*chart.Chart{inner},
+	}
+	outer := &chart.Chart{
+		Metadata: &chart.Metadata{Name: "outer"},
+		Templates: []*chart.Template{
+			{Name: "templates/_computed.yaml", Data: []byte(`host: {{ .Release.Name }}-db`)},
+			{Name: "templates/cfg", Data: []byte(`{{ .Computed.host }} {{ .Computed.url }}`)},
+		},
+		Values:       &chart.Config{Raw: ``},
+		Dependencies: []*chart.Chart{middle},
+	}
+
+	inject := chartutil.Values{
+		"Values":  &chart.Config{Raw: ""},
+		"Chart":   outer.Metadata,
+		"Release": chartutil.Values{"Name": "computed"},
+	}
+
+	out, err := New().Render(outer, inject)
+	if err != nil {
+		t.Fatalf("failed to render templates: %s", err)
+	}
+
+	expects := map[string]string{
+		"outer/templates/cfg":                            "computed-db ",
+		"outer/charts/middle/charts/inner/templates/cfg": "computed-db http://computed-db",
+	}
+	for file, expect := range expects {
+		if out[file] != expect {
+			t.Errorf("Expected %q in %s, got %q", expect, file, out[file])
+		}
+	}
+}
+
+func TestRenderComputedValuesError(t *testing.T) {
+	c := &chart.Chart{
+		Metadata: &chart.Metadata{Name: "broken"},
+		Templates: []*chart.Template{
+			{Name: "templates/_computed.yaml", Data: []byte(`- not a map`)},
+			{Name: "templates/cfg", Data: []byte(`{{ .Computed }}`)},
+		},
+		Values: &chart.Config{Raw: ``},
+	}
+
+	inject := chartutil.Values{
+		"Values": &chart.Config{Raw: ""},
+		"Chart":  c.Metadata,
+	}
+
+	if _, err := New().Render(c, inject); err == nil {
+		t.Fatal("expected an error for computed values that are not a map")
+	}
+}
+
+func TestRenderEnv(t *testing.T) {
+	os.Setenv("HELM_ENGINE_CLUSTER", "east")
+	os.Setenv("HELM_ENGINE_SECRET", "hunter2")
+	defer os.Unsetenv("HELM_ENGINE_CLUSTER")
+	defer os.Unsetenv("HELM_ENGINE_SECRET")
+
+	c := &chart.Chart{
+		Metadata: &chart.Metadata{Name: "envchart"},
+		Templates: []*chart.Template{
+			{Name: "templates/env", Data: []byte(`cluster={{ env "HELM_ENGINE_CLUSTER" }} secret={{ env "HELM_ENGINE_SECRET" }}`)},
+		},
+		Values: &chart.Config{Raw: ``},
+	}
+	vals := chartutil.Values{"Values": &chart.Config{Raw: ""}, "Chart": c.Metadata}
+
+	tests := []struct {
+		name      string
+		allowlist []string
+		strict    bool
+		expect    string
+		err       bool
+	}{
+		{"nothing allowed", nil, false, "cluster= secret=", false},
+		{"allowlisted", []string{"HELM_ENGINE_CLUSTER"}, false, "cluster=east secret=", false},
+		{"strict", []string{"HELM_ENGINE_CLUSTER"}, true, "", true},
+		{"strict and all allowlisted", []string{"HELM_ENGINE_CLUSTER", "HELM_ENGINE_SECRET"}, true, "cluster=east secret=hunter2", false},
+	}
+	for _, tt := range tests {
+		e := New()
+		e.EnvAllowlist = tt.allowlist
+		e.StrictEnv = tt.strict
+
+		out, err := e.Render(c, vals)
+		if tt.err {
+			if err == nil || !strings.Contains(err.Error(), `environment variable "HELM_ENGINE_SECRET" is not allowed`) {
+				t.Errorf("%s: expected an error for the secret variable, got %v", tt.name, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: failed to render: %s", tt.name, err)
+			continue
+		}
+		if got := out["envchart/templates/env"]; got != tt.expect {
+			t.Errorf("%s: expected %q, got %q", tt.name, tt.expect, got)
+		}
+	}
+}
+
 func TestAlterFuncMap(t *testing.T) {
 	c := &chart.Chart{
 		Metadata: &chart.Metadata{Name: "conrad"},
@@ -446,3 +677,323 @@ func TestAlterFuncMap(t *testing.T) {
 	}
 
 }
+
+func TestRenderIncludeCycle(t *testing.T) {
+	tests := []struct {
+		name   string
+		tpls   map[string]renderable
+		expect string
+	}{
+		{
+			name: "self",
+			tpls: map[string]renderable{
+				"self": {tpl: `{{include "self" .}}`, vals: chartutil.Values{}},
+			},
+			expect: `render error in "self": includes nest more than 10 deep: include cycle "self" -> "self"`,
+		},
+		{
+			name: "indirect",
+			tpls: map[string]renderable{
+				"main": {tpl: `{{include "_a" .}}`, vals: chartutil.Values{}},
+				"_a":   {tpl: `a{{include "_b" .}}`, vals: chartutil.Values{}},
+				"_b":   {tpl: `b{{include "_a" .}}`, vals: chartutil.Values{}},
+			},
+			expect: `render error in "main": includes nest more than 10 deep: include cycle "_a" -> "_b" -> "_a"`,
+		},
+		{
+			name: "template",
+			tpls: map[string]renderable{
+				"main": {tpl: `{{define "_t"}}t{{template "_t" .}}{{end}}{{template "_t" .}}`, vals: chartutil.Values{}},
+			},
+			expect: `render error in "main": includes nest more than 10 deep: include cycle "_t" -> "_t"`,
+		},
+		{
+			name: "tpl",
+			tpls: map[string]renderable{
+				"main": {tpl: `{{tpl "{{define \"_t\"}}{{template \"_t\"}}{{end}}{{template \"_t\"}}" .}}`, vals: chartutil.Values{}},
+			},
+			expect: `render error in "main": includes nest more than 10 deep: include cycle "_t" -> "_t"`,
+		},
+	}
+	for _, tt := range tests {
+		e := New()
+		e.MaxIncludeDepth = 10
+		_, err := e.render(tt.tpls, newGenerator(nil), nil)
+		if err == nil {
+			t.Errorf("%s: expected an error", tt.name)
+			continue
+		}
+		if err.Error() != tt.expect {
+			t.Errorf("%s: expected %q, got %q", tt.name, tt.expect, err)
+		}
+	}
+}
+
+func TestRenderRecursiveInclude(t *testing.T) {
+	// A template may include itself as long as the recursion ends.
+	e := New()
+	e.MaxIncludeDepth = 10
+	tpls := map[string]renderable{
+		"count": {tpl: `{{define "down"}}{{.}}{{if gt . 0}} {{include "down" (sub . 1)}}{{end}}{{end}}{{include "down" 5}}`, vals: chartutil.Values{}},
+	}
+	out, err := e.render(tpls, newGenerator(nil), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expect := "5 4 3 2 1 0"; out["count"] != expect {
+		t.Errorf("Expected %q, got %q", expect, out["count"])
+	}
+}
+
+func TestRenderTemplateAction(t *testing.T) {
+	// {{template}} actions still render as before once they are guarded.
+	e := New()
+	tpls := map[string]renderable{
+		"main": {tpl: `{{define "_t"}}[{{.}}]{{end}}{{template "_t" "a"}}{{template "_t"}}{{range list 1 2}}{{template "_t" .}}{{end}}`, vals: chartutil.Values{}},
+	}
+	out, err := e.render(tpls, newGenerator(nil), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expect := "[a][][1][2]"; out["main"] != expect {
+		t.Errorf("Expected %q, got %q", expect, out["main"])
+	}
+}
+
+func TestRenderTimeout(t *testing.T) {
+	e := New()
+	e.Timeout = 50 * time.Millisecond
+	// Far too many iterations to finish, with little memory.
+	tpls := map[string]renderable{
+		"loop": {tpl: `{{range until 100000}}{{range until 100000}}x{{end}}{{end}}`, vals: chartutil.Values{}},
+	}
+
+	start := time.Now()
+	_, err := e.render(tpls, newGenerator(nil), nil)
+	if err == nil {
+		t.Fatal("expected an error")
+	}
+	if expect := `render error in "loop": rendering did not finish within 50ms`; err.Error() != expect {
+		t.Errorf("Expected %q, got %q", expect, err)
+	}
+	if took := time.Since(start); took > 5*time.Second {
+		t.Errorf("Expected rendering to stop soon after the timeout, took %s", took)
+	}
+}
+
+func TestRenderTimeoutWithoutOutput(t *testing.T) {
+	e := New()
+	e.Timeout = 50 * time.Millisecond
+	// The loops neither write nor include anything, so only their check of
+	// the guard in every iteration stops them.
+	tpls := map[string]renderable{
+		"loop": {tpl: `{{range until 1000}}{{range until 10000}}{{end}}{{end}}`, vals: chartutil.Values{}},
+	}
+
+	start := time.Now()
+	_, err := e.render(tpls, newGenerator(nil), nil)
+	if err == nil {
+		t.Fatal("expected an error")
+	}
+	if expect := `render error in "loop": rendering did not finish within 50ms`; err.Error() != expect {
+		t.Errorf("Expected %q, got %q", expect, err)
+	}
+	if took := time.Since(start); took > 5*time.Second {
+		t.Errorf("Expected rendering to stop soon after the timeout, took %s", took)
+	}
+}
+
+func TestRenderGenerated(t *testing.T) {
+	c := &chart.Chart{
+		Metadata: &chart.Metadata{Name: "gen"},
+		Templates: []*chart.Template{
+			{Name: "templates/secret", Data: []byte(`{{ randAlphaNumOnce "password" 16 }} {{ randAlphaNumOnce "password" 8 }}`)},
+			{Name: "templates/token", Data: []byte(`{{ randAlphaNumOnce "token" 8 }}`)},
+		},
+		Values: &chart.Config{Raw: ``},
+	}
+	vals := chartutil.Values{"Values": &chart.Config{Raw: ""}, "Chart": c.Metadata}
+
+	generated := map[string]string{"obsolete": "gone"}
+	out, err := New().RenderGenerated(c, vals, generated)
+	if err != nil {
+		t.Fatal(err)
+	}
+	password := generated["password"]
+	if len(password) != 16 || len(generated["token"]) != 8 || len(generated) != 2 {
+		t.Fatalf("Expected a password and a token to be generated, got %v", generated)
+	}
+	// The same key returns the same value within a render.
+	if expect := password + " " + password; out["gen/templates/secret"] != expect {
+		t.Errorf("Expected %q, got %q", expect, out["gen/templates/secret"])
+	}
+
+	// Later renders reuse the values.
+	generated["token"] = "kept"
+	out, err = New().RenderGenerated(c, vals, generated)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if out["gen/templates/token"] != "kept" || generated["password"] != password {
+		t.Errorf("Expected the generated values to be reused, got %v and %v", out, generated)
+	}
+
+	// Without previous values, Render generates new ones every time.
+	first, err := New().Render(c, vals)
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, err := New().Render(c, vals)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if first["gen/templates/token"] == second["gen/templates/token"] {
+		t.Errorf("Expected Render to generate a new token, got %q twice", first["gen/templates/token"])
+	}
+}
+
+func TestRandAlphaNumOnceErrors(t *testing.T) {
+	tests := []struct {
+		tpl    string
+		expect string
+	}{
+		{`{{ randAlphaNumOnce "" 8 }}`, "key must not be empty"},
+		{`{{ randAlphaNumOnce "password" 0 }}`, `randAlphaNumOnce "password": length must be positive`},
+	}
+	for _, tt := range tests {
+		c := &chart.Chart{
+			Metadata:  &chart.Metadata{Name: "gen"},
+			Templates: []*chart.Template{{Name: "templates/secret", Data: []byte(tt.tpl)}},
+			Values:    &chart.Config{Raw: ``},
+		}
+		vals := chartutil.Values{"Values": &chart.Config{Raw: ""}, "Chart": c.Metadata}
+		_, err := New().RenderGenerated(c, vals, map[string]string{})
+		if err == nil || !strings.Contains(err.Error(), tt.expect) {
+			t.Errorf("%s: expected an error containing %q, got %v", tt.tpl, tt.expect, err)
+		}
+	}
+}
+
+func TestRandAlphaNum(t *testing.T) {
+	v, err := randAlphaNum(100)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(v) != 100 {
+		t.Errorf("Expected 100 characters, got %d", len(v))
+	}
+	for _, r := range v {
+		if !strings.ContainsRune(alphaNum, r) {
+			t.Errorf("Expected only alphanumeric characters, got %q", v)
+			break
+		}
+	}
+}
+
+func templatedValuesChart(values string) *chart.Chart {
+	return &chart.Chart{
+		Metadata: &chart.Metadata{Name: "web", TemplateValues: true},
+		Templates: []*chart.Template{
+			{Name: "templates/host", Data: []byte(`{{ .Values.fullHost }} {{ index .Values.hosts 0 }}`)},
+		},
+		Values: &chart.Config{Raw: values},
+		Dependencies: []*chart.Chart{{
+			Metadata:  &chart.Metadata{Name: "proxy"},
+			Templates: []*chart.Template{{Name: "templates/url", Data: []byte(`{{ .Values.url }}`)}},
+		}},
+	}
+}
+
+func TestRenderTemplatedValues(t *testing.T) {
+	// The values reference each other in an order unrelated to their keys.
+	c := templatedValuesChart(`
+fullHost: "{{ .Values.subdomain }}.{{ .Values.domain }}"
+subdomain: "{{ .Release.Name }}"
+domain: example.com
+hosts:
+- "{{ .Values.fullHost }}"
+proxy:
+  url: "https://{{ .Values.fullHost }}/"
+`)
+	vals, err := chartutil.CoalesceValues(c, &chart.Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	inject := chartutil.Values{
+		"Values":  vals,
+		"Chart":   c.Metadata,
+		"Release": chartutil.Values{"Name": "shop"},
+	}
+
+	out, err := New().Render(c, inject)
+	if err != nil {
+		t.Fatalf("failed to render templates: %s", err)
+	}
+	expect := map[string]string{
+		"web/templates/host":             "shop.example.com shop.example.com",
+		"web/charts/proxy/templates/url": "https://shop.example.com/",
+	}
+	for file, content := range expect {
+		if out[file] != content {
+			t.Errorf("Expected %q in %s, got %q", content, file, out[file])
+		}
+	}
+	if vals["fullHost"] != "{{ .Values.subdomain }}.{{ .Values.domain }}" {
+		t.Errorf("Expected the values passed in to be left alone, got %q", vals["fullHost"])
+	}
+
+	// Without the chart asking for it, values are not rendered.
+	c.Metadata.TemplateValues = false
+	out, err = New().Render(c, inject)
+	if err != nil {
+		t.Fatalf("failed to render templates: %s", err)
+	}
+	if expect := "{{ .Values.subdomain }}.{{ .Values.domain }} {{ .Values.fullHost }}"; out["web/templates/host"] != expect {
+		t.Errorf("Expected %q, got %q", expect, out["web/templates/host"])
+	}
+}
+
+func TestRenderTemplatedValuesCycle(t *testing.T) {
+	for values, expect := range map[string]string{
+		"a: '{{ .Values.b }}'\nb: '{{ .Values.a }}'\nfullHost: x\nhosts: [x]": "values reference themselves: a, b",
+		"fullHost: 'www.{{ .Values.fullHost }}'\nhosts: [x]":                  "values reference themselves: fullHost",
+		"fullHost: x\nhosts: ['{{ index .Values.hosts 0 }}']":                 "values reference themselves: hosts[0]",
+	} {
+		c := templatedValuesChart(values)
+		vals, err := chartutil.CoalesceValues(c, &chart.Config{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		_, err = New().Render(c, chartutil.Values{"Values": vals, "Chart": c.Metadata})
+		if err == nil || err.Error() != expect {
+			t.Errorf("Expected %q, got %v", expect, err)
+		}
+	}
+}
+
+func TestRenderTpl(t *testing.T) {
+	e := New()
+	e.MaxIncludeDepth = 10
+	vals := chartutil.Values{"Values": map[string]interface{}{
+		"name":     "world",
+		"greeting": `{{ include "prefix" . }} {{ .Values.name }}`,
+		"loop":     `{{ tpl .Values.loop . }}`,
+	}}
+	tpls := map[string]renderable{
+		"_helpers": {tpl: `{{ define "prefix" }}hello{{ end }}`, vals: vals},
+		"greeting": {tpl: `{{ tpl .Values.greeting . }}`, vals: vals},
+	}
+	out, err := e.render(tpls, newGenerator(nil), nil)
+	if err != nil {
+		t.Fatalf("failed to render templates: %s", err)
+	}
+	if out["greeting"] != "hello world" {
+		t.Errorf("Expected %q, got %q", "hello world", out["greeting"])
+	}
+
+	tpls["loop"] = renderable{tpl: `{{ tpl .Values.loop . }}`, vals: vals}
+	_, err = e.render(tpls, newGenerator(nil), nil)
+	if err == nil || !strings.Contains(err.Error(), `includes nest more than 10 deep: include cycle "tpl" -> "tpl"`) {
+		t.Errorf("Expected the recursion to be stopped, got %v", err)
+	}
+}
@@ -0,0 +1,255 @@
+/*
+Copyright 2017 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package engine
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"sync/atomic"
+	"text/template"
+	"text/template/parse"
+	"time"
+
+	ctx "golang.org/x/net/context"
+)
+
+// DefaultMaxIncludeDepth is how deeply "include" calls and {{template}}
+// actions may nest when Engine.MaxIncludeDepth is not set.
+const DefaultMaxIncludeDepth = 1000
+
+// guardCheckFunc is the template function that guardTemplates calls in every
+// iteration of a {{range}}.
+const guardCheckFunc = "_renderGuard"
+
+// renderGuard stops a render that runs for longer than its timeout, or whose
+// includes nest too deeply. It is created for a single render.
+type renderGuard struct {
+	ctx      ctx.Context
+	timeout  time.Duration
+	maxDepth int
+	// includes are the names of the templates being included, outermost
+	// first.
+	includes []string
+	// err is the reason the render was stopped, if it was.
+	err error
+	// stage is the prefix of the errors of the template being executed, e.g.
+	// `render error in "mychart/templates/a.yaml"`. It is stored by the
+	// render goroutine and loaded by run; see setStage.
+	stage atomic.Value
+}
+
+// newRenderGuard returns a guard for a render that may take timeout, or any
+// time if timeout is zero. The returned function releases the guard's timer.
+func newRenderGuard(timeout time.Duration, maxDepth int) (*renderGuard, func()) {
+	if maxDepth <= 0 {
+		maxDepth = DefaultMaxIncludeDepth
+	}
+	g := &renderGuard{timeout: timeout, maxDepth: maxDepth}
+	if timeout <= 0 {
+		g.ctx = ctx.Background()
+		return g, func() {}
+	}
+	var cancel func()
+	g.ctx, cancel = ctx.WithTimeout(ctx.Background(), timeout)
+	return g, cancel
+}
+
+// check returns an error if the render must stop.
+func (g *renderGuard) check() error {
+	if g.err == nil && g.ctx.Err() != nil {
+		g.err = g.timedOut()
+	}
+	return g.err
+}
+
+// setStage records that errors of the templates executed from now on are
+// prefixed with stage.
+func (g *renderGuard) setStage(stage string) {
+	g.stage.Store(stage)
+}
+
+func (g *renderGuard) timedOut() error {
+	return fmt.Errorf("rendering did not finish within %s", g.timeout)
+}
+
+// run returns the result of render, or an error once the timeout passes.
+//
+// The guard stops a render when it writes output, includes a template or
+// starts an iteration of a {{range}}; see guardTemplates. A single call of a
+// template function can still take long, e.g. "until" with a huge count. So
+// render runs on its own goroutine, and run stops waiting for it at the
+// deadline. A render abandoned that way stops at its next check of the
+// guard, and its result is dropped.
+func (g *renderGuard) run(render func() (map[string]string, error)) (map[string]string, error) {
+	if g.timeout <= 0 {
+		return render()
+	}
+	type result struct {
+		files map[string]string
+		err   error
+	}
+	done := make(chan result, 1)
+	go func() {
+		files, err := render()
+		done <- result{files, err}
+	}()
+	select {
+	case r := <-done:
+		return r.files, r.err
+	case <-g.ctx.Done():
+		if stage, _ := g.stage.Load().(string); stage != "" {
+			return map[string]string{}, fmt.Errorf("%s: %s", stage, g.timedOut())
+		}
+		return map[string]string{}, g.timedOut()
+	}
+}
+
+// enter records that the named template is being included. It returns an
+// error, naming the cycle of includes, if that nests them too deeply.
+func (g *renderGuard) enter(name string) error {
+	if err := g.check(); err != nil {
+		return err
+	}
+	if len(g.includes) >= g.maxDepth {
+		g.err = fmt.Errorf("includes nest more than %d deep: %s", g.maxDepth, includeCycle(g.includes, name))
+		return g.err
+	}
+	g.includes = append(g.includes, name)
+	return nil
+}
+
+// leave records that the innermost include has returned.
+func (g *renderGuard) leave() {
+	g.includes = g.includes[:len(g.includes)-1]
+}
+
+// cause returns the reason the render was stopped, or err if it was not.
+//
+// Once stopped, a template fails with the reason wrapped once for every
+// include it was nested in; the reason alone is what matters.
+func (g *renderGuard) cause(err error) error {
+	if g.err != nil {
+		return g.err
+	}
+	return err
+}
+
+// includeCycle describes the innermost cycle of includes that ends in name,
+// e.g. `include cycle "a" -> "b" -> "a"`.
+func includeCycle(includes []string, name string) string {
+	for i := len(includes) - 1; i >= 0; i-- {
+		if includes[i] != name {
+			continue
+		}
+		cycle := make([]string, 0, len(includes)-i+1)
+		for _, n := range append(includes[i:len(includes):len(includes)], name) {
+			cycle = append(cycle, fmt.Sprintf("%q", n))
+		}
+		return "include cycle " + strings.Join(cycle, " -> ")
+	}
+	return fmt.Sprintf("last included %q", name)
+}
+
+// guardedWriter writes to w until the render guarded by g must stop. Templates
+// write as they execute, so this also stops loops that never call "include".
+type guardedWriter struct {
+	w io.Writer
+	g *renderGuard
+}
+
+func (w guardedWriter) Write(p []byte) (int, error) {
+	if err := w.g.check(); err != nil {
+		return 0, err
+	}
+	return w.w.Write(p)
+}
+
+// guardTemplates lets the render guard see the templates of t nest and loop.
+// Each {{template}} action is replaced with a call of "include", which enters
+// the guard, and each {{range}} checks the guard at the start of every
+// iteration. Templates already guarded are left as they are.
+func guardTemplates(t *template.Template) {
+	for _, tt := range t.Templates() {
+		if tt.Tree != nil {
+			guardList(tt.Tree, tt.Tree.Root)
+		}
+	}
+}
+
+func guardList(tree *parse.Tree, l *parse.ListNode) {
+	if l == nil {
+		return
+	}
+	for i, n := range l.Nodes {
+		switch n := n.(type) {
+		case *parse.TemplateNode:
+			l.Nodes[i] = includeAction(tree, n)
+		case *parse.ListNode:
+			guardList(tree, n)
+		case *parse.IfNode:
+			guardList(tree, n.List)
+			guardList(tree, n.ElseList)
+		case *parse.WithNode:
+			guardList(tree, n.List)
+			guardList(tree, n.ElseList)
+		case *parse.RangeNode:
+			guardList(tree, n.List)
+			guardList(tree, n.ElseList)
+			if n.List != nil && !isGuardCheck(n.List) {
+				check := callAction(tree, n.Position(), n.Line, guardCheckFunc)
+				n.List.Nodes = append([]parse.Node{check}, n.List.Nodes...)
+			}
+		}
+	}
+}
+
+// includeAction returns the action {{include "name" pipeline}} for the
+// action {{template "name" pipeline}}.
+func includeAction(tree *parse.Tree, n *parse.TemplateNode) *parse.ActionNode {
+	var data parse.Node = &parse.NilNode{NodeType: parse.NodeNil, Pos: n.Position()}
+	if n.Pipe != nil {
+		data = n.Pipe
+	}
+	name := &parse.StringNode{NodeType: parse.NodeString, Pos: n.Position(), Quoted: fmt.Sprintf("%q", n.Name), Text: n.Name}
+	return callAction(tree, n.Position(), n.Line, "include", name, data)
+}
+
+// callAction returns the action {{fn args...}}.
+func callAction(tree *parse.Tree, pos parse.Pos, line int, fn string, args ...parse.Node) *parse.ActionNode {
+	cmd := &parse.CommandNode{NodeType: parse.NodeCommand, Pos: pos}
+	cmd.Args = append([]parse.Node{parse.NewIdentifier(fn).SetTree(tree).SetPos(pos)}, args...)
+	return &parse.ActionNode{
+		NodeType: parse.NodeAction,
+		Pos:      pos,
+		Line:     line,
+		Pipe:     &parse.PipeNode{NodeType: parse.NodePipe, Pos: pos, Line: line, Cmds: []*parse.CommandNode{cmd}},
+	}
+}
+
+// isGuardCheck reports whether l starts with the check of guardList.
+func isGuardCheck(l *parse.ListNode) bool {
+	if len(l.Nodes) == 0 {
+		return false
+	}
+	a, ok := l.Nodes[0].(*parse.ActionNode)
+	if !ok || len(a.Pipe.Cmds) != 1 || len(a.Pipe.Cmds[0].Args) != 1 {
+		return false
+	}
+	id, ok := a.Pipe.Cmds[0].Args[0].(*parse.IdentifierNode)
+	return ok && id.Ident == guardCheckFunc
+}
@@ -0,0 +1,183 @@
+/*
+Copyright 2017 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package engine
+
+import (
+	"bytes"
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+	"text/template"
+
+	"k8s.io/helm/pkg/chartutil"
+	"k8s.io/helm/pkg/proto/hapi/chart"
+)
+
+// templatedValues returns values, the render values of chrt, if chrt asks for
+// its values to be rendered, or nil otherwise.
+func templatedValues(chrt *chart.Chart, values chartutil.Values) chartutil.Values {
+	if chrt.Metadata == nil || !chrt.Metadata.TemplateValues {
+		return nil
+	}
+	return values
+}
+
+// executeString executes text as a template of the set of t, with data. The
+// template is added to the set under name.
+func executeString(t *template.Template, guard *renderGuard, name, text string, data interface{}) (string, error) {
+	tt, err := t.New(name).Parse(text)
+	if err != nil {
+		return "", err
+	}
+	guardTemplates(tt)
+	var buf bytes.Buffer
+	if err := tt.Execute(guardedWriter{&buf, guard}, data); err != nil {
+		return "", err
+	}
+	return strings.Replace(buf.String(), "<no value>", "", -1), nil
+}
+
+// resolveValues renders the strings of the values of top, the render values
+// of the top-level chart, that contain template expressions. Each renderable
+// is then given its chart's part of the rendered values, in a copy of its own
+// render values.
+//
+// The strings are rendered with the other render values of the top-level
+// chart, so that ".Values" are the values of the release as a whole. The
+// rendered values do not depend on the order of the keys: every string is
+// rendered against the values of the previous pass, until a pass changes
+// nothing. A value that references itself, directly or through others, is an
+// error.
+func (e *Engine) resolveValues(t *template.Template, tpls map[string]renderable, guard *renderGuard, top chartutil.Values) error {
+	vals, err := top.Table("Values")
+	if err != nil {
+		vals = chartutil.Values{}
+	}
+	cur := chartutil.DeepCopy(map[string]interface{}(vals)).(map[string]interface{})
+	n := len(templatedStrings(cur, ""))
+
+	data := chartutil.Values{}
+	for k, v := range top {
+		data[k] = v
+	}
+	for pass := 0; ; pass++ {
+		prev := chartutil.DeepCopy(cur).(map[string]interface{})
+		data["Values"] = chartutil.Values(prev)
+		changed := false
+		for _, ts := range templatedStrings(cur, "") {
+			guard.setStage(fmt.Sprintf("render error in value %q", ts.key))
+			out, err := executeString(t, guard, "values:"+ts.key, ts.value, data)
+			if err != nil {
+				return fmt.Errorf("render error in value %q: %s", ts.key, guard.cause(err))
+			}
+			if out != ts.value {
+				ts.set(out)
+				changed = true
+			}
+		}
+		left := templatedStrings(cur, "")
+		if !changed && len(left) > 0 || changed && pass >= n {
+			keys := make([]string, 0, len(left))
+			for _, ts := range left {
+				keys = append(keys, ts.key)
+			}
+			sort.Strings(keys)
+			return fmt.Errorf("values reference themselves: %s", strings.Join(keys, ", "))
+		}
+		if !changed {
+			break
+		}
+	}
+
+	scoped := map[string]chartutil.Values{}
+	for name, r := range tpls {
+		chartPath := path.Dir(r.basePath)
+		v, ok := scoped[chartPath]
+		if !ok {
+			v = chartutil.Values{}
+			for k, val := range r.vals {
+				v[k] = val
+			}
+			v["Values"] = chartValues(cur, chartPath)
+			scoped[chartPath] = v
+		}
+		r.vals = v
+		tpls[name] = r
+	}
+	return nil
+}
+
+// chartValues returns the part of the values of the top-level chart that the
+// chart at chartPath gets, as recAllTpls finds it.
+func chartValues(vals map[string]interface{}, chartPath string) chartutil.Values {
+	cur := chartutil.Values(vals)
+	for _, name := range strings.Split(chartPath, "/charts/")[1:] {
+		next, err := cur.Table(name)
+		if err != nil {
+			return chartutil.Values{}
+		}
+		cur = next
+	}
+	return cur
+}
+
+// templatedString is a string of the values that contains a template
+// expression.
+type templatedString struct {
+	// key is the path of the string in the values, such as "hosts[0].name".
+	key   string
+	value string
+	// set replaces the string in the values.
+	set func(string)
+}
+
+// templatedStrings returns the strings of v, nested at key, that contain
+// template expressions.
+func templatedStrings(v interface{}, key string) []templatedString {
+	var found []templatedString
+	switch v := v.(type) {
+	case map[string]interface{}:
+		for k, item := range v {
+			k, itemKey := k, k
+			if key != "" {
+				itemKey = key + "." + k
+			}
+			if s, ok := item.(string); ok {
+				if strings.Contains(s, "{{") {
+					found = append(found, templatedString{itemKey, s, func(s string) { v[k] = s }})
+				}
+				continue
+			}
+			found = append(found, templatedStrings(item, itemKey)...)
+		}
+	case chartutil.Values:
+		return templatedStrings(map[string]interface{}(v), key)
+	case []interface{}:
+		for i, item := range v {
+			i, itemKey := i, fmt.Sprintf("%s[%d]", key, i)
+			if s, ok := item.(string); ok {
+				if strings.Contains(s, "{{") {
+					found = append(found, templatedString{itemKey, s, func(s string) { v[i] = s }})
+				}
+				continue
+			}
+			found = append(found, templatedStrings(item, itemKey)...)
+		}
+	}
+	return found
+}
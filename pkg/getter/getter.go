@@ -29,6 +29,14 @@ type Getter interface {
 	Get(url string) (*bytes.Buffer, error)
 }
 
+// LimitedGetter is a Getter that can stop reading content after a number of
+// bytes, so that callers can bound the memory used for untrusted URLs.
+type LimitedGetter interface {
+	Getter
+	// GetLimited is like Get, but keeps at most max bytes of the content.
+	GetLimited(url string, max int64) (*bytes.Buffer, error)
+}
+
 // Constructor is the function for every getter which creates a specific instance
 // according to the configuration
 type Constructor func(URL, CertFile, KeyFile, CAFile string) (Getter, error)
@@ -32,6 +32,17 @@ type httpGetter struct {
 
 //Get performs a Get from repo.Getter and returns the body.
 func (g *httpGetter) Get(href string) (*bytes.Buffer, error) {
+	return g.get(href, -1)
+}
+
+// GetLimited performs a Get and returns at most max bytes of the body. The
+// rest of the body is not read.
+func (g *httpGetter) GetLimited(href string, max int64) (*bytes.Buffer, error) {
+	return g.get(href, max)
+}
+
+// get returns the body of href, or its first max bytes if max is not negative.
+func (g *httpGetter) get(href string, max int64) (*bytes.Buffer, error) {
 	buf := bytes.NewBuffer(nil)
 
 	resp, err := g.client.Get(href)
@@ -42,7 +53,11 @@ func (g *httpGetter) Get(href string) (*bytes.Buffer, error) {
 		return buf, fmt.Errorf("Failed to fetch %s : %s", href, resp.Status)
 	}
 
-	_, err = io.Copy(buf, resp.Body)
+	var body io.Reader = resp.Body
+	if max >= 0 {
+		body = io.LimitReader(resp.Body, max)
+	}
+	_, err = io.Copy(buf, body)
 	resp.Body.Close()
 	return buf, err
 }
@@ -16,7 +16,9 @@ limitations under the License.
 package getter
 
 import (
+	"bytes"
 	"net/http"
+	"net/http/httptest"
 	"path/filepath"
 	"testing"
 )
@@ -46,3 +48,35 @@ func TestHTTPGetter(t *testing.T) {
 		t.Fatal("Expected newHTTPGetter to produce an httpGetter")
 	}
 }
+
+func TestHTTPGetterLimited(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write(bytes.Repeat([]byte("a"), 1024))
+	}))
+	defer srv.Close()
+
+	g, err := newHTTPGetter(srv.URL, "", "", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	lg, ok := g.(LimitedGetter)
+	if !ok {
+		t.Fatal("Expected the HTTP getter to be a LimitedGetter")
+	}
+
+	buf, err := lg.GetLimited(srv.URL, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if buf.Len() != 10 {
+		t.Errorf("Expected 10 bytes, got %d", buf.Len())
+	}
+
+	buf, err = lg.GetLimited(srv.URL, 4096)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if buf.Len() != 1024 {
+		t.Errorf("Expected the whole body of 1024 bytes, got %d", buf.Len())
+	}
+}
@@ -62,12 +62,27 @@ type pluginGetter struct {
 
 // Get runs downloader plugin command
 func (p *pluginGetter) Get(href string) (*bytes.Buffer, error) {
+	return p.get(href, -1)
+}
+
+// GetLimited runs the downloader plugin command and keeps at most max bytes
+// of its output. The rest of the output is discarded.
+func (p *pluginGetter) GetLimited(href string, max int64) (*bytes.Buffer, error) {
+	return p.get(href, max)
+}
+
+// get runs the downloader plugin command. If max is not negative, only the
+// first max bytes of its output are kept.
+func (p *pluginGetter) get(href string, max int64) (*bytes.Buffer, error) {
 	argv := []string{p.certFile, p.keyFile, p.cAFile, href}
 	prog := exec.Command(filepath.Join(p.base, p.command), argv...)
 	plugin.SetupPluginEnv(p.settings, p.name, p.base)
 	prog.Env = os.Environ()
 	buf := bytes.NewBuffer(nil)
 	prog.Stdout = buf
+	if max >= 0 {
+		prog.Stdout = &limitedWriter{buf: buf, max: max}
+	}
 	prog.Stderr = os.Stderr
 	if err := prog.Run(); err != nil {
 		if eerr, ok := err.(*exec.ExitError); ok {
@@ -79,6 +94,24 @@ func (p *pluginGetter) Get(href string) (*bytes.Buffer, error) {
 	return buf, nil
 }
 
+// limitedWriter writes to buf until it holds max bytes and drops the rest.
+// Writes never fail, so that the plugin is not killed by a broken pipe.
+type limitedWriter struct {
+	buf *bytes.Buffer
+	max int64
+}
+
+func (w *limitedWriter) Write(p []byte) (int, error) {
+	if room := w.max - int64(w.buf.Len()); room > 0 {
+		if int64(len(p)) > room {
+			w.buf.Write(p[:room])
+		} else {
+			w.buf.Write(p)
+		}
+	}
+	return len(p), nil
+}
+
 // newPluginGetter constructs a valid plugin getter
 func newPluginGetter(command string, settings environment.EnvSettings, name, base string) Constructor {
 	return func(URL, CertFile, KeyFile, CAFile string) (Getter, error) {
@@ -90,3 +90,25 @@ func TestPluginGetter(t *testing.T) {
 		t.Errorf("Expected %q, got %q", expect, got)
 	}
 }
+
+func TestPluginGetterLimited(t *testing.T) {
+	oldhh := os.Getenv("HELM_HOME")
+	defer os.Setenv("HELM_HOME", oldhh)
+	os.Setenv("HELM_HOME", "")
+
+	env := hh(false)
+	pg := newPluginGetter("echo", env, "test", ".")
+	g, err := pg("test://foo/bar", "", "", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// echo prints the empty certificate arguments before the URL.
+	data, err := g.(LimitedGetter).GetLimited("test://foo/bar", 7)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := strings.TrimSpace(data.String()); got != "test" {
+		t.Errorf("Expected %q, got %q", "test", got)
+	}
+}
@@ -197,6 +197,331 @@ func (h *Client) GetVersion(opts ...VersionOption) (*rls.GetVersionResponse, err
 	return h.version(ctx, req)
 }
 
+// MigrationStatus returns the progress of migrating Tiller's release records
+// to another storage driver.
+func (h *Client) MigrationStatus(opts ...MigrationStatusOption) (*rls.MigrationStatusResponse, error) {
+	for _, opt := range opts {
+		opt(&h.opts)
+	}
+	req := &rls.MigrationStatusRequest{}
+	ctx := NewContext()
+
+	if h.opts.before != nil {
+		if err := h.opts.before(ctx, req); err != nil {
+			return nil, err
+		}
+	}
+	return h.migrationStatus(ctx, req)
+}
+
+// GetHealth returns the reachability of Tiller's dependencies
+func (h *Client) GetHealth(opts ...HealthOption) (*rls.GetHealthResponse, error) {
+	for _, opt := range opts {
+		opt(&h.opts)
+	}
+	req := &rls.GetHealthRequest{}
+	ctx := NewContext()
+
+	if h.opts.before != nil {
+		if err := h.opts.before(ctx, req); err != nil {
+			return nil, err
+		}
+	}
+	return h.health(ctx, req)
+}
+
+// ForceUnlock clears the lock on a release. confirm must be the release name.
+func (h *Client) ForceUnlock(rlsName, confirm string, opts ...ForceUnlockOption) (*rls.ForceUnlockResponse, error) {
+	for _, opt := range opts {
+		opt(&h.opts)
+	}
+	req := &rls.ForceUnlockRequest{Name: rlsName, Confirm: confirm}
+	ctx := NewContext()
+
+	if h.opts.before != nil {
+		if err := h.opts.before(ctx, req); err != nil {
+			return nil, err
+		}
+	}
+	return h.forceUnlock(ctx, req)
+}
+
+// BatchInstall installs several releases as a unit. If one of them fails, the
+// ones installed before it are uninstalled; see rls.BatchInstallRequest.
+func (h *Client) BatchInstall(releases []*rls.InstallReleaseRequest, opts ...BatchInstallOption) (*rls.BatchInstallResponse, error) {
+	for _, opt := range opts {
+		opt(&h.opts)
+	}
+	req := &rls.BatchInstallRequest{Releases: releases}
+	ctx := NewContext()
+
+	if h.opts.before != nil {
+		if err := h.opts.before(ctx, req); err != nil {
+			return nil, err
+		}
+	}
+	for _, r := range req.Releases {
+		if r.Chart == nil {
+			continue
+		}
+		if err := chartutil.ProcessRequirementsEnabled(r.Chart, r.Values); err != nil {
+			return nil, err
+		}
+		if err := chartutil.ProcessRequirementsImportValues(r.Chart, r.Values); err != nil {
+			return nil, err
+		}
+	}
+	return h.batchInstall(ctx, req)
+}
+
+// RestoreRelease re-installs a deleted release from its retained record.
+func (h *Client) RestoreRelease(rlsName string, opts ...RestoreOption) (*rls.RestoreReleaseResponse, error) {
+	for _, opt := range opts {
+		opt(&h.opts)
+	}
+	req := &h.opts.restoreReq
+	req.Name = rlsName
+	ctx := NewContext()
+
+	if h.opts.before != nil {
+		if err := h.opts.before(ctx, req); err != nil {
+			return nil, err
+		}
+	}
+	return h.restore(ctx, req)
+}
+
+// AnnotateRelease sets and removes annotations on a revision of a release,
+// without creating a new revision.
+func (h *Client) AnnotateRelease(rlsName string, annotations map[string]string, remove []string, opts ...AnnotateOption) (*rls.AnnotateReleaseResponse, error) {
+	for _, opt := range opts {
+		opt(&h.opts)
+	}
+	req := &h.opts.annotateReq
+	req.Name = rlsName
+	req.Annotations = annotations
+	req.Remove = remove
+	ctx := NewContext()
+
+	if h.opts.before != nil {
+		if err := h.opts.before(ctx, req); err != nil {
+			return nil, err
+		}
+	}
+	return h.annotate(ctx, req)
+}
+
+// RestartRelease restarts the pods of the Deployments, StatefulSets and
+// DaemonSets of a deployed release, without creating a new revision.
+func (h *Client) RestartRelease(rlsName string, opts ...RestartOption) (*rls.RestartReleaseResponse, error) {
+	for _, opt := range opts {
+		opt(&h.opts)
+	}
+	req := &h.opts.restartReq
+	req.Name = rlsName
+	ctx := NewContext()
+
+	if h.opts.before != nil {
+		if err := h.opts.before(ctx, req); err != nil {
+			return nil, err
+		}
+	}
+	return h.restart(ctx, req)
+}
+
+// ApproveRelease lets the upgrade of a release that awaits approval go ahead.
+func (h *Client) ApproveRelease(rlsName string, opts ...ApproveOption) (*rls.ApproveReleaseResponse, error) {
+	for _, opt := range opts {
+		opt(&h.opts)
+	}
+	req := &rls.ApproveReleaseRequest{Name: rlsName}
+	ctx := NewContext()
+
+	if h.opts.before != nil {
+		if err := h.opts.before(ctx, req); err != nil {
+			return nil, err
+		}
+	}
+	return h.approve(ctx, req)
+}
+
+// RejectRelease aborts the upgrade of a release that awaits approval. reason
+// is recorded with the rejected revision.
+func (h *Client) RejectRelease(rlsName, reason string, opts ...RejectOption) (*rls.RejectReleaseResponse, error) {
+	for _, opt := range opts {
+		opt(&h.opts)
+	}
+	req := &rls.RejectReleaseRequest{Name: rlsName, Reason: reason}
+	ctx := NewContext()
+
+	if h.opts.before != nil {
+		if err := h.opts.before(ctx, req); err != nil {
+			return nil, err
+		}
+	}
+	return h.reject(ctx, req)
+}
+
+// ReleaseDrift compares the resources of a release, or those an upgrade of it
+// to the chart of DriftChart would apply, to their live counterparts in the
+// cluster. It changes nothing.
+func (h *Client) ReleaseDrift(rlsName string, opts ...DriftOption) (*rls.GetReleaseDriftResponse, error) {
+	for _, opt := range opts {
+		opt(&h.opts)
+	}
+	req := &h.opts.driftReq
+	req.Name = rlsName
+	ctx := NewContext()
+
+	if h.opts.before != nil {
+		if err := h.opts.before(ctx, req); err != nil {
+			return nil, err
+		}
+	}
+	if req.Chart != nil {
+		if err := chartutil.ProcessRequirementsEnabled(req.Chart, req.Values); err != nil {
+			return nil, err
+		}
+		if err := chartutil.ProcessRequirementsImportValues(req.Chart, req.Values); err != nil {
+			return nil, err
+		}
+	}
+	return h.drift(ctx, req)
+}
+
+// ReleaseHooks returns the hooks that ran or were skipped for a revision of
+// a release, with their outcomes.
+func (h *Client) ReleaseHooks(rlsName string, opts ...HooksOption) (*rls.GetHooksResponse, error) {
+	for _, opt := range opts {
+		opt(&h.opts)
+	}
+	req := &h.opts.hooksReq
+	req.Name = rlsName
+	ctx := NewContext()
+
+	if h.opts.before != nil {
+		if err := h.opts.before(ctx, req); err != nil {
+			return nil, err
+		}
+	}
+	return h.hooks(ctx, req)
+}
+
+// ReleaseLabels returns the labels and annotations that all resources of a
+// revision of a release carry.
+func (h *Client) ReleaseLabels(rlsName string, opts ...LabelsOption) (*rls.GetReleaseLabelsResponse, error) {
+	for _, opt := range opts {
+		opt(&h.opts)
+	}
+	req := &h.opts.labelsReq
+	req.Name = rlsName
+	ctx := NewContext()
+
+	if h.opts.before != nil {
+		if err := h.opts.before(ctx, req); err != nil {
+			return nil, err
+		}
+	}
+	return h.labels(ctx, req)
+}
+
+// InvalidateDiscoveryCache makes Tiller discover the API of a cluster again,
+// so that the kinds added since, e.g. by CustomResourceDefinitions created
+// outside of Helm, can be used.
+func (h *Client) InvalidateDiscoveryCache(opts ...DiscoveryCacheOption) (*rls.InvalidateDiscoveryCacheResponse, error) {
+	for _, opt := range opts {
+		opt(&h.opts)
+	}
+	req := &h.opts.discoveryReq
+	ctx := NewContext()
+
+	if h.opts.before != nil {
+		if err := h.opts.before(ctx, req); err != nil {
+			return nil, err
+		}
+	}
+	return h.invalidateDiscovery(ctx, req)
+}
+
+// ExportRelease renders a release as a kustomize base. It changes nothing.
+func (h *Client) ExportRelease(rlsName string, opts ...ExportOption) (*rls.ExportReleaseResponse, error) {
+	for _, opt := range opts {
+		opt(&h.opts)
+	}
+	req := &h.opts.exportReq
+	req.Name = rlsName
+	ctx := NewContext()
+
+	if h.opts.before != nil {
+		if err := h.opts.before(ctx, req); err != nil {
+			return nil, err
+		}
+	}
+	return h.export(ctx, req)
+}
+
+// ResumeRelease applies the resources that the latest revision of a release
+// did not get to apply before it failed.
+func (h *Client) ResumeRelease(rlsName string, opts ...ResumeOption) (*rls.ResumeReleaseResponse, error) {
+	for _, opt := range opts {
+		opt(&h.opts)
+	}
+	req := &h.opts.resumeReq
+	req.Name = rlsName
+	ctx := NewContext()
+
+	if h.opts.before != nil {
+		if err := h.opts.before(ctx, req); err != nil {
+			return nil, err
+		}
+	}
+	return h.resume(ctx, req)
+}
+
+// SnapshotRelease records the live state of the resources of a release in
+// its current revision, for RollbackFromSnapshot to restore.
+func (h *Client) SnapshotRelease(rlsName string, opts ...SnapshotOption) (*rls.SnapshotReleaseResponse, error) {
+	for _, opt := range opts {
+		opt(&h.opts)
+	}
+	req := &rls.SnapshotReleaseRequest{Name: rlsName}
+	ctx := NewContext()
+
+	if h.opts.before != nil {
+		if err := h.opts.before(ctx, req); err != nil {
+			return nil, err
+		}
+	}
+	return h.snapshot(ctx, req)
+}
+
+// InspectChart loads a chart from chstr and returns its metadata, default
+// values and README, as seen by Tiller.
+func (h *Client) InspectChart(chstr string, opts ...InspectOption) (*rls.InspectChartResponse, error) {
+	ch, err := chartutil.Load(chstr)
+	if err != nil {
+		return nil, err
+	}
+	return h.InspectChartFromChart(ch, opts...)
+}
+
+// InspectChartFromChart returns the metadata, default values and README of a
+// loaded chart, as seen by Tiller.
+func (h *Client) InspectChartFromChart(ch *chart.Chart, opts ...InspectOption) (*rls.InspectChartResponse, error) {
+	for _, opt := range opts {
+		opt(&h.opts)
+	}
+	req := &rls.InspectChartRequest{Chart: ch}
+	ctx := NewContext()
+
+	if h.opts.before != nil {
+		if err := h.opts.before(ctx, req); err != nil {
+			return nil, err
+		}
+	}
+	return h.inspect(ctx, req)
+}
+
 // RollbackRelease rolls back a release to the previous version
 func (h *Client) RollbackRelease(rlsName string, opts ...RollbackOption) (*rls.RollbackReleaseResponse, error) {
 	for _, opt := range opts {
@@ -252,6 +577,26 @@ func (h *Client) ReleaseContent(rlsName string, opts ...ContentOption) (*rls.Get
 	return h.content(ctx, req)
 }
 
+// ReleaseManifest returns only the manifest of a release. Without a
+// ContentReleaseVersion option, it returns the manifest of the deployed version.
+func (h *Client) ReleaseManifest(rlsName string, opts ...ContentOption) (*rls.GetManifestResponse, error) {
+	for _, opt := range opts {
+		opt(&h.opts)
+	}
+	req := &rls.GetManifestRequest{
+		Name:    rlsName,
+		Version: h.opts.contentReq.Version,
+	}
+	ctx := NewContext()
+
+	if h.opts.before != nil {
+		if err := h.opts.before(ctx, req); err != nil {
+			return nil, err
+		}
+	}
+	return h.manifest(ctx, req)
+}
+
 // ReleaseHistory returns a release's revision history.
 func (h *Client) ReleaseHistory(rlsName string, opts ...HistoryOption) (*rls.GetHistoryResponse, error) {
 	for _, opt := range opts {
@@ -283,6 +628,17 @@ func (h *Client) RunReleaseTest(rlsName string, opts ...ReleaseTestOption) (<-ch
 	return h.test(ctx, req)
 }
 
+// WatchWaitLogs streams the logs of the containers that are not ready while
+// an install or upgrade of the named release waits, if the operation asks
+// for them with InstallWaitLogBytes or UpgradeWaitLogBytes. The channels are
+// closed when the operation ends.
+func (h *Client) WatchWaitLogs(rlsName string) (<-chan *rls.WatchWaitLogsResponse, <-chan error) {
+	req := &rls.WatchWaitLogsRequest{Name: rlsName}
+	ctx := NewContext()
+
+	return h.waitLogs(ctx, req)
+}
+
 // connect returns a grpc connection to tiller or error. The grpc dial options
 // are constructed here.
 func (h *Client) connect(ctx context.Context) (conn *grpc.ClientConn, err error) {
@@ -403,6 +759,260 @@ func (h *Client) version(ctx context.Context, req *rls.GetVersionRequest) (*rls.
 	return rlc.GetVersion(ctx, req)
 }
 
+// Executes tiller.MigrationStatus RPC.
+func (h *Client) migrationStatus(ctx context.Context, req *rls.MigrationStatusRequest) (*rls.MigrationStatusResponse, error) {
+	c, err := h.connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer c.Close()
+
+	rlc := rls.NewReleaseServiceClient(c)
+	return rlc.MigrationStatus(ctx, req)
+}
+
+// Executes tiller.GetHealth RPC.
+func (h *Client) health(ctx context.Context, req *rls.GetHealthRequest) (*rls.GetHealthResponse, error) {
+	c, err := h.connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer c.Close()
+
+	rlc := rls.NewReleaseServiceClient(c)
+	return rlc.GetHealth(ctx, req)
+}
+
+// Executes tiller.GetManifest RPC.
+func (h *Client) manifest(ctx context.Context, req *rls.GetManifestRequest) (*rls.GetManifestResponse, error) {
+	c, err := h.connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer c.Close()
+
+	rlc := rls.NewReleaseServiceClient(c)
+	return rlc.GetManifest(ctx, req)
+}
+
+// Executes tiller.ForceUnlock RPC.
+func (h *Client) forceUnlock(ctx context.Context, req *rls.ForceUnlockRequest) (*rls.ForceUnlockResponse, error) {
+	c, err := h.connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer c.Close()
+
+	rlc := rls.NewReleaseServiceClient(c)
+	return rlc.ForceUnlock(ctx, req)
+}
+
+// Executes tiller.AnnotateRelease RPC.
+func (h *Client) annotate(ctx context.Context, req *rls.AnnotateReleaseRequest) (*rls.AnnotateReleaseResponse, error) {
+	c, err := h.connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer c.Close()
+
+	rlc := rls.NewReleaseServiceClient(c)
+	return rlc.AnnotateRelease(ctx, req)
+}
+
+// Executes tiller.RestartRelease RPC.
+func (h *Client) restart(ctx context.Context, req *rls.RestartReleaseRequest) (*rls.RestartReleaseResponse, error) {
+	c, err := h.connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer c.Close()
+
+	rlc := rls.NewReleaseServiceClient(c)
+	return rlc.RestartRelease(ctx, req)
+}
+
+// Executes tiller.ApproveRelease RPC.
+func (h *Client) approve(ctx context.Context, req *rls.ApproveReleaseRequest) (*rls.ApproveReleaseResponse, error) {
+	c, err := h.connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer c.Close()
+
+	rlc := rls.NewReleaseServiceClient(c)
+	return rlc.ApproveRelease(ctx, req)
+}
+
+// Executes tiller.RejectRelease RPC.
+func (h *Client) reject(ctx context.Context, req *rls.RejectReleaseRequest) (*rls.RejectReleaseResponse, error) {
+	c, err := h.connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer c.Close()
+
+	rlc := rls.NewReleaseServiceClient(c)
+	return rlc.RejectRelease(ctx, req)
+}
+
+// Executes tiller.GetReleaseDrift RPC.
+func (h *Client) drift(ctx context.Context, req *rls.GetReleaseDriftRequest) (*rls.GetReleaseDriftResponse, error) {
+	c, err := h.connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer c.Close()
+
+	rlc := rls.NewReleaseServiceClient(c)
+	return rlc.GetReleaseDrift(ctx, req)
+}
+
+// Executes tiller.GetHooks RPC.
+func (h *Client) hooks(ctx context.Context, req *rls.GetHooksRequest) (*rls.GetHooksResponse, error) {
+	c, err := h.connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer c.Close()
+
+	rlc := rls.NewReleaseServiceClient(c)
+	return rlc.GetHooks(ctx, req)
+}
+
+// Executes tiller.GetReleaseLabels RPC.
+func (h *Client) labels(ctx context.Context, req *rls.GetReleaseLabelsRequest) (*rls.GetReleaseLabelsResponse, error) {
+	c, err := h.connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer c.Close()
+
+	rlc := rls.NewReleaseServiceClient(c)
+	return rlc.GetReleaseLabels(ctx, req)
+}
+
+// Executes tiller.WatchWaitLogs RPC.
+func (h *Client) waitLogs(ctx context.Context, req *rls.WatchWaitLogsRequest) (<-chan *rls.WatchWaitLogsResponse, <-chan error) {
+	errc := make(chan error, 1)
+	c, err := h.connect(ctx)
+	if err != nil {
+		errc <- err
+		return nil, errc
+	}
+
+	ch := make(chan *rls.WatchWaitLogsResponse, 1)
+	go func() {
+		defer close(errc)
+		defer close(ch)
+		defer c.Close()
+
+		rlc := rls.NewReleaseServiceClient(c)
+		s, err := rlc.WatchWaitLogs(ctx, req)
+		if err != nil {
+			errc <- err
+			return
+		}
+
+		for {
+			msg, err := s.Recv()
+			if err == io.EOF {
+				return
+			}
+			if err != nil {
+				errc <- err
+				return
+			}
+			ch <- msg
+		}
+	}()
+
+	return ch, errc
+}
+
+// Executes tiller.InvalidateDiscoveryCache RPC.
+func (h *Client) invalidateDiscovery(ctx context.Context, req *rls.InvalidateDiscoveryCacheRequest) (*rls.InvalidateDiscoveryCacheResponse, error) {
+	c, err := h.connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer c.Close()
+
+	rlc := rls.NewReleaseServiceClient(c)
+	return rlc.InvalidateDiscoveryCache(ctx, req)
+}
+
+// Executes tiller.ExportRelease RPC.
+func (h *Client) export(ctx context.Context, req *rls.ExportReleaseRequest) (*rls.ExportReleaseResponse, error) {
+	c, err := h.connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer c.Close()
+
+	rlc := rls.NewReleaseServiceClient(c)
+	return rlc.ExportRelease(ctx, req)
+}
+
+// Executes tiller.ResumeRelease RPC.
+func (h *Client) resume(ctx context.Context, req *rls.ResumeReleaseRequest) (*rls.ResumeReleaseResponse, error) {
+	c, err := h.connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer c.Close()
+
+	rlc := rls.NewReleaseServiceClient(c)
+	return rlc.ResumeRelease(ctx, req)
+}
+
+// Executes tiller.SnapshotRelease RPC.
+func (h *Client) snapshot(ctx context.Context, req *rls.SnapshotReleaseRequest) (*rls.SnapshotReleaseResponse, error) {
+	c, err := h.connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer c.Close()
+
+	rlc := rls.NewReleaseServiceClient(c)
+	return rlc.SnapshotRelease(ctx, req)
+}
+
+// Executes tiller.BatchInstall RPC.
+func (h *Client) batchInstall(ctx context.Context, req *rls.BatchInstallRequest) (*rls.BatchInstallResponse, error) {
+	c, err := h.connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer c.Close()
+
+	rlc := rls.NewReleaseServiceClient(c)
+	return rlc.BatchInstall(ctx, req)
+}
+
+// Executes tiller.RestoreRelease RPC.
+func (h *Client) restore(ctx context.Context, req *rls.RestoreReleaseRequest) (*rls.RestoreReleaseResponse, error) {
+	c, err := h.connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer c.Close()
+
+	rlc := rls.NewReleaseServiceClient(c)
+	return rlc.RestoreRelease(ctx, req)
+}
+
+// Executes tiller.InspectChart RPC.
+func (h *Client) inspect(ctx context.Context, req *rls.InspectChartRequest) (*rls.InspectChartResponse, error) {
+	c, err := h.connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer c.Close()
+
+	rlc := rls.NewReleaseServiceClient(c)
+	return rlc.InspectChart(ctx, req)
+}
+
 // Executes tiller.GetHistory RPC.
 func (h *Client) history(ctx context.Context, req *rls.GetHistoryRequest) (*rls.GetHistoryResponse, error) {
 	c, err := h.connect(ctx)
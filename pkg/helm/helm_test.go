@@ -21,8 +21,10 @@ import (
 	"path/filepath"
 	"reflect"
 	"testing"
+	"time"
 
 	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/ptypes/timestamp"
 	"golang.org/x/net/context"
 
 	"k8s.io/helm/pkg/chartutil"
@@ -55,13 +57,15 @@ func TestListReleases_VerifyOptions(t *testing.T) {
 
 	// Expected ListReleasesRequest message
 	exp := &tpb.ListReleasesRequest{
-		Limit:       int64(limit),
-		Offset:      offset,
-		Filter:      filter,
-		SortBy:      tpb.ListSort_SortBy(sortBy),
-		SortOrder:   tpb.ListSort_SortOrder(sortOrd),
-		StatusCodes: codes,
-		Namespace:   namespace,
+		Limit:         int64(limit),
+		Offset:        offset,
+		Filter:        filter,
+		SortBy:        tpb.ListSort_SortBy(sortBy),
+		SortOrder:     tpb.ListSort_SortOrder(sortOrd),
+		StatusCodes:   codes,
+		Namespace:     namespace,
+		OutputFormat:  tpb.OutputFormat_JSON,
+		IncludeSystem: true,
 	}
 
 	// Options used in ListReleases
@@ -73,6 +77,8 @@ func TestListReleases_VerifyOptions(t *testing.T) {
 		ReleaseListFilter(filter),
 		ReleaseListStatuses(codes),
 		ReleaseListNamespace(namespace),
+		ReleaseListOutputFormat(tpb.OutputFormat_JSON),
+		ReleaseListIncludeSystem(true),
 	}
 
 	// BeforeCall option to intercept helm client ListReleasesRequest
@@ -106,13 +112,30 @@ func TestInstallRelease_VerifyOptions(t *testing.T) {
 
 	// Expected InstallReleaseRequest message
 	exp := &tpb.InstallReleaseRequest{
-		Chart:        loadChart(t, chartName),
-		Values:       &cpb.Config{Raw: string(overrides)},
-		DryRun:       dryRun,
-		Name:         releaseName,
-		DisableHooks: disableHooks,
-		Namespace:    namespace,
-		ReuseName:    reuseName,
+		Chart:               loadChart(t, chartName),
+		Values:              &cpb.Config{Raw: string(overrides)},
+		DryRun:              dryRun,
+		Name:                releaseName,
+		DisableHooks:        disableHooks,
+		Namespace:           namespace,
+		ReuseName:           reuseName,
+		VerifyImages:        true,
+		VerifyReferences:    true,
+		Annotations:         map[string]string{"git-commit": "4f2c1e0"},
+		Profile:             "prod",
+		AllowMissingProfile: true,
+		EnableHooks:         true,
+		TruncateName:        true,
+		Cluster:             "spoke-1",
+		OnFailure:           "revert",
+		WaitLogBytes:        2048,
+		TimeoutBudget:       900,
+		System:              true,
+		StrictValues:        true,
+		Impersonation:       &rls.Impersonation{User: "system:serviceaccount:ci:deployer"},
+		DeferNotes:          true,
+		Flags:               map[string]bool{"canary": true},
+		SkipHookLookup:      true,
 	}
 
 	// Options used in InstallRelease
@@ -122,6 +145,23 @@ func TestInstallRelease_VerifyOptions(t *testing.T) {
 		ReleaseName(releaseName),
 		InstallReuseName(reuseName),
 		InstallDisableHooks(disableHooks),
+		InstallEnableHooks(true),
+		InstallTruncateName(true),
+		InstallCluster("spoke-1"),
+		InstallOnFailure("revert"),
+		InstallWaitLogBytes(2048),
+		InstallTimeoutBudget(900),
+		InstallSystem(true),
+		InstallStrictValues(true),
+		InstallImpersonation(&rls.Impersonation{User: "system:serviceaccount:ci:deployer"}),
+		InstallDeferNotes(true),
+		InstallFlags(map[string]bool{"canary": true}),
+		InstallVerifyImages(true),
+		InstallVerifyReferences(true),
+		InstallAnnotations(map[string]string{"git-commit": "4f2c1e0"}),
+		InstallProfile("prod"),
+		InstallAllowMissingProfile(true),
+		InstallSkipHookLookup(true),
 	}
 
 	// BeforeCall option to intercept helm client InstallReleaseRequest
@@ -150,15 +190,21 @@ func TestDeleteRelease_VerifyOptions(t *testing.T) {
 
 	// Expected DeleteReleaseRequest message
 	exp := &tpb.UninstallReleaseRequest{
-		Name:         releaseName,
-		Purge:        purgeFlag,
-		DisableHooks: disableHooks,
+		Name:                releaseName,
+		Purge:               purgeFlag,
+		DisableHooks:        disableHooks,
+		EnableHooks:         true,
+		Impersonation:       &rls.Impersonation{User: "jane"},
+		DeleteHookResources: true,
 	}
 
 	// Options used in DeleteRelease
 	ops := []DeleteOption{
 		DeletePurge(purgeFlag),
 		DeleteDisableHooks(disableHooks),
+		DeleteEnableHooks(true),
+		DeleteImpersonation(&rls.Impersonation{User: "jane"}),
+		DeleteHookResources(true),
 	}
 
 	// BeforeCall option to intercept helm client DeleteReleaseRequest
@@ -190,11 +236,32 @@ func TestUpdateRelease_VerifyOptions(t *testing.T) {
 
 	// Expected UpdateReleaseRequest message
 	exp := &tpb.UpdateReleaseRequest{
-		Name:         releaseName,
-		Chart:        loadChart(t, chartName),
-		Values:       &cpb.Config{Raw: string(overrides)},
-		DryRun:       dryRun,
-		DisableHooks: disableHooks,
+		Name:                     releaseName,
+		Chart:                    loadChart(t, chartName),
+		Values:                   &cpb.Config{Raw: string(overrides)},
+		DryRun:                   dryRun,
+		DisableHooks:             disableHooks,
+		VerifyImages:             true,
+		VerifyReferences:         true,
+		Annotations:              map[string]string{"git-commit": "4f2c1e0"},
+		RequireApproval:          true,
+		ApprovalTimeout:          600,
+		GracePeriod:              30,
+		PropagationPolicy:        "Foreground",
+		RecreateOnSelectorChange: true,
+		Profile:                  "prod",
+		AllowMissingProfile:      true,
+		EnableHooks:              true,
+		Cluster:                  "spoke-1",
+		OnFailure:                "revert",
+		WaitLogBytes:             2048,
+		TimeoutBudget:            900,
+		AllowProtectedChanges:    true,
+		StrictValues:             true,
+		Impersonation:            &rls.Impersonation{User: "jane", Groups: []string{"deployers"}},
+		DeferNotes:               true,
+		Flags:                    map[string]bool{"canary": false},
+		SkipHookLookup:           true,
 	}
 
 	// Options used in UpdateRelease
@@ -202,6 +269,27 @@ func TestUpdateRelease_VerifyOptions(t *testing.T) {
 		UpgradeDryRun(dryRun),
 		UpdateValueOverrides(overrides),
 		UpgradeDisableHooks(disableHooks),
+		UpgradeEnableHooks(true),
+		UpgradeCluster("spoke-1"),
+		UpgradeOnFailure("revert"),
+		UpgradeWaitLogBytes(2048),
+		UpgradeTimeoutBudget(900),
+		UpgradeAllowProtectedChanges(true),
+		UpgradeStrictValues(true),
+		UpgradeImpersonation(&rls.Impersonation{User: "jane", Groups: []string{"deployers"}}),
+		UpgradeDeferNotes(true),
+		UpgradeFlags(map[string]bool{"canary": false}),
+		UpgradeVerifyImages(true),
+		UpgradeVerifyReferences(true),
+		UpgradeAnnotations(map[string]string{"git-commit": "4f2c1e0"}),
+		UpgradeRequireApproval(true),
+		UpgradeApprovalTimeout(600),
+		UpgradeGracePeriod(30),
+		UpgradePropagationPolicy("Foreground"),
+		UpgradeRecreateOnSelectorChange(true),
+		UpgradeProfile("prod"),
+		UpgradeAllowMissingProfile(true),
+		UpgradeSkipHookLookup(true),
 	}
 
 	// BeforeCall option to intercept helm client UpdateReleaseRequest
@@ -231,10 +319,22 @@ func TestRollbackRelease_VerifyOptions(t *testing.T) {
 
 	// Expected RollbackReleaseRequest message
 	exp := &tpb.RollbackReleaseRequest{
-		Name:         releaseName,
-		DryRun:       dryRun,
-		Version:      revision,
-		DisableHooks: disableHooks,
+		Name:                     releaseName,
+		DryRun:                   dryRun,
+		Version:                  revision,
+		DisableHooks:             disableHooks,
+		GracePeriod:              10,
+		PropagationPolicy:        "Orphan",
+		RecreateOnSelectorChange: true,
+		EnableHooks:              true,
+		ReRender:                 true,
+		Values:                   &cpb.Config{Raw: "replicas: 3\n"},
+		SkipHookLookup:           true,
+		TimeoutBudget:            900,
+		AllowDestructiveHooks:    true,
+		Impersonation:            &rls.Impersonation{User: "jane"},
+		DeployedAt:               &timestamp.Timestamp{Seconds: 1500000000},
+		FromSnapshot:             true,
 	}
 
 	// Options used in RollbackRelease
@@ -242,6 +342,18 @@ func TestRollbackRelease_VerifyOptions(t *testing.T) {
 		RollbackDryRun(dryRun),
 		RollbackVersion(revision),
 		RollbackDisableHooks(disableHooks),
+		RollbackEnableHooks(true),
+		RollbackGracePeriod(10),
+		RollbackPropagationPolicy("Orphan"),
+		RollbackRecreateOnSelectorChange(true),
+		RollbackReRender(true),
+		RollbackValueOverrides([]byte("replicas: 3\n")),
+		RollbackSkipHookLookup(true),
+		RollbackTimeoutBudget(900),
+		RollbackAllowDestructiveHooks(true),
+		RollbackImpersonation(&rls.Impersonation{User: "jane"}),
+		RollbackDeployedAt(time.Unix(1500000000, 0)),
+		RollbackFromSnapshot(true),
 	}
 
 	// BeforeCall option to intercept helm client RollbackReleaseRequest
@@ -269,8 +381,9 @@ func TestReleaseStatus_VerifyOptions(t *testing.T) {
 
 	// Expected GetReleaseStatusRequest message
 	exp := &tpb.GetReleaseStatusRequest{
-		Name:    releaseName,
-		Version: revision,
+		Name:         releaseName,
+		Version:      revision,
+		OutputFormat: tpb.OutputFormat_TABLE,
 	}
 
 	// BeforeCall option to intercept helm client GetReleaseStatusRequest
@@ -285,7 +398,7 @@ func TestReleaseStatus_VerifyOptions(t *testing.T) {
 		return errSkip
 	})
 
-	if _, err := NewClient(b4c).ReleaseStatus(releaseName, StatusReleaseVersion(revision)); err != errSkip {
+	if _, err := NewClient(b4c).ReleaseStatus(releaseName, StatusReleaseVersion(revision), StatusOutputFormat(tpb.OutputFormat_TABLE)); err != errSkip {
 		t.Fatalf("did not expect error but got (%v)\n``", err)
 	}
 }
@@ -319,6 +432,440 @@ func TestReleaseContent_VerifyOptions(t *testing.T) {
 	}
 }
 
+// Verify the releases of a batch are sent in a BatchInstallRequest.
+func TestBatchInstall_VerifyOptions(t *testing.T) {
+	releases := []*tpb.InstallReleaseRequest{
+		{Name: "first", Namespace: "default", Chart: loadChart(t, "alpine")},
+		{Name: "second", Namespace: "default", Chart: loadChart(t, "alpine"), Wait: true},
+	}
+
+	// Expected BatchInstallRequest message
+	exp := &tpb.BatchInstallRequest{Releases: releases}
+
+	// BeforeCall option to intercept helm client BatchInstallRequest
+	b4c := BeforeCall(func(_ context.Context, msg proto.Message) error {
+		switch act := msg.(type) {
+		case *tpb.BatchInstallRequest:
+			t.Logf("BatchInstallRequest: %#+v\n", act)
+			assert(t, exp, act)
+		default:
+			t.Fatalf("expected message of type BatchInstallRequest, got %T\n", act)
+		}
+		return errSkip
+	})
+
+	if _, err := NewClient(b4c).BatchInstall(releases); err != errSkip {
+		t.Fatalf("did not expect error but got (%v)\n``", err)
+	}
+}
+
+// Verify each RestoreOption is applied to a RestoreReleaseRequest correctly.
+func TestRestoreRelease_VerifyOptions(t *testing.T) {
+	// Options testdata
+	var disableHooks = true
+	var releaseName = "test"
+	var timeout int64 = 10
+	var wait = true
+
+	// Expected RestoreReleaseRequest message
+	exp := &tpb.RestoreReleaseRequest{
+		Name:         releaseName,
+		DisableHooks: disableHooks,
+		Timeout:      timeout,
+		Wait:         wait,
+	}
+
+	// Options used in RestoreRelease
+	ops := []RestoreOption{
+		RestoreDisableHooks(disableHooks),
+		RestoreTimeout(timeout),
+		RestoreWait(wait),
+	}
+
+	// BeforeCall option to intercept helm client RestoreReleaseRequest
+	b4c := BeforeCall(func(_ context.Context, msg proto.Message) error {
+		switch act := msg.(type) {
+		case *tpb.RestoreReleaseRequest:
+			t.Logf("RestoreReleaseRequest: %#+v\n", act)
+			assert(t, exp, act)
+		default:
+			t.Fatalf("expected message of type RestoreReleaseRequest, got %T\n", act)
+		}
+		return errSkip
+	})
+
+	if _, err := NewClient(b4c).RestoreRelease(releaseName, ops...); err != errSkip {
+		t.Fatalf("did not expect error but got (%v)\n``", err)
+	}
+}
+
+// Verify each ResumeOption is applied to a ResumeReleaseRequest correctly.
+func TestResumeRelease_VerifyOptions(t *testing.T) {
+	// Options testdata
+	var releaseName = "test"
+	var timeout int64 = 10
+	var wait = true
+
+	// Expected ResumeReleaseRequest message
+	exp := &tpb.ResumeReleaseRequest{
+		Name:    releaseName,
+		Timeout: timeout,
+		Wait:    wait,
+	}
+
+	// Options used in ResumeRelease
+	ops := []ResumeOption{
+		ResumeTimeout(timeout),
+		ResumeWait(wait),
+	}
+
+	// BeforeCall option to intercept helm client ResumeReleaseRequest
+	b4c := BeforeCall(func(_ context.Context, msg proto.Message) error {
+		switch act := msg.(type) {
+		case *tpb.ResumeReleaseRequest:
+			t.Logf("ResumeReleaseRequest: %#+v\n", act)
+			assert(t, exp, act)
+		default:
+			t.Fatalf("expected message of type ResumeReleaseRequest, got %T\n", act)
+		}
+		return errSkip
+	})
+
+	if _, err := NewClient(b4c).ResumeRelease(releaseName, ops...); err != errSkip {
+		t.Fatalf("did not expect error but got (%v)\n``", err)
+	}
+}
+
+// Verify each AnnotateOption is applied to an AnnotateReleaseRequest correctly.
+func TestAnnotateRelease_VerifyOptions(t *testing.T) {
+	// Options testdata
+	var releaseName = "test"
+	var version int32 = 3
+	var annotations = map[string]string{"approved-by": "alice"}
+	var remove = []string{"pipeline"}
+
+	// Expected AnnotateReleaseRequest message
+	exp := &tpb.AnnotateReleaseRequest{
+		Name:        releaseName,
+		Version:     version,
+		Annotations: annotations,
+		Remove:      remove,
+	}
+
+	// BeforeCall option to intercept helm client AnnotateReleaseRequest
+	b4c := BeforeCall(func(_ context.Context, msg proto.Message) error {
+		switch act := msg.(type) {
+		case *tpb.AnnotateReleaseRequest:
+			t.Logf("AnnotateReleaseRequest: %#+v\n", act)
+			assert(t, exp, act)
+		default:
+			t.Fatalf("expected message of type AnnotateReleaseRequest, got %T\n", act)
+		}
+		return errSkip
+	})
+
+	if _, err := NewClient(b4c).AnnotateRelease(releaseName, annotations, remove, AnnotateVersion(version)); err != errSkip {
+		t.Fatalf("did not expect error but got (%v)\n``", err)
+	}
+}
+
+// Verify each RestartOption is applied to a RestartReleaseRequest correctly.
+func TestRestartRelease_VerifyOptions(t *testing.T) {
+	// Options testdata
+	var releaseName = "test"
+	var timeout int64 = 300
+
+	// Expected RestartReleaseRequest message
+	exp := &tpb.RestartReleaseRequest{
+		Name:    releaseName,
+		Timeout: timeout,
+		Wait:    true,
+	}
+
+	// BeforeCall option to intercept helm client RestartReleaseRequest
+	b4c := BeforeCall(func(_ context.Context, msg proto.Message) error {
+		switch act := msg.(type) {
+		case *tpb.RestartReleaseRequest:
+			t.Logf("RestartReleaseRequest: %#+v\n", act)
+			assert(t, exp, act)
+		default:
+			t.Fatalf("expected message of type RestartReleaseRequest, got %T\n", act)
+		}
+		return errSkip
+	})
+
+	if _, err := NewClient(b4c).RestartRelease(releaseName, RestartTimeout(timeout), RestartWait(true)); err != errSkip {
+		t.Fatalf("did not expect error but got (%v)\n``", err)
+	}
+}
+
+// Verify each DriftOption is applied to a GetReleaseDriftRequest correctly.
+func TestReleaseDrift_VerifyOptions(t *testing.T) {
+	// Options testdata
+	var releaseName = "test"
+	var revision int32 = 2
+
+	// Expected GetReleaseDriftRequest message
+	exp := &tpb.GetReleaseDriftRequest{
+		Name:    releaseName,
+		Version: revision,
+	}
+
+	// BeforeCall option to intercept helm client GetReleaseDriftRequest
+	b4c := BeforeCall(func(_ context.Context, msg proto.Message) error {
+		switch act := msg.(type) {
+		case *tpb.GetReleaseDriftRequest:
+			t.Logf("GetReleaseDriftRequest: %#+v\n", act)
+			assert(t, exp, act)
+		default:
+			t.Fatalf("expected message of type GetReleaseDriftRequest, got %T\n", act)
+		}
+		return errSkip
+	})
+
+	if _, err := NewClient(b4c).ReleaseDrift(releaseName, DriftVersion(revision)); err != errSkip {
+		t.Fatalf("did not expect error but got (%v)\n``", err)
+	}
+}
+
+// Verify the chart and values of a DriftOption are applied to a
+// GetReleaseDriftRequest correctly.
+func TestReleaseDriftChart_VerifyOptions(t *testing.T) {
+	// Options testdata
+	var releaseName = "test"
+	var chartName = "alpine"
+	var overrides = []byte("key1=value1,key2=value2")
+
+	// Expected GetReleaseDriftRequest message
+	exp := &tpb.GetReleaseDriftRequest{
+		Name:        releaseName,
+		Chart:       loadChart(t, chartName),
+		Values:      &cpb.Config{Raw: string(overrides)},
+		ResetValues: true,
+		ReuseValues: true,
+	}
+
+	// BeforeCall option to intercept helm client GetReleaseDriftRequest
+	b4c := BeforeCall(func(_ context.Context, msg proto.Message) error {
+		switch act := msg.(type) {
+		case *tpb.GetReleaseDriftRequest:
+			t.Logf("GetReleaseDriftRequest: %#+v\n", act)
+			assert(t, exp, act)
+		default:
+			t.Fatalf("expected message of type GetReleaseDriftRequest, got %T\n", act)
+		}
+		return errSkip
+	})
+
+	ops := []DriftOption{
+		DriftChart(loadChart(t, chartName), overrides),
+		DriftResetValues(true),
+		DriftReuseValues(true),
+	}
+	if _, err := NewClient(b4c).ReleaseDrift(releaseName, ops...); err != errSkip {
+		t.Fatalf("did not expect error but got (%v)\n``", err)
+	}
+}
+
+// Verify each HooksOption is applied to a GetHooksRequest correctly.
+func TestReleaseHooks_VerifyOptions(t *testing.T) {
+	// Options testdata
+	var releaseName = "test"
+	var revision int32 = 2
+
+	// Expected GetHooksRequest message
+	exp := &tpb.GetHooksRequest{
+		Name:    releaseName,
+		Version: revision,
+	}
+
+	// BeforeCall option to intercept helm client GetHooksRequest
+	b4c := BeforeCall(func(_ context.Context, msg proto.Message) error {
+		switch act := msg.(type) {
+		case *tpb.GetHooksRequest:
+			t.Logf("GetHooksRequest: %#+v\n", act)
+			assert(t, exp, act)
+		default:
+			t.Fatalf("expected message of type GetHooksRequest, got %T\n", act)
+		}
+		return errSkip
+	})
+
+	if _, err := NewClient(b4c).ReleaseHooks(releaseName, HooksVersion(revision)); err != errSkip {
+		t.Fatalf("did not expect error but got (%v)\n``", err)
+	}
+}
+
+// Verify each LabelsOption is applied to a GetReleaseLabelsRequest correctly.
+func TestReleaseLabels_VerifyOptions(t *testing.T) {
+	// Options testdata
+	var releaseName = "test"
+	var revision int32 = 2
+	var chartName = "alpine"
+	var overrides = []byte("key1=value1,key2=value2")
+
+	// Expected GetReleaseLabelsRequest message
+	exp := &tpb.GetReleaseLabelsRequest{
+		Name:      releaseName,
+		Version:   revision,
+		Chart:     loadChart(t, chartName),
+		Values:    &cpb.Config{Raw: string(overrides)},
+		Namespace: "team-a",
+	}
+
+	// BeforeCall option to intercept helm client GetReleaseLabelsRequest
+	b4c := BeforeCall(func(_ context.Context, msg proto.Message) error {
+		switch act := msg.(type) {
+		case *tpb.GetReleaseLabelsRequest:
+			t.Logf("GetReleaseLabelsRequest: %#+v\n", act)
+			assert(t, exp, act)
+		default:
+			t.Fatalf("expected message of type GetReleaseLabelsRequest, got %T\n", act)
+		}
+		return errSkip
+	})
+
+	if _, err := NewClient(b4c).ReleaseLabels(releaseName, LabelsVersion(revision), LabelsChart(loadChart(t, chartName), overrides, "team-a")); err != errSkip {
+		t.Fatalf("did not expect error but got (%v)\n``", err)
+	}
+}
+
+// Verify each DiscoveryCacheOption is applied to an InvalidateDiscoveryCacheRequest correctly.
+func TestInvalidateDiscoveryCache_VerifyOptions(t *testing.T) {
+	// Expected InvalidateDiscoveryCacheRequest message
+	exp := &tpb.InvalidateDiscoveryCacheRequest{Cluster: "eu-west"}
+
+	// BeforeCall option to intercept helm client InvalidateDiscoveryCacheRequest
+	b4c := BeforeCall(func(_ context.Context, msg proto.Message) error {
+		switch act := msg.(type) {
+		case *tpb.InvalidateDiscoveryCacheRequest:
+			t.Logf("InvalidateDiscoveryCacheRequest: %#+v\n", act)
+			assert(t, exp, act)
+		default:
+			t.Fatalf("expected message of type InvalidateDiscoveryCacheRequest, got %T\n", act)
+		}
+		return errSkip
+	})
+
+	if _, err := NewClient(b4c).InvalidateDiscoveryCache(DiscoveryCacheCluster("eu-west")); err != errSkip {
+		t.Fatalf("did not expect error but got (%v)\n``", err)
+	}
+}
+
+// Verify each ExportOption is applied to an ExportReleaseRequest correctly.
+func TestExportRelease_VerifyOptions(t *testing.T) {
+	// Options testdata
+	var releaseName = "test"
+	var revision int32 = 2
+	var chartName = "alpine"
+	var overrides = []byte("key1=value1,key2=value2")
+
+	// Expected ExportReleaseRequest message
+	exp := &tpb.ExportReleaseRequest{
+		Name:      releaseName,
+		Version:   revision,
+		Chart:     loadChart(t, chartName),
+		Values:    &cpb.Config{Raw: string(overrides)},
+		Namespace: "staging",
+	}
+
+	// BeforeCall option to intercept helm client ExportReleaseRequest
+	b4c := BeforeCall(func(_ context.Context, msg proto.Message) error {
+		switch act := msg.(type) {
+		case *tpb.ExportReleaseRequest:
+			t.Logf("ExportReleaseRequest: %#+v\n", act)
+			assert(t, exp, act)
+		default:
+			t.Fatalf("expected message of type ExportReleaseRequest, got %T\n", act)
+		}
+		return errSkip
+	})
+
+	ops := []ExportOption{
+		ExportVersion(revision),
+		ExportChart(loadChart(t, chartName), overrides, "staging"),
+	}
+	if _, err := NewClient(b4c).ExportRelease(releaseName, ops...); err != errSkip {
+		t.Fatalf("did not expect error but got (%v)\n``", err)
+	}
+}
+
+// Verify the release name is sent with an ApproveReleaseRequest.
+func TestApproveRelease_VerifyOptions(t *testing.T) {
+	var releaseName = "test"
+
+	// Expected ApproveReleaseRequest message
+	exp := &tpb.ApproveReleaseRequest{Name: releaseName}
+
+	// BeforeCall option to intercept helm client ApproveReleaseRequest
+	b4c := BeforeCall(func(_ context.Context, msg proto.Message) error {
+		switch act := msg.(type) {
+		case *tpb.ApproveReleaseRequest:
+			t.Logf("ApproveReleaseRequest: %#+v\n", act)
+			assert(t, exp, act)
+		default:
+			t.Fatalf("expected message of type ApproveReleaseRequest, got %T\n", act)
+		}
+		return errSkip
+	})
+
+	if _, err := NewClient(b4c).ApproveRelease(releaseName); err != errSkip {
+		t.Fatalf("did not expect error but got (%v)\n``", err)
+	}
+}
+
+// Verify the release name and reason are sent with a RejectReleaseRequest.
+func TestRejectRelease_VerifyOptions(t *testing.T) {
+	var releaseName = "test"
+	var reason = "change freeze"
+
+	// Expected RejectReleaseRequest message
+	exp := &tpb.RejectReleaseRequest{Name: releaseName, Reason: reason}
+
+	// BeforeCall option to intercept helm client RejectReleaseRequest
+	b4c := BeforeCall(func(_ context.Context, msg proto.Message) error {
+		switch act := msg.(type) {
+		case *tpb.RejectReleaseRequest:
+			t.Logf("RejectReleaseRequest: %#+v\n", act)
+			assert(t, exp, act)
+		default:
+			t.Fatalf("expected message of type RejectReleaseRequest, got %T\n", act)
+		}
+		return errSkip
+	})
+
+	if _, err := NewClient(b4c).RejectRelease(releaseName, reason); err != errSkip {
+		t.Fatalf("did not expect error but got (%v)\n``", err)
+	}
+}
+
+// Verify the chart is sent with an InspectChartRequest.
+func TestInspectChart_VerifyOptions(t *testing.T) {
+	var chartName = "alpine"
+	var chartPath = filepath.Join(chartsDir, chartName)
+
+	// Expected InspectChartRequest message
+	exp := &tpb.InspectChartRequest{
+		Chart: loadChart(t, chartName),
+	}
+
+	// BeforeCall option to intercept helm client InspectChartRequest
+	b4c := BeforeCall(func(_ context.Context, msg proto.Message) error {
+		switch act := msg.(type) {
+		case *tpb.InspectChartRequest:
+			t.Logf("InspectChartRequest: %#+v\n", act)
+			assert(t, exp, act)
+		default:
+			t.Fatalf("expected message of type InspectChartRequest, got %T\n", act)
+		}
+		return errSkip
+	})
+
+	if _, err := NewClient(b4c).InspectChart(chartPath); err != errSkip {
+		t.Fatalf("did not expect error but got (%v)\n``", err)
+	}
+}
+
 func assert(t *testing.T, expect, actual interface{}) {
 	if !reflect.DeepEqual(expect, actual) {
 		t.Fatalf("expected %#+v, actual %#+v\n", expect, actual)
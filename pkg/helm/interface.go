@@ -32,7 +32,27 @@ type Interface interface {
 	UpdateReleaseFromChart(rlsName string, chart *chart.Chart, opts ...UpdateOption) (*rls.UpdateReleaseResponse, error)
 	RollbackRelease(rlsName string, opts ...RollbackOption) (*rls.RollbackReleaseResponse, error)
 	ReleaseContent(rlsName string, opts ...ContentOption) (*rls.GetReleaseContentResponse, error)
+	ReleaseManifest(rlsName string, opts ...ContentOption) (*rls.GetManifestResponse, error)
 	ReleaseHistory(rlsName string, opts ...HistoryOption) (*rls.GetHistoryResponse, error)
 	GetVersion(opts ...VersionOption) (*rls.GetVersionResponse, error)
+	GetHealth(opts ...HealthOption) (*rls.GetHealthResponse, error)
+	MigrationStatus(opts ...MigrationStatusOption) (*rls.MigrationStatusResponse, error)
+	ForceUnlock(rlsName, confirm string, opts ...ForceUnlockOption) (*rls.ForceUnlockResponse, error)
+	BatchInstall(releases []*rls.InstallReleaseRequest, opts ...BatchInstallOption) (*rls.BatchInstallResponse, error)
+	RestoreRelease(rlsName string, opts ...RestoreOption) (*rls.RestoreReleaseResponse, error)
+	InspectChart(chStr string, opts ...InspectOption) (*rls.InspectChartResponse, error)
+	InspectChartFromChart(chart *chart.Chart, opts ...InspectOption) (*rls.InspectChartResponse, error)
+	AnnotateRelease(rlsName string, annotations map[string]string, remove []string, opts ...AnnotateOption) (*rls.AnnotateReleaseResponse, error)
+	RestartRelease(rlsName string, opts ...RestartOption) (*rls.RestartReleaseResponse, error)
+	ApproveRelease(rlsName string, opts ...ApproveOption) (*rls.ApproveReleaseResponse, error)
+	RejectRelease(rlsName, reason string, opts ...RejectOption) (*rls.RejectReleaseResponse, error)
+	ReleaseDrift(rlsName string, opts ...DriftOption) (*rls.GetReleaseDriftResponse, error)
+	ExportRelease(rlsName string, opts ...ExportOption) (*rls.ExportReleaseResponse, error)
+	ResumeRelease(rlsName string, opts ...ResumeOption) (*rls.ResumeReleaseResponse, error)
+	SnapshotRelease(rlsName string, opts ...SnapshotOption) (*rls.SnapshotReleaseResponse, error)
+	ReleaseHooks(rlsName string, opts ...HooksOption) (*rls.GetHooksResponse, error)
+	ReleaseLabels(rlsName string, opts ...LabelsOption) (*rls.GetReleaseLabelsResponse, error)
+	InvalidateDiscoveryCache(opts ...DiscoveryCacheOption) (*rls.InvalidateDiscoveryCacheResponse, error)
 	RunReleaseTest(rlsName string, opts ...ReleaseTestOption) (<-chan *rls.TestReleaseResponse, <-chan error)
+	WatchWaitLogs(rlsName string) (<-chan *rls.WatchWaitLogsResponse, <-chan error)
 }
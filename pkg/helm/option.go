@@ -18,6 +18,7 @@ package helm
 
 import (
 	"crypto/tls"
+	"time"
 
 	"github.com/golang/protobuf/proto"
 	"golang.org/x/net/context"
@@ -26,6 +27,7 @@ import (
 	cpb "k8s.io/helm/pkg/proto/hapi/chart"
 	"k8s.io/helm/pkg/proto/hapi/release"
 	rls "k8s.io/helm/pkg/proto/hapi/services"
+	"k8s.io/helm/pkg/timeconv"
 	"k8s.io/helm/pkg/version"
 )
 
@@ -68,6 +70,24 @@ type options struct {
 	contentReq rls.GetReleaseContentRequest
 	// release rollback options are applied directly to the rollback release request
 	rollbackReq rls.RollbackReleaseRequest
+	// release restore options are applied directly to the restore release request
+	restoreReq rls.RestoreReleaseRequest
+	// release annotate options are applied directly to the annotate release request
+	annotateReq rls.AnnotateReleaseRequest
+	// release restart options are applied directly to the restart release request
+	restartReq rls.RestartReleaseRequest
+	// release drift options are applied directly to the get release drift request
+	driftReq rls.GetReleaseDriftRequest
+	// release export options are applied directly to the export release request
+	exportReq rls.ExportReleaseRequest
+	// release resume options are applied directly to the resume release request
+	resumeReq rls.ResumeReleaseRequest
+	// release hooks options are applied directly to the get hooks request
+	hooksReq rls.GetHooksRequest
+	// release labels options are applied directly to the get release labels request
+	labelsReq rls.GetReleaseLabelsRequest
+	// discovery cache options are applied directly to the invalidate discovery cache request
+	discoveryReq rls.InvalidateDiscoveryCacheRequest
 	// before intercepts client calls before sending
 	before func(context.Context, proto.Message) error
 	// release history options are applied directly to the get release history request
@@ -161,6 +181,22 @@ func ReleaseListNamespace(namespace string) ReleaseListOption {
 	}
 }
 
+// ReleaseListOutputFormat makes Tiller render the listed releases in the
+// given format, in the Rendered field of the response.
+func ReleaseListOutputFormat(format rls.OutputFormat_Format) ReleaseListOption {
+	return func(opts *options) {
+		opts.listReq.OutputFormat = format
+	}
+}
+
+// ReleaseListIncludeSystem lists system releases as well, which are left
+// out otherwise.
+func ReleaseListIncludeSystem(include bool) ReleaseListOption {
+	return func(opts *options) {
+		opts.listReq.IncludeSystem = include
+	}
+}
+
 // InstallOption allows specifying various settings
 // configurable by the helm client user for overriding
 // the defaults used when running the `helm install` command.
@@ -194,6 +230,32 @@ func UpgradeTimeout(timeout int64) UpdateOption {
 	}
 }
 
+// InstallTimeoutBudget specifies the number of seconds that the hooks and
+// the install of the resources share. Each gets the time that is left
+// instead of the timeout.
+func InstallTimeoutBudget(budget int64) InstallOption {
+	return func(opts *options) {
+		opts.instReq.TimeoutBudget = budget
+	}
+}
+
+// UpgradeTimeoutBudget specifies the number of seconds that the hooks and
+// the upgrade of the resources share. Each gets the time that is left
+// instead of the timeout.
+func UpgradeTimeoutBudget(budget int64) UpdateOption {
+	return func(opts *options) {
+		opts.updateReq.TimeoutBudget = budget
+	}
+}
+
+// UpgradeAllowProtectedChanges lets the upgrade change the resources that the
+// helm.sh/protected-resources annotation of the release protects.
+func UpgradeAllowProtectedChanges(allow bool) UpdateOption {
+	return func(opts *options) {
+		opts.updateReq.AllowProtectedChanges = allow
+	}
+}
+
 // DeleteTimeout specifies the number of seconds before kubernetes calls timeout
 func DeleteTimeout(timeout int64) DeleteOption {
 	return func(opts *options) {
@@ -222,6 +284,36 @@ func RollbackTimeout(timeout int64) RollbackOption {
 	}
 }
 
+// RollbackTimeoutBudget specifies the number of seconds that the hooks and
+// the rollback of the resources share. Each gets the time that is left
+// instead of the timeout.
+func RollbackTimeoutBudget(budget int64) RollbackOption {
+	return func(opts *options) {
+		opts.rollbackReq.TimeoutBudget = budget
+	}
+}
+
+// RestoreTimeout specifies the number of seconds before kubernetes calls timeout
+func RestoreTimeout(timeout int64) RestoreOption {
+	return func(opts *options) {
+		opts.restoreReq.Timeout = timeout
+	}
+}
+
+// RestoreWait specifies whether or not to wait for all resources to be ready
+func RestoreWait(wait bool) RestoreOption {
+	return func(opts *options) {
+		opts.restoreReq.Wait = wait
+	}
+}
+
+// RestoreDisableHooks will disable hooks for a restore operation
+func RestoreDisableHooks(disable bool) RestoreOption {
+	return func(opts *options) {
+		opts.restoreReq.DisableHooks = disable
+	}
+}
+
 // InstallWait specifies whether or not to wait for all resources to be ready
 func InstallWait(wait bool) InstallOption {
 	return func(opts *options) {
@@ -229,6 +321,377 @@ func InstallWait(wait bool) InstallOption {
 	}
 }
 
+// InstallServerDryRun will (if true) return the resources of a dry-run
+// installation as the API server would store them, with defaults applied.
+func InstallServerDryRun(dry bool) InstallOption {
+	return func(opts *options) {
+		opts.instReq.ServerDryRun = dry
+	}
+}
+
+// UpgradeServerDryRun will (if true) return the resources of a dry-run
+// upgrade as the API server would store them, with defaults applied.
+func UpgradeServerDryRun(dry bool) UpdateOption {
+	return func(opts *options) {
+		opts.updateReq.ServerDryRun = dry
+	}
+}
+
+// InstallVerifyImages will (if true) check that every container image of the
+// release can be pulled before installing it.
+func InstallVerifyImages(verify bool) InstallOption {
+	return func(opts *options) {
+		opts.instReq.VerifyImages = verify
+	}
+}
+
+// UpgradeVerifyImages will (if true) check that every container image of the
+// release can be pulled before upgrading it.
+func UpgradeVerifyImages(verify bool) UpdateOption {
+	return func(opts *options) {
+		opts.updateReq.VerifyImages = verify
+	}
+}
+
+// InstallVerifyReferences will (if true) check that the ServiceAccounts,
+// Secrets and ConfigMaps that the pods of the release refer to exist before
+// installing it.
+func InstallVerifyReferences(verify bool) InstallOption {
+	return func(opts *options) {
+		opts.instReq.VerifyReferences = verify
+	}
+}
+
+// UpgradeVerifyReferences will (if true) check that the ServiceAccounts,
+// Secrets and ConfigMaps that the pods of the release refer to exist before
+// upgrading it.
+func UpgradeVerifyReferences(verify bool) UpdateOption {
+	return func(opts *options) {
+		opts.updateReq.VerifyReferences = verify
+	}
+}
+
+// InstallStrictValues will (if true) fail the install if the values have keys
+// that the chart and its subcharts do not know.
+func InstallStrictValues(strict bool) InstallOption {
+	return func(opts *options) {
+		opts.instReq.StrictValues = strict
+	}
+}
+
+// UpgradeStrictValues will (if true) fail the upgrade if the values have keys
+// that the chart and its subcharts do not know.
+func UpgradeStrictValues(strict bool) UpdateOption {
+	return func(opts *options) {
+		opts.updateReq.StrictValues = strict
+	}
+}
+
+// InstallDeferNotes will (if true) render the notes again once the release's
+// resources are created and, with wait, ready, so that they can use .Live.
+func InstallDeferNotes(deferNotes bool) InstallOption {
+	return func(opts *options) {
+		opts.instReq.DeferNotes = deferNotes
+	}
+}
+
+// UpgradeDeferNotes will (if true) render the notes again once the release's
+// resources are updated and, with wait, ready, so that they can use .Live.
+func UpgradeDeferNotes(deferNotes bool) UpdateOption {
+	return func(opts *options) {
+		opts.updateReq.DeferNotes = deferNotes
+	}
+}
+
+// InstallFlags sets the feature flags that templates read as .Release.Flags
+// during the install.
+func InstallFlags(flags map[string]bool) InstallOption {
+	return func(opts *options) {
+		opts.instReq.Flags = flags
+	}
+}
+
+// UpgradeFlags sets the feature flags that templates read as .Release.Flags
+// during the upgrade. The flags of earlier revisions are not carried over.
+func UpgradeFlags(flags map[string]bool) UpdateOption {
+	return func(opts *options) {
+		opts.updateReq.Flags = flags
+	}
+}
+
+// InstallImpersonation makes Tiller act as the identity imp towards the
+// Kubernetes API while installing the release.
+func InstallImpersonation(imp *release.Impersonation) InstallOption {
+	return func(opts *options) {
+		opts.instReq.Impersonation = imp
+	}
+}
+
+// UpgradeImpersonation makes Tiller act as the identity imp towards the
+// Kubernetes API while upgrading the release.
+func UpgradeImpersonation(imp *release.Impersonation) UpdateOption {
+	return func(opts *options) {
+		opts.updateReq.Impersonation = imp
+	}
+}
+
+// RollbackImpersonation makes Tiller act as the identity imp towards the
+// Kubernetes API while rolling back the release.
+func RollbackImpersonation(imp *release.Impersonation) RollbackOption {
+	return func(opts *options) {
+		opts.rollbackReq.Impersonation = imp
+	}
+}
+
+// DeleteImpersonation makes Tiller act as the identity imp towards the
+// Kubernetes API while deleting the release.
+func DeleteImpersonation(imp *release.Impersonation) DeleteOption {
+	return func(opts *options) {
+		opts.uninstallReq.Impersonation = imp
+	}
+}
+
+// InstallProfile merges the values of the chart's values-<profile>.yaml file
+// over its defaults, under the values given with ValueOverrides.
+func InstallProfile(profile string) InstallOption {
+	return func(opts *options) {
+		opts.instReq.Profile = profile
+	}
+}
+
+// InstallAllowMissingProfile will (if true) install a chart that has no values
+// file for the profile given with InstallProfile, instead of failing.
+func InstallAllowMissingProfile(allow bool) InstallOption {
+	return func(opts *options) {
+		opts.instReq.AllowMissingProfile = allow
+	}
+}
+
+// UpgradeProfile merges the values of the chart's values-<profile>.yaml file
+// over its defaults, under the values given with UpdateValueOverrides.
+func UpgradeProfile(profile string) UpdateOption {
+	return func(opts *options) {
+		opts.updateReq.Profile = profile
+	}
+}
+
+// UpgradeAllowMissingProfile will (if true) upgrade to a chart that has no
+// values file for the profile given with UpgradeProfile, instead of failing.
+func UpgradeAllowMissingProfile(allow bool) UpdateOption {
+	return func(opts *options) {
+		opts.updateReq.AllowMissingProfile = allow
+	}
+}
+
+// UpgradeRequireApproval will (if true) make the upgrade wait for approval
+// after the pre-upgrade hooks; see Client.ApproveRelease.
+func UpgradeRequireApproval(require bool) UpdateOption {
+	return func(opts *options) {
+		opts.updateReq.RequireApproval = require
+	}
+}
+
+// UpgradeApprovalTimeout specifies the number of seconds to wait for approval
+// before the upgrade is rejected.
+func UpgradeApprovalTimeout(timeout int64) UpdateOption {
+	return func(opts *options) {
+		opts.updateReq.ApprovalTimeout = timeout
+	}
+}
+
+// UpgradeGracePeriod specifies the number of seconds that the pods deleted
+// during the upgrade get to shut down. Zero uses each pod's own grace period.
+func UpgradeGracePeriod(seconds int64) UpdateOption {
+	return func(opts *options) {
+		opts.updateReq.GracePeriod = seconds
+	}
+}
+
+// UpgradePropagationPolicy specifies the deletion propagation policy of the
+// resources deleted during the upgrade: "Foreground", "Background" or "Orphan".
+func UpgradePropagationPolicy(policy string) UpdateOption {
+	return func(opts *options) {
+		opts.updateReq.PropagationPolicy = policy
+	}
+}
+
+// UpgradeRecreateOnSelectorChange will (if true) delete and recreate the
+// workloads whose label selector changed, leaving their pods running.
+func UpgradeRecreateOnSelectorChange(recreate bool) UpdateOption {
+	return func(opts *options) {
+		opts.updateReq.RecreateOnSelectorChange = recreate
+	}
+}
+
+// InstallAnnotations records annotations, such as the commit or pipeline
+// that deployed the release, with the installed release.
+func InstallAnnotations(annotations map[string]string) InstallOption {
+	return func(opts *options) {
+		opts.instReq.Annotations = annotations
+	}
+}
+
+// UpgradeAnnotations records annotations, such as the commit or pipeline
+// that deployed the release, with the new revision.
+func UpgradeAnnotations(annotations map[string]string) UpdateOption {
+	return func(opts *options) {
+		opts.updateReq.Annotations = annotations
+	}
+}
+
+// AnnotateVersion sets the revision to annotate. The latest revision is
+// annotated by default.
+func AnnotateVersion(version int32) AnnotateOption {
+	return func(opts *options) {
+		opts.annotateReq.Version = version
+	}
+}
+
+// RestartTimeout specifies the number of seconds before kubernetes calls timeout
+func RestartTimeout(timeout int64) RestartOption {
+	return func(opts *options) {
+		opts.restartReq.Timeout = timeout
+	}
+}
+
+// RestartWait specifies whether or not to wait for the restarted workloads to
+// be ready
+func RestartWait(wait bool) RestartOption {
+	return func(opts *options) {
+		opts.restartReq.Wait = wait
+	}
+}
+
+// DriftVersion sets the revision to compare to the cluster. The latest
+// revision is compared by default.
+func DriftVersion(version int32) DriftOption {
+	return func(opts *options) {
+		opts.driftReq.Version = version
+	}
+}
+
+// DriftChart compares the resources that an upgrade of the release to ch,
+// with the raw values, would apply, instead of those of the release.
+func DriftChart(ch *cpb.Chart, raw []byte) DriftOption {
+	return func(opts *options) {
+		opts.driftReq.Chart = ch
+		opts.driftReq.Values = &cpb.Config{Raw: string(raw)}
+	}
+}
+
+// DriftResetValues renders the chart of DriftChart without the values of the
+// release, as an upgrade with ResetValues would.
+func DriftResetValues(reset bool) DriftOption {
+	return func(opts *options) {
+		opts.driftReq.ResetValues = reset
+	}
+}
+
+// DriftReuseValues renders the chart of DriftChart with its values merged
+// over those of the release, as an upgrade with ReuseValues would.
+func DriftReuseValues(reuse bool) DriftOption {
+	return func(opts *options) {
+		opts.driftReq.ReuseValues = reuse
+	}
+}
+
+// HooksVersion sets the revision whose hook executions are returned. The
+// latest revision is used by default.
+func HooksVersion(version int32) HooksOption {
+	return func(opts *options) {
+		opts.hooksReq.Version = version
+	}
+}
+
+// LabelsVersion sets the revision whose labels and annotations are returned.
+// The latest revision is used by default.
+func LabelsVersion(version int32) LabelsOption {
+	return func(opts *options) {
+		opts.labelsReq.Version = version
+	}
+}
+
+// LabelsChart previews the labels and annotations that the resources of a
+// new release would carry, by rendering ch with the raw values as an install
+// of the release into namespace would, instead of returning those of a
+// revision.
+func LabelsChart(ch *cpb.Chart, raw []byte, namespace string) LabelsOption {
+	return func(opts *options) {
+		opts.labelsReq.Chart = ch
+		opts.labelsReq.Values = &cpb.Config{Raw: string(raw)}
+		opts.labelsReq.Namespace = namespace
+	}
+}
+
+// DiscoveryCacheCluster sets the cluster whose discovery cache is
+// invalidated. Tiller's own cluster is used by default.
+func DiscoveryCacheCluster(cluster string) DiscoveryCacheOption {
+	return func(opts *options) {
+		opts.discoveryReq.Cluster = cluster
+	}
+}
+
+// ExportVersion sets the revision to export. The deployed revision is
+// exported by default.
+func ExportVersion(version int32) ExportOption {
+	return func(opts *options) {
+		opts.exportReq.Version = version
+	}
+}
+
+// ExportChart exports ch, rendered with the raw values for the namespace,
+// instead of a stored release.
+func ExportChart(ch *cpb.Chart, raw []byte, namespace string) ExportOption {
+	return func(opts *options) {
+		opts.exportReq.Chart = ch
+		opts.exportReq.Values = &cpb.Config{Raw: string(raw)}
+		opts.exportReq.Namespace = namespace
+	}
+}
+
+// ResumeTimeout specifies the number of seconds before kubernetes calls timeout
+func ResumeTimeout(timeout int64) ResumeOption {
+	return func(opts *options) {
+		opts.resumeReq.Timeout = timeout
+	}
+}
+
+// ResumeWait specifies whether or not to wait for all resources to be ready
+func ResumeWait(wait bool) ResumeOption {
+	return func(opts *options) {
+		opts.resumeReq.Wait = wait
+	}
+}
+
+// UpgradeKeepRemoved leaves resources that were removed from the chart in place during upgrade.
+func UpgradeKeepRemoved(keep bool) UpdateOption {
+	return func(opts *options) {
+		opts.updateReq.KeepRemoved = keep
+	}
+}
+
+// UpgradeSkipHooks skips the hooks with the given names during upgrade.
+func UpgradeSkipHooks(names []string) UpdateOption {
+	return func(opts *options) {
+		opts.updateReq.SkipHooks = names
+	}
+}
+
+// UpgradeSkipHookWeights skips the hooks with the given weights during upgrade.
+func UpgradeSkipHookWeights(weights []int32) UpdateOption {
+	return func(opts *options) {
+		opts.updateReq.SkipHookWeights = weights
+	}
+}
+
+// UpgradeSkipHookLookup will (if true) keep a dry run from looking up the
+// existing resources of hooks with the before-hook-creation delete policy.
+func UpgradeSkipHookLookup(skip bool) UpdateOption {
+	return func(opts *options) {
+		opts.updateReq.SkipHookLookup = skip
+	}
+}
+
 // UpgradeWait specifies whether or not to wait for all resources to be ready
 func UpgradeWait(wait bool) UpdateOption {
 	return func(opts *options) {
@@ -257,6 +720,27 @@ func DeleteDisableHooks(disable bool) DeleteOption {
 	}
 }
 
+// DeleteEnableHooks runs the hooks of a deletion even if Tiller skips them by default.
+func DeleteEnableHooks(enable bool) DeleteOption {
+	return func(opts *options) {
+		opts.uninstallReq.EnableHooks = enable
+	}
+}
+
+// DeleteSkipHooks skips the hooks with the given names during deletion.
+func DeleteSkipHooks(names []string) DeleteOption {
+	return func(opts *options) {
+		opts.uninstallReq.SkipHooks = names
+	}
+}
+
+// DeleteSkipHookWeights skips the hooks with the given weights during deletion.
+func DeleteSkipHookWeights(weights []int32) DeleteOption {
+	return func(opts *options) {
+		opts.uninstallReq.SkipHookWeights = weights
+	}
+}
+
 // DeleteDryRun will (if true) execute a deletion as a dry run.
 func DeleteDryRun(dry bool) DeleteOption {
 	return func(opts *options) {
@@ -271,6 +755,30 @@ func DeletePurge(purge bool) DeleteOption {
 	}
 }
 
+// DeletePropagationPolicy sets the deletion propagation policy, one of
+// "Foreground", "Background" or "Orphan", for the release's resources.
+func DeletePropagationPolicy(policy string) DeleteOption {
+	return func(opts *options) {
+		opts.uninstallReq.PropagationPolicy = policy
+	}
+}
+
+// DeleteWait waits until the release's resources are gone before the
+// deletion is reported complete.
+func DeleteWait(wait bool) DeleteOption {
+	return func(opts *options) {
+		opts.uninstallReq.Wait = wait
+	}
+}
+
+// DeleteHookResources also deletes the resources left behind by the hooks of
+// the release, except those with the keep resource policy.
+func DeleteHookResources(del bool) DeleteOption {
+	return func(opts *options) {
+		opts.uninstallReq.DeleteHookResources = del
+	}
+}
+
 // InstallDryRun will (if true) execute an installation as a dry run.
 func InstallDryRun(dry bool) InstallOption {
 	return func(opts *options) {
@@ -285,6 +793,77 @@ func InstallDisableHooks(disable bool) InstallOption {
 	}
 }
 
+// InstallEnableHooks runs the hooks of an installation even if Tiller skips them by default.
+func InstallEnableHooks(enable bool) InstallOption {
+	return func(opts *options) {
+		opts.instReq.EnableHooks = enable
+	}
+}
+
+// InstallTruncateName lets Tiller shorten the release name if its resources
+// would otherwise get names too long for Kubernetes.
+func InstallTruncateName(truncate bool) InstallOption {
+	return func(opts *options) {
+		opts.instReq.TruncateName = truncate
+	}
+}
+
+// InstallWaitLogBytes makes the wait of the install stream the end of the
+// logs of the containers that are not ready, in at most n bytes at a time, to
+// WatchWaitLogs.
+func InstallWaitLogBytes(n int64) InstallOption {
+	return func(opts *options) {
+		opts.instReq.WaitLogBytes = n
+	}
+}
+
+// InstallOnFailure sets what happens to an install that fails to apply some
+// of its resources: "keep" leaves it to be resumed with ResumeRelease, and
+// "revert" deletes the resources it applied.
+func InstallOnFailure(action string) InstallOption {
+	return func(opts *options) {
+		opts.instReq.OnFailure = action
+	}
+}
+
+// InstallCluster installs the release in the named cluster, out of those
+// Tiller is configured with, instead of Tiller's own.
+func InstallCluster(cluster string) InstallOption {
+	return func(opts *options) {
+		opts.instReq.Cluster = cluster
+	}
+}
+
+// InstallSystem marks the release as a system release, which listings leave
+// out unless asked for.
+func InstallSystem(system bool) InstallOption {
+	return func(opts *options) {
+		opts.instReq.System = system
+	}
+}
+
+// InstallSkipHooks skips the hooks with the given names during installation.
+func InstallSkipHooks(names []string) InstallOption {
+	return func(opts *options) {
+		opts.instReq.SkipHooks = names
+	}
+}
+
+// InstallSkipHookWeights skips the hooks with the given weights during installation.
+func InstallSkipHookWeights(weights []int32) InstallOption {
+	return func(opts *options) {
+		opts.instReq.SkipHookWeights = weights
+	}
+}
+
+// InstallSkipHookLookup will (if true) keep a dry run from looking up the
+// existing resources of hooks with the before-hook-creation delete policy.
+func InstallSkipHookLookup(skip bool) InstallOption {
+	return func(opts *options) {
+		opts.instReq.SkipHookLookup = skip
+	}
+}
+
 // InstallReuseName will (if true) instruct Tiller to re-use an existing name.
 func InstallReuseName(reuse bool) InstallOption {
 	return func(opts *options) {
@@ -299,6 +878,83 @@ func RollbackDisableHooks(disable bool) RollbackOption {
 	}
 }
 
+// RollbackEnableHooks runs the hooks of a rollback even if Tiller skips them by default.
+func RollbackEnableHooks(enable bool) RollbackOption {
+	return func(opts *options) {
+		opts.rollbackReq.EnableHooks = enable
+	}
+}
+
+// RollbackReRender will (if true) render the chart of the target revision
+// again instead of reusing its manifests.
+func RollbackReRender(rerender bool) RollbackOption {
+	return func(opts *options) {
+		opts.rollbackReq.ReRender = rerender
+	}
+}
+
+// RollbackSkipHookLookup will (if true) keep a dry run from looking up the
+// existing resources of hooks with the before-hook-creation delete policy.
+func RollbackSkipHookLookup(skip bool) RollbackOption {
+	return func(opts *options) {
+		opts.rollbackReq.SkipHookLookup = skip
+	}
+}
+
+// RollbackAllowDestructiveHooks will (if true) run the hooks annotated as
+// destructive, which a rollback skips otherwise.
+func RollbackAllowDestructiveHooks(allow bool) RollbackOption {
+	return func(opts *options) {
+		opts.rollbackReq.AllowDestructiveHooks = allow
+	}
+}
+
+// RollbackValueOverrides specifies values to merge over those of the target
+// revision. They require RollbackReRender.
+func RollbackValueOverrides(raw []byte) RollbackOption {
+	return func(opts *options) {
+		opts.rollbackReq.Values = &cpb.Config{Raw: string(raw)}
+	}
+}
+
+// RollbackSkipHooks skips the hooks with the given names during rollback.
+func RollbackSkipHooks(names []string) RollbackOption {
+	return func(opts *options) {
+		opts.rollbackReq.SkipHooks = names
+	}
+}
+
+// RollbackSkipHookWeights skips the hooks with the given weights during rollback.
+func RollbackSkipHookWeights(weights []int32) RollbackOption {
+	return func(opts *options) {
+		opts.rollbackReq.SkipHookWeights = weights
+	}
+}
+
+// RollbackGracePeriod specifies the number of seconds that the pods deleted
+// during the rollback get to shut down. Zero uses each pod's own grace period.
+func RollbackGracePeriod(seconds int64) RollbackOption {
+	return func(opts *options) {
+		opts.rollbackReq.GracePeriod = seconds
+	}
+}
+
+// RollbackPropagationPolicy specifies the deletion propagation policy of the
+// resources deleted during the rollback: "Foreground", "Background" or "Orphan".
+func RollbackPropagationPolicy(policy string) RollbackOption {
+	return func(opts *options) {
+		opts.rollbackReq.PropagationPolicy = policy
+	}
+}
+
+// RollbackRecreateOnSelectorChange will (if true) delete and recreate the
+// workloads whose label selector changed, leaving their pods running.
+func RollbackRecreateOnSelectorChange(recreate bool) RollbackOption {
+	return func(opts *options) {
+		opts.rollbackReq.RecreateOnSelectorChange = recreate
+	}
+}
+
 // RollbackDryRun will (if true) execute a rollback as a dry run.
 func RollbackDryRun(dry bool) RollbackOption {
 	return func(opts *options) {
@@ -320,6 +976,14 @@ func RollbackForce(force bool) RollbackOption {
 	}
 }
 
+// RollbackPartial will (if true) only revert the resources that the current,
+// failed release did not apply successfully.
+func RollbackPartial(partial bool) RollbackOption {
+	return func(opts *options) {
+		opts.rollbackReq.Partial = partial
+	}
+}
+
 // RollbackVersion sets the version of the release to deploy.
 func RollbackVersion(ver int32) RollbackOption {
 	return func(opts *options) {
@@ -327,6 +991,24 @@ func RollbackVersion(ver int32) RollbackOption {
 	}
 }
 
+// RollbackDeployedAt targets the revision that was deployed at t instead of a
+// version. A zero t leaves the version to target.
+func RollbackDeployedAt(t time.Time) RollbackOption {
+	return func(opts *options) {
+		if !t.IsZero() {
+			opts.rollbackReq.DeployedAt = timeconv.Timestamp(t)
+		}
+	}
+}
+
+// RollbackFromSnapshot applies the snapshot of the target revision, taken with
+// SnapshotRelease, instead of its manifest.
+func RollbackFromSnapshot(fromSnapshot bool) RollbackOption {
+	return func(opts *options) {
+		opts.rollbackReq.FromSnapshot = fromSnapshot
+	}
+}
+
 // UpgradeDisableHooks will disable hooks for an upgrade operation.
 func UpgradeDisableHooks(disable bool) UpdateOption {
 	return func(opts *options) {
@@ -334,6 +1016,39 @@ func UpgradeDisableHooks(disable bool) UpdateOption {
 	}
 }
 
+// UpgradeEnableHooks runs the hooks of an upgrade even if Tiller skips them by default.
+func UpgradeEnableHooks(enable bool) UpdateOption {
+	return func(opts *options) {
+		opts.updateReq.EnableHooks = enable
+	}
+}
+
+// UpgradeOnFailure sets what happens to an upgrade that fails to apply some
+// of its resources: "keep" leaves it to be resumed with ResumeRelease, and
+// "revert" takes the release back to the revision before.
+func UpgradeOnFailure(action string) UpdateOption {
+	return func(opts *options) {
+		opts.updateReq.OnFailure = action
+	}
+}
+
+// UpgradeWaitLogBytes makes the wait of the upgrade stream the end of the
+// logs of the containers that are not ready, in at most n bytes at a time, to
+// WatchWaitLogs.
+func UpgradeWaitLogBytes(n int64) UpdateOption {
+	return func(opts *options) {
+		opts.updateReq.WaitLogBytes = n
+	}
+}
+
+// UpgradeCluster makes the upgrade fail unless the release is installed in
+// the named cluster.
+func UpgradeCluster(cluster string) UpdateOption {
+	return func(opts *options) {
+		opts.updateReq.Cluster = cluster
+	}
+}
+
 // UpgradeDryRun will (if true) execute an upgrade as a dry run.
 func UpgradeDryRun(dry bool) UpdateOption {
 	return func(opts *options) {
@@ -393,6 +1108,14 @@ func StatusReleaseVersion(version int32) StatusOption {
 	}
 }
 
+// StatusOutputFormat makes Tiller render the status in the given format, in
+// the Rendered field of the response.
+func StatusOutputFormat(format rls.OutputFormat_Format) StatusOption {
+	return func(opts *options) {
+		opts.statusReq.OutputFormat = format
+	}
+}
+
 // DeleteOption allows setting optional attributes when
 // performing a UninstallRelease tiller rpc.
 type DeleteOption func(*options)
@@ -400,6 +1123,57 @@ type DeleteOption func(*options)
 // VersionOption -- TODO
 type VersionOption func(*options)
 
+// HealthOption allows configuring a GetHealth request.
+type HealthOption func(*options)
+
+// MigrationStatusOption allows configuring a MigrationStatus request.
+type MigrationStatusOption func(*options)
+
+// ForceUnlockOption allows configuring a ForceUnlock request.
+type ForceUnlockOption func(*options)
+
+// BatchInstallOption allows configuring a BatchInstall request.
+type BatchInstallOption func(*options)
+
+// RestoreOption allows configuring a RestoreRelease request.
+type RestoreOption func(*options)
+
+// InspectOption allows configuring an InspectChart request.
+type InspectOption func(*options)
+
+// AnnotateOption allows configuring an AnnotateRelease request.
+type AnnotateOption func(*options)
+
+// RestartOption allows configuring a RestartRelease request.
+type RestartOption func(*options)
+
+// ApproveOption allows configuring an ApproveRelease request.
+type ApproveOption func(*options)
+
+// RejectOption allows configuring a RejectRelease request.
+type RejectOption func(*options)
+
+// DriftOption allows configuring a GetReleaseDrift request.
+type DriftOption func(*options)
+
+// ExportOption allows configuring an ExportRelease request.
+type ExportOption func(*options)
+
+// ResumeOption allows configuring a ResumeRelease request.
+type ResumeOption func(*options)
+
+// SnapshotOption allows configuring a SnapshotRelease request.
+type SnapshotOption func(*options)
+
+// HooksOption allows configuring a GetHooks request.
+type HooksOption func(*options)
+
+// LabelsOption allows configuring a GetReleaseLabels request.
+type LabelsOption func(*options)
+
+// DiscoveryCacheOption allows configuring an InvalidateDiscoveryCache request.
+type DiscoveryCacheOption func(*options)
+
 // UpdateOption allows specifying various settings
 // configurable by the helm client user for overriding
 // the defaults used when running the `helm upgrade` command.
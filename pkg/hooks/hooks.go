@@ -26,6 +26,15 @@ const HookAnno = "helm.sh/hook"
 // HookWeightAnno is the label name for a hook weight
 const HookWeightAnno = "helm.sh/hook-weight"
 
+// HookDeleteAnno is the label name for the delete policies of a hook
+const HookDeleteAnno = "helm.sh/hook-delete-policy"
+
+// HookRetryAnno is the label name for the retry policy of a hook
+const HookRetryAnno = "helm.sh/hook-retry"
+
+// HookDestructiveAnno is the label name that marks a hook as destroying data
+const HookDestructiveAnno = "helm.sh/hook-destructive"
+
 // Types of hooks
 const (
 	PreInstall         = "pre-install"
@@ -40,6 +49,10 @@ const (
 	ReleaseTestFailure = "test-failure"
 )
 
+// BeforeHookCreation is the hook delete policy that deletes the resources
+// of a hook that exist already before the hook is created.
+const BeforeHookCreation = "before-hook-creation"
+
 // FilterTestHooks filters the list of hooks are returns only testing hooks.
 func FilterTestHooks(hooks []*release.Hook) []*release.Hook {
 	testHooks := []*release.Hook{}
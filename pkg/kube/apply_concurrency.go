@@ -0,0 +1,105 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kube // import "k8s.io/helm/pkg/kube"
+
+import (
+	"sync"
+
+	"k8s.io/kubernetes/pkg/kubectl/resource"
+)
+
+// applyByKind calls fn with the index of each of infos, in order of kind.
+//
+// The manifests of a release are sorted by kind, in install order, so each run
+// of consecutive resources of the same kind is independent of the others in
+// it, but may depend on those before it. The resources of a run are applied by
+// up to ApplyConcurrency calls at once, and the run is done before the next
+// one starts. Once fn fails, no more resources are started, the ones being
+// applied are waited for, and the first error is returned.
+func (c *Client) applyByKind(infos Result, fn func(int, *resource.Info) error) error {
+	for start := 0; start < len(infos); {
+		end := start + 1
+		kind := infos[start].Mapping.GroupVersionKind.GroupKind()
+		for end < len(infos) && infos[end].Mapping.GroupVersionKind.GroupKind() == kind {
+			end++
+		}
+		if err := c.applyConcurrently(infos, start, end, fn); err != nil {
+			return err
+		}
+		start = end
+	}
+	return nil
+}
+
+// applyConcurrently calls fn for infos[start:end], with up to
+// ApplyConcurrency calls at once.
+func (c *Client) applyConcurrently(infos Result, start, end int, fn func(int, *resource.Info) error) error {
+	if c.ApplyConcurrency <= 1 || end-start == 1 {
+		for i := start; i < end; i++ {
+			if err := fn(i, infos[i]); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	var (
+		mu    sync.Mutex
+		first error
+		wg    sync.WaitGroup
+	)
+	failed := func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+		return first != nil
+	}
+	slots := make(chan struct{}, c.ApplyConcurrency)
+	for i := start; i < end; i++ {
+		slots <- struct{}{}
+		if failed() {
+			break
+		}
+		wg.Add(1)
+		go func(i int) {
+			defer func() {
+				<-slots
+				wg.Done()
+			}()
+			if err := fn(i, infos[i]); err != nil {
+				mu.Lock()
+				if first == nil {
+					first = err
+				}
+				mu.Unlock()
+			}
+		}(i)
+	}
+	wg.Wait()
+	return first
+}
+
+// attemptedStatuses returns the statuses of the resources that were applied,
+// or attempted, in the order of the resources.
+func attemptedStatuses(results []*ApplyStatus) []ApplyStatus {
+	statuses := []ApplyStatus{}
+	for _, st := range results {
+		if st != nil {
+			statuses = append(statuses, *st)
+		}
+	}
+	return statuses
+}
@@ -0,0 +1,113 @@
+/*
+Copyright 2017 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kube
+
+import (
+	"errors"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/kubernetes/pkg/kubectl/resource"
+)
+
+func infosOfKinds(kinds ...string) Result {
+	var infos Result
+	for i, kind := range kinds {
+		infos = append(infos, &resource.Info{
+			Name:    string('a' + rune(i)),
+			Mapping: &meta.RESTMapping{GroupVersionKind: schema.GroupVersionKind{Version: "v1", Kind: kind}},
+		})
+	}
+	return infos
+}
+
+func TestApplyByKind(t *testing.T) {
+	infos := infosOfKinds("Namespace", "ConfigMap", "ConfigMap", "ConfigMap", "ConfigMap", "ConfigMap", "Deployment")
+	c := &Client{ApplyConcurrency: 3}
+
+	var (
+		mu               sync.Mutex
+		running, maxRuns int
+		order            []string
+	)
+	err := c.applyByKind(infos, func(i int, info *resource.Info) error {
+		mu.Lock()
+		running++
+		if running > maxRuns {
+			maxRuns = running
+		}
+		order = append(order, info.Mapping.GroupVersionKind.Kind)
+		mu.Unlock()
+
+		time.Sleep(20 * time.Millisecond)
+
+		mu.Lock()
+		running--
+		mu.Unlock()
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if maxRuns != 3 {
+		t.Errorf("Expected up to 3 resources to be applied at once, got %d", maxRuns)
+	}
+	// Kinds are applied one after the other.
+	expect := []string{"Namespace", "ConfigMap", "ConfigMap", "ConfigMap", "ConfigMap", "ConfigMap", "Deployment"}
+	if !reflect.DeepEqual(order, expect) {
+		t.Errorf("Expected kinds to be applied in order %v, got %v", expect, order)
+	}
+}
+
+func TestApplyByKindFailure(t *testing.T) {
+	infos := infosOfKinds("ConfigMap", "ConfigMap", "ConfigMap", "ConfigMap", "ConfigMap", "Deployment")
+	c := &Client{ApplyConcurrency: 2}
+
+	var mu sync.Mutex
+	started := map[string]bool{}
+	err := c.applyByKind(infos, func(i int, info *resource.Info) error {
+		mu.Lock()
+		started[info.Name] = true
+		mu.Unlock()
+		if info.Name == "a" {
+			return errors.New("quota exceeded")
+		}
+		time.Sleep(20 * time.Millisecond)
+		return nil
+	})
+	if err == nil || err.Error() != "quota exceeded" {
+		t.Errorf("Expected the first error, got %v", err)
+	}
+	// a fails while b is still being applied, so nothing else is started.
+	for _, name := range []string{"c", "d", "e", "f"} {
+		if started[name] {
+			t.Errorf("Expected %s not to be applied after the failure", name)
+		}
+	}
+}
+
+func TestAttemptedStatuses(t *testing.T) {
+	results := []*ApplyStatus{{Name: "a"}, nil, {Name: "c"}}
+	expect := []ApplyStatus{{Name: "a"}, {Name: "c"}}
+	if got := attemptedStatuses(results); !reflect.DeepEqual(got, expect) {
+		t.Errorf("Expected %v, got %v", expect, got)
+	}
+}
@@ -18,7 +18,6 @@ package kube // import "k8s.io/helm/pkg/kube"
 
 import (
 	"bytes"
-	"encoding/json"
 	goerrors "errors"
 	"fmt"
 	"io"
@@ -26,16 +25,13 @@ import (
 	"strings"
 	"time"
 
-	jsonpatch "github.com/evanphx/json-patch"
 	"k8s.io/apimachinery/pkg/api/errors"
-	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/fields"
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
-	"k8s.io/apimachinery/pkg/util/strategicpatch"
 	"k8s.io/apimachinery/pkg/watch"
 	"k8s.io/client-go/tools/clientcmd"
 	"k8s.io/kubernetes/pkg/api"
@@ -54,21 +50,94 @@ import (
 // ErrNoObjectsVisited indicates that during a visit operation, no matching objects were found.
 var ErrNoObjectsVisited = goerrors.New("no objects visited")
 
+// ApplyStatus records the outcome of applying a single resource during Create
+// or Update.
+type ApplyStatus struct {
+	Kind string
+	Name string
+	// Recreated is set if the resource belonged to the previous release but
+	// had been deleted outside of Helm, and was created again.
+	Recreated bool
+	// Err is nil if the resource was created or updated successfully.
+	Err error
+}
+
+// ApplyError is returned by Create and Update when one or more resources could
+// not be applied.
+//
+// Statuses lists every resource they attempted to apply, in order. Resources
+// that they never got to are not listed.
+type ApplyError struct {
+	Statuses []ApplyStatus
+	// Err describes why the update failed.
+	Err error
+}
+
+func (e *ApplyError) Error() string {
+	return e.Err.Error()
+}
+
 // Client represents a client capable of communicating with the Kubernetes API.
 type Client struct {
 	cmdutil.Factory
 	// SchemaCacheDir is the path for loading cached schema.
 	SchemaCacheDir string
+	// ReadinessGates are custom conditions that a wait also holds for.
+	ReadinessGates []ReadinessGate
+	// ReadyThresholds define, per kind of workload, the share of its pods
+	// that must be ready for a wait to consider it ready.
+	ReadyThresholds []ReadyThreshold
+	// WaitForWebhooks makes Create pause after each admission webhook
+	// configuration until the services backing it have ready endpoints.
+	WaitForWebhooks bool
+	// HPAStabilization makes a wait also hold until each
+	// HorizontalPodAutoscaler has had as many replicas as it desires, without
+	// change, for this long. Zero disables the check.
+	HPAStabilization time.Duration
+	// WaitLogBytes makes a wait that times out report the end of the logs
+	// of the containers that are not ready, in at most this many bytes.
+	// Zero disables it.
+	WaitLogBytes int64
+	// WaitLogSink, if set, is given the end of the logs of the containers
+	// that are not ready while a wait goes on, every WaitLogInterval, in at
+	// most WaitLogBytes, so that they can be followed before the wait ends.
+	// Logs that did not change since they were last given are not given
+	// again.
+	WaitLogSink func(logs string)
+	// PatchStrategy is how resources are patched when they are updated,
+	// unless their PatchStrategyAnno annotation says otherwise: StrategicPatch,
+	// MergePatch or ThreeWayPatch. Empty is StrategicPatch. Custom resources
+	// get a JSON merge patch unless it is ThreeWayPatch.
+	PatchStrategy string
+	// FailOnMissing makes Update fail on resources of the previous release
+	// that were deleted outside of Helm, instead of creating them again.
+	FailOnMissing bool
+	// ApplyConcurrency is how many resources of the same kind Create and
+	// Update apply at once. Resources of different kinds are still applied
+	// one kind after the other, in the order of the manifest. Zero or one
+	// applies them one at a time.
+	ApplyConcurrency int
+	// FieldManager names the manager of the fields Helm sets. It identifies
+	// Helm's side of a conflict in a ConflictError. Defaults to "helm".
+	FieldManager string
 
 	Log func(string, ...interface{})
+
+	// config is the client config the Factory was created with, if any.
+	config clientcmd.ClientConfig
+	// impersonations are the factories of the identities the client has
+	// impersonated, shared by its copies.
+	impersonations *impersonations
 }
 
 // New create a new Client
 func New(config clientcmd.ClientConfig) *Client {
 	return &Client{
 		Factory:        cmdutil.NewFactory(config),
+		config:         config,
 		SchemaCacheDir: clientcmd.RecommendedSchemaFile,
 		Log:            func(_ string, _ ...interface{}) {},
+		impersonations: &impersonations{},
 	}
 }
 
@@ -78,7 +147,9 @@ type ResourceActorFunc func(*resource.Info) error
 // Create creates kubernetes resources from an io.reader
 //
 // Namespace will set the namespace
-func (c *Client) Create(namespace string, reader io.Reader, timeout int64, shouldWait bool) error {
+func (c *Client) Create(namespace string, reader io.Reader, timeout int64, shouldWait bool) (err error) {
+	defer observe("create", time.Now(), &err)
+
 	client, err := c.ClientSet()
 	if err != nil {
 		return err
@@ -90,7 +161,7 @@ func (c *Client) Create(namespace string, reader io.Reader, timeout int64, shoul
 	if buildErr != nil {
 		return buildErr
 	}
-	if err := perform(infos, createResource); err != nil {
+	if err := c.createResources(infos, time.Duration(timeout)*time.Second); err != nil {
 		return err
 	}
 	if shouldWait {
@@ -148,7 +219,9 @@ func (c *Client) Build(namespace string, reader io.Reader) (Result, error) {
 // Get gets kubernetes resources as pretty printed string
 //
 // Namespace will set the namespace
-func (c *Client) Get(namespace string, reader io.Reader) (string, error) {
+func (c *Client) Get(namespace string, reader io.Reader) (_ string, err error) {
+	defer observe("get", time.Now(), &err)
+
 	// Since we don't know what order the objects come in, let's group them by the types, so
 	// that when we print them, they come looking good (headers apply to subgroups, etc.)
 	objs := make(map[string][]runtime.Object)
@@ -212,6 +285,81 @@ func (c *Client) Get(namespace string, reader io.Reader) (string, error) {
 //
 // Namespace will set the namespaces
 func (c *Client) Update(namespace string, originalReader, targetReader io.Reader, force bool, recreate bool, timeout int64, shouldWait bool) error {
+	return c.UpdateWithOptions(namespace, originalReader, targetReader, UpdateOptions{
+		Force:    force,
+		Recreate: recreate,
+		Timeout:  timeout,
+		Wait:     shouldWait,
+	})
+}
+
+// UpdateOptions configure UpdateWithOptions.
+type UpdateOptions struct {
+	// Force replaces resources that cannot be patched by deleting and
+	// recreating them.
+	Force bool
+	// Recreate deletes the pods of the updated workloads, so that their
+	// controllers recreate them.
+	Recreate bool
+	// Timeout is the number of seconds to wait for the resources to be
+	// ready if Wait is set.
+	Timeout int64
+	Wait    bool
+	// GracePeriod is the number of seconds that deleted pods get to shut
+	// down. Zero uses the termination grace period of each pod.
+	GracePeriod int64
+	// PropagationPolicy is the deletion propagation policy of the resources
+	// that are deleted: "Foreground", "Background" or "Orphan". When it is
+	// empty, resources are deleted as kubectl would delete them.
+	PropagationPolicy string
+	// RecreateOnSelectorChange deletes and recreates a workload whose patch
+	// is rejected only because it changes the workload's label selector or
+	// pod template labels. The workload is deleted with the Orphan policy,
+	// so its pods keep running until the new workload takes them over.
+	RecreateOnSelectorChange bool
+}
+
+// deleteOptions returns the options to delete resources and pods with, or
+// nil if the defaults apply.
+func (o UpdateOptions) deleteOptions() (*metav1.DeleteOptions, error) {
+	if o.GracePeriod < 0 {
+		return nil, fmt.Errorf("invalid grace period %d: must not be negative", o.GracePeriod)
+	}
+	if o.GracePeriod == 0 && o.PropagationPolicy == "" {
+		return nil, nil
+	}
+	opts := &metav1.DeleteOptions{}
+	if o.GracePeriod > 0 {
+		grace := o.GracePeriod
+		opts.GracePeriodSeconds = &grace
+	}
+	if o.PropagationPolicy != "" {
+		p, err := ParsePropagationPolicy(o.PropagationPolicy)
+		if err != nil {
+			return nil, err
+		}
+		opts.PropagationPolicy = &p
+	}
+	return opts, nil
+}
+
+// Validate checks the grace period and propagation policy of o.
+func (o UpdateOptions) Validate() error {
+	_, err := o.deleteOptions()
+	return err
+}
+
+// UpdateWithOptions updates resources like Update does. The grace period and
+// propagation policy of opts apply to everything it deletes: resources that
+// were removed from the target configuration, resources that Force replaces
+// and pods that Recreate restarts.
+func (c *Client) UpdateWithOptions(namespace string, originalReader, targetReader io.Reader, opts UpdateOptions) (err error) {
+	defer observe("update", time.Now(), &err)
+
+	delOpts, err := opts.deleteOptions()
+	if err != nil {
+		return err
+	}
 	original, err := c.BuildUnstructured(namespace, originalReader)
 	if err != nil {
 		return fmt.Errorf("failed decoding reader into objects: %s", err)
@@ -222,57 +370,90 @@ func (c *Client) Update(namespace string, originalReader, targetReader io.Reader
 		return fmt.Errorf("failed decoding reader into objects: %s", err)
 	}
 
-	updateErrors := []string{}
-
-	err = target.Visit(func(info *resource.Info, err error) error {
-		if err != nil {
-			return err
+	// Resources of the same kind may be applied concurrently, so each records
+	// its outcome under its own index.
+	results := make([]*ApplyStatus, len(target))
+	updateErrors := make([]string, len(target))
+	err = c.applyByKind(target, func(i int, info *resource.Info) error {
+		applied := func(info *resource.Info, recreated bool, err error) {
+			results[i] = &ApplyStatus{Kind: info.Mapping.GroupVersionKind.Kind, Name: info.Name, Recreated: recreated, Err: err}
 		}
 
+		kind := info.Mapping.GroupVersionKind.Kind
+		originalInfo := original.Get(info)
 		helper := resource.NewHelper(info.Client, info.Mapping)
-		if _, err := helper.Get(info.Namespace, info.Name, info.Export); err != nil {
+		live, err := helper.Get(info.Namespace, info.Name, info.Export)
+		if err != nil {
 			if !errors.IsNotFound(err) {
-				return fmt.Errorf("Could not get information about the resource: err: %s", err)
+				err = fmt.Errorf("Could not get information about the resource: err: %s", err)
+				applied(info, false, err)
+				return err
+			}
+			// A resource of the previous release that does not exist was
+			// deleted outside of Helm.
+			missing := originalInfo != nil
+			if missing && c.FailOnMissing {
+				err := fmt.Errorf("%s %q of the release was deleted outside of Helm", kind, info.Name)
+				applied(info, false, err)
+				return err
 			}
 
 			// Since the resource does not exist, create it.
 			if err := createResource(info); err != nil {
-				return fmt.Errorf("failed to create resource: %s", err)
+				err = fmt.Errorf("failed to create resource: %s", err)
+				applied(info, false, err)
+				return err
 			}
+			applied(info, missing, nil)
 
-			kind := info.Mapping.GroupVersionKind.Kind
-			c.Log("Created a new %s called %q\n", kind, info.Name)
+			if missing {
+				c.Log("Re-created %s %q, which was deleted outside of Helm", kind, info.Name)
+			} else {
+				c.Log("Created a new %s called %q\n", kind, info.Name)
+			}
 			return nil
 		}
 
-		originalInfo := original.Get(info)
 		if originalInfo == nil {
-			return fmt.Errorf("no resource with the name %q found", info.Name)
+			err := fmt.Errorf("no resource with the name %q found", info.Name)
+			applied(info, false, err)
+			return err
 		}
 
-		if err := updateResource(c, info, originalInfo.Object, force, recreate); err != nil {
+		recreated, err := updateResource(c, info, originalInfo.Object, live, opts, delOpts)
+		if err != nil {
 			c.Log("error updating the resource %q:\n\t %v", info.Name, err)
-			updateErrors = append(updateErrors, err.Error())
+			updateErrors[i] = err.Error()
+			applied(info, false, err)
+			return nil
 		}
+		applied(info, recreated, nil)
 
 		return nil
 	})
 
+	statuses := attemptedStatuses(results)
+	failed := []string{}
+	for _, e := range updateErrors {
+		if e != "" {
+			failed = append(failed, e)
+		}
+	}
 	switch {
 	case err != nil:
-		return err
-	case len(updateErrors) != 0:
-		return fmt.Errorf(strings.Join(updateErrors, " && "))
+		return &ApplyError{Statuses: statuses, Err: err}
+	case len(failed) != 0:
+		return &ApplyError{Statuses: statuses, Err: fmt.Errorf(strings.Join(failed, " && "))}
 	}
 
 	for _, info := range original.Difference(target) {
 		c.Log("Deleting %q in %s...", info.Name, info.Namespace)
-		if err := deleteResource(c, info); err != nil {
+		if err := deleteResource(c, info, delOpts); err != nil {
 			c.Log("Failed to delete %q, err: %s", info.Name, err)
 		}
 	}
-	if shouldWait {
-		return c.waitForResources(time.Duration(timeout)*time.Second, target)
+	if opts.Wait {
+		return c.waitForResources(time.Duration(opts.Timeout)*time.Second, target)
 	}
 	return nil
 }
@@ -281,15 +462,7 @@ func (c *Client) Update(namespace string, originalReader, targetReader io.Reader
 //
 // Namespace will set the namespace
 func (c *Client) Delete(namespace string, reader io.Reader) error {
-	infos, err := c.BuildUnstructured(namespace, reader)
-	if err != nil {
-		return err
-	}
-	return perform(infos, func(info *resource.Info) error {
-		c.Log("Starting delete for %q %s", info.Name, info.Mapping.GroupVersionKind.Kind)
-		err := deleteResource(c, info)
-		return c.skipIfNotFound(err)
-	})
+	return c.DeleteWithPolicy(namespace, reader, "", 0, false)
 }
 
 func (c *Client) skipIfNotFound(err error) error {
@@ -318,14 +491,22 @@ func (c *Client) watchTimeout(t time.Duration) ResourceActorFunc {
 //   ascertained by watching the Status fields in a job's output.
 //
 // Handling for other kinds will be added as necessary.
-func (c *Client) WatchUntilReady(namespace string, reader io.Reader, timeout int64, shouldWait bool) error {
+//
+// If the client's WaitLogBytes is set, a Pod or Job that fails or times out
+// is reported as a HookError with the end of the logs of its pods.
+func (c *Client) WatchUntilReady(namespace string, reader io.Reader, timeout int64, shouldWait bool) (err error) {
+	defer observe("watch", time.Now(), &err)
+
 	infos, err := c.Build(namespace, reader)
 	if err != nil {
 		return err
 	}
 	// For jobs, there's also the option to do poll c.Jobs(namespace).Get():
 	// https://github.com/adamreese/kubernetes/blob/master/test/e2e/job.go#L291-L300
-	return perform(infos, c.watchTimeout(time.Duration(timeout)*time.Second))
+	if err := perform(infos, c.watchTimeout(time.Duration(timeout)*time.Second)); err != nil {
+		return c.withHookLogs(err, infos)
+	}
+	return nil
 }
 
 func perform(infos Result, fn ResourceActorFunc) error {
@@ -349,62 +530,46 @@ func createResource(info *resource.Info) error {
 	return info.Refresh(obj, true)
 }
 
-func deleteResource(c *Client, info *resource.Info) error {
+// deleteResource deletes a resource. opts, if set, sets the grace period and
+// propagation policy; with a propagation policy, the API server rather than a
+// reaper deletes the resource's dependents.
+func deleteResource(c *Client, info *resource.Info, opts *metav1.DeleteOptions) error {
+	helper := resource.NewHelper(info.Client, info.Mapping)
+	if opts != nil && opts.PropagationPolicy != nil {
+		return helper.DeleteWithOptions(info.Namespace, info.Name, opts)
+	}
 	reaper, err := c.Reaper(info.Mapping)
 	if err != nil {
 		// If there is no reaper for this resources, delete it.
 		if kubectl.IsNoSuchReaperError(err) {
-			return resource.NewHelper(info.Client, info.Mapping).Delete(info.Namespace, info.Name)
+			if opts != nil {
+				return helper.DeleteWithOptions(info.Namespace, info.Name, opts)
+			}
+			return helper.Delete(info.Namespace, info.Name)
 		}
 		return err
 	}
 	c.Log("Using reaper for deleting %q", info.Name)
-	return reaper.Stop(info.Namespace, info.Name, 0, nil)
+	return reaper.Stop(info.Namespace, info.Name, 0, opts)
 }
 
-func createPatch(mapping *meta.RESTMapping, target, current runtime.Object) ([]byte, types.PatchType, error) {
-	oldData, err := json.Marshal(current)
+func updateResource(c *Client, target *resource.Info, currentObj, liveObj runtime.Object, opts UpdateOptions, delOpts *metav1.DeleteOptions) (recreated bool, err error) {
+	strategy, err := patchStrategy(target.Object, c.PatchStrategy)
 	if err != nil {
-		return nil, types.StrategicMergePatchType, fmt.Errorf("serializing current configuration: %s", err)
+		return false, err
 	}
-	newData, err := json.Marshal(target)
+	patch, patchType, err := createPatch(target.Mapping, target.Object, currentObj, liveObj, strategy)
 	if err != nil {
-		return nil, types.StrategicMergePatchType, fmt.Errorf("serializing target configuration: %s", err)
-	}
-
-	if api.Semantic.DeepEqual(oldData, newData) {
-		return nil, types.StrategicMergePatchType, nil
-	}
-
-	// Get a versioned object
-	versionedObject, err := api.Scheme.New(mapping.GroupVersionKind)
-	switch {
-	case runtime.IsNotRegisteredError(err):
-		// fall back to generic JSON merge patch
-		patch, err := jsonpatch.CreateMergePatch(oldData, newData)
-		return patch, types.MergePatchType, err
-	case err != nil:
-		return nil, types.StrategicMergePatchType, fmt.Errorf("failed to get versionedObject: %s", err)
-	default:
-		log.Printf("generating strategic merge patch for %T", target)
-		patch, err := strategicpatch.CreateTwoWayMergePatch(oldData, newData, versionedObject)
-		return patch, types.StrategicMergePatchType, err
-	}
-}
-
-func updateResource(c *Client, target *resource.Info, currentObj runtime.Object, force bool, recreate bool) error {
-	patch, patchType, err := createPatch(target.Mapping, target.Object, currentObj)
-	if err != nil {
-		return fmt.Errorf("failed to create patch: %s", err)
+		return false, fmt.Errorf("failed to create patch: %s", err)
 	}
 	if patch == nil {
 		c.Log("Looks like there are no changes for %s %q", target.Mapping.GroupVersionKind.Kind, target.Name)
 		// This needs to happen to make sure that tiller has the latest info from the API
 		// Otherwise there will be no labels and other functions that use labels will panic
 		if err := target.Get(); err != nil {
-			return fmt.Errorf("error trying to refresh resource information: %v", err)
+			return false, fmt.Errorf("error trying to refresh resource information: %v", err)
 		}
-		return nil
+		return false, nil
 	}
 
 	// send patch to server
@@ -415,51 +580,66 @@ func updateResource(c *Client, target *resource.Info, currentObj runtime.Object,
 		kind := target.Mapping.GroupVersionKind.Kind
 		log.Printf("Cannot patch %s: %q (%v)", kind, target.Name, err)
 
-		if force {
+		switch {
+		case errors.IsNotFound(err) && !c.FailOnMissing:
+			// The resource was deleted outside of Helm since it was read.
+			if err := createResource(target); err != nil {
+				return recreated, fmt.Errorf("failed to re-create resource: %s", err)
+			}
+			c.Log("Re-created %s %q, which was deleted outside of Helm", kind, target.Name)
+			recreated = true
+		case opts.Force:
 			// Attempt to delete...
-			if err := deleteResource(c, target); err != nil {
-				return err
+			if err := deleteResource(c, target, delOpts); err != nil {
+				return recreated, err
 			}
 			log.Printf("Deleted %s: %q", kind, target.Name)
 
 			// ... and recreate
 			if err := createResource(target); err != nil {
-				return fmt.Errorf("Failed to recreate resource: %s", err)
+				return recreated, fmt.Errorf("Failed to recreate resource: %s", err)
 			}
 			log.Printf("Created a new %s called %q\n", kind, target.Name)
 
 			// No need to refresh the target, as we recreated the resource based
 			// on it. In addition, it might not exist yet and a call to `Refresh`
 			// may fail.
-		} else {
+		case opts.RecreateOnSelectorChange && isSelectorChange(err):
+			if err := c.recreateOrphaning(target, time.Duration(opts.Timeout)*time.Second); err != nil {
+				return recreated, err
+			}
+		default:
 			log.Print("Use --force to force recreation of the resource")
-			return err
+			if errors.IsConflict(err) {
+				return recreated, c.conflictReport(target, currentObj, patch, err)
+			}
+			return recreated, err
 		}
 	} else {
 		// When patch succeeds without needing to recreate, refresh target.
 		target.Refresh(obj, true)
 	}
 
-	if !recreate {
-		return nil
+	if !opts.Recreate {
+		return recreated, nil
 	}
 
 	versioned, err := c.AsVersionedObject(target.Object)
 	if runtime.IsNotRegisteredError(err) {
-		return nil
+		return recreated, nil
 	}
 	if err != nil {
-		return err
+		return recreated, err
 	}
 
 	selector, err := getSelectorFromObject(versioned)
 	if err != nil {
-		return nil
+		return recreated, nil
 	}
 
 	client, err := c.ClientSet()
 	if err != nil {
-		return err
+		return recreated, err
 	}
 
 	pods, err := client.Core().Pods(target.Namespace).List(metav1.ListOptions{
@@ -467,7 +647,7 @@ func updateResource(c *Client, target *resource.Info, currentObj runtime.Object,
 		LabelSelector: labels.Set(selector).AsSelector().String(),
 	})
 	if err != nil {
-		return err
+		return recreated, err
 	}
 
 	// Restart pods
@@ -475,11 +655,22 @@ func updateResource(c *Client, target *resource.Info, currentObj runtime.Object,
 		c.Log("Restarting pod: %v/%v", pod.Namespace, pod.Name)
 
 		// Delete each pod for get them restarted with changed spec.
-		if err := client.Core().Pods(pod.Namespace).Delete(pod.Name, metav1.NewPreconditionDeleteOptions(string(pod.UID))); err != nil {
-			return err
+		if err := client.Core().Pods(pod.Namespace).Delete(pod.Name, podDeleteOptions(pod.UID, delOpts)); err != nil {
+			return recreated, err
 		}
 	}
-	return nil
+	return recreated, nil
+}
+
+// podDeleteOptions returns the options to delete the pod with the given UID
+// with: the grace period and propagation policy of opts, if it is set.
+func podDeleteOptions(uid types.UID, opts *metav1.DeleteOptions) *metav1.DeleteOptions {
+	o := metav1.NewPreconditionDeleteOptions(string(uid))
+	if opts != nil {
+		o.GracePeriodSeconds = opts.GracePeriodSeconds
+		o.PropagationPolicy = opts.PropagationPolicy
+	}
+	return o
 }
 
 func getSelectorFromObject(obj runtime.Object) (map[string]string, error) {
@@ -589,7 +780,9 @@ func scrubValidationError(err error) error {
 
 // WaitAndGetCompletedPodPhase waits up to a timeout until a pod enters a completed phase
 // and returns said phase (PodSucceeded or PodFailed qualify)
-func (c *Client) WaitAndGetCompletedPodPhase(namespace string, reader io.Reader, timeout time.Duration) (api.PodPhase, error) {
+func (c *Client) WaitAndGetCompletedPodPhase(namespace string, reader io.Reader, timeout time.Duration) (_ api.PodPhase, err error) {
+	defer observe("wait_pod", time.Now(), &err)
+
 	infos, err := c.Build(namespace, reader)
 	if err != nil {
 		return api.PodUnknown, err
@@ -23,6 +23,7 @@ import (
 	"io"
 	"io/ioutil"
 	"net/http"
+	"reflect"
 	"strings"
 	"testing"
 	"time"
@@ -101,10 +102,12 @@ func newResponse(code int, obj runtime.Object) (*http.Response, error) {
 
 type fakeReaper struct {
 	name string
+	opts *metav1.DeleteOptions
 }
 
 func (r *fakeReaper) Stop(namespace, name string, timeout time.Duration, gracePeriod *metav1.DeleteOptions) error {
 	r.name = name
+	r.opts = gracePeriod
 	return nil
 }
 
@@ -230,6 +233,173 @@ func TestUpdate(t *testing.T) {
 
 }
 
+func TestUpdateRecreatesMissingResources(t *testing.T) {
+	listA := newPodList("starfish", "otter")
+	listB := newPodList("starfish", "otter")
+	listB.Items[1].Spec.Containers[0].Ports = []api.ContainerPort{{Name: "https", ContainerPort: 443}}
+
+	var actions []string
+	f, tf, codec, _ := cmdtesting.NewAPIFactory()
+	tf.UnstructuredClient = &fake.RESTClient{
+		APIRegistry:          api.Registry,
+		NegotiatedSerializer: dynamic.ContentConfig().NegotiatedSerializer,
+		Client: fake.CreateHTTPClient(func(req *http.Request) (*http.Response, error) {
+			p, m := req.URL.Path, req.Method
+			actions = append(actions, p+":"+m)
+			switch {
+			case p == "/namespaces/default/pods/starfish" && m == "GET":
+				return newResponse(404, notFoundBody())
+			case p == "/namespaces/default/pods/otter" && m == "GET":
+				return newResponse(200, &listA.Items[1])
+			case p == "/namespaces/default/pods/otter" && m == "PATCH":
+				// otter is deleted between being read and being patched.
+				return newResponse(404, notFoundBody())
+			case p == "/namespaces/default/pods" && m == "POST":
+				data, err := ioutil.ReadAll(req.Body)
+				if err != nil {
+					t.Fatalf("could not read request: %s", err)
+				}
+				if strings.Contains(string(data), `"name":"otter"`) {
+					return newResponse(201, &listB.Items[1])
+				}
+				return newResponse(201, &listB.Items[0])
+			}
+			t.Fatalf("unexpected request: %s %s", req.Method, req.URL.Path)
+			return nil, nil
+		}),
+	}
+
+	var logs []string
+	c := newTestClient(f)
+	c.Log = func(format string, v ...interface{}) {
+		logs = append(logs, fmt.Sprintf(format, v...))
+	}
+	if err := c.Update(api.NamespaceDefault, objBody(codec, &listA), objBody(codec, &listB), false, false, 0, false); err != nil {
+		t.Fatal(err)
+	}
+	expectedActions := []string{
+		"/namespaces/default/pods/starfish:GET",
+		"/namespaces/default/pods:POST",
+		"/namespaces/default/pods/otter:GET",
+		"/namespaces/default/pods/otter:PATCH",
+		"/namespaces/default/pods:POST",
+	}
+	if !reflect.DeepEqual(actions, expectedActions) {
+		t.Errorf("expected requests %v, got %v", expectedActions, actions)
+	}
+	for _, name := range []string{"starfish", "otter"} {
+		expect := fmt.Sprintf("Re-created Pod %q, which was deleted outside of Helm", name)
+		found := false
+		for _, l := range logs {
+			found = found || l == expect
+		}
+		if !found {
+			t.Errorf("expected %q to be logged, got %v", expect, logs)
+		}
+	}
+
+	actions = nil
+	c.FailOnMissing = true
+	err := c.Update(api.NamespaceDefault, objBody(codec, &listA), objBody(codec, &listB), false, false, 0, false)
+	ae, ok := err.(*ApplyError)
+	if !ok {
+		t.Fatalf("expected an ApplyError, got %v", err)
+	}
+	if expect := `Pod "starfish" of the release was deleted outside of Helm`; ae.Err.Error() != expect {
+		t.Errorf("expected %q, got %q", expect, ae.Err)
+	}
+	if len(actions) != 1 {
+		t.Errorf("expected nothing to be created, got requests %v", actions)
+	}
+}
+
+func TestUpdateWithOptions(t *testing.T) {
+	listA := newPodList("otter", "squid")
+	listB := newPodList("otter")
+	var deleteBody string
+
+	f, tf, codec, _ := cmdtesting.NewAPIFactory()
+	tf.UnstructuredClient = &fake.RESTClient{
+		APIRegistry:          api.Registry,
+		NegotiatedSerializer: dynamic.ContentConfig().NegotiatedSerializer,
+		Client: fake.CreateHTTPClient(func(req *http.Request) (*http.Response, error) {
+			p, m := req.URL.Path, req.Method
+			switch {
+			case p == "/namespaces/default/pods/otter" && m == "GET":
+				return newResponse(200, &listA.Items[0])
+			case p == "/namespaces/default/pods/squid" && m == "DELETE":
+				data, err := ioutil.ReadAll(req.Body)
+				if err != nil {
+					t.Fatalf("could not read request: %s", err)
+				}
+				deleteBody = string(data)
+				return newResponse(200, &listA.Items[1])
+			}
+			t.Fatalf("unexpected request: %s %s", req.Method, req.URL.Path)
+			return nil, nil
+		}),
+	}
+
+	reaper := &fakeReaper{}
+	c := newTestClient(&fakeReaperFactory{Factory: f, reaper: reaper})
+
+	// A grace period alone is passed to the reaper.
+	opts := UpdateOptions{GracePeriod: 30}
+	if err := c.UpdateWithOptions(api.NamespaceDefault, objBody(codec, &listA), objBody(codec, &listB), opts); err != nil {
+		t.Fatal(err)
+	}
+	if reaper.name != "squid" {
+		t.Errorf("expected the reaper to delete squid, got %q", reaper.name)
+	}
+	if reaper.opts == nil || reaper.opts.GracePeriodSeconds == nil || *reaper.opts.GracePeriodSeconds != 30 {
+		t.Errorf("expected the reaper to be given a grace period of 30 seconds, got %+v", reaper.opts)
+	}
+
+	// With a propagation policy, the API server deletes the resource.
+	reaper.name = ""
+	opts = UpdateOptions{GracePeriod: 30, PropagationPolicy: "Foreground"}
+	if err := c.UpdateWithOptions(api.NamespaceDefault, objBody(codec, &listA), objBody(codec, &listB), opts); err != nil {
+		t.Fatal(err)
+	}
+	if reaper.name != "" {
+		t.Errorf("expected no reaper to be used with a propagation policy, got %q", reaper.name)
+	}
+	for _, expect := range []string{`"gracePeriodSeconds":30`, `"propagationPolicy":"Foreground"`} {
+		if !strings.Contains(deleteBody, expect) {
+			t.Errorf("expected the delete options to contain %s, got %s", expect, deleteBody)
+		}
+	}
+
+	opts = UpdateOptions{GracePeriod: -1}
+	if err := c.UpdateWithOptions(api.NamespaceDefault, objBody(codec, &listA), objBody(codec, &listB), opts); err == nil {
+		t.Error("expected an error for a negative grace period")
+	}
+}
+
+func TestUpdateOptionsDeleteOptions(t *testing.T) {
+	if opts, err := (UpdateOptions{Force: true, Recreate: true}).deleteOptions(); err != nil || opts != nil {
+		t.Errorf("expected no delete options by default, got %+v (%v)", opts, err)
+	}
+	if err := (UpdateOptions{PropagationPolicy: "Sideways"}).Validate(); err == nil {
+		t.Error("expected an error for an invalid propagation policy")
+	}
+
+	opts, err := UpdateOptions{GracePeriod: 10, PropagationPolicy: "Orphan"}.deleteOptions()
+	if err != nil {
+		t.Fatal(err)
+	}
+	pod := podDeleteOptions("1234", opts)
+	if pod.Preconditions == nil || *pod.Preconditions.UID != "1234" {
+		t.Errorf("expected the pod UID as precondition, got %+v", pod.Preconditions)
+	}
+	if *pod.GracePeriodSeconds != 10 || *pod.PropagationPolicy != metav1.DeletePropagationOrphan {
+		t.Errorf("expected a grace period of 10 and orphan propagation, got %d and %s", *pod.GracePeriodSeconds, *pod.PropagationPolicy)
+	}
+	if pod := podDeleteOptions("1234", nil); pod.GracePeriodSeconds != nil || pod.PropagationPolicy != nil {
+		t.Errorf("expected the pod's own grace period to apply, got %+v", pod)
+	}
+}
+
 func TestBuild(t *testing.T) {
 	tests := []struct {
 		name        string
@@ -284,6 +454,35 @@ func TestBuild(t *testing.T) {
 	}
 }
 
+// Resources that declare a namespace must be applied to, and deleted from,
+// that namespace rather than the release's.
+func TestBuildKeepsDeclaredNamespaces(t *testing.T) {
+	manifest := `apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: agent
+  namespace: monitoring
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: settings
+`
+	f, _, _, _ := cmdtesting.NewAPIFactory()
+	c := newTestClient(f)
+	infos, err := c.Build("test", strings.NewReader(manifest))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, info := range infos {
+		got = append(got, info.Namespace+"/"+info.Name)
+	}
+	if expect := []string{"monitoring/agent", "test/settings"}; !reflect.DeepEqual(got, expect) {
+		t.Errorf("expected %v, got %v", expect, got)
+	}
+}
+
 type testPrinter struct {
 	Objects []runtime.Object
 	Err     error
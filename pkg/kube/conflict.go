@@ -0,0 +1,184 @@
+/*
+Copyright 2017 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kube // import "k8s.io/helm/pkg/kube"
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/kubernetes/pkg/kubectl/resource"
+)
+
+// DefaultFieldManager is the field manager used when Client.FieldManager is not set.
+const DefaultFieldManager = "helm"
+
+// FieldConflict is a field that an update wanted to change after something
+// else had already changed it in the cluster.
+type FieldConflict struct {
+	// Path is the dotted path of the field, e.g. "spec.replicas".
+	Path string
+	// Ours is the value the update wanted to set. It is nil if the update
+	// wanted to remove the field.
+	Ours interface{}
+	// Theirs is the value in the cluster. It is nil if the field was removed.
+	Theirs interface{}
+}
+
+// ConflictError is returned for a resource that could not be patched because
+// of a conflict.
+//
+// Conflicts lists the fields the patch would have changed whose live value
+// differs from the previously applied one. It is empty if the conflict could
+// not be traced to any such field, for example because the live object could
+// not be fetched.
+type ConflictError struct {
+	Kind string
+	Name string
+	// Manager is the field manager of the update.
+	Manager string
+	// Controller is the managing controller of the live object, as
+	// "Kind/name", if it has one.
+	Controller string
+	Conflicts  []FieldConflict
+	// Err is the error returned by the API server.
+	Err error
+}
+
+func (e *ConflictError) Error() string {
+	if len(e.Conflicts) == 0 {
+		return fmt.Sprintf("conflict updating %s %q: %s", e.Kind, e.Name, e.Err)
+	}
+	other := "another client"
+	if e.Controller != "" {
+		other = e.Controller
+	}
+	fields := make([]string, 0, len(e.Conflicts))
+	for _, f := range e.Conflicts {
+		fields = append(fields, fmt.Sprintf("%s (ours: %s, theirs: %s)", f.Path, conflictValue(f.Ours), conflictValue(f.Theirs)))
+	}
+	return fmt.Sprintf("conflict updating %s %q: %s changed fields that %s is updating: %s",
+		e.Kind, e.Name, other, e.Manager, strings.Join(fields, ", "))
+}
+
+func conflictValue(v interface{}) string {
+	if v == nil {
+		return "<unset>"
+	}
+	b, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprintf("%v", v)
+	}
+	return string(b)
+}
+
+func (c *Client) fieldManager() string {
+	if c.FieldManager == "" {
+		return DefaultFieldManager
+	}
+	return c.FieldManager
+}
+
+// conflictReport builds a ConflictError for a patch of target that failed with
+// err. current is the previously applied object the patch was computed from.
+func (c *Client) conflictReport(target *resource.Info, current runtime.Object, patch []byte, err error) *ConflictError {
+	report := &ConflictError{
+		Kind:    target.Mapping.GroupVersionKind.Kind,
+		Name:    target.Name,
+		Manager: c.fieldManager(),
+		Err:     err,
+	}
+
+	helper := resource.NewHelper(target.Client, target.Mapping)
+	live, getErr := helper.Get(target.Namespace, target.Name, target.Export)
+	if getErr != nil {
+		c.Log("Cannot fetch %s %q to report the conflict: %s", report.Kind, report.Name, getErr)
+		return report
+	}
+	report.Controller = controllerOf(live)
+
+	var changes map[string]interface{}
+	if json.Unmarshal(patch, &changes) != nil {
+		return report
+	}
+	ours, theirs, previous := objectMap(target.Object), objectMap(live), objectMap(current)
+	for _, p := range patchPaths(changes, nil) {
+		o, t, prev := lookupPath(ours, p), lookupPath(theirs, p), lookupPath(previous, p)
+		if reflect.DeepEqual(t, prev) || reflect.DeepEqual(t, o) {
+			continue
+		}
+		report.Conflicts = append(report.Conflicts, FieldConflict{Path: strings.Join(p, "."), Ours: o, Theirs: t})
+	}
+	sort.Slice(report.Conflicts, func(i, j int) bool { return report.Conflicts[i].Path < report.Conflicts[j].Path })
+	return report
+}
+
+// controllerOf returns the managing controller of obj as "Kind/name".
+func controllerOf(obj runtime.Object) string {
+	accessor, err := meta.Accessor(obj)
+	if err != nil {
+		return ""
+	}
+	for _, ref := range accessor.GetOwnerReferences() {
+		if ref.Controller != nil && *ref.Controller {
+			return ref.Kind + "/" + ref.Name
+		}
+	}
+	return ""
+}
+
+func objectMap(obj runtime.Object) map[string]interface{} {
+	m := map[string]interface{}{}
+	if b, err := json.Marshal(obj); err == nil {
+		json.Unmarshal(b, &m)
+	}
+	return m
+}
+
+// patchPaths returns the paths of the fields a merge patch changes. Lists are
+// treated as single fields, and patch directives are skipped.
+func patchPaths(patch map[string]interface{}, prefix []string) [][]string {
+	paths := [][]string{}
+	for k, v := range patch {
+		if strings.HasPrefix(k, "$") {
+			continue
+		}
+		p := append(append([]string{}, prefix...), k)
+		if sub, ok := v.(map[string]interface{}); ok && len(sub) > 0 {
+			paths = append(paths, patchPaths(sub, p)...)
+			continue
+		}
+		paths = append(paths, p)
+	}
+	return paths
+}
+
+func lookupPath(m map[string]interface{}, p []string) interface{} {
+	var v interface{} = m
+	for _, k := range p {
+		sub, ok := v.(map[string]interface{})
+		if !ok {
+			return nil
+		}
+		v = sub[k]
+	}
+	return v
+}
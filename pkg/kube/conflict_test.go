@@ -0,0 +1,122 @@
+/*
+Copyright 2017 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kube
+
+import (
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/rest/fake"
+	"k8s.io/kubernetes/pkg/api"
+	cmdtesting "k8s.io/kubernetes/pkg/kubectl/cmd/testing"
+)
+
+func conflictBody() *metav1.Status {
+	return &metav1.Status{
+		Code:    http.StatusConflict,
+		Status:  metav1.StatusFailure,
+		Reason:  metav1.StatusReasonConflict,
+		Message: `Operation cannot be fulfilled on pods "starfish": the object has been modified`,
+		Details: &metav1.StatusDetails{Name: "starfish", Kind: "pods"},
+	}
+}
+
+func labelledPod(app string) api.Pod {
+	pod := newPod("starfish")
+	pod.Labels = map[string]string{"app": app, "tier": "web"}
+	return pod
+}
+
+func TestUpdateConflictReport(t *testing.T) {
+	isController := true
+
+	tests := []struct {
+		name       string
+		live       string
+		manager    string
+		controller bool
+		expect     []FieldConflict
+		message    string
+	}{
+		{
+			name:       "changed by a controller",
+			live:       "v3",
+			controller: true,
+			expect:     []FieldConflict{{Path: "metadata.labels.app", Ours: "v2", Theirs: "v3"}},
+			message:    `conflict updating Pod "starfish": ReplicaSet/web changed fields that helm is updating: metadata.labels.app (ours: "v2", theirs: "v3")`,
+		},
+		{
+			name:    "changed by another client",
+			live:    "v3",
+			manager: "tiller",
+			expect:  []FieldConflict{{Path: "metadata.labels.app", Ours: "v2", Theirs: "v3"}},
+			message: `conflict updating Pod "starfish": another client changed fields that tiller is updating: metadata.labels.app (ours: "v2", theirs: "v3")`,
+		},
+		{
+			name:    "unchanged in the cluster",
+			live:    "v1",
+			message: `conflict updating Pod "starfish": Operation cannot be fulfilled`,
+		},
+	}
+
+	for _, tt := range tests {
+		original, target, live := labelledPod("v1"), labelledPod("v2"), labelledPod(tt.live)
+		if tt.controller {
+			live.OwnerReferences = []metav1.OwnerReference{{Kind: "ReplicaSet", Name: "web", Controller: &isController}}
+		}
+
+		f, tf, codec, _ := cmdtesting.NewAPIFactory()
+		tf.UnstructuredClient = &fake.RESTClient{
+			APIRegistry:          api.Registry,
+			NegotiatedSerializer: dynamic.ContentConfig().NegotiatedSerializer,
+			Client: fake.CreateHTTPClient(func(req *http.Request) (*http.Response, error) {
+				p, m := req.URL.Path, req.Method
+				switch {
+				case p == "/namespaces/default/pods/starfish" && m == "GET":
+					return newResponse(200, &live)
+				case p == "/namespaces/default/pods/starfish" && m == "PATCH":
+					return newResponse(409, conflictBody())
+				default:
+					t.Fatalf("unexpected request: %s %s", req.Method, req.URL.Path)
+					return nil, nil
+				}
+			}),
+		}
+
+		c := newTestClient(f)
+		c.FieldManager = tt.manager
+		err := c.Update(api.NamespaceDefault, objBody(codec, &original), objBody(codec, &target), false, false, 0, false)
+		applyErr, ok := err.(*ApplyError)
+		if !ok || len(applyErr.Statuses) != 1 {
+			t.Fatalf("%s: expected an apply error for one resource, got %v", tt.name, err)
+		}
+		conflict, ok := applyErr.Statuses[0].Err.(*ConflictError)
+		if !ok {
+			t.Fatalf("%s: expected a conflict error, got %v", tt.name, applyErr.Statuses[0].Err)
+		}
+		if !reflect.DeepEqual(conflict.Conflicts, tt.expect) {
+			t.Errorf("%s: expected conflicts %v, got %v", tt.name, tt.expect, conflict.Conflicts)
+		}
+		if !strings.HasPrefix(conflict.Error(), tt.message) {
+			t.Errorf("%s: expected message starting with\n%s\ngot\n%s", tt.name, tt.message, conflict.Error())
+		}
+	}
+}
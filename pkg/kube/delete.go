@@ -0,0 +1,99 @@
+/*
+Copyright 2017 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kube // import "k8s.io/helm/pkg/kube"
+
+import (
+	"fmt"
+	"io"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/kubernetes/pkg/kubectl/resource"
+)
+
+// ParsePropagationPolicy returns the deletion propagation policy named by s,
+// one of "Foreground", "Background" or "Orphan".
+func ParsePropagationPolicy(s string) (metav1.DeletionPropagation, error) {
+	switch p := metav1.DeletionPropagation(s); p {
+	case metav1.DeletePropagationForeground, metav1.DeletePropagationBackground, metav1.DeletePropagationOrphan:
+		return p, nil
+	}
+	return "", fmt.Errorf("invalid propagation policy %q: must be one of Foreground, Background or Orphan", s)
+}
+
+// DeleteWithPolicy deletes kubernetes resources from an io.reader using the
+// given deletion propagation policy.
+//
+// When policy is empty, resources are deleted the way Delete deletes them.
+// If shouldWait is set, DeleteWithPolicy waits up to timeout seconds for the
+// resources to be gone. With the Foreground policy, that includes their
+// dependents.
+func (c *Client) DeleteWithPolicy(namespace string, reader io.Reader, policy string, timeout int64, shouldWait bool) (err error) {
+	defer observe("delete", time.Now(), &err)
+
+	var opts *metav1.DeleteOptions
+	if policy != "" {
+		p, err := ParsePropagationPolicy(policy)
+		if err != nil {
+			return err
+		}
+		opts = &metav1.DeleteOptions{PropagationPolicy: &p}
+	}
+	infos, err := c.BuildUnstructured(namespace, reader)
+	if err != nil {
+		return err
+	}
+	err = perform(infos, func(info *resource.Info) error {
+		c.Log("Starting delete for %q %s", info.Name, info.Mapping.GroupVersionKind.Kind)
+		if opts == nil {
+			return c.skipIfNotFound(deleteResource(c, info, nil))
+		}
+		err := resource.NewHelper(info.Client, info.Mapping).DeleteWithOptions(info.Namespace, info.Name, opts)
+		return c.skipIfNotFound(err)
+	})
+	if err != nil || !shouldWait {
+		return err
+	}
+	return c.waitForDeletion(time.Duration(timeout)*time.Second, infos)
+}
+
+// waitForDeletion polls until none of infos can be found, or the timeout is
+// reached.
+func (c *Client) waitForDeletion(timeout time.Duration, infos Result) error {
+	c.Log("waiting for %d resource(s) to be deleted, up to %v", len(infos), timeout)
+	var pending *resource.Info
+	err := wait.PollImmediate(2*time.Second, timeout, func() (bool, error) {
+		for _, info := range infos {
+			_, err := resource.NewHelper(info.Client, info.Mapping).Get(info.Namespace, info.Name, false)
+			if errors.IsNotFound(err) {
+				continue
+			}
+			if err != nil {
+				return false, err
+			}
+			pending = info
+			return false, nil
+		}
+		return true, nil
+	})
+	if err == wait.ErrWaitTimeout {
+		return fmt.Errorf("timed out waiting for %s %q to be deleted", pending.Mapping.GroupVersionKind.Kind, pending.Name)
+	}
+	return err
+}
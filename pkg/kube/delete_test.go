@@ -0,0 +1,105 @@
+/*
+Copyright 2017 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kube
+
+import (
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/rest/fake"
+	"k8s.io/kubernetes/pkg/api"
+	cmdtesting "k8s.io/kubernetes/pkg/kubectl/cmd/testing"
+)
+
+func TestParsePropagationPolicy(t *testing.T) {
+	for _, p := range []string{"Foreground", "Background", "Orphan"} {
+		if got, err := ParsePropagationPolicy(p); err != nil {
+			t.Errorf("%s: %s", p, err)
+		} else if string(got) != p {
+			t.Errorf("expected %q, got %q", p, got)
+		}
+	}
+	for _, p := range []string{"", "foreground", "Cascade"} {
+		if _, err := ParsePropagationPolicy(p); err == nil {
+			t.Errorf("expected an error for %q", p)
+		}
+	}
+}
+
+func TestDeleteWithPolicy(t *testing.T) {
+	pod := newPod("squid")
+	var deleteBody string
+	var actions []string
+
+	f, tf, codec, _ := cmdtesting.NewAPIFactory()
+	tf.UnstructuredClient = &fake.RESTClient{
+		APIRegistry:          api.Registry,
+		NegotiatedSerializer: dynamic.ContentConfig().NegotiatedSerializer,
+		Client: fake.CreateHTTPClient(func(req *http.Request) (*http.Response, error) {
+			p, m := req.URL.Path, req.Method
+			actions = append(actions, p+":"+m)
+			switch {
+			case p == "/namespaces/default/pods/squid" && m == "DELETE":
+				data, err := ioutil.ReadAll(req.Body)
+				if err != nil {
+					t.Fatalf("could not read request: %s", err)
+				}
+				deleteBody = string(data)
+				return newResponse(200, &pod)
+			case p == "/namespaces/default/pods/squid" && m == "GET":
+				return newResponse(404, notFoundBody())
+			}
+			t.Fatalf("unexpected request: %s %s", req.Method, req.URL.Path)
+			return nil, nil
+		}),
+	}
+
+	reaper := &fakeReaper{}
+	c := newTestClient(&fakeReaperFactory{Factory: f, reaper: reaper})
+
+	if err := c.DeleteWithPolicy("default", objBody(codec, &pod), "Foreground", 10, true); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(deleteBody, `"propagationPolicy":"Foreground"`) {
+		t.Errorf("expected the delete options to set the propagation policy, got %s", deleteBody)
+	}
+	if got, expect := strings.Join(actions, ","), "/namespaces/default/pods/squid:DELETE,/namespaces/default/pods/squid:GET"; got != expect {
+		t.Errorf("expected requests %q, got %q", expect, got)
+	}
+	if reaper.name != "" {
+		t.Errorf("expected no reaper to be used with a propagation policy, got %q", reaper.name)
+	}
+
+	// Without a policy, resources are deleted the way Delete deletes them.
+	actions = nil
+	if err := c.DeleteWithPolicy("default", objBody(codec, &pod), "", 0, false); err != nil {
+		t.Fatal(err)
+	}
+	if reaper.name != "squid" {
+		t.Errorf("expected the reaper to delete squid, got %q", reaper.name)
+	}
+	if len(actions) != 0 {
+		t.Errorf("expected no requests, got %v", actions)
+	}
+
+	if err := c.DeleteWithPolicy("default", objBody(codec, &pod), "Sideways", 0, false); err == nil {
+		t.Error("expected an error for an invalid propagation policy")
+	}
+}
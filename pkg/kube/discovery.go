@@ -0,0 +1,47 @@
+/*
+Copyright 2017 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kube // import "k8s.io/helm/pkg/kube"
+
+import (
+	"fmt"
+	"time"
+
+	"k8s.io/client-go/discovery"
+)
+
+// InvalidateDiscovery discovers the API groups and resources of the server
+// again, replacing those that the client cached.
+//
+// The client maps the kinds of resources to their APIs with a discovery
+// cache that is kept for minutes. Resources of a kind whose
+// CustomResourceDefinition was created since the cache was filled cannot be
+// built until it is invalidated.
+func (c *Client) InvalidateDiscovery() (err error) {
+	defer observe("invalidate_discovery", time.Now(), &err)
+
+	dc, err := c.DiscoveryClient()
+	if err != nil {
+		return err
+	}
+	dc.Invalidate()
+	// Discovering with the invalidated client refills the cache that later
+	// clients read.
+	if _, err := discovery.GetAPIGroupResources(dc); err != nil {
+		return fmt.Errorf("could not discover the API of the server: %s", err)
+	}
+	return nil
+}
@@ -0,0 +1,205 @@
+/*
+Copyright 2017 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kube // import "k8s.io/helm/pkg/kube"
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"strconv"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/kubernetes/pkg/kubectl/resource"
+)
+
+// ResourceDrift describes how a live resource differs from its manifest.
+type ResourceDrift struct {
+	APIVersion string
+	Kind       string
+	Name       string
+	Namespace  string
+	// Missing is set if the resource does not exist.
+	Missing bool
+	// Fields are the fields of the manifest that the live resource does not
+	// match, by path.
+	Fields []FieldDrift
+}
+
+// FieldDrift is a field whose live value differs from its manifest.
+type FieldDrift struct {
+	// Path locates the field, e.g. "spec.template.spec.containers[0].image".
+	Path string
+	// Expected is the value in the manifest, as JSON.
+	Expected string
+	// Actual is the live value as JSON, or empty if the field is not set.
+	Actual string
+}
+
+// ignoredMetadata are the metadata fields that the API server manages, and
+// that a manifest may still carry, e.g. as "creationTimestamp: null".
+var ignoredMetadata = map[string]bool{
+	"creationTimestamp": true,
+	"generation":        true,
+	"resourceVersion":   true,
+	"selfLink":          true,
+	"uid":               true,
+}
+
+// Drift compares the resources in reader to their live counterparts. It
+// reports, for each resource, whether it is missing and which of the fields
+// that the manifest sets have a different live value. Nothing is changed.
+//
+// Fields that the manifest does not set, such as defaults filled in by the
+// server, and the status of the resources are not compared. Values are
+// compared as written, so a quantity such as "0.5" differs from "500m".
+//
+// Namespace will set the namespace
+func (c *Client) Drift(namespace string, reader io.Reader) (_ []ResourceDrift, err error) {
+	defer observe("drift", time.Now(), &err)
+
+	infos, err := c.BuildUnstructured(namespace, reader)
+	if err != nil {
+		return nil, err
+	}
+	return driftResources(infos)
+}
+
+func driftResources(infos Result) ([]ResourceDrift, error) {
+	drifts := make([]ResourceDrift, 0, len(infos))
+	for _, info := range infos {
+		gvk := info.Mapping.GroupVersionKind
+		d := ResourceDrift{
+			APIVersion: gvk.GroupVersion().String(),
+			Kind:       gvk.Kind,
+			Name:       info.Name,
+			Namespace:  info.Namespace,
+		}
+		live, err := resource.NewHelper(info.Client, info.Mapping).Get(info.Namespace, info.Name, false)
+		switch {
+		case errors.IsNotFound(err):
+			d.Missing = true
+		case err != nil:
+			return nil, fmt.Errorf("cannot get %s %q: %s", gvk.Kind, info.Name, err)
+		default:
+			if d.Fields, err = driftObject(info.Object, live); err != nil {
+				return nil, fmt.Errorf("cannot compare %s %q: %s", gvk.Kind, info.Name, err)
+			}
+		}
+		drifts = append(drifts, d)
+	}
+	return drifts, nil
+}
+
+// driftObject lists the fields set in expected that differ in actual.
+func driftObject(expected, actual runtime.Object) ([]FieldDrift, error) {
+	e, err := objectFields(expected)
+	if err != nil {
+		return nil, err
+	}
+	a, err := objectFields(actual)
+	if err != nil {
+		return nil, err
+	}
+	delete(e, "status")
+	if m, ok := e["metadata"].(map[string]interface{}); ok {
+		for k := range ignoredMetadata {
+			delete(m, k)
+		}
+	}
+	return driftFields(nil, "", e, a), nil
+}
+
+// objectFields returns the fields of obj as they are encoded in JSON.
+func objectFields(obj runtime.Object) (map[string]interface{}, error) {
+	data, err := runtime.Encode(unstructured.UnstructuredJSONScheme, obj)
+	if err != nil {
+		return nil, err
+	}
+	fields := map[string]interface{}{}
+	return fields, json.Unmarshal(data, &fields)
+}
+
+// driftFields appends to drifts the fields under path that are set in
+// expected and differ in actual. Tables are compared key by key, ignoring
+// keys only actual has; lists of different lengths differ as a whole.
+func driftFields(drifts []FieldDrift, path string, expected, actual interface{}) []FieldDrift {
+	switch e := expected.(type) {
+	case nil:
+		// An explicit null in a manifest leaves the field unset.
+		return drifts
+	case map[string]interface{}:
+		a, ok := actual.(map[string]interface{})
+		if !ok {
+			break
+		}
+		keys := make([]string, 0, len(e))
+		for k := range e {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			drifts = driftFields(drifts, fieldPath(path, k), e[k], a[k])
+		}
+		return drifts
+	case []interface{}:
+		a, ok := actual.([]interface{})
+		if !ok || len(a) != len(e) {
+			break
+		}
+		for i := range e {
+			drifts = driftFields(drifts, path+"["+strconv.Itoa(i)+"]", e[i], a[i])
+		}
+		return drifts
+	default:
+		if e == actual {
+			return drifts
+		}
+	}
+	return append(drifts, FieldDrift{Path: path, Expected: jsonValue(expected), Actual: jsonValue(actual)})
+}
+
+// plainKey matches the keys that a field path can use without quoting.
+var plainKey = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_-]*$`)
+
+// fieldPath appends key to path, quoting keys such as label names that are
+// not plain, e.g. `metadata.labels["app.kubernetes.io/name"]`.
+func fieldPath(path, key string) string {
+	if !plainKey.MatchString(key) {
+		return path + "[" + strconv.Quote(key) + "]"
+	}
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
+
+// jsonValue encodes a field value as JSON. A missing value is empty.
+func jsonValue(v interface{}) string {
+	if v == nil {
+		return ""
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+	return string(data)
+}
@@ -0,0 +1,153 @@
+/*
+Copyright 2017 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kube
+
+import (
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/rest/fake"
+	"k8s.io/kubernetes/pkg/api"
+	cmdtesting "k8s.io/kubernetes/pkg/kubectl/cmd/testing"
+)
+
+func TestDriftResources(t *testing.T) {
+	target := newPodList("otter", "squid", "starfish")
+	target.Items[0].Labels = map[string]string{"app.kubernetes.io/name": "otter", "tier": "web"}
+
+	edited := target.Items[0]
+	edited.Labels = map[string]string{"app.kubernetes.io/name": "seal", "tier": "web", "edited": "true"}
+	edited.Spec.Containers = []api.Container{{
+		Name:  "app:v4",
+		Image: "abc/app:v5",
+		Ports: []api.ContainerPort{{Name: "http", ContainerPort: 80}},
+	}}
+	edited.Spec.ServiceAccountName = "default"
+	edited.ResourceVersion = "42"
+	edited.Status.Phase = api.PodRunning
+	starfish := target.Items[2]
+
+	var actions []string
+
+	f, tf, codec, _ := cmdtesting.NewAPIFactory()
+	tf.UnstructuredClient = &fake.RESTClient{
+		APIRegistry:          api.Registry,
+		NegotiatedSerializer: dynamic.ContentConfig().NegotiatedSerializer,
+		Client: fake.CreateHTTPClient(func(req *http.Request) (*http.Response, error) {
+			p, m := req.URL.Path, req.Method
+			actions = append(actions, p+":"+m)
+			switch {
+			case p == "/namespaces/default/pods/otter" && m == "GET":
+				return newResponse(200, &edited)
+			case p == "/namespaces/default/pods/squid" && m == "GET":
+				return newResponse(404, notFoundBody())
+			case p == "/namespaces/default/pods/starfish" && m == "GET":
+				return newResponse(200, &starfish)
+			}
+			t.Fatalf("unexpected request: %s %s", req.Method, req.URL.Path)
+			return nil, nil
+		}),
+	}
+
+	c := newTestClient(f)
+	infos, err := c.BuildUnstructured(api.NamespaceDefault, objBody(codec, &target))
+	if err != nil {
+		t.Fatal(err)
+	}
+	drifts, err := driftResources(infos)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Only reads are made.
+	expectedActions := []string{
+		"/namespaces/default/pods/otter:GET",
+		"/namespaces/default/pods/squid:GET",
+		"/namespaces/default/pods/starfish:GET",
+	}
+	if strings.Join(actions, ",") != strings.Join(expectedActions, ",") {
+		t.Errorf("expected requests %v, got %v", expectedActions, actions)
+	}
+
+	expected := []ResourceDrift{
+		{
+			APIVersion: "v1", Kind: "Pod", Name: "otter", Namespace: "default",
+			// The extra label, server defaults, resource version and status
+			// are not drift.
+			Fields: []FieldDrift{
+				{Path: `metadata.labels["app.kubernetes.io/name"]`, Expected: `"otter"`, Actual: `"seal"`},
+				{Path: "spec.containers[0].image", Expected: `"abc/app:v4"`, Actual: `"abc/app:v5"`},
+			},
+		},
+		{APIVersion: "v1", Kind: "Pod", Name: "squid", Namespace: "default", Missing: true},
+		{APIVersion: "v1", Kind: "Pod", Name: "starfish", Namespace: "default"},
+	}
+	if !reflect.DeepEqual(drifts, expected) {
+		t.Errorf("expected drift\n%+v\ngot\n%+v", expected, drifts)
+	}
+}
+
+func TestDriftFields(t *testing.T) {
+	tests := []struct {
+		name             string
+		expected, actual interface{}
+		drifts           []FieldDrift
+	}{
+		{
+			name:     "equal",
+			expected: map[string]interface{}{"a": float64(1), "b": []interface{}{"x"}},
+			actual:   map[string]interface{}{"a": float64(1), "b": []interface{}{"x"}, "c": true},
+		},
+		{
+			name:     "missing field",
+			expected: map[string]interface{}{"a": map[string]interface{}{"b": "x"}},
+			actual:   map[string]interface{}{"a": map[string]interface{}{}},
+			drifts:   []FieldDrift{{Path: "a.b", Expected: `"x"`}},
+		},
+		{
+			name:     "list length",
+			expected: map[string]interface{}{"env": []interface{}{"A"}},
+			actual:   map[string]interface{}{"env": []interface{}{"A", "B"}},
+			drifts:   []FieldDrift{{Path: "env", Expected: `["A"]`, Actual: `["A","B"]`}},
+		},
+		{
+			name:     "type",
+			expected: map[string]interface{}{"port": float64(80)},
+			actual:   map[string]interface{}{"port": "80"},
+			drifts:   []FieldDrift{{Path: "port", Expected: `80`, Actual: `"80"`}},
+		},
+		{
+			name:     "table replaced",
+			expected: map[string]interface{}{"a": map[string]interface{}{"b": "x"}},
+			actual:   map[string]interface{}{"a": "x"},
+			drifts:   []FieldDrift{{Path: "a", Expected: `{"b":"x"}`, Actual: `"x"`}},
+		},
+		{
+			name:     "null",
+			expected: map[string]interface{}{"a": nil},
+			actual:   map[string]interface{}{"a": "x"},
+		},
+	}
+	for _, tt := range tests {
+		if got := driftFields(nil, "", tt.expected, tt.actual); !reflect.DeepEqual(got, tt.drifts) {
+			t.Errorf("%s: expected %+v, got %+v", tt.name, tt.drifts, got)
+		}
+	}
+}
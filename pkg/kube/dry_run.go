@@ -0,0 +1,155 @@
+/*
+Copyright 2017 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kube // import "k8s.io/helm/pkg/kube"
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/ghodss/yaml"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/version"
+	"k8s.io/kubernetes/pkg/kubectl/resource"
+)
+
+// serverDryRunMinor is the first minor release of Kubernetes 1.x that honors
+// the dryRun parameter. Older API servers silently ignore it and would persist
+// the object, so the server version must be checked before any request is sent.
+const serverDryRunMinor = 13
+
+// DryRun sends the resources in reader to the API server as a server-side
+// dry-run and returns the objects the server would have stored, with defaults
+// applied and admission controllers run, as a YAML stream.
+//
+// Resources that do not exist yet are dry-run created; resources that already
+// exist are dry-run patched, the same way Update would patch them. Nothing is
+// persisted.
+//
+// Namespace will set the namespace
+func (c *Client) DryRun(namespace string, reader io.Reader) (_ string, err error) {
+	defer observe("dry_run", time.Now(), &err)
+
+	dc, err := c.DiscoveryClient()
+	if err != nil {
+		return "", err
+	}
+	v, err := dc.ServerVersion()
+	if err != nil {
+		return "", fmt.Errorf("could not get server version: %s", err)
+	}
+	if err := checkServerDryRun(v); err != nil {
+		return "", err
+	}
+
+	infos, err := c.BuildUnstructured(namespace, reader)
+	if err != nil {
+		return "", err
+	}
+	return dryRunResources(infos, c.PatchStrategy)
+}
+
+// checkServerDryRun returns an error unless v is recent enough to support
+// server-side dry-run.
+func checkServerDryRun(v *version.Info) error {
+	major, err := strconv.Atoi(trimNonDigits(v.Major))
+	if err != nil {
+		return fmt.Errorf("could not parse server version %q: %s", v.Major, err)
+	}
+	minor, err := strconv.Atoi(trimNonDigits(v.Minor))
+	if err != nil {
+		return fmt.Errorf("could not parse server version %q: %s", v.Minor, err)
+	}
+	if major > 1 || (major == 1 && minor >= serverDryRunMinor) {
+		return nil
+	}
+	return fmt.Errorf("server-side dry-run requires Kubernetes 1.%d or later, the server is %s.%s", serverDryRunMinor, v.Major, v.Minor)
+}
+
+// trimNonDigits drops trailing markers such as the "+" in "13+".
+func trimNonDigits(s string) string {
+	return strings.TrimRightFunc(s, func(r rune) bool { return !unicode.IsDigit(r) })
+}
+
+func dryRunResources(infos Result, defStrategy string) (string, error) {
+	b := bytes.NewBuffer(nil)
+	err := perform(infos, func(info *resource.Info) error {
+		raw, err := dryRunResource(info, defStrategy)
+		if err != nil {
+			return fmt.Errorf("server dry-run of %s %q failed: %s", info.Mapping.GroupVersionKind.Kind, info.Name, err)
+		}
+		doc, err := yaml.JSONToYAML(raw)
+		if err != nil {
+			return err
+		}
+		b.WriteString("---\n")
+		b.Write(doc)
+		return nil
+	})
+	return b.String(), err
+}
+
+// dryRunResource returns the JSON of the object the server would store for
+// info, patching existing objects with the given default patch strategy.
+func dryRunResource(info *resource.Info, defStrategy string) ([]byte, error) {
+	helper := resource.NewHelper(info.Client, info.Mapping)
+
+	data, err := runtime.Encode(unstructured.UnstructuredJSONScheme, info.Object)
+	if err != nil {
+		return nil, err
+	}
+	raw, err := helper.RESTClient.Post().
+		NamespaceIfScoped(info.Namespace, helper.NamespaceScoped).
+		Resource(helper.Resource).
+		Param("dryRun", "All").
+		Body(data).
+		Do().
+		Raw()
+	if !errors.IsAlreadyExists(err) {
+		return raw, err
+	}
+
+	current, err := helper.Get(info.Namespace, info.Name, false)
+	if err != nil {
+		return nil, err
+	}
+	strategy, err := patchStrategy(info.Object, defStrategy)
+	if err != nil {
+		return nil, err
+	}
+	patch, patchType, err := createPatch(info.Mapping, info.Object, current, current, strategy)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create patch: %s", err)
+	}
+	if patch == nil {
+		return runtime.Encode(unstructured.UnstructuredJSONScheme, current)
+	}
+	return helper.RESTClient.Patch(patchType).
+		NamespaceIfScoped(info.Namespace, helper.NamespaceScoped).
+		Resource(helper.Resource).
+		Name(info.Name).
+		Param("dryRun", "All").
+		Body(patch).
+		Do().
+		Raw()
+}
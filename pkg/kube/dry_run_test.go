@@ -0,0 +1,135 @@
+/*
+Copyright 2017 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kube
+
+import (
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/version"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/rest/fake"
+	"k8s.io/kubernetes/pkg/api"
+	cmdtesting "k8s.io/kubernetes/pkg/kubectl/cmd/testing"
+)
+
+func TestCheckServerDryRun(t *testing.T) {
+	tests := []struct {
+		major, minor string
+		ok           bool
+	}{
+		{"1", "6", false},
+		{"1", "12+", false},
+		{"1", "13", true},
+		{"1", "14+", true},
+		{"2", "0", true},
+		{"1", "", false},
+	}
+	for _, tt := range tests {
+		err := checkServerDryRun(&version.Info{Major: tt.major, Minor: tt.minor})
+		if (err == nil) != tt.ok {
+			t.Errorf("%s.%s: expected ok=%t, got %v", tt.major, tt.minor, tt.ok, err)
+		}
+	}
+}
+
+func TestDryRunResources(t *testing.T) {
+	current := newPodList("otter", "squid")
+	target := newPodList("starfish", "otter", "squid")
+	target.Items[1].Spec.Containers[0].Ports = []api.ContainerPort{{Name: "https", ContainerPort: 443}}
+	created := newPod("starfish")
+	created.Spec.ServiceAccountName = "default"
+	patched := target.Items[1]
+	patched.Spec.ServiceAccountName = "default"
+
+	var actions []string
+
+	f, tf, codec, _ := cmdtesting.NewAPIFactory()
+	tf.UnstructuredClient = &fake.RESTClient{
+		APIRegistry:          api.Registry,
+		NegotiatedSerializer: dynamic.ContentConfig().NegotiatedSerializer,
+		Client: fake.CreateHTTPClient(func(req *http.Request) (*http.Response, error) {
+			p, m := req.URL.Path, req.Method
+			actions = append(actions, p+":"+m)
+			if m != "GET" && req.URL.Query().Get("dryRun") != "All" {
+				t.Fatalf("expected a dry-run request, got %s %s", m, req.URL)
+			}
+			switch {
+			case p == "/namespaces/default/pods" && m == "POST":
+				data, err := ioutil.ReadAll(req.Body)
+				if err != nil {
+					t.Fatalf("could not dump request: %s", err)
+				}
+				req.Body.Close()
+				if strings.Contains(string(data), `"name":"starfish"`) {
+					return newResponse(201, &created)
+				}
+				return newResponse(409, &metav1.Status{
+					Code:   http.StatusConflict,
+					Status: metav1.StatusFailure,
+					Reason: metav1.StatusReasonAlreadyExists,
+				})
+			case p == "/namespaces/default/pods/otter" && m == "GET":
+				return newResponse(200, &current.Items[0])
+			case p == "/namespaces/default/pods/otter" && m == "PATCH":
+				return newResponse(200, &patched)
+			case p == "/namespaces/default/pods/squid" && m == "GET":
+				return newResponse(200, &current.Items[1])
+			}
+			t.Fatalf("unexpected request: %s %s", req.Method, req.URL.Path)
+			return nil, nil
+		}),
+	}
+
+	c := newTestClient(f)
+	infos, err := c.BuildUnstructured(api.NamespaceDefault, objBody(codec, &target))
+	if err != nil {
+		t.Fatal(err)
+	}
+	out, err := dryRunResources(infos, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expectedActions := []string{
+		"/namespaces/default/pods:POST",
+		"/namespaces/default/pods:POST",
+		"/namespaces/default/pods/otter:GET",
+		"/namespaces/default/pods/otter:PATCH",
+		"/namespaces/default/pods:POST",
+		"/namespaces/default/pods/squid:GET",
+	}
+	if strings.Join(actions, ",") != strings.Join(expectedActions, ",") {
+		t.Errorf("expected requests %v, got %v", expectedActions, actions)
+	}
+
+	docs := strings.Split(out, "---\n")[1:]
+	if len(docs) != 3 {
+		t.Fatalf("expected 3 documents, got %q", out)
+	}
+	for i, name := range []string{"starfish", "otter"} {
+		if !strings.Contains(docs[i], "name: "+name) || !strings.Contains(docs[i], "serviceAccountName: default") {
+			t.Errorf("expected %s with server defaults, got %q", name, docs[i])
+		}
+	}
+	if !strings.Contains(docs[2], "name: squid") || strings.Contains(docs[2], "serviceAccountName") {
+		t.Errorf("expected unchanged squid, got %q", docs[2])
+	}
+}
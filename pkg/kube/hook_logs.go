@@ -0,0 +1,96 @@
+/*
+Copyright 2017 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kube // import "k8s.io/helm/pkg/kube"
+
+import (
+	"fmt"
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/kubernetes/pkg/api/v1"
+	"k8s.io/kubernetes/pkg/client/clientset_generated/clientset"
+	"k8s.io/kubernetes/pkg/kubectl/resource"
+)
+
+// HookError is the error of a hook that failed or timed out in
+// WatchUntilReady, with the end of the logs of its pods.
+type HookError struct {
+	Err error
+	// Logs are the end of the logs of the containers of the hook's pods that
+	// are not ready, each headed by "==> namespace/pod/container <==".
+	Logs string
+}
+
+func (e *HookError) Error() string {
+	return fmt.Sprintf("%s\nlogs of the hook's pods:\n%s", e.Err, strings.TrimSuffix(e.Logs, "\n"))
+}
+
+// withHookLogs returns err as a HookError with the logs of the pods of the
+// Pods and Jobs in infos, if the client's WaitLogBytes is set and there are
+// logs to read. Otherwise err is returned as it is.
+func (c *Client) withHookLogs(err error, infos Result) error {
+	if c.WaitLogBytes <= 0 {
+		return err
+	}
+	cs, cerr := c.ClientSet()
+	if cerr != nil {
+		return err
+	}
+	client := versionedClientsetForDeployment(cs)
+	var pods []v1.Pod
+	for _, info := range infos {
+		list, perr := hookPods(client, info)
+		if perr != nil {
+			c.Log("cannot look up the pods of %s: %s", describeInfo(info), perr)
+			continue
+		}
+		pods = append(pods, list...)
+	}
+	logs := podLogs(clientLogFetcher(client), pods, c.WaitLogBytes)
+	if logs == "" {
+		return err
+	}
+	c.Log("logs of the hook's pods:\n%s", logs)
+	return &HookError{Err: err, Logs: logs}
+}
+
+// hookPods returns the pods of a hook: the hook itself if it is a Pod, or the
+// pods of a Job. Hooks of other kinds have none.
+func hookPods(client clientset.Interface, info *resource.Info) ([]v1.Pod, error) {
+	switch info.Mapping.GroupVersionKind.Kind {
+	case "Pod":
+		pod, err := client.Core().Pods(info.Namespace).Get(info.Name, metav1.GetOptions{})
+		if err != nil {
+			return nil, err
+		}
+		return []v1.Pod{*pod}, nil
+	case "Job":
+		job, err := client.Batch().Jobs(info.Namespace).Get(info.Name, metav1.GetOptions{})
+		if err != nil || job.Spec.Selector == nil {
+			return nil, err
+		}
+		list, err := client.Core().Pods(info.Namespace).List(metav1.ListOptions{
+			LabelSelector: labels.Set(job.Spec.Selector.MatchLabels).String(),
+		})
+		if err != nil {
+			return nil, err
+		}
+		return list.Items, nil
+	}
+	return nil, nil
+}
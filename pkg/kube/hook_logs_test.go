@@ -0,0 +1,67 @@
+/*
+Copyright 2017 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kube
+
+import (
+	"testing"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/kubernetes/pkg/api/v1"
+	batch "k8s.io/kubernetes/pkg/apis/batch/v1"
+	"k8s.io/kubernetes/pkg/client/clientset_generated/clientset/fake"
+	"k8s.io/kubernetes/pkg/kubectl/resource"
+)
+
+func TestHookPods(t *testing.T) {
+	labeled := func(name, job string) *v1.Pod {
+		pod := logPod(name, []string{"migrate"})
+		pod.Labels = map[string]string{"job-name": job}
+		return &pod
+	}
+	job := &batch.Job{
+		ObjectMeta: metav1.ObjectMeta{Name: "migrate", Namespace: "default"},
+		Spec:       batch.JobSpec{Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"job-name": "migrate"}}},
+	}
+	client := fake.NewSimpleClientset(job, labeled("migrate-1", "migrate"), labeled("migrate-2", "migrate"), labeled("seed-1", "seed"))
+
+	info := func(kind, name string) *resource.Info {
+		return &resource.Info{
+			Name:      name,
+			Namespace: "default",
+			Mapping:   &meta.RESTMapping{GroupVersionKind: schema.GroupVersionKind{Kind: kind}},
+		}
+	}
+
+	pods, err := hookPods(client, info("Job", "migrate"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(pods) != 2 {
+		t.Errorf("Expected the 2 pods of the job, got %d", len(pods))
+	}
+
+	pods, err = hookPods(client, info("Pod", "seed-1"))
+	if err != nil || len(pods) != 1 || pods[0].Name != "seed-1" {
+		t.Errorf("Expected the pod itself, got %v (%v)", pods, err)
+	}
+
+	if pods, err := hookPods(client, info("ConfigMap", "settings")); err != nil || len(pods) != 0 {
+		t.Errorf("Expected no pods for a ConfigMap, got %v (%v)", pods, err)
+	}
+}
@@ -0,0 +1,77 @@
+/*
+Copyright 2017 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kube // import "k8s.io/helm/pkg/kube"
+
+import (
+	"fmt"
+	"time"
+
+	autoscaling "k8s.io/kubernetes/pkg/apis/autoscaling/v1"
+)
+
+// hpaStabilization tells, during a wait, whether HorizontalPodAutoscalers
+// have settled: their current number of replicas must equal the desired one
+// and stay unchanged for the whole window.
+type hpaStabilization struct {
+	window time.Duration
+	now    func() time.Time
+	// seen is the last observation of each autoscaler, by namespace and name.
+	seen map[string]hpaObservation
+}
+
+// hpaObservation is the replica count of an autoscaler, and when it was
+// first seen with it.
+type hpaObservation struct {
+	current int32
+	desired int32
+	since   time.Time
+}
+
+func newHPAStabilization(window time.Duration) *hpaStabilization {
+	return &hpaStabilization{
+		window: window,
+		now:    time.Now,
+		seen:   map[string]hpaObservation{},
+	}
+}
+
+// pending records the status of hpa and returns what it still waits for, or
+// "" if it is stable. It never waits if the window is not positive.
+func (s *hpaStabilization) pending(hpa *autoscaling.HorizontalPodAutoscaler) string {
+	if s == nil || s.window <= 0 {
+		return ""
+	}
+	key := hpa.Namespace + "/" + hpa.Name
+	now := s.now()
+	obs := hpaObservation{
+		current: hpa.Status.CurrentReplicas,
+		desired: hpa.Status.DesiredReplicas,
+		since:   now,
+	}
+	if last, ok := s.seen[key]; ok && last.current == obs.current && last.desired == obs.desired {
+		obs.since = last.since
+	}
+	s.seen[key] = obs
+
+	switch {
+	case obs.current != obs.desired:
+		return fmt.Sprintf("HorizontalPodAutoscaler %q to scale from %d to %d replicas", hpa.Name, obs.current, obs.desired)
+	case now.Sub(obs.since) < s.window:
+		return fmt.Sprintf("HorizontalPodAutoscaler %q to stay at %d replicas for %v", hpa.Name, obs.current, s.window)
+	}
+	return ""
+}
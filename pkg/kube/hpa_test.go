@@ -0,0 +1,73 @@
+/*
+Copyright 2017 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kube
+
+import (
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	autoscaling "k8s.io/kubernetes/pkg/apis/autoscaling/v1"
+)
+
+func newHPA(name string, current, desired int32) *autoscaling.HorizontalPodAutoscaler {
+	return &autoscaling.HorizontalPodAutoscaler{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+		Status: autoscaling.HorizontalPodAutoscalerStatus{
+			CurrentReplicas: current,
+			DesiredReplicas: desired,
+		},
+	}
+}
+
+func TestHPAStabilization(t *testing.T) {
+	now := time.Unix(0, 0)
+	s := newHPAStabilization(time.Minute)
+	s.now = func() time.Time { return now }
+
+	steps := []struct {
+		after   time.Duration
+		hpa     *autoscaling.HorizontalPodAutoscaler
+		pending string
+	}{
+		{0, newHPA("web", 2, 5), `HorizontalPodAutoscaler "web" to scale from 2 to 5 replicas`},
+		{30 * time.Second, newHPA("web", 5, 5), `HorizontalPodAutoscaler "web" to stay at 5 replicas for 1m0s`},
+		{30 * time.Second, newHPA("web", 5, 5), `HorizontalPodAutoscaler "web" to stay at 5 replicas for 1m0s`},
+		{30 * time.Second, newHPA("web", 5, 5), ""},
+		// Scaling again restarts the window.
+		{10 * time.Second, newHPA("web", 6, 6), `HorizontalPodAutoscaler "web" to stay at 6 replicas for 1m0s`},
+		{time.Minute, newHPA("web", 6, 6), ""},
+		// Autoscalers are tracked separately.
+		{0, newHPA("api", 3, 3), `HorizontalPodAutoscaler "api" to stay at 3 replicas for 1m0s`},
+	}
+	for i, step := range steps {
+		now = now.Add(step.after)
+		if got := s.pending(step.hpa); got != step.pending {
+			t.Errorf("step %d: expected %q, got %q", i, step.pending, got)
+		}
+	}
+}
+
+func TestHPAStabilizationDisabled(t *testing.T) {
+	var s *hpaStabilization
+	if got := s.pending(newHPA("web", 2, 5)); got != "" {
+		t.Errorf("expected no wait without a tracker, got %q", got)
+	}
+	if got := newHPAStabilization(0).pending(newHPA("web", 2, 5)); got != "" {
+		t.Errorf("expected no wait without a window, got %q", got)
+	}
+}
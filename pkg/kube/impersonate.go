@@ -0,0 +1,162 @@
+/*
+Copyright 2017 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kube // import "k8s.io/helm/pkg/kube"
+
+import (
+	goerrors "errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/spf13/pflag"
+	"k8s.io/apimachinery/pkg/api/errors"
+	restclient "k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+	cmdutil "k8s.io/kubernetes/pkg/kubectl/cmd/util"
+)
+
+// impersonatingConfig is a client config whose clients act as another
+// identity.
+type impersonatingConfig struct {
+	config      clientcmd.ClientConfig
+	impersonate restclient.ImpersonationConfig
+}
+
+// RawConfig returns the raw config of the wrapped config.
+func (c impersonatingConfig) RawConfig() (clientcmdapi.Config, error) {
+	return c.config.RawConfig()
+}
+
+// ClientConfig returns the client config of the wrapped config with the
+// impersonation set.
+func (c impersonatingConfig) ClientConfig() (*restclient.Config, error) {
+	config, err := c.config.ClientConfig()
+	if err != nil {
+		return nil, err
+	}
+	config.Impersonate = c.impersonate
+	return config, nil
+}
+
+// Namespace returns the namespace of the wrapped config.
+func (c impersonatingConfig) Namespace() (string, bool, error) {
+	return c.config.Namespace()
+}
+
+// ConfigAccess returns the config access of the wrapped config.
+func (c impersonatingConfig) ConfigAccess() clientcmd.ConfigAccess {
+	return c.config.ConfigAccess()
+}
+
+// impersonations caches the factories of the identities a client
+// impersonated, so that later operations as the same identity neither build
+// a factory nor check the impersonation again. Only permitted impersonations
+// are cached; the API server still checks every request made with them.
+type impersonations struct {
+	mu        sync.Mutex
+	factories map[string]impersonation
+}
+
+// impersonation is the config and factory of an impersonated identity.
+type impersonation struct {
+	config  clientcmd.ClientConfig
+	factory cmdutil.Factory
+}
+
+// identityKey identifies a user and its groups, in any order.
+func identityKey(user string, groups []string) string {
+	g := append([]string(nil), groups...)
+	sort.Strings(g)
+	return user + "\x00" + strings.Join(g, "\x00")
+}
+
+func (i *impersonations) get(key string) (impersonation, bool) {
+	if i == nil {
+		return impersonation{}, false
+	}
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	imp, ok := i.factories[key]
+	return imp, ok
+}
+
+func (i *impersonations) put(key string, imp impersonation) {
+	if i == nil {
+		return
+	}
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	if i.factories == nil {
+		i.factories = map[string]impersonation{}
+	}
+	i.factories[key] = imp
+}
+
+// Impersonate returns a copy of the client whose requests act as user, a
+// member of groups, so that the RBAC rules and audit log of the API server
+// apply to that identity instead of to the client's own. A service account
+// is impersonated as "system:serviceaccount:NAMESPACE:NAME".
+//
+// The first time an identity is impersonated, it makes a request as user
+// right away, and fails if the API server does not let the client
+// impersonate it. Later copies for the same identity reuse its factory.
+func (c *Client) Impersonate(user string, groups []string) (*Client, error) {
+	if user == "" {
+		return nil, goerrors.New("impersonation requires a user")
+	}
+	n := *c
+	key := identityKey(user, groups)
+	if imp, ok := c.impersonations.get(key); ok {
+		n.config, n.Factory = imp.config, imp.factory
+		return &n, nil
+	}
+	config := c.config
+	if config == nil {
+		config = cmdutil.DefaultClientConfig(pflag.NewFlagSet("", pflag.ContinueOnError))
+	}
+	n.config = impersonatingConfig{
+		config:      config,
+		impersonate: restclient.ImpersonationConfig{UserName: user, Groups: groups},
+	}
+	n.Factory = cmdutil.NewFactory(n.config)
+
+	cs, err := n.ClientSet()
+	if err != nil {
+		return nil, err
+	}
+	// The API server checks the impersonation of every request before
+	// anything else, so the version, which any user may read, tells whether
+	// it is permitted.
+	if err := cs.Core().RESTClient().Get().AbsPath("/version").Do().Error(); err != nil {
+		if errors.IsForbidden(err) || errors.IsUnauthorized(err) {
+			return nil, fmt.Errorf("not allowed to impersonate %s: %s", describeIdentity(user, groups), err)
+		}
+		return nil, fmt.Errorf("cannot impersonate %s: %s", describeIdentity(user, groups), err)
+	}
+	c.impersonations.put(key, impersonation{config: n.config, factory: n.Factory})
+	return &n, nil
+}
+
+// describeIdentity names a user and its groups in messages.
+func describeIdentity(user string, groups []string) string {
+	if len(groups) == 0 {
+		return fmt.Sprintf("user %q", user)
+	}
+	return fmt.Sprintf("user %q in groups %s", user, strings.Join(groups, ", "))
+}
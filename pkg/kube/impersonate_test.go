@@ -0,0 +1,96 @@
+/*
+Copyright 2017 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kube
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"k8s.io/client-go/tools/clientcmd"
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+)
+
+func TestImpersonate(t *testing.T) {
+	var groups []string
+	checks := 0
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		groups = r.Header["Impersonate-Group"]
+		if r.URL.Path == "/version" {
+			checks++
+		}
+		w.Header().Set("Content-Type", "application/json")
+		if user := r.Header.Get("Impersonate-User"); user != "system:serviceaccount:ci:deployer" {
+			w.WriteHeader(http.StatusForbidden)
+			w.Write([]byte(`{"kind": "Status", "apiVersion": "v1", "status": "Failure", "reason": "Forbidden", "code": 403,
+				"message": "users \"` + user + `\" is forbidden: User \"tiller\" cannot impersonate users at the cluster scope"}`))
+			return
+		}
+		w.Write([]byte(`{"major": "1", "minor": "8", "gitVersion": "v1.8.0"}`))
+	}))
+	defer ts.Close()
+
+	config := clientcmd.NewDefaultClientConfig(clientcmdapi.Config{
+		Clusters:       map[string]*clientcmdapi.Cluster{"test": {Server: ts.URL}},
+		Contexts:       map[string]*clientcmdapi.Context{"test": {Cluster: "test"}},
+		CurrentContext: "test",
+	}, &clientcmd.ConfigOverrides{})
+	c := New(config)
+
+	ic, err := c.Impersonate("system:serviceaccount:ci:deployer", []string{"deployers"})
+	if err != nil {
+		t.Fatalf("Failed to impersonate: %s", err)
+	}
+	if !reflect.DeepEqual(groups, []string{"deployers"}) {
+		t.Errorf("Expected the groups to be impersonated, got %v", groups)
+	}
+	rc, err := ic.ClientConfig()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rc.Impersonate.UserName != "system:serviceaccount:ci:deployer" {
+		t.Errorf("Expected the client to impersonate the service account, got %+v", rc.Impersonate)
+	}
+	if rc, _ := c.ClientConfig(); rc.Impersonate.UserName != "" {
+		t.Errorf("Expected the original client to be left alone, got %+v", rc.Impersonate)
+	}
+
+	// The same identity, even from a copy of the client, reuses the factory
+	// without checking the impersonation again.
+	cp := *c
+	again, err := cp.Impersonate("system:serviceaccount:ci:deployer", []string{"deployers"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if checks != 1 {
+		t.Errorf("Expected the impersonation to be checked once, got %d checks", checks)
+	}
+	if again.Factory != ic.Factory {
+		t.Error("Expected the factory of the identity to be reused")
+	}
+
+	_, err = c.Impersonate("jane", nil)
+	if err == nil || !strings.HasPrefix(err.Error(), `not allowed to impersonate user "jane": `) || !strings.Contains(err.Error(), "cannot impersonate users") {
+		t.Errorf("Expected the rejection to be reported, got %v", err)
+	}
+
+	if _, err := c.Impersonate("", []string{"deployers"}); err == nil {
+		t.Error("Expected impersonating groups without a user to fail")
+	}
+}
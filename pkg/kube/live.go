@@ -0,0 +1,64 @@
+/*
+Copyright 2017 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kube // import "k8s.io/helm/pkg/kube"
+
+import (
+	"fmt"
+	"io"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/kubernetes/pkg/kubectl/resource"
+)
+
+// LiveObjects gets the live state of one or more resources, as they are
+// encoded in JSON, keyed by "Kind/name". Resources that do not exist are left
+// out.
+//
+// namespace must contain a valid existing namespace.
+//
+// reader must contain a YAML stream (one or more YAML documents separated
+// by "\n---\n").
+func (c *Client) LiveObjects(namespace string, reader io.Reader) (_ map[string]interface{}, err error) {
+	defer observe("live", time.Now(), &err)
+
+	infos, err := c.BuildUnstructured(namespace, reader)
+	if err != nil {
+		return nil, err
+	}
+	return liveObjects(infos)
+}
+
+func liveObjects(infos Result) (map[string]interface{}, error) {
+	objs := map[string]interface{}{}
+	for _, info := range infos {
+		kind := info.Mapping.GroupVersionKind.Kind
+		live, err := resource.NewHelper(info.Client, info.Mapping).Get(info.Namespace, info.Name, false)
+		switch {
+		case errors.IsNotFound(err):
+			continue
+		case err != nil:
+			return nil, fmt.Errorf("cannot get %s %q: %s", kind, info.Name, err)
+		}
+		fields, err := objectFields(live)
+		if err != nil {
+			return nil, fmt.Errorf("cannot encode %s %q: %s", kind, info.Name, err)
+		}
+		objs[kind+"/"+info.Name] = fields
+	}
+	return objs, nil
+}
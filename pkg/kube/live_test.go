@@ -0,0 +1,65 @@
+/*
+Copyright 2017 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kube
+
+import (
+	"net/http"
+	"testing"
+
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/rest/fake"
+	"k8s.io/kubernetes/pkg/api"
+	cmdtesting "k8s.io/kubernetes/pkg/kubectl/cmd/testing"
+)
+
+func TestLiveObjects(t *testing.T) {
+	target := newPodList("otter", "squid")
+	live := target.Items[0]
+	live.Status.PodIP = "10.0.0.7"
+
+	f, tf, codec, _ := cmdtesting.NewAPIFactory()
+	tf.UnstructuredClient = &fake.RESTClient{
+		APIRegistry:          api.Registry,
+		NegotiatedSerializer: dynamic.ContentConfig().NegotiatedSerializer,
+		Client: fake.CreateHTTPClient(func(req *http.Request) (*http.Response, error) {
+			p, m := req.URL.Path, req.Method
+			switch {
+			case p == "/namespaces/default/pods/otter" && m == "GET":
+				return newResponse(200, &live)
+			case p == "/namespaces/default/pods/squid" && m == "GET":
+				return newResponse(404, notFoundBody())
+			}
+			t.Fatalf("unexpected request: %s %s", req.Method, req.URL.Path)
+			return nil, nil
+		}),
+	}
+
+	objs, err := newTestClient(f).LiveObjects(api.NamespaceDefault, objBody(codec, &target))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(objs) != 1 {
+		t.Fatalf("Expected only the existing pod, got %v", objs)
+	}
+	pod, ok := objs["Pod/otter"].(map[string]interface{})
+	if !ok {
+		t.Fatalf("Expected Pod/otter, got %v", objs)
+	}
+	if ip := pod["status"].(map[string]interface{})["podIP"]; ip != "10.0.0.7" {
+		t.Errorf("Expected the live status, got pod IP %v", ip)
+	}
+}
@@ -0,0 +1,45 @@
+/*
+Copyright 2017 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kube // import "k8s.io/helm/pkg/kube"
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var kubeOperationDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+	Namespace: "tiller",
+	Name:      "kube_operation_duration_seconds",
+	Help:      "Duration of Kubernetes client operations, including waits, by operation and status.",
+	Buckets:   []float64{0.05, 0.1, 0.5, 1, 2.5, 5, 10, 30, 60, 120, 300, 600},
+}, []string{"operation", "status"})
+
+func init() {
+	prometheus.MustRegister(kubeOperationDuration)
+}
+
+// observe records the duration of a client operation that started at start,
+// and whether *err reports that it failed. It is meant to be deferred by
+// operations with a named error result.
+func observe(operation string, start time.Time, err *error) {
+	status := "success"
+	if *err != nil {
+		status = "failure"
+	}
+	kubeOperationDuration.WithLabelValues(operation, status).Observe(time.Since(start).Seconds())
+}
@@ -0,0 +1,60 @@
+/*
+Copyright 2017 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kube
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+)
+
+func kubeSamples(t *testing.T, operation, status string) uint64 {
+	m := &dto.Metric{}
+	if err := kubeOperationDuration.WithLabelValues(operation, status).(prometheus.Metric).Write(m); err != nil {
+		t.Fatal(err)
+	}
+	return m.GetHistogram().GetSampleCount()
+}
+
+func TestObserve(t *testing.T) {
+	successes := kubeSamples(t, "test", "success")
+	failures := kubeSamples(t, "test", "failure")
+
+	var err error
+	observe("test", time.Now(), &err)
+	err = errors.New("boom")
+	observe("test", time.Now(), &err)
+
+	if got := kubeSamples(t, "test", "success"); got != successes+1 {
+		t.Errorf("Expected %d successes, got %d", successes+1, got)
+	}
+	if got := kubeSamples(t, "test", "failure"); got != failures+1 {
+		t.Errorf("Expected %d failures, got %d", failures+1, got)
+	}
+}
+
+func TestObserveDeferred(t *testing.T) {
+	failures := kubeSamples(t, "restart", "failure")
+	// Restarting a pod fails, and the deferred observation sees the error.
+	TestRestartRefusesOtherKinds(t)
+	if got := kubeSamples(t, "restart", "failure"); got != failures+1 {
+		t.Errorf("Expected %d failed restarts, got %d", failures+1, got)
+	}
+}
@@ -0,0 +1,167 @@
+/*
+Copyright 2017 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kube // import "k8s.io/helm/pkg/kube"
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+
+	jsonpatch "github.com/evanphx/json-patch"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/jsonmergepatch"
+	"k8s.io/apimachinery/pkg/util/strategicpatch"
+	"k8s.io/kubernetes/pkg/api"
+)
+
+// PatchStrategyAnno is the resource annotation that chooses how an upgrade
+// patches the resource, overriding Client.PatchStrategy.
+const PatchStrategyAnno = "helm.sh/patch-strategy"
+
+const (
+	// StrategicPatch patches a resource with a strategic merge patch from
+	// its previous manifest to its new one. Lists such as the containers of
+	// a pod are merged by key. It is not available for custom resources.
+	StrategicPatch = "strategic"
+	// MergePatch patches a resource with a JSON merge patch from its
+	// previous manifest to its new one. Lists are replaced as a whole.
+	MergePatch = "merge"
+	// ThreeWayPatch patches a resource from its live state to its new
+	// manifest, removing only the fields that its previous manifest set. It
+	// is a strategic merge patch, or a JSON merge patch for custom
+	// resources.
+	ThreeWayPatch = "three-way"
+)
+
+// ValidPatchStrategy returns an error if strategy is not a patch strategy.
+// An empty strategy is the default one.
+func ValidPatchStrategy(strategy string) error {
+	switch strategy {
+	case "", StrategicPatch, MergePatch, ThreeWayPatch:
+		return nil
+	}
+	return fmt.Errorf("invalid patch strategy %q: must be %s, %s or %s", strategy, StrategicPatch, MergePatch, ThreeWayPatch)
+}
+
+// patchStrategy returns the strategy to patch target with: that of its
+// annotation, or def. A default of StrategicPatch leaves custom resources to
+// the default JSON merge patch, as only the annotation can ask for a patch
+// that they do not support.
+func patchStrategy(target runtime.Object, def string) (string, error) {
+	if def == StrategicPatch {
+		def = ""
+	}
+	accessor, err := meta.Accessor(target)
+	if err != nil {
+		return def, nil
+	}
+	strategy, ok := accessor.GetAnnotations()[PatchStrategyAnno]
+	if !ok {
+		return def, nil
+	}
+	if err := ValidPatchStrategy(strategy); err != nil {
+		return "", fmt.Errorf("%s annotation of %q: %s", PatchStrategyAnno, accessor.GetName(), err)
+	}
+	return strategy, nil
+}
+
+// createPatch returns the patch that changes current, the previous manifest
+// of a resource, into target, with the given strategy. live is the live state
+// of the resource, which three-way patches and preserved fields use. An empty
+// strategy is a strategic merge patch, or a JSON merge patch for kinds that
+// strategic merge patches are not available for, such as custom resources.
+func createPatch(mapping *meta.RESTMapping, target, current, live runtime.Object, strategy string) ([]byte, types.PatchType, error) {
+	oldData, err := json.Marshal(current)
+	if err != nil {
+		return nil, types.StrategicMergePatchType, fmt.Errorf("serializing current configuration: %s", err)
+	}
+	newData, err := json.Marshal(target)
+	if err != nil {
+		return nil, types.StrategicMergePatchType, fmt.Errorf("serializing target configuration: %s", err)
+	}
+
+	// Fields that are preserved take their live value on both sides, so that
+	// the patch leaves them alone. Without the live state, the previous
+	// manifest stands in for it.
+	paths, err := preservedFields(target)
+	if err != nil {
+		return nil, types.StrategicMergePatchType, err
+	}
+	if len(paths) > 0 {
+		from := live
+		if from == nil {
+			from = current
+		}
+		fromData, err := json.Marshal(from)
+		if err != nil {
+			return nil, types.StrategicMergePatchType, fmt.Errorf("serializing live configuration: %s", err)
+		}
+		if oldData, err = preserveFields(oldData, fromData, paths); err != nil {
+			return nil, types.StrategicMergePatchType, fmt.Errorf("preserving fields of current configuration: %s", err)
+		}
+		if newData, err = preserveFields(newData, fromData, paths); err != nil {
+			return nil, types.StrategicMergePatchType, fmt.Errorf("preserving fields of target configuration: %s", err)
+		}
+	}
+
+	// Get a versioned object
+	versionedObject, err := api.Scheme.New(mapping.GroupVersionKind)
+	registered := true
+	switch {
+	case runtime.IsNotRegisteredError(err):
+		registered = false
+	case err != nil:
+		return nil, types.StrategicMergePatchType, fmt.Errorf("failed to get versionedObject: %s", err)
+	}
+
+	if strategy == ThreeWayPatch {
+		liveData, err := json.Marshal(live)
+		if err != nil {
+			return nil, types.StrategicMergePatchType, fmt.Errorf("serializing live configuration: %s", err)
+		}
+		var patch []byte
+		patchType := types.MergePatchType
+		if registered {
+			patchType = types.StrategicMergePatchType
+			patch, err = strategicpatch.CreateThreeWayMergePatch(oldData, newData, liveData, versionedObject, true)
+		} else {
+			patch, err = jsonmergepatch.CreateThreeWayJSONMergePatch(oldData, newData, liveData)
+		}
+		if err != nil || string(patch) == "{}" {
+			return nil, patchType, err
+		}
+		return patch, patchType, nil
+	}
+
+	if api.Semantic.DeepEqual(oldData, newData) {
+		return nil, types.StrategicMergePatchType, nil
+	}
+
+	switch {
+	case strategy == MergePatch || strategy == "" && !registered:
+		patch, err := jsonpatch.CreateMergePatch(oldData, newData)
+		return patch, types.MergePatchType, err
+	case strategy == StrategicPatch && !registered:
+		return nil, types.StrategicMergePatchType, fmt.Errorf("strategic merge patches are not available for %s; use the %s or %s patch strategy", mapping.GroupVersionKind.Kind, MergePatch, ThreeWayPatch)
+	default:
+		log.Printf("generating strategic merge patch for %T", target)
+		patch, err := strategicpatch.CreateTwoWayMergePatch(oldData, newData, versionedObject)
+		return patch, types.StrategicMergePatchType, err
+	}
+}
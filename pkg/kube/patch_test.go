@@ -0,0 +1,155 @@
+/*
+Copyright 2017 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kube
+
+import (
+	"strings"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+func podWithContainers(containers ...interface{}) *unstructured.Unstructured {
+	return &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "v1",
+		"kind":       "Pod",
+		"metadata":   map[string]interface{}{"name": "web"},
+		"spec":       map[string]interface{}{"containers": containers},
+	}}
+}
+
+func container(name, image string) map[string]interface{} {
+	return map[string]interface{}{"name": name, "image": image}
+}
+
+func TestCreatePatchStrategies(t *testing.T) {
+	mapping := &meta.RESTMapping{GroupVersionKind: schema.GroupVersionKind{Version: "v1", Kind: "Pod"}}
+	current := podWithContainers(container("app", "app:1"), container("metrics", "metrics:1"))
+	target := podWithContainers(container("app", "app:2"), container("metrics", "metrics:1"))
+	// A sidecar was injected into the live pod.
+	live := podWithContainers(container("app", "app:1"), container("metrics", "metrics:1"), container("proxy", "proxy:1"))
+
+	tests := []struct {
+		strategy  string
+		patchType types.PatchType
+		patch     string
+	}{
+		// The containers are merged by name: only the image of "app" changes.
+		{"", types.StrategicMergePatchType, `{"spec":{"containers":[{"image":"app:2","name":"app"}]}}`},
+		{StrategicPatch, types.StrategicMergePatchType, `{"spec":{"containers":[{"image":"app:2","name":"app"}]}}`},
+		// The list is replaced as a whole, which would drop the sidecar.
+		{MergePatch, types.MergePatchType, `{"spec":{"containers":[{"image":"app:2","name":"app"},{"image":"metrics:1","name":"metrics"}]}}`},
+		// The sidecar, which the previous manifest did not set, is kept.
+		{ThreeWayPatch, types.StrategicMergePatchType, `{"spec":{"containers":[{"image":"app:2","name":"app"}]}}`},
+	}
+	for _, tt := range tests {
+		patch, patchType, err := createPatch(mapping, target, current, live, tt.strategy)
+		if err != nil {
+			t.Fatalf("%q: %s", tt.strategy, err)
+		}
+		if patchType != tt.patchType {
+			t.Errorf("%q: expected patch type %s, got %s", tt.strategy, tt.patchType, patchType)
+		}
+		if string(patch) != tt.patch {
+			t.Errorf("%q: expected patch\n%s\ngot\n%s", tt.strategy, tt.patch, patch)
+		}
+	}
+}
+
+func TestCreatePatchThreeWayResetsLiveEdits(t *testing.T) {
+	mapping := &meta.RESTMapping{GroupVersionKind: schema.GroupVersionKind{Version: "v1", Kind: "Pod"}}
+	manifest := podWithContainers(container("app", "app:1"))
+	// Someone edited the image of the live pod.
+	live := podWithContainers(container("app", "app:debug"))
+
+	patch, _, err := createPatch(mapping, manifest, manifest, live, "")
+	if err != nil || patch != nil {
+		t.Errorf("Expected a two-way patch to ignore the live edit, got %s (%v)", patch, err)
+	}
+	patch, _, err = createPatch(mapping, manifest, manifest, live, ThreeWayPatch)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(patch), `"image":"app:1"`) {
+		t.Errorf("Expected a three-way patch to reset the live edit, got %s", patch)
+	}
+}
+
+func TestCreatePatchCustomResource(t *testing.T) {
+	mapping := &meta.RESTMapping{GroupVersionKind: schema.GroupVersionKind{Group: "example.com", Version: "v1", Kind: "Widget"}}
+	widget := func(sizes ...interface{}) *unstructured.Unstructured {
+		return &unstructured.Unstructured{Object: map[string]interface{}{
+			"apiVersion": "example.com/v1",
+			"kind":       "Widget",
+			"metadata":   map[string]interface{}{"name": "w"},
+			"spec":       map[string]interface{}{"sizes": sizes},
+		}}
+	}
+	current, target := widget("s", "m"), widget("m", "l")
+
+	for _, strategy := range []string{"", MergePatch} {
+		patch, patchType, err := createPatch(mapping, target, current, nil, strategy)
+		if err != nil {
+			t.Fatalf("%q: %s", strategy, err)
+		}
+		if patchType != types.MergePatchType || string(patch) != `{"spec":{"sizes":["m","l"]}}` {
+			t.Errorf("%q: expected a JSON merge patch, got %s %s", strategy, patchType, patch)
+		}
+	}
+
+	patch, patchType, err := createPatch(mapping, target, current, widget("s", "m", "xl"), ThreeWayPatch)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if patchType != types.MergePatchType || string(patch) != `{"spec":{"sizes":["m","l"]}}` {
+		t.Errorf("Expected a three-way JSON merge patch, got %s %s", patchType, patch)
+	}
+
+	if _, _, err := createPatch(mapping, target, current, nil, StrategicPatch); err == nil || !strings.Contains(err.Error(), "not available for Widget") {
+		t.Errorf("Expected strategic merge patches to be refused, got %v", err)
+	}
+}
+
+func TestPatchStrategy(t *testing.T) {
+	pod := podWithContainers()
+	for _, tt := range []struct {
+		anno, def, expect string
+	}{
+		{"", "", ""},
+		{"", StrategicPatch, ""},
+		{"", ThreeWayPatch, ThreeWayPatch},
+		{MergePatch, ThreeWayPatch, MergePatch},
+		{StrategicPatch, "", StrategicPatch},
+	} {
+		if tt.anno != "" {
+			pod.SetAnnotations(map[string]string{PatchStrategyAnno: tt.anno})
+		} else {
+			pod.SetAnnotations(nil)
+		}
+		if got, err := patchStrategy(pod, tt.def); err != nil || got != tt.expect {
+			t.Errorf("annotation %q, default %q: expected %q, got %q (%v)", tt.anno, tt.def, tt.expect, got, err)
+		}
+	}
+
+	pod.SetAnnotations(map[string]string{PatchStrategyAnno: "replace"})
+	if _, err := patchStrategy(pod, ""); err == nil || !strings.Contains(err.Error(), `invalid patch strategy "replace"`) {
+		t.Errorf("Expected an invalid strategy to be rejected, got %v", err)
+	}
+}
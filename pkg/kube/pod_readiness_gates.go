@@ -0,0 +1,142 @@
+/*
+Copyright 2017 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kube // import "k8s.io/helm/pkg/kube"
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/kubernetes/pkg/api/v1"
+	"k8s.io/kubernetes/pkg/client/clientset_generated/clientset"
+	"k8s.io/kubernetes/pkg/kubectl/resource"
+)
+
+// PodReadinessGatesWaitAnno is the annotation that, when set to "false" on a
+// Pod or a workload, stops a wait from holding until the readiness gates of
+// its pods pass. The pods still have to be ready.
+const PodReadinessGatesWaitAnno = "helm.sh/wait-for-readiness-gates"
+
+// gatedPod is a pod with the condition types of its readiness gates.
+type gatedPod struct {
+	v1.Pod
+	gates []string
+}
+
+// rawPodGates is the part of a raw pod that holds its readiness gates. They
+// are read from the raw object, as spec.readinessGates is newer than the API
+// types Helm is built with.
+type rawPodGates struct {
+	Spec struct {
+		ReadinessGates []struct {
+			ConditionType string `json:"conditionType"`
+		} `json:"readinessGates"`
+	} `json:"spec"`
+}
+
+// decodeGatedPod decodes a raw pod.
+func decodeGatedPod(raw []byte) (gatedPod, error) {
+	var p gatedPod
+	if err := json.Unmarshal(raw, &p.Pod); err != nil {
+		return p, err
+	}
+	var g rawPodGates
+	if err := json.Unmarshal(raw, &g); err != nil {
+		return p, err
+	}
+	for _, gate := range g.Spec.ReadinessGates {
+		p.gates = append(p.gates, gate.ConditionType)
+	}
+	return p, nil
+}
+
+// decodeGatedPods decodes a raw pod list.
+func decodeGatedPods(raw []byte) ([]gatedPod, error) {
+	var list struct {
+		Items []json.RawMessage `json:"items"`
+	}
+	if err := json.Unmarshal(raw, &list); err != nil {
+		return nil, err
+	}
+	pods := make([]gatedPod, 0, len(list.Items))
+	for _, item := range list.Items {
+		p, err := decodeGatedPod(item)
+		if err != nil {
+			return nil, err
+		}
+		pods = append(pods, p)
+	}
+	return pods, nil
+}
+
+// getGatedPod returns the named pod with its readiness gates.
+func getGatedPod(client clientset.Interface, namespace, name string) (gatedPod, error) {
+	raw, err := client.Core().RESTClient().Get().Namespace(namespace).Resource("pods").Name(name).DoRaw()
+	if err != nil {
+		return gatedPod{}, err
+	}
+	return decodeGatedPod(raw)
+}
+
+// getGatedPods returns the pods that match selector with their readiness
+// gates.
+func getGatedPods(client clientset.Interface, namespace string, selector map[string]string) ([]gatedPod, error) {
+	raw, err := client.Core().RESTClient().Get().Namespace(namespace).Resource("pods").
+		Param("labelSelector", labels.Set(selector).AsSelector().String()).DoRaw()
+	if err != nil {
+		return nil, err
+	}
+	return decodeGatedPods(raw)
+}
+
+// pendingPodGate returns a description of the first readiness gate of p whose
+// condition is not "True" yet, or "" if all of them are.
+func pendingPodGate(p gatedPod) string {
+	for _, gate := range p.gates {
+		if !podConditionTrue(p.Pod, gate) {
+			return fmt.Sprintf("readiness gate %s of Pod %s/%s", gate, p.Namespace, p.Name)
+		}
+	}
+	return ""
+}
+
+func podConditionTrue(pod v1.Pod, condType string) bool {
+	for _, c := range pod.Status.Conditions {
+		if string(c.Type) == condType {
+			return c.Status == v1.ConditionTrue
+		}
+	}
+	return false
+}
+
+// waitForPodGates returns false if the resource of info opts out of waiting
+// for the readiness gates of its pods with PodReadinessGatesWaitAnno.
+func waitForPodGates(info *resource.Info) bool {
+	accessor, err := meta.Accessor(info.Object)
+	if err != nil {
+		return true
+	}
+	return accessor.GetAnnotations()[PodReadinessGatesWaitAnno] != "false"
+}
+
+// ignoreGates drops the readiness gates of pods.
+func ignoreGates(pods []gatedPod) {
+	for i := range pods {
+		pods[i].gates = nil
+	}
+}
@@ -0,0 +1,170 @@
+/*
+Copyright 2017 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kube
+
+import (
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/kubernetes/pkg/api/v1"
+	extensions "k8s.io/kubernetes/pkg/apis/extensions/v1beta1"
+	"k8s.io/kubernetes/pkg/kubectl/resource"
+)
+
+const gatedPodList = `{
+  "kind": "PodList",
+  "apiVersion": "v1",
+  "items": [
+    {
+      "metadata": {"name": "web-1", "namespace": "default"},
+      "spec": {"readinessGates": [{"conditionType": "example.com/lb-registered"}]},
+      "status": {"conditions": [
+        {"type": "Ready", "status": "True"},
+        {"type": "example.com/lb-registered", "status": "False"}
+      ]}
+    },
+    {
+      "metadata": {"name": "web-2", "namespace": "default"},
+      "status": {"conditions": [{"type": "Ready", "status": "True"}]}
+    }
+  ]
+}`
+
+func TestDecodeGatedPods(t *testing.T) {
+	pods, err := decodeGatedPods([]byte(gatedPodList))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(pods) != 2 || pods[0].Name != "web-1" || len(pods[0].gates) != 1 || pods[0].gates[0] != "example.com/lb-registered" || len(pods[1].gates) != 0 {
+		t.Fatalf("Unexpected pods: %+v", pods)
+	}
+	if !v1.IsPodReady(&pods[0].Pod) {
+		t.Error("Expected the pod to be decoded with its conditions")
+	}
+}
+
+// readyPod returns a ready pod with gates, of which those in passed are true.
+func readyPod(name string, gates []string, passed ...string) gatedPod {
+	p := gatedPod{gates: gates}
+	p.Name = name
+	p.Namespace = "default"
+	p.Status.Conditions = []v1.PodCondition{{Type: v1.PodReady, Status: v1.ConditionTrue}}
+	for _, c := range passed {
+		p.Status.Conditions = append(p.Status.Conditions, v1.PodCondition{Type: v1.PodConditionType(c), Status: v1.ConditionTrue})
+	}
+	return p
+}
+
+func TestPodsReadyWithGates(t *testing.T) {
+	gates := []string{"example.com/lb-registered", "example.com/warm"}
+
+	if !podsReady([]gatedPod{readyPod("web-1", nil)}) {
+		t.Error("Expected a ready pod without gates to be ready")
+	}
+	pending := []gatedPod{readyPod("web-1", gates, "example.com/lb-registered")}
+	if podsReady(pending) {
+		t.Error("Expected a pod with a gate that has not passed not to be ready")
+	}
+	if got := pendingPodGates(pending, nil); got != "readiness gate example.com/warm of Pod default/web-1" {
+		t.Errorf("Unexpected pending gate %q", got)
+	}
+	if !podsReady([]gatedPod{readyPod("web-1", gates, gates...)}) {
+		t.Error("Expected a pod whose gates passed to be ready")
+	}
+
+	ignoreGates(pending)
+	if !podsReady(pending) {
+		t.Error("Expected ignored gates not to be waited for")
+	}
+}
+
+func TestDeploymentsReadyWithGates(t *testing.T) {
+	replicas := int32(2)
+	d := deployment{
+		replicaSets: &extensions.ReplicaSet{Status: extensions.ReplicaSetStatus{ReadyReplicas: 2}},
+		deployment:  &extensions.Deployment{Spec: extensions.DeploymentSpec{Replicas: &replicas, Strategy: extensions.DeploymentStrategy{Type: extensions.RecreateDeploymentStrategyType}}},
+	}
+	if !deploymentsReady([]deployment{d}) {
+		t.Fatal("Expected a deployment with ready replicas to be ready")
+	}
+
+	gates := []string{"example.com/lb-registered"}
+	d.pods = []gatedPod{readyPod("web-1", gates, gates...), readyPod("web-2", gates)}
+	if deploymentsReady([]deployment{d}) {
+		t.Error("Expected a replica whose gate has not passed not to count as ready")
+	}
+	if got := pendingPodGates(nil, []deployment{d}); got != "readiness gate example.com/lb-registered of Pod default/web-2" {
+		t.Errorf("Unexpected pending gate %q", got)
+	}
+
+	d.pods[1] = readyPod("web-2", gates, gates...)
+	if !deploymentsReady([]deployment{d}) {
+		t.Error("Expected the deployment to be ready once the gates passed")
+	}
+}
+
+func TestDeploymentGatesCountAgainstNeeded(t *testing.T) {
+	// A rolling update may leave one of the four replicas unavailable, so
+	// three that passed their gates are enough.
+	replicas := int32(4)
+	unavailable, surge := intstr.FromInt(1), intstr.FromInt(1)
+	d := deployment{
+		replicaSets: &extensions.ReplicaSet{Status: extensions.ReplicaSetStatus{ReadyReplicas: 4}},
+		deployment: &extensions.Deployment{Spec: extensions.DeploymentSpec{
+			Replicas: &replicas,
+			Strategy: extensions.DeploymentStrategy{
+				Type:          extensions.RollingUpdateDeploymentStrategyType,
+				RollingUpdate: &extensions.RollingUpdateDeployment{MaxUnavailable: &unavailable, MaxSurge: &surge},
+			},
+		}},
+	}
+	gates := []string{"example.com/lb-registered"}
+	d.pods = []gatedPod{
+		readyPod("web-1", gates, gates...),
+		readyPod("web-2", gates, gates...),
+		readyPod("web-3", gates, gates...),
+		readyPod("web-4", gates),
+	}
+	if !deploymentsReady([]deployment{d}) {
+		t.Error("Expected three replicas that passed their gates to be enough")
+	}
+	if got := pendingPodGates(nil, []deployment{d}); got != "" {
+		t.Errorf("Expected no pending gate, got %q", got)
+	}
+
+	// The ready threshold of the deployment sets what is needed instead.
+	d.threshold = 1
+	if deploymentsReady([]deployment{d}) {
+		t.Error("Expected the threshold to require every replica")
+	}
+	if got := pendingPodGates(nil, []deployment{d}); got != "readiness gate example.com/lb-registered of Pod default/web-4" {
+		t.Errorf("Unexpected pending gate %q", got)
+	}
+}
+
+func TestWaitForPodGates(t *testing.T) {
+	info := func(annotations map[string]string) *resource.Info {
+		return &resource.Info{Object: &extensions.Deployment{ObjectMeta: metav1.ObjectMeta{Name: "web", Annotations: annotations}}}
+	}
+	if !waitForPodGates(info(nil)) {
+		t.Error("Expected readiness gates to be waited for by default")
+	}
+	if waitForPodGates(info(map[string]string{PodReadinessGatesWaitAnno: "false"})) {
+		t.Error("Expected the annotation to opt out of waiting for readiness gates")
+	}
+}
@@ -0,0 +1,132 @@
+/*
+Copyright 2017 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kube // import "k8s.io/helm/pkg/kube"
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// PreserveFieldAnno is the resource annotation that lists the fields that
+// upgrades leave as they are in the cluster, as comma-separated paths such as
+// "spec.replicas,{.spec.clusterIP}". It is meant for fields that are set when
+// the resource is created and then owned by a controller or the API server.
+const PreserveFieldAnno = "helm.sh/preserve-field"
+
+// preservedFields returns the paths listed by the preserve field annotation
+// of obj, each split into its keys.
+func preservedFields(obj runtime.Object) ([][]string, error) {
+	accessor, err := meta.Accessor(obj)
+	if err != nil {
+		return nil, nil
+	}
+	v, ok := accessor.GetAnnotations()[PreserveFieldAnno]
+	if !ok {
+		return nil, nil
+	}
+	var paths [][]string
+	for _, p := range strings.Split(v, ",") {
+		p = strings.TrimSpace(p)
+		if p == "" {
+			continue
+		}
+		path, err := parseFieldPath(p)
+		if err != nil {
+			return nil, fmt.Errorf("%s annotation of %q: %s", PreserveFieldAnno, accessor.GetName(), err)
+		}
+		paths = append(paths, path)
+	}
+	return paths, nil
+}
+
+// parseFieldPath splits a JSONPath of object fields, such as ".spec.replicas"
+// or "{.spec.replicas}", into its keys. Lists cannot be indexed, as their
+// items do not keep their position across patches.
+func parseFieldPath(p string) ([]string, error) {
+	path := strings.TrimPrefix(strings.TrimSuffix(strings.TrimPrefix(p, "{"), "}"), ".")
+	if strings.ContainsAny(path, "[]*") {
+		return nil, fmt.Errorf("invalid field path %q: only object fields can be preserved", p)
+	}
+	keys := strings.Split(path, ".")
+	for _, k := range keys {
+		if k == "" {
+			return nil, fmt.Errorf("invalid field path %q", p)
+		}
+	}
+	return keys, nil
+}
+
+// preserveFields returns data, a JSON object, with the fields at paths set to
+// their values in from, or removed if from does not have them. A patch
+// between two objects preserved from the same source leaves those fields
+// alone.
+func preserveFields(data, from []byte, paths [][]string) ([]byte, error) {
+	var obj, src map[string]interface{}
+	if err := json.Unmarshal(data, &obj); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(from, &src); err != nil {
+		return nil, err
+	}
+	for _, path := range paths {
+		if v, ok := lookupField(src, path); ok {
+			setField(obj, path, v)
+		} else {
+			removeField(obj, path)
+		}
+	}
+	return json.Marshal(obj)
+}
+
+func lookupField(obj map[string]interface{}, path []string) (interface{}, bool) {
+	for _, k := range path[:len(path)-1] {
+		next, ok := obj[k].(map[string]interface{})
+		if !ok {
+			return nil, false
+		}
+		obj = next
+	}
+	v, ok := obj[path[len(path)-1]]
+	return v, ok
+}
+
+func setField(obj map[string]interface{}, path []string, v interface{}) {
+	for _, k := range path[:len(path)-1] {
+		next, ok := obj[k].(map[string]interface{})
+		if !ok {
+			next = map[string]interface{}{}
+			obj[k] = next
+		}
+		obj = next
+	}
+	obj[path[len(path)-1]] = v
+}
+
+func removeField(obj map[string]interface{}, path []string) {
+	for _, k := range path[:len(path)-1] {
+		next, ok := obj[k].(map[string]interface{})
+		if !ok {
+			return
+		}
+		obj = next
+	}
+	delete(obj, path[len(path)-1])
+}
@@ -0,0 +1,94 @@
+/*
+Copyright 2017 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kube
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+func TestPreservedFields(t *testing.T) {
+	pod := podWithContainers()
+	pod.SetAnnotations(map[string]string{PreserveFieldAnno: " spec.nodeName, {.metadata.labels.owner},"})
+	paths, err := preservedFields(pod)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expect := [][]string{{"spec", "nodeName"}, {"metadata", "labels", "owner"}}
+	if !reflect.DeepEqual(paths, expect) {
+		t.Errorf("Expected %v, got %v", expect, paths)
+	}
+
+	for _, p := range []string{"spec.containers[0].image", "spec..nodeName"} {
+		pod.SetAnnotations(map[string]string{PreserveFieldAnno: p})
+		if _, err := preservedFields(pod); err == nil || !strings.Contains(err.Error(), "invalid field path") {
+			t.Errorf("%s: expected the path to be rejected, got %v", p, err)
+		}
+	}
+}
+
+func scaled(replicas, image interface{}) *unstructured.Unstructured {
+	spec := map[string]interface{}{"image": image}
+	if replicas != nil {
+		spec["replicas"] = replicas
+	}
+	return &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "example.com/v1",
+		"kind":       "Widget",
+		"metadata": map[string]interface{}{
+			"name":        "w",
+			"annotations": map[string]interface{}{PreserveFieldAnno: "spec.replicas"},
+		},
+		"spec": spec,
+	}}
+}
+
+func TestCreatePatchPreservesFields(t *testing.T) {
+	mapping := &meta.RESTMapping{GroupVersionKind: schema.GroupVersionKind{Group: "example.com", Version: "v1", Kind: "Widget"}}
+	current := scaled(3, "app:1")
+	// An autoscaler scaled the live widget.
+	live := scaled(5, "app:1")
+
+	tests := []struct {
+		name     string
+		target   *unstructured.Unstructured
+		live     runtime.Object
+		strategy string
+		expect   string
+	}{
+		{"changed field", scaled(4, "app:2"), live, "", `{"spec":{"image":"app:2"}}`},
+		{"removed field", scaled(nil, "app:2"), live, MergePatch, `{"spec":{"image":"app:2"}}`},
+		{"three-way", scaled(4, "app:2"), live, ThreeWayPatch, `{"spec":{"image":"app:2"}}`},
+		{"without the live state", scaled(4, "app:2"), nil, "", `{"spec":{"image":"app:2"}}`},
+		{"only preserved changes", scaled(4, "app:1"), live, "", ""},
+	}
+	for _, tt := range tests {
+		patch, _, err := createPatch(mapping, tt.target, current, tt.live, tt.strategy)
+		if err != nil {
+			t.Fatalf("%s: %s", tt.name, err)
+		}
+		if string(patch) != tt.expect {
+			t.Errorf("%s: expected patch %q, got %q", tt.name, tt.expect, patch)
+		}
+	}
+}
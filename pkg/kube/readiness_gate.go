@@ -0,0 +1,140 @@
+/*
+Copyright 2017 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kube // import "k8s.io/helm/pkg/kube"
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/kubernetes/pkg/kubectl/resource"
+)
+
+// ReadinessGate makes a wait hold until resources of a kind report a
+// condition as "True" in status.conditions.
+//
+// Gates apply in addition to the built-in checks for Pods, Deployments,
+// Services and the like, and let custom resources take part in a wait.
+type ReadinessGate struct {
+	// Kind selects the resources the gate applies to. An empty Version
+	// matches every version of the group and kind.
+	Kind schema.GroupVersionKind
+	// ConditionType is the condition that must be "True", e.g. "Ready".
+	ConditionType string
+}
+
+func (g ReadinessGate) String() string {
+	if g.Kind.Group == "" {
+		return fmt.Sprintf("%s/%s=%s", g.Kind.Version, g.Kind.Kind, g.ConditionType)
+	}
+	return fmt.Sprintf("%s/%s/%s=%s", g.Kind.Group, g.Kind.Version, g.Kind.Kind, g.ConditionType)
+}
+
+// ParseReadinessGate parses a gate written as "group/version/Kind=Condition".
+//
+// The version may be left empty to match any version, and core kinds are
+// written without a group, e.g. "v1/Pod=Ready" or
+// "certmanager.k8s.io//Certificate=Ready".
+func ParseReadinessGate(s string) (ReadinessGate, error) {
+	var g ReadinessGate
+	parts := strings.SplitN(s, "=", 2)
+	if len(parts) != 2 || parts[1] == "" {
+		return g, fmt.Errorf("invalid readiness gate %q: expected KIND=CONDITION", s)
+	}
+	g.ConditionType = parts[1]
+
+	kind := strings.Split(parts[0], "/")
+	switch len(kind) {
+	case 2:
+		g.Kind = schema.GroupVersionKind{Version: kind[0], Kind: kind[1]}
+	case 3:
+		g.Kind = schema.GroupVersionKind{Group: kind[0], Version: kind[1], Kind: kind[2]}
+	default:
+		return g, fmt.Errorf("invalid readiness gate %q: expected group/version/Kind", s)
+	}
+	if g.Kind.Kind == "" {
+		return g, fmt.Errorf("invalid readiness gate %q: missing kind", s)
+	}
+	return g, nil
+}
+
+// matches returns true if the gate applies to resources of kind gvk.
+func (g ReadinessGate) matches(gvk schema.GroupVersionKind) bool {
+	return g.Kind.Group == gvk.Group && g.Kind.Kind == gvk.Kind &&
+		(g.Kind.Version == "" || g.Kind.Version == gvk.Version)
+}
+
+// pendingGate returns a description of the first gate that a resource in
+// infos has not passed yet, or "" if all gates are satisfied.
+func (c *Client) pendingGate(infos Result) (string, error) {
+	for _, g := range c.ReadinessGates {
+		for _, info := range infos {
+			if !g.matches(info.Mapping.GroupVersionKind) {
+				continue
+			}
+			obj, err := resource.NewHelper(info.Client, info.Mapping).Get(info.Namespace, info.Name, false)
+			if err != nil {
+				return "", err
+			}
+			ok, err := conditionTrue(obj, g.ConditionType)
+			if err != nil {
+				return "", err
+			}
+			if !ok {
+				return fmt.Sprintf("condition %s on %s %q", g.ConditionType, info.Mapping.GroupVersionKind.Kind, info.Name), nil
+			}
+		}
+	}
+	return "", nil
+}
+
+// conditionTrue returns true if obj has a condition of type condType with
+// status "True" in status.conditions.
+func conditionTrue(obj runtime.Object, condType string) (bool, error) {
+	content, err := objectContent(obj)
+	if err != nil {
+		return false, err
+	}
+
+	status, _ := content["status"].(map[string]interface{})
+	conditions, _ := status["conditions"].([]interface{})
+	for _, c := range conditions {
+		cond, ok := c.(map[string]interface{})
+		if !ok || cond["type"] != condType {
+			continue
+		}
+		return cond["status"] == "True", nil
+	}
+	return false, nil
+}
+
+// objectContent returns the fields of obj as a generic map.
+func objectContent(obj runtime.Object) (map[string]interface{}, error) {
+	if u, ok := obj.(*unstructured.Unstructured); ok {
+		return u.Object, nil
+	}
+	data, err := json.Marshal(obj)
+	if err != nil {
+		return nil, err
+	}
+	var content map[string]interface{}
+	err = json.Unmarshal(data, &content)
+	return content, err
+}
@@ -0,0 +1,110 @@
+/*
+Copyright 2017 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kube
+
+import (
+	"net/http"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/rest/fake"
+	"k8s.io/kubernetes/pkg/api"
+	cmdtesting "k8s.io/kubernetes/pkg/kubectl/cmd/testing"
+)
+
+func TestParseReadinessGate(t *testing.T) {
+	tests := []struct {
+		in   string
+		gvk  schema.GroupVersionKind
+		cond string
+		err  bool
+	}{
+		{"v1/Pod=Ready", schema.GroupVersionKind{Version: "v1", Kind: "Pod"}, "Ready", false},
+		{"certmanager.k8s.io/v1alpha1/Certificate=Ready", schema.GroupVersionKind{Group: "certmanager.k8s.io", Version: "v1alpha1", Kind: "Certificate"}, "Ready", false},
+		{"certmanager.k8s.io//Certificate=Ready", schema.GroupVersionKind{Group: "certmanager.k8s.io", Kind: "Certificate"}, "Ready", false},
+		{"Certificate=Ready", schema.GroupVersionKind{}, "", true},
+		{"v1/Pod", schema.GroupVersionKind{}, "", true},
+		{"v1/=Ready", schema.GroupVersionKind{}, "", true},
+	}
+	for _, tt := range tests {
+		g, err := ParseReadinessGate(tt.in)
+		if (err != nil) != tt.err {
+			t.Errorf("%q: expected error %t, got %v", tt.in, tt.err, err)
+			continue
+		}
+		if tt.err {
+			continue
+		}
+		if g.Kind != tt.gvk || g.ConditionType != tt.cond {
+			t.Errorf("%q: unexpected gate %#v", tt.in, g)
+		}
+		if g.String() != tt.in {
+			t.Errorf("%q: expected String() to round trip, got %q", tt.in, g.String())
+		}
+	}
+}
+
+func TestPendingGate(t *testing.T) {
+	ready := newPod("starfish")
+	ready.Status.Conditions = []api.PodCondition{{Type: api.PodReady, Status: api.ConditionTrue}}
+	initializing := newPod("otter")
+	initializing.Status.Conditions = []api.PodCondition{{Type: api.PodReady, Status: api.ConditionFalse}}
+	list := newPodList("starfish", "otter")
+
+	f, tf, codec, _ := cmdtesting.NewAPIFactory()
+	tf.UnstructuredClient = &fake.RESTClient{
+		APIRegistry:          api.Registry,
+		NegotiatedSerializer: dynamic.ContentConfig().NegotiatedSerializer,
+		Client: fake.CreateHTTPClient(func(req *http.Request) (*http.Response, error) {
+			switch p, m := req.URL.Path, req.Method; {
+			case p == "/namespaces/default/pods/starfish" && m == "GET":
+				return newResponse(200, &ready)
+			case p == "/namespaces/default/pods/otter" && m == "GET":
+				return newResponse(200, &initializing)
+			}
+			t.Fatalf("unexpected request: %s %s", req.Method, req.URL.Path)
+			return nil, nil
+		}),
+	}
+
+	c := newTestClient(f)
+	infos, err := c.BuildUnstructured(api.NamespaceDefault, objBody(codec, &list))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// no gates, nothing pending
+	if pending, err := c.pendingGate(infos); err != nil || pending != "" {
+		t.Errorf("expected no pending gate, got %q, %v", pending, err)
+	}
+
+	// gates for other kinds do not apply
+	c.ReadinessGates = []ReadinessGate{{Kind: schema.GroupVersionKind{Group: "certmanager.k8s.io", Kind: "Certificate"}, ConditionType: "Ready"}}
+	if pending, err := c.pendingGate(infos); err != nil || pending != "" {
+		t.Errorf("expected no pending gate, got %q, %v", pending, err)
+	}
+
+	c.ReadinessGates = []ReadinessGate{{Kind: schema.GroupVersionKind{Kind: "Pod"}, ConditionType: "Ready"}}
+	pending, err := c.pendingGate(infos)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expect := `condition Ready on Pod "otter"`; pending != expect {
+		t.Errorf("expected %q, got %q", expect, pending)
+	}
+}
@@ -0,0 +1,140 @@
+/*
+Copyright 2017 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kube // import "k8s.io/helm/pkg/kube"
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+
+	"k8s.io/kubernetes/pkg/api/v1"
+)
+
+// thresholdKinds are the workloads whose readiness a ReadyThreshold can
+// define.
+var thresholdKinds = map[string]bool{
+	"DaemonSet":             true,
+	"Deployment":            true,
+	"ReplicaSet":            true,
+	"ReplicationController": true,
+	"StatefulSet":           true,
+}
+
+// ReadyThreshold makes a wait consider the workloads of a kind ready once a
+// share of the pods they desire is ready, instead of by the built-in rule:
+// every pod, or for Deployments all but maxUnavailable of them.
+//
+// A DaemonSet desires a pod on every node it is scheduled to, so on clusters
+// with nodes that are known to be drained, a threshold below one keeps the
+// wait from holding for pods that will never be ready.
+type ReadyThreshold struct {
+	// Kind is the kind of workload, e.g. "DaemonSet".
+	Kind string
+	// Ratio is the share of the desired pods that must be ready, greater
+	// than zero and at most one.
+	Ratio float64
+}
+
+func (t ReadyThreshold) String() string {
+	return fmt.Sprintf("%s=%s", t.Kind, strconv.FormatFloat(t.Ratio, 'f', -1, 64))
+}
+
+// ParseReadyThreshold parses a threshold written as "Kind=ratio", where the
+// ratio is a fraction or a percentage, e.g. "DaemonSet=0.9" or
+// "DaemonSet=90%".
+func ParseReadyThreshold(s string) (ReadyThreshold, error) {
+	var t ReadyThreshold
+	parts := strings.SplitN(s, "=", 2)
+	if len(parts) != 2 || parts[1] == "" {
+		return t, fmt.Errorf("invalid ready threshold %q: expected KIND=RATIO", s)
+	}
+	t.Kind = parts[0]
+	if !thresholdKinds[t.Kind] {
+		kinds := make([]string, 0, len(thresholdKinds))
+		for k := range thresholdKinds {
+			kinds = append(kinds, k)
+		}
+		sort.Strings(kinds)
+		return t, fmt.Errorf("invalid ready threshold %q: the kind must be one of %s", s, strings.Join(kinds, ", "))
+	}
+
+	v, scale := parts[1], 1.0
+	if strings.HasSuffix(v, "%") {
+		v, scale = strings.TrimSuffix(v, "%"), 100
+	}
+	ratio, err := strconv.ParseFloat(v, 64)
+	if err != nil || ratio <= 0 || ratio > scale {
+		return t, fmt.Errorf("invalid ready threshold %q: the ratio must be above 0 and at most 1, or 100%%", s)
+	}
+	t.Ratio = ratio / scale
+	return t, nil
+}
+
+// readyThreshold returns the ratio of the client's threshold for kind, or zero
+// if the built-in rule applies.
+func (c *Client) readyThreshold(kind string) float64 {
+	for _, t := range c.ReadyThresholds {
+		if t.Kind == kind {
+			return t.Ratio
+		}
+	}
+	return 0
+}
+
+// neededReady returns how many of desired pods must be ready for ratio to be
+// met.
+func neededReady(desired int32, ratio float64) int32 {
+	// Allow for rounding errors, so that 90% of 10 pods is 9 and not 10.
+	return int32(math.Ceil(float64(desired)*ratio - 1e-9))
+}
+
+// podThreshold holds the pods of a workload that is ready once ratio of the
+// desired number of pods is.
+type podThreshold struct {
+	kind, name string
+	desired    int32
+	ratio      float64
+	pods       []gatedPod
+}
+
+// pending describes the pods that t is still waiting for, or returns "" if
+// enough of them are ready. Pods only count as ready once their readiness
+// gates passed.
+func (t *podThreshold) pending() string {
+	var ready int32
+	for _, p := range t.pods {
+		if v1.IsPodReady(&p.Pod) && pendingPodGate(p) == "" {
+			ready++
+		}
+	}
+	needed := neededReady(t.desired, t.ratio)
+	if ready >= needed {
+		return ""
+	}
+	return fmt.Sprintf("%d of the %d pods of %s %q to be ready (%d are)", needed, t.desired, t.kind, t.name, ready)
+}
+
+// desiredReplicas returns the number of replicas a workload desires, which
+// defaults to one.
+func desiredReplicas(replicas *int32) int32 {
+	if replicas == nil {
+		return 1
+	}
+	return *replicas
+}
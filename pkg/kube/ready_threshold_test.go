@@ -0,0 +1,118 @@
+/*
+Copyright 2017 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kube
+
+import (
+	"testing"
+
+	extensions "k8s.io/kubernetes/pkg/apis/extensions/v1beta1"
+)
+
+func TestParseReadyThreshold(t *testing.T) {
+	for in, expect := range map[string]ReadyThreshold{
+		"DaemonSet=0.9":           {Kind: "DaemonSet", Ratio: 0.9},
+		"DaemonSet=90%":           {Kind: "DaemonSet", Ratio: 0.9},
+		"StatefulSet=1":           {Kind: "StatefulSet", Ratio: 1},
+		"Deployment=50%":          {Kind: "Deployment", Ratio: 0.5},
+		"ReplicaSet=0.25":         {Kind: "ReplicaSet", Ratio: 0.25},
+		"ReplicationController=1": {Kind: "ReplicationController", Ratio: 1},
+	} {
+		got, err := ParseReadyThreshold(in)
+		if err != nil {
+			t.Errorf("%s: unexpected error: %s", in, err)
+			continue
+		}
+		if got != expect {
+			t.Errorf("%s: expected %v, got %v", in, expect, got)
+		}
+	}
+
+	for _, in := range []string{"DaemonSet", "DaemonSet=", "Pod=0.5", "DaemonSet=0", "DaemonSet=1.5", "DaemonSet=120%", "DaemonSet=most"} {
+		if _, err := ParseReadyThreshold(in); err == nil {
+			t.Errorf("%s: expected an error", in)
+		}
+	}
+}
+
+func TestNeededReady(t *testing.T) {
+	for _, tt := range []struct {
+		desired int32
+		ratio   float64
+		needed  int32
+	}{
+		{10, 0.9, 9},
+		{10, 1, 10},
+		{3, 0.5, 2},
+		{7, 0.9, 7},
+		{0, 0.9, 0},
+	} {
+		if got := neededReady(tt.desired, tt.ratio); got != tt.needed {
+			t.Errorf("%v of %d: expected %d, got %d", tt.ratio, tt.desired, tt.needed, got)
+		}
+	}
+}
+
+func TestDaemonSetReadyThreshold(t *testing.T) {
+	// Ten nodes, of which two are drained: their pods never become ready.
+	var pods []gatedPod
+	for i := 0; i < 10; i++ {
+		p := readyPod(string(rune('a'+i)), nil)
+		if i >= 8 {
+			p.Status.Conditions = nil
+		}
+		pods = append(pods, p)
+	}
+
+	c := &Client{}
+	if c.readyThreshold("DaemonSet") != 0 {
+		t.Fatal("Expected no threshold by default")
+	}
+	if podsReady(pods) {
+		t.Error("Expected the built-in rule to wait for every pod")
+	}
+
+	ds := &podThreshold{kind: "DaemonSet", name: "agent", desired: 10, ratio: 0.8, pods: pods}
+	if got := ds.pending(); got != "" {
+		t.Errorf("Expected 8 of 10 ready pods to meet a threshold of 80%%, got %q", got)
+	}
+	ds.ratio = 0.9
+	if expect := `9 of the 10 pods of DaemonSet "agent" to be ready (8 are)`; ds.pending() != expect {
+		t.Errorf("Expected %q, got %q", expect, ds.pending())
+	}
+
+	// Pods only count once their readiness gates passed.
+	ds.ratio = 0.8
+	ds.pods[0].gates = []string{"example.com/lb-registered"}
+	if ds.pending() == "" {
+		t.Error("Expected a pod whose gate has not passed not to count as ready")
+	}
+}
+
+func TestDeploymentsReadyThreshold(t *testing.T) {
+	replicas := int32(10)
+	d := deployment{
+		replicaSets: &extensions.ReplicaSet{Status: extensions.ReplicaSetStatus{ReadyReplicas: 8}},
+		deployment:  &extensions.Deployment{Spec: extensions.DeploymentSpec{Replicas: &replicas, Strategy: extensions.DeploymentStrategy{Type: extensions.RecreateDeploymentStrategyType}}},
+	}
+	if deploymentsReady([]deployment{d}) {
+		t.Error("Expected the built-in rule to wait for every replica of a Recreate deployment")
+	}
+	d.threshold = 0.8
+	if !deploymentsReady([]deployment{d}) {
+		t.Error("Expected 8 of 10 ready replicas to meet a threshold of 80%")
+	}
+}
@@ -0,0 +1,94 @@
+/*
+Copyright 2017 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kube // import "k8s.io/helm/pkg/kube"
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/kubernetes/pkg/kubectl/resource"
+)
+
+// RestartedAtAnno is the pod template annotation that Restart sets to the
+// time of the restart. Changing it makes the workload controller replace its
+// pods.
+const RestartedAtAnno = "helm.sh/restartedAt"
+
+// RestartableKinds are the kinds of workloads that Restart can restart.
+var RestartableKinds = map[string]bool{
+	"Deployment":  true,
+	"StatefulSet": true,
+	"DaemonSet":   true,
+}
+
+// Restart restarts the pods of Deployments, StatefulSets and DaemonSets from
+// an io.reader by setting the helm.sh/restartedAt annotation of their pod
+// templates to the current time. The workload controllers then roll out new
+// pods according to the update strategy of each workload. Other kinds of
+// resources are refused before anything is changed.
+//
+// If shouldWait is set, Restart waits up to timeout seconds for the
+// workloads to be ready.
+func (c *Client) Restart(namespace string, reader io.Reader, timeout int64, shouldWait bool) (err error) {
+	defer observe("restart", time.Now(), &err)
+
+	infos, err := c.BuildUnstructured(namespace, reader)
+	if err != nil {
+		return err
+	}
+	for _, info := range infos {
+		if kind := info.Mapping.GroupVersionKind.Kind; !RestartableKinds[kind] {
+			return fmt.Errorf("cannot restart %s %q: only Deployments, StatefulSets and DaemonSets can be restarted", kind, info.Name)
+		}
+	}
+
+	patch, err := restartPatch(time.Now())
+	if err != nil {
+		return err
+	}
+	err = perform(infos, func(info *resource.Info) error {
+		c.Log("Restarting %s %q", info.Mapping.GroupVersionKind.Kind, info.Name)
+		obj, err := resource.NewHelper(info.Client, info.Mapping).Patch(info.Namespace, info.Name, types.StrategicMergePatchType, patch)
+		if err != nil {
+			return fmt.Errorf("cannot restart %s %q: %s", info.Mapping.GroupVersionKind.Kind, info.Name, err)
+		}
+		return info.Refresh(obj, true)
+	})
+	if err != nil || !shouldWait {
+		return err
+	}
+	return c.waitForResources(time.Duration(timeout)*time.Second, infos)
+}
+
+// restartPatch returns the patch that sets the helm.sh/restartedAt annotation
+// of a pod template to at.
+func restartPatch(at time.Time) ([]byte, error) {
+	return json.Marshal(map[string]interface{}{
+		"spec": map[string]interface{}{
+			"template": map[string]interface{}{
+				"metadata": map[string]interface{}{
+					"annotations": map[string]string{
+						RestartedAtAnno: at.UTC().Format(time.RFC3339),
+					},
+				},
+			},
+		},
+	})
+}
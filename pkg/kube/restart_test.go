@@ -0,0 +1,117 @@
+/*
+Copyright 2017 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kube
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/rest/fake"
+	"k8s.io/kubernetes/pkg/api"
+	cmdtesting "k8s.io/kubernetes/pkg/kubectl/cmd/testing"
+)
+
+func TestRestartPatch(t *testing.T) {
+	at := time.Date(2017, 6, 1, 12, 30, 0, 0, time.FixedZone("CEST", 2*60*60))
+	patch, err := restartPatch(at)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expect := `{"spec":{"template":{"metadata":{"annotations":{"helm.sh/restartedAt":"2017-06-01T10:30:00Z"}}}}}`
+	if string(patch) != expect {
+		t.Errorf("expected %s, got %s", expect, patch)
+	}
+}
+
+const restartDeployment = `apiVersion: extensions/v1beta1
+kind: Deployment
+metadata:
+  name: web
+spec:
+  replicas: 1
+  template:
+    metadata:
+      labels:
+        app: web
+    spec:
+      containers:
+      - name: web
+        image: nginx
+`
+
+func TestRestart(t *testing.T) {
+	var patchBody string
+	var actions []string
+
+	f, tf, _, _ := cmdtesting.NewAPIFactory()
+	tf.UnstructuredClient = &fake.RESTClient{
+		APIRegistry:          api.Registry,
+		NegotiatedSerializer: dynamic.ContentConfig().NegotiatedSerializer,
+		Client: fake.CreateHTTPClient(func(req *http.Request) (*http.Response, error) {
+			p, m := req.URL.Path, req.Method
+			actions = append(actions, p+":"+m)
+			if p == "/namespaces/default/deployments/web" && m == "PATCH" {
+				data, err := ioutil.ReadAll(req.Body)
+				if err != nil {
+					t.Fatalf("could not read request: %s", err)
+				}
+				patchBody = string(data)
+				header := http.Header{}
+				header.Set("Content-Type", "application/json")
+				body := `{"apiVersion":"extensions/v1beta1","kind":"Deployment","metadata":{"name":"web","namespace":"default"}}`
+				return &http.Response{StatusCode: 200, Header: header, Body: ioutil.NopCloser(bytes.NewReader([]byte(body)))}, nil
+			}
+			t.Fatalf("unexpected request: %s %s", req.Method, req.URL.Path)
+			return nil, nil
+		}),
+	}
+	c := newTestClient(f)
+
+	if err := c.Restart("default", strings.NewReader(restartDeployment), 0, false); err != nil {
+		t.Fatal(err)
+	}
+	if got, expect := strings.Join(actions, ","), "/namespaces/default/deployments/web:PATCH"; got != expect {
+		t.Errorf("expected requests %q, got %q", expect, got)
+	}
+	if !strings.Contains(patchBody, `"helm.sh/restartedAt"`) {
+		t.Errorf("expected the patch to set the restartedAt annotation, got %s", patchBody)
+	}
+}
+
+func TestRestartRefusesOtherKinds(t *testing.T) {
+	pod := newPod("squid")
+	f, tf, codec, _ := cmdtesting.NewAPIFactory()
+	tf.UnstructuredClient = &fake.RESTClient{
+		APIRegistry:          api.Registry,
+		NegotiatedSerializer: dynamic.ContentConfig().NegotiatedSerializer,
+		Client: fake.CreateHTTPClient(func(req *http.Request) (*http.Response, error) {
+			t.Fatalf("unexpected request: %s %s", req.Method, req.URL.Path)
+			return nil, nil
+		}),
+	}
+	c := newTestClient(f)
+
+	err := c.Restart("default", objBody(codec, &pod), 0, false)
+	if err == nil || !strings.Contains(err.Error(), `cannot restart Pod "squid"`) {
+		t.Errorf("expected pods to be refused, got %v", err)
+	}
+}
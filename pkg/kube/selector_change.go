@@ -0,0 +1,92 @@
+/*
+Copyright 2017 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kube // import "k8s.io/helm/pkg/kube"
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/kubernetes/pkg/kubectl/resource"
+)
+
+// defaultRecreateTimeout is how long recreateOrphaning waits for the old
+// object to be gone when the update has no timeout.
+const defaultRecreateTimeout = 5 * time.Minute
+
+// selectorFields are the fields whose changes are rejected when a workload's
+// label selector is immutable, or no longer matches its pod template.
+var selectorFields = []string{"spec.selector", "spec.template.metadata.labels"}
+
+// isSelectorChange reports whether err rejects a patch only because it changes
+// the label selector of a workload, or the pod labels it selects.
+func isSelectorChange(err error) bool {
+	if !errors.IsInvalid(err) {
+		return false
+	}
+	status, ok := err.(errors.APIStatus)
+	if !ok || status.Status().Details == nil {
+		return false
+	}
+	causes := status.Status().Details.Causes
+	if len(causes) == 0 {
+		return false
+	}
+	for _, cause := range causes {
+		if !isSelectorField(cause.Field) {
+			return false
+		}
+	}
+	return true
+}
+
+// isSelectorField reports whether field is, or is within, one of
+// selectorFields.
+func isSelectorField(field string) bool {
+	for _, f := range selectorFields {
+		if field == f || strings.HasPrefix(field, f+".") || strings.HasPrefix(field, f+"[") {
+			return true
+		}
+	}
+	return false
+}
+
+// recreateOrphaning deletes the live object of target with the Orphan
+// propagation policy, so that the pods it manages keep running, waits for it
+// to be gone, and creates target in its place. The new object adopts the
+// orphaned pods and ReplicaSets that its selector matches.
+func (c *Client) recreateOrphaning(target *resource.Info, timeout time.Duration) error {
+	kind := target.Mapping.GroupVersionKind.Kind
+	orphan := metav1.DeletePropagationOrphan
+	helper := resource.NewHelper(target.Client, target.Mapping)
+	if err := helper.DeleteWithOptions(target.Namespace, target.Name, &metav1.DeleteOptions{PropagationPolicy: &orphan}); err != nil {
+		return fmt.Errorf("failed to delete %s %q to change its selector: %s", kind, target.Name, err)
+	}
+	if timeout <= 0 {
+		timeout = defaultRecreateTimeout
+	}
+	if err := c.waitForDeletion(timeout, Result{target}); err != nil {
+		return err
+	}
+	if err := createResource(target); err != nil {
+		return fmt.Errorf("failed to recreate %s %q with its new selector: %s", kind, target.Name, err)
+	}
+	c.Log("Recreated %s %q because its selector changed; its pods were orphaned for the new %s to adopt. Pods the new selector does not match keep running until they are deleted", kind, target.Name, kind)
+	return nil
+}
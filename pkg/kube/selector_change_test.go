@@ -0,0 +1,145 @@
+/*
+Copyright 2017 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kube
+
+import (
+	goerrors "errors"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/rest/fake"
+	"k8s.io/kubernetes/pkg/api"
+	cmdtesting "k8s.io/kubernetes/pkg/kubectl/cmd/testing"
+)
+
+func invalidErr(fields ...string) error {
+	var errs field.ErrorList
+	for _, f := range fields {
+		errs = append(errs, field.Invalid(field.NewPath(f), "x", "field is immutable"))
+	}
+	return errors.NewInvalid(schema.GroupKind{Group: "apps", Kind: "Deployment"}, "web", errs)
+}
+
+func TestIsSelectorChange(t *testing.T) {
+	tests := []struct {
+		name   string
+		err    error
+		expect bool
+	}{
+		{"selector", invalidErr("spec.selector"), true},
+		{"selector labels", invalidErr("spec.selector.matchLabels.app"), true},
+		{"selector and pod labels", invalidErr("spec.selector", "spec.template.metadata.labels"), true},
+		{"selector and another field", invalidErr("spec.selector", "spec.replicas"), false},
+		{"other field", invalidErr("spec.selectorX"), false},
+		{"no causes", invalidErr(), false},
+		{"conflict", errors.NewConflict(schema.GroupResource{Resource: "deployments"}, "web", goerrors.New("modified")), false},
+		{"not an API error", goerrors.New("spec.selector: field is immutable"), false},
+	}
+	for _, tt := range tests {
+		if got := isSelectorChange(tt.err); got != tt.expect {
+			t.Errorf("%s: expected %t, got %t", tt.name, tt.expect, got)
+		}
+	}
+}
+
+func selectorChangeBody() *metav1.Status {
+	return &metav1.Status{
+		Code:    http.StatusUnprocessableEntity,
+		Status:  metav1.StatusFailure,
+		Reason:  metav1.StatusReasonInvalid,
+		Message: `Pod "starfish" is invalid: spec.selector: Invalid value: "x": field is immutable`,
+		Details: &metav1.StatusDetails{
+			Name:   "starfish",
+			Kind:   "Pod",
+			Causes: []metav1.StatusCause{{Type: metav1.CauseTypeFieldValueInvalid, Field: "spec.selector"}},
+		},
+	}
+}
+
+func TestUpdateRecreatesOnSelectorChange(t *testing.T) {
+	original, target := labelledPod("v1"), labelledPod("v2")
+
+	for _, recreate := range []bool{false, true} {
+		var actions []string
+		var deleteBody string
+		deleted := false
+
+		f, tf, codec, _ := cmdtesting.NewAPIFactory()
+		tf.UnstructuredClient = &fake.RESTClient{
+			APIRegistry:          api.Registry,
+			NegotiatedSerializer: dynamic.ContentConfig().NegotiatedSerializer,
+			Client: fake.CreateHTTPClient(func(req *http.Request) (*http.Response, error) {
+				p, m := req.URL.Path, req.Method
+				actions = append(actions, p+":"+m)
+				switch {
+				case p == "/namespaces/default/pods/starfish" && m == "GET":
+					if deleted {
+						return newResponse(404, notFoundBody())
+					}
+					return newResponse(200, &original)
+				case p == "/namespaces/default/pods/starfish" && m == "PATCH":
+					return newResponse(422, selectorChangeBody())
+				case p == "/namespaces/default/pods/starfish" && m == "DELETE":
+					data, err := ioutil.ReadAll(req.Body)
+					if err != nil {
+						t.Fatalf("could not read request: %s", err)
+					}
+					deleteBody = string(data)
+					deleted = true
+					return newResponse(200, &original)
+				case p == "/namespaces/default/pods" && m == "POST":
+					return newResponse(201, &target)
+				}
+				t.Fatalf("unexpected request: %s %s", req.Method, req.URL.Path)
+				return nil, nil
+			}),
+		}
+
+		reaper := &fakeReaper{}
+		c := newTestClient(&fakeReaperFactory{Factory: f, reaper: reaper})
+		opts := UpdateOptions{RecreateOnSelectorChange: recreate}
+		err := c.UpdateWithOptions(api.NamespaceDefault, objBody(codec, &original), objBody(codec, &target), opts)
+
+		if !recreate {
+			if err == nil || !strings.Contains(err.Error(), "field is immutable") {
+				t.Errorf("expected the selector change to fail the update, got %v", err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		expect := "/namespaces/default/pods/starfish:GET,/namespaces/default/pods/starfish:PATCH," +
+			"/namespaces/default/pods/starfish:DELETE,/namespaces/default/pods/starfish:GET,/namespaces/default/pods:POST"
+		if got := strings.Join(actions, ","); got != expect {
+			t.Errorf("expected requests\n%s\ngot\n%s", expect, got)
+		}
+		if !strings.Contains(deleteBody, `"propagationPolicy":"Orphan"`) {
+			t.Errorf("expected the workload to be deleted orphaning its pods, got %s", deleteBody)
+		}
+		if reaper.name != "" {
+			t.Errorf("expected no reaper to be used, got %q", reaper.name)
+		}
+	}
+}
@@ -0,0 +1,87 @@
+/*
+Copyright 2017 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kube // import "k8s.io/helm/pkg/kube"
+
+// serverAnnotations are the annotations that Kubernetes and kubectl add to
+// objects to track them.
+var serverAnnotations = []string{
+	"deployment.kubernetes.io/revision",
+	"kubectl.kubernetes.io/last-applied-configuration",
+	"pv.kubernetes.io/bind-completed",
+	"pv.kubernetes.io/bound-by-controller",
+	"volume.beta.kubernetes.io/storage-provisioner",
+}
+
+// StripServerFields removes from the fields of a live object, as returned by
+// LiveObjects, those that the API server and controllers manage, so that
+// what is left can be applied to re-create or restore the object:
+//
+//   - the status
+//   - the metadata fields creationTimestamp, deletionGracePeriodSeconds,
+//     deletionTimestamp, generation, ownerReferences, resourceVersion,
+//     selfLink and uid
+//   - the annotations that Kubernetes and kubectl track objects with, such
+//     as kubectl.kubernetes.io/last-applied-configuration
+//   - the cluster IP of Services, unless it is "None"
+//   - the generated selector of Jobs, and the labels that it matches in the
+//     pod template, unless the Job sets spec.manualSelector
+//
+// Defaults that the server filled in, such as the image pull policy of
+// containers, are kept: they are valid parts of the spec.
+func StripServerFields(obj map[string]interface{}) {
+	delete(obj, "status")
+	if m, ok := obj["metadata"].(map[string]interface{}); ok {
+		for k := range ignoredMetadata {
+			delete(m, k)
+		}
+		delete(m, "deletionGracePeriodSeconds")
+		delete(m, "deletionTimestamp")
+		delete(m, "ownerReferences")
+		if a, ok := m["annotations"].(map[string]interface{}); ok {
+			for _, k := range serverAnnotations {
+				delete(a, k)
+			}
+			if len(a) == 0 {
+				delete(m, "annotations")
+			}
+		}
+	}
+
+	spec, ok := obj["spec"].(map[string]interface{})
+	if !ok {
+		return
+	}
+	switch obj["kind"] {
+	case "Service":
+		if spec["clusterIP"] != "None" {
+			delete(spec, "clusterIP")
+		}
+	case "Job":
+		if manual, _ := spec["manualSelector"].(bool); manual {
+			return
+		}
+		delete(spec, "selector")
+		if tpl, ok := spec["template"].(map[string]interface{}); ok {
+			if m, ok := tpl["metadata"].(map[string]interface{}); ok {
+				if l, ok := m["labels"].(map[string]interface{}); ok {
+					delete(l, "controller-uid")
+					delete(l, "job-name")
+				}
+			}
+		}
+	}
+}
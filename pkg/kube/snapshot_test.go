@@ -0,0 +1,90 @@
+/*
+Copyright 2017 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kube
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestStripServerFields(t *testing.T) {
+	tests := []struct {
+		name   string
+		live   string
+		expect string
+	}{
+		{
+			name: "metadata and status",
+			live: `{"apiVersion": "v1", "kind": "ConfigMap",
+				"metadata": {"name": "settings", "namespace": "default", "uid": "123", "resourceVersion": "7",
+					"selfLink": "/api/v1/namespaces/default/configmaps/settings", "creationTimestamp": "2017-10-01T00:00:00Z",
+					"annotations": {"kubectl.kubernetes.io/last-applied-configuration": "{}"},
+					"labels": {"app": "hello"}},
+				"data": {"greeting": "hi"}}`,
+			expect: `{"apiVersion": "v1", "kind": "ConfigMap",
+				"metadata": {"name": "settings", "namespace": "default", "labels": {"app": "hello"}},
+				"data": {"greeting": "hi"}}`,
+		},
+		{
+			name: "deployment",
+			live: `{"apiVersion": "extensions/v1beta1", "kind": "Deployment",
+				"metadata": {"name": "web", "generation": 3, "annotations": {"deployment.kubernetes.io/revision": "3", "team": "web"}},
+				"spec": {"replicas": 5},
+				"status": {"replicas": 5}}`,
+			expect: `{"apiVersion": "extensions/v1beta1", "kind": "Deployment",
+				"metadata": {"name": "web", "annotations": {"team": "web"}},
+				"spec": {"replicas": 5}}`,
+		},
+		{
+			name:   "service",
+			live:   `{"kind": "Service", "metadata": {"name": "web"}, "spec": {"clusterIP": "10.0.0.12", "ports": [{"port": 80, "nodePort": 30080}]}}`,
+			expect: `{"kind": "Service", "metadata": {"name": "web"}, "spec": {"ports": [{"port": 80, "nodePort": 30080}]}}`,
+		},
+		{
+			name:   "headless service",
+			live:   `{"kind": "Service", "metadata": {"name": "db"}, "spec": {"clusterIP": "None"}}`,
+			expect: `{"kind": "Service", "metadata": {"name": "db"}, "spec": {"clusterIP": "None"}}`,
+		},
+		{
+			name: "job",
+			live: `{"kind": "Job", "metadata": {"name": "migrate"},
+				"spec": {"selector": {"matchLabels": {"controller-uid": "123"}},
+					"template": {"metadata": {"labels": {"app": "hello", "controller-uid": "123", "job-name": "migrate"}}}}}`,
+			expect: `{"kind": "Job", "metadata": {"name": "migrate"},
+				"spec": {"template": {"metadata": {"labels": {"app": "hello"}}}}}`,
+		},
+		{
+			name:   "job with a manual selector",
+			live:   `{"kind": "Job", "metadata": {"name": "migrate"}, "spec": {"manualSelector": true, "selector": {"matchLabels": {"app": "hello"}}}}`,
+			expect: `{"kind": "Job", "metadata": {"name": "migrate"}, "spec": {"manualSelector": true, "selector": {"matchLabels": {"app": "hello"}}}}`,
+		},
+	}
+	for _, tt := range tests {
+		var obj, expect map[string]interface{}
+		if err := json.Unmarshal([]byte(tt.live), &obj); err != nil {
+			t.Fatal(err)
+		}
+		if err := json.Unmarshal([]byte(tt.expect), &expect); err != nil {
+			t.Fatal(err)
+		}
+		StripServerFields(obj)
+		if !reflect.DeepEqual(obj, expect) {
+			t.Errorf("%s: expected %v, got %v", tt.name, expect, obj)
+		}
+	}
+}
@@ -17,123 +17,456 @@ limitations under the License.
 package kube // import "k8s.io/helm/pkg/kube"
 
 import (
+	"encoding/json"
+	"fmt"
 	"log"
+	"strconv"
+	"strings"
 	"time"
 
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
-	"k8s.io/apimachinery/pkg/fields"
-	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/kubernetes/pkg/api/v1"
 	apps "k8s.io/kubernetes/pkg/apis/apps/v1beta1"
+	autoscaling "k8s.io/kubernetes/pkg/apis/autoscaling/v1"
 	extensions "k8s.io/kubernetes/pkg/apis/extensions/v1beta1"
 	"k8s.io/kubernetes/pkg/client/clientset_generated/clientset"
 	core "k8s.io/kubernetes/pkg/client/clientset_generated/clientset/typed/core/v1"
 	extensionsclient "k8s.io/kubernetes/pkg/client/clientset_generated/clientset/typed/extensions/v1beta1"
 	internalclientset "k8s.io/kubernetes/pkg/client/clientset_generated/internalclientset"
 	deploymentutil "k8s.io/kubernetes/pkg/controller/deployment/util"
+	"k8s.io/kubernetes/pkg/kubectl/resource"
 )
 
 // deployment holds associated replicaSets for a deployment
 type deployment struct {
 	replicaSets *extensions.ReplicaSet
 	deployment  *extensions.Deployment
+	// pods are the pods of the new replica set, if their readiness gates
+	// are waited for or the logs of a wait are collected.
+	pods []gatedPod
+	// threshold, if set, is the share of the desired replicas that must be
+	// ready, instead of all but maxUnavailable of them.
+	threshold float64
+}
+
+// volumeBindingWaitForFirstConsumer is the binding mode of storage classes
+// whose claims are only bound once a pod using them is scheduled.
+const volumeBindingWaitForFirstConsumer = "WaitForFirstConsumer"
+
+// WaitTimeoutAnno is the annotation that gives a resource its own time limit
+// for becoming ready in a wait, instead of the timeout of the release. The
+// value is a duration such as "20m", or a number of seconds.
+const WaitTimeoutAnno = "helm.sh/wait-timeout"
+
+// parseWaitTimeout parses the value of a helm.sh/wait-timeout annotation.
+func parseWaitTimeout(v string) (time.Duration, error) {
+	v = strings.TrimSpace(v)
+	if secs, err := strconv.Atoi(v); err == nil {
+		v = fmt.Sprintf("%ds", secs)
+	}
+	d, err := time.ParseDuration(v)
+	if err != nil || d <= 0 {
+		return 0, fmt.Errorf("invalid %s %q: expected a positive duration such as 20m", WaitTimeoutAnno, v)
+	}
+	return d, nil
+}
+
+// waitLimit is how long a wait holds for a resource to become ready.
+type waitLimit struct {
+	timeout time.Duration
+	// own is set when the resource sets its timeout with WaitTimeoutAnno.
+	own bool
+}
+
+// waitLimits returns the wait limit of each resource in infos. Resources
+// without a helm.sh/wait-timeout annotation get the release timeout.
+func waitLimits(infos Result, timeout time.Duration) ([]waitLimit, error) {
+	limits := make([]waitLimit, len(infos))
+	for i, info := range infos {
+		limits[i] = waitLimit{timeout: timeout}
+		accessor, err := meta.Accessor(info.Object)
+		if err != nil {
+			continue
+		}
+		v, ok := accessor.GetAnnotations()[WaitTimeoutAnno]
+		if !ok {
+			continue
+		}
+		d, err := parseWaitTimeout(v)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %s", describeInfo(info), err)
+		}
+		limits[i] = waitLimit{timeout: d, own: true}
+	}
+	return limits, nil
+}
+
+// describeInfo returns the kind, namespace and name of a resource.
+func describeInfo(info *resource.Info) string {
+	kind := ""
+	if info.Mapping != nil {
+		kind = info.Mapping.GroupVersionKind.Kind + " "
+	}
+	return fmt.Sprintf("%s%s/%s", kind, info.Namespace, info.Name)
+}
+
+// readiness is what a resource still waits for.
+type readiness struct {
+	ready bool
+	// unbound are the PersistentVolumeClaims that are not bound yet.
+	unbound []string
+	// pending is the readiness gate that has not passed yet.
+	pending string
+	// unready are the pods of the resource that are not ready yet.
+	unready []v1.Pod
 }
 
 // waitForResources polls to get the current status of all pods, PVCs, and Services
-// until all are ready or a timeout is reached
+// until all are ready or a timeout is reached. Resources matched by one of the
+// client's readiness gates must also report the gate's condition as true.
+//
+// PVCs must be bound, unless their storage class delays binding until a pod
+// uses them. On timeout, the error names the PVCs that are still unbound.
+//
+// Pods with readiness gates must also report the condition of each gate as
+// true, and only such pods count as ready replicas of a Deployment, unless the
+// pods or their workload opt out with helm.sh/wait-for-readiness-gates.
+//
+// If the client's HPAStabilization is set, HorizontalPodAutoscalers must have
+// had their desired number of replicas, without change, for that long.
+//
+// A resource annotated with helm.sh/wait-timeout has its own time limit, which
+// may be shorter or longer than timeout. The wait fails as soon as a resource
+// is not ready within its limit, and the error names that resource.
+//
+// If the client's WaitLogBytes is set, the error of a wait that timed out ends
+// with the logs of the containers that are not ready, in at most that many
+// bytes. They are also given to the client's WaitLogSink, if set, while the
+// wait goes on.
 func (c *Client) waitForResources(timeout time.Duration, created Result) error {
 	log.Printf("beginning wait for resources with timeout of %v", timeout)
 
+	limits, err := waitLimits(created, timeout)
+	if err != nil {
+		return err
+	}
+	longest := timeout
+	for _, l := range limits {
+		if l.timeout > longest {
+			longest = l.timeout
+		}
+	}
+
 	cs, err := c.ClientSet()
 	if err != nil {
 		return err
 	}
 	client := versionedClientsetForDeployment(cs)
-	return wait.Poll(2*time.Second, timeout, func() (bool, error) {
-		pods := []v1.Pod{}
-		services := []v1.Service{}
-		pvc := []v1.PersistentVolumeClaim{}
-		deployments := []deployment{}
-		for _, v := range created {
-			obj, err := c.AsVersionedObject(v.Object)
-			if err != nil && !runtime.IsNotRegisteredError(err) {
+	// Storage classes do not change during a wait, so each is looked up once.
+	lateBinding := map[string]bool{}
+	delaysBinding := func(class string) (bool, error) {
+		if late, ok := lateBinding[class]; ok {
+			return late, nil
+		}
+		mode, err := storageClassBindingMode(cs, class)
+		switch {
+		case errors.IsNotFound(err) || errors.IsForbidden(err):
+			// Without the class, wait for the claim to bind as usual.
+			c.Log("cannot look up storage class %q: %s", class, err)
+		case err != nil:
+			return false, err
+		}
+		lateBinding[class] = mode == volumeBindingWaitForFirstConsumer
+		return lateBinding[class], nil
+	}
+
+	hpas := newHPAStabilization(c.HPAStabilization)
+	start := time.Now()
+	states := make([]readiness, len(created))
+	// The first logs are given after WaitLogInterval, as containers that
+	// start normally need not be followed.
+	logs := &waitLogStream{c: c, last: start}
+	err = wait.Poll(2*time.Second, longest, func() (bool, error) {
+		done := true
+		for i, info := range created {
+			state, err := c.resourceReadiness(client, info, delaysBinding, hpas)
+			if err != nil {
 				return false, err
 			}
-			switch value := obj.(type) {
-			case (*v1.ReplicationController):
-				list, err := getPods(client, value.Namespace, value.Spec.Selector)
-				if err != nil {
-					return false, err
-				}
-				pods = append(pods, list...)
-			case (*v1.Pod):
-				pod, err := client.Core().Pods(value.Namespace).Get(value.Name, metav1.GetOptions{})
-				if err != nil {
-					return false, err
-				}
-				pods = append(pods, *pod)
-			case (*extensions.Deployment):
-				currentDeployment, err := client.Extensions().Deployments(value.Namespace).Get(value.Name, metav1.GetOptions{})
-				if err != nil {
-					return false, err
-				}
-				// Find RS associated with deployment
-				newReplicaSet, err := deploymentutil.GetNewReplicaSet(currentDeployment, client)
-				if err != nil || newReplicaSet == nil {
-					return false, err
-				}
-				newDeployment := deployment{
-					newReplicaSet,
-					currentDeployment,
-				}
-				deployments = append(deployments, newDeployment)
-			case (*extensions.DaemonSet):
-				list, err := getPods(client, value.Namespace, value.Spec.Selector.MatchLabels)
-				if err != nil {
-					return false, err
-				}
-				pods = append(pods, list...)
-			case (*apps.StatefulSet):
-				list, err := getPods(client, value.Namespace, value.Spec.Selector.MatchLabels)
-				if err != nil {
-					return false, err
-				}
-				pods = append(pods, list...)
-			case (*extensions.ReplicaSet):
-				list, err := getPods(client, value.Namespace, value.Spec.Selector.MatchLabels)
-				if err != nil {
-					return false, err
-				}
-				pods = append(pods, list...)
-			case (*v1.PersistentVolumeClaim):
-				claim, err := client.Core().PersistentVolumeClaims(value.Namespace).Get(value.Name, metav1.GetOptions{})
-				if err != nil {
-					return false, err
-				}
-				pvc = append(pvc, *claim)
-			case (*v1.Service):
-				svc, err := client.Core().Services(value.Namespace).Get(value.Name, metav1.GetOptions{})
-				if err != nil {
-					return false, err
-				}
-				services = append(services, *svc)
-			}
+			states[i] = state
+			done = done && state.ready
 		}
-		return podsReady(pods) && servicesReady(services) && volumesReady(pvc) && deploymentsReady(deployments), nil
+		if done {
+			return true, nil
+		}
+		if err := waitTimeoutError(created, limits, states, time.Since(start)); err != nil {
+			return false, c.withPodLogs(client, err, states)
+		}
+		logs.send(clientLogFetcher(client), states)
+		return false, nil
 	})
+	if err == wait.ErrWaitTimeout {
+		// The last poll may have happened just before the longest limit.
+		if err = waitTimeoutError(created, limits, states, longest); err == nil {
+			err = wait.ErrWaitTimeout
+		}
+		err = c.withPodLogs(client, err, states)
+	}
+	return err
+}
+
+// withPodLogs appends to the timeout error err the logs of the containers
+// that states report as not ready, if the client's WaitLogBytes is set. The
+// logs are also written to the client's log.
+func (c *Client) withPodLogs(client clientset.Interface, err error, states []readiness) error {
+	if c.WaitLogBytes <= 0 {
+		return err
+	}
+	logs := podLogs(clientLogFetcher(client), statesUnready(states), c.WaitLogBytes)
+	if logs == "" {
+		return err
+	}
+	c.Log("logs of the pods that are not ready:\n%s", logs)
+	return fmt.Errorf("%s\nlogs of the pods that are not ready:\n%s", err, strings.TrimSuffix(logs, "\n"))
+}
+
+// statesUnready returns the pods that are not ready of the resources that
+// states report as not ready.
+func statesUnready(states []readiness) []v1.Pod {
+	var pods []v1.Pod
+	for _, s := range states {
+		if !s.ready {
+			pods = append(pods, s.unready...)
+		}
+	}
+	return pods
+}
+
+// waitTimeoutError returns the error for the resources that are not ready
+// although elapsed exceeds their limit, or nil if there are none. Resources
+// with a limit of their own are reported first, by name.
+func waitTimeoutError(infos Result, limits []waitLimit, states []readiness, elapsed time.Duration) error {
+	var unbound []string
+	var pending string
+	timedOut := false
+	for i, info := range infos {
+		if states[i].ready || elapsed < limits[i].timeout {
+			continue
+		}
+		if limits[i].own {
+			detail := ""
+			switch {
+			case len(states[i].unbound) > 0:
+				detail = fmt.Sprintf(" (PersistentVolumeClaims not bound: %s)", strings.Join(states[i].unbound, ", "))
+			case states[i].pending != "":
+				detail = fmt.Sprintf(" (waiting for %s)", states[i].pending)
+			}
+			return fmt.Errorf("timed out waiting for %s: not ready within its %s of %v%s", describeInfo(info), WaitTimeoutAnno, limits[i].timeout, detail)
+		}
+		timedOut = true
+		unbound = append(unbound, states[i].unbound...)
+		if pending == "" {
+			pending = states[i].pending
+		}
+	}
+	switch {
+	case !timedOut:
+		return nil
+	case len(unbound) > 0:
+		return fmt.Errorf("timed out waiting for PersistentVolumeClaims to bind: %s", strings.Join(unbound, ", "))
+	case pending != "":
+		return fmt.Errorf("timed out waiting for %s", pending)
+	}
+	return wait.ErrWaitTimeout
+}
+
+// resourceReadiness checks whether the resource of info is ready.
+func (c *Client) resourceReadiness(client clientset.Interface, info *resource.Info, delaysBinding func(class string) (bool, error), hpas *hpaStabilization) (readiness, error) {
+	pending := ""
+	gates := waitForPodGates(info)
+	pods := []gatedPod{}
+	services := []v1.Service{}
+	pvc := []v1.PersistentVolumeClaim{}
+	deployments := []deployment{}
+	var threshold *podThreshold
+	obj, err := c.AsVersionedObject(info.Object)
+	if err != nil && !runtime.IsNotRegisteredError(err) {
+		return readiness{}, err
+	}
+	switch value := obj.(type) {
+	case (*v1.ReplicationController):
+		list, err := getGatedPods(client, value.Namespace, value.Spec.Selector)
+		if err != nil {
+			return readiness{}, err
+		}
+		if ratio := c.readyThreshold("ReplicationController"); ratio > 0 {
+			rc, err := client.Core().ReplicationControllers(value.Namespace).Get(value.Name, metav1.GetOptions{})
+			if err != nil {
+				return readiness{}, err
+			}
+			threshold = &podThreshold{kind: "ReplicationController", name: value.Name, desired: desiredReplicas(rc.Spec.Replicas), ratio: ratio, pods: list}
+			break
+		}
+		pods = append(pods, list...)
+	case (*v1.Pod):
+		pod, err := getGatedPod(client, value.Namespace, value.Name)
+		if err != nil {
+			return readiness{}, err
+		}
+		pods = append(pods, pod)
+	case (*extensions.Deployment):
+		currentDeployment, err := client.Extensions().Deployments(value.Namespace).Get(value.Name, metav1.GetOptions{})
+		if err != nil {
+			return readiness{}, err
+		}
+		// Find RS associated with deployment
+		newReplicaSet, err := deploymentutil.GetNewReplicaSet(currentDeployment, client)
+		if err != nil || newReplicaSet == nil {
+			return readiness{}, err
+		}
+		newDeployment := deployment{
+			replicaSets: newReplicaSet,
+			deployment:  currentDeployment,
+			threshold:   c.readyThreshold("Deployment"),
+		}
+		if gates || c.WaitLogBytes > 0 {
+			if newDeployment.pods, err = getGatedPods(client, value.Namespace, newReplicaSet.Spec.Selector.MatchLabels); err != nil {
+				return readiness{}, err
+			}
+			if !gates {
+				ignoreGates(newDeployment.pods)
+			}
+		}
+		deployments = append(deployments, newDeployment)
+	case (*extensions.DaemonSet):
+		list, err := getGatedPods(client, value.Namespace, value.Spec.Selector.MatchLabels)
+		if err != nil {
+			return readiness{}, err
+		}
+		if ratio := c.readyThreshold("DaemonSet"); ratio > 0 {
+			ds, err := client.Extensions().DaemonSets(value.Namespace).Get(value.Name, metav1.GetOptions{})
+			if err != nil {
+				return readiness{}, err
+			}
+			threshold = &podThreshold{kind: "DaemonSet", name: value.Name, desired: ds.Status.DesiredNumberScheduled, ratio: ratio, pods: list}
+			break
+		}
+		pods = append(pods, list...)
+	case (*apps.StatefulSet):
+		list, err := getGatedPods(client, value.Namespace, value.Spec.Selector.MatchLabels)
+		if err != nil {
+			return readiness{}, err
+		}
+		if ratio := c.readyThreshold("StatefulSet"); ratio > 0 {
+			ss, err := client.Apps().StatefulSets(value.Namespace).Get(value.Name, metav1.GetOptions{})
+			if err != nil {
+				return readiness{}, err
+			}
+			threshold = &podThreshold{kind: "StatefulSet", name: value.Name, desired: desiredReplicas(ss.Spec.Replicas), ratio: ratio, pods: list}
+			break
+		}
+		pods = append(pods, list...)
+	case (*extensions.ReplicaSet):
+		list, err := getGatedPods(client, value.Namespace, value.Spec.Selector.MatchLabels)
+		if err != nil {
+			return readiness{}, err
+		}
+		if ratio := c.readyThreshold("ReplicaSet"); ratio > 0 {
+			rs, err := client.Extensions().ReplicaSets(value.Namespace).Get(value.Name, metav1.GetOptions{})
+			if err != nil {
+				return readiness{}, err
+			}
+			threshold = &podThreshold{kind: "ReplicaSet", name: value.Name, desired: desiredReplicas(rs.Spec.Replicas), ratio: ratio, pods: list}
+			break
+		}
+		pods = append(pods, list...)
+	case (*v1.PersistentVolumeClaim):
+		claim, err := client.Core().PersistentVolumeClaims(value.Namespace).Get(value.Name, metav1.GetOptions{})
+		if err != nil {
+			return readiness{}, err
+		}
+		pvc = append(pvc, *claim)
+	case (*v1.Service):
+		svc, err := client.Core().Services(value.Namespace).Get(value.Name, metav1.GetOptions{})
+		if err != nil {
+			return readiness{}, err
+		}
+		services = append(services, *svc)
+	case (*autoscaling.HorizontalPodAutoscaler):
+		if hpas == nil || hpas.window <= 0 {
+			break
+		}
+		hpa, err := client.Autoscaling().HorizontalPodAutoscalers(value.Namespace).Get(value.Name, metav1.GetOptions{})
+		if err != nil {
+			return readiness{}, err
+		}
+		pending = hpas.pending(hpa)
+	}
+
+	if !gates {
+		ignoreGates(pods)
+		if threshold != nil {
+			ignoreGates(threshold.pods)
+		}
+	}
+	if pending == "" {
+		pending = pendingPodGates(pods, deployments)
+	}
+	if pending == "" && threshold != nil {
+		pending = threshold.pending()
+	}
+
+	var state readiness
+	if state.unbound, err = unboundVolumes(pvc, delaysBinding); err != nil {
+		return readiness{}, err
+	}
+	if state.pending, err = c.pendingGate(Result{info}); err != nil {
+		return readiness{}, err
+	}
+	if state.pending == "" {
+		state.pending = pending
+	}
+	state.ready = podsReady(pods) && servicesReady(services) && len(state.unbound) == 0 && deploymentsReady(deployments) && state.pending == ""
+	if !state.ready {
+		if threshold != nil {
+			pods = append(pods, threshold.pods...)
+		}
+		state.unready = unreadyPods(pods, deployments)
+	}
+	return state, nil
 }
 
-func podsReady(pods []v1.Pod) bool {
+func podsReady(pods []gatedPod) bool {
 	for _, pod := range pods {
-		if !v1.IsPodReady(&pod) {
+		if !v1.IsPodReady(&pod.Pod) || pendingPodGate(pod) != "" {
 			return false
 		}
 	}
 	return true
 }
 
+// pendingPodGates returns a description of the first readiness gate that a
+// pod in pods, or of a deployment, is still waiting for, or "" if there is
+// none. The pods of a deployment that has as many ready replicas as it
+// needs are not waited for, like pods without gates are not.
+func pendingPodGates(pods []gatedPod, deployments []deployment) string {
+	for _, d := range deployments {
+		if readyReplicas(d) < neededReplicas(d) {
+			pods = append(pods, d.pods...)
+		}
+	}
+	for _, p := range pods {
+		if gate := pendingPodGate(p); gate != "" {
+			return gate
+		}
+	}
+	return ""
+}
+
 func servicesReady(svc []v1.Service) bool {
 	for _, s := range svc {
 		// ExternalName Services are external to cluster so helm shouldn't be checking to see if they're 'ready' (i.e. have an IP Set)
@@ -146,37 +479,108 @@ func servicesReady(svc []v1.Service) bool {
 			return false
 		}
 		// This checks if the service has a LoadBalancer and that balancer has an Ingress defined
-		if s.Spec.Type == v1.ServiceTypeLoadBalancer && s.Status.LoadBalancer.Ingress == nil {
+		if s.Spec.Type == v1.ServiceTypeLoadBalancer && waitForLoadBalancer(s) && !loadBalancerReady(s) {
 			return false
 		}
 	}
 	return true
 }
 
-func volumesReady(vols []v1.PersistentVolumeClaim) bool {
+// LoadBalancerWaitAnno is the annotation that, when set to "false" on a
+// LoadBalancer Service, stops a wait from holding until the load balancer is
+// assigned an address.
+const LoadBalancerWaitAnno = "helm.sh/wait-for-load-balancer"
+
+func waitForLoadBalancer(s v1.Service) bool {
+	return s.Annotations[LoadBalancerWaitAnno] != "false"
+}
+
+// loadBalancerReady returns true once the cloud provider has assigned the
+// load balancer an IP address or, on providers such as AWS, a hostname.
+func loadBalancerReady(s v1.Service) bool {
+	for _, ing := range s.Status.LoadBalancer.Ingress {
+		if ing.IP != "" || ing.Hostname != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// unboundVolumes returns the claims in vols that are not bound yet, as
+// "namespace/name (phase)". Pending claims are left out if delaysBinding
+// reports that their storage class only binds them once a pod uses them.
+func unboundVolumes(vols []v1.PersistentVolumeClaim, delaysBinding func(class string) (bool, error)) ([]string, error) {
+	unbound := []string{}
 	for _, v := range vols {
-		if v.Status.Phase != v1.ClaimBound {
-			return false
+		if v.Status.Phase == v1.ClaimBound {
+			continue
+		}
+		if class := v1.GetPersistentVolumeClaimClass(&v); v.Status.Phase == v1.ClaimPending && class != "" {
+			late, err := delaysBinding(class)
+			if err != nil {
+				return nil, err
+			}
+			if late {
+				continue
+			}
 		}
+		unbound = append(unbound, fmt.Sprintf("%s/%s (%s)", v.Namespace, v.Name, v.Status.Phase))
 	}
-	return true
+	return unbound, nil
+}
+
+// storageClassBindingMode returns the volume binding mode of the named
+// storage class. The field is read from the raw object, as it is newer than
+// the API types Helm is built with; servers that do not know it return "".
+func storageClassBindingMode(cs internalclientset.Interface, name string) (string, error) {
+	raw, err := cs.Storage().RESTClient().Get().AbsPath("/apis/storage.k8s.io/v1/storageclasses", name).DoRaw()
+	if err != nil {
+		return "", err
+	}
+	var class struct {
+		VolumeBindingMode string `json:"volumeBindingMode"`
+	}
+	if err := json.Unmarshal(raw, &class); err != nil {
+		return "", err
+	}
+	return class.VolumeBindingMode, nil
 }
 
 func deploymentsReady(deployments []deployment) bool {
 	for _, v := range deployments {
-		if !(v.replicaSets.Status.ReadyReplicas >= *v.deployment.Spec.Replicas-deploymentutil.MaxUnavailable(*v.deployment)) {
+		if readyReplicas(v) < neededReplicas(v) {
 			return false
 		}
 	}
 	return true
 }
 
-func getPods(client clientset.Interface, namespace string, selector map[string]string) ([]v1.Pod, error) {
-	list, err := client.Core().Pods(namespace).List(metav1.ListOptions{
-		FieldSelector: fields.Everything().String(),
-		LabelSelector: labels.Set(selector).AsSelector().String(),
-	})
-	return list.Items, err
+// neededReplicas returns how many ready replicas d needs: all but those its
+// rollout may leave unavailable, or its ready threshold if it has one.
+func neededReplicas(d deployment) int32 {
+	if d.threshold > 0 {
+		return neededReady(*d.deployment.Spec.Replicas, d.threshold)
+	}
+	return *d.deployment.Spec.Replicas - deploymentutil.MaxUnavailable(*d.deployment)
+}
+
+// readyReplicas returns the number of ready pods of the new replica set of
+// d. If the pods have readiness gates, only those that passed them count.
+func readyReplicas(d deployment) int32 {
+	gated := false
+	for _, p := range d.pods {
+		gated = gated || len(p.gates) > 0
+	}
+	if !gated {
+		return d.replicaSets.Status.ReadyReplicas
+	}
+	var ready int32
+	for _, p := range d.pods {
+		if v1.IsPodReady(&p.Pod) && pendingPodGate(p) == "" {
+			ready++
+		}
+	}
+	return ready
 }
 
 func versionedClientsetForDeployment(internalClient internalclientset.Interface) clientset.Interface {
@@ -0,0 +1,172 @@
+/*
+Copyright 2017 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kube // import "k8s.io/helm/pkg/kube"
+
+import (
+	"bytes"
+	"fmt"
+	"time"
+
+	"k8s.io/kubernetes/pkg/api/v1"
+	"k8s.io/kubernetes/pkg/client/clientset_generated/clientset"
+)
+
+// waitLogLines is the number of lines a wait reads from the end of the log of
+// each container that is not ready.
+const waitLogLines = 20
+
+// WaitLogInterval is how often a wait gives the logs of the containers that
+// are not ready to the client's WaitLogSink.
+var WaitLogInterval = 10 * time.Second
+
+// WithWaitLogs returns a copy of the client whose waits give the end of the
+// logs of the containers that are not ready to sink as they go on, in at most
+// limit bytes at a time, and add them to the error of a wait that times out.
+func (c *Client) WithWaitLogs(limit int64, sink func(logs string)) *Client {
+	n := *c
+	n.WaitLogBytes = limit
+	n.WaitLogSink = sink
+	return &n
+}
+
+// waitLogStream gives the logs of the pods that are not ready to the
+// client's WaitLogSink during a wait.
+type waitLogStream struct {
+	c    *Client
+	last time.Time
+	logs string
+}
+
+// send gives the logs of the containers that states report as not ready to
+// the sink, if WaitLogInterval has passed since they were last given and they
+// changed since.
+func (w *waitLogStream) send(fetch logFetcher, states []readiness) {
+	if w.c.WaitLogSink == nil || w.c.WaitLogBytes <= 0 || time.Since(w.last) < WaitLogInterval {
+		return
+	}
+	w.last = time.Now()
+	logs := podLogs(fetch, statesUnready(states), w.c.WaitLogBytes)
+	if logs == "" || logs == w.logs {
+		return
+	}
+	w.logs = logs
+	w.c.WaitLogSink(logs)
+}
+
+// logFetcher returns the log of a container of pod, as selected by opts.
+type logFetcher func(pod v1.Pod, opts *v1.PodLogOptions) ([]byte, error)
+
+func clientLogFetcher(client clientset.Interface) logFetcher {
+	return func(pod v1.Pod, opts *v1.PodLogOptions) ([]byte, error) {
+		return client.Core().Pods(pod.Namespace).GetLogs(pod.Name, opts).DoRaw()
+	}
+}
+
+// unreadyPods returns the pods of pods and deployments that are not ready or
+// wait for a readiness gate.
+func unreadyPods(pods []gatedPod, deployments []deployment) []v1.Pod {
+	for _, d := range deployments {
+		pods = append(pods, d.pods...)
+	}
+	var unready []v1.Pod
+	for _, p := range pods {
+		if !v1.IsPodReady(&p.Pod) || pendingPodGate(p) != "" {
+			unready = append(unready, p.Pod)
+		}
+	}
+	return unready
+}
+
+// unreadyContainers returns the containers of pod that are not ready. A
+// container without a status yet counts as not ready.
+func unreadyContainers(pod v1.Pod) []v1.ContainerStatus {
+	statuses := map[string]v1.ContainerStatus{}
+	for _, s := range pod.Status.ContainerStatuses {
+		statuses[s.Name] = s
+	}
+	var unready []v1.ContainerStatus
+	for _, c := range pod.Spec.Containers {
+		s, ok := statuses[c.Name]
+		if !ok {
+			s = v1.ContainerStatus{Name: c.Name}
+		}
+		if !s.Ready {
+			unready = append(unready, s)
+		}
+	}
+	return unready
+}
+
+// podLogs returns the end of the logs of the containers of pods that are not
+// ready, each headed by "==> namespace/pod/container <==", in at most limit
+// bytes. The bytes left are shared between the containers that have not been
+// read yet. Of a container that is waiting to be restarted, as in
+// CrashLoopBackOff, the log of its last run is read, since that explains why
+// it stopped.
+func podLogs(fetch logFetcher, pods []v1.Pod, limit int64) string {
+	type source struct {
+		pod       v1.Pod
+		container v1.ContainerStatus
+	}
+	var sources []source
+	seen := map[string]bool{}
+	for _, pod := range pods {
+		key := pod.Namespace + "/" + pod.Name
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		for _, c := range unreadyContainers(pod) {
+			sources = append(sources, source{pod, c})
+		}
+	}
+
+	var buf bytes.Buffer
+	for i, s := range sources {
+		head := fmt.Sprintf("==> %s/%s/%s <==\n", s.pod.Namespace, s.pod.Name, s.container.Name)
+		share := (limit - int64(buf.Len())) / int64(len(sources)-i)
+		if share <= int64(len(head)) {
+			continue
+		}
+		opts := &v1.PodLogOptions{
+			Container:  s.container.Name,
+			Previous:   s.container.State.Waiting != nil && s.container.RestartCount > 0,
+			TailLines:  int64Ptr(waitLogLines),
+			LimitBytes: int64Ptr(share - int64(len(head))),
+		}
+		out, err := fetch(s.pod, opts)
+		if err != nil {
+			out = []byte(fmt.Sprintf("cannot read the log: %s\n", err))
+		}
+		if len(out) == 0 {
+			continue
+		}
+		// The server may not know LimitBytes, so the budget is kept here,
+		// keeping the end of the log.
+		if max := share - int64(len(head)); int64(len(out)) > max {
+			out = out[int64(len(out))-max:]
+		}
+		buf.WriteString(head)
+		buf.Write(out)
+		if out[len(out)-1] != '\n' && int64(buf.Len()) < limit {
+			buf.WriteByte('\n')
+		}
+	}
+	return buf.String()
+}
+
+func int64Ptr(i int64) *int64 { return &i }
@@ -0,0 +1,136 @@
+/*
+Copyright 2017 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kube
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"k8s.io/kubernetes/pkg/api/v1"
+)
+
+// logPod returns a pod with containers, of which those in ready are ready.
+func logPod(name string, containers []string, ready ...string) v1.Pod {
+	var pod v1.Pod
+	pod.Name = name
+	pod.Namespace = "default"
+	for _, c := range containers {
+		pod.Spec.Containers = append(pod.Spec.Containers, v1.Container{Name: c})
+		status := v1.ContainerStatus{Name: c}
+		for _, r := range ready {
+			status.Ready = status.Ready || r == c
+		}
+		pod.Status.ContainerStatuses = append(pod.Status.ContainerStatuses, status)
+	}
+	return pod
+}
+
+func TestPodLogs(t *testing.T) {
+	crashing := logPod("web-2", []string{"app"})
+	crashing.Status.ContainerStatuses[0].RestartCount = 3
+	crashing.Status.ContainerStatuses[0].State.Waiting = &v1.ContainerStateWaiting{Reason: "CrashLoopBackOff"}
+	pods := []v1.Pod{
+		logPod("web-1", []string{"app", "proxy"}, "proxy"),
+		crashing,
+		logPod("web-1", []string{"app", "proxy"}, "proxy"),
+	}
+
+	var read []string
+	fetch := func(pod v1.Pod, opts *v1.PodLogOptions) ([]byte, error) {
+		read = append(read, fmt.Sprintf("%s/%s previous=%t", pod.Name, opts.Container, opts.Previous))
+		if pod.Name == "web-2" {
+			return []byte("panic: cannot connect to the database"), nil
+		}
+		return []byte("starting\n"), nil
+	}
+
+	logs := podLogs(fetch, pods, 1024)
+	expect := "==> default/web-1/app <==\nstarting\n==> default/web-2/app <==\npanic: cannot connect to the database\n"
+	if logs != expect {
+		t.Errorf("Expected %q, got %q", expect, logs)
+	}
+	if expect := "web-1/app previous=false, web-2/app previous=true"; strings.Join(read, ", ") != expect {
+		t.Errorf("Expected the logs of %s to be read, got %v", expect, read)
+	}
+
+	// The budget keeps the end of each log.
+	logs = podLogs(fetch, pods, 100)
+	if len(logs) > 100 {
+		t.Errorf("Expected at most 100 bytes, got %d: %q", len(logs), logs)
+	}
+	if !strings.Contains(logs, "==> default/web-2/app <==\n") || !strings.Contains(logs, "the database") {
+		t.Errorf("Expected the end of the log of each container, got %q", logs)
+	}
+
+	if logs := podLogs(fetch, pods, 10); logs != "" {
+		t.Errorf("Expected no logs without room for a header, got %q", logs)
+	}
+}
+
+func TestUnreadyPods(t *testing.T) {
+	ready := readyPod("web-1", nil)
+	gated := readyPod("web-2", []string{"example.com/lb-registered"})
+	starting := gatedPod{}
+	starting.Name = "web-3"
+
+	unready := unreadyPods([]gatedPod{ready, gated}, []deployment{{pods: []gatedPod{starting}}})
+	if len(unready) != 2 || unready[0].Name != "web-2" || unready[1].Name != "web-3" {
+		t.Errorf("Expected web-2 and web-3 to be unready, got %v", unready)
+	}
+}
+
+func TestWaitLogStream(t *testing.T) {
+	defer func(interval time.Duration) { WaitLogInterval = interval }(WaitLogInterval)
+	WaitLogInterval = time.Hour
+
+	var sent []string
+	c := (&Client{}).WithWaitLogs(1024, func(logs string) { sent = append(sent, logs) })
+	out := "starting\n"
+	fetch := func(pod v1.Pod, opts *v1.PodLogOptions) ([]byte, error) {
+		return []byte(out), nil
+	}
+	states := []readiness{
+		{ready: true, unready: []v1.Pod{logPod("db-1", []string{"db"})}},
+		{unready: []v1.Pod{logPod("web-1", []string{"app"})}},
+	}
+
+	// Nothing is sent before the interval has passed.
+	w := &waitLogStream{c: c, last: time.Now()}
+	w.send(fetch, states)
+	if len(sent) != 0 {
+		t.Fatalf("Expected no logs within the interval, got %q", sent)
+	}
+
+	w.last = time.Time{}
+	w.send(fetch, states)
+	// Logs that did not change are not sent again.
+	w.last = time.Time{}
+	w.send(fetch, states)
+	out = "panic: cannot connect to the database\n"
+	w.last = time.Time{}
+	w.send(fetch, states)
+	expect := []string{
+		"==> default/web-1/app <==\nstarting\n",
+		"==> default/web-1/app <==\npanic: cannot connect to the database\n",
+	}
+	if !reflect.DeepEqual(sent, expect) {
+		t.Errorf("Expected %q, got %q", expect, sent)
+	}
+}
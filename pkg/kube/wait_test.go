@@ -0,0 +1,206 @@
+/*
+Copyright 2017 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kube
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/kubernetes/pkg/api/v1"
+	"k8s.io/kubernetes/pkg/kubectl/resource"
+)
+
+func TestServicesReady(t *testing.T) {
+	loadBalancer := func(annotations map[string]string, ingress ...v1.LoadBalancerIngress) v1.Service {
+		return v1.Service{
+			ObjectMeta: metav1.ObjectMeta{Name: "web", Annotations: annotations},
+			Spec:       v1.ServiceSpec{Type: v1.ServiceTypeLoadBalancer, ClusterIP: "10.0.0.10"},
+			Status:     v1.ServiceStatus{LoadBalancer: v1.LoadBalancerStatus{Ingress: ingress}},
+		}
+	}
+
+	tests := []struct {
+		name   string
+		svc    v1.Service
+		expect bool
+	}{
+		{"cluster IP", v1.Service{Spec: v1.ServiceSpec{Type: v1.ServiceTypeClusterIP, ClusterIP: "10.0.0.10"}}, true},
+		{"cluster IP pending", v1.Service{Spec: v1.ServiceSpec{Type: v1.ServiceTypeClusterIP}}, false},
+		{"headless", v1.Service{Spec: v1.ServiceSpec{Type: v1.ServiceTypeClusterIP, ClusterIP: v1.ClusterIPNone}}, true},
+		{"external name", v1.Service{Spec: v1.ServiceSpec{Type: v1.ServiceTypeExternalName}}, true},
+		{"load balancer pending", loadBalancer(nil), false},
+		{"load balancer empty ingress", loadBalancer(nil, v1.LoadBalancerIngress{}), false},
+		{"load balancer IP", loadBalancer(nil, v1.LoadBalancerIngress{IP: "203.0.113.7"}), true},
+		{"load balancer hostname", loadBalancer(nil, v1.LoadBalancerIngress{Hostname: "web.elb.example.com"}), true},
+		{"load balancer wait skipped", loadBalancer(map[string]string{LoadBalancerWaitAnno: "false"}), true},
+	}
+	for _, tt := range tests {
+		if got := servicesReady([]v1.Service{tt.svc}); got != tt.expect {
+			t.Errorf("%s: expected ready to be %t, got %t", tt.name, tt.expect, got)
+		}
+	}
+}
+
+func TestUnboundVolumes(t *testing.T) {
+	claim := func(name, class string, phase v1.PersistentVolumeClaimPhase) v1.PersistentVolumeClaim {
+		c := v1.PersistentVolumeClaim{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+			Status:     v1.PersistentVolumeClaimStatus{Phase: phase},
+		}
+		if class != "" {
+			c.Spec.StorageClassName = &class
+		}
+		return c
+	}
+	delaysBinding := func(class string) (bool, error) {
+		if class == "broken" {
+			return false, errors.New("storage class lookup failed")
+		}
+		return class == "local", nil
+	}
+
+	tests := []struct {
+		name   string
+		claim  v1.PersistentVolumeClaim
+		expect []string
+	}{
+		{"bound", claim("data", "standard", v1.ClaimBound), []string{}},
+		{"pending", claim("data", "standard", v1.ClaimPending), []string{"default/data (Pending)"}},
+		{"pending without class", claim("data", "", v1.ClaimPending), []string{"default/data (Pending)"}},
+		{"waiting for first consumer", claim("data", "local", v1.ClaimPending), []string{}},
+		{"lost", claim("data", "local", v1.ClaimLost), []string{"default/data (Lost)"}},
+	}
+	for _, tt := range tests {
+		got, err := unboundVolumes([]v1.PersistentVolumeClaim{tt.claim}, delaysBinding)
+		if err != nil {
+			t.Fatalf("%s: %s", tt.name, err)
+		}
+		if !reflect.DeepEqual(got, tt.expect) {
+			t.Errorf("%s: expected %v, got %v", tt.name, tt.expect, got)
+		}
+	}
+
+	if _, err := unboundVolumes([]v1.PersistentVolumeClaim{claim("data", "broken", v1.ClaimPending)}, delaysBinding); err == nil {
+		t.Error("expected the storage class lookup error to be returned")
+	}
+}
+
+func TestParseWaitTimeout(t *testing.T) {
+	tests := []struct {
+		value  string
+		expect time.Duration
+		err    bool
+	}{
+		{"20m", 20 * time.Minute, false},
+		{" 90s ", 90 * time.Second, false},
+		{"600", 10 * time.Minute, false},
+		{"0", 0, true},
+		{"-5m", 0, true},
+		{"soon", 0, true},
+	}
+	for _, tt := range tests {
+		got, err := parseWaitTimeout(tt.value)
+		if tt.err {
+			if err == nil {
+				t.Errorf("%q: expected an error", tt.value)
+			}
+			continue
+		}
+		if err != nil || got != tt.expect {
+			t.Errorf("%q: expected %v, got %v (%v)", tt.value, tt.expect, got, err)
+		}
+	}
+}
+
+func waitInfo(kind, name string, annotations map[string]string) *resource.Info {
+	return &resource.Info{
+		Name:      name,
+		Namespace: "default",
+		Object:    &v1.Pod{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default", Annotations: annotations}},
+		Mapping:   &meta.RESTMapping{GroupVersionKind: schema.GroupVersionKind{Kind: kind}},
+	}
+}
+
+func TestWaitLimits(t *testing.T) {
+	infos := Result{
+		waitInfo("StatefulSet", "db", map[string]string{WaitTimeoutAnno: "20m"}),
+		waitInfo("Deployment", "web", nil),
+	}
+	limits, err := waitLimits(infos, 2*time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expect := []waitLimit{{timeout: 20 * time.Minute, own: true}, {timeout: 2 * time.Minute}}
+	if !reflect.DeepEqual(limits, expect) {
+		t.Errorf("expected %v, got %v", expect, limits)
+	}
+
+	infos = Result{waitInfo("StatefulSet", "db", map[string]string{WaitTimeoutAnno: "later"})}
+	_, err = waitLimits(infos, 2*time.Minute)
+	if err == nil || err.Error() != `StatefulSet default/db: invalid helm.sh/wait-timeout "later": expected a positive duration such as 20m` {
+		t.Errorf("expected the invalid annotation to be reported, got %v", err)
+	}
+}
+
+func TestWaitTimeoutError(t *testing.T) {
+	infos := Result{
+		waitInfo("StatefulSet", "db", nil),
+		waitInfo("Deployment", "web", nil),
+		waitInfo("PersistentVolumeClaim", "data", nil),
+	}
+	limits := []waitLimit{{timeout: 20 * time.Minute, own: true}, {timeout: 2 * time.Minute}, {timeout: 2 * time.Minute}}
+	notReady := []readiness{{}, {}, {}}
+
+	tests := []struct {
+		name    string
+		states  []readiness
+		elapsed time.Duration
+		expect  string
+	}{
+		{"within all limits", notReady, time.Minute, ""},
+		{"overdue resources are ready", []readiness{{}, {ready: true}, {ready: true}}, 5 * time.Minute, ""},
+		{"release timeout", notReady, 5 * time.Minute, wait.ErrWaitTimeout.Error()},
+		{
+			"release timeout with unbound claims",
+			[]readiness{{}, {}, {unbound: []string{"default/data (Pending)"}}},
+			5 * time.Minute,
+			"timed out waiting for PersistentVolumeClaims to bind: default/data (Pending)",
+		},
+		{
+			"own limit",
+			[]readiness{{pending: `condition Ready on StatefulSet "db"`}, {ready: true}, {ready: true}},
+			20 * time.Minute,
+			`timed out waiting for StatefulSet default/db: not ready within its helm.sh/wait-timeout of 20m0s (waiting for condition Ready on StatefulSet "db")`,
+		},
+	}
+	for _, tt := range tests {
+		err := waitTimeoutError(infos, limits, tt.states, tt.elapsed)
+		got := ""
+		if err != nil {
+			got = err.Error()
+		}
+		if got != tt.expect {
+			t.Errorf("%s: expected %q, got %q", tt.name, tt.expect, got)
+		}
+	}
+}
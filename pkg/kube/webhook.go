@@ -0,0 +1,161 @@
+/*
+Copyright 2017 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kube // import "k8s.io/helm/pkg/kube"
+
+import (
+	"fmt"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/kubernetes/pkg/api"
+	"k8s.io/kubernetes/pkg/kubectl/resource"
+)
+
+// admissionGroup is the API group of webhook configurations.
+const admissionGroup = "admissionregistration.k8s.io"
+
+// serviceRef names a service that backs an admission webhook.
+type serviceRef struct {
+	namespace, name string
+}
+
+func (s serviceRef) String() string {
+	return s.namespace + "/" + s.name
+}
+
+// isWebhookConfiguration returns true if gvk is a validating or mutating
+// webhook configuration.
+func isWebhookConfiguration(gvk schema.GroupVersionKind) bool {
+	return gvk.Group == admissionGroup &&
+		(gvk.Kind == "ValidatingWebhookConfiguration" || gvk.Kind == "MutatingWebhookConfiguration")
+}
+
+// webhookServices returns the in-cluster services that the webhooks of a
+// webhook configuration call. Webhooks that call a URL are skipped.
+func webhookServices(obj runtime.Object) ([]serviceRef, error) {
+	content, err := objectContent(obj)
+	if err != nil {
+		return nil, err
+	}
+
+	var refs []serviceRef
+	webhooks, _ := content["webhooks"].([]interface{})
+	for _, w := range webhooks {
+		webhook, _ := w.(map[string]interface{})
+		clientConfig, _ := webhook["clientConfig"].(map[string]interface{})
+		service, ok := clientConfig["service"].(map[string]interface{})
+		if !ok {
+			continue
+		}
+		ns, _ := service["namespace"].(string)
+		name, _ := service["name"].(string)
+		if name == "" {
+			continue
+		}
+		refs = append(refs, serviceRef{namespace: ns, name: name})
+	}
+	return refs, nil
+}
+
+// createResources creates infos in order.
+//
+// If WaitForWebhooks is set, creation pauses after each webhook configuration
+// until the services behind its webhooks have ready endpoints, so resources
+// later in the manifest are not sent to a webhook that cannot answer yet.
+//
+// Creation stops at the first resource that fails. The returned ApplyError
+// lists the resources created before it, and the one that failed.
+func (c *Client) createResources(infos Result, timeout time.Duration) error {
+	if len(infos) == 0 {
+		return ErrNoObjectsVisited
+	}
+	results := make([]*ApplyStatus, len(infos))
+	err := c.applyByKind(infos, func(i int, info *resource.Info) error {
+		err := c.createAndAwaitWebhook(info, timeout)
+		results[i] = &ApplyStatus{Kind: info.Mapping.GroupVersionKind.Kind, Name: info.Name, Err: err}
+		return err
+	})
+	if err != nil {
+		return &ApplyError{Statuses: attemptedStatuses(results), Err: err}
+	}
+	return nil
+}
+
+// createAndAwaitWebhook creates info and, if it is a webhook configuration and
+// WaitForWebhooks is set, waits for the services behind its webhooks.
+func (c *Client) createAndAwaitWebhook(info *resource.Info, timeout time.Duration) error {
+	if err := createResource(info); err != nil {
+		return err
+	}
+	if !c.WaitForWebhooks || !isWebhookConfiguration(info.Mapping.GroupVersionKind) {
+		return nil
+	}
+	services, err := webhookServices(info.Object)
+	if err != nil {
+		return err
+	}
+	c.Log("waiting for endpoints of %d service(s) backing %s %q", len(services), info.Mapping.GroupVersionKind.Kind, info.Name)
+	return c.waitForEndpoints(timeout, services)
+}
+
+// waitForEndpoints polls until every service in services has at least one
+// ready endpoint address, or the timeout is reached.
+func (c *Client) waitForEndpoints(timeout time.Duration, services []serviceRef) error {
+	if len(services) == 0 {
+		return nil
+	}
+	cs, err := c.ClientSet()
+	if err != nil {
+		return err
+	}
+	var pending serviceRef
+	err = wait.PollImmediate(2*time.Second, timeout, func() (bool, error) {
+		for _, s := range services {
+			ep, err := cs.Core().Endpoints(s.namespace).Get(s.name, metav1.GetOptions{})
+			if errors.IsNotFound(err) {
+				pending = s
+				return false, nil
+			}
+			if err != nil {
+				return false, err
+			}
+			if !hasReadyAddress(ep) {
+				pending = s
+				return false, nil
+			}
+		}
+		return true, nil
+	})
+	if err == wait.ErrWaitTimeout {
+		return fmt.Errorf("timed out waiting for ready endpoints of webhook service %s", pending)
+	}
+	return err
+}
+
+// hasReadyAddress returns true if ep has at least one ready address.
+func hasReadyAddress(ep *api.Endpoints) bool {
+	for _, subset := range ep.Subsets {
+		if len(subset.Addresses) > 0 {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,178 @@
+/*
+Copyright 2017 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kube
+
+import (
+	"net/http"
+	"reflect"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+	restclient "k8s.io/client-go/rest"
+	"k8s.io/client-go/rest/fake"
+	"k8s.io/kubernetes/pkg/api"
+	cmdtesting "k8s.io/kubernetes/pkg/kubectl/cmd/testing"
+)
+
+func TestIsWebhookConfiguration(t *testing.T) {
+	tests := []struct {
+		gvk    schema.GroupVersionKind
+		expect bool
+	}{
+		{schema.GroupVersionKind{Group: "admissionregistration.k8s.io", Version: "v1beta1", Kind: "ValidatingWebhookConfiguration"}, true},
+		{schema.GroupVersionKind{Group: "admissionregistration.k8s.io", Version: "v1beta1", Kind: "MutatingWebhookConfiguration"}, true},
+		{schema.GroupVersionKind{Group: "admissionregistration.k8s.io", Version: "v1alpha1", Kind: "InitializerConfiguration"}, false},
+		{schema.GroupVersionKind{Version: "v1", Kind: "Service"}, false},
+	}
+	for _, tt := range tests {
+		if got := isWebhookConfiguration(tt.gvk); got != tt.expect {
+			t.Errorf("%s: expected %t, got %t", tt.gvk, tt.expect, got)
+		}
+	}
+}
+
+func TestWebhookServices(t *testing.T) {
+	obj := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "admissionregistration.k8s.io/v1beta1",
+		"kind":       "ValidatingWebhookConfiguration",
+		"metadata":   map[string]interface{}{"name": "policy"},
+		"webhooks": []interface{}{
+			map[string]interface{}{
+				"name": "pods.policy.example.com",
+				"clientConfig": map[string]interface{}{
+					"service": map[string]interface{}{"namespace": "policy", "name": "policy-webhook"},
+				},
+			},
+			map[string]interface{}{
+				"name": "external.policy.example.com",
+				"clientConfig": map[string]interface{}{
+					"url": "https://policy.example.com/validate",
+				},
+			},
+		},
+	}}
+
+	refs, err := webhookServices(obj)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expect := []serviceRef{{namespace: "policy", name: "policy-webhook"}}
+	if !reflect.DeepEqual(refs, expect) {
+		t.Errorf("expected %v, got %v", expect, refs)
+	}
+}
+
+func TestWaitForEndpoints(t *testing.T) {
+	ready := api.Endpoints{
+		ObjectMeta: metav1.ObjectMeta{Name: "ready", Namespace: "policy"},
+		Subsets: []api.EndpointSubset{{
+			Addresses: []api.EndpointAddress{{IP: "10.0.0.1"}},
+		}},
+	}
+	starting := api.Endpoints{
+		ObjectMeta: metav1.ObjectMeta{Name: "starting", Namespace: "policy"},
+		Subsets: []api.EndpointSubset{{
+			NotReadyAddresses: []api.EndpointAddress{{IP: "10.0.0.2"}},
+		}},
+	}
+
+	f, tf, _, ns := cmdtesting.NewAPIFactory()
+	tf.Client = &fake.RESTClient{
+		APIRegistry:          api.Registry,
+		NegotiatedSerializer: ns,
+		Client: fake.CreateHTTPClient(func(req *http.Request) (*http.Response, error) {
+			switch p, m := req.URL.Path, req.Method; {
+			case p == "/api/v1/namespaces/policy/endpoints/ready" && m == "GET":
+				return newResponse(200, &ready)
+			case p == "/api/v1/namespaces/policy/endpoints/starting" && m == "GET":
+				return newResponse(200, &starting)
+			case p == "/api/v1/namespaces/policy/endpoints/missing" && m == "GET":
+				return newResponse(404, notFoundBody())
+			}
+			t.Fatalf("unexpected request: %s %s", req.Method, req.URL.Path)
+			return nil, nil
+		}),
+	}
+	tf.ClientConfig = &restclient.Config{
+		APIPath: "/api",
+		ContentConfig: restclient.ContentConfig{
+			NegotiatedSerializer: api.Codecs,
+			ContentType:          runtime.ContentTypeJSON,
+			GroupVersion:         &api.Registry.GroupOrDie(api.GroupName).GroupVersion,
+		},
+	}
+	c := newTestClient(f)
+
+	if err := c.waitForEndpoints(time.Second, []serviceRef{{"policy", "ready"}}); err != nil {
+		t.Errorf("expected ready endpoints, got %v", err)
+	}
+
+	for _, name := range []string{"starting", "missing"} {
+		err := c.waitForEndpoints(time.Millisecond, []serviceRef{{"policy", "ready"}, {"policy", name}})
+		expect := "timed out waiting for ready endpoints of webhook service policy/" + name
+		if err == nil || err.Error() != expect {
+			t.Errorf("expected %q, got %v", expect, err)
+		}
+	}
+}
+
+func TestCreateResourcesStatuses(t *testing.T) {
+	pods := newPodList("starfish", "otter", "squid")
+
+	var created []string
+	f, tf, codec, _ := cmdtesting.NewAPIFactory()
+	tf.UnstructuredClient = &fake.RESTClient{
+		APIRegistry:          api.Registry,
+		NegotiatedSerializer: dynamic.ContentConfig().NegotiatedSerializer,
+		Client: fake.CreateHTTPClient(func(req *http.Request) (*http.Response, error) {
+			if req.URL.Path != "/namespaces/default/pods" || req.Method != "POST" {
+				t.Fatalf("unexpected request: %s %s", req.Method, req.URL.Path)
+			}
+			if len(created) == 1 {
+				return newResponse(422, &metav1.Status{Status: metav1.StatusFailure, Reason: metav1.StatusReasonInvalid, Message: "otter is invalid"})
+			}
+			created = append(created, req.URL.Path)
+			return newResponse(201, &pods.Items[len(created)-1])
+		}),
+	}
+	c := newTestClient(f)
+
+	infos, err := c.BuildUnstructured(api.NamespaceDefault, objBody(codec, &pods))
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = c.createResources(infos, time.Second)
+	applyErr, ok := err.(*ApplyError)
+	if !ok {
+		t.Fatalf("expected an apply error, got %v", err)
+	}
+	// The squid was never got to, so it is not listed.
+	if len(applyErr.Statuses) != 2 {
+		t.Fatalf("expected statuses for two pods, got %v", applyErr.Statuses)
+	}
+	if st := applyErr.Statuses[0]; st.Kind != "Pod" || st.Name != "starfish" || st.Err != nil {
+		t.Errorf("expected starfish to be created, got %v", st)
+	}
+	if st := applyErr.Statuses[1]; st.Name != "otter" || st.Err == nil {
+		t.Errorf("expected otter to fail, got %v", st)
+	}
+}
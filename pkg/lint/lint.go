@@ -21,16 +21,31 @@ import (
 
 	"k8s.io/helm/pkg/lint/rules"
 	"k8s.io/helm/pkg/lint/support"
+	"k8s.io/helm/pkg/openapi"
 )
 
 // All runs all of the available linters on the given base directory.
 func All(basedir string) support.Linter {
+	return AllWithSchema(basedir, nil)
+}
+
+// AllWithSchema runs all of the available linters on the given base
+// directory, and validates the rendered objects against schema, if it is not
+// nil.
+func AllWithSchema(basedir string, schema *openapi.Schema) support.Linter {
+	return AllWithHelmVersion(basedir, schema, "")
+}
+
+// AllWithHelmVersion runs all of the available linters like AllWithSchema,
+// rendering the templates as Helm helmVersion, e.g. "v2.7.0", would. If it is
+// empty, the version of this Helm is used.
+func AllWithHelmVersion(basedir string, schema *openapi.Schema, helmVersion string) support.Linter {
 	// Using abs path to get directory context
 	chartDir, _ := filepath.Abs(basedir)
 
 	linter := support.Linter{ChartDir: chartDir}
 	rules.Chartfile(&linter)
 	rules.Values(&linter)
-	rules.Templates(&linter)
+	rules.TemplatesWithHelmVersion(&linter, schema, helmVersion)
 	return linter
 }
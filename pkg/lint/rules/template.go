@@ -28,12 +28,29 @@ import (
 	"k8s.io/helm/pkg/chartutil"
 	"k8s.io/helm/pkg/engine"
 	"k8s.io/helm/pkg/lint/support"
+	"k8s.io/helm/pkg/openapi"
+	"k8s.io/helm/pkg/proto/hapi/chart"
+	"k8s.io/helm/pkg/releaseutil"
 	"k8s.io/helm/pkg/timeconv"
 	tversion "k8s.io/helm/pkg/version"
 )
 
 // Templates lints the templates in the Linter.
 func Templates(linter *support.Linter) {
+	TemplatesWithSchema(linter, nil)
+}
+
+// TemplatesWithSchema lints the templates in the Linter, and validates the
+// objects they render against schema, if it is not nil.
+func TemplatesWithSchema(linter *support.Linter, schema *openapi.Schema) {
+	TemplatesWithHelmVersion(linter, schema, "")
+}
+
+// TemplatesWithHelmVersion lints the templates like TemplatesWithSchema,
+// rendering them with helmVersion, e.g. "v2.7.0", as
+// .Capabilities.HelmVersion.Version. If it is empty, the version of this Helm
+// is used.
+func TemplatesWithHelmVersion(linter *support.Linter, schema *openapi.Schema, helmVersion string) {
 	path := "templates/"
 	templatesPath := filepath.Join(linter.ChartDir, path)
 
@@ -64,6 +81,10 @@ func Templates(linter *support.Linter) {
 			Platform:  fmt.Sprintf("%s/%s", runtime.GOOS, runtime.GOARCH),
 		},
 		TillerVersion: tversion.GetVersionProto(),
+		HelmVersion:   chartutil.NewHelmVersion(tversion.GetVersionProto()),
+	}
+	if helmVersion != "" {
+		caps.HelmVersion = &chartutil.HelmVersion{Version: helmVersion}
 	}
 	valuesToRender, err := chartutil.ToRenderValuesCaps(chart, chart.Values, options, caps)
 	if err != nil {
@@ -80,6 +101,12 @@ func Templates(linter *support.Linter) {
 		return
 	}
 
+	// Tests run with the chart's test values, so the templates must also
+	// render with them.
+	if chartutil.HasTestValues(chart) {
+		linter.RunLinterRule(support.ErrorSev, chartutil.TestValuesFile, validateTestValues(chart, options, caps))
+	}
+
 	/* Iterate over all the templates to check:
 	- It is a .yaml file
 	- All the values in the template file is defined
@@ -113,9 +140,15 @@ func Templates(linter *support.Linter) {
 
 		validYaml := linter.RunLinterRule(support.ErrorSev, path, validateYamlContent(err))
 
-		if !validYaml {
+		if !validYaml || schema == nil {
 			continue
 		}
+		// Documents are keyed "manifest-N" in the order they were rendered.
+		docs := releaseutil.SplitManifests(renderedContent)
+		for i := 0; i < len(docs); i++ {
+			doc := docs[fmt.Sprintf("manifest-%d", i)]
+			linter.RunLinterRule(support.ErrorSev, path, schema.ValidateYAML([]byte(doc)))
+		}
 	}
 }
 
@@ -129,6 +162,23 @@ func validateTemplatesDir(templatesPath string) error {
 	return nil
 }
 
+// validateTestValues renders the templates of chrt with its test values
+// merged over its default values.
+func validateTestValues(chrt *chart.Chart, options chartutil.ReleaseOptions, caps *chartutil.Capabilities) error {
+	vals, err := chartutil.ApplyTestValues(chrt, chrt.Values)
+	if err != nil {
+		return err
+	}
+	valuesToRender, err := chartutil.ToRenderValuesCaps(chrt, vals, options, caps)
+	if err != nil {
+		return err
+	}
+	if _, err := engine.New().Render(chrt, valuesToRender); err != nil {
+		return fmt.Errorf("templates do not render with the test values: %s", err)
+	}
+	return nil
+}
+
 func validateAllowedExtension(fileName string) error {
 	ext := filepath.Ext(fileName)
 	validExtensions := []string{".yaml", ".tpl", ".txt"}
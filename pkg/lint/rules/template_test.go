@@ -23,6 +23,7 @@ import (
 	"testing"
 
 	"k8s.io/helm/pkg/lint/support"
+	"k8s.io/helm/pkg/openapi"
 )
 
 const templateTestBasedir = "./testdata/albatross"
@@ -76,3 +77,53 @@ func TestTemplateIntegrationHappyPath(t *testing.T) {
 		t.Fatalf("Expected no error, got %d, %v", len(res), res)
 	}
 }
+
+func TestTemplatesWithSchema(t *testing.T) {
+	schema, err := openapi.LoadFile("../../openapi/testdata/swagger.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	linter := support.Linter{ChartDir: "./testdata/badschema"}
+	TemplatesWithSchema(&linter, schema)
+	res := linter.Messages
+
+	if len(res) != 1 {
+		t.Fatalf("Expected one error, got %d, %v", len(res), res)
+	}
+	expect := `templates/pod.yaml: Deployment "testRelease-web" does not match the OpenAPI schema: spec.replicas: expected integer, got string; spec.template.spec.containers[0].imagePullPolicy: unknown field`
+	if got := res[0].Error(); got != "[ERROR] "+expect {
+		t.Errorf("Expected %q, got %q", expect, got)
+	}
+
+	// Without a schema, the objects are not validated.
+	linter = support.Linter{ChartDir: "./testdata/badschema"}
+	Templates(&linter)
+	if len(linter.Messages) != 0 {
+		t.Errorf("Expected no errors, got %v", linter.Messages)
+	}
+}
+
+func TestTemplatesWithHelmVersion(t *testing.T) {
+	linter := support.Linter{ChartDir: "./testdata/helmversion"}
+	TemplatesWithHelmVersion(&linter, nil, "v2.7.0")
+	if len(linter.Messages) != 0 {
+		t.Fatalf("Expected no errors, got %v", linter.Messages)
+	}
+
+	linter = support.Linter{ChartDir: "./testdata/helmversion"}
+	TemplatesWithHelmVersion(&linter, nil, "v2.4.1")
+	if len(linter.Messages) != 1 || !strings.Contains(linter.Messages[0].Error(), "this chart needs Helm 2.5.0 or newer") {
+		t.Errorf("Expected the chart to fail to render, got %v", linter.Messages)
+	}
+}
+
+func TestTemplatesWithTestValues(t *testing.T) {
+	linter := support.Linter{ChartDir: "./testdata/testvalues"}
+	Templates(&linter)
+	if len(linter.Messages) != 1 {
+		t.Fatalf("Expected one error, got %v", linter.Messages)
+	}
+	if msg := linter.Messages[0]; msg.Path != "values-test.yaml" || !strings.Contains(msg.Err.Error(), "smoke.url is required") {
+		t.Errorf("Expected the test values to fail to render, got %v", msg)
+	}
+}
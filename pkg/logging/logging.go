@@ -0,0 +1,230 @@
+/*
+Copyright 2017 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+/*Package logging provides structured, leveled logging for Tiller.
+
+A Logger writes printf-style messages at a severity level, together with a set
+of key/value fields attached through With. Loggers are cheap to derive, so a
+request can carry a logger that already knows which release and operation it
+belongs to.
+*/
+package logging // import "k8s.io/helm/pkg/logging"
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Level is the severity of a log entry.
+type Level int
+
+const (
+	// DebugLevel is for verbose diagnostic output.
+	DebugLevel Level = iota
+	// InfoLevel is for routine operational messages.
+	InfoLevel
+	// WarnLevel is for unexpected conditions that do not stop an operation.
+	WarnLevel
+	// ErrorLevel is for failures.
+	ErrorLevel
+)
+
+var levelNames = []string{"debug", "info", "warn", "error"}
+
+func (l Level) String() string {
+	if l < DebugLevel || l > ErrorLevel {
+		return fmt.Sprintf("level(%d)", int(l))
+	}
+	return levelNames[l]
+}
+
+// ParseLevel returns the Level with the given name.
+func ParseLevel(name string) (Level, error) {
+	for i, n := range levelNames {
+		if strings.EqualFold(name, n) {
+			return Level(i), nil
+		}
+	}
+	return InfoLevel, fmt.Errorf("unknown log level %q", name)
+}
+
+// Logger is a structured, leveled logger.
+//
+// A Logger must be safe for concurrent use.
+type Logger interface {
+	Debugf(format string, args ...interface{})
+	Infof(format string, args ...interface{})
+	Warnf(format string, args ...interface{})
+	Errorf(format string, args ...interface{})
+
+	// With returns a Logger that adds the given fields to every entry.
+	// Fields are alternating keys and values, e.g. With("release", "foo").
+	With(keyvals ...interface{}) Logger
+}
+
+// Format selects how a Logger created by New encodes entries.
+type Format int
+
+const (
+	// TextFormat writes one human-readable line per entry.
+	TextFormat Format = iota
+	// JSONFormat writes one JSON object per line.
+	JSONFormat
+)
+
+// ParseFormat returns the Format with the given name, "text" or "json".
+func ParseFormat(name string) (Format, error) {
+	switch strings.ToLower(name) {
+	case "text":
+		return TextFormat, nil
+	case "json":
+		return JSONFormat, nil
+	}
+	return TextFormat, fmt.Errorf("unknown log format %q", name)
+}
+
+// New returns a Logger that writes entries at or above level to out.
+func New(out io.Writer, format Format, level Level) Logger {
+	return &logger{
+		out:    out,
+		mu:     &sync.Mutex{},
+		format: format,
+		level:  level,
+		now:    time.Now,
+	}
+}
+
+type logger struct {
+	out    io.Writer
+	mu     *sync.Mutex
+	format Format
+	level  Level
+	fields []interface{}
+	now    func() time.Time
+}
+
+func (l *logger) Debugf(format string, args ...interface{}) { l.log(DebugLevel, format, args) }
+func (l *logger) Infof(format string, args ...interface{})  { l.log(InfoLevel, format, args) }
+func (l *logger) Warnf(format string, args ...interface{})  { l.log(WarnLevel, format, args) }
+func (l *logger) Errorf(format string, args ...interface{}) { l.log(ErrorLevel, format, args) }
+
+func (l *logger) With(keyvals ...interface{}) Logger {
+	if len(keyvals)%2 != 0 {
+		keyvals = append(keyvals, "(MISSING)")
+	}
+	nl := *l
+	nl.fields = make([]interface{}, 0, len(l.fields)+len(keyvals))
+	nl.fields = append(nl.fields, l.fields...)
+	nl.fields = append(nl.fields, keyvals...)
+	return &nl
+}
+
+func (l *logger) log(level Level, format string, args []interface{}) {
+	if level < l.level {
+		return
+	}
+	msg := strings.TrimSpace(fmt.Sprintf(format, args...))
+	ts := l.now().UTC()
+
+	var b bytes.Buffer
+	if l.format == JSONFormat {
+		entry := map[string]interface{}{
+			"time":  ts.Format(time.RFC3339),
+			"level": level.String(),
+			"msg":   msg,
+		}
+		for i := 0; i < len(l.fields); i += 2 {
+			entry[fmt.Sprint(l.fields[i])] = jsonValue(l.fields[i+1])
+		}
+		if err := json.NewEncoder(&b).Encode(entry); err != nil {
+			return
+		}
+	} else {
+		fmt.Fprintf(&b, "%s %-5s %s", ts.Format("2006/01/02 15:04:05"), strings.ToUpper(level.String()), msg)
+		for i := 0; i < len(l.fields); i += 2 {
+			fmt.Fprintf(&b, " %v=%v", l.fields[i], l.fields[i+1])
+		}
+		b.WriteByte('\n')
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.out.Write(b.Bytes())
+}
+
+// jsonValue makes values that would not encode usefully, like errors, into strings.
+func jsonValue(v interface{}) interface{} {
+	switch t := v.(type) {
+	case error:
+		return t.Error()
+	case fmt.Stringer:
+		return t.String()
+	}
+	return v
+}
+
+// Discard returns a Logger that drops every entry.
+func Discard() Logger {
+	return FromPrintf(func(string, ...interface{}) {})
+}
+
+// FromPrintf adapts a printf-style function, such as log.Printf, to a Logger.
+//
+// Levels other than info are written as a prefix on the message and fields
+// are appended as key=value pairs.
+func FromPrintf(fn func(string, ...interface{})) Logger {
+	return &printfLogger{fn: fn}
+}
+
+type printfLogger struct {
+	fn     func(string, ...interface{})
+	fields []interface{}
+}
+
+func (p *printfLogger) Debugf(format string, args ...interface{}) { p.log("debug: ", format, args) }
+func (p *printfLogger) Infof(format string, args ...interface{})  { p.log("", format, args) }
+func (p *printfLogger) Warnf(format string, args ...interface{})  { p.log("warning: ", format, args) }
+func (p *printfLogger) Errorf(format string, args ...interface{}) { p.log("error: ", format, args) }
+
+func (p *printfLogger) With(keyvals ...interface{}) Logger {
+	fields := make([]interface{}, 0, len(p.fields)+len(keyvals))
+	fields = append(fields, p.fields...)
+	fields = append(fields, keyvals...)
+	return &printfLogger{fn: p.fn, fields: fields}
+}
+
+func (p *printfLogger) log(prefix, format string, args []interface{}) {
+	var b bytes.Buffer
+	b.WriteString(prefix)
+	fmt.Fprintf(&b, format, args...)
+	for i := 0; i+1 < len(p.fields); i += 2 {
+		fmt.Fprintf(&b, " %v=%v", p.fields[i], p.fields[i+1])
+	}
+	p.fn("%s", b.String())
+}
+
+// Printf adapts a Logger to a printf-style function that logs at info level.
+//
+// It lets code written against func(string, ...interface{}) log through a
+// structured Logger.
+func Printf(l Logger) func(string, ...interface{}) {
+	return l.Infof
+}
@@ -0,0 +1,166 @@
+/*
+Copyright 2017 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package logging
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+)
+
+func fixedLogger(b *bytes.Buffer, format Format, level Level) Logger {
+	l := New(b, format, level).(*logger)
+	l.now = func() time.Time { return time.Date(2017, 5, 1, 12, 0, 0, 0, time.UTC) }
+	return l
+}
+
+func TestParseLevel(t *testing.T) {
+	tests := []struct {
+		name  string
+		level Level
+		err   bool
+	}{
+		{"debug", DebugLevel, false},
+		{"INFO", InfoLevel, false},
+		{"warn", WarnLevel, false},
+		{"error", ErrorLevel, false},
+		{"verbose", InfoLevel, true},
+	}
+	for _, tt := range tests {
+		level, err := ParseLevel(tt.name)
+		if (err != nil) != tt.err {
+			t.Errorf("%q: expected error %t, got %v", tt.name, tt.err, err)
+		}
+		if level != tt.level {
+			t.Errorf("%q: expected %s, got %s", tt.name, tt.level, level)
+		}
+	}
+}
+
+func TestParseFormat(t *testing.T) {
+	if f, err := ParseFormat("json"); err != nil || f != JSONFormat {
+		t.Errorf("expected json format, got %v, %v", f, err)
+	}
+	if f, err := ParseFormat("text"); err != nil || f != TextFormat {
+		t.Errorf("expected text format, got %v, %v", f, err)
+	}
+	if _, err := ParseFormat("xml"); err == nil {
+		t.Error("expected an error for an unknown format")
+	}
+}
+
+func TestTextLogger(t *testing.T) {
+	var b bytes.Buffer
+	l := fixedLogger(&b, TextFormat, InfoLevel).With("release", "angry-bird", "revision", 2)
+	l.Infof("Upgrade %s", "complete")
+
+	expect := "2017/05/01 12:00:00 INFO  Upgrade complete release=angry-bird revision=2\n"
+	if got := b.String(); got != expect {
+		t.Errorf("expected %q, got %q", expect, got)
+	}
+}
+
+func TestJSONLogger(t *testing.T) {
+	var b bytes.Buffer
+	l := fixedLogger(&b, JSONFormat, InfoLevel).With("release", "angry-bird")
+	l.With("revision", 2).Warnf("hook failed: %s", errors.New("boom"))
+
+	var entry map[string]interface{}
+	if err := json.Unmarshal(b.Bytes(), &entry); err != nil {
+		t.Fatalf("failed to decode %q: %s", b.String(), err)
+	}
+	expect := map[string]interface{}{
+		"time":     "2017-05-01T12:00:00Z",
+		"level":    "warn",
+		"msg":      "hook failed: boom",
+		"release":  "angry-bird",
+		"revision": float64(2),
+	}
+	for k, v := range expect {
+		if entry[k] != v {
+			t.Errorf("expected %s=%v, got %v", k, v, entry[k])
+		}
+	}
+	if len(entry) != len(expect) {
+		t.Errorf("unexpected fields in %v", entry)
+	}
+}
+
+func TestWithDoesNotModifyParent(t *testing.T) {
+	var b bytes.Buffer
+	parent := fixedLogger(&b, TextFormat, InfoLevel).With("release", "a")
+	parent.With("revision", 1)
+	parent.Infof("hello")
+
+	if strings.Contains(b.String(), "revision") {
+		t.Errorf("parent logger picked up child fields: %q", b.String())
+	}
+}
+
+func TestLevelFiltering(t *testing.T) {
+	var b bytes.Buffer
+	l := fixedLogger(&b, TextFormat, WarnLevel)
+	l.Debugf("debug")
+	l.Infof("info")
+	l.Warnf("warn")
+	l.Errorf("error")
+
+	lines := strings.Split(strings.TrimSpace(b.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("expected 2 entries, got %d: %q", len(lines), b.String())
+	}
+	if !strings.Contains(lines[0], "WARN") || !strings.Contains(lines[1], "ERROR") {
+		t.Errorf("unexpected entries: %q", lines)
+	}
+}
+
+func TestPrintf(t *testing.T) {
+	var b bytes.Buffer
+	l := fixedLogger(&b, TextFormat, WarnLevel)
+	Printf(l)("dropped below warn")
+	if b.Len() != 0 {
+		t.Errorf("expected Printf to log at info level, got %q", b.String())
+	}
+
+	l = fixedLogger(&b, TextFormat, InfoLevel)
+	Printf(l)("kept at %s", "info")
+	if !strings.Contains(b.String(), "INFO  kept at info") {
+		t.Errorf("unexpected output %q", b.String())
+	}
+}
+
+func TestFromPrintf(t *testing.T) {
+	var got []string
+	l := FromPrintf(func(format string, args ...interface{}) {
+		got = append(got, fmt.Sprintf(format, args...))
+	}).With("release", "100%")
+
+	l.Infof("rolled back to %d", 3)
+	l.Warnf("slow")
+
+	expect := []string{
+		"rolled back to 3 release=100%",
+		"warning: slow release=100%",
+	}
+	if strings.Join(got, "\n") != strings.Join(expect, "\n") {
+		t.Errorf("expected %q, got %q", expect, got)
+	}
+}
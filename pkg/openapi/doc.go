@@ -0,0 +1,28 @@
+/*
+Copyright 2017 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+/*Package openapi validates Kubernetes objects against the OpenAPI spec of a
+Kubernetes version, without a cluster.
+
+The spec is the swagger.json published with each Kubernetes release, e.g.
+
+	https://raw.githubusercontent.com/kubernetes/kubernetes/v1.8.0/api/openapi-spec/swagger.json
+
+Values of the wrong type, unknown fields and missing required fields are
+reported with the path of the field. Objects of API groups that the spec does
+not define, such as custom resources, are not validated.
+*/
+package openapi // import "k8s.io/helm/pkg/openapi"
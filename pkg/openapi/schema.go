@@ -0,0 +1,305 @@
+/*
+Copyright 2017 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package openapi // import "k8s.io/helm/pkg/openapi"
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"math"
+	"sort"
+	"strings"
+
+	"github.com/ghodss/yaml"
+)
+
+// quantityRef is the definition of resource quantities, which are strings
+// that may also be written as numbers.
+const quantityRef = "#/definitions/io.k8s.apimachinery.pkg.api.resource.Quantity"
+
+// Schema holds the object definitions of an OpenAPI spec.
+type Schema struct {
+	definitions map[string]*definition
+	// kinds are the definitions of top-level objects, by apiVersion and kind.
+	kinds map[string]*definition
+	// groups are the API groups the spec has kinds for.
+	groups map[string]bool
+}
+
+type spec struct {
+	Definitions map[string]*definition `json:"definitions"`
+}
+
+type definition struct {
+	Type                 string                 `json:"type"`
+	Format               string                 `json:"format"`
+	Ref                  string                 `json:"$ref"`
+	Properties           map[string]*definition `json:"properties"`
+	Required             []string               `json:"required"`
+	Items                *definition            `json:"items"`
+	AdditionalProperties *schemaOrBool          `json:"additionalProperties"`
+	GroupVersionKinds    []groupVersionKind     `json:"x-kubernetes-group-version-kind"`
+}
+
+type groupVersionKind struct {
+	Group   string `json:"group"`
+	Version string `json:"version"`
+	Kind    string `json:"kind"`
+}
+
+func (gvk groupVersionKind) apiVersion() string {
+	if gvk.Group == "" {
+		return gvk.Version
+	}
+	return gvk.Group + "/" + gvk.Version
+}
+
+// schemaOrBool is the value of additionalProperties: a definition of the
+// additional properties, or whether any are allowed.
+type schemaOrBool struct {
+	allows bool
+	schema *definition
+}
+
+func (s *schemaOrBool) UnmarshalJSON(b []byte) error {
+	if err := json.Unmarshal(b, &s.allows); err == nil {
+		return nil
+	}
+	s.allows = true
+	s.schema = &definition{}
+	return json.Unmarshal(b, s.schema)
+}
+
+// FieldError is a field of an object that does not match its definition.
+type FieldError struct {
+	// Path is the path of the field, e.g. "spec.template.spec.containers[0].ports[0].containerPort".
+	Path    string
+	Message string
+}
+
+func (e FieldError) Error() string {
+	if e.Path == "" {
+		return e.Message
+	}
+	return e.Path + ": " + e.Message
+}
+
+// Load parses an OpenAPI (Swagger 2.0) spec in JSON.
+func Load(data []byte) (*Schema, error) {
+	var sp spec
+	if err := json.Unmarshal(data, &sp); err != nil {
+		return nil, fmt.Errorf("parsing OpenAPI spec: %s", err)
+	}
+	s := &Schema{
+		definitions: sp.Definitions,
+		kinds:       map[string]*definition{},
+		groups:      map[string]bool{},
+	}
+	for _, d := range sp.Definitions {
+		for _, gvk := range d.GroupVersionKinds {
+			s.kinds[gvk.apiVersion()+"/"+gvk.Kind] = d
+			s.groups[gvk.Group] = true
+		}
+	}
+	if len(s.kinds) == 0 {
+		return nil, fmt.Errorf("OpenAPI spec defines no Kubernetes kinds")
+	}
+	return s, nil
+}
+
+// LoadFile parses the OpenAPI spec in the file at path.
+func LoadFile(path string) (*Schema, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return Load(data)
+}
+
+// Validate returns the fields of obj that do not match the definition of its
+// kind: values of the wrong type, unknown fields and missing required
+// fields. Objects of API groups that the spec does not know, such as custom
+// resources, are not validated.
+func (s *Schema) Validate(obj map[string]interface{}) []FieldError {
+	apiVersion, _ := obj["apiVersion"].(string)
+	kind, _ := obj["kind"].(string)
+	switch {
+	case apiVersion == "":
+		return []FieldError{{Path: "apiVersion", Message: "is required"}}
+	case kind == "":
+		return []FieldError{{Path: "kind", Message: "is required"}}
+	}
+
+	d, ok := s.kinds[apiVersion+"/"+kind]
+	if !ok {
+		group := ""
+		if i := strings.Index(apiVersion, "/"); i >= 0 {
+			group = apiVersion[:i]
+		}
+		if s.groups[group] {
+			return []FieldError{{Message: fmt.Sprintf("unknown kind %s in %s", kind, apiVersion)}}
+		}
+		return nil
+	}
+
+	var errs []FieldError
+	s.validate(obj, d, "", &errs)
+	sort.Slice(errs, func(i, j int) bool { return errs[i].Path < errs[j].Path })
+	return errs
+}
+
+// ValidateYAML validates the object in a YAML document, and returns an error
+// that lists the fields that do not match. Empty documents are valid.
+func (s *Schema) ValidateYAML(doc []byte) error {
+	var obj map[string]interface{}
+	if err := yaml.Unmarshal(doc, &obj); err != nil {
+		return err
+	}
+	if obj == nil {
+		return nil
+	}
+	errs := s.Validate(obj)
+	if len(errs) == 0 {
+		return nil
+	}
+	msgs := make([]string, len(errs))
+	for i, e := range errs {
+		msgs[i] = e.Error()
+	}
+	name := ""
+	if meta, ok := obj["metadata"].(map[string]interface{}); ok {
+		name, _ = meta["name"].(string)
+	}
+	return fmt.Errorf("%s %q does not match the OpenAPI schema: %s", obj["kind"], name, strings.Join(msgs, "; "))
+}
+
+func (s *Schema) validate(v interface{}, d *definition, path string, errs *[]FieldError) {
+	if v == nil || d == nil {
+		// Null is the same as leaving the field out.
+		return
+	}
+	quantity := false
+	for seen := 0; d.Ref != ""; seen++ {
+		quantity = d.Ref == quantityRef
+		next, ok := s.definitions[strings.TrimPrefix(d.Ref, "#/definitions/")]
+		if !ok || seen > len(s.definitions) {
+			return
+		}
+		d = next
+	}
+
+	fail := func(format string, args ...interface{}) {
+		*errs = append(*errs, FieldError{Path: path, Message: fmt.Sprintf(format, args...)})
+	}
+
+	switch d.Type {
+	case "string":
+		switch v.(type) {
+		case string:
+		case float64:
+			if !quantity && !(d.Format == "int-or-string" && isInteger(v)) {
+				fail("expected string, got %s", typeName(v))
+			}
+		default:
+			fail("expected string, got %s", typeName(v))
+		}
+	case "integer":
+		if !isInteger(v) {
+			fail("expected integer, got %s", typeName(v))
+		}
+	case "number":
+		if _, ok := v.(float64); !ok {
+			fail("expected number, got %s", typeName(v))
+		}
+	case "boolean":
+		if _, ok := v.(bool); !ok {
+			fail("expected boolean, got %s", typeName(v))
+		}
+	case "array":
+		list, ok := v.([]interface{})
+		if !ok {
+			fail("expected array, got %s", typeName(v))
+			return
+		}
+		for i, item := range list {
+			s.validate(item, d.Items, fmt.Sprintf("%s[%d]", path, i), errs)
+		}
+	case "object", "":
+		if d.Type == "" && len(d.Properties) == 0 && d.AdditionalProperties == nil {
+			// A definition without a type, such as a RawExtension, allows
+			// anything.
+			return
+		}
+		m, ok := v.(map[string]interface{})
+		if !ok {
+			fail("expected object, got %s", typeName(v))
+			return
+		}
+		s.validateObject(m, d, path, errs)
+	}
+}
+
+func (s *Schema) validateObject(m map[string]interface{}, d *definition, path string, errs *[]FieldError) {
+	for _, r := range d.Required {
+		if _, ok := m[r]; !ok {
+			*errs = append(*errs, FieldError{Path: join(path, r), Message: "is required"})
+		}
+	}
+	// Objects without any declared properties are free-form.
+	freeForm := len(d.Properties) == 0 && d.AdditionalProperties == nil
+	for k, child := range m {
+		if p, ok := d.Properties[k]; ok {
+			s.validate(child, p, join(path, k), errs)
+			continue
+		}
+		if ap := d.AdditionalProperties; ap != nil && ap.allows {
+			s.validate(child, ap.schema, join(path, k), errs)
+			continue
+		}
+		if !freeForm {
+			*errs = append(*errs, FieldError{Path: join(path, k), Message: "unknown field"})
+		}
+	}
+}
+
+func join(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
+
+func isInteger(v interface{}) bool {
+	f, ok := v.(float64)
+	return ok && f == math.Trunc(f)
+}
+
+func typeName(v interface{}) string {
+	switch v.(type) {
+	case string:
+		return "string"
+	case float64:
+		return "number"
+	case bool:
+		return "boolean"
+	case []interface{}:
+		return "array"
+	case map[string]interface{}:
+		return "object"
+	}
+	return fmt.Sprintf("%T", v)
+}
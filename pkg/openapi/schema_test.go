@@ -0,0 +1,162 @@
+/*
+Copyright 2017 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package openapi
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/ghodss/yaml"
+)
+
+const validDeployment = `apiVersion: apps/v1beta2
+kind: Deployment
+metadata:
+  name: web
+  creationTimestamp: null
+  labels:
+    app: web
+spec:
+  replicas: 3
+  template:
+    spec:
+      nodeSelector:
+        disk: ssd
+      containers:
+      - name: web
+        image: nginx
+        ports:
+        - containerPort: 80
+          targetPort: http
+        - containerPort: 443
+          targetPort: 8443
+        resources:
+          limits:
+            cpu: 1
+            memory: 128Mi
+`
+
+func loadSchema(t *testing.T) *Schema {
+	s, err := LoadFile("testdata/swagger.json")
+	if err != nil {
+		t.Fatalf("Failed to load the schema: %s", err)
+	}
+	return s
+}
+
+func parse(t *testing.T, doc string) map[string]interface{} {
+	var obj map[string]interface{}
+	if err := yaml.Unmarshal([]byte(doc), &obj); err != nil {
+		t.Fatal(err)
+	}
+	return obj
+}
+
+func TestValidateValid(t *testing.T) {
+	s := loadSchema(t)
+	if errs := s.Validate(parse(t, validDeployment)); len(errs) != 0 {
+		t.Errorf("Expected no errors, got %v", errs)
+	}
+}
+
+func TestValidateFieldErrors(t *testing.T) {
+	s := loadSchema(t)
+	doc := `apiVersion: apps/v1beta2
+kind: Deployment
+metadata:
+  name: web
+  labels:
+    replicas: 3
+spec:
+  replicas: "3"
+  paused: yes
+  revisionHistory: 2
+  template:
+    spec:
+      containers:
+      - image: nginx
+        ports:
+        - containerPort: 80.5
+          targetPort: 80.5
+        resources:
+          limits:
+            cpu: [1]
+`
+	expect := []FieldError{
+		{Path: "metadata.labels.replicas", Message: "expected string, got number"},
+		{Path: "spec.replicas", Message: "expected integer, got string"},
+		{Path: "spec.revisionHistory", Message: "unknown field"},
+		{Path: "spec.template.spec.containers[0].name", Message: "is required"},
+		{Path: "spec.template.spec.containers[0].ports[0].containerPort", Message: "expected integer, got number"},
+		{Path: "spec.template.spec.containers[0].ports[0].targetPort", Message: "expected string, got number"},
+		{Path: "spec.template.spec.containers[0].resources.limits.cpu", Message: "expected string, got array"},
+	}
+	if errs := s.Validate(parse(t, doc)); !reflect.DeepEqual(errs, expect) {
+		t.Errorf("Expected errors\n%v\ngot\n%v", expect, errs)
+	}
+}
+
+func TestValidateKinds(t *testing.T) {
+	s := loadSchema(t)
+	tests := []struct {
+		doc string
+		err string
+	}{
+		{"kind: Pod\nmetadata:\n  name: x\n", "apiVersion: is required"},
+		{"apiVersion: v1\nmetadata:\n  name: x\n", "kind: is required"},
+		{"apiVersion: v1\nkind: Pod\nspec: {}\n", "spec.containers: is required"},
+		{"apiVersion: v1\nkind: Podd\n", "unknown kind Podd in v1"},
+		{"apiVersion: apps/v1\nkind: Deployment\n", "unknown kind Deployment in apps/v1"},
+		// Custom resources are not validated.
+		{"apiVersion: example.com/v1\nkind: Widget\nspec:\n  anything: [1]\n", ""},
+	}
+	for _, tt := range tests {
+		errs := s.Validate(parse(t, tt.doc))
+		got := ""
+		if len(errs) > 0 {
+			got = errs[0].Error()
+		}
+		if got != tt.err {
+			t.Errorf("Expected %q for\n%s\ngot %v", tt.err, tt.doc, errs)
+		}
+	}
+}
+
+func TestValidateYAML(t *testing.T) {
+	s := loadSchema(t)
+	if err := s.ValidateYAML([]byte(validDeployment)); err != nil {
+		t.Errorf("Expected no error, got %s", err)
+	}
+	if err := s.ValidateYAML([]byte("# nothing rendered\n")); err != nil {
+		t.Errorf("Expected an empty document to be valid, got %s", err)
+	}
+	err := s.ValidateYAML([]byte("apiVersion: v1\nkind: Pod\nmetadata:\n  name: worker\nspec:\n  containers: {}\n"))
+	expect := `Pod "worker" does not match the OpenAPI schema: spec.containers: expected array, got object`
+	if err == nil || err.Error() != expect {
+		t.Errorf("Expected %q, got %v", expect, err)
+	}
+}
+
+func TestLoadInvalid(t *testing.T) {
+	if _, err := Load([]byte(`{"definitions": {}}`)); err == nil || !strings.Contains(err.Error(), "no Kubernetes kinds") {
+		t.Errorf("Expected an error for a spec without kinds, got %v", err)
+	}
+	if _, err := Load([]byte(`not json`)); err == nil {
+		t.Error("Expected an error for a spec that is not JSON")
+	}
+}
@@ -96,6 +96,9 @@ type Metadata struct {
 	// TillerVersion is a SemVer constraints on what version of Tiller is required.
 	// See SemVer ranges here: https://github.com/Masterminds/semver#basic-comparisons
 	TillerVersion string `protobuf:"bytes,15,opt,name=tillerVersion" json:"tillerVersion,omitempty"`
+	// TemplateValues renders the values of the chart that contain template
+	// expressions, against the other values, before its templates are rendered.
+	TemplateValues bool `protobuf:"varint,16,opt,name=templateValues" json:"templateValues,omitempty"`
 }
 
 func (m *Metadata) Reset()                    { *m = Metadata{} }
@@ -208,6 +211,13 @@ func (m *Metadata) GetTillerVersion() string {
 	return ""
 }
 
+func (m *Metadata) GetTemplateValues() bool {
+	if m != nil {
+		return m.TemplateValues
+	}
+	return false
+}
+
 func init() {
 	proto.RegisterType((*Maintainer)(nil), "hapi.chart.Maintainer")
 	proto.RegisterType((*Metadata)(nil), "hapi.chart.Metadata")
@@ -217,28 +227,28 @@ func init() {
 func init() { proto.RegisterFile("hapi/chart/metadata.proto", fileDescriptor2) }
 
 var fileDescriptor2 = []byte{
-	// 354 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x6c, 0x92, 0x5f, 0x4b, 0xe3, 0x40,
-	0x14, 0xc5, 0x37, 0x9b, 0xe6, 0xdf, 0xcd, 0x76, 0xb7, 0x0c, 0x4b, 0x99, 0x5d, 0x44, 0x42, 0xf1,
-	0x21, 0x4f, 0x29, 0x28, 0x88, 0xcf, 0x82, 0xf8, 0xa0, 0x6d, 0x25, 0xf8, 0x07, 0x7c, 0x1b, 0x93,
-	0x4b, 0x3b, 0xd8, 0xcc, 0x84, 0xc9, 0xa8, 0xf8, 0x7d, 0xfd, 0x20, 0x32, 0x93, 0xa4, 0x8d, 0xe0,
-	0xdb, 0x3d, 0xe7, 0xe4, 0xfe, 0xc2, 0xb9, 0x0c, 0xfc, 0xdb, 0xb0, 0x9a, 0xcf, 0x8b, 0x0d, 0x53,
-	0x7a, 0x5e, 0xa1, 0x66, 0x25, 0xd3, 0x2c, 0xab, 0x95, 0xd4, 0x92, 0x80, 0x89, 0x32, 0x1b, 0xcd,
-	0x4e, 0x01, 0x16, 0x8c, 0x0b, 0xcd, 0xb8, 0x40, 0x45, 0x08, 0x8c, 0x04, 0xab, 0x90, 0x3a, 0x89,
-	0x93, 0x46, 0xb9, 0x9d, 0xc9, 0x5f, 0xf0, 0xb0, 0x62, 0x7c, 0x4b, 0x7f, 0x5a, 0xb3, 0x15, 0xb3,
-	0x0f, 0x17, 0xc2, 0x45, 0x87, 0xfd, 0x76, 0x8d, 0xc0, 0x68, 0x23, 0x2b, 0xec, 0xb6, 0xec, 0x4c,
-	0x28, 0x04, 0x8d, 0x7c, 0x51, 0x05, 0x36, 0xd4, 0x4d, 0xdc, 0x34, 0xca, 0x7b, 0x69, 0x92, 0x57,
-	0x54, 0x0d, 0x97, 0x82, 0x8e, 0xec, 0x42, 0x2f, 0x49, 0x02, 0x71, 0x89, 0x4d, 0xa1, 0x78, 0xad,
-	0x4d, 0xea, 0xd9, 0x74, 0x68, 0x91, 0xff, 0x10, 0x3e, 0xe3, 0xfb, 0x9b, 0x54, 0x65, 0x43, 0x7d,
-	0x8b, 0xdd, 0x69, 0x72, 0x06, 0x71, 0xb5, 0xab, 0xd7, 0xd0, 0x20, 0x71, 0xd3, 0xf8, 0x78, 0x9a,
-	0xed, 0x0f, 0x90, 0xed, 0xdb, 0xe7, 0xc3, 0x4f, 0xc9, 0x14, 0x7c, 0x14, 0x6b, 0x2e, 0x90, 0x86,
-	0xf6, 0x97, 0x9d, 0x32, 0xbd, 0x78, 0x21, 0x05, 0x8d, 0xda, 0x5e, 0x66, 0x26, 0x87, 0x00, 0xac,
-	0xe6, 0xf7, 0x5d, 0x01, 0xb0, 0xc9, 0xc0, 0x21, 0x07, 0x10, 0x15, 0x52, 0x94, 0xdc, 0x36, 0x88,
-	0x6d, 0xbc, 0x37, 0x0c, 0x51, 0xb3, 0x75, 0x43, 0x7f, 0xb5, 0x44, 0x33, 0xb7, 0xc4, 0xba, 0x27,
-	0x8e, 0x7b, 0x62, 0xef, 0x98, 0xbc, 0xc4, 0x5a, 0x61, 0xc1, 0x34, 0x96, 0xf4, 0x77, 0xe2, 0xa4,
-	0x61, 0x3e, 0x70, 0xc8, 0x11, 0x8c, 0x35, 0xdf, 0x6e, 0x51, 0xf5, 0x88, 0x3f, 0x16, 0xf1, 0xd5,
-	0x9c, 0x25, 0xe0, 0x5f, 0xb4, 0xad, 0x62, 0x08, 0xee, 0x96, 0x57, 0xcb, 0xd5, 0xc3, 0x72, 0xf2,
-	0x83, 0x44, 0xe0, 0x5d, 0xae, 0x6e, 0x6f, 0xae, 0x27, 0xce, 0x79, 0xf0, 0xe8, 0xd9, 0x33, 0x3d,
-	0xf9, 0xf6, 0xe9, 0x9c, 0x7c, 0x06, 0x00, 0x00, 0xff, 0xff, 0xea, 0xb5, 0x4c, 0xbe, 0x57, 0x02,
-	0x00, 0x00,
+	// 368 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x6c, 0x92, 0x4f, 0xab, 0xd3, 0x40,
+	0x14, 0xc5, 0x8d, 0xcd, 0xdf, 0x1b, 0x5b, 0xcb, 0x20, 0x65, 0x14, 0x91, 0x50, 0x44, 0xb2, 0x4a,
+	0x41, 0x41, 0x5c, 0x0b, 0xe2, 0x42, 0xdb, 0x4a, 0xd0, 0x0a, 0xee, 0xc6, 0xe4, 0xd2, 0x0e, 0x26,
+	0x33, 0x61, 0x66, 0xaa, 0xf8, 0x1d, 0xde, 0x87, 0x7e, 0xcc, 0x24, 0x69, 0xf3, 0x1e, 0x6f, 0x77,
+	0xcf, 0x39, 0xb9, 0xbf, 0x70, 0x2e, 0x03, 0xcf, 0x4f, 0xac, 0xe3, 0x9b, 0xea, 0xc4, 0x94, 0xd9,
+	0xb4, 0x68, 0x58, 0xcd, 0x0c, 0x2b, 0x3a, 0x25, 0x8d, 0x24, 0x60, 0xa3, 0xc2, 0x45, 0xeb, 0xf7,
+	0x00, 0x5b, 0xc6, 0x85, 0x61, 0x5c, 0xa0, 0x22, 0x04, 0x7c, 0xc1, 0x5a, 0xa4, 0x5e, 0xe6, 0xe5,
+	0x49, 0xe9, 0x66, 0xf2, 0x0c, 0x02, 0x6c, 0x19, 0x6f, 0xe8, 0x63, 0x67, 0xf6, 0x62, 0x7d, 0xe3,
+	0x43, 0xbc, 0x1d, 0xb0, 0x0f, 0xae, 0x11, 0xf0, 0x4f, 0xb2, 0xc5, 0x61, 0xcb, 0xcd, 0x84, 0x42,
+	0xa4, 0xe5, 0x59, 0x55, 0xa8, 0xe9, 0x2c, 0x9b, 0xe5, 0x49, 0x39, 0x4a, 0x9b, 0xfc, 0x45, 0xa5,
+	0xb9, 0x14, 0xd4, 0x77, 0x0b, 0xa3, 0x24, 0x19, 0xa4, 0x35, 0xea, 0x4a, 0xf1, 0xce, 0xd8, 0x34,
+	0x70, 0xe9, 0xd4, 0x22, 0x2f, 0x20, 0xfe, 0x83, 0xff, 0xff, 0x49, 0x55, 0x6b, 0x1a, 0x3a, 0xec,
+	0x45, 0x93, 0x0f, 0x90, 0xb6, 0x97, 0x7a, 0x9a, 0x46, 0xd9, 0x2c, 0x4f, 0xdf, 0xae, 0x8a, 0xeb,
+	0x01, 0x8a, 0x6b, 0xfb, 0x72, 0xfa, 0x29, 0x59, 0x41, 0x88, 0xe2, 0xc8, 0x05, 0xd2, 0xd8, 0xfd,
+	0x72, 0x50, 0xb6, 0x17, 0xaf, 0xa4, 0xa0, 0x49, 0xdf, 0xcb, 0xce, 0xe4, 0x15, 0x00, 0xeb, 0xf8,
+	0x61, 0x28, 0x00, 0x2e, 0x99, 0x38, 0xe4, 0x25, 0x24, 0x95, 0x14, 0x35, 0x77, 0x0d, 0x52, 0x17,
+	0x5f, 0x0d, 0x4b, 0x34, 0xec, 0xa8, 0xe9, 0x93, 0x9e, 0x68, 0xe7, 0x9e, 0xd8, 0x8d, 0xc4, 0xf9,
+	0x48, 0x1c, 0x1d, 0x9b, 0xd7, 0xd8, 0x29, 0xac, 0x98, 0xc1, 0x9a, 0x2e, 0x32, 0x2f, 0x8f, 0xcb,
+	0x89, 0x43, 0x5e, 0xc3, 0xdc, 0xf0, 0xa6, 0x41, 0x35, 0x22, 0x9e, 0x3a, 0xc4, 0x5d, 0x93, 0xbc,
+	0x81, 0x85, 0xc1, 0xb6, 0x6b, 0x98, 0xc1, 0x03, 0x6b, 0xce, 0xa8, 0xe9, 0xd2, 0x91, 0xee, 0xb9,
+	0xeb, 0x0c, 0xc2, 0x4f, 0x7d, 0xfb, 0x14, 0xa2, 0x1f, 0xbb, 0x2f, 0xbb, 0xfd, 0xcf, 0xdd, 0xf2,
+	0x11, 0x49, 0x20, 0xf8, 0xbc, 0xff, 0xfe, 0xed, 0xeb, 0xd2, 0xfb, 0x18, 0xfd, 0x0a, 0xdc, 0x39,
+	0x7f, 0x87, 0xee, 0x89, 0xbd, 0xbb, 0x1d, 0x00, 0xb2, 0x45, 0x43, 0xc7, 0x7f, 0x02, 0x00, 0x00,
 }
@@ -9,14 +9,22 @@ It is generated from these files:
 	hapi/release/hook.proto
 	hapi/release/info.proto
 	hapi/release/release.proto
+	hapi/release/snapshot.proto
 	hapi/release/status.proto
 	hapi/release/test_run.proto
 	hapi/release/test_suite.proto
 
 It has these top-level messages:
 	Hook
+	HookExecution
+	HookAttempt
 	Info
+	Impersonation
+	ValuesMutation
+	ContainerInjection
+	ResourceStatus
 	Release
+	Snapshot
 	Status
 	TestRun
 	TestSuite
@@ -87,6 +95,38 @@ func (x Hook_Event) String() string {
 }
 func (Hook_Event) EnumDescriptor() ([]byte, []int) { return fileDescriptor0, []int{0, 0} }
 
+type HookExecution_Phase int32
+
+const (
+	HookExecution_UNKNOWN HookExecution_Phase = 0
+	// SUCCEEDED hooks were created and became ready.
+	HookExecution_SUCCEEDED HookExecution_Phase = 1
+	// FAILED hooks could not be created, or failed or timed out before
+	// becoming ready, in their last attempt.
+	HookExecution_FAILED HookExecution_Phase = 2
+	// SKIPPED hooks were not run at the request of the user, or because
+	// they are destructive.
+	HookExecution_SKIPPED HookExecution_Phase = 3
+)
+
+var HookExecution_Phase_name = map[int32]string{
+	0: "UNKNOWN",
+	1: "SUCCEEDED",
+	2: "FAILED",
+	3: "SKIPPED",
+}
+var HookExecution_Phase_value = map[string]int32{
+	"UNKNOWN":   0,
+	"SUCCEEDED": 1,
+	"FAILED":    2,
+	"SKIPPED":   3,
+}
+
+func (x HookExecution_Phase) String() string {
+	return proto.EnumName(HookExecution_Phase_name, int32(x))
+}
+func (HookExecution_Phase) EnumDescriptor() ([]byte, []int) { return fileDescriptor0, []int{1, 0} }
+
 // Hook defines a hook object.
 type Hook struct {
 	Name string `protobuf:"bytes,1,opt,name=name" json:"name,omitempty"`
@@ -102,6 +142,11 @@ type Hook struct {
 	LastRun *google_protobuf.Timestamp `protobuf:"bytes,6,opt,name=last_run,json=lastRun" json:"last_run,omitempty"`
 	// Weight indicates the sort order for execution among similar Hook type
 	Weight int32 `protobuf:"varint,7,opt,name=weight" json:"weight,omitempty"`
+	// LastSkipped indicates the date/time this was last skipped at the
+	// request of the user.
+	LastSkipped *google_protobuf.Timestamp `protobuf:"bytes,8,opt,name=last_skipped,json=lastSkipped" json:"last_skipped,omitempty"`
+	// LastPhase is the outcome of the last execution of the hook.
+	LastPhase HookExecution_Phase `protobuf:"varint,9,opt,name=last_phase,json=lastPhase,enum=hapi.release.HookExecution_Phase" json:"last_phase,omitempty"`
 }
 
 func (m *Hook) Reset()                    { *m = Hook{} }
@@ -158,37 +203,242 @@ func (m *Hook) GetWeight() int32 {
 	return 0
 }
 
+func (m *Hook) GetLastSkipped() *google_protobuf.Timestamp {
+	if m != nil {
+		return m.LastSkipped
+	}
+	return nil
+}
+
+func (m *Hook) GetLastPhase() HookExecution_Phase {
+	if m != nil {
+		return m.LastPhase
+	}
+	return HookExecution_UNKNOWN
+}
+
+// HookExecution records a run of a hook: when it ran, for which event and
+// how it ended.
+type HookExecution struct {
+	// Name is the name of the hook.
+	Name string `protobuf:"bytes,1,opt,name=name" json:"name,omitempty"`
+	// Kind is the Kubernetes kind of the hook.
+	Kind string `protobuf:"bytes,2,opt,name=kind" json:"kind,omitempty"`
+	// Path is the chart-relative path to the template of the hook.
+	Path string `protobuf:"bytes,3,opt,name=path" json:"path,omitempty"`
+	// Event is the event that the hook ran for.
+	Event Hook_Event `protobuf:"varint,4,opt,name=event,enum=hapi.release.Hook_Event" json:"event,omitempty"`
+	// Weight is the weight of the hook.
+	Weight int32 `protobuf:"varint,5,opt,name=weight" json:"weight,omitempty"`
+	// StartedAt is when the first attempt started.
+	StartedAt *google_protobuf.Timestamp `protobuf:"bytes,6,opt,name=started_at,json=startedAt" json:"started_at,omitempty"`
+	// CompletedAt is when the last attempt ended.
+	CompletedAt *google_protobuf.Timestamp `protobuf:"bytes,7,opt,name=completed_at,json=completedAt" json:"completed_at,omitempty"`
+	Phase       HookExecution_Phase        `protobuf:"varint,8,opt,name=phase,enum=hapi.release.HookExecution_Phase" json:"phase,omitempty"`
+	// Attempts is how many times the hook was run, including retries.
+	Attempts int32 `protobuf:"varint,9,opt,name=attempts" json:"attempts,omitempty"`
+	// Error is why the last attempt failed.
+	Error string `protobuf:"bytes,10,opt,name=error" json:"error,omitempty"`
+	// Log is the end of the logs of the pods of a failed hook, if Tiller is
+	// configured to collect them.
+	Log string `protobuf:"bytes,11,opt,name=log" json:"log,omitempty"`
+	// FailedAttempts are the attempts before the last one, which failed and
+	// were retried. Error and Log are those of the last attempt.
+	FailedAttempts []*HookAttempt `protobuf:"bytes,12,rep,name=failed_attempts,json=failedAttempts" json:"failed_attempts,omitempty"`
+}
+
+func (m *HookExecution) Reset()                    { *m = HookExecution{} }
+func (m *HookExecution) String() string            { return proto.CompactTextString(m) }
+func (*HookExecution) ProtoMessage()               {}
+func (*HookExecution) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{1} }
+
+func (m *HookExecution) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *HookExecution) GetKind() string {
+	if m != nil {
+		return m.Kind
+	}
+	return ""
+}
+
+func (m *HookExecution) GetPath() string {
+	if m != nil {
+		return m.Path
+	}
+	return ""
+}
+
+func (m *HookExecution) GetEvent() Hook_Event {
+	if m != nil {
+		return m.Event
+	}
+	return Hook_UNKNOWN
+}
+
+func (m *HookExecution) GetWeight() int32 {
+	if m != nil {
+		return m.Weight
+	}
+	return 0
+}
+
+func (m *HookExecution) GetStartedAt() *google_protobuf.Timestamp {
+	if m != nil {
+		return m.StartedAt
+	}
+	return nil
+}
+
+func (m *HookExecution) GetCompletedAt() *google_protobuf.Timestamp {
+	if m != nil {
+		return m.CompletedAt
+	}
+	return nil
+}
+
+func (m *HookExecution) GetPhase() HookExecution_Phase {
+	if m != nil {
+		return m.Phase
+	}
+	return HookExecution_UNKNOWN
+}
+
+func (m *HookExecution) GetAttempts() int32 {
+	if m != nil {
+		return m.Attempts
+	}
+	return 0
+}
+
+func (m *HookExecution) GetError() string {
+	if m != nil {
+		return m.Error
+	}
+	return ""
+}
+
+func (m *HookExecution) GetLog() string {
+	if m != nil {
+		return m.Log
+	}
+	return ""
+}
+
+func (m *HookExecution) GetFailedAttempts() []*HookAttempt {
+	if m != nil {
+		return m.FailedAttempts
+	}
+	return nil
+}
+
+// HookAttempt is a failed attempt to run a hook that was retried.
+type HookAttempt struct {
+	// Number counts the attempts of the hook from 1.
+	Number      int32                      `protobuf:"varint,1,opt,name=number" json:"number,omitempty"`
+	StartedAt   *google_protobuf.Timestamp `protobuf:"bytes,2,opt,name=started_at,json=startedAt" json:"started_at,omitempty"`
+	CompletedAt *google_protobuf.Timestamp `protobuf:"bytes,3,opt,name=completed_at,json=completedAt" json:"completed_at,omitempty"`
+	// Error is why the attempt failed.
+	Error string `protobuf:"bytes,4,opt,name=error" json:"error,omitempty"`
+	// Log is the end of the logs of the pods of the attempt, if Tiller is
+	// configured to collect them.
+	Log string `protobuf:"bytes,5,opt,name=log" json:"log,omitempty"`
+}
+
+func (m *HookAttempt) Reset()                    { *m = HookAttempt{} }
+func (m *HookAttempt) String() string            { return proto.CompactTextString(m) }
+func (*HookAttempt) ProtoMessage()               {}
+func (*HookAttempt) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{2} }
+
+func (m *HookAttempt) GetNumber() int32 {
+	if m != nil {
+		return m.Number
+	}
+	return 0
+}
+
+func (m *HookAttempt) GetStartedAt() *google_protobuf.Timestamp {
+	if m != nil {
+		return m.StartedAt
+	}
+	return nil
+}
+
+func (m *HookAttempt) GetCompletedAt() *google_protobuf.Timestamp {
+	if m != nil {
+		return m.CompletedAt
+	}
+	return nil
+}
+
+func (m *HookAttempt) GetError() string {
+	if m != nil {
+		return m.Error
+	}
+	return ""
+}
+
+func (m *HookAttempt) GetLog() string {
+	if m != nil {
+		return m.Log
+	}
+	return ""
+}
+
 func init() {
 	proto.RegisterType((*Hook)(nil), "hapi.release.Hook")
+	proto.RegisterType((*HookExecution)(nil), "hapi.release.HookExecution")
+	proto.RegisterType((*HookAttempt)(nil), "hapi.release.HookAttempt")
 	proto.RegisterEnum("hapi.release.Hook_Event", Hook_Event_name, Hook_Event_value)
+	proto.RegisterEnum("hapi.release.HookExecution_Phase", HookExecution_Phase_name, HookExecution_Phase_value)
 }
 
 func init() { proto.RegisterFile("hapi/release/hook.proto", fileDescriptor0) }
 
 var fileDescriptor0 = []byte{
-	// 371 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x64, 0x90, 0xdf, 0x8e, 0x93, 0x40,
-	0x14, 0x87, 0x65, 0x5b, 0xa0, 0x3d, 0x5d, 0xd7, 0x71, 0x62, 0x74, 0xd2, 0x1b, 0xc9, 0x5e, 0x71,
-	0x35, 0x98, 0x35, 0x3e, 0x00, 0xdb, 0x1d, 0x75, 0xb3, 0x84, 0x36, 0x03, 0xc4, 0xc4, 0x1b, 0xc2,
-	0xc6, 0x69, 0x21, 0x2d, 0x0c, 0x29, 0x53, 0x7d, 0x33, 0x9f, 0xc4, 0x07, 0x32, 0x33, 0xfc, 0x89,
-	0x89, 0x77, 0x67, 0xbe, 0xdf, 0xc7, 0x39, 0x9c, 0x03, 0xef, 0xca, 0xa2, 0xad, 0x82, 0xb3, 0x38,
-	0x89, 0xa2, 0x13, 0x41, 0x29, 0xe5, 0x91, 0xb6, 0x67, 0xa9, 0x24, 0xbe, 0xd6, 0x01, 0x1d, 0x82,
-	0xf5, 0xfb, 0x83, 0x94, 0x87, 0x93, 0x08, 0x4c, 0xf6, 0x7c, 0xd9, 0x07, 0xaa, 0xaa, 0x45, 0xa7,
-	0x8a, 0xba, 0xed, 0xf5, 0xdb, 0xdf, 0x33, 0x98, 0x7f, 0x95, 0xf2, 0x88, 0x31, 0xcc, 0x9b, 0xa2,
-	0x16, 0xc4, 0xf2, 0x2c, 0x7f, 0xc9, 0x4d, 0xad, 0xd9, 0xb1, 0x6a, 0x7e, 0x90, 0xab, 0x9e, 0xe9,
-	0x5a, 0xb3, 0xb6, 0x50, 0x25, 0x99, 0xf5, 0x4c, 0xd7, 0x78, 0x0d, 0x8b, 0xba, 0x68, 0xaa, 0xbd,
-	0xe8, 0x14, 0x99, 0x1b, 0x3e, 0xbd, 0xf1, 0x07, 0x70, 0xc4, 0x4f, 0xd1, 0xa8, 0x8e, 0xd8, 0xde,
-	0xcc, 0xbf, 0xb9, 0x23, 0xf4, 0xdf, 0x1f, 0xa4, 0x7a, 0x36, 0x65, 0x5a, 0xe0, 0x83, 0x87, 0x3f,
-	0xc1, 0xe2, 0x54, 0x74, 0x2a, 0x3f, 0x5f, 0x1a, 0xe2, 0x78, 0x96, 0xbf, 0xba, 0x5b, 0xd3, 0x7e,
-	0x0d, 0x3a, 0xae, 0x41, 0xd3, 0x71, 0x0d, 0xee, 0x6a, 0x97, 0x5f, 0x1a, 0xfc, 0x16, 0x9c, 0x5f,
-	0xa2, 0x3a, 0x94, 0x8a, 0xb8, 0x9e, 0xe5, 0xdb, 0x7c, 0x78, 0xdd, 0xfe, 0xb1, 0xc0, 0x36, 0x03,
-	0xf0, 0x0a, 0xdc, 0x2c, 0x7e, 0x8a, 0xb7, 0xdf, 0x62, 0xf4, 0x02, 0xbf, 0x82, 0xd5, 0x8e, 0xb3,
-	0xfc, 0x31, 0x4e, 0xd2, 0x30, 0x8a, 0x90, 0x85, 0x11, 0x5c, 0xef, 0xb6, 0x49, 0x3a, 0x91, 0x2b,
-	0x7c, 0x03, 0xa0, 0x95, 0x07, 0x16, 0xb1, 0x94, 0xa1, 0x99, 0xf9, 0x44, 0x1b, 0x03, 0x98, 0x8f,
-	0x3d, 0xb2, 0xdd, 0x17, 0x1e, 0x3e, 0x30, 0x64, 0x4f, 0x3d, 0x46, 0xe2, 0x18, 0xc2, 0x59, 0xce,
-	0xb7, 0x51, 0x74, 0x1f, 0x6e, 0x9e, 0x90, 0x8b, 0x5f, 0xc3, 0x4b, 0xe3, 0x4c, 0x68, 0x81, 0x09,
-	0xbc, 0xe1, 0x2c, 0x62, 0x61, 0xc2, 0xf2, 0x94, 0x25, 0x69, 0x9e, 0x64, 0x9b, 0x0d, 0x4b, 0x12,
-	0xb4, 0xfc, 0x2f, 0xf9, 0x1c, 0x3e, 0x46, 0x19, 0x67, 0x08, 0xee, 0x97, 0xdf, 0xdd, 0xe1, 0x86,
-	0xcf, 0x8e, 0x39, 0xcb, 0xc7, 0xbf, 0x01, 0x00, 0x00, 0xff, 0xff, 0x82, 0x3c, 0x7a, 0x0e, 0x14,
-	0x02, 0x00, 0x00,
+	// 629 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xa4, 0x93, 0xd1, 0x6e, 0xd3, 0x30,
+	0x14, 0x86, 0x49, 0x93, 0xb4, 0xcd, 0x49, 0xd7, 0x05, 0x6b, 0x02, 0xb3, 0x1b, 0x4a, 0xaf, 0x7a,
+	0x95, 0xa2, 0x22, 0x84, 0x90, 0x40, 0x22, 0x5b, 0x0d, 0x4c, 0x8b, 0xba, 0xca, 0x69, 0x85, 0xc4,
+	0x4d, 0x95, 0x6d, 0x5e, 0x1b, 0x35, 0x89, 0xa3, 0xc4, 0x05, 0x1e, 0x82, 0xf7, 0xe1, 0x11, 0x78,
+	0x01, 0x1e, 0x08, 0xd9, 0x49, 0xa3, 0x8d, 0x22, 0x36, 0xb4, 0x3b, 0x9f, 0xdf, 0xdf, 0x39, 0xf1,
+	0x39, 0xe7, 0x0f, 0x3c, 0x5e, 0x85, 0x59, 0x34, 0xcc, 0x59, 0xcc, 0xc2, 0x82, 0x0d, 0x57, 0x9c,
+	0xaf, 0xdd, 0x2c, 0xe7, 0x82, 0xa3, 0x8e, 0xbc, 0x70, 0xab, 0x8b, 0xc3, 0xa7, 0x4b, 0xce, 0x97,
+	0x31, 0x1b, 0xaa, 0xbb, 0xf3, 0xcd, 0xd5, 0x50, 0x44, 0x09, 0x2b, 0x44, 0x98, 0x64, 0x25, 0xde,
+	0xff, 0x61, 0x80, 0xf1, 0x91, 0xf3, 0x35, 0x42, 0x60, 0xa4, 0x61, 0xc2, 0xb0, 0xd6, 0xd3, 0x06,
+	0x16, 0x55, 0x67, 0xa9, 0xad, 0xa3, 0xf4, 0x12, 0x37, 0x4a, 0x4d, 0x9e, 0xa5, 0x96, 0x85, 0x62,
+	0x85, 0xf5, 0x52, 0x93, 0x67, 0x74, 0x08, 0xed, 0x24, 0x4c, 0xa3, 0x2b, 0x56, 0x08, 0x6c, 0x28,
+	0xbd, 0x8e, 0xd1, 0x73, 0x68, 0xb2, 0x2f, 0x2c, 0x15, 0x05, 0x36, 0x7b, 0xfa, 0xa0, 0x3b, 0xc2,
+	0xee, 0xf5, 0x07, 0xba, 0xf2, 0xdb, 0x2e, 0x91, 0x00, 0xad, 0x38, 0xf4, 0x12, 0xda, 0x71, 0x58,
+	0x88, 0x45, 0xbe, 0x49, 0x71, 0xb3, 0xa7, 0x0d, 0xec, 0xd1, 0xa1, 0x5b, 0xb6, 0xe1, 0x6e, 0xdb,
+	0x70, 0x67, 0xdb, 0x36, 0x68, 0x4b, 0xb2, 0x74, 0x93, 0xa2, 0x47, 0xd0, 0xfc, 0xca, 0xa2, 0xe5,
+	0x4a, 0xe0, 0x56, 0x4f, 0x1b, 0x98, 0xb4, 0x8a, 0xd0, 0x5b, 0xe8, 0xa8, 0x72, 0xc5, 0x3a, 0xca,
+	0x32, 0x76, 0x89, 0xdb, 0xb7, 0x96, 0xb4, 0x25, 0x1f, 0x94, 0x38, 0x7a, 0x07, 0xa0, 0xd2, 0xb3,
+	0x55, 0x58, 0x30, 0x6c, 0xf5, 0xb4, 0x41, 0x77, 0xf4, 0x6c, 0xb7, 0x07, 0xf2, 0x8d, 0x5d, 0x6c,
+	0x44, 0xc4, 0x53, 0x77, 0x2a, 0x41, 0x6a, 0xc9, 0x24, 0x75, 0xec, 0xff, 0xd2, 0xc0, 0x54, 0x1d,
+	0x22, 0x1b, 0x5a, 0xf3, 0xc9, 0xe9, 0xe4, 0xec, 0xd3, 0xc4, 0x79, 0x80, 0xf6, 0xc1, 0x9e, 0x52,
+	0xb2, 0x38, 0x99, 0x04, 0x33, 0xcf, 0xf7, 0x1d, 0x0d, 0x39, 0xd0, 0x99, 0x9e, 0x05, 0xb3, 0x5a,
+	0x69, 0xa0, 0x2e, 0x80, 0x44, 0xc6, 0xc4, 0x27, 0x33, 0xe2, 0xe8, 0x2a, 0x45, 0x12, 0x95, 0x60,
+	0x6c, 0x6b, 0xcc, 0xa7, 0x1f, 0xa8, 0x37, 0x26, 0x8e, 0x59, 0xd7, 0xd8, 0x2a, 0x4d, 0xa5, 0x50,
+	0xb2, 0xa0, 0x67, 0xbe, 0x7f, 0xe4, 0x1d, 0x9f, 0x3a, 0x2d, 0xf4, 0x10, 0xf6, 0x14, 0x53, 0x4b,
+	0x6d, 0x84, 0xe1, 0x80, 0x12, 0x9f, 0x78, 0x01, 0x59, 0xcc, 0x48, 0x30, 0x5b, 0x04, 0xf3, 0xe3,
+	0x63, 0x12, 0x04, 0x8e, 0xb5, 0x73, 0xf3, 0xde, 0x3b, 0xf1, 0xe7, 0x94, 0x38, 0xd0, 0xff, 0x6e,
+	0xc0, 0xde, 0x8d, 0xce, 0xef, 0x65, 0x21, 0x17, 0x4c, 0xb5, 0x7e, 0xe5, 0x9f, 0x7f, 0xb9, 0xa4,
+	0xc4, 0xae, 0x6d, 0xdb, 0xbc, 0xb1, 0xed, 0xd7, 0x00, 0x85, 0x08, 0x73, 0xc1, 0x2e, 0x17, 0xa1,
+	0xb8, 0x83, 0x7d, 0xac, 0x8a, 0xf6, 0x94, 0x51, 0x2e, 0x78, 0x92, 0xc5, 0xac, 0x4a, 0x6e, 0xdd,
+	0x6e, 0x94, 0x9a, 0xf7, 0x04, 0x7a, 0x05, 0x66, 0xe9, 0x91, 0xf6, 0x5d, 0x3d, 0x52, 0xf2, 0xf2,
+	0xef, 0x09, 0x85, 0x60, 0x49, 0x26, 0x0a, 0xe5, 0x2f, 0x93, 0xd6, 0x31, 0x3a, 0x00, 0x93, 0xe5,
+	0x39, 0xcf, 0x31, 0xa8, 0x59, 0x95, 0x01, 0x72, 0x40, 0x8f, 0xf9, 0x12, 0xdb, 0x4a, 0x93, 0x47,
+	0x74, 0x04, 0xfb, 0x57, 0x61, 0x14, 0xab, 0x87, 0x57, 0xa5, 0x3a, 0x3d, 0x7d, 0x60, 0x8f, 0x9e,
+	0xec, 0x3e, 0xc3, 0x2b, 0x09, 0xda, 0x2d, 0x33, 0xaa, 0xb0, 0xe8, 0xbf, 0x01, 0x53, 0xbd, 0xeb,
+	0xa6, 0x4d, 0xf7, 0xc0, 0x52, 0x6e, 0x20, 0x63, 0x32, 0x76, 0x34, 0x04, 0xd0, 0x94, 0x16, 0x20,
+	0x63, 0xa7, 0x21, 0xb9, 0xe0, 0xf4, 0x64, 0x3a, 0x25, 0x63, 0x47, 0xef, 0xff, 0xd4, 0xc0, 0xbe,
+	0x56, 0x5d, 0x2e, 0x28, 0xdd, 0x24, 0xe7, 0x2c, 0x57, 0x76, 0x30, 0x69, 0x15, 0xfd, 0xb1, 0xa0,
+	0xc6, 0x7d, 0x16, 0xa4, 0xff, 0xdf, 0x82, 0xea, 0x59, 0x1a, 0x7f, 0x99, 0xa5, 0x59, 0xcf, 0xf2,
+	0xc8, 0xfa, 0xdc, 0xaa, 0xc6, 0x75, 0xde, 0x54, 0x35, 0x5f, 0xfc, 0x1e, 0x00, 0xba, 0x62, 0x30,
+	0xad, 0x6e, 0x05, 0x00, 0x00,
 }
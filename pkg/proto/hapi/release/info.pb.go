@@ -14,6 +14,33 @@ var _ = proto.Marshal
 var _ = fmt.Errorf
 var _ = math.Inf
 
+type ResourceStatus_Code int32
+
+const (
+	// UNKNOWN indicates that the resource was not applied.
+	ResourceStatus_UNKNOWN ResourceStatus_Code = 0
+	// APPLIED indicates that the resource was created or updated.
+	ResourceStatus_APPLIED ResourceStatus_Code = 1
+	// FAILED indicates that the resource could not be created or updated.
+	ResourceStatus_FAILED ResourceStatus_Code = 2
+)
+
+var ResourceStatus_Code_name = map[int32]string{
+	0: "UNKNOWN",
+	1: "APPLIED",
+	2: "FAILED",
+}
+var ResourceStatus_Code_value = map[string]int32{
+	"UNKNOWN": 0,
+	"APPLIED": 1,
+	"FAILED":  2,
+}
+
+func (x ResourceStatus_Code) String() string {
+	return proto.EnumName(ResourceStatus_Code_name, int32(x))
+}
+func (ResourceStatus_Code) EnumDescriptor() ([]byte, []int) { return fileDescriptor1, []int{4, 0} }
+
 // Info describes release information.
 type Info struct {
 	Status        *Status                    `protobuf:"bytes,1,opt,name=status" json:"status,omitempty"`
@@ -23,6 +50,31 @@ type Info struct {
 	Deleted *google_protobuf.Timestamp `protobuf:"bytes,4,opt,name=deleted" json:"deleted,omitempty"`
 	// Description is human-friendly "log entry" about this release.
 	Description string `protobuf:"bytes,5,opt,name=Description" json:"Description,omitempty"`
+	// ResourceStatuses records, for each resource that was applied, whether
+	// applying it succeeded. It is only populated for releases that failed
+	// part way through being applied.
+	ResourceStatuses []*ResourceStatus `protobuf:"bytes,6,rep,name=resource_statuses,json=resourceStatuses" json:"resource_statuses,omitempty"`
+	// Annotations are metadata recorded with the revision, such as the commit
+	// or pipeline that deployed it. They do not affect rendering, and can be
+	// changed without creating a new revision.
+	Annotations map[string]string `protobuf:"bytes,7,rep,name=annotations" json:"annotations,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	// ValuesMutations records the values mutators that Tiller ran on the
+	// values of the revision before rendering it, in order.
+	ValuesMutations []*ValuesMutation `protobuf:"bytes,8,rep,name=values_mutations,json=valuesMutations" json:"values_mutations,omitempty"`
+	// Impersonation is the identity that Tiller acted as towards the
+	// Kubernetes API for the operation that recorded the revision, if it
+	// was asked to act as another identity.
+	Impersonation *Impersonation `protobuf:"bytes,9,opt,name=impersonation" json:"impersonation,omitempty"`
+	// Flags are the feature flags that the revision was rendered with, as
+	// .Release.Flags. A rollback renders the revision it restores with them
+	// again.
+	Flags map[string]bool `protobuf:"bytes,10,rep,name=flags" json:"flags,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"varint,2,opt,name=value"`
+	// ContainerInjections records the containers that Tiller's injection
+	// policies added to the workloads of the revision before it was applied.
+	ContainerInjections []*ContainerInjection `protobuf:"bytes,11,rep,name=container_injections,json=containerInjections" json:"container_injections,omitempty"`
+	// Failed records that the revision failed. Unlike the status, it is kept
+	// once a later revision supersedes the revision.
+	Failed bool `protobuf:"varint,12,opt,name=failed" json:"failed,omitempty"`
 }
 
 func (m *Info) Reset()                    { *m = Info{} }
@@ -65,27 +117,266 @@ func (m *Info) GetDescription() string {
 	return ""
 }
 
+func (m *Info) GetResourceStatuses() []*ResourceStatus {
+	if m != nil {
+		return m.ResourceStatuses
+	}
+	return nil
+}
+
+func (m *Info) GetAnnotations() map[string]string {
+	if m != nil {
+		return m.Annotations
+	}
+	return nil
+}
+
+func (m *Info) GetValuesMutations() []*ValuesMutation {
+	if m != nil {
+		return m.ValuesMutations
+	}
+	return nil
+}
+
+func (m *Info) GetImpersonation() *Impersonation {
+	if m != nil {
+		return m.Impersonation
+	}
+	return nil
+}
+
+func (m *Info) GetFlags() map[string]bool {
+	if m != nil {
+		return m.Flags
+	}
+	return nil
+}
+
+func (m *Info) GetContainerInjections() []*ContainerInjection {
+	if m != nil {
+		return m.ContainerInjections
+	}
+	return nil
+}
+
+func (m *Info) GetFailed() bool {
+	if m != nil {
+		return m.Failed
+	}
+	return false
+}
+
+// Impersonation is a Kubernetes identity that Tiller acts as, by setting the
+// impersonation headers of its requests. It is asserted by the client that
+// asks for it, and is not checked against who sent the request.
+type Impersonation struct {
+	// User is the user name, such as "jane@example.com", or
+	// "system:serviceaccount:NAMESPACE:NAME" for a service account.
+	User string `protobuf:"bytes,1,opt,name=user" json:"user,omitempty"`
+	// Groups are the groups of the user.
+	Groups []string `protobuf:"bytes,2,rep,name=groups" json:"groups,omitempty"`
+}
+
+func (m *Impersonation) Reset()                    { *m = Impersonation{} }
+func (m *Impersonation) String() string            { return proto.CompactTextString(m) }
+func (*Impersonation) ProtoMessage()               {}
+func (*Impersonation) Descriptor() ([]byte, []int) { return fileDescriptor1, []int{1} }
+
+func (m *Impersonation) GetUser() string {
+	if m != nil {
+		return m.User
+	}
+	return ""
+}
+
+func (m *Impersonation) GetGroups() []string {
+	if m != nil {
+		return m.Groups
+	}
+	return nil
+}
+
+// ValuesMutation describes what a values mutator changed.
+type ValuesMutation struct {
+	// Mutator is the name the mutator was registered with.
+	Mutator string `protobuf:"bytes,1,opt,name=mutator" json:"mutator,omitempty"`
+	// Changed lists the paths of the values it set, changed or removed, such
+	// as "network.ip", in order.
+	Changed []string `protobuf:"bytes,2,rep,name=changed" json:"changed,omitempty"`
+}
+
+func (m *ValuesMutation) Reset()                    { *m = ValuesMutation{} }
+func (m *ValuesMutation) String() string            { return proto.CompactTextString(m) }
+func (*ValuesMutation) ProtoMessage()               {}
+func (*ValuesMutation) Descriptor() ([]byte, []int) { return fileDescriptor1, []int{2} }
+
+func (m *ValuesMutation) GetMutator() string {
+	if m != nil {
+		return m.Mutator
+	}
+	return ""
+}
+
+func (m *ValuesMutation) GetChanged() []string {
+	if m != nil {
+		return m.Changed
+	}
+	return nil
+}
+
+// ContainerInjection describes the containers that an injection policy added
+// to the Pod template of a workload.
+type ContainerInjection struct {
+	// Policy is the name of the injection policy.
+	Policy string `protobuf:"bytes,1,opt,name=policy" json:"policy,omitempty"`
+	// Kind is the Kubernetes kind of the workload.
+	Kind string `protobuf:"bytes,2,opt,name=kind" json:"kind,omitempty"`
+	// Name is the name of the workload.
+	Name string `protobuf:"bytes,3,opt,name=name" json:"name,omitempty"`
+	// Containers are the names of the containers and init containers that
+	// were added, in order.
+	Containers []string `protobuf:"bytes,4,rep,name=containers" json:"containers,omitempty"`
+}
+
+func (m *ContainerInjection) Reset()                    { *m = ContainerInjection{} }
+func (m *ContainerInjection) String() string            { return proto.CompactTextString(m) }
+func (*ContainerInjection) ProtoMessage()               {}
+func (*ContainerInjection) Descriptor() ([]byte, []int) { return fileDescriptor1, []int{3} }
+
+func (m *ContainerInjection) GetPolicy() string {
+	if m != nil {
+		return m.Policy
+	}
+	return ""
+}
+
+func (m *ContainerInjection) GetKind() string {
+	if m != nil {
+		return m.Kind
+	}
+	return ""
+}
+
+func (m *ContainerInjection) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *ContainerInjection) GetContainers() []string {
+	if m != nil {
+		return m.Containers
+	}
+	return nil
+}
+
+// ResourceStatus describes the outcome of applying a single resource.
+type ResourceStatus struct {
+	// Kind is the Kubernetes kind of the resource.
+	Kind string `protobuf:"bytes,1,opt,name=kind" json:"kind,omitempty"`
+	// Name is the name of the resource.
+	Name string              `protobuf:"bytes,2,opt,name=name" json:"name,omitempty"`
+	Code ResourceStatus_Code `protobuf:"varint,3,opt,name=code,enum=hapi.release.ResourceStatus_Code" json:"code,omitempty"`
+	// Error is the reason the resource failed to apply.
+	Error string `protobuf:"bytes,4,opt,name=error" json:"error,omitempty"`
+	// Recreated is set if the resource had been deleted outside of Helm and
+	// was created again from the manifest.
+	Recreated bool `protobuf:"varint,5,opt,name=recreated" json:"recreated,omitempty"`
+}
+
+func (m *ResourceStatus) Reset()                    { *m = ResourceStatus{} }
+func (m *ResourceStatus) String() string            { return proto.CompactTextString(m) }
+func (*ResourceStatus) ProtoMessage()               {}
+func (*ResourceStatus) Descriptor() ([]byte, []int) { return fileDescriptor1, []int{4} }
+
+func (m *ResourceStatus) GetKind() string {
+	if m != nil {
+		return m.Kind
+	}
+	return ""
+}
+
+func (m *ResourceStatus) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *ResourceStatus) GetCode() ResourceStatus_Code {
+	if m != nil {
+		return m.Code
+	}
+	return ResourceStatus_UNKNOWN
+}
+
+func (m *ResourceStatus) GetError() string {
+	if m != nil {
+		return m.Error
+	}
+	return ""
+}
+
+func (m *ResourceStatus) GetRecreated() bool {
+	if m != nil {
+		return m.Recreated
+	}
+	return false
+}
+
 func init() {
 	proto.RegisterType((*Info)(nil), "hapi.release.Info")
+	proto.RegisterType((*Impersonation)(nil), "hapi.release.Impersonation")
+	proto.RegisterType((*ValuesMutation)(nil), "hapi.release.ValuesMutation")
+	proto.RegisterType((*ContainerInjection)(nil), "hapi.release.ContainerInjection")
+	proto.RegisterType((*ResourceStatus)(nil), "hapi.release.ResourceStatus")
+	proto.RegisterEnum("hapi.release.ResourceStatus_Code", ResourceStatus_Code_name, ResourceStatus_Code_value)
 }
 
 func init() { proto.RegisterFile("hapi/release/info.proto", fileDescriptor1) }
 
 var fileDescriptor1 = []byte{
-	// 235 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x84, 0x8f, 0x31, 0x4f, 0xc3, 0x30,
-	0x10, 0x85, 0x95, 0x52, 0x5a, 0xd5, 0x6d, 0x19, 0x2c, 0x24, 0x42, 0x16, 0x22, 0xa6, 0x0e, 0xc8,
-	0x91, 0x80, 0x1d, 0x81, 0xba, 0xb0, 0x06, 0x26, 0x16, 0xe4, 0xe2, 0x73, 0xb1, 0xe4, 0xe6, 0x2c,
-	0xfb, 0x3a, 0xf0, 0x2f, 0xf8, 0xc9, 0xa8, 0xb6, 0x83, 0xd2, 0xa9, 0xab, 0xbf, 0xf7, 0x3e, 0xbf,
-	0x63, 0x57, 0xdf, 0xd2, 0x99, 0xc6, 0x83, 0x05, 0x19, 0xa0, 0x31, 0x9d, 0x46, 0xe1, 0x3c, 0x12,
-	0xf2, 0xc5, 0x01, 0x88, 0x0c, 0xaa, 0x9b, 0x2d, 0xe2, 0xd6, 0x42, 0x13, 0xd9, 0x66, 0xaf, 0x1b,
-	0x32, 0x3b, 0x08, 0x24, 0x77, 0x2e, 0xc5, 0xab, 0xeb, 0x23, 0x4f, 0x20, 0x49, 0xfb, 0x90, 0xd0,
-	0xed, 0xef, 0x88, 0x8d, 0x5f, 0x3b, 0x8d, 0xfc, 0x8e, 0x4d, 0x12, 0x28, 0x8b, 0xba, 0x58, 0xcd,
-	0xef, 0x2f, 0xc5, 0xf0, 0x0f, 0xf1, 0x16, 0x59, 0x9b, 0x33, 0xfc, 0x99, 0x5d, 0x68, 0xe3, 0x03,
-	0x7d, 0x2a, 0x70, 0x16, 0x7f, 0x40, 0x95, 0xa3, 0xd8, 0xaa, 0x44, 0xda, 0x22, 0xfa, 0x2d, 0xe2,
-	0xbd, 0xdf, 0xd2, 0x2e, 0x63, 0x63, 0x9d, 0x0b, 0xfc, 0x89, 0x2d, 0xad, 0x1c, 0x1a, 0xce, 0x4e,
-	0x1a, 0x16, 0x87, 0xc2, 0xbf, 0xe0, 0x91, 0x4d, 0x15, 0x58, 0x20, 0x50, 0xe5, 0xf8, 0x64, 0xb5,
-	0x8f, 0xf2, 0x9a, 0xcd, 0xd7, 0x10, 0xbe, 0xbc, 0x71, 0x64, 0xb0, 0x2b, 0xcf, 0xeb, 0x62, 0x35,
-	0x6b, 0x87, 0x4f, 0x2f, 0xb3, 0x8f, 0x69, 0xbe, 0x7a, 0x33, 0x89, 0xa6, 0x87, 0xbf, 0x00, 0x00,
-	0x00, 0xff, 0xff, 0x1a, 0x52, 0x8f, 0x9c, 0x89, 0x01, 0x00, 0x00,
+	// 651 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x84, 0x54, 0x4d, 0x6f, 0xd3, 0x40,
+	0x10, 0xc5, 0xf9, 0xac, 0x27, 0x4d, 0x30, 0x4b, 0x05, 0x26, 0x14, 0x30, 0xe1, 0x92, 0x43, 0xe5,
+	0x48, 0x2d, 0x48, 0x15, 0x48, 0xa0, 0xd0, 0xa4, 0x28, 0xa2, 0x94, 0x6a, 0xcb, 0x87, 0xc4, 0x25,
+	0xda, 0xda, 0x93, 0xd4, 0xd4, 0xf1, 0x5a, 0xbb, 0x76, 0xa5, 0xfe, 0x45, 0x6e, 0xfc, 0x23, 0xb4,
+	0x6b, 0xbb, 0xb5, 0xdb, 0x8a, 0xde, 0xf6, 0xcd, 0xbc, 0x79, 0x3b, 0xf3, 0xc6, 0x6b, 0x78, 0x7c,
+	0xca, 0xe2, 0x60, 0x24, 0x30, 0x44, 0x26, 0x71, 0x14, 0x44, 0x0b, 0xee, 0xc6, 0x82, 0x27, 0x9c,
+	0xac, 0xab, 0x84, 0x9b, 0x27, 0xfa, 0x2f, 0x96, 0x9c, 0x2f, 0x43, 0x1c, 0xe9, 0xdc, 0x49, 0xba,
+	0x18, 0x25, 0xc1, 0x0a, 0x65, 0xc2, 0x56, 0x71, 0x46, 0xef, 0x3f, 0xa9, 0xe8, 0xc8, 0x84, 0x25,
+	0xa9, 0xcc, 0x52, 0x83, 0x3f, 0x2d, 0x68, 0xcc, 0xa2, 0x05, 0x27, 0x5b, 0xd0, 0xca, 0x12, 0xb6,
+	0xe1, 0x18, 0xc3, 0xce, 0xf6, 0x86, 0x5b, 0xbe, 0xc3, 0x3d, 0xd6, 0x39, 0x9a, 0x73, 0xc8, 0x18,
+	0x7a, 0x8b, 0x40, 0xc8, 0x64, 0xee, 0x63, 0x1c, 0xf2, 0x0b, 0xf4, 0xed, 0x9a, 0xae, 0xea, 0xbb,
+	0x59, 0x2f, 0x6e, 0xd1, 0x8b, 0xfb, 0xad, 0xe8, 0x85, 0x76, 0x75, 0xc5, 0x24, 0x2f, 0x20, 0x1f,
+	0xa0, 0x1b, 0xb2, 0xb2, 0x42, 0xfd, 0x4e, 0x85, 0xf5, 0x90, 0x95, 0x04, 0x5e, 0x43, 0xdb, 0xc7,
+	0x10, 0x13, 0xf4, 0xed, 0xc6, 0x9d, 0xa5, 0x05, 0x95, 0x38, 0xd0, 0x99, 0xa0, 0xf4, 0x44, 0x10,
+	0x27, 0x01, 0x8f, 0xec, 0xa6, 0x63, 0x0c, 0x4d, 0x5a, 0x0e, 0x91, 0x19, 0x3c, 0x10, 0x28, 0x79,
+	0x2a, 0x3c, 0x9c, 0x67, 0xe3, 0xa2, 0xb4, 0x5b, 0x4e, 0x7d, 0xd8, 0xd9, 0xde, 0xac, 0x9a, 0x42,
+	0x73, 0x5a, 0x6e, 0x8e, 0x25, 0x2a, 0x18, 0x25, 0x99, 0x42, 0x87, 0x45, 0x11, 0x4f, 0x98, 0x12,
+	0x96, 0x76, 0x5b, 0x8b, 0xbc, 0xaa, 0x8a, 0x28, 0xf7, 0xdd, 0xf1, 0x15, 0x6b, 0x1a, 0x25, 0xe2,
+	0x82, 0x96, 0xeb, 0xc8, 0x27, 0xb0, 0xce, 0x59, 0x98, 0xa2, 0x9c, 0xaf, 0xd2, 0x42, 0x6b, 0xed,
+	0xb6, 0x86, 0x7e, 0x68, 0xd6, 0x97, 0x9c, 0x44, 0xef, 0x9f, 0x57, 0xb0, 0x5a, 0x5b, 0x37, 0x58,
+	0xc5, 0x28, 0x24, 0x8f, 0x74, 0xc4, 0x36, 0xb5, 0x71, 0x4f, 0xaf, 0x75, 0x54, 0xa6, 0xd0, 0x6a,
+	0x05, 0xd9, 0x81, 0xe6, 0x22, 0x64, 0x4b, 0x69, 0x83, 0x6e, 0xe0, 0xd9, 0x2d, 0xc3, 0xec, 0xab,
+	0x7c, 0x36, 0x46, 0xc6, 0x25, 0xc7, 0xb0, 0xe1, 0xf1, 0x28, 0x61, 0x41, 0x84, 0x62, 0x1e, 0x44,
+	0xbf, 0xd1, 0xcb, 0x86, 0xe8, 0x68, 0x0d, 0xa7, 0xaa, 0xb1, 0x57, 0x30, 0x67, 0x05, 0x91, 0x3e,
+	0xf4, 0x6e, 0xc4, 0x24, 0x79, 0x04, 0xad, 0x05, 0x0b, 0x42, 0xf4, 0xed, 0x75, 0xc7, 0x18, 0xae,
+	0xd1, 0x1c, 0xf5, 0xdf, 0x83, 0x75, 0xdd, 0x4e, 0x62, 0x41, 0xfd, 0x0c, 0x2f, 0xf4, 0xa7, 0x6d,
+	0x52, 0x75, 0x24, 0x1b, 0xd0, 0xd4, 0xee, 0xe8, 0x0f, 0xd7, 0xa4, 0x19, 0x78, 0x5b, 0xdb, 0x35,
+	0xfa, 0xbb, 0x00, 0x57, 0x13, 0xdc, 0x55, 0xb9, 0x56, 0xaa, 0x1c, 0xbc, 0x83, 0x6e, 0xc5, 0x3b,
+	0x42, 0xa0, 0x91, 0x4a, 0x14, 0x79, 0xb5, 0x3e, 0xab, 0xb6, 0x97, 0x82, 0xa7, 0xb1, 0xb4, 0x6b,
+	0x4e, 0x7d, 0x68, 0xd2, 0x1c, 0x0d, 0x26, 0xd0, 0xab, 0xae, 0x8f, 0xd8, 0xd0, 0xd6, 0xfb, 0xe6,
+	0x85, 0x40, 0x01, 0x55, 0xc6, 0x3b, 0x65, 0xd1, 0x12, 0xfd, 0x5c, 0xa4, 0x80, 0x83, 0x04, 0xc8,
+	0x4d, 0xff, 0xd4, 0x9d, 0x31, 0x0f, 0x03, 0xaf, 0x98, 0x23, 0x47, 0xaa, 0xbf, 0xb3, 0x20, 0xf2,
+	0x73, 0x0f, 0xf4, 0x59, 0xc5, 0x22, 0xb6, 0x42, 0xfd, 0x1c, 0x4d, 0xaa, 0xcf, 0xe4, 0x39, 0xc0,
+	0xe5, 0x06, 0xa4, 0xdd, 0xd0, 0x57, 0x96, 0x22, 0x83, 0xbf, 0x06, 0xf4, 0xaa, 0x8f, 0xe1, 0x52,
+	0xda, 0xb8, 0x45, 0xba, 0x56, 0x92, 0x7e, 0x03, 0x0d, 0x8f, 0xfb, 0xd9, 0x75, 0xbd, 0xed, 0x97,
+	0xff, 0x7b, 0x60, 0xee, 0x1e, 0xf7, 0x91, 0x6a, 0xba, 0x5a, 0x02, 0x0a, 0xc1, 0x85, 0x7e, 0xfa,
+	0x26, 0xcd, 0x00, 0xd9, 0x04, 0x53, 0xa0, 0x27, 0x90, 0xa9, 0x9f, 0x42, 0x53, 0xaf, 0xe7, 0x2a,
+	0x30, 0xd8, 0x82, 0x86, 0x52, 0x20, 0x1d, 0x68, 0x7f, 0x3f, 0xfc, 0x7c, 0xf8, 0xf5, 0xe7, 0xa1,
+	0x75, 0x4f, 0x81, 0xf1, 0xd1, 0xd1, 0xc1, 0x6c, 0x3a, 0xb1, 0x0c, 0x02, 0xd0, 0xda, 0x1f, 0xcf,
+	0x0e, 0xa6, 0x13, 0xab, 0xf6, 0xd1, 0xfc, 0xd5, 0xce, 0xdb, 0x38, 0x69, 0xe9, 0x1f, 0xca, 0xce,
+	0xbf, 0x01, 0x00, 0x21, 0xfe, 0xac, 0x49, 0x90, 0x05, 0x00, 0x00,
 }
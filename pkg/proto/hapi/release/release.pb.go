@@ -35,6 +35,39 @@ type Release struct {
 	Version int32 `protobuf:"varint,7,opt,name=version" json:"version,omitempty"`
 	// Namespace is the kubernetes namespace of the release.
 	Namespace string `protobuf:"bytes,8,opt,name=namespace" json:"namespace,omitempty"`
+	// ComputedValues are the values the release was rendered with: the
+	// chart's default values coalesced with Config. They are only stored if
+	// Tiller is configured to keep them.
+	ComputedValues *hapi_chart.Config `protobuf:"bytes,9,opt,name=computed_values,json=computedValues" json:"computed_values,omitempty"`
+	// GeneratedValues are the values that templates generated with functions
+	// such as randAlphaNumOnce, by key. Upgrades reuse them instead of
+	// generating new ones.
+	GeneratedValues map[string]string `protobuf:"bytes,10,rep,name=generated_values,json=generatedValues" json:"generated_values,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	// Namespaces lists every namespace that the resources and hooks of the
+	// release are in: Namespace first, then the namespaces that resources
+	// declare themselves, in alphabetical order.
+	Namespaces []string `protobuf:"bytes,11,rep,name=namespaces" json:"namespaces,omitempty"`
+	// Cluster names the cluster, out of those Tiller is configured with, that
+	// the release is installed in. It is empty for Tiller's own cluster.
+	Cluster string `protobuf:"bytes,12,opt,name=cluster" json:"cluster,omitempty"`
+	// System marks a release as a system release, such as platform
+	// infrastructure, which listings leave out unless asked for. It is set at
+	// install and kept by every later revision.
+	System bool `protobuf:"varint,13,opt,name=system" json:"system,omitempty"`
+	// Snapshot, if set, is the live state of the resources of the release
+	// when it was this revision, as taken by SnapshotRelease.
+	Snapshot *Snapshot `protobuf:"bytes,14,opt,name=snapshot" json:"snapshot,omitempty"`
+	// HookExecutions lists, in the order they ran, the hooks that the
+	// operations on this revision ran or skipped, and how they ended.
+	HookExecutions []*HookExecution `protobuf:"bytes,15,rep,name=hook_executions,json=hookExecutions" json:"hook_executions,omitempty"`
+	// Profile names the values profile of the chart that the release was
+	// rendered with. Its values are merged under Config when rendering, not
+	// stored in it, so upgrades that keep the values of the release apply the
+	// profile of the new chart.
+	Profile string `protobuf:"bytes,16,opt,name=profile" json:"profile,omitempty"`
+	// AllowMissingProfile records that the chart need not have a values file
+	// for Profile.
+	AllowMissingProfile bool `protobuf:"varint,17,opt,name=allow_missing_profile,json=allowMissingProfile" json:"allow_missing_profile,omitempty"`
 }
 
 func (m *Release) Reset()                    { *m = Release{} }
@@ -98,6 +131,69 @@ func (m *Release) GetNamespace() string {
 	return ""
 }
 
+func (m *Release) GetComputedValues() *hapi_chart.Config {
+	if m != nil {
+		return m.ComputedValues
+	}
+	return nil
+}
+
+func (m *Release) GetGeneratedValues() map[string]string {
+	if m != nil {
+		return m.GeneratedValues
+	}
+	return nil
+}
+
+func (m *Release) GetNamespaces() []string {
+	if m != nil {
+		return m.Namespaces
+	}
+	return nil
+}
+
+func (m *Release) GetCluster() string {
+	if m != nil {
+		return m.Cluster
+	}
+	return ""
+}
+
+func (m *Release) GetSystem() bool {
+	if m != nil {
+		return m.System
+	}
+	return false
+}
+
+func (m *Release) GetSnapshot() *Snapshot {
+	if m != nil {
+		return m.Snapshot
+	}
+	return nil
+}
+
+func (m *Release) GetHookExecutions() []*HookExecution {
+	if m != nil {
+		return m.HookExecutions
+	}
+	return nil
+}
+
+func (m *Release) GetProfile() string {
+	if m != nil {
+		return m.Profile
+	}
+	return ""
+}
+
+func (m *Release) GetAllowMissingProfile() bool {
+	if m != nil {
+		return m.AllowMissingProfile
+	}
+	return false
+}
+
 func init() {
 	proto.RegisterType((*Release)(nil), "hapi.release.Release")
 }
@@ -105,21 +201,36 @@ func init() {
 func init() { proto.RegisterFile("hapi/release/release.proto", fileDescriptor2) }
 
 var fileDescriptor2 = []byte{
-	// 256 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x64, 0x90, 0xbf, 0x4e, 0xc3, 0x40,
-	0x0c, 0xc6, 0x95, 0x36, 0x7f, 0x1a, 0xc3, 0x82, 0x07, 0xb0, 0x22, 0x86, 0x88, 0x01, 0x22, 0x86,
-	0x54, 0x82, 0x37, 0x80, 0x05, 0xd6, 0x1b, 0xd9, 0x8e, 0xe8, 0x42, 0x4e, 0xa5, 0xe7, 0x28, 0x17,
-	0xf1, 0x2c, 0x3c, 0x2e, 0xba, 0x3f, 0x85, 0x94, 0x2e, 0x4e, 0xec, 0xdf, 0xa7, 0xcf, 0xdf, 0x19,
-	0xaa, 0x41, 0x8e, 0x7a, 0x3b, 0xa9, 0x4f, 0x25, 0xad, 0x3a, 0x7c, 0xdb, 0x71, 0xe2, 0x99, 0xf1,
-	0xdc, 0xb1, 0x36, 0xce, 0xaa, 0xab, 0x23, 0xe5, 0xc0, 0xbc, 0x0b, 0xb2, 0x7f, 0x40, 0x9b, 0x9e,
-	0x8f, 0x40, 0x37, 0xc8, 0x69, 0xde, 0x76, 0x6c, 0x7a, 0xfd, 0x11, 0xc1, 0xe5, 0x12, 0xb8, 0x1a,
-	0xe6, 0x37, 0xdf, 0x2b, 0x28, 0x44, 0xf0, 0x41, 0x84, 0xd4, 0xc8, 0xbd, 0xa2, 0xa4, 0x4e, 0x9a,
-	0x52, 0xf8, 0x7f, 0xbc, 0x85, 0xd4, 0xd9, 0xd3, 0xaa, 0x4e, 0x9a, 0xb3, 0x07, 0x6c, 0x97, 0xf9,
-	0xda, 0x57, 0xd3, 0xb3, 0xf0, 0x1c, 0xef, 0x20, 0xf3, 0xb6, 0xb4, 0xf6, 0xc2, 0x8b, 0x20, 0x0c,
-	0x9b, 0x9e, 0x5d, 0x15, 0x81, 0xe3, 0x3d, 0xe4, 0x21, 0x18, 0xa5, 0x4b, 0xcb, 0xa8, 0xf4, 0x44,
-	0x44, 0x05, 0x56, 0xb0, 0xd9, 0x4b, 0xa3, 0x7b, 0x65, 0x67, 0xca, 0x7c, 0xa8, 0xdf, 0x1e, 0x1b,
-	0xc8, 0xdc, 0x41, 0x2c, 0xe5, 0xf5, 0xfa, 0x34, 0xd9, 0x0b, 0xf3, 0x4e, 0x04, 0x01, 0x12, 0x14,
-	0x5f, 0x6a, 0xb2, 0x9a, 0x0d, 0x15, 0x75, 0xd2, 0x64, 0xe2, 0xd0, 0xe2, 0x35, 0x94, 0xee, 0x91,
-	0x76, 0x94, 0x9d, 0xa2, 0x8d, 0x5f, 0xf0, 0x37, 0x78, 0x2a, 0xdf, 0x8a, 0x68, 0xf7, 0x9e, 0xfb,
-	0x63, 0x3d, 0xfe, 0x04, 0x00, 0x00, 0xff, 0xff, 0xc8, 0x8f, 0xec, 0x97, 0xbb, 0x01, 0x00, 0x00,
+	// 481 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x74, 0x53, 0x4d, 0x6f, 0xd3, 0x40,
+	0x10, 0x95, 0x9b, 0xda, 0x89, 0xa7, 0x25, 0x49, 0x87, 0x12, 0x56, 0x29, 0x42, 0x16, 0x07, 0xb0,
+	0x7a, 0x70, 0xa5, 0x70, 0x41, 0x70, 0x2b, 0x54, 0xc0, 0x01, 0x09, 0x2d, 0x82, 0x03, 0x97, 0x68,
+	0x31, 0xeb, 0xd8, 0x8a, 0xbd, 0x6b, 0x79, 0x9d, 0x42, 0xfe, 0x02, 0xbf, 0x1a, 0xed, 0x87, 0x83,
+	0x4d, 0xe1, 0xb2, 0xde, 0x37, 0xef, 0xed, 0xec, 0x9b, 0xd9, 0x31, 0x2c, 0x73, 0x56, 0x17, 0x57,
+	0x0d, 0x2f, 0x39, 0x53, 0xbc, 0xfb, 0x26, 0x75, 0x23, 0x5b, 0x89, 0xa7, 0x9a, 0x4b, 0x5c, 0x6c,
+	0xf9, 0x70, 0xa0, 0xcc, 0xa5, 0xdc, 0x5a, 0xd9, 0x5f, 0x44, 0x21, 0x32, 0xe9, 0x88, 0x8b, 0x01,
+	0xa1, 0x04, 0xab, 0x55, 0x2e, 0xdb, 0xc1, 0xa9, 0x34, 0x67, 0x4d, 0x7b, 0x95, 0x4a, 0x91, 0x15,
+	0x1b, 0x47, 0x2c, 0xfa, 0x84, 0x5e, 0x6d, 0xfc, 0xc9, 0xaf, 0x00, 0xc6, 0xd4, 0xe6, 0x42, 0x84,
+	0x63, 0xc1, 0x2a, 0x4e, 0xbc, 0xc8, 0x8b, 0x43, 0x6a, 0xf6, 0xf8, 0x14, 0x8e, 0xf5, 0xdd, 0xe4,
+	0x28, 0xf2, 0xe2, 0x93, 0x15, 0x26, 0x7d, 0xf3, 0xc9, 0x7b, 0x91, 0x49, 0x6a, 0x78, 0x7c, 0x06,
+	0xbe, 0x49, 0x4b, 0x46, 0x46, 0x78, 0x66, 0x85, 0xf6, 0xa6, 0xd7, 0x7a, 0xa5, 0x96, 0xc7, 0x4b,
+	0x08, 0xac, 0x31, 0x72, 0xdc, 0x4f, 0xe9, 0x94, 0x86, 0xa1, 0x4e, 0x81, 0x4b, 0x98, 0x54, 0x4c,
+	0x14, 0x19, 0x57, 0x2d, 0xf1, 0x8d, 0xa9, 0x03, 0xc6, 0x18, 0x7c, 0xdd, 0x2d, 0x45, 0x82, 0x68,
+	0x74, 0xd7, 0xd9, 0x3b, 0x29, 0xb7, 0xd4, 0x0a, 0x90, 0xc0, 0xf8, 0x96, 0x37, 0xaa, 0x90, 0x82,
+	0x8c, 0x23, 0x2f, 0xf6, 0x69, 0x07, 0xf1, 0x11, 0x84, 0xba, 0x48, 0x55, 0xb3, 0x94, 0x93, 0x89,
+	0xb9, 0xe0, 0x4f, 0x00, 0x5f, 0xc1, 0x2c, 0x95, 0x55, 0xbd, 0x6b, 0xf9, 0xf7, 0xf5, 0x2d, 0x2b,
+	0x77, 0x5c, 0x91, 0xf0, 0xbf, 0x96, 0xa7, 0x9d, 0xf4, 0x8b, 0x51, 0xe2, 0x67, 0x98, 0x6f, 0xb8,
+	0xe0, 0x0d, 0xeb, 0x9d, 0x06, 0xe3, 0xf4, 0x72, 0xe8, 0xd4, 0x35, 0x3f, 0x79, 0xdb, 0xa9, 0x6d,
+	0x82, 0x1b, 0xd1, 0x36, 0x7b, 0x3a, 0xdb, 0x0c, 0xa3, 0xf8, 0x18, 0xe0, 0x60, 0x50, 0x91, 0x93,
+	0x68, 0x14, 0x87, 0xb4, 0x17, 0xd1, 0xb5, 0xa6, 0xe5, 0x4e, 0xb5, 0xbc, 0x21, 0xa7, 0xa6, 0x9e,
+	0x0e, 0xe2, 0x02, 0x02, 0xb5, 0x57, 0x2d, 0xaf, 0xc8, 0xbd, 0xc8, 0x8b, 0x27, 0xd4, 0x21, 0x5c,
+	0xc1, 0xa4, 0x9b, 0x21, 0x32, 0x35, 0xe5, 0x2d, 0x86, 0x06, 0x3f, 0x39, 0x96, 0x1e, 0x74, 0xf8,
+	0x06, 0x66, 0xba, 0xb5, 0x6b, 0xfe, 0x93, 0xa7, 0xbb, 0xb6, 0x90, 0x42, 0x91, 0x99, 0xa9, 0xed,
+	0xe2, 0xee, 0x2b, 0xdc, 0x74, 0x1a, 0x3a, 0xcd, 0xfb, 0xd0, 0x78, 0xad, 0x1b, 0x99, 0x15, 0x25,
+	0x27, 0x73, 0xeb, 0xd5, 0x41, 0x5c, 0xc1, 0x03, 0x56, 0x96, 0xf2, 0xc7, 0xba, 0x2a, 0x94, 0x2a,
+	0xc4, 0x66, 0xdd, 0xe9, 0xce, 0x8c, 0xf5, 0xfb, 0x86, 0xfc, 0x60, 0xb9, 0x8f, 0x96, 0x5a, 0x5e,
+	0xc3, 0xf9, 0xbf, 0x5a, 0x88, 0x73, 0x18, 0x6d, 0xf9, 0xde, 0xcd, 0xb4, 0xde, 0xe2, 0x39, 0xf8,
+	0xe6, 0x41, 0xcc, 0x4c, 0x87, 0xd4, 0x82, 0x97, 0x47, 0x2f, 0xbc, 0xeb, 0xf0, 0xeb, 0xd8, 0x59,
+	0xff, 0x16, 0x98, 0xdf, 0xe3, 0xf9, 0xef, 0x01, 0x00, 0x25, 0xa9, 0xed, 0x16, 0xca, 0x03, 0x00,
+	0x00,
 }
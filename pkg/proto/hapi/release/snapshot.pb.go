@@ -0,0 +1,78 @@
+// Code generated by protoc-gen-go.
+// source: hapi/release/snapshot.proto
+// DO NOT EDIT!
+
+package release
+
+import proto "github.com/golang/protobuf/proto"
+import fmt "fmt"
+import math "math"
+import google_protobuf "github.com/golang/protobuf/ptypes/timestamp"
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ = proto.Marshal
+var _ = fmt.Errorf
+var _ = math.Inf
+
+// Snapshot is the live state of the resources of a release, as it was when
+// the snapshot was taken. A rollback can apply it instead of the manifest of
+// its revision, to restore what was running, including changes made outside
+// of Helm.
+type Snapshot struct {
+	// Taken is when the snapshot was taken.
+	Taken *google_protobuf.Timestamp `protobuf:"bytes,1,opt,name=taken" json:"taken,omitempty"`
+	// Manifest holds the live objects, one YAML document each, in the order
+	// of the manifest of the release. The fields that the API server manages
+	// are left out; see kube.StripServerFields.
+	Manifest string `protobuf:"bytes,2,opt,name=manifest" json:"manifest,omitempty"`
+	// Missing lists the resources of the manifest that did not exist when
+	// the snapshot was taken, as "Kind/name".
+	Missing []string `protobuf:"bytes,3,rep,name=missing" json:"missing,omitempty"`
+}
+
+func (m *Snapshot) Reset()                    { *m = Snapshot{} }
+func (m *Snapshot) String() string            { return proto.CompactTextString(m) }
+func (*Snapshot) ProtoMessage()               {}
+func (*Snapshot) Descriptor() ([]byte, []int) { return fileDescriptor3, []int{0} }
+
+func (m *Snapshot) GetTaken() *google_protobuf.Timestamp {
+	if m != nil {
+		return m.Taken
+	}
+	return nil
+}
+
+func (m *Snapshot) GetManifest() string {
+	if m != nil {
+		return m.Manifest
+	}
+	return ""
+}
+
+func (m *Snapshot) GetMissing() []string {
+	if m != nil {
+		return m.Missing
+	}
+	return nil
+}
+
+func init() {
+	proto.RegisterType((*Snapshot)(nil), "hapi.release.Snapshot")
+}
+
+func init() { proto.RegisterFile("hapi/release/snapshot.proto", fileDescriptor3) }
+
+var fileDescriptor3 = []byte{
+	// 168 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x3c, 0x8e, 0xbd, 0x0e, 0x82, 0x30,
+	0x14, 0x85, 0x83, 0x44, 0x81, 0xea, 0xd4, 0xa9, 0xc1, 0x41, 0xe2, 0xc4, 0xd4, 0x1a, 0x7d, 0x03,
+	0x1f, 0x01, 0x9d, 0xdc, 0x4a, 0x72, 0x81, 0x46, 0xfa, 0x13, 0xee, 0xf5, 0xfd, 0x8d, 0x40, 0x1d,
+	0x4f, 0xbe, 0xf3, 0x9d, 0x1c, 0x76, 0x1c, 0x74, 0x30, 0x6a, 0x82, 0x11, 0x34, 0x82, 0x42, 0xa7,
+	0x03, 0x0e, 0x9e, 0x64, 0x98, 0x3c, 0x79, 0x7e, 0xf8, 0x41, 0xb9, 0xc2, 0xf2, 0xd4, 0x7b, 0xdf,
+	0x8f, 0xa0, 0x66, 0xd6, 0x7e, 0x3a, 0x45, 0xc6, 0x02, 0x92, 0xb6, 0x61, 0xa9, 0x9f, 0x27, 0x96,
+	0x3f, 0xd6, 0x01, 0x7e, 0x61, 0x5b, 0xd2, 0x6f, 0x70, 0x22, 0xa9, 0x92, 0x7a, 0x7f, 0x2d, 0xe5,
+	0x22, 0xcb, 0x28, 0xcb, 0x67, 0x94, 0x9b, 0xa5, 0xc8, 0x4b, 0x96, 0x5b, 0xed, 0x4c, 0x07, 0x48,
+	0x62, 0x53, 0x25, 0x75, 0xd1, 0xfc, 0x33, 0x17, 0x2c, 0xb3, 0x06, 0xd1, 0xb8, 0x5e, 0xa4, 0x55,
+	0x5a, 0x17, 0x4d, 0x8c, 0xf7, 0xe2, 0x95, 0xad, 0xff, 0xda, 0xdd, 0xbc, 0x7d, 0xfb, 0x0e, 0x00,
+	0x0a, 0x06, 0xf7, 0xd4, 0xd3, 0x00, 0x00, 0x00,
+}
@@ -39,7 +39,7 @@ var TestRun_Status_value = map[string]int32{
 func (x TestRun_Status) String() string {
 	return proto.EnumName(TestRun_Status_name, int32(x))
 }
-func (TestRun_Status) EnumDescriptor() ([]byte, []int) { return fileDescriptor4, []int{0, 0} }
+func (TestRun_Status) EnumDescriptor() ([]byte, []int) { return fileDescriptor5, []int{0, 0} }
 
 type TestRun struct {
 	Name        string                     `protobuf:"bytes,1,opt,name=name" json:"name,omitempty"`
@@ -52,7 +52,7 @@ type TestRun struct {
 func (m *TestRun) Reset()                    { *m = TestRun{} }
 func (m *TestRun) String() string            { return proto.CompactTextString(m) }
 func (*TestRun) ProtoMessage()               {}
-func (*TestRun) Descriptor() ([]byte, []int) { return fileDescriptor4, []int{0} }
+func (*TestRun) Descriptor() ([]byte, []int) { return fileDescriptor5, []int{0} }
 
 func (m *TestRun) GetName() string {
 	if m != nil {
@@ -94,10 +94,10 @@ func init() {
 	proto.RegisterEnum("hapi.release.TestRun_Status", TestRun_Status_name, TestRun_Status_value)
 }
 
-func init() { proto.RegisterFile("hapi/release/test_run.proto", fileDescriptor4) }
+func init() { proto.RegisterFile("hapi/release/test_run.proto", fileDescriptor5) }
 
-var fileDescriptor4 = []byte{
-	// 274 bytes of a gzipped FileDescriptorProto
+var fileDescriptor5 = []byte{
+	// 271 bytes of a gzipped FileDescriptorProto
 	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x84, 0x8f, 0xc1, 0x4b, 0xfb, 0x30,
 	0x1c, 0xc5, 0x7f, 0xe9, 0xf6, 0x6b, 0x69, 0x3a, 0xa4, 0xe4, 0x54, 0xa6, 0x60, 0xd9, 0xa9, 0xa7,
 	0x14, 0xa6, 0x17, 0x41, 0x0f, 0x75, 0x4c, 0x19, 0x4a, 0x84, 0x74, 0x45, 0xf0, 0x32, 0x32, 0xcd,
@@ -114,6 +114,5 @@ var fileDescriptor4 = []byte{
 	0x7c, 0x06, 0x8b, 0x5b, 0xec, 0x8e, 0xfb, 0x48, 0x80, 0xbd, 0x82, 0x3d, 0xb1, 0x97, 0x57, 0x16,
 	0xfe, 0x33, 0x22, 0x2f, 0x56, 0xab, 0x75, 0x9e, 0x87, 0xc8, 0x88, 0x87, 0x6c, 0xf3, 0x5c, 0xf0,
 	0x75, 0xe8, 0x18, 0xc1, 0x0b, 0xc6, 0x36, 0xec, 0x31, 0x9c, 0xdc, 0xfb, 0x6f, 0x9e, 0xfd, 0xed,
-	0xde, 0x1d, 0x5e, 0xba, 0xfa, 0x0e, 0x00, 0x00, 0xff, 0xff, 0x31, 0x86, 0x46, 0xdb, 0x81, 0x01,
-	0x00, 0x00,
+	0xde, 0x1d, 0x5e, 0xba, 0xfa, 0x1e, 0x00, 0x31, 0x86, 0x46, 0xdb, 0x81, 0x01, 0x00, 0x00,
 }
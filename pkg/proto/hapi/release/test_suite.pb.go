@@ -27,7 +27,7 @@ type TestSuite struct {
 func (m *TestSuite) Reset()                    { *m = TestSuite{} }
 func (m *TestSuite) String() string            { return proto.CompactTextString(m) }
 func (*TestSuite) ProtoMessage()               {}
-func (*TestSuite) Descriptor() ([]byte, []int) { return fileDescriptor5, []int{0} }
+func (*TestSuite) Descriptor() ([]byte, []int) { return fileDescriptor6, []int{0} }
 
 func (m *TestSuite) GetStartedAt() *google_protobuf.Timestamp {
 	if m != nil {
@@ -54,10 +54,10 @@ func init() {
 	proto.RegisterType((*TestSuite)(nil), "hapi.release.TestSuite")
 }
 
-func init() { proto.RegisterFile("hapi/release/test_suite.proto", fileDescriptor5) }
+func init() { proto.RegisterFile("hapi/release/test_suite.proto", fileDescriptor6) }
 
-var fileDescriptor5 = []byte{
-	// 207 bytes of a gzipped FileDescriptorProto
+var fileDescriptor6 = []byte{
+	// 204 bytes of a gzipped FileDescriptorProto
 	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x84, 0x8f, 0xc1, 0x4a, 0x86, 0x40,
 	0x14, 0x85, 0x31, 0x21, 0x71, 0x74, 0x35, 0x10, 0x88, 0x11, 0x49, 0x2b, 0x57, 0x33, 0x60, 0xab,
 	0x16, 0x2d, 0xec, 0x11, 0xcc, 0x55, 0x1b, 0x19, 0xeb, 0x66, 0xc2, 0xe8, 0x0c, 0x73, 0xef, 0xbc,
@@ -70,5 +70,5 @@ var fileDescriptor5 = []byte{
 	0xdd, 0x12, 0x7f, 0x66, 0xf9, 0xbb, 0x59, 0xac, 0x86, 0x10, 0xbe, 0xba, 0x18, 0xce, 0x7e, 0xfd,
 	0x96, 0xb8, 0x64, 0x89, 0x03, 0xf4, 0x9a, 0xb0, 0x88, 0xab, 0xb8, 0xce, 0x9a, 0x1b, 0xf1, 0xf7,
 	0x4b, 0xb1, 0xdd, 0xd8, 0xf9, 0xb5, 0x3b, 0xad, 0x97, 0xf4, 0x2d, 0x09, 0x6c, 0xbc, 0xde, 0xcb,
-	0x1f, 0x7f, 0x02, 0x00, 0x00, 0xff, 0xff, 0x8c, 0x59, 0x65, 0x4f, 0x37, 0x01, 0x00, 0x00,
+	0x1f, 0x7f, 0x06, 0x00, 0x8c, 0x59, 0x65, 0x4f, 0x37, 0x01, 0x00, 0x00,
 }
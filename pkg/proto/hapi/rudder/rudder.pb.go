@@ -29,7 +29,7 @@ import proto "github.com/golang/protobuf/proto"
 import fmt "fmt"
 import math "math"
 import hapi_release3 "k8s.io/helm/pkg/proto/hapi/release"
-import hapi_release5 "k8s.io/helm/pkg/proto/hapi/release"
+import hapi_release6 "k8s.io/helm/pkg/proto/hapi/release"
 
 import (
 	context "golang.org/x/net/context"
@@ -135,7 +135,7 @@ func (m *VersionReleaseResponse) GetVersion() string {
 }
 
 type InstallReleaseRequest struct {
-	Release *hapi_release5.Release `protobuf:"bytes,1,opt,name=release" json:"release,omitempty"`
+	Release *hapi_release6.Release `protobuf:"bytes,1,opt,name=release" json:"release,omitempty"`
 }
 
 func (m *InstallReleaseRequest) Reset()                    { *m = InstallReleaseRequest{} }
@@ -143,7 +143,7 @@ func (m *InstallReleaseRequest) String() string            { return proto.Compac
 func (*InstallReleaseRequest) ProtoMessage()               {}
 func (*InstallReleaseRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{3} }
 
-func (m *InstallReleaseRequest) GetRelease() *hapi_release5.Release {
+func (m *InstallReleaseRequest) GetRelease() *hapi_release6.Release {
 	if m != nil {
 		return m.Release
 	}
@@ -151,7 +151,7 @@ func (m *InstallReleaseRequest) GetRelease() *hapi_release5.Release {
 }
 
 type InstallReleaseResponse struct {
-	Release *hapi_release5.Release `protobuf:"bytes,1,opt,name=release" json:"release,omitempty"`
+	Release *hapi_release6.Release `protobuf:"bytes,1,opt,name=release" json:"release,omitempty"`
 	Result  *Result                `protobuf:"bytes,2,opt,name=result" json:"result,omitempty"`
 }
 
@@ -160,7 +160,7 @@ func (m *InstallReleaseResponse) String() string            { return proto.Compa
 func (*InstallReleaseResponse) ProtoMessage()               {}
 func (*InstallReleaseResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{4} }
 
-func (m *InstallReleaseResponse) GetRelease() *hapi_release5.Release {
+func (m *InstallReleaseResponse) GetRelease() *hapi_release6.Release {
 	if m != nil {
 		return m.Release
 	}
@@ -175,7 +175,10 @@ func (m *InstallReleaseResponse) GetResult() *Result {
 }
 
 type DeleteReleaseRequest struct {
-	Release *hapi_release5.Release `protobuf:"bytes,1,opt,name=release" json:"release,omitempty"`
+	Release           *hapi_release6.Release `protobuf:"bytes,1,opt,name=release" json:"release,omitempty"`
+	Timeout           int64                  `protobuf:"varint,2,opt,name=Timeout" json:"Timeout,omitempty"`
+	Wait              bool                   `protobuf:"varint,3,opt,name=Wait" json:"Wait,omitempty"`
+	PropagationPolicy string                 `protobuf:"bytes,4,opt,name=PropagationPolicy" json:"PropagationPolicy,omitempty"`
 }
 
 func (m *DeleteReleaseRequest) Reset()                    { *m = DeleteReleaseRequest{} }
@@ -183,15 +186,36 @@ func (m *DeleteReleaseRequest) String() string            { return proto.Compact
 func (*DeleteReleaseRequest) ProtoMessage()               {}
 func (*DeleteReleaseRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{5} }
 
-func (m *DeleteReleaseRequest) GetRelease() *hapi_release5.Release {
+func (m *DeleteReleaseRequest) GetRelease() *hapi_release6.Release {
 	if m != nil {
 		return m.Release
 	}
 	return nil
 }
 
+func (m *DeleteReleaseRequest) GetTimeout() int64 {
+	if m != nil {
+		return m.Timeout
+	}
+	return 0
+}
+
+func (m *DeleteReleaseRequest) GetWait() bool {
+	if m != nil {
+		return m.Wait
+	}
+	return false
+}
+
+func (m *DeleteReleaseRequest) GetPropagationPolicy() string {
+	if m != nil {
+		return m.PropagationPolicy
+	}
+	return ""
+}
+
 type DeleteReleaseResponse struct {
-	Release *hapi_release5.Release `protobuf:"bytes,1,opt,name=release" json:"release,omitempty"`
+	Release *hapi_release6.Release `protobuf:"bytes,1,opt,name=release" json:"release,omitempty"`
 	Result  *Result                `protobuf:"bytes,2,opt,name=result" json:"result,omitempty"`
 }
 
@@ -200,7 +224,7 @@ func (m *DeleteReleaseResponse) String() string            { return proto.Compac
 func (*DeleteReleaseResponse) ProtoMessage()               {}
 func (*DeleteReleaseResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{6} }
 
-func (m *DeleteReleaseResponse) GetRelease() *hapi_release5.Release {
+func (m *DeleteReleaseResponse) GetRelease() *hapi_release6.Release {
 	if m != nil {
 		return m.Release
 	}
@@ -215,12 +239,15 @@ func (m *DeleteReleaseResponse) GetResult() *Result {
 }
 
 type UpgradeReleaseRequest struct {
-	Current  *hapi_release5.Release `protobuf:"bytes,1,opt,name=current" json:"current,omitempty"`
-	Target   *hapi_release5.Release `protobuf:"bytes,2,opt,name=target" json:"target,omitempty"`
-	Timeout  int64                  `protobuf:"varint,3,opt,name=Timeout" json:"Timeout,omitempty"`
-	Wait     bool                   `protobuf:"varint,4,opt,name=Wait" json:"Wait,omitempty"`
-	Recreate bool                   `protobuf:"varint,5,opt,name=Recreate" json:"Recreate,omitempty"`
-	Force    bool                   `protobuf:"varint,6,opt,name=Force" json:"Force,omitempty"`
+	Current                  *hapi_release6.Release `protobuf:"bytes,1,opt,name=current" json:"current,omitempty"`
+	Target                   *hapi_release6.Release `protobuf:"bytes,2,opt,name=target" json:"target,omitempty"`
+	Timeout                  int64                  `protobuf:"varint,3,opt,name=Timeout" json:"Timeout,omitempty"`
+	Wait                     bool                   `protobuf:"varint,4,opt,name=Wait" json:"Wait,omitempty"`
+	Recreate                 bool                   `protobuf:"varint,5,opt,name=Recreate" json:"Recreate,omitempty"`
+	Force                    bool                   `protobuf:"varint,6,opt,name=Force" json:"Force,omitempty"`
+	GracePeriod              int64                  `protobuf:"varint,7,opt,name=GracePeriod" json:"GracePeriod,omitempty"`
+	PropagationPolicy        string                 `protobuf:"bytes,8,opt,name=PropagationPolicy" json:"PropagationPolicy,omitempty"`
+	RecreateOnSelectorChange bool                   `protobuf:"varint,9,opt,name=RecreateOnSelectorChange" json:"RecreateOnSelectorChange,omitempty"`
 }
 
 func (m *UpgradeReleaseRequest) Reset()                    { *m = UpgradeReleaseRequest{} }
@@ -228,14 +255,14 @@ func (m *UpgradeReleaseRequest) String() string            { return proto.Compac
 func (*UpgradeReleaseRequest) ProtoMessage()               {}
 func (*UpgradeReleaseRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{7} }
 
-func (m *UpgradeReleaseRequest) GetCurrent() *hapi_release5.Release {
+func (m *UpgradeReleaseRequest) GetCurrent() *hapi_release6.Release {
 	if m != nil {
 		return m.Current
 	}
 	return nil
 }
 
-func (m *UpgradeReleaseRequest) GetTarget() *hapi_release5.Release {
+func (m *UpgradeReleaseRequest) GetTarget() *hapi_release6.Release {
 	if m != nil {
 		return m.Target
 	}
@@ -270,8 +297,29 @@ func (m *UpgradeReleaseRequest) GetForce() bool {
 	return false
 }
 
+func (m *UpgradeReleaseRequest) GetGracePeriod() int64 {
+	if m != nil {
+		return m.GracePeriod
+	}
+	return 0
+}
+
+func (m *UpgradeReleaseRequest) GetPropagationPolicy() string {
+	if m != nil {
+		return m.PropagationPolicy
+	}
+	return ""
+}
+
+func (m *UpgradeReleaseRequest) GetRecreateOnSelectorChange() bool {
+	if m != nil {
+		return m.RecreateOnSelectorChange
+	}
+	return false
+}
+
 type UpgradeReleaseResponse struct {
-	Release *hapi_release5.Release `protobuf:"bytes,1,opt,name=release" json:"release,omitempty"`
+	Release *hapi_release6.Release `protobuf:"bytes,1,opt,name=release" json:"release,omitempty"`
 	Result  *Result                `protobuf:"bytes,2,opt,name=result" json:"result,omitempty"`
 }
 
@@ -280,7 +328,7 @@ func (m *UpgradeReleaseResponse) String() string            { return proto.Compa
 func (*UpgradeReleaseResponse) ProtoMessage()               {}
 func (*UpgradeReleaseResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{8} }
 
-func (m *UpgradeReleaseResponse) GetRelease() *hapi_release5.Release {
+func (m *UpgradeReleaseResponse) GetRelease() *hapi_release6.Release {
 	if m != nil {
 		return m.Release
 	}
@@ -295,12 +343,15 @@ func (m *UpgradeReleaseResponse) GetResult() *Result {
 }
 
 type RollbackReleaseRequest struct {
-	Current  *hapi_release5.Release `protobuf:"bytes,1,opt,name=current" json:"current,omitempty"`
-	Target   *hapi_release5.Release `protobuf:"bytes,2,opt,name=target" json:"target,omitempty"`
-	Timeout  int64                  `protobuf:"varint,3,opt,name=Timeout" json:"Timeout,omitempty"`
-	Wait     bool                   `protobuf:"varint,4,opt,name=Wait" json:"Wait,omitempty"`
-	Recreate bool                   `protobuf:"varint,5,opt,name=Recreate" json:"Recreate,omitempty"`
-	Force    bool                   `protobuf:"varint,6,opt,name=Force" json:"Force,omitempty"`
+	Current                  *hapi_release6.Release `protobuf:"bytes,1,opt,name=current" json:"current,omitempty"`
+	Target                   *hapi_release6.Release `protobuf:"bytes,2,opt,name=target" json:"target,omitempty"`
+	Timeout                  int64                  `protobuf:"varint,3,opt,name=Timeout" json:"Timeout,omitempty"`
+	Wait                     bool                   `protobuf:"varint,4,opt,name=Wait" json:"Wait,omitempty"`
+	Recreate                 bool                   `protobuf:"varint,5,opt,name=Recreate" json:"Recreate,omitempty"`
+	Force                    bool                   `protobuf:"varint,6,opt,name=Force" json:"Force,omitempty"`
+	GracePeriod              int64                  `protobuf:"varint,7,opt,name=GracePeriod" json:"GracePeriod,omitempty"`
+	PropagationPolicy        string                 `protobuf:"bytes,8,opt,name=PropagationPolicy" json:"PropagationPolicy,omitempty"`
+	RecreateOnSelectorChange bool                   `protobuf:"varint,9,opt,name=RecreateOnSelectorChange" json:"RecreateOnSelectorChange,omitempty"`
 }
 
 func (m *RollbackReleaseRequest) Reset()                    { *m = RollbackReleaseRequest{} }
@@ -308,14 +359,14 @@ func (m *RollbackReleaseRequest) String() string            { return proto.Compa
 func (*RollbackReleaseRequest) ProtoMessage()               {}
 func (*RollbackReleaseRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{9} }
 
-func (m *RollbackReleaseRequest) GetCurrent() *hapi_release5.Release {
+func (m *RollbackReleaseRequest) GetCurrent() *hapi_release6.Release {
 	if m != nil {
 		return m.Current
 	}
 	return nil
 }
 
-func (m *RollbackReleaseRequest) GetTarget() *hapi_release5.Release {
+func (m *RollbackReleaseRequest) GetTarget() *hapi_release6.Release {
 	if m != nil {
 		return m.Target
 	}
@@ -350,8 +401,29 @@ func (m *RollbackReleaseRequest) GetForce() bool {
 	return false
 }
 
+func (m *RollbackReleaseRequest) GetGracePeriod() int64 {
+	if m != nil {
+		return m.GracePeriod
+	}
+	return 0
+}
+
+func (m *RollbackReleaseRequest) GetPropagationPolicy() string {
+	if m != nil {
+		return m.PropagationPolicy
+	}
+	return ""
+}
+
+func (m *RollbackReleaseRequest) GetRecreateOnSelectorChange() bool {
+	if m != nil {
+		return m.RecreateOnSelectorChange
+	}
+	return false
+}
+
 type RollbackReleaseResponse struct {
-	Release *hapi_release5.Release `protobuf:"bytes,1,opt,name=release" json:"release,omitempty"`
+	Release *hapi_release6.Release `protobuf:"bytes,1,opt,name=release" json:"release,omitempty"`
 	Result  *Result                `protobuf:"bytes,2,opt,name=result" json:"result,omitempty"`
 }
 
@@ -360,7 +432,7 @@ func (m *RollbackReleaseResponse) String() string            { return proto.Comp
 func (*RollbackReleaseResponse) ProtoMessage()               {}
 func (*RollbackReleaseResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{10} }
 
-func (m *RollbackReleaseResponse) GetRelease() *hapi_release5.Release {
+func (m *RollbackReleaseResponse) GetRelease() *hapi_release6.Release {
 	if m != nil {
 		return m.Release
 	}
@@ -375,7 +447,7 @@ func (m *RollbackReleaseResponse) GetResult() *Result {
 }
 
 type ReleaseStatusRequest struct {
-	Release *hapi_release5.Release `protobuf:"bytes,1,opt,name=release" json:"release,omitempty"`
+	Release *hapi_release6.Release `protobuf:"bytes,1,opt,name=release" json:"release,omitempty"`
 }
 
 func (m *ReleaseStatusRequest) Reset()                    { *m = ReleaseStatusRequest{} }
@@ -383,7 +455,7 @@ func (m *ReleaseStatusRequest) String() string            { return proto.Compact
 func (*ReleaseStatusRequest) ProtoMessage()               {}
 func (*ReleaseStatusRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{11} }
 
-func (m *ReleaseStatusRequest) GetRelease() *hapi_release5.Release {
+func (m *ReleaseStatusRequest) GetRelease() *hapi_release6.Release {
 	if m != nil {
 		return m.Release
 	}
@@ -391,7 +463,7 @@ func (m *ReleaseStatusRequest) GetRelease() *hapi_release5.Release {
 }
 
 type ReleaseStatusResponse struct {
-	Release *hapi_release5.Release `protobuf:"bytes,1,opt,name=release" json:"release,omitempty"`
+	Release *hapi_release6.Release `protobuf:"bytes,1,opt,name=release" json:"release,omitempty"`
 	Info    *hapi_release3.Info    `protobuf:"bytes,2,opt,name=info" json:"info,omitempty"`
 }
 
@@ -400,7 +472,7 @@ func (m *ReleaseStatusResponse) String() string            { return proto.Compac
 func (*ReleaseStatusResponse) ProtoMessage()               {}
 func (*ReleaseStatusResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{12} }
 
-func (m *ReleaseStatusResponse) GetRelease() *hapi_release5.Release {
+func (m *ReleaseStatusResponse) GetRelease() *hapi_release6.Release {
 	if m != nil {
 		return m.Release
 	}
@@ -681,43 +753,48 @@ var _ReleaseModuleService_serviceDesc = grpc.ServiceDesc{
 func init() { proto.RegisterFile("hapi/rudder/rudder.proto", fileDescriptor0) }
 
 var fileDescriptor0 = []byte{
-	// 597 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xdc, 0x56, 0x5f, 0x8f, 0xd2, 0x4e,
-	0x14, 0xa5, 0xb0, 0x14, 0xb8, 0x64, 0x7f, 0x3f, 0x32, 0xa1, 0xd0, 0x34, 0x3e, 0x90, 0x3e, 0x18,
-	0xe2, 0xba, 0x25, 0x41, 0x1f, 0x7d, 0x51, 0x96, 0xfd, 0x13, 0x23, 0x9b, 0x0c, 0xe2, 0x26, 0xbe,
-	0x75, 0xe1, 0x82, 0xd5, 0xd2, 0xd6, 0xe9, 0x74, 0x1f, 0xd5, 0x4f, 0xe3, 0x57, 0xd2, 0x8f, 0x63,
-	0xda, 0x69, 0x89, 0xad, 0xd3, 0x88, 0x6b, 0xc2, 0x83, 0x4f, 0x9d, 0xe9, 0x3d, 0xdc, 0x39, 0xe7,
-	0xf4, 0xce, 0x09, 0xa0, 0xbf, 0xb3, 0x03, 0x67, 0xc4, 0xa2, 0xd5, 0x0a, 0x59, 0xfa, 0xb0, 0x02,
-	0xe6, 0x73, 0x9f, 0x74, 0xe3, 0x8a, 0x15, 0x22, 0xbb, 0x73, 0x96, 0x18, 0x5a, 0xa2, 0x66, 0xf4,
-	0x05, 0x1e, 0x5d, 0xb4, 0x43, 0x1c, 0x39, 0xde, 0xda, 0x17, 0x70, 0xc3, 0xc8, 0x15, 0xd2, 0xa7,
-	0xa8, 0x99, 0x2e, 0xa8, 0x14, 0xc3, 0xc8, 0xe5, 0x84, 0xc0, 0x51, 0xfc, 0x1b, 0x5d, 0x19, 0x28,
-	0xc3, 0x16, 0x4d, 0xd6, 0xa4, 0x03, 0x35, 0xd7, 0xdf, 0xe8, 0xd5, 0x41, 0x6d, 0xd8, 0xa2, 0xf1,
-	0xd2, 0x7c, 0x06, 0xea, 0x9c, 0xdb, 0x3c, 0x0a, 0x49, 0x1b, 0x1a, 0x8b, 0xd9, 0xcb, 0xd9, 0xf5,
-	0xcd, 0xac, 0x53, 0x89, 0x37, 0xf3, 0xc5, 0x64, 0x32, 0x9d, 0xcf, 0x3b, 0x0a, 0x39, 0x86, 0xd6,
-	0x62, 0x36, 0xb9, 0x7c, 0x3e, 0xbb, 0x98, 0x9e, 0x75, 0xaa, 0xa4, 0x05, 0xf5, 0x29, 0xa5, 0xd7,
-	0xb4, 0x53, 0x33, 0xfb, 0xa0, 0xbd, 0x41, 0x16, 0x3a, 0xbe, 0x47, 0x05, 0x0b, 0x8a, 0x1f, 0x23,
-	0x0c, 0xb9, 0x79, 0x0e, 0xbd, 0x62, 0x21, 0x0c, 0x7c, 0x2f, 0xc4, 0x98, 0x96, 0x67, 0x6f, 0x31,
-	0xa3, 0x15, 0xaf, 0x89, 0x0e, 0x8d, 0x3b, 0x81, 0xd6, 0xab, 0xc9, 0xeb, 0x6c, 0x6b, 0x5e, 0x82,
-	0x76, 0xe5, 0x85, 0xdc, 0x76, 0xdd, 0xfc, 0x01, 0x64, 0x04, 0x8d, 0x54, 0x78, 0xd2, 0xa9, 0x3d,
-	0xd6, 0xac, 0xc4, 0xc4, 0xcc, 0x8d, 0x0c, 0x9e, 0xa1, 0xcc, 0xcf, 0xd0, 0x2b, 0x76, 0x4a, 0x19,
-	0xfd, 0x69, 0x2b, 0xf2, 0x14, 0x54, 0x96, 0x78, 0x9c, 0xb0, 0x6d, 0x8f, 0x1f, 0x58, 0xb2, 0xef,
-	0x67, 0x89, 0xef, 0x40, 0x53, 0xac, 0x79, 0x01, 0xdd, 0x33, 0x74, 0x91, 0xe3, 0xdf, 0x2a, 0xf9,
-	0x04, 0x5a, 0xa1, 0xd1, 0x61, 0x85, 0x7c, 0x53, 0x40, 0x5b, 0x04, 0x1b, 0x66, 0xaf, 0x24, 0x52,
-	0x96, 0x11, 0x63, 0xe8, 0xf1, 0xdf, 0x10, 0x48, 0x51, 0xe4, 0x14, 0x54, 0x6e, 0xb3, 0x0d, 0x66,
-	0x04, 0x4a, 0xf0, 0x29, 0x28, 0x9e, 0x93, 0xd7, 0xce, 0x16, 0xfd, 0x88, 0xeb, 0xb5, 0x81, 0x32,
-	0xac, 0xd1, 0x6c, 0x1b, 0x4f, 0xd5, 0x8d, 0xed, 0x70, 0xfd, 0x68, 0xa0, 0x0c, 0x9b, 0x34, 0x59,
-	0x13, 0x03, 0x9a, 0x14, 0x97, 0x0c, 0x6d, 0x8e, 0x7a, 0x3d, 0x79, 0xbf, 0xdb, 0x93, 0x2e, 0xd4,
-	0xcf, 0x7d, 0xb6, 0x44, 0x5d, 0x4d, 0x0a, 0x62, 0x13, 0xcf, 0x48, 0x51, 0xd8, 0x61, 0xad, 0xfd,
-	0xae, 0x40, 0x8f, 0xfa, 0xae, 0x7b, 0x6b, 0x2f, 0x3f, 0xfc, 0x63, 0xde, 0x7e, 0x51, 0xa0, 0xff,
-	0x8b, 0xb4, 0x83, 0xdf, 0xc0, 0xb4, 0x93, 0x88, 0xbc, 0x7b, 0xdf, 0xc0, 0x00, 0xb4, 0x42, 0xa3,
-	0xfb, 0x0a, 0x79, 0x98, 0x86, 0xb4, 0x90, 0x41, 0xf2, 0xe8, 0x2b, 0x6f, 0xed, 0x8b, 0xe0, 0x1e,
-	0x7f, 0xad, 0xef, 0xb8, 0xbf, 0xf2, 0x57, 0x91, 0x8b, 0x73, 0x21, 0x95, 0xac, 0xa1, 0x91, 0x06,
-	0x2d, 0x39, 0x91, 0x9b, 0x20, 0x0d, 0x68, 0xe3, 0xf1, 0x7e, 0x60, 0xa1, 0xcb, 0xac, 0x90, 0x2d,
-	0xfc, 0x97, 0x8f, 0xcf, 0xb2, 0xe3, 0xa4, 0x71, 0x5d, 0x76, 0x9c, 0x3c, 0x91, 0xcd, 0x0a, 0x79,
-	0x0f, 0xc7, 0xb9, 0x8c, 0x23, 0x8f, 0xe4, 0x0d, 0x64, 0x89, 0x6a, 0x9c, 0xec, 0x85, 0xdd, 0x9d,
-	0x15, 0xc0, 0xff, 0x85, 0xc1, 0x24, 0x25, 0x74, 0xe5, 0x57, 0xd3, 0x38, 0xdd, 0x13, 0xfd, 0xb3,
-	0x99, 0xf9, 0x9c, 0x29, 0x33, 0x53, 0x1a, 0xb3, 0x65, 0x66, 0xca, 0xa3, 0x4b, 0x98, 0x99, 0x1b,
-	0xd7, 0x32, 0x33, 0x65, 0x97, 0xa3, 0xcc, 0x4c, 0xe9, 0xfc, 0x9b, 0x95, 0x17, 0xcd, 0xb7, 0xaa,
-	0x40, 0xdc, 0xaa, 0xc9, 0x1f, 0x92, 0x27, 0x3f, 0x02, 0x00, 0x00, 0xff, 0xff, 0xb6, 0xa5, 0x37,
-	0x75, 0xf7, 0x08, 0x00, 0x00,
+	// 674 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xec, 0x56, 0x4d, 0x6f, 0xd3, 0x40,
+	0x10, 0x6d, 0x92, 0xc6, 0x49, 0x26, 0x2a, 0x84, 0x55, 0xd2, 0x5a, 0x16, 0x87, 0xc8, 0x07, 0x54,
+	0xd1, 0x34, 0x95, 0x0a, 0x27, 0xc4, 0x05, 0xd2, 0x4f, 0x21, 0xd2, 0x6a, 0x43, 0xa8, 0xc4, 0x6d,
+	0xeb, 0x4c, 0x53, 0x83, 0xeb, 0x35, 0xeb, 0x75, 0x25, 0x2e, 0xc0, 0x7f, 0x41, 0xe2, 0x8f, 0xc1,
+	0x0f, 0x41, 0xde, 0xb5, 0xa3, 0x38, 0xb5, 0x45, 0x28, 0xa2, 0x27, 0x4e, 0xde, 0xdd, 0x79, 0x99,
+	0x79, 0xf3, 0xc6, 0xde, 0x17, 0x30, 0x2f, 0x59, 0xe0, 0xee, 0x88, 0x68, 0x32, 0x41, 0x91, 0x3c,
+	0xfa, 0x81, 0xe0, 0x92, 0x93, 0x76, 0x1c, 0xe9, 0x87, 0x28, 0xae, 0x5d, 0x07, 0xc3, 0xbe, 0x8e,
+	0x59, 0x1b, 0x1a, 0x8f, 0x1e, 0xb2, 0x10, 0x77, 0x5c, 0xff, 0x82, 0x6b, 0xb8, 0x65, 0x65, 0x02,
+	0xc9, 0x53, 0xc7, 0x6c, 0x0f, 0x0c, 0x8a, 0x61, 0xe4, 0x49, 0x42, 0x60, 0x35, 0xfe, 0x8d, 0x59,
+	0xea, 0x96, 0x36, 0x1b, 0x54, 0xad, 0x49, 0x0b, 0x2a, 0x1e, 0x9f, 0x9a, 0xe5, 0x6e, 0x65, 0xb3,
+	0x41, 0xe3, 0xa5, 0xfd, 0x1c, 0x8c, 0x91, 0x64, 0x32, 0x0a, 0x49, 0x13, 0x6a, 0xe3, 0xe1, 0xab,
+	0xe1, 0xc9, 0xd9, 0xb0, 0xb5, 0x12, 0x6f, 0x46, 0xe3, 0xc1, 0x60, 0x7f, 0x34, 0x6a, 0x95, 0xc8,
+	0x1a, 0x34, 0xc6, 0xc3, 0xc1, 0xd1, 0x8b, 0xe1, 0xe1, 0xfe, 0x5e, 0xab, 0x4c, 0x1a, 0x50, 0xdd,
+	0xa7, 0xf4, 0x84, 0xb6, 0x2a, 0xf6, 0x06, 0x74, 0xde, 0xa2, 0x08, 0x5d, 0xee, 0x53, 0xcd, 0x82,
+	0xe2, 0xc7, 0x08, 0x43, 0x69, 0x1f, 0xc0, 0xfa, 0x62, 0x20, 0x0c, 0xb8, 0x1f, 0x62, 0x4c, 0xcb,
+	0x67, 0x57, 0x98, 0xd2, 0x8a, 0xd7, 0xc4, 0x84, 0xda, 0xb5, 0x46, 0x9b, 0x65, 0x75, 0x9c, 0x6e,
+	0xed, 0x23, 0xe8, 0x1c, 0xfb, 0xa1, 0x64, 0x9e, 0x97, 0x2d, 0x40, 0x76, 0xa0, 0x96, 0x34, 0xae,
+	0x32, 0x35, 0x77, 0x3b, 0x7d, 0x25, 0x62, 0xaa, 0x46, 0x0a, 0x4f, 0x51, 0xf6, 0x17, 0x58, 0x5f,
+	0xcc, 0x94, 0x30, 0xfa, 0xd3, 0x54, 0xe4, 0x29, 0x18, 0x42, 0x69, 0xac, 0xd8, 0x36, 0x77, 0x1f,
+	0xf6, 0xf3, 0xe6, 0xd7, 0xd7, 0x73, 0xa0, 0x09, 0xd6, 0xfe, 0x56, 0x82, 0xf6, 0x1e, 0x7a, 0x28,
+	0xf1, 0x2f, 0x5b, 0x89, 0xe5, 0x7a, 0xe3, 0x5e, 0x21, 0x8f, 0x34, 0x81, 0x0a, 0x4d, 0xb7, 0xb1,
+	0xb8, 0x67, 0xcc, 0x95, 0x66, 0xa5, 0x5b, 0xda, 0xac, 0x53, 0xb5, 0x26, 0x3d, 0x78, 0x70, 0x2a,
+	0x78, 0xc0, 0xa6, 0x4c, 0xba, 0xdc, 0x3f, 0xe5, 0x9e, 0xeb, 0x7c, 0x32, 0x57, 0x95, 0xcc, 0x37,
+	0x03, 0xf6, 0x67, 0xe8, 0x2c, 0x90, 0xbc, 0x5b, 0x95, 0x7e, 0x94, 0xa1, 0x33, 0x0e, 0xa6, 0x82,
+	0x4d, 0x72, 0x64, 0x72, 0x22, 0x21, 0xd0, 0x97, 0xbf, 0x21, 0x90, 0xa0, 0xc8, 0x36, 0x18, 0x92,
+	0x89, 0x29, 0xa6, 0x04, 0x0a, 0xf0, 0x09, 0x68, 0x5e, 0xd5, 0x4a, 0xbe, 0xaa, 0xab, 0x73, 0xaa,
+	0x5a, 0x50, 0xa7, 0xe8, 0x08, 0x64, 0x12, 0xcd, 0xaa, 0x3a, 0x9f, 0xed, 0x49, 0x1b, 0xaa, 0x07,
+	0x5c, 0x38, 0x68, 0x1a, 0x2a, 0xa0, 0x37, 0xa4, 0x0b, 0xcd, 0x43, 0xc1, 0x1c, 0x3c, 0x45, 0xe1,
+	0xf2, 0x89, 0x59, 0x53, 0x35, 0xe6, 0x8f, 0xf2, 0x27, 0x55, 0x2f, 0x98, 0x14, 0x79, 0x06, 0x66,
+	0x5a, 0xf1, 0xc4, 0x1f, 0xa1, 0x87, 0x8e, 0xe4, 0x62, 0x70, 0xc9, 0xfc, 0x29, 0x9a, 0x0d, 0x55,
+	0xb8, 0x30, 0x1e, 0x7f, 0x0c, 0x8b, 0x22, 0xdf, 0xed, 0x98, 0x7f, 0x96, 0x61, 0x9d, 0x72, 0xcf,
+	0x3b, 0x67, 0xce, 0x87, 0xff, 0x73, 0xfe, 0x67, 0x73, 0xfe, 0x5a, 0x82, 0x8d, 0x1b, 0x32, 0xdf,
+	0xed, 0xa4, 0x0f, 0xa1, 0x9d, 0x64, 0xd2, 0x3e, 0x73, 0xeb, 0x0b, 0x3c, 0x80, 0xce, 0x42, 0xa2,
+	0xdb, 0x36, 0xf2, 0x28, 0x71, 0x46, 0xdd, 0x06, 0xc9, 0xa2, 0x8f, 0xfd, 0x0b, 0xae, 0xdd, 0x72,
+	0xf7, 0x7b, 0x75, 0xc6, 0xfd, 0x35, 0x9f, 0x44, 0x1e, 0x8e, 0x74, 0xab, 0xe4, 0x02, 0x6a, 0x89,
+	0xbb, 0x91, 0xad, 0x7c, 0x11, 0x72, 0x5d, 0xd1, 0xea, 0x2d, 0x07, 0xd6, 0x7d, 0xd9, 0x2b, 0xe4,
+	0x0a, 0xee, 0x65, 0x3d, 0xab, 0xa8, 0x5c, 0xae, 0x47, 0x5a, 0xbd, 0xe5, 0xc0, 0xb3, 0x72, 0xef,
+	0x61, 0x2d, 0x73, 0xf7, 0x93, 0xc7, 0xf9, 0x09, 0xf2, 0x5c, 0xcc, 0xda, 0x5a, 0x0a, 0x3b, 0xab,
+	0x15, 0xc0, 0xfd, 0x85, 0x17, 0x93, 0x14, 0xd0, 0xcd, 0xbf, 0x26, 0xac, 0xed, 0x25, 0xd1, 0xf3,
+	0x62, 0x66, 0xef, 0xbc, 0x22, 0x31, 0x73, 0xed, 0xc7, 0xea, 0x2d, 0x07, 0x9e, 0x17, 0x33, 0xf3,
+	0xba, 0x16, 0x89, 0x99, 0xf7, 0x71, 0x58, 0x5b, 0x4b, 0x61, 0xd3, 0x5a, 0x2f, 0xeb, 0xef, 0x0c,
+	0x8d, 0x38, 0x37, 0xd4, 0xbf, 0xc0, 0x27, 0xbf, 0x06, 0x00, 0xe1, 0xbf, 0xab, 0x1a, 0x6c, 0x0a,
+	0x00, 0x00,
 }
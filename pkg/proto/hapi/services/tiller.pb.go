@@ -10,16 +10,22 @@ It is generated from these files:
 
 It has these top-level messages:
 	ListReleasesRequest
+	OutputFormat
 	ListSort
 	ListReleasesResponse
 	GetReleaseStatusRequest
 	GetReleaseStatusResponse
 	GetReleaseContentRequest
 	GetReleaseContentResponse
+	GetManifestRequest
+	GetManifestResponse
+	ForceUnlockRequest
+	ForceUnlockResponse
 	UpdateReleaseRequest
 	UpdateReleaseResponse
 	RollbackReleaseRequest
 	RollbackReleaseResponse
+	HookPreview
 	InstallReleaseRequest
 	InstallReleaseResponse
 	UninstallReleaseRequest
@@ -30,15 +36,58 @@ It has these top-level messages:
 	GetHistoryResponse
 	TestReleaseRequest
 	TestReleaseResponse
+	GetHealthRequest
+	DependencyHealth
+	GetHealthResponse
+	BatchInstallRequest
+	BatchInstallResult
+	BatchInstallResponse
+	RestoreReleaseRequest
+	RestoreReleaseResponse
+	InspectChartRequest
+	ChartDependency
+	InspectChartResponse
+	AnnotateReleaseRequest
+	AnnotateReleaseResponse
+	RestartReleaseRequest
+	RestartReleaseResponse
+	ApproveReleaseRequest
+	ApproveReleaseResponse
+	RejectReleaseRequest
+	RejectReleaseResponse
+	GetReleaseDriftRequest
+	ResourceDrift
+	FieldDrift
+	GetReleaseDriftResponse
+	ExportReleaseRequest
+	ExportedFile
+	ExportReleaseResponse
+	ResumeReleaseRequest
+	ResumeReleaseResponse
+	MigrationStatusRequest
+	MigrationStatusResponse
+	SnapshotReleaseRequest
+	SnapshotReleaseResponse
+	GetHooksRequest
+	GetHooksResponse
+	InvalidateDiscoveryCacheRequest
+	InvalidateDiscoveryCacheResponse
+	GetReleaseLabelsRequest
+	GetReleaseLabelsResponse
+	WatchWaitLogsRequest
+	WatchWaitLogsResponse
 */
 package services
 
 import proto "github.com/golang/protobuf/proto"
 import fmt "fmt"
 import math "math"
+import google_protobuf "github.com/golang/protobuf/ptypes/timestamp"
 import hapi_chart3 "k8s.io/helm/pkg/proto/hapi/chart"
 import hapi_chart "k8s.io/helm/pkg/proto/hapi/chart"
-import hapi_release5 "k8s.io/helm/pkg/proto/hapi/release"
+import hapi_chart1 "k8s.io/helm/pkg/proto/hapi/chart"
+import hapi_release "k8s.io/helm/pkg/proto/hapi/release"
+import hapi_release6 "k8s.io/helm/pkg/proto/hapi/release"
 import hapi_release4 "k8s.io/helm/pkg/proto/hapi/release"
 import hapi_release1 "k8s.io/helm/pkg/proto/hapi/release"
 import hapi_release3 "k8s.io/helm/pkg/proto/hapi/release"
@@ -60,6 +109,38 @@ var _ = math.Inf
 // proto package needs to be updated.
 const _ = proto.ProtoPackageIsVersion2 // please upgrade the proto package
 
+type OutputFormat_Format int32
+
+const (
+	// NONE renders nothing.
+	OutputFormat_NONE OutputFormat_Format = 0
+	// JSON renders a flat JSON object, or an array of them for a list.
+	OutputFormat_JSON OutputFormat_Format = 1
+	// YAML renders the same fields as JSON, as YAML.
+	OutputFormat_YAML OutputFormat_Format = 2
+	// TABLE renders a table like the one 'helm list' prints. A status is
+	// rendered as a table of its single-line fields.
+	OutputFormat_TABLE OutputFormat_Format = 3
+)
+
+var OutputFormat_Format_name = map[int32]string{
+	0: "NONE",
+	1: "JSON",
+	2: "YAML",
+	3: "TABLE",
+}
+var OutputFormat_Format_value = map[string]int32{
+	"NONE":  0,
+	"JSON":  1,
+	"YAML":  2,
+	"TABLE": 3,
+}
+
+func (x OutputFormat_Format) String() string {
+	return proto.EnumName(OutputFormat_Format_name, int32(x))
+}
+func (OutputFormat_Format) EnumDescriptor() ([]byte, []int) { return fileDescriptor0, []int{1, 0} }
+
 // SortBy defines sort operations.
 type ListSort_SortBy int32
 
@@ -83,7 +164,7 @@ var ListSort_SortBy_value = map[string]int32{
 func (x ListSort_SortBy) String() string {
 	return proto.EnumName(ListSort_SortBy_name, int32(x))
 }
-func (ListSort_SortBy) EnumDescriptor() ([]byte, []int) { return fileDescriptor0, []int{1, 0} }
+func (ListSort_SortBy) EnumDescriptor() ([]byte, []int) { return fileDescriptor0, []int{2, 0} }
 
 // SortOrder defines sort orders to augment sorting operations.
 type ListSort_SortOrder int32
@@ -105,7 +186,114 @@ var ListSort_SortOrder_value = map[string]int32{
 func (x ListSort_SortOrder) String() string {
 	return proto.EnumName(ListSort_SortOrder_name, int32(x))
 }
-func (ListSort_SortOrder) EnumDescriptor() ([]byte, []int) { return fileDescriptor0, []int{1, 1} }
+func (ListSort_SortOrder) EnumDescriptor() ([]byte, []int) { return fileDescriptor0, []int{2, 1} }
+
+type DependencyHealth_Status int32
+
+const (
+	DependencyHealth_UNKNOWN  DependencyHealth_Status = 0
+	DependencyHealth_OK       DependencyHealth_Status = 1
+	DependencyHealth_DEGRADED DependencyHealth_Status = 2
+)
+
+var DependencyHealth_Status_name = map[int32]string{
+	0: "UNKNOWN",
+	1: "OK",
+	2: "DEGRADED",
+}
+var DependencyHealth_Status_value = map[string]int32{
+	"UNKNOWN":  0,
+	"OK":       1,
+	"DEGRADED": 2,
+}
+
+func (x DependencyHealth_Status) String() string {
+	return proto.EnumName(DependencyHealth_Status_name, int32(x))
+}
+func (DependencyHealth_Status) EnumDescriptor() ([]byte, []int) { return fileDescriptor0, []int{28, 0} }
+
+type BatchInstallResult_Outcome int32
+
+const (
+	// SKIPPED entries were not attempted because an earlier one failed.
+	BatchInstallResult_SKIPPED BatchInstallResult_Outcome = 0
+	// INSTALLED entries are installed. Either every entry is INSTALLED,
+	// or none is.
+	BatchInstallResult_INSTALLED BatchInstallResult_Outcome = 1
+	// FAILED is the entry that failed to install. Anything it created
+	// has been uninstalled, unless error says otherwise.
+	BatchInstallResult_FAILED BatchInstallResult_Outcome = 2
+	// ROLLED_BACK entries were installed, then uninstalled because a
+	// later entry failed.
+	BatchInstallResult_ROLLED_BACK BatchInstallResult_Outcome = 3
+	// ROLLBACK_FAILED entries could not be fully uninstalled after a
+	// failure, and may need to be deleted by hand.
+	BatchInstallResult_ROLLBACK_FAILED BatchInstallResult_Outcome = 4
+)
+
+var BatchInstallResult_Outcome_name = map[int32]string{
+	0: "SKIPPED",
+	1: "INSTALLED",
+	2: "FAILED",
+	3: "ROLLED_BACK",
+	4: "ROLLBACK_FAILED",
+}
+var BatchInstallResult_Outcome_value = map[string]int32{
+	"SKIPPED":         0,
+	"INSTALLED":       1,
+	"FAILED":          2,
+	"ROLLED_BACK":     3,
+	"ROLLBACK_FAILED": 4,
+}
+
+func (x BatchInstallResult_Outcome) String() string {
+	return proto.EnumName(BatchInstallResult_Outcome_name, int32(x))
+}
+func (BatchInstallResult_Outcome) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor0, []int{31, 0}
+}
+
+type ResourceDrift_Status int32
+
+const (
+	// IN_SYNC means the live resource matches the manifest.
+	ResourceDrift_IN_SYNC ResourceDrift_Status = 0
+	// MODIFIED means fields set in the manifest have other live values.
+	ResourceDrift_MODIFIED ResourceDrift_Status = 1
+	// MISSING means the resource is in the manifest, but does not exist.
+	ResourceDrift_MISSING ResourceDrift_Status = 2
+	// EXTRA means the resource was in an earlier revision, but not in the
+	// compared one, and still exists.
+	ResourceDrift_EXTRA ResourceDrift_Status = 3
+	// ADDED means the resource is in the chart compared with an upgrade,
+	// but not in the release, and does not exist: the upgrade creates it.
+	ResourceDrift_ADDED ResourceDrift_Status = 4
+	// REMOVED means the resource is in the release, but not in the chart
+	// compared with an upgrade, and exists: the upgrade deletes it.
+	ResourceDrift_REMOVED ResourceDrift_Status = 5
+)
+
+var ResourceDrift_Status_name = map[int32]string{
+	0: "IN_SYNC",
+	1: "MODIFIED",
+	2: "MISSING",
+	3: "EXTRA",
+	4: "ADDED",
+	5: "REMOVED",
+}
+var ResourceDrift_Status_value = map[string]int32{
+	"IN_SYNC":  0,
+	"MODIFIED": 1,
+	"MISSING":  2,
+	"EXTRA":    3,
+	"ADDED":    4,
+	"REMOVED":  5,
+}
+
+func (x ResourceDrift_Status) String() string {
+	return proto.EnumName(ResourceDrift_Status_name, int32(x))
+}
+func (ResourceDrift_Status) EnumDescriptor() ([]byte, []int) { return fileDescriptor0, []int{47, 0} }
 
 // ListReleasesRequest requests a list of releases.
 //
@@ -132,6 +320,12 @@ type ListReleasesRequest struct {
 	StatusCodes []hapi_release3.Status_Code `protobuf:"varint,6,rep,packed,name=status_codes,json=statusCodes,enum=hapi.release.Status_Code" json:"status_codes,omitempty"`
 	// Namespace is the filter to select releases only from a specific namespace.
 	Namespace string `protobuf:"bytes,7,opt,name=namespace" json:"namespace,omitempty"`
+	// OutputFormat, if set, makes the response carry a rendering of the
+	// listed releases in that format; see ListReleasesResponse.rendered.
+	OutputFormat OutputFormat_Format `protobuf:"varint,8,opt,name=output_format,json=outputFormat,enum=hapi.services.tiller.OutputFormat_Format" json:"output_format,omitempty"`
+	// IncludeSystem lists the releases marked as system releases as well,
+	// which are left out otherwise.
+	IncludeSystem bool `protobuf:"varint,9,opt,name=include_system,json=includeSystem" json:"include_system,omitempty"`
 }
 
 func (m *ListReleasesRequest) Reset()                    { *m = ListReleasesRequest{} }
@@ -188,6 +382,31 @@ func (m *ListReleasesRequest) GetNamespace() string {
 	return ""
 }
 
+func (m *ListReleasesRequest) GetOutputFormat() OutputFormat_Format {
+	if m != nil {
+		return m.OutputFormat
+	}
+	return OutputFormat_NONE
+}
+
+func (m *ListReleasesRequest) GetIncludeSystem() bool {
+	if m != nil {
+		return m.IncludeSystem
+	}
+	return false
+}
+
+// OutputFormat defines the formats that list and status responses can be
+// rendered in, for clients that do not want to process the structured
+// response themselves.
+type OutputFormat struct {
+}
+
+func (m *OutputFormat) Reset()                    { *m = OutputFormat{} }
+func (m *OutputFormat) String() string            { return proto.CompactTextString(m) }
+func (*OutputFormat) ProtoMessage()               {}
+func (*OutputFormat) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{1} }
+
 // ListSort defines sorting fields on a release list.
 type ListSort struct {
 }
@@ -195,7 +414,7 @@ type ListSort struct {
 func (m *ListSort) Reset()                    { *m = ListSort{} }
 func (m *ListSort) String() string            { return proto.CompactTextString(m) }
 func (*ListSort) ProtoMessage()               {}
-func (*ListSort) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{1} }
+func (*ListSort) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{2} }
 
 // ListReleasesResponse is a list of releases.
 type ListReleasesResponse struct {
@@ -207,13 +426,16 @@ type ListReleasesResponse struct {
 	// Total is the total number of queryable releases.
 	Total int64 `protobuf:"varint,3,opt,name=total" json:"total,omitempty"`
 	// Releases is the list of found release objects.
-	Releases []*hapi_release5.Release `protobuf:"bytes,4,rep,name=releases" json:"releases,omitempty"`
+	Releases []*hapi_release6.Release `protobuf:"bytes,4,rep,name=releases" json:"releases,omitempty"`
+	// Rendered is the rendering of releases in the requested output format.
+	// It is derived from releases, which remain authoritative.
+	Rendered string `protobuf:"bytes,5,opt,name=rendered" json:"rendered,omitempty"`
 }
 
 func (m *ListReleasesResponse) Reset()                    { *m = ListReleasesResponse{} }
 func (m *ListReleasesResponse) String() string            { return proto.CompactTextString(m) }
 func (*ListReleasesResponse) ProtoMessage()               {}
-func (*ListReleasesResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{2} }
+func (*ListReleasesResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{3} }
 
 func (m *ListReleasesResponse) GetCount() int64 {
 	if m != nil {
@@ -236,25 +458,35 @@ func (m *ListReleasesResponse) GetTotal() int64 {
 	return 0
 }
 
-func (m *ListReleasesResponse) GetReleases() []*hapi_release5.Release {
+func (m *ListReleasesResponse) GetReleases() []*hapi_release6.Release {
 	if m != nil {
 		return m.Releases
 	}
 	return nil
 }
 
+func (m *ListReleasesResponse) GetRendered() string {
+	if m != nil {
+		return m.Rendered
+	}
+	return ""
+}
+
 // GetReleaseStatusRequest is a request to get the status of a release.
 type GetReleaseStatusRequest struct {
 	// Name is the name of the release
 	Name string `protobuf:"bytes,1,opt,name=name" json:"name,omitempty"`
 	// Version is the version of the release
 	Version int32 `protobuf:"varint,2,opt,name=version" json:"version,omitempty"`
+	// OutputFormat, if set, makes the response carry a rendering of the
+	// status in that format; see GetReleaseStatusResponse.rendered.
+	OutputFormat OutputFormat_Format `protobuf:"varint,3,opt,name=output_format,json=outputFormat,enum=hapi.services.tiller.OutputFormat_Format" json:"output_format,omitempty"`
 }
 
 func (m *GetReleaseStatusRequest) Reset()                    { *m = GetReleaseStatusRequest{} }
 func (m *GetReleaseStatusRequest) String() string            { return proto.CompactTextString(m) }
 func (*GetReleaseStatusRequest) ProtoMessage()               {}
-func (*GetReleaseStatusRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{3} }
+func (*GetReleaseStatusRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{4} }
 
 func (m *GetReleaseStatusRequest) GetName() string {
 	if m != nil {
@@ -270,6 +502,13 @@ func (m *GetReleaseStatusRequest) GetVersion() int32 {
 	return 0
 }
 
+func (m *GetReleaseStatusRequest) GetOutputFormat() OutputFormat_Format {
+	if m != nil {
+		return m.OutputFormat
+	}
+	return OutputFormat_NONE
+}
+
 // GetReleaseStatusResponse is the response indicating the status of the named release.
 type GetReleaseStatusResponse struct {
 	// Name is the name of the release.
@@ -278,12 +517,21 @@ type GetReleaseStatusResponse struct {
 	Info *hapi_release4.Info `protobuf:"bytes,2,opt,name=info" json:"info,omitempty"`
 	// Namesapce the release was released into
 	Namespace string `protobuf:"bytes,3,opt,name=namespace" json:"namespace,omitempty"`
+	// Namespaces lists every namespace the release has resources in; see
+	// hapi.release.Release.namespaces.
+	Namespaces []string `protobuf:"bytes,4,rep,name=namespaces" json:"namespaces,omitempty"`
+	// Rendered is the rendering of the status in the requested output format.
+	// It is derived from the other fields, which remain authoritative.
+	Rendered string `protobuf:"bytes,5,opt,name=rendered" json:"rendered,omitempty"`
+	// Cluster names the cluster the release is installed in; see
+	// hapi.release.Release.cluster.
+	Cluster string `protobuf:"bytes,6,opt,name=cluster" json:"cluster,omitempty"`
 }
 
 func (m *GetReleaseStatusResponse) Reset()                    { *m = GetReleaseStatusResponse{} }
 func (m *GetReleaseStatusResponse) String() string            { return proto.CompactTextString(m) }
 func (*GetReleaseStatusResponse) ProtoMessage()               {}
-func (*GetReleaseStatusResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{4} }
+func (*GetReleaseStatusResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{5} }
 
 func (m *GetReleaseStatusResponse) GetName() string {
 	if m != nil {
@@ -306,6 +554,27 @@ func (m *GetReleaseStatusResponse) GetNamespace() string {
 	return ""
 }
 
+func (m *GetReleaseStatusResponse) GetNamespaces() []string {
+	if m != nil {
+		return m.Namespaces
+	}
+	return nil
+}
+
+func (m *GetReleaseStatusResponse) GetRendered() string {
+	if m != nil {
+		return m.Rendered
+	}
+	return ""
+}
+
+func (m *GetReleaseStatusResponse) GetCluster() string {
+	if m != nil {
+		return m.Cluster
+	}
+	return ""
+}
+
 // GetReleaseContentRequest is a request to get the contents of a release.
 type GetReleaseContentRequest struct {
 	// The name of the release
@@ -317,7 +586,7 @@ type GetReleaseContentRequest struct {
 func (m *GetReleaseContentRequest) Reset()                    { *m = GetReleaseContentRequest{} }
 func (m *GetReleaseContentRequest) String() string            { return proto.CompactTextString(m) }
 func (*GetReleaseContentRequest) ProtoMessage()               {}
-func (*GetReleaseContentRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{5} }
+func (*GetReleaseContentRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{6} }
 
 func (m *GetReleaseContentRequest) GetName() string {
 	if m != nil {
@@ -336,21 +605,131 @@ func (m *GetReleaseContentRequest) GetVersion() int32 {
 // GetReleaseContentResponse is a response containing the contents of a release.
 type GetReleaseContentResponse struct {
 	// The release content
-	Release *hapi_release5.Release `protobuf:"bytes,1,opt,name=release" json:"release,omitempty"`
+	Release *hapi_release6.Release `protobuf:"bytes,1,opt,name=release" json:"release,omitempty"`
 }
 
 func (m *GetReleaseContentResponse) Reset()                    { *m = GetReleaseContentResponse{} }
 func (m *GetReleaseContentResponse) String() string            { return proto.CompactTextString(m) }
 func (*GetReleaseContentResponse) ProtoMessage()               {}
-func (*GetReleaseContentResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{6} }
+func (*GetReleaseContentResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{7} }
 
-func (m *GetReleaseContentResponse) GetRelease() *hapi_release5.Release {
+func (m *GetReleaseContentResponse) GetRelease() *hapi_release6.Release {
 	if m != nil {
 		return m.Release
 	}
 	return nil
 }
 
+// GetManifestRequest is a request to get the manifest of a release.
+type GetManifestRequest struct {
+	// The name of the release
+	Name string `protobuf:"bytes,1,opt,name=name" json:"name,omitempty"`
+	// Version is the version of the release. If it is not set, the manifest
+	// of the deployed version is returned.
+	Version int32 `protobuf:"varint,2,opt,name=version" json:"version,omitempty"`
+}
+
+func (m *GetManifestRequest) Reset()                    { *m = GetManifestRequest{} }
+func (m *GetManifestRequest) String() string            { return proto.CompactTextString(m) }
+func (*GetManifestRequest) ProtoMessage()               {}
+func (*GetManifestRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{8} }
+
+func (m *GetManifestRequest) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *GetManifestRequest) GetVersion() int32 {
+	if m != nil {
+		return m.Version
+	}
+	return 0
+}
+
+// GetManifestResponse is a response containing the manifest of a release.
+type GetManifestResponse struct {
+	// Version is the version of the release the manifest belongs to.
+	Version int32 `protobuf:"varint,1,opt,name=version" json:"version,omitempty"`
+	// Manifest is the rendered manifest of the release.
+	Manifest string `protobuf:"bytes,2,opt,name=manifest" json:"manifest,omitempty"`
+}
+
+func (m *GetManifestResponse) Reset()                    { *m = GetManifestResponse{} }
+func (m *GetManifestResponse) String() string            { return proto.CompactTextString(m) }
+func (*GetManifestResponse) ProtoMessage()               {}
+func (*GetManifestResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{9} }
+
+func (m *GetManifestResponse) GetVersion() int32 {
+	if m != nil {
+		return m.Version
+	}
+	return 0
+}
+
+func (m *GetManifestResponse) GetManifest() string {
+	if m != nil {
+		return m.Manifest
+	}
+	return ""
+}
+
+// ForceUnlockRequest is a request to clear the lock on a release.
+type ForceUnlockRequest struct {
+	// The name of the release
+	Name string `protobuf:"bytes,1,opt,name=name" json:"name,omitempty"`
+	// Confirm must be set to the name of the release. Requests where it is
+	// not are rejected.
+	Confirm string `protobuf:"bytes,2,opt,name=confirm" json:"confirm,omitempty"`
+}
+
+func (m *ForceUnlockRequest) Reset()                    { *m = ForceUnlockRequest{} }
+func (m *ForceUnlockRequest) String() string            { return proto.CompactTextString(m) }
+func (*ForceUnlockRequest) ProtoMessage()               {}
+func (*ForceUnlockRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{10} }
+
+func (m *ForceUnlockRequest) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *ForceUnlockRequest) GetConfirm() string {
+	if m != nil {
+		return m.Confirm
+	}
+	return ""
+}
+
+// ForceUnlockResponse reports the outcome of a force unlock.
+type ForceUnlockResponse struct {
+	// WasLocked is true if the release was locked.
+	WasLocked bool `protobuf:"varint,1,opt,name=was_locked,json=wasLocked" json:"was_locked,omitempty"`
+	// Warning is set when an operation may still be running on the release.
+	Warning string `protobuf:"bytes,2,opt,name=warning" json:"warning,omitempty"`
+}
+
+func (m *ForceUnlockResponse) Reset()                    { *m = ForceUnlockResponse{} }
+func (m *ForceUnlockResponse) String() string            { return proto.CompactTextString(m) }
+func (*ForceUnlockResponse) ProtoMessage()               {}
+func (*ForceUnlockResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{11} }
+
+func (m *ForceUnlockResponse) GetWasLocked() bool {
+	if m != nil {
+		return m.WasLocked
+	}
+	return false
+}
+
+func (m *ForceUnlockResponse) GetWarning() string {
+	if m != nil {
+		return m.Warning
+	}
+	return ""
+}
+
 // UpdateReleaseRequest updates a release.
 type UpdateReleaseRequest struct {
 	// The name of the release
@@ -377,12 +756,108 @@ type UpdateReleaseRequest struct {
 	ReuseValues bool `protobuf:"varint,10,opt,name=reuse_values,json=reuseValues" json:"reuse_values,omitempty"`
 	// Force resource update through delete/recreate if needed.
 	Force bool `protobuf:"varint,11,opt,name=force" json:"force,omitempty"`
+	// ServerDryRun, if true along with dry_run, sends the upgraded resources
+	// through a server-side dry-run and returns them with server defaults applied.
+	ServerDryRun bool `protobuf:"varint,12,opt,name=server_dry_run,json=serverDryRun" json:"server_dry_run,omitempty"`
+	// SkipHooks lists the names of hooks that should not be run.
+	SkipHooks []string `protobuf:"bytes,13,rep,name=skip_hooks,json=skipHooks" json:"skip_hooks,omitempty"`
+	// SkipHookWeights lists the weights of hooks that should not be run.
+	SkipHookWeights []int32 `protobuf:"varint,14,rep,packed,name=skip_hook_weights,json=skipHookWeights" json:"skip_hook_weights,omitempty"`
+	// VerifyImages, if true, checks that the registries have every container
+	// image of the release before anything is upgraded. The image pull
+	// secrets of the release's pods are used to log in to the registries.
+	VerifyImages bool `protobuf:"varint,16,opt,name=verify_images,json=verifyImages" json:"verify_images,omitempty"`
+	// Annotations are recorded with the new revision; see
+	// hapi.release.Info.annotations.
+	Annotations map[string]string `protobuf:"bytes,17,rep,name=annotations" json:"annotations,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	// RequireApproval, if true, makes the upgrade stop after the pre-upgrade
+	// hooks and record the new revision as AWAITING_APPROVAL. Nothing else is
+	// changed until ApproveRelease is called; RejectRelease aborts the upgrade.
+	RequireApproval bool `protobuf:"varint,18,opt,name=require_approval,json=requireApproval" json:"require_approval,omitempty"`
+	// ApprovalTimeout is the number of seconds to wait for approval before the
+	// upgrade is rejected. It defaults to one hour.
+	ApprovalTimeout int64 `protobuf:"varint,19,opt,name=approval_timeout,json=approvalTimeout" json:"approval_timeout,omitempty"`
+	// GracePeriod is the number of seconds that the pods deleted by recreate,
+	// force or the removal of resources get to shut down. Zero uses the
+	// termination grace period of each pod.
+	GracePeriod int64 `protobuf:"varint,20,opt,name=grace_period,json=gracePeriod" json:"grace_period,omitempty"`
+	// PropagationPolicy is the Kubernetes deletion propagation policy of the
+	// resources that the upgrade deletes: "Foreground", "Background" or
+	// "Orphan". When it is empty, they are deleted as kubectl would delete them.
+	PropagationPolicy string `protobuf:"bytes,21,opt,name=propagation_policy,json=propagationPolicy" json:"propagation_policy,omitempty"`
+	// RecreateOnSelectorChange deletes and recreates the workloads that cannot
+	// be patched only because their label selector changed. They are deleted
+	// with the Orphan policy, so that the new workloads adopt their pods.
+	RecreateOnSelectorChange bool `protobuf:"varint,22,opt,name=recreate_on_selector_change,json=recreateOnSelectorChange" json:"recreate_on_selector_change,omitempty"`
+	// Profile names a values file of the chart, values-<profile>.yaml, whose
+	// values override the chart's defaults and are overridden by values.
+	Profile string `protobuf:"bytes,23,opt,name=profile" json:"profile,omitempty"`
+	// AllowMissingProfile upgrades to the chart with its defaults if it has no
+	// values file for the profile, instead of failing.
+	AllowMissingProfile bool `protobuf:"varint,24,opt,name=allow_missing_profile,json=allowMissingProfile" json:"allow_missing_profile,omitempty"`
+	// EnableHooks runs the hooks of the upgrade even if Tiller skips them by
+	// default. DisableHooks takes precedence.
+	EnableHooks bool `protobuf:"varint,25,opt,name=enable_hooks,json=enableHooks" json:"enable_hooks,omitempty"`
+	// Cluster, if set, must name the cluster the release is installed in. It
+	// guards against upgrading a release of the same name in another cluster.
+	Cluster string `protobuf:"bytes,26,opt,name=cluster" json:"cluster,omitempty"`
+	// OnFailure is what happens when some resources cannot be applied: with
+	// "keep", the default, the failed revision records which resources were
+	// applied and can be resumed with ResumeRelease. With "revert", the
+	// upgrade is undone at once: the resources it applied are returned to the
+	// previous revision, which is recorded again as a new revision.
+	OnFailure string `protobuf:"bytes,27,opt,name=on_failure,json=onFailure" json:"on_failure,omitempty"`
+	// VerifyReferences, if true, checks before anything is upgraded that the
+	// ServiceAccounts, Secrets and ConfigMaps that the release's pods refer to
+	// are part of the release or exist in the cluster.
+	VerifyReferences bool `protobuf:"varint,28,opt,name=verify_references,json=verifyReferences" json:"verify_references,omitempty"`
+	// TimeoutBudget, if set, is how long, in seconds, the whole upgrade may
+	// take. The pre- and post-upgrade hooks and applying and waiting for the resources run
+	// one after the other, each with the time that is left, instead of with
+	// timeout each.
+	TimeoutBudget int64 `protobuf:"varint,29,opt,name=timeout_budget,json=timeoutBudget" json:"timeout_budget,omitempty"`
+	// AllowProtectedChanges, if true, lets the upgrade change the resources
+	// that the helm.sh/protected-resources annotation of the release protects.
+	AllowProtectedChanges bool `protobuf:"varint,30,opt,name=allow_protected_changes,json=allowProtectedChanges" json:"allow_protected_changes,omitempty"`
+	// StrictValues, if true, fails the upgrade if values has keys that match
+	// nothing in the default values or values schemas of the chart and its
+	// subcharts, naming all of them.
+	StrictValues bool `protobuf:"varint,31,opt,name=strict_values,json=strictValues" json:"strict_values,omitempty"`
+	// Impersonation, if set, is the identity that Tiller acts as towards the
+	// Kubernetes API while upgrading the release, so that RBAC and audit logs
+	// apply to it instead of to Tiller. Tiller must be allowed to impersonate
+	// it. It is recorded in the info of the revision.
+	Impersonation *hapi_release4.Impersonation `protobuf:"bytes,32,opt,name=impersonation" json:"impersonation,omitempty"`
+	// DeferNotes, if true, renders the notes again once the resources are
+	// created and, with wait, ready, with .Live holding the live state of the
+	// release's resources. If they cannot be rendered again, the notes rendered
+	// before are kept.
+	DeferNotes bool `protobuf:"varint,33,opt,name=defer_notes,json=deferNotes" json:"defer_notes,omitempty"`
+	// Flags are feature flags exposed to templates as .Release.Flags, to turn
+	// template branches on and off for this operation only. They are not
+	// values: they are recorded in the info of the revision, and are not
+	// carried over to later upgrades.
+	Flags map[string]bool `protobuf:"bytes,34,rep,name=flags" json:"flags,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"varint,2,opt,name=value"`
+	// KeepRemoved, if true, leaves the resources that were removed from the
+	// chart since the previous release in the cluster. By default they are
+	// deleted, except for resources with the "keep" resource policy and
+	// resources that another deployed release declares.
+	KeepRemoved bool `protobuf:"varint,35,opt,name=keep_removed,json=keepRemoved" json:"keep_removed,omitempty"`
+	// SkipHookLookup keeps a dry run from querying the cluster for existing
+	// resources that the before-hook-creation delete policy of a hook would
+	// delete. See HookPreview.replaces_existing.
+	SkipHookLookup bool `protobuf:"varint,36,opt,name=skip_hook_lookup,json=skipHookLookup" json:"skip_hook_lookup,omitempty"`
+	// WaitLogBytes, if positive, makes the wait of the upgrade stream the
+	// end of the logs of the containers that are not ready, in at most this
+	// many bytes at a time, to the clients that watch the release with
+	// WatchWaitLogs.
+	WaitLogBytes int64 `protobuf:"varint,37,opt,name=wait_log_bytes,json=waitLogBytes" json:"wait_log_bytes,omitempty"`
 }
 
 func (m *UpdateReleaseRequest) Reset()                    { *m = UpdateReleaseRequest{} }
 func (m *UpdateReleaseRequest) String() string            { return proto.CompactTextString(m) }
 func (*UpdateReleaseRequest) ProtoMessage()               {}
-func (*UpdateReleaseRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{7} }
+func (*UpdateReleaseRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{12} }
 
 func (m *UpdateReleaseRequest) GetName() string {
 	if m != nil {
@@ -461,447 +936,2541 @@ func (m *UpdateReleaseRequest) GetForce() bool {
 	return false
 }
 
-// UpdateReleaseResponse is the response to an update request.
-type UpdateReleaseResponse struct {
-	Release *hapi_release5.Release `protobuf:"bytes,1,opt,name=release" json:"release,omitempty"`
+func (m *UpdateReleaseRequest) GetServerDryRun() bool {
+	if m != nil {
+		return m.ServerDryRun
+	}
+	return false
 }
 
-func (m *UpdateReleaseResponse) Reset()                    { *m = UpdateReleaseResponse{} }
-func (m *UpdateReleaseResponse) String() string            { return proto.CompactTextString(m) }
-func (*UpdateReleaseResponse) ProtoMessage()               {}
-func (*UpdateReleaseResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{8} }
-
-func (m *UpdateReleaseResponse) GetRelease() *hapi_release5.Release {
+func (m *UpdateReleaseRequest) GetSkipHooks() []string {
 	if m != nil {
-		return m.Release
+		return m.SkipHooks
 	}
 	return nil
 }
 
-type RollbackReleaseRequest struct {
-	// The name of the release
-	Name string `protobuf:"bytes,1,opt,name=name" json:"name,omitempty"`
-	// dry_run, if true, will run through the release logic but no create
-	DryRun bool `protobuf:"varint,2,opt,name=dry_run,json=dryRun" json:"dry_run,omitempty"`
-	// DisableHooks causes the server to skip running any hooks for the rollback
-	DisableHooks bool `protobuf:"varint,3,opt,name=disable_hooks,json=disableHooks" json:"disable_hooks,omitempty"`
-	// Version is the version of the release to deploy.
-	Version int32 `protobuf:"varint,4,opt,name=version" json:"version,omitempty"`
-	// Performs pods restart for resources if applicable
-	Recreate bool `protobuf:"varint,5,opt,name=recreate" json:"recreate,omitempty"`
-	// timeout specifies the max amount of time any kubernetes client command can run.
-	Timeout int64 `protobuf:"varint,6,opt,name=timeout" json:"timeout,omitempty"`
-	// wait, if true, will wait until all Pods, PVCs, and Services are in a ready state
-	// before marking the release as successful. It will wait for as long as timeout
-	Wait bool `protobuf:"varint,7,opt,name=wait" json:"wait,omitempty"`
-	// Force resource update through delete/recreate if needed.
-	Force bool `protobuf:"varint,8,opt,name=force" json:"force,omitempty"`
-}
-
-func (m *RollbackReleaseRequest) Reset()                    { *m = RollbackReleaseRequest{} }
-func (m *RollbackReleaseRequest) String() string            { return proto.CompactTextString(m) }
-func (*RollbackReleaseRequest) ProtoMessage()               {}
-func (*RollbackReleaseRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{9} }
-
-func (m *RollbackReleaseRequest) GetName() string {
+func (m *UpdateReleaseRequest) GetSkipHookWeights() []int32 {
 	if m != nil {
-		return m.Name
+		return m.SkipHookWeights
 	}
-	return ""
+	return nil
 }
 
-func (m *RollbackReleaseRequest) GetDryRun() bool {
+func (m *UpdateReleaseRequest) GetVerifyImages() bool {
 	if m != nil {
-		return m.DryRun
+		return m.VerifyImages
 	}
 	return false
 }
 
-func (m *RollbackReleaseRequest) GetDisableHooks() bool {
+func (m *UpdateReleaseRequest) GetAnnotations() map[string]string {
 	if m != nil {
-		return m.DisableHooks
+		return m.Annotations
 	}
-	return false
+	return nil
 }
 
-func (m *RollbackReleaseRequest) GetVersion() int32 {
+func (m *UpdateReleaseRequest) GetRequireApproval() bool {
 	if m != nil {
-		return m.Version
+		return m.RequireApproval
 	}
-	return 0
+	return false
 }
 
-func (m *RollbackReleaseRequest) GetRecreate() bool {
+func (m *UpdateReleaseRequest) GetApprovalTimeout() int64 {
 	if m != nil {
-		return m.Recreate
+		return m.ApprovalTimeout
 	}
-	return false
+	return 0
 }
 
-func (m *RollbackReleaseRequest) GetTimeout() int64 {
+func (m *UpdateReleaseRequest) GetGracePeriod() int64 {
 	if m != nil {
-		return m.Timeout
+		return m.GracePeriod
 	}
 	return 0
 }
 
-func (m *RollbackReleaseRequest) GetWait() bool {
+func (m *UpdateReleaseRequest) GetPropagationPolicy() string {
 	if m != nil {
-		return m.Wait
+		return m.PropagationPolicy
 	}
-	return false
+	return ""
 }
 
-func (m *RollbackReleaseRequest) GetForce() bool {
+func (m *UpdateReleaseRequest) GetRecreateOnSelectorChange() bool {
 	if m != nil {
-		return m.Force
+		return m.RecreateOnSelectorChange
 	}
 	return false
 }
 
-// RollbackReleaseResponse is the response to an update request.
-type RollbackReleaseResponse struct {
-	Release *hapi_release5.Release `protobuf:"bytes,1,opt,name=release" json:"release,omitempty"`
+func (m *UpdateReleaseRequest) GetProfile() string {
+	if m != nil {
+		return m.Profile
+	}
+	return ""
 }
 
-func (m *RollbackReleaseResponse) Reset()                    { *m = RollbackReleaseResponse{} }
-func (m *RollbackReleaseResponse) String() string            { return proto.CompactTextString(m) }
-func (*RollbackReleaseResponse) ProtoMessage()               {}
-func (*RollbackReleaseResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{10} }
-
-func (m *RollbackReleaseResponse) GetRelease() *hapi_release5.Release {
+func (m *UpdateReleaseRequest) GetAllowMissingProfile() bool {
 	if m != nil {
-		return m.Release
+		return m.AllowMissingProfile
 	}
-	return nil
+	return false
 }
 
-// InstallReleaseRequest is the request for an installation of a chart.
-type InstallReleaseRequest struct {
-	// Chart is the protobuf representation of a chart.
-	Chart *hapi_chart3.Chart `protobuf:"bytes,1,opt,name=chart" json:"chart,omitempty"`
-	// Values is a string containing (unparsed) YAML values.
-	Values *hapi_chart.Config `protobuf:"bytes,2,opt,name=values" json:"values,omitempty"`
-	// DryRun, if true, will run through the release logic, but neither create
-	// a release object nor deploy to Kubernetes. The release object returned
-	// in the response will be fake.
-	DryRun bool `protobuf:"varint,3,opt,name=dry_run,json=dryRun" json:"dry_run,omitempty"`
-	// Name is the candidate release name. This must be unique to the
-	// namespace, otherwise the server will return an error. If it is not
-	// supplied, the server will autogenerate one.
-	Name string `protobuf:"bytes,4,opt,name=name" json:"name,omitempty"`
-	// DisableHooks causes the server to skip running any hooks for the install.
-	DisableHooks bool `protobuf:"varint,5,opt,name=disable_hooks,json=disableHooks" json:"disable_hooks,omitempty"`
-	// Namepace is the kubernetes namespace of the release.
-	Namespace string `protobuf:"bytes,6,opt,name=namespace" json:"namespace,omitempty"`
-	// ReuseName requests that Tiller re-uses a name, instead of erroring out.
-	ReuseName bool `protobuf:"varint,7,opt,name=reuse_name,json=reuseName" json:"reuse_name,omitempty"`
-	// timeout specifies the max amount of time any kubernetes client command can run.
-	Timeout int64 `protobuf:"varint,8,opt,name=timeout" json:"timeout,omitempty"`
-	// wait, if true, will wait until all Pods, PVCs, and Services are in a ready state
-	// before marking the release as successful. It will wait for as long as timeout
-	Wait bool `protobuf:"varint,9,opt,name=wait" json:"wait,omitempty"`
+func (m *UpdateReleaseRequest) GetEnableHooks() bool {
+	if m != nil {
+		return m.EnableHooks
+	}
+	return false
 }
 
-func (m *InstallReleaseRequest) Reset()                    { *m = InstallReleaseRequest{} }
-func (m *InstallReleaseRequest) String() string            { return proto.CompactTextString(m) }
-func (*InstallReleaseRequest) ProtoMessage()               {}
-func (*InstallReleaseRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{11} }
-
-func (m *InstallReleaseRequest) GetChart() *hapi_chart3.Chart {
+func (m *UpdateReleaseRequest) GetCluster() string {
 	if m != nil {
-		return m.Chart
+		return m.Cluster
 	}
-	return nil
+	return ""
 }
 
-func (m *InstallReleaseRequest) GetValues() *hapi_chart.Config {
+func (m *UpdateReleaseRequest) GetOnFailure() string {
 	if m != nil {
-		return m.Values
+		return m.OnFailure
 	}
-	return nil
+	return ""
 }
 
-func (m *InstallReleaseRequest) GetDryRun() bool {
+func (m *UpdateReleaseRequest) GetVerifyReferences() bool {
 	if m != nil {
-		return m.DryRun
+		return m.VerifyReferences
 	}
 	return false
 }
 
-func (m *InstallReleaseRequest) GetName() string {
+func (m *UpdateReleaseRequest) GetTimeoutBudget() int64 {
 	if m != nil {
-		return m.Name
+		return m.TimeoutBudget
 	}
-	return ""
+	return 0
 }
 
-func (m *InstallReleaseRequest) GetDisableHooks() bool {
+func (m *UpdateReleaseRequest) GetAllowProtectedChanges() bool {
 	if m != nil {
-		return m.DisableHooks
+		return m.AllowProtectedChanges
 	}
 	return false
 }
 
-func (m *InstallReleaseRequest) GetNamespace() string {
+func (m *UpdateReleaseRequest) GetStrictValues() bool {
 	if m != nil {
-		return m.Namespace
+		return m.StrictValues
 	}
-	return ""
+	return false
 }
 
-func (m *InstallReleaseRequest) GetReuseName() bool {
+func (m *UpdateReleaseRequest) GetImpersonation() *hapi_release4.Impersonation {
 	if m != nil {
-		return m.ReuseName
+		return m.Impersonation
+	}
+	return nil
+}
+
+func (m *UpdateReleaseRequest) GetDeferNotes() bool {
+	if m != nil {
+		return m.DeferNotes
 	}
 	return false
 }
 
-func (m *InstallReleaseRequest) GetTimeout() int64 {
+func (m *UpdateReleaseRequest) GetFlags() map[string]bool {
 	if m != nil {
-		return m.Timeout
+		return m.Flags
 	}
-	return 0
+	return nil
 }
 
-func (m *InstallReleaseRequest) GetWait() bool {
+func (m *UpdateReleaseRequest) GetKeepRemoved() bool {
 	if m != nil {
-		return m.Wait
+		return m.KeepRemoved
 	}
 	return false
 }
 
-// InstallReleaseResponse is the response from a release installation.
-type InstallReleaseResponse struct {
-	Release *hapi_release5.Release `protobuf:"bytes,1,opt,name=release" json:"release,omitempty"`
+func (m *UpdateReleaseRequest) GetSkipHookLookup() bool {
+	if m != nil {
+		return m.SkipHookLookup
+	}
+	return false
 }
 
-func (m *InstallReleaseResponse) Reset()                    { *m = InstallReleaseResponse{} }
-func (m *InstallReleaseResponse) String() string            { return proto.CompactTextString(m) }
-func (*InstallReleaseResponse) ProtoMessage()               {}
-func (*InstallReleaseResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{12} }
+func (m *UpdateReleaseRequest) GetWaitLogBytes() int64 {
+	if m != nil {
+		return m.WaitLogBytes
+	}
+	return 0
+}
+
+// UpdateReleaseResponse is the response to an update request.
+type UpdateReleaseResponse struct {
+	Release *hapi_release6.Release `protobuf:"bytes,1,opt,name=release" json:"release,omitempty"`
+	// Hooks lists, in order, the hooks that the upgrade runs. It is only set
+	// for dry runs.
+	Hooks []*HookPreview `protobuf:"bytes,2,rep,name=hooks" json:"hooks,omitempty"`
+}
 
-func (m *InstallReleaseResponse) GetRelease() *hapi_release5.Release {
+func (m *UpdateReleaseResponse) Reset()                    { *m = UpdateReleaseResponse{} }
+func (m *UpdateReleaseResponse) String() string            { return proto.CompactTextString(m) }
+func (*UpdateReleaseResponse) ProtoMessage()               {}
+func (*UpdateReleaseResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{13} }
+
+func (m *UpdateReleaseResponse) GetRelease() *hapi_release6.Release {
 	if m != nil {
 		return m.Release
 	}
 	return nil
 }
 
-// UninstallReleaseRequest represents a request to uninstall a named release.
-type UninstallReleaseRequest struct {
-	// Name is the name of the release to delete.
+func (m *UpdateReleaseResponse) GetHooks() []*HookPreview {
+	if m != nil {
+		return m.Hooks
+	}
+	return nil
+}
+
+type RollbackReleaseRequest struct {
+	// The name of the release
 	Name string `protobuf:"bytes,1,opt,name=name" json:"name,omitempty"`
-	// DisableHooks causes the server to skip running any hooks for the uninstall.
-	DisableHooks bool `protobuf:"varint,2,opt,name=disable_hooks,json=disableHooks" json:"disable_hooks,omitempty"`
-	// Purge removes the release from the store and make its name free for later use.
-	Purge bool `protobuf:"varint,3,opt,name=purge" json:"purge,omitempty"`
+	// dry_run, if true, will run through the release logic but no create
+	DryRun bool `protobuf:"varint,2,opt,name=dry_run,json=dryRun" json:"dry_run,omitempty"`
+	// DisableHooks causes the server to skip running any hooks for the rollback
+	DisableHooks bool `protobuf:"varint,3,opt,name=disable_hooks,json=disableHooks" json:"disable_hooks,omitempty"`
+	// Version is the version of the release to deploy.
+	Version int32 `protobuf:"varint,4,opt,name=version" json:"version,omitempty"`
+	// Performs pods restart for resources if applicable
+	Recreate bool `protobuf:"varint,5,opt,name=recreate" json:"recreate,omitempty"`
 	// timeout specifies the max amount of time any kubernetes client command can run.
-	Timeout int64 `protobuf:"varint,4,opt,name=timeout" json:"timeout,omitempty"`
+	Timeout int64 `protobuf:"varint,6,opt,name=timeout" json:"timeout,omitempty"`
+	// wait, if true, will wait until all Pods, PVCs, and Services are in a ready state
+	// before marking the release as successful. It will wait for as long as timeout
+	Wait bool `protobuf:"varint,7,opt,name=wait" json:"wait,omitempty"`
+	// Force resource update through delete/recreate if needed.
+	Force bool `protobuf:"varint,8,opt,name=force" json:"force,omitempty"`
+	// Partial, if true, only reverts the resources that were not successfully
+	// applied by the current release. The current release must have failed
+	// and recorded per-resource status.
+	Partial bool `protobuf:"varint,9,opt,name=partial" json:"partial,omitempty"`
+	// SkipHooks lists the names of hooks that should not be run.
+	SkipHooks []string `protobuf:"bytes,10,rep,name=skip_hooks,json=skipHooks" json:"skip_hooks,omitempty"`
+	// SkipHookWeights lists the weights of hooks that should not be run.
+	SkipHookWeights []int32 `protobuf:"varint,11,rep,packed,name=skip_hook_weights,json=skipHookWeights" json:"skip_hook_weights,omitempty"`
+	// GracePeriod is the number of seconds that the pods deleted by recreate,
+	// force or the removal of resources get to shut down. Zero uses the
+	// termination grace period of each pod.
+	GracePeriod int64 `protobuf:"varint,12,opt,name=grace_period,json=gracePeriod" json:"grace_period,omitempty"`
+	// PropagationPolicy is the Kubernetes deletion propagation policy of the
+	// resources that the rollback deletes: "Foreground", "Background" or
+	// "Orphan". When it is empty, they are deleted as kubectl would delete them.
+	PropagationPolicy string `protobuf:"bytes,13,opt,name=propagation_policy,json=propagationPolicy" json:"propagation_policy,omitempty"`
+	// RecreateOnSelectorChange deletes and recreates the workloads that cannot
+	// be patched only because their label selector changed. They are deleted
+	// with the Orphan policy, so that the new workloads adopt their pods.
+	RecreateOnSelectorChange bool `protobuf:"varint,14,opt,name=recreate_on_selector_change,json=recreateOnSelectorChange" json:"recreate_on_selector_change,omitempty"`
+	// EnableHooks runs the hooks of the rollback even if Tiller skips them by
+	// default. DisableHooks takes precedence.
+	EnableHooks bool `protobuf:"varint,15,opt,name=enable_hooks,json=enableHooks" json:"enable_hooks,omitempty"`
+	// ReRender renders the chart of the target revision again, instead of
+	// reusing the manifests it was rendered to.
+	ReRender bool `protobuf:"varint,16,opt,name=re_render,json=reRender" json:"re_render,omitempty"`
+	// Values are merged over the values of the target revision before it is
+	// rendered again. They require re_render.
+	Values *hapi_chart.Config `protobuf:"bytes,17,opt,name=values" json:"values,omitempty"`
+	// SkipHookLookup keeps a dry run from querying the cluster for existing
+	// resources that the before-hook-creation delete policy of a hook would
+	// delete. See HookPreview.replaces_existing.
+	SkipHookLookup bool `protobuf:"varint,18,opt,name=skip_hook_lookup,json=skipHookLookup" json:"skip_hook_lookup,omitempty"`
+	// TimeoutBudget, if set, is how long, in seconds, the whole rollback may
+	// take. The pre- and post-rollback hooks and applying and waiting for the resources run
+	// one after the other, each with the time that is left, instead of with
+	// timeout each.
+	TimeoutBudget int64 `protobuf:"varint,19,opt,name=timeout_budget,json=timeoutBudget" json:"timeout_budget,omitempty"`
+	// AllowDestructiveHooks runs the hooks annotated with
+	// helm.sh/hook-destructive: "true", which a rollback skips otherwise.
+	AllowDestructiveHooks bool `protobuf:"varint,20,opt,name=allow_destructive_hooks,json=allowDestructiveHooks" json:"allow_destructive_hooks,omitempty"`
+	// Impersonation, if set, is the identity that Tiller acts as towards the
+	// Kubernetes API while rolling back the release, so that RBAC and audit logs
+	// apply to it instead of to Tiller. Tiller must be allowed to impersonate
+	// it. It is recorded in the info of the revision.
+	Impersonation *hapi_release4.Impersonation `protobuf:"bytes,21,opt,name=impersonation" json:"impersonation,omitempty"`
+	// DeployedAt, if set, targets the revision that was deployed at that time
+	// instead of Version: the last one deployed before it, which was
+	// superseded since.
+	DeployedAt *google_protobuf.Timestamp `protobuf:"bytes,22,opt,name=deployed_at,json=deployedAt" json:"deployed_at,omitempty"`
+	// FromSnapshot applies the snapshot of the target revision, as taken by
+	// SnapshotRelease, instead of its manifest. It cannot be combined with
+	// partial or re_render.
+	FromSnapshot bool `protobuf:"varint,23,opt,name=from_snapshot,json=fromSnapshot" json:"from_snapshot,omitempty"`
 }
 
-func (m *UninstallReleaseRequest) Reset()                    { *m = UninstallReleaseRequest{} }
-func (m *UninstallReleaseRequest) String() string            { return proto.CompactTextString(m) }
-func (*UninstallReleaseRequest) ProtoMessage()               {}
-func (*UninstallReleaseRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{13} }
+func (m *RollbackReleaseRequest) Reset()                    { *m = RollbackReleaseRequest{} }
+func (m *RollbackReleaseRequest) String() string            { return proto.CompactTextString(m) }
+func (*RollbackReleaseRequest) ProtoMessage()               {}
+func (*RollbackReleaseRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{14} }
 
-func (m *UninstallReleaseRequest) GetName() string {
+func (m *RollbackReleaseRequest) GetName() string {
 	if m != nil {
 		return m.Name
 	}
 	return ""
 }
 
-func (m *UninstallReleaseRequest) GetDisableHooks() bool {
+func (m *RollbackReleaseRequest) GetDryRun() bool {
+	if m != nil {
+		return m.DryRun
+	}
+	return false
+}
+
+func (m *RollbackReleaseRequest) GetDisableHooks() bool {
 	if m != nil {
 		return m.DisableHooks
 	}
 	return false
 }
 
-func (m *UninstallReleaseRequest) GetPurge() bool {
+func (m *RollbackReleaseRequest) GetVersion() int32 {
 	if m != nil {
-		return m.Purge
+		return m.Version
+	}
+	return 0
+}
+
+func (m *RollbackReleaseRequest) GetRecreate() bool {
+	if m != nil {
+		return m.Recreate
 	}
 	return false
 }
 
-func (m *UninstallReleaseRequest) GetTimeout() int64 {
+func (m *RollbackReleaseRequest) GetTimeout() int64 {
 	if m != nil {
 		return m.Timeout
 	}
 	return 0
 }
 
-// UninstallReleaseResponse represents a successful response to an uninstall request.
-type UninstallReleaseResponse struct {
-	// Release is the release that was marked deleted.
-	Release *hapi_release5.Release `protobuf:"bytes,1,opt,name=release" json:"release,omitempty"`
-	// Info is an uninstall message
-	Info string `protobuf:"bytes,2,opt,name=info" json:"info,omitempty"`
+func (m *RollbackReleaseRequest) GetWait() bool {
+	if m != nil {
+		return m.Wait
+	}
+	return false
 }
 
-func (m *UninstallReleaseResponse) Reset()                    { *m = UninstallReleaseResponse{} }
-func (m *UninstallReleaseResponse) String() string            { return proto.CompactTextString(m) }
-func (*UninstallReleaseResponse) ProtoMessage()               {}
-func (*UninstallReleaseResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{14} }
+func (m *RollbackReleaseRequest) GetForce() bool {
+	if m != nil {
+		return m.Force
+	}
+	return false
+}
 
-func (m *UninstallReleaseResponse) GetRelease() *hapi_release5.Release {
+func (m *RollbackReleaseRequest) GetPartial() bool {
 	if m != nil {
-		return m.Release
+		return m.Partial
+	}
+	return false
+}
+
+func (m *RollbackReleaseRequest) GetSkipHooks() []string {
+	if m != nil {
+		return m.SkipHooks
 	}
 	return nil
 }
 
-func (m *UninstallReleaseResponse) GetInfo() string {
+func (m *RollbackReleaseRequest) GetSkipHookWeights() []int32 {
 	if m != nil {
-		return m.Info
+		return m.SkipHookWeights
 	}
-	return ""
+	return nil
 }
 
-// GetVersionRequest requests for version information.
-type GetVersionRequest struct {
+func (m *RollbackReleaseRequest) GetGracePeriod() int64 {
+	if m != nil {
+		return m.GracePeriod
+	}
+	return 0
 }
 
-func (m *GetVersionRequest) Reset()                    { *m = GetVersionRequest{} }
-func (m *GetVersionRequest) String() string            { return proto.CompactTextString(m) }
-func (*GetVersionRequest) ProtoMessage()               {}
-func (*GetVersionRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{15} }
+func (m *RollbackReleaseRequest) GetPropagationPolicy() string {
+	if m != nil {
+		return m.PropagationPolicy
+	}
+	return ""
+}
 
-type GetVersionResponse struct {
-	Version *hapi_version.Version `protobuf:"bytes,1,opt,name=Version" json:"Version,omitempty"`
+func (m *RollbackReleaseRequest) GetRecreateOnSelectorChange() bool {
+	if m != nil {
+		return m.RecreateOnSelectorChange
+	}
+	return false
 }
 
-func (m *GetVersionResponse) Reset()                    { *m = GetVersionResponse{} }
-func (m *GetVersionResponse) String() string            { return proto.CompactTextString(m) }
-func (*GetVersionResponse) ProtoMessage()               {}
-func (*GetVersionResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{16} }
+func (m *RollbackReleaseRequest) GetEnableHooks() bool {
+	if m != nil {
+		return m.EnableHooks
+	}
+	return false
+}
 
-func (m *GetVersionResponse) GetVersion() *hapi_version.Version {
+func (m *RollbackReleaseRequest) GetReRender() bool {
 	if m != nil {
-		return m.Version
+		return m.ReRender
+	}
+	return false
+}
+
+func (m *RollbackReleaseRequest) GetValues() *hapi_chart.Config {
+	if m != nil {
+		return m.Values
 	}
 	return nil
 }
 
-// GetHistoryRequest requests a release's history.
-type GetHistoryRequest struct {
-	// The name of the release.
-	Name string `protobuf:"bytes,1,opt,name=name" json:"name,omitempty"`
-	// The maximum number of releases to include.
-	Max int32 `protobuf:"varint,2,opt,name=max" json:"max,omitempty"`
+func (m *RollbackReleaseRequest) GetSkipHookLookup() bool {
+	if m != nil {
+		return m.SkipHookLookup
+	}
+	return false
 }
 
-func (m *GetHistoryRequest) Reset()                    { *m = GetHistoryRequest{} }
-func (m *GetHistoryRequest) String() string            { return proto.CompactTextString(m) }
-func (*GetHistoryRequest) ProtoMessage()               {}
-func (*GetHistoryRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{17} }
+func (m *RollbackReleaseRequest) GetTimeoutBudget() int64 {
+	if m != nil {
+		return m.TimeoutBudget
+	}
+	return 0
+}
 
-func (m *GetHistoryRequest) GetName() string {
+func (m *RollbackReleaseRequest) GetAllowDestructiveHooks() bool {
 	if m != nil {
-		return m.Name
+		return m.AllowDestructiveHooks
 	}
-	return ""
+	return false
 }
 
-func (m *GetHistoryRequest) GetMax() int32 {
+func (m *RollbackReleaseRequest) GetImpersonation() *hapi_release4.Impersonation {
 	if m != nil {
-		return m.Max
+		return m.Impersonation
 	}
-	return 0
+	return nil
 }
 
-// GetHistoryResponse is received in response to a GetHistory rpc.
-type GetHistoryResponse struct {
-	Releases []*hapi_release5.Release `protobuf:"bytes,1,rep,name=releases" json:"releases,omitempty"`
+func (m *RollbackReleaseRequest) GetDeployedAt() *google_protobuf.Timestamp {
+	if m != nil {
+		return m.DeployedAt
+	}
+	return nil
 }
 
-func (m *GetHistoryResponse) Reset()                    { *m = GetHistoryResponse{} }
-func (m *GetHistoryResponse) String() string            { return proto.CompactTextString(m) }
-func (*GetHistoryResponse) ProtoMessage()               {}
-func (*GetHistoryResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{18} }
+func (m *RollbackReleaseRequest) GetFromSnapshot() bool {
+	if m != nil {
+		return m.FromSnapshot
+	}
+	return false
+}
 
-func (m *GetHistoryResponse) GetReleases() []*hapi_release5.Release {
+// RollbackReleaseResponse is the response to an update request.
+type RollbackReleaseResponse struct {
+	Release *hapi_release6.Release `protobuf:"bytes,1,opt,name=release" json:"release,omitempty"`
+	// Hooks lists, in order, the hooks that the rollback runs. It is only
+	// set for dry runs.
+	Hooks []*HookPreview `protobuf:"bytes,2,rep,name=hooks" json:"hooks,omitempty"`
+	// SkippedDestructiveHooks names the hooks that were, or for dry runs
+	// would be, skipped because they are annotated as destructive.
+	SkippedDestructiveHooks []string `protobuf:"bytes,3,rep,name=skipped_destructive_hooks,json=skippedDestructiveHooks" json:"skipped_destructive_hooks,omitempty"`
+}
+
+func (m *RollbackReleaseResponse) Reset()                    { *m = RollbackReleaseResponse{} }
+func (m *RollbackReleaseResponse) String() string            { return proto.CompactTextString(m) }
+func (*RollbackReleaseResponse) ProtoMessage()               {}
+func (*RollbackReleaseResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{15} }
+
+func (m *RollbackReleaseResponse) GetRelease() *hapi_release6.Release {
 	if m != nil {
-		return m.Releases
+		return m.Release
 	}
 	return nil
 }
 
-// TestReleaseRequest is a request to get the status of a release.
-type TestReleaseRequest struct {
-	// Name is the name of the release
-	Name string `protobuf:"bytes,1,opt,name=name" json:"name,omitempty"`
-	// timeout specifies the max amount of time any kubernetes client command can run.
-	Timeout int64 `protobuf:"varint,2,opt,name=timeout" json:"timeout,omitempty"`
-	// cleanup specifies whether or not to attempt pod deletion after test completes
-	Cleanup bool `protobuf:"varint,3,opt,name=cleanup" json:"cleanup,omitempty"`
+func (m *RollbackReleaseResponse) GetHooks() []*HookPreview {
+	if m != nil {
+		return m.Hooks
+	}
+	return nil
 }
 
-func (m *TestReleaseRequest) Reset()                    { *m = TestReleaseRequest{} }
-func (m *TestReleaseRequest) String() string            { return proto.CompactTextString(m) }
-func (*TestReleaseRequest) ProtoMessage()               {}
-func (*TestReleaseRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{19} }
+func (m *RollbackReleaseResponse) GetSkippedDestructiveHooks() []string {
+	if m != nil {
+		return m.SkippedDestructiveHooks
+	}
+	return nil
+}
 
-func (m *TestReleaseRequest) GetName() string {
+// HookPreview describes a hook that an operation would run.
+type HookPreview struct {
+	Name string `protobuf:"bytes,1,opt,name=name" json:"name,omitempty"`
+	// Kind is the Kubernetes kind.
+	Kind string `protobuf:"bytes,2,opt,name=kind" json:"kind,omitempty"`
+	// Path is the chart-relative path to the template.
+	Path string `protobuf:"bytes,3,opt,name=path" json:"path,omitempty"`
+	// Event is the hook event the hook would run for, e.g. "pre-rollback".
+	Event  string `protobuf:"bytes,4,opt,name=event" json:"event,omitempty"`
+	Weight int32  `protobuf:"varint,5,opt,name=weight" json:"weight,omitempty"`
+	// DeletePolicies lists the hook delete policies declared by the hook's
+	// helm.sh/hook-delete-policy annotation.
+	DeletePolicies []string `protobuf:"bytes,6,rep,name=delete_policies,json=deletePolicies" json:"delete_policies,omitempty"`
+	// Skipped is true if the request asked for the hook to be skipped, or
+	// the hook is destructive and the request did not allow those.
+	Skipped bool `protobuf:"varint,7,opt,name=skipped" json:"skipped,omitempty"`
+	// ReplacesExisting is true if the hook has the before-hook-creation delete
+	// policy and a resource of it already exists, which would be deleted
+	// before the hook is created. Finding out reads from the cluster, which
+	// the request can skip.
+	ReplacesExisting bool `protobuf:"varint,8,opt,name=replaces_existing,json=replacesExisting" json:"replaces_existing,omitempty"`
+	// Destructive is true if the hook is annotated with
+	// helm.sh/hook-destructive: "true".
+	Destructive bool `protobuf:"varint,9,opt,name=destructive" json:"destructive,omitempty"`
+}
+
+func (m *HookPreview) Reset()                    { *m = HookPreview{} }
+func (m *HookPreview) String() string            { return proto.CompactTextString(m) }
+func (*HookPreview) ProtoMessage()               {}
+func (*HookPreview) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{16} }
+
+func (m *HookPreview) GetName() string {
 	if m != nil {
 		return m.Name
 	}
 	return ""
 }
 
-func (m *TestReleaseRequest) GetTimeout() int64 {
+func (m *HookPreview) GetKind() string {
 	if m != nil {
-		return m.Timeout
+		return m.Kind
 	}
-	return 0
+	return ""
 }
 
-func (m *TestReleaseRequest) GetCleanup() bool {
+func (m *HookPreview) GetPath() string {
 	if m != nil {
-		return m.Cleanup
+		return m.Path
 	}
-	return false
+	return ""
 }
 
-// TestReleaseResponse represents a message from executing a test
-type TestReleaseResponse struct {
-	Msg    string                       `protobuf:"bytes,1,opt,name=msg" json:"msg,omitempty"`
-	Status hapi_release1.TestRun_Status `protobuf:"varint,2,opt,name=status,enum=hapi.release.TestRun_Status" json:"status,omitempty"`
+func (m *HookPreview) GetEvent() string {
+	if m != nil {
+		return m.Event
+	}
+	return ""
 }
 
-func (m *TestReleaseResponse) Reset()                    { *m = TestReleaseResponse{} }
-func (m *TestReleaseResponse) String() string            { return proto.CompactTextString(m) }
-func (*TestReleaseResponse) ProtoMessage()               {}
-func (*TestReleaseResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{20} }
+func (m *HookPreview) GetWeight() int32 {
+	if m != nil {
+		return m.Weight
+	}
+	return 0
+}
 
-func (m *TestReleaseResponse) GetMsg() string {
+func (m *HookPreview) GetDeletePolicies() []string {
 	if m != nil {
-		return m.Msg
+		return m.DeletePolicies
 	}
-	return ""
+	return nil
 }
 
-func (m *TestReleaseResponse) GetStatus() hapi_release1.TestRun_Status {
+func (m *HookPreview) GetSkipped() bool {
 	if m != nil {
-		return m.Status
+		return m.Skipped
 	}
-	return hapi_release1.TestRun_UNKNOWN
+	return false
 }
 
-func init() {
-	proto.RegisterType((*ListReleasesRequest)(nil), "hapi.services.tiller.ListReleasesRequest")
-	proto.RegisterType((*ListSort)(nil), "hapi.services.tiller.ListSort")
-	proto.RegisterType((*ListReleasesResponse)(nil), "hapi.services.tiller.ListReleasesResponse")
+func (m *HookPreview) GetReplacesExisting() bool {
+	if m != nil {
+		return m.ReplacesExisting
+	}
+	return false
+}
+
+func (m *HookPreview) GetDestructive() bool {
+	if m != nil {
+		return m.Destructive
+	}
+	return false
+}
+
+// InstallReleaseRequest is the request for an installation of a chart.
+type InstallReleaseRequest struct {
+	// Chart is the protobuf representation of a chart.
+	Chart *hapi_chart3.Chart `protobuf:"bytes,1,opt,name=chart" json:"chart,omitempty"`
+	// Values is a string containing (unparsed) YAML values.
+	Values *hapi_chart.Config `protobuf:"bytes,2,opt,name=values" json:"values,omitempty"`
+	// DryRun, if true, will run through the release logic, but neither create
+	// a release object nor deploy to Kubernetes. The release object returned
+	// in the response will be fake.
+	DryRun bool `protobuf:"varint,3,opt,name=dry_run,json=dryRun" json:"dry_run,omitempty"`
+	// Name is the candidate release name. This must be unique to the
+	// namespace, otherwise the server will return an error. If it is not
+	// supplied, the server will autogenerate one.
+	Name string `protobuf:"bytes,4,opt,name=name" json:"name,omitempty"`
+	// DisableHooks causes the server to skip running any hooks for the install.
+	DisableHooks bool `protobuf:"varint,5,opt,name=disable_hooks,json=disableHooks" json:"disable_hooks,omitempty"`
+	// Namepace is the kubernetes namespace of the release.
+	Namespace string `protobuf:"bytes,6,opt,name=namespace" json:"namespace,omitempty"`
+	// ReuseName requests that Tiller re-uses a name, instead of erroring out.
+	ReuseName bool `protobuf:"varint,7,opt,name=reuse_name,json=reuseName" json:"reuse_name,omitempty"`
+	// timeout specifies the max amount of time any kubernetes client command can run.
+	Timeout int64 `protobuf:"varint,8,opt,name=timeout" json:"timeout,omitempty"`
+	// wait, if true, will wait until all Pods, PVCs, and Services are in a ready state
+	// before marking the release as successful. It will wait for as long as timeout
+	Wait bool `protobuf:"varint,9,opt,name=wait" json:"wait,omitempty"`
+	// ServerDryRun, if true along with dry_run, sends the rendered resources
+	// through a server-side dry-run and returns them with server defaults applied.
+	ServerDryRun bool `protobuf:"varint,10,opt,name=server_dry_run,json=serverDryRun" json:"server_dry_run,omitempty"`
+	// SkipHooks lists the names of hooks that should not be run.
+	SkipHooks []string `protobuf:"bytes,11,rep,name=skip_hooks,json=skipHooks" json:"skip_hooks,omitempty"`
+	// SkipHookWeights lists the weights of hooks that should not be run.
+	SkipHookWeights []int32 `protobuf:"varint,12,rep,packed,name=skip_hook_weights,json=skipHookWeights" json:"skip_hook_weights,omitempty"`
+	// VerifyImages, if true, checks that the registries have every container
+	// image of the release before anything is installed. The image pull
+	// secrets of the release's pods are used to log in to the registries.
+	VerifyImages bool `protobuf:"varint,13,opt,name=verify_images,json=verifyImages" json:"verify_images,omitempty"`
+	// Annotations are recorded with the release; see
+	// hapi.release.Info.annotations.
+	Annotations map[string]string `protobuf:"bytes,14,rep,name=annotations" json:"annotations,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	// Profile names a values file of the chart, values-<profile>.yaml, whose
+	// values override the chart's defaults and are overridden by values.
+	Profile string `protobuf:"bytes,15,opt,name=profile" json:"profile,omitempty"`
+	// AllowMissingProfile installs the chart with its defaults if it has no
+	// values file for the profile, instead of failing.
+	AllowMissingProfile bool `protobuf:"varint,16,opt,name=allow_missing_profile,json=allowMissingProfile" json:"allow_missing_profile,omitempty"`
+	// EnableHooks runs the hooks of the install even if Tiller skips them by
+	// default. DisableHooks takes precedence.
+	EnableHooks bool `protobuf:"varint,17,opt,name=enable_hooks,json=enableHooks" json:"enable_hooks,omitempty"`
+	// TruncateName shortens the release name if the resources of the release
+	// would otherwise get names or labels too long for Kubernetes. The
+	// shortened name ends in a hash of the full name. Without it, such a
+	// release fails before anything is installed.
+	TruncateName bool `protobuf:"varint,18,opt,name=truncate_name,json=truncateName" json:"truncate_name,omitempty"`
+	// Cluster names the cluster, out of those Tiller is configured with, to
+	// install the release in. When it is empty, Tiller's own cluster is used.
+	// The release stays in that cluster for its lifetime.
+	Cluster string `protobuf:"bytes,19,opt,name=cluster" json:"cluster,omitempty"`
+	// VerifyReferences, if true, checks before anything is installed that the
+	// ServiceAccounts, Secrets and ConfigMaps that the release's pods refer to
+	// are part of the release or exist in the cluster.
+	VerifyReferences bool `protobuf:"varint,20,opt,name=verify_references,json=verifyReferences" json:"verify_references,omitempty"`
+	// TimeoutBudget, if set, is how long, in seconds, the whole install may
+	// take. The pre- and post-install hooks and applying and waiting for the resources run
+	// one after the other, each with the time that is left, instead of with
+	// timeout each.
+	TimeoutBudget int64 `protobuf:"varint,21,opt,name=timeout_budget,json=timeoutBudget" json:"timeout_budget,omitempty"`
+	// System marks the release as a system release, such as platform
+	// infrastructure, which listings leave out unless asked for. Later
+	// revisions keep the mark.
+	System bool `protobuf:"varint,22,opt,name=system" json:"system,omitempty"`
+	// StrictValues, if true, fails the install if values has keys that match
+	// nothing in the default values or values schemas of the chart and its
+	// subcharts, naming all of them.
+	StrictValues bool `protobuf:"varint,23,opt,name=strict_values,json=strictValues" json:"strict_values,omitempty"`
+	// Impersonation, if set, is the identity that Tiller acts as towards the
+	// Kubernetes API while installing the release, so that RBAC and audit logs
+	// apply to it instead of to Tiller. Tiller must be allowed to impersonate
+	// it. It is recorded in the info of the revision.
+	Impersonation *hapi_release4.Impersonation `protobuf:"bytes,24,opt,name=impersonation" json:"impersonation,omitempty"`
+	// DeferNotes, if true, renders the notes again once the resources are
+	// created and, with wait, ready, with .Live holding the live state of the
+	// release's resources. If they cannot be rendered again, the notes rendered
+	// before are kept.
+	DeferNotes bool `protobuf:"varint,25,opt,name=defer_notes,json=deferNotes" json:"defer_notes,omitempty"`
+	// Flags are feature flags exposed to templates as .Release.Flags, to turn
+	// template branches on and off for this operation only. They are not
+	// values: they are recorded in the info of the revision, and are not
+	// carried over to later upgrades.
+	Flags map[string]bool `protobuf:"bytes,26,rep,name=flags" json:"flags,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"varint,2,opt,name=value"`
+	// SkipHookLookup keeps a dry run from querying the cluster for existing
+	// resources that the before-hook-creation delete policy of a hook would
+	// delete. See HookPreview.replaces_existing.
+	SkipHookLookup bool `protobuf:"varint,27,opt,name=skip_hook_lookup,json=skipHookLookup" json:"skip_hook_lookup,omitempty"`
+	// OnFailure is what happens when some resources cannot be applied: with
+	// "keep", the default, the failed release records which resources were
+	// applied and can be resumed with ResumeRelease. With "revert", the
+	// resources it applied are deleted at once.
+	OnFailure string `protobuf:"bytes,28,opt,name=on_failure,json=onFailure" json:"on_failure,omitempty"`
+	// WaitLogBytes, if positive, makes the wait of the install stream the end
+	// of the logs of the containers that are not ready, in at most this many
+	// bytes at a time, to the clients that watch the release with
+	// WatchWaitLogs.
+	WaitLogBytes int64 `protobuf:"varint,29,opt,name=wait_log_bytes,json=waitLogBytes" json:"wait_log_bytes,omitempty"`
+}
+
+func (m *InstallReleaseRequest) Reset()                    { *m = InstallReleaseRequest{} }
+func (m *InstallReleaseRequest) String() string            { return proto.CompactTextString(m) }
+func (*InstallReleaseRequest) ProtoMessage()               {}
+func (*InstallReleaseRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{17} }
+
+func (m *InstallReleaseRequest) GetChart() *hapi_chart3.Chart {
+	if m != nil {
+		return m.Chart
+	}
+	return nil
+}
+
+func (m *InstallReleaseRequest) GetValues() *hapi_chart.Config {
+	if m != nil {
+		return m.Values
+	}
+	return nil
+}
+
+func (m *InstallReleaseRequest) GetDryRun() bool {
+	if m != nil {
+		return m.DryRun
+	}
+	return false
+}
+
+func (m *InstallReleaseRequest) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *InstallReleaseRequest) GetDisableHooks() bool {
+	if m != nil {
+		return m.DisableHooks
+	}
+	return false
+}
+
+func (m *InstallReleaseRequest) GetNamespace() string {
+	if m != nil {
+		return m.Namespace
+	}
+	return ""
+}
+
+func (m *InstallReleaseRequest) GetReuseName() bool {
+	if m != nil {
+		return m.ReuseName
+	}
+	return false
+}
+
+func (m *InstallReleaseRequest) GetTimeout() int64 {
+	if m != nil {
+		return m.Timeout
+	}
+	return 0
+}
+
+func (m *InstallReleaseRequest) GetWait() bool {
+	if m != nil {
+		return m.Wait
+	}
+	return false
+}
+
+func (m *InstallReleaseRequest) GetServerDryRun() bool {
+	if m != nil {
+		return m.ServerDryRun
+	}
+	return false
+}
+
+func (m *InstallReleaseRequest) GetSkipHooks() []string {
+	if m != nil {
+		return m.SkipHooks
+	}
+	return nil
+}
+
+func (m *InstallReleaseRequest) GetSkipHookWeights() []int32 {
+	if m != nil {
+		return m.SkipHookWeights
+	}
+	return nil
+}
+
+func (m *InstallReleaseRequest) GetVerifyImages() bool {
+	if m != nil {
+		return m.VerifyImages
+	}
+	return false
+}
+
+func (m *InstallReleaseRequest) GetAnnotations() map[string]string {
+	if m != nil {
+		return m.Annotations
+	}
+	return nil
+}
+
+func (m *InstallReleaseRequest) GetProfile() string {
+	if m != nil {
+		return m.Profile
+	}
+	return ""
+}
+
+func (m *InstallReleaseRequest) GetAllowMissingProfile() bool {
+	if m != nil {
+		return m.AllowMissingProfile
+	}
+	return false
+}
+
+func (m *InstallReleaseRequest) GetEnableHooks() bool {
+	if m != nil {
+		return m.EnableHooks
+	}
+	return false
+}
+
+func (m *InstallReleaseRequest) GetTruncateName() bool {
+	if m != nil {
+		return m.TruncateName
+	}
+	return false
+}
+
+func (m *InstallReleaseRequest) GetCluster() string {
+	if m != nil {
+		return m.Cluster
+	}
+	return ""
+}
+
+func (m *InstallReleaseRequest) GetVerifyReferences() bool {
+	if m != nil {
+		return m.VerifyReferences
+	}
+	return false
+}
+
+func (m *InstallReleaseRequest) GetTimeoutBudget() int64 {
+	if m != nil {
+		return m.TimeoutBudget
+	}
+	return 0
+}
+
+func (m *InstallReleaseRequest) GetSystem() bool {
+	if m != nil {
+		return m.System
+	}
+	return false
+}
+
+func (m *InstallReleaseRequest) GetStrictValues() bool {
+	if m != nil {
+		return m.StrictValues
+	}
+	return false
+}
+
+func (m *InstallReleaseRequest) GetImpersonation() *hapi_release4.Impersonation {
+	if m != nil {
+		return m.Impersonation
+	}
+	return nil
+}
+
+func (m *InstallReleaseRequest) GetDeferNotes() bool {
+	if m != nil {
+		return m.DeferNotes
+	}
+	return false
+}
+
+func (m *InstallReleaseRequest) GetFlags() map[string]bool {
+	if m != nil {
+		return m.Flags
+	}
+	return nil
+}
+
+func (m *InstallReleaseRequest) GetSkipHookLookup() bool {
+	if m != nil {
+		return m.SkipHookLookup
+	}
+	return false
+}
+
+func (m *InstallReleaseRequest) GetOnFailure() string {
+	if m != nil {
+		return m.OnFailure
+	}
+	return ""
+}
+
+func (m *InstallReleaseRequest) GetWaitLogBytes() int64 {
+	if m != nil {
+		return m.WaitLogBytes
+	}
+	return 0
+}
+
+// InstallReleaseResponse is the response from a release installation.
+type InstallReleaseResponse struct {
+	Release *hapi_release6.Release `protobuf:"bytes,1,opt,name=release" json:"release,omitempty"`
+	// Hooks lists, in order, the hooks that the install runs. It is only set
+	// for dry runs.
+	Hooks []*HookPreview `protobuf:"bytes,2,rep,name=hooks" json:"hooks,omitempty"`
+}
+
+func (m *InstallReleaseResponse) Reset()                    { *m = InstallReleaseResponse{} }
+func (m *InstallReleaseResponse) String() string            { return proto.CompactTextString(m) }
+func (*InstallReleaseResponse) ProtoMessage()               {}
+func (*InstallReleaseResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{18} }
+
+func (m *InstallReleaseResponse) GetRelease() *hapi_release6.Release {
+	if m != nil {
+		return m.Release
+	}
+	return nil
+}
+
+func (m *InstallReleaseResponse) GetHooks() []*HookPreview {
+	if m != nil {
+		return m.Hooks
+	}
+	return nil
+}
+
+// UninstallReleaseRequest represents a request to uninstall a named release.
+type UninstallReleaseRequest struct {
+	// Name is the name of the release to delete.
+	Name string `protobuf:"bytes,1,opt,name=name" json:"name,omitempty"`
+	// DisableHooks causes the server to skip running any hooks for the uninstall.
+	DisableHooks bool `protobuf:"varint,2,opt,name=disable_hooks,json=disableHooks" json:"disable_hooks,omitempty"`
+	// Purge removes the release from the store and make its name free for later use.
+	Purge bool `protobuf:"varint,3,opt,name=purge" json:"purge,omitempty"`
+	// timeout specifies the max amount of time any kubernetes client command can run.
+	Timeout int64 `protobuf:"varint,4,opt,name=timeout" json:"timeout,omitempty"`
+	// SkipHooks lists the names of hooks that should not be run.
+	SkipHooks []string `protobuf:"bytes,5,rep,name=skip_hooks,json=skipHooks" json:"skip_hooks,omitempty"`
+	// SkipHookWeights lists the weights of hooks that should not be run.
+	SkipHookWeights []int32 `protobuf:"varint,6,rep,packed,name=skip_hook_weights,json=skipHookWeights" json:"skip_hook_weights,omitempty"`
+	// PropagationPolicy is the Kubernetes deletion propagation policy used for
+	// the release's resources: "Foreground", "Background" or "Orphan". When it
+	// is empty, Foreground is used if wait is set, and otherwise resources
+	// are deleted as kubectl would delete them.
+	PropagationPolicy string `protobuf:"bytes,7,opt,name=propagation_policy,json=propagationPolicy" json:"propagation_policy,omitempty"`
+	// wait, if true, will wait until the release's resources are gone before
+	// marking the release as deleted. It will wait for as long as timeout.
+	Wait bool `protobuf:"varint,8,opt,name=wait" json:"wait,omitempty"`
+	// EnableHooks runs the hooks of the uninstall even if Tiller skips them by
+	// default. DisableHooks takes precedence.
+	EnableHooks bool `protobuf:"varint,9,opt,name=enable_hooks,json=enableHooks" json:"enable_hooks,omitempty"`
+	// Impersonation, if set, is the identity that Tiller acts as towards the
+	// Kubernetes API while deleting the release, so that RBAC and audit logs
+	// apply to it instead of to Tiller. Tiller must be allowed to impersonate
+	// it. It is recorded in the info of the revision.
+	Impersonation *hapi_release4.Impersonation `protobuf:"bytes,10,opt,name=impersonation" json:"impersonation,omitempty"`
+	// DeleteHookResources also deletes the resources that the hooks of every
+	// revision of the release created and left behind, such as completed
+	// Jobs. Resources with the keep resource policy are not deleted.
+	DeleteHookResources bool `protobuf:"varint,11,opt,name=delete_hook_resources,json=deleteHookResources" json:"delete_hook_resources,omitempty"`
+}
+
+func (m *UninstallReleaseRequest) Reset()                    { *m = UninstallReleaseRequest{} }
+func (m *UninstallReleaseRequest) String() string            { return proto.CompactTextString(m) }
+func (*UninstallReleaseRequest) ProtoMessage()               {}
+func (*UninstallReleaseRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{19} }
+
+func (m *UninstallReleaseRequest) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *UninstallReleaseRequest) GetDisableHooks() bool {
+	if m != nil {
+		return m.DisableHooks
+	}
+	return false
+}
+
+func (m *UninstallReleaseRequest) GetPurge() bool {
+	if m != nil {
+		return m.Purge
+	}
+	return false
+}
+
+func (m *UninstallReleaseRequest) GetTimeout() int64 {
+	if m != nil {
+		return m.Timeout
+	}
+	return 0
+}
+
+func (m *UninstallReleaseRequest) GetSkipHooks() []string {
+	if m != nil {
+		return m.SkipHooks
+	}
+	return nil
+}
+
+func (m *UninstallReleaseRequest) GetSkipHookWeights() []int32 {
+	if m != nil {
+		return m.SkipHookWeights
+	}
+	return nil
+}
+
+func (m *UninstallReleaseRequest) GetPropagationPolicy() string {
+	if m != nil {
+		return m.PropagationPolicy
+	}
+	return ""
+}
+
+func (m *UninstallReleaseRequest) GetWait() bool {
+	if m != nil {
+		return m.Wait
+	}
+	return false
+}
+
+func (m *UninstallReleaseRequest) GetEnableHooks() bool {
+	if m != nil {
+		return m.EnableHooks
+	}
+	return false
+}
+
+func (m *UninstallReleaseRequest) GetImpersonation() *hapi_release4.Impersonation {
+	if m != nil {
+		return m.Impersonation
+	}
+	return nil
+}
+
+func (m *UninstallReleaseRequest) GetDeleteHookResources() bool {
+	if m != nil {
+		return m.DeleteHookResources
+	}
+	return false
+}
+
+// UninstallReleaseResponse represents a successful response to an uninstall request.
+type UninstallReleaseResponse struct {
+	// Release is the release that was marked deleted.
+	Release *hapi_release6.Release `protobuf:"bytes,1,opt,name=release" json:"release,omitempty"`
+	// Info is an uninstall message
+	Info string `protobuf:"bytes,2,opt,name=info" json:"info,omitempty"`
+}
+
+func (m *UninstallReleaseResponse) Reset()                    { *m = UninstallReleaseResponse{} }
+func (m *UninstallReleaseResponse) String() string            { return proto.CompactTextString(m) }
+func (*UninstallReleaseResponse) ProtoMessage()               {}
+func (*UninstallReleaseResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{20} }
+
+func (m *UninstallReleaseResponse) GetRelease() *hapi_release6.Release {
+	if m != nil {
+		return m.Release
+	}
+	return nil
+}
+
+func (m *UninstallReleaseResponse) GetInfo() string {
+	if m != nil {
+		return m.Info
+	}
+	return ""
+}
+
+// GetVersionRequest requests for version information.
+type GetVersionRequest struct {
+}
+
+func (m *GetVersionRequest) Reset()                    { *m = GetVersionRequest{} }
+func (m *GetVersionRequest) String() string            { return proto.CompactTextString(m) }
+func (*GetVersionRequest) ProtoMessage()               {}
+func (*GetVersionRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{21} }
+
+type GetVersionResponse struct {
+	Version *hapi_version.Version `protobuf:"bytes,1,opt,name=Version" json:"Version,omitempty"`
+	// Features are the optional features that the server supports as it is
+	// configured and with the cluster it manages, e.g. "server-dry-run". See
+	// pkg/version for their names.
+	Features []string `protobuf:"bytes,2,rep,name=features" json:"features,omitempty"`
+	// StorageDriver is the name of the driver storing release records, e.g.
+	// "ConfigMap". Servers that report their features always set it.
+	StorageDriver string `protobuf:"bytes,3,opt,name=storage_driver,json=storageDriver" json:"storage_driver,omitempty"`
+}
+
+func (m *GetVersionResponse) Reset()                    { *m = GetVersionResponse{} }
+func (m *GetVersionResponse) String() string            { return proto.CompactTextString(m) }
+func (*GetVersionResponse) ProtoMessage()               {}
+func (*GetVersionResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{22} }
+
+func (m *GetVersionResponse) GetVersion() *hapi_version.Version {
+	if m != nil {
+		return m.Version
+	}
+	return nil
+}
+
+func (m *GetVersionResponse) GetFeatures() []string {
+	if m != nil {
+		return m.Features
+	}
+	return nil
+}
+
+func (m *GetVersionResponse) GetStorageDriver() string {
+	if m != nil {
+		return m.StorageDriver
+	}
+	return ""
+}
+
+// GetHistoryRequest requests a release's history.
+type GetHistoryRequest struct {
+	// The name of the release.
+	Name string `protobuf:"bytes,1,opt,name=name" json:"name,omitempty"`
+	// The maximum number of releases to include.
+	Max int32 `protobuf:"varint,2,opt,name=max" json:"max,omitempty"`
+}
+
+func (m *GetHistoryRequest) Reset()                    { *m = GetHistoryRequest{} }
+func (m *GetHistoryRequest) String() string            { return proto.CompactTextString(m) }
+func (*GetHistoryRequest) ProtoMessage()               {}
+func (*GetHistoryRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{23} }
+
+func (m *GetHistoryRequest) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *GetHistoryRequest) GetMax() int32 {
+	if m != nil {
+		return m.Max
+	}
+	return 0
+}
+
+// GetHistoryResponse is received in response to a GetHistory rpc.
+type GetHistoryResponse struct {
+	Releases []*hapi_release6.Release `protobuf:"bytes,1,rep,name=releases" json:"releases,omitempty"`
+}
+
+func (m *GetHistoryResponse) Reset()                    { *m = GetHistoryResponse{} }
+func (m *GetHistoryResponse) String() string            { return proto.CompactTextString(m) }
+func (*GetHistoryResponse) ProtoMessage()               {}
+func (*GetHistoryResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{24} }
+
+func (m *GetHistoryResponse) GetReleases() []*hapi_release6.Release {
+	if m != nil {
+		return m.Releases
+	}
+	return nil
+}
+
+// TestReleaseRequest is a request to get the status of a release.
+type TestReleaseRequest struct {
+	// Name is the name of the release
+	Name string `protobuf:"bytes,1,opt,name=name" json:"name,omitempty"`
+	// timeout specifies the max amount of time any kubernetes client command can run.
+	Timeout int64 `protobuf:"varint,2,opt,name=timeout" json:"timeout,omitempty"`
+	// cleanup specifies whether or not to attempt pod deletion after test completes
+	Cleanup bool `protobuf:"varint,3,opt,name=cleanup" json:"cleanup,omitempty"`
+}
+
+func (m *TestReleaseRequest) Reset()                    { *m = TestReleaseRequest{} }
+func (m *TestReleaseRequest) String() string            { return proto.CompactTextString(m) }
+func (*TestReleaseRequest) ProtoMessage()               {}
+func (*TestReleaseRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{25} }
+
+func (m *TestReleaseRequest) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *TestReleaseRequest) GetTimeout() int64 {
+	if m != nil {
+		return m.Timeout
+	}
+	return 0
+}
+
+func (m *TestReleaseRequest) GetCleanup() bool {
+	if m != nil {
+		return m.Cleanup
+	}
+	return false
+}
+
+// TestReleaseResponse represents a message from executing a test
+type TestReleaseResponse struct {
+	Msg    string                       `protobuf:"bytes,1,opt,name=msg" json:"msg,omitempty"`
+	Status hapi_release1.TestRun_Status `protobuf:"varint,2,opt,name=status,enum=hapi.release.TestRun_Status" json:"status,omitempty"`
+}
+
+func (m *TestReleaseResponse) Reset()                    { *m = TestReleaseResponse{} }
+func (m *TestReleaseResponse) String() string            { return proto.CompactTextString(m) }
+func (*TestReleaseResponse) ProtoMessage()               {}
+func (*TestReleaseResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{26} }
+
+func (m *TestReleaseResponse) GetMsg() string {
+	if m != nil {
+		return m.Msg
+	}
+	return ""
+}
+
+func (m *TestReleaseResponse) GetStatus() hapi_release1.TestRun_Status {
+	if m != nil {
+		return m.Status
+	}
+	return hapi_release1.TestRun_UNKNOWN
+}
+
+// GetHealthRequest requests the health of the server.
+type GetHealthRequest struct {
+}
+
+func (m *GetHealthRequest) Reset()                    { *m = GetHealthRequest{} }
+func (m *GetHealthRequest) String() string            { return proto.CompactTextString(m) }
+func (*GetHealthRequest) ProtoMessage()               {}
+func (*GetHealthRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{27} }
+
+// DependencyHealth is the result of checking a single dependency.
+type DependencyHealth struct {
+	// Name identifies the dependency, e.g. "storage".
+	Name   string                  `protobuf:"bytes,1,opt,name=name" json:"name,omitempty"`
+	Status DependencyHealth_Status `protobuf:"varint,2,opt,name=status,enum=hapi.services.tiller.DependencyHealth_Status" json:"status,omitempty"`
+	// LatencyMs is how long the check took, in milliseconds.
+	LatencyMs int64 `protobuf:"varint,3,opt,name=latency_ms,json=latencyMs" json:"latency_ms,omitempty"`
+	// Error is the reason the dependency is degraded.
+	Error string `protobuf:"bytes,4,opt,name=error" json:"error,omitempty"`
+}
+
+func (m *DependencyHealth) Reset()                    { *m = DependencyHealth{} }
+func (m *DependencyHealth) String() string            { return proto.CompactTextString(m) }
+func (*DependencyHealth) ProtoMessage()               {}
+func (*DependencyHealth) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{28} }
+
+func (m *DependencyHealth) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *DependencyHealth) GetStatus() DependencyHealth_Status {
+	if m != nil {
+		return m.Status
+	}
+	return DependencyHealth_UNKNOWN
+}
+
+func (m *DependencyHealth) GetLatencyMs() int64 {
+	if m != nil {
+		return m.LatencyMs
+	}
+	return 0
+}
+
+func (m *DependencyHealth) GetError() string {
+	if m != nil {
+		return m.Error
+	}
+	return ""
+}
+
+// GetHealthResponse is received in response to a GetHealth rpc.
+type GetHealthResponse struct {
+	// Status is OK if every dependency is OK, and DEGRADED otherwise.
+	Status       DependencyHealth_Status `protobuf:"varint,1,opt,name=status,enum=hapi.services.tiller.DependencyHealth_Status" json:"status,omitempty"`
+	Dependencies []*DependencyHealth     `protobuf:"bytes,2,rep,name=dependencies" json:"dependencies,omitempty"`
+}
+
+func (m *GetHealthResponse) Reset()                    { *m = GetHealthResponse{} }
+func (m *GetHealthResponse) String() string            { return proto.CompactTextString(m) }
+func (*GetHealthResponse) ProtoMessage()               {}
+func (*GetHealthResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{29} }
+
+func (m *GetHealthResponse) GetStatus() DependencyHealth_Status {
+	if m != nil {
+		return m.Status
+	}
+	return DependencyHealth_UNKNOWN
+}
+
+func (m *GetHealthResponse) GetDependencies() []*DependencyHealth {
+	if m != nil {
+		return m.Dependencies
+	}
+	return nil
+}
+
+// BatchInstallRequest is a request to install several releases as a unit.
+//
+// The releases are installed one at a time, in the order they are listed,
+// each as InstallRelease would install it. If one fails, it and every release
+// installed before it are uninstalled and purged, in reverse order, and the
+// rest are not attempted. A release that cannot be fully uninstalled is left
+// marked as deleted, rather than purged.
+//
+// The whole batch is validated before anything is installed. Every entry must
+// have a chart, names must not repeat, and dry_run and reuse_name are not
+// supported.
+type BatchInstallRequest struct {
+	Releases []*InstallReleaseRequest `protobuf:"bytes,1,rep,name=releases" json:"releases,omitempty"`
+}
+
+func (m *BatchInstallRequest) Reset()                    { *m = BatchInstallRequest{} }
+func (m *BatchInstallRequest) String() string            { return proto.CompactTextString(m) }
+func (*BatchInstallRequest) ProtoMessage()               {}
+func (*BatchInstallRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{30} }
+
+func (m *BatchInstallRequest) GetReleases() []*InstallReleaseRequest {
+	if m != nil {
+		return m.Releases
+	}
+	return nil
+}
+
+// BatchInstallResult is the outcome of one entry of a batch install.
+type BatchInstallResult struct {
+	// Release is the release as it was installed. It is unset for SKIPPED
+	// entries and for entries that failed before a release was built.
+	Release *hapi_release6.Release     `protobuf:"bytes,1,opt,name=release" json:"release,omitempty"`
+	Outcome BatchInstallResult_Outcome `protobuf:"varint,2,opt,name=outcome,enum=hapi.services.tiller.BatchInstallResult_Outcome" json:"outcome,omitempty"`
+	// Error is the reason the entry was FAILED or ROLLBACK_FAILED.
+	Error string `protobuf:"bytes,3,opt,name=error" json:"error,omitempty"`
+}
+
+func (m *BatchInstallResult) Reset()                    { *m = BatchInstallResult{} }
+func (m *BatchInstallResult) String() string            { return proto.CompactTextString(m) }
+func (*BatchInstallResult) ProtoMessage()               {}
+func (*BatchInstallResult) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{31} }
+
+func (m *BatchInstallResult) GetRelease() *hapi_release6.Release {
+	if m != nil {
+		return m.Release
+	}
+	return nil
+}
+
+func (m *BatchInstallResult) GetOutcome() BatchInstallResult_Outcome {
+	if m != nil {
+		return m.Outcome
+	}
+	return BatchInstallResult_SKIPPED
+}
+
+func (m *BatchInstallResult) GetError() string {
+	if m != nil {
+		return m.Error
+	}
+	return ""
+}
+
+// BatchInstallResponse has a result for each entry of the request, in the
+// same order.
+type BatchInstallResponse struct {
+	Results []*BatchInstallResult `protobuf:"bytes,1,rep,name=results" json:"results,omitempty"`
+}
+
+func (m *BatchInstallResponse) Reset()                    { *m = BatchInstallResponse{} }
+func (m *BatchInstallResponse) String() string            { return proto.CompactTextString(m) }
+func (*BatchInstallResponse) ProtoMessage()               {}
+func (*BatchInstallResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{32} }
+
+func (m *BatchInstallResponse) GetResults() []*BatchInstallResult {
+	if m != nil {
+		return m.Results
+	}
+	return nil
+}
+
+// RestoreReleaseRequest re-installs a deleted release, using the manifest and
+// configuration of its last revision.
+type RestoreReleaseRequest struct {
+	// The name of the release
+	Name string `protobuf:"bytes,1,opt,name=name" json:"name,omitempty"`
+	// disable_hooks causes the server to skip running any hooks for the restore.
+	DisableHooks bool `protobuf:"varint,2,opt,name=disable_hooks,json=disableHooks" json:"disable_hooks,omitempty"`
+	// timeout specifies the max amount of time any kubernetes client command can run.
+	Timeout int64 `protobuf:"varint,3,opt,name=timeout" json:"timeout,omitempty"`
+	// wait, if true, will wait until all Pods, PVCs, and Services are in a ready state
+	// before marking the release as successful. It will wait for as long as timeout
+	Wait bool `protobuf:"varint,4,opt,name=wait" json:"wait,omitempty"`
+}
+
+func (m *RestoreReleaseRequest) Reset()                    { *m = RestoreReleaseRequest{} }
+func (m *RestoreReleaseRequest) String() string            { return proto.CompactTextString(m) }
+func (*RestoreReleaseRequest) ProtoMessage()               {}
+func (*RestoreReleaseRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{33} }
+
+func (m *RestoreReleaseRequest) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *RestoreReleaseRequest) GetDisableHooks() bool {
+	if m != nil {
+		return m.DisableHooks
+	}
+	return false
+}
+
+func (m *RestoreReleaseRequest) GetTimeout() int64 {
+	if m != nil {
+		return m.Timeout
+	}
+	return 0
+}
+
+func (m *RestoreReleaseRequest) GetWait() bool {
+	if m != nil {
+		return m.Wait
+	}
+	return false
+}
+
+// RestoreReleaseResponse is the response to a restore request.
+type RestoreReleaseResponse struct {
+	Release *hapi_release6.Release `protobuf:"bytes,1,opt,name=release" json:"release,omitempty"`
+}
+
+func (m *RestoreReleaseResponse) Reset()                    { *m = RestoreReleaseResponse{} }
+func (m *RestoreReleaseResponse) String() string            { return proto.CompactTextString(m) }
+func (*RestoreReleaseResponse) ProtoMessage()               {}
+func (*RestoreReleaseResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{34} }
+
+func (m *RestoreReleaseResponse) GetRelease() *hapi_release6.Release {
+	if m != nil {
+		return m.Release
+	}
+	return nil
+}
+
+// InspectChartRequest asks for the details of a chart. The chart is sent
+// either loaded or as a packaged archive. Tiller does not read chart
+// repositories, so clients fetch charts from a repository before inspecting
+// them.
+type InspectChartRequest struct {
+	Chart *hapi_chart3.Chart `protobuf:"bytes,1,opt,name=chart" json:"chart,omitempty"`
+	// chart_archive is a packaged chart (a gzipped tarball). It is used if
+	// chart is not set.
+	ChartArchive []byte `protobuf:"bytes,2,opt,name=chart_archive,json=chartArchive,proto3" json:"chart_archive,omitempty"`
+}
+
+func (m *InspectChartRequest) Reset()                    { *m = InspectChartRequest{} }
+func (m *InspectChartRequest) String() string            { return proto.CompactTextString(m) }
+func (*InspectChartRequest) ProtoMessage()               {}
+func (*InspectChartRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{35} }
+
+func (m *InspectChartRequest) GetChart() *hapi_chart3.Chart {
+	if m != nil {
+		return m.Chart
+	}
+	return nil
+}
+
+func (m *InspectChartRequest) GetChartArchive() []byte {
+	if m != nil {
+		return m.ChartArchive
+	}
+	return nil
+}
+
+// ChartDependency is a dependency declared in a chart's requirements.yaml.
+type ChartDependency struct {
+	Name       string   `protobuf:"bytes,1,opt,name=name" json:"name,omitempty"`
+	Version    string   `protobuf:"bytes,2,opt,name=version" json:"version,omitempty"`
+	Repository string   `protobuf:"bytes,3,opt,name=repository" json:"repository,omitempty"`
+	Condition  string   `protobuf:"bytes,4,opt,name=condition" json:"condition,omitempty"`
+	Tags       []string `protobuf:"bytes,5,rep,name=tags" json:"tags,omitempty"`
+}
+
+func (m *ChartDependency) Reset()                    { *m = ChartDependency{} }
+func (m *ChartDependency) String() string            { return proto.CompactTextString(m) }
+func (*ChartDependency) ProtoMessage()               {}
+func (*ChartDependency) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{36} }
+
+func (m *ChartDependency) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *ChartDependency) GetVersion() string {
+	if m != nil {
+		return m.Version
+	}
+	return ""
+}
+
+func (m *ChartDependency) GetRepository() string {
+	if m != nil {
+		return m.Repository
+	}
+	return ""
+}
+
+func (m *ChartDependency) GetCondition() string {
+	if m != nil {
+		return m.Condition
+	}
+	return ""
+}
+
+func (m *ChartDependency) GetTags() []string {
+	if m != nil {
+		return m.Tags
+	}
+	return nil
+}
+
+// InspectChartResponse describes a chart.
+type InspectChartResponse struct {
+	// metadata is the content of the chart's Chart.yaml.
+	Metadata     *hapi_chart1.Metadata `protobuf:"bytes,1,opt,name=metadata" json:"metadata,omitempty"`
+	Dependencies []*ChartDependency    `protobuf:"bytes,2,rep,name=dependencies" json:"dependencies,omitempty"`
+	// values are the chart's default values, as YAML.
+	Values string `protobuf:"bytes,3,opt,name=values" json:"values,omitempty"`
+	// readme is the chart's README, or empty if it has none.
+	Readme string `protobuf:"bytes,4,opt,name=readme" json:"readme,omitempty"`
+}
+
+func (m *InspectChartResponse) Reset()                    { *m = InspectChartResponse{} }
+func (m *InspectChartResponse) String() string            { return proto.CompactTextString(m) }
+func (*InspectChartResponse) ProtoMessage()               {}
+func (*InspectChartResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{37} }
+
+func (m *InspectChartResponse) GetMetadata() *hapi_chart1.Metadata {
+	if m != nil {
+		return m.Metadata
+	}
+	return nil
+}
+
+func (m *InspectChartResponse) GetDependencies() []*ChartDependency {
+	if m != nil {
+		return m.Dependencies
+	}
+	return nil
+}
+
+func (m *InspectChartResponse) GetValues() string {
+	if m != nil {
+		return m.Values
+	}
+	return ""
+}
+
+func (m *InspectChartResponse) GetReadme() string {
+	if m != nil {
+		return m.Readme
+	}
+	return ""
+}
+
+// AnnotateReleaseRequest changes the annotations of a revision of a release.
+type AnnotateReleaseRequest struct {
+	// The name of the release
+	Name string `protobuf:"bytes,1,opt,name=name" json:"name,omitempty"`
+	// Version is the revision to annotate. The latest revision is annotated
+	// if it is 0.
+	Version int32 `protobuf:"varint,2,opt,name=version" json:"version,omitempty"`
+	// Annotations are added to the revision, replacing the values of
+	// existing keys.
+	Annotations map[string]string `protobuf:"bytes,3,rep,name=annotations" json:"annotations,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	// Remove lists the keys of annotations to remove from the revision.
+	Remove []string `protobuf:"bytes,4,rep,name=remove" json:"remove,omitempty"`
+}
+
+func (m *AnnotateReleaseRequest) Reset()                    { *m = AnnotateReleaseRequest{} }
+func (m *AnnotateReleaseRequest) String() string            { return proto.CompactTextString(m) }
+func (*AnnotateReleaseRequest) ProtoMessage()               {}
+func (*AnnotateReleaseRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{38} }
+
+func (m *AnnotateReleaseRequest) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *AnnotateReleaseRequest) GetVersion() int32 {
+	if m != nil {
+		return m.Version
+	}
+	return 0
+}
+
+func (m *AnnotateReleaseRequest) GetAnnotations() map[string]string {
+	if m != nil {
+		return m.Annotations
+	}
+	return nil
+}
+
+func (m *AnnotateReleaseRequest) GetRemove() []string {
+	if m != nil {
+		return m.Remove
+	}
+	return nil
+}
+
+// AnnotateReleaseResponse is the response to an annotate request.
+type AnnotateReleaseResponse struct {
+	Release *hapi_release6.Release `protobuf:"bytes,1,opt,name=release" json:"release,omitempty"`
+}
+
+func (m *AnnotateReleaseResponse) Reset()                    { *m = AnnotateReleaseResponse{} }
+func (m *AnnotateReleaseResponse) String() string            { return proto.CompactTextString(m) }
+func (*AnnotateReleaseResponse) ProtoMessage()               {}
+func (*AnnotateReleaseResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{39} }
+
+func (m *AnnotateReleaseResponse) GetRelease() *hapi_release6.Release {
+	if m != nil {
+		return m.Release
+	}
+	return nil
+}
+
+// RestartReleaseRequest restarts the Deployments, StatefulSets and DaemonSets
+// of a deployed release.
+type RestartReleaseRequest struct {
+	// The name of the release
+	Name string `protobuf:"bytes,1,opt,name=name" json:"name,omitempty"`
+	// timeout specifies the max amount of time any kubernetes client command can run.
+	Timeout int64 `protobuf:"varint,2,opt,name=timeout" json:"timeout,omitempty"`
+	// wait, if true, will wait until the restarted workloads are ready, up to
+	// timeout seconds.
+	Wait bool `protobuf:"varint,3,opt,name=wait" json:"wait,omitempty"`
+}
+
+func (m *RestartReleaseRequest) Reset()                    { *m = RestartReleaseRequest{} }
+func (m *RestartReleaseRequest) String() string            { return proto.CompactTextString(m) }
+func (*RestartReleaseRequest) ProtoMessage()               {}
+func (*RestartReleaseRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{40} }
+
+func (m *RestartReleaseRequest) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *RestartReleaseRequest) GetTimeout() int64 {
+	if m != nil {
+		return m.Timeout
+	}
+	return 0
+}
+
+func (m *RestartReleaseRequest) GetWait() bool {
+	if m != nil {
+		return m.Wait
+	}
+	return false
+}
+
+// RestartReleaseResponse is the response to a restart request.
+type RestartReleaseResponse struct {
+	Release *hapi_release6.Release `protobuf:"bytes,1,opt,name=release" json:"release,omitempty"`
+	// Restarted lists the restarted workloads, e.g. "Deployment/web".
+	Restarted []string `protobuf:"bytes,2,rep,name=restarted" json:"restarted,omitempty"`
+}
+
+func (m *RestartReleaseResponse) Reset()                    { *m = RestartReleaseResponse{} }
+func (m *RestartReleaseResponse) String() string            { return proto.CompactTextString(m) }
+func (*RestartReleaseResponse) ProtoMessage()               {}
+func (*RestartReleaseResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{41} }
+
+func (m *RestartReleaseResponse) GetRelease() *hapi_release6.Release {
+	if m != nil {
+		return m.Release
+	}
+	return nil
+}
+
+func (m *RestartReleaseResponse) GetRestarted() []string {
+	if m != nil {
+		return m.Restarted
+	}
+	return nil
+}
+
+// ApproveReleaseRequest approves the upgrade of a release that awaits
+// approval.
+type ApproveReleaseRequest struct {
+	// The name of the release
+	Name string `protobuf:"bytes,1,opt,name=name" json:"name,omitempty"`
+}
+
+func (m *ApproveReleaseRequest) Reset()                    { *m = ApproveReleaseRequest{} }
+func (m *ApproveReleaseRequest) String() string            { return proto.CompactTextString(m) }
+func (*ApproveReleaseRequest) ProtoMessage()               {}
+func (*ApproveReleaseRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{42} }
+
+func (m *ApproveReleaseRequest) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+// ApproveReleaseResponse is the response to an approve request.
+type ApproveReleaseResponse struct {
+	// Release is the revision that was approved.
+	Release *hapi_release6.Release `protobuf:"bytes,1,opt,name=release" json:"release,omitempty"`
+}
+
+func (m *ApproveReleaseResponse) Reset()                    { *m = ApproveReleaseResponse{} }
+func (m *ApproveReleaseResponse) String() string            { return proto.CompactTextString(m) }
+func (*ApproveReleaseResponse) ProtoMessage()               {}
+func (*ApproveReleaseResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{43} }
+
+func (m *ApproveReleaseResponse) GetRelease() *hapi_release6.Release {
+	if m != nil {
+		return m.Release
+	}
+	return nil
+}
+
+// RejectReleaseRequest rejects the upgrade of a release that awaits
+// approval.
+type RejectReleaseRequest struct {
+	// The name of the release
+	Name string `protobuf:"bytes,1,opt,name=name" json:"name,omitempty"`
+	// Reason is recorded in the description of the rejected revision.
+	Reason string `protobuf:"bytes,2,opt,name=reason" json:"reason,omitempty"`
+}
+
+func (m *RejectReleaseRequest) Reset()                    { *m = RejectReleaseRequest{} }
+func (m *RejectReleaseRequest) String() string            { return proto.CompactTextString(m) }
+func (*RejectReleaseRequest) ProtoMessage()               {}
+func (*RejectReleaseRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{44} }
+
+func (m *RejectReleaseRequest) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *RejectReleaseRequest) GetReason() string {
+	if m != nil {
+		return m.Reason
+	}
+	return ""
+}
+
+// RejectReleaseResponse is the response to a reject request.
+type RejectReleaseResponse struct {
+	// Release is the revision that was rejected.
+	Release *hapi_release6.Release `protobuf:"bytes,1,opt,name=release" json:"release,omitempty"`
+}
+
+func (m *RejectReleaseResponse) Reset()                    { *m = RejectReleaseResponse{} }
+func (m *RejectReleaseResponse) String() string            { return proto.CompactTextString(m) }
+func (*RejectReleaseResponse) ProtoMessage()               {}
+func (*RejectReleaseResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{45} }
+
+func (m *RejectReleaseResponse) GetRelease() *hapi_release6.Release {
+	if m != nil {
+		return m.Release
+	}
+	return nil
+}
+
+// GetReleaseDriftRequest asks how the live resources of a release differ from
+// its manifest.
+type GetReleaseDriftRequest struct {
+	// The name of the release
+	Name string `protobuf:"bytes,1,opt,name=name" json:"name,omitempty"`
+	// Version is the revision to compare. When it is 0, the latest revision
+	// is compared.
+	Version int32 `protobuf:"varint,2,opt,name=version" json:"version,omitempty"`
+	// Chart, if set, is rendered as an upgrade of the latest revision to it
+	// would render it, and the resulting resources are compared instead of
+	// those of the release. Nothing is upgraded.
+	Chart *hapi_chart3.Chart `protobuf:"bytes,3,opt,name=chart" json:"chart,omitempty"`
+	// Values are the values to render Chart with.
+	Values *hapi_chart.Config `protobuf:"bytes,4,opt,name=values" json:"values,omitempty"`
+	// ResetValues ignores the values of the release when rendering Chart.
+	ResetValues bool `protobuf:"varint,5,opt,name=reset_values,json=resetValues" json:"reset_values,omitempty"`
+	// ReuseValues merges Values over the values of the release when
+	// rendering Chart. It is ignored if reset_values is set.
+	ReuseValues bool `protobuf:"varint,6,opt,name=reuse_values,json=reuseValues" json:"reuse_values,omitempty"`
+}
+
+func (m *GetReleaseDriftRequest) Reset()                    { *m = GetReleaseDriftRequest{} }
+func (m *GetReleaseDriftRequest) String() string            { return proto.CompactTextString(m) }
+func (*GetReleaseDriftRequest) ProtoMessage()               {}
+func (*GetReleaseDriftRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{46} }
+
+func (m *GetReleaseDriftRequest) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *GetReleaseDriftRequest) GetVersion() int32 {
+	if m != nil {
+		return m.Version
+	}
+	return 0
+}
+
+func (m *GetReleaseDriftRequest) GetChart() *hapi_chart3.Chart {
+	if m != nil {
+		return m.Chart
+	}
+	return nil
+}
+
+func (m *GetReleaseDriftRequest) GetValues() *hapi_chart.Config {
+	if m != nil {
+		return m.Values
+	}
+	return nil
+}
+
+func (m *GetReleaseDriftRequest) GetResetValues() bool {
+	if m != nil {
+		return m.ResetValues
+	}
+	return false
+}
+
+func (m *GetReleaseDriftRequest) GetReuseValues() bool {
+	if m != nil {
+		return m.ReuseValues
+	}
+	return false
+}
+
+// ResourceDrift describes how a live resource differs from a release.
+type ResourceDrift struct {
+	ApiVersion string               `protobuf:"bytes,1,opt,name=api_version,json=apiVersion" json:"api_version,omitempty"`
+	Kind       string               `protobuf:"bytes,2,opt,name=kind" json:"kind,omitempty"`
+	Name       string               `protobuf:"bytes,3,opt,name=name" json:"name,omitempty"`
+	Namespace  string               `protobuf:"bytes,4,opt,name=namespace" json:"namespace,omitempty"`
+	Status     ResourceDrift_Status `protobuf:"varint,5,opt,name=status,enum=hapi.services.tiller.ResourceDrift_Status" json:"status,omitempty"`
+	// Fields lists the fields of a MODIFIED resource that differ.
+	Fields []*FieldDrift `protobuf:"bytes,6,rep,name=fields" json:"fields,omitempty"`
+}
+
+func (m *ResourceDrift) Reset()                    { *m = ResourceDrift{} }
+func (m *ResourceDrift) String() string            { return proto.CompactTextString(m) }
+func (*ResourceDrift) ProtoMessage()               {}
+func (*ResourceDrift) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{47} }
+
+func (m *ResourceDrift) GetApiVersion() string {
+	if m != nil {
+		return m.ApiVersion
+	}
+	return ""
+}
+
+func (m *ResourceDrift) GetKind() string {
+	if m != nil {
+		return m.Kind
+	}
+	return ""
+}
+
+func (m *ResourceDrift) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *ResourceDrift) GetNamespace() string {
+	if m != nil {
+		return m.Namespace
+	}
+	return ""
+}
+
+func (m *ResourceDrift) GetStatus() ResourceDrift_Status {
+	if m != nil {
+		return m.Status
+	}
+	return ResourceDrift_IN_SYNC
+}
+
+func (m *ResourceDrift) GetFields() []*FieldDrift {
+	if m != nil {
+		return m.Fields
+	}
+	return nil
+}
+
+// FieldDrift is a field whose live value differs from the manifest.
+type FieldDrift struct {
+	// Path locates the field, e.g. "spec.template.spec.containers[0].image".
+	Path string `protobuf:"bytes,1,opt,name=path" json:"path,omitempty"`
+	// Expected is the value in the manifest, as JSON.
+	Expected string `protobuf:"bytes,2,opt,name=expected" json:"expected,omitempty"`
+	// Actual is the live value as JSON, or empty if the field is not set.
+	Actual string `protobuf:"bytes,3,opt,name=actual" json:"actual,omitempty"`
+}
+
+func (m *FieldDrift) Reset()                    { *m = FieldDrift{} }
+func (m *FieldDrift) String() string            { return proto.CompactTextString(m) }
+func (*FieldDrift) ProtoMessage()               {}
+func (*FieldDrift) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{48} }
+
+func (m *FieldDrift) GetPath() string {
+	if m != nil {
+		return m.Path
+	}
+	return ""
+}
+
+func (m *FieldDrift) GetExpected() string {
+	if m != nil {
+		return m.Expected
+	}
+	return ""
+}
+
+func (m *FieldDrift) GetActual() string {
+	if m != nil {
+		return m.Actual
+	}
+	return ""
+}
+
+// GetReleaseDriftResponse reports the drift of each resource of a release.
+type GetReleaseDriftResponse struct {
+	Name string `protobuf:"bytes,1,opt,name=name" json:"name,omitempty"`
+	// Version is the revision that was compared.
+	Version int32 `protobuf:"varint,2,opt,name=version" json:"version,omitempty"`
+	// Drifted is set if any resource is not IN_SYNC.
+	Drifted   bool             `protobuf:"varint,3,opt,name=drifted" json:"drifted,omitempty"`
+	Resources []*ResourceDrift `protobuf:"bytes,4,rep,name=resources" json:"resources,omitempty"`
+}
+
+func (m *GetReleaseDriftResponse) Reset()                    { *m = GetReleaseDriftResponse{} }
+func (m *GetReleaseDriftResponse) String() string            { return proto.CompactTextString(m) }
+func (*GetReleaseDriftResponse) ProtoMessage()               {}
+func (*GetReleaseDriftResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{49} }
+
+func (m *GetReleaseDriftResponse) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *GetReleaseDriftResponse) GetVersion() int32 {
+	if m != nil {
+		return m.Version
+	}
+	return 0
+}
+
+func (m *GetReleaseDriftResponse) GetDrifted() bool {
+	if m != nil {
+		return m.Drifted
+	}
+	return false
+}
+
+func (m *GetReleaseDriftResponse) GetResources() []*ResourceDrift {
+	if m != nil {
+		return m.Resources
+	}
+	return nil
+}
+
+// ExportReleaseRequest asks for a release, or a chart rendered with values,
+// as a kustomize base.
+type ExportReleaseRequest struct {
+	// Name is the release to export, or the release name to render the chart
+	// with.
+	Name string `protobuf:"bytes,1,opt,name=name" json:"name,omitempty"`
+	// Version is the revision to export. When it is 0, the deployed revision
+	// is exported.
+	Version int32 `protobuf:"varint,2,opt,name=version" json:"version,omitempty"`
+	// Chart, if set, is rendered with values and exported instead of a stored
+	// release.
+	Chart  *hapi_chart3.Chart `protobuf:"bytes,3,opt,name=chart" json:"chart,omitempty"`
+	Values *hapi_chart.Config `protobuf:"bytes,4,opt,name=values" json:"values,omitempty"`
+	// Namespace is the namespace to render the chart for.
+	Namespace string `protobuf:"bytes,5,opt,name=namespace" json:"namespace,omitempty"`
+}
+
+func (m *ExportReleaseRequest) Reset()                    { *m = ExportReleaseRequest{} }
+func (m *ExportReleaseRequest) String() string            { return proto.CompactTextString(m) }
+func (*ExportReleaseRequest) ProtoMessage()               {}
+func (*ExportReleaseRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{50} }
+
+func (m *ExportReleaseRequest) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *ExportReleaseRequest) GetVersion() int32 {
+	if m != nil {
+		return m.Version
+	}
+	return 0
+}
+
+func (m *ExportReleaseRequest) GetChart() *hapi_chart3.Chart {
+	if m != nil {
+		return m.Chart
+	}
+	return nil
+}
+
+func (m *ExportReleaseRequest) GetValues() *hapi_chart.Config {
+	if m != nil {
+		return m.Values
+	}
+	return nil
+}
+
+func (m *ExportReleaseRequest) GetNamespace() string {
+	if m != nil {
+		return m.Namespace
+	}
+	return ""
+}
+
+// ExportedFile is a file of an exported kustomize base.
+type ExportedFile struct {
+	// Name is the path of the file within the base.
+	Name    string `protobuf:"bytes,1,opt,name=name" json:"name,omitempty"`
+	Content string `protobuf:"bytes,2,opt,name=content" json:"content,omitempty"`
+}
+
+func (m *ExportedFile) Reset()                    { *m = ExportedFile{} }
+func (m *ExportedFile) String() string            { return proto.CompactTextString(m) }
+func (*ExportedFile) ProtoMessage()               {}
+func (*ExportedFile) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{51} }
+
+func (m *ExportedFile) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *ExportedFile) GetContent() string {
+	if m != nil {
+		return m.Content
+	}
+	return ""
+}
+
+// ExportReleaseResponse is a kustomize base: a file for each resource, and a
+// kustomization.yaml that lists them.
+type ExportReleaseResponse struct {
+	Files []*ExportedFile `protobuf:"bytes,1,rep,name=files" json:"files,omitempty"`
+	// OmittedHooks lists the hooks that the base leaves out, as
+	// "event kind/name", e.g. "pre-upgrade Job/migrate".
+	OmittedHooks []string `protobuf:"bytes,2,rep,name=omitted_hooks,json=omittedHooks" json:"omitted_hooks,omitempty"`
+}
+
+func (m *ExportReleaseResponse) Reset()                    { *m = ExportReleaseResponse{} }
+func (m *ExportReleaseResponse) String() string            { return proto.CompactTextString(m) }
+func (*ExportReleaseResponse) ProtoMessage()               {}
+func (*ExportReleaseResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{52} }
+
+func (m *ExportReleaseResponse) GetFiles() []*ExportedFile {
+	if m != nil {
+		return m.Files
+	}
+	return nil
+}
+
+func (m *ExportReleaseResponse) GetOmittedHooks() []string {
+	if m != nil {
+		return m.OmittedHooks
+	}
+	return nil
+}
+
+// ResumeReleaseRequest applies the rest of the latest revision of a release,
+// if it failed while its resources were applied. No hooks are run.
+type ResumeReleaseRequest struct {
+	// The name of the release
+	Name string `protobuf:"bytes,1,opt,name=name" json:"name,omitempty"`
+	// timeout specifies the max amount of time any kubernetes client command can run.
+	Timeout int64 `protobuf:"varint,2,opt,name=timeout" json:"timeout,omitempty"`
+	// wait, if true, will wait until all Pods, PVCs, and Services are in a ready state
+	// before marking the release as successful. It will wait for as long as timeout
+	Wait bool `protobuf:"varint,3,opt,name=wait" json:"wait,omitempty"`
+}
+
+func (m *ResumeReleaseRequest) Reset()                    { *m = ResumeReleaseRequest{} }
+func (m *ResumeReleaseRequest) String() string            { return proto.CompactTextString(m) }
+func (*ResumeReleaseRequest) ProtoMessage()               {}
+func (*ResumeReleaseRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{53} }
+
+func (m *ResumeReleaseRequest) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *ResumeReleaseRequest) GetTimeout() int64 {
+	if m != nil {
+		return m.Timeout
+	}
+	return 0
+}
+
+func (m *ResumeReleaseRequest) GetWait() bool {
+	if m != nil {
+		return m.Wait
+	}
+	return false
+}
+
+// ResumeReleaseResponse is the response to a resume request.
+type ResumeReleaseResponse struct {
+	Release *hapi_release6.Release `protobuf:"bytes,1,opt,name=release" json:"release,omitempty"`
+}
+
+func (m *ResumeReleaseResponse) Reset()                    { *m = ResumeReleaseResponse{} }
+func (m *ResumeReleaseResponse) String() string            { return proto.CompactTextString(m) }
+func (*ResumeReleaseResponse) ProtoMessage()               {}
+func (*ResumeReleaseResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{54} }
+
+func (m *ResumeReleaseResponse) GetRelease() *hapi_release6.Release {
+	if m != nil {
+		return m.Release
+	}
+	return nil
+}
+
+// MigrationStatusRequest requests the progress of a storage migration.
+type MigrationStatusRequest struct {
+}
+
+func (m *MigrationStatusRequest) Reset()                    { *m = MigrationStatusRequest{} }
+func (m *MigrationStatusRequest) String() string            { return proto.CompactTextString(m) }
+func (*MigrationStatusRequest) ProtoMessage()               {}
+func (*MigrationStatusRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{55} }
+
+// MigrationStatusResponse is the progress of a storage migration.
+type MigrationStatusResponse struct {
+	// Migrating is set if Tiller was started to migrate its release records.
+	// The other fields are only set if it is.
+	Migrating bool `protobuf:"varint,1,opt,name=migrating" json:"migrating,omitempty"`
+	// From and To are the names of the storage drivers that records are
+	// moved from and to, e.g. "ConfigMap".
+	From string `protobuf:"bytes,2,opt,name=from" json:"from,omitempty"`
+	To   string `protobuf:"bytes,3,opt,name=to" json:"to,omitempty"`
+	// Total is how many records the old driver held when the latest attempt
+	// to copy them started.
+	Total int32 `protobuf:"varint,4,opt,name=total" json:"total,omitempty"`
+	// Migrated and Failed are how many of them were copied, and could not be
+	// copied, so far.
+	Migrated int32 `protobuf:"varint,5,opt,name=migrated" json:"migrated,omitempty"`
+	Failed   int32 `protobuf:"varint,6,opt,name=failed" json:"failed,omitempty"`
+	// Complete is set once every record was copied and deleted from the old
+	// driver, and the old driver is no longer used.
+	Complete bool `protobuf:"varint,7,opt,name=complete" json:"complete,omitempty"`
+	// Error is the latest error copying or deleting a record, if any.
+	Error string `protobuf:"bytes,8,opt,name=error" json:"error,omitempty"`
+}
+
+func (m *MigrationStatusResponse) Reset()                    { *m = MigrationStatusResponse{} }
+func (m *MigrationStatusResponse) String() string            { return proto.CompactTextString(m) }
+func (*MigrationStatusResponse) ProtoMessage()               {}
+func (*MigrationStatusResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{56} }
+
+func (m *MigrationStatusResponse) GetMigrating() bool {
+	if m != nil {
+		return m.Migrating
+	}
+	return false
+}
+
+func (m *MigrationStatusResponse) GetFrom() string {
+	if m != nil {
+		return m.From
+	}
+	return ""
+}
+
+func (m *MigrationStatusResponse) GetTo() string {
+	if m != nil {
+		return m.To
+	}
+	return ""
+}
+
+func (m *MigrationStatusResponse) GetTotal() int32 {
+	if m != nil {
+		return m.Total
+	}
+	return 0
+}
+
+func (m *MigrationStatusResponse) GetMigrated() int32 {
+	if m != nil {
+		return m.Migrated
+	}
+	return 0
+}
+
+func (m *MigrationStatusResponse) GetFailed() int32 {
+	if m != nil {
+		return m.Failed
+	}
+	return 0
+}
+
+func (m *MigrationStatusResponse) GetComplete() bool {
+	if m != nil {
+		return m.Complete
+	}
+	return false
+}
+
+func (m *MigrationStatusResponse) GetError() string {
+	if m != nil {
+		return m.Error
+	}
+	return ""
+}
+
+// SnapshotReleaseRequest asks for the live state of the resources of a
+// release to be recorded in its current revision, replacing any earlier
+// snapshot of that revision.
+type SnapshotReleaseRequest struct {
+	// The name of the release
+	Name string `protobuf:"bytes,1,opt,name=name" json:"name,omitempty"`
+}
+
+func (m *SnapshotReleaseRequest) Reset()                    { *m = SnapshotReleaseRequest{} }
+func (m *SnapshotReleaseRequest) String() string            { return proto.CompactTextString(m) }
+func (*SnapshotReleaseRequest) ProtoMessage()               {}
+func (*SnapshotReleaseRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{57} }
+
+func (m *SnapshotReleaseRequest) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+// SnapshotReleaseResponse is the response to a snapshot request.
+type SnapshotReleaseResponse struct {
+	// Release is the revision the snapshot was recorded in.
+	Release *hapi_release6.Release `protobuf:"bytes,1,opt,name=release" json:"release,omitempty"`
+}
+
+func (m *SnapshotReleaseResponse) Reset()                    { *m = SnapshotReleaseResponse{} }
+func (m *SnapshotReleaseResponse) String() string            { return proto.CompactTextString(m) }
+func (*SnapshotReleaseResponse) ProtoMessage()               {}
+func (*SnapshotReleaseResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{58} }
+
+func (m *SnapshotReleaseResponse) GetRelease() *hapi_release6.Release {
+	if m != nil {
+		return m.Release
+	}
+	return nil
+}
+
+// GetHooksRequest asks for the hook executions of a revision of a release.
+type GetHooksRequest struct {
+	// The name of the release
+	Name string `protobuf:"bytes,1,opt,name=name" json:"name,omitempty"`
+	// Version is the revision. Zero is the latest one.
+	Version int32 `protobuf:"varint,2,opt,name=version" json:"version,omitempty"`
+}
+
+func (m *GetHooksRequest) Reset()                    { *m = GetHooksRequest{} }
+func (m *GetHooksRequest) String() string            { return proto.CompactTextString(m) }
+func (*GetHooksRequest) ProtoMessage()               {}
+func (*GetHooksRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{59} }
+
+func (m *GetHooksRequest) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *GetHooksRequest) GetVersion() int32 {
+	if m != nil {
+		return m.Version
+	}
+	return 0
+}
+
+// GetHooksResponse lists the hook executions of a revision.
+type GetHooksResponse struct {
+	Name    string `protobuf:"bytes,1,opt,name=name" json:"name,omitempty"`
+	Version int32  `protobuf:"varint,2,opt,name=version" json:"version,omitempty"`
+	// Executions are the hooks that ran or were skipped, in order.
+	Executions []*hapi_release.HookExecution `protobuf:"bytes,3,rep,name=executions" json:"executions,omitempty"`
+}
+
+func (m *GetHooksResponse) Reset()                    { *m = GetHooksResponse{} }
+func (m *GetHooksResponse) String() string            { return proto.CompactTextString(m) }
+func (*GetHooksResponse) ProtoMessage()               {}
+func (*GetHooksResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{60} }
+
+func (m *GetHooksResponse) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *GetHooksResponse) GetVersion() int32 {
+	if m != nil {
+		return m.Version
+	}
+	return 0
+}
+
+func (m *GetHooksResponse) GetExecutions() []*hapi_release.HookExecution {
+	if m != nil {
+		return m.Executions
+	}
+	return nil
+}
+
+// InvalidateDiscoveryCacheRequest is a request to forget what Tiller
+// discovered about the API of a cluster.
+type InvalidateDiscoveryCacheRequest struct {
+	// Cluster is the name of the cluster, or empty for Tiller's own cluster.
+	Cluster string `protobuf:"bytes,1,opt,name=cluster" json:"cluster,omitempty"`
+}
+
+func (m *InvalidateDiscoveryCacheRequest) Reset()         { *m = InvalidateDiscoveryCacheRequest{} }
+func (m *InvalidateDiscoveryCacheRequest) String() string { return proto.CompactTextString(m) }
+func (*InvalidateDiscoveryCacheRequest) ProtoMessage()    {}
+func (*InvalidateDiscoveryCacheRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor0, []int{61}
+}
+
+func (m *InvalidateDiscoveryCacheRequest) GetCluster() string {
+	if m != nil {
+		return m.Cluster
+	}
+	return ""
+}
+
+// InvalidateDiscoveryCacheResponse reports the outcome of an invalidation.
+type InvalidateDiscoveryCacheResponse struct {
+	// Entries is the number of cached discoveries that were dropped.
+	Entries int32 `protobuf:"varint,1,opt,name=entries" json:"entries,omitempty"`
+}
+
+func (m *InvalidateDiscoveryCacheResponse) Reset()         { *m = InvalidateDiscoveryCacheResponse{} }
+func (m *InvalidateDiscoveryCacheResponse) String() string { return proto.CompactTextString(m) }
+func (*InvalidateDiscoveryCacheResponse) ProtoMessage()    {}
+func (*InvalidateDiscoveryCacheResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor0, []int{62}
+}
+
+func (m *InvalidateDiscoveryCacheResponse) GetEntries() int32 {
+	if m != nil {
+		return m.Entries
+	}
+	return 0
+}
+
+// GetReleaseLabelsRequest asks for the labels and annotations of the
+// resources of a revision of a release.
+type GetReleaseLabelsRequest struct {
+	// The name of the release
+	Name string `protobuf:"bytes,1,opt,name=name" json:"name,omitempty"`
+	// Version is the revision. Zero is the latest one.
+	Version int32 `protobuf:"varint,2,opt,name=version" json:"version,omitempty"`
+	// Chart, if set, is rendered with values as an install of a release of
+	// the name into namespace would render it, and the labels and annotations
+	// of its resources are returned instead of those of a revision. No
+	// release of the name needs to exist.
+	Chart     *hapi_chart3.Chart `protobuf:"bytes,3,opt,name=chart" json:"chart,omitempty"`
+	Values    *hapi_chart.Config `protobuf:"bytes,4,opt,name=values" json:"values,omitempty"`
+	Namespace string             `protobuf:"bytes,5,opt,name=namespace" json:"namespace,omitempty"`
+}
+
+func (m *GetReleaseLabelsRequest) Reset()                    { *m = GetReleaseLabelsRequest{} }
+func (m *GetReleaseLabelsRequest) String() string            { return proto.CompactTextString(m) }
+func (*GetReleaseLabelsRequest) ProtoMessage()               {}
+func (*GetReleaseLabelsRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{63} }
+
+func (m *GetReleaseLabelsRequest) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *GetReleaseLabelsRequest) GetVersion() int32 {
+	if m != nil {
+		return m.Version
+	}
+	return 0
+}
+
+func (m *GetReleaseLabelsRequest) GetChart() *hapi_chart3.Chart {
+	if m != nil {
+		return m.Chart
+	}
+	return nil
+}
+
+func (m *GetReleaseLabelsRequest) GetValues() *hapi_chart.Config {
+	if m != nil {
+		return m.Values
+	}
+	return nil
+}
+
+func (m *GetReleaseLabelsRequest) GetNamespace() string {
+	if m != nil {
+		return m.Namespace
+	}
+	return ""
+}
+
+// GetReleaseLabelsResponse lists the labels and annotations of the resources
+// of a revision.
+type GetReleaseLabelsResponse struct {
+	Name    string `protobuf:"bytes,1,opt,name=name" json:"name,omitempty"`
+	Version int32  `protobuf:"varint,2,opt,name=version" json:"version,omitempty"`
+	// CommonLabels and CommonAnnotations are what every resource of the
+	// revision carries.
+	CommonLabels      map[string]string `protobuf:"bytes,5,rep,name=common_labels,json=commonLabels" json:"common_labels,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	CommonAnnotations map[string]string `protobuf:"bytes,6,rep,name=common_annotations,json=commonAnnotations" json:"common_annotations,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	// Resources is the number of resources of the revision, without hooks.
+	Resources int32 `protobuf:"varint,7,opt,name=resources" json:"resources,omitempty"`
+}
+
+func (m *GetReleaseLabelsResponse) Reset()                    { *m = GetReleaseLabelsResponse{} }
+func (m *GetReleaseLabelsResponse) String() string            { return proto.CompactTextString(m) }
+func (*GetReleaseLabelsResponse) ProtoMessage()               {}
+func (*GetReleaseLabelsResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{64} }
+
+func (m *GetReleaseLabelsResponse) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *GetReleaseLabelsResponse) GetVersion() int32 {
+	if m != nil {
+		return m.Version
+	}
+	return 0
+}
+
+func (m *GetReleaseLabelsResponse) GetCommonLabels() map[string]string {
+	if m != nil {
+		return m.CommonLabels
+	}
+	return nil
+}
+
+func (m *GetReleaseLabelsResponse) GetCommonAnnotations() map[string]string {
+	if m != nil {
+		return m.CommonAnnotations
+	}
+	return nil
+}
+
+func (m *GetReleaseLabelsResponse) GetResources() int32 {
+	if m != nil {
+		return m.Resources
+	}
+	return 0
+}
+
+// WatchWaitLogsRequest asks for the logs that the waits of a release stream.
+type WatchWaitLogsRequest struct {
+	// The name of the release
+	Name string `protobuf:"bytes,1,opt,name=name" json:"name,omitempty"`
+}
+
+func (m *WatchWaitLogsRequest) Reset()                    { *m = WatchWaitLogsRequest{} }
+func (m *WatchWaitLogsRequest) String() string            { return proto.CompactTextString(m) }
+func (*WatchWaitLogsRequest) ProtoMessage()               {}
+func (*WatchWaitLogsRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{65} }
+
+func (m *WatchWaitLogsRequest) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+// WatchWaitLogsResponse carries the end of the logs of the containers that
+// are not ready, each headed by "==> namespace/pod/container <==". The
+// stream ends with the operation that was waiting.
+type WatchWaitLogsResponse struct {
+	Logs string `protobuf:"bytes,1,opt,name=logs" json:"logs,omitempty"`
+}
+
+func (m *WatchWaitLogsResponse) Reset()                    { *m = WatchWaitLogsResponse{} }
+func (m *WatchWaitLogsResponse) String() string            { return proto.CompactTextString(m) }
+func (*WatchWaitLogsResponse) ProtoMessage()               {}
+func (*WatchWaitLogsResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{66} }
+
+func (m *WatchWaitLogsResponse) GetLogs() string {
+	if m != nil {
+		return m.Logs
+	}
+	return ""
+}
+
+func init() {
+	proto.RegisterType((*ListReleasesRequest)(nil), "hapi.services.tiller.ListReleasesRequest")
+	proto.RegisterType((*OutputFormat)(nil), "hapi.services.tiller.OutputFormat")
+	proto.RegisterType((*ListSort)(nil), "hapi.services.tiller.ListSort")
+	proto.RegisterType((*ListReleasesResponse)(nil), "hapi.services.tiller.ListReleasesResponse")
 	proto.RegisterType((*GetReleaseStatusRequest)(nil), "hapi.services.tiller.GetReleaseStatusRequest")
 	proto.RegisterType((*GetReleaseStatusResponse)(nil), "hapi.services.tiller.GetReleaseStatusResponse")
 	proto.RegisterType((*GetReleaseContentRequest)(nil), "hapi.services.tiller.GetReleaseContentRequest")
 	proto.RegisterType((*GetReleaseContentResponse)(nil), "hapi.services.tiller.GetReleaseContentResponse")
+	proto.RegisterType((*GetManifestRequest)(nil), "hapi.services.tiller.GetManifestRequest")
+	proto.RegisterType((*GetManifestResponse)(nil), "hapi.services.tiller.GetManifestResponse")
+	proto.RegisterType((*ForceUnlockRequest)(nil), "hapi.services.tiller.ForceUnlockRequest")
+	proto.RegisterType((*ForceUnlockResponse)(nil), "hapi.services.tiller.ForceUnlockResponse")
 	proto.RegisterType((*UpdateReleaseRequest)(nil), "hapi.services.tiller.UpdateReleaseRequest")
 	proto.RegisterType((*UpdateReleaseResponse)(nil), "hapi.services.tiller.UpdateReleaseResponse")
 	proto.RegisterType((*RollbackReleaseRequest)(nil), "hapi.services.tiller.RollbackReleaseRequest")
 	proto.RegisterType((*RollbackReleaseResponse)(nil), "hapi.services.tiller.RollbackReleaseResponse")
+	proto.RegisterType((*HookPreview)(nil), "hapi.services.tiller.HookPreview")
 	proto.RegisterType((*InstallReleaseRequest)(nil), "hapi.services.tiller.InstallReleaseRequest")
 	proto.RegisterType((*InstallReleaseResponse)(nil), "hapi.services.tiller.InstallReleaseResponse")
 	proto.RegisterType((*UninstallReleaseRequest)(nil), "hapi.services.tiller.UninstallReleaseRequest")
@@ -912,405 +3481,1102 @@ func init() {
 	proto.RegisterType((*GetHistoryResponse)(nil), "hapi.services.tiller.GetHistoryResponse")
 	proto.RegisterType((*TestReleaseRequest)(nil), "hapi.services.tiller.TestReleaseRequest")
 	proto.RegisterType((*TestReleaseResponse)(nil), "hapi.services.tiller.TestReleaseResponse")
+	proto.RegisterType((*GetHealthRequest)(nil), "hapi.services.tiller.GetHealthRequest")
+	proto.RegisterType((*DependencyHealth)(nil), "hapi.services.tiller.DependencyHealth")
+	proto.RegisterType((*GetHealthResponse)(nil), "hapi.services.tiller.GetHealthResponse")
+	proto.RegisterType((*BatchInstallRequest)(nil), "hapi.services.tiller.BatchInstallRequest")
+	proto.RegisterType((*BatchInstallResult)(nil), "hapi.services.tiller.BatchInstallResult")
+	proto.RegisterType((*BatchInstallResponse)(nil), "hapi.services.tiller.BatchInstallResponse")
+	proto.RegisterType((*RestoreReleaseRequest)(nil), "hapi.services.tiller.RestoreReleaseRequest")
+	proto.RegisterType((*RestoreReleaseResponse)(nil), "hapi.services.tiller.RestoreReleaseResponse")
+	proto.RegisterType((*InspectChartRequest)(nil), "hapi.services.tiller.InspectChartRequest")
+	proto.RegisterType((*ChartDependency)(nil), "hapi.services.tiller.ChartDependency")
+	proto.RegisterType((*InspectChartResponse)(nil), "hapi.services.tiller.InspectChartResponse")
+	proto.RegisterType((*AnnotateReleaseRequest)(nil), "hapi.services.tiller.AnnotateReleaseRequest")
+	proto.RegisterType((*AnnotateReleaseResponse)(nil), "hapi.services.tiller.AnnotateReleaseResponse")
+	proto.RegisterType((*RestartReleaseRequest)(nil), "hapi.services.tiller.RestartReleaseRequest")
+	proto.RegisterType((*RestartReleaseResponse)(nil), "hapi.services.tiller.RestartReleaseResponse")
+	proto.RegisterType((*ApproveReleaseRequest)(nil), "hapi.services.tiller.ApproveReleaseRequest")
+	proto.RegisterType((*ApproveReleaseResponse)(nil), "hapi.services.tiller.ApproveReleaseResponse")
+	proto.RegisterType((*RejectReleaseRequest)(nil), "hapi.services.tiller.RejectReleaseRequest")
+	proto.RegisterType((*RejectReleaseResponse)(nil), "hapi.services.tiller.RejectReleaseResponse")
+	proto.RegisterType((*GetReleaseDriftRequest)(nil), "hapi.services.tiller.GetReleaseDriftRequest")
+	proto.RegisterType((*ResourceDrift)(nil), "hapi.services.tiller.ResourceDrift")
+	proto.RegisterType((*FieldDrift)(nil), "hapi.services.tiller.FieldDrift")
+	proto.RegisterType((*GetReleaseDriftResponse)(nil), "hapi.services.tiller.GetReleaseDriftResponse")
+	proto.RegisterType((*ExportReleaseRequest)(nil), "hapi.services.tiller.ExportReleaseRequest")
+	proto.RegisterType((*ExportedFile)(nil), "hapi.services.tiller.ExportedFile")
+	proto.RegisterType((*ExportReleaseResponse)(nil), "hapi.services.tiller.ExportReleaseResponse")
+	proto.RegisterType((*ResumeReleaseRequest)(nil), "hapi.services.tiller.ResumeReleaseRequest")
+	proto.RegisterType((*ResumeReleaseResponse)(nil), "hapi.services.tiller.ResumeReleaseResponse")
+	proto.RegisterType((*MigrationStatusRequest)(nil), "hapi.services.tiller.MigrationStatusRequest")
+	proto.RegisterType((*MigrationStatusResponse)(nil), "hapi.services.tiller.MigrationStatusResponse")
+	proto.RegisterType((*SnapshotReleaseRequest)(nil), "hapi.services.tiller.SnapshotReleaseRequest")
+	proto.RegisterType((*SnapshotReleaseResponse)(nil), "hapi.services.tiller.SnapshotReleaseResponse")
+	proto.RegisterType((*GetHooksRequest)(nil), "hapi.services.tiller.GetHooksRequest")
+	proto.RegisterType((*GetHooksResponse)(nil), "hapi.services.tiller.GetHooksResponse")
+	proto.RegisterType((*InvalidateDiscoveryCacheRequest)(nil), "hapi.services.tiller.InvalidateDiscoveryCacheRequest")
+	proto.RegisterType((*InvalidateDiscoveryCacheResponse)(nil), "hapi.services.tiller.InvalidateDiscoveryCacheResponse")
+	proto.RegisterType((*GetReleaseLabelsRequest)(nil), "hapi.services.tiller.GetReleaseLabelsRequest")
+	proto.RegisterType((*GetReleaseLabelsResponse)(nil), "hapi.services.tiller.GetReleaseLabelsResponse")
+	proto.RegisterType((*WatchWaitLogsRequest)(nil), "hapi.services.tiller.WatchWaitLogsRequest")
+	proto.RegisterType((*WatchWaitLogsResponse)(nil), "hapi.services.tiller.WatchWaitLogsResponse")
+	proto.RegisterEnum("hapi.services.tiller.OutputFormat_Format", OutputFormat_Format_name, OutputFormat_Format_value)
 	proto.RegisterEnum("hapi.services.tiller.ListSort_SortBy", ListSort_SortBy_name, ListSort_SortBy_value)
 	proto.RegisterEnum("hapi.services.tiller.ListSort_SortOrder", ListSort_SortOrder_name, ListSort_SortOrder_value)
+	proto.RegisterEnum("hapi.services.tiller.DependencyHealth_Status", DependencyHealth_Status_name, DependencyHealth_Status_value)
+	proto.RegisterEnum("hapi.services.tiller.BatchInstallResult_Outcome", BatchInstallResult_Outcome_name, BatchInstallResult_Outcome_value)
+	proto.RegisterEnum("hapi.services.tiller.ResourceDrift_Status", ResourceDrift_Status_name, ResourceDrift_Status_value)
+}
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ context.Context
+var _ grpc.ClientConn
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+const _ = grpc.SupportPackageIsVersion4
+
+// Client API for ReleaseService service
+
+type ReleaseServiceClient interface {
+	// ListReleases retrieves release history.
+	// TODO: Allow filtering the set of releases by
+	// release status. By default, ListAllReleases returns the releases who
+	// current status is "Active".
+	ListReleases(ctx context.Context, in *ListReleasesRequest, opts ...grpc.CallOption) (ReleaseService_ListReleasesClient, error)
+	// GetReleasesStatus retrieves status information for the specified release.
+	GetReleaseStatus(ctx context.Context, in *GetReleaseStatusRequest, opts ...grpc.CallOption) (*GetReleaseStatusResponse, error)
+	// GetReleaseContent retrieves the release content (chart + value) for the specified release.
+	GetReleaseContent(ctx context.Context, in *GetReleaseContentRequest, opts ...grpc.CallOption) (*GetReleaseContentResponse, error)
+	// UpdateRelease updates release content.
+	UpdateRelease(ctx context.Context, in *UpdateReleaseRequest, opts ...grpc.CallOption) (*UpdateReleaseResponse, error)
+	// InstallRelease requests installation of a chart as a new release.
+	InstallRelease(ctx context.Context, in *InstallReleaseRequest, opts ...grpc.CallOption) (*InstallReleaseResponse, error)
+	// UninstallRelease requests deletion of a named release.
+	UninstallRelease(ctx context.Context, in *UninstallReleaseRequest, opts ...grpc.CallOption) (*UninstallReleaseResponse, error)
+	// GetVersion returns the current version of the server, and the features
+	// it supports.
+	GetVersion(ctx context.Context, in *GetVersionRequest, opts ...grpc.CallOption) (*GetVersionResponse, error)
+	// RollbackRelease rolls back a release to a previous version.
+	RollbackRelease(ctx context.Context, in *RollbackReleaseRequest, opts ...grpc.CallOption) (*RollbackReleaseResponse, error)
+	// ReleaseHistory retrieves a releasse's history.
+	GetHistory(ctx context.Context, in *GetHistoryRequest, opts ...grpc.CallOption) (*GetHistoryResponse, error)
+	// RunReleaseTest executes the tests defined of a named release
+	RunReleaseTest(ctx context.Context, in *TestReleaseRequest, opts ...grpc.CallOption) (ReleaseService_RunReleaseTestClient, error)
+	// GetHealth reports whether the dependencies of the server are reachable.
+	GetHealth(ctx context.Context, in *GetHealthRequest, opts ...grpc.CallOption) (*GetHealthResponse, error)
+	// GetManifest retrieves only the manifest of the specified release.
+	GetManifest(ctx context.Context, in *GetManifestRequest, opts ...grpc.CallOption) (*GetManifestResponse, error)
+	// ForceUnlock clears the lock held on a release by an operation that will
+	// never finish, such as one whose process crashed.
+	ForceUnlock(ctx context.Context, in *ForceUnlockRequest, opts ...grpc.CallOption) (*ForceUnlockResponse, error)
+	// BatchInstall installs several releases as a unit: either all of them
+	// are installed, or none of them are left behind.
+	BatchInstall(ctx context.Context, in *BatchInstallRequest, opts ...grpc.CallOption) (*BatchInstallResponse, error)
+	// RestoreRelease re-installs a deleted release from its retained record.
+	RestoreRelease(ctx context.Context, in *RestoreReleaseRequest, opts ...grpc.CallOption) (*RestoreReleaseResponse, error)
+	// InspectChart returns the metadata, default values and README of a chart
+	// without installing it.
+	InspectChart(ctx context.Context, in *InspectChartRequest, opts ...grpc.CallOption) (*InspectChartResponse, error)
+	// AnnotateRelease changes the annotations of a revision of a release in
+	// place, without creating a new revision.
+	AnnotateRelease(ctx context.Context, in *AnnotateReleaseRequest, opts ...grpc.CallOption) (*AnnotateReleaseResponse, error)
+	// RestartRelease restarts the pods of the workloads of a deployed release,
+	// without creating a new revision.
+	RestartRelease(ctx context.Context, in *RestartReleaseRequest, opts ...grpc.CallOption) (*RestartReleaseResponse, error)
+	// ApproveRelease lets an upgrade that awaits approval go ahead.
+	ApproveRelease(ctx context.Context, in *ApproveReleaseRequest, opts ...grpc.CallOption) (*ApproveReleaseResponse, error)
+	// RejectRelease aborts an upgrade that awaits approval.
+	RejectRelease(ctx context.Context, in *RejectReleaseRequest, opts ...grpc.CallOption) (*RejectReleaseResponse, error)
+	// GetReleaseDrift compares the resources of a release, or those an
+	// upgrade of it to a chart would apply, to their live counterparts. It
+	// changes nothing.
+	GetReleaseDrift(ctx context.Context, in *GetReleaseDriftRequest, opts ...grpc.CallOption) (*GetReleaseDriftResponse, error)
+	// ExportRelease renders a release, or a chart with values, as a kustomize
+	// base. It changes nothing.
+	ExportRelease(ctx context.Context, in *ExportReleaseRequest, opts ...grpc.CallOption) (*ExportReleaseResponse, error)
+	// ResumeRelease applies the resources that a failed install or upgrade
+	// did not get to apply.
+	ResumeRelease(ctx context.Context, in *ResumeReleaseRequest, opts ...grpc.CallOption) (*ResumeReleaseResponse, error)
+	// MigrationStatus reports the progress of moving the release records to
+	// another storage driver while Tiller keeps running.
+	MigrationStatus(ctx context.Context, in *MigrationStatusRequest, opts ...grpc.CallOption) (*MigrationStatusResponse, error)
+	// SnapshotRelease records the live state of the resources of a release
+	// in its current revision, for a rollback to restore later.
+	SnapshotRelease(ctx context.Context, in *SnapshotReleaseRequest, opts ...grpc.CallOption) (*SnapshotReleaseResponse, error)
+	// GetHooks returns the hooks that ran or were skipped for a revision of a
+	// release, with their outcomes.
+	GetHooks(ctx context.Context, in *GetHooksRequest, opts ...grpc.CallOption) (*GetHooksResponse, error)
+	// InvalidateDiscoveryCache makes Tiller discover the API groups and
+	// versions of a cluster again, e.g. after CustomResourceDefinitions were
+	// added outside of Helm.
+	InvalidateDiscoveryCache(ctx context.Context, in *InvalidateDiscoveryCacheRequest, opts ...grpc.CallOption) (*InvalidateDiscoveryCacheResponse, error)
+	// GetReleaseLabels returns the labels and annotations that mark the
+	// resources of a revision of a release, or that a chart would give the
+	// resources of a new release, for tools that select them.
+	GetReleaseLabels(ctx context.Context, in *GetReleaseLabelsRequest, opts ...grpc.CallOption) (*GetReleaseLabelsResponse, error)
+	// WatchWaitLogs streams the logs of the containers that are not ready
+	// while an install or upgrade of a release waits for its resources, if
+	// the operation asks for them with wait_log_bytes.
+	WatchWaitLogs(ctx context.Context, in *WatchWaitLogsRequest, opts ...grpc.CallOption) (ReleaseService_WatchWaitLogsClient, error)
+}
+
+type releaseServiceClient struct {
+	cc *grpc.ClientConn
+}
+
+func NewReleaseServiceClient(cc *grpc.ClientConn) ReleaseServiceClient {
+	return &releaseServiceClient{cc}
+}
+
+func (c *releaseServiceClient) ListReleases(ctx context.Context, in *ListReleasesRequest, opts ...grpc.CallOption) (ReleaseService_ListReleasesClient, error) {
+	stream, err := grpc.NewClientStream(ctx, &_ReleaseService_serviceDesc.Streams[0], c.cc, "/hapi.services.tiller.ReleaseService/ListReleases", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &releaseServiceListReleasesClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type ReleaseService_ListReleasesClient interface {
+	Recv() (*ListReleasesResponse, error)
+	grpc.ClientStream
+}
+
+type releaseServiceListReleasesClient struct {
+	grpc.ClientStream
+}
+
+func (x *releaseServiceListReleasesClient) Recv() (*ListReleasesResponse, error) {
+	m := new(ListReleasesResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *releaseServiceClient) GetReleaseStatus(ctx context.Context, in *GetReleaseStatusRequest, opts ...grpc.CallOption) (*GetReleaseStatusResponse, error) {
+	out := new(GetReleaseStatusResponse)
+	err := grpc.Invoke(ctx, "/hapi.services.tiller.ReleaseService/GetReleaseStatus", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *releaseServiceClient) GetReleaseContent(ctx context.Context, in *GetReleaseContentRequest, opts ...grpc.CallOption) (*GetReleaseContentResponse, error) {
+	out := new(GetReleaseContentResponse)
+	err := grpc.Invoke(ctx, "/hapi.services.tiller.ReleaseService/GetReleaseContent", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *releaseServiceClient) UpdateRelease(ctx context.Context, in *UpdateReleaseRequest, opts ...grpc.CallOption) (*UpdateReleaseResponse, error) {
+	out := new(UpdateReleaseResponse)
+	err := grpc.Invoke(ctx, "/hapi.services.tiller.ReleaseService/UpdateRelease", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *releaseServiceClient) InstallRelease(ctx context.Context, in *InstallReleaseRequest, opts ...grpc.CallOption) (*InstallReleaseResponse, error) {
+	out := new(InstallReleaseResponse)
+	err := grpc.Invoke(ctx, "/hapi.services.tiller.ReleaseService/InstallRelease", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *releaseServiceClient) UninstallRelease(ctx context.Context, in *UninstallReleaseRequest, opts ...grpc.CallOption) (*UninstallReleaseResponse, error) {
+	out := new(UninstallReleaseResponse)
+	err := grpc.Invoke(ctx, "/hapi.services.tiller.ReleaseService/UninstallRelease", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *releaseServiceClient) GetVersion(ctx context.Context, in *GetVersionRequest, opts ...grpc.CallOption) (*GetVersionResponse, error) {
+	out := new(GetVersionResponse)
+	err := grpc.Invoke(ctx, "/hapi.services.tiller.ReleaseService/GetVersion", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *releaseServiceClient) RollbackRelease(ctx context.Context, in *RollbackReleaseRequest, opts ...grpc.CallOption) (*RollbackReleaseResponse, error) {
+	out := new(RollbackReleaseResponse)
+	err := grpc.Invoke(ctx, "/hapi.services.tiller.ReleaseService/RollbackRelease", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *releaseServiceClient) GetHistory(ctx context.Context, in *GetHistoryRequest, opts ...grpc.CallOption) (*GetHistoryResponse, error) {
+	out := new(GetHistoryResponse)
+	err := grpc.Invoke(ctx, "/hapi.services.tiller.ReleaseService/GetHistory", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *releaseServiceClient) RunReleaseTest(ctx context.Context, in *TestReleaseRequest, opts ...grpc.CallOption) (ReleaseService_RunReleaseTestClient, error) {
+	stream, err := grpc.NewClientStream(ctx, &_ReleaseService_serviceDesc.Streams[1], c.cc, "/hapi.services.tiller.ReleaseService/RunReleaseTest", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &releaseServiceRunReleaseTestClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type ReleaseService_RunReleaseTestClient interface {
+	Recv() (*TestReleaseResponse, error)
+	grpc.ClientStream
+}
+
+type releaseServiceRunReleaseTestClient struct {
+	grpc.ClientStream
+}
+
+func (x *releaseServiceRunReleaseTestClient) Recv() (*TestReleaseResponse, error) {
+	m := new(TestReleaseResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *releaseServiceClient) GetHealth(ctx context.Context, in *GetHealthRequest, opts ...grpc.CallOption) (*GetHealthResponse, error) {
+	out := new(GetHealthResponse)
+	err := grpc.Invoke(ctx, "/hapi.services.tiller.ReleaseService/GetHealth", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *releaseServiceClient) GetManifest(ctx context.Context, in *GetManifestRequest, opts ...grpc.CallOption) (*GetManifestResponse, error) {
+	out := new(GetManifestResponse)
+	err := grpc.Invoke(ctx, "/hapi.services.tiller.ReleaseService/GetManifest", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *releaseServiceClient) ForceUnlock(ctx context.Context, in *ForceUnlockRequest, opts ...grpc.CallOption) (*ForceUnlockResponse, error) {
+	out := new(ForceUnlockResponse)
+	err := grpc.Invoke(ctx, "/hapi.services.tiller.ReleaseService/ForceUnlock", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *releaseServiceClient) BatchInstall(ctx context.Context, in *BatchInstallRequest, opts ...grpc.CallOption) (*BatchInstallResponse, error) {
+	out := new(BatchInstallResponse)
+	err := grpc.Invoke(ctx, "/hapi.services.tiller.ReleaseService/BatchInstall", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *releaseServiceClient) RestoreRelease(ctx context.Context, in *RestoreReleaseRequest, opts ...grpc.CallOption) (*RestoreReleaseResponse, error) {
+	out := new(RestoreReleaseResponse)
+	err := grpc.Invoke(ctx, "/hapi.services.tiller.ReleaseService/RestoreRelease", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *releaseServiceClient) InspectChart(ctx context.Context, in *InspectChartRequest, opts ...grpc.CallOption) (*InspectChartResponse, error) {
+	out := new(InspectChartResponse)
+	err := grpc.Invoke(ctx, "/hapi.services.tiller.ReleaseService/InspectChart", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *releaseServiceClient) AnnotateRelease(ctx context.Context, in *AnnotateReleaseRequest, opts ...grpc.CallOption) (*AnnotateReleaseResponse, error) {
+	out := new(AnnotateReleaseResponse)
+	err := grpc.Invoke(ctx, "/hapi.services.tiller.ReleaseService/AnnotateRelease", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *releaseServiceClient) RestartRelease(ctx context.Context, in *RestartReleaseRequest, opts ...grpc.CallOption) (*RestartReleaseResponse, error) {
+	out := new(RestartReleaseResponse)
+	err := grpc.Invoke(ctx, "/hapi.services.tiller.ReleaseService/RestartRelease", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *releaseServiceClient) ApproveRelease(ctx context.Context, in *ApproveReleaseRequest, opts ...grpc.CallOption) (*ApproveReleaseResponse, error) {
+	out := new(ApproveReleaseResponse)
+	err := grpc.Invoke(ctx, "/hapi.services.tiller.ReleaseService/ApproveRelease", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *releaseServiceClient) RejectRelease(ctx context.Context, in *RejectReleaseRequest, opts ...grpc.CallOption) (*RejectReleaseResponse, error) {
+	out := new(RejectReleaseResponse)
+	err := grpc.Invoke(ctx, "/hapi.services.tiller.ReleaseService/RejectRelease", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *releaseServiceClient) GetReleaseDrift(ctx context.Context, in *GetReleaseDriftRequest, opts ...grpc.CallOption) (*GetReleaseDriftResponse, error) {
+	out := new(GetReleaseDriftResponse)
+	err := grpc.Invoke(ctx, "/hapi.services.tiller.ReleaseService/GetReleaseDrift", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *releaseServiceClient) ExportRelease(ctx context.Context, in *ExportReleaseRequest, opts ...grpc.CallOption) (*ExportReleaseResponse, error) {
+	out := new(ExportReleaseResponse)
+	err := grpc.Invoke(ctx, "/hapi.services.tiller.ReleaseService/ExportRelease", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *releaseServiceClient) ResumeRelease(ctx context.Context, in *ResumeReleaseRequest, opts ...grpc.CallOption) (*ResumeReleaseResponse, error) {
+	out := new(ResumeReleaseResponse)
+	err := grpc.Invoke(ctx, "/hapi.services.tiller.ReleaseService/ResumeRelease", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *releaseServiceClient) MigrationStatus(ctx context.Context, in *MigrationStatusRequest, opts ...grpc.CallOption) (*MigrationStatusResponse, error) {
+	out := new(MigrationStatusResponse)
+	err := grpc.Invoke(ctx, "/hapi.services.tiller.ReleaseService/MigrationStatus", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *releaseServiceClient) SnapshotRelease(ctx context.Context, in *SnapshotReleaseRequest, opts ...grpc.CallOption) (*SnapshotReleaseResponse, error) {
+	out := new(SnapshotReleaseResponse)
+	err := grpc.Invoke(ctx, "/hapi.services.tiller.ReleaseService/SnapshotRelease", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *releaseServiceClient) GetHooks(ctx context.Context, in *GetHooksRequest, opts ...grpc.CallOption) (*GetHooksResponse, error) {
+	out := new(GetHooksResponse)
+	err := grpc.Invoke(ctx, "/hapi.services.tiller.ReleaseService/GetHooks", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *releaseServiceClient) InvalidateDiscoveryCache(ctx context.Context, in *InvalidateDiscoveryCacheRequest, opts ...grpc.CallOption) (*InvalidateDiscoveryCacheResponse, error) {
+	out := new(InvalidateDiscoveryCacheResponse)
+	err := grpc.Invoke(ctx, "/hapi.services.tiller.ReleaseService/InvalidateDiscoveryCache", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *releaseServiceClient) GetReleaseLabels(ctx context.Context, in *GetReleaseLabelsRequest, opts ...grpc.CallOption) (*GetReleaseLabelsResponse, error) {
+	out := new(GetReleaseLabelsResponse)
+	err := grpc.Invoke(ctx, "/hapi.services.tiller.ReleaseService/GetReleaseLabels", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *releaseServiceClient) WatchWaitLogs(ctx context.Context, in *WatchWaitLogsRequest, opts ...grpc.CallOption) (ReleaseService_WatchWaitLogsClient, error) {
+	stream, err := grpc.NewClientStream(ctx, &_ReleaseService_serviceDesc.Streams[2], c.cc, "/hapi.services.tiller.ReleaseService/WatchWaitLogs", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &releaseServiceWatchWaitLogsClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
 }
 
-// Reference imports to suppress errors if they are not otherwise used.
-var _ context.Context
-var _ grpc.ClientConn
+type ReleaseService_WatchWaitLogsClient interface {
+	Recv() (*WatchWaitLogsResponse, error)
+	grpc.ClientStream
+}
 
-// This is a compile-time assertion to ensure that this generated file
-// is compatible with the grpc package it is being compiled against.
-const _ = grpc.SupportPackageIsVersion4
+type releaseServiceWatchWaitLogsClient struct {
+	grpc.ClientStream
+}
 
-// Client API for ReleaseService service
+func (x *releaseServiceWatchWaitLogsClient) Recv() (*WatchWaitLogsResponse, error) {
+	m := new(WatchWaitLogsResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
 
-type ReleaseServiceClient interface {
+// Server API for ReleaseService service
+
+type ReleaseServiceServer interface {
 	// ListReleases retrieves release history.
 	// TODO: Allow filtering the set of releases by
 	// release status. By default, ListAllReleases returns the releases who
 	// current status is "Active".
-	ListReleases(ctx context.Context, in *ListReleasesRequest, opts ...grpc.CallOption) (ReleaseService_ListReleasesClient, error)
+	ListReleases(*ListReleasesRequest, ReleaseService_ListReleasesServer) error
 	// GetReleasesStatus retrieves status information for the specified release.
-	GetReleaseStatus(ctx context.Context, in *GetReleaseStatusRequest, opts ...grpc.CallOption) (*GetReleaseStatusResponse, error)
+	GetReleaseStatus(context.Context, *GetReleaseStatusRequest) (*GetReleaseStatusResponse, error)
 	// GetReleaseContent retrieves the release content (chart + value) for the specified release.
-	GetReleaseContent(ctx context.Context, in *GetReleaseContentRequest, opts ...grpc.CallOption) (*GetReleaseContentResponse, error)
+	GetReleaseContent(context.Context, *GetReleaseContentRequest) (*GetReleaseContentResponse, error)
 	// UpdateRelease updates release content.
-	UpdateRelease(ctx context.Context, in *UpdateReleaseRequest, opts ...grpc.CallOption) (*UpdateReleaseResponse, error)
+	UpdateRelease(context.Context, *UpdateReleaseRequest) (*UpdateReleaseResponse, error)
 	// InstallRelease requests installation of a chart as a new release.
-	InstallRelease(ctx context.Context, in *InstallReleaseRequest, opts ...grpc.CallOption) (*InstallReleaseResponse, error)
+	InstallRelease(context.Context, *InstallReleaseRequest) (*InstallReleaseResponse, error)
 	// UninstallRelease requests deletion of a named release.
-	UninstallRelease(ctx context.Context, in *UninstallReleaseRequest, opts ...grpc.CallOption) (*UninstallReleaseResponse, error)
-	// GetVersion returns the current version of the server.
-	GetVersion(ctx context.Context, in *GetVersionRequest, opts ...grpc.CallOption) (*GetVersionResponse, error)
+	UninstallRelease(context.Context, *UninstallReleaseRequest) (*UninstallReleaseResponse, error)
+	// GetVersion returns the current version of the server, and the features
+	// it supports.
+	GetVersion(context.Context, *GetVersionRequest) (*GetVersionResponse, error)
 	// RollbackRelease rolls back a release to a previous version.
-	RollbackRelease(ctx context.Context, in *RollbackReleaseRequest, opts ...grpc.CallOption) (*RollbackReleaseResponse, error)
+	RollbackRelease(context.Context, *RollbackReleaseRequest) (*RollbackReleaseResponse, error)
 	// ReleaseHistory retrieves a releasse's history.
-	GetHistory(ctx context.Context, in *GetHistoryRequest, opts ...grpc.CallOption) (*GetHistoryResponse, error)
+	GetHistory(context.Context, *GetHistoryRequest) (*GetHistoryResponse, error)
 	// RunReleaseTest executes the tests defined of a named release
-	RunReleaseTest(ctx context.Context, in *TestReleaseRequest, opts ...grpc.CallOption) (ReleaseService_RunReleaseTestClient, error)
+	RunReleaseTest(*TestReleaseRequest, ReleaseService_RunReleaseTestServer) error
+	// GetHealth reports whether the dependencies of the server are reachable.
+	GetHealth(context.Context, *GetHealthRequest) (*GetHealthResponse, error)
+	// GetManifest retrieves only the manifest of the specified release.
+	GetManifest(context.Context, *GetManifestRequest) (*GetManifestResponse, error)
+	// ForceUnlock clears the lock held on a release by an operation that will
+	// never finish, such as one whose process crashed.
+	ForceUnlock(context.Context, *ForceUnlockRequest) (*ForceUnlockResponse, error)
+	// BatchInstall installs several releases as a unit: either all of them
+	// are installed, or none of them are left behind.
+	BatchInstall(context.Context, *BatchInstallRequest) (*BatchInstallResponse, error)
+	// RestoreRelease re-installs a deleted release from its retained record.
+	RestoreRelease(context.Context, *RestoreReleaseRequest) (*RestoreReleaseResponse, error)
+	// InspectChart returns the metadata, default values and README of a chart
+	// without installing it.
+	InspectChart(context.Context, *InspectChartRequest) (*InspectChartResponse, error)
+	// AnnotateRelease changes the annotations of a revision of a release in
+	// place, without creating a new revision.
+	AnnotateRelease(context.Context, *AnnotateReleaseRequest) (*AnnotateReleaseResponse, error)
+	// RestartRelease restarts the pods of the workloads of a deployed release,
+	// without creating a new revision.
+	RestartRelease(context.Context, *RestartReleaseRequest) (*RestartReleaseResponse, error)
+	// ApproveRelease lets an upgrade that awaits approval go ahead.
+	ApproveRelease(context.Context, *ApproveReleaseRequest) (*ApproveReleaseResponse, error)
+	// RejectRelease aborts an upgrade that awaits approval.
+	RejectRelease(context.Context, *RejectReleaseRequest) (*RejectReleaseResponse, error)
+	// GetReleaseDrift compares the resources of a release, or those an
+	// upgrade of it to a chart would apply, to their live counterparts. It
+	// changes nothing.
+	GetReleaseDrift(context.Context, *GetReleaseDriftRequest) (*GetReleaseDriftResponse, error)
+	// ExportRelease renders a release, or a chart with values, as a kustomize
+	// base. It changes nothing.
+	ExportRelease(context.Context, *ExportReleaseRequest) (*ExportReleaseResponse, error)
+	// ResumeRelease applies the resources that a failed install or upgrade
+	// did not get to apply.
+	ResumeRelease(context.Context, *ResumeReleaseRequest) (*ResumeReleaseResponse, error)
+	// MigrationStatus reports the progress of moving the release records to
+	// another storage driver while Tiller keeps running.
+	MigrationStatus(context.Context, *MigrationStatusRequest) (*MigrationStatusResponse, error)
+	// SnapshotRelease records the live state of the resources of a release
+	// in its current revision, for a rollback to restore later.
+	SnapshotRelease(context.Context, *SnapshotReleaseRequest) (*SnapshotReleaseResponse, error)
+	// GetHooks returns the hooks that ran or were skipped for a revision of a
+	// release, with their outcomes.
+	GetHooks(context.Context, *GetHooksRequest) (*GetHooksResponse, error)
+	// InvalidateDiscoveryCache makes Tiller discover the API groups and
+	// versions of a cluster again, e.g. after CustomResourceDefinitions were
+	// added outside of Helm.
+	InvalidateDiscoveryCache(context.Context, *InvalidateDiscoveryCacheRequest) (*InvalidateDiscoveryCacheResponse, error)
+	// GetReleaseLabels returns the labels and annotations that mark the
+	// resources of a revision of a release, or that a chart would give the
+	// resources of a new release, for tools that select them.
+	GetReleaseLabels(context.Context, *GetReleaseLabelsRequest) (*GetReleaseLabelsResponse, error)
+	// WatchWaitLogs streams the logs of the containers that are not ready
+	// while an install or upgrade of a release waits for its resources, if
+	// the operation asks for them with wait_log_bytes.
+	WatchWaitLogs(*WatchWaitLogsRequest, ReleaseService_WatchWaitLogsServer) error
 }
 
-type releaseServiceClient struct {
-	cc *grpc.ClientConn
+func RegisterReleaseServiceServer(s *grpc.Server, srv ReleaseServiceServer) {
+	s.RegisterService(&_ReleaseService_serviceDesc, srv)
 }
 
-func NewReleaseServiceClient(cc *grpc.ClientConn) ReleaseServiceClient {
-	return &releaseServiceClient{cc}
+func _ReleaseService_ListReleases_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(ListReleasesRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(ReleaseServiceServer).ListReleases(m, &releaseServiceListReleasesServer{stream})
 }
 
-func (c *releaseServiceClient) ListReleases(ctx context.Context, in *ListReleasesRequest, opts ...grpc.CallOption) (ReleaseService_ListReleasesClient, error) {
-	stream, err := grpc.NewClientStream(ctx, &_ReleaseService_serviceDesc.Streams[0], c.cc, "/hapi.services.tiller.ReleaseService/ListReleases", opts...)
-	if err != nil {
+type ReleaseService_ListReleasesServer interface {
+	Send(*ListReleasesResponse) error
+	grpc.ServerStream
+}
+
+type releaseServiceListReleasesServer struct {
+	grpc.ServerStream
+}
+
+func (x *releaseServiceListReleasesServer) Send(m *ListReleasesResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _ReleaseService_GetReleaseStatus_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetReleaseStatusRequest)
+	if err := dec(in); err != nil {
 		return nil, err
 	}
-	x := &releaseServiceListReleasesClient{stream}
-	if err := x.ClientStream.SendMsg(in); err != nil {
+	if interceptor == nil {
+		return srv.(ReleaseServiceServer).GetReleaseStatus(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/hapi.services.tiller.ReleaseService/GetReleaseStatus",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ReleaseServiceServer).GetReleaseStatus(ctx, req.(*GetReleaseStatusRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ReleaseService_GetReleaseContent_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetReleaseContentRequest)
+	if err := dec(in); err != nil {
 		return nil, err
 	}
-	if err := x.ClientStream.CloseSend(); err != nil {
+	if interceptor == nil {
+		return srv.(ReleaseServiceServer).GetReleaseContent(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/hapi.services.tiller.ReleaseService/GetReleaseContent",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ReleaseServiceServer).GetReleaseContent(ctx, req.(*GetReleaseContentRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ReleaseService_UpdateRelease_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateReleaseRequest)
+	if err := dec(in); err != nil {
 		return nil, err
 	}
-	return x, nil
+	if interceptor == nil {
+		return srv.(ReleaseServiceServer).UpdateRelease(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/hapi.services.tiller.ReleaseService/UpdateRelease",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ReleaseServiceServer).UpdateRelease(ctx, req.(*UpdateReleaseRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-type ReleaseService_ListReleasesClient interface {
-	Recv() (*ListReleasesResponse, error)
-	grpc.ClientStream
+func _ReleaseService_InstallRelease_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(InstallReleaseRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ReleaseServiceServer).InstallRelease(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/hapi.services.tiller.ReleaseService/InstallRelease",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ReleaseServiceServer).InstallRelease(ctx, req.(*InstallReleaseRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-type releaseServiceListReleasesClient struct {
-	grpc.ClientStream
+func _ReleaseService_UninstallRelease_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UninstallReleaseRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ReleaseServiceServer).UninstallRelease(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/hapi.services.tiller.ReleaseService/UninstallRelease",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ReleaseServiceServer).UninstallRelease(ctx, req.(*UninstallReleaseRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func (x *releaseServiceListReleasesClient) Recv() (*ListReleasesResponse, error) {
-	m := new(ListReleasesResponse)
-	if err := x.ClientStream.RecvMsg(m); err != nil {
+func _ReleaseService_GetVersion_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetVersionRequest)
+	if err := dec(in); err != nil {
 		return nil, err
 	}
-	return m, nil
+	if interceptor == nil {
+		return srv.(ReleaseServiceServer).GetVersion(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/hapi.services.tiller.ReleaseService/GetVersion",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ReleaseServiceServer).GetVersion(ctx, req.(*GetVersionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func (c *releaseServiceClient) GetReleaseStatus(ctx context.Context, in *GetReleaseStatusRequest, opts ...grpc.CallOption) (*GetReleaseStatusResponse, error) {
-	out := new(GetReleaseStatusResponse)
-	err := grpc.Invoke(ctx, "/hapi.services.tiller.ReleaseService/GetReleaseStatus", in, out, c.cc, opts...)
-	if err != nil {
+func _ReleaseService_RollbackRelease_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RollbackReleaseRequest)
+	if err := dec(in); err != nil {
 		return nil, err
 	}
-	return out, nil
+	if interceptor == nil {
+		return srv.(ReleaseServiceServer).RollbackRelease(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/hapi.services.tiller.ReleaseService/RollbackRelease",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ReleaseServiceServer).RollbackRelease(ctx, req.(*RollbackReleaseRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func (c *releaseServiceClient) GetReleaseContent(ctx context.Context, in *GetReleaseContentRequest, opts ...grpc.CallOption) (*GetReleaseContentResponse, error) {
-	out := new(GetReleaseContentResponse)
-	err := grpc.Invoke(ctx, "/hapi.services.tiller.ReleaseService/GetReleaseContent", in, out, c.cc, opts...)
-	if err != nil {
+func _ReleaseService_GetHistory_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetHistoryRequest)
+	if err := dec(in); err != nil {
 		return nil, err
 	}
-	return out, nil
+	if interceptor == nil {
+		return srv.(ReleaseServiceServer).GetHistory(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/hapi.services.tiller.ReleaseService/GetHistory",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ReleaseServiceServer).GetHistory(ctx, req.(*GetHistoryRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func (c *releaseServiceClient) UpdateRelease(ctx context.Context, in *UpdateReleaseRequest, opts ...grpc.CallOption) (*UpdateReleaseResponse, error) {
-	out := new(UpdateReleaseResponse)
-	err := grpc.Invoke(ctx, "/hapi.services.tiller.ReleaseService/UpdateRelease", in, out, c.cc, opts...)
-	if err != nil {
+func _ReleaseService_RunReleaseTest_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(TestReleaseRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(ReleaseServiceServer).RunReleaseTest(m, &releaseServiceRunReleaseTestServer{stream})
+}
+
+type ReleaseService_RunReleaseTestServer interface {
+	Send(*TestReleaseResponse) error
+	grpc.ServerStream
+}
+
+type releaseServiceRunReleaseTestServer struct {
+	grpc.ServerStream
+}
+
+func (x *releaseServiceRunReleaseTestServer) Send(m *TestReleaseResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _ReleaseService_GetHealth_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetHealthRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ReleaseServiceServer).GetHealth(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/hapi.services.tiller.ReleaseService/GetHealth",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ReleaseServiceServer).GetHealth(ctx, req.(*GetHealthRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ReleaseService_GetManifest_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetManifestRequest)
+	if err := dec(in); err != nil {
 		return nil, err
 	}
-	return out, nil
+	if interceptor == nil {
+		return srv.(ReleaseServiceServer).GetManifest(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/hapi.services.tiller.ReleaseService/GetManifest",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ReleaseServiceServer).GetManifest(ctx, req.(*GetManifestRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func (c *releaseServiceClient) InstallRelease(ctx context.Context, in *InstallReleaseRequest, opts ...grpc.CallOption) (*InstallReleaseResponse, error) {
-	out := new(InstallReleaseResponse)
-	err := grpc.Invoke(ctx, "/hapi.services.tiller.ReleaseService/InstallRelease", in, out, c.cc, opts...)
-	if err != nil {
+func _ReleaseService_ForceUnlock_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ForceUnlockRequest)
+	if err := dec(in); err != nil {
 		return nil, err
 	}
-	return out, nil
+	if interceptor == nil {
+		return srv.(ReleaseServiceServer).ForceUnlock(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/hapi.services.tiller.ReleaseService/ForceUnlock",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ReleaseServiceServer).ForceUnlock(ctx, req.(*ForceUnlockRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func (c *releaseServiceClient) UninstallRelease(ctx context.Context, in *UninstallReleaseRequest, opts ...grpc.CallOption) (*UninstallReleaseResponse, error) {
-	out := new(UninstallReleaseResponse)
-	err := grpc.Invoke(ctx, "/hapi.services.tiller.ReleaseService/UninstallRelease", in, out, c.cc, opts...)
-	if err != nil {
+func _ReleaseService_BatchInstall_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BatchInstallRequest)
+	if err := dec(in); err != nil {
 		return nil, err
 	}
-	return out, nil
+	if interceptor == nil {
+		return srv.(ReleaseServiceServer).BatchInstall(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/hapi.services.tiller.ReleaseService/BatchInstall",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ReleaseServiceServer).BatchInstall(ctx, req.(*BatchInstallRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func (c *releaseServiceClient) GetVersion(ctx context.Context, in *GetVersionRequest, opts ...grpc.CallOption) (*GetVersionResponse, error) {
-	out := new(GetVersionResponse)
-	err := grpc.Invoke(ctx, "/hapi.services.tiller.ReleaseService/GetVersion", in, out, c.cc, opts...)
-	if err != nil {
+func _ReleaseService_RestoreRelease_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RestoreReleaseRequest)
+	if err := dec(in); err != nil {
 		return nil, err
 	}
-	return out, nil
+	if interceptor == nil {
+		return srv.(ReleaseServiceServer).RestoreRelease(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/hapi.services.tiller.ReleaseService/RestoreRelease",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ReleaseServiceServer).RestoreRelease(ctx, req.(*RestoreReleaseRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func (c *releaseServiceClient) RollbackRelease(ctx context.Context, in *RollbackReleaseRequest, opts ...grpc.CallOption) (*RollbackReleaseResponse, error) {
-	out := new(RollbackReleaseResponse)
-	err := grpc.Invoke(ctx, "/hapi.services.tiller.ReleaseService/RollbackRelease", in, out, c.cc, opts...)
-	if err != nil {
+func _ReleaseService_InspectChart_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(InspectChartRequest)
+	if err := dec(in); err != nil {
 		return nil, err
 	}
-	return out, nil
+	if interceptor == nil {
+		return srv.(ReleaseServiceServer).InspectChart(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/hapi.services.tiller.ReleaseService/InspectChart",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ReleaseServiceServer).InspectChart(ctx, req.(*InspectChartRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func (c *releaseServiceClient) GetHistory(ctx context.Context, in *GetHistoryRequest, opts ...grpc.CallOption) (*GetHistoryResponse, error) {
-	out := new(GetHistoryResponse)
-	err := grpc.Invoke(ctx, "/hapi.services.tiller.ReleaseService/GetHistory", in, out, c.cc, opts...)
-	if err != nil {
+func _ReleaseService_AnnotateRelease_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AnnotateReleaseRequest)
+	if err := dec(in); err != nil {
 		return nil, err
 	}
-	return out, nil
+	if interceptor == nil {
+		return srv.(ReleaseServiceServer).AnnotateRelease(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/hapi.services.tiller.ReleaseService/AnnotateRelease",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ReleaseServiceServer).AnnotateRelease(ctx, req.(*AnnotateReleaseRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func (c *releaseServiceClient) RunReleaseTest(ctx context.Context, in *TestReleaseRequest, opts ...grpc.CallOption) (ReleaseService_RunReleaseTestClient, error) {
-	stream, err := grpc.NewClientStream(ctx, &_ReleaseService_serviceDesc.Streams[1], c.cc, "/hapi.services.tiller.ReleaseService/RunReleaseTest", opts...)
-	if err != nil {
+func _ReleaseService_RestartRelease_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RestartReleaseRequest)
+	if err := dec(in); err != nil {
 		return nil, err
 	}
-	x := &releaseServiceRunReleaseTestClient{stream}
-	if err := x.ClientStream.SendMsg(in); err != nil {
-		return nil, err
+	if interceptor == nil {
+		return srv.(ReleaseServiceServer).RestartRelease(ctx, in)
 	}
-	if err := x.ClientStream.CloseSend(); err != nil {
-		return nil, err
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/hapi.services.tiller.ReleaseService/RestartRelease",
 	}
-	return x, nil
-}
-
-type ReleaseService_RunReleaseTestClient interface {
-	Recv() (*TestReleaseResponse, error)
-	grpc.ClientStream
-}
-
-type releaseServiceRunReleaseTestClient struct {
-	grpc.ClientStream
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ReleaseServiceServer).RestartRelease(ctx, req.(*RestartReleaseRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func (x *releaseServiceRunReleaseTestClient) Recv() (*TestReleaseResponse, error) {
-	m := new(TestReleaseResponse)
-	if err := x.ClientStream.RecvMsg(m); err != nil {
+func _ReleaseService_ApproveRelease_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ApproveReleaseRequest)
+	if err := dec(in); err != nil {
 		return nil, err
 	}
-	return m, nil
-}
-
-// Server API for ReleaseService service
-
-type ReleaseServiceServer interface {
-	// ListReleases retrieves release history.
-	// TODO: Allow filtering the set of releases by
-	// release status. By default, ListAllReleases returns the releases who
-	// current status is "Active".
-	ListReleases(*ListReleasesRequest, ReleaseService_ListReleasesServer) error
-	// GetReleasesStatus retrieves status information for the specified release.
-	GetReleaseStatus(context.Context, *GetReleaseStatusRequest) (*GetReleaseStatusResponse, error)
-	// GetReleaseContent retrieves the release content (chart + value) for the specified release.
-	GetReleaseContent(context.Context, *GetReleaseContentRequest) (*GetReleaseContentResponse, error)
-	// UpdateRelease updates release content.
-	UpdateRelease(context.Context, *UpdateReleaseRequest) (*UpdateReleaseResponse, error)
-	// InstallRelease requests installation of a chart as a new release.
-	InstallRelease(context.Context, *InstallReleaseRequest) (*InstallReleaseResponse, error)
-	// UninstallRelease requests deletion of a named release.
-	UninstallRelease(context.Context, *UninstallReleaseRequest) (*UninstallReleaseResponse, error)
-	// GetVersion returns the current version of the server.
-	GetVersion(context.Context, *GetVersionRequest) (*GetVersionResponse, error)
-	// RollbackRelease rolls back a release to a previous version.
-	RollbackRelease(context.Context, *RollbackReleaseRequest) (*RollbackReleaseResponse, error)
-	// ReleaseHistory retrieves a releasse's history.
-	GetHistory(context.Context, *GetHistoryRequest) (*GetHistoryResponse, error)
-	// RunReleaseTest executes the tests defined of a named release
-	RunReleaseTest(*TestReleaseRequest, ReleaseService_RunReleaseTestServer) error
-}
-
-func RegisterReleaseServiceServer(s *grpc.Server, srv ReleaseServiceServer) {
-	s.RegisterService(&_ReleaseService_serviceDesc, srv)
-}
-
-func _ReleaseService_ListReleases_Handler(srv interface{}, stream grpc.ServerStream) error {
-	m := new(ListReleasesRequest)
-	if err := stream.RecvMsg(m); err != nil {
-		return err
+	if interceptor == nil {
+		return srv.(ReleaseServiceServer).ApproveRelease(ctx, in)
 	}
-	return srv.(ReleaseServiceServer).ListReleases(m, &releaseServiceListReleasesServer{stream})
-}
-
-type ReleaseService_ListReleasesServer interface {
-	Send(*ListReleasesResponse) error
-	grpc.ServerStream
-}
-
-type releaseServiceListReleasesServer struct {
-	grpc.ServerStream
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/hapi.services.tiller.ReleaseService/ApproveRelease",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ReleaseServiceServer).ApproveRelease(ctx, req.(*ApproveReleaseRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func (x *releaseServiceListReleasesServer) Send(m *ListReleasesResponse) error {
-	return x.ServerStream.SendMsg(m)
+func _ReleaseService_RejectRelease_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RejectReleaseRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ReleaseServiceServer).RejectRelease(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/hapi.services.tiller.ReleaseService/RejectRelease",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ReleaseServiceServer).RejectRelease(ctx, req.(*RejectReleaseRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func _ReleaseService_GetReleaseStatus_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(GetReleaseStatusRequest)
+func _ReleaseService_GetReleaseDrift_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetReleaseDriftRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(ReleaseServiceServer).GetReleaseStatus(ctx, in)
+		return srv.(ReleaseServiceServer).GetReleaseDrift(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/hapi.services.tiller.ReleaseService/GetReleaseStatus",
+		FullMethod: "/hapi.services.tiller.ReleaseService/GetReleaseDrift",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(ReleaseServiceServer).GetReleaseStatus(ctx, req.(*GetReleaseStatusRequest))
+		return srv.(ReleaseServiceServer).GetReleaseDrift(ctx, req.(*GetReleaseDriftRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _ReleaseService_GetReleaseContent_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(GetReleaseContentRequest)
+func _ReleaseService_ExportRelease_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ExportReleaseRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(ReleaseServiceServer).GetReleaseContent(ctx, in)
+		return srv.(ReleaseServiceServer).ExportRelease(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/hapi.services.tiller.ReleaseService/GetReleaseContent",
+		FullMethod: "/hapi.services.tiller.ReleaseService/ExportRelease",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(ReleaseServiceServer).GetReleaseContent(ctx, req.(*GetReleaseContentRequest))
+		return srv.(ReleaseServiceServer).ExportRelease(ctx, req.(*ExportReleaseRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _ReleaseService_UpdateRelease_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(UpdateReleaseRequest)
+func _ReleaseService_ResumeRelease_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ResumeReleaseRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(ReleaseServiceServer).UpdateRelease(ctx, in)
+		return srv.(ReleaseServiceServer).ResumeRelease(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/hapi.services.tiller.ReleaseService/UpdateRelease",
+		FullMethod: "/hapi.services.tiller.ReleaseService/ResumeRelease",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(ReleaseServiceServer).UpdateRelease(ctx, req.(*UpdateReleaseRequest))
+		return srv.(ReleaseServiceServer).ResumeRelease(ctx, req.(*ResumeReleaseRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _ReleaseService_InstallRelease_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(InstallReleaseRequest)
+func _ReleaseService_MigrationStatus_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(MigrationStatusRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(ReleaseServiceServer).InstallRelease(ctx, in)
+		return srv.(ReleaseServiceServer).MigrationStatus(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/hapi.services.tiller.ReleaseService/InstallRelease",
+		FullMethod: "/hapi.services.tiller.ReleaseService/MigrationStatus",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(ReleaseServiceServer).InstallRelease(ctx, req.(*InstallReleaseRequest))
+		return srv.(ReleaseServiceServer).MigrationStatus(ctx, req.(*MigrationStatusRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _ReleaseService_UninstallRelease_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(UninstallReleaseRequest)
+func _ReleaseService_SnapshotRelease_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SnapshotReleaseRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(ReleaseServiceServer).UninstallRelease(ctx, in)
+		return srv.(ReleaseServiceServer).SnapshotRelease(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/hapi.services.tiller.ReleaseService/UninstallRelease",
+		FullMethod: "/hapi.services.tiller.ReleaseService/SnapshotRelease",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(ReleaseServiceServer).UninstallRelease(ctx, req.(*UninstallReleaseRequest))
+		return srv.(ReleaseServiceServer).SnapshotRelease(ctx, req.(*SnapshotReleaseRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _ReleaseService_GetVersion_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(GetVersionRequest)
+func _ReleaseService_GetHooks_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetHooksRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(ReleaseServiceServer).GetVersion(ctx, in)
+		return srv.(ReleaseServiceServer).GetHooks(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/hapi.services.tiller.ReleaseService/GetVersion",
+		FullMethod: "/hapi.services.tiller.ReleaseService/GetHooks",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(ReleaseServiceServer).GetVersion(ctx, req.(*GetVersionRequest))
+		return srv.(ReleaseServiceServer).GetHooks(ctx, req.(*GetHooksRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _ReleaseService_RollbackRelease_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(RollbackReleaseRequest)
+func _ReleaseService_InvalidateDiscoveryCache_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(InvalidateDiscoveryCacheRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(ReleaseServiceServer).RollbackRelease(ctx, in)
+		return srv.(ReleaseServiceServer).InvalidateDiscoveryCache(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/hapi.services.tiller.ReleaseService/RollbackRelease",
+		FullMethod: "/hapi.services.tiller.ReleaseService/InvalidateDiscoveryCache",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(ReleaseServiceServer).RollbackRelease(ctx, req.(*RollbackReleaseRequest))
+		return srv.(ReleaseServiceServer).InvalidateDiscoveryCache(ctx, req.(*InvalidateDiscoveryCacheRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _ReleaseService_GetHistory_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(GetHistoryRequest)
+func _ReleaseService_GetReleaseLabels_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetReleaseLabelsRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(ReleaseServiceServer).GetHistory(ctx, in)
+		return srv.(ReleaseServiceServer).GetReleaseLabels(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/hapi.services.tiller.ReleaseService/GetHistory",
+		FullMethod: "/hapi.services.tiller.ReleaseService/GetReleaseLabels",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(ReleaseServiceServer).GetHistory(ctx, req.(*GetHistoryRequest))
+		return srv.(ReleaseServiceServer).GetReleaseLabels(ctx, req.(*GetReleaseLabelsRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _ReleaseService_RunReleaseTest_Handler(srv interface{}, stream grpc.ServerStream) error {
-	m := new(TestReleaseRequest)
+func _ReleaseService_WatchWaitLogs_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(WatchWaitLogsRequest)
 	if err := stream.RecvMsg(m); err != nil {
 		return err
 	}
-	return srv.(ReleaseServiceServer).RunReleaseTest(m, &releaseServiceRunReleaseTestServer{stream})
+	return srv.(ReleaseServiceServer).WatchWaitLogs(m, &releaseServiceWatchWaitLogsServer{stream})
 }
 
-type ReleaseService_RunReleaseTestServer interface {
-	Send(*TestReleaseResponse) error
+type ReleaseService_WatchWaitLogsServer interface {
+	Send(*WatchWaitLogsResponse) error
 	grpc.ServerStream
 }
 
-type releaseServiceRunReleaseTestServer struct {
+type releaseServiceWatchWaitLogsServer struct {
 	grpc.ServerStream
 }
 
-func (x *releaseServiceRunReleaseTestServer) Send(m *TestReleaseResponse) error {
+func (x *releaseServiceWatchWaitLogsServer) Send(m *WatchWaitLogsResponse) error {
 	return x.ServerStream.SendMsg(m)
 }
 
@@ -1350,6 +4616,78 @@ var _ReleaseService_serviceDesc = grpc.ServiceDesc{
 			MethodName: "GetHistory",
 			Handler:    _ReleaseService_GetHistory_Handler,
 		},
+		{
+			MethodName: "GetHealth",
+			Handler:    _ReleaseService_GetHealth_Handler,
+		},
+		{
+			MethodName: "GetManifest",
+			Handler:    _ReleaseService_GetManifest_Handler,
+		},
+		{
+			MethodName: "ForceUnlock",
+			Handler:    _ReleaseService_ForceUnlock_Handler,
+		},
+		{
+			MethodName: "BatchInstall",
+			Handler:    _ReleaseService_BatchInstall_Handler,
+		},
+		{
+			MethodName: "RestoreRelease",
+			Handler:    _ReleaseService_RestoreRelease_Handler,
+		},
+		{
+			MethodName: "InspectChart",
+			Handler:    _ReleaseService_InspectChart_Handler,
+		},
+		{
+			MethodName: "AnnotateRelease",
+			Handler:    _ReleaseService_AnnotateRelease_Handler,
+		},
+		{
+			MethodName: "RestartRelease",
+			Handler:    _ReleaseService_RestartRelease_Handler,
+		},
+		{
+			MethodName: "ApproveRelease",
+			Handler:    _ReleaseService_ApproveRelease_Handler,
+		},
+		{
+			MethodName: "RejectRelease",
+			Handler:    _ReleaseService_RejectRelease_Handler,
+		},
+		{
+			MethodName: "GetReleaseDrift",
+			Handler:    _ReleaseService_GetReleaseDrift_Handler,
+		},
+		{
+			MethodName: "ExportRelease",
+			Handler:    _ReleaseService_ExportRelease_Handler,
+		},
+		{
+			MethodName: "ResumeRelease",
+			Handler:    _ReleaseService_ResumeRelease_Handler,
+		},
+		{
+			MethodName: "MigrationStatus",
+			Handler:    _ReleaseService_MigrationStatus_Handler,
+		},
+		{
+			MethodName: "SnapshotRelease",
+			Handler:    _ReleaseService_SnapshotRelease_Handler,
+		},
+		{
+			MethodName: "GetHooks",
+			Handler:    _ReleaseService_GetHooks_Handler,
+		},
+		{
+			MethodName: "InvalidateDiscoveryCache",
+			Handler:    _ReleaseService_InvalidateDiscoveryCache_Handler,
+		},
+		{
+			MethodName: "GetReleaseLabels",
+			Handler:    _ReleaseService_GetReleaseLabels_Handler,
+		},
 	},
 	Streams: []grpc.StreamDesc{
 		{
@@ -1362,6 +4700,11 @@ var _ReleaseService_serviceDesc = grpc.ServiceDesc{
 			Handler:       _ReleaseService_RunReleaseTest_Handler,
 			ServerStreams: true,
 		},
+		{
+			StreamName:    "WatchWaitLogs",
+			Handler:       _ReleaseService_WatchWaitLogs_Handler,
+			ServerStreams: true,
+		},
 	},
 	Metadata: "hapi/services/tiller.proto",
 }
@@ -1369,82 +4712,266 @@ var _ReleaseService_serviceDesc = grpc.ServiceDesc{
 func init() { proto.RegisterFile("hapi/services/tiller.proto", fileDescriptor0) }
 
 var fileDescriptor0 = []byte{
-	// 1217 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x9c, 0x57, 0xdd, 0x6e, 0xe3, 0xc4,
-	0x17, 0xaf, 0xf3, 0x9d, 0x93, 0x36, 0xff, 0x74, 0x9a, 0xb6, 0xae, 0xff, 0x0b, 0x2a, 0x46, 0xb0,
-	0xd9, 0x85, 0x4d, 0x21, 0x70, 0x83, 0x84, 0x90, 0xba, 0xdd, 0xa8, 0x2d, 0x94, 0xae, 0xe4, 0x6c,
-	0x17, 0x09, 0x01, 0x91, 0x9b, 0x4c, 0x5a, 0xb3, 0x8e, 0x27, 0x78, 0xc6, 0x65, 0x7b, 0xcb, 0x1d,
-	0x8f, 0xc2, 0x5b, 0xf0, 0x1e, 0x5c, 0xc2, 0x83, 0x20, 0xcf, 0x87, 0xeb, 0x49, 0xed, 0xd6, 0xf4,
-	0x26, 0x9e, 0x99, 0xf3, 0xfd, 0x3b, 0x67, 0xce, 0x9c, 0x80, 0x75, 0xe9, 0x2e, 0xbc, 0x3d, 0x8a,
-	0xc3, 0x2b, 0x6f, 0x82, 0xe9, 0x1e, 0xf3, 0x7c, 0x1f, 0x87, 0xfd, 0x45, 0x48, 0x18, 0x41, 0xdd,
-	0x98, 0xd6, 0x57, 0xb4, 0xbe, 0xa0, 0x59, 0x5b, 0x5c, 0x62, 0x72, 0xe9, 0x86, 0x4c, 0xfc, 0x0a,
-	0x6e, 0x6b, 0x3b, 0x7d, 0x4e, 0x82, 0x99, 0x77, 0x21, 0x09, 0xc2, 0x44, 0x88, 0x7d, 0xec, 0x52,
-	0xac, 0xbe, 0x9a, 0x90, 0xa2, 0x79, 0xc1, 0x8c, 0x48, 0xc2, 0xff, 0x35, 0x02, 0xc3, 0x94, 0x8d,
-	0xc3, 0x28, 0x90, 0xc4, 0x1d, 0x8d, 0x48, 0x99, 0xcb, 0x22, 0xaa, 0x19, 0xbb, 0xc2, 0x21, 0xf5,
-	0x48, 0xa0, 0xbe, 0x82, 0x66, 0xff, 0x59, 0x82, 0x8d, 0x13, 0x8f, 0x32, 0x47, 0x08, 0x52, 0x07,
-	0xff, 0x12, 0x61, 0xca, 0x50, 0x17, 0xaa, 0xbe, 0x37, 0xf7, 0x98, 0x69, 0xec, 0x1a, 0xbd, 0xb2,
-	0x23, 0x36, 0x68, 0x0b, 0x6a, 0x64, 0x36, 0xa3, 0x98, 0x99, 0xa5, 0x5d, 0xa3, 0xd7, 0x74, 0xe4,
-	0x0e, 0x7d, 0x05, 0x75, 0x4a, 0x42, 0x36, 0x3e, 0xbf, 0x36, 0xcb, 0xbb, 0x46, 0xaf, 0x3d, 0xf8,
-	0xa0, 0x9f, 0x85, 0x53, 0x3f, 0xb6, 0x34, 0x22, 0x21, 0xeb, 0xc7, 0x3f, 0xcf, 0xaf, 0x9d, 0x1a,
-	0xe5, 0xdf, 0x58, 0xef, 0xcc, 0xf3, 0x19, 0x0e, 0xcd, 0x8a, 0xd0, 0x2b, 0x76, 0xe8, 0x10, 0x80,
-	0xeb, 0x25, 0xe1, 0x14, 0x87, 0x66, 0x95, 0xab, 0xee, 0x15, 0x50, 0xfd, 0x32, 0xe6, 0x77, 0x9a,
-	0x54, 0x2d, 0xd1, 0x97, 0xb0, 0x2a, 0x20, 0x19, 0x4f, 0xc8, 0x14, 0x53, 0xb3, 0xb6, 0x5b, 0xee,
-	0xb5, 0x07, 0x3b, 0x42, 0x95, 0x82, 0x7f, 0x24, 0x40, 0x3b, 0x20, 0x53, 0xec, 0xb4, 0x04, 0x7b,
-	0xbc, 0xa6, 0xe8, 0x11, 0x34, 0x03, 0x77, 0x8e, 0xe9, 0xc2, 0x9d, 0x60, 0xb3, 0xce, 0x3d, 0xbc,
-	0x39, 0xb0, 0x7f, 0x82, 0x86, 0x32, 0x6e, 0x0f, 0xa0, 0x26, 0x42, 0x43, 0x2d, 0xa8, 0x9f, 0x9d,
-	0x7e, 0x73, 0xfa, 0xf2, 0xbb, 0xd3, 0xce, 0x0a, 0x6a, 0x40, 0xe5, 0x74, 0xff, 0xdb, 0x61, 0xc7,
-	0x40, 0xeb, 0xb0, 0x76, 0xb2, 0x3f, 0x7a, 0x35, 0x76, 0x86, 0x27, 0xc3, 0xfd, 0xd1, 0xf0, 0x45,
-	0xa7, 0x64, 0xbf, 0x0b, 0xcd, 0xc4, 0x67, 0x54, 0x87, 0xf2, 0xfe, 0xe8, 0x40, 0x88, 0xbc, 0x18,
-	0x8e, 0x0e, 0x3a, 0x86, 0xfd, 0xbb, 0x01, 0x5d, 0x3d, 0x45, 0x74, 0x41, 0x02, 0x8a, 0xe3, 0x1c,
-	0x4d, 0x48, 0x14, 0x24, 0x39, 0xe2, 0x1b, 0x84, 0xa0, 0x12, 0xe0, 0xb7, 0x2a, 0x43, 0x7c, 0x1d,
-	0x73, 0x32, 0xc2, 0x5c, 0x9f, 0x67, 0xa7, 0xec, 0x88, 0x0d, 0xfa, 0x14, 0x1a, 0x32, 0x74, 0x6a,
-	0x56, 0x76, 0xcb, 0xbd, 0xd6, 0x60, 0x53, 0x07, 0x44, 0x5a, 0x74, 0x12, 0x36, 0xfb, 0x10, 0xb6,
-	0x0f, 0xb1, 0xf2, 0x44, 0xe0, 0xa5, 0x2a, 0x26, 0xb6, 0xeb, 0xce, 0x31, 0x77, 0x26, 0xb6, 0xeb,
-	0xce, 0x31, 0x32, 0xa1, 0x2e, 0xcb, 0x8d, 0xbb, 0x53, 0x75, 0xd4, 0xd6, 0x66, 0x60, 0xde, 0x56,
-	0x24, 0xe3, 0xca, 0xd2, 0xf4, 0x21, 0x54, 0xe2, 0x9b, 0xc0, 0xd5, 0xb4, 0x06, 0x48, 0xf7, 0xf3,
-	0x38, 0x98, 0x11, 0x87, 0xd3, 0xf5, 0x54, 0x95, 0x97, 0x53, 0x75, 0x94, 0xb6, 0x7a, 0x40, 0x02,
-	0x86, 0x03, 0xf6, 0x30, 0xff, 0x4f, 0x60, 0x27, 0x43, 0x93, 0x0c, 0x60, 0x0f, 0xea, 0xd2, 0x35,
-	0xae, 0x2d, 0x17, 0x57, 0xc5, 0x65, 0xff, 0x5d, 0x82, 0xee, 0xd9, 0x62, 0xea, 0x32, 0xac, 0x48,
-	0x77, 0x38, 0xf5, 0x18, 0xaa, 0xbc, 0xa3, 0x48, 0x2c, 0xd6, 0x85, 0x6e, 0xd1, 0x76, 0x0e, 0xe2,
-	0x5f, 0x47, 0xd0, 0xd1, 0x53, 0xa8, 0x5d, 0xb9, 0x7e, 0x84, 0x29, 0x07, 0x22, 0x41, 0x4d, 0x72,
-	0xf2, 0x76, 0xe4, 0x48, 0x0e, 0xb4, 0x0d, 0xf5, 0x69, 0x78, 0x1d, 0xf7, 0x13, 0x7e, 0x05, 0x1b,
-	0x4e, 0x6d, 0x1a, 0x5e, 0x3b, 0x51, 0x80, 0xde, 0x87, 0xb5, 0xa9, 0x47, 0xdd, 0x73, 0x1f, 0x8f,
-	0x2f, 0x09, 0x79, 0x43, 0xf9, 0x2d, 0x6c, 0x38, 0xab, 0xf2, 0xf0, 0x28, 0x3e, 0x43, 0x56, 0x5c,
-	0x49, 0x93, 0x10, 0xbb, 0x0c, 0x9b, 0x35, 0x4e, 0x4f, 0xf6, 0x31, 0x86, 0xcc, 0x9b, 0x63, 0x12,
-	0x31, 0x7e, 0x75, 0xca, 0x8e, 0xda, 0xa2, 0xf7, 0x60, 0x35, 0xc4, 0x14, 0xb3, 0xb1, 0xf4, 0xb2,
-	0xc1, 0x25, 0x5b, 0xfc, 0xec, 0xb5, 0x70, 0x0b, 0x41, 0xe5, 0x57, 0xd7, 0x63, 0x66, 0x93, 0x93,
-	0xf8, 0x5a, 0x88, 0x45, 0x14, 0x2b, 0x31, 0x50, 0x62, 0x11, 0xc5, 0x52, 0xac, 0x0b, 0xd5, 0x19,
-	0x09, 0x27, 0xd8, 0x6c, 0x71, 0x9a, 0xd8, 0xd8, 0x47, 0xb0, 0xb9, 0x04, 0xf2, 0x43, 0xf3, 0xf5,
-	0x8f, 0x01, 0x5b, 0x0e, 0xf1, 0xfd, 0x73, 0x77, 0xf2, 0xa6, 0x40, 0xc6, 0x52, 0xe0, 0x96, 0xee,
-	0x06, 0xb7, 0x9c, 0x01, 0x6e, 0xaa, 0x08, 0x2b, 0x5a, 0x11, 0x6a, 0xb0, 0x57, 0xf3, 0x61, 0xaf,
-	0xe9, 0xb0, 0x2b, 0x4c, 0xeb, 0x29, 0x4c, 0x13, 0xc0, 0x1a, 0x69, 0xc0, 0xbe, 0x86, 0xed, 0x5b,
-	0x51, 0x3e, 0x14, 0xb2, 0x3f, 0x4a, 0xb0, 0x79, 0x1c, 0x50, 0xe6, 0xfa, 0xfe, 0x12, 0x62, 0x49,
-	0x3d, 0x1b, 0x85, 0xeb, 0xb9, 0xf4, 0x5f, 0xea, 0xb9, 0xac, 0x41, 0xae, 0xf2, 0x53, 0x49, 0xe5,
-	0xa7, 0x50, 0x8d, 0x6b, 0x9d, 0xa5, 0xb6, 0xd4, 0x59, 0xd0, 0x3b, 0x00, 0xa2, 0x28, 0xb9, 0x72,
-	0x01, 0x6d, 0x93, 0x9f, 0x9c, 0xca, 0x46, 0xa2, 0xb2, 0xd1, 0xc8, 0xce, 0x46, 0xaa, 0xc2, 0xed,
-	0x63, 0xd8, 0x5a, 0x86, 0xea, 0xa1, 0xb0, 0xff, 0x66, 0xc0, 0xf6, 0x59, 0xe0, 0x65, 0x02, 0x9f,
-	0x55, 0xaa, 0xb7, 0xa0, 0x28, 0x65, 0x40, 0xd1, 0x85, 0xea, 0x22, 0x0a, 0x2f, 0xb0, 0x84, 0x56,
-	0x6c, 0xd2, 0x31, 0x56, 0xb4, 0x18, 0xed, 0x31, 0x98, 0xb7, 0x7d, 0x78, 0x60, 0x44, 0xb1, 0xd7,
-	0xc9, 0x4b, 0xd0, 0x14, 0x5d, 0xdf, 0xde, 0x80, 0xf5, 0x43, 0xcc, 0x5e, 0x8b, 0x6b, 0x21, 0xc3,
-	0xb3, 0x87, 0x80, 0xd2, 0x87, 0x37, 0xf6, 0xe4, 0x91, 0x6e, 0x4f, 0x8d, 0x45, 0x8a, 0x5f, 0x71,
-	0xd9, 0x5f, 0x70, 0xdd, 0x47, 0x1e, 0x65, 0x24, 0xbc, 0xbe, 0x0b, 0xba, 0x0e, 0x94, 0xe7, 0xee,
-	0x5b, 0xf9, 0x50, 0xc4, 0x4b, 0xfb, 0x90, 0x7b, 0x90, 0x88, 0x4a, 0x0f, 0xd2, 0xcf, 0xae, 0x51,
-	0xec, 0xd9, 0xfd, 0x01, 0xd0, 0x2b, 0x9c, 0x4c, 0x00, 0xf7, 0xbc, 0x58, 0x2a, 0x09, 0x25, 0xbd,
-	0xd0, 0x4c, 0xa8, 0x4f, 0x7c, 0xec, 0x06, 0xd1, 0x42, 0xa6, 0x4d, 0x6d, 0xed, 0x1f, 0x61, 0x43,
-	0xd3, 0x2e, 0xfd, 0x8c, 0xe3, 0xa1, 0x17, 0x52, 0x7b, 0xbc, 0x44, 0x9f, 0x43, 0x4d, 0x8c, 0x45,
-	0x5c, 0x77, 0x7b, 0xf0, 0x48, 0xf7, 0x9b, 0x2b, 0x89, 0x02, 0x39, 0x47, 0x39, 0x92, 0x77, 0xf0,
-	0x57, 0x03, 0xda, 0xea, 0xa1, 0x17, 0x43, 0x1b, 0xf2, 0x60, 0x35, 0x3d, 0xd1, 0xa0, 0x27, 0xf9,
-	0x33, 0xdd, 0xd2, 0x60, 0x6a, 0x3d, 0x2d, 0xc2, 0x2a, 0x22, 0xb0, 0x57, 0x3e, 0x31, 0x10, 0x85,
-	0xce, 0xf2, 0xa0, 0x81, 0x9e, 0x65, 0xeb, 0xc8, 0x99, 0x6c, 0xac, 0x7e, 0x51, 0x76, 0x65, 0x16,
-	0x5d, 0xf1, 0x9a, 0xd1, 0xa7, 0x03, 0x74, 0xaf, 0x1a, 0x7d, 0x20, 0xb1, 0xf6, 0x0a, 0xf3, 0x27,
-	0x76, 0x7f, 0x86, 0x35, 0xed, 0x85, 0x43, 0x39, 0x68, 0x65, 0xcd, 0x1a, 0xd6, 0x47, 0x85, 0x78,
-	0x13, 0x5b, 0x73, 0x68, 0xeb, 0x4d, 0x0a, 0xe5, 0x28, 0xc8, 0xec, 0xfa, 0xd6, 0xc7, 0xc5, 0x98,
-	0x13, 0x73, 0x14, 0x3a, 0xcb, 0x3d, 0x24, 0x2f, 0x8f, 0x39, 0xfd, 0x2e, 0x2f, 0x8f, 0x79, 0xad,
-	0xc9, 0x5e, 0x41, 0x2e, 0xc0, 0x4d, 0x0b, 0x41, 0x8f, 0x73, 0x13, 0xa2, 0x77, 0x1e, 0xab, 0x77,
-	0x3f, 0x63, 0x62, 0x62, 0x01, 0xff, 0x5b, 0x7a, 0x63, 0x51, 0x0e, 0x34, 0xd9, 0x03, 0x87, 0xf5,
-	0xac, 0x20, 0xf7, 0x52, 0x50, 0xb2, 0x2b, 0xdd, 0x11, 0x94, 0xde, 0xf2, 0xee, 0x08, 0x6a, 0xa9,
-	0xc1, 0xd9, 0x2b, 0xc8, 0x83, 0xb6, 0x13, 0x05, 0xd2, 0x74, 0xdc, 0x16, 0x50, 0x8e, 0xf4, 0xed,
-	0xae, 0x66, 0x3d, 0x29, 0xc0, 0x79, 0x73, 0xbf, 0x9f, 0xc3, 0xf7, 0x0d, 0xc5, 0x7a, 0x5e, 0xe3,
-	0xff, 0x69, 0x3f, 0xfb, 0x37, 0x00, 0x00, 0xff, 0xff, 0xf3, 0x7c, 0x9c, 0x49, 0xc1, 0x0f, 0x00,
-	0x00,
+	// 4163 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xd4, 0x3a, 0x4d, 0x73, 0xdb, 0x48,
+	0x76, 0x43, 0xf1, 0x43, 0xe4, 0x23, 0x25, 0x51, 0xad, 0x2f, 0x18, 0xb6, 0x67, 0x34, 0xf0, 0xce,
+	0x8c, 0x6c, 0xd9, 0xf2, 0xac, 0x92, 0x9d, 0x4c, 0x76, 0x66, 0x77, 0x43, 0x49, 0xb4, 0x47, 0xb6,
+	0x3e, 0x5c, 0x90, 0xc7, 0xde, 0xd9, 0x64, 0x07, 0x05, 0x83, 0x4d, 0x0a, 0x6b, 0x10, 0xc0, 0x02,
+	0x4d, 0xd9, 0x3a, 0x24, 0xb5, 0xc9, 0x29, 0xa9, 0x9c, 0x72, 0xca, 0x1f, 0x48, 0x72, 0xc8, 0x1f,
+	0xd8, 0xaa, 0x5c, 0x72, 0x48, 0xe5, 0x90, 0x5c, 0x72, 0xcc, 0x39, 0xff, 0x21, 0xb7, 0x3d, 0x24,
+	0xd5, 0x5f, 0x60, 0x03, 0x04, 0x25, 0x48, 0x9e, 0x9a, 0x4a, 0x2e, 0x24, 0xfa, 0xf5, 0xeb, 0xd7,
+	0xaf, 0x5f, 0xbf, 0x7e, 0x5f, 0xdd, 0xa0, 0x9f, 0xda, 0xa1, 0xfb, 0x30, 0xc6, 0xd1, 0x99, 0xeb,
+	0xe0, 0xf8, 0x21, 0x71, 0x3d, 0x0f, 0x47, 0x5b, 0x61, 0x14, 0x90, 0x00, 0x2d, 0xd3, 0xbe, 0x2d,
+	0xd9, 0xb7, 0xc5, 0xfb, 0xf4, 0x0f, 0x06, 0x41, 0x30, 0xf0, 0xf0, 0x43, 0x86, 0xf3, 0x6a, 0xd4,
+	0x7f, 0x48, 0xdc, 0x21, 0x8e, 0x89, 0x3d, 0x0c, 0xf9, 0x30, 0x7d, 0x95, 0x91, 0x74, 0x4e, 0xed,
+	0x88, 0xf0, 0x5f, 0x01, 0x5f, 0x53, 0xe1, 0x81, 0xdf, 0x77, 0x07, 0xa2, 0xe3, 0x86, 0xd2, 0x31,
+	0xc4, 0xc4, 0xee, 0xd9, 0xc4, 0x4e, 0x8d, 0x89, 0xb0, 0x87, 0xed, 0x18, 0x3f, 0x3c, 0x0d, 0x82,
+	0xd7, 0xa2, 0x43, 0x4f, 0x75, 0x88, 0xff, 0xdc, 0x41, 0xae, 0xdf, 0x0f, 0x44, 0xc7, 0xcd, 0x54,
+	0x07, 0xc1, 0x31, 0xb1, 0xa2, 0x91, 0x9f, 0xe2, 0x42, 0x76, 0xc6, 0xc4, 0x26, 0xa3, 0x38, 0x35,
+	0xd9, 0x19, 0x8e, 0x62, 0x37, 0xf0, 0xe5, 0x3f, 0xef, 0x33, 0x7e, 0x5b, 0x86, 0xa5, 0x03, 0x37,
+	0x26, 0x26, 0x1f, 0x18, 0x9b, 0xf8, 0xd7, 0x23, 0x1c, 0x13, 0xb4, 0x0c, 0x55, 0xcf, 0x1d, 0xba,
+	0x44, 0x2b, 0xad, 0x97, 0x36, 0xca, 0x26, 0x6f, 0xa0, 0x55, 0xa8, 0x05, 0xfd, 0x7e, 0x8c, 0x89,
+	0x36, 0xb3, 0x5e, 0xda, 0x68, 0x98, 0xa2, 0x85, 0x7e, 0x0a, 0xb3, 0x71, 0x10, 0x11, 0xeb, 0xd5,
+	0xb9, 0x56, 0x5e, 0x2f, 0x6d, 0xcc, 0x6f, 0x7f, 0xb4, 0x95, 0x27, 0xfc, 0x2d, 0x3a, 0xd3, 0x49,
+	0x10, 0x91, 0x2d, 0xfa, 0xb3, 0x73, 0x6e, 0xd6, 0x62, 0xf6, 0x4f, 0xe9, 0xf6, 0x5d, 0x8f, 0xe0,
+	0x48, 0xab, 0x70, 0xba, 0xbc, 0x85, 0x1e, 0x03, 0x30, 0xba, 0x41, 0xd4, 0xc3, 0x91, 0x56, 0x65,
+	0xa4, 0x37, 0x0a, 0x90, 0x3e, 0xa6, 0xf8, 0x66, 0x23, 0x96, 0x9f, 0xe8, 0x4b, 0x68, 0x71, 0x91,
+	0x58, 0x4e, 0xd0, 0xc3, 0xb1, 0x56, 0x5b, 0x2f, 0x6f, 0xcc, 0x6f, 0xdf, 0xe0, 0xa4, 0xa4, 0xf8,
+	0x4f, 0xb8, 0xd0, 0x76, 0x83, 0x1e, 0x36, 0x9b, 0x1c, 0x9d, 0x7e, 0xc7, 0xe8, 0x16, 0x34, 0x7c,
+	0x7b, 0x88, 0xe3, 0xd0, 0x76, 0xb0, 0x36, 0xcb, 0x38, 0x1c, 0x03, 0xd0, 0x11, 0xcc, 0x05, 0x23,
+	0x12, 0x8e, 0x88, 0xd5, 0x0f, 0xa2, 0xa1, 0x4d, 0xb4, 0x3a, 0xe3, 0xf3, 0x6e, 0x3e, 0x9f, 0xc7,
+	0x0c, 0xf5, 0x11, 0xc3, 0xdc, 0xe2, 0x7f, 0x66, 0x2b, 0x50, 0x80, 0xe8, 0x23, 0x98, 0x77, 0x7d,
+	0xc7, 0x1b, 0xf5, 0xb0, 0x15, 0x9f, 0xc7, 0x04, 0x0f, 0xb5, 0xc6, 0x7a, 0x69, 0xa3, 0x6e, 0xce,
+	0x09, 0xe8, 0x09, 0x03, 0x1a, 0x1d, 0x68, 0xa9, 0xb4, 0x8c, 0x1f, 0x42, 0x4d, 0x10, 0xa8, 0x43,
+	0xe5, 0xe8, 0xf8, 0xa8, 0xdb, 0x7e, 0x8f, 0x7e, 0x3d, 0x39, 0x39, 0x3e, 0x6a, 0x97, 0xe8, 0xd7,
+	0x37, 0x9d, 0xc3, 0x83, 0xf6, 0x0c, 0x6a, 0x40, 0xf5, 0x79, 0x67, 0xe7, 0xa0, 0xdb, 0x2e, 0x1b,
+	0xdf, 0x42, 0x5d, 0x8a, 0xcd, 0xd8, 0x86, 0x1a, 0xdf, 0x14, 0xd4, 0x84, 0xd9, 0xaf, 0x8f, 0x9e,
+	0x1e, 0x1d, 0xbf, 0x3c, 0xe2, 0x14, 0x8e, 0x3a, 0x87, 0xdd, 0x76, 0x09, 0x2d, 0xc2, 0xdc, 0x41,
+	0xe7, 0xe4, 0xb9, 0x65, 0x76, 0x0f, 0xba, 0x9d, 0x93, 0xee, 0x5e, 0x7b, 0xc6, 0x78, 0x1f, 0x1a,
+	0x89, 0xb4, 0xd1, 0x2c, 0x94, 0x3b, 0x27, 0xbb, 0x7c, 0xc8, 0x5e, 0xf7, 0x64, 0xb7, 0x5d, 0x32,
+	0xfe, 0xbe, 0x04, 0xcb, 0x69, 0xe5, 0x8a, 0xc3, 0xc0, 0x8f, 0x31, 0xd5, 0x2e, 0x27, 0x18, 0xf9,
+	0x89, 0x76, 0xb1, 0x06, 0x42, 0x50, 0xf1, 0xf1, 0x5b, 0xa9, 0x5b, 0xec, 0x9b, 0x62, 0x92, 0x80,
+	0xd8, 0x1e, 0xd3, 0xab, 0xb2, 0xc9, 0x1b, 0xe8, 0x87, 0x50, 0x17, 0x9b, 0x16, 0x6b, 0x95, 0xf5,
+	0xf2, 0x46, 0x73, 0x7b, 0x25, 0xbd, 0x95, 0x62, 0x46, 0x33, 0x41, 0x43, 0x3a, 0x1d, 0xe2, 0xf7,
+	0x70, 0x84, 0x7b, 0x4c, 0x91, 0x1a, 0x66, 0xd2, 0x36, 0xfe, 0xb6, 0x04, 0x6b, 0x8f, 0xb1, 0x64,
+	0x93, 0xab, 0x81, 0x3c, 0x08, 0x94, 0x29, 0x7b, 0x88, 0xb5, 0x92, 0x60, 0xca, 0x1e, 0x62, 0xa4,
+	0xc1, 0xac, 0x38, 0x45, 0x8c, 0xd7, 0xaa, 0x29, 0x9b, 0x93, 0xba, 0x50, 0x7e, 0x27, 0x5d, 0x30,
+	0xfe, 0xbd, 0x04, 0xda, 0x24, 0x67, 0x42, 0x8a, 0x79, 0xac, 0x7d, 0x0c, 0x15, 0x6a, 0x31, 0x18,
+	0x5f, 0xcd, 0x6d, 0x94, 0x96, 0xca, 0xbe, 0xdf, 0x0f, 0x4c, 0xd6, 0x9f, 0x56, 0xe9, 0x72, 0x56,
+	0xa5, 0xdf, 0x07, 0x48, 0x1a, 0x5c, 0xc2, 0x0d, 0x53, 0x81, 0x5c, 0x24, 0x4c, 0x2a, 0x1c, 0xc7,
+	0x1b, 0xc5, 0xf4, 0x30, 0xd7, 0x58, 0x97, 0x6c, 0x1a, 0x5f, 0xa9, 0x6b, 0xd9, 0x0d, 0x7c, 0x82,
+	0x7d, 0x72, 0x2d, 0x31, 0x1b, 0x07, 0x70, 0x23, 0x87, 0x92, 0x10, 0xcb, 0x43, 0x98, 0x15, 0x0b,
+	0x66, 0xd4, 0xa6, 0xea, 0x86, 0xc4, 0x32, 0x76, 0x00, 0x3d, 0xc6, 0xe4, 0xd0, 0xf6, 0xdd, 0x3e,
+	0x8e, 0xaf, 0xc9, 0xd1, 0x53, 0x58, 0x4a, 0xd1, 0x10, 0xbc, 0x28, 0x03, 0x4a, 0x69, 0x4d, 0xd1,
+	0xa1, 0x3e, 0x14, 0xd8, 0x42, 0xe1, 0x93, 0x36, 0x65, 0xe8, 0x51, 0x10, 0x39, 0xf8, 0x6b, 0xdf,
+	0x0b, 0x9c, 0xd7, 0x97, 0x30, 0xc4, 0x7c, 0x51, 0x34, 0x14, 0x44, 0x64, 0xd3, 0x38, 0x82, 0xa5,
+	0x14, 0x0d, 0xc1, 0xd0, 0x6d, 0x80, 0x37, 0x76, 0x6c, 0x51, 0x18, 0xee, 0x31, 0x52, 0x75, 0xb3,
+	0xf1, 0xc6, 0x8e, 0x0f, 0x18, 0x80, 0xd2, 0x7b, 0x63, 0x47, 0xbe, 0xeb, 0x0f, 0x24, 0x3d, 0xd1,
+	0x34, 0xfe, 0xb5, 0x05, 0xcb, 0x5f, 0x87, 0x3d, 0x9b, 0x60, 0x29, 0xbf, 0x0b, 0xd8, 0xfa, 0x04,
+	0xaa, 0xcc, 0x1f, 0x0a, 0x35, 0x5c, 0xe4, 0x1b, 0xc0, 0x40, 0x5b, 0xbb, 0xf4, 0xd7, 0xe4, 0xfd,
+	0xe8, 0x1e, 0xd4, 0xce, 0x6c, 0x6f, 0x84, 0x63, 0xad, 0xac, 0x2a, 0xac, 0xc0, 0x64, 0x5e, 0xd6,
+	0x14, 0x18, 0x68, 0x0d, 0x66, 0x7b, 0xd1, 0x39, 0x75, 0x79, 0xcc, 0x4b, 0xd4, 0xcd, 0x5a, 0x2f,
+	0x3a, 0x37, 0x47, 0x3e, 0xba, 0x03, 0x73, 0x3d, 0x37, 0xb6, 0x5f, 0x79, 0xd8, 0xa2, 0x2e, 0x36,
+	0x66, 0x2a, 0x59, 0x37, 0x5b, 0x02, 0xf8, 0x15, 0x85, 0x71, 0x95, 0x75, 0x22, 0x6c, 0x13, 0xcc,
+	0xf4, 0xb2, 0x6e, 0x26, 0x6d, 0xba, 0x6a, 0x1a, 0x05, 0x04, 0x23, 0xc2, 0xac, 0x7b, 0xd9, 0x94,
+	0x4d, 0xf4, 0x21, 0xb4, 0x22, 0x1c, 0x63, 0x62, 0x09, 0x2e, 0xeb, 0x6c, 0x64, 0x93, 0xc1, 0x5e,
+	0x70, 0xb6, 0x10, 0x54, 0xde, 0xd8, 0x2e, 0x11, 0x46, 0x9a, 0x7d, 0xf3, 0x61, 0xa3, 0x18, 0xcb,
+	0x61, 0x20, 0x87, 0x8d, 0x62, 0x2c, 0x86, 0x2d, 0x43, 0xb5, 0x4f, 0xf7, 0x47, 0x6b, 0xb2, 0x3e,
+	0xde, 0x40, 0x3f, 0x80, 0x79, 0x6a, 0x24, 0x70, 0x64, 0xc9, 0xa5, 0xb6, 0xf8, 0x5a, 0x38, 0x74,
+	0x8f, 0x2f, 0xf8, 0x36, 0x40, 0xfc, 0xda, 0x0d, 0xc5, 0x6a, 0xe7, 0xd8, 0xf1, 0x6c, 0x50, 0x08,
+	0x5f, 0xea, 0x3d, 0x58, 0x4c, 0xba, 0xad, 0x37, 0xd8, 0x1d, 0x9c, 0x92, 0x58, 0x9b, 0x5f, 0x2f,
+	0x6f, 0x54, 0xcd, 0x05, 0x89, 0xf5, 0x92, 0x83, 0xa9, 0xec, 0xce, 0x70, 0xe4, 0xf6, 0xcf, 0x2d,
+	0x77, 0x68, 0x0f, 0x70, 0xac, 0xb5, 0xf9, 0x7c, 0x1c, 0xb8, 0xcf, 0x60, 0xe8, 0x97, 0xd0, 0xb4,
+	0x7d, 0x3f, 0x20, 0x36, 0x71, 0x03, 0x3f, 0xd6, 0x16, 0x99, 0xc5, 0xfd, 0x22, 0xdf, 0xa6, 0xe5,
+	0xe9, 0xc8, 0x56, 0x67, 0x3c, 0xba, 0xeb, 0x93, 0xe8, 0xdc, 0x54, 0xe9, 0xa1, 0xbb, 0xd0, 0x8e,
+	0xf0, 0xaf, 0x47, 0x6e, 0x84, 0x2d, 0x3b, 0x0c, 0xa3, 0xe0, 0xcc, 0xf6, 0x34, 0xc4, 0xd8, 0x58,
+	0x10, 0xf0, 0x8e, 0x00, 0x53, 0x54, 0x89, 0x62, 0xc9, 0x2d, 0x5b, 0x62, 0x5b, 0xb6, 0x20, 0xe1,
+	0xcf, 0xc7, 0x5b, 0x37, 0x88, 0x6c, 0x07, 0x5b, 0x21, 0x8e, 0xdc, 0xa0, 0xa7, 0x2d, 0x33, 0xb4,
+	0x26, 0x83, 0x3d, 0x63, 0x20, 0xf4, 0x00, 0x50, 0x18, 0x05, 0xa1, 0x3d, 0x60, 0x8c, 0x58, 0x61,
+	0xe0, 0xb9, 0xce, 0xb9, 0xb6, 0xc2, 0x14, 0x79, 0x51, 0xe9, 0x79, 0xc6, 0x3a, 0xd0, 0x4f, 0xe0,
+	0xa6, 0x54, 0x19, 0x2b, 0xf0, 0xad, 0x18, 0x7b, 0xd8, 0x21, 0x41, 0x64, 0x39, 0xa7, 0xb6, 0x3f,
+	0xc0, 0xda, 0x2a, 0x63, 0x59, 0x93, 0x28, 0xc7, 0xfe, 0x89, 0x40, 0xd8, 0x65, 0xfd, 0x54, 0xcb,
+	0xc2, 0x28, 0xe8, 0xbb, 0x1e, 0xd6, 0xd6, 0xf8, 0xd9, 0x12, 0x4d, 0xb4, 0x0d, 0x2b, 0xb6, 0xe7,
+	0x05, 0x6f, 0xac, 0xa1, 0x1b, 0xc7, 0xae, 0x3f, 0xb0, 0x24, 0x9e, 0xc6, 0x48, 0x2e, 0xb1, 0xce,
+	0x43, 0xde, 0xf7, 0x4c, 0x8c, 0xf9, 0x10, 0x5a, 0xd8, 0x57, 0x74, 0xfe, 0x06, 0x57, 0x31, 0x0e,
+	0xe3, 0x7a, 0xa0, 0x58, 0x62, 0x3d, 0x65, 0x89, 0xa9, 0x02, 0x05, 0xbe, 0xd5, 0xb7, 0x5d, 0x6f,
+	0x14, 0x61, 0xed, 0x26, 0x37, 0xff, 0x81, 0xff, 0x88, 0x03, 0xd0, 0x26, 0x2c, 0x0a, 0xa5, 0x88,
+	0x70, 0x1f, 0x47, 0xd8, 0xa7, 0x5e, 0xe0, 0x16, 0x9b, 0xa0, 0xcd, 0x3b, 0xcc, 0x04, 0x4e, 0xc3,
+	0x15, 0xb1, 0x13, 0xd6, 0xab, 0x51, 0x6f, 0x80, 0x89, 0x76, 0x9b, 0x49, 0x7a, 0x4e, 0x40, 0x77,
+	0x18, 0x10, 0x7d, 0x06, 0x6b, 0x7c, 0x8d, 0x34, 0xee, 0xc4, 0x0e, 0xc1, 0x3d, 0x21, 0xb7, 0x58,
+	0x7b, 0x9f, 0x51, 0xe6, 0x22, 0x78, 0x26, 0x7b, 0xb9, 0xd0, 0x98, 0x82, 0xc6, 0x24, 0x72, 0x9d,
+	0xe4, 0x08, 0x7e, 0x20, 0x0e, 0x04, 0x03, 0x8a, 0xc3, 0xd4, 0x81, 0x39, 0x77, 0x18, 0xe2, 0x28,
+	0x0e, 0x7c, 0xb6, 0x61, 0xda, 0x3a, 0xb3, 0x26, 0x37, 0x33, 0xee, 0x4f, 0x45, 0x31, 0xd3, 0x23,
+	0xd0, 0x07, 0xd0, 0xec, 0xd1, 0x45, 0x59, 0x7e, 0x40, 0x70, 0xac, 0x7d, 0xc8, 0x66, 0x01, 0x06,
+	0x3a, 0xa2, 0x10, 0xf4, 0x14, 0xaa, 0x7d, 0xcf, 0x1e, 0xc4, 0x9a, 0xc1, 0xd4, 0xff, 0x47, 0x57,
+	0x50, 0xff, 0x47, 0x74, 0x1c, 0x57, 0x7c, 0x4e, 0x83, 0xee, 0xde, 0x6b, 0x8c, 0x43, 0x2b, 0xc2,
+	0xc3, 0xe0, 0x0c, 0xf7, 0xb4, 0x3b, 0x7c, 0xf7, 0x28, 0xcc, 0xe4, 0x20, 0xb4, 0x01, 0xed, 0xf1,
+	0x29, 0xf6, 0x82, 0xe0, 0xf5, 0x28, 0xd4, 0x7e, 0xc0, 0xd0, 0xe6, 0xe5, 0x21, 0x3e, 0x60, 0x50,
+	0x6a, 0x34, 0xa8, 0xd5, 0xb1, 0xbc, 0x60, 0x60, 0xbd, 0x3a, 0xa7, 0xdc, 0x7f, 0xc4, 0x76, 0xa0,
+	0x45, 0xa1, 0x07, 0xc1, 0x60, 0x87, 0xc2, 0xf4, 0x9f, 0x42, 0x3b, 0x7b, 0x0c, 0x51, 0x1b, 0xca,
+	0xaf, 0xf1, 0xb9, 0x30, 0xdd, 0xf4, 0x93, 0x9a, 0x25, 0x26, 0x67, 0x61, 0xfe, 0x79, 0xe3, 0xc7,
+	0x33, 0x9f, 0x97, 0xf4, 0xcf, 0x01, 0xc6, 0xeb, 0xb8, 0x6c, 0x64, 0x5d, 0x19, 0xf9, 0xa4, 0x52,
+	0x5f, 0x68, 0xb7, 0xcd, 0x6a, 0x18, 0x8d, 0x7c, 0x6c, 0xfc, 0x79, 0x09, 0x56, 0x32, 0x42, 0xba,
+	0xa6, 0xdf, 0x46, 0x7f, 0x00, 0x55, 0xae, 0xfb, 0x33, 0x6c, 0x47, 0x3e, 0xcc, 0xdf, 0x11, 0x2a,
+	0xa8, 0x67, 0x11, 0x3e, 0x73, 0xf1, 0x1b, 0x93, 0xe3, 0x1b, 0xff, 0x5d, 0x83, 0x55, 0x33, 0xf0,
+	0xbc, 0x57, 0x36, 0xf5, 0x8c, 0x97, 0x7a, 0x33, 0xc5, 0xf1, 0xcc, 0x5c, 0xec, 0x78, 0xca, 0x39,
+	0x8e, 0x47, 0x09, 0x01, 0x2a, 0x13, 0x21, 0x40, 0xe2, 0x92, 0xaa, 0xd3, 0x5d, 0x52, 0x2d, 0xed,
+	0x92, 0xa4, 0xbf, 0x99, 0x55, 0xfc, 0x4d, 0xe2, 0x4c, 0xea, 0xaa, 0x33, 0xa1, 0x06, 0xc7, 0x8e,
+	0x88, 0x6b, 0x7b, 0xc2, 0x39, 0xc9, 0x66, 0xc6, 0x81, 0x40, 0x21, 0x07, 0xd2, 0xcc, 0x77, 0x20,
+	0x59, 0x33, 0xdb, 0x2a, 0x6a, 0x66, 0xe7, 0xae, 0x69, 0x66, 0xe7, 0x2f, 0x31, 0xb3, 0x59, 0xc3,
+	0xb8, 0x30, 0x69, 0x18, 0x6f, 0x42, 0x23, 0xc2, 0x16, 0x8f, 0x58, 0x85, 0xc3, 0xab, 0x47, 0xd8,
+	0x64, 0x6d, 0x25, 0x24, 0x59, 0xbc, 0x34, 0x24, 0xc9, 0x3b, 0xa3, 0x28, 0xf7, 0x8c, 0x4e, 0x5a,
+	0xc9, 0xa5, 0x0b, 0xad, 0x64, 0x0f, 0xc7, 0x24, 0x1a, 0x39, 0xc4, 0x3d, 0x93, 0xeb, 0x58, 0x56,
+	0xac, 0xe4, 0xde, 0xb8, 0x97, 0xaf, 0x68, 0xc2, 0x00, 0xae, 0x5c, 0xd9, 0x00, 0x7e, 0x41, 0x0d,
+	0x60, 0xe8, 0x05, 0xe7, 0xb8, 0x67, 0xd9, 0x84, 0x79, 0xb3, 0xe6, 0xb6, 0xbe, 0xc5, 0xcb, 0x25,
+	0x5b, 0xb2, 0x5c, 0xb2, 0xf5, 0x5c, 0x96, 0x4b, 0x4c, 0x90, 0xe8, 0x1d, 0x42, 0x4f, 0x42, 0x3f,
+	0x0a, 0x86, 0x56, 0xec, 0xdb, 0x61, 0x7c, 0x1a, 0x10, 0xe6, 0xe1, 0xea, 0x66, 0x8b, 0x02, 0x4f,
+	0x04, 0xcc, 0xf8, 0xe7, 0x12, 0xac, 0x4d, 0x1c, 0xbb, 0xef, 0xfb, 0xf0, 0xa3, 0x1f, 0xc3, 0x0d,
+	0xba, 0x37, 0x21, 0xee, 0xe5, 0x08, 0xb9, 0xcc, 0x8e, 0xc2, 0x9a, 0x40, 0xc8, 0x8a, 0xd9, 0xf8,
+	0xab, 0x19, 0x68, 0x2a, 0x24, 0x73, 0xad, 0x05, 0x82, 0xca, 0x6b, 0xd7, 0xef, 0xc9, 0x2c, 0x96,
+	0x7e, 0x53, 0x58, 0x68, 0x93, 0x53, 0x91, 0x68, 0xb1, 0x6f, 0x7a, 0x66, 0xf1, 0x19, 0xf6, 0x89,
+	0x28, 0x79, 0xf0, 0x06, 0xad, 0x84, 0xf0, 0x03, 0xc7, 0x2c, 0x42, 0xd5, 0x14, 0x2d, 0xf4, 0x09,
+	0x2c, 0xf4, 0xb0, 0x87, 0x09, 0xe6, 0xc7, 0xc7, 0x15, 0x35, 0x8c, 0x86, 0x39, 0xcf, 0xc1, 0xcf,
+	0x04, 0x94, 0x1e, 0x7a, 0xc1, 0xbd, 0xb0, 0x10, 0xb2, 0x49, 0xbd, 0x7a, 0x84, 0x43, 0xcf, 0x76,
+	0x70, 0x6c, 0xe1, 0xb7, 0x6e, 0x4c, 0x68, 0x94, 0xcf, 0x0d, 0x46, 0x5b, 0x76, 0x74, 0x05, 0x1c,
+	0xad, 0x53, 0x6d, 0x48, 0x56, 0x2f, 0xec, 0x87, 0x0a, 0x32, 0x7e, 0xd7, 0x80, 0x95, 0x7d, 0x3f,
+	0x26, 0xb6, 0xe7, 0x65, 0x6c, 0x68, 0x12, 0xfd, 0x97, 0x0a, 0x47, 0xff, 0x33, 0x57, 0x89, 0xfe,
+	0xcb, 0x29, 0x23, 0x2c, 0xf7, 0xa0, 0xa2, 0xec, 0x41, 0xa1, 0x8c, 0x20, 0x95, 0x02, 0xd7, 0xb2,
+	0x29, 0xf0, 0x6d, 0x00, 0x1e, 0xc2, 0x33, 0xe2, 0x5c, 0x94, 0x0d, 0x06, 0x39, 0x12, 0x89, 0x97,
+	0xb4, 0xcf, 0xf5, 0x7c, 0xfb, 0xac, 0xe6, 0x03, 0x93, 0x61, 0x3d, 0x5c, 0x1a, 0xd6, 0x37, 0x0b,
+	0x59, 0xe5, 0x56, 0xc1, 0xb0, 0x7e, 0x2e, 0x27, 0xac, 0xff, 0x36, 0x1d, 0xd6, 0xcf, 0xb3, 0x83,
+	0xf4, 0x65, 0xfe, 0x41, 0xca, 0xdd, 0xe9, 0x4b, 0xe2, 0x7a, 0x25, 0xe0, 0x5d, 0x28, 0x18, 0xf0,
+	0xb6, 0x8b, 0x07, 0xbc, 0x8b, 0x93, 0x76, 0xfd, 0x0e, 0xcc, 0x91, 0x68, 0xe4, 0x3b, 0x36, 0x11,
+	0xdb, 0xc6, 0x6d, 0x71, 0x4b, 0x02, 0xe5, 0xce, 0xc9, 0xa8, 0x78, 0x29, 0x1d, 0x15, 0xe7, 0x86,
+	0xbd, 0xcb, 0x85, 0xc3, 0xde, 0x95, 0x3c, 0x83, 0xbe, 0x0a, 0x35, 0x51, 0xc4, 0xe3, 0xe9, 0x81,
+	0x68, 0x4d, 0x86, 0xb5, 0x6b, 0x45, 0xc2, 0x5a, 0xed, 0x5d, 0xc3, 0xda, 0x1b, 0x13, 0x61, 0xed,
+	0x81, 0x0c, 0x6b, 0x75, 0xb6, 0xfd, 0x9f, 0x5d, 0x65, 0xfb, 0x27, 0xe3, 0xda, 0x3c, 0x87, 0x78,
+	0x33, 0xd7, 0x21, 0xa6, 0x53, 0x90, 0x5b, 0xd9, 0x14, 0x64, 0x32, 0xa6, 0xbd, 0xfd, 0x7f, 0x29,
+	0xa6, 0x35, 0xfe, 0xa2, 0x04, 0xab, 0x59, 0xa1, 0x7c, 0xef, 0x71, 0xec, 0x3f, 0x96, 0x61, 0xed,
+	0x6b, 0xdf, 0xcd, 0x35, 0xc2, 0x79, 0xae, 0x69, 0xc2, 0x2c, 0xce, 0xe4, 0x98, 0xc5, 0x65, 0xa8,
+	0x86, 0xa3, 0x68, 0x80, 0x85, 0x99, 0xe5, 0x0d, 0xd5, 0xde, 0x55, 0xd2, 0xf6, 0x2e, 0x6d, 0xb5,
+	0xaa, 0x85, 0xac, 0x56, 0x2d, 0xdf, 0x6a, 0xe5, 0x07, 0x8a, 0xb3, 0xd3, 0x02, 0x45, 0x69, 0x69,
+	0xeb, 0xe9, 0xca, 0x4b, 0xca, 0x4a, 0x34, 0x26, 0xad, 0xc4, 0xc4, 0xa9, 0x82, 0x2b, 0x9f, 0xaa,
+	0x6d, 0x58, 0x11, 0xde, 0x98, 0x2d, 0x2b, 0xc2, 0x71, 0x30, 0x8a, 0xa8, 0xb5, 0xe0, 0xc5, 0x9c,
+	0x25, 0xde, 0x49, 0xa7, 0x33, 0x65, 0x97, 0x61, 0x81, 0x36, 0xb9, 0x57, 0xd7, 0x55, 0x19, 0xa4,
+	0x94, 0x79, 0x1b, 0xbc, 0xa4, 0x6b, 0x2c, 0xc1, 0xe2, 0x63, 0x4c, 0x5e, 0xf0, 0xe4, 0x42, 0xa8,
+	0x81, 0xf1, 0x97, 0x25, 0x40, 0x2a, 0x74, 0x3c, 0xe1, 0x0b, 0xa5, 0x2e, 0x99, 0x4c, 0x28, 0x2f,
+	0x87, 0x24, 0xfe, 0xec, 0x8b, 0x71, 0xae, 0xd2, 0xc7, 0x36, 0x19, 0x45, 0x98, 0xab, 0x69, 0xc3,
+	0x4c, 0xda, 0xd4, 0x14, 0xc6, 0x24, 0x88, 0xec, 0x01, 0xb6, 0x7a, 0x91, 0x7b, 0x86, 0x23, 0x11,
+	0xe7, 0xcc, 0x09, 0xe8, 0x1e, 0x03, 0x1a, 0x7f, 0xc8, 0xf8, 0xfb, 0xca, 0xa5, 0xd0, 0xf3, 0x8b,
+	0xd4, 0xb4, 0x0d, 0xe5, 0xa1, 0xfd, 0x56, 0x54, 0x58, 0xe9, 0xa7, 0xf1, 0x18, 0x90, 0x3a, 0x54,
+	0x2c, 0x42, 0xbd, 0x05, 0x28, 0x15, 0xba, 0x05, 0x30, 0xfe, 0x04, 0xd0, 0x73, 0x9c, 0x5c, 0x48,
+	0x5c, 0x52, 0x59, 0x95, 0x0a, 0x3f, 0x93, 0x56, 0x78, 0xe6, 0x40, 0xb0, 0xed, 0x8f, 0x42, 0x71,
+	0x44, 0x64, 0xd3, 0xf8, 0x25, 0x2c, 0xa5, 0xa8, 0x0b, 0x3e, 0xe9, 0x7a, 0xe2, 0x81, 0xb4, 0x2b,
+	0xc3, 0x78, 0x80, 0x7e, 0x1f, 0x6a, 0xfc, 0x7e, 0x89, 0xd1, 0x9e, 0xdf, 0xbe, 0x95, 0xe6, 0x9b,
+	0x11, 0x19, 0xf9, 0xe2, 0x42, 0xca, 0x14, 0xb8, 0x06, 0x82, 0x36, 0x95, 0x02, 0xb6, 0x3d, 0x72,
+	0x2a, 0xf7, 0xf7, 0x3f, 0x4a, 0xd0, 0xde, 0xc3, 0x21, 0x4d, 0x5d, 0x7c, 0xe7, 0x9c, 0xf7, 0xe5,
+	0xae, 0xa7, 0x9b, 0x99, 0xf2, 0x41, 0xbe, 0x95, 0xc9, 0xd2, 0xca, 0xf0, 0x40, 0x4f, 0xbb, 0x67,
+	0x13, 0xda, 0x6f, 0x0d, 0x63, 0x71, 0x29, 0xd3, 0x10, 0x90, 0x43, 0x66, 0x3c, 0x70, 0x14, 0x05,
+	0x51, 0x12, 0xd4, 0xd2, 0x86, 0xb1, 0x09, 0x35, 0x4e, 0x26, 0x7d, 0xb7, 0x54, 0x83, 0x99, 0xe3,
+	0xa7, 0xed, 0x12, 0x6a, 0x41, 0x7d, 0xaf, 0xfb, 0xd8, 0xec, 0xec, 0xb1, 0x4b, 0xa5, 0x7f, 0x28,
+	0x71, 0x3d, 0x11, 0xcb, 0x14, 0x32, 0x1c, 0xb3, 0x5f, 0x7a, 0x17, 0xf6, 0x9f, 0x40, 0xab, 0x27,
+	0x51, 0x5c, 0x2c, 0x2d, 0xee, 0xc7, 0xc5, 0x88, 0x99, 0xa9, 0xb1, 0xc6, 0xb7, 0xb0, 0xb4, 0x63,
+	0x13, 0xe7, 0x34, 0x71, 0x03, 0x5c, 0x99, 0x1e, 0x4f, 0x68, 0xe5, 0xe6, 0x15, 0x7c, 0xaa, 0xa2,
+	0xab, 0xbf, 0x99, 0x01, 0x94, 0x9e, 0x20, 0x1e, 0x79, 0xe4, 0xea, 0xb6, 0xe2, 0x09, 0xcc, 0x06,
+	0x23, 0xe2, 0x04, 0x43, 0x2c, 0xb6, 0xfe, 0xd3, 0x7c, 0x7e, 0x26, 0xe7, 0xda, 0x3a, 0xe6, 0xe3,
+	0x4c, 0x49, 0x60, 0xbc, 0xbf, 0x65, 0x75, 0x7f, 0x5f, 0xc2, 0xac, 0xc0, 0xa4, 0x1b, 0x7c, 0xf2,
+	0x74, 0xff, 0xd9, 0xb3, 0xee, 0x5e, 0xfb, 0x3d, 0x34, 0x07, 0x8d, 0xfd, 0xa3, 0x93, 0xe7, 0x9d,
+	0x83, 0x83, 0xee, 0x5e, 0xbb, 0x84, 0x00, 0x6a, 0x8f, 0x3a, 0xfb, 0xf4, 0x7b, 0x06, 0x2d, 0x40,
+	0xd3, 0x3c, 0xa6, 0x70, 0x6b, 0xa7, 0xb3, 0xfb, 0xb4, 0x5d, 0x46, 0x4b, 0xb0, 0x40, 0x01, 0xb4,
+	0x65, 0x09, 0xac, 0x8a, 0xf1, 0x0b, 0x58, 0xce, 0x70, 0xc5, 0xb5, 0x61, 0x87, 0xca, 0x80, 0x72,
+	0x28, 0x45, 0xbc, 0x51, 0x74, 0x49, 0xa6, 0x1c, 0x68, 0xfc, 0x19, 0xac, 0x98, 0x98, 0x1a, 0x14,
+	0xfc, 0x5d, 0x79, 0x4e, 0xc5, 0x64, 0x94, 0xf3, 0x73, 0x82, 0xca, 0xd8, 0x53, 0x19, 0xfb, 0xb0,
+	0x9a, 0x9d, 0xff, 0xba, 0x17, 0x58, 0x0e, 0x2c, 0xed, 0xfb, 0x71, 0x88, 0x1d, 0xc2, 0xd3, 0xab,
+	0xab, 0xe6, 0x61, 0x77, 0x60, 0x8e, 0x7d, 0x58, 0x76, 0xe4, 0x9c, 0xd2, 0x74, 0x8f, 0xae, 0xae,
+	0x65, 0xb6, 0x18, 0xb0, 0xc3, 0x61, 0xc6, 0xdf, 0x94, 0x60, 0x81, 0x8d, 0x1a, 0x1f, 0x8b, 0x22,
+	0x77, 0x64, 0x8d, 0x71, 0xbd, 0xeb, 0x7d, 0x9a, 0x52, 0x85, 0x41, 0xec, 0x52, 0x2b, 0x2e, 0x34,
+	0x48, 0x81, 0xd0, 0x84, 0xcc, 0x09, 0xfc, 0x9e, 0x4b, 0x64, 0xad, 0xac, 0x61, 0x8e, 0x01, 0x74,
+	0x2e, 0x62, 0x0f, 0x64, 0x84, 0xc1, 0xbe, 0x8d, 0x7f, 0x29, 0xc1, 0x72, 0x7a, 0xe5, 0x42, 0x84,
+	0x9f, 0x42, 0x5d, 0x3e, 0xc5, 0x10, 0xab, 0x5f, 0x56, 0x57, 0x7f, 0x28, 0xfa, 0xcc, 0x04, 0x0b,
+	0xed, 0xe7, 0x5a, 0x86, 0x29, 0xef, 0x18, 0x32, 0x72, 0x48, 0x1b, 0x06, 0x1a, 0xf3, 0x2b, 0x97,
+	0x5a, 0x8d, 0x24, 0x85, 0x5d, 0x85, 0x5a, 0x84, 0xed, 0x5e, 0x92, 0xab, 0x8a, 0x96, 0xf1, 0x3f,
+	0x25, 0x58, 0x15, 0x61, 0x2c, 0x2e, 0xe6, 0x99, 0xa6, 0xdc, 0x3e, 0x5b, 0xe9, 0x84, 0xae, 0xcc,
+	0x96, 0xf0, 0x93, 0xfc, 0x25, 0xe4, 0x4f, 0x78, 0x49, 0x46, 0xc7, 0x56, 0x40, 0xcb, 0xd3, 0xe2,
+	0x4e, 0x58, 0xb4, 0xde, 0x35, 0x0e, 0x37, 0x9e, 0xc0, 0xda, 0x04, 0x3f, 0xd7, 0x3d, 0x0c, 0xdf,
+	0xf0, 0x73, 0xcd, 0xb4, 0xe1, 0x1d, 0xbc, 0xbc, 0x3c, 0xb2, 0x65, 0xe5, 0xc8, 0x0e, 0x60, 0x35,
+	0x4b, 0xfa, 0xba, 0x01, 0xdc, 0x2d, 0x5a, 0x82, 0x64, 0xa4, 0x70, 0x4f, 0x04, 0x54, 0x63, 0x80,
+	0xb1, 0x09, 0x2b, 0xfc, 0xca, 0xab, 0x80, 0x3e, 0x50, 0x43, 0x92, 0x45, 0xbe, 0xfe, 0x4d, 0xf8,
+	0xb2, 0x89, 0x7f, 0x85, 0x9d, 0x22, 0xa2, 0xe3, 0xda, 0x1c, 0x27, 0xc7, 0x5c, 0xb4, 0x8c, 0xaf,
+	0x60, 0x25, 0x43, 0xe3, 0xba, 0xdc, 0xfc, 0x57, 0x09, 0x56, 0xc7, 0xd7, 0xfc, 0x7b, 0x91, 0xdb,
+	0xbf, 0xde, 0xe5, 0xfc, 0xd8, 0x10, 0x96, 0x0b, 0x17, 0xa4, 0x2a, 0x97, 0x16, 0xa4, 0xb2, 0x57,
+	0xc3, 0xd5, 0xc9, 0xab, 0xe1, 0xec, 0x35, 0x70, 0x6d, 0xe2, 0x1a, 0xd8, 0xf8, 0xb7, 0x19, 0x98,
+	0x93, 0x39, 0x02, 0x5b, 0x21, 0xcd, 0xd8, 0xed, 0xd0, 0xb5, 0xd4, 0x67, 0x03, 0x0d, 0x13, 0xec,
+	0xd0, 0x95, 0xa1, 0xf8, 0x94, 0x02, 0x23, 0x93, 0x47, 0x59, 0x91, 0x47, 0xaa, 0xbe, 0x55, 0xc9,
+	0xd6, 0xb7, 0x76, 0x92, 0x80, 0x8a, 0x3f, 0xab, 0xba, 0x97, 0x6f, 0x26, 0x52, 0xbc, 0x65, 0xa3,
+	0xa9, 0xcf, 0xe9, 0xb3, 0x2d, 0xec, 0xf5, 0x78, 0x42, 0xd7, 0xdc, 0x5e, 0xcf, 0xa7, 0xf1, 0x88,
+	0xe2, 0xf0, 0xed, 0x13, 0xf8, 0xc6, 0x89, 0x1a, 0x11, 0xee, 0x1f, 0x59, 0x27, 0xdf, 0x1c, 0xd1,
+	0xa7, 0x43, 0x2d, 0xa8, 0x1f, 0x1e, 0xef, 0xed, 0x3f, 0xda, 0x67, 0xf1, 0x42, 0x13, 0x66, 0x0f,
+	0xf7, 0x4f, 0x4e, 0xf6, 0x8f, 0x1e, 0xf3, 0x67, 0x4b, 0xdd, 0x9f, 0x3f, 0x37, 0x3b, 0xed, 0x32,
+	0xfd, 0xec, 0xec, 0xd1, 0x60, 0xb1, 0x42, 0x51, 0xcc, 0xee, 0xe1, 0xf1, 0x8b, 0xee, 0x5e, 0xbb,
+	0x6a, 0x3c, 0x07, 0x18, 0x4f, 0x95, 0xd4, 0x5c, 0x4b, 0x4a, 0xcd, 0x55, 0x87, 0x3a, 0x7e, 0x1b,
+	0xb2, 0xfb, 0x45, 0xf9, 0xe8, 0x42, 0xb6, 0xa9, 0x3e, 0xdb, 0x0e, 0x19, 0x89, 0xa7, 0x46, 0x0d,
+	0x53, 0xb4, 0x8c, 0xbf, 0x4b, 0x3d, 0x0e, 0x12, 0x5a, 0x78, 0xc1, 0x0b, 0x9c, 0xe9, 0x6a, 0xa8,
+	0xd1, 0x12, 0xa6, 0xdb, 0xa7, 0x93, 0x8b, 0xc4, 0x41, 0x34, 0x51, 0x87, 0x59, 0x03, 0x91, 0x43,
+	0xf2, 0x07, 0x4d, 0x77, 0x0a, 0xec, 0x87, 0x39, 0x1e, 0x65, 0xfc, 0xb6, 0x04, 0xcb, 0xdd, 0xb7,
+	0x61, 0x50, 0xd4, 0xec, 0x7d, 0x9f, 0x47, 0x25, 0xa5, 0x89, 0xd5, 0x8c, 0x26, 0x1a, 0x5f, 0x42,
+	0x8b, 0x33, 0x8e, 0x7b, 0x8f, 0x5c, 0x0f, 0x5f, 0xf0, 0xce, 0x85, 0x60, 0x9f, 0x28, 0xef, 0x5c,
+	0x68, 0xd3, 0x38, 0x83, 0x95, 0xcc, 0xb2, 0xc5, 0xde, 0x7c, 0x0e, 0x55, 0x5a, 0x37, 0x94, 0x11,
+	0xa2, 0x91, 0x2f, 0x4f, 0x75, 0x66, 0x93, 0x0f, 0xa0, 0xe1, 0x50, 0x30, 0x74, 0x09, 0xbd, 0xa2,
+	0x1e, 0xd7, 0x65, 0x1a, 0x66, 0x4b, 0x00, 0xf9, 0x55, 0xc0, 0xcf, 0xa9, 0xa9, 0x8c, 0x47, 0x43,
+	0xfc, 0x9d, 0x7b, 0x19, 0x66, 0x40, 0x53, 0x94, 0xaf, 0x6b, 0x40, 0x35, 0x58, 0x3d, 0x74, 0x07,
+	0x11, 0xf3, 0xca, 0xa9, 0x57, 0x6d, 0xc6, 0x7f, 0x96, 0x60, 0x6d, 0xa2, 0x4b, 0x4c, 0x73, 0x0b,
+	0x1a, 0x43, 0xde, 0xe5, 0x0f, 0xe4, 0x0b, 0xa1, 0x04, 0x40, 0x39, 0xa6, 0x97, 0x3a, 0xd2, 0xfa,
+	0xd0, 0x6f, 0x34, 0x0f, 0x33, 0x24, 0x10, 0xc7, 0x66, 0x86, 0x04, 0xe3, 0x47, 0x7b, 0xfc, 0xc2,
+	0x93, 0x37, 0xd8, 0x8b, 0x27, 0x46, 0x46, 0x3c, 0x1a, 0xab, 0x9a, 0x49, 0x9b, 0x3d, 0x00, 0xb5,
+	0x5d, 0x0f, 0xf7, 0x98, 0x8d, 0xac, 0x9a, 0xa2, 0x45, 0xc7, 0x38, 0xc1, 0x30, 0xf4, 0x30, 0x91,
+	0x35, 0xf8, 0xa4, 0x3d, 0xce, 0x45, 0xea, 0x6a, 0x2e, 0x72, 0x1f, 0x56, 0xe5, 0x85, 0x53, 0x01,
+	0xdf, 0xf9, 0x04, 0xd6, 0x26, 0xb0, 0xaf, 0x2b, 0xed, 0x9f, 0xc1, 0x02, 0xcd, 0x5b, 0xa9, 0x76,
+	0x5c, 0xef, 0x0d, 0xd9, 0x9f, 0x42, 0x7b, 0x4c, 0xe0, 0x5a, 0x16, 0xe6, 0x0b, 0x00, 0xfc, 0x16,
+	0x3b, 0x23, 0x35, 0xfe, 0xcb, 0xd4, 0xb5, 0x28, 0xf9, 0xae, 0xc4, 0x31, 0x15, 0x74, 0xe3, 0x0b,
+	0xf8, 0x60, 0xdf, 0x3f, 0xb3, 0x3d, 0xb7, 0x67, 0x13, 0xbc, 0xe7, 0xc6, 0x4e, 0x70, 0x86, 0xa3,
+	0xf3, 0x5d, 0xdb, 0x39, 0x4d, 0x44, 0xa8, 0xd4, 0xce, 0x4b, 0xe9, 0xb7, 0x7d, 0x5f, 0xc2, 0xfa,
+	0xf4, 0xc1, 0xe3, 0xc7, 0x70, 0xd8, 0x27, 0x91, 0x8b, 0x63, 0xf9, 0x18, 0x4e, 0x34, 0x8d, 0x7f,
+	0x4a, 0xd9, 0xd8, 0x03, 0xfb, 0x15, 0xf6, 0xe2, 0xff, 0x37, 0xf6, 0xeb, 0x77, 0x65, 0xd0, 0x26,
+	0x99, 0xbf, 0xd6, 0xfe, 0x61, 0x98, 0x73, 0x82, 0xe1, 0x30, 0xf0, 0x2d, 0x8f, 0x91, 0x61, 0xc9,
+	0x4e, 0x73, 0xfb, 0x8f, 0xf2, 0x6d, 0xd7, 0xb4, 0x49, 0xb7, 0x76, 0x19, 0x0d, 0x0e, 0xe4, 0x51,
+	0x7c, 0xcb, 0x51, 0x40, 0x88, 0x00, 0x12, 0xd3, 0xa8, 0xe9, 0x02, 0xf7, 0xe1, 0xdd, 0x6b, 0xcd,
+	0x35, 0x91, 0x36, 0x2c, 0x3a, 0x59, 0xb8, 0x08, 0x79, 0x85, 0x93, 0x9b, 0x65, 0x0b, 0x1f, 0x03,
+	0xf4, 0x9f, 0xc1, 0xe2, 0x04, 0xdb, 0x57, 0xaa, 0xe5, 0xef, 0xc1, 0x6a, 0x3e, 0x2f, 0x57, 0xa1,
+	0xf2, 0xa4, 0x52, 0x2f, 0xb7, 0x2b, 0x4f, 0x2a, 0xf5, 0x4a, 0xbb, 0x6a, 0xce, 0xdb, 0x61, 0xe8,
+	0xb9, 0xb8, 0x27, 0x36, 0xc3, 0x5c, 0x92, 0x6d, 0x45, 0x6a, 0xc6, 0x3d, 0x58, 0x7e, 0x49, 0xab,
+	0x0c, 0x2f, 0xf9, 0xbd, 0xc4, 0x45, 0x6a, 0x4b, 0xc3, 0xfa, 0x0c, 0xee, 0x58, 0x4b, 0xbc, 0x60,
+	0x10, 0x4b, 0x64, 0xfa, 0xbd, 0xfd, 0x9b, 0x1b, 0x30, 0x2f, 0xdf, 0xfd, 0xf2, 0xcd, 0x40, 0x2e,
+	0xb4, 0xd4, 0xe7, 0xd4, 0xe8, 0xee, 0xf4, 0xa7, 0xf0, 0x99, 0xf7, 0xfc, 0xfa, 0xbd, 0x22, 0xa8,
+	0x9c, 0x1b, 0xe3, 0xbd, 0x4f, 0x4b, 0x28, 0x66, 0xb6, 0x28, 0xf5, 0xee, 0x18, 0x3d, 0xb8, 0x4c,
+	0x35, 0x52, 0x3e, 0x46, 0xdf, 0x2a, 0x8a, 0x2e, 0xa7, 0x45, 0x67, 0xb0, 0x38, 0xee, 0x15, 0xcf,
+	0x7a, 0xd1, 0xa5, 0x64, 0xd2, 0x2f, 0x89, 0xf5, 0x87, 0x85, 0xf1, 0x93, 0x79, 0x7f, 0x05, 0x73,
+	0xa9, 0x27, 0x49, 0xe8, 0x5e, 0xf1, 0xc7, 0x5d, 0xfa, 0x66, 0x21, 0xdc, 0x64, 0xae, 0x21, 0xcc,
+	0xa7, 0x0b, 0x7f, 0xe8, 0x2a, 0xe5, 0x41, 0xfd, 0x7e, 0x31, 0xe4, 0x64, 0xba, 0x18, 0xda, 0xd9,
+	0x5b, 0x87, 0x69, 0xfb, 0x38, 0xe5, 0x26, 0x49, 0xdf, 0x2a, 0x8a, 0x9e, 0x4c, 0x6a, 0x03, 0x8c,
+	0xef, 0x1c, 0xd0, 0x27, 0x53, 0x37, 0x24, 0x7d, 0x57, 0xa1, 0x6f, 0x5c, 0x8e, 0x98, 0x4c, 0x11,
+	0xc2, 0x42, 0xe6, 0x29, 0x09, 0x9a, 0x22, 0x9a, 0xfc, 0x87, 0x5e, 0xfa, 0x83, 0x82, 0xd8, 0x99,
+	0x45, 0x89, 0x3b, 0x88, 0x0b, 0x16, 0x95, 0xbe, 0xe0, 0xd0, 0x37, 0x2e, 0x47, 0x4c, 0xa6, 0x70,
+	0x61, 0xde, 0x1c, 0xf9, 0x62, 0x6a, 0x7a, 0x09, 0x80, 0xa6, 0x8c, 0x9e, 0xbc, 0xc3, 0xd0, 0xef,
+	0x16, 0xc0, 0x54, 0xce, 0xf7, 0xb7, 0xd0, 0x48, 0x8a, 0xec, 0xe8, 0xe3, 0xe9, 0x3c, 0xaa, 0x97,
+	0x0d, 0xfa, 0x27, 0x97, 0xe2, 0x25, 0x4b, 0xe9, 0x41, 0x53, 0x79, 0x0f, 0x8f, 0xa6, 0x4b, 0x21,
+	0xf3, 0xec, 0x5e, 0xbf, 0x5b, 0x00, 0x53, 0x9d, 0x45, 0x79, 0xe4, 0x3e, 0x6d, 0x96, 0xc9, 0xb7,
+	0xf4, 0xfa, 0xdd, 0x02, 0x98, 0xc9, 0x2c, 0x03, 0x68, 0xa9, 0x85, 0xe4, 0x69, 0x66, 0x37, 0xe7,
+	0x32, 0x40, 0xbf, 0x57, 0x04, 0x55, 0xb5, 0x0d, 0xe9, 0x92, 0xf0, 0x34, 0xdb, 0x90, 0x5b, 0xb8,
+	0xd6, 0xef, 0x17, 0x43, 0x56, 0xd7, 0xa5, 0x16, 0x4f, 0xa7, 0xad, 0x2b, 0xa7, 0xb4, 0xac, 0xdf,
+	0x2b, 0x82, 0xaa, 0x1e, 0xd6, 0x4c, 0x79, 0x6f, 0xda, 0x61, 0xcd, 0xaf, 0x4a, 0xea, 0x0f, 0x0a,
+	0x62, 0x67, 0x25, 0x39, 0xae, 0xd4, 0x5d, 0x24, 0xc9, 0x89, 0x52, 0xa1, 0x7e, 0xbf, 0x18, 0xb2,
+	0x3a, 0x5d, 0xba, 0x04, 0x37, 0x6d, 0xba, 0xdc, 0xaa, 0x9e, 0x7e, 0xbf, 0x18, 0xb2, 0xea, 0xaf,
+	0x52, 0x25, 0x36, 0x34, 0xb5, 0x78, 0x33, 0x59, 0xcb, 0xd3, 0x37, 0x0b, 0xe1, 0xaa, 0x7b, 0x97,
+	0xa9, 0x7e, 0x4c, 0xdb, 0xbb, 0xfc, 0x52, 0x9d, 0xfe, 0xa0, 0x20, 0xb6, 0xba, 0xba, 0x54, 0x46,
+	0x3f, 0x6d, 0x75, 0x79, 0xd5, 0x0e, 0x7d, 0xb3, 0x10, 0x6e, 0x5a, 0x92, 0x4a, 0xae, 0x3d, 0x5d,
+	0x92, 0x93, 0xa9, 0xbe, 0xbe, 0x59, 0x08, 0x57, 0x95, 0x64, 0x26, 0xe5, 0x9e, 0x26, 0xc9, 0xfc,
+	0xa4, 0x5d, 0x7f, 0x50, 0x10, 0x5b, 0x9d, 0x31, 0x93, 0xdd, 0x4e, 0x9b, 0x31, 0x3f, 0x65, 0xd6,
+	0x1f, 0x14, 0xc4, 0x4e, 0x66, 0xfc, 0x63, 0xa8, 0xcb, 0x14, 0x16, 0x7d, 0x34, 0xdd, 0x5b, 0x28,
+	0x39, 0xb2, 0xfe, 0xf1, 0x65, 0x68, 0x09, 0xf1, 0xbf, 0x2e, 0x81, 0x36, 0x2d, 0xc9, 0x44, 0x3f,
+	0x9a, 0x66, 0x91, 0x2e, 0xcc, 0x68, 0xf5, 0xcf, 0xae, 0x3a, 0x4c, 0x8d, 0xac, 0xb2, 0x49, 0xd1,
+	0xe5, 0x11, 0x72, 0x2a, 0xb5, 0xd5, 0xb7, 0x8a, 0xa2, 0x27, 0x93, 0x7a, 0x30, 0x97, 0xca, 0x20,
+	0xa6, 0xe9, 0x6b, 0x5e, 0x4a, 0xa2, 0x6f, 0x16, 0xc2, 0x1d, 0x07, 0x09, 0x3b, 0xf0, 0x8b, 0xba,
+	0x44, 0x7e, 0x55, 0x63, 0x2f, 0x80, 0x7f, 0xef, 0x7f, 0x07, 0x00, 0x35, 0xa0, 0xb2, 0x39, 0x72,
+	0x3d, 0x00, 0x00,
 }
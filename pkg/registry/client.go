@@ -0,0 +1,181 @@
+/*
+Copyright 2017 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package registry
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// ErrManifestUnknown is returned for images that the registry does not have.
+var ErrManifestUnknown = errors.New("manifest unknown")
+
+// ErrUnauthorized is returned for images that the credentials do not give
+// access to.
+var ErrUnauthorized = errors.New("unauthorized")
+
+// defaultTimeout bounds each request to a registry when Client.HTTPClient is
+// not set.
+const defaultTimeout = 30 * time.Second
+
+// manifestMediaTypes are the manifest formats a container runtime pulls.
+var manifestMediaTypes = []string{
+	"application/vnd.docker.distribution.manifest.v2+json",
+	"application/vnd.docker.distribution.manifest.list.v2+json",
+	"application/vnd.docker.distribution.manifest.v1+prettyjws",
+	"application/vnd.oci.image.manifest.v1+json",
+	"application/vnd.oci.image.index.v1+json",
+}
+
+// Client checks images against registries that implement the Docker
+// Registry HTTP API V2.
+type Client struct {
+	// HTTPClient makes the requests. When it is nil, a client with a timeout
+	// of 30 seconds is used.
+	HTTPClient *http.Client
+}
+
+// VerifyImage checks that the manifest of image exists, logging in with the
+// credentials for its registry, if there are any. Nothing is downloaded.
+func (c *Client) VerifyImage(image string, creds Credentials) error {
+	ref, err := ParseReference(image)
+	if err != nil {
+		return err
+	}
+	auth, hasAuth := creds.For(ref.Registry)
+
+	u := fmt.Sprintf("https://%s/v2/%s/manifests/%s", ref.apiHost(), ref.Repository, ref.manifestRef())
+	resp, err := c.head(u, "")
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode == http.StatusUnauthorized {
+		authorization, err := c.authorize(resp.Header.Get("WWW-Authenticate"), ref, auth, hasAuth)
+		if err != nil {
+			return err
+		}
+		if resp, err = c.head(u, authorization); err != nil {
+			return err
+		}
+	}
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return nil
+	case http.StatusNotFound:
+		return ErrManifestUnknown
+	case http.StatusUnauthorized, http.StatusForbidden:
+		return ErrUnauthorized
+	default:
+		return fmt.Errorf("unexpected status %q from %s", resp.Status, ref.apiHost())
+	}
+}
+
+func (c *Client) client() *http.Client {
+	if c.HTTPClient != nil {
+		return c.HTTPClient
+	}
+	return &http.Client{Timeout: defaultTimeout}
+}
+
+func (c *Client) head(u, authorization string) (*http.Response, error) {
+	req, err := http.NewRequest("HEAD", u, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", strings.Join(manifestMediaTypes, ", "))
+	if authorization != "" {
+		req.Header.Set("Authorization", authorization)
+	}
+	resp, err := c.client().Do(req)
+	if err != nil {
+		return nil, err
+	}
+	resp.Body.Close()
+	return resp, nil
+}
+
+var challengeParam = regexp.MustCompile(`(\w+)="([^"]*)"`)
+
+// authorize answers the authentication challenge of a registry, returning
+// the value of the Authorization header to retry with.
+func (c *Client) authorize(challenge string, ref Reference, auth Auth, hasAuth bool) (string, error) {
+	scheme := strings.ToLower(strings.SplitN(challenge, " ", 2)[0])
+	switch scheme {
+	case "basic":
+		if !hasAuth {
+			return "", ErrUnauthorized
+		}
+		req, _ := http.NewRequest("GET", "/", nil)
+		req.SetBasicAuth(auth.Username, auth.Password)
+		return req.Header.Get("Authorization"), nil
+	case "bearer":
+	default:
+		return "", fmt.Errorf("unsupported authentication challenge %q from %s", challenge, ref.apiHost())
+	}
+
+	params := map[string]string{}
+	for _, m := range challengeParam.FindAllStringSubmatch(challenge, -1) {
+		params[strings.ToLower(m[1])] = m[2]
+	}
+	realm, err := url.Parse(params["realm"])
+	if err != nil || params["realm"] == "" {
+		return "", fmt.Errorf("invalid authentication challenge %q from %s", challenge, ref.apiHost())
+	}
+	q := realm.Query()
+	if params["service"] != "" {
+		q.Set("service", params["service"])
+	}
+	q.Set("scope", fmt.Sprintf("repository:%s:pull", ref.Repository))
+	realm.RawQuery = q.Encode()
+
+	req, err := http.NewRequest("GET", realm.String(), nil)
+	if err != nil {
+		return "", err
+	}
+	if hasAuth {
+		req.SetBasicAuth(auth.Username, auth.Password)
+	}
+	resp, err := c.client().Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusUnauthorized || resp.StatusCode == http.StatusForbidden {
+		return "", ErrUnauthorized
+	}
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("unexpected status %q requesting a token from %s", resp.Status, realm.Host)
+	}
+	var token struct {
+		Token       string `json:"token"`
+		AccessToken string `json:"access_token"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&token); err != nil {
+		return "", fmt.Errorf("cannot read the token from %s: %s", realm.Host, err)
+	}
+	if token.Token == "" {
+		token.Token = token.AccessToken
+	}
+	return "Bearer " + token.Token, nil
+}
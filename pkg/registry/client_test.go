@@ -0,0 +1,99 @@
+/*
+Copyright 2017 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package registry
+
+import (
+	"crypto/tls"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// testRegistry serves the manifests of public/app:1.0 to anyone and of
+// private/app:1.0 to the user "bot". Tokens are handed out at /token.
+func testRegistry(t *testing.T, bearer bool) *httptest.Server {
+	var srv *httptest.Server
+	srv = httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/token" {
+			user, pass, ok := r.BasicAuth()
+			scope := r.URL.Query().Get("scope")
+			if scope == "repository:private/app:pull" && (!ok || user != "bot" || pass != "s3cret") {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			fmt.Fprintf(w, `{"token": %q}`, scope)
+			return
+		}
+		if r.Method != "HEAD" || !strings.Contains(r.Header.Get("Accept"), "manifest.v2+json") {
+			t.Errorf("unexpected request %s %s (Accept: %s)", r.Method, r.URL, r.Header.Get("Accept"))
+		}
+
+		repo := strings.TrimPrefix(strings.SplitN(r.URL.Path, "/manifests/", 2)[0], "/v2/")
+		authorized := repo == "public/app"
+		if bearer {
+			authorized = authorized || r.Header.Get("Authorization") == "Bearer repository:private/app:pull"
+		} else if user, pass, ok := r.BasicAuth(); ok {
+			authorized = authorized || user == "bot" && pass == "s3cret"
+		}
+		if !authorized {
+			if bearer {
+				w.Header().Set("WWW-Authenticate", fmt.Sprintf(`Bearer realm="%s/token",service="test"`, srv.URL))
+			} else {
+				w.Header().Set("WWW-Authenticate", `Basic realm="test"`)
+			}
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		if !strings.HasSuffix(r.URL.Path, "/manifests/1.0") {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	return srv
+}
+
+func TestVerifyImage(t *testing.T) {
+	for _, bearer := range []bool{true, false} {
+		srv := testRegistry(t, bearer)
+		host := strings.TrimPrefix(srv.URL, "https://")
+		c := &Client{HTTPClient: &http.Client{Transport: &http.Transport{
+			TLSClientConfig: &tls.Config{InsecureSkipVerify: true},
+		}}}
+		login := Credentials{host: {Username: "bot", Password: "s3cret"}}
+
+		tests := []struct {
+			image string
+			creds Credentials
+			err   error
+		}{
+			{image: "public/app:1.0"},
+			{image: "public/app:2.0", err: ErrManifestUnknown},
+			{image: "private/app:1.0", creds: login},
+			{image: "private/app:1.0", err: ErrUnauthorized},
+			{image: "private/app:1.0", creds: Credentials{host: {Username: "bot", Password: "wrong"}}, err: ErrUnauthorized},
+		}
+		for _, tt := range tests {
+			if err := c.VerifyImage(host+"/"+tt.image, tt.creds); err != tt.err {
+				t.Errorf("bearer=%t %s: expected %v, got %v", bearer, tt.image, tt.err, err)
+			}
+		}
+		srv.Close()
+	}
+}
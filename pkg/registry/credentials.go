@@ -0,0 +1,99 @@
+/*
+Copyright 2017 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package registry
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// Auth is a login to a registry.
+type Auth struct {
+	Username string
+	Password string
+}
+
+// Credentials are registry logins, by registry host.
+type Credentials map[string]Auth
+
+// dockerConfigEntry is a login in a docker config file.
+type dockerConfigEntry struct {
+	Username string `json:"username"`
+	Password string `json:"password"`
+	Auth     string `json:"auth"`
+}
+
+// AddDockerConfig adds the logins in a docker config file to c. data is
+// either a ~/.docker/config.json, as stored in secrets of type
+// kubernetes.io/dockerconfigjson, or a legacy ~/.dockercfg.
+func (c Credentials) AddDockerConfig(data []byte) error {
+	var config struct {
+		Auths map[string]dockerConfigEntry `json:"auths"`
+	}
+	if err := json.Unmarshal(data, &config); err != nil {
+		return fmt.Errorf("cannot parse docker config: %s", err)
+	}
+	entries := config.Auths
+	if entries == nil {
+		// A .dockercfg has the logins at the top level.
+		if err := json.Unmarshal(data, &entries); err != nil {
+			return fmt.Errorf("cannot parse docker config: %s", err)
+		}
+	}
+
+	for server, e := range entries {
+		auth := Auth{Username: e.Username, Password: e.Password}
+		if e.Auth != "" {
+			decoded, err := base64.StdEncoding.DecodeString(e.Auth)
+			if err != nil {
+				return fmt.Errorf("cannot decode the login for %s: %s", server, err)
+			}
+			parts := strings.SplitN(string(decoded), ":", 2)
+			if len(parts) != 2 {
+				return fmt.Errorf("cannot decode the login for %s: expected username:password", server)
+			}
+			auth = Auth{Username: parts[0], Password: parts[1]}
+		}
+		c[registryHost(server)] = auth
+	}
+	return nil
+}
+
+// For returns the login for registry, if there is one.
+func (c Credentials) For(registry string) (Auth, bool) {
+	auth, ok := c[registryHost(registry)]
+	return auth, ok
+}
+
+// registryHost normalizes the server of a docker config entry, which may be
+// a URL, to a registry host. The many names of DockerHub become DockerHub.
+func registryHost(server string) string {
+	host := server
+	if i := strings.Index(host, "://"); i >= 0 {
+		host = host[i+3:]
+	}
+	if i := strings.Index(host, "/"); i >= 0 {
+		host = host[:i]
+	}
+	switch host {
+	case "index.docker.io", dockerHubAPI:
+		return DockerHub
+	}
+	return host
+}
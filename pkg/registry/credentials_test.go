@@ -0,0 +1,55 @@
+/*
+Copyright 2017 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package registry
+
+import (
+	"testing"
+)
+
+func TestAddDockerConfig(t *testing.T) {
+	creds := Credentials{}
+	// "Ym90OnMzY3JldA==" is "bot:s3cret".
+	configJSON := `{"auths": {"https://index.docker.io/v1/": {"auth": "Ym90OnMzY3JldA=="}, "quay.io": {"username": "robot", "password": "pw"}}}`
+	if err := creds.AddDockerConfig([]byte(configJSON)); err != nil {
+		t.Fatal(err)
+	}
+	dockercfg := `{"https://registry.example.com:5000/v2": {"auth": "Ym90OnMzY3JldA=="}}`
+	if err := creds.AddDockerConfig([]byte(dockercfg)); err != nil {
+		t.Fatal(err)
+	}
+
+	expect := map[string]Auth{
+		"docker.io":                 {Username: "bot", Password: "s3cret"},
+		"quay.io":                   {Username: "robot", Password: "pw"},
+		"registry.example.com:5000": {Username: "bot", Password: "s3cret"},
+	}
+	for registry, auth := range expect {
+		got, ok := creds.For(registry)
+		if !ok || got != auth {
+			t.Errorf("%s: expected %+v, got %+v (%t)", registry, auth, got, ok)
+		}
+	}
+	if _, ok := creds.For("gcr.io"); ok {
+		t.Error("expected no login for gcr.io")
+	}
+
+	for _, bad := range []string{`not json`, `{"auths": {"quay.io": {"auth": "!!"}}}`, `{"auths": {"quay.io": {"auth": "bm9jb2xvbg=="}}}`} {
+		if err := (Credentials{}).AddDockerConfig([]byte(bad)); err == nil {
+			t.Errorf("expected an error parsing %s", bad)
+		}
+	}
+}
@@ -0,0 +1,108 @@
+/*
+Copyright 2017 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package registry checks container images against image registries.
+package registry // import "k8s.io/helm/pkg/registry"
+
+import (
+	"fmt"
+	"strings"
+)
+
+// DockerHub is the registry of images whose reference names no registry.
+const DockerHub = "docker.io"
+
+// dockerHubAPI is the host that serves the registry API for DockerHub.
+const dockerHubAPI = "registry-1.docker.io"
+
+// Reference is a parsed container image reference, such as
+// "quay.io/coreos/etcd:v3.1.0".
+type Reference struct {
+	// Registry is the host of the registry, e.g. "quay.io" or "localhost:5000".
+	Registry string
+	// Repository is the path of the image in the registry, e.g. "coreos/etcd".
+	Repository string
+	// Tag is the tag of the image. It is empty if Digest is set.
+	Tag string
+	// Digest is the content digest of the image, e.g. "sha256:...".
+	Digest string
+}
+
+// ParseReference parses an image reference the way the container runtime
+// does. Images without a registry are on DockerHub, official DockerHub images
+// are in "library/", and images without a tag or digest are "latest".
+func ParseReference(image string) (Reference, error) {
+	ref := Reference{}
+	name := strings.TrimSpace(image)
+	if name == "" {
+		return ref, fmt.Errorf("invalid image reference %q", image)
+	}
+
+	if i := strings.Index(name, "@"); i >= 0 {
+		name, ref.Digest = name[:i], name[i+1:]
+		if !strings.Contains(ref.Digest, ":") {
+			return ref, fmt.Errorf("invalid image reference %q: malformed digest", image)
+		}
+	}
+	if i := strings.LastIndex(name, ":"); i > strings.LastIndex(name, "/") {
+		name, ref.Tag = name[:i], name[i+1:]
+	}
+	if ref.Tag == "" && ref.Digest == "" {
+		ref.Tag = "latest"
+	}
+	if ref.Digest != "" {
+		ref.Tag = ""
+	}
+
+	parts := strings.SplitN(name, "/", 2)
+	if len(parts) == 2 && (strings.ContainsAny(parts[0], ".:") || parts[0] == "localhost") {
+		ref.Registry, ref.Repository = parts[0], parts[1]
+	} else {
+		ref.Registry, ref.Repository = DockerHub, name
+	}
+	if ref.Registry == DockerHub && !strings.Contains(ref.Repository, "/") {
+		ref.Repository = "library/" + ref.Repository
+	}
+	if ref.Repository == "" || strings.HasSuffix(ref.Repository, "/") || strings.ToLower(ref.Repository) != ref.Repository {
+		return ref, fmt.Errorf("invalid image reference %q", image)
+	}
+	return ref, nil
+}
+
+// String returns the fully qualified reference.
+func (r Reference) String() string {
+	s := r.Registry + "/" + r.Repository
+	if r.Digest != "" {
+		return s + "@" + r.Digest
+	}
+	return s + ":" + r.Tag
+}
+
+// apiHost returns the host that serves the registry API for r.
+func (r Reference) apiHost() string {
+	if r.Registry == DockerHub {
+		return dockerHubAPI
+	}
+	return r.Registry
+}
+
+// manifestRef returns the tag or digest used to fetch the manifest of r.
+func (r Reference) manifestRef() string {
+	if r.Digest != "" {
+		return r.Digest
+	}
+	return r.Tag
+}
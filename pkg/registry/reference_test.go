@@ -0,0 +1,67 @@
+/*
+Copyright 2017 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package registry
+
+import (
+	"testing"
+)
+
+func TestParseReference(t *testing.T) {
+	tests := []struct {
+		image  string
+		expect Reference
+		err    bool
+	}{
+		{image: "nginx", expect: Reference{Registry: "docker.io", Repository: "library/nginx", Tag: "latest"}},
+		{image: "nginx:1.13", expect: Reference{Registry: "docker.io", Repository: "library/nginx", Tag: "1.13"}},
+		{image: "bitnami/redis:4.0", expect: Reference{Registry: "docker.io", Repository: "bitnami/redis", Tag: "4.0"}},
+		{image: "quay.io/coreos/etcd:v3.1.0", expect: Reference{Registry: "quay.io", Repository: "coreos/etcd", Tag: "v3.1.0"}},
+		{image: "localhost:5000/app", expect: Reference{Registry: "localhost:5000", Repository: "app", Tag: "latest"}},
+		{image: "localhost/app:dev", expect: Reference{Registry: "localhost", Repository: "app", Tag: "dev"}},
+		{image: "gcr.io/google_containers/pause@sha256:abc", expect: Reference{Registry: "gcr.io", Repository: "google_containers/pause", Digest: "sha256:abc"}},
+		{image: "nginx:1.13@sha256:abc", expect: Reference{Registry: "docker.io", Repository: "library/nginx", Digest: "sha256:abc"}},
+		{image: "", err: true},
+		{image: "Nginx", err: true},
+		{image: "nginx@abc", err: true},
+		{image: "quay.io/", err: true},
+	}
+	for _, tt := range tests {
+		got, err := ParseReference(tt.image)
+		if (err != nil) != tt.err {
+			t.Errorf("%q: expected error %t, got %v", tt.image, tt.err, err)
+			continue
+		}
+		if !tt.err && got != tt.expect {
+			t.Errorf("%q: expected %+v, got %+v", tt.image, tt.expect, got)
+		}
+	}
+}
+
+func TestReferenceString(t *testing.T) {
+	for image, expect := range map[string]string{
+		"nginx":                          "docker.io/library/nginx:latest",
+		"quay.io/coreos/etcd@sha256:abc": "quay.io/coreos/etcd@sha256:abc",
+	} {
+		ref, err := ParseReference(image)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if ref.String() != expect {
+			t.Errorf("%q: expected %q, got %q", image, expect, ref.String())
+		}
+	}
+}
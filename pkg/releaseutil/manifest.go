@@ -20,6 +20,8 @@ import (
 	"fmt"
 	"regexp"
 	"strings"
+
+	"github.com/ghodss/yaml"
 )
 
 // SimpleHead defines what the structure of the head of a manifest file
@@ -28,32 +30,167 @@ type SimpleHead struct {
 	Kind     string `json:"kind,omitempty"`
 	Metadata *struct {
 		Name        string            `json:"name"`
+		Namespace   string            `json:"namespace,omitempty"`
+		Labels      map[string]string `json:"labels,omitempty"`
 		Annotations map[string]string `json:"annotations"`
 	} `json:"metadata,omitempty"`
 }
 
-var sep = regexp.MustCompile("(?:^|\\s*\n)---\\s*")
+// SplitOptions configure how SplitManifestsWithOptions splits a YAML stream.
+type SplitOptions struct {
+	// KeepEmpty keeps documents that are null or only hold comments, such as
+	// the output of a template that rendered nothing.
+	KeepEmpty bool
+}
 
 // SplitManifests takes a string of manifest and returns a map contains individual manifests
+//
+// Documents that are null or only hold comments are skipped. See
+// SplitManifestsWithOptions.
 func SplitManifests(bigFile string) map[string]string {
-	// Basically, we're quickly splitting a stream of YAML documents into an
-	// array of YAML docs. In the current implementation, the file name is just
-	// a place holder, and doesn't have any further meaning.
+	return SplitManifestsWithOptions(bigFile, SplitOptions{})
+}
+
+// SplitManifestsWithOptions splits a stream of YAML documents, returning them
+// keyed by "manifest-N" in the order they appear in the stream.
+//
+// Documents are separated by lines that start with "---" followed by nothing
+// but whitespace, a comment or document content. A "---" line inside a quoted
+// scalar does not separate documents. Comments, such as the "# Source:" line
+// that names the template a document was rendered from, stay with the
+// document that follows them.
+func SplitManifestsWithOptions(bigFile string, opts SplitOptions) map[string]string {
+	// In the current implementation, the file name is just a place holder,
+	// and doesn't have any further meaning.
 	tpl := "manifest-%d"
 	res := map[string]string{}
-	// Making sure that any extra whitespace in YAML stream doesn't interfere in splitting documents correctly.
-	bigFileTmp := strings.TrimSpace(bigFile)
-	docs := sep.Split(bigFileTmp, -1)
 	var count int
-	for _, d := range docs {
-
-		if d == "" {
+	for _, d := range splitDocuments(bigFile) {
+		d = strings.TrimSpace(d)
+		if d == "" || (!opts.KeepEmpty && isEmptyDocument(d)) {
 			continue
 		}
-
-		d = strings.TrimSpace(d)
 		res[fmt.Sprintf(tpl, count)] = d
 		count = count + 1
 	}
 	return res
 }
+
+// ManifestSource returns the path of the template a document was rendered
+// from, as recorded by its "# Source:" comment, or "" if it has none.
+func ManifestSource(doc string) string {
+	for _, line := range strings.Split(doc, "\n") {
+		line = strings.TrimSpace(line)
+		if strings.HasPrefix(line, "# Source:") {
+			return strings.TrimSpace(strings.TrimPrefix(line, "# Source:"))
+		}
+		if line != "" && !strings.HasPrefix(line, "#") {
+			return ""
+		}
+	}
+	return ""
+}
+
+// isEmptyDocument reports whether a document is null or only holds
+// comments. Documents that do not parse are not empty, so that the error
+// is reported when they are used.
+func isEmptyDocument(doc string) bool {
+	var v interface{}
+	return yaml.Unmarshal([]byte(doc), &v) == nil && v == nil
+}
+
+// blockScalar matches the end of a line that starts a literal or folded
+// block scalar, such as "script: |" or "- >-".
+var blockScalar = regexp.MustCompile(`(?:^|[:-])\s*[|>][-+0-9]*$`)
+
+// splitDocuments splits a YAML stream on its document separators. It tracks
+// quoted and block scalars well enough to tell separators from scalar
+// content, without parsing the documents.
+func splitDocuments(stream string) []string {
+	var docs, cur []string
+	var quote byte
+	// block is the indentation of the line that started the current block
+	// scalar, or -1 outside block scalars.
+	block := -1
+	for _, line := range strings.Split(stream, "\n") {
+		line = strings.TrimSuffix(line, "\r")
+		trimmed := strings.TrimLeft(line, " \t")
+		indent := len(line) - len(trimmed)
+		if block >= 0 {
+			if strings.TrimSpace(line) == "" || indent > block {
+				cur = append(cur, line)
+				continue
+			}
+			block = -1
+		}
+		if quote == 0 {
+			if rest, ok := documentMarker(line); ok {
+				docs = append(docs, strings.Join(cur, "\n"))
+				cur = nil
+				if line = rest; line == "" {
+					continue
+				}
+				indent = 0
+			}
+		}
+		cur = append(cur, line)
+		var content string
+		quote, content = scanLine(line, quote)
+		if quote == 0 && blockScalar.MatchString(content) {
+			block = indent
+		}
+	}
+	return append(docs, strings.Join(cur, "\n"))
+}
+
+// documentMarker reports whether line starts a new document: a "---"
+// separator or a "..." document end marker, followed by nothing or by
+// whitespace. It returns what follows the marker on the line.
+func documentMarker(line string) (string, bool) {
+	for _, marker := range []string{"---", "..."} {
+		if !strings.HasPrefix(line, marker) {
+			continue
+		}
+		rest := line[len(marker):]
+		if rest == "" || rest[0] == ' ' || rest[0] == '\t' {
+			return strings.TrimSpace(rest), true
+		}
+	}
+	return "", false
+}
+
+// scanLine scans a line of YAML for quoted scalars, starting inside a
+// scalar quoted with quote if it is not 0. It returns the quote of a scalar
+// that continues on the next line, or 0, and the line without its comment.
+func scanLine(line string, quote byte) (byte, string) {
+	// prev is the last character outside of quoted scalars that is not
+	// whitespace. A quote only starts a scalar where a value starts.
+	var prev byte
+	for i := 0; i < len(line); i++ {
+		c := line[i]
+		switch {
+		case quote == '"':
+			if c == '\\' {
+				i++
+			} else if c == '"' {
+				quote, prev = 0, c
+			}
+		case quote == '\'':
+			if c == '\'' && i+1 < len(line) && line[i+1] == '\'' {
+				i++
+			} else if c == '\'' {
+				quote, prev = 0, c
+			}
+		case c == '#' && (i == 0 || line[i-1] == ' ' || line[i-1] == '\t'):
+			return 0, strings.TrimSpace(line[:i])
+		case c == '"' || c == '\'':
+			if prev == 0 || strings.IndexByte(":-[{,?", prev) >= 0 {
+				quote = c
+			}
+			prev = c
+		case c != ' ' && c != '\t':
+			prev = c
+		}
+	}
+	return quote, strings.TrimSpace(line)
+}
@@ -17,6 +17,7 @@ limitations under the License.
 package releaseutil // import "k8s.io/helm/pkg/releaseutil"
 
 import (
+	"fmt"
 	"reflect"
 	"testing"
 )
@@ -59,3 +60,107 @@ func TestSplitManifest(t *testing.T) {
 		t.Errorf("Expected %v, got %v", expected, manifests)
 	}
 }
+
+func TestSplitManifestsEdgeCases(t *testing.T) {
+	tests := []struct {
+		name   string
+		stream string
+		expect []string
+	}{
+		{
+			name:   "leading, trailing and repeated separators",
+			stream: "---\n---\nkind: A\n---\n\n---\nkind: B\n---\n",
+			expect: []string{"kind: A", "kind: B"},
+		},
+		{
+			name:   "templates that rendered nothing",
+			stream: "---\n# Source: chart/templates/empty.yaml\n---\n# Source: chart/templates/a.yaml\nkind: A\n",
+			expect: []string{"# Source: chart/templates/a.yaml\nkind: A"},
+		},
+		{
+			name:   "null documents",
+			stream: "null\n---\n~\n---\nkind: A\n",
+			expect: []string{"kind: A"},
+		},
+		{
+			name:   "separator in a double-quoted scalar",
+			stream: "kind: A\ndata:\n  key: \"one\n---\n  two\"\n---\nkind: B\n",
+			expect: []string{"kind: A\ndata:\n  key: \"one\n---\n  two\"", "kind: B"},
+		},
+		{
+			name:   "separator in a single-quoted scalar with escaped quotes",
+			stream: "kind: A\nkey: 'it''s\n---\n  fine'\n---\nkind: B\n",
+			expect: []string{"kind: A\nkey: 'it''s\n---\n  fine'", "kind: B"},
+		},
+		{
+			name:   "escaped double quotes",
+			stream: "kind: A\nkey: \"say \\\"hi\\\"\"\n---\nkind: B\n",
+			expect: []string{"kind: A\nkey: \"say \\\"hi\\\"\"", "kind: B"},
+		},
+		{
+			name:   "apostrophes in plain scalars and comments",
+			stream: "kind: A\ndescription: don't stop # it's fine\n---\nkind: B\n",
+			expect: []string{"kind: A\ndescription: don't stop # it's fine", "kind: B"},
+		},
+		{
+			name:   "unbalanced quotes in a block scalar",
+			stream: "kind: A\nscript: |\n  echo \"hello\n\n  echo it's\n---\nkind: B\n",
+			expect: []string{"kind: A\nscript: |\n  echo \"hello\n\n  echo it's", "kind: B"},
+		},
+		{
+			name:   "indented separators and dashes that are not separators",
+			stream: "kind: A\ncert: |\n  ---\n-----BEGIN CERTIFICATE-----\n---\nkind: B\n",
+			expect: []string{"kind: A\ncert: |\n  ---\n-----BEGIN CERTIFICATE-----", "kind: B"},
+		},
+		{
+			name:   "separators with comments, content and CRLF line endings",
+			stream: "--- # Source: chart/templates/a.yaml\r\nkind: A\r\n---   \r\nkind: B\r\n--- {kind: C}\n",
+			expect: []string{"# Source: chart/templates/a.yaml\nkind: A", "kind: B", "{kind: C}"},
+		},
+		{
+			name:   "document end markers",
+			stream: "kind: A\n...\n---\nkind: B\n",
+			expect: []string{"kind: A", "kind: B"},
+		},
+	}
+	for _, tt := range tests {
+		manifests := SplitManifests(tt.stream)
+		expect := map[string]string{}
+		for i, m := range tt.expect {
+			expect[fmt.Sprintf("manifest-%d", i)] = m
+		}
+		if !reflect.DeepEqual(manifests, expect) {
+			t.Errorf("%s: expected %q, got %q", tt.name, expect, manifests)
+		}
+	}
+}
+
+func TestSplitManifestsKeepEmpty(t *testing.T) {
+	stream := "---\n# Source: chart/templates/empty.yaml\n---\nnull\n---\nkind: A\n---\n"
+	manifests := SplitManifestsWithOptions(stream, SplitOptions{KeepEmpty: true})
+	expect := map[string]string{
+		"manifest-0": "# Source: chart/templates/empty.yaml",
+		"manifest-1": "null",
+		"manifest-2": "kind: A",
+	}
+	if !reflect.DeepEqual(manifests, expect) {
+		t.Errorf("Expected %q, got %q", expect, manifests)
+	}
+}
+
+func TestManifestSource(t *testing.T) {
+	tests := []struct {
+		doc    string
+		expect string
+	}{
+		{"# Source: chart/templates/a.yaml\nkind: A", "chart/templates/a.yaml"},
+		{"# generated\n\n# Source: chart/charts/sub/templates/b.yaml\nkind: B", "chart/charts/sub/templates/b.yaml"},
+		{"kind: A\n# Source: chart/templates/a.yaml", ""},
+		{"kind: A", ""},
+	}
+	for _, tt := range tests {
+		if got := ManifestSource(tt.doc); got != tt.expect {
+			t.Errorf("%q: expected %q, got %q", tt.doc, tt.expect, got)
+		}
+	}
+}
@@ -53,12 +53,18 @@ func (r *Resolver) Resolve(reqs *chartutil.Requirements, repoNames map[string]st
 	locked := make([]*chartutil.Dependency, len(reqs.Dependencies))
 	missing := []string{}
 	for i, d := range reqs.Dependencies {
-		if strings.HasPrefix(d.Repository, "file://") {
+		constraint, err := semver.NewConstraint(d.Version)
+		if err != nil {
+			return nil, fmt.Errorf("dependency %q has an invalid version/constraint format: %s", d.Name, err)
+		}
 
-			if _, err := GetLocalPath(d.Repository, r.chartpath); err != nil {
+		if IsLocalRepository(d.Repository) {
+			if err := r.checkLocal(d, constraint); err != nil {
 				return nil, err
 			}
-
+			// The range is locked rather than the version, so that the
+			// chart is archived afresh from its directory whenever the
+			// dependencies are built, as long as it still satisfies it.
 			locked[i] = &chartutil.Dependency{
 				Name:       d.Name,
 				Repository: d.Repository,
@@ -66,10 +72,6 @@ func (r *Resolver) Resolve(reqs *chartutil.Requirements, repoNames map[string]st
 			}
 			continue
 		}
-		constraint, err := semver.NewConstraint(d.Version)
-		if err != nil {
-			return nil, fmt.Errorf("dependency %q has an invalid version/constraint format: %s", d.Name, err)
-		}
 
 		repoIndex, err := repo.LoadIndexFile(r.helmhome.CacheIndex(repoNames[d.Name]))
 		if err != nil {
@@ -114,6 +116,39 @@ func (r *Resolver) Resolve(reqs *chartutil.Requirements, repoNames map[string]st
 	}, nil
 }
 
+// checkLocal checks that the directory of a local dependency holds a chart
+// whose version satisfies constraint.
+func (r *Resolver) checkLocal(d *chartutil.Dependency, constraint *semver.Constraints) error {
+	p, err := GetLocalPath(d.Repository, r.chartpath)
+	if err != nil {
+		return err
+	}
+	ch, err := chartutil.LoadDir(p)
+	if err != nil {
+		return fmt.Errorf("dependency %q: cannot load chart from %s: %s", d.Name, p, err)
+	}
+	v, err := semver.NewVersion(ch.Metadata.Version)
+	if err != nil {
+		return fmt.Errorf("dependency %q: chart in %s has an invalid version %q: %s", d.Name, p, ch.Metadata.Version, err)
+	}
+	if !constraint.Check(v) {
+		return fmt.Errorf("dependency %q: chart in %s has version %s, which does not satisfy %s", d.Name, p, v, d.Version)
+	}
+	return nil
+}
+
+// IsLocalRepository reports whether repo names a chart directory on the local
+// filesystem: either a "file://" URL or a path starting with "/", "./" or
+// "../". Relative paths are relative to the chart that depends on them.
+func IsLocalRepository(repo string) bool {
+	for _, prefix := range []string{"file://", "/", "./", "../"} {
+		if strings.HasPrefix(repo, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
 // HashReq generates a hash of the requirements.
 //
 // This should be used only to compare against another hash generated by this
@@ -128,7 +163,7 @@ func HashReq(req *chartutil.Requirements) (string, error) {
 }
 
 // GetLocalPath generates absolute local path when use
-// "file://" in repository of requirements
+// "file://" or a local path in repository of requirements
 func GetLocalPath(repo string, chartpath string) (string, error) {
 	var depPath string
 	var err error
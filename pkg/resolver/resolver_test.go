@@ -90,6 +90,37 @@ func TestResolve(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "repo from relative local path",
+			req: &chartutil.Requirements{
+				Dependencies: []*chartutil.Dependency{
+					{Name: "signtest", Repository: "../../../../cmd/helm/testdata/testcharts/signtest", Version: "~0.1.0"},
+				},
+			},
+			expect: &chartutil.RequirementsLock{
+				Dependencies: []*chartutil.Dependency{
+					{Name: "signtest", Repository: "../../../../cmd/helm/testdata/testcharts/signtest", Version: "~0.1.0"},
+				},
+			},
+		},
+		{
+			name: "local chart does not satisfy constraint",
+			req: &chartutil.Requirements{
+				Dependencies: []*chartutil.Dependency{
+					{Name: "signtest", Repository: "file://../../../../cmd/helm/testdata/testcharts/signtest", Version: ">=1.0.0"},
+				},
+			},
+			err: true,
+		},
+		{
+			name: "local directory is not a chart",
+			req: &chartutil.Requirements{
+				Dependencies: []*chartutil.Dependency{
+					{Name: "helmhome", Repository: "file://../helmhome", Version: "0.1.0"},
+				},
+			},
+			err: true,
+		},
 		{
 			name: "repo from invalid local path",
 			req: &chartutil.Requirements{
@@ -145,6 +176,25 @@ func TestResolve(t *testing.T) {
 	}
 }
 
+func TestIsLocalRepository(t *testing.T) {
+	tests := map[string]bool{
+		"file://../common":           true,
+		"/srv/charts/common":         true,
+		"./common":                   true,
+		"../common":                  true,
+		"https://example.com/charts": false,
+		"@stable":                    false,
+		"alias:stable":               false,
+		"":                           false,
+		"common":                     false,
+	}
+	for repo, expect := range tests {
+		if got := IsLocalRepository(repo); got != expect {
+			t.Errorf("IsLocalRepository(%q): expected %t, got %t", repo, expect, got)
+		}
+	}
+}
+
 func TestHashReq(t *testing.T) {
 	expect := "sha256:917e251ddba291096889f81eb7de713ab4e1afbbb07c576dfd7d66ba9300b12b"
 	req := &chartutil.Requirements{
@@ -51,6 +51,12 @@ var magicGzip = []byte{0x1f, 0x8b, 0x08}
 type ConfigMaps struct {
 	impl internalversion.ConfigMapInterface
 	Log  func(string, ...interface{})
+
+	// Legacy, if set, is searched for releases that are not found in impl.
+	// It keeps releases recorded in a previous storage namespace visible
+	// after the storage namespace has been changed. Releases are moved out
+	// of Legacy as they are updated.
+	Legacy internalversion.ConfigMapInterface
 }
 
 // NewConfigMaps initializes a new ConfigMaps wrapping an implmenetation of
@@ -70,24 +76,71 @@ func (cfgmaps *ConfigMaps) Name() string {
 // Get fetches the release named by key. The corresponding release is returned
 // or error if not found.
 func (cfgmaps *ConfigMaps) Get(key string) (*rspb.Release, error) {
+	r, _, err := cfgmaps.GetVersion(key)
+	return r, err
+}
+
+// GetVersion implements Versioned. The version is the resourceVersion of the
+// ConfigMap holding the release.
+func (cfgmaps *ConfigMaps) GetVersion(key string) (*rspb.Release, string, error) {
 	// fetch the configmap holding the release named by key
-	obj, err := cfgmaps.impl.Get(key, metav1.GetOptions{})
+	obj, _, err := cfgmaps.get(key)
 	if err != nil {
 		if apierrors.IsNotFound(err) {
-			return nil, ErrReleaseNotFound(key)
+			return nil, "", ErrReleaseNotFound(key)
 		}
 
 		cfgmaps.Log("get: failed to get %q: %s", key, err)
-		return nil, err
+		return nil, "", err
 	}
 	// found the configmap, decode the base64 data string
 	r, err := decodeRelease(obj.Data["release"])
 	if err != nil {
 		cfgmaps.Log("get: failed to decode data %q: %s", key, err)
-		return nil, err
+		return nil, "", err
 	}
 	// return the release object
-	return r, nil
+	return r, obj.ResourceVersion, nil
+}
+
+// get fetches the configmap named by key, along with the interface it was
+// found in, falling back to Legacy if it is set.
+func (cfgmaps *ConfigMaps) get(key string) (*api.ConfigMap, internalversion.ConfigMapInterface, error) {
+	obj, err := cfgmaps.impl.Get(key, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) && cfgmaps.Legacy != nil {
+		if obj, lerr := cfgmaps.Legacy.Get(key, metav1.GetOptions{}); lerr == nil {
+			return obj, cfgmaps.Legacy, nil
+		}
+	}
+	return obj, cfgmaps.impl, err
+}
+
+// list lists the configmaps matching opts in impl and Legacy. Where a
+// configmap exists in both, the one in impl wins.
+func (cfgmaps *ConfigMaps) list(opts metav1.ListOptions) ([]api.ConfigMap, error) {
+	list, err := cfgmaps.impl.List(opts)
+	if err != nil {
+		return nil, err
+	}
+	items := list.Items
+	if cfgmaps.Legacy == nil {
+		return items, nil
+	}
+
+	legacy, err := cfgmaps.Legacy.List(opts)
+	if err != nil {
+		return nil, err
+	}
+	seen := make(map[string]bool, len(items))
+	for _, item := range items {
+		seen[item.Name] = true
+	}
+	for _, item := range legacy.Items {
+		if !seen[item.Name] {
+			items = append(items, item)
+		}
+	}
+	return items, nil
 }
 
 // List fetches all releases and returns the list releases such
@@ -97,7 +150,7 @@ func (cfgmaps *ConfigMaps) List(filter func(*rspb.Release) bool) ([]*rspb.Releas
 	lsel := kblabels.Set{"OWNER": "TILLER"}.AsSelector()
 	opts := metav1.ListOptions{LabelSelector: lsel.String()}
 
-	list, err := cfgmaps.impl.List(opts)
+	items, err := cfgmaps.list(opts)
 	if err != nil {
 		cfgmaps.Log("list: failed to list: %s", err)
 		return nil, err
@@ -107,7 +160,7 @@ func (cfgmaps *ConfigMaps) List(filter func(*rspb.Release) bool) ([]*rspb.Releas
 
 	// iterate over the configmaps object list
 	// and decode each release
-	for _, item := range list.Items {
+	for _, item := range items {
 		rls, err := decodeRelease(item.Data["release"])
 		if err != nil {
 			cfgmaps.Log("list: failed to decode release: %v: %s", item, err)
@@ -133,18 +186,18 @@ func (cfgmaps *ConfigMaps) Query(labels map[string]string) ([]*rspb.Release, err
 
 	opts := metav1.ListOptions{LabelSelector: ls.AsSelector().String()}
 
-	list, err := cfgmaps.impl.List(opts)
+	items, err := cfgmaps.list(opts)
 	if err != nil {
 		cfgmaps.Log("query: failed to query with labels: %s", err)
 		return nil, err
 	}
 
-	if len(list.Items) == 0 {
+	if len(items) == 0 {
 		return nil, ErrReleaseNotFound(labels["NAME"])
 	}
 
 	var results []*rspb.Release
-	for _, item := range list.Items {
+	for _, item := range items {
 		rls, err := decodeRelease(item.Data["release"])
 		if err != nil {
 			cfgmaps.Log("query: failed to decode release: %s", err)
@@ -185,6 +238,13 @@ func (cfgmaps *ConfigMaps) Create(key string, rls *rspb.Release) error {
 // Update updates the ConfigMap holding the release. If not found
 // the ConfigMap is created to hold the release.
 func (cfgmaps *ConfigMaps) Update(key string, rls *rspb.Release) error {
+	return cfgmaps.UpdateVersion(key, rls, "")
+}
+
+// UpdateVersion implements Versioned. The API server rejects the update with
+// a conflict unless version is the current resourceVersion of the ConfigMap.
+// An empty version updates the ConfigMap regardless.
+func (cfgmaps *ConfigMaps) UpdateVersion(key string, rls *rspb.Release, version string) error {
 	// set labels for configmaps object meta data
 	var lbs labels
 
@@ -197,8 +257,12 @@ func (cfgmaps *ConfigMaps) Update(key string, rls *rspb.Release) error {
 		cfgmaps.Log("update: failed to encode release %q: %s", rls.Name, err)
 		return err
 	}
+	obj.ResourceVersion = version
 	// push the configmap object out into the kubiverse
 	_, err = cfgmaps.impl.Update(obj)
+	if apierrors.IsNotFound(err) && cfgmaps.Legacy != nil {
+		return cfgmaps.migrate(key, obj)
+	}
 	if err != nil {
 		cfgmaps.Log("update: failed to update: %s", err)
 		return err
@@ -206,19 +270,48 @@ func (cfgmaps *ConfigMaps) Update(key string, rls *rspb.Release) error {
 	return nil
 }
 
+// IsConflict implements ConflictDetector. The API server rejects a write with
+// a conflict when an object it changes along with the ConfigMap, such as a
+// ResourceQuota of the namespace, was changed by another writer.
+func (cfgmaps *ConfigMaps) IsConflict(err error) bool {
+	return apierrors.IsConflict(err)
+}
+
+// migrate moves a release that is only recorded in Legacy into impl,
+// storing obj in place of the legacy record.
+func (cfgmaps *ConfigMaps) migrate(key string, obj *api.ConfigMap) error {
+	if _, err := cfgmaps.Legacy.Get(key, metav1.GetOptions{}); err != nil {
+		cfgmaps.Log("update: failed to update: %s", err)
+		return err
+	}
+	if _, err := cfgmaps.impl.Create(obj); err != nil {
+		cfgmaps.Log("update: failed to move %q out of the legacy namespace: %s", key, err)
+		return err
+	}
+	if err := cfgmaps.Legacy.Delete(key, &metav1.DeleteOptions{}); err != nil {
+		cfgmaps.Log("update: failed to delete legacy record %q: %s", key, err)
+	}
+	return nil
+}
+
 // Delete deletes the ConfigMap holding the release named by key.
 func (cfgmaps *ConfigMaps) Delete(key string) (rls *rspb.Release, err error) {
 	// fetch the release to check existence
-	if rls, err = cfgmaps.Get(key); err != nil {
+	obj, impl, err := cfgmaps.get(key)
+	if err != nil {
 		if apierrors.IsNotFound(err) {
-			return nil, ErrReleaseExists(rls.Name)
+			return nil, ErrReleaseNotFound(key)
 		}
 
 		cfgmaps.Log("delete: failed to get release %q: %s", key, err)
 		return nil, err
 	}
+	if rls, err = decodeRelease(obj.Data["release"]); err != nil {
+		cfgmaps.Log("delete: failed to decode data %q: %s", key, err)
+		return nil, err
+	}
 	// delete the release
-	if err = cfgmaps.impl.Delete(key, &metav1.DeleteOptions{}); err != nil {
+	if err = impl.Delete(key, &metav1.DeleteOptions{}); err != nil {
 		return rls, err
 	}
 	return rls, nil
@@ -236,6 +329,8 @@ func (cfgmaps *ConfigMaps) Delete(key string) (rls *rspb.Release, err error) {
 //    "STATUS"         - status of the release (see proto/hapi/release.status.pb.go for variants)
 //    "OWNER"          - owner of the configmap, currently "TILLER".
 //    "NAME"           - name of the release.
+//    "CLUSTER"        - cluster the release is installed in, unless it is Tiller's own.
+//    "SYSTEM"         - "true" for system releases, which listings leave out by default.
 //
 func newConfigMapsObject(key string, rls *rspb.Release, lbs labels) (*api.ConfigMap, error) {
 	const owner = "TILLER"
@@ -255,6 +350,12 @@ func newConfigMapsObject(key string, rls *rspb.Release, lbs labels) (*api.Config
 	lbs.set("OWNER", owner)
 	lbs.set("STATUS", rspb.Status_Code_name[int32(rls.Info.Status.Code)])
 	lbs.set("VERSION", strconv.Itoa(int(rls.Version)))
+	if rls.Cluster != "" {
+		lbs.set("CLUSTER", rls.Cluster)
+	}
+	if rls.System {
+		lbs.set("SYSTEM", "true")
+	}
 
 	// create and return configmap object
 	return &api.ConfigMap{
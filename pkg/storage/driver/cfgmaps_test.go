@@ -15,10 +15,13 @@ package driver
 
 import (
 	"encoding/base64"
+	"errors"
 	"reflect"
 	"testing"
 
 	"github.com/gogo/protobuf/proto"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/kubernetes/pkg/api"
 
 	rspb "k8s.io/helm/pkg/proto/hapi/release"
@@ -156,6 +159,58 @@ func TestConfigMapCreate(t *testing.T) {
 	}
 }
 
+func TestConfigMapCreateCluster(t *testing.T) {
+	cfgmaps := newTestFixtureCfgMaps(t)
+
+	rel := releaseStub("smug-pigeon", 1, "default", rspb.Status_DEPLOYED)
+	rel.Cluster = "spoke-1"
+	key := testKey(rel.Name, rel.Version)
+	if err := cfgmaps.Create(key, rel); err != nil {
+		t.Fatalf("Failed to create release with key %q: %s", key, err)
+	}
+
+	obj, err := cfgmaps.impl.Get(key, metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("Failed to get configmap with key %q: %s", key, err)
+	}
+	if got := obj.Labels["CLUSTER"]; got != "spoke-1" {
+		t.Errorf("Expected the cluster to be recorded in a label, got %q", got)
+	}
+	got, err := cfgmaps.Get(key)
+	if err != nil {
+		t.Fatalf("Failed to get release with key %q: %s", key, err)
+	}
+	if got.Cluster != "spoke-1" {
+		t.Errorf("Expected the cluster to be recorded with the release, got %q", got.Cluster)
+	}
+}
+
+func TestConfigMapCreateSystem(t *testing.T) {
+	cfgmaps := newTestFixtureCfgMaps(t)
+
+	rel := releaseStub("kube-dns", 1, "kube-system", rspb.Status_DEPLOYED)
+	rel.System = true
+	key := testKey(rel.Name, rel.Version)
+	if err := cfgmaps.Create(key, rel); err != nil {
+		t.Fatalf("Failed to create release with key %q: %s", key, err)
+	}
+
+	obj, err := cfgmaps.impl.Get(key, metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("Failed to get configmap with key %q: %s", key, err)
+	}
+	if got := obj.Labels["SYSTEM"]; got != "true" {
+		t.Errorf("Expected the system mark to be recorded in a label, got %q", got)
+	}
+	got, err := cfgmaps.Get(key)
+	if err != nil {
+		t.Fatalf("Failed to get release with key %q: %s", key, err)
+	}
+	if !got.System {
+		t.Error("Expected the system mark to be recorded with the release")
+	}
+}
+
 func TestConfigMapUpdate(t *testing.T) {
 	vers := int32(1)
 	name := "smug-pigeon"
@@ -184,3 +239,78 @@ func TestConfigMapUpdate(t *testing.T) {
 		t.Errorf("Expected status %s, got status %s", rel.Info.Status.Code, got.Info.Status.Code)
 	}
 }
+
+func TestConfigMapLegacyFallback(t *testing.T) {
+	name := "smug-pigeon"
+	legacyRel := releaseStub(name, 1, "tenant-a", rspb.Status_SUPERSEDED)
+	currentRel := releaseStub(name, 2, "tenant-a", rspb.Status_DEPLOYED)
+
+	var legacy MockConfigMapsInterface
+	legacy.Init(t, legacyRel)
+
+	cfgmaps := newTestFixtureCfgMaps(t, currentRel)
+	cfgmaps.Legacy = &legacy
+
+	// releases recorded in the legacy namespace are still found
+	if _, err := cfgmaps.Get(testKey(name, 1)); err != nil {
+		t.Fatalf("Failed to get legacy release: %s", err)
+	}
+
+	all, err := cfgmaps.List(func(*rspb.Release) bool { return true })
+	if err != nil {
+		t.Fatalf("Failed to list releases: %s", err)
+	}
+	if len(all) != 2 {
+		t.Errorf("Expected 2 releases, got %d", len(all))
+	}
+
+	hist, err := cfgmaps.Query(map[string]string{"NAME": name, "OWNER": "TILLER"})
+	if err != nil {
+		t.Fatalf("Failed to query releases: %s", err)
+	}
+	if len(hist) != 2 {
+		t.Errorf("Expected 2 releases, got %d", len(hist))
+	}
+
+	// updating a legacy release moves it into the storage namespace
+	legacyRel.Info.Status.Code = rspb.Status_DELETED
+	if err := cfgmaps.Update(testKey(name, 1), legacyRel); err != nil {
+		t.Fatalf("Failed to update legacy release: %s", err)
+	}
+	if _, err := legacy.Get(testKey(name, 1), metav1.GetOptions{}); err == nil {
+		t.Error("Expected the legacy record to be removed")
+	}
+	if _, err := cfgmaps.impl.Get(testKey(name, 1), metav1.GetOptions{}); err != nil {
+		t.Errorf("Expected the release in the storage namespace: %s", err)
+	}
+
+	// deleting falls back to the legacy namespace as well
+	legacy.Init(t, releaseStub(name, 0, "tenant-a", rspb.Status_SUPERSEDED))
+	if _, err := cfgmaps.Delete(testKey(name, 0)); err != nil {
+		t.Fatalf("Failed to delete legacy release: %s", err)
+	}
+	if _, err := cfgmaps.Get(testKey(name, 0)); err == nil {
+		t.Error("Expected the legacy release to be deleted")
+	}
+	if _, err := cfgmaps.Delete(testKey(name, 9)); err == nil {
+		t.Error("Expected an error deleting a missing release")
+	}
+}
+
+func TestConfigMapIsConflict(t *testing.T) {
+	cfgmaps := newTestFixtureCfgMaps(t)
+
+	quota := api.Resource("resourcequotas")
+	if !cfgmaps.IsConflict(apierrors.NewConflict(quota, "compute", errors.New("the object has been modified"))) {
+		t.Error("Expected a conflict to be detected")
+	}
+	if cfgmaps.IsConflict(apierrors.NewAlreadyExists(api.Resource("configmaps"), "smug-pigeon.v1")) {
+		t.Error("Expected an existing ConfigMap not to be a conflict")
+	}
+	if !IsConflict(NewInstrumented(cfgmaps), apierrors.NewConflict(quota, "compute", errors.New("modified"))) {
+		t.Error("Expected an instrumented driver to pass on the conflict detection")
+	}
+	if IsConflict(NewMemory(), errors.New("modified")) {
+		t.Error("Expected the memory driver to have no conflicts")
+	}
+}
@@ -69,6 +69,58 @@ type Queryor interface {
 	Query(labels map[string]string) ([]*rspb.Release, error)
 }
 
+// ConflictDetector is implemented by drivers whose writes can fail because
+// another writer changed a shared object at the same time, such as the index
+// of the Objects driver, or a ResourceQuota that Kubernetes updates along with
+// every ConfigMap it admits.
+//
+// IsConflict reports whether err, as returned by Create or Update, is such a
+// conflict. The write did not take place, and can be tried again.
+type ConflictDetector interface {
+	IsConflict(err error) bool
+}
+
+// IsConflict reports whether d detects err as a write conflict. Drivers that
+// do not implement ConflictDetector have none.
+func IsConflict(d Driver, err error) bool {
+	c, ok := d.(ConflictDetector)
+	return ok && err != nil && c.IsConflict(err)
+}
+
+// Versioned is implemented by drivers whose records carry a version that
+// changes with every write, such as the resourceVersion of a ConfigMap.
+//
+// GetVersion returns the release stored under key along with the version of
+// its record.
+//
+// UpdateVersion updates the release stored under key like Update, but only if
+// its record is still at version. Otherwise the write fails with a write
+// conflict, see ConflictDetector.
+type Versioned interface {
+	GetVersion(key string) (*rspb.Release, string, error)
+	UpdateVersion(key string, rls *rspb.Release, version string) error
+}
+
+// GetVersion returns the release stored under key in d with the version of
+// its record. The version is empty if d does not implement Versioned.
+func GetVersion(d Driver, key string) (*rspb.Release, string, error) {
+	if v, ok := d.(Versioned); ok {
+		return v.GetVersion(key)
+	}
+	rls, err := d.Get(key)
+	return rls, "", err
+}
+
+// UpdateVersion updates the release stored under key in d, if its record is
+// still at version. An empty version, or a driver that does not implement
+// Versioned, updates the release regardless of its version.
+func UpdateVersion(d Driver, key string, rls *rspb.Release, version string) error {
+	if v, ok := d.(Versioned); ok && version != "" {
+		return v.UpdateVersion(key, rls, version)
+	}
+	return d.Update(key, rls)
+}
+
 // Driver is the interface composed of Creator, Updator, Deletor, Queryor
 // interfaces. It defines the behavior for storing, updating, deleted,
 // and retrieving tiller releases from some underlying storage mechanism,
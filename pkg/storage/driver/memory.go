@@ -141,6 +141,13 @@ func (mem *Memory) Delete(key string) (*rspb.Release, error) {
 		}
 		if recs, ok := mem.cache[name]; ok {
 			if r := recs.Remove(key); r != nil {
+				// Remove shrinks the local copy of the slice; store it back
+				// so that the removed record is not left in the cache.
+				if len(recs) == 0 {
+					delete(mem.cache, name)
+				} else {
+					mem.cache[name] = recs
+				}
 				return r.rls, nil
 			}
 		}
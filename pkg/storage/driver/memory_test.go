@@ -166,3 +166,26 @@ func TestMemoryDelete(t *testing.T) {
 		}
 	}
 }
+
+func TestMemoryDeleteAll(t *testing.T) {
+	ts := tsFixtureMemory(t)
+	for v := 1; v <= 4; v++ {
+		if _, err := ts.Delete(testKey("rls-a", int32(v))); err != nil {
+			t.Fatalf("Failed to delete rls-a v%d: %s", v, err)
+		}
+	}
+
+	// Every revision of rls-a is gone, and listing must not trip over them.
+	ls, err := ts.List(func(*rspb.Release) bool { return true })
+	if err != nil {
+		t.Fatalf("Failed to list: %s", err)
+	}
+	if len(ls) != 4 {
+		t.Errorf("Expected the 4 revisions of rls-b, got %d releases", len(ls))
+	}
+	for _, rls := range ls {
+		if rls.Name != "rls-b" {
+			t.Errorf("Expected only rls-b, got %s", rls.Name)
+		}
+	}
+}
@@ -0,0 +1,105 @@
+/*
+Copyright 2017 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package driver // import "k8s.io/helm/pkg/storage/driver"
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	rspb "k8s.io/helm/pkg/proto/hapi/release"
+)
+
+var storageDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+	Namespace: "tiller",
+	Name:      "storage_operation_duration_seconds",
+	Help:      "Duration of storage driver calls, by driver and operation.",
+	Buckets:   []float64{0.001, 0.005, 0.01, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10},
+}, []string{"driver", "operation"})
+
+func init() {
+	prometheus.MustRegister(storageDuration)
+}
+
+// Instrumented is a Driver that records the duration of the calls it passes
+// on to another Driver in the tiller_storage_operation_duration_seconds
+// metric.
+type Instrumented struct {
+	Driver
+}
+
+// NewInstrumented wraps d so that the duration of its calls is recorded.
+func NewInstrumented(d Driver) *Instrumented {
+	return &Instrumented{Driver: d}
+}
+
+func (i *Instrumented) observe(operation string, start time.Time) {
+	storageDuration.WithLabelValues(i.Driver.Name(), operation).Observe(time.Since(start).Seconds())
+}
+
+// Get implements Queryor.
+func (i *Instrumented) Get(key string) (*rspb.Release, error) {
+	defer i.observe("get", time.Now())
+	return i.Driver.Get(key)
+}
+
+// List implements Queryor.
+func (i *Instrumented) List(filter func(*rspb.Release) bool) ([]*rspb.Release, error) {
+	defer i.observe("list", time.Now())
+	return i.Driver.List(filter)
+}
+
+// Query implements Queryor.
+func (i *Instrumented) Query(labels map[string]string) ([]*rspb.Release, error) {
+	defer i.observe("query", time.Now())
+	return i.Driver.Query(labels)
+}
+
+// Create implements Creator.
+func (i *Instrumented) Create(key string, rls *rspb.Release) error {
+	defer i.observe("create", time.Now())
+	return i.Driver.Create(key, rls)
+}
+
+// Update implements Updator.
+func (i *Instrumented) Update(key string, rls *rspb.Release) error {
+	defer i.observe("update", time.Now())
+	return i.Driver.Update(key, rls)
+}
+
+// GetVersion implements Versioned for the wrapped Driver.
+func (i *Instrumented) GetVersion(key string) (*rspb.Release, string, error) {
+	defer i.observe("get", time.Now())
+	return GetVersion(i.Driver, key)
+}
+
+// UpdateVersion implements Versioned for the wrapped Driver.
+func (i *Instrumented) UpdateVersion(key string, rls *rspb.Release, version string) error {
+	defer i.observe("update", time.Now())
+	return UpdateVersion(i.Driver, key, rls, version)
+}
+
+// Delete implements Deletor.
+func (i *Instrumented) Delete(key string) (*rspb.Release, error) {
+	defer i.observe("delete", time.Now())
+	return i.Driver.Delete(key)
+}
+
+// IsConflict implements ConflictDetector for the wrapped Driver.
+func (i *Instrumented) IsConflict(err error) bool {
+	return IsConflict(i.Driver, err)
+}
@@ -0,0 +1,73 @@
+/*
+Copyright 2017 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package driver
+
+import (
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+
+	rspb "k8s.io/helm/pkg/proto/hapi/release"
+)
+
+func TestInstrumented(t *testing.T) {
+	d := NewInstrumented(NewMemory())
+	if d.Name() != MemoryDriverName {
+		t.Errorf("Expected name to be %q, got %q", MemoryDriverName, d.Name())
+	}
+
+	samples := func(operation string) uint64 {
+		m := &dto.Metric{}
+		if err := storageDuration.WithLabelValues(MemoryDriverName, operation).(prometheus.Metric).Write(m); err != nil {
+			t.Fatal(err)
+		}
+		return m.GetHistogram().GetSampleCount()
+	}
+	before := map[string]uint64{}
+	operations := []string{"create", "get", "update", "list", "query", "delete"}
+	for _, op := range operations {
+		before[op] = samples(op)
+	}
+
+	rls := releaseStub("rls-a", 1, "default", rspb.Status_DEPLOYED)
+	key := testKey(rls.Name, rls.Version)
+	if err := d.Create(key, rls); err != nil {
+		t.Fatal(err)
+	}
+	if got, err := d.Get(key); err != nil || got.Name != rls.Name {
+		t.Fatalf("Expected to get %s, got %v (%v)", rls.Name, got, err)
+	}
+	if err := d.Update(key, rls); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := d.List(func(*rspb.Release) bool { return true }); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := d.Query(map[string]string{"NAME": rls.Name}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := d.Delete(key); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, op := range operations {
+		if got := samples(op); got != before[op]+1 {
+			t.Errorf("Expected %d %s samples, got %d", before[op]+1, op, got)
+		}
+	}
+}
@@ -0,0 +1,358 @@
+/*
+Copyright 2017 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package driver // import "k8s.io/helm/pkg/storage/driver"
+
+import (
+	"fmt"
+	"sync"
+
+	rspb "k8s.io/helm/pkg/proto/hapi/release"
+)
+
+var _ Driver = (*Migrating)(nil)
+
+// Migrating is a storage driver that moves the releases of one driver to
+// another while Tiller keeps serving requests.
+//
+// Until the migration is complete, releases are written to both drivers and
+// read from the new one, falling back to the old one for the releases that
+// have not been copied yet. Migrate copies them. Once every release is in
+// the new driver, Migrate deletes them from the old one, and the old driver
+// is dropped: it is neither read nor written anymore. As the old driver is
+// left empty, a Migrating driver that is set up again after a restart has
+// nothing to copy, and cannot bring back releases that were deleted from the
+// new driver since.
+type Migrating struct {
+	from Driver
+	to   Driver
+	Log  func(string, ...interface{})
+
+	// mu serializes writes with the copying of each release, so that a
+	// release that is changed or deleted while it is copied is not
+	// overwritten with, or brought back as, the copy.
+	mu sync.Mutex
+
+	// statusMu guards status.
+	statusMu sync.RWMutex
+	status   MigrationStatus
+}
+
+// MigrationStatus is the progress of a Migrating driver.
+type MigrationStatus struct {
+	// From and To are the names of the old and the new driver.
+	From, To string
+	// Total is how many releases the old driver held when the latest attempt
+	// to copy them started, and Migrated and Failed how many of them were
+	// copied and could not be copied so far.
+	Total, Migrated, Failed int
+	// Complete is set once every release was copied and deleted from the old
+	// driver, and the old driver was dropped.
+	Complete bool
+	// Error is the latest error copying or deleting a release, if any.
+	Error string
+}
+
+// NewMigrating initializes a driver that migrates the releases in from to
+// to.
+func NewMigrating(from, to Driver) *Migrating {
+	return &Migrating{
+		from:   from,
+		to:     to,
+		Log:    func(_ string, _ ...interface{}) {},
+		status: MigrationStatus{From: from.Name(), To: to.Name()},
+	}
+}
+
+// Name returns the name of the driver that releases are migrated to.
+func (m *Migrating) Name() string {
+	return m.to.Name()
+}
+
+// Status returns the progress of the migration.
+func (m *Migrating) Status() MigrationStatus {
+	m.statusMu.RLock()
+	defer m.statusMu.RUnlock()
+	return m.status
+}
+
+// migrating returns whether the old driver is still in use.
+func (m *Migrating) migrating() bool {
+	return !m.Status().Complete
+}
+
+// Migrate copies the releases of the old driver that the new one does not
+// hold. Once all of them were copied, it deletes them from the old driver
+// and drops it. It returns an error if any release could not be copied or
+// deleted, in which case it can be called again to retry.
+func (m *Migrating) Migrate() error {
+	if !m.migrating() {
+		return nil
+	}
+	rels, err := m.from.List(func(_ *rspb.Release) bool { return true })
+	if err != nil {
+		m.update(func(st *MigrationStatus) { st.Error = err.Error() })
+		return err
+	}
+	m.update(func(st *MigrationStatus) {
+		st.Total, st.Migrated, st.Failed = len(rels), 0, 0
+	})
+	m.Log("migrating %d releases from %s to %s", len(rels), m.from.Name(), m.to.Name())
+
+	var lastErr error
+	for _, rls := range rels {
+		key := fmt.Sprintf("%s.v%d", rls.Name, rls.Version)
+		if err := m.copy(key); err != nil {
+			m.Log("migrate: failed to copy release %q: %s", key, err)
+			lastErr = fmt.Errorf("release %q: %s", key, err)
+			m.update(func(st *MigrationStatus) {
+				st.Failed++
+				st.Error = lastErr.Error()
+			})
+			continue
+		}
+		m.update(func(st *MigrationStatus) { st.Migrated++ })
+	}
+	if lastErr != nil {
+		st := m.Status()
+		return fmt.Errorf("%d of %d releases could not be migrated; the last error: %s", st.Failed, st.Total, lastErr)
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if err := m.drain(); err != nil {
+		m.update(func(st *MigrationStatus) { st.Error = err.Error() })
+		return err
+	}
+	m.update(func(st *MigrationStatus) {
+		st.Complete = true
+		st.Error = ""
+	})
+	m.Log("migrated %d releases from %s to %s; %s is no longer used", len(rels), m.from.Name(), m.to.Name(), m.from.Name())
+	return nil
+}
+
+// drain deletes every release from the old driver, copying those that the
+// new one does not hold yet first. The old driver is only left empty if
+// every release could be deleted; otherwise the migration goes on, and the
+// releases that are left are still written to both drivers. m.mu must be
+// held.
+func (m *Migrating) drain() error {
+	rels, err := m.from.List(func(_ *rspb.Release) bool { return true })
+	if err != nil {
+		return err
+	}
+	failed := 0
+	var lastErr error
+	for _, rls := range rels {
+		key := fmt.Sprintf("%s.v%d", rls.Name, rls.Version)
+		err := m.copyLocked(key)
+		if err == nil {
+			_, err = m.from.Delete(key)
+		}
+		if err != nil {
+			m.Log("migrate: failed to delete release %q from %s: %s", key, m.from.Name(), err)
+			failed++
+			lastErr = fmt.Errorf("release %q: %s", key, err)
+		}
+	}
+	if lastErr != nil {
+		return fmt.Errorf("%d of %d releases could not be deleted from %s; the last error: %s", failed, len(rels), m.from.Name(), lastErr)
+	}
+	return nil
+}
+
+// copy copies the release named by key to the new driver, unless it is
+// there already. The release is read again, as it may have changed since it
+// was listed.
+func (m *Migrating) copy(key string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.copyLocked(key)
+}
+
+// copyLocked is copy with m.mu held.
+func (m *Migrating) copyLocked(key string) error {
+	if _, err := m.to.Get(key); err == nil {
+		return nil
+	}
+	rls, err := m.from.Get(key)
+	if err != nil {
+		return err
+	}
+	return m.to.Create(key, rls)
+}
+
+func (m *Migrating) update(fn func(*MigrationStatus)) {
+	m.statusMu.Lock()
+	defer m.statusMu.Unlock()
+	fn(&m.status)
+}
+
+// Get returns the release named by key from the new driver or, until the
+// migration is complete, from the old one.
+func (m *Migrating) Get(key string) (*rspb.Release, error) {
+	rls, err := m.to.Get(key)
+	if err != nil && m.migrating() {
+		if old, oerr := m.from.Get(key); oerr == nil {
+			return old, nil
+		}
+	}
+	return rls, err
+}
+
+// List returns the releases of both drivers such that filter(release) ==
+// true, preferring the copy in the new driver.
+func (m *Migrating) List(filter func(*rspb.Release) bool) ([]*rspb.Release, error) {
+	rels, err := m.to.List(filter)
+	if err != nil || !m.migrating() {
+		return rels, err
+	}
+	old, err := m.from.List(filter)
+	if err != nil {
+		return nil, err
+	}
+	return mergeReleases(rels, old), nil
+}
+
+// Query returns the releases of both drivers that match the labels,
+// preferring the copy in the new driver. As releases are written to both
+// drivers while they are migrated, an error of one driver is only returned
+// if neither found a release.
+func (m *Migrating) Query(labels map[string]string) ([]*rspb.Release, error) {
+	rels, err := m.to.Query(labels)
+	if !m.migrating() {
+		return rels, err
+	}
+	old, oerr := m.from.Query(labels)
+	if merged := mergeReleases(rels, old); len(merged) > 0 {
+		return merged, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return nil, oerr
+}
+
+// mergeReleases adds the releases of old that are not in rels to rels.
+func mergeReleases(rels, old []*rspb.Release) []*rspb.Release {
+	seen := make(map[string]bool, len(rels))
+	for _, rls := range rels {
+		seen[fmt.Sprintf("%s.v%d", rls.Name, rls.Version)] = true
+	}
+	for _, rls := range old {
+		if !seen[fmt.Sprintf("%s.v%d", rls.Name, rls.Version)] {
+			rels = append(rels, rls)
+		}
+	}
+	return rels
+}
+
+// Create stores the release in the new driver and, until the migration is
+// complete, in the old one. It returns ErrReleaseExists if either holds it.
+func (m *Migrating) Create(key string, rls *rspb.Release) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if !m.migrating() {
+		return m.to.Create(key, rls)
+	}
+	if _, err := m.from.Get(key); err == nil {
+		return ErrReleaseExists(key)
+	}
+	if err := m.to.Create(key, rls); err != nil {
+		return err
+	}
+	if err := m.from.Create(key, rls); err != nil {
+		m.Log("create: failed to write release %q to %s: %s", key, m.from.Name(), err)
+	}
+	return nil
+}
+
+// Update updates the release in the new driver and, until the migration is
+// complete, in the old one. A release that was not copied yet is copied
+// with the update.
+func (m *Migrating) Update(key string, rls *rspb.Release) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if !m.migrating() {
+		return m.to.Update(key, rls)
+	}
+	if err := m.to.Update(key, rls); err != nil {
+		if _, gerr := m.to.Get(key); gerr == nil {
+			return err
+		}
+		if _, oerr := m.from.Get(key); oerr != nil {
+			return err
+		}
+		if err := m.to.Create(key, rls); err != nil {
+			return err
+		}
+	}
+	if err := m.from.Update(key, rls); err != nil {
+		m.Log("update: failed to write release %q to %s: %s", key, m.from.Name(), err)
+	}
+	return nil
+}
+
+// GetVersion implements Versioned for the new driver. While migrating, the
+// version is empty, as the release may only be stored in the old driver.
+func (m *Migrating) GetVersion(key string) (*rspb.Release, string, error) {
+	if m.migrating() {
+		rls, err := m.Get(key)
+		return rls, "", err
+	}
+	return GetVersion(m.to, key)
+}
+
+// UpdateVersion implements Versioned for the new driver. While migrating, it
+// updates the release like Update.
+func (m *Migrating) UpdateVersion(key string, rls *rspb.Release, version string) error {
+	if m.migrating() {
+		return m.Update(key, rls)
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return UpdateVersion(m.to, key, rls, version)
+}
+
+// IsConflict implements ConflictDetector for both drivers, as writes go to
+// both of them.
+func (m *Migrating) IsConflict(err error) bool {
+	return IsConflict(m.to, err) || IsConflict(m.from, err)
+}
+
+// Delete deletes the release from the new driver and, until the migration
+// is complete, from the old one. It returns ErrReleaseNotFound if neither
+// holds it.
+func (m *Migrating) Delete(key string) (*rspb.Release, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if !m.migrating() {
+		return m.to.Delete(key)
+	}
+	if _, err := m.to.Get(key); err != nil {
+		// Not copied yet: the old driver holds the only copy.
+		return m.from.Delete(key)
+	}
+	rls, err := m.to.Delete(key)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := m.from.Delete(key); err != nil {
+		m.Log("delete: failed to delete release %q from %s: %s", key, m.from.Name(), err)
+	}
+	return rls, nil
+}
@@ -0,0 +1,240 @@
+/*
+Copyright 2017 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package driver
+
+import (
+	"errors"
+	"testing"
+
+	rspb "k8s.io/helm/pkg/proto/hapi/release"
+)
+
+func newMigratingFixture(t *testing.T) (*Migrating, *Memory, *Memory) {
+	from := tsFixtureMemory(t)
+	to := NewMemory()
+	return NewMigrating(from, to), from, to
+}
+
+func TestMigratingName(t *testing.T) {
+	m, _, _ := newMigratingFixture(t)
+	if m.Name() != MemoryDriverName {
+		t.Errorf("Expected the name of the new driver, got %q", m.Name())
+	}
+}
+
+func TestMigratingReadsFallBack(t *testing.T) {
+	m, _, to := newMigratingFixture(t)
+	moved := releaseStub("rls-a", 4, "default", rspb.Status_FAILED)
+	to.Create(testKey("rls-a", 4), moved)
+
+	rls, err := m.Get(testKey("rls-a", 4))
+	if err != nil || rls.Info.Status.Code != rspb.Status_FAILED {
+		t.Errorf("Expected the copy in the new driver, got %v (%v)", rls, err)
+	}
+	if _, err := m.Get(testKey("rls-b", 2)); err != nil {
+		t.Errorf("Expected a release that was not migrated to be read from the old driver: %s", err)
+	}
+	if _, err := m.Get(testKey("rls-c", 1)); err == nil {
+		t.Error("Expected an error for a release in neither driver")
+	}
+
+	rels, err := m.List(func(_ *rspb.Release) bool { return true })
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rels) != 8 {
+		t.Errorf("Expected every release once, got %d", len(rels))
+	}
+	rels, err = m.Query(map[string]string{"NAME": "rls-a", "OWNER": "TILLER"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rels) != 4 {
+		t.Errorf("Expected the 4 revisions of rls-a, got %d", len(rels))
+	}
+	for _, rls := range rels {
+		if rls.Version == 4 && rls.Info.Status.Code != rspb.Status_FAILED {
+			t.Error("Expected the copy in the new driver to be preferred")
+		}
+	}
+}
+
+func TestMigratingWritesToBoth(t *testing.T) {
+	m, from, to := newMigratingFixture(t)
+
+	if err := m.Create(testKey("rls-a", 1), releaseStub("rls-a", 1, "default", rspb.Status_DEPLOYED)); err == nil {
+		t.Error("Expected an error creating a release that the old driver holds")
+	}
+	key := testKey("rls-c", 1)
+	if err := m.Create(key, releaseStub("rls-c", 1, "default", rspb.Status_DEPLOYED)); err != nil {
+		t.Fatal(err)
+	}
+	for _, d := range []Driver{from, to} {
+		if _, err := d.Get(key); err != nil {
+			t.Errorf("Expected the created release in both drivers: %s", err)
+		}
+	}
+
+	// Updating a release that was not copied yet copies it.
+	key = testKey("rls-b", 4)
+	if err := m.Update(key, releaseStub("rls-b", 4, "default", rspb.Status_SUPERSEDED)); err != nil {
+		t.Fatal(err)
+	}
+	for _, d := range []Driver{from, to} {
+		if rls, err := d.Get(key); err != nil || rls.Info.Status.Code != rspb.Status_SUPERSEDED {
+			t.Errorf("Expected the updated release in both drivers, got %v (%v)", rls, err)
+		}
+	}
+	if err := m.Update(testKey("rls-d", 1), releaseStub("rls-d", 1, "default", rspb.Status_DEPLOYED)); err == nil {
+		t.Error("Expected an error updating a release in neither driver")
+	}
+
+	// Deleting removes a release from both drivers, whether it was copied or not.
+	for _, key := range []string{testKey("rls-b", 4), testKey("rls-b", 3)} {
+		if _, err := m.Delete(key); err != nil {
+			t.Fatalf("Failed to delete %s: %s", key, err)
+		}
+		if _, err := m.Get(key); err == nil {
+			t.Errorf("Expected %s to be deleted", key)
+		}
+	}
+	if _, err := m.Delete(testKey("rls-d", 1)); err == nil {
+		t.Error("Expected an error deleting a release in neither driver")
+	}
+}
+
+func TestMigratingMigrate(t *testing.T) {
+	m, from, to := newMigratingFixture(t)
+	// Copies that were written through are kept.
+	to.Create(testKey("rls-a", 4), releaseStub("rls-a", 4, "default", rspb.Status_FAILED))
+
+	if err := m.Migrate(); err != nil {
+		t.Fatal(err)
+	}
+	st := m.Status()
+	if !st.Complete || st.Total != 8 || st.Migrated != 8 || st.Failed != 0 {
+		t.Errorf("Unexpected status %+v", st)
+	}
+	if st.From != MemoryDriverName || st.To != MemoryDriverName {
+		t.Errorf("Expected the names of the drivers, got %q and %q", st.From, st.To)
+	}
+	rels, _ := to.List(func(_ *rspb.Release) bool { return true })
+	if len(rels) != 8 {
+		t.Errorf("Expected 8 releases in the new driver, got %d", len(rels))
+	}
+	if rls, _ := to.Get(testKey("rls-a", 4)); rls.Info.Status.Code != rspb.Status_FAILED {
+		t.Error("Expected a copy that was written through to be kept")
+	}
+	if old, _ := from.List(func(_ *rspb.Release) bool { return true }); len(old) != 0 {
+		t.Errorf("Expected the releases to be deleted from the old driver, got %d", len(old))
+	}
+
+	// The old driver is dropped.
+	key := testKey("rls-c", 1)
+	if err := m.Create(key, releaseStub("rls-c", 1, "default", rspb.Status_DEPLOYED)); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := from.Get(key); err == nil {
+		t.Error("Expected no writes to the old driver after the migration")
+	}
+	from.Create(testKey("rls-d", 1), releaseStub("rls-d", 1, "default", rspb.Status_DEPLOYED))
+	if _, err := m.Get(testKey("rls-d", 1)); err == nil {
+		t.Error("Expected no reads from the old driver after the migration")
+	}
+}
+
+func TestMigratingRestartAfterMigrate(t *testing.T) {
+	m, from, to := newMigratingFixture(t)
+	if err := m.Migrate(); err != nil {
+		t.Fatal(err)
+	}
+	key := testKey("rls-a", 1)
+	if _, err := m.Delete(key); err != nil {
+		t.Fatal(err)
+	}
+
+	// A restarted Tiller migrates the same drivers again.
+	m = NewMigrating(from, to)
+	if _, err := m.Get(key); err == nil {
+		t.Error("Expected a deleted release not to be read from the old driver")
+	}
+	if err := m.Migrate(); err != nil {
+		t.Fatal(err)
+	}
+	if st := m.Status(); !st.Complete || st.Total != 0 {
+		t.Errorf("Expected nothing left to migrate, got %+v", st)
+	}
+	if _, err := to.Get(key); err == nil {
+		t.Error("Expected a deleted release not to be copied again")
+	}
+}
+
+// failingDeletes is a Driver that fails to delete releases.
+type failingDeletes struct {
+	*Memory
+}
+
+func (f failingDeletes) Delete(key string) (*rspb.Release, error) {
+	return nil, errors.New("forbidden")
+}
+
+func TestMigratingMigrateDeleteFails(t *testing.T) {
+	from := failingDeletes{tsFixtureMemory(t)}
+	m := NewMigrating(from, NewMemory())
+
+	if err := m.Migrate(); err == nil {
+		t.Fatal("Expected an error")
+	}
+	st := m.Status()
+	if st.Complete || st.Migrated != 8 || st.Error == "" {
+		t.Errorf("Unexpected status %+v", st)
+	}
+	// Releases are still written to the old driver until they are deleted.
+	key := testKey("rls-c", 1)
+	if err := m.Create(key, releaseStub("rls-c", 1, "default", rspb.Status_DEPLOYED)); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := from.Get(key); err != nil {
+		t.Errorf("Expected writes to the old driver until the migration is complete: %s", err)
+	}
+}
+
+// failingCreates is a Driver that fails to create releases.
+type failingCreates struct {
+	*Memory
+}
+
+func (f failingCreates) Create(key string, rls *rspb.Release) error {
+	return errors.New("quota exceeded")
+}
+
+func TestMigratingMigrateFails(t *testing.T) {
+	from := tsFixtureMemory(t)
+	m := NewMigrating(from, failingCreates{NewMemory()})
+
+	if err := m.Migrate(); err == nil {
+		t.Fatal("Expected an error")
+	}
+	st := m.Status()
+	if st.Complete || st.Failed != 8 || st.Migrated != 0 || st.Error == "" {
+		t.Errorf("Unexpected status %+v", st)
+	}
+	// The old driver is still used.
+	if _, err := m.Get(testKey("rls-a", 1)); err != nil {
+		t.Errorf("Expected releases to be read from the old driver: %s", err)
+	}
+}
@@ -0,0 +1,409 @@
+/*
+Copyright 2017 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package driver // import "k8s.io/helm/pkg/storage/driver"
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	rspb "k8s.io/helm/pkg/proto/hapi/release"
+)
+
+var _ Driver = (*Objects)(nil)
+
+// ObjectsDriverName is the string name of the driver.
+const ObjectsDriverName = "Objects"
+
+// maxIndexAttempts is how often a change to the index is attempted before
+// giving up on concurrent writers.
+const maxIndexAttempts = 10
+
+var (
+	// ErrObjectNotFound is returned by an ObjectStore for a missing object.
+	ErrObjectNotFound = errors.New("object not found")
+	// ErrPreconditionFailed is returned by an ObjectStore when a conditional
+	// write finds another generation of the object than expected.
+	ErrPreconditionFailed = errors.New("object precondition failed")
+)
+
+// ObjectStore is a bucket of objects, such as an S3 or a GCS bucket. Every
+// write of an object gives it a new generation, e.g. an ETag or a GCS
+// generation number, that conditional writes compare against.
+type ObjectStore interface {
+	// Get returns the content and the generation of the object named key,
+	// or ErrObjectNotFound.
+	Get(key string) (data []byte, generation string, err error)
+	// Put writes the object named key if its generation is match, where an
+	// empty match requires that the object does not exist. It returns the
+	// new generation, or ErrPreconditionFailed.
+	Put(key string, data []byte, match string) (generation string, err error)
+	// Delete deletes the object named key, or returns ErrObjectNotFound.
+	Delete(key string) error
+}
+
+// indexConflictError is returned when the index changed concurrently on
+// every attempt to change it.
+type indexConflictError struct {
+	key string
+}
+
+func (e indexConflictError) Error() string {
+	return fmt.Sprintf("%s changed concurrently %d times in a row", e.key, maxIndexAttempts)
+}
+
+// Objects is the storage driver that stores releases in an ObjectStore.
+//
+// Each release is stored in a payload object that is never overwritten: a
+// new payload key is written for every change, so that a reader never sees
+// an older copy of a payload under a key it just learned about. A single
+// index object lists the payload key and the labels of every release. It is
+// changed with conditional writes only, so that concurrent writers do not
+// lose each other's changes, and is what List and Query read.
+type Objects struct {
+	store  ObjectStore
+	prefix string
+	Log    func(string, ...interface{})
+}
+
+// NewObjects initializes a new Objects driver that keeps its objects under
+// prefix in store, e.g. "tiller/".
+func NewObjects(store ObjectStore, prefix string) *Objects {
+	return &Objects{
+		store:  store,
+		prefix: prefix,
+		Log:    func(_ string, _ ...interface{}) {},
+	}
+}
+
+// objectIndex is the content of the index object, by release key.
+type objectIndex map[string]objectEntry
+
+// objectEntry is where the payload of a release is, and its labels.
+type objectEntry struct {
+	Payload string            `json:"payload"`
+	Labels  map[string]string `json:"labels"`
+}
+
+// Name returns the name of the driver.
+func (objs *Objects) Name() string {
+	return ObjectsDriverName
+}
+
+// Get fetches the release named by key, or returns ErrReleaseNotFound.
+func (objs *Objects) Get(key string) (*rspb.Release, error) {
+	index, _, err := objs.readIndex()
+	if err != nil {
+		objs.Log("get: failed to read the index: %s", err)
+		return nil, err
+	}
+	entry, ok := index[key]
+	if !ok {
+		return nil, ErrReleaseNotFound(key)
+	}
+	rls, err := objs.readRelease(key, entry)
+	if err != nil {
+		objs.Log("get: failed to read release %q: %s", key, err)
+		return nil, err
+	}
+	if rls == nil {
+		return nil, ErrReleaseNotFound(key)
+	}
+	return rls, nil
+}
+
+// List fetches all releases and returns those such that filter(release) ==
+// true. A release that cannot be read fails the listing, rather than be left
+// out of it.
+func (objs *Objects) List(filter func(*rspb.Release) bool) ([]*rspb.Release, error) {
+	index, _, err := objs.readIndex()
+	if err != nil {
+		objs.Log("list: failed to read the index: %s", err)
+		return nil, err
+	}
+	var results []*rspb.Release
+	for key, entry := range index {
+		rls, err := objs.readRelease(key, entry)
+		if err != nil {
+			objs.Log("list: failed to read release %q: %s", key, err)
+			return nil, err
+		}
+		if rls != nil && filter(rls) {
+			results = append(results, rls)
+		}
+	}
+	return results, nil
+}
+
+// Query fetches all releases that match the provided map of labels. Only
+// the payloads of matching releases are read.
+func (objs *Objects) Query(keyvals map[string]string) ([]*rspb.Release, error) {
+	index, _, err := objs.readIndex()
+	if err != nil {
+		objs.Log("query: failed to read the index: %s", err)
+		return nil, err
+	}
+
+	var lbs labels
+
+	lbs.init()
+	lbs.fromMap(keyvals)
+
+	var results []*rspb.Release
+	for key, entry := range index {
+		if !labels(entry.Labels).match(lbs) {
+			continue
+		}
+		rls, err := objs.readRelease(key, entry)
+		if err != nil {
+			objs.Log("query: failed to read release %q: %s", key, err)
+			return nil, err
+		}
+		if rls != nil {
+			results = append(results, rls)
+		}
+	}
+	if len(results) == 0 {
+		return nil, ErrReleaseNotFound(keyvals["NAME"])
+	}
+	return results, nil
+}
+
+// Create stores the release, or returns ErrReleaseExists.
+func (objs *Objects) Create(key string, rls *rspb.Release) error {
+	var lbs labels
+
+	lbs.init()
+	lbs.set("CREATED_AT", strconv.Itoa(int(time.Now().Unix())))
+
+	payload, err := objs.writePayload(key, rls)
+	if err != nil {
+		objs.Log("create: failed to write release %q: %s", key, err)
+		return err
+	}
+	err = objs.changeIndex(func(index objectIndex) error {
+		if _, ok := index[key]; ok {
+			return ErrReleaseExists(key)
+		}
+		index[key] = objectEntry{Payload: payload, Labels: objectLabels(rls, lbs)}
+		return nil
+	})
+	if err != nil {
+		objs.Log("create: failed to add %q to the index: %s", key, err)
+		objs.deletePayload(payload)
+		return err
+	}
+	return nil
+}
+
+// Update replaces the stored release, or returns ErrReleaseNotFound.
+func (objs *Objects) Update(key string, rls *rspb.Release) error {
+	var lbs labels
+
+	lbs.init()
+	lbs.set("MODIFIED_AT", strconv.Itoa(int(time.Now().Unix())))
+
+	payload, err := objs.writePayload(key, rls)
+	if err != nil {
+		objs.Log("update: failed to write release %q: %s", key, err)
+		return err
+	}
+	var old string
+	err = objs.changeIndex(func(index objectIndex) error {
+		entry, ok := index[key]
+		if !ok {
+			return ErrReleaseNotFound(key)
+		}
+		old = entry.Payload
+		index[key] = objectEntry{Payload: payload, Labels: objectLabels(rls, lbs)}
+		return nil
+	})
+	if err != nil {
+		objs.Log("update: failed to update %q in the index: %s", key, err)
+		objs.deletePayload(payload)
+		return err
+	}
+	// Only now that the index refers to the new payload is the old one
+	// deleted. Readers that read the index before find it gone, and follow
+	// the index to the new one; see readRelease.
+	objs.deletePayload(old)
+	return nil
+}
+
+// Delete deletes the release named by key, or returns ErrReleaseNotFound.
+func (objs *Objects) Delete(key string) (*rspb.Release, error) {
+	var (
+		rls     *rspb.Release
+		payload string
+	)
+	err := objs.changeIndex(func(index objectIndex) error {
+		entry, ok := index[key]
+		if !ok {
+			return ErrReleaseNotFound(key)
+		}
+		// The release is read before it is removed from the index, and again
+		// only if another writer replaced it.
+		if entry.Payload != payload {
+			r, err := objs.readRelease(key, entry)
+			if err != nil {
+				return err
+			}
+			if r == nil {
+				return ErrReleaseNotFound(key)
+			}
+			rls, payload = r, entry.Payload
+		}
+		delete(index, key)
+		return nil
+	})
+	if err != nil {
+		objs.Log("delete: failed to delete %q: %s", key, err)
+		return nil, err
+	}
+	objs.deletePayload(payload)
+	return rls, nil
+}
+
+// IsConflict implements ConflictDetector. A change of the index is retried
+// before the driver gives up on concurrent writers.
+func (objs *Objects) IsConflict(err error) bool {
+	_, ok := err.(indexConflictError)
+	return ok
+}
+
+func (objs *Objects) indexKey() string {
+	return objs.prefix + "index.json"
+}
+
+// readIndex returns the index and its generation. A missing index is empty.
+func (objs *Objects) readIndex() (objectIndex, string, error) {
+	data, generation, err := objs.store.Get(objs.indexKey())
+	if err == ErrObjectNotFound {
+		return objectIndex{}, "", nil
+	}
+	if err != nil {
+		return nil, "", err
+	}
+	index := objectIndex{}
+	if err := json.Unmarshal(data, &index); err != nil {
+		return nil, "", fmt.Errorf("decoding %s: %s", objs.indexKey(), err)
+	}
+	return index, generation, nil
+}
+
+// changeIndex applies change to the latest index and writes it back, unless
+// change returns an error. It starts over if another writer changed the
+// index in the meantime.
+func (objs *Objects) changeIndex(change func(objectIndex) error) error {
+	for attempt := 0; attempt < maxIndexAttempts; attempt++ {
+		index, generation, err := objs.readIndex()
+		if err != nil {
+			return err
+		}
+		if err := change(index); err != nil {
+			return err
+		}
+		data, err := json.Marshal(index)
+		if err != nil {
+			return err
+		}
+		_, err = objs.store.Put(objs.indexKey(), data, generation)
+		if err != ErrPreconditionFailed {
+			return err
+		}
+		objs.Log("index changed concurrently, retrying")
+	}
+	return indexConflictError{key: objs.indexKey()}
+}
+
+// writePayload writes rls to a new payload object and returns its key.
+func (objs *Objects) writePayload(key string, rls *rspb.Release) (string, error) {
+	s, err := encodeRelease(rls)
+	if err != nil {
+		return "", err
+	}
+	suffix := make([]byte, 8)
+	if _, err := rand.Read(suffix); err != nil {
+		return "", err
+	}
+	payload := fmt.Sprintf("%sreleases/%s/%d-%s", objs.prefix, key, time.Now().UnixNano(), hex.EncodeToString(suffix))
+	if _, err := objs.store.Put(payload, []byte(s), ""); err != nil {
+		return "", err
+	}
+	return payload, nil
+}
+
+// readRelease reads the release named by key from the payload that entry,
+// from an index read earlier, refers to. Writers delete a payload once the
+// index no longer refers to it, so if the payload is gone the latest index
+// tells where the release is now. It returns a nil release if the release
+// was deleted in the meantime.
+func (objs *Objects) readRelease(key string, entry objectEntry) (*rspb.Release, error) {
+	for attempt := 0; attempt < maxIndexAttempts; attempt++ {
+		rls, err := objs.readPayload(entry.Payload)
+		if err != ErrObjectNotFound {
+			return rls, err
+		}
+		index, _, err := objs.readIndex()
+		if err != nil {
+			return nil, err
+		}
+		latest, ok := index[key]
+		if !ok {
+			return nil, nil
+		}
+		if latest.Payload == entry.Payload {
+			return nil, fmt.Errorf("reading %s: %s", entry.Payload, ErrObjectNotFound)
+		}
+		entry = latest
+	}
+	return nil, indexConflictError{key: objs.indexKey()}
+}
+
+// readPayload reads the release in payload. It returns ErrObjectNotFound as
+// is if the payload does not exist.
+func (objs *Objects) readPayload(payload string) (*rspb.Release, error) {
+	data, _, err := objs.store.Get(payload)
+	if err == ErrObjectNotFound {
+		return nil, err
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading %s: %s", payload, err)
+	}
+	return decodeRelease(string(data))
+}
+
+// deletePayload deletes a payload that the index no longer refers to. A
+// payload that is left behind takes up space, but is never read.
+func (objs *Objects) deletePayload(payload string) {
+	if err := objs.store.Delete(payload); err != nil && err != ErrObjectNotFound {
+		objs.Log("failed to delete %s: %s", payload, err)
+	}
+}
+
+// objectLabels returns lbs with the labels that identify rls.
+func objectLabels(rls *rspb.Release, lbs labels) map[string]string {
+	lbs.set("NAME", rls.Name)
+	lbs.set("OWNER", "TILLER")
+	lbs.set("STATUS", rspb.Status_Code_name[int32(rls.Info.Status.Code)])
+	lbs.set("VERSION", strconv.Itoa(int(rls.Version)))
+	return lbs.toMap()
+}
@@ -0,0 +1,322 @@
+/*
+Copyright 2017 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package driver // import "k8s.io/helm/pkg/storage/driver"
+
+import (
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	rspb "k8s.io/helm/pkg/proto/hapi/release"
+)
+
+// fakeObjectStore is an ObjectStore in memory. beforeGet and beforePut, if
+// set, are called before each read and write, e.g. to simulate a concurrent
+// writer.
+type fakeObjectStore struct {
+	sync.Mutex
+	objects     map[string][]byte
+	generations map[string]string
+	next        int
+	beforeGet   func(key string)
+	beforePut   func(key string)
+}
+
+func newFakeObjectStore() *fakeObjectStore {
+	return &fakeObjectStore{objects: map[string][]byte{}, generations: map[string]string{}}
+}
+
+func (f *fakeObjectStore) Get(key string) ([]byte, string, error) {
+	if f.beforeGet != nil {
+		f.beforeGet(key)
+	}
+	f.Lock()
+	defer f.Unlock()
+	data, ok := f.objects[key]
+	if !ok {
+		return nil, "", ErrObjectNotFound
+	}
+	return data, f.generations[key], nil
+}
+
+func (f *fakeObjectStore) Put(key string, data []byte, match string) (string, error) {
+	if f.beforePut != nil {
+		f.beforePut(key)
+	}
+	f.Lock()
+	defer f.Unlock()
+	if f.generations[key] != match {
+		return "", ErrPreconditionFailed
+	}
+	f.next++
+	f.objects[key] = data
+	f.generations[key] = strconv.Itoa(f.next)
+	return f.generations[key], nil
+}
+
+func (f *fakeObjectStore) Delete(key string) error {
+	f.Lock()
+	defer f.Unlock()
+	if _, ok := f.objects[key]; !ok {
+		return ErrObjectNotFound
+	}
+	delete(f.objects, key)
+	delete(f.generations, key)
+	return nil
+}
+
+// payloads returns the keys of the stored payloads.
+func (f *fakeObjectStore) payloads() []string {
+	f.Lock()
+	defer f.Unlock()
+	var keys []string
+	for key := range f.objects {
+		if strings.HasPrefix(key, "tiller/releases/") {
+			keys = append(keys, key)
+		}
+	}
+	return keys
+}
+
+func newTestFixtureObjects(t *testing.T, releases ...*rspb.Release) (*Objects, *fakeObjectStore) {
+	store := newFakeObjectStore()
+	objs := NewObjects(store, "tiller/")
+	for _, rls := range releases {
+		if err := objs.Create(testKey(rls.Name, rls.Version), rls); err != nil {
+			t.Fatalf("Failed to create release: %s", err)
+		}
+	}
+	return objs, store
+}
+
+func TestObjectsName(t *testing.T) {
+	objs, _ := newTestFixtureObjects(t)
+	if objs.Name() != ObjectsDriverName {
+		t.Errorf("Expected name to be %q, got %q", ObjectsDriverName, objs.Name())
+	}
+}
+
+func TestObjectsGet(t *testing.T) {
+	rel := releaseStub("smug-pigeon", 1, "default", rspb.Status_DEPLOYED)
+	objs, _ := newTestFixtureObjects(t, rel)
+
+	got, err := objs.Get(testKey("smug-pigeon", 1))
+	if err != nil {
+		t.Fatalf("Failed to get release: %s", err)
+	}
+	if !reflect.DeepEqual(rel, got) {
+		t.Errorf("Expected {%q}, got {%q}", rel, got)
+	}
+
+	if _, err := objs.Get(testKey("smug-pigeon", 2)); err == nil || !strings.Contains(err.Error(), "not found") {
+		t.Errorf("Expected ErrReleaseNotFound, got %v", err)
+	}
+}
+
+func TestObjectsListAndQuery(t *testing.T) {
+	objs, _ := newTestFixtureObjects(t,
+		releaseStub("key-1", 1, "default", rspb.Status_DELETED),
+		releaseStub("key-2", 1, "default", rspb.Status_DEPLOYED),
+		releaseStub("key-2", 2, "default", rspb.Status_DEPLOYED),
+		releaseStub("key-3", 1, "default", rspb.Status_SUPERSEDED),
+	)
+
+	dpl, err := objs.List(func(rel *rspb.Release) bool {
+		return rel.Info.Status.Code == rspb.Status_DEPLOYED
+	})
+	if err != nil {
+		t.Fatalf("Failed to list deployed: %s", err)
+	}
+	if len(dpl) != 2 {
+		t.Errorf("Expected 2 deployed, got %d", len(dpl))
+	}
+
+	rls, err := objs.Query(map[string]string{"NAME": "key-2", "STATUS": "DEPLOYED", "OWNER": "TILLER"})
+	if err != nil {
+		t.Fatalf("Failed to query: %s", err)
+	}
+	if len(rls) != 2 {
+		t.Errorf("Expected 2 releases, got %d", len(rls))
+	}
+
+	if _, err := objs.Query(map[string]string{"NAME": "key-4"}); err == nil {
+		t.Error("Expected ErrReleaseNotFound for a query without results")
+	}
+}
+
+func TestObjectsCreateExists(t *testing.T) {
+	rel := releaseStub("smug-pigeon", 1, "default", rspb.Status_DEPLOYED)
+	objs, store := newTestFixtureObjects(t, rel)
+
+	err := objs.Create(testKey("smug-pigeon", 1), rel)
+	if err == nil || !strings.Contains(err.Error(), "already exists") {
+		t.Errorf("Expected ErrReleaseExists, got %v", err)
+	}
+	// The payload written for the failed create is removed again.
+	if p := store.payloads(); len(p) != 1 {
+		t.Errorf("Expected 1 payload, got %v", p)
+	}
+}
+
+func TestObjectsUpdate(t *testing.T) {
+	rel := releaseStub("smug-pigeon", 1, "default", rspb.Status_DEPLOYED)
+	objs, store := newTestFixtureObjects(t, rel)
+	before := store.payloads()
+
+	rel.Info.Status.Code = rspb.Status_SUPERSEDED
+	if err := objs.Update(testKey("smug-pigeon", 1), rel); err != nil {
+		t.Fatalf("Failed to update release: %s", err)
+	}
+
+	got, err := objs.Get(testKey("smug-pigeon", 1))
+	if err != nil {
+		t.Fatalf("Failed to get release: %s", err)
+	}
+	if got.Info.Status.Code != rspb.Status_SUPERSEDED {
+		t.Errorf("Expected status SUPERSEDED, got %s", got.Info.Status.Code)
+	}
+	if _, err := objs.Query(map[string]string{"NAME": "smug-pigeon", "STATUS": "SUPERSEDED"}); err != nil {
+		t.Errorf("Expected the index labels to be updated: %s", err)
+	}
+
+	// Payloads are never overwritten: the update wrote a new one and removed
+	// the old one.
+	after := store.payloads()
+	if len(after) != 1 || after[0] == before[0] {
+		t.Errorf("Expected a single new payload, got %v (was %v)", after, before)
+	}
+
+	missing := releaseStub("other", 1, "default", rspb.Status_DEPLOYED)
+	if err := objs.Update(testKey("other", 1), missing); err == nil {
+		t.Error("Expected updating a missing release to fail")
+	}
+}
+
+func TestObjectsReadDuringUpdate(t *testing.T) {
+	rel := releaseStub("smug-pigeon", 1, "default", rspb.Status_DEPLOYED)
+	objs, store := newTestFixtureObjects(t, rel)
+	other := NewObjects(store, "tiller/")
+
+	// Another writer updates the release after the reader read the index,
+	// and deletes the payload the reader was about to read.
+	updated := false
+	store.beforeGet = func(key string) {
+		if !strings.HasPrefix(key, "tiller/releases/") || updated {
+			return
+		}
+		updated = true
+		changed := releaseStub("smug-pigeon", 1, "default", rspb.Status_SUPERSEDED)
+		if err := other.Update(testKey("smug-pigeon", 1), changed); err != nil {
+			t.Fatalf("Failed to update release: %s", err)
+		}
+	}
+	got, err := objs.Get(testKey("smug-pigeon", 1))
+	if err != nil {
+		t.Fatalf("Failed to get release: %s", err)
+	}
+	if got.Info.Status.Code != rspb.Status_SUPERSEDED {
+		t.Errorf("Expected the updated release, got status %s", got.Info.Status.Code)
+	}
+}
+
+func TestObjectsListUnreadable(t *testing.T) {
+	objs, store := newTestFixtureObjects(t, releaseStub("smug-pigeon", 1, "default", rspb.Status_DEPLOYED))
+	for _, payload := range store.payloads() {
+		store.objects[payload] = []byte("not a release")
+	}
+
+	if _, err := objs.List(func(*rspb.Release) bool { return true }); err == nil {
+		t.Error("Expected listing an unreadable release to fail")
+	}
+	if _, err := objs.Query(map[string]string{"NAME": "smug-pigeon"}); err == nil {
+		t.Error("Expected querying an unreadable release to fail")
+	}
+}
+
+func TestObjectsDelete(t *testing.T) {
+	rel := releaseStub("smug-pigeon", 1, "default", rspb.Status_DEPLOYED)
+	objs, store := newTestFixtureObjects(t, rel)
+
+	got, err := objs.Delete(testKey("smug-pigeon", 1))
+	if err != nil {
+		t.Fatalf("Failed to delete release: %s", err)
+	}
+	if !reflect.DeepEqual(rel, got) {
+		t.Errorf("Expected {%q}, got {%q}", rel, got)
+	}
+	if _, err := objs.Get(testKey("smug-pigeon", 1)); err == nil {
+		t.Error("Expected the release to be gone")
+	}
+	if p := store.payloads(); len(p) != 0 {
+		t.Errorf("Expected no payloads, got %v", p)
+	}
+	if _, err := objs.Delete(testKey("smug-pigeon", 1)); err == nil {
+		t.Error("Expected deleting a missing release to fail")
+	}
+}
+
+func TestObjectsConcurrentIndexWrite(t *testing.T) {
+	objs, store := newTestFixtureObjects(t)
+	other := NewObjects(store, "tiller/")
+
+	// Another writer changes the index between the read and the write of
+	// the first attempt, so the write must be retried.
+	interfered := false
+	store.beforePut = func(key string) {
+		if key != "tiller/index.json" || interfered {
+			return
+		}
+		interfered = true
+		if err := other.Create(testKey("rls-b", 1), releaseStub("rls-b", 1, "default", rspb.Status_DEPLOYED)); err != nil {
+			t.Fatalf("Failed to create concurrent release: %s", err)
+		}
+	}
+	if err := objs.Create(testKey("rls-a", 1), releaseStub("rls-a", 1, "default", rspb.Status_DEPLOYED)); err != nil {
+		t.Fatalf("Failed to create release: %s", err)
+	}
+
+	for _, key := range []string{testKey("rls-a", 1), testKey("rls-b", 1)} {
+		if _, err := objs.Get(key); err != nil {
+			t.Errorf("Expected %s to be stored: %s", key, err)
+		}
+	}
+}
+
+func TestObjectsIndexContention(t *testing.T) {
+	objs, store := newTestFixtureObjects(t)
+
+	// The index changes before every write.
+	store.beforePut = func(key string) {
+		if key == "tiller/index.json" {
+			store.Lock()
+			store.generations[key] += "x"
+			store.Unlock()
+		}
+	}
+	err := objs.Create(testKey("rls-a", 1), releaseStub("rls-a", 1, "default", rspb.Status_DEPLOYED))
+	if err == nil || !strings.Contains(err.Error(), "changed concurrently") {
+		t.Errorf("Expected the create to give up, got %v", err)
+	}
+	if !objs.IsConflict(err) {
+		t.Errorf("Expected %v to be a conflict", err)
+	}
+	if p := store.payloads(); len(p) != 0 {
+		t.Errorf("Expected no payloads, got %v", p)
+	}
+}
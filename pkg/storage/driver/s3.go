@@ -0,0 +1,269 @@
+/*
+Copyright 2017 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package driver // import "k8s.io/helm/pkg/storage/driver"
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+)
+
+var _ ObjectStore = (*S3Store)(nil)
+
+// s3Timeout limits how long a single request to S3 may take.
+const s3Timeout = 30 * time.Second
+
+// S3Config locates an S3 bucket and holds the credentials to access it.
+type S3Config struct {
+	// Bucket is the name of the bucket.
+	Bucket string
+	// Region is the region of the bucket, e.g. "eu-west-1".
+	Region string
+	// Endpoint is the URL of a store with the S3 API other than Amazon S3,
+	// such as MinIO, e.g. "https://minio.example.com:9000". The bucket is
+	// then addressed by path. When it is empty, the bucket is addressed by
+	// virtual host on Amazon S3.
+	Endpoint string
+	// AccessKeyID, SecretAccessKey and, for temporary credentials,
+	// SessionToken sign the requests.
+	AccessKeyID     string
+	SecretAccessKey string
+	SessionToken    string
+}
+
+// S3Store is an ObjectStore in an S3 bucket. The generation of an object is
+// its ETag.
+//
+// Conditional writes use the If-None-Match and If-Match headers of PUT
+// requests, so the store must support them, as Amazon S3 does.
+type S3Store struct {
+	config S3Config
+	base   *url.URL
+	client *http.Client
+	now    func() time.Time
+}
+
+// NewS3Store returns an ObjectStore for the bucket of c.
+func NewS3Store(c S3Config) (*S3Store, error) {
+	if c.Bucket == "" {
+		return nil, errors.New("no S3 bucket given")
+	}
+	if c.Region == "" {
+		return nil, errors.New("no S3 region given")
+	}
+	if c.AccessKeyID == "" || c.SecretAccessKey == "" {
+		return nil, errors.New("no S3 credentials given")
+	}
+
+	var base *url.URL
+	if c.Endpoint == "" {
+		base = &url.URL{Scheme: "https", Host: fmt.Sprintf("%s.s3.%s.amazonaws.com", c.Bucket, c.Region), Path: "/"}
+	} else {
+		u, err := url.Parse(c.Endpoint)
+		if err != nil || u.Host == "" {
+			return nil, fmt.Errorf("invalid S3 endpoint %q", c.Endpoint)
+		}
+		base = &url.URL{Scheme: u.Scheme, Host: u.Host, Path: "/" + c.Bucket + "/"}
+	}
+	return &S3Store{
+		config: c,
+		base:   base,
+		client: &http.Client{Timeout: s3Timeout},
+		now:    time.Now,
+	}, nil
+}
+
+// Get implements ObjectStore.
+func (s *S3Store) Get(key string) ([]byte, string, error) {
+	resp, err := s.do("GET", key, nil, nil)
+	if err != nil {
+		return nil, "", err
+	}
+	defer resp.Body.Close()
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusNotFound:
+		return nil, "", ErrObjectNotFound
+	default:
+		return nil, "", s3Error("GET", key, resp)
+	}
+	data, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, "", err
+	}
+	return data, resp.Header.Get("ETag"), nil
+}
+
+// Put implements ObjectStore.
+func (s *S3Store) Put(key string, data []byte, match string) (string, error) {
+	header := http.Header{"Content-Type": {"application/octet-stream"}}
+	if match == "" {
+		header.Set("If-None-Match", "*")
+	} else {
+		header.Set("If-Match", match)
+	}
+	resp, err := s.do("PUT", key, data, header)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return resp.Header.Get("ETag"), nil
+	case http.StatusPreconditionFailed, http.StatusConflict:
+		// S3 answers 409 to a conditional write that raced with another
+		// write of the object.
+		return "", ErrPreconditionFailed
+	}
+	return "", s3Error("PUT", key, resp)
+}
+
+// Delete implements ObjectStore. S3 reports no error for a missing object,
+// so ErrObjectNotFound is only returned by stores that do.
+func (s *S3Store) Delete(key string) error {
+	resp, err := s.do("DELETE", key, nil, nil)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	switch resp.StatusCode {
+	case http.StatusOK, http.StatusNoContent:
+		return nil
+	case http.StatusNotFound:
+		return ErrObjectNotFound
+	}
+	return s3Error("DELETE", key, resp)
+}
+
+// do sends a signed request for the object named key.
+func (s *S3Store) do(method, key string, body []byte, header http.Header) (*http.Response, error) {
+	u := *s.base
+	u.Path += key
+	u.RawPath = s3Escape(u.Path, false)
+
+	req, err := http.NewRequest(method, u.String(), bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	for k, v := range header {
+		req.Header[k] = v
+	}
+	sum := sha256.Sum256(body)
+	s.sign(req, hex.EncodeToString(sum[:]), s.now().UTC())
+	return s.client.Do(req)
+}
+
+// sign adds an AWS Signature Version 4 to req, whose body hashes to
+// payloadHash, covering the host and all headers of req.
+func (s *S3Store) sign(req *http.Request, payloadHash string, t time.Time) {
+	amzDate := t.Format("20060102T150405Z")
+	date := t.Format("20060102")
+	req.Header.Set("X-Amz-Date", amzDate)
+	req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+	if s.config.SessionToken != "" {
+		req.Header.Set("X-Amz-Security-Token", s.config.SessionToken)
+	}
+
+	headers := map[string]string{"host": req.URL.Host}
+	for k, v := range req.Header {
+		headers[strings.ToLower(k)] = strings.TrimSpace(strings.Join(v, ","))
+	}
+	names := make([]string, 0, len(headers))
+	for k := range headers {
+		names = append(names, k)
+	}
+	sort.Strings(names)
+	var canonicalHeaders bytes.Buffer
+	for _, k := range names {
+		fmt.Fprintf(&canonicalHeaders, "%s:%s\n", k, headers[k])
+	}
+	signedHeaders := strings.Join(names, ";")
+
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		s3Escape(req.URL.Path, false),
+		s3Query(req.URL.Query()),
+		canonicalHeaders.String(),
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+	scope := date + "/" + s.config.Region + "/s3/aws4_request"
+	sum := sha256.Sum256([]byte(canonicalRequest))
+	stringToSign := "AWS4-HMAC-SHA256\n" + amzDate + "\n" + scope + "\n" + hex.EncodeToString(sum[:])
+
+	key := hmacSHA256([]byte("AWS4"+s.config.SecretAccessKey), date)
+	key = hmacSHA256(key, s.config.Region)
+	key = hmacSHA256(key, "s3")
+	key = hmacSHA256(key, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf("AWS4-HMAC-SHA256 Credential=%s/%s,SignedHeaders=%s,Signature=%s",
+		s.config.AccessKeyID, scope, signedHeaders, signature))
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	h := hmac.New(sha256.New, key)
+	h.Write([]byte(data))
+	return h.Sum(nil)
+}
+
+// s3Escape percent-encodes every byte of s but the unreserved characters
+// of RFC 3986 and, unless escapeSlash is set, '/'.
+func s3Escape(s string, escapeSlash bool) string {
+	var b bytes.Buffer
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case 'A' <= c && c <= 'Z', 'a' <= c && c <= 'z', '0' <= c && c <= '9',
+			c == '-', c == '_', c == '.', c == '~', c == '/' && !escapeSlash:
+			b.WriteByte(c)
+		default:
+			fmt.Fprintf(&b, "%%%02X", c)
+		}
+	}
+	return b.String()
+}
+
+// s3Query returns the canonical form of a query string, with the
+// parameters sorted by name.
+func s3Query(q url.Values) string {
+	var params []string
+	for k, vs := range q {
+		for _, v := range vs {
+			params = append(params, s3Escape(k, true)+"="+s3Escape(v, true))
+		}
+	}
+	sort.Strings(params)
+	return strings.Join(params, "&")
+}
+
+// s3Error describes a failed request, with the start of the error document
+// that S3 returned.
+func s3Error(method, key string, resp *http.Response) error {
+	msg, _ := ioutil.ReadAll(io.LimitReader(resp.Body, 512))
+	return fmt.Errorf("S3 %s %s: %s: %s", method, key, resp.Status, strings.TrimSpace(string(msg)))
+}
@@ -18,7 +18,11 @@ package storage // import "k8s.io/helm/pkg/storage"
 
 import (
 	"fmt"
+	"math/rand"
 	"sync"
+	"time"
+
+	"github.com/golang/protobuf/proto"
 
 	rspb "k8s.io/helm/pkg/proto/hapi/release"
 	relutil "k8s.io/helm/pkg/releaseutil"
@@ -29,14 +33,27 @@ import (
 type Storage struct {
 	driver.Driver
 
-	// releaseLocks are for locking releases to make sure that only one operation at a time is executed on each release
-	releaseLocks map[string]*sync.Mutex
+	// releaseLocks are for locking releases to make sure that only one operation at a time is executed on each release.
+	// Each lock is a channel with a buffer of one; the lock is held while the buffer is full.
+	releaseLocks map[string]chan struct{}
 	// releaseLocksLock is a mutex for accessing releaseLocks
 	releaseLocksLock *sync.Mutex
 
+	// WriteAttempts is how often Create and Update write a release whose
+	// write conflicts with another writer, as detected by the driver. The
+	// write is not retried if it is below two.
+	WriteAttempts int
+
 	Log func(string, ...interface{})
 }
 
+// DefaultWriteAttempts is the WriteAttempts of a new Storage.
+const DefaultWriteAttempts = 5
+
+// WriteRetryInterval is the upper bound of the randomized wait before a
+// write is retried after a conflict.
+var WriteRetryInterval = 50 * time.Millisecond
+
 // Get retrieves the release from storage. An error is returned
 // if the storage driver failed to fetch the release, or the
 // release identified by the key, version pair does not exist.
@@ -49,16 +66,74 @@ func (s *Storage) Get(name string, version int32) (*rspb.Release, error) {
 // error is returned if the storage driver failed to store the
 // release, or a release with identical an key already exists.
 func (s *Storage) Create(rls *rspb.Release) error {
-	s.Log("Creating release %q", makeKey(rls.Name, rls.Version))
-	return s.Driver.Create(makeKey(rls.Name, rls.Version), rls)
+	key := makeKey(rls.Name, rls.Version)
+	s.Log("Creating release %q", key)
+	return s.write(key, func() error {
+		return s.Driver.Create(key, rls)
+	}, func(conflict error) error {
+		// The write that conflicted may have been another create of the
+		// same release.
+		_, err := s.Driver.Get(key)
+		switch {
+		case err == nil:
+			return driver.ErrReleaseExists(key)
+		case err.Error() == driver.ErrReleaseNotFound(key).Error():
+			return nil
+		default:
+			return err
+		}
+	})
 }
 
 // Update update the release in storage. An error is returned if the
 // storage backend fails to update the release or if the release
 // does not exist.
 func (s *Storage) Update(rls *rspb.Release) error {
-	s.Log("Updating release %q", makeKey(rls.Name, rls.Version))
-	return s.Driver.Update(makeKey(rls.Name, rls.Version), rls)
+	key := makeKey(rls.Name, rls.Version)
+	s.Log("Updating release %q", key)
+	// The update is only retried as long as the record is as it was before
+	// the first attempt: a conflict with a writer that changed the record
+	// itself fails the update, rather than overwriting that change.
+	current, version, err := driver.GetVersion(s.Driver, key)
+	if err != nil {
+		return err
+	}
+	return s.write(key, func() error {
+		return driver.UpdateVersion(s.Driver, key, rls, version)
+	}, func(conflict error) error {
+		latest, latestVersion, err := driver.GetVersion(s.Driver, key)
+		if err != nil {
+			return err
+		}
+		if latestVersion != version || !proto.Equal(latest, current) {
+			return fmt.Errorf("release %q was changed by another writer while it was updated: %s", key, conflict)
+		}
+		return nil
+	})
+}
+
+// write calls fn, and calls it again while it fails with a write conflict,
+// up to WriteAttempts times in all.
+//
+// Before each retry, recheck is given the conflict and reads the record of
+// the release again, to find out whether the conflicting writer changed it.
+// An error from recheck ends the retries and is returned, so that a retry is
+// only made if the conflict was over an object that other records share.
+func (s *Storage) write(key string, fn func() error, recheck func(conflict error) error) error {
+	err := fn()
+	for attempt := 1; attempt < s.WriteAttempts && driver.IsConflict(s.Driver, err); attempt++ {
+		var wait time.Duration
+		if WriteRetryInterval > 0 {
+			wait = time.Duration(rand.Int63n(int64(WriteRetryInterval)) + 1)
+		}
+		s.Log("Writing release %q conflicted with another writer, retrying in %s: %s", key, wait, err)
+		time.Sleep(wait)
+		if rerr := recheck(err); rerr != nil {
+			return rerr
+		}
+		err = fn()
+	}
+	return err
 }
 
 // Delete deletes the release from storage. An error is returned if
@@ -114,6 +189,33 @@ func (s *Storage) ListFilterAny(fns ...relutil.FilterFunc) ([]*rspb.Release, err
 	})
 }
 
+// ListByStatus returns the releases, in all namespaces, whose status is one of
+// codes. Releases are selected by the STATUS label of their records, so that
+// drivers backed by the Kubernetes API only fetch and decode the releases
+// that match. An error is returned if the storage backend fails to retrieve
+// the releases.
+func (s *Storage) ListByStatus(codes ...rspb.Status_Code) ([]*rspb.Release, error) {
+	s.Log("Listing releases with status %v", codes)
+	notFound := driver.ErrReleaseNotFound("").Error()
+	seen := map[rspb.Status_Code]bool{}
+	var rels []*rspb.Release
+	for _, code := range codes {
+		if seen[code] {
+			continue
+		}
+		seen[code] = true
+		ls, err := s.Driver.Query(map[string]string{
+			"OWNER":  "TILLER",
+			"STATUS": code.String(),
+		})
+		if err != nil && err.Error() != notFound {
+			return nil, err
+		}
+		rels = append(rels, ls...)
+	}
+	return rels, nil
+}
+
 // Deployed returns the deployed release with the provided release name, or
 // returns ErrReleaseNotFound if not found.
 func (s *Storage) Deployed(name string) (*rspb.Release, error) {
@@ -157,35 +259,96 @@ func (s *Storage) Last(name string) (*rspb.Release, error) {
 	return h[0], nil
 }
 
-// LockRelease gains a mutually exclusive access to a release via a mutex.
+// LockRetryInterval is the upper bound of the first randomized wait between
+// attempts made by LockReleaseWithJitter. The bound doubles after each failed
+// attempt up to LockRetryMaxInterval.
+var LockRetryInterval = 100 * time.Millisecond
+
+// LockRetryMaxInterval is the largest bound used for the randomized wait
+// between attempts made by LockReleaseWithJitter.
+var LockRetryMaxInterval = 2 * time.Second
+
+// LockRelease gains a mutually exclusive access to a release, blocking until
+// the lock is available.
 func (s *Storage) LockRelease(name string) error {
-	s.releaseLocksLock.Lock()
-	defer s.releaseLocksLock.Unlock()
+	lock, err := s.releaseLock(name)
+	if err != nil {
+		return err
+	}
+	lock <- struct{}{}
+	locksHeld.Inc()
+	return nil
+}
 
-	var lock *sync.Mutex
-	lock, exists := s.releaseLocks[name]
+// LockReleaseWithJitter gains a mutually exclusive access to a release like
+// LockRelease, but instead of queueing on the lock it polls for it, sleeping a
+// random duration between attempts. Spreading the attempts out keeps many
+// clients retrying against the same release from waking up at the same time.
+//
+// An error is returned if the lock cannot be acquired within timeout.
+func (s *Storage) LockReleaseWithJitter(name string, timeout time.Duration) error {
+	lock, err := s.releaseLock(name)
+	if err != nil {
+		return err
+	}
 
-	if !exists {
-		releases, err := s.ListReleases()
-		if err != nil {
-			return err
+	deadline := time.Now().Add(timeout)
+	backoff := LockRetryInterval
+	for {
+		select {
+		case lock <- struct{}{}:
+			locksHeld.Inc()
+			return nil
+		default:
 		}
 
-		found := false
-		for _, release := range releases {
-			if release.Name == name {
-				found = true
-			}
+		remaining := deadline.Sub(time.Now())
+		if remaining <= 0 {
+			return fmt.Errorf("Unable to lock release %q: timed out after %s", name, timeout)
 		}
-		if !found {
-			return fmt.Errorf("Unable to lock release %q: release not found", name)
+
+		wait := time.Duration(rand.Int63n(int64(backoff)) + 1)
+		if wait > remaining {
+			wait = remaining
 		}
+		s.Log("Release %q is locked, retrying in %s", name, wait)
+		time.Sleep(wait)
 
-		lock = &sync.Mutex{}
-		s.releaseLocks[name] = lock
+		if backoff *= 2; backoff > LockRetryMaxInterval {
+			backoff = LockRetryMaxInterval
+		}
 	}
-	lock.Lock()
-	return nil
+}
+
+// releaseLock returns the lock for the named release, creating it if the
+// release exists but has not been locked before.
+func (s *Storage) releaseLock(name string) (chan struct{}, error) {
+	s.releaseLocksLock.Lock()
+	defer s.releaseLocksLock.Unlock()
+
+	lock, exists := s.releaseLocks[name]
+	if exists {
+		return lock, nil
+	}
+
+	releases, err := s.ListReleases()
+	if err != nil {
+		return nil, err
+	}
+
+	found := false
+	for _, release := range releases {
+		if release.Name == name {
+			found = true
+		}
+	}
+	if !found {
+		return nil, fmt.Errorf("Unable to lock release %q: release not found", name)
+	}
+
+	lock = make(chan struct{}, 1)
+	s.releaseLocks[name] = lock
+	return lock, nil
 }
 
 // UnlockRelease releases a mutually exclusive access to a release.
@@ -194,12 +357,39 @@ func (s *Storage) UnlockRelease(name string) {
 	s.releaseLocksLock.Lock()
 	defer s.releaseLocksLock.Unlock()
 
-	var lock *sync.Mutex
 	lock, exists := s.releaseLocks[name]
 	if !exists {
 		return
 	}
-	lock.Unlock()
+	select {
+	case <-lock:
+		locksHeld.Dec()
+	default:
+	}
+}
+
+// ForceUnlockRelease clears the lock on a release, whoever holds it, and
+// reports whether the release was locked.
+//
+// It is meant for recovering releases whose lock will never be released. If
+// the holder is still running, it will release the lock when it finishes,
+// which may be while another operation holds it.
+func (s *Storage) ForceUnlockRelease(name string) bool {
+	s.releaseLocksLock.Lock()
+	defer s.releaseLocksLock.Unlock()
+
+	lock, exists := s.releaseLocks[name]
+	if !exists {
+		return false
+	}
+	select {
+	case <-lock:
+		locksHeld.Dec()
+		s.Log("Forcibly unlocked release %q", name)
+		return true
+	default:
+		return false
+	}
 }
 
 // makeKey concatenates a release name and version into
@@ -218,8 +408,9 @@ func Init(d driver.Driver) *Storage {
 	}
 	return &Storage{
 		Driver:           d,
-		releaseLocks:     make(map[string]*sync.Mutex),
+		releaseLocks:     make(map[string]chan struct{}),
 		releaseLocksLock: &sync.Mutex{},
+		WriteAttempts:    DefaultWriteAttempts,
 		Log:              func(_ string, _ ...interface{}) {},
 	}
 }
@@ -17,9 +17,16 @@ limitations under the License.
 package storage // import "k8s.io/helm/pkg/storage"
 
 import (
+	"errors"
 	"fmt"
 	"reflect"
+	"sort"
+	"strconv"
+	"strings"
 	"testing"
+	"time"
+
+	dto "github.com/prometheus/client_model/go"
 
 	rspb "k8s.io/helm/pkg/proto/hapi/release"
 	"k8s.io/helm/pkg/storage/driver"
@@ -74,6 +81,206 @@ func TestStorageUpdate(t *testing.T) {
 	}
 }
 
+var errWriteConflict = errors.New("the object has been modified")
+
+// conflictingDriver fails the next conflicts writes with a write conflict,
+// as if another writer changed a shared object at the same time. That
+// writer is simulated by calling other before each failed write.
+type conflictingDriver struct {
+	driver.Driver
+	conflicts int
+	other     func()
+	writes    int
+}
+
+func (d *conflictingDriver) conflict() error {
+	d.writes++
+	if d.conflicts == 0 {
+		return nil
+	}
+	d.conflicts--
+	if d.other != nil {
+		d.other()
+	}
+	return errWriteConflict
+}
+
+func (d *conflictingDriver) Create(key string, rls *rspb.Release) error {
+	if err := d.conflict(); err != nil {
+		return err
+	}
+	return d.Driver.Create(key, rls)
+}
+
+func (d *conflictingDriver) Update(key string, rls *rspb.Release) error {
+	if err := d.conflict(); err != nil {
+		return err
+	}
+	return d.Driver.Update(key, rls)
+}
+
+func (d *conflictingDriver) IsConflict(err error) bool {
+	return err == errWriteConflict
+}
+
+func TestStorageWriteConflicts(t *testing.T) {
+	defer func(interval time.Duration) { WriteRetryInterval = interval }(WriteRetryInterval)
+	WriteRetryInterval = time.Millisecond
+
+	mem := driver.NewMemory()
+	d := &conflictingDriver{Driver: mem, conflicts: 2}
+	d.other = func() {
+		// A release that shares the conflicting object with ours.
+		other := ReleaseTestData{Name: "other", Version: int32(d.writes), Status: rspb.Status_DEPLOYED}.ToRelease()
+		mem.Create(makeKey(other.Name, other.Version), other)
+	}
+	storage := Init(d)
+
+	rls := ReleaseTestData{Name: "angry-beaver", Version: 1, Status: rspb.Status_DEPLOYED}.ToRelease()
+	assertErrNil(t.Fatal, storage.Create(rls), "StoreRelease")
+	if d.writes != 3 {
+		t.Errorf("Expected the create to be attempted 3 times, got %d", d.writes)
+	}
+	_, err := storage.Get(rls.Name, rls.Version)
+	assertErrNil(t.Fatal, err, "QueryRelease")
+
+	d.conflicts, d.writes = 1, 0
+	rls.Info.Status.Code = rspb.Status_SUPERSEDED
+	assertErrNil(t.Fatal, storage.Update(rls), "UpdateRelease")
+	res, err := storage.Get(rls.Name, rls.Version)
+	assertErrNil(t.Fatal, err, "QueryRelease")
+	if res.Info.Status.Code != rspb.Status_SUPERSEDED || d.writes != 2 {
+		t.Errorf("Expected the update to be stored after 2 attempts, got %s after %d", res.Info.Status.Code, d.writes)
+	}
+
+	// Attempts are bounded.
+	storage.WriteAttempts = 3
+	d.conflicts, d.writes = 10, 0
+	if err := storage.Update(rls); err != errWriteConflict {
+		t.Errorf("Expected the conflict to be returned, got %v", err)
+	}
+	if d.writes != 3 {
+		t.Errorf("Expected 3 attempts, got %d", d.writes)
+	}
+
+	// A create that conflicted with a create of the same release is not
+	// retried.
+	dup := ReleaseTestData{Name: "dup", Version: 1}.ToRelease()
+	d.conflicts, d.writes = 1, 0
+	d.other = func() { mem.Create(makeKey(dup.Name, dup.Version), dup) }
+	if err := storage.Create(dup); err == nil || err.Error() != driver.ErrReleaseExists("dup.v1").Error() {
+		t.Errorf("Expected the release to exist, got %v", err)
+	}
+	if d.writes != 1 {
+		t.Errorf("Expected a single attempt, got %d", d.writes)
+	}
+}
+
+// versionedDriver keeps a version for each record, which every write
+// changes, and fails updates made against an older version.
+type versionedDriver struct {
+	*conflictingDriver
+	versions map[string]int
+	// updatedAt are the versions that updates were made against.
+	updatedAt []string
+}
+
+func (d *versionedDriver) GetVersion(key string) (*rspb.Release, string, error) {
+	rls, err := d.Get(key)
+	return rls, strconv.Itoa(d.versions[key]), err
+}
+
+func (d *versionedDriver) UpdateVersion(key string, rls *rspb.Release, version string) error {
+	d.updatedAt = append(d.updatedAt, version)
+	if version != strconv.Itoa(d.versions[key]) {
+		d.writes++
+		return errWriteConflict
+	}
+	if err := d.Update(key, rls); err != nil {
+		return err
+	}
+	d.versions[key]++
+	return nil
+}
+
+func TestStorageWriteConflictsRecheck(t *testing.T) {
+	defer func(interval time.Duration) { WriteRetryInterval = interval }(WriteRetryInterval)
+	WriteRetryInterval = time.Millisecond
+
+	mem := driver.NewMemory()
+	d := &versionedDriver{conflictingDriver: &conflictingDriver{Driver: mem}, versions: map[string]int{}}
+	storage := Init(d)
+
+	rls := ReleaseTestData{Name: "angry-beaver", Version: 1, Status: rspb.Status_DEPLOYED}.ToRelease()
+	key := makeKey(rls.Name, rls.Version)
+	assertErrNil(t.Fatal, storage.Create(rls), "StoreRelease")
+
+	// A conflict over a shared object is retried against the same version.
+	d.conflicts, d.writes = 1, 0
+	update := *rls
+	update.Info = &rspb.Info{Status: &rspb.Status{Code: rspb.Status_SUPERSEDED}}
+	assertErrNil(t.Fatal, storage.Update(&update), "UpdateRelease")
+	if expect := []string{"0", "0"}; !reflect.DeepEqual(d.updatedAt, expect) {
+		t.Errorf("Expected the update to be made against versions %v, got %v", expect, d.updatedAt)
+	}
+
+	// Another writer changes the record while ours conflicts.
+	d.other = func() {
+		changed := *rls
+		changed.Info = &rspb.Info{Status: &rspb.Status{Code: rspb.Status_FAILED}, Description: "changed by another writer"}
+		mem.Update(key, &changed)
+		d.versions[key]++
+	}
+	d.conflicts, d.writes, d.updatedAt = 2, 0, nil
+
+	update.Info = &rspb.Info{Status: &rspb.Status{Code: rspb.Status_DELETED}}
+	err := storage.Update(&update)
+	if err == nil || !strings.Contains(err.Error(), "was changed by another writer") {
+		t.Errorf("Expected the update to fail, got %v", err)
+	}
+	// The change of the other writer is kept.
+	if d.writes != 1 {
+		t.Errorf("Expected a single attempt, got %d", d.writes)
+	}
+	res, err := storage.Get(rls.Name, rls.Version)
+	assertErrNil(t.Fatal, err, "QueryRelease")
+	if res.Info.Description != "changed by another writer" {
+		t.Errorf("Expected the other writer's change to be kept, got %q", res.Info.Description)
+	}
+
+	// An update against a version that changed in the meantime conflicts.
+	if err := driver.UpdateVersion(d, key, &update, "1"); err != errWriteConflict {
+		t.Errorf("Expected a stale version to conflict, got %v", err)
+	}
+}
+
+func TestStorageCreateConflictRecheckFails(t *testing.T) {
+	defer func(interval time.Duration) { WriteRetryInterval = interval }(WriteRetryInterval)
+	WriteRetryInterval = time.Millisecond
+
+	d := &conflictingDriver{Driver: &failingGets{driver.NewMemory()}, conflicts: 1}
+	storage := Init(d)
+
+	rls := ReleaseTestData{Name: "angry-beaver", Version: 1}.ToRelease()
+	if err := storage.Create(rls); err != errGetFailed {
+		t.Errorf("Expected the error reading the release to be returned, got %v", err)
+	}
+	if d.writes != 1 {
+		t.Errorf("Expected a single attempt, got %d", d.writes)
+	}
+}
+
+var errGetFailed = errors.New("connection refused")
+
+// failingGets is a Driver that fails to read releases.
+type failingGets struct {
+	driver.Driver
+}
+
+func (f *failingGets) Get(key string) (*rspb.Release, error) {
+	return nil, errGetFailed
+}
+
 func TestStorageDelete(t *testing.T) {
 	// initialize storage
 	storage := Init(driver.NewMemory())
@@ -146,6 +353,57 @@ func TestStorageList(t *testing.T) {
 	}
 }
 
+// notFoundQueries makes Query fail for empty results, as the ConfigMaps
+// driver does.
+type notFoundQueries struct {
+	driver.Driver
+}
+
+func (d notFoundQueries) Query(labels map[string]string) ([]*rspb.Release, error) {
+	ls, err := d.Driver.Query(labels)
+	if err == nil && len(ls) == 0 {
+		return nil, driver.ErrReleaseNotFound(labels["NAME"])
+	}
+	return ls, err
+}
+
+func TestStorageListByStatus(t *testing.T) {
+	storage := Init(notFoundQueries{driver.NewMemory()})
+
+	rels := []*rspb.Release{
+		ReleaseTestData{Name: "happy-catdog", Namespace: "default", Status: rspb.Status_SUPERSEDED}.ToRelease(),
+		ReleaseTestData{Name: "hungry-hippo", Namespace: "zoo", Status: rspb.Status_DEPLOYED}.ToRelease(),
+		ReleaseTestData{Name: "angry-beaver", Namespace: "forest", Status: rspb.Status_DEPLOYED}.ToRelease(),
+		ReleaseTestData{Name: "opulent-frog", Namespace: "pond", Status: rspb.Status_FAILED}.ToRelease(),
+	}
+	for _, rls := range rels {
+		assertErrNil(t.Fatal, storage.Create(rls), "Storing release "+rls.Name)
+	}
+
+	var tests = []struct {
+		Description string
+		Codes       []rspb.Status_Code
+		Expected    []string
+	}{
+		{"deployed", []rspb.Status_Code{rspb.Status_DEPLOYED}, []string{"angry-beaver", "hungry-hippo"}},
+		{"deployed or failed", []rspb.Status_Code{rspb.Status_DEPLOYED, rspb.Status_FAILED}, []string{"angry-beaver", "hungry-hippo", "opulent-frog"}},
+		{"repeated status", []rspb.Status_Code{rspb.Status_FAILED, rspb.Status_FAILED}, []string{"opulent-frog"}},
+		{"no match", []rspb.Status_Code{rspb.Status_DELETED}, nil},
+	}
+	for _, tt := range tests {
+		list, err := storage.ListByStatus(tt.Codes...)
+		assertErrNil(t.Fatal, err, tt.Description)
+		var names []string
+		for _, rls := range list {
+			names = append(names, rls.Name)
+		}
+		sort.Strings(names)
+		if !reflect.DeepEqual(names, tt.Expected) {
+			t.Errorf("ListByStatus(%s): expected %v, got %v", tt.Description, tt.Expected, names)
+		}
+	}
+}
+
 func TestStorageDeployed(t *testing.T) {
 	storage := Init(driver.NewMemory())
 
@@ -300,3 +558,102 @@ func TestReleaseLocks(t *testing.T) {
 	}
 	s.UnlockRelease(releaseName)
 }
+
+func TestReleaseLocksWithJitter(t *testing.T) {
+	s := Init(driver.NewMemory())
+
+	releaseName := "angry-beaver"
+	rls := ReleaseTestData{
+		Name:    releaseName,
+		Version: 1,
+	}.ToRelease()
+
+	s.Create(rls)
+
+	if err := s.LockReleaseWithJitter(releaseName, time.Second); err != nil {
+		t.Fatalf("Expected nil err when locking an unlocked release, got %s", err)
+	}
+
+	// The lock is held, so a second attempt must give up after the timeout.
+	if err := s.LockReleaseWithJitter(releaseName, 50*time.Millisecond); err == nil {
+		t.Errorf("Expected error when locking a locked release")
+	}
+
+	// Once released, a waiting caller must acquire it.
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		s.UnlockRelease(releaseName)
+	}()
+	if err := s.LockReleaseWithJitter(releaseName, 5*time.Second); err != nil {
+		t.Errorf("Expected nil err after the lock was released, got %s", err)
+	}
+	s.UnlockRelease(releaseName)
+}
+
+func TestReleaseLocksWithJitterNotExist(t *testing.T) {
+	s := Init(driver.NewMemory())
+
+	if err := s.LockReleaseWithJitter("no-such-release", time.Second); err == nil {
+		t.Errorf("Expected error when trying to lock non-existing release, got nil")
+	}
+}
+
+func TestForceUnlockRelease(t *testing.T) {
+	s := Init(driver.NewMemory())
+
+	releaseName := "angry-beaver"
+	rls := ReleaseTestData{
+		Name:    releaseName,
+		Version: 1,
+	}.ToRelease()
+
+	s.Create(rls)
+
+	if s.ForceUnlockRelease(releaseName) {
+		t.Errorf("Expected a release that was never locked to report unlocked")
+	}
+	if err := s.LockRelease(releaseName); err != nil {
+		t.Fatalf("Expected nil err when locking an unlocked release, got %s", err)
+	}
+	if !s.ForceUnlockRelease(releaseName) {
+		t.Errorf("Expected a locked release to report locked")
+	}
+	if s.ForceUnlockRelease(releaseName) {
+		t.Errorf("Expected the release to be unlocked")
+	}
+	if err := s.LockReleaseWithJitter(releaseName, 50*time.Millisecond); err != nil {
+		t.Errorf("Expected nil err when locking a force-unlocked release, got %s", err)
+	}
+}
+
+func TestReleaseLocksHeldMetric(t *testing.T) {
+	s := Init(driver.NewMemory())
+	s.Create(ReleaseTestData{Name: "angry-beaver", Version: 1}.ToRelease())
+	s.Create(ReleaseTestData{Name: "sad-beaver", Version: 1}.ToRelease())
+
+	held := func() float64 {
+		m := &dto.Metric{}
+		if err := locksHeld.Write(m); err != nil {
+			t.Fatal(err)
+		}
+		return m.GetGauge().GetValue()
+	}
+	before := held()
+
+	if err := s.LockRelease("angry-beaver"); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.LockReleaseWithJitter("sad-beaver", time.Second); err != nil {
+		t.Fatal(err)
+	}
+	if got := held(); got != before+2 {
+		t.Errorf("Expected %v locks held, got %v", before+2, got)
+	}
+	s.UnlockRelease("angry-beaver")
+	s.ForceUnlockRelease("sad-beaver")
+	// Unlocking a release that is not locked does not change the count.
+	s.UnlockRelease("sad-beaver")
+	if got := held(); got != before {
+		t.Errorf("Expected %v locks held, got %v", before, got)
+	}
+}
@@ -45,7 +45,18 @@ func ToYAML(s string) (string, error) {
 func Parse(s string) (map[string]interface{}, error) {
 	vals := map[string]interface{}{}
 	scanner := bytes.NewBufferString(s)
-	t := newParser(scanner, vals)
+	t := newParser(scanner, vals, false)
+	err := t.parse()
+	return vals, err
+}
+
+// ParseString parses a set line, keeping every value a string.
+//
+// A set line is of the form name1=value1,name2=value2
+func ParseString(s string) (map[string]interface{}, error) {
+	vals := map[string]interface{}{}
+	scanner := bytes.NewBufferString(s)
+	t := newParser(scanner, vals, true)
 	err := t.parse()
 	return vals, err
 }
@@ -56,7 +67,19 @@ func Parse(s string) (map[string]interface{}, error) {
 // dest version.
 func ParseInto(s string, dest map[string]interface{}) error {
 	scanner := bytes.NewBufferString(s)
-	t := newParser(scanner, dest)
+	t := newParser(scanner, dest, false)
+	return t.parse()
+}
+
+// ParseIntoString parses a strvals line and merges the result into dest,
+// keeping every value a string, so that values such as "1.0", "007" or
+// "true" are not turned into numbers or booleans.
+//
+// If the strval string has a key that exists in dest, it overwrites the
+// dest version.
+func ParseIntoString(s string, dest map[string]interface{}) error {
+	scanner := bytes.NewBufferString(s)
+	t := newParser(scanner, dest, true)
 	return t.parse()
 }
 
@@ -65,10 +88,12 @@ func ParseInto(s string, dest map[string]interface{}) error {
 type parser struct {
 	sc   *bytes.Buffer
 	data map[string]interface{}
+	// st keeps the values strings instead of guessing their types.
+	st bool
 }
 
-func newParser(sc *bytes.Buffer, data map[string]interface{}) *parser {
-	return &parser{sc: sc, data: data}
+func newParser(sc *bytes.Buffer, data map[string]interface{}, stringsOnly bool) *parser {
+	return &parser{sc: sc, data: data, st: stringsOnly}
 }
 
 func (t *parser) parse() error {
@@ -116,7 +141,7 @@ func (t *parser) key(data map[string]interface{}) error {
 				return e
 			case ErrNotList:
 				v, e := t.val()
-				set(data, string(k), typedVal(v))
+				set(data, string(k), typedVal(v, t.st))
 				return e
 			default:
 				return e
@@ -183,10 +208,10 @@ func (t *parser) valList() ([]interface{}, error) {
 			if r, _, e := t.sc.ReadRune(); e == nil && r != ',' {
 				t.sc.UnreadRune()
 			}
-			list = append(list, typedVal(v))
+			list = append(list, typedVal(v, t.st))
 			return list, nil
 		case last == ',':
-			list = append(list, typedVal(v))
+			list = append(list, typedVal(v, t.st))
 		}
 	}
 }
@@ -216,8 +241,12 @@ func inMap(k rune, m map[rune]bool) bool {
 	return ok
 }
 
-func typedVal(v []rune) interface{} {
+func typedVal(v []rune, st bool) interface{} {
 	val := string(v)
+	if st {
+		return val
+	}
+
 	if strings.EqualFold(val, "true") {
 		return true
 	}
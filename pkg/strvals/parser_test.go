@@ -16,6 +16,7 @@ limitations under the License.
 package strvals
 
 import (
+	"reflect"
 	"testing"
 
 	"github.com/ghodss/yaml"
@@ -218,6 +219,60 @@ func TestParseInto(t *testing.T) {
 	}
 }
 
+func TestParseSetString(t *testing.T) {
+	tests := []struct {
+		str    string
+		expect map[string]interface{}
+	}{
+		{"tag=1.0", map[string]interface{}{"tag": "1.0"}},
+		{"long_int_string=1234567890", map[string]interface{}{"long_int_string": "1234567890"}},
+		{"zip=01234", map[string]interface{}{"zip": "01234"}},
+		{"enabled=true,debug=False", map[string]interface{}{"enabled": "true", "debug": "False"}},
+		{"name1.name2=1", map[string]interface{}{"name1": map[string]interface{}{"name2": "1"}}},
+		{"ports={80,true}", map[string]interface{}{"ports": []interface{}{"80", "true"}}},
+		{"empty=", map[string]interface{}{"empty": ""}},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseString(tt.str)
+		if err != nil {
+			t.Fatalf("%s: %s", tt.str, err)
+		}
+		if !reflect.DeepEqual(got, tt.expect) {
+			t.Errorf("%s: Expected %#v, got %#v", tt.str, tt.expect, got)
+		}
+	}
+
+	if _, err := ParseString("name1,name2=value2"); err == nil {
+		t.Error("Expected a key without a value to be an error")
+	}
+}
+
+func TestParseIntoString(t *testing.T) {
+	got := map[string]interface{}{
+		"image": map[string]interface{}{
+			"repository": "nginx",
+		},
+	}
+	if err := ParseInto("image.tag=1,replicas=2", got); err != nil {
+		t.Fatal(err)
+	}
+	if err := ParseIntoString("image.tag=1.10,enabled=true", got); err != nil {
+		t.Fatal(err)
+	}
+	expect := map[string]interface{}{
+		"image": map[string]interface{}{
+			"repository": "nginx",
+			"tag":        "1.10",
+		},
+		"replicas": int64(2),
+		"enabled":  "true",
+	}
+	if !reflect.DeepEqual(got, expect) {
+		t.Errorf("Expected %#v, got %#v", expect, got)
+	}
+}
+
 func TestToYAML(t *testing.T) {
 	// The TestParse does the hard part. We just verify that YAML formatting is
 	// happening.
@@ -0,0 +1,88 @@
+/*
+Copyright 2017 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tiller
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	ctx "golang.org/x/net/context"
+
+	"k8s.io/helm/pkg/chartutil"
+	"k8s.io/helm/pkg/proto/hapi/chart"
+	"k8s.io/helm/pkg/proto/hapi/services"
+)
+
+// readmeFileNames are the names of the files read as a chart's README, in
+// order of preference. They are matched case-insensitively.
+var readmeFileNames = []string{"readme.md", "readme.txt", "readme"}
+
+// InspectChart returns the metadata, default values and README of a chart.
+func (s *ReleaseServer) InspectChart(c ctx.Context, req *services.InspectChartRequest) (*services.InspectChartResponse, error) {
+	ch := req.Chart
+	if ch == nil {
+		if len(req.ChartArchive) == 0 {
+			return nil, errMissingChart
+		}
+		var err error
+		if ch, err = chartutil.LoadArchive(bytes.NewReader(req.ChartArchive)); err != nil {
+			return nil, fmt.Errorf("cannot load chart archive: %s", err)
+		}
+	}
+	if ch.Metadata == nil {
+		return nil, fmt.Errorf("chart has no metadata")
+	}
+
+	res := &services.InspectChartResponse{
+		Metadata: ch.Metadata,
+		Readme:   chartReadme(ch),
+	}
+	if ch.Values != nil {
+		res.Values = ch.Values.Raw
+	}
+
+	reqs, err := chartutil.LoadRequirements(ch)
+	switch err {
+	case nil:
+		for _, d := range reqs.Dependencies {
+			res.Dependencies = append(res.Dependencies, &services.ChartDependency{
+				Name:       d.Name,
+				Version:    d.Version,
+				Repository: d.Repository,
+				Condition:  d.Condition,
+				Tags:       d.Tags,
+			})
+		}
+	case chartutil.ErrRequirementsNotFound:
+	default:
+		return nil, fmt.Errorf("cannot load requirements of chart %s: %s", ch.Metadata.Name, err)
+	}
+	return res, nil
+}
+
+// chartReadme returns the content of the chart's README, or "" if it has none.
+func chartReadme(ch *chart.Chart) string {
+	for _, name := range readmeFileNames {
+		for _, f := range ch.Files {
+			if f != nil && strings.ToLower(f.TypeUrl) == name {
+				return string(f.Value)
+			}
+		}
+	}
+	return ""
+}
@@ -0,0 +1,131 @@
+/*
+Copyright 2017 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tiller
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/golang/protobuf/ptypes/any"
+
+	"k8s.io/helm/pkg/chartutil"
+	"k8s.io/helm/pkg/helm"
+	"k8s.io/helm/pkg/proto/hapi/chart"
+	"k8s.io/helm/pkg/proto/hapi/services"
+)
+
+func inspectChartStub() *chart.Chart {
+	return &chart.Chart{
+		Metadata: &chart.Metadata{
+			Name:        "catalog",
+			Version:     "1.2.3",
+			AppVersion:  "4.5",
+			Maintainers: []*chart.Maintainer{{Name: "ops", Email: "ops@example.com"}},
+		},
+		Templates: []*chart.Template{
+			{Name: "templates/hello", Data: []byte("hello: world")},
+		},
+		Values: &chart.Config{Raw: "replicas: 1\n"},
+		Files: []*any.Any{
+			{TypeUrl: "README.md", Value: []byte("# Catalog\n")},
+			{TypeUrl: "requirements.yaml", Value: []byte("dependencies:\n- name: db\n  version: ~1.0\n  repository: https://example.com/charts\n  condition: db.enabled\n  tags: [storage]\n")},
+		},
+	}
+}
+
+func expectInspected(t *testing.T, res *services.InspectChartResponse, readme string) {
+	if res.Metadata.Name != "catalog" || res.Metadata.AppVersion != "4.5" || len(res.Metadata.Maintainers) != 1 {
+		t.Errorf("Unexpected metadata %v", res.Metadata)
+	}
+	if res.Values != "replicas: 1\n" {
+		t.Errorf("Unexpected values %q", res.Values)
+	}
+	if res.Readme != readme {
+		t.Errorf("Expected README %q, got %q", readme, res.Readme)
+	}
+	if len(res.Dependencies) != 1 {
+		t.Fatalf("Expected one dependency, got %v", res.Dependencies)
+	}
+	dep := res.Dependencies[0]
+	if dep.Name != "db" || dep.Version != "~1.0" || dep.Repository != "https://example.com/charts" || dep.Condition != "db.enabled" || len(dep.Tags) != 1 {
+		t.Errorf("Unexpected dependency %v", dep)
+	}
+}
+
+func TestInspectChart(t *testing.T) {
+	c := helm.NewContext()
+	rs := rsFixture()
+
+	res, err := rs.InspectChart(c, &services.InspectChartRequest{Chart: inspectChartStub()})
+	if err != nil {
+		t.Fatalf("Failed inspect: %s", err)
+	}
+	expectInspected(t, res, "# Catalog\n")
+}
+
+func TestInspectChart_Archive(t *testing.T) {
+	c := helm.NewContext()
+	rs := rsFixture()
+
+	dir, err := ioutil.TempDir("", "helm-inspect-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	ch := inspectChartStub()
+	ch.Files = ch.Files[1:]
+	path, err := chartutil.Save(ch, dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	archive, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	res, err := rs.InspectChart(c, &services.InspectChartRequest{ChartArchive: archive})
+	if err != nil {
+		t.Fatalf("Failed inspect: %s", err)
+	}
+	// A chart without a README is not an error.
+	expectInspected(t, res, "")
+}
+
+func TestInspectChart_Errors(t *testing.T) {
+	c := helm.NewContext()
+	rs := rsFixture()
+
+	if _, err := rs.InspectChart(c, &services.InspectChartRequest{}); err != errMissingChart {
+		t.Errorf("Expected %q, got %v", errMissingChart, err)
+	}
+	if _, err := rs.InspectChart(c, &services.InspectChartRequest{ChartArchive: []byte("not a chart")}); err == nil {
+		t.Error("Expected an error inspecting an invalid archive")
+	}
+}
+
+func TestChartReadme(t *testing.T) {
+	ch := &chart.Chart{Files: []*any.Any{
+		{TypeUrl: "README", Value: []byte("plain")},
+		{TypeUrl: "readme.txt", Value: []byte("text")},
+		{TypeUrl: "docs/README.md", Value: []byte("nested")},
+	}}
+	if got := chartReadme(ch); got != "text" {
+		t.Errorf("Expected the README.txt to be preferred, got %q", got)
+	}
+}
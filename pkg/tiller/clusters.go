@@ -0,0 +1,115 @@
+/*
+Copyright 2017 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tiller
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+
+	"k8s.io/kubernetes/pkg/client/clientset_generated/internalclientset"
+
+	"k8s.io/helm/pkg/proto/hapi/release"
+	"k8s.io/helm/pkg/tiller/environment"
+)
+
+// validClusterName matches the names that clusters can be configured with.
+var validClusterName = regexp.MustCompile(`^[a-z0-9]([-a-z0-9]*[a-z0-9])?$`)
+
+// kubeCluster is a Kubernetes cluster that releases can be installed in.
+type kubeCluster struct {
+	// env shares the template engines and release storage of Tiller's
+	// environment, but talks to the cluster.
+	env       *environment.Environment
+	clientset internalclientset.Interface
+	module    ReleaseModule
+}
+
+// AddCluster lets requests install releases in another cluster than Tiller's
+// own, by name. The records of those releases are kept in Tiller's storage,
+// with the name of their cluster.
+//
+// Clusters cannot be added when release modules are remote, as Rudder only
+// manages the cluster it runs in.
+func (s *ReleaseServer) AddCluster(name string, kubeClient environment.KubeClient, clientset internalclientset.Interface) error {
+	if !validClusterName.MatchString(name) {
+		return fmt.Errorf("invalid cluster name %q: must consist of lower case alphanumeric characters or '-'", name)
+	}
+	if _, ok := s.ReleaseModule.(*LocalReleaseModule); !ok {
+		return errors.New("clusters cannot be added when release modules are remote")
+	}
+	if _, ok := s.clusters[name]; ok {
+		return fmt.Errorf("cluster %q is already configured", name)
+	}
+	if s.clusters == nil {
+		s.clusters = map[string]*kubeCluster{}
+	}
+	s.clusters[name] = &kubeCluster{
+		env: &environment.Environment{
+			EngineYard: s.env.EngineYard,
+			Releases:   s.env.Releases,
+			KubeClient: kubeClient,
+		},
+		clientset: clientset,
+		module:    &LocalReleaseModule{clientset: clientset},
+	}
+	return nil
+}
+
+// cluster returns the cluster named name, or Tiller's own cluster if name is
+// empty.
+func (s *ReleaseServer) cluster(name string) (*kubeCluster, error) {
+	if name == "" {
+		return &kubeCluster{env: s.env, clientset: s.clientset, module: s.ReleaseModule}, nil
+	}
+	c, ok := s.clusters[name]
+	if !ok {
+		return nil, fmt.Errorf("cluster %q is not configured%s", name, s.clusterHint())
+	}
+	return c, nil
+}
+
+// releaseCluster returns the cluster that r is installed in.
+func (s *ReleaseServer) releaseCluster(r *release.Release) (*kubeCluster, error) {
+	c, err := s.cluster(r.Cluster)
+	if err != nil {
+		return nil, fmt.Errorf("release %s: %s", r.Name, err)
+	}
+	return c, nil
+}
+
+// describeCluster names the cluster named name in messages.
+func describeCluster(name string) string {
+	if name == "" {
+		return "Tiller's own cluster"
+	}
+	return fmt.Sprintf("cluster %q", name)
+}
+
+// clusterHint lists the configured clusters for an error message.
+func (s *ReleaseServer) clusterHint() string {
+	if len(s.clusters) == 0 {
+		return "; Tiller only manages its own cluster"
+	}
+	names := make([]string, 0, len(s.clusters))
+	for name := range s.clusters {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return fmt.Sprintf("; configured clusters are %v", names)
+}
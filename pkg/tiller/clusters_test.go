@@ -0,0 +1,119 @@
+/*
+Copyright 2017 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tiller
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"k8s.io/kubernetes/pkg/client/clientset_generated/internalclientset/fake"
+
+	"k8s.io/helm/pkg/helm"
+	"k8s.io/helm/pkg/proto/hapi/chart"
+	"k8s.io/helm/pkg/proto/hapi/services"
+	"k8s.io/helm/pkg/tiller/environment"
+)
+
+func TestAddCluster(t *testing.T) {
+	rs := rsFixture()
+	kc := &environment.PrintingKubeClient{Out: &bytes.Buffer{}}
+
+	if err := rs.AddCluster("spoke-1", kc, fake.NewSimpleClientset()); err != nil {
+		t.Fatal(err)
+	}
+	if err := rs.AddCluster("spoke-1", kc, fake.NewSimpleClientset()); err == nil {
+		t.Error("Expected a cluster to be configured only once")
+	}
+	if err := rs.AddCluster("Spoke_1", kc, fake.NewSimpleClientset()); err == nil {
+		t.Error("Expected an invalid cluster name to be rejected")
+	}
+
+	remote := NewReleaseServer(MockEnvironment(), fake.NewSimpleClientset(), true)
+	if err := remote.AddCluster("spoke-1", kc, fake.NewSimpleClientset()); err == nil {
+		t.Error("Expected clusters to be rejected with remote release modules")
+	}
+
+	if _, err := rs.cluster("spoke-2"); err == nil || !strings.Contains(err.Error(), `cluster "spoke-2" is not configured; configured clusters are [spoke-1]`) {
+		t.Errorf("Expected an unknown cluster to be reported, got %v", err)
+	}
+}
+
+func TestReleaseInCluster(t *testing.T) {
+	c := helm.NewContext()
+	rs := rsFixture()
+	var own, spoke bytes.Buffer
+	rs.env.KubeClient = &environment.PrintingKubeClient{Out: &own}
+	if err := rs.AddCluster("spoke-1", &environment.PrintingKubeClient{Out: &spoke}, fake.NewSimpleClientset()); err != nil {
+		t.Fatal(err)
+	}
+	ch := &chart.Chart{
+		Metadata: &chart.Metadata{Name: "hello"},
+		Templates: []*chart.Template{
+			{Name: "templates/cm.yaml", Data: []byte("apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: {{ .Release.Name }}-cm\n")},
+		},
+	}
+
+	if _, err := rs.InstallRelease(c, &services.InstallReleaseRequest{Name: "edge", Namespace: "default", Chart: ch, Cluster: "spoke-2"}); err == nil || !strings.Contains(err.Error(), `cluster "spoke-2" is not configured`) {
+		t.Fatalf("Expected an unknown cluster to be rejected, got %v", err)
+	}
+
+	res, err := rs.InstallRelease(c, &services.InstallReleaseRequest{Name: "edge", Namespace: "default", Chart: ch, Cluster: "spoke-1"})
+	if err != nil {
+		t.Fatalf("Failed install: %s", err)
+	}
+	if res.Release.Cluster != "spoke-1" {
+		t.Errorf("Expected the release to record its cluster, got %q", res.Release.Cluster)
+	}
+	if !strings.Contains(spoke.String(), "name: edge-cm") || own.Len() != 0 {
+		t.Errorf("Expected the release to be installed in its cluster only, got %q and %q", spoke.String(), own.String())
+	}
+
+	// Later operations follow the release to its cluster.
+	spoke.Reset()
+	upd, err := rs.UpdateRelease(c, &services.UpdateReleaseRequest{Name: "edge", Chart: ch})
+	if err != nil {
+		t.Fatalf("Failed upgrade: %s", err)
+	}
+	if upd.Release.Cluster != "spoke-1" || spoke.Len() == 0 || own.Len() != 0 {
+		t.Errorf("Expected the upgrade in the cluster of the release, got %q", upd.Release.Cluster)
+	}
+	if _, err := rs.UpdateRelease(c, &services.UpdateReleaseRequest{Name: "edge", Chart: ch, Cluster: "spoke-2"}); err == nil || !strings.Contains(err.Error(), `is installed in cluster "spoke-1", not in cluster "spoke-2"`) {
+		t.Errorf("Expected an upgrade for another cluster to be rejected, got %v", err)
+	}
+
+	st, err := rs.GetReleaseStatus(c, &services.GetReleaseStatusRequest{Name: "edge"})
+	if err != nil {
+		t.Fatalf("Failed status: %s", err)
+	}
+	if st.Cluster != "spoke-1" {
+		t.Errorf("Expected the status to name the cluster, got %q", st.Cluster)
+	}
+
+	spoke.Reset()
+	if _, err := rs.UninstallRelease(c, &services.UninstallReleaseRequest{Name: "edge"}); err != nil {
+		t.Fatalf("Failed uninstall: %s", err)
+	}
+	if spoke.Len() == 0 || own.Len() != 0 {
+		t.Errorf("Expected the release to be deleted from its cluster, got %q and %q", spoke.String(), own.String())
+	}
+
+	// A deleted release is replaced in its own cluster only.
+	if _, err := rs.InstallRelease(c, &services.InstallReleaseRequest{Name: "edge", Namespace: "default", Chart: ch, ReuseName: true}); err == nil || !strings.Contains(err.Error(), `cannot be replaced in Tiller's own cluster`) {
+		t.Errorf("Expected the release not to be replaced in another cluster, got %v", err)
+	}
+}
@@ -0,0 +1,209 @@
+/*
+Copyright 2017 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tiller
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/ghodss/yaml"
+	"k8s.io/apimachinery/pkg/labels"
+
+	"k8s.io/helm/pkg/chartutil"
+	"k8s.io/helm/pkg/proto/hapi/release"
+)
+
+// skipInjectionAnno is the annotation with which a workload opts out of
+// container injection: "true" skips every injection policy, and a
+// comma-separated list of policy names skips those policies.
+const skipInjectionAnno = "helm.sh/skip-injection"
+
+// InjectionPolicy adds init containers and containers, such as log shippers
+// or proxies, to the Pod templates of workloads whose Pod templates have
+// matching labels.
+type InjectionPolicy struct {
+	// Name identifies the policy in the revisions it changed, and in the
+	// skip-injection annotation of workloads.
+	Name string `json:"name"`
+	// Selector is a label selector, such as "tier=web,!no-proxy", that the
+	// labels of a Pod template have to match. An empty selector matches
+	// every workload.
+	Selector string `json:"selector,omitempty"`
+	// InitContainers are appended to the init containers of matching Pod
+	// templates.
+	InitContainers []map[string]interface{} `json:"initContainers,omitempty"`
+	// Containers are appended to the containers of matching Pod templates.
+	Containers []map[string]interface{} `json:"containers,omitempty"`
+}
+
+// injectionPolicy is an InjectionPolicy with its parsed selector.
+type injectionPolicy struct {
+	InjectionPolicy
+	selector labels.Selector
+}
+
+// LoadInjectionPolicies parses a YAML list of injection policies.
+func LoadInjectionPolicies(data []byte) ([]InjectionPolicy, error) {
+	var policies []InjectionPolicy
+	if err := yaml.Unmarshal(data, &policies); err != nil {
+		return nil, err
+	}
+	return policies, nil
+}
+
+// SetInjectionPolicies makes the server add the containers of policies to
+// the workloads of installs, upgrades and re-rendered rollbacks after they
+// are rendered, in the order of the policies. Hooks are left alone, since
+// Jobs with sidecars do not complete.
+//
+// A container is only added to a Pod template that has no container of the
+// same name, so one that the chart defines itself is kept, and upgrading a
+// release never adds a container twice. Each revision records the
+// containers that every policy added.
+func (s *ReleaseServer) SetInjectionPolicies(policies []InjectionPolicy) error {
+	compiled := make([]injectionPolicy, 0, len(policies))
+	names := map[string]bool{}
+	for _, p := range policies {
+		if p.Name == "" {
+			return errors.New("injection policies need a name")
+		}
+		if names[p.Name] {
+			return fmt.Errorf("injection policy %q is defined more than once", p.Name)
+		}
+		names[p.Name] = true
+		sel, err := labels.Parse(p.Selector)
+		if err != nil {
+			return fmt.Errorf("injection policy %s: %s", p.Name, err)
+		}
+		if len(p.InitContainers)+len(p.Containers) == 0 {
+			return fmt.Errorf("injection policy %s has no containers", p.Name)
+		}
+		for _, list := range [][]map[string]interface{}{p.InitContainers, p.Containers} {
+			for _, c := range list {
+				if name, _ := c["name"].(string); name == "" {
+					return fmt.Errorf("injection policy %s: containers need a name", p.Name)
+				}
+			}
+		}
+		compiled = append(compiled, injectionPolicy{InjectionPolicy: p, selector: sel})
+	}
+	s.injectionPolicies = compiled
+	return nil
+}
+
+// injectContainers applies the injection policies to manifests, and returns
+// the containers that each policy added to each workload.
+func (s *ReleaseServer) injectContainers(manifests []manifest) ([]*release.ContainerInjection, error) {
+	if len(s.injectionPolicies) == 0 {
+		return nil, nil
+	}
+	var injections []*release.ContainerInjection
+	for i, m := range manifests {
+		path := podSpecPaths[m.head.Kind]
+		if path == nil || m.head.Metadata == nil {
+			continue
+		}
+		obj := map[string]interface{}{}
+		if err := yaml.Unmarshal([]byte(m.content), &obj); err != nil {
+			return nil, fmt.Errorf("%s: %s", m.name, err)
+		}
+		spec := nestedMap(obj, path...)
+		if spec == nil {
+			continue
+		}
+		// The labels of the Pod template are next to its spec.
+		labelsPath := append(append([]string{}, path[:len(path)-1]...), "metadata", "labels")
+		podLabels := labels.Set{}
+		for k, v := range nestedMap(obj, labelsPath...) {
+			if v, ok := v.(string); ok {
+				podLabels[k] = v
+			}
+		}
+
+		skip := m.head.Metadata.Annotations[skipInjectionAnno]
+		changed := false
+		for _, p := range s.injectionPolicies {
+			if skipsInjection(skip, p.Name) || !p.selector.Matches(podLabels) {
+				continue
+			}
+			added := appendContainers(spec, "initContainers", p.InitContainers)
+			added = append(added, appendContainers(spec, "containers", p.Containers)...)
+			if len(added) == 0 {
+				continue
+			}
+			injections = append(injections, &release.ContainerInjection{
+				Policy:     p.Name,
+				Kind:       m.head.Kind,
+				Name:       m.head.Metadata.Name,
+				Containers: added,
+			})
+			changed = true
+		}
+		if !changed {
+			continue
+		}
+		content, err := yaml.Marshal(obj)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %s", m.name, err)
+		}
+		manifests[i].content = string(content)
+	}
+	return injections, nil
+}
+
+// skipsInjection reports whether anno, the value of skipInjectionAnno on a
+// workload, opts it out of the policy named policy.
+func skipsInjection(anno, policy string) bool {
+	if strings.ToLower(strings.TrimSpace(anno)) == "true" {
+		return true
+	}
+	for _, name := range strings.Split(anno, ",") {
+		if strings.TrimSpace(name) == policy {
+			return true
+		}
+	}
+	return false
+}
+
+// appendContainers appends containers to the list field of a Pod spec,
+// skipping those whose names are already in it, and returns the names of
+// the containers it appended.
+func appendContainers(spec map[string]interface{}, field string, containers []map[string]interface{}) []string {
+	list, _ := spec[field].([]interface{})
+	existing := map[string]bool{}
+	for _, c := range list {
+		if c, ok := c.(map[string]interface{}); ok {
+			name, _ := c["name"].(string)
+			existing[name] = true
+		}
+	}
+	var added []string
+	for _, c := range containers {
+		name := c["name"].(string)
+		if existing[name] {
+			continue
+		}
+		existing[name] = true
+		list = append(list, chartutil.DeepCopy(c))
+		added = append(added, name)
+	}
+	if len(added) > 0 {
+		spec[field] = list
+	}
+	return added
+}
@@ -0,0 +1,252 @@
+/*
+Copyright 2017 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tiller
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/ghodss/yaml"
+
+	"k8s.io/helm/pkg/helm"
+	"k8s.io/helm/pkg/proto/hapi/chart"
+	"k8s.io/helm/pkg/proto/hapi/release"
+	"k8s.io/helm/pkg/proto/hapi/services"
+)
+
+var testInjectionPolicies = []InjectionPolicy{
+	{
+		Name:       "logging",
+		Containers: []map[string]interface{}{{"name": "log-shipper", "image": "fluent-bit"}},
+	},
+	{
+		Name:           "mesh",
+		Selector:       "tier=web",
+		InitContainers: []map[string]interface{}{{"name": "proxy-init", "image": "proxy-init"}},
+		Containers:     []map[string]interface{}{{"name": "proxy", "image": "proxy"}},
+	},
+}
+
+var webDeployment = `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+spec:
+  template:
+    metadata:
+      labels:
+        tier: web
+    spec:
+      containers:
+      - name: web
+        image: nginx
+`
+
+var optedOutDeployment = `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: admin
+  annotations:
+    helm.sh/skip-injection: mesh
+spec:
+  template:
+    metadata:
+      labels:
+        tier: web
+    spec:
+      containers:
+      - name: admin
+        image: admin
+`
+
+var ownLoggerPod = `apiVersion: v1
+kind: Pod
+metadata:
+  name: worker
+  labels:
+    tier: batch
+spec:
+  containers:
+  - name: worker
+    image: worker
+  - name: log-shipper
+    image: custom-shipper
+`
+
+func containerNames(t *testing.T, content string, path ...string) []string {
+	obj := map[string]interface{}{}
+	if err := yaml.Unmarshal([]byte(content), &obj); err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for _, c := range nestedSlice(obj, path...) {
+		names = append(names, c["name"].(string))
+	}
+	return names
+}
+
+func TestSetInjectionPolicies(t *testing.T) {
+	rs := rsFixture()
+	if err := rs.SetInjectionPolicies(testInjectionPolicies); err != nil {
+		t.Fatal(err)
+	}
+
+	container := []map[string]interface{}{{"name": "proxy"}}
+	for _, tt := range []struct {
+		policies []InjectionPolicy
+		expect   string
+	}{
+		{[]InjectionPolicy{{Containers: container}}, "need a name"},
+		{[]InjectionPolicy{{Name: "mesh", Containers: container}, {Name: "mesh", Containers: container}}, "defined more than once"},
+		{[]InjectionPolicy{{Name: "mesh", Selector: "tier in (", Containers: container}}, "injection policy mesh"},
+		{[]InjectionPolicy{{Name: "mesh"}}, "has no containers"},
+		{[]InjectionPolicy{{Name: "mesh", InitContainers: []map[string]interface{}{{"image": "proxy"}}}}, "containers need a name"},
+	} {
+		err := rs.SetInjectionPolicies(tt.policies)
+		if err == nil || !strings.Contains(err.Error(), tt.expect) {
+			t.Errorf("Expected an error containing %q for %v, got %v", tt.expect, tt.policies, err)
+		}
+	}
+}
+
+func TestLoadInjectionPolicies(t *testing.T) {
+	policies, err := LoadInjectionPolicies([]byte(`- name: mesh
+  selector: tier=web
+  initContainers:
+  - name: proxy-init
+    image: proxy-init
+  containers:
+  - name: proxy
+    image: proxy
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(policies, testInjectionPolicies[1:]) {
+		t.Errorf("Expected %v, got %v", testInjectionPolicies[1:], policies)
+	}
+}
+
+func TestInjectContainers(t *testing.T) {
+	rs := rsFixture()
+	if err := rs.SetInjectionPolicies(testInjectionPolicies); err != nil {
+		t.Fatal(err)
+	}
+	manifests := []manifest{
+		testManifest(t, "templates/web.yaml", webDeployment),
+		testManifest(t, "templates/admin.yaml", optedOutDeployment),
+		testManifest(t, "templates/worker.yaml", ownLoggerPod),
+		testManifest(t, "templates/configmap.yaml", plainConfigMap),
+	}
+
+	injections, err := rs.injectContainers(manifests)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expect := []*release.ContainerInjection{
+		{Policy: "logging", Kind: "Deployment", Name: "web", Containers: []string{"log-shipper"}},
+		{Policy: "mesh", Kind: "Deployment", Name: "web", Containers: []string{"proxy-init", "proxy"}},
+		{Policy: "logging", Kind: "Deployment", Name: "admin", Containers: []string{"log-shipper"}},
+	}
+	if !reflect.DeepEqual(injections, expect) {
+		t.Errorf("Expected injections %v, got %v", expect, injections)
+	}
+
+	for _, tt := range []struct {
+		content string
+		path    []string
+		expect  []string
+	}{
+		{manifests[0].content, []string{"spec", "template", "spec", "initContainers"}, []string{"proxy-init"}},
+		{manifests[0].content, []string{"spec", "template", "spec", "containers"}, []string{"web", "log-shipper", "proxy"}},
+		{manifests[1].content, []string{"spec", "template", "spec", "containers"}, []string{"admin", "log-shipper"}},
+		// The Pod defines its own log shipper, which is kept.
+		{manifests[2].content, []string{"spec", "containers"}, []string{"worker", "log-shipper"}},
+	} {
+		if names := containerNames(t, tt.content, tt.path...); !reflect.DeepEqual(names, tt.expect) {
+			t.Errorf("Expected %s %v, got %v in\n%s", tt.path[len(tt.path)-1], tt.expect, names, tt.content)
+		}
+	}
+	if manifests[2].content != ownLoggerPod || manifests[3].content != plainConfigMap {
+		t.Error("Expected manifests that no policy changed to be left alone")
+	}
+
+	// Injecting into what was injected before adds nothing.
+	injected := manifests[0].content
+	if injections, err := rs.injectContainers(manifests); err != nil || len(injections) != 0 {
+		t.Errorf("Expected no injections the second time, got %v (%v)", injections, err)
+	}
+	if manifests[0].content != injected {
+		t.Errorf("Expected the manifest to be unchanged the second time, got\n%s", manifests[0].content)
+	}
+}
+
+func TestSkipsInjection(t *testing.T) {
+	for _, tt := range []struct {
+		anno   string
+		expect bool
+	}{
+		{"", false},
+		{"true", true},
+		{" True ", true},
+		{"false", false},
+		{"logging, mesh", true},
+		{"logging", false},
+		{"meshes", false},
+	} {
+		if got := skipsInjection(tt.anno, "mesh"); got != tt.expect {
+			t.Errorf("Expected skipsInjection(%q, mesh) to be %v", tt.anno, tt.expect)
+		}
+	}
+}
+
+func TestInstallAndUpdateRelease_InjectsContainers(t *testing.T) {
+	c := helm.NewContext()
+	rs := rsFixture()
+	if err := rs.SetInjectionPolicies(testInjectionPolicies); err != nil {
+		t.Fatal(err)
+	}
+	ch := &chart.Chart{
+		Metadata: &chart.Metadata{Name: "web"},
+		Templates: []*chart.Template{
+			{Name: "templates/pod.yaml", Data: []byte(strings.Replace(ownLoggerPod, "batch", "web", 1))},
+		},
+	}
+
+	res, err := rs.InstallRelease(c, &services.InstallReleaseRequest{Name: "injected", Namespace: "spaced", Chart: ch})
+	if err != nil {
+		t.Fatalf("Failed install: %s", err)
+	}
+	expect := []*release.ContainerInjection{
+		{Policy: "mesh", Kind: "Pod", Name: "worker", Containers: []string{"proxy-init", "proxy"}},
+	}
+	if !reflect.DeepEqual(res.Release.Info.ContainerInjections, expect) {
+		t.Errorf("Expected the install to record %v, got %v", expect, res.Release.Info.ContainerInjections)
+	}
+
+	up, err := rs.UpdateRelease(c, &services.UpdateReleaseRequest{Name: "injected", Chart: ch})
+	if err != nil {
+		t.Fatalf("Failed upgrade: %s", err)
+	}
+	if !reflect.DeepEqual(up.Release.Info.ContainerInjections, expect) {
+		t.Errorf("Expected the upgrade to record %v, got %v", expect, up.Release.Info.ContainerInjections)
+	}
+	if names := containerNames(t, up.Release.Manifest[strings.Index(up.Release.Manifest, "apiVersion"):], "spec", "containers"); !reflect.DeepEqual(names, []string{"worker", "log-shipper", "proxy"}) {
+		t.Errorf("Expected each container once after the upgrade, got %v", names)
+	}
+}
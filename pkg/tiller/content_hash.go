@@ -0,0 +1,232 @@
+/*
+Copyright 2017 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tiller
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/ghodss/yaml"
+
+	"k8s.io/helm/pkg/proto/hapi/release"
+	relutil "k8s.io/helm/pkg/releaseutil"
+)
+
+// contentHashAnno is the annotation that, when set to "true" on a ConfigMap
+// or Secret, appends a hash of the object's contents to its name.
+//
+// References to the object from Pod templates in the release are rewritten
+// to the new name, so changing the contents rolls the Pods that use it.
+// Objects with the names of earlier revisions are always deleted on upgrade,
+// even if the upgrade keeps the other resources removed from the chart, unless
+// they have the keep resource policy or another release declares them.
+const contentHashAnno = "helm.sh/content-hash-suffix"
+
+// contentHashLen is the number of hex digits of the hash used in a name.
+const contentHashLen = 10
+
+// configRenames maps the original names of hashed objects to their new
+// names, by kind ("ConfigMap" or "Secret").
+type configRenames map[string]map[string]string
+
+// hashConfigNames applies contentHashAnno to manifests, and rewrites the
+// references in manifests and hooks to objects that were renamed.
+func hashConfigNames(manifests []manifest, hooks []*release.Hook) error {
+	renames := configRenames{}
+	for i, m := range manifests {
+		if !wantsContentHash(m) {
+			continue
+		}
+		obj := map[string]interface{}{}
+		if err := yaml.Unmarshal([]byte(m.content), &obj); err != nil {
+			return fmt.Errorf("%s: %s", m.name, err)
+		}
+		hash, err := contentHash(obj)
+		if err != nil {
+			return fmt.Errorf("%s: %s", m.name, err)
+		}
+		name := m.head.Metadata.Name + "-" + hash
+		obj["metadata"].(map[string]interface{})["name"] = name
+		content, err := yaml.Marshal(obj)
+		if err != nil {
+			return fmt.Errorf("%s: %s", m.name, err)
+		}
+		if renames[m.head.Kind] == nil {
+			renames[m.head.Kind] = map[string]string{}
+		}
+		renames[m.head.Kind][m.head.Metadata.Name] = name
+		manifests[i].content = string(content)
+		manifests[i].head.Metadata.Name = name
+	}
+	if len(renames) == 0 {
+		return nil
+	}
+
+	for i, m := range manifests {
+		content, err := renames.rewrite(m.head.Kind, m.content)
+		if err != nil {
+			return fmt.Errorf("%s: %s", m.name, err)
+		}
+		manifests[i].content = content
+	}
+	for _, h := range hooks {
+		content, err := renames.rewrite(h.Kind, h.Manifest)
+		if err != nil {
+			return fmt.Errorf("%s: %s", h.Path, err)
+		}
+		h.Manifest = content
+	}
+	return nil
+}
+
+func wantsContentHash(m manifest) bool {
+	return hasContentHash(m.head)
+}
+
+// hasContentHash reports whether the resource of head is a ConfigMap or
+// Secret with contentHashAnno.
+func hasContentHash(head *relutil.SimpleHead) bool {
+	if head.Kind != "ConfigMap" && head.Kind != "Secret" {
+		return false
+	}
+	if head.Metadata == nil || head.Metadata.Name == "" {
+		return false
+	}
+	return strings.ToLower(strings.TrimSpace(head.Metadata.Annotations[contentHashAnno])) == "true"
+}
+
+// contentHash hashes the parts of a ConfigMap or Secret that are exposed to
+// Pods. Metadata is left out, so relabelling an object does not rename it.
+func contentHash(obj map[string]interface{}) (string, error) {
+	content := map[string]interface{}{}
+	for _, k := range []string{"kind", "type", "data", "stringData", "binaryData"} {
+		if v, ok := obj[k]; ok {
+			content[k] = v
+		}
+	}
+	// encoding/json sorts map keys, so equal contents always hash the same.
+	b, err := json.Marshal(content)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:])[:contentHashLen], nil
+}
+
+// rewrite updates the references to renamed objects in the Pod spec of a
+// manifest of the given kind. Manifests without references are returned
+// unchanged.
+func (r configRenames) rewrite(kind, content string) (string, error) {
+	path := podSpecPaths[kind]
+	if path == nil {
+		return content, nil
+	}
+	obj := map[string]interface{}{}
+	if err := yaml.Unmarshal([]byte(content), &obj); err != nil {
+		return content, err
+	}
+	spec := nestedMap(obj, path...)
+	if spec == nil || !r.rewritePodSpec(spec) {
+		return content, nil
+	}
+	b, err := yaml.Marshal(obj)
+	return string(b), err
+}
+
+// podSpecPaths gives the location of the Pod spec in each kind that has one.
+var podSpecPaths = map[string][]string{
+	"Pod":                   {"spec"},
+	"ReplicationController": {"spec", "template", "spec"},
+	"ReplicaSet":            {"spec", "template", "spec"},
+	"Deployment":            {"spec", "template", "spec"},
+	"DaemonSet":             {"spec", "template", "spec"},
+	"StatefulSet":           {"spec", "template", "spec"},
+	"Job":                   {"spec", "template", "spec"},
+	"CronJob":               {"spec", "jobTemplate", "spec", "template", "spec"},
+}
+
+// rewritePodSpec renames references in volumes, projected volumes, envFrom,
+// env and imagePullSecrets. It returns true if anything was renamed.
+func (r configRenames) rewritePodSpec(spec map[string]interface{}) bool {
+	changed := false
+	rename := func(m map[string]interface{}, kind, field string) {
+		if m == nil {
+			return
+		}
+		name, _ := m[field].(string)
+		if n, ok := r[kind][name]; ok {
+			m[field] = n
+			changed = true
+		}
+	}
+
+	for _, v := range nestedSlice(spec, "volumes") {
+		rename(nestedMap(v, "configMap"), "ConfigMap", "name")
+		rename(nestedMap(v, "secret"), "Secret", "secretName")
+		for _, src := range nestedSlice(v, "projected", "sources") {
+			rename(nestedMap(src, "configMap"), "ConfigMap", "name")
+			rename(nestedMap(src, "secret"), "Secret", "name")
+		}
+	}
+	for _, s := range nestedSlice(spec, "imagePullSecrets") {
+		rename(s, "Secret", "name")
+	}
+	for _, containers := range []string{"initContainers", "containers"} {
+		for _, c := range nestedSlice(spec, containers) {
+			for _, e := range nestedSlice(c, "envFrom") {
+				rename(nestedMap(e, "configMapRef"), "ConfigMap", "name")
+				rename(nestedMap(e, "secretRef"), "Secret", "name")
+			}
+			for _, e := range nestedSlice(c, "env") {
+				rename(nestedMap(e, "valueFrom", "configMapKeyRef"), "ConfigMap", "name")
+				rename(nestedMap(e, "valueFrom", "secretKeyRef"), "Secret", "name")
+			}
+		}
+	}
+	return changed
+}
+
+// nestedMap returns the map found by following path from obj, or nil.
+func nestedMap(obj map[string]interface{}, path ...string) map[string]interface{} {
+	for _, p := range path {
+		next, ok := obj[p].(map[string]interface{})
+		if !ok {
+			return nil
+		}
+		obj = next
+	}
+	return obj
+}
+
+// nestedSlice returns the maps in the list found by following path from obj.
+func nestedSlice(obj map[string]interface{}, path ...string) []map[string]interface{} {
+	parent := nestedMap(obj, path[:len(path)-1]...)
+	if parent == nil {
+		return nil
+	}
+	items, _ := parent[path[len(path)-1]].([]interface{})
+	var maps []map[string]interface{}
+	for _, item := range items {
+		if m, ok := item.(map[string]interface{}); ok {
+			maps = append(maps, m)
+		}
+	}
+	return maps
+}
@@ -0,0 +1,229 @@
+/*
+Copyright 2017 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tiller
+
+import (
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/ghodss/yaml"
+
+	"k8s.io/helm/pkg/helm"
+	"k8s.io/helm/pkg/proto/hapi/chart"
+	"k8s.io/helm/pkg/proto/hapi/release"
+	"k8s.io/helm/pkg/proto/hapi/services"
+	util "k8s.io/helm/pkg/releaseutil"
+	"k8s.io/helm/pkg/tiller/environment"
+)
+
+var hashedConfigMap = `apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: settings
+  annotations:
+    helm.sh/content-hash-suffix: "true"
+data:
+  color: blue
+`
+
+var hashedSecret = `apiVersion: v1
+kind: Secret
+metadata:
+  name: creds
+  annotations:
+    helm.sh/content-hash-suffix: "true"
+data:
+  password: aHVudGVyMg==
+`
+
+var plainConfigMap = `apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: plain
+data:
+  color: red
+`
+
+var deploymentUsingConfig = `apiVersion: extensions/v1beta1
+kind: Deployment
+metadata:
+  name: web
+spec:
+  template:
+    spec:
+      imagePullSecrets:
+      - name: creds
+      volumes:
+      - name: settings
+        configMap:
+          name: settings
+      - name: plain
+        configMap:
+          name: plain
+      - name: all
+        projected:
+          sources:
+          - secret:
+              name: creds
+      containers:
+      - name: web
+        envFrom:
+        - configMapRef:
+            name: settings
+        env:
+        - name: PASSWORD
+          valueFrom:
+            secretKeyRef:
+              name: creds
+              key: password
+`
+
+var cronJobUsingConfig = `apiVersion: batch/v2alpha1
+kind: CronJob
+metadata:
+  name: report
+spec:
+  jobTemplate:
+    spec:
+      template:
+        spec:
+          containers:
+          - name: report
+            envFrom:
+            - secretRef:
+                name: creds
+`
+
+func testManifest(t *testing.T, name, content string) manifest {
+	var head util.SimpleHead
+	if err := yaml.Unmarshal([]byte(content), &head); err != nil {
+		t.Fatal(err)
+	}
+	return manifest{name: name, content: content, head: &head}
+}
+
+func hashedName(t *testing.T, content string) string {
+	obj := map[string]interface{}{}
+	if err := yaml.Unmarshal([]byte(content), &obj); err != nil {
+		t.Fatal(err)
+	}
+	return obj["metadata"].(map[string]interface{})["name"].(string)
+}
+
+func TestHashConfigNames(t *testing.T) {
+	manifests := []manifest{
+		testManifest(t, "templates/configmap.yaml", hashedConfigMap),
+		testManifest(t, "templates/secret.yaml", hashedSecret),
+		testManifest(t, "templates/plain.yaml", plainConfigMap),
+		testManifest(t, "templates/deployment.yaml", deploymentUsingConfig),
+	}
+	hooks := []*release.Hook{{Name: "report", Kind: "CronJob", Path: "templates/cronjob.yaml", Manifest: cronJobUsingConfig}}
+
+	if err := hashConfigNames(manifests, hooks); err != nil {
+		t.Fatal(err)
+	}
+
+	cm := hashedName(t, manifests[0].content)
+	secret := hashedName(t, manifests[1].content)
+	if !strings.HasPrefix(cm, "settings-") || len(cm) != len("settings-")+contentHashLen {
+		t.Errorf("Expected a hashed ConfigMap name, got %q", cm)
+	}
+	if !strings.HasPrefix(secret, "creds-") || len(secret) != len("creds-")+contentHashLen {
+		t.Errorf("Expected a hashed Secret name, got %q", secret)
+	}
+	if manifests[0].head.Metadata.Name != cm {
+		t.Errorf("Expected manifest head to be renamed to %q, got %q", cm, manifests[0].head.Metadata.Name)
+	}
+	if manifests[2].content != plainConfigMap {
+		t.Errorf("Expected ConfigMap without annotation to be unchanged, got\n%s", manifests[2].content)
+	}
+
+	deployment := manifests[3].content
+	if n := strings.Count(deployment, "name: "+cm); n != 2 {
+		t.Errorf("Expected 2 references to %s in the Deployment, got %d:\n%s", cm, n, deployment)
+	}
+	if n := strings.Count(deployment, "name: "+secret); n != 3 {
+		t.Errorf("Expected 3 references to %s in the Deployment, got %d:\n%s", secret, n, deployment)
+	}
+	if !strings.Contains(deployment, "name: plain") {
+		t.Errorf("Expected reference to unhashed ConfigMap to be unchanged:\n%s", deployment)
+	}
+	if !strings.Contains(hooks[0].Manifest, "name: "+secret) {
+		t.Errorf("Expected hook reference to be rewritten:\n%s", hooks[0].Manifest)
+	}
+}
+
+func TestHashConfigNamesFollowsContent(t *testing.T) {
+	hash := func(content string) string {
+		manifests := []manifest{testManifest(t, "templates/configmap.yaml", content)}
+		if err := hashConfigNames(manifests, nil); err != nil {
+			t.Fatal(err)
+		}
+		return hashedName(t, manifests[0].content)
+	}
+
+	original := hash(hashedConfigMap)
+	relabelled := hash(strings.Replace(hashedConfigMap, "  annotations:", "  labels:\n    tier: web\n  annotations:", 1))
+	changed := hash(strings.Replace(hashedConfigMap, "color: blue", "color: green", 1))
+
+	if original != relabelled {
+		t.Errorf("Expected metadata changes to keep the name %q, got %q", original, relabelled)
+	}
+	if original == changed {
+		t.Errorf("Expected a data change to change the name, got %q for both", original)
+	}
+}
+
+func TestUpdateRelease_DeletesSupersededHashedConfig(t *testing.T) {
+	c := helm.NewContext()
+	previous := strings.Replace(hashedConfigMap, "name: settings", "name: settings-0123456789", 1)
+
+	for _, keepRemoved := range []bool{false, true} {
+		rs := rsFixture()
+		kc := &pruneRecordingKubeClient{PrintingKubeClient: environment.PrintingKubeClient{Out: os.Stdout}}
+		rs.env.KubeClient = kc
+
+		rel := releaseStub()
+		rel.Manifest = pruneManifest(previous)
+		rs.env.Releases.Create(rel)
+
+		ch := &chart.Chart{
+			Metadata:  &chart.Metadata{Name: "hello"},
+			Templates: []*chart.Template{{Name: "templates/settings.yaml", Data: []byte(hashedConfigMap)}},
+		}
+		res, err := rs.UpdateRelease(c, &services.UpdateReleaseRequest{
+			Name:         rel.Name,
+			Chart:        ch,
+			KeepRemoved:  keepRemoved,
+			DisableHooks: true,
+		})
+		if err != nil {
+			t.Fatalf("Failed upgrade: %s", err)
+		}
+		if strings.Contains(res.Release.Manifest, "settings-0123456789") {
+			t.Fatalf("Expected the upgrade to rename the ConfigMap, got\n%s", res.Release.Manifest)
+		}
+
+		// The Kubernetes client deletes the resources of the original
+		// manifest that the upgraded one does not declare.
+		if got := pruneNames(kc.original); !reflect.DeepEqual(got, []string{"settings-0123456789"}) {
+			t.Errorf("keepRemoved=%t: expected the previous hashed ConfigMap to be deleted, got original resources %v", keepRemoved, got)
+		}
+	}
+}
@@ -0,0 +1,87 @@
+/*
+Copyright 2017 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tiller
+
+import (
+	"strings"
+
+	"github.com/ghodss/yaml"
+)
+
+// customResourceDeletionTimeout is how long, in seconds, custom resources
+// are waited for to be gone before their definition is deleted, if the
+// request sets no timeout.
+const customResourceDeletionTimeout = 300
+
+// crdHead is the part of a CustomResourceDefinition that names the kind it
+// defines.
+type crdHead struct {
+	Spec struct {
+		Group string `json:"group"`
+		Names struct {
+			Kind string `json:"kind"`
+		} `json:"names"`
+	} `json:"spec"`
+}
+
+// definedKinds returns the names of the CustomResourceDefinitions among
+// manifests, by the group and kind they define, e.g. "example.com/Widget".
+func definedKinds(manifests []manifest) map[string]string {
+	kinds := map[string]string{}
+	for _, m := range manifests {
+		if m.head == nil || m.head.Kind != "CustomResourceDefinition" || m.head.Metadata == nil {
+			continue
+		}
+		var crd crdHead
+		if err := yaml.Unmarshal([]byte(m.content), &crd); err != nil || crd.Spec.Names.Kind == "" {
+			continue
+		}
+		kinds[crd.Spec.Group+"/"+crd.Spec.Names.Kind] = m.head.Metadata.Name
+	}
+	return kinds
+}
+
+// definingCRD returns the name of the CustomResourceDefinition in kinds
+// that defines the kind of m, or "" if none does.
+func definingCRD(m manifest, kinds map[string]string) string {
+	if m.head == nil {
+		return ""
+	}
+	i := strings.LastIndex(m.head.Version, "/")
+	if i < 0 {
+		// Custom resources are never in the core group.
+		return ""
+	}
+	return kinds[m.head.Version[:i]+"/"+m.head.Kind]
+}
+
+// customResourcesFirst moves the custom resources in manifests that are
+// defined by kinds to the front, keeping the order of the others.
+func customResourcesFirst(manifests []manifest, kinds map[string]string) []manifest {
+	sorted := make([]manifest, 0, len(manifests))
+	for _, m := range manifests {
+		if definingCRD(m, kinds) != "" {
+			sorted = append(sorted, m)
+		}
+	}
+	for _, m := range manifests {
+		if definingCRD(m, kinds) == "" {
+			sorted = append(sorted, m)
+		}
+	}
+	return sorted
+}
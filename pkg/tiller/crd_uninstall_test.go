@@ -0,0 +1,192 @@
+/*
+Copyright 2017 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tiller
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"k8s.io/helm/pkg/chartutil"
+	"k8s.io/helm/pkg/tiller/environment"
+)
+
+var crdManifest = `---
+# Source: widgets/templates/crd.yaml
+apiVersion: apiextensions.k8s.io/v1beta1
+kind: CustomResourceDefinition
+metadata:
+  name: widgets.example.com
+spec:
+  group: example.com
+  version: v1
+  names:
+    kind: Widget
+    plural: widgets
+---
+# Source: widgets/templates/controller.yaml
+apiVersion: extensions/v1beta1
+kind: Deployment
+metadata:
+  name: widget-controller
+---
+# Source: widgets/templates/widget.yaml
+apiVersion: example.com/v1
+kind: Widget
+metadata:
+  name: blue
+---
+# Source: widgets/templates/monitor.yaml
+apiVersion: monitoring.coreos.com/v1
+kind: ServiceMonitor
+metadata:
+  name: widgets
+---
+# Source: widgets/templates/widget2.yaml
+apiVersion: example.com/v1
+kind: Widget
+metadata:
+  name: green
+`
+
+var crdVersionSet = chartutil.NewVersionSet("v1", "apiextensions.k8s.io/v1beta1", "extensions/v1beta1", "example.com/v1", "monitoring.coreos.com/v1")
+
+// crdDeleteKubeClient records the resources it deletes, and fails to
+// delete those that are stuck. Waiting for a deletion takes delay.
+type crdDeleteKubeClient struct {
+	environment.PrintingKubeClient
+	stuck   map[string]bool
+	deleted []string
+	waits   map[string]int64
+	delay   time.Duration
+}
+
+func (c *crdDeleteKubeClient) DeleteWithPolicy(ns string, r io.Reader, policy string, timeout int64, shouldWait bool) error {
+	b, err := ioutil.ReadAll(r)
+	if err != nil {
+		return err
+	}
+	head := manifestHead(string(b))
+	if head == nil {
+		return fmt.Errorf("unexpected manifest %q", b)
+	}
+	name := head.Kind + "/" + head.Metadata.Name
+	c.deleted = append(c.deleted, name)
+	if shouldWait {
+		c.waits[name] = timeout
+		time.Sleep(c.delay)
+	}
+	if c.stuck[name] {
+		return fmt.Errorf("timed out waiting for %s %q to be deleted", head.Kind, head.Metadata.Name)
+	}
+	return nil
+}
+
+func newCRDDeleteKubeClient(stuck ...string) *crdDeleteKubeClient {
+	c := &crdDeleteKubeClient{stuck: map[string]bool{}, waits: map[string]int64{}}
+	for _, s := range stuck {
+		c.stuck[s] = true
+	}
+	return c
+}
+
+func TestDeleteReleaseCustomResourcesBeforeDefinition(t *testing.T) {
+	kc := newCRDDeleteKubeClient()
+	rel := releaseStub()
+	rel.Manifest = crdManifest
+
+	_, errs := DeleteRelease(rel, crdVersionSet, kc, "", 0, false)
+	if len(errs) != 0 {
+		t.Fatalf("Unexpected errors: %v", errs)
+	}
+
+	// The widgets go first, while their controller still runs, and the
+	// definition goes last. Resources of the same kind are in no particular
+	// order.
+	if len(kc.deleted) > 2 {
+		sort.Strings(kc.deleted[:2])
+	}
+	expect := []string{
+		"Widget/blue",
+		"Widget/green",
+		"Deployment/widget-controller",
+		"ServiceMonitor/widgets",
+		"CustomResourceDefinition/widgets.example.com",
+	}
+	if !reflect.DeepEqual(kc.deleted, expect) {
+		t.Errorf("Expected deletion order %v, got %v", expect, kc.deleted)
+	}
+	// Only the widgets are waited for; the definition of service monitors is
+	// not part of the release.
+	expectWaits := map[string]int64{"Widget/blue": customResourceDeletionTimeout, "Widget/green": customResourceDeletionTimeout}
+	if !reflect.DeepEqual(kc.waits, expectWaits) {
+		t.Errorf("Expected waits %v, got %v", expectWaits, kc.waits)
+	}
+}
+
+func TestDeleteReleaseStuckCustomResource(t *testing.T) {
+	kc := newCRDDeleteKubeClient("Widget/green")
+	rel := releaseStub()
+	rel.Manifest = crdManifest
+
+	_, errs := DeleteRelease(rel, crdVersionSet, kc, "", 60, false)
+	if len(errs) != 2 {
+		t.Fatalf("Expected 2 errors, got %v", errs)
+	}
+	if !strings.Contains(errs[1].Error(), `CustomResourceDefinition "widgets.example.com" was not deleted`) ||
+		!strings.Contains(errs[1].Error(), `Widget "green"`) {
+		t.Errorf("Expected the definition to be reported as kept, got %s", errs[1])
+	}
+	for _, name := range kc.deleted {
+		if name == "CustomResourceDefinition/widgets.example.com" {
+			t.Error("Expected the definition not to be deleted")
+		}
+	}
+	if kc.waits["Widget/blue"] != 60 {
+		t.Errorf("Expected the widgets to be waited for up to the request timeout, got %d", kc.waits["Widget/blue"])
+	}
+}
+
+func TestDeleteReleaseCustomResourcesShareTimeout(t *testing.T) {
+	// The first widget takes the whole timeout to be deleted, which leaves
+	// none to wait for the second.
+	kc := newCRDDeleteKubeClient()
+	kc.delay = 1100 * time.Millisecond
+	rel := releaseStub()
+	rel.Manifest = crdManifest
+
+	_, errs := DeleteRelease(rel, crdVersionSet, kc, "", 1, false)
+	if len(errs) != 1 || !strings.Contains(errs[0].Error(), `CustomResourceDefinition "widgets.example.com" was not deleted`) {
+		t.Fatalf("Expected the definition to be reported as kept, got %v", errs)
+	}
+	if len(kc.waits) != 1 {
+		t.Errorf("Expected only one widget to be waited for, got %v", kc.waits)
+	}
+	for _, name := range kc.deleted {
+		if name == "CustomResourceDefinition/widgets.example.com" {
+			t.Error("Expected the definition not to be deleted")
+		}
+	}
+	if len(kc.deleted) != 4 {
+		t.Errorf("Expected both widgets to be deleted, got %v", kc.deleted)
+	}
+}
@@ -0,0 +1,180 @@
+/*
+Copyright 2017 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tiller
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	ctx "golang.org/x/net/context"
+
+	"k8s.io/helm/pkg/chartutil"
+	"k8s.io/helm/pkg/logging"
+	"k8s.io/helm/pkg/proto/hapi/services"
+	"k8s.io/helm/pkg/tiller/environment"
+	"k8s.io/helm/pkg/version"
+)
+
+// discoveryCache keeps the API versions that clusters serve, so that every
+// operation does not discover them again. Entries are keyed by the cluster
+// and the version of its API server, which is still asked for on every
+// operation: an upgraded server is discovered again right away.
+type discoveryCache struct {
+	// ttl is how long entries are used for. When it is zero, nothing is
+	// cached.
+	ttl time.Duration
+
+	mu      sync.Mutex
+	entries map[string]discoveryCacheEntry
+}
+
+type discoveryCacheEntry struct {
+	versions chartutil.VersionSet
+	expires  time.Time
+}
+
+// discoveryCacheKey is the key of the API versions of the cluster named
+// cluster, whose API server runs gitVersion. Cluster names cannot contain
+// "/".
+func discoveryCacheKey(cluster, gitVersion string) string {
+	return cluster + "/" + gitVersion
+}
+
+// get returns the API versions cached under key, if they have not expired.
+func (d *discoveryCache) get(key string) (chartutil.VersionSet, bool) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	e, ok := d.entries[key]
+	if !ok || time.Now().After(e.expires) {
+		return nil, false
+	}
+	return e.versions, true
+}
+
+// put caches the API versions vs under key for the TTL of the cache.
+func (d *discoveryCache) put(key string, vs chartutil.VersionSet) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.ttl <= 0 {
+		return
+	}
+	if d.entries == nil {
+		d.entries = map[string]discoveryCacheEntry{}
+	}
+	d.entries[key] = discoveryCacheEntry{versions: vs, expires: time.Now().Add(d.ttl)}
+}
+
+// invalidate drops the entries of the cluster named cluster, and returns how
+// many there were.
+func (d *discoveryCache) invalidate(cluster string) int {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	n := 0
+	for key := range d.entries {
+		if strings.HasPrefix(key, cluster+"/") {
+			delete(d.entries, key)
+			n++
+		}
+	}
+	return n
+}
+
+// SetDiscoveryCacheTTL makes the server reuse the API versions it discovered
+// in a cluster for d, instead of discovering them for every operation. Zero
+// disables the cache.
+//
+// The cache of a cluster is invalidated when a release adds
+// CustomResourceDefinitions to it, and with InvalidateDiscoveryCache.
+func (s *ReleaseServer) SetDiscoveryCacheTTL(d time.Duration) {
+	s.discovery.mu.Lock()
+	defer s.discovery.mu.Unlock()
+	s.discovery.ttl = d
+}
+
+// clusterCapabilities returns the capabilities of the cluster named cluster,
+// with the API versions taken from the discovery cache while it has them.
+func (s *ReleaseServer) clusterCapabilities(cluster string, kc *kubeCluster) (*chartutil.Capabilities, error) {
+	disc := kc.clientset.Discovery()
+	sv, err := disc.ServerVersion()
+	if err != nil {
+		return nil, err
+	}
+	key := discoveryCacheKey(cluster, sv.GitVersion)
+	vs, ok := s.discovery.get(key)
+	if !ok {
+		if vs, err = GetVersionSet(disc); err != nil {
+			return nil, fmt.Errorf("Could not get apiVersions from Kubernetes: %s", err)
+		}
+		s.discovery.put(key, vs)
+	}
+	return &chartutil.Capabilities{
+		APIVersions:   vs,
+		KubeVersion:   sv,
+		TillerVersion: version.GetVersionProto(),
+		HelmVersion:   chartutil.NewHelmVersion(version.GetVersionProto()),
+	}, nil
+}
+
+// invalidateDiscovery forgets what is known about the API of the cluster
+// named cluster, both in the discovery cache and in the cache of its
+// Kubernetes client, so that the kinds added since can be rendered and
+// applied.
+func (s *ReleaseServer) invalidateDiscovery(log logging.Logger, cluster string, kubeCli environment.KubeClient) (int, error) {
+	n := s.discovery.invalidate(cluster)
+	if err := kubeCli.InvalidateDiscovery(); err != nil {
+		log.Warnf("Could not discover the API of %s again: %s", describeCluster(cluster), err)
+		return n, err
+	}
+	log.Infof("Invalidated the API discovery of %s", describeCluster(cluster))
+	return n, nil
+}
+
+// hasCRDs reports whether manifest has a CustomResourceDefinition.
+func hasCRDs(manifest string) bool {
+	for _, doc := range sortedManifests(manifest) {
+		if head := manifestHead(doc); head != nil && head.Kind == "CustomResourceDefinition" {
+			return true
+		}
+	}
+	return false
+}
+
+// afterApply invalidates the API discovery of the cluster named cluster if
+// manifest, which was just applied to it, has CustomResourceDefinitions, so
+// that the hooks and operations that follow see their kinds.
+func (s *ReleaseServer) afterApply(log logging.Logger, cluster string, kubeCli environment.KubeClient, manifest string) {
+	if hasCRDs(manifest) {
+		s.invalidateDiscovery(log, cluster, kubeCli)
+	}
+}
+
+// InvalidateDiscoveryCache makes the server discover the API of a cluster
+// again, e.g. after CustomResourceDefinitions were created outside of Helm.
+func (s *ReleaseServer) InvalidateDiscoveryCache(c ctx.Context, req *services.InvalidateDiscoveryCacheRequest) (*services.InvalidateDiscoveryCacheResponse, error) {
+	kc, err := s.cluster(req.Cluster)
+	if err != nil {
+		return nil, err
+	}
+	log := s.requestLogger("invalidate-discovery", "", 0)
+	n, err := s.invalidateDiscovery(log, req.Cluster, kc.env.KubeClient)
+	if err != nil {
+		return nil, err
+	}
+	return &services.InvalidateDiscoveryCacheResponse{Entries: int32(n)}, nil
+}
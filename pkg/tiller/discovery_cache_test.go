@@ -0,0 +1,195 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tiller
+
+import (
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"k8s.io/helm/pkg/chartutil"
+	"k8s.io/helm/pkg/helm"
+	"k8s.io/helm/pkg/proto/hapi/chart"
+	"k8s.io/helm/pkg/proto/hapi/services"
+	"k8s.io/helm/pkg/tiller/environment"
+)
+
+func TestDiscoveryCache(t *testing.T) {
+	var d discoveryCache
+	d.put("/v1.7.0", chartutil.DefaultVersionSet)
+	if _, ok := d.get("/v1.7.0"); ok {
+		t.Error("Expected nothing to be cached without a TTL")
+	}
+
+	d.ttl = time.Minute
+	d.put("/v1.7.0", chartutil.DefaultVersionSet)
+	d.put("eu-west/v1.7.0", chartutil.DefaultVersionSet)
+	d.put("eu-west/v1.8.0", chartutil.DefaultVersionSet)
+	if _, ok := d.get("/v1.7.0"); !ok {
+		t.Error("Expected the versions to be cached")
+	}
+	if _, ok := d.get("/v1.8.0"); ok {
+		t.Error("Expected another server version to miss the cache")
+	}
+
+	if n := d.invalidate("eu-west"); n != 2 {
+		t.Errorf("Expected 2 entries to be dropped, got %d", n)
+	}
+	if _, ok := d.get("/v1.7.0"); !ok {
+		t.Error("Expected the entries of other clusters to be kept")
+	}
+
+	d.entries["/v1.7.0"] = discoveryCacheEntry{versions: chartutil.DefaultVersionSet, expires: time.Now().Add(-time.Second)}
+	if _, ok := d.get("/v1.7.0"); ok {
+		t.Error("Expected an expired entry to miss the cache")
+	}
+}
+
+// discoveryKubeClient counts the invalidations of its discovery.
+type discoveryKubeClient struct {
+	environment.PrintingKubeClient
+	invalidations int
+}
+
+func (d *discoveryKubeClient) InvalidateDiscovery() error {
+	d.invalidations++
+	return nil
+}
+
+var manifestWithCRD = `apiVersion: apiextensions.k8s.io/v1beta1
+kind: CustomResourceDefinition
+metadata:
+  name: crontabs.stable.example.com
+spec:
+  group: stable.example.com
+  version: v1
+  names:
+    kind: CronTab
+    plural: crontabs
+  scope: Namespaced
+`
+
+func TestClusterCapabilities_DiscoveryCache(t *testing.T) {
+	rs := rsFixture()
+	kc := &discoveryKubeClient{PrintingKubeClient: environment.PrintingKubeClient{Out: os.Stdout}}
+	rs.env.KubeClient = kc
+	rs.SetDiscoveryCacheTTL(time.Minute)
+	cluster, _ := rs.cluster("")
+
+	if _, err := rs.clusterCapabilities("", cluster); err != nil {
+		t.Fatal(err)
+	}
+	if len(rs.discovery.entries) != 1 {
+		t.Fatalf("Expected the versions to be cached, got %v", rs.discovery.entries)
+	}
+	// Make the cached versions tell themselves apart from discovered ones.
+	for key, e := range rs.discovery.entries {
+		e.versions = chartutil.NewVersionSet("v1", "apiextensions.k8s.io/v1beta1", "stable.example.com/v1")
+		rs.discovery.entries[key] = e
+	}
+	caps, err := rs.clusterCapabilities("", cluster)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !caps.APIVersions.Has("stable.example.com/v1") {
+		t.Errorf("Expected the cached versions to be used, got %v", caps.APIVersions)
+	}
+
+	// Installing a release without CustomResourceDefinitions keeps the cache.
+	req := &services.InstallReleaseRequest{
+		Namespace: "spaced",
+		Chart: &chart.Chart{
+			Metadata:  &chart.Metadata{Name: "hello"},
+			Templates: []*chart.Template{{Name: "templates/hello", Data: []byte("hello: world")}},
+		},
+	}
+	if _, err := rs.InstallRelease(helm.NewContext(), req); err != nil {
+		t.Fatalf("Failed install: %s", err)
+	}
+	if len(rs.discovery.entries) != 1 || kc.invalidations != 0 {
+		t.Errorf("Expected the cache to be kept, got %v and %d invalidations", rs.discovery.entries, kc.invalidations)
+	}
+
+	req.Chart.Templates = append(req.Chart.Templates, &chart.Template{Name: "templates/crd", Data: []byte(manifestWithCRD)})
+	if _, err := rs.InstallRelease(helm.NewContext(), req); err != nil {
+		t.Fatalf("Failed install: %s", err)
+	}
+	if len(rs.discovery.entries) != 0 || kc.invalidations != 1 {
+		t.Errorf("Expected the CustomResourceDefinition to invalidate the cache, got %v and %d invalidations", rs.discovery.entries, kc.invalidations)
+	}
+}
+
+// cacheVersions caches versions as those of Tiller's own cluster in rs.
+func cacheVersions(t *testing.T, rs *ReleaseServer, versions ...string) {
+	sv, err := rs.clientset.Discovery().ServerVersion()
+	if err != nil {
+		t.Fatal(err)
+	}
+	rs.SetDiscoveryCacheTTL(time.Minute)
+	rs.discovery.put(discoveryCacheKey("", sv.GitVersion), chartutil.NewVersionSet(versions...))
+}
+
+func TestExecHook_CRDInvalidatesDiscovery(t *testing.T) {
+	rs := rsFixture()
+	kc := &discoveryKubeClient{PrintingKubeClient: environment.PrintingKubeClient{Out: os.Stdout}}
+	rs.env.KubeClient = kc
+	cacheVersions(t, rs, "v1", "apiextensions.k8s.io/v1beta1")
+
+	req := &services.InstallReleaseRequest{
+		Namespace: "spaced",
+		Chart: &chart.Chart{
+			Metadata: &chart.Metadata{Name: "hello"},
+			Templates: []*chart.Template{
+				{Name: "templates/crd", Data: []byte(strings.Replace(manifestWithCRD, "metadata:\n", "metadata:\n  annotations:\n    \"helm.sh/hook\": pre-install\n", 1))},
+				{Name: "templates/hello", Data: []byte("hello: world")},
+			},
+		},
+	}
+	res, err := rs.InstallRelease(helm.NewContext(), req)
+	if err != nil {
+		t.Fatalf("Failed install: %s", err)
+	}
+	if len(res.Release.Hooks) != 1 {
+		t.Fatalf("Expected the CustomResourceDefinition to be a hook, got %d hooks", len(res.Release.Hooks))
+	}
+	// The pre-install hook invalidates the discovery before the manifest is
+	// applied, which has no CustomResourceDefinitions of its own.
+	if kc.invalidations != 1 || len(rs.discovery.entries) != 0 {
+		t.Errorf("Expected 1 invalidation, got %d and %v", kc.invalidations, rs.discovery.entries)
+	}
+}
+
+func TestInvalidateDiscoveryCache(t *testing.T) {
+	rs := rsFixture()
+	kc := &discoveryKubeClient{PrintingKubeClient: environment.PrintingKubeClient{Out: os.Stdout}}
+	rs.env.KubeClient = kc
+	rs.SetDiscoveryCacheTTL(time.Minute)
+	rs.discovery.put(discoveryCacheKey("", "v1.7.0"), chartutil.DefaultVersionSet)
+
+	res, err := rs.InvalidateDiscoveryCache(helm.NewContext(), &services.InvalidateDiscoveryCacheRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Entries != 1 || kc.invalidations != 1 {
+		t.Errorf("Expected 1 entry to be dropped and the client invalidated, got %d and %d invalidations", res.Entries, kc.invalidations)
+	}
+
+	if _, err := rs.InvalidateDiscoveryCache(helm.NewContext(), &services.InvalidateDiscoveryCacheRequest{Cluster: "eu-west"}); err == nil {
+		t.Error("Expected an unknown cluster to be rejected")
+	}
+}
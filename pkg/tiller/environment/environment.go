@@ -23,6 +23,7 @@ These dependencies are expressed as interfaces so that alternate implementations
 package environment
 
 import (
+	"bytes"
 	"io"
 	"os"
 	"time"
@@ -104,6 +105,16 @@ type Engine interface {
 	Render(*chart.Chart, chartutil.Values) (map[string]string, error)
 }
 
+// GeneratingEngine is an Engine whose templates can generate values, such as
+// random passwords, that are stored with the release so that upgrades reuse
+// them instead of generating new ones.
+type GeneratingEngine interface {
+	Engine
+	// RenderGenerated renders a chart like Render. It reuses the values in
+	// generated, and updates generated to hold the values the templates used.
+	RenderGenerated(*chart.Chart, chartutil.Values, map[string]string) (map[string]string, error)
+}
+
 // KubeClient represents a client capable of communicating with the Kubernetes API.
 //
 // A KubeClient must be concurrency safe.
@@ -133,6 +144,17 @@ type KubeClient interface {
 	// by "\n---\n").
 	Delete(namespace string, reader io.Reader) error
 
+	// DeleteWithPolicy destroys one or more resources using a deletion
+	// propagation policy ("Foreground", "Background" or "Orphan"). An empty
+	// policy deletes them as Delete does. If shouldWait is set, it waits up to
+	// timeout seconds for the resources to be gone.
+	//
+	// namespace must contain a valid existing namespace.
+	//
+	// reader must contain a YAML stream (one or more YAML documents separated
+	// by "\n---\n").
+	DeleteWithPolicy(namespace string, reader io.Reader, policy string, timeout int64, shouldWait bool) error
+
 	// Watch the resource in reader until it is "ready".
 	//
 	// For Jobs, "ready" means the job ran to completion (excited without error).
@@ -149,6 +171,54 @@ type KubeClient interface {
 	// by "\n---\n").
 	Update(namespace string, originalReader, modifiedReader io.Reader, force bool, recreate bool, timeout int64, shouldWait bool) error
 
+	// UpdateWithOptions updates resources as Update does. The grace period
+	// and propagation policy of opts apply to the resources and pods that
+	// the update deletes.
+	UpdateWithOptions(namespace string, originalReader, modifiedReader io.Reader, opts kube.UpdateOptions) error
+
+	// DryRun sends one or more resources to the API server as a server-side
+	// dry-run and returns the objects, with server defaults applied, as a YAML
+	// stream. Nothing is persisted.
+	//
+	// namespace must contain a valid existing namespace.
+	//
+	// reader must contain a YAML stream (one or more YAML documents separated
+	// by "\n---\n").
+	DryRun(namespace string, reader io.Reader) (string, error)
+
+	// Restart restarts the pods of one or more Deployments, StatefulSets and
+	// DaemonSets, as "kubectl rollout restart" does. If shouldWait is set, it
+	// waits up to timeout seconds for the workloads to be ready again.
+	//
+	// namespace must contain a valid existing namespace.
+	//
+	// reader must contain a YAML stream (one or more YAML documents separated
+	// by "\n---\n").
+	Restart(namespace string, reader io.Reader, timeout int64, shouldWait bool) error
+
+	// Drift compares one or more resources to their live counterparts,
+	// without changing anything. See kube.Client.Drift.
+	//
+	// namespace must contain a valid existing namespace.
+	//
+	// reader must contain a YAML stream (one or more YAML documents separated
+	// by "\n---\n").
+	Drift(namespace string, reader io.Reader) ([]kube.ResourceDrift, error)
+
+	// LiveObjects gets the live state of one or more resources, keyed by
+	// "Kind/name". See kube.Client.LiveObjects.
+	//
+	// namespace must contain a valid existing namespace.
+	//
+	// reader must contain a YAML stream (one or more YAML documents separated
+	// by "\n---\n").
+	LiveObjects(namespace string, reader io.Reader) (map[string]interface{}, error)
+
+	// InvalidateDiscovery makes the client discover the API of the server
+	// again, so that it can build resources of kinds added since it last
+	// did. See kube.Client.InvalidateDiscovery.
+	InvalidateDiscovery() error
+
 	Build(namespace string, reader io.Reader) (kube.Result, error)
 	BuildUnstructured(namespace string, reader io.Reader) (kube.Result, error)
 
@@ -183,6 +253,14 @@ func (p *PrintingKubeClient) Delete(ns string, r io.Reader) error {
 	return err
 }
 
+// DeleteWithPolicy implements KubeClient DeleteWithPolicy.
+//
+// It only prints out the content to be deleted.
+func (p *PrintingKubeClient) DeleteWithPolicy(ns string, r io.Reader, policy string, timeout int64, shouldWait bool) error {
+	_, err := io.Copy(p.Out, r)
+	return err
+}
+
 // WatchUntilReady implements KubeClient WatchUntilReady.
 func (p *PrintingKubeClient) WatchUntilReady(ns string, r io.Reader, timeout int64, shouldWait bool) error {
 	_, err := io.Copy(p.Out, r)
@@ -195,6 +273,50 @@ func (p *PrintingKubeClient) Update(ns string, currentReader, modifiedReader io.
 	return err
 }
 
+// UpdateWithOptions implements KubeClient UpdateWithOptions.
+func (p *PrintingKubeClient) UpdateWithOptions(ns string, currentReader, modifiedReader io.Reader, opts kube.UpdateOptions) error {
+	_, err := io.Copy(p.Out, modifiedReader)
+	return err
+}
+
+// DryRun implements KubeClient DryRun.
+//
+// It prints the resources and returns them unchanged.
+func (p *PrintingKubeClient) DryRun(ns string, r io.Reader) (string, error) {
+	b := bytes.NewBuffer(nil)
+	_, err := io.Copy(io.MultiWriter(p.Out, b), r)
+	return b.String(), err
+}
+
+// Restart implements KubeClient Restart.
+//
+// It only prints out the workloads to be restarted.
+func (p *PrintingKubeClient) Restart(ns string, r io.Reader, timeout int64, shouldWait bool) error {
+	_, err := io.Copy(p.Out, r)
+	return err
+}
+
+// Drift implements KubeClient Drift.
+//
+// It prints the resources and reports no drift.
+func (p *PrintingKubeClient) Drift(ns string, r io.Reader) ([]kube.ResourceDrift, error) {
+	_, err := io.Copy(p.Out, r)
+	return []kube.ResourceDrift{}, err
+}
+
+// LiveObjects implements KubeClient LiveObjects.
+//
+// It prints the resources and finds none of them.
+func (p *PrintingKubeClient) LiveObjects(ns string, r io.Reader) (map[string]interface{}, error) {
+	_, err := io.Copy(p.Out, r)
+	return map[string]interface{}{}, err
+}
+
+// InvalidateDiscovery implements KubeClient InvalidateDiscovery.
+func (p *PrintingKubeClient) InvalidateDiscovery() error {
+	return nil
+}
+
 // Build implements KubeClient Build.
 func (p *PrintingKubeClient) Build(ns string, reader io.Reader) (kube.Result, error) {
 	return []*resource.Info{}, nil
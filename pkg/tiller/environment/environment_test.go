@@ -48,12 +48,33 @@ func (k *mockKubeClient) Get(ns string, r io.Reader) (string, error) {
 func (k *mockKubeClient) Delete(ns string, r io.Reader) error {
 	return nil
 }
+func (k *mockKubeClient) DeleteWithPolicy(ns string, r io.Reader, policy string, timeout int64, shouldWait bool) error {
+	return nil
+}
 func (k *mockKubeClient) Update(ns string, currentReader, modifiedReader io.Reader, force bool, recreate bool, timeout int64, shouldWait bool) error {
 	return nil
 }
+func (k *mockKubeClient) UpdateWithOptions(ns string, currentReader, modifiedReader io.Reader, opts kube.UpdateOptions) error {
+	return nil
+}
 func (k *mockKubeClient) WatchUntilReady(ns string, r io.Reader, timeout int64, shouldWait bool) error {
 	return nil
 }
+func (k *mockKubeClient) DryRun(ns string, r io.Reader) (string, error) {
+	return "", nil
+}
+func (k *mockKubeClient) Restart(ns string, r io.Reader, timeout int64, shouldWait bool) error {
+	return nil
+}
+func (k *mockKubeClient) Drift(ns string, r io.Reader) ([]kube.ResourceDrift, error) {
+	return []kube.ResourceDrift{}, nil
+}
+func (k *mockKubeClient) LiveObjects(ns string, r io.Reader) (map[string]interface{}, error) {
+	return map[string]interface{}{}, nil
+}
+func (k *mockKubeClient) InvalidateDiscovery() error {
+	return nil
+}
 func (k *mockKubeClient) Build(ns string, reader io.Reader) (kube.Result, error) {
 	return []*resource.Info{}, nil
 }
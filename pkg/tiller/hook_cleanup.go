@@ -0,0 +1,87 @@
+/*
+Copyright 2017 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tiller
+
+import (
+	"bytes"
+	"fmt"
+
+	"k8s.io/helm/pkg/logging"
+	"k8s.io/helm/pkg/proto/hapi/release"
+	"k8s.io/helm/pkg/tiller/environment"
+)
+
+// deleteHookResources deletes the resources that the hooks of rels created
+// and that are still there. Hooks are resources of the release that its
+// manifest does not list, so they are found through the hooks that the
+// revisions record; a hook shared by several revisions is deleted once.
+// Hook resources with the keep resource policy are left alone.
+//
+// It returns a summary of what was deleted and kept, and the errors of the
+// resources that could not be deleted.
+func deleteHookResources(log logging.Logger, kc environment.KubeClient, rels []*release.Release) (string, []error) {
+	var deleted, kept []string
+	var errs []error
+	seen := map[string]bool{}
+	// The newest revision comes first, so that a hook is deleted with the
+	// manifest it was last created from.
+	for i := len(rels) - 1; i >= 0; i-- {
+		rel := rels[i]
+		for _, h := range rel.Hooks {
+			head := manifestHead(h.Manifest)
+			if head == nil || seen[resourceKey(head, rel.Namespace)] {
+				continue
+			}
+			seen[resourceKey(head, rel.Namespace)] = true
+			desc := "[" + head.Kind + "] " + head.Metadata.Name
+			if hasKeepPolicy(head) {
+				kept = append(kept, desc)
+				continue
+			}
+
+			drift, err := kc.Drift(rel.Namespace, bytes.NewBufferString(h.Manifest))
+			if err != nil {
+				errs = append(errs, fmt.Errorf("could not look up the resource of hook %s: %s", h.Name, err))
+				continue
+			}
+			if len(drift) == 0 || drift[0].Missing {
+				continue
+			}
+			if err := kc.Delete(rel.Namespace, bytes.NewBufferString(h.Manifest)); err != nil {
+				errs = append(errs, fmt.Errorf("could not delete the resource of hook %s: %s", h.Name, err))
+				continue
+			}
+			log.Infof("Deleted the resource of hook %s", h.Name)
+			deleted = append(deleted, desc)
+		}
+	}
+
+	var summary bytes.Buffer
+	if len(deleted) > 0 {
+		summary.WriteString("These hook resources were deleted:\n")
+		for _, d := range deleted {
+			summary.WriteString(d + "\n")
+		}
+	}
+	if len(kept) > 0 {
+		summary.WriteString("These hook resources were kept due to the resource policy:\n")
+		for _, k := range kept {
+			summary.WriteString(k + "\n")
+		}
+	}
+	return summary.String(), errs
+}
@@ -0,0 +1,69 @@
+/*
+Copyright 2017 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tiller
+
+import (
+	"fmt"
+	"strings"
+
+	"k8s.io/helm/pkg/logging"
+)
+
+// HookOperations are the operations whose hooks can be skipped by default.
+var HookOperations = []string{"install", "upgrade", "rollback", "uninstall"}
+
+// DisableHooksByDefault makes the server skip the hooks of operations, which
+// must be among HookOperations, unless a request enables them. Requests may
+// still disable the hooks of the other operations.
+func (s *ReleaseServer) DisableHooksByDefault(operations []string) error {
+	disabled := make(map[string]bool, len(operations))
+	for _, op := range operations {
+		if !isHookOperation(op) {
+			return fmt.Errorf("cannot disable hooks for %q, expected one of %s", op, strings.Join(HookOperations, ", "))
+		}
+		disabled[op] = true
+	}
+	s.hooksDisabled = disabled
+	return nil
+}
+
+func isHookOperation(op string) bool {
+	for _, o := range HookOperations {
+		if o == op {
+			return true
+		}
+	}
+	return false
+}
+
+// runHooks decides whether the hooks of an operation run, given whether the
+// request disables or enables them, and logs the decision.
+func (s *ReleaseServer) runHooks(log logging.Logger, operation string, disable, enable bool) bool {
+	switch {
+	case disable:
+		log.Infof("Skipping %s hooks, the request disables them", operation)
+		return false
+	case enable && s.hooksDisabled[operation]:
+		log.Infof("Running %s hooks, the request enables them despite the server default", operation)
+		return true
+	case s.hooksDisabled[operation]:
+		log.Infof("Skipping %s hooks, the server disables them by default", operation)
+		return false
+	}
+	log.Infof("Running %s hooks", operation)
+	return true
+}
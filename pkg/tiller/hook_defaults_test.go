@@ -0,0 +1,106 @@
+/*
+Copyright 2017 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tiller
+
+import (
+	"testing"
+
+	"k8s.io/helm/pkg/helm"
+	"k8s.io/helm/pkg/logging"
+	"k8s.io/helm/pkg/proto/hapi/services"
+)
+
+func TestDisableHooksByDefault(t *testing.T) {
+	rs := rsFixture()
+	if err := rs.DisableHooksByDefault([]string{"rollback", "uninstall"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := rs.DisableHooksByDefault([]string{"rollback", "restart"}); err == nil {
+		t.Error("Expected an error for an unknown operation")
+	}
+	// A failed call keeps the previous defaults.
+	if !rs.hooksDisabled["uninstall"] {
+		t.Error("Expected uninstall hooks to stay disabled")
+	}
+}
+
+func TestRunHooks(t *testing.T) {
+	rs := rsFixture()
+	if err := rs.DisableHooksByDefault([]string{"rollback"}); err != nil {
+		t.Fatal(err)
+	}
+	log := logging.Discard()
+
+	tests := []struct {
+		operation       string
+		disable, enable bool
+		expect          bool
+	}{
+		{"install", false, false, true},
+		{"install", true, false, false},
+		{"install", false, true, true},
+		{"rollback", false, false, false},
+		{"rollback", false, true, true},
+		{"rollback", true, true, false},
+	}
+	for _, tt := range tests {
+		if got := rs.runHooks(log, tt.operation, tt.disable, tt.enable); got != tt.expect {
+			t.Errorf("%s (disable %t, enable %t): expected %t, got %t", tt.operation, tt.disable, tt.enable, tt.expect, got)
+		}
+	}
+}
+
+func TestInstallReleaseHooksDisabledByDefault(t *testing.T) {
+	c := helm.NewContext()
+	rs := rsFixture()
+	if err := rs.DisableHooksByDefault([]string{"install"}); err != nil {
+		t.Fatal(err)
+	}
+
+	res, err := rs.InstallRelease(c, &services.InstallReleaseRequest{Chart: chartStub()})
+	if err != nil {
+		t.Fatalf("Failed install: %s", err)
+	}
+	if hl := res.Release.Hooks[0].LastRun; hl != nil {
+		t.Errorf("Expected that no hooks were run. Got %d", hl)
+	}
+
+	res, err = rs.InstallRelease(c, &services.InstallReleaseRequest{Chart: chartStub(), EnableHooks: true})
+	if err != nil {
+		t.Fatalf("Failed install: %s", err)
+	}
+	if res.Release.Hooks[0].LastRun == nil {
+		t.Error("Expected the hooks enabled by the request to run")
+	}
+}
+
+func TestUninstallReleaseHooksDisabledByDefault(t *testing.T) {
+	c := helm.NewContext()
+	rs := rsFixture()
+	rs.env.Releases.Create(releaseStub())
+	if err := rs.DisableHooksByDefault([]string{"uninstall"}); err != nil {
+		t.Fatal(err)
+	}
+
+	res, err := rs.UninstallRelease(c, &services.UninstallReleaseRequest{Name: "angry-panda"})
+	if err != nil {
+		t.Fatalf("Failed uninstall: %s", err)
+	}
+	if res.Release.Hooks[0].LastRun != nil {
+		t.Errorf("Expected LastRun to be zero, got %d.", res.Release.Hooks[0].LastRun.Seconds)
+	}
+}
@@ -0,0 +1,121 @@
+/*
+Copyright 2017 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tiller
+
+import (
+	"bytes"
+	"strings"
+
+	"github.com/ghodss/yaml"
+
+	"k8s.io/helm/pkg/hooks"
+	"k8s.io/helm/pkg/logging"
+	"k8s.io/helm/pkg/proto/hapi/release"
+	"k8s.io/helm/pkg/proto/hapi/services"
+	util "k8s.io/helm/pkg/releaseutil"
+	"k8s.io/helm/pkg/tiller/environment"
+)
+
+// hooksFor returns the hooks in hs that fire on code, in the order they run.
+func hooksFor(hs []*release.Hook, code release.Hook_Event) []*release.Hook {
+	matching := []*release.Hook{}
+	for _, h := range hs {
+		// Releases stored before events were deduplicated may list an event
+		// twice; the hook still runs once.
+		if hasEvent(h, code) {
+			matching = append(matching, h)
+		}
+	}
+	return sortByHookWeight(matching)
+}
+
+// previewHooks describes the hooks in hs that would run for each of the
+// named hook events, in the order they would run. Nothing is executed.
+func previewHooks(hs []*release.Hook, skip hookSkipList, hookNames ...string) []*services.HookPreview {
+	var previews []*services.HookPreview
+	for _, name := range hookNames {
+		for _, h := range hooksFor(hs, events[name]) {
+			previews = append(previews, &services.HookPreview{
+				Name:           h.Name,
+				Kind:           h.Kind,
+				Path:           h.Path,
+				Event:          name,
+				Weight:         h.Weight,
+				DeletePolicies: hookDeletePolicies(h),
+				Skipped:        skip.skips(h),
+				Destructive:    isDestructiveHook(h),
+			})
+		}
+	}
+	return previews
+}
+
+// hookDeletePolicies returns the delete policies declared on h.
+func hookDeletePolicies(h *release.Hook) []string {
+	var head util.SimpleHead
+	if err := yaml.Unmarshal([]byte(h.Manifest), &head); err != nil || head.Metadata == nil {
+		return nil
+	}
+	var policies []string
+	for _, p := range strings.Split(head.Metadata.Annotations[hooks.HookDeleteAnno], ",") {
+		if p = strings.TrimSpace(p); p != "" {
+			policies = append(policies, p)
+		}
+	}
+	return policies
+}
+
+// hasDeletePolicy reports whether h declares the delete policy.
+func hasDeletePolicy(h *release.Hook, policy string) bool {
+	for _, p := range hookDeletePolicies(h) {
+		if p == policy {
+			return true
+		}
+	}
+	return false
+}
+
+// lookUpReplacedHooks marks the previews of hooks with the
+// before-hook-creation delete policy whose resources exist already, and
+// would be deleted before the hook is created. It only reads from the
+// cluster; a hook whose resource cannot be looked up is not marked.
+func lookUpReplacedHooks(log logging.Logger, kc environment.KubeClient, namespace string, hs []*release.Hook, previews []*services.HookPreview) {
+	previewed := map[string]bool{}
+	for _, p := range previews {
+		if !p.Skipped {
+			previewed[p.Kind+"/"+p.Name] = true
+		}
+	}
+
+	exists := map[string]bool{}
+	for _, h := range hs {
+		key := h.Kind + "/" + h.Name
+		if _, seen := exists[key]; seen || !previewed[key] || !hasDeletePolicy(h, hooks.BeforeHookCreation) {
+			continue
+		}
+		drift, err := kc.Drift(namespace, bytes.NewBufferString(h.Manifest))
+		if err != nil {
+			log.Warnf("Could not look up the existing resource of hook %s: %s", h.Name, err)
+			continue
+		}
+		exists[key] = len(drift) > 0 && !drift[0].Missing
+	}
+
+	for _, p := range previews {
+		p.ReplacesExisting = !p.Skipped && exists[p.Kind+"/"+p.Name]
+	}
+}
@@ -0,0 +1,70 @@
+/*
+Copyright 2017 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tiller
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ghodss/yaml"
+
+	"k8s.io/helm/pkg/hooks"
+	"k8s.io/helm/pkg/proto/hapi/release"
+	util "k8s.io/helm/pkg/releaseutil"
+)
+
+// hookRetry is how often a failing hook is run, and how long to wait between
+// runs.
+type hookRetry struct {
+	attempts int
+	delay    time.Duration
+}
+
+// noHookRetry runs a hook once.
+var noHookRetry = hookRetry{attempts: 1}
+
+// parseHookRetry parses the value of a helm.sh/hook-retry annotation. It is
+// the maximum number of attempts, optionally followed by the delay between
+// attempts, e.g. "3" or "3,10s". An empty value means a single attempt.
+func parseHookRetry(v string) (hookRetry, error) {
+	if strings.TrimSpace(v) == "" {
+		return noHookRetry, nil
+	}
+	parts := strings.SplitN(v, ",", 2)
+	attempts, err := strconv.Atoi(strings.TrimSpace(parts[0]))
+	if err != nil || attempts < 1 {
+		return noHookRetry, fmt.Errorf("invalid %s %q: attempts must be a positive number", hooks.HookRetryAnno, v)
+	}
+	r := hookRetry{attempts: attempts}
+	if len(parts) == 2 {
+		if r.delay, err = time.ParseDuration(strings.TrimSpace(parts[1])); err != nil || r.delay < 0 {
+			return noHookRetry, fmt.Errorf("invalid %s %q: delay must be a duration such as 10s", hooks.HookRetryAnno, v)
+		}
+	}
+	return r, nil
+}
+
+// hookRetryPolicy returns the retry policy declared on h.
+func hookRetryPolicy(h *release.Hook) (hookRetry, error) {
+	var head util.SimpleHead
+	if err := yaml.Unmarshal([]byte(h.Manifest), &head); err != nil || head.Metadata == nil {
+		return noHookRetry, nil
+	}
+	return parseHookRetry(head.Metadata.Annotations[hooks.HookRetryAnno])
+}
@@ -0,0 +1,176 @@
+/*
+Copyright 2017 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tiller
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"k8s.io/helm/pkg/chartutil"
+	"k8s.io/helm/pkg/hooks"
+	"k8s.io/helm/pkg/logging"
+	"k8s.io/helm/pkg/proto/hapi/release"
+	"k8s.io/helm/pkg/tiller/environment"
+)
+
+func TestParseHookRetry(t *testing.T) {
+	tests := []struct {
+		value  string
+		expect hookRetry
+		err    bool
+	}{
+		{"", hookRetry{attempts: 1}, false},
+		{"3", hookRetry{attempts: 3}, false},
+		{" 3 , 10s ", hookRetry{attempts: 3, delay: 10 * time.Second}, false},
+		{"5,500ms", hookRetry{attempts: 5, delay: 500 * time.Millisecond}, false},
+		{"0", noHookRetry, true},
+		{"three", noHookRetry, true},
+		{"3,soon", noHookRetry, true},
+		{"3,-1s", noHookRetry, true},
+	}
+	for _, tt := range tests {
+		got, err := parseHookRetry(tt.value)
+		if (err != nil) != tt.err {
+			t.Errorf("%q: expected error %t, got %v", tt.value, tt.err, err)
+		}
+		if got != tt.expect {
+			t.Errorf("%q: expected %+v, got %+v", tt.value, tt.expect, got)
+		}
+	}
+}
+
+// flakyHookKubeClient fails to watch a hook the first failures times it is
+// created.
+type flakyHookKubeClient struct {
+	environment.PrintingKubeClient
+	failures int
+	creates  int
+	deletes  int
+}
+
+func (f *flakyHookKubeClient) Create(ns string, r io.Reader, timeout int64, shouldWait bool) error {
+	f.creates++
+	return nil
+}
+
+func (f *flakyHookKubeClient) Delete(ns string, r io.Reader) error {
+	f.deletes++
+	return nil
+}
+
+func (f *flakyHookKubeClient) WatchUntilReady(ns string, r io.Reader, timeout int64, shouldWait bool) error {
+	if f.creates <= f.failures {
+		return errors.New("connection refused")
+	}
+	return nil
+}
+
+func retryHook(retry string) *release.Hook {
+	manifest := "kind: Job\nmetadata:\n  name: migrate\n  annotations:\n    helm.sh/hook: pre-install\n"
+	if retry != "" {
+		manifest += "    helm.sh/hook-retry: \"" + retry + "\"\n"
+	}
+	return &release.Hook{
+		Name:     "migrate",
+		Kind:     "Job",
+		Path:     "templates/migrate.yaml",
+		Manifest: manifest,
+		Events:   []release.Hook_Event{release.Hook_PRE_INSTALL},
+	}
+}
+
+func TestExecHookRetry(t *testing.T) {
+	tests := []struct {
+		name     string
+		retry    string
+		failures int
+		creates  int
+		err      string
+	}{
+		{"no retry", "", 1, 1, "connection refused"},
+		{"succeeds on retry", "3,1ms", 2, 3, ""},
+		{"gives up", "2", 5, 2, "pre-install hook migrate failed after 2 attempts: connection refused"},
+	}
+	for _, tt := range tests {
+		rs := rsFixture()
+		kc := &flakyHookKubeClient{PrintingKubeClient: environment.PrintingKubeClient{Out: os.Stdout}, failures: tt.failures}
+		rs.env.KubeClient = kc
+		var out bytes.Buffer
+		rs.SetLogger(logging.New(&out, logging.TextFormat, logging.InfoLevel))
+
+		h := retryHook(tt.retry)
+		rel := &release.Release{Name: "flaky", Namespace: "default", Hooks: []*release.Hook{h}}
+		err := rs.execHook(rs.requestLogger("install", "flaky", 1), rs.env.KubeClient, rel, hooks.PreInstall, 0, newHookSkipList(nil, nil))
+		if tt.err == "" && err != nil {
+			t.Errorf("%s: unexpected error %s", tt.name, err)
+		}
+		if tt.err != "" && (err == nil || err.Error() != tt.err) {
+			t.Errorf("%s: expected error %q, got %v", tt.name, tt.err, err)
+		}
+		if kc.creates != tt.creates || kc.deletes != tt.creates-1 {
+			t.Errorf("%s: expected %d creates and %d deletes, got %d and %d", tt.name, tt.creates, tt.creates-1, kc.creates, kc.deletes)
+		}
+		if (h.LastRun != nil) != (tt.err == "") {
+			t.Errorf("%s: expected the last run to be recorded only on success", tt.name)
+		}
+		if tt.retry != "" && !strings.Contains(out.String(), "attempt=2") {
+			t.Errorf("%s: expected the attempts to be logged, got %s", tt.name, out.String())
+		}
+		failed := rel.HookExecutions[0].FailedAttempts
+		if len(failed) != tt.creates-1 {
+			t.Fatalf("%s: expected %d failed attempts to be recorded, got %d", tt.name, tt.creates-1, len(failed))
+		}
+		for i, a := range failed {
+			if a.Number != int32(i+1) || a.Error != "connection refused" || a.StartedAt == nil || a.CompletedAt == nil {
+				t.Errorf("%s: unexpected failed attempt %+v", tt.name, a)
+			}
+		}
+	}
+}
+
+func TestExecHookRetryWithinBudget(t *testing.T) {
+	rs := rsFixture()
+	kc := &flakyHookKubeClient{PrintingKubeClient: environment.PrintingKubeClient{Out: os.Stdout}, failures: 5}
+	rs.env.KubeClient = kc
+
+	h := retryHook("3,1h")
+	rel := &release.Release{Name: "flaky", Namespace: "default", Hooks: []*release.Hook{h}}
+	start := time.Now()
+	err := rs.execHookWithin(rs.requestLogger("install", "flaky", 1), rs.env.KubeClient, rel, hooks.PreInstall, newTimeoutBudget(1, 0), newHookSkipList(nil, nil))
+	if err == nil || !strings.Contains(err.Error(), "timeout budget") {
+		t.Errorf("Expected the budget to be used up, got %v", err)
+	}
+	if d := time.Since(start); d > 10*time.Second {
+		t.Errorf("Expected the delay between attempts to end with the budget, took %s", d)
+	}
+	if kc.creates != 1 {
+		t.Errorf("Expected 1 attempt, got %d", kc.creates)
+	}
+}
+
+func TestSortManifestsInvalidHookRetry(t *testing.T) {
+	files := map[string]string{"templates/migrate.yaml": retryHook("never").Manifest}
+	_, _, err := sortManifests(files, chartutil.NewVersionSet("v1"), InstallOrder)
+	if err == nil || !strings.Contains(err.Error(), "templates/migrate.yaml") {
+		t.Errorf("expected an error naming the hook, got %v", err)
+	}
+}
@@ -0,0 +1,92 @@
+/*
+Copyright 2017 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tiller
+
+import (
+	"strconv"
+
+	"github.com/ghodss/yaml"
+
+	"k8s.io/helm/pkg/hooks"
+	"k8s.io/helm/pkg/proto/hapi/release"
+	util "k8s.io/helm/pkg/releaseutil"
+)
+
+// hookSkipList holds the hooks a request asked not to run, by name or by
+// weight. The zero value skips nothing.
+type hookSkipList struct {
+	names   map[string]bool
+	weights map[int32]bool
+	// destructive skips the hooks that are annotated as destructive.
+	destructive bool
+}
+
+func newHookSkipList(names []string, weights []int32) hookSkipList {
+	l := hookSkipList{
+		names:   make(map[string]bool, len(names)),
+		weights: make(map[int32]bool, len(weights)),
+	}
+	for _, n := range names {
+		l.names[n] = true
+	}
+	for _, w := range weights {
+		l.weights[w] = true
+	}
+	return l
+}
+
+// skips returns true if h should not be run.
+func (l hookSkipList) skips(h *release.Hook) bool {
+	return l.names[h.Name] || l.weights[h.Weight] || l.skipsDestructive(h)
+}
+
+// skipsDestructive returns true if h should not be run only because it is
+// destructive.
+func (l hookSkipList) skipsDestructive(h *release.Hook) bool {
+	return l.destructive && !l.names[h.Name] && !l.weights[h.Weight] && isDestructiveHook(h)
+}
+
+// skippedDestructive returns the names of the hooks in hs that fire on the
+// named hook events and are skipped because they are destructive.
+func (l hookSkipList) skippedDestructive(hs []*release.Hook, hookNames ...string) []string {
+	var names []string
+	seen := map[*release.Hook]bool{}
+	for _, name := range hookNames {
+		for _, h := range hooksFor(hs, events[name]) {
+			if !seen[h] && l.skipsDestructive(h) {
+				names = append(names, h.Name)
+			}
+			seen[h] = true
+		}
+	}
+	return names
+}
+
+// isDestructiveHook returns whether h is annotated as destroying data. A
+// value that is not a boolean counts as true.
+func isDestructiveHook(h *release.Hook) bool {
+	var head util.SimpleHead
+	if err := yaml.Unmarshal([]byte(h.Manifest), &head); err != nil || head.Metadata == nil {
+		return false
+	}
+	v, ok := head.Metadata.Annotations[hooks.HookDestructiveAnno]
+	if !ok {
+		return false
+	}
+	destructive, err := strconv.ParseBool(v)
+	return destructive || err != nil
+}
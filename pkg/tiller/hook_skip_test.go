@@ -0,0 +1,107 @@
+/*
+Copyright 2017 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tiller
+
+import (
+	"reflect"
+	"testing"
+
+	"k8s.io/helm/pkg/hooks"
+	"k8s.io/helm/pkg/proto/hapi/release"
+)
+
+func TestHookSkipList(t *testing.T) {
+	reindex := &release.Hook{Name: "reindex", Weight: 10}
+	migrate := &release.Hook{Name: "migrate", Weight: 5}
+	notify := &release.Hook{Name: "notify", Weight: 0}
+
+	var none hookSkipList
+	for _, h := range []*release.Hook{reindex, migrate, notify} {
+		if none.skips(h) {
+			t.Errorf("Expected the zero value not to skip %s", h.Name)
+		}
+	}
+
+	l := newHookSkipList([]string{"reindex", "missing"}, []int32{5})
+	tests := []struct {
+		hook *release.Hook
+		skip bool
+	}{
+		{reindex, true},
+		{migrate, true},
+		{notify, false},
+	}
+	for _, tt := range tests {
+		if got := l.skips(tt.hook); got != tt.skip {
+			t.Errorf("%s: expected skip=%t, got %t", tt.hook.Name, tt.skip, got)
+		}
+	}
+}
+
+// destructiveHook returns a hook for events with the given value of the
+// helm.sh/hook-destructive annotation, if any.
+func destructiveHook(name, value string, events ...release.Hook_Event) *release.Hook {
+	manifest := "kind: Job\nmetadata:\n  name: " + name + "\n"
+	if value != "" {
+		manifest += "  annotations:\n    helm.sh/hook-destructive: \"" + value + "\"\n"
+	}
+	return &release.Hook{Name: name, Kind: "Job", Manifest: manifest, Events: events}
+}
+
+func TestIsDestructiveHook(t *testing.T) {
+	tests := []struct {
+		value       string
+		destructive bool
+	}{
+		{"", false},
+		{"false", false},
+		{"true", true},
+		{"yes", true},
+	}
+	for _, tt := range tests {
+		if got := isDestructiveHook(destructiveHook("wipe", tt.value)); got != tt.destructive {
+			t.Errorf("%q: expected destructive=%t, got %t", tt.value, tt.destructive, got)
+		}
+	}
+}
+
+func TestHookSkipListDestructive(t *testing.T) {
+	wipe := destructiveHook("wipe-cache", "true", release.Hook_PRE_ROLLBACK, release.Hook_POST_ROLLBACK)
+	reset := destructiveHook("reset-db", "true", release.Hook_POST_ROLLBACK)
+	notify := destructiveHook("notify", "", release.Hook_PRE_ROLLBACK)
+	hs := []*release.Hook{wipe, reset, notify}
+
+	l := newHookSkipList([]string{"reset-db"}, nil)
+	if l.skips(wipe) {
+		t.Error("Expected destructive hooks to run unless asked otherwise")
+	}
+
+	l.destructive = true
+	if !l.skips(wipe) || !l.skipsDestructive(wipe) {
+		t.Error("Expected the destructive hook to be skipped")
+	}
+	if !l.skips(reset) || l.skipsDestructive(reset) {
+		t.Error("Expected the hook skipped by name not to count as skipped for being destructive")
+	}
+	if l.skips(notify) {
+		t.Error("Expected the hook that is not destructive to run")
+	}
+	skipped := l.skippedDestructive(hs, hooks.PreRollback, hooks.PostRollback)
+	if !reflect.DeepEqual(skipped, []string{"wipe-cache"}) {
+		t.Errorf("Expected wipe-cache to be reported once, got %v", skipped)
+	}
+}
@@ -111,6 +111,10 @@ func sortManifests(files map[string]string, apis chartutil.VersionSet, sort Sort
 			continue
 		}
 
+		if _, err := parseHookRetry(sh.Metadata.Annotations[hooks.HookRetryAnno]); err != nil {
+			return hs, generic, fmt.Errorf("hook %s: %s", n, err)
+		}
+
 		hws, _ := sh.Metadata.Annotations[hooks.HookWeightAnno]
 		hw, err := strconv.Atoi(hws)
 		if err != nil {
@@ -126,13 +130,17 @@ func sortManifests(files map[string]string, apis chartutil.VersionSet, sort Sort
 			Weight:   int32(hw),
 		}
 
+		// A hook may run for several events, e.g. "pre-install,pre-upgrade".
+		// It is rendered once and listed once per event.
 		isHook := false
 		for _, hookType := range strings.Split(hookTypes, ",") {
 			hookType = strings.ToLower(strings.TrimSpace(hookType))
 			e, ok := events[hookType]
 			if ok {
 				isHook = true
-				h.Events = append(h.Events, e)
+				if !hasEvent(h, e) {
+					h.Events = append(h.Events, e)
+				}
 			}
 		}
 
@@ -144,3 +152,13 @@ func sortManifests(files map[string]string, apis chartutil.VersionSet, sort Sort
 	}
 	return hs, sortByKind(generic, sort), nil
 }
+
+// hasEvent reports whether h runs for the event e.
+func hasEvent(h *release.Hook, e release.Hook_Event) bool {
+	for _, event := range h.Events {
+		if event == e {
+			return true
+		}
+	}
+	return false
+}
@@ -17,13 +17,19 @@ limitations under the License.
 package tiller
 
 import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"reflect"
 	"testing"
 
 	"github.com/ghodss/yaml"
 
 	"k8s.io/helm/pkg/chartutil"
+	"k8s.io/helm/pkg/hooks"
 	"k8s.io/helm/pkg/proto/hapi/release"
 	util "k8s.io/helm/pkg/releaseutil"
+	"k8s.io/helm/pkg/tiller/environment"
 )
 
 func TestSortManifests(t *testing.T) {
@@ -199,3 +205,73 @@ func TestVersionSet(t *testing.T) {
 		t.Error("Found nonexistent extension")
 	}
 }
+
+func TestSortManifestsMultipleHookEvents(t *testing.T) {
+	manifests := map[string]string{
+		"templates/migrate.yaml": `apiVersion: v1
+kind: Job
+metadata:
+  name: migrate
+  annotations:
+    "helm.sh/hook": pre-install, pre-upgrade,PRE-INSTALL
+`,
+	}
+	hs, _, err := sortManifests(manifests, chartutil.NewVersionSet("v1"), InstallOrder)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	if len(hs) != 1 {
+		t.Fatalf("Expected the hook to be rendered once, got %d hooks", len(hs))
+	}
+	expect := []release.Hook_Event{release.Hook_PRE_INSTALL, release.Hook_PRE_UPGRADE}
+	if !reflect.DeepEqual(hs[0].Events, expect) {
+		t.Errorf("Expected events %v, got %v", expect, hs[0].Events)
+	}
+}
+
+func TestExecHookMultipleEvents(t *testing.T) {
+	h := &release.Hook{
+		Name:     "migrate",
+		Kind:     "Job",
+		Path:     "templates/migrate.yaml",
+		Manifest: "kind: Job\nmetadata:\n  name: migrate\n",
+		// Releases stored by earlier versions may list an event twice.
+		Events: []release.Hook_Event{release.Hook_PRE_INSTALL, release.Hook_PRE_UPGRADE, release.Hook_PRE_INSTALL},
+	}
+
+	for _, tt := range []struct {
+		hook    string
+		creates int
+	}{
+		{hooks.PreInstall, 1},
+		{hooks.PostInstall, 0},
+		{hooks.PreUpgrade, 1},
+		{hooks.PostUpgrade, 0},
+	} {
+		rs := rsFixture()
+		kc := &flakyHookKubeClient{PrintingKubeClient: environment.PrintingKubeClient{Out: ioutil.Discard}}
+		rs.env.KubeClient = kc
+		if err := rs.execHook(rs.requestLogger("test", "multi", 1), rs.env.KubeClient, &release.Release{Name: "multi", Namespace: "default", Hooks: []*release.Hook{h}}, tt.hook, 0, newHookSkipList(nil, nil)); err != nil {
+			t.Fatalf("%s: unexpected error %s", tt.hook, err)
+		}
+		if kc.creates != tt.creates {
+			t.Errorf("%s: expected the hook to be created %d times, got %d", tt.hook, tt.creates, kc.creates)
+		}
+	}
+}
+
+// hookOrderKubeClient records the calls that create and delete hooks.
+type hookOrderKubeClient struct {
+	environment.PrintingKubeClient
+	calls []string
+}
+
+func (k *hookOrderKubeClient) Create(ns string, r io.Reader, timeout int64, shouldWait bool) error {
+	k.calls = append(k.calls, "create")
+	return nil
+}
+
+func (k *hookOrderKubeClient) DeleteWithPolicy(ns string, r io.Reader, policy string, timeout int64, shouldWait bool) error {
+	k.calls = append(k.calls, fmt.Sprintf("delete wait=%t", shouldWait))
+	return nil
+}
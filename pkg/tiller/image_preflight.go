@@ -0,0 +1,196 @@
+/*
+Copyright 2017 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tiller
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/ghodss/yaml"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/kubernetes/pkg/api"
+	"k8s.io/kubernetes/pkg/client/clientset_generated/internalclientset"
+
+	"k8s.io/helm/pkg/proto/hapi/release"
+	"k8s.io/helm/pkg/registry"
+	util "k8s.io/helm/pkg/releaseutil"
+)
+
+// ImageVerifier checks that a container image can be pulled, using the
+// registry logins in creds.
+type ImageVerifier interface {
+	VerifyImage(image string, creds registry.Credentials) error
+}
+
+// podImages is what the pod specs of a set of manifests need to pull their
+// images.
+type podImages struct {
+	images          map[string]bool
+	pullSecrets     map[string]bool
+	serviceAccounts map[string]bool
+}
+
+// collectPodImages returns the images, image pull secrets and service
+// accounts of the pod specs in manifests, at any depth, so that pods, pod
+// templates and custom resources that embed them are all covered.
+func collectPodImages(manifests ...string) podImages {
+	p := podImages{images: map[string]bool{}, pullSecrets: map[string]bool{}, serviceAccounts: map[string]bool{}}
+	for _, m := range manifests {
+		for _, doc := range util.SplitManifests(m) {
+			var obj map[string]interface{}
+			if err := yaml.Unmarshal([]byte(doc), &obj); err != nil {
+				continue
+			}
+			p.walk(obj)
+		}
+	}
+	return p
+}
+
+func (p podImages) walk(v interface{}) {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		if containers, ok := v["containers"].([]interface{}); ok {
+			p.addPodSpec(v, containers)
+		}
+		for _, sub := range v {
+			p.walk(sub)
+		}
+	case []interface{}:
+		for _, sub := range v {
+			p.walk(sub)
+		}
+	}
+}
+
+func (p podImages) addPodSpec(spec map[string]interface{}, containers []interface{}) {
+	init, _ := spec["initContainers"].([]interface{})
+	for _, list := range [][]interface{}{containers, init} {
+		for _, c := range list {
+			if c, ok := c.(map[string]interface{}); ok {
+				if image, ok := c["image"].(string); ok && image != "" {
+					p.images[image] = true
+				}
+			}
+		}
+	}
+	secrets, _ := spec["imagePullSecrets"].([]interface{})
+	for _, s := range secrets {
+		if s, ok := s.(map[string]interface{}); ok {
+			if name, ok := s["name"].(string); ok && name != "" {
+				p.pullSecrets[name] = true
+			}
+		}
+	}
+	sa, _ := spec["serviceAccountName"].(string)
+	if sa == "" {
+		sa = "default"
+	}
+	p.serviceAccounts[sa] = true
+}
+
+// verifyImages checks that every image used by r, including by its hooks,
+// can be pulled. The image pull secrets of the pod specs and their service
+// accounts are used to log in to registries. It returns an error listing the
+// images that cannot be pulled.
+func (s *ReleaseServer) verifyImages(r *release.Release) error {
+	manifests := []string{r.Manifest}
+	for _, h := range r.Hooks {
+		manifests = append(manifests, h.Manifest)
+	}
+	p := collectPodImages(manifests...)
+	if len(p.images) == 0 {
+		return nil
+	}
+
+	kc, err := s.releaseCluster(r)
+	if err != nil {
+		return err
+	}
+	creds, err := pullCredentials(kc.clientset, r.Namespace, p)
+	if err != nil {
+		return err
+	}
+	verifier := s.imageVerifier
+	if verifier == nil {
+		verifier = &registry.Client{}
+	}
+
+	images := make([]string, 0, len(p.images))
+	for image := range p.images {
+		images = append(images, image)
+	}
+	sort.Strings(images)
+	var failed []string
+	for _, image := range images {
+		if err := verifier.VerifyImage(image, creds); err != nil {
+			failed = append(failed, fmt.Sprintf("%s (%s)", image, err))
+		}
+	}
+	if len(failed) > 0 {
+		return fmt.Errorf("images of release %s cannot be pulled: %s", r.Name, strings.Join(failed, ", "))
+	}
+	return nil
+}
+
+// pullCredentials reads the registry logins from the image pull secrets in p
+// and those of its service accounts. Secrets and service accounts that do not
+// exist yet, because the release creates them, are skipped.
+func pullCredentials(clientset internalclientset.Interface, namespace string, p podImages) (registry.Credentials, error) {
+	secrets := map[string]bool{}
+	for name := range p.pullSecrets {
+		secrets[name] = true
+	}
+	for name := range p.serviceAccounts {
+		sa, err := clientset.Core().ServiceAccounts(namespace).Get(name, metav1.GetOptions{})
+		if errors.IsNotFound(err) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("cannot read service account %s: %s", name, err)
+		}
+		for _, ref := range sa.ImagePullSecrets {
+			secrets[ref.Name] = true
+		}
+	}
+
+	creds := registry.Credentials{}
+	for name := range secrets {
+		secret, err := clientset.Core().Secrets(namespace).Get(name, metav1.GetOptions{})
+		if errors.IsNotFound(err) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("cannot read image pull secret %s: %s", name, err)
+		}
+		var data []byte
+		switch secret.Type {
+		case api.SecretTypeDockerConfigJson:
+			data = secret.Data[api.DockerConfigJsonKey]
+		case api.SecretTypeDockercfg:
+			data = secret.Data[api.DockerConfigKey]
+		default:
+			continue
+		}
+		if err := creds.AddDockerConfig(data); err != nil {
+			return nil, fmt.Errorf("image pull secret %s: %s", name, err)
+		}
+	}
+	return creds, nil
+}
@@ -0,0 +1,167 @@
+/*
+Copyright 2017 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tiller
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/kubernetes/pkg/api"
+
+	"k8s.io/helm/pkg/helm"
+	"k8s.io/helm/pkg/proto/hapi/chart"
+	"k8s.io/helm/pkg/proto/hapi/services"
+	"k8s.io/helm/pkg/registry"
+)
+
+var manifestWithImages = `apiVersion: v1
+kind: ReplicationController
+metadata:
+  name: web
+spec:
+  template:
+    spec:
+      serviceAccountName: web
+      imagePullSecrets:
+      - name: quay-login
+      initContainers:
+      - name: migrate
+        image: quay.io/example/migrate:1.0
+      containers:
+      - name: web
+        image: nginx:1.13
+---
+apiVersion: v1
+kind: Pod
+metadata:
+  name: sidecar
+spec:
+  containers:
+  - name: proxy
+    image: envoyproxy/envoy:v1.5.0
+`
+
+func TestCollectPodImages(t *testing.T) {
+	p := collectPodImages(manifestWithImages, "kind: ConfigMap\ndata:\n  image: not-an-image\n")
+
+	expect := podImages{
+		images:          map[string]bool{"quay.io/example/migrate:1.0": true, "nginx:1.13": true, "envoyproxy/envoy:v1.5.0": true},
+		pullSecrets:     map[string]bool{"quay-login": true},
+		serviceAccounts: map[string]bool{"web": true, "default": true},
+	}
+	if !reflect.DeepEqual(p, expect) {
+		t.Errorf("expected %+v, got %+v", expect, p)
+	}
+}
+
+// recordingImageVerifier fails the images in missing, and records the
+// credentials it was given.
+type recordingImageVerifier struct {
+	missing map[string]bool
+	checked []string
+	creds   registry.Credentials
+}
+
+func (v *recordingImageVerifier) VerifyImage(image string, creds registry.Credentials) error {
+	v.checked = append(v.checked, image)
+	v.creds = creds
+	if v.missing[image] {
+		return registry.ErrManifestUnknown
+	}
+	return nil
+}
+
+func imagesChart() *chart.Chart {
+	return &chart.Chart{
+		Metadata: &chart.Metadata{Name: "hello"},
+		Templates: []*chart.Template{
+			{Name: "templates/deployment.yaml", Data: []byte(manifestWithImages)},
+		},
+	}
+}
+
+func TestInstallRelease_VerifyImages(t *testing.T) {
+	c := helm.NewContext()
+	rs := rsFixture()
+	verifier := &recordingImageVerifier{}
+	rs.imageVerifier = verifier
+
+	// "Ym90OnMzY3JldA==" is "bot:s3cret".
+	rs.clientset.Core().Secrets("spaced").Create(&api.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "quay-login", Namespace: "spaced"},
+		Type:       api.SecretTypeDockerConfigJson,
+		Data:       map[string][]byte{api.DockerConfigJsonKey: []byte(`{"auths": {"quay.io": {"auth": "Ym90OnMzY3JldA=="}}}`)},
+	})
+
+	req := &services.InstallReleaseRequest{
+		Name:         "images",
+		Namespace:    "spaced",
+		Chart:        imagesChart(),
+		VerifyImages: true,
+	}
+	if _, err := rs.InstallRelease(c, req); err != nil {
+		t.Fatalf("Failed install: %s", err)
+	}
+	expect := []string{"envoyproxy/envoy:v1.5.0", "nginx:1.13", "quay.io/example/migrate:1.0"}
+	if !reflect.DeepEqual(verifier.checked, expect) {
+		t.Errorf("Expected %v to be checked, got %v", expect, verifier.checked)
+	}
+	if auth, ok := verifier.creds.For("quay.io"); !ok || auth.Username != "bot" {
+		t.Errorf("Expected the login from the image pull secret, got %v", verifier.creds)
+	}
+
+	// A missing image fails the install before anything is created.
+	verifier.missing = map[string]bool{"nginx:1.13": true}
+	req.Name = "missing-image"
+	_, err := rs.InstallRelease(c, req)
+	if err == nil || !strings.Contains(err.Error(), "nginx:1.13 (manifest unknown)") {
+		t.Fatalf("Expected the missing image to be reported, got %v", err)
+	}
+	if _, err := rs.env.Releases.Last("missing-image"); err == nil {
+		t.Error("Expected no release to be recorded")
+	}
+
+	// Images are not checked unless requested.
+	req.Name = "unchecked"
+	req.VerifyImages = false
+	if _, err := rs.InstallRelease(c, req); err != nil {
+		t.Fatalf("Failed install: %s", err)
+	}
+}
+
+func TestUpdateRelease_VerifyImages(t *testing.T) {
+	c := helm.NewContext()
+	rs := rsFixture()
+	rs.imageVerifier = &recordingImageVerifier{missing: map[string]bool{"envoyproxy/envoy:v1.5.0": true}}
+	rel := releaseStub()
+	rel.Namespace = "spaced"
+	rs.env.Releases.Create(rel)
+
+	_, err := rs.UpdateRelease(c, &services.UpdateReleaseRequest{
+		Name:         rel.Name,
+		Chart:        imagesChart(),
+		VerifyImages: true,
+	})
+	if err == nil || !strings.Contains(err.Error(), "envoyproxy/envoy:v1.5.0") {
+		t.Fatalf("Expected the missing image to be reported, got %v", err)
+	}
+	if last, _ := rs.env.Releases.Last(rel.Name); last.Version != rel.Version {
+		t.Errorf("Expected no new revision, got %d", last.Version)
+	}
+}
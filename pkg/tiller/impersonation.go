@@ -0,0 +1,63 @@
+/*
+Copyright 2017 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tiller
+
+import (
+	"errors"
+
+	"k8s.io/helm/pkg/kube"
+	"k8s.io/helm/pkg/proto/hapi/release"
+)
+
+// impersonator is a KubeClient that can act as another identity. See
+// kube.Client.Impersonate.
+type impersonator interface {
+	Impersonate(user string, groups []string) (*kube.Client, error)
+}
+
+// impersonate returns a copy of kc whose Kubernetes client acts as imp, or
+// kc itself if imp is nil.
+func (kc *kubeCluster) impersonate(imp *release.Impersonation) (*kubeCluster, error) {
+	if imp == nil {
+		return kc, nil
+	}
+	if _, ok := kc.module.(*LocalReleaseModule); !ok {
+		return nil, errors.New("impersonation is not supported when release modules are remote")
+	}
+	i, ok := kc.env.KubeClient.(impersonator)
+	if !ok {
+		return nil, errors.New("impersonation is not supported by Tiller's Kubernetes client")
+	}
+	client, err := i.Impersonate(imp.User, imp.Groups)
+	if err != nil {
+		return nil, err
+	}
+	env := *kc.env
+	env.KubeClient = client
+	return &kubeCluster{env: &env, clientset: kc.clientset, module: kc.module}, nil
+}
+
+// actingCluster returns the cluster that r is installed in, with a
+// Kubernetes client that acts as the identity r records, if any. It is used
+// by the operations that change the resources of r.
+func (s *ReleaseServer) actingCluster(r *release.Release) (*kubeCluster, error) {
+	kc, err := s.releaseCluster(r)
+	if err != nil {
+		return nil, err
+	}
+	return kc.impersonate(r.Info.Impersonation)
+}
@@ -0,0 +1,127 @@
+/*
+Copyright 2017 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tiller
+
+import (
+	"fmt"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+
+	"k8s.io/helm/pkg/helm"
+	"k8s.io/helm/pkg/kube"
+	"k8s.io/helm/pkg/proto/hapi/release"
+	"k8s.io/helm/pkg/proto/hapi/services"
+	"k8s.io/helm/pkg/tiller/environment"
+	"k8s.io/helm/pkg/version"
+)
+
+// impersonatingKubeClient records the identities it is asked to act as, and
+// only lets Tiller impersonate allowed.
+type impersonatingKubeClient struct {
+	environment.PrintingKubeClient
+	allowed string
+	users   []string
+}
+
+func (c *impersonatingKubeClient) Impersonate(user string, groups []string) (*kube.Client, error) {
+	c.users = append(c.users, user)
+	if user != c.allowed {
+		return nil, fmt.Errorf("not allowed to impersonate user %q: forbidden", user)
+	}
+	return &kube.Client{}, nil
+}
+
+func TestInstallRelease_Impersonation(t *testing.T) {
+	c := helm.NewContext()
+	rs := rsFixture()
+	kc := &impersonatingKubeClient{PrintingKubeClient: environment.PrintingKubeClient{Out: os.Stdout}, allowed: "system:serviceaccount:ci:deployer"}
+	rs.env.KubeClient = kc
+
+	imp := &release.Impersonation{User: "system:serviceaccount:ci:deployer", Groups: []string{"deployers"}}
+	res, err := rs.InstallRelease(c, &services.InstallReleaseRequest{
+		Namespace:     "spaced",
+		Chart:         chartStub(),
+		DryRun:        true,
+		Impersonation: imp,
+	})
+	if err != nil {
+		t.Fatalf("Failed install: %s", err)
+	}
+	if !reflect.DeepEqual(res.Release.Info.Impersonation, imp) {
+		t.Errorf("Expected the impersonated identity to be recorded, got %v", res.Release.Info.Impersonation)
+	}
+
+	_, err = rs.InstallRelease(c, &services.InstallReleaseRequest{
+		Namespace:     "spaced",
+		Chart:         chartStub(),
+		Impersonation: &release.Impersonation{User: "jane"},
+	})
+	if err == nil || !strings.Contains(err.Error(), `not allowed to impersonate user "jane"`) {
+		t.Errorf("Expected the install to fail, got %v", err)
+	}
+	if expect := []string{imp.User, "jane"}; !reflect.DeepEqual(kc.users, expect) {
+		t.Errorf("Expected %v to be impersonated, got %v", expect, kc.users)
+	}
+}
+
+func TestUninstallRelease_Impersonation(t *testing.T) {
+	c := helm.NewContext()
+	rs := rsFixture()
+	rs.env.KubeClient = &impersonatingKubeClient{PrintingKubeClient: environment.PrintingKubeClient{Out: os.Stdout}}
+	rel := releaseStub()
+	rs.env.Releases.Create(rel)
+
+	_, err := rs.UninstallRelease(c, &services.UninstallReleaseRequest{
+		Name:          rel.Name,
+		Impersonation: &release.Impersonation{User: "jane"},
+	})
+	if err == nil || !strings.Contains(err.Error(), `not allowed to impersonate user "jane"`) {
+		t.Fatalf("Expected the delete to fail, got %v", err)
+	}
+	if last, _ := rs.env.Releases.Last(rel.Name); last.Info.Status.Code != release.Status_DEPLOYED {
+		t.Errorf("Expected the release to be left alone, got %s", last.Info.Status.Code)
+	}
+}
+
+func TestGetVersionImpersonation(t *testing.T) {
+	rs := rsFixture()
+	rs.env.KubeClient = &impersonatingKubeClient{PrintingKubeClient: environment.PrintingKubeClient{Out: os.Stdout}}
+
+	res, err := rs.GetVersion(helm.NewContext(), &services.GetVersionRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expect := []string{version.FeatureImpersonation}; !reflect.DeepEqual(res.Features, expect) {
+		t.Errorf("Expected features %v, got %v", expect, res.Features)
+	}
+}
+
+func TestImpersonationNotSupported(t *testing.T) {
+	rs := rsFixture()
+	kc, err := rs.cluster("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := kc.impersonate(&release.Impersonation{User: "jane"}); err == nil {
+		t.Error("Expected impersonation to fail without support from the Kubernetes client")
+	}
+	if same, err := kc.impersonate(nil); err != nil || same != kc {
+		t.Errorf("Expected no impersonation to keep the cluster, got %v", err)
+	}
+}
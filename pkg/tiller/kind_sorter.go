@@ -21,13 +21,20 @@ import (
 )
 
 // SortOrder is an ordering of Kinds.
+//
+// Kinds that are not in a SortOrder, such as custom resources, sort after all
+// of the Kinds that are, unless the SortOrder contains OtherKinds.
 type SortOrder []string
 
+// OtherKinds marks the position in a SortOrder of the Kinds it does not name.
+const OtherKinds = "*"
+
 // InstallOrder is the order in which manifests should be installed (by Kind).
 //
 // Those occurring earlier in the list get installed before those occurring later in the list.
 var InstallOrder SortOrder = []string{
 	"Namespace",
+	"CustomResourceDefinition",
 	"ResourceQuota",
 	"LimitRange",
 	"Secret",
@@ -46,6 +53,8 @@ var InstallOrder SortOrder = []string{
 	"ReplicaSet",
 	"Deployment",
 	"StatefulSet",
+	"ValidatingWebhookConfiguration",
+	"MutatingWebhookConfiguration",
 	"Job",
 	"CronJob",
 	"Ingress",
@@ -54,7 +63,11 @@ var InstallOrder SortOrder = []string{
 // UninstallOrder is the order in which manifests should be uninstalled (by Kind).
 //
 // Those occurring earlier in the list get uninstalled before those occurring later in the list.
+// Custom resources are uninstalled before the CustomResourceDefinitions that
+// define them.
 var UninstallOrder SortOrder = []string{
+	"MutatingWebhookConfiguration",
+	"ValidatingWebhookConfiguration",
 	"Ingress",
 	"Service",
 	"CronJob",
@@ -77,6 +90,8 @@ var UninstallOrder SortOrder = []string{
 	"LimitRange",
 	"ResourceQuota",
 	"Namespace",
+	OtherKinds,
+	"CustomResourceDefinition",
 }
 
 // sortByKind does an in-place sort of manifests by Kind.
@@ -110,16 +125,16 @@ func (k *kindSorter) Len() int { return len(k.manifests) }
 func (k *kindSorter) Swap(i, j int) { k.manifests[i], k.manifests[j] = k.manifests[j], k.manifests[i] }
 
 func (k *kindSorter) Less(i, j int) bool {
-	a := k.manifests[i]
-	b := k.manifests[j]
-	first, ok := k.ordering[a.head.Kind]
-	if !ok {
-		// Unknown is always last
-		return false
+	return k.rank(k.manifests[i].head.Kind) < k.rank(k.manifests[j].head.Kind)
+}
+
+func (k *kindSorter) rank(kind string) int {
+	if r, ok := k.ordering[kind]; ok {
+		return r
 	}
-	second, ok := k.ordering[b.head.Kind]
-	if !ok {
-		return true
+	if r, ok := k.ordering[OtherKinds]; ok {
+		return r
 	}
-	return first < second
+	// Unknown is always last
+	return len(k.ordering)
 }
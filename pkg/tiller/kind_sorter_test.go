@@ -18,6 +18,7 @@ package tiller
 
 import (
 	"bytes"
+	"strings"
 	"testing"
 
 	util "k8s.io/helm/pkg/releaseutil"
@@ -165,3 +166,52 @@ func TestKindSorter(t *testing.T) {
 		})
 	}
 }
+
+func TestKindSorterWebhooks(t *testing.T) {
+	manifests := []manifest{
+		{name: "job", head: &util.SimpleHead{Kind: "Job"}},
+		{name: "webhook", head: &util.SimpleHead{Kind: "ValidatingWebhookConfiguration"}},
+		{name: "custom", head: &util.SimpleHead{Kind: "Certificate"}},
+		{name: "deployment", head: &util.SimpleHead{Kind: "Deployment"}},
+		{name: "service", head: &util.SimpleHead{Kind: "Service"}},
+	}
+
+	// The webhook is installed after the workloads that serve it and before
+	// the resources it may need to admit.
+	var names []string
+	for _, m := range sortByKind(manifests, InstallOrder) {
+		names = append(names, m.name)
+	}
+	if got, expect := strings.Join(names, ","), "service,deployment,webhook,job,custom"; got != expect {
+		t.Errorf("Expected install order %q, got %q", expect, got)
+	}
+
+	// It is uninstalled first so that it does not block other deletions.
+	if got := sortByKind(manifests, UninstallOrder)[0].name; got != "webhook" {
+		t.Errorf("Expected webhook to be uninstalled first, got %q", got)
+	}
+}
+
+func TestKindSorterCustomResources(t *testing.T) {
+	manifests := []manifest{
+		{name: "crd", head: &util.SimpleHead{Kind: "CustomResourceDefinition"}},
+		{name: "cr", head: &util.SimpleHead{Kind: "Certificate"}},
+		{name: "deployment", head: &util.SimpleHead{Kind: "Deployment"}},
+	}
+
+	for _, tt := range []struct {
+		order  SortOrder
+		expect string
+	}{
+		{InstallOrder, "crd,deployment,cr"},
+		{UninstallOrder, "deployment,cr,crd"},
+	} {
+		var names []string
+		for _, m := range sortByKind(manifests, tt.order) {
+			names = append(names, m.name)
+		}
+		if got := strings.Join(names, ","); got != tt.expect {
+			t.Errorf("Expected %q, got %q", tt.expect, got)
+		}
+	}
+}
@@ -0,0 +1,178 @@
+/*
+Copyright 2017 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tiller
+
+import (
+	"bytes"
+
+	"github.com/ghodss/yaml"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"k8s.io/helm/pkg/chartutil"
+	"k8s.io/helm/pkg/logging"
+	"k8s.io/helm/pkg/proto/hapi/release"
+	relutil "k8s.io/helm/pkg/releaseutil"
+	"k8s.io/helm/pkg/timeconv"
+)
+
+// serviceHead is the part of a Service manifest needed to find LoadBalancers.
+type serviceHead struct {
+	Kind     string `json:"kind"`
+	Metadata struct {
+		Name      string `json:"name"`
+		Namespace string `json:"namespace"`
+	} `json:"metadata"`
+	Spec struct {
+		Type string `json:"type"`
+	} `json:"spec"`
+}
+
+// loadBalancerAddresses returns the external address of every LoadBalancer
+// Service in r's manifest that has been assigned one, keyed by Service name.
+// The address is the IP if there is one, and the hostname otherwise.
+func (s *ReleaseServer) loadBalancerAddresses(r *release.Release) (map[string]interface{}, error) {
+	kc, err := s.releaseCluster(r)
+	if err != nil {
+		return nil, err
+	}
+	addrs := map[string]interface{}{}
+	for _, m := range relutil.SplitManifests(r.Manifest) {
+		var head serviceHead
+		if err := yaml.Unmarshal([]byte(m), &head); err != nil {
+			return nil, err
+		}
+		if head.Kind != "Service" || head.Spec.Type != "LoadBalancer" {
+			continue
+		}
+		ns := head.Metadata.Namespace
+		if ns == "" {
+			ns = r.Namespace
+		}
+		svc, err := kc.clientset.Core().Services(ns).Get(head.Metadata.Name, metav1.GetOptions{})
+		if err != nil {
+			return nil, err
+		}
+		for _, ing := range svc.Status.LoadBalancer.Ingress {
+			if ing.IP != "" {
+				addrs[svc.Name] = ing.IP
+				break
+			}
+			if ing.Hostname != "" {
+				addrs[svc.Name] = ing.Hostname
+				break
+			}
+		}
+	}
+	return addrs, nil
+}
+
+// refreshNotes re-renders the notes of r with .LoadBalancers filled in, so
+// that notes can print the addresses of load balancers created by the release.
+// If live is set, the notes are rendered again even without load balancers,
+// with .Live holding the live state of the release's resources, keyed by
+// "Kind/name".
+//
+// It is called after a wait, once load balancers have been assigned their
+// addresses, or once the resources are created if live is set. previous is
+// the release that r upgrades, or nil for an install. values are the values r
+// was rendered with; if they are nil, they are composed again from r, with
+// the mutations it recorded instead of running the values mutators again. If
+// the notes cannot be rendered again, the original notes are kept.
+func (s *ReleaseServer) refreshNotes(log logging.Logger, r *release.Release, previous *release.Release, values chartutil.Values, live bool) {
+	if r.Info.Status.Notes == "" && !live {
+		return
+	}
+	addrs, err := s.loadBalancerAddresses(r)
+	if err != nil {
+		log.Warnf("Failed to look up load balancer addresses: %s", err)
+		return
+	}
+	if len(addrs) == 0 && !live {
+		return
+	}
+
+	kc, err := s.releaseCluster(r)
+	if err != nil {
+		log.Warnf("Failed to re-render notes: %s", err)
+		return
+	}
+	caps, err := s.clusterCapabilities(r.Cluster, kc)
+	if err != nil {
+		log.Warnf("Failed to re-render notes: %s", err)
+		return
+	}
+	if values == nil {
+		if values, err = s.notesValues(r, previous, caps); err != nil {
+			log.Warnf("Failed to re-render notes: %s", err)
+			return
+		}
+	}
+	// The values may be shared with the caller, so only a copy gets the
+	// addresses and live state.
+	notesValues := chartutil.Values{}
+	for k, v := range values {
+		notesValues[k] = v
+	}
+	notesValues["LoadBalancers"] = addrs
+	if live {
+		objs, err := kc.env.KubeClient.LiveObjects(r.Namespace, bytes.NewBufferString(r.Manifest))
+		if err != nil {
+			log.Warnf("Failed to look up the live resources for the notes: %s", err)
+			return
+		}
+		notesValues["Live"] = objs
+	}
+
+	_, _, notes, _, err := s.renderResources(r.Chart, notesValues, caps.APIVersions, generatedValues(r))
+	if err != nil {
+		log.Warnf("Failed to re-render notes: %s", err)
+		return
+	}
+	r.Info.Status.Notes = notes
+}
+
+// notesValues composes the values that r was rendered with again, for
+// refreshNotes, replaying the mutations r recorded.
+func (s *ReleaseServer) notesValues(r, previous *release.Release, caps *chartutil.Capabilities) (chartutil.Values, error) {
+	options := chartutil.ReleaseOptions{
+		Name:      r.Name,
+		Time:      r.Info.LastDeployed,
+		Namespace: r.Namespace,
+		Revision:  int(r.Version),
+		IsInstall: previous == nil,
+		IsUpgrade: previous != nil,
+		Flags:     r.Info.Flags,
+	}
+	if options.Time == nil {
+		options.Time = timeconv.Now()
+	}
+	var err error
+	if previous != nil {
+		if options.PreviousValues, err = renderedValues(previous); err != nil {
+			return nil, err
+		}
+	}
+	vals, err := profileValues(r)
+	if err != nil {
+		return nil, err
+	}
+	values, err := chartutil.ToRenderValuesCaps(r.Chart, vals, options, caps)
+	if err != nil {
+		return nil, err
+	}
+	return values, replayMutations(r, values)
+}
@@ -0,0 +1,198 @@
+/*
+Copyright 2017 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tiller
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/kubernetes/pkg/api"
+
+	"k8s.io/helm/pkg/chartutil"
+	"k8s.io/helm/pkg/helm"
+	"k8s.io/helm/pkg/proto/hapi/chart"
+	"k8s.io/helm/pkg/proto/hapi/services"
+	"k8s.io/helm/pkg/tiller/environment"
+)
+
+var manifestWithLoadBalancer = `apiVersion: v1
+kind: Service
+metadata:
+  name: web
+spec:
+  type: LoadBalancer
+  ports:
+  - port: 80
+`
+
+var notesWithLoadBalancer = `{{ with index .LoadBalancers "web" }}Visit http://{{ . }}{{ else }}Address pending{{ end }}`
+
+func loadBalancerInstallRequest(wait bool) *services.InstallReleaseRequest {
+	return &services.InstallReleaseRequest{
+		Namespace: "spaced",
+		Wait:      wait,
+		Chart: &chart.Chart{
+			Metadata: &chart.Metadata{Name: "hello"},
+			Templates: []*chart.Template{
+				{Name: "templates/service", Data: []byte(manifestWithLoadBalancer)},
+				{Name: "templates/NOTES.txt", Data: []byte(notesWithLoadBalancer)},
+			},
+		},
+	}
+}
+
+func createLoadBalancer(t *testing.T, rs *ReleaseServer, ingress api.LoadBalancerIngress) {
+	svc := &api.Service{
+		ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "spaced"},
+		Spec:       api.ServiceSpec{Type: api.ServiceTypeLoadBalancer},
+		Status:     api.ServiceStatus{LoadBalancer: api.LoadBalancerStatus{Ingress: []api.LoadBalancerIngress{ingress}}},
+	}
+	if _, err := rs.clientset.Core().Services("spaced").Create(svc); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestInstallRelease_LoadBalancerNotes(t *testing.T) {
+	tests := []struct {
+		name    string
+		ingress api.LoadBalancerIngress
+		wait    bool
+		expect  string
+	}{
+		{"IP", api.LoadBalancerIngress{IP: "203.0.113.7"}, true, "Visit http://203.0.113.7"},
+		{"hostname", api.LoadBalancerIngress{Hostname: "web.elb.example.com"}, true, "Visit http://web.elb.example.com"},
+		{"no wait", api.LoadBalancerIngress{IP: "203.0.113.7"}, false, "Address pending"},
+	}
+	for _, tt := range tests {
+		rs := rsFixture()
+		createLoadBalancer(t, rs, tt.ingress)
+
+		res, err := rs.InstallRelease(helm.NewContext(), loadBalancerInstallRequest(tt.wait))
+		if err != nil {
+			t.Fatalf("%s: failed install: %s", tt.name, err)
+		}
+		if notes := res.Release.Info.Status.Notes; notes != tt.expect {
+			t.Errorf("%s: expected notes %q, got %q", tt.name, tt.expect, notes)
+		}
+	}
+}
+
+func TestInstallRelease_LoadBalancerNotesKeepMutations(t *testing.T) {
+	rs := rsFixture()
+	createLoadBalancer(t, rs, api.LoadBalancerIngress{IP: "203.0.113.7"})
+	runs := 0
+	rs.AddValuesMutator("ipam", ValuesMutatorFunc(func(_ chartutil.ReleaseOptions, _ *chart.Metadata, values chartutil.Values) (chartutil.Values, error) {
+		runs++
+		values["ip"] = fmt.Sprintf("10.0.0.%d", runs)
+		return values, nil
+	}))
+	req := loadBalancerInstallRequest(true)
+	req.Chart.Templates[1].Data = []byte(notesWithLoadBalancer + " from {{ .Values.ip }}")
+
+	res, err := rs.InstallRelease(helm.NewContext(), req)
+	if err != nil {
+		t.Fatalf("Failed install: %s", err)
+	}
+	if runs != 1 {
+		t.Errorf("Expected the mutator to run once, ran %d times", runs)
+	}
+	if expect := "Visit http://203.0.113.7 from 10.0.0.1"; res.Release.Info.Status.Notes != expect {
+		t.Errorf("Expected notes %q, got %q", expect, res.Release.Info.Status.Notes)
+	}
+}
+
+func TestLoadBalancerAddresses_Pending(t *testing.T) {
+	rs := rsFixture()
+	createLoadBalancer(t, rs, api.LoadBalancerIngress{})
+
+	rel := releaseStub()
+	rel.Namespace = "spaced"
+	rel.Manifest = manifestWithLoadBalancer
+	addrs, err := rs.loadBalancerAddresses(rel)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(addrs) != 0 {
+		t.Errorf("Expected no addresses, got %v", addrs)
+	}
+}
+
+// liveKubeClient finds objs as the live resources, or fails with err.
+type liveKubeClient struct {
+	environment.PrintingKubeClient
+	objs map[string]interface{}
+	err  error
+}
+
+func (k *liveKubeClient) LiveObjects(ns string, r io.Reader) (map[string]interface{}, error) {
+	return k.objs, k.err
+}
+
+func TestInstallRelease_DeferNotes(t *testing.T) {
+	secret := map[string]interface{}{
+		"data": map[string]interface{}{"password": "c2VjcmV0"},
+	}
+	notes := `{{ with index .Live "Secret/db" }}Password: {{ .data.password | b64dec }}{{ else }}Password pending{{ end }}`
+	tests := []struct {
+		name       string
+		deferNotes bool
+		err        error
+		expect     string
+	}{
+		{"deferred", true, nil, "Password: secret"},
+		{"not deferred", false, nil, "Password pending"},
+		{"lookup failure", true, errors.New("forbidden"), "Password pending"},
+	}
+	for _, tt := range tests {
+		rs := rsFixture()
+		rs.env.KubeClient = &liveKubeClient{
+			PrintingKubeClient: environment.PrintingKubeClient{Out: os.Stdout},
+			objs:               map[string]interface{}{"Secret/db": secret},
+			err:                tt.err,
+		}
+		req := &services.InstallReleaseRequest{
+			Namespace:  "spaced",
+			DeferNotes: tt.deferNotes,
+			Chart: &chart.Chart{
+				Metadata: &chart.Metadata{Name: "hello"},
+				Templates: []*chart.Template{
+					{Name: "templates/secret", Data: []byte("apiVersion: v1\nkind: Secret\nmetadata:\n  name: db\n")},
+					{Name: "templates/NOTES.txt", Data: []byte(notes)},
+				},
+			},
+		}
+
+		res, err := rs.InstallRelease(helm.NewContext(), req)
+		if err != nil {
+			t.Fatalf("%s: failed install: %s", tt.name, err)
+		}
+		if got := res.Release.Info.Status.Notes; got != tt.expect {
+			t.Errorf("%s: expected notes %q, got %q", tt.name, tt.expect, got)
+		}
+		stored, err := rs.env.Releases.Get(res.Release.Name, res.Release.Version)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := stored.Info.Status.Notes; got != tt.expect {
+			t.Errorf("%s: expected stored notes %q, got %q", tt.name, tt.expect, got)
+		}
+	}
+}
@@ -0,0 +1,90 @@
+/*
+Copyright 2017 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tiller
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Release operations and hooks are labelled with their kind and outcome only.
+// Release names are deliberately left out, as they would make the number of
+// time series grow without bound.
+var (
+	releaseOperations = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "tiller",
+		Name:      "release_operations_total",
+		Help:      "Number of release operations, by operation and status.",
+	}, []string{"operation", "status"})
+
+	releaseOperationDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: "tiller",
+		Name:      "release_operation_duration_seconds",
+		Help:      "Duration of release operations, by operation and status.",
+		Buckets:   operationBuckets,
+	}, []string{"operation", "status"})
+
+	releaseOperationsInFlight = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: "tiller",
+		Name:      "release_operations_in_flight",
+		Help:      "Number of release operations in progress, by operation.",
+	}, []string{"operation"})
+
+	hookDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: "tiller",
+		Name:      "hook_duration_seconds",
+		Help:      "Duration of hook runs, by hook event and status.",
+		Buckets:   operationBuckets,
+	}, []string{"hook", "status"})
+)
+
+// operationBuckets are the histogram buckets for operations that may wait
+// for resources, from a fraction of a second up to half an hour.
+var operationBuckets = []float64{0.1, 0.5, 1, 2.5, 5, 10, 30, 60, 120, 300, 600, 1800}
+
+func init() {
+	prometheus.MustRegister(releaseOperations, releaseOperationDuration, releaseOperationsInFlight, hookDuration)
+}
+
+// observeOperation counts a release operation as in flight until the returned
+// function is called with the operation's error, which records its outcome
+// and duration.
+func observeOperation(operation string) func(error) {
+	start := time.Now()
+	releaseOperationsInFlight.WithLabelValues(operation).Inc()
+	return func(err error) {
+		releaseOperationsInFlight.WithLabelValues(operation).Dec()
+		status := metricStatus(err)
+		releaseOperations.WithLabelValues(operation, status).Inc()
+		releaseOperationDuration.WithLabelValues(operation, status).Observe(time.Since(start).Seconds())
+	}
+}
+
+// observeHook records the duration and outcome of a hook run that started at
+// start.
+func observeHook(hook string, start time.Time, err error) {
+	hookDuration.WithLabelValues(hook, metricStatus(err)).Observe(time.Since(start).Seconds())
+}
+
+// metricStatus is the status label for an outcome.
+func metricStatus(err error) string {
+	if err != nil {
+		return "failure"
+	}
+	return "success"
+}
@@ -0,0 +1,104 @@
+/*
+Copyright 2017 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tiller
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+
+	"k8s.io/helm/pkg/helm"
+	"k8s.io/helm/pkg/proto/hapi/services"
+)
+
+func metricValue(t *testing.T, m prometheus.Metric) *dto.Metric {
+	out := &dto.Metric{}
+	if err := m.Write(out); err != nil {
+		t.Fatal(err)
+	}
+	return out
+}
+
+func TestObserveOperation(t *testing.T) {
+	inFlight := releaseOperationsInFlight.WithLabelValues("test")
+	failures := releaseOperations.WithLabelValues("test", "failure")
+	before := metricValue(t, failures).GetCounter().GetValue()
+
+	done := observeOperation("test")
+	if got := metricValue(t, inFlight).GetGauge().GetValue(); got != 1 {
+		t.Errorf("Expected 1 operation in flight, got %v", got)
+	}
+	done(errors.New("boom"))
+
+	if got := metricValue(t, inFlight).GetGauge().GetValue(); got != 0 {
+		t.Errorf("Expected no operation in flight, got %v", got)
+	}
+	if got := metricValue(t, failures).GetCounter().GetValue(); got != before+1 {
+		t.Errorf("Expected %v failed operations, got %v", before+1, got)
+	}
+	duration := releaseOperationDuration.WithLabelValues("test", "failure").(prometheus.Metric)
+	if got := metricValue(t, duration).GetHistogram().GetSampleCount(); got == 0 {
+		t.Error("Expected the duration to be observed")
+	}
+}
+
+func TestInstallRelease_Metrics(t *testing.T) {
+	c := helm.NewContext()
+	rs := rsFixture()
+	installs := releaseOperations.WithLabelValues("install", "success")
+	before := metricValue(t, installs).GetCounter().GetValue()
+
+	req := &services.InstallReleaseRequest{Chart: chartStub()}
+	if _, err := rs.InstallRelease(c, req); err != nil {
+		t.Fatalf("Failed install: %s", err)
+	}
+	if got := metricValue(t, installs).GetCounter().GetValue(); got != before+1 {
+		t.Errorf("Expected %v successful installs, got %v", before+1, got)
+	}
+	hooks := hookDuration.WithLabelValues("post-install", "success").(prometheus.Metric)
+	if got := metricValue(t, hooks).GetHistogram().GetSampleCount(); got == 0 {
+		t.Error("Expected the post-install hook to be observed")
+	}
+}
+
+func TestMetricsLabelCardinality(t *testing.T) {
+	families, err := prometheus.DefaultGatherer.Gather()
+	if err != nil {
+		t.Fatal(err)
+	}
+	seen := 0
+	for _, f := range families {
+		if !strings.HasPrefix(f.GetName(), "tiller_") {
+			continue
+		}
+		seen++
+		for _, m := range f.Metric {
+			for _, l := range m.Label {
+				switch l.GetName() {
+				case "release", "name", "namespace", "revision":
+					t.Errorf("%s has the unbounded label %q", f.GetName(), l.GetName())
+				}
+			}
+		}
+	}
+	if seen == 0 {
+		t.Error("Expected tiller metrics to be registered")
+	}
+}
@@ -0,0 +1,85 @@
+/*
+Copyright 2017 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tiller
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/technosophos/moniker"
+
+	"k8s.io/helm/pkg/proto/hapi/chart"
+)
+
+// NameGenerator generates names for releases that are installed without one.
+//
+// GenerateName is called again, up to a few times, if the name it returns is
+// already taken, so it should not return the same name every time. Names
+// longer than the maximum release name length are truncated.
+type NameGenerator interface {
+	GenerateName(ch *chart.Chart) (string, error)
+}
+
+// NameGeneratorFunc adapts a function to a NameGenerator.
+type NameGeneratorFunc func(ch *chart.Chart) (string, error)
+
+// GenerateName calls f(ch).
+func (f NameGeneratorFunc) GenerateName(ch *chart.Chart) (string, error) {
+	return f(ch)
+}
+
+// MonikerNameGenerator generates random names such as "wintering-otter". It
+// is the default.
+type MonikerNameGenerator struct{}
+
+// GenerateName returns a random adjective-animal pair.
+func (MonikerNameGenerator) GenerateName(*chart.Chart) (string, error) {
+	return moniker.New().NameSep("-"), nil
+}
+
+// PatternNameGenerator generates names from a pattern in which these
+// placeholders are replaced:
+//
+//	{chart}      the name of the chart
+//	{timestamp}  the current time in seconds since the Unix epoch
+//	{moniker}    a random adjective-animal pair, as generated by default
+//
+// For example, "{chart}-{timestamp}" gives names such as "mysql-1500000000".
+type PatternNameGenerator struct {
+	Pattern string
+
+	now func() time.Time
+}
+
+// GenerateName expands the pattern for ch.
+func (g PatternNameGenerator) GenerateName(ch *chart.Chart) (string, error) {
+	now := g.now
+	if now == nil {
+		now = time.Now
+	}
+	chartName := ""
+	if ch != nil && ch.Metadata != nil {
+		chartName = ch.Metadata.Name
+	}
+	r := strings.NewReplacer(
+		"{chart}", chartName,
+		"{timestamp}", strconv.FormatInt(now().Unix(), 10),
+		"{moniker}", moniker.New().NameSep("-"),
+	)
+	return r.Replace(g.Pattern), nil
+}
@@ -0,0 +1,149 @@
+/*
+Copyright 2017 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tiller
+
+import (
+	"fmt"
+	"path"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/ghodss/yaml"
+
+	"k8s.io/helm/pkg/proto/hapi/release"
+)
+
+// protectedResourcesAnno is the release annotation that lists the resources
+// an upgrade may only change with AllowProtectedChanges, as comma-separated
+// "Kind/name" patterns, e.g. "StatefulSet/db,PersistentVolumeClaim/*". A kind
+// on its own protects every resource of that kind.
+const protectedResourcesAnno = "helm.sh/protected-resources"
+
+// parseProtectedResources returns the "Kind/name" patterns of the value of
+// the protected resources annotation.
+func parseProtectedResources(v string) ([]string, error) {
+	var patterns []string
+	for _, p := range strings.Split(v, ",") {
+		p = strings.TrimSpace(p)
+		if p == "" {
+			continue
+		}
+		if !strings.Contains(p, "/") {
+			p += "/*"
+		}
+		if _, err := path.Match(p, ""); err != nil {
+			return nil, fmt.Errorf("invalid %s pattern %q: %s", protectedResourcesAnno, p, err)
+		}
+		patterns = append(patterns, p)
+	}
+	return patterns, nil
+}
+
+// isProtected reports whether the resource key, "Kind/name", matches one of
+// patterns.
+func isProtected(key string, patterns []string) bool {
+	for _, p := range patterns {
+		if ok, _ := path.Match(p, key); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// keepProtection carries the protected resources annotation of current over
+// to target, unless the upgrade sets it. The resources stay protected until
+// the annotation is removed from the release.
+func keepProtection(current, target *release.Release) {
+	v, ok := current.Info.Annotations[protectedResourcesAnno]
+	if !ok {
+		return
+	}
+	if _, ok := target.Info.Annotations[protectedResourcesAnno]; ok {
+		return
+	}
+	annotations := map[string]string{protectedResourcesAnno: v}
+	for k, v := range target.Info.Annotations {
+		annotations[k] = v
+	}
+	target.Info.Annotations = annotations
+}
+
+// checkProtectedResources returns an error naming the protected resources of
+// current that upgrading it to target would add, change or, if prune is set,
+// delete. The resources are protected by the annotation of current, so that
+// an upgrade cannot lift the protection it is checked against.
+func checkProtectedResources(current, target *release.Release, prune bool) error {
+	patterns, err := parseProtectedResources(current.Info.Annotations[protectedResourcesAnno])
+	if err != nil || len(patterns) == 0 {
+		return err
+	}
+
+	cur, err := manifestsByResource(current.Manifest)
+	if err != nil {
+		return err
+	}
+	next, err := manifestsByResource(target.Manifest)
+	if err != nil {
+		return err
+	}
+	keys := []string{}
+	for key := range cur {
+		keys = append(keys, key)
+	}
+	for key := range next {
+		if _, ok := cur[key]; !ok {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	var changed []string
+	for _, key := range keys {
+		if !isProtected(key, patterns) {
+			continue
+		}
+		c, inCur := cur[key]
+		n, inNext := next[key]
+		switch {
+		case !inCur:
+			changed = append(changed, key+" (added)")
+		case !inNext:
+			// Resources removed from the chart are not deleted if the
+			// upgrade keeps them.
+			if prune && !hasKeepPolicy(c.head) {
+				changed = append(changed, key+" (deleted)")
+			}
+		case !sameObject(c.content, n.content):
+			changed = append(changed, key+" (changed)")
+		}
+	}
+	if len(changed) == 0 {
+		return nil
+	}
+	return fmt.Errorf("the upgrade of %s changes resources that its %s annotation protects: %s\nReview the changes and upgrade with --allow-protected-changes to apply them", current.Name, protectedResourcesAnno, strings.Join(changed, ", "))
+}
+
+// sameObject reports whether two manifest documents describe the same
+// object, regardless of formatting and comments.
+func sameObject(a, b string) bool {
+	var oa, ob map[string]interface{}
+	if yaml.Unmarshal([]byte(a), &oa) != nil || yaml.Unmarshal([]byte(b), &ob) != nil {
+		return a == b
+	}
+	return reflect.DeepEqual(oa, ob)
+}
@@ -0,0 +1,200 @@
+/*
+Copyright 2017 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tiller
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"k8s.io/helm/pkg/helm"
+	"k8s.io/helm/pkg/proto/hapi/chart"
+	"k8s.io/helm/pkg/proto/hapi/release"
+	"k8s.io/helm/pkg/proto/hapi/services"
+)
+
+func TestParseProtectedResources(t *testing.T) {
+	patterns, err := parseProtectedResources(" StatefulSet/db, PersistentVolumeClaim ,,Secret/*-tls")
+	if err != nil {
+		t.Fatal(err)
+	}
+	expect := []string{"StatefulSet/db", "PersistentVolumeClaim/*", "Secret/*-tls"}
+	if !reflect.DeepEqual(patterns, expect) {
+		t.Errorf("Expected %v, got %v", expect, patterns)
+	}
+	for key, protected := range map[string]bool{
+		"StatefulSet/db":           true,
+		"StatefulSet/web":          false,
+		"PersistentVolumeClaim/db": true,
+		"Secret/web-tls":           true,
+		"Secret/web":               false,
+	} {
+		if isProtected(key, patterns) != protected {
+			t.Errorf("Expected %s to be protected: %t", key, protected)
+		}
+	}
+
+	if _, err := parseProtectedResources("StatefulSet/[db"); err == nil {
+		t.Error("Expected an invalid pattern to be rejected")
+	}
+}
+
+func protectedRelease(manifest string) *release.Release {
+	rel := releaseStub()
+	rel.Manifest = manifest
+	rel.Info.Annotations = map[string]string{protectedResourcesAnno: "ReplicationController/db,PersistentVolumeClaim"}
+	return rel
+}
+
+func TestCheckProtectedResources(t *testing.T) {
+	current := protectedRelease(`---
+# Source: hello/templates/db.yaml
+apiVersion: v1
+kind: ReplicationController
+metadata:
+  name: db
+spec:
+  replicas: 1
+---
+# Source: hello/templates/data.yaml
+apiVersion: v1
+kind: PersistentVolumeClaim
+metadata:
+  name: data
+---
+# Source: hello/templates/settings.yaml
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: settings
+data:
+  greeting: hello
+`)
+
+	tests := []struct {
+		name     string
+		manifest string
+		prune    bool
+		expect   string
+	}{
+		{
+			name: "unprotected changes",
+			manifest: `---
+# Source: hello/templates/db.yaml
+apiVersion: v1
+kind: ReplicationController
+metadata: {name: db}
+spec: {replicas: 1}
+---
+# Source: hello/templates/settings.yaml
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: settings
+data:
+  greeting: hi
+`,
+		},
+		{
+			name: "protected changes",
+			manifest: `---
+apiVersion: v1
+kind: ReplicationController
+metadata:
+  name: db
+spec:
+  replicas: 3
+---
+apiVersion: v1
+kind: PersistentVolumeClaim
+metadata:
+  name: logs
+`,
+			prune:  true,
+			expect: "PersistentVolumeClaim/data (deleted), PersistentVolumeClaim/logs (added), ReplicationController/db (changed)",
+		},
+		{
+			name: "removal without pruning",
+			manifest: `---
+apiVersion: v1
+kind: ReplicationController
+metadata:
+  name: db
+spec:
+  replicas: 1
+`,
+		},
+	}
+	for _, tt := range tests {
+		err := checkProtectedResources(current, &release.Release{Manifest: tt.manifest}, tt.prune)
+		if tt.expect == "" {
+			if err != nil {
+				t.Errorf("%s: unexpected error: %s", tt.name, err)
+			}
+			continue
+		}
+		if err == nil || !strings.Contains(err.Error(), "protects: "+tt.expect+"\n") {
+			t.Errorf("%s: expected %q to be reported, got %v", tt.name, tt.expect, err)
+		}
+	}
+}
+
+func protectedChart() *chart.Chart {
+	return &chart.Chart{
+		Metadata: &chart.Metadata{Name: "hello"},
+		Templates: []*chart.Template{
+			{Name: "templates/db.yaml", Data: []byte("apiVersion: v1\nkind: ReplicationController\nmetadata:\n  name: db\nspec:\n  replicas: {{ .Values.replicas }}\n")},
+			{Name: "templates/settings.yaml", Data: []byte("apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: settings\ndata:\n  greeting: {{ .Values.greeting }}\n")},
+		},
+	}
+}
+
+func TestUpdateRelease_ProtectedResources(t *testing.T) {
+	c := helm.NewContext()
+	rs := rsFixture()
+	rel := protectedRelease("---\n# Source: hello/templates/db.yaml\napiVersion: v1\nkind: ReplicationController\nmetadata:\n  name: db\nspec:\n  replicas: 1\n")
+	rs.env.Releases.Create(rel)
+
+	req := &services.UpdateReleaseRequest{
+		Name:   rel.Name,
+		Chart:  protectedChart(),
+		Values: &chart.Config{Raw: "replicas: 3\ngreeting: hello\n"},
+	}
+	_, err := rs.UpdateRelease(c, req)
+	if err == nil || !strings.Contains(err.Error(), "ReplicationController/db (changed)") {
+		t.Fatalf("Expected the protected change to abort the upgrade, got %v", err)
+	}
+	if last, _ := rs.env.Releases.Last(rel.Name); last.Version != rel.Version {
+		t.Errorf("Expected no new revision, got %d", last.Version)
+	}
+
+	req.AllowProtectedChanges = true
+	res, err := rs.UpdateRelease(c, req)
+	if err != nil {
+		t.Fatalf("Failed upgrade: %s", err)
+	}
+	// The protection is kept by upgrades that do not set it.
+	if v := res.Release.Info.Annotations[protectedResourcesAnno]; v != "ReplicationController/db,PersistentVolumeClaim" {
+		t.Errorf("Expected the protection to be kept, got %q", v)
+	}
+
+	req.AllowProtectedChanges = false
+	req.Values = &chart.Config{Raw: "replicas: 3\ngreeting: hi\n"}
+	if _, err := rs.UpdateRelease(c, req); err != nil {
+		t.Errorf("Expected an upgrade of unprotected resources to pass, got %s", err)
+	}
+}
@@ -0,0 +1,247 @@
+/*
+Copyright 2017 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tiller
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/ghodss/yaml"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/kubernetes/pkg/client/clientset_generated/internalclientset"
+
+	"k8s.io/helm/pkg/proto/hapi/release"
+	util "k8s.io/helm/pkg/releaseutil"
+)
+
+// objectRef names a ServiceAccount, Secret or ConfigMap.
+type objectRef struct {
+	Kind, Namespace, Name string
+}
+
+func (o objectRef) String() string {
+	return fmt.Sprintf("%s %q", o.Kind, o.Name)
+}
+
+// workloadRefs are the objects that the pod specs of a resource refer to.
+type workloadRefs struct {
+	// resource is the "Kind/name" of the resource.
+	resource string
+	refs     []objectRef
+}
+
+// collectReferences returns the resources of manifests, sorted by kind and
+// name, whose pod specs, at any depth, refer to ServiceAccounts, Secrets or
+// ConfigMaps, and the ServiceAccounts, Secrets and ConfigMaps that manifests
+// define. Optional references, and the "default" ServiceAccount, are left
+// out.
+func collectReferences(namespace string, manifests ...string) ([]workloadRefs, map[objectRef]bool) {
+	var workloads []workloadRefs
+	defined := map[objectRef]bool{}
+	for _, m := range manifests {
+		for _, doc := range util.SplitManifests(m) {
+			var obj map[string]interface{}
+			if err := yaml.Unmarshal([]byte(doc), &obj); err != nil || obj == nil {
+				continue
+			}
+			kind, _ := obj["kind"].(string)
+			meta, _ := obj["metadata"].(map[string]interface{})
+			name, _ := meta["name"].(string)
+			ns, _ := meta["namespace"].(string)
+			if ns == "" {
+				ns = namespace
+			}
+			switch kind {
+			case "ServiceAccount", "Secret", "ConfigMap":
+				defined[objectRef{kind, ns, name}] = true
+				continue
+			}
+			refs := map[objectRef]bool{}
+			collectPodReferences(obj, ns, refs)
+			if len(refs) == 0 {
+				continue
+			}
+			w := workloadRefs{resource: kind + "/" + name}
+			for ref := range refs {
+				w.refs = append(w.refs, ref)
+			}
+			// ServiceAccounts first, then Secrets and ConfigMaps.
+			sort.Slice(w.refs, func(i, j int) bool {
+				if w.refs[i].Kind != w.refs[j].Kind {
+					return w.refs[i].Kind > w.refs[j].Kind
+				}
+				return w.refs[i].Name < w.refs[j].Name
+			})
+			workloads = append(workloads, w)
+		}
+	}
+	sort.Slice(workloads, func(i, j int) bool { return workloads[i].resource < workloads[j].resource })
+	return workloads, defined
+}
+
+// collectPodReferences adds the references of the pod specs in v to refs.
+func collectPodReferences(v interface{}, ns string, refs map[objectRef]bool) {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		if containers, ok := v["containers"].([]interface{}); ok {
+			addPodSpecReferences(v, containers, ns, refs)
+		}
+		for _, sub := range v {
+			collectPodReferences(sub, ns, refs)
+		}
+	case []interface{}:
+		for _, sub := range v {
+			collectPodReferences(sub, ns, refs)
+		}
+	}
+}
+
+func addPodSpecReferences(spec map[string]interface{}, containers []interface{}, ns string, refs map[objectRef]bool) {
+	add := func(kind string, ref map[string]interface{}, key string) {
+		if ref == nil || ref["optional"] == true {
+			return
+		}
+		if name, ok := ref[key].(string); ok && name != "" {
+			refs[objectRef{kind, ns, name}] = true
+		}
+	}
+
+	sa, _ := spec["serviceAccountName"].(string)
+	if sa == "" {
+		sa, _ = spec["serviceAccount"].(string)
+	}
+	if sa != "" && sa != "default" {
+		refs[objectRef{"ServiceAccount", ns, sa}] = true
+	}
+	for _, s := range maps(spec["imagePullSecrets"]) {
+		add("Secret", s, "name")
+	}
+	for _, vol := range maps(spec["volumes"]) {
+		add("Secret", child(vol, "secret"), "secretName")
+		add("ConfigMap", child(vol, "configMap"), "name")
+		for _, src := range maps(child(vol, "projected")["sources"]) {
+			add("Secret", child(src, "secret"), "name")
+			add("ConfigMap", child(src, "configMap"), "name")
+		}
+	}
+	init, _ := spec["initContainers"].([]interface{})
+	for _, list := range [][]interface{}{containers, init} {
+		for _, c := range maps(list) {
+			for _, from := range maps(c["envFrom"]) {
+				add("Secret", child(from, "secretRef"), "name")
+				add("ConfigMap", child(from, "configMapRef"), "name")
+			}
+			for _, env := range maps(c["env"]) {
+				valueFrom := child(env, "valueFrom")
+				add("Secret", child(valueFrom, "secretKeyRef"), "name")
+				add("ConfigMap", child(valueFrom, "configMapKeyRef"), "name")
+			}
+		}
+	}
+}
+
+// maps returns the maps in the list v.
+func maps(v interface{}) []map[string]interface{} {
+	list, _ := v.([]interface{})
+	var ms []map[string]interface{}
+	for _, item := range list {
+		if m, ok := item.(map[string]interface{}); ok {
+			ms = append(ms, m)
+		}
+	}
+	return ms
+}
+
+// child returns the map under key in m, or nil.
+func child(m map[string]interface{}, key string) map[string]interface{} {
+	if m == nil {
+		return nil
+	}
+	c, _ := m[key].(map[string]interface{})
+	return c
+}
+
+// verifyReferences checks that the ServiceAccounts, Secrets and ConfigMaps
+// that the pods of r, including those of its hooks, refer to are defined by r
+// or exist in its cluster. It returns an error listing the missing ones by
+// the resource that refers to them.
+func (s *ReleaseServer) verifyReferences(r *release.Release) error {
+	manifests := []string{r.Manifest}
+	for _, h := range r.Hooks {
+		manifests = append(manifests, h.Manifest)
+	}
+	workloads, defined := collectReferences(r.Namespace, manifests...)
+	if len(workloads) == 0 {
+		return nil
+	}
+	kc, err := s.releaseCluster(r)
+	if err != nil {
+		return err
+	}
+
+	exists := map[objectRef]bool{}
+	var missing []string
+	for _, w := range workloads {
+		var refs []string
+		for _, ref := range w.refs {
+			if defined[ref] {
+				continue
+			}
+			found, checked := exists[ref]
+			if !checked {
+				if found, err = objectExists(kc.clientset, ref); err != nil {
+					return err
+				}
+				exists[ref] = found
+			}
+			if !found {
+				refs = append(refs, ref.String())
+			}
+		}
+		if len(refs) > 0 {
+			missing = append(missing, fmt.Sprintf("%s needs %s", w.resource, strings.Join(refs, ", ")))
+		}
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("release %s refers to objects that neither it nor the cluster has: %s", r.Name, strings.Join(missing, "; "))
+	}
+	return nil
+}
+
+// objectExists returns whether the object of ref exists.
+func objectExists(clientset internalclientset.Interface, ref objectRef) (bool, error) {
+	var err error
+	switch ref.Kind {
+	case "ServiceAccount":
+		_, err = clientset.Core().ServiceAccounts(ref.Namespace).Get(ref.Name, metav1.GetOptions{})
+	case "Secret":
+		_, err = clientset.Core().Secrets(ref.Namespace).Get(ref.Name, metav1.GetOptions{})
+	case "ConfigMap":
+		_, err = clientset.Core().ConfigMaps(ref.Namespace).Get(ref.Name, metav1.GetOptions{})
+	default:
+		return false, fmt.Errorf("cannot check %s references", ref.Kind)
+	}
+	if errors.IsNotFound(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("cannot read %s %s/%s: %s", ref.Kind, ref.Namespace, ref.Name, err)
+	}
+	return true, nil
+}
@@ -0,0 +1,191 @@
+/*
+Copyright 2017 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tiller
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/kubernetes/pkg/api"
+
+	"k8s.io/helm/pkg/helm"
+	"k8s.io/helm/pkg/proto/hapi/chart"
+	"k8s.io/helm/pkg/proto/hapi/services"
+)
+
+var manifestWithReferences = `apiVersion: v1
+kind: ReplicationController
+metadata:
+  name: web
+spec:
+  template:
+    spec:
+      serviceAccountName: web
+      imagePullSecrets:
+      - name: registry
+      volumes:
+      - name: tls
+        secret:
+          secretName: web-tls
+      - name: extra
+        configMap:
+          name: extra
+          optional: true
+      - name: bundle
+        projected:
+          sources:
+          - configMap:
+              name: ca-bundle
+      containers:
+      - name: web
+        image: nginx:1.13
+        envFrom:
+        - configMapRef:
+            name: web-settings
+        env:
+        - name: PASSWORD
+          valueFrom:
+            secretKeyRef:
+              name: db
+              key: password
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: web-settings
+---
+apiVersion: v1
+kind: Pod
+metadata:
+  name: migrate
+  namespace: jobs
+spec:
+  containers:
+  - name: migrate
+    image: migrate:1.0
+    env:
+    - name: PASSWORD
+      valueFrom:
+        secretKeyRef:
+          name: db
+          key: password
+---
+apiVersion: v1
+kind: Pod
+metadata:
+  name: plain
+spec:
+  containers:
+  - name: plain
+    image: busybox
+`
+
+func TestCollectReferences(t *testing.T) {
+	workloads, defined := collectReferences("spaced", manifestWithReferences)
+
+	expect := []workloadRefs{
+		{resource: "Pod/migrate", refs: []objectRef{
+			{"Secret", "jobs", "db"},
+		}},
+		{resource: "ReplicationController/web", refs: []objectRef{
+			{"ServiceAccount", "spaced", "web"},
+			{"Secret", "spaced", "db"},
+			{"Secret", "spaced", "registry"},
+			{"Secret", "spaced", "web-tls"},
+			{"ConfigMap", "spaced", "ca-bundle"},
+			{"ConfigMap", "spaced", "web-settings"},
+		}},
+	}
+	if !reflect.DeepEqual(workloads, expect) {
+		t.Errorf("Expected %+v, got %+v", expect, workloads)
+	}
+	if !reflect.DeepEqual(defined, map[objectRef]bool{{"ConfigMap", "spaced", "web-settings"}: true}) {
+		t.Errorf("Unexpected defined objects %v", defined)
+	}
+}
+
+func referencesChart() *chart.Chart {
+	return &chart.Chart{
+		Metadata: &chart.Metadata{Name: "hello"},
+		Templates: []*chart.Template{
+			{Name: "templates/web.yaml", Data: []byte(manifestWithReferences)},
+		},
+	}
+}
+
+func TestInstallRelease_VerifyReferences(t *testing.T) {
+	c := helm.NewContext()
+	rs := rsFixture()
+	rs.clientset.Core().ServiceAccounts("spaced").Create(&api.ServiceAccount{ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "spaced"}})
+	rs.clientset.Core().Secrets("spaced").Create(&api.Secret{ObjectMeta: metav1.ObjectMeta{Name: "registry", Namespace: "spaced"}})
+	rs.clientset.Core().Secrets("spaced").Create(&api.Secret{ObjectMeta: metav1.ObjectMeta{Name: "db", Namespace: "spaced"}})
+
+	req := &services.InstallReleaseRequest{
+		Name:             "references",
+		Namespace:        "spaced",
+		Chart:            referencesChart(),
+		VerifyReferences: true,
+	}
+	_, err := rs.InstallRelease(c, req)
+	if err == nil {
+		t.Fatal("Expected the missing references to fail the install")
+	}
+	expect := `Pod/migrate needs Secret "db"; ReplicationController/web needs Secret "web-tls", ConfigMap "ca-bundle"`
+	if !strings.Contains(err.Error(), expect) {
+		t.Errorf("Expected %q in %q", expect, err)
+	}
+	if _, err := rs.env.Releases.Last("references"); err == nil {
+		t.Error("Expected no release to be recorded")
+	}
+
+	rs.clientset.Core().Secrets("spaced").Create(&api.Secret{ObjectMeta: metav1.ObjectMeta{Name: "web-tls", Namespace: "spaced"}})
+	rs.clientset.Core().ConfigMaps("spaced").Create(&api.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "ca-bundle", Namespace: "spaced"}})
+	rs.clientset.Core().Secrets("jobs").Create(&api.Secret{ObjectMeta: metav1.ObjectMeta{Name: "db", Namespace: "jobs"}})
+	if _, err := rs.InstallRelease(c, req); err != nil {
+		t.Fatalf("Failed install: %s", err)
+	}
+
+	// References are not checked unless requested.
+	req.Name = "unchecked"
+	req.Namespace = "empty"
+	req.VerifyReferences = false
+	if _, err := rs.InstallRelease(c, req); err != nil {
+		t.Fatalf("Failed install: %s", err)
+	}
+}
+
+func TestUpdateRelease_VerifyReferences(t *testing.T) {
+	c := helm.NewContext()
+	rs := rsFixture()
+	rel := releaseStub()
+	rel.Namespace = "spaced"
+	rs.env.Releases.Create(rel)
+
+	_, err := rs.UpdateRelease(c, &services.UpdateReleaseRequest{
+		Name:             rel.Name,
+		Chart:            referencesChart(),
+		VerifyReferences: true,
+	})
+	if err == nil || !strings.Contains(err.Error(), `ServiceAccount "web"`) {
+		t.Fatalf("Expected the missing service account to be reported, got %v", err)
+	}
+	if last, _ := rs.env.Releases.Last(rel.Name); last.Version != rel.Version {
+		t.Errorf("Expected no new revision, got %d", last.Version)
+	}
+}
@@ -0,0 +1,98 @@
+/*
+Copyright 2017 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tiller
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	ctx "golang.org/x/net/context"
+	"k8s.io/apimachinery/pkg/util/validation"
+
+	"k8s.io/helm/pkg/proto/hapi/release"
+	"k8s.io/helm/pkg/proto/hapi/services"
+)
+
+// AnnotateRelease adds annotations to, and removes annotations from, a
+// revision of a release. The stored revision is changed in place: no new
+// revision is created and nothing is applied to the cluster.
+func (s *ReleaseServer) AnnotateRelease(c ctx.Context, req *services.AnnotateReleaseRequest) (*services.AnnotateReleaseResponse, error) {
+	if !ValidName.MatchString(req.Name) {
+		return nil, errMissingRelease
+	}
+	if err := validateAnnotations(req.Annotations); err != nil {
+		return nil, err
+	}
+	if req.Version < 0 {
+		return nil, errInvalidRevision
+	}
+
+	if err := s.lockRelease(req.Name); err != nil {
+		return nil, err
+	}
+	defer s.env.Releases.UnlockRelease(req.Name)
+
+	var rel *release.Release
+	var err error
+	if req.Version == 0 {
+		if rel, err = s.env.Releases.Last(req.Name); err != nil {
+			return nil, fmt.Errorf("getting release %q: %s", req.Name, err)
+		}
+	} else if rel, err = s.env.Releases.Get(req.Name, req.Version); err != nil {
+		return nil, fmt.Errorf("getting release '%s' (v%d): %s", req.Name, req.Version, err)
+	}
+
+	if rel.Info.Annotations == nil {
+		rel.Info.Annotations = map[string]string{}
+	}
+	for k, v := range req.Annotations {
+		rel.Info.Annotations[k] = v
+	}
+	for _, k := range req.Remove {
+		delete(rel.Info.Annotations, k)
+	}
+	if err := s.env.Releases.Update(rel); err != nil {
+		return nil, err
+	}
+
+	log := s.requestLogger("annotate", rel.Name, rel.Version)
+	log.Infof("Annotations changed by %s", caller(c))
+	return &services.AnnotateReleaseResponse{Release: rel}, nil
+}
+
+// validateAnnotations checks that the keys of annotations are valid
+// Kubernetes annotation keys, such as "ci.example.com/pipeline", and that
+// the annotations Tiller acts on have valid values.
+func validateAnnotations(annotations map[string]string) error {
+	var invalid []string
+	for k := range annotations {
+		if errs := validation.IsQualifiedName(k); len(errs) > 0 {
+			invalid = append(invalid, fmt.Sprintf("%q: %s", k, strings.Join(errs, "; ")))
+		}
+	}
+	if len(invalid) > 0 {
+		sort.Strings(invalid)
+		return fmt.Errorf("invalid annotation keys: %s", strings.Join(invalid, ", "))
+	}
+	if v, ok := annotations[protectedResourcesAnno]; ok {
+		if _, err := parseProtectedResources(v); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,139 @@
+/*
+Copyright 2017 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tiller
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"k8s.io/helm/pkg/helm"
+	"k8s.io/helm/pkg/proto/hapi/release"
+	"k8s.io/helm/pkg/proto/hapi/services"
+)
+
+func TestAnnotateRelease(t *testing.T) {
+	c := helm.NewContext()
+	rs := rsFixture()
+	rel := releaseStub()
+	rel.Info.Annotations = map[string]string{"pipeline": "41"}
+	rs.env.Releases.Create(rel)
+	upgraded := upgradeReleaseVersion(rel)
+	rs.env.Releases.Update(rel)
+	rs.env.Releases.Create(upgraded)
+
+	res, err := rs.AnnotateRelease(c, &services.AnnotateReleaseRequest{
+		Name:        rel.Name,
+		Annotations: map[string]string{"approved-by": "alice"},
+	})
+	if err != nil {
+		t.Fatalf("Failed annotate: %s", err)
+	}
+	if res.Release.Version != upgraded.Version {
+		t.Errorf("Expected the latest revision %d to be annotated, got %d", upgraded.Version, res.Release.Version)
+	}
+	stored, err := rs.env.Releases.Get(rel.Name, upgraded.Version)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expect := map[string]string{"approved-by": "alice"}; !reflect.DeepEqual(stored.Info.Annotations, expect) {
+		t.Errorf("Expected annotations %v, got %v", expect, stored.Info.Annotations)
+	}
+	if stored.Info.Status.Code != release.Status_DEPLOYED {
+		t.Errorf("Expected the status to be unchanged, got %s", stored.Info.Status.Code)
+	}
+	if h, _ := rs.env.Releases.History(rel.Name); len(h) != 2 {
+		t.Errorf("Expected no new revision, got %d revisions", len(h))
+	}
+
+	// An older revision can be annotated too, and annotations removed.
+	res, err = rs.AnnotateRelease(c, &services.AnnotateReleaseRequest{
+		Name:        rel.Name,
+		Version:     1,
+		Annotations: map[string]string{"git-commit": "4f2c1e0"},
+		Remove:      []string{"pipeline"},
+	})
+	if err != nil {
+		t.Fatalf("Failed annotate: %s", err)
+	}
+	if expect := map[string]string{"git-commit": "4f2c1e0"}; !reflect.DeepEqual(res.Release.Info.Annotations, expect) {
+		t.Errorf("Expected annotations %v, got %v", expect, res.Release.Info.Annotations)
+	}
+}
+
+func TestAnnotateReleaseErrors(t *testing.T) {
+	c := helm.NewContext()
+	rs := rsFixture()
+	rs.env.Releases.Create(releaseStub())
+
+	tests := []struct {
+		name   string
+		req    *services.AnnotateReleaseRequest
+		expect string
+	}{
+		{"missing release", &services.AnnotateReleaseRequest{Name: "no-such-release"}, "not found"},
+		{"missing revision", &services.AnnotateReleaseRequest{Name: "angry-panda", Version: 7}, "getting release 'angry-panda' (v7)"},
+		{"invalid key", &services.AnnotateReleaseRequest{Name: "angry-panda", Annotations: map[string]string{"approved by": "alice"}}, `invalid annotation keys: "approved by"`},
+		{"invalid name", &services.AnnotateReleaseRequest{Name: "Angry Panda"}, errMissingRelease.Error()},
+	}
+	for _, tt := range tests {
+		_, err := rs.AnnotateRelease(c, tt.req)
+		if err == nil || !strings.Contains(err.Error(), tt.expect) {
+			t.Errorf("%s: expected an error containing %q, got %v", tt.name, tt.expect, err)
+		}
+	}
+}
+
+func TestInstallAndUpdateRelease_Annotations(t *testing.T) {
+	c := helm.NewContext()
+	rs := rsFixture()
+
+	installed, err := rs.InstallRelease(c, &services.InstallReleaseRequest{
+		Name:        "annotated",
+		Namespace:   "spaced",
+		Chart:       chartStub(),
+		Annotations: map[string]string{"git-commit": "4f2c1e0"},
+	})
+	if err != nil {
+		t.Fatalf("Failed install: %s", err)
+	}
+	if installed.Release.Info.Annotations["git-commit"] != "4f2c1e0" {
+		t.Errorf("Expected the install annotations to be recorded, got %v", installed.Release.Info.Annotations)
+	}
+
+	// Annotations belong to a revision: an upgrade records its own.
+	upgraded, err := rs.UpdateRelease(c, &services.UpdateReleaseRequest{
+		Name:        "annotated",
+		Chart:       chartStub(),
+		Annotations: map[string]string{"git-commit": "9a7b3d2"},
+	})
+	if err != nil {
+		t.Fatalf("Failed update: %s", err)
+	}
+	if upgraded.Release.Info.Annotations["git-commit"] != "9a7b3d2" {
+		t.Errorf("Expected the upgrade annotations to be recorded, got %v", upgraded.Release.Info.Annotations)
+	}
+
+	_, err = rs.UpdateRelease(c, &services.UpdateReleaseRequest{
+		Name:        "annotated",
+		Chart:       chartStub(),
+		Annotations: map[string]string{"-bad": "x"},
+	})
+	if err == nil || !strings.Contains(err.Error(), "invalid annotation keys") {
+		t.Errorf("Expected invalid annotation keys to be rejected, got %v", err)
+	}
+}
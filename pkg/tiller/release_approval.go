@@ -0,0 +1,190 @@
+/*
+Copyright 2017 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tiller
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	ctx "golang.org/x/net/context"
+
+	"k8s.io/helm/pkg/logging"
+	"k8s.io/helm/pkg/proto/hapi/release"
+	"k8s.io/helm/pkg/proto/hapi/services"
+)
+
+// defaultApprovalTimeout is how long an upgrade waits for approval when the
+// request does not say.
+const defaultApprovalTimeout = time.Hour
+
+// approvalDecision is the answer given to an upgrade awaiting approval.
+type approvalDecision struct {
+	approved bool
+	by       string
+	reason   string
+}
+
+// approvalGate holds the upgrades that are waiting for approval, by release
+// name. As the release stays locked while its upgrade waits, there is at most
+// one per release. The zero value is ready to use.
+type approvalGate struct {
+	mu      sync.Mutex
+	pending map[string]chan approvalDecision
+}
+
+// await blocks until a decision is made on the named release, or timeout
+// passes. ok is false if it timed out.
+func (g *approvalGate) await(name string, timeout time.Duration) (d approvalDecision, ok bool) {
+	ch := make(chan approvalDecision, 1)
+	g.mu.Lock()
+	if g.pending == nil {
+		g.pending = make(map[string]chan approvalDecision)
+	}
+	g.pending[name] = ch
+	g.mu.Unlock()
+
+	select {
+	case d := <-ch:
+		return d, true
+	case <-time.After(timeout):
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if g.pending[name] == ch {
+		delete(g.pending, name)
+		return approvalDecision{}, false
+	}
+	// A decision was made while the timer fired.
+	return <-ch, true
+}
+
+// decide passes d to the upgrade waiting on the named release. It returns
+// false if no upgrade is waiting, e.g. because Tiller was restarted since the
+// upgrade started.
+func (g *approvalGate) decide(name string, d approvalDecision) bool {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	ch, ok := g.pending[name]
+	if !ok {
+		return false
+	}
+	delete(g.pending, name)
+	ch <- d
+	return true
+}
+
+// awaitApproval records updated as awaiting approval and waits for it to be
+// approved. Only the pre-upgrade hooks have run at this point; the resources
+// of the release are untouched until the upgrade is approved.
+//
+// If the upgrade is rejected, or not approved in time, updated is recorded as
+// failed and an error is returned. current is left deployed.
+func (s *ReleaseServer) awaitApproval(log logging.Logger, updated *release.Release, req *services.UpdateReleaseRequest) error {
+	timeout := time.Duration(req.ApprovalTimeout) * time.Second
+	if timeout <= 0 {
+		timeout = defaultApprovalTimeout
+	}
+
+	// Copies are recorded, as other requests read the record while the
+	// upgrade waits.
+	updated.Info.Status.Code = release.Status_AWAITING_APPROVAL
+	updated.Info.Description = fmt.Sprintf("Awaiting approval (rejected after %s)", timeout)
+	s.recordRelease(proto.Clone(updated).(*release.Release), false)
+	log.Infof("Waiting up to %s for the upgrade to be approved", timeout)
+
+	d, ok := s.approvals.await(updated.Name, timeout)
+	switch {
+	case !ok:
+		updated.Info.Description = fmt.Sprintf("Upgrade rejected: not approved within %s", timeout)
+	case !d.approved:
+		updated.Info.Description = rejectedDescription(d)
+	default:
+		log.Infof("Upgrade approved by %s", d.by)
+		updated.Info.Status.Code = release.Status_UNKNOWN
+		updated.Info.Description = "Upgrade approved by " + d.by
+		s.recordRelease(proto.Clone(updated).(*release.Release), true)
+		return nil
+	}
+
+	log.Warnf("%s", updated.Info.Description)
+	updated.Info.Status.Code = release.Status_FAILED
+	s.recordRelease(proto.Clone(updated).(*release.Release), true)
+	return fmt.Errorf("upgrade of %q: %s", updated.Name, updated.Info.Description)
+}
+
+// rejectedDescription describes a rejected upgrade.
+func rejectedDescription(d approvalDecision) string {
+	msg := "Upgrade rejected by " + d.by
+	if d.reason != "" {
+		msg += ": " + d.reason
+	}
+	return msg
+}
+
+// ApproveRelease lets the upgrade of a release that awaits approval go ahead.
+func (s *ReleaseServer) ApproveRelease(c ctx.Context, req *services.ApproveReleaseRequest) (*services.ApproveReleaseResponse, error) {
+	rel, err := s.awaitingApproval(req.Name)
+	if err != nil {
+		return nil, err
+	}
+	if !s.approvals.decide(rel.Name, approvalDecision{approved: true, by: caller(c)}) {
+		return nil, fmt.Errorf("the upgrade to revision %d of %q is no longer running; reject it and upgrade again", rel.Version, rel.Name)
+	}
+	s.requestLogger("approve", rel.Name, rel.Version).Infof("Upgrade approved by %s", caller(c))
+	return &services.ApproveReleaseResponse{Release: rel}, nil
+}
+
+// RejectRelease aborts the upgrade of a release that awaits approval. The
+// revision that was waiting is recorded as failed.
+//
+// A revision that is left awaiting approval because Tiller was restarted
+// while its upgrade waited can only be rejected.
+func (s *ReleaseServer) RejectRelease(c ctx.Context, req *services.RejectReleaseRequest) (*services.RejectReleaseResponse, error) {
+	rel, err := s.awaitingApproval(req.Name)
+	if err != nil {
+		return nil, err
+	}
+	d := approvalDecision{by: caller(c), reason: req.Reason}
+	log := s.requestLogger("reject", rel.Name, rel.Version)
+	log.Infof("Upgrade rejected by %s", d.by)
+	if !s.approvals.decide(rel.Name, d) {
+		log.Warnf("No upgrade was waiting for approval; recording the revision as failed")
+		rel.Info.Status.Code = release.Status_FAILED
+		rel.Info.Description = rejectedDescription(d)
+		s.recordRelease(rel, true)
+	}
+	return &services.RejectReleaseResponse{Release: rel}, nil
+}
+
+// awaitingApproval returns the latest revision of the named release, which
+// must be awaiting approval.
+func (s *ReleaseServer) awaitingApproval(name string) (*release.Release, error) {
+	if !ValidName.MatchString(name) {
+		return nil, errMissingRelease
+	}
+	rel, err := s.env.Releases.Last(name)
+	if err != nil {
+		return nil, err
+	}
+	if rel.Info.Status.Code != release.Status_AWAITING_APPROVAL {
+		return nil, fmt.Errorf("release %q is not awaiting approval; revision %d is %s", name, rel.Version, rel.Info.Status.Code)
+	}
+	return rel, nil
+}
@@ -0,0 +1,299 @@
+/*
+Copyright 2017 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tiller
+
+import (
+	"errors"
+	"io"
+	"io/ioutil"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"k8s.io/helm/pkg/helm"
+	"k8s.io/helm/pkg/kube"
+	"k8s.io/helm/pkg/proto/hapi/chart"
+	"k8s.io/helm/pkg/proto/hapi/release"
+	"k8s.io/helm/pkg/proto/hapi/services"
+	"k8s.io/helm/pkg/tiller/environment"
+)
+
+// updateCountingKubeClient counts the calls that change the resources of a
+// release.
+type updateCountingKubeClient struct {
+	environment.PrintingKubeClient
+	updates int32
+}
+
+func (u *updateCountingKubeClient) UpdateWithOptions(ns string, currentReader, modifiedReader io.Reader, opts kube.UpdateOptions) error {
+	atomic.AddInt32(&u.updates, 1)
+	return nil
+}
+
+func approvalUpdateRequest(name string, timeout int64) *services.UpdateReleaseRequest {
+	return &services.UpdateReleaseRequest{
+		Name: name,
+		Chart: &chart.Chart{
+			Metadata: &chart.Metadata{Name: "hello"},
+			Templates: []*chart.Template{
+				{Name: "templates/hello", Data: []byte("hello: world")},
+			},
+		},
+		RequireApproval: true,
+		ApprovalTimeout: timeout,
+	}
+}
+
+// upgradeAsync runs the upgrade in the background and waits for it to await
+// approval.
+func upgradeAsync(t *testing.T, rs *ReleaseServer, req *services.UpdateReleaseRequest) <-chan error {
+	done := make(chan error, 1)
+	go func() {
+		_, err := rs.UpdateRelease(helm.NewContext(), req)
+		done <- err
+	}()
+
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		if rel, err := rs.env.Releases.Last(req.Name); err == nil && rel.Info.Status.Code == release.Status_AWAITING_APPROVAL {
+			return done
+		}
+		select {
+		case err := <-done:
+			t.Fatalf("Expected the upgrade to await approval, it returned %v", err)
+		case <-time.After(10 * time.Millisecond):
+		}
+	}
+	t.Fatal("Timed out waiting for the upgrade to await approval")
+	return nil
+}
+
+func TestUpdateRelease_Approved(t *testing.T) {
+	rs := rsFixture()
+	kc := &updateCountingKubeClient{}
+	rs.env.KubeClient = kc
+	rel := releaseStub()
+	rs.env.Releases.Create(rel)
+
+	done := upgradeAsync(t, rs, approvalUpdateRequest(rel.Name, 0))
+	if n := atomic.LoadInt32(&kc.updates); n != 0 {
+		t.Fatalf("Expected nothing to be applied before approval, got %d updates", n)
+	}
+	if deployed, err := rs.env.Releases.Deployed(rel.Name); err != nil || deployed.Version != rel.Version {
+		t.Fatalf("Expected revision %d to stay deployed while awaiting approval, got %v (%v)", rel.Version, deployed, err)
+	}
+
+	res, err := rs.ApproveRelease(helm.NewContext(), &services.ApproveReleaseRequest{Name: rel.Name})
+	if err != nil {
+		t.Fatalf("Failed approval: %s", err)
+	}
+	if res.Release.Version != 2 {
+		t.Errorf("Expected revision 2 to be approved, got %d", res.Release.Version)
+	}
+	if err := <-done; err != nil {
+		t.Fatalf("Failed upgrade: %s", err)
+	}
+	if n := atomic.LoadInt32(&kc.updates); n != 1 {
+		t.Errorf("Expected the upgrade to be applied once, got %d updates", n)
+	}
+
+	updated, err := rs.env.Releases.Get(rel.Name, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if updated.Info.Status.Code != release.Status_DEPLOYED {
+		t.Errorf("Expected the approved revision to be deployed, got %s", updated.Info.Status.Code)
+	}
+	original, err := rs.env.Releases.Get(rel.Name, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if original.Info.Status.Code != release.Status_SUPERSEDED {
+		t.Errorf("Expected the original revision to be superseded, got %s", original.Info.Status.Code)
+	}
+}
+
+// postHookFailingKubeClient fails every hook after the first one.
+type postHookFailingKubeClient struct {
+	environment.PrintingKubeClient
+	watches int32
+}
+
+func (p *postHookFailingKubeClient) WatchUntilReady(ns string, r io.Reader, timeout int64, shouldWait bool) error {
+	if atomic.AddInt32(&p.watches, 1) > 1 {
+		return errors.New("hook failed")
+	}
+	return nil
+}
+
+func TestUpdateRelease_ApprovedHookFails(t *testing.T) {
+	rs := rsFixture()
+	rs.env.KubeClient = &postHookFailingKubeClient{PrintingKubeClient: environment.PrintingKubeClient{Out: ioutil.Discard}}
+	rel := releaseStub()
+	rs.env.Releases.Create(rel)
+
+	req := approvalUpdateRequest(rel.Name, 0)
+	req.Chart.Templates = append(req.Chart.Templates, &chart.Template{Name: "templates/hooks", Data: []byte(manifestWithUpgradeHooks)})
+	done := upgradeAsync(t, rs, req)
+	if _, err := rs.ApproveRelease(helm.NewContext(), &services.ApproveReleaseRequest{Name: rel.Name}); err != nil {
+		t.Fatalf("Failed approval: %s", err)
+	}
+	if err := <-done; err == nil {
+		t.Fatal("Expected the post-upgrade hook to fail the upgrade")
+	}
+
+	updated, err := rs.env.Releases.Get(rel.Name, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if updated.Info.Status.Code != release.Status_FAILED {
+		t.Errorf("Expected the approved revision to fail, got %s", updated.Info.Status.Code)
+	}
+	original, err := rs.env.Releases.Get(rel.Name, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if original.Info.Status.Code != release.Status_SUPERSEDED {
+		t.Errorf("Expected the original revision to be superseded, got %s", original.Info.Status.Code)
+	}
+}
+
+func TestUpdateRelease_Rejected(t *testing.T) {
+	rs := rsFixture()
+	kc := &updateCountingKubeClient{}
+	rs.env.KubeClient = kc
+	rel := releaseStub()
+	rs.env.Releases.Create(rel)
+
+	done := upgradeAsync(t, rs, approvalUpdateRequest(rel.Name, 0))
+	if _, err := rs.RejectRelease(helm.NewContext(), &services.RejectReleaseRequest{Name: rel.Name, Reason: "change freeze"}); err != nil {
+		t.Fatalf("Failed rejection: %s", err)
+	}
+	err := <-done
+	if err == nil || !strings.Contains(err.Error(), "Upgrade rejected by unknown client: change freeze") {
+		t.Fatalf("Expected the upgrade to be rejected, got %v", err)
+	}
+	if n := atomic.LoadInt32(&kc.updates); n != 0 {
+		t.Errorf("Expected nothing to be applied, got %d updates", n)
+	}
+
+	rejected, err := rs.env.Releases.Get(rel.Name, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rejected.Info.Status.Code != release.Status_FAILED {
+		t.Errorf("Expected the rejected revision to be failed, got %s", rejected.Info.Status.Code)
+	}
+	if deployed, err := rs.env.Releases.Deployed(rel.Name); err != nil || deployed.Version != rel.Version {
+		t.Errorf("Expected revision %d to stay deployed, got %v (%v)", rel.Version, deployed, err)
+	}
+}
+
+func TestUpdateRelease_ApprovalTimeout(t *testing.T) {
+	rs := rsFixture()
+	kc := &updateCountingKubeClient{}
+	rs.env.KubeClient = kc
+	rel := releaseStub()
+	rs.env.Releases.Create(rel)
+
+	done := upgradeAsync(t, rs, approvalUpdateRequest(rel.Name, 1))
+	err := <-done
+	if err == nil || !strings.Contains(err.Error(), "not approved within 1s") {
+		t.Fatalf("Expected the upgrade to time out, got %v", err)
+	}
+	if n := atomic.LoadInt32(&kc.updates); n != 0 {
+		t.Errorf("Expected nothing to be applied, got %d updates", n)
+	}
+	rejected, err := rs.env.Releases.Get(rel.Name, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rejected.Info.Status.Code != release.Status_FAILED {
+		t.Errorf("Expected the timed out revision to be failed, got %s", rejected.Info.Status.Code)
+	}
+	if _, err := rs.ApproveRelease(helm.NewContext(), &services.ApproveReleaseRequest{Name: rel.Name}); err == nil {
+		t.Error("Expected a late approval to fail")
+	}
+}
+
+func TestApproveReleaseErrors(t *testing.T) {
+	c := helm.NewContext()
+	rs := rsFixture()
+	rs.env.Releases.Create(releaseStub())
+	rs.env.Releases.Create(namedReleaseStub("orphaned-panda", release.Status_AWAITING_APPROVAL))
+
+	tests := []struct {
+		name   string
+		expect string
+	}{
+		{"", "no release provided"},
+		{"missing-panda", "no revision"},
+		{"angry-panda", "is not awaiting approval"},
+		{"orphaned-panda", "is no longer running"},
+	}
+	for _, tt := range tests {
+		_, err := rs.ApproveRelease(c, &services.ApproveReleaseRequest{Name: tt.name})
+		if err == nil || !strings.Contains(err.Error(), tt.expect) {
+			t.Errorf("%q: expected an error containing %q, got %v", tt.name, tt.expect, err)
+		}
+	}
+}
+
+func TestRejectRelease_Orphaned(t *testing.T) {
+	rs := rsFixture()
+	rel := namedReleaseStub("orphaned-panda", release.Status_AWAITING_APPROVAL)
+	rs.env.Releases.Create(rel)
+
+	if _, err := rs.RejectRelease(helm.NewContext(), &services.RejectReleaseRequest{Name: rel.Name}); err != nil {
+		t.Fatalf("Failed rejection: %s", err)
+	}
+	stored, err := rs.env.Releases.Get(rel.Name, rel.Version)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stored.Info.Status.Code != release.Status_FAILED {
+		t.Errorf("Expected the orphaned revision to be failed, got %s", stored.Info.Status.Code)
+	}
+	if got := stored.Info.Description; got != "Upgrade rejected by unknown client" {
+		t.Errorf("Expected the rejection to be recorded, got description %q", got)
+	}
+}
+
+func TestApprovalGate(t *testing.T) {
+	var g approvalGate
+	if g.decide("angry-panda", approvalDecision{approved: true}) {
+		t.Error("Expected no upgrade to be waiting")
+	}
+
+	go func() {
+		for !g.decide("angry-panda", approvalDecision{approved: true, by: "alice"}) {
+			time.Sleep(time.Millisecond)
+		}
+	}()
+	d, ok := g.await("angry-panda", 5*time.Second)
+	if !ok || !d.approved || d.by != "alice" {
+		t.Errorf("Expected approval by alice, got %+v (decided: %t)", d, ok)
+	}
+
+	if _, ok := g.await("angry-panda", time.Millisecond); ok {
+		t.Error("Expected the wait to time out")
+	}
+	if g.decide("angry-panda", approvalDecision{}) {
+		t.Error("Expected no upgrade to be waiting after the timeout")
+	}
+}
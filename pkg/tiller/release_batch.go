@@ -0,0 +1,135 @@
+/*
+Copyright 2017 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tiller
+
+import (
+	"errors"
+	"fmt"
+
+	ctx "golang.org/x/net/context"
+
+	"k8s.io/helm/pkg/proto/hapi/release"
+	"k8s.io/helm/pkg/proto/hapi/services"
+)
+
+// BatchInstall installs the releases of a batch in order. If one fails, it
+// and the releases installed before it are uninstalled and purged in reverse
+// order, and the rest are skipped. See BatchInstallRequest.
+//
+// Once the batch has been validated, the outcome of each entry is reported in
+// the response rather than as an error.
+func (s *ReleaseServer) BatchInstall(c ctx.Context, req *services.BatchInstallRequest) (*services.BatchInstallResponse, error) {
+	if err := s.validateBatch(req.Releases); err != nil {
+		return nil, err
+	}
+
+	res := &services.BatchInstallResponse{Results: make([]*services.BatchInstallResult, len(req.Releases))}
+	for i := range res.Results {
+		res.Results[i] = &services.BatchInstallResult{Outcome: services.BatchInstallResult_SKIPPED}
+	}
+
+	for i, ir := range req.Releases {
+		result := res.Results[i]
+		ires, err := s.InstallRelease(c, ir)
+		if ires != nil {
+			result.Release = ires.Release
+		}
+		if err == nil {
+			result.Outcome = services.BatchInstallResult_INSTALLED
+			continue
+		}
+
+		s.Log("batch install: entry %d of %d failed, uninstalling %d release(s): %s", i+1, len(req.Releases), i, err)
+		result.Outcome = services.BatchInstallResult_FAILED
+		result.Error = err.Error()
+		if s.isStored(result.Release) {
+			if err := s.undoInstall(ir, result.Release); err != nil {
+				result.Error = fmt.Sprintf("%s; uninstalling it also failed: %s", result.Error, err)
+			}
+		}
+		for j := i - 1; j >= 0; j-- {
+			prev := res.Results[j]
+			if err := s.undoInstall(req.Releases[j], prev.Release); err != nil {
+				prev.Outcome = services.BatchInstallResult_ROLLBACK_FAILED
+				prev.Error = err.Error()
+				continue
+			}
+			prev.Outcome = services.BatchInstallResult_ROLLED_BACK
+		}
+		break
+	}
+	return res, nil
+}
+
+// validateBatch checks a batch before anything in it is installed, so that
+// problems which would only show up part way through do not cause releases
+// to be installed and then uninstalled.
+func (s *ReleaseServer) validateBatch(reqs []*services.InstallReleaseRequest) error {
+	if len(reqs) == 0 {
+		return errors.New("batch install: no releases to install")
+	}
+	names := make(map[string]bool, len(reqs))
+	for i, req := range reqs {
+		switch {
+		case req.Chart == nil:
+			return fmt.Errorf("batch install: entry %d: %s", i+1, errMissingChart)
+		case req.DryRun:
+			return fmt.Errorf("batch install: entry %d: dry runs are not supported in a batch", i+1)
+		case req.ReuseName:
+			return fmt.Errorf("batch install: entry %d: reusing names is not supported in a batch", i+1)
+		case req.Name == "":
+			continue
+		case names[req.Name]:
+			return fmt.Errorf("batch install: release %q is listed more than once", req.Name)
+		}
+		names[req.Name] = true
+		if h, err := s.env.Releases.History(req.Name); err == nil && len(h) > 0 {
+			return fmt.Errorf("batch install: a release named %q already exists", req.Name)
+		}
+	}
+	return nil
+}
+
+// isStored reports whether r was recorded in storage, and so has to be
+// uninstalled.
+func (s *ReleaseServer) isStored(r *release.Release) bool {
+	if r == nil || r.Version == 0 {
+		return false
+	}
+	_, err := s.env.Releases.Get(r.Name, r.Version)
+	return err == nil
+}
+
+// undoInstall uninstalls a release installed by req, and purges it if all of
+// its resources were deleted. Otherwise the deleted release is kept as a
+// record of what may have been left behind.
+func (s *ReleaseServer) undoInstall(req *services.InstallReleaseRequest, r *release.Release) error {
+	_, err := s.uninstallRelease(&services.UninstallReleaseRequest{
+		Name:         r.Name,
+		DisableHooks: req.DisableHooks,
+		Timeout:      req.Timeout,
+		Wait:         req.Wait,
+	})
+	if err != nil {
+		return err
+	}
+	rels, err := s.env.Releases.History(r.Name)
+	if err != nil {
+		return err
+	}
+	return s.purgeReleases(rels...)
+}
@@ -0,0 +1,204 @@
+/*
+Copyright 2017 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tiller
+
+import (
+	"errors"
+	"io"
+	"io/ioutil"
+	"os"
+	"strings"
+	"testing"
+
+	"k8s.io/helm/pkg/helm"
+	"k8s.io/helm/pkg/proto/hapi/chart"
+	"k8s.io/helm/pkg/proto/hapi/release"
+	"k8s.io/helm/pkg/proto/hapi/services"
+	"k8s.io/helm/pkg/tiller/environment"
+)
+
+// batchKubeClient fails to create resources named "fail-me", and to delete
+// resources named "stuck".
+type batchKubeClient struct {
+	environment.PrintingKubeClient
+}
+
+func (b *batchKubeClient) Create(ns string, r io.Reader, timeout int64, shouldWait bool) error {
+	data, err := ioutil.ReadAll(r)
+	if err != nil {
+		return err
+	}
+	if strings.Contains(string(data), "name: fail-me") {
+		return errors.New("create failed")
+	}
+	return nil
+}
+
+func (b *batchKubeClient) DeleteWithPolicy(ns string, r io.Reader, policy string, timeout int64, shouldWait bool) error {
+	data, err := ioutil.ReadAll(r)
+	if err != nil {
+		return err
+	}
+	if strings.Contains(string(data), "name: stuck") {
+		return errors.New("delete failed")
+	}
+	return nil
+}
+
+func batchEntry(name, configMap string) *services.InstallReleaseRequest {
+	return &services.InstallReleaseRequest{
+		Name:      name,
+		Namespace: "spaced",
+		Chart: &chart.Chart{
+			Metadata: &chart.Metadata{Name: "hello"},
+			Templates: []*chart.Template{
+				{Name: "templates/configmap", Data: []byte("apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: " + configMap + "\n")},
+			},
+		},
+	}
+}
+
+func batchFixture() *ReleaseServer {
+	rs := rsFixture()
+	rs.env.KubeClient = &batchKubeClient{PrintingKubeClient: environment.PrintingKubeClient{Out: os.Stdout}}
+	return rs
+}
+
+func outcomes(res *services.BatchInstallResponse) string {
+	var s []string
+	for _, r := range res.Results {
+		s = append(s, r.Outcome.String())
+	}
+	return strings.Join(s, ",")
+}
+
+func TestBatchInstall(t *testing.T) {
+	rs := batchFixture()
+
+	res, err := rs.BatchInstall(helm.NewContext(), &services.BatchInstallRequest{
+		Releases: []*services.InstallReleaseRequest{
+			batchEntry("first", "one"),
+			batchEntry("", "two"),
+		},
+	})
+	if err != nil {
+		t.Fatalf("Failed batch install: %s", err)
+	}
+	if got, expect := outcomes(res), "INSTALLED,INSTALLED"; got != expect {
+		t.Errorf("Expected outcomes %q, got %q", expect, got)
+	}
+	for _, r := range res.Results {
+		rel, err := rs.env.Releases.Get(r.Release.Name, 1)
+		if err != nil {
+			t.Fatalf("Expected %q to be stored: %s", r.Release.Name, err)
+		}
+		if rel.Info.Status.Code != release.Status_DEPLOYED {
+			t.Errorf("Expected %q to be deployed, got %s", rel.Name, rel.Info.Status.Code)
+		}
+	}
+}
+
+func TestBatchInstall_Failure(t *testing.T) {
+	rs := batchFixture()
+
+	res, err := rs.BatchInstall(helm.NewContext(), &services.BatchInstallRequest{
+		Releases: []*services.InstallReleaseRequest{
+			batchEntry("first", "one"),
+			batchEntry("second", "two"),
+			batchEntry("third", "fail-me"),
+			batchEntry("fourth", "four"),
+		},
+	})
+	if err != nil {
+		t.Fatalf("Expected the outcome in the response, got error %s", err)
+	}
+	if got, expect := outcomes(res), "ROLLED_BACK,ROLLED_BACK,FAILED,SKIPPED"; got != expect {
+		t.Errorf("Expected outcomes %q, got %q", expect, got)
+	}
+	if !strings.Contains(res.Results[2].Error, "create failed") {
+		t.Errorf("Expected the failure to be reported, got %q", res.Results[2].Error)
+	}
+	if res.Results[3].Release != nil {
+		t.Errorf("Expected no release for a skipped entry")
+	}
+
+	// Everything, including the failed release, is purged.
+	for _, name := range []string{"first", "second", "third", "fourth"} {
+		if h, err := rs.env.Releases.History(name); err == nil && len(h) > 0 {
+			t.Errorf("Expected %q to be purged, found %d revision(s)", name, len(h))
+		}
+	}
+}
+
+func TestBatchInstall_RollbackFailure(t *testing.T) {
+	rs := batchFixture()
+
+	res, err := rs.BatchInstall(helm.NewContext(), &services.BatchInstallRequest{
+		Releases: []*services.InstallReleaseRequest{
+			batchEntry("first", "stuck"),
+			batchEntry("second", "two"),
+			batchEntry("third", "fail-me"),
+		},
+	})
+	if err != nil {
+		t.Fatalf("Expected the outcome in the response, got error %s", err)
+	}
+	if got, expect := outcomes(res), "ROLLBACK_FAILED,ROLLED_BACK,FAILED"; got != expect {
+		t.Errorf("Expected outcomes %q, got %q", expect, got)
+	}
+	if !strings.Contains(res.Results[0].Error, "delete failed") {
+		t.Errorf("Expected the rollback failure to be reported, got %q", res.Results[0].Error)
+	}
+
+	// A release that could not be uninstalled is kept as deleted.
+	rel, err := rs.env.Releases.Get("first", 1)
+	if err != nil {
+		t.Fatalf("Expected the release to be kept: %s", err)
+	}
+	if rel.Info.Status.Code != release.Status_DELETED {
+		t.Errorf("Expected the release to be marked deleted, got %s", rel.Info.Status.Code)
+	}
+}
+
+func TestBatchInstall_Validation(t *testing.T) {
+	rs := batchFixture()
+	rs.env.Releases.Create(releaseStub())
+
+	noChart := batchEntry("no-chart", "one")
+	noChart.Chart = nil
+	dryRun := batchEntry("dry-run", "one")
+	dryRun.DryRun = true
+	reuse := batchEntry("reuse", "one")
+	reuse.ReuseName = true
+
+	for name, releases := range map[string][]*services.InstallReleaseRequest{
+		"empty":     nil,
+		"no chart":  {batchEntry("first", "one"), noChart},
+		"dry run":   {dryRun},
+		"reuse":     {reuse},
+		"duplicate": {batchEntry("first", "one"), batchEntry("first", "two")},
+		"existing":  {batchEntry("first", "one"), batchEntry("angry-panda", "two")},
+	} {
+		if _, err := rs.BatchInstall(helm.NewContext(), &services.BatchInstallRequest{Releases: releases}); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+		// Nothing is installed when the batch is rejected.
+		if h, err := rs.env.Releases.History("first"); err == nil && len(h) > 0 {
+			t.Errorf("%s: expected nothing to be installed", name)
+		}
+	}
+}
@@ -0,0 +1,99 @@
+/*
+Copyright 2017 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tiller
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sync"
+
+	"github.com/golang/protobuf/proto"
+)
+
+// operationKey identifies an operation for deduplication.
+//
+// Two operations are identical when they have the same intent (update,
+// rollback, uninstall), target the same release name, start from the same
+// stored revision of that release, and carry byte-for-byte identical requests.
+// A retry of an upgrade that is still in flight is identical to the original;
+// an upgrade with different values, or one issued after the release moved to a
+// new revision, is not.
+func operationKey(intent, name string, revision int32, req proto.Message) (string, error) {
+	b, err := proto.Marshal(req)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(b)
+	return fmt.Sprintf("%s/%s/%d/%s", intent, name, revision, hex.EncodeToString(sum[:])), nil
+}
+
+// operationCall is an in-flight or completed operation.
+type operationCall struct {
+	wg  sync.WaitGroup
+	res interface{}
+	err error
+	// dups is the number of callers waiting on this operation's result.
+	dups int
+}
+
+// operationGroup runs at most one copy of an operation per key at a time.
+//
+// Callers that arrive while an identical operation is running do not run it
+// again. They wait for the running operation and receive its response and
+// error unchanged. The zero value is ready to use.
+type operationGroup struct {
+	mu    sync.Mutex
+	calls map[string]*operationCall
+
+	// joined, when set, is called each time a caller attaches to an
+	// operation that is already running, before it waits for the result.
+	joined func(key string)
+}
+
+// do executes fn unless an operation with the same key is already running, in
+// which case it waits for that operation and returns its result. shared is true
+// when the result was produced by another caller.
+func (g *operationGroup) do(key string, fn func() (interface{}, error)) (res interface{}, err error, shared bool) {
+	g.mu.Lock()
+	if g.calls == nil {
+		g.calls = make(map[string]*operationCall)
+	}
+	if c, ok := g.calls[key]; ok {
+		c.dups++
+		joined := g.joined
+		g.mu.Unlock()
+		if joined != nil {
+			joined(key)
+		}
+		c.wg.Wait()
+		return c.res, c.err, true
+	}
+	c := &operationCall{}
+	c.wg.Add(1)
+	g.calls[key] = c
+	g.mu.Unlock()
+
+	c.res, c.err = fn()
+	c.wg.Done()
+
+	g.mu.Lock()
+	delete(g.calls, key)
+	g.mu.Unlock()
+
+	return c.res, c.err, false
+}
@@ -0,0 +1,113 @@
+/*
+Copyright 2017 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tiller
+
+import (
+	"errors"
+	"sync"
+	"testing"
+
+	"k8s.io/helm/pkg/proto/hapi/services"
+)
+
+func TestOperationKey(t *testing.T) {
+	req := &services.UpdateReleaseRequest{Name: "angry-panda", Chart: chartStub()}
+
+	a, err := operationKey("update", req.Name, 1, req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, _ := operationKey("update", req.Name, 1, &services.UpdateReleaseRequest{Name: "angry-panda", Chart: chartStub()})
+	if a != b {
+		t.Errorf("Expected identical requests to share a key, got %q and %q", a, b)
+	}
+
+	for _, other := range []struct {
+		intent   string
+		revision int32
+		req      *services.UpdateReleaseRequest
+	}{
+		{"rollback", 1, req},
+		{"update", 2, req},
+		{"update", 1, &services.UpdateReleaseRequest{Name: "angry-panda", Chart: chartStub(), Wait: true}},
+	} {
+		k, _ := operationKey(other.intent, req.Name, other.revision, other.req)
+		if k == a {
+			t.Errorf("Expected %s at v%d to have a different key", other.intent, other.revision)
+		}
+	}
+}
+
+func TestOperationGroup(t *testing.T) {
+	joined := make(chan struct{}, 2)
+	g := operationGroup{joined: func(string) { joined <- struct{}{} }}
+
+	release := make(chan struct{})
+	started := make(chan struct{})
+	calls := 0
+	wantErr := errors.New("shared failure")
+
+	fn := func() (interface{}, error) {
+		calls++
+		close(started)
+		<-release
+		return "result", wantErr
+	}
+
+	var wg sync.WaitGroup
+	results := make([]interface{}, 3)
+	errs := make([]error, 3)
+	shared := make([]bool, 3)
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		results[0], errs[0], shared[0] = g.do("key", fn)
+	}()
+	<-started
+
+	for i := 1; i < 3; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			results[i], errs[i], shared[i] = g.do("key", fn)
+		}(i)
+	}
+
+	// Wait for the followers to attach before finishing the leader.
+	<-joined
+	<-joined
+	close(release)
+	wg.Wait()
+
+	if calls != 1 {
+		t.Errorf("Expected the operation to run once, ran %d times", calls)
+	}
+	for i := range results {
+		if results[i] != "result" || errs[i] != wantErr {
+			t.Errorf("Caller %d got (%v, %v), expected the shared result", i, results[i], errs[i])
+		}
+	}
+	if shared[0] {
+		t.Errorf("Expected the first caller to run the operation itself")
+	}
+
+	// Once finished, the same key runs again.
+	if _, _, sh := g.do("key", func() (interface{}, error) { return nil, nil }); sh {
+		t.Errorf("Expected a completed operation not to be shared")
+	}
+}
@@ -0,0 +1,254 @@
+/*
+Copyright 2017 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tiller
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+
+	ctx "golang.org/x/net/context"
+
+	"k8s.io/helm/pkg/kube"
+	"k8s.io/helm/pkg/logging"
+	"k8s.io/helm/pkg/proto/hapi/release"
+	"k8s.io/helm/pkg/proto/hapi/services"
+	relutil "k8s.io/helm/pkg/releaseutil"
+)
+
+// GetReleaseDrift compares the resources in the manifest of a release to their
+// live counterparts, and reports the fields that differ and the resources that
+// are missing. Resources that only earlier revisions declared, and that still
+// exist, are reported as extra unless another deployed release declares them.
+//
+// If the request has a chart, the resources that an upgrade of the release to
+// the chart would apply are compared instead; see upgradeDrift.
+//
+// It only reads from the cluster and from storage, so the release is not
+// locked.
+func (s *ReleaseServer) GetReleaseDrift(c ctx.Context, req *services.GetReleaseDriftRequest) (*services.GetReleaseDriftResponse, error) {
+	if !ValidName.MatchString(req.Name) {
+		return nil, errMissingRelease
+	}
+	if req.Chart != nil {
+		return s.upgradeDrift(req)
+	}
+
+	var rel *release.Release
+	var err error
+	if req.Version <= 0 {
+		if rel, err = s.env.Releases.Last(req.Name); err != nil {
+			return nil, fmt.Errorf("getting deployed release %q: %s", req.Name, err)
+		}
+	} else {
+		if rel, err = s.env.Releases.Get(req.Name, req.Version); err != nil {
+			return nil, fmt.Errorf("getting release '%s' (v%d): %s", req.Name, req.Version, err)
+		}
+	}
+
+	kc, err := s.releaseCluster(rel)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &services.GetReleaseDriftResponse{Name: rel.Name, Version: rel.Version}
+	if len(manifestKeys(rel.Manifest, rel.Namespace)) > 0 {
+		drift, err := kc.env.KubeClient.Drift(rel.Namespace, bytes.NewBufferString(rel.Manifest))
+		if err != nil {
+			return nil, fmt.Errorf("comparing release %q to the cluster: %s", rel.Name, err)
+		}
+		for _, d := range drift {
+			resp.Resources = append(resp.Resources, resourceDrift(d, false))
+		}
+	}
+
+	extra, err := s.removedResources(rel)
+	if err != nil {
+		return nil, err
+	}
+	if extra != "" {
+		drift, err := kc.env.KubeClient.Drift(rel.Namespace, bytes.NewBufferString(extra))
+		if err != nil {
+			return nil, fmt.Errorf("looking up resources removed from release %q: %s", rel.Name, err)
+		}
+		for _, d := range drift {
+			if !d.Missing {
+				resp.Resources = append(resp.Resources, resourceDrift(d, true))
+			}
+		}
+	}
+
+	for _, r := range resp.Resources {
+		if r.Status != services.ResourceDrift_IN_SYNC {
+			resp.Drifted = true
+		}
+	}
+	return resp, nil
+}
+
+// upgradeDrift renders the chart of req as an upgrade of the latest revision
+// of the release would, with the same values, and compares the resources to
+// their live counterparts. It answers what the upgrade would change:
+// MODIFIED resources have fields that the upgrade would set to other values,
+// ADDED resources would be created, MISSING ones would be created again, and
+// REMOVED ones would be deleted. Like an upgrade, it runs the injection
+// policies, but no hooks. The values mutators are not run, as this only
+// reads; the mutations of the latest revision are replayed instead. Removed
+// resources that the upgrade would leave in place, such as those with the
+// keep resource policy, are not reported.
+func (s *ReleaseServer) upgradeDrift(req *services.GetReleaseDriftRequest) (*services.GetReleaseDriftResponse, error) {
+	if req.Version > 0 {
+		return nil, errors.New("only the latest revision of a release can be compared to a chart")
+	}
+	current, updated, _, err := s.prepareUpdate(&services.UpdateReleaseRequest{
+		Name:        req.Name,
+		Chart:       req.Chart,
+		Values:      req.Values,
+		ResetValues: req.ResetValues,
+		ReuseValues: req.ReuseValues,
+		DryRun:      true,
+	}, true)
+	if err != nil {
+		return nil, err
+	}
+	kc, err := s.releaseCluster(current)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &services.GetReleaseDriftResponse{Name: current.Name, Version: current.Version}
+	if len(manifestKeys(updated.Manifest, current.Namespace)) > 0 {
+		drift, err := kc.env.KubeClient.Drift(current.Namespace, bytes.NewBufferString(updated.Manifest))
+		if err != nil {
+			return nil, fmt.Errorf("comparing the upgrade of release %q to the cluster: %s", current.Name, err)
+		}
+		deployed := manifestKeys(current.Manifest, current.Namespace)
+		for _, d := range drift {
+			r := resourceDrift(d, false)
+			if d.Missing && !deployed[objectKey(d.APIVersion, d.Kind, d.Namespace, d.Name, current.Namespace)] {
+				r.Status = services.ResourceDrift_ADDED
+			}
+			resp.Resources = append(resp.Resources, r)
+		}
+	}
+
+	// The upgrade only deletes the removed resources that pruneBase keeps.
+	base := s.pruneBase(logging.Discard(), current, updated, false)
+	if removed := missingFrom(base.Manifest, updated.Manifest, current.Namespace); removed != "" {
+		drift, err := kc.env.KubeClient.Drift(current.Namespace, bytes.NewBufferString(removed))
+		if err != nil {
+			return nil, fmt.Errorf("looking up resources removed from release %q: %s", current.Name, err)
+		}
+		for _, d := range drift {
+			if !d.Missing {
+				r := resourceDrift(d, false)
+				r.Status = services.ResourceDrift_REMOVED
+				resp.Resources = append(resp.Resources, r)
+			}
+		}
+	}
+
+	for _, r := range resp.Resources {
+		if r.Status != services.ResourceDrift_IN_SYNC {
+			resp.Drifted = true
+		}
+	}
+	return resp, nil
+}
+
+// missingFrom returns, as a manifest, the resources that manifest from
+// declares, but manifest to does not. Both manifests are installed in
+// namespace.
+func missingFrom(from, to, namespace string) string {
+	declared := manifestKeys(to, namespace)
+	var b bytes.Buffer
+	for _, content := range sortedManifests(from) {
+		if head := manifestHead(content); head != nil && !declared[resourceKey(head, namespace)] {
+			b.WriteString("---\n")
+			b.WriteString(content)
+			b.WriteString("\n")
+		}
+	}
+	return b.String()
+}
+
+// removedResources returns, as a manifest, the resources that revisions of a
+// release older than rel declared, but rel does not. Each resource is taken
+// from the latest revision that declared it. Resources declared by other
+// deployed releases in the namespace are left out.
+func (s *ReleaseServer) removedResources(rel *release.Release) (string, error) {
+	history, err := s.env.Releases.History(rel.Name)
+	if err != nil {
+		return "", fmt.Errorf("getting history of release %q: %s", rel.Name, err)
+	}
+	relutil.Reverse(history, relutil.SortByRevision)
+
+	declared := manifestKeys(rel.Manifest, rel.Namespace)
+	var foreign map[string]bool
+	var b bytes.Buffer
+	for _, h := range history {
+		if h.Version >= rel.Version || h.Namespace != rel.Namespace {
+			continue
+		}
+		for _, content := range sortedManifests(h.Manifest) {
+			head := manifestHead(content)
+			if head == nil {
+				continue
+			}
+			key := resourceKey(head, rel.Namespace)
+			if declared[key] {
+				continue
+			}
+			if foreign == nil {
+				if foreign, err = s.foreignResources(rel); err != nil {
+					return "", fmt.Errorf("determining resource ownership: %s", err)
+				}
+			}
+			declared[key] = true
+			if foreign[key] {
+				continue
+			}
+			b.WriteString("---\n")
+			b.WriteString(content)
+			b.WriteString("\n")
+		}
+	}
+	return b.String(), nil
+}
+
+// resourceDrift converts the drift of a resource as found by the Kubernetes
+// client. Resources that are not missing are extra if removed is set.
+func resourceDrift(d kube.ResourceDrift, removed bool) *services.ResourceDrift {
+	r := &services.ResourceDrift{
+		ApiVersion: d.APIVersion,
+		Kind:       d.Kind,
+		Name:       d.Name,
+		Namespace:  d.Namespace,
+	}
+	switch {
+	case d.Missing:
+		r.Status = services.ResourceDrift_MISSING
+	case removed:
+		r.Status = services.ResourceDrift_EXTRA
+	case len(d.Fields) > 0:
+		r.Status = services.ResourceDrift_MODIFIED
+		for _, f := range d.Fields {
+			r.Fields = append(r.Fields, &services.FieldDrift{Path: f.Path, Expected: f.Expected, Actual: f.Actual})
+		}
+	}
+	return r
+}
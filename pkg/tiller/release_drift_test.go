@@ -0,0 +1,326 @@
+/*
+Copyright 2017 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tiller
+
+import (
+	"errors"
+	"io"
+	"io/ioutil"
+	"reflect"
+	"strings"
+	"testing"
+
+	"k8s.io/helm/pkg/chartutil"
+	"k8s.io/helm/pkg/helm"
+	"k8s.io/helm/pkg/kube"
+	"k8s.io/helm/pkg/proto/hapi/chart"
+	"k8s.io/helm/pkg/proto/hapi/release"
+	"k8s.io/helm/pkg/proto/hapi/services"
+	"k8s.io/helm/pkg/tiller/environment"
+)
+
+var driftManifestV1 = `---
+# Source: hello/templates/configmap.yaml
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: settings
+---
+# Source: hello/templates/secret.yaml
+apiVersion: v1
+kind: Secret
+metadata:
+  name: creds
+---
+# Source: hello/templates/service.yaml
+apiVersion: v1
+kind: Service
+metadata:
+  name: gone
+---
+# Source: hello/templates/shared.yaml
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: shared
+`
+
+var driftManifestV2 = `---
+# Source: hello/templates/configmap.yaml
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: settings
+---
+# Source: hello/templates/deployment.yaml
+apiVersion: extensions/v1beta1
+kind: Deployment
+metadata:
+  name: web
+---
+# Source: hello/templates/pod.yaml
+apiVersion: v1
+kind: Pod
+metadata:
+  name: worker
+`
+
+// driftKubeClient reports every resource as in sync, except those it is told
+// are missing or modified.
+type driftKubeClient struct {
+	environment.PrintingKubeClient
+	missing  map[string]bool
+	modified map[string][]kube.FieldDrift
+	// compared are the manifests that were compared.
+	compared []string
+}
+
+func (d *driftKubeClient) Drift(ns string, r io.Reader) ([]kube.ResourceDrift, error) {
+	b, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	d.compared = append(d.compared, string(b))
+	var drift []kube.ResourceDrift
+	for _, doc := range sortedManifests(string(b)) {
+		head := manifestHead(doc)
+		drift = append(drift, kube.ResourceDrift{
+			APIVersion: head.Version,
+			Kind:       head.Kind,
+			Name:       head.Metadata.Name,
+			Namespace:  ns,
+			Missing:    d.missing[head.Metadata.Name],
+			Fields:     d.modified[head.Metadata.Name],
+		})
+	}
+	return drift, nil
+}
+
+func TestGetReleaseDrift(t *testing.T) {
+	c := helm.NewContext()
+	rs := rsFixture()
+	kc := &driftKubeClient{
+		missing: map[string]bool{"web": true, "creds": true},
+		modified: map[string][]kube.FieldDrift{
+			"settings": {{Path: "data.mode", Expected: `"fast"`, Actual: `"slow"`}},
+		},
+	}
+	rs.env.KubeClient = kc
+
+	v1 := namedReleaseStub("drifty", release.Status_SUPERSEDED)
+	v1.Namespace = "spaced"
+	v1.Manifest = driftManifestV1
+	v2 := namedReleaseStub("drifty", release.Status_DEPLOYED)
+	v2.Namespace = "spaced"
+	v2.Version = 2
+	v2.Manifest = driftManifestV2
+	other := namedReleaseStub("other", release.Status_DEPLOYED)
+	other.Namespace = "spaced"
+	other.Manifest = `---
+# Source: other/templates/shared.yaml
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: shared
+`
+	for _, r := range []*release.Release{v1, v2, other} {
+		if err := rs.env.Releases.Create(r); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	res, err := rs.GetReleaseDrift(c, &services.GetReleaseDriftRequest{Name: "drifty"})
+	if err != nil {
+		t.Fatalf("Failed to get drift: %s", err)
+	}
+	if res.Name != "drifty" || res.Version != 2 {
+		t.Errorf("Expected drifty v2 to be compared, got %s v%d", res.Name, res.Version)
+	}
+	if !res.Drifted {
+		t.Error("Expected the release to have drifted")
+	}
+
+	got := map[string]services.ResourceDrift_Status{}
+	for _, r := range res.Resources {
+		got[r.Kind+"/"+r.Name] = r.Status
+		if r.Namespace != "spaced" {
+			t.Errorf("Expected %s/%s in namespace spaced, got %q", r.Kind, r.Name, r.Namespace)
+		}
+	}
+	// The removed secret is gone, and the shared config map belongs to
+	// another release: neither is extra.
+	expect := map[string]services.ResourceDrift_Status{
+		"ConfigMap/settings": services.ResourceDrift_MODIFIED,
+		"Deployment/web":     services.ResourceDrift_MISSING,
+		"Pod/worker":         services.ResourceDrift_IN_SYNC,
+		"Service/gone":       services.ResourceDrift_EXTRA,
+	}
+	if !reflect.DeepEqual(got, expect) {
+		t.Errorf("Expected drift %v, got %v", expect, got)
+	}
+
+	for _, r := range res.Resources {
+		if r.Name != "settings" {
+			continue
+		}
+		expectFields := []*services.FieldDrift{{Path: "data.mode", Expected: `"fast"`, Actual: `"slow"`}}
+		if !reflect.DeepEqual(r.Fields, expectFields) {
+			t.Errorf("Expected fields %v, got %v", expectFields, r.Fields)
+		}
+	}
+
+	// Drift is read-only: nothing may be stored.
+	h, err := rs.env.Releases.History("drifty")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(h) != 2 {
+		t.Errorf("Expected 2 revisions, got %d", len(h))
+	}
+}
+
+func TestGetReleaseDriftOfRevision(t *testing.T) {
+	c := helm.NewContext()
+	rs := rsFixture()
+	kc := &driftKubeClient{}
+	rs.env.KubeClient = kc
+
+	v1 := namedReleaseStub("drifty", release.Status_SUPERSEDED)
+	v1.Manifest = driftManifestV1
+	v2 := namedReleaseStub("drifty", release.Status_DEPLOYED)
+	v2.Version = 2
+	v2.Manifest = driftManifestV2
+	for _, r := range []*release.Release{v1, v2} {
+		if err := rs.env.Releases.Create(r); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	res, err := rs.GetReleaseDrift(c, &services.GetReleaseDriftRequest{Name: "drifty", Version: 1})
+	if err != nil {
+		t.Fatalf("Failed to get drift: %s", err)
+	}
+	if res.Version != 1 {
+		t.Errorf("Expected v1 to be compared, got v%d", res.Version)
+	}
+	if res.Drifted {
+		t.Errorf("Expected no drift, got %v", res.Resources)
+	}
+	if len(res.Resources) != 4 {
+		t.Errorf("Expected 4 resources, got %d", len(res.Resources))
+	}
+	// Later revisions do not make resources extra.
+	if len(kc.compared) != 1 {
+		t.Errorf("Expected only the manifest of v1 to be compared, got %d manifests", len(kc.compared))
+	}
+}
+
+func TestGetReleaseDriftMissingRelease(t *testing.T) {
+	c := helm.NewContext()
+	rs := rsFixture()
+
+	if _, err := rs.GetReleaseDrift(c, &services.GetReleaseDriftRequest{Name: "nope"}); err == nil {
+		t.Error("Expected an error for a missing release")
+	}
+	if _, err := rs.GetReleaseDrift(c, &services.GetReleaseDriftRequest{Name: "Not_Valid!"}); err != errMissingRelease {
+		t.Errorf("Expected errMissingRelease, got %v", err)
+	}
+}
+
+func TestGetReleaseDriftOfUpgrade(t *testing.T) {
+	c := helm.NewContext()
+	rs := rsFixture()
+	kc := &driftKubeClient{
+		missing: map[string]bool{"worker": true},
+		modified: map[string][]kube.FieldDrift{
+			"settings": {{Path: "data.mode", Expected: `"fast"`, Actual: `"slow"`}},
+		},
+	}
+	rs.env.KubeClient = kc
+	// Comparing only reads, so the mutations of the release are replayed
+	// rather than the mutators run again.
+	rs.AddValuesMutator("ipam", ValuesMutatorFunc(func(_ chartutil.ReleaseOptions, _ *chart.Metadata, _ chartutil.Values) (chartutil.Values, error) {
+		return nil, errors.New("ran again")
+	}))
+
+	rel := namedReleaseStub("drifty", release.Status_DEPLOYED)
+	rel.Config = &chart.Config{Raw: "mode: fast\n"}
+	rel.ComputedValues = &chart.Config{Raw: "mode: fast\nlevel: 1\nip: 10.0.0.7\n"}
+	rel.Info.ValuesMutations = []*release.ValuesMutation{{Mutator: "ipam", Changed: []string{"ip"}}}
+	// The upgrade would keep the removed Secret.
+	rel.Manifest = driftManifestV1 + "---\n# Source: hello/templates/kept.yaml\napiVersion: v1\nkind: Secret\nmetadata:\n  name: kept\n  annotations:\n    helm.sh/resource-policy: keep\n"
+	if err := rs.env.Releases.Create(rel); err != nil {
+		t.Fatal(err)
+	}
+	target := &chart.Chart{
+		Metadata: &chart.Metadata{Name: "hello", Version: "0.2.0"},
+		Templates: []*chart.Template{
+			{Name: "templates/configmap.yaml", Data: []byte("apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: settings\ndata:\n  mode: {{ .Values.mode }}\n  level: {{ .Values.level | quote }}\n  ip: {{ .Values.ip }}\n")},
+			{Name: "templates/secret.yaml", Data: []byte("apiVersion: v1\nkind: Secret\nmetadata:\n  name: creds\n")},
+			{Name: "templates/pod.yaml", Data: []byte("apiVersion: v1\nkind: Pod\nmetadata:\n  name: worker\n")},
+		},
+		Values: &chart.Config{Raw: "mode: slow\nlevel: 1\n"},
+	}
+
+	res, err := rs.GetReleaseDrift(c, &services.GetReleaseDriftRequest{
+		Name:        "drifty",
+		Chart:       target,
+		Values:      &chart.Config{Raw: "level: 3\n"},
+		ReuseValues: true,
+	})
+	if err != nil {
+		t.Fatalf("Failed to get drift: %s", err)
+	}
+	if res.Version != 1 || !res.Drifted {
+		t.Errorf("Expected v1 to have drifted from the upgrade, got v%d (drifted: %v)", res.Version, res.Drifted)
+	}
+
+	got := map[string]services.ResourceDrift_Status{}
+	for _, r := range res.Resources {
+		got[r.Kind+"/"+r.Name] = r.Status
+	}
+	// The shared ConfigMap and the Service are not in the chart, and the
+	// Pod is new to the release.
+	expect := map[string]services.ResourceDrift_Status{
+		"ConfigMap/settings": services.ResourceDrift_MODIFIED,
+		"Secret/creds":       services.ResourceDrift_IN_SYNC,
+		"Pod/worker":         services.ResourceDrift_ADDED,
+		"Service/gone":       services.ResourceDrift_REMOVED,
+		"ConfigMap/shared":   services.ResourceDrift_REMOVED,
+	}
+	if !reflect.DeepEqual(got, expect) {
+		t.Errorf("Expected drift %v, got %v", expect, got)
+	}
+
+	// The values of the release are reused under the supplied ones.
+	if len(kc.compared) == 0 || !strings.Contains(kc.compared[0], "mode: fast") || !strings.Contains(kc.compared[0], `level: "3"`) || !strings.Contains(kc.compared[0], "ip: 10.0.0.7") {
+		t.Errorf("Expected the chart to be rendered with the reused values and mutations, got %q", kc.compared)
+	}
+
+	// Nothing is upgraded.
+	h, err := rs.env.Releases.History("drifty")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(h) != 1 {
+		t.Errorf("Expected 1 revision, got %d", len(h))
+	}
+
+	if _, err := rs.GetReleaseDrift(c, &services.GetReleaseDriftRequest{Name: "drifty", Version: 1, Chart: target}); err == nil {
+		t.Error("Expected an error comparing an older revision to a chart")
+	}
+}
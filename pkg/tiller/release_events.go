@@ -0,0 +1,105 @@
+/*
+Copyright 2017 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tiller
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/util/flowcontrol"
+	"k8s.io/kubernetes/pkg/api"
+
+	"k8s.io/helm/pkg/proto/hapi/release"
+)
+
+// eventBurst is the number of events that may be emitted at once before the
+// rate limit applies.
+const eventBurst = 25
+
+// EnableEvents makes the server emit a Kubernetes Event each time a release
+// changes status, at no more than qps events per second on average. Events
+// over the limit are dropped.
+func (s *ReleaseServer) EnableEvents(qps float32) {
+	s.events = flowcontrol.NewTokenBucketRateLimiter(qps, eventBurst)
+}
+
+// emitEvent records the current status of r as a Kubernetes Event in the
+// release namespace. The event refers to the release itself, so
+//
+//	kubectl get events --namespace <ns>
+//
+// shows release activity next to the release's resources.
+//
+// Failures are logged and otherwise ignored; events never fail an operation.
+func (s *ReleaseServer) emitEvent(r *release.Release) {
+	if s.events == nil || s.clientset == nil {
+		return
+	}
+	kc, err := s.releaseCluster(r)
+	if err != nil {
+		s.Log("warning: failed to emit event: %s", err)
+		return
+	}
+	if !s.events.TryAccept() {
+		s.Log("warning: dropped event for release %q: rate limit exceeded", r.Name)
+		return
+	}
+	if _, err := kc.clientset.Core().Events(r.Namespace).Create(releaseEvent(r, time.Now())); err != nil {
+		s.Log("warning: failed to emit event for release %q: %s", r.Name, err)
+	}
+}
+
+// releaseEvent builds an event describing the status of r.
+func releaseEvent(r *release.Release, now time.Time) *api.Event {
+	code := r.Info.Status.Code
+	eventType := api.EventTypeNormal
+	if code == release.Status_FAILED {
+		eventType = api.EventTypeWarning
+	}
+	msg := fmt.Sprintf("Release %q revision %d is %s", r.Name, r.Version, code)
+	if r.Info.Description != "" {
+		msg += ": " + r.Info.Description
+	}
+
+	t := metav1.NewTime(now)
+	return &api.Event{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      fmt.Sprintf("%s.v%d.%x", r.Name, r.Version, now.UnixNano()),
+			Namespace: r.Namespace,
+		},
+		InvolvedObject: api.ObjectReference{
+			Kind:      "Release",
+			Namespace: r.Namespace,
+			Name:      r.Name,
+		},
+		Reason:         eventReason(code),
+		Message:        msg,
+		Source:         api.EventSource{Component: "tiller"},
+		FirstTimestamp: t,
+		LastTimestamp:  t,
+		Count:          1,
+		Type:           eventType,
+	}
+}
+
+// eventReason turns a status code such as SUPERSEDED into an event reason
+// such as ReleaseSuperseded.
+func eventReason(code release.Status_Code) string {
+	return "Release" + strings.Title(strings.ToLower(code.String()))
+}
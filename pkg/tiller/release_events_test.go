@@ -0,0 +1,128 @@
+/*
+Copyright 2017 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tiller
+
+import (
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/kubernetes/pkg/api"
+
+	"k8s.io/helm/pkg/helm"
+	"k8s.io/helm/pkg/proto/hapi/chart"
+	"k8s.io/helm/pkg/proto/hapi/release"
+	"k8s.io/helm/pkg/proto/hapi/services"
+)
+
+func TestEventReason(t *testing.T) {
+	tests := map[release.Status_Code]string{
+		release.Status_DEPLOYED:   "ReleaseDeployed",
+		release.Status_FAILED:     "ReleaseFailed",
+		release.Status_SUPERSEDED: "ReleaseSuperseded",
+	}
+	for code, expect := range tests {
+		if got := eventReason(code); got != expect {
+			t.Errorf("%s: expected %q, got %q", code, expect, got)
+		}
+	}
+}
+
+func TestReleaseEvent(t *testing.T) {
+	rel := namedReleaseStub("angry-panda", release.Status_FAILED)
+	rel.Namespace = "spaced"
+	rel.Info.Description = "Upgrade \"angry-panda\" failed: boom"
+
+	ev := releaseEvent(rel, time.Unix(0, 42))
+	if ev.Namespace != "spaced" || ev.InvolvedObject.Namespace != "spaced" {
+		t.Errorf("Expected event in namespace spaced, got %q (object %q)", ev.Namespace, ev.InvolvedObject.Namespace)
+	}
+	if ev.InvolvedObject.Kind != "Release" || ev.InvolvedObject.Name != "angry-panda" {
+		t.Errorf("Unexpected involved object %v", ev.InvolvedObject)
+	}
+	if ev.Name != "angry-panda.v1.2a" {
+		t.Errorf("Unexpected event name %q", ev.Name)
+	}
+	if ev.Type != api.EventTypeWarning || ev.Reason != "ReleaseFailed" {
+		t.Errorf("Expected a ReleaseFailed warning, got %s %s", ev.Type, ev.Reason)
+	}
+	if expect := `Release "angry-panda" revision 1 is FAILED: Upgrade "angry-panda" failed: boom`; ev.Message != expect {
+		t.Errorf("Expected message %q, got %q", expect, ev.Message)
+	}
+}
+
+func TestInstallRelease_EmitsEvents(t *testing.T) {
+	c := helm.NewContext()
+	rs := rsFixture()
+	rs.EnableEvents(100)
+
+	req := &services.InstallReleaseRequest{
+		Namespace: "spaced",
+		Chart: &chart.Chart{
+			Metadata:  &chart.Metadata{Name: "hello"},
+			Templates: []*chart.Template{{Name: "templates/hello", Data: []byte("hello: world")}},
+		},
+	}
+	res, err := rs.InstallRelease(c, req)
+	if err != nil {
+		t.Fatalf("Failed install: %s", err)
+	}
+
+	events, err := rs.clientset.Core().Events("spaced").List(metav1.ListOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(events.Items) != 1 {
+		t.Fatalf("Expected 1 event, got %d", len(events.Items))
+	}
+	ev := events.Items[0]
+	if ev.Reason != "ReleaseDeployed" || ev.InvolvedObject.Name != res.Release.Name {
+		t.Errorf("Unexpected event %s for %s", ev.Reason, ev.InvolvedObject.Name)
+	}
+}
+
+func TestEmitEvent_Disabled(t *testing.T) {
+	rs := rsFixture()
+	rs.emitEvent(releaseStub())
+
+	events, err := rs.clientset.Core().Events("").List(metav1.ListOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(events.Items) != 0 {
+		t.Errorf("Expected no events, got %d", len(events.Items))
+	}
+}
+
+func TestEmitEvent_RateLimited(t *testing.T) {
+	rs := rsFixture()
+	rs.EnableEvents(0.001)
+
+	rel := releaseStub()
+	for i := 0; i < eventBurst+5; i++ {
+		rel.Version = int32(i + 1)
+		rs.emitEvent(rel)
+	}
+
+	events, err := rs.clientset.Core().Events("").List(metav1.ListOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(events.Items) != eventBurst {
+		t.Errorf("Expected %d events, got %d", eventBurst, len(events.Items))
+	}
+}
@@ -0,0 +1,264 @@
+/*
+Copyright 2017 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tiller
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/ghodss/yaml"
+	ctx "golang.org/x/net/context"
+
+	"k8s.io/helm/pkg/chartutil"
+	"k8s.io/helm/pkg/proto/hapi/release"
+	"k8s.io/helm/pkg/proto/hapi/services"
+	"k8s.io/helm/pkg/timeconv"
+)
+
+// kustomizationFile is the name of the file that lists the resources of an
+// exported base.
+const kustomizationFile = "kustomization.yaml"
+
+// helmAnnotationPrefix is the prefix of the annotations that only Helm acts
+// on, such as helm.sh/hook and helm.sh/resource-policy.
+const helmAnnotationPrefix = "helm.sh/"
+
+// ExportRelease renders a release, or a chart with values, as a kustomize
+// base: a file for each resource, in the order Tiller installs them, and a
+// kustomization.yaml that lists them. Hooks are left out, as kustomize has
+// nothing like them, and so are the annotations that only Helm acts on. The
+// export is one way; Helm cannot turn a base back into a release.
+func (s *ReleaseServer) ExportRelease(c ctx.Context, req *services.ExportReleaseRequest) (*services.ExportReleaseResponse, error) {
+	var (
+		rel *release.Release
+		err error
+	)
+	if req.Chart != nil {
+		rel, err = s.renderExport(req)
+	} else {
+		rel, err = s.releaseRevision(req.Name, req.Version)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return exportBase(rel)
+}
+
+// releaseRevision returns the given revision of the release named name, or
+// its deployed revision if version is 0.
+func (s *ReleaseServer) releaseRevision(name string, version int32) (*release.Release, error) {
+	if !ValidName.MatchString(name) {
+		return nil, errMissingRelease
+	}
+
+	var rel *release.Release
+	var err error
+	if version > 0 {
+		rel, err = s.env.Releases.Get(name, version)
+	} else {
+		rel, err = s.env.Releases.Deployed(name)
+	}
+	if err != nil {
+		// Tell a missing release apart from a missing revision.
+		if h, herr := s.env.Releases.History(name); herr != nil || len(h) == 0 {
+			return nil, fmt.Errorf("release: %q not found", name)
+		}
+		if version > 0 {
+			return nil, fmt.Errorf("release: %q has no revision %d", name, version)
+		}
+		return nil, fmt.Errorf("release: %q has no deployed revision", name)
+	}
+	return rel, nil
+}
+
+// renderExport renders the chart of req as a first revision of the release
+// it names, without storing or installing anything.
+func (s *ReleaseServer) renderExport(req *services.ExportReleaseRequest) (*release.Release, error) {
+	if !ValidName.MatchString(req.Name) {
+		return nil, errors.New("a release name is required to render a chart")
+	}
+	if err := s.valuesLimits.Check(req.Chart, req.Values); err != nil {
+		return nil, err
+	}
+	kc, err := s.cluster("")
+	if err != nil {
+		return nil, err
+	}
+	caps, err := s.clusterCapabilities("", kc)
+	if err != nil {
+		return nil, err
+	}
+	options := chartutil.ReleaseOptions{
+		Name:      req.Name,
+		Time:      timeconv.Now(),
+		Namespace: req.Namespace,
+		Revision:  1,
+		IsInstall: true,
+	}
+	valuesToRender, err := chartutil.ToRenderValuesCaps(req.Chart, req.Values, options, caps)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := s.mutateValues(req.Chart, options, valuesToRender); err != nil {
+		return nil, err
+	}
+	hooks, manifestDoc, _, _, err := s.renderResources(req.Chart, valuesToRender, caps.APIVersions, generatedValues(nil))
+	if err != nil {
+		return nil, err
+	}
+	return &release.Release{
+		Name:      req.Name,
+		Namespace: req.Namespace,
+		Chart:     req.Chart,
+		Manifest:  manifestDoc.String(),
+		Hooks:     hooks,
+		Version:   1,
+	}, nil
+}
+
+// kustomization is the content of kustomization.yaml.
+type kustomization struct {
+	APIVersion string   `json:"apiVersion"`
+	Kind       string   `json:"kind"`
+	Namespace  string   `json:"namespace,omitempty"`
+	Resources  []string `json:"resources"`
+}
+
+// exportBase returns the files of a kustomize base for rel.
+func exportBase(rel *release.Release) (*services.ExportReleaseResponse, error) {
+	var manifests []manifest
+	for _, doc := range sortedManifests(rel.Manifest) {
+		head := manifestHead(doc)
+		if head == nil {
+			continue
+		}
+		manifests = append(manifests, manifest{content: doc, head: head})
+	}
+	// Resources of the same kind keep the order of the manifest.
+	sort.Stable(newKindSorter(manifests, InstallOrder))
+
+	res := &services.ExportReleaseResponse{}
+	k := kustomization{
+		APIVersion: "kustomize.config.k8s.io/v1beta1",
+		Kind:       "Kustomization",
+		Namespace:  rel.Namespace,
+		Resources:  []string{},
+	}
+	taken := map[string]bool{kustomizationFile: true}
+	for _, m := range manifests {
+		content, err := stripHelmAnnotations(m.content)
+		if err != nil {
+			return nil, fmt.Errorf("cannot export %s %q: %s", m.head.Kind, m.head.Metadata.Name, err)
+		}
+		name := exportFileName(m, taken)
+		res.Files = append(res.Files, &services.ExportedFile{Name: name, Content: content})
+		k.Resources = append(k.Resources, name)
+	}
+
+	for _, h := range rel.Hooks {
+		for _, e := range h.Events {
+			res.OmittedHooks = append(res.OmittedHooks, fmt.Sprintf("%s %s/%s", eventName(e), h.Kind, h.Name))
+		}
+	}
+
+	body, err := yaml.Marshal(k)
+	if err != nil {
+		return nil, err
+	}
+	var header bytes.Buffer
+	fmt.Fprintf(&header, "# Exported from Helm release %q, revision %d", rel.Name, rel.Version)
+	if rel.Chart != nil && rel.Chart.Metadata != nil {
+		fmt.Fprintf(&header, ", chart %s-%s", rel.Chart.Metadata.Name, rel.Chart.Metadata.Version)
+	}
+	header.WriteString(".\n# This is a one-way export: Helm cannot manage these resources as a release again.\n")
+	if len(res.OmittedHooks) > 0 {
+		header.WriteString("# The hooks of the release are not included and must be run some other way:\n")
+		for _, h := range res.OmittedHooks {
+			fmt.Fprintf(&header, "#   %s\n", h)
+		}
+	}
+	res.Files = append([]*services.ExportedFile{{Name: kustomizationFile, Content: header.String() + string(body)}}, res.Files...)
+	return res, nil
+}
+
+// stripHelmAnnotations removes the annotations that only Helm acts on from
+// the resource in doc. The "# Source:" line that names the template is kept.
+func stripHelmAnnotations(doc string) (string, error) {
+	var obj map[string]interface{}
+	if err := yaml.Unmarshal([]byte(doc), &obj); err != nil {
+		return "", err
+	}
+	if meta, ok := obj["metadata"].(map[string]interface{}); ok {
+		if annotations, ok := meta["annotations"].(map[string]interface{}); ok {
+			for k := range annotations {
+				if strings.HasPrefix(k, helmAnnotationPrefix) {
+					delete(annotations, k)
+				}
+			}
+			if len(annotations) == 0 {
+				delete(meta, "annotations")
+			}
+		}
+	}
+	out, err := yaml.Marshal(obj)
+	if err != nil {
+		return "", err
+	}
+	var source string
+	for _, line := range strings.Split(doc, "\n") {
+		if strings.HasPrefix(line, "# Source: ") {
+			source = line + "\n"
+			break
+		}
+	}
+	return source + string(out), nil
+}
+
+// exportFileName names the file of m after its kind and name, e.g.
+// "deployment-web.yaml", and adds it to taken.
+func exportFileName(m manifest, taken map[string]bool) string {
+	base := strings.ToLower(m.head.Kind) + "-" + m.head.Metadata.Name
+	name := base + ".yaml"
+	if taken[name] && m.head.Metadata.Namespace != "" {
+		// The same kind and name in another namespace.
+		base += "-" + m.head.Metadata.Namespace
+		name = base + ".yaml"
+	}
+	for i := 2; taken[name]; i++ {
+		name = fmt.Sprintf("%s-%d.yaml", base, i)
+	}
+	taken[name] = true
+	return name
+}
+
+// eventName returns the name of the hook event e, e.g. "pre-upgrade".
+func eventName(e release.Hook_Event) string {
+	names := make([]string, 0, len(events))
+	for name, code := range events {
+		if code == e {
+			names = append(names, name)
+		}
+	}
+	if len(names) == 0 {
+		return strings.ToLower(e.String())
+	}
+	sort.Strings(names)
+	return names[0]
+}
@@ -0,0 +1,156 @@
+/*
+Copyright 2017 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tiller
+
+import (
+	"strings"
+	"testing"
+
+	"k8s.io/helm/pkg/helm"
+	"k8s.io/helm/pkg/proto/hapi/chart"
+	"k8s.io/helm/pkg/proto/hapi/services"
+)
+
+var exportManifest = `---
+# Source: hello/templates/deploy.yaml
+apiVersion: extensions/v1beta1
+kind: Deployment
+metadata:
+  name: web
+---
+# Source: hello/templates/cm.yaml
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: settings
+  annotations:
+    helm.sh/resource-policy: keep
+    team: payments
+---
+# Source: hello/templates/svc.yaml
+apiVersion: v1
+kind: Service
+metadata:
+  name: web
+  annotations:
+    helm.sh/resource-policy: keep
+`
+
+func TestExportRelease(t *testing.T) {
+	c := helm.NewContext()
+	rs := rsFixture()
+	rel := releaseStub()
+	rel.Namespace = "shop"
+	rel.Manifest = exportManifest
+	rs.env.Releases.Create(rel)
+
+	res, err := rs.ExportRelease(c, &services.ExportReleaseRequest{Name: rel.Name})
+	if err != nil {
+		t.Fatalf("Failed export: %s", err)
+	}
+
+	var names []string
+	files := map[string]string{}
+	for _, f := range res.Files {
+		names = append(names, f.Name)
+		files[f.Name] = f.Content
+	}
+	expect := "kustomization.yaml, configmap-settings.yaml, service-web.yaml, deployment-web.yaml"
+	if got := strings.Join(names, ", "); got != expect {
+		t.Errorf("Expected files %s, got %s", expect, got)
+	}
+
+	k := files[kustomizationFile]
+	for _, s := range []string{
+		`# Exported from Helm release "angry-panda", revision 1, chart hello-`,
+		"one-way export",
+		"#   post-install ConfigMap/test-cm\n",
+		"kind: Kustomization\n",
+		"namespace: shop\n",
+		"resources:\n- configmap-settings.yaml\n- service-web.yaml\n- deployment-web.yaml\n",
+	} {
+		if !strings.Contains(k, s) {
+			t.Errorf("Expected kustomization.yaml to contain %q, got:\n%s", s, k)
+		}
+	}
+
+	cm := files["configmap-settings.yaml"]
+	if !strings.HasPrefix(cm, "# Source: hello/templates/cm.yaml\n") {
+		t.Errorf("Expected the source of the resource to be kept, got:\n%s", cm)
+	}
+	if strings.Contains(cm, "helm.sh/") || !strings.Contains(cm, "team: payments") {
+		t.Errorf("Expected only the Helm annotations to be removed, got:\n%s", cm)
+	}
+	if svc := files["service-web.yaml"]; strings.Contains(svc, "annotations") {
+		t.Errorf("Expected empty annotations to be removed, got:\n%s", svc)
+	}
+
+	hooks := strings.Join(res.OmittedHooks, ", ")
+	if hooks != "post-install ConfigMap/test-cm, pre-delete ConfigMap/test-cm, test-success Pod/finding-nemo" {
+		t.Errorf("Unexpected omitted hooks: %s", hooks)
+	}
+}
+
+func TestExportReleaseChart(t *testing.T) {
+	c := helm.NewContext()
+	rs := rsFixture()
+	ch := &chart.Chart{
+		Metadata: &chart.Metadata{Name: "hello", Version: "0.1.0"},
+		Templates: []*chart.Template{
+			{Name: "templates/cm.yaml", Data: []byte("apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: {{ .Release.Name }}-cm\ndata:\n  color: {{ .Values.color }}\n")},
+		},
+	}
+	values := &chart.Config{Raw: "color: blue\n"}
+
+	res, err := rs.ExportRelease(c, &services.ExportReleaseRequest{Name: "preview", Namespace: "dev", Chart: ch, Values: values})
+	if err != nil {
+		t.Fatalf("Failed export: %s", err)
+	}
+	if len(res.Files) != 2 || res.Files[1].Name != "configmap-preview-cm.yaml" {
+		t.Fatalf("Unexpected files: %v", res.Files)
+	}
+	if !strings.Contains(res.Files[1].Content, "color: blue") {
+		t.Errorf("Expected the values to be rendered, got:\n%s", res.Files[1].Content)
+	}
+	if h, _ := rs.env.Releases.History("preview"); len(h) != 0 {
+		t.Errorf("Expected no release to be recorded, got %d", len(h))
+	}
+
+	if _, err := rs.ExportRelease(c, &services.ExportReleaseRequest{Chart: ch}); err == nil {
+		t.Error("Expected a chart without a release name to be rejected")
+	}
+	if _, err := rs.ExportRelease(c, &services.ExportReleaseRequest{Name: "no-such-release"}); err == nil || !strings.Contains(err.Error(), "not found") {
+		t.Errorf("Expected a missing release to be reported, got %v", err)
+	}
+}
+
+func TestExportFileName(t *testing.T) {
+	taken := map[string]bool{kustomizationFile: true}
+	m := func(doc string) manifest {
+		head := manifestHead(doc)
+		return manifest{content: doc, head: head}
+	}
+	got := []string{
+		exportFileName(m("kind: Secret\nmetadata:\n  name: db\n"), taken),
+		exportFileName(m("kind: Secret\nmetadata:\n  name: db\n  namespace: other\n"), taken),
+		exportFileName(m("kind: Secret\nmetadata:\n  name: db\n"), taken),
+	}
+	expect := "secret-db.yaml, secret-db-other.yaml, secret-db-2.yaml"
+	if strings.Join(got, ", ") != expect {
+		t.Errorf("Expected %s, got %s", expect, strings.Join(got, ", "))
+	}
+}
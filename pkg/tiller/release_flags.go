@@ -0,0 +1,44 @@
+/*
+Copyright 2017 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tiller
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// validFlag matches the names of feature flags, which templates read as
+// fields, e.g. .Release.Flags.canary.
+var validFlag = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// validateFlags returns an error listing the feature flags whose names
+// templates could not read as fields of .Release.Flags.
+func validateFlags(flags map[string]bool) error {
+	var invalid []string
+	for k := range flags {
+		if !validFlag.MatchString(k) {
+			invalid = append(invalid, fmt.Sprintf("%q", k))
+		}
+	}
+	if len(invalid) == 0 {
+		return nil
+	}
+	sort.Strings(invalid)
+	return fmt.Errorf("invalid feature flag names: %s; names must start with a letter or underscore and contain only letters, digits and underscores", strings.Join(invalid, ", "))
+}
@@ -0,0 +1,92 @@
+/*
+Copyright 2017 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tiller
+
+import (
+	"strings"
+	"testing"
+
+	"k8s.io/helm/pkg/helm"
+	"k8s.io/helm/pkg/proto/hapi/chart"
+	"k8s.io/helm/pkg/proto/hapi/services"
+)
+
+func flagsChart() *chart.Chart {
+	return &chart.Chart{
+		Metadata: &chart.Metadata{Name: "hello"},
+		Templates: []*chart.Template{
+			{Name: "templates/web.yaml", Data: []byte("apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: web\ndata:\n  track: {{ if .Release.Flags.canary }}canary{{ else }}stable{{ end }}\n")},
+		},
+	}
+}
+
+func TestReleaseFlags(t *testing.T) {
+	c := helm.NewContext()
+	rs := rsFixture()
+
+	// The flags of an install are rendered and recorded.
+	res, err := rs.InstallRelease(c, &services.InstallReleaseRequest{
+		Name:      "flagged",
+		Namespace: "spaced",
+		Chart:     flagsChart(),
+		Flags:     map[string]bool{"canary": true},
+	})
+	if err != nil {
+		t.Fatalf("Failed install: %s", err)
+	}
+	if !strings.Contains(res.Release.Manifest, "track: canary") {
+		t.Errorf("Expected the canary branch to be rendered, got %s", res.Release.Manifest)
+	}
+	if !res.Release.Info.Flags["canary"] {
+		t.Errorf("Expected the flags to be recorded, got %v", res.Release.Info.Flags)
+	}
+	if res.Release.Config != nil && strings.Contains(res.Release.Config.Raw, "canary") {
+		t.Errorf("Expected the flags to stay out of the values, got %q", res.Release.Config.Raw)
+	}
+
+	// Flags are not carried over to an upgrade, even when it reuses values.
+	ures, err := rs.UpdateRelease(c, &services.UpdateReleaseRequest{
+		Name:        "flagged",
+		Chart:       flagsChart(),
+		ReuseValues: true,
+	})
+	if err != nil {
+		t.Fatalf("Failed upgrade: %s", err)
+	}
+	if !strings.Contains(ures.Release.Manifest, "track: stable") || len(ures.Release.Info.Flags) != 0 {
+		t.Errorf("Expected the upgrade to be rendered without flags, got %v and %s", ures.Release.Info.Flags, ures.Release.Manifest)
+	}
+
+	// A rollback renders the revision it restores with its flags.
+	rres, err := rs.RollbackRelease(c, &services.RollbackReleaseRequest{Name: "flagged", Version: 1})
+	if err != nil {
+		t.Fatalf("Failed rollback: %s", err)
+	}
+	if !strings.Contains(rres.Release.Manifest, "track: canary") || !rres.Release.Info.Flags["canary"] {
+		t.Errorf("Expected the rollback to be rendered with the flags of v1, got %v and %s", rres.Release.Info.Flags, rres.Release.Manifest)
+	}
+}
+
+func TestValidateFlags(t *testing.T) {
+	if err := validateFlags(map[string]bool{"canary": true, "new_ui": false, "_beta2": true}); err != nil {
+		t.Errorf("Expected valid flags, got %s", err)
+	}
+	err := validateFlags(map[string]bool{"dark-mode": true, "2fa": true, "ok": true})
+	if err == nil || !strings.Contains(err.Error(), `"2fa", "dark-mode"`) {
+		t.Errorf("Expected the invalid flag names to be listed, got %v", err)
+	}
+}
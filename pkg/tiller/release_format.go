@@ -0,0 +1,202 @@
+/*
+Copyright 2017 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tiller
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/ghodss/yaml"
+	"github.com/golang/protobuf/ptypes/timestamp"
+	"github.com/gosuri/uitable"
+
+	"k8s.io/helm/pkg/proto/hapi/release"
+	"k8s.io/helm/pkg/proto/hapi/services"
+	"k8s.io/helm/pkg/timeconv"
+)
+
+// releaseRow is the flattened form of a release in a rendered listing.
+type releaseRow struct {
+	Name      string `json:"name"`
+	Revision  int32  `json:"revision"`
+	Updated   string `json:"updated"`
+	Status    string `json:"status"`
+	Chart     string `json:"chart"`
+	Namespace string `json:"namespace"`
+}
+
+// releaseStatus is the flattened form of a rendered release status.
+type releaseStatus struct {
+	Name         string            `json:"name"`
+	Revision     int32             `json:"revision"`
+	Namespace    string            `json:"namespace"`
+	Namespaces   []string          `json:"namespaces,omitempty"`
+	Cluster      string            `json:"cluster,omitempty"`
+	Status       string            `json:"status"`
+	Description  string            `json:"description,omitempty"`
+	LastDeployed string            `json:"lastDeployed,omitempty"`
+	Annotations  map[string]string `json:"annotations,omitempty"`
+	Resources    string            `json:"resources,omitempty"`
+	Applied      []appliedRow      `json:"applied,omitempty"`
+	Mutations    []mutationRow     `json:"valuesMutations,omitempty"`
+	Injections   []injectionRow    `json:"containerInjections,omitempty"`
+	Notes        string            `json:"notes,omitempty"`
+}
+
+// appliedRow is the outcome of applying a resource of a revision that failed
+// part way.
+type appliedRow struct {
+	Resource  string `json:"resource"`
+	Status    string `json:"status"`
+	Recreated bool   `json:"recreated,omitempty"`
+	Error     string `json:"error,omitempty"`
+}
+
+// mutationRow is what a values mutator changed in the values of a revision.
+type mutationRow struct {
+	Mutator string   `json:"mutator"`
+	Changed []string `json:"changed"`
+}
+
+// injectionRow is what an injection policy added to a workload of a revision.
+type injectionRow struct {
+	Policy     string   `json:"policy"`
+	Resource   string   `json:"resource"`
+	Containers []string `json:"containers"`
+}
+
+// validateOutputFormat checks that f is a known output format.
+func validateOutputFormat(f services.OutputFormat_Format) error {
+	if _, ok := services.OutputFormat_Format_name[int32(f)]; !ok {
+		return fmt.Errorf("unknown output format %d", f)
+	}
+	return nil
+}
+
+// renderList renders rels in format f, or returns "" for OutputFormat_NONE.
+func renderList(f services.OutputFormat_Format, rels []*release.Release) (string, error) {
+	rows := make([]releaseRow, 0, len(rels))
+	for _, r := range rels {
+		rows = append(rows, releaseRow{
+			Name:      r.Name,
+			Revision:  r.Version,
+			Updated:   formatTime(r.Info.LastDeployed),
+			Status:    r.Info.Status.Code.String(),
+			Chart:     chartRef(r),
+			Namespace: r.Namespace,
+		})
+	}
+	return render(f, rows, func() string {
+		table := uitable.New()
+		table.MaxColWidth = 60
+		table.AddRow("NAME", "REVISION", "UPDATED", "STATUS", "CHART", "NAMESPACE")
+		for _, r := range rels {
+			table.AddRow(r.Name, r.Version, timeconv.String(r.Info.LastDeployed), r.Info.Status.Code, chartRef(r), r.Namespace)
+		}
+		return table.String()
+	})
+}
+
+// renderStatus renders the status of rel, as found in res, in format f, or
+// returns "" for OutputFormat_NONE.
+func renderStatus(f services.OutputFormat_Format, rel *release.Release, res *services.GetReleaseStatusResponse) (string, error) {
+	st := releaseStatus{
+		Name:         res.Name,
+		Revision:     rel.Version,
+		Namespace:    res.Namespace,
+		Namespaces:   res.Namespaces,
+		Cluster:      res.Cluster,
+		Status:       res.Info.Status.Code.String(),
+		Description:  res.Info.Description,
+		LastDeployed: formatTime(res.Info.LastDeployed),
+		Annotations:  res.Info.Annotations,
+		Resources:    res.Info.Status.Resources,
+		Notes:        res.Info.Status.Notes,
+	}
+	for _, rs := range res.Info.ResourceStatuses {
+		st.Applied = append(st.Applied, appliedRow{Resource: rs.Kind + "/" + rs.Name, Status: rs.Code.String(), Recreated: rs.Recreated, Error: rs.Error})
+	}
+	for _, m := range res.Info.ValuesMutations {
+		st.Mutations = append(st.Mutations, mutationRow{Mutator: m.Mutator, Changed: m.Changed})
+	}
+	for _, in := range res.Info.ContainerInjections {
+		st.Injections = append(st.Injections, injectionRow{Policy: in.Policy, Resource: in.Kind + "/" + in.Name, Containers: in.Containers})
+	}
+	return render(f, st, func() string {
+		table := uitable.New()
+		table.MaxColWidth = 80
+		table.AddRow("NAME:", st.Name)
+		table.AddRow("REVISION:", st.Revision)
+		table.AddRow("NAMESPACE:", st.Namespace)
+		if len(st.Namespaces) > 1 {
+			table.AddRow("OTHER NAMESPACES:", strings.Join(st.Namespaces[1:], ", "))
+		}
+		if st.Cluster != "" {
+			table.AddRow("CLUSTER:", st.Cluster)
+		}
+		table.AddRow("STATUS:", st.Status)
+		table.AddRow("DESCRIPTION:", st.Description)
+		if res.Info.LastDeployed != nil {
+			table.AddRow("LAST DEPLOYED:", timeconv.String(res.Info.LastDeployed))
+		}
+		for _, a := range st.Applied {
+			resource := a.Resource
+			if a.Recreated {
+				resource += " (re-created)"
+			}
+			if a.Error != "" {
+				table.AddRow(a.Status+":", resource+": "+a.Error)
+			} else {
+				table.AddRow(a.Status+":", resource)
+			}
+		}
+		return table.String()
+	})
+}
+
+// chartRef returns the name and version of the chart of r, as 'helm list'
+// shows them.
+func chartRef(r *release.Release) string {
+	return fmt.Sprintf("%s-%s", r.Chart.Metadata.Name, r.Chart.Metadata.Version)
+}
+
+// render marshals v in format f. table renders the TABLE format.
+func render(f services.OutputFormat_Format, v interface{}, table func() string) (string, error) {
+	switch f {
+	case services.OutputFormat_NONE:
+		return "", nil
+	case services.OutputFormat_JSON:
+		b, err := json.Marshal(v)
+		return string(b), err
+	case services.OutputFormat_YAML:
+		b, err := yaml.Marshal(v)
+		return string(b), err
+	case services.OutputFormat_TABLE:
+		return table(), nil
+	}
+	return "", validateOutputFormat(f)
+}
+
+// formatTime formats ts as RFC 3339 in UTC, or returns "" if it is not set.
+func formatTime(ts *timestamp.Timestamp) string {
+	if ts == nil {
+		return ""
+	}
+	return timeconv.Time(ts).UTC().Format(time.RFC3339)
+}
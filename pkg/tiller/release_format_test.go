@@ -0,0 +1,188 @@
+/*
+Copyright 2017 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tiller
+
+import (
+	"strings"
+	"testing"
+
+	"k8s.io/helm/pkg/helm"
+	"k8s.io/helm/pkg/proto/hapi/release"
+	"k8s.io/helm/pkg/proto/hapi/services"
+)
+
+func TestListReleasesRendered(t *testing.T) {
+	rs := rsFixture()
+	for _, name := range []string{"bashful-beaver", "clever-cat"} {
+		rel := namedReleaseStub(name, release.Status_DEPLOYED)
+		rel.Namespace = "default"
+		if err := rs.env.Releases.Create(rel); err != nil {
+			t.Fatalf("Could not store mock release: %s", err)
+		}
+	}
+
+	tests := []struct {
+		format services.OutputFormat_Format
+		expect string
+	}{
+		{services.OutputFormat_NONE, ""},
+		{
+			services.OutputFormat_JSON,
+			`[{"name":"bashful-beaver","revision":1,"updated":"1977-09-02T22:04:05Z","status":"DEPLOYED","chart":"hello-","namespace":"default"},` +
+				`{"name":"clever-cat","revision":1,"updated":"1977-09-02T22:04:05Z","status":"DEPLOYED","chart":"hello-","namespace":"default"}]`,
+		},
+		{
+			services.OutputFormat_YAML,
+			`- chart: hello-
+  name: bashful-beaver
+  namespace: default
+  revision: 1
+  status: DEPLOYED
+  updated: 1977-09-02T22:04:05Z
+- chart: hello-
+  name: clever-cat
+  namespace: default
+  revision: 1
+  status: DEPLOYED
+  updated: 1977-09-02T22:04:05Z
+`,
+		},
+	}
+	for _, tt := range tests {
+		mrs := &mockListServer{}
+		req := &services.ListReleasesRequest{SortBy: services.ListSort_NAME, OutputFormat: tt.format}
+		if err := rs.ListReleases(req, mrs); err != nil {
+			t.Fatalf("Failed listing in %s: %s", tt.format, err)
+		}
+		if mrs.val.Rendered != tt.expect {
+			t.Errorf("Expected %s rendering\n%s\ngot\n%s", tt.format, tt.expect, mrs.val.Rendered)
+		}
+		if len(mrs.val.Releases) != 2 {
+			t.Errorf("Expected the releases to be returned with the %s rendering, got %d", tt.format, len(mrs.val.Releases))
+		}
+	}
+
+	mrs := &mockListServer{}
+	req := &services.ListReleasesRequest{SortBy: services.ListSort_NAME, OutputFormat: services.OutputFormat_TABLE}
+	if err := rs.ListReleases(req, mrs); err != nil {
+		t.Fatalf("Failed listing: %s", err)
+	}
+	lines := strings.Split(mrs.val.Rendered, "\n")
+	if len(lines) != 3 || !strings.HasPrefix(lines[0], "NAME") || !strings.HasPrefix(lines[1], "bashful-beaver") {
+		t.Errorf("Expected a table with a header and two releases, got\n%s", mrs.val.Rendered)
+	}
+
+	if err := rs.ListReleases(&services.ListReleasesRequest{OutputFormat: 42}, &mockListServer{}); err == nil {
+		t.Error("Expected an error for an unknown output format")
+	}
+}
+
+func TestGetReleaseStatusRendered(t *testing.T) {
+	c := helm.NewContext()
+	rs := rsFixture()
+	rel := releaseStub()
+	rel.Namespace = "default"
+	rel.Namespaces = []string{"default", "monitoring"}
+	if err := rs.env.Releases.Create(rel); err != nil {
+		t.Fatalf("Could not store mock release: %s", err)
+	}
+
+	req := &services.GetReleaseStatusRequest{Name: rel.Name, OutputFormat: services.OutputFormat_JSON}
+	res, err := rs.GetReleaseStatus(c, req)
+	if err != nil {
+		t.Fatalf("Error getting release status: %s", err)
+	}
+	expect := `{"name":"angry-panda","revision":1,"namespace":"default","namespaces":["default","monitoring"],` +
+		`"status":"DEPLOYED","description":"Named Release Stub","lastDeployed":"1977-09-02T22:04:05Z"}`
+	if res.Rendered != expect {
+		t.Errorf("Expected\n%s\ngot\n%s", expect, res.Rendered)
+	}
+
+	req.OutputFormat = services.OutputFormat_TABLE
+	if res, err = rs.GetReleaseStatus(c, req); err != nil {
+		t.Fatalf("Error getting release status: %s", err)
+	}
+	rows := map[string]string{}
+	for _, line := range strings.Split(res.Rendered, "\n") {
+		if parts := strings.SplitN(line, ":", 2); len(parts) == 2 {
+			rows[parts[0]] = strings.TrimSpace(parts[1])
+		}
+	}
+	for field, value := range map[string]string{"NAME": "angry-panda", "REVISION": "1", "OTHER NAMESPACES": "monitoring", "STATUS": "DEPLOYED"} {
+		if rows[field] != value {
+			t.Errorf("Expected %s to be %q, got %q in\n%s", field, value, rows[field], res.Rendered)
+		}
+	}
+
+	req.OutputFormat = -1
+	if _, err := rs.GetReleaseStatus(c, req); err == nil {
+		t.Error("Expected an error for an unknown output format")
+	}
+}
+
+func TestGetReleaseStatusRenderedApplied(t *testing.T) {
+	c := helm.NewContext()
+	rs := rsFixture()
+	_, failed := partiallyFailedRelease(rs)
+
+	req := &services.GetReleaseStatusRequest{Name: failed.Name, OutputFormat: services.OutputFormat_JSON}
+	res, err := rs.GetReleaseStatus(c, req)
+	if err != nil {
+		t.Fatalf("Error getting release status: %s", err)
+	}
+	expect := `"applied":[{"resource":"ConfigMap/test-cm","status":"APPLIED"},` +
+		`{"resource":"Service/test-svc","status":"FAILED","error":"field is immutable"}]`
+	if !strings.Contains(res.Rendered, expect) {
+		t.Errorf("Expected\n%s\nin\n%s", expect, res.Rendered)
+	}
+}
+
+func TestGetReleaseStatusRenderedMutations(t *testing.T) {
+	c := helm.NewContext()
+	rs := rsFixture()
+	rel := releaseStub()
+	rel.Info.ValuesMutations = []*release.ValuesMutation{{Mutator: "ipam", Changed: []string{"network.ip"}}}
+	rs.env.Releases.Create(rel)
+
+	req := &services.GetReleaseStatusRequest{Name: rel.Name, OutputFormat: services.OutputFormat_JSON}
+	res, err := rs.GetReleaseStatus(c, req)
+	if err != nil {
+		t.Fatalf("Error getting release status: %s", err)
+	}
+	expect := `"valuesMutations":[{"mutator":"ipam","changed":["network.ip"]}]`
+	if !strings.Contains(res.Rendered, expect) {
+		t.Errorf("Expected\n%s\nin\n%s", expect, res.Rendered)
+	}
+}
+
+func TestGetReleaseStatusRenderedInjections(t *testing.T) {
+	c := helm.NewContext()
+	rs := rsFixture()
+	rel := releaseStub()
+	rel.Info.ContainerInjections = []*release.ContainerInjection{{Policy: "mesh", Kind: "Deployment", Name: "web", Containers: []string{"proxy"}}}
+	rs.env.Releases.Create(rel)
+
+	req := &services.GetReleaseStatusRequest{Name: rel.Name, OutputFormat: services.OutputFormat_JSON}
+	res, err := rs.GetReleaseStatus(c, req)
+	if err != nil {
+		t.Fatalf("Error getting release status: %s", err)
+	}
+	expect := `"containerInjections":[{"policy":"mesh","resource":"Deployment/web","containers":["proxy"]}]`
+	if !strings.Contains(res.Rendered, expect) {
+		t.Errorf("Expected\n%s\nin\n%s", expect, res.Rendered)
+	}
+}
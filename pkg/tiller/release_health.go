@@ -0,0 +1,82 @@
+/*
+Copyright 2017 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tiller
+
+import (
+	"errors"
+	"time"
+
+	ctx "golang.org/x/net/context"
+
+	"k8s.io/helm/pkg/proto/hapi/release"
+	"k8s.io/helm/pkg/proto/hapi/services"
+)
+
+// GetHealth checks that the storage driver and the Kubernetes API can be
+// reached, and reports the result and latency of each check.
+//
+// It does not take any release locks, so it can be polled freely.
+func (s *ReleaseServer) GetHealth(c ctx.Context, req *services.GetHealthRequest) (*services.GetHealthResponse, error) {
+	res := &services.GetHealthResponse{
+		Status: services.DependencyHealth_OK,
+		Dependencies: []*services.DependencyHealth{
+			checkHealth("storage", s.checkStorage),
+			checkHealth("kubernetes", s.checkKubernetes),
+		},
+	}
+	for _, d := range res.Dependencies {
+		if d.Status != services.DependencyHealth_OK {
+			res.Status = services.DependencyHealth_DEGRADED
+			s.Log("warning: health check for %s failed: %s", d.Name, d.Error)
+		}
+	}
+	return res, nil
+}
+
+// checkHealth runs check and records its outcome and latency.
+func checkHealth(name string, check func() error) *services.DependencyHealth {
+	start := time.Now()
+	err := check()
+	d := &services.DependencyHealth{
+		Name:      name,
+		Status:    services.DependencyHealth_OK,
+		LatencyMs: int64(time.Since(start) / time.Millisecond),
+	}
+	if err != nil {
+		d.Status = services.DependencyHealth_DEGRADED
+		d.Error = err.Error()
+	}
+	return d
+}
+
+// checkStorage lists releases through the storage driver without keeping any.
+func (s *ReleaseServer) checkStorage() error {
+	if s.env.Releases == nil {
+		return errors.New("no storage driver configured")
+	}
+	_, err := s.env.Releases.List(func(*release.Release) bool { return false })
+	return err
+}
+
+// checkKubernetes asks the API server for its version.
+func (s *ReleaseServer) checkKubernetes() error {
+	if s.clientset == nil {
+		return errors.New("no Kubernetes client configured")
+	}
+	_, err := s.clientset.Discovery().ServerVersion()
+	return err
+}
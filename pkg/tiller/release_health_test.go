@@ -0,0 +1,127 @@
+/*
+Copyright 2017 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tiller
+
+import (
+	"errors"
+	"testing"
+
+	"golang.org/x/net/context"
+	"k8s.io/apimachinery/pkg/version"
+	"k8s.io/client-go/discovery"
+	"k8s.io/kubernetes/pkg/client/clientset_generated/internalclientset/fake"
+
+	"k8s.io/helm/pkg/proto/hapi/release"
+	"k8s.io/helm/pkg/proto/hapi/services"
+	"k8s.io/helm/pkg/storage"
+	"k8s.io/helm/pkg/storage/driver"
+)
+
+type unreachableDriver struct {
+	driver.Driver
+}
+
+func (d *unreachableDriver) List(func(*release.Release) bool) ([]*release.Release, error) {
+	return nil, errors.New("storage unreachable")
+}
+
+type unreachableDiscovery struct {
+	discovery.DiscoveryInterface
+}
+
+func (d *unreachableDiscovery) ServerVersion() (*version.Info, error) {
+	return nil, errors.New("api server unreachable")
+}
+
+type unreachableClientset struct {
+	*fake.Clientset
+}
+
+func (c *unreachableClientset) Discovery() discovery.DiscoveryInterface {
+	return &unreachableDiscovery{c.Clientset.Discovery()}
+}
+
+func healthByName(res *services.GetHealthResponse) map[string]*services.DependencyHealth {
+	m := map[string]*services.DependencyHealth{}
+	for _, d := range res.Dependencies {
+		m[d.Name] = d
+	}
+	return m
+}
+
+func TestGetHealth(t *testing.T) {
+	rs := rsFixture()
+
+	res, err := rs.GetHealth(context.TODO(), &services.GetHealthRequest{})
+	if err != nil {
+		t.Fatalf("Failed health check: %s", err)
+	}
+	if res.Status != services.DependencyHealth_OK {
+		t.Errorf("Expected status OK, got %s", res.Status)
+	}
+	deps := healthByName(res)
+	for _, name := range []string{"storage", "kubernetes"} {
+		d, ok := deps[name]
+		if !ok {
+			t.Errorf("Expected a %q dependency", name)
+			continue
+		}
+		if d.Status != services.DependencyHealth_OK || d.Error != "" {
+			t.Errorf("Expected %s to be OK, got %s (%q)", name, d.Status, d.Error)
+		}
+	}
+}
+
+func TestGetHealth_StorageUnreachable(t *testing.T) {
+	rs := rsFixture()
+	rs.env.Releases = storage.Init(&unreachableDriver{driver.NewMemory()})
+
+	res, err := rs.GetHealth(context.TODO(), &services.GetHealthRequest{})
+	if err != nil {
+		t.Fatalf("Failed health check: %s", err)
+	}
+	if res.Status != services.DependencyHealth_DEGRADED {
+		t.Errorf("Expected status DEGRADED, got %s", res.Status)
+	}
+	deps := healthByName(res)
+	if d := deps["storage"]; d.Status != services.DependencyHealth_DEGRADED || d.Error != "storage unreachable" {
+		t.Errorf("Expected storage to be degraded, got %s (%q)", d.Status, d.Error)
+	}
+	if d := deps["kubernetes"]; d.Status != services.DependencyHealth_OK {
+		t.Errorf("Expected kubernetes to be OK, got %s (%q)", d.Status, d.Error)
+	}
+}
+
+func TestGetHealth_KubernetesUnreachable(t *testing.T) {
+	rs := rsFixture()
+	rs.clientset = &unreachableClientset{fake.NewSimpleClientset()}
+
+	res, err := rs.GetHealth(context.TODO(), &services.GetHealthRequest{})
+	if err != nil {
+		t.Fatalf("Failed health check: %s", err)
+	}
+	if res.Status != services.DependencyHealth_DEGRADED {
+		t.Errorf("Expected status DEGRADED, got %s", res.Status)
+	}
+	deps := healthByName(res)
+	if d := deps["kubernetes"]; d.Status != services.DependencyHealth_DEGRADED || d.Error != "api server unreachable" {
+		t.Errorf("Expected kubernetes to be degraded, got %s (%q)", d.Status, d.Error)
+	}
+	if d := deps["storage"]; d.Status != services.DependencyHealth_OK {
+		t.Errorf("Expected storage to be OK, got %s (%q)", d.Status, d.Error)
+	}
+}
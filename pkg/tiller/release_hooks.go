@@ -0,0 +1,55 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tiller
+
+import (
+	"fmt"
+
+	ctx "golang.org/x/net/context"
+
+	"k8s.io/helm/pkg/proto/hapi/release"
+	"k8s.io/helm/pkg/proto/hapi/services"
+)
+
+// GetHooks returns the hooks that the operations on a revision of a release
+// ran or skipped, in order, with how they ended. Version zero is the latest
+// revision, whether it was deployed or failed, so that the hooks of a failed
+// operation can be looked into.
+func (s *ReleaseServer) GetHooks(c ctx.Context, req *services.GetHooksRequest) (*services.GetHooksResponse, error) {
+	if !ValidName.MatchString(req.Name) {
+		return nil, errMissingRelease
+	}
+	if req.Version < 0 {
+		return nil, errInvalidRevision
+	}
+
+	var rel *release.Release
+	var err error
+	if req.Version == 0 {
+		if rel, err = s.env.Releases.Last(req.Name); err != nil {
+			return nil, fmt.Errorf("getting release %q: %s", req.Name, err)
+		}
+	} else if rel, err = s.env.Releases.Get(req.Name, req.Version); err != nil {
+		return nil, fmt.Errorf("getting release '%s' (v%d): %s", req.Name, req.Version, err)
+	}
+
+	return &services.GetHooksResponse{
+		Name:       rel.Name,
+		Version:    rel.Version,
+		Executions: rel.HookExecutions,
+	}, nil
+}
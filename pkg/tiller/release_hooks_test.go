@@ -0,0 +1,131 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tiller
+
+import (
+	"errors"
+	"io"
+	"os"
+	"testing"
+
+	"k8s.io/helm/pkg/helm"
+	"k8s.io/helm/pkg/kube"
+	"k8s.io/helm/pkg/proto/hapi/chart"
+	"k8s.io/helm/pkg/proto/hapi/release"
+	"k8s.io/helm/pkg/proto/hapi/services"
+	"k8s.io/helm/pkg/tiller/environment"
+)
+
+// failingHookKubeClient fails every hook with err.
+type failingHookKubeClient struct {
+	environment.PrintingKubeClient
+	err error
+}
+
+func (k *failingHookKubeClient) WatchUntilReady(ns string, r io.Reader, timeout int64, shouldWait bool) error {
+	return k.err
+}
+
+func hooksInstallRequest() *services.InstallReleaseRequest {
+	return &services.InstallReleaseRequest{
+		Name: "hooked",
+		Chart: &chart.Chart{
+			Metadata: &chart.Metadata{Name: "hello"},
+			Templates: []*chart.Template{
+				{Name: "templates/hello", Data: []byte("hello: world")},
+				{Name: "templates/hooks", Data: []byte(manifestWithHook)},
+			},
+		},
+	}
+}
+
+func TestGetHooks(t *testing.T) {
+	c := helm.NewContext()
+	rs := rsFixture()
+	if _, err := rs.InstallRelease(c, hooksInstallRequest()); err != nil {
+		t.Fatalf("Failed install: %s", err)
+	}
+
+	res, err := rs.GetHooks(c, &services.GetHooksRequest{Name: "hooked"})
+	if err != nil {
+		t.Fatalf("Failed to get hooks: %s", err)
+	}
+	if res.Version != 1 || len(res.Executions) != 1 {
+		t.Fatalf("Expected one execution of revision 1, got %d of revision %d", len(res.Executions), res.Version)
+	}
+	e := res.Executions[0]
+	if e.Name != "test-cm" || e.Event != release.Hook_POST_INSTALL || e.Phase != release.HookExecution_SUCCEEDED || e.Attempts != 1 {
+		t.Errorf("Unexpected execution: %+v", e)
+	}
+	if e.StartedAt == nil || e.CompletedAt == nil {
+		t.Errorf("Expected the execution to be timed, got %v to %v", e.StartedAt, e.CompletedAt)
+	}
+
+	rel, _ := rs.env.Releases.Get("hooked", 1)
+	if rel.Hooks[0].LastPhase != release.HookExecution_SUCCEEDED {
+		t.Errorf("Expected the last phase of the hook to be recorded, got %s", rel.Hooks[0].LastPhase)
+	}
+
+	if _, err := rs.GetHooks(c, &services.GetHooksRequest{Name: "hooked", Version: 2}); err == nil {
+		t.Error("Expected an error for a missing revision")
+	}
+}
+
+func TestGetHooks_Failed(t *testing.T) {
+	c := helm.NewContext()
+	rs := rsFixture()
+	rs.env.KubeClient = &failingHookKubeClient{
+		PrintingKubeClient: environment.PrintingKubeClient{Out: os.Stdout},
+		err: &kube.HookError{
+			Err:  errors.New("Job failed: BackoffLimitExceeded"),
+			Logs: "==> default/migrate-1/migrate <==\nmigration 42 failed\n",
+		},
+	}
+	if _, err := rs.InstallRelease(c, hooksInstallRequest()); err == nil {
+		t.Fatal("Expected the install to fail")
+	}
+
+	res, err := rs.GetHooks(c, &services.GetHooksRequest{Name: "hooked"})
+	if err != nil {
+		t.Fatalf("Failed to get hooks: %s", err)
+	}
+	if len(res.Executions) != 1 {
+		t.Fatalf("Expected one execution, got %d", len(res.Executions))
+	}
+	e := res.Executions[0]
+	if e.Phase != release.HookExecution_FAILED {
+		t.Errorf("Expected the hook to have failed, got %s", e.Phase)
+	}
+	if e.Error != "Job failed: BackoffLimitExceeded" {
+		t.Errorf("Unexpected error %q", e.Error)
+	}
+	if e.Log != "==> default/migrate-1/migrate <==\nmigration 42 failed\n" {
+		t.Errorf("Expected the logs to be kept apart from the error, got %q", e.Log)
+	}
+}
+
+func TestExecHook_Skipped(t *testing.T) {
+	rs := rsFixture()
+	h := &release.Hook{Name: "cleanup", Kind: "Job", Events: []release.Hook_Event{release.Hook_PRE_INSTALL}, Weight: 5}
+	rel := &release.Release{Name: "skipped", Namespace: "default", Hooks: []*release.Hook{h}}
+	if err := rs.execHook(rs.requestLogger("install", "skipped", 1), rs.env.KubeClient, rel, "pre-install", 0, newHookSkipList([]string{"cleanup"}, nil)); err != nil {
+		t.Fatal(err)
+	}
+	if len(rel.HookExecutions) != 1 || rel.HookExecutions[0].Phase != release.HookExecution_SKIPPED || rel.HookExecutions[0].Weight != 5 {
+		t.Errorf("Expected the skipped hook to be recorded, got %v", rel.HookExecutions)
+	}
+}
@@ -17,10 +17,12 @@ limitations under the License.
 package tiller
 
 import (
+	"bytes"
 	"fmt"
 	ctx "golang.org/x/net/context"
 	"k8s.io/helm/pkg/chartutil"
 	"k8s.io/helm/pkg/hooks"
+	"k8s.io/helm/pkg/logging"
 	"k8s.io/helm/pkg/proto/hapi/release"
 	"k8s.io/helm/pkg/proto/hapi/services"
 	relutil "k8s.io/helm/pkg/releaseutil"
@@ -30,7 +32,14 @@ import (
 
 // InstallRelease installs a release and stores the release record.
 func (s *ReleaseServer) InstallRelease(c ctx.Context, req *services.InstallReleaseRequest) (*services.InstallReleaseResponse, error) {
-	rel, err := s.prepareRelease(req)
+	done := observeOperation("install")
+	res, err := s.installRelease(req)
+	done(err)
+	return res, err
+}
+
+func (s *ReleaseServer) installRelease(req *services.InstallReleaseRequest) (*services.InstallReleaseResponse, error) {
+	rel, values, err := s.prepareRelease(req)
 	if err != nil {
 		s.Log("Failed install prepare step: %s", err)
 		res := &services.InstallReleaseResponse{Release: rel}
@@ -43,107 +52,248 @@ func (s *ReleaseServer) InstallRelease(c ctx.Context, req *services.InstallRelea
 		return res, err
 	}
 
-	res, err := s.performRelease(rel, req)
+	log := s.requestLogger("install", rel.Name, rel.Version)
+	res, err := s.performRelease(log, rel, values, req)
 	if err != nil {
-		s.Log("Failed install perform step: %s", err)
+		log.Errorf("Failed install perform step: %s", err)
 	}
 	return res, err
 }
 
-// prepareRelease builds a release for an install operation.
-func (s *ReleaseServer) prepareRelease(req *services.InstallReleaseRequest) (*release.Release, error) {
+// prepareRelease builds a release for an install operation. It also returns
+// the values the release was rendered with, for its notes to be rendered
+// again with.
+func (s *ReleaseServer) prepareRelease(req *services.InstallReleaseRequest) (*release.Release, chartutil.Values, error) {
 	if req.Chart == nil {
-		return nil, errMissingChart
+		return nil, nil, errMissingChart
+	}
+	if err := validateAnnotations(req.Annotations); err != nil {
+		return nil, nil, err
+	}
+	if err := validateFlags(req.Flags); err != nil {
+		return nil, nil, err
+	}
+	if err := validateOnFailure(req.OnFailure); err != nil {
+		return nil, nil, err
+	}
+	if err := s.valuesLimits.Check(req.Chart, req.Values); err != nil {
+		return nil, nil, err
+	}
+	if req.StrictValues {
+		if err := chartutil.CheckUnknownValues(req.Chart, req.Values); err != nil {
+			return nil, nil, err
+		}
 	}
 
-	name, err := s.uniqName(req.Name, req.ReuseName)
+	name, err := s.uniqName(req.Name, req.ReuseName, req.Chart)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
-	caps, err := capabilities(s.clientset.Discovery())
-	if err != nil {
-		return nil, err
+	// The values of the profile are only merged in for rendering; the
+	// release keeps the name of the profile instead.
+	vals := req.Values
+	if req.Profile != "" {
+		if vals, err = chartutil.ApplyProfile(req.Chart, req.Profile, req.Values, req.AllowMissingProfile); err != nil {
+			return nil, nil, err
+		}
 	}
 
-	revision := 1
-	ts := timeconv.Now()
-	options := chartutil.ReleaseOptions{
-		Name:      name,
-		Time:      ts,
-		Namespace: req.Namespace,
-		Revision:  revision,
-		IsInstall: true,
+	kc, err := s.cluster(req.Cluster)
+	if err != nil {
+		return nil, nil, err
 	}
-	valuesToRender, err := chartutil.ToRenderValuesCaps(req.Chart, req.Values, options, caps)
+	caps, err := s.clusterCapabilities(req.Cluster, kc)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
-	hooks, manifestDoc, notesTxt, err := s.renderResources(req.Chart, valuesToRender, caps.APIVersions)
-	if err != nil {
-		// Return a release with partial data so that client can show debugging
-		// information.
-		rel := &release.Release{
+	var (
+		revision       int
+		generated      map[string]string
+		ts             = timeconv.Now()
+		valuesToRender chartutil.Values
+		hooks          []*release.Hook
+		manifestDoc    *bytes.Buffer
+		notesTxt       string
+		mutations      []*release.ValuesMutation
+		injections     []*release.ContainerInjection
+	)
+	for original, attempt := name, 1; ; attempt++ {
+		// A replaced release is appended to the history of the old one (see
+		// performRelease), so templates should see the revision it will get.
+		// It also keeps the values the old release generated, as resources
+		// that outlived it may still use them.
+		revision = 1
+		generated = generatedValues(nil)
+		if req.ReuseName {
+			if h, err := s.env.Releases.History(name); err == nil && len(h) > 0 {
+				relutil.Reverse(h, relutil.SortByRevision)
+				if h[0].Cluster != req.Cluster {
+					return nil, nil, fmt.Errorf("release %s cannot be replaced in %s, as it was installed in %s", name, describeCluster(req.Cluster), describeCluster(h[0].Cluster))
+				}
+				revision = int(h[0].Version) + 1
+				generated = generatedValues(h[0])
+			}
+		}
+		options := chartutil.ReleaseOptions{
 			Name:      name,
+			Time:      ts,
 			Namespace: req.Namespace,
-			Chart:     req.Chart,
-			Config:    req.Values,
-			Info: &release.Info{
-				FirstDeployed: ts,
-				LastDeployed:  ts,
-				Status:        &release.Status{Code: release.Status_UNKNOWN},
-				Description:   fmt.Sprintf("Install failed: %s", err),
-			},
-			Version: 0,
+			Revision:  revision,
+			IsInstall: true,
+			Flags:     req.Flags,
+		}
+		valuesToRender, err = chartutil.ToRenderValuesCaps(req.Chart, vals, options, caps)
+		if err != nil {
+			return nil, nil, err
+		}
+		if mutations, err = s.mutateValues(req.Chart, options, valuesToRender); err != nil {
+			return nil, nil, err
+		}
+
+		hooks, manifestDoc, notesTxt, injections, err = s.renderResources(req.Chart, valuesToRender, caps.APIVersions, generated)
+		if err != nil {
+			// Return a release with partial data so that client can show
+			// debugging information.
+			rel := &release.Release{
+				Name:      name,
+				Namespace: req.Namespace,
+				Chart:     req.Chart,
+				Config:    req.Values,
+				Info: &release.Info{
+					FirstDeployed: ts,
+					LastDeployed:  ts,
+					Status:        &release.Status{Code: release.Status_UNKNOWN},
+					Description:   fmt.Sprintf("Install failed: %s", err),
+				},
+				Version: 0,
+			}
+			if manifestDoc != nil {
+				rel.Manifest = manifestDoc.String()
+			}
+			return rel, nil, err
 		}
-		if manifestDoc != nil {
-			rel.Manifest = manifestDoc.String()
+
+		// Catch names that are too long for Kubernetes before anything is
+		// installed, and shorten the release name if asked to.
+		problems := checkResourceNames(manifestDoc.String(), hooks)
+		if len(problems) == 0 {
+			break
+		}
+		short, err := releaseNameProblems(original, name, problems, req.TruncateName, attempt)
+		if err != nil {
+			return nil, nil, err
+		}
+		if name, err = s.uniqName(short, req.ReuseName, req.Chart); err != nil {
+			return nil, nil, err
+		}
+		s.Log("shortened release name %q to %q", original, name)
+		if req.Name != "" {
+			// performRelease looks up the release to replace by the name.
+			req.Name = name
 		}
-		return rel, err
+	}
+
+	computed, err := s.computedValues(valuesToRender)
+	if err != nil {
+		return nil, nil, err
 	}
 
 	// Store a release.
 	rel := &release.Release{
-		Name:      name,
-		Namespace: req.Namespace,
-		Chart:     req.Chart,
-		Config:    req.Values,
+		Name:            name,
+		Namespace:       req.Namespace,
+		Chart:           req.Chart,
+		Config:          req.Values,
+		ComputedValues:  computed,
+		GeneratedValues: generated,
+		Profile:         req.Profile,
+		AllowMissingProfile: req.AllowMissingProfile,
 		Info: &release.Info{
-			FirstDeployed: ts,
-			LastDeployed:  ts,
-			Status:        &release.Status{Code: release.Status_UNKNOWN},
-			Description:   "Initial install underway", // Will be overwritten.
+			FirstDeployed:       ts,
+			LastDeployed:        ts,
+			Status:              &release.Status{Code: release.Status_UNKNOWN},
+			Description:         "Initial install underway", // Will be overwritten.
+			Annotations:         req.Annotations,
+			ValuesMutations:     mutations,
+			ContainerInjections: injections,
+			Impersonation:       req.Impersonation,
+			Flags:               req.Flags,
 		},
 		Manifest: manifestDoc.String(),
 		Hooks:    hooks,
 		Version:  int32(revision),
+		Cluster:  req.Cluster,
+		System:   req.System,
 	}
 	if len(notesTxt) > 0 {
 		rel.Info.Status.Notes = notesTxt
 	}
 
-	err = validateManifest(s.env.KubeClient, req.Namespace, manifestDoc.Bytes())
-	return rel, err
+	err = validateManifest(kc.env.KubeClient, req.Namespace, manifestDoc.Bytes())
+	if err == nil {
+		err = s.setNamespaces(rel)
+	}
+	return rel, valuesToRender, err
 }
 
-// performRelease runs a release.
-func (s *ReleaseServer) performRelease(r *release.Release, req *services.InstallReleaseRequest) (*services.InstallReleaseResponse, error) {
+// performRelease runs a release. values are the values it was rendered with,
+// if known.
+func (s *ReleaseServer) performRelease(log logging.Logger, r *release.Release, values chartutil.Values, req *services.InstallReleaseRequest) (*services.InstallReleaseResponse, error) {
 	res := &services.InstallReleaseResponse{Release: r}
+	kc, err := s.actingCluster(r)
+	if err != nil {
+		return res, err
+	}
+	kc = s.withWaitLogs(log, kc, r.Name, req.WaitLogBytes)
+	defer s.waitLogs.end(r.Name)
+
+	if req.VerifyImages {
+		if err := s.verifyImages(r); err != nil {
+			log.Warnf("%s", err)
+			return res, err
+		}
+	}
+	if req.VerifyReferences {
+		if err := s.verifyReferences(r); err != nil {
+			log.Warnf("%s", err)
+			return res, err
+		}
+	}
+
+	skip := newHookSkipList(req.SkipHooks, req.SkipHookWeights)
+	runHooks := s.runHooks(log, "install", req.DisableHooks, req.EnableHooks)
 
 	if req.DryRun {
-		s.Log("Dry run for %s", r.Name)
+		log.Infof("Dry run for %s", r.Name)
 		res.Release.Info.Description = "Dry run complete"
+		if runHooks {
+			res.Hooks = previewHooks(r.Hooks, skip, hooks.PreInstall, hooks.PostInstall)
+			if !req.SkipHookLookup {
+				lookUpReplacedHooks(log, kc.env.KubeClient, r.Namespace, r.Hooks, res.Hooks)
+			}
+		}
+		if req.ServerDryRun {
+			return res, s.serverDryRun(r)
+		}
 		return res, nil
 	}
 
+	budget := newTimeoutBudget(req.TimeoutBudget, req.Timeout)
+
 	// pre-install hooks
-	if !req.DisableHooks {
-		if err := s.execHook(r.Hooks, r.Name, r.Namespace, hooks.PreInstall, req.Timeout); err != nil {
+	if runHooks {
+		if err := s.execHookWithin(log, kc.env.KubeClient, r, hooks.PreInstall, budget, skip); err != nil {
 			return res, err
 		}
 	}
 
+	timeout, err := budget.start("install of the resources")
+	if err != nil {
+		return res, err
+	}
+
 	switch h, err := s.env.Releases.History(req.Name); {
 	// if this is a replace operation, append to the release history
 	case req.ReuseName && err == nil && len(h) >= 1:
@@ -160,40 +310,59 @@ func (s *ReleaseServer) performRelease(r *release.Release, req *services.Install
 		// update new release with next revision number
 		// so as to append to the old release's history
 		r.Version = old.Version + 1
+		log = log.With("revision", r.Version)
 		updateReq := &services.UpdateReleaseRequest{
 			Wait:     req.Wait,
 			Recreate: false,
-			Timeout:  req.Timeout,
+			Timeout:  timeout,
 		}
-		if err := s.ReleaseModule.Update(old, r, updateReq, s.env); err != nil {
+		if err := kc.module.Update(old, r, updateReq, kc.env); err != nil {
+			err = budget.exhausted(err)
 			msg := fmt.Sprintf("Release replace %q failed: %s", r.Name, err)
-			s.Log("warning: %s", msg)
+			log.Warnf("%s", msg)
 			old.Info.Status.Code = release.Status_SUPERSEDED
 			r.Info.Status.Code = release.Status_FAILED
 			r.Info.Description = msg
+			recordResourceStatuses(r, err)
 			s.recordRelease(old, true)
 			s.recordRelease(r, false)
+			if req.OnFailure == onFailureRevert {
+				if rerr := s.revertInstall(log, kc, r, req); rerr != nil {
+					return res, fmt.Errorf("%s; reverting the install failed as well: %s", err, rerr)
+				}
+			}
 			return res, err
 		}
 
 	default:
 		// nothing to replace, create as normal
 		// regular manifests
-		if err := s.ReleaseModule.Create(r, req, s.env); err != nil {
+		createReq := *req
+		createReq.Timeout = timeout
+		if err := kc.module.Create(r, &createReq, kc.env); err != nil {
+			err = budget.exhausted(err)
 			msg := fmt.Sprintf("Release %q failed: %s", r.Name, err)
-			s.Log("warning: %s", msg)
+			log.Warnf("%s", msg)
 			r.Info.Status.Code = release.Status_FAILED
 			r.Info.Description = msg
+			recordResourceStatuses(r, err)
 			s.recordRelease(r, false)
+			if req.OnFailure == onFailureRevert {
+				if rerr := s.revertInstall(log, kc, r, req); rerr != nil {
+					return res, fmt.Errorf("release %s failed: %s; reverting the install failed as well: %s", r.Name, err, rerr)
+				}
+			}
 			return res, fmt.Errorf("release %s failed: %s", r.Name, err)
 		}
 	}
 
+	s.afterApply(log, r.Cluster, kc.env.KubeClient, r.Manifest)
+
 	// post-install hooks
-	if !req.DisableHooks {
-		if err := s.execHook(r.Hooks, r.Name, r.Namespace, hooks.PostInstall, req.Timeout); err != nil {
+	if runHooks {
+		if err := s.execHookWithin(log, kc.env.KubeClient, r, hooks.PostInstall, budget, skip); err != nil {
 			msg := fmt.Sprintf("Release %q failed post-install: %s", r.Name, err)
-			s.Log("warning: %s", msg)
+			log.Warnf("%s", msg)
 			r.Info.Status.Code = release.Status_FAILED
 			r.Info.Description = msg
 			s.recordRelease(r, false)
@@ -201,6 +370,10 @@ func (s *ReleaseServer) performRelease(r *release.Release, req *services.Install
 		}
 	}
 
+	if req.Wait || req.DeferNotes {
+		s.refreshNotes(log, r, nil, values, req.DeferNotes)
+	}
+
 	r.Info.Status.Code = release.Status_DEPLOYED
 	r.Info.Description = "Install complete"
 	// This is a tricky case. The release has been created, but the result
@@ -17,14 +17,21 @@ limitations under the License.
 package tiller
 
 import (
+	"errors"
 	"fmt"
+	"io/ioutil"
+	"reflect"
 	"strings"
 	"testing"
 
+	"github.com/golang/protobuf/ptypes/any"
+
+	"k8s.io/helm/pkg/chartutil"
 	"k8s.io/helm/pkg/helm"
 	"k8s.io/helm/pkg/proto/hapi/chart"
 	"k8s.io/helm/pkg/proto/hapi/release"
 	"k8s.io/helm/pkg/proto/hapi/services"
+	"k8s.io/helm/pkg/tiller/environment"
 	"k8s.io/helm/pkg/version"
 )
 
@@ -253,6 +260,36 @@ func TestInstallRelease_TillerVersion(t *testing.T) {
 	}
 }
 
+func TestInstallRelease_HelmVersion(t *testing.T) {
+	defer func(v string) { version.Version = v }(version.Version)
+	c := helm.NewContext()
+	rs := rsFixture()
+
+	tpl := `{{ if semverCompare ">=2.5.0" .Capabilities.HelmVersion.Version }}greeting: new{{ else }}greeting: old{{ end }}
+version: {{ .Capabilities.HelmVersion.Version }}`
+	for _, tt := range []struct {
+		version, expect string
+	}{
+		{"v2.5.1", "greeting: new\nversion: v2.5.1+unreleased"},
+		{"v2.4.0", "greeting: old\nversion: v2.4.0+unreleased"},
+	} {
+		version.Version = tt.version
+		res, err := rs.InstallRelease(c, &services.InstallReleaseRequest{
+			Namespace: "spaced",
+			Chart: &chart.Chart{
+				Metadata:  &chart.Metadata{Name: "hello"},
+				Templates: []*chart.Template{{Name: "templates/hello", Data: []byte(tpl)}},
+			},
+		})
+		if err != nil {
+			t.Fatalf("Failed install: %s", err)
+		}
+		if !strings.Contains(res.Release.Manifest, tt.expect) {
+			t.Errorf("Expected %q in the manifest of Helm %s, got %q", tt.expect, tt.version, res.Release.Manifest)
+		}
+	}
+}
+
 func TestInstallRelease_WrongTillerVersion(t *testing.T) {
 	version.Version = "2.2.0"
 	c := helm.NewContext()
@@ -384,6 +421,125 @@ func TestInstallRelease_DryRun(t *testing.T) {
 	}
 }
 
+// beforeHookCreationHook returns a hook template for event with the
+// before-hook-creation delete policy.
+func beforeHookCreationHook(name, event string) []byte {
+	return []byte(fmt.Sprintf(`apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: %s
+  annotations:
+    "helm.sh/hook": %s
+    "helm.sh/hook-delete-policy": before-hook-creation
+`, name, event))
+}
+
+func TestInstallRelease_DryRunHooks(t *testing.T) {
+	c := helm.NewContext()
+	rs := rsFixture()
+	kc := &driftKubeClient{
+		PrintingKubeClient: environment.PrintingKubeClient{Out: ioutil.Discard},
+		missing:            map[string]bool{"fresh": true},
+	}
+	rs.env.KubeClient = kc
+
+	req := &services.InstallReleaseRequest{
+		Chart: &chart.Chart{
+			Metadata: &chart.Metadata{Name: "hello"},
+			Templates: []*chart.Template{
+				{Name: "templates/hello", Data: []byte("hello: world")},
+				{Name: "templates/leftover", Data: beforeHookCreationHook("leftover", "pre-install")},
+				{Name: "templates/fresh", Data: beforeHookCreationHook("fresh", "post-install")},
+			},
+		},
+		DryRun: true,
+	}
+	res, err := rs.InstallRelease(c, req)
+	if err != nil {
+		t.Fatalf("Failed install: %s", err)
+	}
+	replaced := map[string]bool{}
+	for _, h := range res.Hooks {
+		replaced[h.Name] = h.ReplacesExisting
+	}
+	if expect := map[string]bool{"leftover": true, "fresh": false}; !reflect.DeepEqual(replaced, expect) {
+		t.Errorf("Expected replaced hooks %v, got %v", expect, replaced)
+	}
+
+	kc.compared = nil
+	req.SkipHookLookup = true
+	if res, err = rs.InstallRelease(c, req); err != nil {
+		t.Fatalf("Failed install: %s", err)
+	}
+	if len(res.Hooks) != 2 || len(kc.compared) != 0 {
+		t.Errorf("Expected 2 hooks and no lookups, got %v and %d lookups", res.Hooks, len(kc.compared))
+	}
+}
+
+func TestInstallRelease_ServerDryRun(t *testing.T) {
+	c := helm.NewContext()
+	rs := rsFixture()
+	defaulted := "---\nhello: world\nserviceAccountName: default\n"
+	rs.env.KubeClient = &serverDryRunKubeClient{manifest: defaulted}
+
+	req := &services.InstallReleaseRequest{
+		Chart:        chartStub(),
+		DryRun:       true,
+		ServerDryRun: true,
+	}
+	res, err := rs.InstallRelease(c, req)
+	if err != nil {
+		t.Fatalf("Failed install: %s", err)
+	}
+	if res.Release.Manifest != defaulted {
+		t.Errorf("expected manifest from the server dry run, got %q", res.Release.Manifest)
+	}
+	if res.Release.Info.Description != "Server dry run complete" {
+		t.Errorf("unexpected description: %s", res.Release.Info.Description)
+	}
+	if _, err := rs.env.Releases.Get(res.Release.Name, res.Release.Version); err == nil {
+		t.Errorf("Expected no stored release.")
+	}
+}
+
+func TestInstallRelease_ServerDryRunFailure(t *testing.T) {
+	c := helm.NewContext()
+	rs := rsFixture()
+	rs.env.KubeClient = &serverDryRunKubeClient{err: errors.New("server-side dry-run requires Kubernetes 1.13 or later")}
+
+	req := &services.InstallReleaseRequest{
+		Chart:        chartStub(),
+		DryRun:       true,
+		ServerDryRun: true,
+	}
+	_, err := rs.InstallRelease(c, req)
+	if err == nil || !strings.Contains(err.Error(), "requires Kubernetes 1.13") {
+		t.Errorf("expected server dry run error, got %v", err)
+	}
+}
+
+func TestInstallRelease_SkipHookWeights(t *testing.T) {
+	c := helm.NewContext()
+	rs := rsFixture()
+
+	req := &services.InstallReleaseRequest{
+		Chart:           chartStub(),
+		SkipHookWeights: []int32{0, 42},
+	}
+	res, err := rs.InstallRelease(c, req)
+	if err != nil {
+		t.Fatalf("Failed install: %s", err)
+	}
+
+	h := res.Release.Hooks[0]
+	if h.LastRun != nil {
+		t.Errorf("Expected the hook with weight 0 to be skipped. Got %v", h.LastRun)
+	}
+	if h.LastSkipped == nil {
+		t.Error("Expected the skipped hook to be recorded.")
+	}
+}
+
 func TestInstallRelease_NoHooks(t *testing.T) {
 	c := helm.NewContext()
 	rs := rsFixture()
@@ -452,3 +608,349 @@ func TestInstallRelease_ReuseName(t *testing.T) {
 		t.Errorf("Release status is %q", getres.Info.Status.Code)
 	}
 }
+
+func TestInstallRelease_ReplaceFailed(t *testing.T) {
+	c := helm.NewContext()
+	rs := rsFixture()
+	rel := namedReleaseStub("half-installed", release.Status_FAILED)
+	rs.env.Releases.Create(rel)
+
+	res, err := rs.InstallRelease(c, &services.InstallReleaseRequest{
+		Chart:     chartStub(),
+		ReuseName: true,
+		Name:      rel.Name,
+	})
+	if err != nil {
+		t.Fatalf("Failed install: %s", err)
+	}
+	if res.Release.Version != 2 || res.Release.Info.Status.Code != release.Status_DEPLOYED {
+		t.Errorf("Expected a deployed revision 2, got %s (v%d)", res.Release.Info.Status.Code, res.Release.Version)
+	}
+	if old, _ := rs.env.Releases.Get(rel.Name, 1); old.Info.Status.Code != release.Status_SUPERSEDED {
+		t.Errorf("Expected the failed revision to be superseded, got %s", old.Info.Status.Code)
+	}
+}
+
+func TestInstallRelease_ReplaceDeployed(t *testing.T) {
+	c := helm.NewContext()
+	rs := rsFixture()
+	rel := releaseStub()
+	rs.env.Releases.Create(rel)
+
+	_, err := rs.InstallRelease(c, &services.InstallReleaseRequest{
+		Chart:     chartStub(),
+		ReuseName: true,
+		Name:      rel.Name,
+	})
+	expect := "cannot re-use a name that is still in use: angry-panda (v1) is DEPLOYED, and only FAILED or DELETED releases can be replaced"
+	if err == nil || err.Error() != expect {
+		t.Errorf("Expected %q, got %v", expect, err)
+	}
+	if last, _ := rs.env.Releases.Last(rel.Name); last.Version != 1 || last.Info.Status.Code != release.Status_DEPLOYED {
+		t.Errorf("Expected the deployed release to be left alone, got %s (v%d)", last.Info.Status.Code, last.Version)
+	}
+}
+
+var manifestWithReleaseContext = `apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: release-context
+data:
+  context: "install={{ .Release.IsInstall }} upgrade={{ .Release.IsUpgrade }} revision={{ .Release.Revision }}"
+`
+
+func releaseContextChart() *chart.Chart {
+	return &chart.Chart{
+		Metadata: &chart.Metadata{Name: "hello"},
+		Templates: []*chart.Template{
+			{Name: "templates/configmap", Data: []byte(manifestWithReleaseContext)},
+		},
+	}
+}
+
+func expectReleaseContext(t *testing.T, op, manifest, expect string) {
+	if !strings.Contains(manifest, expect) {
+		t.Errorf("%s: expected the manifest to be rendered with %q, got %s", op, expect, manifest)
+	}
+}
+
+func TestInstallRelease_ReleaseContext(t *testing.T) {
+	c := helm.NewContext()
+	rs := rsFixture()
+
+	res, err := rs.InstallRelease(c, &services.InstallReleaseRequest{
+		Name:      "context",
+		Namespace: "spaced",
+		Chart:     releaseContextChart(),
+	})
+	if err != nil {
+		t.Fatalf("Failed install: %s", err)
+	}
+	expectReleaseContext(t, "install", res.Release.Manifest, "install=true upgrade=false revision=1")
+
+	// Replacing a deleted release appends to its history, so templates see
+	// the next revision.
+	rel := namedReleaseStub("deleted", release.Status_DELETED)
+	rel.Version = 3
+	rs.env.Releases.Create(rel)
+	res, err = rs.InstallRelease(c, &services.InstallReleaseRequest{
+		Name:      "deleted",
+		Namespace: "spaced",
+		ReuseName: true,
+		Chart:     releaseContextChart(),
+	})
+	if err != nil {
+		t.Fatalf("Failed replace: %s", err)
+	}
+	if res.Release.Version != 4 {
+		t.Errorf("Expected revision 4, got %d", res.Release.Version)
+	}
+	expectReleaseContext(t, "replace", res.Release.Manifest, "install=true upgrade=false revision=4")
+}
+
+func computedValuesChart() *chart.Chart {
+	return &chart.Chart{
+		Metadata: &chart.Metadata{Name: "hello"},
+		Templates: []*chart.Template{
+			{Name: "templates/hello", Data: []byte("hello: {{ .Values.name }}")},
+		},
+		Values: &chart.Config{Raw: "name: default\nreplicas: 1\n"},
+	}
+}
+
+func TestInstallRelease_ComputedValues(t *testing.T) {
+	c := helm.NewContext()
+	rs := rsFixture()
+
+	req := &services.InstallReleaseRequest{
+		Name:      "plain",
+		Namespace: "spaced",
+		Chart:     computedValuesChart(),
+		Values:    &chart.Config{Raw: "name: custom\n"},
+	}
+	res, err := rs.InstallRelease(c, req)
+	if err != nil {
+		t.Fatalf("Failed install: %s", err)
+	}
+	if res.Release.ComputedValues != nil {
+		t.Errorf("Expected no computed values unless they are stored, got %q", res.Release.ComputedValues.Raw)
+	}
+
+	rs.StoreComputedValues(true)
+	req.Name = "snapshot"
+	if _, err := rs.InstallRelease(c, req); err != nil {
+		t.Fatalf("Failed install: %s", err)
+	}
+	rel, err := rs.env.Releases.Get("snapshot", 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expect := "name: custom\nreplicas: 1\n"
+	if rel.ComputedValues == nil || rel.ComputedValues.Raw != expect {
+		t.Errorf("Expected stored computed values %q, got %v", expect, rel.ComputedValues)
+	}
+	if rel.Config.Raw != "name: custom\n" {
+		t.Errorf("Expected the supplied values to be stored unchanged, got %q", rel.Config.Raw)
+	}
+}
+
+func profileChart() *chart.Chart {
+	c := computedValuesChart()
+	c.Files = []*any.Any{{TypeUrl: "values-prod.yaml", Value: []byte("name: prod\nreplicas: 3\n")}}
+	return c
+}
+
+func TestInstallRelease_Profile(t *testing.T) {
+	c := helm.NewContext()
+	rs := rsFixture()
+
+	req := &services.InstallReleaseRequest{
+		Name:      "profiled",
+		Namespace: "spaced",
+		Chart:     profileChart(),
+		Values:    &chart.Config{Raw: "replicas: 5\n"},
+		Profile:   "prod",
+	}
+	res, err := rs.InstallRelease(c, req)
+	if err != nil {
+		t.Fatalf("Failed install: %s", err)
+	}
+	if !strings.Contains(res.Release.Manifest, "hello: prod") {
+		t.Errorf("Expected the profile's values to be rendered, got %q", res.Release.Manifest)
+	}
+	if expect := "replicas: 5\n"; res.Release.Config.Raw != expect {
+		t.Errorf("Expected only the supplied values %q to be stored, got %q", expect, res.Release.Config.Raw)
+	}
+	if res.Release.Profile != "prod" {
+		t.Errorf("Expected the profile to be stored, got %q", res.Release.Profile)
+	}
+}
+
+func TestInstallRelease_MissingProfile(t *testing.T) {
+	c := helm.NewContext()
+	rs := rsFixture()
+
+	req := &services.InstallReleaseRequest{
+		Name:      "profiled",
+		Namespace: "spaced",
+		Chart:     profileChart(),
+		Values:    &chart.Config{},
+		Profile:   "staging",
+	}
+	_, err := rs.InstallRelease(c, req)
+	if err == nil || !strings.Contains(err.Error(), `no values profile "staging"`) {
+		t.Fatalf("Expected an error for a missing profile, got %v", err)
+	}
+	if _, err := rs.env.Releases.Get("profiled", 1); err == nil {
+		t.Error("Expected no release to be recorded")
+	}
+
+	req.AllowMissingProfile = true
+	res, err := rs.InstallRelease(c, req)
+	if err != nil {
+		t.Fatalf("Failed install: %s", err)
+	}
+	if !strings.Contains(res.Release.Manifest, "hello: default") {
+		t.Errorf("Expected the chart's defaults to be rendered, got %q", res.Release.Manifest)
+	}
+}
+
+func TestInstallRelease_ValuesLimits(t *testing.T) {
+	c := helm.NewContext()
+	rs := rsFixture()
+	rs.SetValuesLimits(chartutil.ValuesLimits{MaxDepth: 2, MaxSize: 1024})
+
+	req := &services.InstallReleaseRequest{
+		Name:   "limited",
+		Chart:  chartStub(),
+		Values: &chart.Config{Raw: "a:\n  b:\n    c: 1\n"},
+	}
+	_, err := rs.InstallRelease(c, req)
+	if err == nil || !strings.Contains(err.Error(), "supplied values nest more than 2 levels deep at a.b") {
+		t.Fatalf("Expected an error for values nested too deeply, got %v", err)
+	}
+
+	req.Values = &chart.Config{Raw: "a: " + strings.Repeat("x", 1024) + "\n"}
+	if _, err = rs.InstallRelease(c, req); err == nil || !strings.Contains(err.Error(), "more than the limit of 1024 bytes") {
+		t.Fatalf("Expected an error for values too large, got %v", err)
+	}
+	if _, err := rs.env.Releases.Get("limited", 1); err == nil {
+		t.Error("Expected no release to be recorded")
+	}
+
+	req.Values = &chart.Config{Raw: "a:\n  b: 1\n"}
+	if _, err := rs.InstallRelease(c, req); err != nil {
+		t.Errorf("Failed install: %s", err)
+	}
+}
+
+func TestInstallRelease_StrictValues(t *testing.T) {
+	c := helm.NewContext()
+	rs := rsFixture()
+
+	ch := chartStub()
+	ch.Values = &chart.Config{Raw: "image:\n  tag: v1\n"}
+	req := &services.InstallReleaseRequest{
+		Name:   "strict",
+		Chart:  ch,
+		Values: &chart.Config{Raw: "image:\n  tga: v2\nreplicas: 3\n"},
+	}
+	if _, err := rs.InstallRelease(c, req); err != nil {
+		t.Fatalf("Expected unknown values to be ignored by default, got %s", err)
+	}
+
+	req.Name = "stricter"
+	req.StrictValues = true
+	_, err := rs.InstallRelease(c, req)
+	if err == nil || !strings.Contains(err.Error(), "values not known to chart hello or its subcharts: image.tga, replicas") {
+		t.Fatalf("Expected the unknown values to be reported, got %v", err)
+	}
+	if _, err := rs.env.Releases.Get("stricter", 1); err == nil {
+		t.Error("Expected no release to be recorded")
+	}
+}
+
+func generatedValuesChart() *chart.Chart {
+	return &chart.Chart{
+		Metadata: &chart.Metadata{Name: "hello"},
+		Templates: []*chart.Template{
+			{Name: "templates/secret", Data: []byte(`password: {{ randAlphaNumOnce "password" 12 }}`)},
+		},
+	}
+}
+
+func TestInstallRelease_GeneratedValues(t *testing.T) {
+	c := helm.NewContext()
+	rs := rsFixture()
+
+	res, err := rs.InstallRelease(c, &services.InstallReleaseRequest{
+		Name:      "generated",
+		Namespace: "spaced",
+		Chart:     generatedValuesChart(),
+	})
+	if err != nil {
+		t.Fatalf("Failed install: %s", err)
+	}
+	rel, err := rs.env.Releases.Get("generated", 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	password := rel.GeneratedValues["password"]
+	if len(password) != 12 {
+		t.Fatalf("Expected a generated password to be stored, got %v", rel.GeneratedValues)
+	}
+	if !strings.Contains(res.Release.Manifest, "password: "+password) {
+		t.Errorf("Expected the manifest to use the stored password, got %q", res.Release.Manifest)
+	}
+
+	// A dry run generates values too, but nothing is stored.
+	res, err = rs.InstallRelease(c, &services.InstallReleaseRequest{
+		Name:      "dry",
+		Namespace: "spaced",
+		Chart:     generatedValuesChart(),
+		DryRun:    true,
+	})
+	if err != nil {
+		t.Fatalf("Failed dry-run install: %s", err)
+	}
+	if len(res.Release.GeneratedValues["password"]) != 12 {
+		t.Errorf("Expected a dry run to generate a password, got %v", res.Release.GeneratedValues)
+	}
+	if _, err := rs.env.Releases.Get("dry", 1); err == nil {
+		t.Error("Expected a dry run not to store the release")
+	}
+}
+
+func TestInstallRelease_System(t *testing.T) {
+	c := helm.NewContext()
+	rs := rsFixture()
+
+	res, err := rs.InstallRelease(c, &services.InstallReleaseRequest{
+		Name:      "kube-dns",
+		Namespace: "kube-system",
+		Chart:     chartStub(),
+		System:    true,
+	})
+	if err != nil {
+		t.Fatalf("Failed install: %s", err)
+	}
+	if !res.Release.System {
+		t.Error("Expected the release to be marked as a system release")
+	}
+
+	// Later revisions keep the mark.
+	up, err := rs.UpdateRelease(c, &services.UpdateReleaseRequest{Name: "kube-dns", Chart: chartStub()})
+	if err != nil {
+		t.Fatalf("Failed upgrade: %s", err)
+	}
+	if !up.Release.System {
+		t.Error("Expected the upgrade to keep the system mark")
+	}
+	rb, err := rs.RollbackRelease(c, &services.RollbackReleaseRequest{Name: "kube-dns"})
+	if err != nil {
+		t.Fatalf("Failed rollback: %s", err)
+	}
+	if !rb.Release.System {
+		t.Error("Expected the rollback to keep the system mark")
+	}
+}
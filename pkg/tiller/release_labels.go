@@ -0,0 +1,112 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tiller
+
+import (
+	"errors"
+	"fmt"
+
+	ctx "golang.org/x/net/context"
+
+	"k8s.io/helm/pkg/proto/hapi/release"
+	"k8s.io/helm/pkg/proto/hapi/services"
+	relutil "k8s.io/helm/pkg/releaseutil"
+)
+
+// GetReleaseLabels returns the labels and annotations that tools can select
+// the resources of a revision of a release by. Tiller applies resources
+// exactly as the chart renders them, without labels or annotations of its
+// own, so these are what the chart's templates put on every resource of the
+// revision.
+//
+// Given a chart, it previews them for a release that does not exist yet
+// instead: the chart is rendered as an install of the release would render
+// it, values mutators and container injection included, and nothing is
+// installed.
+func (s *ReleaseServer) GetReleaseLabels(c ctx.Context, req *services.GetReleaseLabelsRequest) (*services.GetReleaseLabelsResponse, error) {
+	if !ValidName.MatchString(req.Name) {
+		return nil, errMissingRelease
+	}
+	if req.Version < 0 {
+		return nil, errInvalidRevision
+	}
+
+	var rel *release.Release
+	var err error
+	switch {
+	case req.Chart != nil:
+		if req.Version != 0 {
+			return nil, errors.New("a revision cannot be previewed with a chart")
+		}
+		rel, _, err = s.prepareRelease(&services.InstallReleaseRequest{
+			Name:      req.Name,
+			Namespace: req.Namespace,
+			Chart:     req.Chart,
+			Values:    req.Values,
+			DryRun:    true,
+		})
+		if err != nil {
+			return nil, err
+		}
+	case req.Version == 0:
+		if rel, err = s.env.Releases.Last(req.Name); err != nil {
+			return nil, fmt.Errorf("getting release %q: %s", req.Name, err)
+		}
+	default:
+		if rel, err = s.env.Releases.Get(req.Name, req.Version); err != nil {
+			return nil, fmt.Errorf("getting release '%s' (v%d): %s", req.Name, req.Version, err)
+		}
+	}
+
+	res := &services.GetReleaseLabelsResponse{
+		Name:    rel.Name,
+		Version: rel.Version,
+	}
+	for _, content := range relutil.SplitManifests(rel.Manifest) {
+		head := manifestHead(content)
+		if head == nil {
+			continue
+		}
+		if res.Resources == 0 {
+			res.CommonLabels = copyStrings(head.Metadata.Labels)
+			res.CommonAnnotations = copyStrings(head.Metadata.Annotations)
+		} else {
+			intersectStrings(res.CommonLabels, head.Metadata.Labels)
+			intersectStrings(res.CommonAnnotations, head.Metadata.Annotations)
+		}
+		res.Resources++
+	}
+	return res, nil
+}
+
+func copyStrings(m map[string]string) map[string]string {
+	c := make(map[string]string, len(m))
+	for k, v := range m {
+		c[k] = v
+	}
+	return c
+}
+
+// intersectStrings removes the entries from common that other does not have
+// with the same value.
+func intersectStrings(common, other map[string]string) {
+	for k, v := range common {
+		if w, ok := other[k]; !ok || w != v {
+			delete(common, k)
+		}
+	}
+}
@@ -0,0 +1,104 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tiller
+
+import (
+	"reflect"
+	"testing"
+
+	"k8s.io/helm/pkg/helm"
+	"k8s.io/helm/pkg/proto/hapi/chart"
+	"k8s.io/helm/pkg/proto/hapi/services"
+)
+
+var labeledManifests = []*chart.Template{
+	{Name: "templates/a", Data: []byte(`apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: a
+  labels:
+    heritage: Tiller
+    release: "{{ .Release.Name }}"
+    component: a
+  annotations:
+    team: storage
+`)},
+	{Name: "templates/b", Data: []byte(`apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: b
+  labels:
+    heritage: Tiller
+    release: "{{ .Release.Name }}"
+    component: b
+`)},
+}
+
+func TestGetReleaseLabels(t *testing.T) {
+	c := helm.NewContext()
+	rs := rsFixture()
+	req := &services.InstallReleaseRequest{
+		Name:  "labeled",
+		Chart: &chart.Chart{Metadata: &chart.Metadata{Name: "hello"}, Templates: labeledManifests},
+	}
+	if _, err := rs.InstallRelease(c, req); err != nil {
+		t.Fatalf("Failed install: %s", err)
+	}
+
+	res, err := rs.GetReleaseLabels(c, &services.GetReleaseLabelsRequest{Name: "labeled"})
+	if err != nil {
+		t.Fatalf("Failed to get labels: %s", err)
+	}
+	if res.Version != 1 || res.Resources != 2 {
+		t.Errorf("Expected 2 resources of revision 1, got %d of revision %d", res.Resources, res.Version)
+	}
+	expect := map[string]string{"heritage": "Tiller", "release": "labeled"}
+	if !reflect.DeepEqual(res.CommonLabels, expect) {
+		t.Errorf("Expected common labels %v, got %v", expect, res.CommonLabels)
+	}
+	if len(res.CommonAnnotations) != 0 {
+		t.Errorf("Expected no common annotations, got %v", res.CommonAnnotations)
+	}
+
+	if _, err := rs.GetReleaseLabels(c, &services.GetReleaseLabelsRequest{Name: "labeled", Version: 2}); err == nil {
+		t.Error("Expected an error for a missing revision")
+	}
+}
+
+func TestGetReleaseLabelsPreview(t *testing.T) {
+	c := helm.NewContext()
+	rs := rsFixture()
+	ch := &chart.Chart{Metadata: &chart.Metadata{Name: "hello"}, Templates: labeledManifests}
+
+	req := &services.GetReleaseLabelsRequest{Name: "preview", Namespace: "team-a", Chart: ch}
+	res, err := rs.GetReleaseLabels(c, req)
+	if err != nil {
+		t.Fatalf("Failed to preview labels: %s", err)
+	}
+	expect := map[string]string{"heritage": "Tiller", "release": "preview"}
+	if res.Version != 1 || res.Resources != 2 || !reflect.DeepEqual(res.CommonLabels, expect) {
+		t.Errorf("Expected common labels %v of 2 resources of revision 1, got %v of %d of revision %d", expect, res.CommonLabels, res.Resources, res.Version)
+	}
+	if _, err := rs.env.Releases.Last("preview"); err == nil {
+		t.Error("Expected the preview not to install the release")
+	}
+
+	req.Version = 1
+	if _, err := rs.GetReleaseLabels(c, req); err == nil {
+		t.Error("Expected an error previewing a revision")
+	}
+}
@@ -26,23 +26,23 @@ import (
 
 // ListReleases lists the releases found by the server.
 func (s *ReleaseServer) ListReleases(req *services.ListReleasesRequest, stream services.ReleaseService_ListReleasesServer) error {
+	if err := validateOutputFormat(req.OutputFormat); err != nil {
+		return err
+	}
 	if len(req.StatusCodes) == 0 {
 		req.StatusCodes = []release.Status_Code{release.Status_DEPLOYED}
 	}
 
-	//rels, err := s.env.Releases.ListDeployed()
-	rels, err := s.env.Releases.ListFilterAll(func(r *release.Release) bool {
-		for _, sc := range req.StatusCodes {
-			if sc == r.Info.Status.Code {
-				return true
-			}
-		}
-		return false
-	})
+	// Releases of all namespaces are listed unless a namespace is given.
+	rels, err := s.env.Releases.ListByStatus(req.StatusCodes...)
 	if err != nil {
 		return err
 	}
 
+	if !req.IncludeSystem {
+		rels = filterSystem(rels)
+	}
+
 	if req.Namespace != "" {
 		rels, err = filterByNamespace(req.Namespace, rels)
 		if err != nil {
@@ -107,11 +107,16 @@ func (s *ReleaseServer) ListReleases(req *services.ListReleasesRequest, stream s
 		l = int64(len(rels))
 	}
 
+	rendered, err := renderList(req.OutputFormat, rels)
+	if err != nil {
+		return err
+	}
 	res := &services.ListReleasesResponse{
 		Next:     next,
 		Count:    l,
 		Total:    total,
 		Releases: rels,
+		Rendered: rendered,
 	}
 	return stream.Send(res)
 }
@@ -126,6 +131,17 @@ func filterByNamespace(namespace string, rels []*release.Release) ([]*release.Re
 	return matches, nil
 }
 
+// filterSystem leaves out the releases marked as system releases.
+func filterSystem(rels []*release.Release) []*release.Release {
+	matches := []*release.Release{}
+	for _, r := range rels {
+		if !r.System {
+			matches = append(matches, r)
+		}
+	}
+	return matches
+}
+
 func filterReleases(filter string, rels []*release.Release) ([]*release.Release, error) {
 	preg, err := regexp.Compile(filter)
 	if err != nil {
@@ -231,3 +231,74 @@ func TestReleasesNamespace(t *testing.T) {
 		t.Errorf("Expected 2 releases, got %d", len(mrs.val.Releases))
 	}
 }
+
+func TestListReleasesAllNamespaces(t *testing.T) {
+	rs := rsFixture()
+
+	namespaces := map[string]string{
+		"axon":     "default",
+		"dendrite": "test123",
+		"neuron":   "cerebellum",
+	}
+	for name, ns := range namespaces {
+		rel := namedReleaseStub(name, release.Status_DEPLOYED)
+		rel.Namespace = ns
+		if err := rs.env.Releases.Create(rel); err != nil {
+			t.Fatalf("Could not store mock release: %s", err)
+		}
+	}
+	deleted := namedReleaseStub("synapse", release.Status_DELETED)
+	deleted.Namespace = "default"
+	if err := rs.env.Releases.Create(deleted); err != nil {
+		t.Fatalf("Could not store mock release: %s", err)
+	}
+
+	mrs := &mockListServer{}
+	if err := rs.ListReleases(&services.ListReleasesRequest{Limit: 64}, mrs); err != nil {
+		t.Fatalf("Failed listing: %s", err)
+	}
+	if len(mrs.val.Releases) != len(namespaces) {
+		t.Fatalf("Expected %d releases, got %d", len(namespaces), len(mrs.val.Releases))
+	}
+	for _, r := range mrs.val.Releases {
+		if r.Namespace != namespaces[r.Name] {
+			t.Errorf("Expected release %s in namespace %q, got %q", r.Name, namespaces[r.Name], r.Namespace)
+		}
+	}
+
+	// The name filter applies across namespaces.
+	mrs = &mockListServer{}
+	if err := rs.ListReleases(&services.ListReleasesRequest{Limit: 64, Filter: "^(axon|neuron)$"}, mrs); err != nil {
+		t.Fatalf("Failed listing: %s", err)
+	}
+	if len(mrs.val.Releases) != 2 {
+		t.Errorf("Expected 2 releases, got %d", len(mrs.val.Releases))
+	}
+}
+
+func TestListReleasesSystem(t *testing.T) {
+	rs := rsFixture()
+	for name, system := range map[string]bool{"axon": false, "kube-dns": true, "ingress": true} {
+		rel := namedReleaseStub(name, release.Status_DEPLOYED)
+		rel.System = system
+		if err := rs.env.Releases.Create(rel); err != nil {
+			t.Fatalf("Could not store mock release: %s", err)
+		}
+	}
+
+	mrs := &mockListServer{}
+	if err := rs.ListReleases(&services.ListReleasesRequest{Limit: 64}, mrs); err != nil {
+		t.Fatalf("Failed listing: %s", err)
+	}
+	if len(mrs.val.Releases) != 1 || mrs.val.Releases[0].Name != "axon" || mrs.val.Total != 1 {
+		t.Errorf("Expected only axon, got %v", mrs.val.Releases)
+	}
+
+	mrs = &mockListServer{}
+	if err := rs.ListReleases(&services.ListReleasesRequest{Limit: 64, IncludeSystem: true}, mrs); err != nil {
+		t.Fatalf("Failed listing: %s", err)
+	}
+	if len(mrs.val.Releases) != 3 {
+		t.Errorf("Expected the system releases as well, got %d releases", len(mrs.val.Releases))
+	}
+}
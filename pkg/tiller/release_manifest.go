@@ -0,0 +1,33 @@
+/*
+Copyright 2017 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tiller
+
+import (
+	ctx "golang.org/x/net/context"
+
+	"k8s.io/helm/pkg/proto/hapi/services"
+)
+
+// GetManifest gets the manifest of the given release, without the rest of
+// the stored release. If no version is given, the deployed version is used.
+func (s *ReleaseServer) GetManifest(c ctx.Context, req *services.GetManifestRequest) (*services.GetManifestResponse, error) {
+	rel, err := s.releaseRevision(req.Name, req.Version)
+	if err != nil {
+		return nil, err
+	}
+	return &services.GetManifestResponse{Version: rel.Version, Manifest: rel.Manifest}, nil
+}
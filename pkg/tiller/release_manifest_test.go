@@ -0,0 +1,77 @@
+/*
+Copyright 2017 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tiller
+
+import (
+	"testing"
+
+	"k8s.io/helm/pkg/helm"
+	"k8s.io/helm/pkg/proto/hapi/release"
+	"k8s.io/helm/pkg/proto/hapi/services"
+)
+
+func TestGetManifest(t *testing.T) {
+	c := helm.NewContext()
+	rs := rsFixture()
+	rel := releaseStub()
+	rel.Manifest = "kind: ConfigMap\nmetadata:\n  name: v1\n"
+	rel.Info.Status.Code = release.Status_SUPERSEDED
+	upgraded := upgradeReleaseVersion(rel)
+	upgraded.Manifest = "kind: ConfigMap\nmetadata:\n  name: v2\n"
+	rs.env.Releases.Create(rel)
+	rs.env.Releases.Create(upgraded)
+
+	res, err := rs.GetManifest(c, &services.GetManifestRequest{Name: rel.Name})
+	if err != nil {
+		t.Fatalf("Error getting manifest: %s", err)
+	}
+	if res.Version != upgraded.Version || res.Manifest != upgraded.Manifest {
+		t.Errorf("Expected deployed manifest of revision %d, got revision %d:\n%s", upgraded.Version, res.Version, res.Manifest)
+	}
+
+	res, err = rs.GetManifest(c, &services.GetManifestRequest{Name: rel.Name, Version: 1})
+	if err != nil {
+		t.Fatalf("Error getting manifest: %s", err)
+	}
+	if res.Version != 1 || res.Manifest != rel.Manifest {
+		t.Errorf("Expected manifest of revision 1, got revision %d:\n%s", res.Version, res.Manifest)
+	}
+}
+
+func TestGetManifest_Errors(t *testing.T) {
+	c := helm.NewContext()
+	rs := rsFixture()
+	rel := releaseStub()
+	rel.Info.Status.Code = release.Status_FAILED
+	rs.env.Releases.Create(rel)
+
+	tests := []struct {
+		req    *services.GetManifestRequest
+		expect string
+	}{
+		{&services.GetManifestRequest{Name: "no-such-release"}, `release: "no-such-release" not found`},
+		{&services.GetManifestRequest{Name: rel.Name, Version: 7}, `release: "angry-panda" has no revision 7`},
+		{&services.GetManifestRequest{Name: rel.Name}, `release: "angry-panda" has no deployed revision`},
+		{&services.GetManifestRequest{Name: ""}, errMissingRelease.Error()},
+	}
+	for _, tt := range tests {
+		_, err := rs.GetManifest(c, tt.req)
+		if err == nil || err.Error() != tt.expect {
+			t.Errorf("Expected error %q, got %v", tt.expect, err)
+		}
+	}
+}
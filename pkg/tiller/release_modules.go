@@ -59,14 +59,30 @@ func (m *LocalReleaseModule) Create(r *release.Release, req *services.InstallRel
 func (m *LocalReleaseModule) Update(current, target *release.Release, req *services.UpdateReleaseRequest, env *environment.Environment) error {
 	c := bytes.NewBufferString(current.Manifest)
 	t := bytes.NewBufferString(target.Manifest)
-	return env.KubeClient.Update(target.Namespace, c, t, req.Force, req.Recreate, req.Timeout, req.Wait)
+	return env.KubeClient.UpdateWithOptions(target.Namespace, c, t, kube.UpdateOptions{
+		Force:                    req.Force,
+		Recreate:                 req.Recreate,
+		Timeout:                  req.Timeout,
+		Wait:                     req.Wait,
+		GracePeriod:              req.GracePeriod,
+		PropagationPolicy:        req.PropagationPolicy,
+		RecreateOnSelectorChange: req.RecreateOnSelectorChange,
+	})
 }
 
 // Rollback performs a rollback from current to target release
 func (m *LocalReleaseModule) Rollback(current, target *release.Release, req *services.RollbackReleaseRequest, env *environment.Environment) error {
 	c := bytes.NewBufferString(current.Manifest)
 	t := bytes.NewBufferString(target.Manifest)
-	return env.KubeClient.Update(target.Namespace, c, t, req.Force, req.Recreate, req.Timeout, req.Wait)
+	return env.KubeClient.UpdateWithOptions(target.Namespace, c, t, kube.UpdateOptions{
+		Force:                    req.Force,
+		Recreate:                 req.Recreate,
+		Timeout:                  req.Timeout,
+		Wait:                     req.Wait,
+		GracePeriod:              req.GracePeriod,
+		PropagationPolicy:        req.PropagationPolicy,
+		RecreateOnSelectorChange: req.RecreateOnSelectorChange,
+	})
 }
 
 // Status returns kubectl-like formatted status of release objects
@@ -80,7 +96,7 @@ func (m *LocalReleaseModule) Delete(rel *release.Release, req *services.Uninstal
 	if err != nil {
 		return rel.Manifest, []error{fmt.Errorf("Could not get apiVersions from Kubernetes: %v", err)}
 	}
-	return DeleteRelease(rel, vs, env.KubeClient)
+	return DeleteRelease(rel, vs, env.KubeClient, req.PropagationPolicy, req.Timeout, req.Wait)
 }
 
 // RemoteReleaseModule is a ReleaseModule which calls Rudder service to operate on a release
@@ -96,12 +112,15 @@ func (m *RemoteReleaseModule) Create(r *release.Release, req *services.InstallRe
 // Update calls rudder.UpgradeRelease
 func (m *RemoteReleaseModule) Update(current, target *release.Release, req *services.UpdateReleaseRequest, env *environment.Environment) error {
 	upgrade := &rudderAPI.UpgradeReleaseRequest{
-		Current:  current,
-		Target:   target,
-		Recreate: req.Recreate,
-		Timeout:  req.Timeout,
-		Wait:     req.Wait,
-		Force:    req.Force,
+		Current:                  current,
+		Target:                   target,
+		Recreate:                 req.Recreate,
+		Timeout:                  req.Timeout,
+		Wait:                     req.Wait,
+		Force:                    req.Force,
+		GracePeriod:              req.GracePeriod,
+		PropagationPolicy:        req.PropagationPolicy,
+		RecreateOnSelectorChange: req.RecreateOnSelectorChange,
 	}
 	_, err := rudder.UpgradeRelease(upgrade)
 	return err
@@ -110,11 +129,14 @@ func (m *RemoteReleaseModule) Update(current, target *release.Release, req *serv
 // Rollback calls rudder.Rollback
 func (m *RemoteReleaseModule) Rollback(current, target *release.Release, req *services.RollbackReleaseRequest, env *environment.Environment) error {
 	rollback := &rudderAPI.RollbackReleaseRequest{
-		Current:  current,
-		Target:   target,
-		Recreate: req.Recreate,
-		Timeout:  req.Timeout,
-		Wait:     req.Wait,
+		Current:                  current,
+		Target:                   target,
+		Recreate:                 req.Recreate,
+		Timeout:                  req.Timeout,
+		Wait:                     req.Wait,
+		GracePeriod:              req.GracePeriod,
+		PropagationPolicy:        req.PropagationPolicy,
+		RecreateOnSelectorChange: req.RecreateOnSelectorChange,
 	}
 	_, err := rudder.RollbackRelease(rollback)
 	return err
@@ -129,7 +151,12 @@ func (m *RemoteReleaseModule) Status(r *release.Release, req *services.GetReleas
 
 // Delete calls rudder.DeleteRelease
 func (m *RemoteReleaseModule) Delete(r *release.Release, req *services.UninstallReleaseRequest, env *environment.Environment) (string, []error) {
-	deleteRequest := &rudderAPI.DeleteReleaseRequest{Release: r}
+	deleteRequest := &rudderAPI.DeleteReleaseRequest{
+		Release:           r,
+		Timeout:           req.Timeout,
+		Wait:              req.Wait,
+		PropagationPolicy: req.PropagationPolicy,
+	}
 	resp, err := rudder.DeleteRelease(deleteRequest)
 	if err != nil {
 		return resp.Release.Manifest, []error{err}
@@ -138,7 +165,15 @@ func (m *RemoteReleaseModule) Delete(r *release.Release, req *services.Uninstall
 }
 
 // DeleteRelease is a helper that allows Rudder to delete a release without exposing most of Tiller inner functions
-func DeleteRelease(rel *release.Release, vs chartutil.VersionSet, kubeClient environment.KubeClient) (kept string, errs []error) {
+//
+// Resources are deleted in UninstallOrder with the given propagation policy;
+// see KubeClient.DeleteWithPolicy. Custom resources whose
+// CustomResourceDefinition is part of the release are deleted first, while
+// controllers that the release may run can still handle their finalizers,
+// and are waited for to be gone before the definition is deleted. The custom
+// resources share a single timeout between them. A definition whose custom
+// resources remain, e.g. because their finalizers hang, is not deleted.
+func DeleteRelease(rel *release.Release, vs chartutil.VersionSet, kubeClient environment.KubeClient, policy string, timeout int64, wait bool) (kept string, errs []error) {
 	manifests := relutil.SplitManifests(rel.Manifest)
 	_, files, err := sortManifests(manifests, vs, UninstallOrder)
 	if err != nil {
@@ -154,17 +189,48 @@ func DeleteRelease(rel *release.Release, vs chartutil.VersionSet, kubeClient env
 		kept = summarizeKeptManifests(filesToKeep)
 	}
 
+	kinds := definedKinds(filesToDelete)
+	filesToDelete = customResourcesFirst(filesToDelete, kinds)
+	crTimeout := timeout
+	if crTimeout <= 0 {
+		crTimeout = customResourceDeletionTimeout
+	}
+	crBudget := newTimeoutBudget(crTimeout, 0)
+	// remaining are the errors of custom resources that may still exist, by
+	// the name of their definition.
+	remaining := map[string]error{}
+
 	errs = []error{}
 	for _, file := range filesToDelete {
 		b := bytes.NewBufferString(strings.TrimSpace(file.content))
 		if b.Len() == 0 {
 			continue
 		}
-		if err := kubeClient.Delete(rel.Namespace, b); err != nil {
+		if file.head.Kind == "CustomResourceDefinition" && file.head.Metadata != nil {
+			if err, ok := remaining[file.head.Metadata.Name]; ok {
+				log.Printf("uninstall: Not deleting CustomResourceDefinition %q of %q: %s", file.head.Metadata.Name, rel.Name, err)
+				errs = append(errs, fmt.Errorf("CustomResourceDefinition %q was not deleted, because its custom resources may still exist (%s). Check them for finalizers that cannot complete, then delete it manually", file.head.Metadata.Name, err))
+				continue
+			}
+		}
+		crd := definingCRD(file, kinds)
+		resourceTimeout, resourceWait := timeout, wait
+		if crd != "" {
+			// Custom resources left without time are still deleted, but not
+			// waited for, so their definition is kept.
+			t, err := crBudget.start("deletion of custom resources")
+			if err != nil {
+				remaining[crd] = err
+			}
+			resourceTimeout, resourceWait = t, err == nil
+		}
+		if err := kubeClient.DeleteWithPolicy(rel.Namespace, b, policy, resourceTimeout, resourceWait); err != nil {
 			log.Printf("uninstall: Failed deletion of %q: %s", rel.Name, err)
 			if err == kube.ErrNoObjectsVisited {
 				// Rewrite the message from "no objects visited"
 				err = errors.New("object not found, skipping delete")
+			} else if crd != "" {
+				remaining[crd] = err
 			}
 			errs = append(errs, err)
 		}
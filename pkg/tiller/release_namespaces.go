@@ -0,0 +1,61 @@
+/*
+Copyright 2017 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tiller
+
+import (
+	"fmt"
+	"sort"
+
+	"k8s.io/helm/pkg/proto/hapi/release"
+)
+
+// SetAllowedNamespaces restricts the namespaces that the resources and hooks
+// of a release may declare, other than the release's own namespace, to
+// namespaces. Without namespaces, releases are confined to their own
+// namespace. If it is never called, any namespace may be used.
+func (s *ReleaseServer) SetAllowedNamespaces(namespaces []string) {
+	s.allowedNamespaces = make(map[string]bool, len(namespaces))
+	for _, ns := range namespaces {
+		s.allowedNamespaces[ns] = true
+	}
+}
+
+// setNamespaces records the namespaces that the resources and hooks of r are
+// in, after checking that each is allowed.
+func (s *ReleaseServer) setNamespaces(r *release.Release) error {
+	seen := map[string]bool{r.Namespace: true}
+	r.Namespaces = []string{r.Namespace}
+
+	docs := sortedManifests(r.Manifest)
+	for _, h := range r.Hooks {
+		docs = append(docs, h.Manifest)
+	}
+	for _, doc := range docs {
+		head := manifestHead(doc)
+		if head == nil || head.Metadata.Namespace == "" || seen[head.Metadata.Namespace] {
+			continue
+		}
+		ns := head.Metadata.Namespace
+		if s.allowedNamespaces != nil && !s.allowedNamespaces[ns] {
+			return fmt.Errorf("%s %q is in namespace %q, which release %s may not use: only %s and the namespaces allowed by Tiller's --allowed-namespace can be used", head.Kind, head.Metadata.Name, ns, r.Name, r.Namespace)
+		}
+		seen[ns] = true
+		r.Namespaces = append(r.Namespaces, ns)
+	}
+	sort.Strings(r.Namespaces[1:])
+	return nil
+}
@@ -0,0 +1,148 @@
+/*
+Copyright 2017 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tiller
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"k8s.io/helm/pkg/helm"
+	"k8s.io/helm/pkg/proto/hapi/chart"
+	"k8s.io/helm/pkg/proto/hapi/release"
+	"k8s.io/helm/pkg/proto/hapi/services"
+)
+
+var multiNamespaceChart = &chart.Chart{
+	Metadata: &chart.Metadata{Name: "platform"},
+	Templates: []*chart.Template{
+		{Name: "templates/agents", Data: []byte(`apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: agent
+  namespace: monitoring
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: agent
+  namespace: logging
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: settings
+`)},
+		{Name: "templates/hook", Data: []byte(`apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: migrate
+  namespace: jobs
+  annotations:
+    "helm.sh/hook": pre-install
+`)},
+	},
+}
+
+func TestInstallRelease_Namespaces(t *testing.T) {
+	rs := rsFixture()
+	res, err := rs.InstallRelease(helm.NewContext(), &services.InstallReleaseRequest{
+		Name:      "platform",
+		Namespace: "spaced",
+		Chart:     multiNamespaceChart,
+	})
+	if err != nil {
+		t.Fatalf("Failed install: %s", err)
+	}
+	expect := []string{"spaced", "jobs", "logging", "monitoring"}
+	if !reflect.DeepEqual(res.Release.Namespaces, expect) {
+		t.Errorf("Expected namespaces %v, got %v", expect, res.Release.Namespaces)
+	}
+
+	status, err := rs.GetReleaseStatus(helm.NewContext(), &services.GetReleaseStatusRequest{Name: "platform"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(status.Namespaces, expect) {
+		t.Errorf("Expected the status to list namespaces %v, got %v", expect, status.Namespaces)
+	}
+}
+
+func TestInstallRelease_NamespaceAllowlist(t *testing.T) {
+	rs := rsFixture()
+	rs.SetAllowedNamespaces([]string{"monitoring", "jobs"})
+
+	_, err := rs.InstallRelease(helm.NewContext(), &services.InstallReleaseRequest{
+		Name:      "platform",
+		Namespace: "spaced",
+		Chart:     multiNamespaceChart,
+	})
+	if err == nil || !strings.Contains(err.Error(), `ConfigMap "agent" is in namespace "logging", which release platform may not use`) {
+		t.Fatalf("Expected the logging namespace to be refused, got %v", err)
+	}
+
+	rs.SetAllowedNamespaces([]string{"monitoring", "jobs", "logging"})
+	if _, err := rs.InstallRelease(helm.NewContext(), &services.InstallReleaseRequest{
+		Name:      "platform",
+		Namespace: "spaced",
+		Chart:     multiNamespaceChart,
+	}); err != nil {
+		t.Fatalf("Failed install: %s", err)
+	}
+}
+
+func TestUpdateRelease_NamespaceAllowlist(t *testing.T) {
+	rs := rsFixture()
+	rs.SetAllowedNamespaces(nil)
+	rel := releaseStub()
+	rs.env.Releases.Create(rel)
+
+	_, err := rs.UpdateRelease(helm.NewContext(), &services.UpdateReleaseRequest{
+		Name:  rel.Name,
+		Chart: multiNamespaceChart,
+	})
+	if err == nil || !strings.Contains(err.Error(), "may not use") {
+		t.Fatalf("Expected other namespaces to be refused, got %v", err)
+	}
+	if last, err := rs.env.Releases.Last(rel.Name); err != nil || last.Version != rel.Version {
+		t.Errorf("Expected no new revision, got %v (%v)", last, err)
+	}
+}
+
+func TestRollbackRelease_Namespaces(t *testing.T) {
+	rs := rsFixture()
+	rel := releaseStub()
+	rel.Manifest = `apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: agent
+  namespace: monitoring
+`
+	rs.env.Releases.Create(rel)
+	upgraded := upgradeReleaseVersion(rel)
+	rel.Info.Status.Code = release.Status_SUPERSEDED
+	rs.env.Releases.Update(rel)
+	rs.env.Releases.Create(upgraded)
+
+	res, err := rs.RollbackRelease(helm.NewContext(), &services.RollbackReleaseRequest{Name: rel.Name, Version: 1})
+	if err != nil {
+		t.Fatalf("Failed rollback: %s", err)
+	}
+	if expect := []string{rel.Namespace, "monitoring"}; !reflect.DeepEqual(res.Release.Namespaces, expect) {
+		t.Errorf("Expected namespaces %v, got %v", expect, res.Release.Namespaces)
+	}
+}
@@ -0,0 +1,164 @@
+/*
+Copyright 2017 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tiller
+
+import (
+	"bytes"
+	"sort"
+	"strings"
+
+	"github.com/ghodss/yaml"
+
+	"k8s.io/helm/pkg/logging"
+	"k8s.io/helm/pkg/proto/hapi/release"
+	relutil "k8s.io/helm/pkg/releaseutil"
+)
+
+// pruneBase returns the release to hand to the release module as the original
+// state of an upgrade from original to target.
+//
+// The Kubernetes client deletes every object of the original manifest that is
+// missing from the target manifest. Objects with the keep resource policy and
+// objects that another deployed release also declares are left out of the
+// returned manifest, so that they are not deleted. If keepRemoved is set, all
+// objects removed from the chart are left out, except for the content-hashed
+// objects of earlier revisions, which nothing refers to any more.
+func (s *ReleaseServer) pruneBase(log logging.Logger, original, target *release.Release, keepRemoved bool) *release.Release {
+	wanted := manifestKeys(target.Manifest, target.Namespace)
+
+	foreign, err := s.foreignResources(original)
+	if err != nil {
+		log.Warnf("not deleting removed resources, cannot determine resource ownership: %s", err)
+	}
+
+	var b bytes.Buffer
+	skipped := false
+	for _, content := range sortedManifests(original.Manifest) {
+		head := manifestHead(content)
+		var key string
+		if head != nil {
+			key = resourceKey(head, original.Namespace)
+		}
+		if head != nil && !wanted[key] {
+			switch {
+			case err != nil:
+				skipped = true
+				continue
+			case keepRemoved && !hasContentHash(head):
+				log.Infof("leaving removed %s %q in place", head.Kind, head.Metadata.Name)
+				skipped = true
+				continue
+			case hasKeepPolicy(head):
+				log.Infof("not deleting removed %s %q due to the resource policy", head.Kind, head.Metadata.Name)
+				skipped = true
+				continue
+			case foreign[key]:
+				log.Infof("not deleting removed %s %q, it is declared by another release", head.Kind, head.Metadata.Name)
+				skipped = true
+				continue
+			}
+		}
+		b.WriteString("---\n")
+		b.WriteString(content)
+		b.WriteString("\n")
+	}
+	if !skipped {
+		return original
+	}
+
+	base := *original
+	base.Manifest = b.String()
+	return &base
+}
+
+// foreignResources returns the keys of the resources declared by the deployed
+// releases other than rel.
+func (s *ReleaseServer) foreignResources(rel *release.Release) (map[string]bool, error) {
+	deployed, err := s.env.Releases.ListDeployed()
+	if err != nil {
+		return nil, err
+	}
+	keys := map[string]bool{}
+	for _, r := range deployed {
+		if r.Name == rel.Name {
+			continue
+		}
+		for k := range manifestKeys(r.Manifest, r.Namespace) {
+			keys[k] = true
+		}
+	}
+	return keys, nil
+}
+
+// manifestKeys returns the keys of the resources declared in a release manifest
+// that is installed in namespace. See resourceKey.
+func manifestKeys(m, namespace string) map[string]bool {
+	keys := map[string]bool{}
+	for _, content := range relutil.SplitManifests(m) {
+		if head := manifestHead(content); head != nil {
+			keys[resourceKey(head, namespace)] = true
+		}
+	}
+	return keys
+}
+
+// sortedManifests splits a release manifest into its documents, in a stable order.
+func sortedManifests(m string) []string {
+	files := relutil.SplitManifests(m)
+	names := make([]string, 0, len(files))
+	for n := range files {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+
+	docs := make([]string, 0, len(names))
+	for _, n := range names {
+		if strings.TrimSpace(files[n]) != "" {
+			docs = append(docs, files[n])
+		}
+	}
+	return docs
+}
+
+// manifestHead parses the head of a manifest document. It returns nil for
+// documents that do not name a resource.
+func manifestHead(content string) *relutil.SimpleHead {
+	var head relutil.SimpleHead
+	if err := yaml.Unmarshal([]byte(content), &head); err != nil {
+		return nil
+	}
+	if head.Kind == "" || head.Metadata == nil || head.Metadata.Name == "" {
+		return nil
+	}
+	return &head
+}
+
+// resourceKey identifies the resource of a manifest head the way the
+// Kubernetes client matches objects, by API version, kind, namespace and name.
+// Resources that do not set a namespace are in namespace, the namespace the
+// release is installed in.
+func resourceKey(head *relutil.SimpleHead, namespace string) string {
+	return objectKey(head.Version, head.Kind, head.Metadata.Namespace, head.Metadata.Name, namespace)
+}
+
+// objectKey is resourceKey for an object given by its parts.
+func objectKey(version, kind, ns, name, namespace string) string {
+	if ns == "" {
+		ns = namespace
+	}
+	return version + "/" + kind + "/" + ns + "/" + name
+}
@@ -0,0 +1,212 @@
+/*
+Copyright 2017 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tiller
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+
+	"k8s.io/helm/pkg/helm"
+	"k8s.io/helm/pkg/kube"
+	"k8s.io/helm/pkg/proto/hapi/chart"
+	"k8s.io/helm/pkg/proto/hapi/release"
+	"k8s.io/helm/pkg/proto/hapi/services"
+	"k8s.io/helm/pkg/tiller/environment"
+)
+
+func pruneConfigMap(name string, keep bool) string {
+	cm := fmt.Sprintf("apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: %s\n", name)
+	if keep {
+		cm += "  annotations:\n    helm.sh/resource-policy: keep\n"
+	}
+	return cm + "data:\n  name: value\n"
+}
+
+func pruneManifest(docs ...string) string {
+	m := ""
+	for _, d := range docs {
+		m += "---\n" + d
+	}
+	return m
+}
+
+func pruneNames(m string) []string {
+	names := []string{}
+	for _, content := range sortedManifests(m) {
+		if head := manifestHead(content); head != nil {
+			names = append(names, head.Metadata.Name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+func TestPruneBase(t *testing.T) {
+	rs := rsFixture()
+
+	other := namedReleaseStub("other", release.Status_DEPLOYED)
+	other.Manifest = pruneManifest(pruneConfigMap("shared", false))
+	rs.env.Releases.Create(other)
+
+	original := releaseStub()
+	original.Manifest = pruneManifest(
+		pruneConfigMap("stays", false),
+		pruneConfigMap("removed", false),
+		pruneConfigMap("kept", true),
+		pruneConfigMap("shared", false),
+	)
+	target := upgradeReleaseVersion(original)
+	target.Manifest = pruneManifest(pruneConfigMap("stays", false))
+
+	tests := []struct {
+		keepRemoved bool
+		expect      []string
+	}{
+		{false, []string{"removed", "stays"}},
+		{true, []string{"stays"}},
+	}
+	for _, tt := range tests {
+		log := rs.requestLogger("update", original.Name, target.Version)
+		base := rs.pruneBase(log, original, target, tt.keepRemoved)
+		if got := pruneNames(base.Manifest); !reflect.DeepEqual(got, tt.expect) {
+			t.Errorf("keepRemoved=%t: expected original resources %v, got %v", tt.keepRemoved, tt.expect, got)
+		}
+		if len(pruneNames(original.Manifest)) != 4 {
+			t.Errorf("keepRemoved=%t: the stored release manifest was modified", tt.keepRemoved)
+		}
+	}
+}
+
+func TestPruneBaseUnchanged(t *testing.T) {
+	rs := rsFixture()
+
+	original := releaseStub()
+	original.Manifest = pruneManifest(pruneConfigMap("stays", false))
+	target := upgradeReleaseVersion(original)
+	target.Manifest = pruneManifest(pruneConfigMap("stays", false), pruneConfigMap("added", false))
+
+	log := rs.requestLogger("update", original.Name, target.Version)
+	if base := rs.pruneBase(log, original, target, false); base != original {
+		t.Error("expected the original release when nothing was removed")
+	}
+}
+
+func TestPruneBaseOtherNamespace(t *testing.T) {
+	rs := rsFixture()
+
+	other := namedReleaseStub("other", release.Status_DEPLOYED)
+	other.Namespace = "elsewhere"
+	other.Manifest = pruneManifest(pruneConfigMap("removed", false))
+	rs.env.Releases.Create(other)
+
+	original := releaseStub()
+	original.Manifest = pruneManifest(pruneConfigMap("removed", false))
+	target := upgradeReleaseVersion(original)
+	target.Manifest = ""
+
+	log := rs.requestLogger("update", original.Name, target.Version)
+	base := rs.pruneBase(log, original, target, false)
+	if got := pruneNames(base.Manifest); !reflect.DeepEqual(got, []string{"removed"}) {
+		t.Errorf("expected a resource of another namespace's release to be deleted, got %v", got)
+	}
+}
+
+func TestPruneBaseNamespaces(t *testing.T) {
+	rs := rsFixture()
+
+	elsewhere := "apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: settings\n  namespace: elsewhere\n"
+
+	// A release in another namespace declares the resource in the namespace
+	// of the upgraded release.
+	other := namedReleaseStub("other", release.Status_DEPLOYED)
+	other.Namespace = "elsewhere"
+	other.Manifest = pruneManifest(pruneConfigMap("shared", false))
+	rs.env.Releases.Create(other)
+
+	original := releaseStub()
+	original.Manifest = pruneManifest(
+		pruneConfigMap("settings", false),
+		elsewhere,
+		"apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: shared\n  namespace: elsewhere\n",
+	)
+	target := upgradeReleaseVersion(original)
+	target.Manifest = pruneManifest(pruneConfigMap("settings", false))
+
+	log := rs.requestLogger("update", original.Name, target.Version)
+	base := rs.pruneBase(log, original, target, false)
+	if got := pruneNames(base.Manifest); !reflect.DeepEqual(got, []string{"settings", "settings"}) {
+		t.Fatalf("expected the removed resource of the same name in another namespace to be deleted, got %v", got)
+	}
+	if !strings.Contains(base.Manifest, elsewhere) {
+		t.Errorf("expected the resource in namespace elsewhere to be deleted, got\n%s", base.Manifest)
+	}
+}
+
+type pruneRecordingKubeClient struct {
+	environment.PrintingKubeClient
+	original string
+}
+
+func (p *pruneRecordingKubeClient) UpdateWithOptions(ns string, originalReader, modifiedReader io.Reader, opts kube.UpdateOptions) error {
+	b, err := ioutil.ReadAll(originalReader)
+	p.original = string(b)
+	return err
+}
+
+func TestUpdateRelease_DeletesRemoved(t *testing.T) {
+	c := helm.NewContext()
+
+	tests := []struct {
+		keepRemoved bool
+		expect      []string
+	}{
+		{false, []string{"removed", "stays"}},
+		{true, []string{"stays"}},
+	}
+	for _, tt := range tests {
+		rs := rsFixture()
+		kc := &pruneRecordingKubeClient{PrintingKubeClient: environment.PrintingKubeClient{Out: os.Stdout}}
+		rs.env.KubeClient = kc
+
+		rel := releaseStub()
+		rel.Manifest = pruneManifest(pruneConfigMap("stays", false), pruneConfigMap("removed", false))
+		rs.env.Releases.Create(rel)
+
+		ch := &chart.Chart{
+			Metadata:  &chart.Metadata{Name: "hello"},
+			Templates: []*chart.Template{{Name: "templates/stays", Data: []byte(pruneConfigMap("stays", false))}},
+		}
+		if _, err := rs.UpdateRelease(c, &services.UpdateReleaseRequest{
+			Name:         rel.Name,
+			Chart:        ch,
+			KeepRemoved:  tt.keepRemoved,
+			DisableHooks: true,
+		}); err != nil {
+			t.Fatalf("Failed upgrade: %s", err)
+		}
+
+		if got := pruneNames(kc.original); !reflect.DeepEqual(got, tt.expect) {
+			t.Errorf("keepRemoved=%t: expected original resources %v, got %v", tt.keepRemoved, tt.expect, got)
+		}
+	}
+}
@@ -0,0 +1,91 @@
+/*
+Copyright 2017 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tiller
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	ctx "golang.org/x/net/context"
+
+	"k8s.io/helm/pkg/kube"
+	"k8s.io/helm/pkg/proto/hapi/services"
+)
+
+// RestartRelease restarts the pods of the Deployments, StatefulSets and
+// DaemonSets of a deployed release, e.g. so that they pick up a rotated
+// secret. Neither the chart nor the values change, so no new revision is
+// created: the restart is recorded in the description of the deployed
+// revision.
+func (s *ReleaseServer) RestartRelease(c ctx.Context, req *services.RestartReleaseRequest) (*services.RestartReleaseResponse, error) {
+	if !ValidName.MatchString(req.Name) {
+		return nil, errMissingRelease
+	}
+
+	if err := s.lockRelease(req.Name); err != nil {
+		return nil, err
+	}
+	defer s.env.Releases.UnlockRelease(req.Name)
+
+	rel, err := s.env.Releases.Deployed(req.Name)
+	if err != nil {
+		return nil, fmt.Errorf("getting deployed release %q: %s", req.Name, err)
+	}
+	workloads, restarted := restartableWorkloads(rel.Manifest)
+	if len(restarted) == 0 {
+		return nil, fmt.Errorf("release %s has no Deployments, StatefulSets or DaemonSets to restart", rel.Name)
+	}
+
+	kc, err := s.releaseCluster(rel)
+	if err != nil {
+		return nil, err
+	}
+
+	log := s.requestLogger("restart", rel.Name, rel.Version)
+	log.Infof("Restarting %s for %s", strings.Join(restarted, ", "), caller(c))
+	err = kc.env.KubeClient.Restart(rel.Namespace, bytes.NewBufferString(workloads), req.Timeout, req.Wait)
+	if err != nil {
+		log.Warnf("Restart failed: %s", err)
+		rel.Info.Description = fmt.Sprintf("Restart failed: %s", err)
+	} else {
+		rel.Info.Description = "Restarted " + strings.Join(restarted, ", ")
+	}
+	if uerr := s.env.Releases.Update(rel); uerr != nil {
+		log.Warnf("Failed to record the restart: %s", uerr)
+	}
+	s.emitEvent(rel)
+	return &services.RestartReleaseResponse{Release: rel, Restarted: restarted}, err
+}
+
+// restartableWorkloads returns the documents of a release manifest that
+// declare a Deployment, StatefulSet or DaemonSet, and the workloads they
+// declare as "Kind/name".
+func restartableWorkloads(m string) (string, []string) {
+	var b bytes.Buffer
+	var workloads []string
+	for _, doc := range sortedManifests(m) {
+		head := manifestHead(doc)
+		if head == nil || !kube.RestartableKinds[head.Kind] {
+			continue
+		}
+		b.WriteString("\n---\n")
+		b.WriteString(doc)
+		workloads = append(workloads, head.Kind+"/"+head.Metadata.Name)
+	}
+	return b.String(), workloads
+}
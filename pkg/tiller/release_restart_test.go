@@ -0,0 +1,171 @@
+/*
+Copyright 2017 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tiller
+
+import (
+	"errors"
+	"io"
+	"io/ioutil"
+	"reflect"
+	"strings"
+	"testing"
+
+	"k8s.io/helm/pkg/helm"
+	"k8s.io/helm/pkg/proto/hapi/release"
+	"k8s.io/helm/pkg/proto/hapi/services"
+	"k8s.io/helm/pkg/tiller/environment"
+)
+
+var restartManifest = `---
+# Source: hello/templates/configmap.yaml
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: settings
+---
+# Source: hello/templates/deployment.yaml
+apiVersion: extensions/v1beta1
+kind: Deployment
+metadata:
+  name: web
+---
+# Source: hello/templates/statefulset.yaml
+apiVersion: apps/v1beta1
+kind: StatefulSet
+metadata:
+  name: db
+---
+# Source: hello/templates/job.yaml
+apiVersion: batch/v1
+kind: Job
+metadata:
+  name: migrate
+`
+
+type restartRecordingKubeClient struct {
+	environment.PrintingKubeClient
+	restarted string
+	wait      bool
+	err       error
+}
+
+func (r *restartRecordingKubeClient) Restart(ns string, reader io.Reader, timeout int64, shouldWait bool) error {
+	b, err := ioutil.ReadAll(reader)
+	if err != nil {
+		return err
+	}
+	r.restarted = string(b)
+	r.wait = shouldWait
+	return r.err
+}
+
+func TestRestartRelease(t *testing.T) {
+	c := helm.NewContext()
+	rs := rsFixture()
+	kc := &restartRecordingKubeClient{}
+	rs.env.KubeClient = kc
+	rel := releaseStub()
+	rel.Manifest = restartManifest
+	rs.env.Releases.Create(rel)
+
+	res, err := rs.RestartRelease(c, &services.RestartReleaseRequest{Name: rel.Name, Wait: true})
+	if err != nil {
+		t.Fatalf("Failed restart: %s", err)
+	}
+	if expect := []string{"Deployment/web", "StatefulSet/db"}; !reflect.DeepEqual(res.Restarted, expect) {
+		t.Errorf("Expected %v to be restarted, got %v", expect, res.Restarted)
+	}
+	for _, name := range []string{"name: web", "name: db"} {
+		if !strings.Contains(kc.restarted, name) {
+			t.Errorf("Expected %q to be restarted, got:\n%s", name, kc.restarted)
+		}
+	}
+	for _, name := range []string{"name: settings", "name: migrate"} {
+		if strings.Contains(kc.restarted, name) {
+			t.Errorf("Expected %q not to be touched, got:\n%s", name, kc.restarted)
+		}
+	}
+	if !kc.wait {
+		t.Error("Expected the restart to wait")
+	}
+
+	h, err := rs.env.Releases.History(rel.Name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(h) != 1 {
+		t.Fatalf("Expected no new revision, got %d revisions", len(h))
+	}
+	if got := h[0].Info.Description; got != "Restarted Deployment/web, StatefulSet/db" {
+		t.Errorf("Expected the restart to be recorded, got description %q", got)
+	}
+	if h[0].Info.Status.Code != release.Status_DEPLOYED {
+		t.Errorf("Expected the release to stay deployed, got %s", h[0].Info.Status.Code)
+	}
+}
+
+func TestRestartRelease_Failure(t *testing.T) {
+	c := helm.NewContext()
+	rs := rsFixture()
+	rs.env.KubeClient = &restartRecordingKubeClient{err: errors.New("timed out waiting for the condition")}
+	rel := releaseStub()
+	rel.Manifest = restartManifest
+	rs.env.Releases.Create(rel)
+
+	if _, err := rs.RestartRelease(c, &services.RestartReleaseRequest{Name: rel.Name, Wait: true}); err == nil {
+		t.Fatal("Expected the restart to fail")
+	}
+	stored, err := rs.env.Releases.Get(rel.Name, rel.Version)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := stored.Info.Description; got != "Restart failed: timed out waiting for the condition" {
+		t.Errorf("Expected the failure to be recorded, got description %q", got)
+	}
+}
+
+func TestRestartReleaseErrors(t *testing.T) {
+	c := helm.NewContext()
+	rs := rsFixture()
+	rs.env.KubeClient = &restartRecordingKubeClient{}
+
+	noWorkloads := releaseStub()
+	noWorkloads.Manifest = `---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: settings
+`
+	rs.env.Releases.Create(noWorkloads)
+	rs.env.Releases.Create(namedReleaseStub("deleted-panda", release.Status_DELETED))
+
+	tests := []struct {
+		name   string
+		expect string
+	}{
+		{"", "no release provided"},
+		{"missing-panda", "not found"},
+		{"deleted-panda", "getting deployed release"},
+		{noWorkloads.Name, "has no Deployments, StatefulSets or DaemonSets to restart"},
+	}
+	for _, tt := range tests {
+		_, err := rs.RestartRelease(c, &services.RestartReleaseRequest{Name: tt.name})
+		if err == nil || !strings.Contains(err.Error(), tt.expect) {
+			t.Errorf("%q: expected an error containing %q, got %v", tt.name, tt.expect, err)
+		}
+	}
+}
@@ -0,0 +1,202 @@
+/*
+Copyright 2017 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tiller
+
+import (
+	"fmt"
+	"time"
+
+	ctx "golang.org/x/net/context"
+
+	"k8s.io/helm/pkg/hooks"
+	"k8s.io/helm/pkg/proto/hapi/release"
+	"k8s.io/helm/pkg/proto/hapi/services"
+	"k8s.io/helm/pkg/timeconv"
+)
+
+// RestoreRelease re-installs a deleted release from the record of its last
+// revision. The restored release is stored as a new revision.
+//
+// Identical concurrent restore requests are only performed once; see operationKey.
+func (s *ReleaseServer) RestoreRelease(c ctx.Context, req *services.RestoreReleaseRequest) (*services.RestoreReleaseResponse, error) {
+	res, err := s.dedupe("restore", req.Name, req, func() (interface{}, error) {
+		return s.restoreRelease(req)
+	})
+	resp, _ := res.(*services.RestoreReleaseResponse)
+	return resp, err
+}
+
+func (s *ReleaseServer) restoreRelease(req *services.RestoreReleaseRequest) (*services.RestoreReleaseResponse, error) {
+	if !ValidName.MatchString(req.Name) {
+		return nil, errMissingRelease
+	}
+
+	if err := s.lockRelease(req.Name); err != nil {
+		return nil, err
+	}
+	defer s.env.Releases.UnlockRelease(req.Name)
+
+	deleted, err := s.env.Releases.Last(req.Name)
+	if err != nil {
+		return nil, err
+	}
+	if deleted.Info.Status.Code != release.Status_DELETED {
+		return nil, fmt.Errorf("release %q is not deleted", req.Name)
+	}
+	kc, err := s.releaseCluster(deleted)
+	if err != nil {
+		return nil, err
+	}
+
+	target := &release.Release{
+		Name:            deleted.Name,
+		Namespace:       deleted.Namespace,
+		Chart:           deleted.Chart,
+		Config:          deleted.Config,
+		ComputedValues:  deleted.ComputedValues,
+		GeneratedValues: deleted.GeneratedValues,
+		Profile: deleted.Profile,
+		AllowMissingProfile: deleted.AllowMissingProfile,
+		Info: &release.Info{
+			FirstDeployed: deleted.Info.FirstDeployed,
+			LastDeployed:  timeconv.Now(),
+			Status: &release.Status{
+				Code:  release.Status_UNKNOWN,
+				Notes: deleted.Info.Status.Notes,
+			},
+			Description: fmt.Sprintf("Restored from %d", deleted.Version),
+		},
+		Version:  deleted.Version + 1,
+		Manifest: deleted.Manifest,
+		Hooks:    deleted.Hooks,
+		Cluster:  deleted.Cluster,
+		System:   deleted.System,
+	}
+	res := &services.RestoreReleaseResponse{Release: target}
+	log := s.requestLogger("restore", target.Name, target.Version)
+	skip := newHookSkipList(nil, nil)
+
+	if !req.DisableHooks {
+		if err := s.execHook(log, kc.env.KubeClient, target, hooks.PreInstall, req.Timeout, skip); err != nil {
+			return res, err
+		}
+	}
+
+	// Resources kept by their resource policy survived the deletion. Updating
+	// from the deleted revision adopts them and creates everything else.
+	updateReq := &services.UpdateReleaseRequest{
+		Wait:    req.Wait,
+		Timeout: req.Timeout,
+	}
+	if err := kc.module.Update(deleted, target, updateReq, kc.env); err != nil {
+		msg := fmt.Sprintf("Restore %q failed: %s", target.Name, err)
+		log.Warnf("%s", msg)
+		target.Info.Status.Code = release.Status_FAILED
+		target.Info.Description = msg
+		recordResourceStatuses(target, err)
+		s.recordRelease(target, false)
+		return res, err
+	}
+
+	if !req.DisableHooks {
+		if err := s.execHook(log, kc.env.KubeClient, target, hooks.PostInstall, req.Timeout, skip); err != nil {
+			msg := fmt.Sprintf("Release %q failed post-install: %s", target.Name, err)
+			log.Warnf("%s", msg)
+			target.Info.Status.Code = release.Status_FAILED
+			target.Info.Description = msg
+			s.recordRelease(target, false)
+			return res, err
+		}
+	}
+
+	if req.Wait {
+		s.refreshNotes(log, target, nil, nil, false)
+	}
+
+	deleted.Info.Status.Code = release.Status_SUPERSEDED
+	s.recordRelease(deleted, true)
+
+	target.Info.Status.Code = release.Status_DEPLOYED
+	s.recordRelease(target, false)
+	log.Infof("Restored %s from revision %d", target.Name, deleted.Version)
+
+	return res, nil
+}
+
+// PurgeExpiredReleases permanently removes the records of releases that were
+// deleted longer ago than the retention set with SetDeletedReleaseRetention.
+// Releases that are locked by another operation are left for the next call.
+//
+// It returns the names of the purged releases.
+func (s *ReleaseServer) PurgeExpiredReleases() ([]string, error) {
+	if s.deletedRetention <= 0 {
+		return nil, nil
+	}
+	deleted, err := s.env.Releases.ListDeleted()
+	if err != nil {
+		return nil, err
+	}
+
+	cutoff := time.Now().Add(-s.deletedRetention)
+	purged := []string{}
+	seen := map[string]bool{}
+	for _, rel := range deleted {
+		if seen[rel.Name] {
+			continue
+		}
+		seen[rel.Name] = true
+
+		ok, err := s.purgeIfExpired(rel.Name, cutoff)
+		if err != nil {
+			return purged, err
+		}
+		if ok {
+			purged = append(purged, rel.Name)
+		}
+	}
+	return purged, nil
+}
+
+// purgeIfExpired purges the named release if its latest revision was deleted
+// before cutoff.
+func (s *ReleaseServer) purgeIfExpired(name string, cutoff time.Time) (bool, error) {
+	// Do not wait for releases that are being operated on.
+	if err := s.env.Releases.LockReleaseWithJitter(name, time.Millisecond); err != nil {
+		return false, nil
+	}
+	defer s.env.Releases.UnlockRelease(name)
+
+	rels, err := s.env.Releases.History(name)
+	if err != nil || len(rels) == 0 {
+		return false, err
+	}
+	latest := rels[0]
+	for _, rel := range rels {
+		if rel.Version > latest.Version {
+			latest = rel
+		}
+	}
+	if latest.Info.Status.Code != release.Status_DELETED || latest.Info.Deleted == nil {
+		return false, nil
+	}
+	if !timeconv.Time(latest.Info.Deleted).Before(cutoff) {
+		return false, nil
+	}
+
+	s.requestLogger("purge", name, latest.Version).Infof("Purging %s, deleted at %s", name, timeconv.String(latest.Info.Deleted))
+	return true, s.purgeReleases(rels...)
+}
@@ -0,0 +1,148 @@
+/*
+Copyright 2017 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tiller
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"k8s.io/helm/pkg/helm"
+	"k8s.io/helm/pkg/proto/hapi/release"
+	"k8s.io/helm/pkg/proto/hapi/services"
+	"k8s.io/helm/pkg/timeconv"
+)
+
+func TestRestoreRelease(t *testing.T) {
+	c := helm.NewContext()
+	rs := rsFixture()
+	rel := releaseStub()
+	rel.Manifest = pruneConfigMap("restored", false)
+	rs.env.Releases.Create(rel)
+
+	if _, err := rs.UninstallRelease(c, &services.UninstallReleaseRequest{Name: rel.Name}); err != nil {
+		t.Fatalf("Failed uninstall: %s", err)
+	}
+
+	res, err := rs.RestoreRelease(c, &services.RestoreReleaseRequest{Name: rel.Name})
+	if err != nil {
+		t.Fatalf("Failed restore: %s", err)
+	}
+
+	if res.Release.Version != 2 {
+		t.Errorf("Expected revision 2, got %d", res.Release.Version)
+	}
+	if res.Release.Info.Status.Code != release.Status_DEPLOYED {
+		t.Errorf("Expected DEPLOYED, got %s", res.Release.Info.Status.Code)
+	}
+	if res.Release.Info.Description != "Restored from 1" {
+		t.Errorf("Unexpected description %q", res.Release.Info.Description)
+	}
+	if res.Release.Manifest != rel.Manifest {
+		t.Errorf("Expected the manifest of the deleted release, got %q", res.Release.Manifest)
+	}
+	if res.Release.Info.Deleted != nil {
+		t.Error("Expected the restored release not to be marked as deleted")
+	}
+
+	deleted, err := rs.env.Releases.Get(rel.Name, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if deleted.Info.Status.Code != release.Status_SUPERSEDED {
+		t.Errorf("Expected the deleted revision to be SUPERSEDED, got %s", deleted.Info.Status.Code)
+	}
+	if _, err := rs.env.Releases.Get(rel.Name, 2); err != nil {
+		t.Errorf("Expected the restored revision to be stored: %s", err)
+	}
+}
+
+func TestRestoreRelease_NotDeleted(t *testing.T) {
+	c := helm.NewContext()
+	rs := rsFixture()
+	rs.env.Releases.Create(releaseStub())
+
+	if _, err := rs.RestoreRelease(c, &services.RestoreReleaseRequest{Name: "angry-panda"}); err == nil {
+		t.Fatal("Expected an error restoring a deployed release")
+	}
+	if _, err := rs.RestoreRelease(c, &services.RestoreReleaseRequest{Name: "missing"}); err == nil {
+		t.Fatal("Expected an error restoring a missing release")
+	}
+}
+
+func TestRestoreRelease_Failure(t *testing.T) {
+	c := helm.NewContext()
+	rs := rsFixture()
+	rs.env.KubeClient = newUpdateFailingKubeClient()
+	rel := namedReleaseStub("angry-panda", release.Status_DELETED)
+	rs.env.Releases.Create(rel)
+
+	res, err := rs.RestoreRelease(c, &services.RestoreReleaseRequest{Name: rel.Name, DisableHooks: true})
+	if err == nil {
+		t.Fatal("Expected the restore to fail")
+	}
+	if res.Release.Info.Status.Code != release.Status_FAILED {
+		t.Errorf("Expected FAILED, got %s", res.Release.Info.Status.Code)
+	}
+
+	stored, err := rs.env.Releases.Last(rel.Name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stored.Version != 2 || stored.Info.Status.Code != release.Status_FAILED {
+		t.Errorf("Expected a FAILED revision 2 to be stored, got revision %d %s", stored.Version, stored.Info.Status.Code)
+	}
+}
+
+func TestPurgeExpiredReleases(t *testing.T) {
+	now := time.Now()
+	deletedAt := func(rel *release.Release, ago time.Duration) *release.Release {
+		rel.Info.Status.Code = release.Status_DELETED
+		rel.Info.Deleted = timeconv.Timestamp(now.Add(-ago))
+		return rel
+	}
+
+	rs := rsFixture()
+	expired := namedReleaseStub("expired", release.Status_DEPLOYED)
+	rs.env.Releases.Create(deletedAt(upgradeReleaseVersion(expired), 2*time.Hour))
+	rs.env.Releases.Create(expired)
+	rs.env.Releases.Create(deletedAt(namedReleaseStub("recent", release.Status_DELETED), 10*time.Minute))
+	rs.env.Releases.Create(namedReleaseStub("deployed", release.Status_DEPLOYED))
+
+	// Without a retention period, deleted releases are kept forever.
+	if purged, err := rs.PurgeExpiredReleases(); err != nil || len(purged) != 0 {
+		t.Fatalf("Expected nothing to be purged, got %v (%v)", purged, err)
+	}
+
+	rs.SetDeletedReleaseRetention(time.Hour)
+	purged, err := rs.PurgeExpiredReleases()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(purged, []string{"expired"}) {
+		t.Errorf("Expected only expired to be purged, got %v", purged)
+	}
+
+	if h, _ := rs.env.Releases.History("expired"); len(h) != 0 {
+		t.Errorf("Expected every revision of expired to be purged, %d left", len(h))
+	}
+	for _, name := range []string{"recent", "deployed"} {
+		if _, err := rs.env.Releases.Last(name); err != nil {
+			t.Errorf("Expected %s to be kept: %s", name, err)
+		}
+	}
+}
@@ -0,0 +1,345 @@
+/*
+Copyright 2017 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tiller
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"strings"
+
+	ctx "golang.org/x/net/context"
+
+	"k8s.io/helm/pkg/logging"
+	"k8s.io/helm/pkg/proto/hapi/release"
+	"k8s.io/helm/pkg/proto/hapi/services"
+	"k8s.io/helm/pkg/timeconv"
+)
+
+// What an upgrade does when some of its resources cannot be applied.
+const (
+	// onFailureKeep leaves the failed revision as it is, to be resumed.
+	onFailureKeep = "keep"
+	// onFailureRevert undoes what the failed operation applied.
+	onFailureRevert = "revert"
+)
+
+func validateOnFailure(onFailure string) error {
+	switch onFailure {
+	case "", onFailureKeep, onFailureRevert:
+		return nil
+	}
+	return fmt.Errorf("invalid on-failure action %q: must be %q or %q", onFailure, onFailureKeep, onFailureRevert)
+}
+
+// ResumeRelease applies the resources that the latest revision of a release
+// did not get to apply, if it failed while applying them. The resources it
+// did apply are left as they are.
+//
+// The resumed release is stored as a new revision, with the chart, values and
+// manifest of the failed one. If the resume fails as well, the new revision
+// records what it applied and can be resumed in turn. No hooks are run: the
+// pre hooks ran before the failure, and the post hooks of a failed operation
+// are not run after it.
+//
+// Identical concurrent resume requests are only performed once; see operationKey.
+func (s *ReleaseServer) ResumeRelease(c ctx.Context, req *services.ResumeReleaseRequest) (*services.ResumeReleaseResponse, error) {
+	res, err := s.dedupe("resume", req.Name, req, func() (interface{}, error) {
+		return s.resumeRelease(req)
+	})
+	resp, _ := res.(*services.ResumeReleaseResponse)
+	return resp, err
+}
+
+func (s *ReleaseServer) resumeRelease(req *services.ResumeReleaseRequest) (*services.ResumeReleaseResponse, error) {
+	if !ValidName.MatchString(req.Name) {
+		return nil, errMissingRelease
+	}
+
+	if err := s.lockRelease(req.Name); err != nil {
+		return nil, err
+	}
+	defer s.env.Releases.UnlockRelease(req.Name)
+
+	failed, err := s.env.Releases.Last(req.Name)
+	if err != nil {
+		return nil, err
+	}
+	if failed.Info.Status.Code != release.Status_FAILED {
+		return nil, fmt.Errorf("release %q cannot be resumed: its latest revision (v%d) is %s, not FAILED", req.Name, failed.Version, failed.Info.Status.Code)
+	}
+	if len(failed.Info.ResourceStatuses) == 0 {
+		return nil, fmt.Errorf("release %q cannot be resumed: revision %d did not fail while applying its resources", req.Name, failed.Version)
+	}
+	kc, err := s.releaseCluster(failed)
+	if err != nil {
+		return nil, err
+	}
+	applied, err := s.appliedManifest(failed)
+	if err != nil {
+		return nil, err
+	}
+
+	target := &release.Release{
+		Name:            failed.Name,
+		Namespace:       failed.Namespace,
+		Namespaces:      failed.Namespaces,
+		Chart:           failed.Chart,
+		Config:          failed.Config,
+		ComputedValues:  failed.ComputedValues,
+		GeneratedValues: failed.GeneratedValues,
+		Profile: failed.Profile,
+		AllowMissingProfile: failed.AllowMissingProfile,
+		Info: &release.Info{
+			FirstDeployed: failed.Info.FirstDeployed,
+			LastDeployed:  timeconv.Now(),
+			Status: &release.Status{
+				Code:  release.Status_UNKNOWN,
+				Notes: failed.Info.Status.Notes,
+			},
+			Description:         fmt.Sprintf("Resumed %d", failed.Version),
+			Annotations:         failed.Info.Annotations,
+			ValuesMutations:     failed.Info.ValuesMutations,
+			ContainerInjections: failed.Info.ContainerInjections,
+		},
+		Version:  failed.Version + 1,
+		Manifest: failed.Manifest,
+		Hooks:    failed.Hooks,
+		Cluster:  failed.Cluster,
+		System:   failed.System,
+	}
+	res := &services.ResumeReleaseResponse{Release: target}
+	log := s.requestLogger("resume", target.Name, target.Version)
+
+	// Updating from what is in the cluster leaves the applied resources
+	// unchanged, and applies the rest as the failed revision would have.
+	current := &release.Release{Name: failed.Name, Namespace: failed.Namespace, Manifest: applied}
+	updateReq := &services.UpdateReleaseRequest{
+		Wait:    req.Wait,
+		Timeout: req.Timeout,
+	}
+	err = kc.module.Update(current, target, updateReq, kc.env)
+
+	failed.Info.Status.Code = release.Status_SUPERSEDED
+	s.recordRelease(failed, true)
+	if err != nil {
+		msg := fmt.Sprintf("Resume %q failed: %s", target.Name, err)
+		log.Warnf("%s", msg)
+		target.Info.Status.Code = release.Status_FAILED
+		target.Info.Description = msg
+		recordResourceStatuses(target, err)
+		s.recordRelease(target, false)
+		return res, err
+	}
+
+	if req.Wait {
+		s.refreshNotes(log, target, nil, nil, false)
+	}
+
+	target.Info.Status.Code = release.Status_DEPLOYED
+	s.recordRelease(target, false)
+	log.Infof("Resumed %s from revision %d", target.Name, failed.Version)
+
+	return res, nil
+}
+
+// revertUpgrade undoes the failed upgrade: it returns the resources that
+// the upgrade applied, or created, to what the previous revision defines, and
+// records the result as a new revision of the previous chart, values and
+// manifest. An upgrade that applied every resource, and failed after, has
+// nothing to revert.
+func (s *ReleaseServer) revertUpgrade(log logging.Logger, failed *release.Release, req *services.UpdateReleaseRequest) error {
+	if len(failed.Info.ResourceStatuses) == 0 {
+		log.Infof("Upgrade of %s applied every resource; there is nothing to revert", failed.Name)
+		return nil
+	}
+	previous, err := s.env.Releases.Get(failed.Name, failed.Version-1)
+	if err != nil {
+		return err
+	}
+	kc, err := s.releaseCluster(failed)
+	if err != nil {
+		return err
+	}
+	applied, err := s.appliedManifest(failed)
+	if err != nil {
+		return err
+	}
+
+	target := &release.Release{
+		Name:                previous.Name,
+		Namespace:           previous.Namespace,
+		Namespaces:          previous.Namespaces,
+		Chart:               previous.Chart,
+		Config:              previous.Config,
+		ComputedValues:      previous.ComputedValues,
+		GeneratedValues:     previous.GeneratedValues,
+		Profile:             previous.Profile,
+		AllowMissingProfile: previous.AllowMissingProfile,
+		Info: &release.Info{
+			FirstDeployed: failed.Info.FirstDeployed,
+			LastDeployed:  timeconv.Now(),
+			Status: &release.Status{
+				Code:  release.Status_UNKNOWN,
+				Notes: previous.Info.Status.Notes,
+			},
+			Description:         fmt.Sprintf("Reverted upgrade %d", failed.Version),
+			Annotations:         previous.Info.Annotations,
+			ValuesMutations:     previous.Info.ValuesMutations,
+			ContainerInjections: previous.Info.ContainerInjections,
+			Flags:               previous.Info.Flags,
+		},
+		Version:  failed.Version + 1,
+		Manifest: previous.Manifest,
+		Hooks:    previous.Hooks,
+		Cluster:  previous.Cluster,
+		System:   previous.System,
+	}
+	// Updating from what is in the cluster reverts the resources that the
+	// upgrade applied, and deletes those it created.
+	current := &release.Release{Name: failed.Name, Namespace: failed.Namespace, Manifest: applied}
+	updateReq := &services.UpdateReleaseRequest{
+		Wait:              req.Wait,
+		Timeout:           req.Timeout,
+		GracePeriod:       req.GracePeriod,
+		PropagationPolicy: req.PropagationPolicy,
+	}
+	err = kc.module.Update(current, target, updateReq, kc.env)
+
+	failed.Info.Status.Code = release.Status_SUPERSEDED
+	s.recordRelease(failed, true)
+	if err != nil {
+		msg := fmt.Sprintf("Revert %q failed: %s", target.Name, err)
+		log.Warnf("%s", msg)
+		target.Info.Status.Code = release.Status_FAILED
+		target.Info.Description = msg
+		recordResourceStatuses(target, err)
+		s.recordRelease(target, false)
+		return err
+	}
+	target.Info.Status.Code = release.Status_DEPLOYED
+	s.recordRelease(target, false)
+	log.Infof("Reverted upgrade %d of %s", failed.Version, failed.Name)
+	return nil
+}
+
+// revertInstall undoes the failed install r by deleting the resources it
+// applied. r stays FAILED, but no longer records them, as there is nothing
+// left to resume. An install that applied every resource, and failed after,
+// has nothing to revert.
+func (s *ReleaseServer) revertInstall(log logging.Logger, kc *kubeCluster, r *release.Release, req *services.InstallReleaseRequest) error {
+	applied := appliedResources(r)
+	if applied == nil {
+		log.Infof("Install of %s applied every resource; there is nothing to revert", r.Name)
+		return nil
+	}
+	resources, err := manifestsByResource(r.Manifest)
+	if err != nil {
+		return err
+	}
+	keys := make([]string, 0, len(applied))
+	for key := range resources {
+		if applied[key] {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	b := bytes.NewBuffer(nil)
+	for _, key := range keys {
+		b.WriteString("\n---\n")
+		b.WriteString(resources[key].content)
+	}
+	created := &release.Release{Name: r.Name, Namespace: r.Namespace, Manifest: b.String()}
+	uninstallReq := &services.UninstallReleaseRequest{Name: r.Name, Timeout: req.Timeout}
+	if _, errs := kc.module.Delete(created, uninstallReq, kc.env); len(errs) > 0 {
+		es := make([]string, 0, len(errs))
+		for _, e := range errs {
+			es = append(es, e.Error())
+		}
+		return fmt.Errorf("%s", strings.Join(es, "; "))
+	}
+	r.Info.Description += "; reverted by deleting the resources it applied"
+	r.Info.ResourceStatuses = nil
+	s.recordRelease(r, true)
+	log.Infof("Reverted install of %s", r.Name)
+	return nil
+}
+
+// appliedManifest returns the manifest of the resources that are in the
+// cluster after rel failed to apply, as far as the release records tell.
+//
+// The resources that rel applied are as rel defines them. The rest are as the
+// revision before left them, which is worked out the same way if that
+// revision failed too, until a revision that applied all of its resources.
+// Resources are only deleted once everything was applied, so resources that
+// an earlier revision applied and rel dropped are still there. The resource
+// that failed to apply is assumed to be unchanged.
+func (s *ReleaseServer) appliedManifest(rel *release.Release) (string, error) {
+	seen := map[string]bool{}
+	merged := []manifest{}
+	for r := rel; r != nil && r.Info.Status.Code != release.Status_DELETED; {
+		resources, err := manifestsByResource(r.Manifest)
+		if err != nil {
+			return "", err
+		}
+		applied := appliedResources(r)
+		keys := make([]string, 0, len(resources))
+		for key := range resources {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			if seen[key] || (applied != nil && !applied[key]) {
+				continue
+			}
+			seen[key] = true
+			merged = append(merged, resources[key])
+		}
+		if applied == nil || r.Version <= 1 {
+			break
+		}
+		if r, err = s.env.Releases.Get(rel.Name, r.Version-1); err != nil {
+			break
+		}
+	}
+
+	// Resources of the same kind keep the order of their keys.
+	ks := newKindSorter(merged, InstallOrder)
+	sort.Stable(ks)
+	b := bytes.NewBuffer(nil)
+	for _, m := range ks.manifests {
+		b.WriteString("\n---\n")
+		b.WriteString(m.content)
+	}
+	return b.String(), nil
+}
+
+// appliedResources returns the "Kind/name" keys of the resources that r
+// applied, or nil if it recorded no per-resource status and so applied all of
+// them.
+func appliedResources(r *release.Release) map[string]bool {
+	if len(r.Info.ResourceStatuses) == 0 {
+		return nil
+	}
+	applied := map[string]bool{}
+	for _, st := range r.Info.ResourceStatuses {
+		if st.Code == release.ResourceStatus_APPLIED {
+			applied[st.Kind+"/"+st.Name] = true
+		}
+	}
+	return applied
+}
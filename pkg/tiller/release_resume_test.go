@@ -0,0 +1,333 @@
+/*
+Copyright 2017 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tiller
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"strings"
+	"testing"
+
+	"k8s.io/helm/pkg/helm"
+	"k8s.io/helm/pkg/kube"
+	"k8s.io/helm/pkg/proto/hapi/release"
+	"k8s.io/helm/pkg/proto/hapi/services"
+	"k8s.io/helm/pkg/tiller/environment"
+)
+
+// failOnceKubeClient fails the first update like applyFailingKubeClient,
+// and records the manifests of the updates after it.
+type failOnceKubeClient struct {
+	applyFailingKubeClient
+	calls            int
+	original, target string
+}
+
+func (f *failOnceKubeClient) UpdateWithOptions(namespace string, originalReader, targetReader io.Reader, opts kube.UpdateOptions) error {
+	f.calls++
+	if f.calls == 1 {
+		return f.applyFailingKubeClient.UpdateWithOptions(namespace, originalReader, targetReader, opts)
+	}
+	original, _ := ioutil.ReadAll(originalReader)
+	target, _ := ioutil.ReadAll(targetReader)
+	f.original, f.target = string(original), string(target)
+	return nil
+}
+
+// newFailOnceKubeClient returns a failOnceKubeClient that has already failed
+// if failed is true.
+func newFailOnceKubeClient(failed bool) *failOnceKubeClient {
+	f := &failOnceKubeClient{applyFailingKubeClient: applyFailingKubeClient{environment.PrintingKubeClient{Out: os.Stdout}}}
+	if failed {
+		f.calls = 1
+	}
+	return f
+}
+
+// partiallyFailedRelease stores a deployed first revision of a ConfigMap and
+// a Service, and a second revision that applied the ConfigMap but failed on
+// the Service.
+func partiallyFailedRelease(rs *ReleaseServer) (*release.Release, *release.Release) {
+	rel := releaseStub()
+	rel.Manifest = fmt.Sprintf(manifestPartialCM, 1) + fmt.Sprintf(manifestPartialSvc, 1)
+	rel.Info.Status.Code = release.Status_SUPERSEDED
+	rs.env.Releases.Create(rel)
+
+	failed := upgradeReleaseVersion(rel)
+	failed.Manifest = fmt.Sprintf(manifestPartialCM, 2) + fmt.Sprintf(manifestPartialSvc, 2)
+	failed.Info.Status.Code = release.Status_FAILED
+	failed.Info.ResourceStatuses = []*release.ResourceStatus{
+		{Kind: "ConfigMap", Name: "test-cm", Code: release.ResourceStatus_APPLIED},
+		{Kind: "Service", Name: "test-svc", Code: release.ResourceStatus_FAILED, Error: "field is immutable"},
+	}
+	rs.env.Releases.Create(failed)
+	return rel, failed
+}
+
+func TestAppliedManifest(t *testing.T) {
+	rs := rsFixture()
+	_, failed := partiallyFailedRelease(rs)
+
+	m, err := rs.appliedManifest(failed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(m, `revision: "2"`) || !strings.Contains(m, `clusterIP: "10.0.0.1"`) {
+		t.Errorf("Expected the applied ConfigMap and the previous Service, got %s", m)
+	}
+
+	// A third revision that only applied the Service leaves the ConfigMap as
+	// the second one applied it.
+	again := upgradeReleaseVersion(failed)
+	again.Manifest = fmt.Sprintf(manifestPartialCM, 3) + fmt.Sprintf(manifestPartialSvc, 3)
+	again.Info.Status.Code = release.Status_FAILED
+	again.Info.ResourceStatuses = []*release.ResourceStatus{
+		{Kind: "Service", Name: "test-svc", Code: release.ResourceStatus_APPLIED},
+		{Kind: "ConfigMap", Name: "test-cm", Code: release.ResourceStatus_FAILED},
+	}
+	rs.env.Releases.Create(again)
+
+	if m, err = rs.appliedManifest(again); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(m, `revision: "2"`) || !strings.Contains(m, `clusterIP: "10.0.0.3"`) {
+		t.Errorf("Expected the ConfigMap of revision 2 and the Service of revision 3, got %s", m)
+	}
+	if strings.Index(m, "kind: Service") < strings.Index(m, "kind: ConfigMap") {
+		t.Errorf("Expected the resources in install order, got %s", m)
+	}
+}
+
+func TestResumeRelease(t *testing.T) {
+	c := helm.NewContext()
+	rs := rsFixture()
+	_, failed := partiallyFailedRelease(rs)
+	kubeClient := newFailOnceKubeClient(true)
+	rs.env.KubeClient = kubeClient
+
+	res, err := rs.ResumeRelease(c, &services.ResumeReleaseRequest{Name: failed.Name})
+	if err != nil {
+		t.Fatalf("Failed resume: %s", err)
+	}
+
+	if res.Release.Version != 3 || res.Release.Info.Status.Code != release.Status_DEPLOYED {
+		t.Errorf("Expected a DEPLOYED revision 3, got %s revision %d", res.Release.Info.Status.Code, res.Release.Version)
+	}
+	if res.Release.Info.Description != "Resumed 2" {
+		t.Errorf("Unexpected description %q", res.Release.Info.Description)
+	}
+	// The update starts from what is in the cluster, so that only the
+	// Service is changed.
+	if !strings.Contains(kubeClient.original, `revision: "2"`) || !strings.Contains(kubeClient.original, `clusterIP: "10.0.0.1"`) {
+		t.Errorf("Expected the update to start from the applied resources, got %s", kubeClient.original)
+	}
+	if kubeClient.target != failed.Manifest {
+		t.Errorf("Expected the manifest of the failed revision, got %s", kubeClient.target)
+	}
+
+	old, err := rs.env.Releases.Get(failed.Name, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if old.Info.Status.Code != release.Status_SUPERSEDED {
+		t.Errorf("Expected the failed revision to be SUPERSEDED, got %s", old.Info.Status.Code)
+	}
+}
+
+func TestResumeRelease_Failure(t *testing.T) {
+	c := helm.NewContext()
+	rs := rsFixture()
+	_, failed := partiallyFailedRelease(rs)
+	rs.env.KubeClient = &applyFailingKubeClient{environment.PrintingKubeClient{Out: os.Stdout}}
+
+	res, err := rs.ResumeRelease(c, &services.ResumeReleaseRequest{Name: failed.Name})
+	if err == nil {
+		t.Fatal("Expected the resume to fail")
+	}
+	if res.Release.Info.Status.Code != release.Status_FAILED || len(res.Release.Info.ResourceStatuses) != 2 {
+		t.Errorf("Expected a FAILED revision that records what it applied, got %v", res.Release.Info)
+	}
+	// The failed resume can be resumed in turn.
+	rs.env.KubeClient = newFailOnceKubeClient(true)
+	if res, err = rs.ResumeRelease(c, &services.ResumeReleaseRequest{Name: failed.Name}); err != nil {
+		t.Fatalf("Failed resume: %s", err)
+	}
+	if res.Release.Version != 4 {
+		t.Errorf("Expected revision 4, got %d", res.Release.Version)
+	}
+}
+
+func TestResumeRelease_NotResumable(t *testing.T) {
+	c := helm.NewContext()
+	rs := rsFixture()
+	rel := releaseStub()
+	rs.env.Releases.Create(rel)
+
+	_, err := rs.ResumeRelease(c, &services.ResumeReleaseRequest{Name: rel.Name})
+	if err == nil || !strings.Contains(err.Error(), "is DEPLOYED, not FAILED") {
+		t.Errorf("Expected a deployed release not to be resumable, got %v", err)
+	}
+
+	// A revision that failed after applying everything has nothing to resume.
+	failed := upgradeReleaseVersion(rel)
+	failed.Info.Status.Code = release.Status_FAILED
+	rs.env.Releases.Create(failed)
+	_, err = rs.ResumeRelease(c, &services.ResumeReleaseRequest{Name: rel.Name})
+	if err == nil || !strings.Contains(err.Error(), "did not fail while applying its resources") {
+		t.Errorf("Expected a release without resource statuses not to be resumable, got %v", err)
+	}
+}
+
+func TestUpdateReleaseOnFailureRevert(t *testing.T) {
+	rs := rsFixture()
+	rel := releaseStub()
+	rel.Manifest = fmt.Sprintf(manifestPartialCM, 1) + fmt.Sprintf(manifestPartialSvc, 1)
+	rs.env.Releases.Create(rel)
+	kubeClient := newFailOnceKubeClient(false)
+	rs.env.KubeClient = kubeClient
+
+	upgradedRel := upgradeReleaseVersion(rel)
+	upgradedRel.Manifest = fmt.Sprintf(manifestPartialCM, 2) + fmt.Sprintf(manifestPartialSvc, 2)
+	req := &services.UpdateReleaseRequest{Name: rel.Name, OnFailure: onFailureRevert}
+	if _, err := rs.performUpdate(rs.requestLogger("upgrade", upgradedRel.Name, upgradedRel.Version), rel, upgradedRel, nil, req); err == nil {
+		t.Fatal("Expected the upgrade to fail")
+	}
+
+	reverted, err := rs.env.Releases.Get(rel.Name, 3)
+	if err != nil {
+		t.Fatalf("Expected the revert to be recorded: %s", err)
+	}
+	if reverted.Info.Status.Code != release.Status_DEPLOYED {
+		t.Errorf("Expected the revert to be DEPLOYED, got %s", reverted.Info.Status.Code)
+	}
+	if reverted.Info.Description != "Reverted upgrade 2" {
+		t.Errorf("Unexpected description %q", reverted.Info.Description)
+	}
+	// Both the applied ConfigMap and the Service go back to revision 1.
+	if reverted.Manifest != rel.Manifest {
+		t.Errorf("Expected the previous manifest, got %s", reverted.Manifest)
+	}
+	if kubeClient.target != reverted.Manifest {
+		t.Errorf("Expected the cluster to be updated to the reverted manifest, got %s", kubeClient.target)
+	}
+	if failed, _ := rs.env.Releases.Get(rel.Name, 2); failed.Info.Status.Code != release.Status_SUPERSEDED {
+		t.Errorf("Expected the failed upgrade to be SUPERSEDED, got %s", failed.Info.Status.Code)
+	}
+}
+
+func TestUpdateReleaseInvalidOnFailure(t *testing.T) {
+	c := helm.NewContext()
+	rs := rsFixture()
+	rel := releaseStub()
+	rs.env.Releases.Create(rel)
+
+	req := &services.UpdateReleaseRequest{Name: rel.Name, Chart: rel.Chart, OnFailure: "discard"}
+	if _, err := rs.UpdateRelease(c, req); err == nil || !strings.Contains(err.Error(), `invalid on-failure action "discard"`) {
+		t.Errorf("Expected an invalid on-failure action to be rejected, got %v", err)
+	}
+}
+
+func TestInstallReleaseRecordsResourceStatuses(t *testing.T) {
+	c := helm.NewContext()
+	rs := rsFixture()
+	rs.env.KubeClient = &createFailingKubeClient{environment.PrintingKubeClient{Out: os.Stdout}}
+
+	res, err := rs.InstallRelease(c, &services.InstallReleaseRequest{Chart: chartStub()})
+	if err == nil {
+		t.Fatal("Expected the install to fail")
+	}
+	if sts := res.Release.Info.ResourceStatuses; len(sts) != 1 || sts[0].Code != release.ResourceStatus_FAILED {
+		t.Errorf("Expected the failed resource to be recorded, got %v", sts)
+	}
+}
+
+func TestInstallReleaseOnFailureRevert(t *testing.T) {
+	rs := rsFixture()
+	kubeClient := &partialCreateKubeClient{PrintingKubeClient: environment.PrintingKubeClient{Out: os.Stdout}}
+	rs.env.KubeClient = kubeClient
+
+	rel := releaseStub()
+	rel.Manifest = fmt.Sprintf(manifestPartialCM, 1) + fmt.Sprintf(manifestPartialSvc, 1)
+	req := &services.InstallReleaseRequest{Name: rel.Name, OnFailure: onFailureRevert}
+	if _, err := rs.performRelease(rs.requestLogger("install", rel.Name, rel.Version), rel, nil, req); err == nil {
+		t.Fatal("Expected the install to fail")
+	}
+
+	failed, err := rs.env.Releases.Get(rel.Name, rel.Version)
+	if err != nil {
+		t.Fatalf("Expected the install to be recorded: %s", err)
+	}
+	if failed.Info.Status.Code != release.Status_FAILED {
+		t.Errorf("Expected the install to be FAILED, got %s", failed.Info.Status.Code)
+	}
+	if !strings.HasSuffix(failed.Info.Description, "; reverted by deleting the resources it applied") {
+		t.Errorf("Unexpected description %q", failed.Info.Description)
+	}
+	if len(failed.Info.ResourceStatuses) != 0 {
+		t.Errorf("Expected a reverted install not to be resumable, got %v", failed.Info.ResourceStatuses)
+	}
+	// Only the ConfigMap was created, so only the ConfigMap is deleted.
+	if !strings.Contains(kubeClient.deleted, "name: test-cm") || strings.Contains(kubeClient.deleted, "name: test-svc") {
+		t.Errorf("Expected only the applied ConfigMap to be deleted, got %s", kubeClient.deleted)
+	}
+}
+
+func TestInstallReleaseInvalidOnFailure(t *testing.T) {
+	c := helm.NewContext()
+	rs := rsFixture()
+
+	req := &services.InstallReleaseRequest{Chart: chartStub(), OnFailure: "discard"}
+	if _, err := rs.InstallRelease(c, req); err == nil || !strings.Contains(err.Error(), `invalid on-failure action "discard"`) {
+		t.Errorf("Expected an invalid on-failure action to be rejected, got %v", err)
+	}
+}
+
+// partialCreateKubeClient creates the ConfigMap of the partial manifests but
+// fails on the Service, and records what it is asked to delete.
+type partialCreateKubeClient struct {
+	environment.PrintingKubeClient
+	deleted string
+}
+
+func (c *partialCreateKubeClient) Create(ns string, r io.Reader, timeout int64, shouldWait bool) error {
+	err := errors.New("quota exceeded")
+	return &kube.ApplyError{
+		Statuses: []kube.ApplyStatus{
+			{Kind: "ConfigMap", Name: "test-cm"},
+			{Kind: "Service", Name: "test-svc", Err: err},
+		},
+		Err: err,
+	}
+}
+
+func (c *partialCreateKubeClient) DeleteWithPolicy(ns string, r io.Reader, policy string, timeout int64, shouldWait bool) error {
+	b, _ := ioutil.ReadAll(r)
+	c.deleted += string(b)
+	return nil
+}
+
+type createFailingKubeClient struct {
+	environment.PrintingKubeClient
+}
+
+func (c *createFailingKubeClient) Create(ns string, r io.Reader, timeout int64, shouldWait bool) error {
+	err := errors.New("quota exceeded")
+	return &kube.ApplyError{Statuses: []kube.ApplyStatus{{Kind: "ConfigMap", Name: "test-cm", Err: err}}, Err: err}
+}
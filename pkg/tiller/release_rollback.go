@@ -17,17 +17,37 @@ limitations under the License.
 package tiller
 
 import (
+	"errors"
 	"fmt"
+	"strings"
+
 	ctx "golang.org/x/net/context"
+
+	"k8s.io/helm/pkg/chartutil"
 	"k8s.io/helm/pkg/hooks"
+	"k8s.io/helm/pkg/kube"
+	"k8s.io/helm/pkg/logging"
+	"k8s.io/helm/pkg/proto/hapi/chart"
 	"k8s.io/helm/pkg/proto/hapi/release"
 	"k8s.io/helm/pkg/proto/hapi/services"
 	"k8s.io/helm/pkg/timeconv"
 )
 
 // RollbackRelease rolls back to a previous version of the given release.
+//
+// Identical concurrent rollback requests are only performed once; see operationKey.
 func (s *ReleaseServer) RollbackRelease(c ctx.Context, req *services.RollbackReleaseRequest) (*services.RollbackReleaseResponse, error) {
-	err := s.env.Releases.LockRelease(req.Name)
+	done := observeOperation("rollback")
+	res, err := s.dedupe("rollback", req.Name, req, func() (interface{}, error) {
+		return s.rollbackRelease(req)
+	})
+	done(err)
+	resp, _ := res.(*services.RollbackReleaseResponse)
+	return resp, err
+}
+
+func (s *ReleaseServer) rollbackRelease(req *services.RollbackReleaseRequest) (*services.RollbackReleaseResponse, error) {
+	err := s.lockRelease(req.Name)
 	if err != nil {
 		return nil, err
 	}
@@ -38,7 +58,8 @@ func (s *ReleaseServer) RollbackRelease(c ctx.Context, req *services.RollbackRel
 		return nil, err
 	}
 
-	res, err := s.performRollback(currentRelease, targetRelease, req)
+	log := s.requestLogger("rollback", targetRelease.Name, targetRelease.Version)
+	res, err := s.performRollback(log, currentRelease, targetRelease, req)
 	if err != nil {
 		return res, err
 	}
@@ -60,6 +81,18 @@ func (s *ReleaseServer) prepareRollback(req *services.RollbackReleaseRequest) (*
 		return nil, nil, errMissingRelease
 	case req.Version < 0:
 		return nil, nil, errInvalidRevision
+	case !req.ReRender && req.Values != nil && req.Values.Raw != "":
+		return nil, nil, errors.New("values can only be overridden when the rollback re-renders the chart")
+	case req.ReRender && req.Partial:
+		return nil, nil, errors.New("a partial rollback cannot re-render the chart")
+	case req.Version != 0 && req.DeployedAt != nil:
+		return nil, nil, errors.New("a rollback can target a revision or the time it was deployed, not both")
+	case req.FromSnapshot && (req.Partial || req.ReRender):
+		return nil, nil, errors.New("a rollback from a snapshot can neither be partial nor re-render the chart")
+	}
+	opts := kube.UpdateOptions{GracePeriod: req.GracePeriod, PropagationPolicy: req.PropagationPolicy}
+	if err := opts.Validate(); err != nil {
+		return nil, nil, err
 	}
 
 	crls, err := s.env.Releases.Last(req.Name)
@@ -68,7 +101,12 @@ func (s *ReleaseServer) prepareRollback(req *services.RollbackReleaseRequest) (*
 	}
 
 	rbv := req.Version
-	if req.Version == 0 {
+	switch {
+	case req.DeployedAt != nil:
+		if rbv, err = s.revisionDeployedAt(crls, req.DeployedAt); err != nil {
+			return nil, nil, err
+		}
+	case req.Version == 0:
 		rbv = crls.Version - 1
 	}
 
@@ -81,10 +119,14 @@ func (s *ReleaseServer) prepareRollback(req *services.RollbackReleaseRequest) (*
 
 	// Store a new release object with previous release's configuration
 	target := &release.Release{
-		Name:      req.Name,
-		Namespace: crls.Namespace,
-		Chart:     prls.Chart,
-		Config:    prls.Config,
+		Name:            req.Name,
+		Namespace:       crls.Namespace,
+		Chart:           prls.Chart,
+		Config:          prls.Config,
+		ComputedValues:  prls.ComputedValues,
+		GeneratedValues: prls.GeneratedValues,
+		Profile: prls.Profile,
+		AllowMissingProfile: prls.AllowMissingProfile,
 		Info: &release.Info{
 			FirstDeployed: crls.Info.FirstDeployed,
 			LastDeployed:  timeconv.Now(),
@@ -94,53 +136,201 @@ func (s *ReleaseServer) prepareRollback(req *services.RollbackReleaseRequest) (*
 			},
 			// Because we lose the reference to rbv elsewhere, we set the
 			// message here, and only override it later if we experience failure.
-			Description: fmt.Sprintf("Rollback to %d", rbv),
+			Description:         fmt.Sprintf("Rollback to %d", rbv),
+			ValuesMutations:     prls.Info.ValuesMutations,
+			ContainerInjections: prls.Info.ContainerInjections,
+			Impersonation:       req.Impersonation,
+			Flags:               prls.Info.Flags,
 		},
 		Version:  crls.Version + 1,
 		Manifest: prls.Manifest,
 		Hooks:    prls.Hooks,
+		Cluster:  crls.Cluster,
+		System:   crls.System,
+	}
+
+	if req.FromSnapshot {
+		if prls.Snapshot == nil {
+			return nil, nil, fmt.Errorf("revision %d of %s has no snapshot", rbv, req.Name)
+		}
+		target.Manifest = prls.Snapshot.Manifest
+		target.Info.Description = fmt.Sprintf("Rollback to the snapshot of %d", rbv)
+	}
+
+	if req.Partial {
+		m, err := partialRollbackManifest(crls, prls)
+		if err != nil {
+			return nil, nil, err
+		}
+		target.Manifest = m
+		target.Info.Description = fmt.Sprintf("Partial rollback to %d", rbv)
+	}
+
+	if req.ReRender {
+		if err := s.rerenderRollback(crls, prls, target, req.Values); err != nil {
+			return nil, nil, err
+		}
+		target.Info.Description = fmt.Sprintf("Rollback to %d, re-rendered", rbv)
+		if req.Values != nil && req.Values.Raw != "" {
+			target.Info.Description = fmt.Sprintf("Rollback to %d with overridden values", rbv)
+		}
 	}
 
+	if err := s.setNamespaces(target); err != nil {
+		return nil, nil, err
+	}
 	return crls, target, nil
 }
 
-func (s *ReleaseServer) performRollback(currentRelease, targetRelease *release.Release, req *services.RollbackReleaseRequest) (*services.RollbackReleaseResponse, error) {
+// rerenderRollback renders the chart of prls into target again, with the
+// values of prls overridden by values. The templates see the rollback as an
+// upgrade of crls, and target records the values it was rendered with.
+func (s *ReleaseServer) rerenderRollback(crls, prls, target *release.Release, values *chart.Config) error {
+	merged, err := chartutil.OverrideValues(prls.Config, values)
+	if err != nil {
+		return fmt.Errorf("overriding the values of %s (v%d): %s", prls.Name, prls.Version, err)
+	}
+	if err := s.valuesLimits.Check(prls.Chart, merged); err != nil {
+		return err
+	}
+	previous, err := renderedValues(crls)
+	if err != nil {
+		return fmt.Errorf("reading the values of %s (v%d): %s", crls.Name, crls.Version, err)
+	}
+
+	options := chartutil.ReleaseOptions{
+		Name:           target.Name,
+		Time:           target.Info.LastDeployed,
+		Namespace:      crls.Namespace,
+		IsUpgrade:      true,
+		Revision:       int(target.Version),
+		PreviousValues: previous,
+		Flags:          target.Info.Flags,
+	}
+	kc, err := s.releaseCluster(crls)
+	if err != nil {
+		return err
+	}
+	caps, err := s.clusterCapabilities(crls.Cluster, kc)
+	if err != nil {
+		return err
+	}
+	vals := merged
+	if prls.Profile != "" {
+		if vals, err = chartutil.ApplyProfile(prls.Chart, prls.Profile, merged, prls.AllowMissingProfile); err != nil {
+			return err
+		}
+	}
+	valuesToRender, err := chartutil.ToRenderValuesCaps(prls.Chart, vals, options, caps)
+	if err != nil {
+		return err
+	}
+	mutations, err := s.mutateValues(prls.Chart, options, valuesToRender)
+	if err != nil {
+		return err
+	}
+	generated := generatedValues(prls)
+	hooks, manifestDoc, notesTxt, injections, err := s.renderResources(prls.Chart, valuesToRender, caps.APIVersions, generated)
+	if err != nil {
+		return err
+	}
+	computed, err := s.computedValues(valuesToRender)
+	if err != nil {
+		return err
+	}
+	if err := validateManifest(kc.env.KubeClient, crls.Namespace, manifestDoc.Bytes()); err != nil {
+		return err
+	}
+
+	target.Config = merged
+	target.ComputedValues = computed
+	target.GeneratedValues = generated
+	target.Manifest = manifestDoc.String()
+	target.Hooks = hooks
+	target.Info.Status.Notes = notesTxt
+	target.Info.ValuesMutations = mutations
+	target.Info.ContainerInjections = injections
+	return nil
+}
+
+func (s *ReleaseServer) performRollback(log logging.Logger, currentRelease, targetRelease *release.Release, req *services.RollbackReleaseRequest) (*services.RollbackReleaseResponse, error) {
 	res := &services.RollbackReleaseResponse{Release: targetRelease}
+	kc, err := s.actingCluster(targetRelease)
+	if err != nil {
+		return res, err
+	}
+
+	skip := newHookSkipList(req.SkipHooks, req.SkipHookWeights)
+	// The hooks of the target revision run again; those that destroy data
+	// could do more damage than the failure the rollback is meant to fix.
+	skip.destructive = !req.AllowDestructiveHooks
+	runHooks := s.runHooks(log, "rollback", req.DisableHooks, req.EnableHooks)
+	if runHooks {
+		res.SkippedDestructiveHooks = skip.skippedDestructive(targetRelease.Hooks, hooks.PreRollback, hooks.PostRollback)
+	}
 
 	if req.DryRun {
-		s.Log("Dry run for %s", targetRelease.Name)
+		log.Infof("Dry run for %s", targetRelease.Name)
+		if runHooks {
+			res.Hooks = previewHooks(targetRelease.Hooks, skip, hooks.PreRollback, hooks.PostRollback)
+			if !req.SkipHookLookup {
+				lookUpReplacedHooks(log, kc.env.KubeClient, targetRelease.Namespace, targetRelease.Hooks, res.Hooks)
+			}
+		}
 		return res, nil
 	}
 
+	budget := newTimeoutBudget(req.TimeoutBudget, req.Timeout)
+
 	// pre-rollback hooks
-	if !req.DisableHooks {
-		if err := s.execHook(targetRelease.Hooks, targetRelease.Name, targetRelease.Namespace, hooks.PreRollback, req.Timeout); err != nil {
+	if runHooks {
+		if err := s.execHookWithin(log, kc.env.KubeClient, targetRelease, hooks.PreRollback, budget, skip); err != nil {
 			return res, err
 		}
 	}
 
-	if err := s.ReleaseModule.Rollback(currentRelease, targetRelease, req, s.env); err != nil {
+	timeout, err := budget.start("rollback of the resources")
+	if err != nil {
+		return res, err
+	}
+	rollbackReq := *req
+	rollbackReq.Timeout = timeout
+	if err := kc.module.Rollback(currentRelease, targetRelease, &rollbackReq, kc.env); err != nil {
+		err = budget.exhausted(err)
 		msg := fmt.Sprintf("Rollback %q failed: %s", targetRelease.Name, err)
-		s.Log("warning: %s", msg)
+		log.Warnf("%s", msg)
 		currentRelease.Info.Status.Code = release.Status_SUPERSEDED
 		targetRelease.Info.Status.Code = release.Status_FAILED
 		targetRelease.Info.Description = msg
+		recordResourceStatuses(targetRelease, err)
 		s.recordRelease(currentRelease, true)
 		s.recordRelease(targetRelease, false)
 		return res, err
 	}
 
+	s.afterApply(log, targetRelease.Cluster, kc.env.KubeClient, targetRelease.Manifest)
+
 	// post-rollback hooks
-	if !req.DisableHooks {
-		if err := s.execHook(targetRelease.Hooks, targetRelease.Name, targetRelease.Namespace, hooks.PostRollback, req.Timeout); err != nil {
+	if runHooks {
+		if err := s.execHookWithin(log, kc.env.KubeClient, targetRelease, hooks.PostRollback, budget, skip); err != nil {
 			return res, err
 		}
 	}
 
+	// The manifests of the revision being rolled back to are reused as they
+	// are, but the notes are re-rendered once there are addresses to show.
+	// Templates see the rollback as an upgrade to the new revision.
+	if req.Wait {
+		s.refreshNotes(log, targetRelease, currentRelease, nil, false)
+	}
+
 	currentRelease.Info.Status.Code = release.Status_SUPERSEDED
 	s.recordRelease(currentRelease, true)
 
 	targetRelease.Info.Status.Code = release.Status_DEPLOYED
+	if len(res.SkippedDestructiveHooks) > 0 {
+		targetRelease.Info.Description += fmt.Sprintf(" (skipped destructive hooks: %s)", strings.Join(res.SkippedDestructiveHooks, ", "))
+	}
 
 	return res, nil
 }
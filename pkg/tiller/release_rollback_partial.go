@@ -0,0 +1,128 @@
+/*
+Copyright 2017 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tiller
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/ghodss/yaml"
+
+	"k8s.io/helm/pkg/kube"
+	"k8s.io/helm/pkg/proto/hapi/release"
+	relutil "k8s.io/helm/pkg/releaseutil"
+)
+
+// recordResourceStatuses stores the per-resource outcome of a failed apply on
+// the release, so that a later partial rollback knows which resources to revert.
+//
+// Only errors that carry per-resource information (kube.ApplyError) are
+// recorded. Anything else leaves the release untouched.
+func recordResourceStatuses(r *release.Release, err error) {
+	ae, ok := err.(*kube.ApplyError)
+	if !ok {
+		return
+	}
+	r.Info.ResourceStatuses = make([]*release.ResourceStatus, 0, len(ae.Statuses))
+	for _, st := range ae.Statuses {
+		rs := &release.ResourceStatus{
+			Kind:      st.Kind,
+			Name:      st.Name,
+			Code:      release.ResourceStatus_APPLIED,
+			Recreated: st.Recreated,
+		}
+		if st.Err != nil {
+			rs.Code = release.ResourceStatus_FAILED
+			rs.Error = st.Err.Error()
+		}
+		r.Info.ResourceStatuses = append(r.Info.ResourceStatuses, rs)
+	}
+}
+
+// partialRollbackManifest builds the manifest for rolling the failed release
+// current back to previous, reverting only what current did not apply.
+//
+// Resources that current applied successfully are kept as current defines
+// them. Resources that failed to apply, that current never got to, or that
+// only exist in previous are taken from previous. Resources that only exist in
+// current and were not applied are dropped.
+//
+// The resulting manifest is a mix of both revisions and matches neither chart
+// exactly; the rolled back release records previous's chart and values.
+func partialRollbackManifest(current, previous *release.Release) (string, error) {
+	if current.Info.Status.Code != release.Status_FAILED {
+		return "", fmt.Errorf("partial rollback requires a failed release, but %s (v%d) is %s", current.Name, current.Version, current.Info.Status.Code)
+	}
+	if len(current.Info.ResourceStatuses) == 0 {
+		return "", fmt.Errorf("partial rollback requires per-resource status, but none was recorded for %s (v%d)", current.Name, current.Version)
+	}
+
+	applied := map[string]bool{}
+	for _, st := range current.Info.ResourceStatuses {
+		if st.Code == release.ResourceStatus_APPLIED {
+			applied[st.Kind+"/"+st.Name] = true
+		}
+	}
+
+	cur, err := manifestsByResource(current.Manifest)
+	if err != nil {
+		return "", err
+	}
+	prev, err := manifestsByResource(previous.Manifest)
+	if err != nil {
+		return "", err
+	}
+
+	merged := []manifest{}
+	for key, m := range cur {
+		if applied[key] {
+			merged = append(merged, m)
+		}
+	}
+	for key, m := range prev {
+		if !applied[key] {
+			merged = append(merged, m)
+		}
+	}
+
+	b := bytes.NewBuffer(nil)
+	for _, m := range sortByKind(merged, InstallOrder) {
+		b.WriteString("\n---\n")
+		b.WriteString(m.content)
+	}
+	return b.String(), nil
+}
+
+// manifestsByResource splits a release manifest into its documents, keyed by
+// "Kind/name".
+func manifestsByResource(m string) (map[string]manifest, error) {
+	res := map[string]manifest{}
+	for n, c := range relutil.SplitManifests(m) {
+		var sh relutil.SimpleHead
+		if err := yaml.Unmarshal([]byte(c), &sh); err != nil {
+			if src := relutil.ManifestSource(c); src != "" {
+				n = src
+			}
+			return nil, fmt.Errorf("YAML parse error on %s: %s", n, err)
+		}
+		if sh.Metadata == nil {
+			continue
+		}
+		res[sh.Kind+"/"+sh.Metadata.Name] = manifest{name: n, content: c, head: &sh}
+	}
+	return res, nil
+}
@@ -17,11 +17,23 @@ limitations under the License.
 package tiller
 
 import (
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+
+	"k8s.io/kubernetes/pkg/api"
+
 	"k8s.io/helm/pkg/helm"
+	"k8s.io/helm/pkg/kube"
+	"k8s.io/helm/pkg/proto/hapi/chart"
 	"k8s.io/helm/pkg/proto/hapi/release"
 	"k8s.io/helm/pkg/proto/hapi/services"
-	"strings"
-	"testing"
+	"k8s.io/helm/pkg/tiller/environment"
 )
 
 func TestRollbackRelease(t *testing.T) {
@@ -135,6 +147,74 @@ func TestRollbackRelease(t *testing.T) {
 	}
 }
 
+func TestRollbackReleaseSkipsDestructiveHooks(t *testing.T) {
+	c := helm.NewContext()
+	for _, allow := range []bool{false, true} {
+		rs := rsFixture()
+		kc := &hookOrderKubeClient{PrintingKubeClient: environment.PrintingKubeClient{Out: ioutil.Discard}}
+		rs.env.KubeClient = kc
+		rel := releaseStub()
+		rel.Hooks = []*release.Hook{
+			destructiveHook("wipe-cache", "true", release.Hook_PRE_ROLLBACK),
+			destructiveHook("notify", "", release.Hook_POST_ROLLBACK),
+		}
+		rs.env.Releases.Create(rel)
+		upgradedRel := upgradeReleaseVersion(rel)
+		rs.env.Releases.Update(rel)
+		rs.env.Releases.Create(upgradedRel)
+
+		res, err := rs.RollbackRelease(c, &services.RollbackReleaseRequest{
+			Name:                  rel.Name,
+			AllowDestructiveHooks: allow,
+		})
+		if err != nil {
+			t.Fatalf("allow=%t: failed rollback: %s", allow, err)
+		}
+		wipe := res.Release.Hooks[0]
+		if allow {
+			if len(kc.calls) != 2 || wipe.LastRun == nil || len(res.SkippedDestructiveHooks) != 0 {
+				t.Errorf("allow=%t: expected both hooks to run, got %v", allow, kc.calls)
+			}
+			continue
+		}
+		if len(kc.calls) != 1 || wipe.LastRun != nil || wipe.LastSkipped == nil {
+			t.Errorf("allow=%t: expected only the hook that is not destructive to run, got %v", allow, kc.calls)
+		}
+		if !reflect.DeepEqual(res.SkippedDestructiveHooks, []string{"wipe-cache"}) {
+			t.Errorf("allow=%t: expected wipe-cache to be reported, got %v", allow, res.SkippedDestructiveHooks)
+		}
+		if expect := "Rollback to 1 (skipped destructive hooks: wipe-cache)"; res.Release.Info.Description != expect {
+			t.Errorf("allow=%t: expected description %q, got %q", allow, expect, res.Release.Info.Description)
+		}
+	}
+}
+
+func TestRollbackReleaseDryRunMarksDestructiveHooks(t *testing.T) {
+	c := helm.NewContext()
+	rs := rsFixture()
+	rel := releaseStub()
+	rel.Hooks = []*release.Hook{destructiveHook("wipe-cache", "true", release.Hook_PRE_ROLLBACK)}
+	rs.env.Releases.Create(rel)
+	upgradedRel := upgradeReleaseVersion(rel)
+	rs.env.Releases.Update(rel)
+	rs.env.Releases.Create(upgradedRel)
+
+	res, err := rs.RollbackRelease(c, &services.RollbackReleaseRequest{
+		Name:           rel.Name,
+		DryRun:         true,
+		SkipHookLookup: true,
+	})
+	if err != nil {
+		t.Fatalf("Failed rollback: %s", err)
+	}
+	if len(res.Hooks) != 1 || !res.Hooks[0].Destructive || !res.Hooks[0].Skipped {
+		t.Errorf("Expected the destructive hook to be previewed as skipped, got %v", res.Hooks)
+	}
+	if !reflect.DeepEqual(res.SkippedDestructiveHooks, []string{"wipe-cache"}) {
+		t.Errorf("Expected wipe-cache to be reported, got %v", res.SkippedDestructiveHooks)
+	}
+}
+
 func TestRollbackWithReleaseVersion(t *testing.T) {
 	c := helm.NewContext()
 	rs := rsFixture()
@@ -224,3 +304,386 @@ func TestRollbackReleaseFailure(t *testing.T) {
 		t.Errorf("Expected SUPERSEDED status on previous Release version. Got %v", oldStatus)
 	}
 }
+
+var manifestPartialCM = `
+---
+# Source: hello/templates/cm.yaml
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: test-cm
+data:
+  revision: "%d"`
+
+var manifestPartialSvc = `
+---
+# Source: hello/templates/svc.yaml
+apiVersion: v1
+kind: Service
+metadata:
+  name: test-svc
+spec:
+  clusterIP: "10.0.0.%d"`
+
+type applyFailingKubeClient struct {
+	environment.PrintingKubeClient
+}
+
+func (a *applyFailingKubeClient) UpdateWithOptions(namespace string, originalReader, modifiedReader io.Reader, opts kube.UpdateOptions) error {
+	return &kube.ApplyError{
+		Statuses: []kube.ApplyStatus{
+			{Kind: "ConfigMap", Name: "test-cm"},
+			{Kind: "Service", Name: "test-svc", Err: errors.New("field is immutable")},
+		},
+		Err: errors.New("field is immutable"),
+	}
+}
+
+func TestRollbackReleasePartial(t *testing.T) {
+	c := helm.NewContext()
+	rs := rsFixture()
+	rel := releaseStub()
+	rel.Manifest = fmt.Sprintf(manifestPartialCM, 1) + fmt.Sprintf(manifestPartialSvc, 1)
+	rs.env.Releases.Create(rel)
+
+	// A failing upgrade records the per-resource outcome.
+	rs.env.KubeClient = &applyFailingKubeClient{environment.PrintingKubeClient{Out: os.Stdout}}
+	upgradedRel := upgradeReleaseVersion(rel)
+	upgradedRel.Manifest = fmt.Sprintf(manifestPartialCM, 2) + fmt.Sprintf(manifestPartialSvc, 2)
+	if _, err := rs.performUpdate(rs.requestLogger("upgrade", upgradedRel.Name, upgradedRel.Version), rel, upgradedRel, nil, &services.UpdateReleaseRequest{Name: rel.Name}); err == nil {
+		t.Fatalf("Expected upgrade to fail")
+	}
+	if len(upgradedRel.Info.ResourceStatuses) != 2 {
+		t.Fatalf("Expected 2 resource statuses, got %v", upgradedRel.Info.ResourceStatuses)
+	}
+	if st := upgradedRel.Info.ResourceStatuses[1]; st.Code != release.ResourceStatus_FAILED || st.Error != "field is immutable" {
+		t.Errorf("Expected the Service to be recorded as failed, got %v", st)
+	}
+
+	rs.env.KubeClient = &environment.PrintingKubeClient{Out: os.Stdout}
+	res, err := rs.RollbackRelease(c, &services.RollbackReleaseRequest{Name: rel.Name, Partial: true})
+	if err != nil {
+		t.Fatalf("Failed partial rollback: %s", err)
+	}
+
+	// The ConfigMap was applied by the failed upgrade and is kept; the
+	// Service failed and is reverted.
+	if !strings.Contains(res.Release.Manifest, `revision: "2"`) {
+		t.Errorf("Expected the applied ConfigMap to be kept, got %s", res.Release.Manifest)
+	}
+	if !strings.Contains(res.Release.Manifest, `clusterIP: "10.0.0.1"`) {
+		t.Errorf("Expected the failed Service to be reverted, got %s", res.Release.Manifest)
+	}
+	if strings.Contains(res.Release.Manifest, `clusterIP: "10.0.0.2"`) {
+		t.Errorf("Expected the failed Service not to be kept, got %s", res.Release.Manifest)
+	}
+	if res.Release.Info.Description != "Partial rollback to 1" {
+		t.Errorf("Unexpected description %q", res.Release.Info.Description)
+	}
+}
+
+func TestRollbackReleasePartialNotFailed(t *testing.T) {
+	c := helm.NewContext()
+	rs := rsFixture()
+	rel := releaseStub()
+	rs.env.Releases.Create(rel)
+	upgradedRel := upgradeReleaseVersion(rel)
+	rs.env.Releases.Update(rel)
+	rs.env.Releases.Create(upgradedRel)
+
+	_, err := rs.RollbackRelease(c, &services.RollbackReleaseRequest{Name: rel.Name, Partial: true})
+	if err == nil {
+		t.Fatalf("Expected partial rollback of a deployed release to fail")
+	}
+	if !strings.Contains(err.Error(), "requires a failed release") {
+		t.Errorf("Unexpected error: %s", err)
+	}
+}
+
+func TestRollbackReleaseReRender(t *testing.T) {
+	c := helm.NewContext()
+	rs := rsFixture()
+	rel := releaseStub()
+	rel.Chart = &chart.Chart{
+		Metadata: &chart.Metadata{Name: "hello"},
+		Templates: []*chart.Template{
+			{Name: "templates/configmap", Data: []byte("apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: settings\ndata:\n  tag: {{ .Values.image.tag | quote }}\n  replicas: {{ .Values.replicas | quote }}\n  revision: {{ .Release.Revision | quote }}\n")},
+		},
+	}
+	rel.Config = &chart.Config{Raw: "image:\n  tag: 1.0.0\nreplicas: 2\n"}
+	rel.Manifest = "stale"
+	rs.env.Releases.Create(rel)
+	upgradedRel := upgradeReleaseVersion(rel)
+	upgradedRel.Config = &chart.Config{Raw: "image:\n  tag: 2.0.0\nreplicas: 5\n"}
+	rs.env.Releases.Update(rel)
+	rs.env.Releases.Create(upgradedRel)
+
+	res, err := rs.RollbackRelease(c, &services.RollbackReleaseRequest{
+		Name:     rel.Name,
+		ReRender: true,
+		Values:   &chart.Config{Raw: "image:\n  tag: 1.0.1\n"},
+	})
+	if err != nil {
+		t.Fatalf("Failed rollback: %s", err)
+	}
+
+	for _, expect := range []string{`tag: "1.0.1"`, `replicas: "2"`, `revision: "3"`} {
+		if !strings.Contains(res.Release.Manifest, expect) {
+			t.Errorf("Expected manifest to contain %s, got %s", expect, res.Release.Manifest)
+		}
+	}
+	if res.Release.Config.Raw != "image:\n  tag: 1.0.1\nreplicas: 2\n" {
+		t.Errorf("Expected the merged values to be recorded, got %q", res.Release.Config.Raw)
+	}
+	if res.Release.Info.Description != "Rollback to 1 with overridden values" {
+		t.Errorf("Unexpected description %q", res.Release.Info.Description)
+	}
+
+	// The values of the revision rolled back to are not changed.
+	old, err := rs.env.Releases.Get(rel.Name, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if old.Config.Raw != "image:\n  tag: 1.0.0\nreplicas: 2\n" || old.Manifest != "stale" {
+		t.Errorf("Expected v1 to be unchanged, got %q and %q", old.Config.Raw, old.Manifest)
+	}
+}
+
+func TestRollbackReleaseOverrideRequiresReRender(t *testing.T) {
+	c := helm.NewContext()
+	rs := rsFixture()
+	rel := releaseStub()
+	rs.env.Releases.Create(rel)
+	upgradedRel := upgradeReleaseVersion(rel)
+	rs.env.Releases.Update(rel)
+	rs.env.Releases.Create(upgradedRel)
+
+	for _, req := range []*services.RollbackReleaseRequest{
+		{Name: rel.Name, Values: &chart.Config{Raw: "name: other"}},
+		{Name: rel.Name, ReRender: true, Partial: true},
+	} {
+		if _, err := rs.RollbackRelease(c, req); err == nil {
+			t.Errorf("Expected rollback %v to fail", req)
+		}
+	}
+	if h, _ := rs.env.Releases.History(rel.Name); len(h) != 2 {
+		t.Errorf("Expected no new revision, got %d revisions", len(h))
+	}
+}
+
+func TestRollbackReleaseDryRunHooks(t *testing.T) {
+	c := helm.NewContext()
+	rs := rsFixture()
+	rel := releaseStub()
+	rel.Hooks = []*release.Hook{
+		{
+			Name:     "restore-data",
+			Kind:     "Job",
+			Path:     "templates/restore",
+			Manifest: "kind: Job\nmetadata:\n  name: restore-data\n  annotations:\n    \"helm.sh/hook-delete-policy\": \"hook-succeeded, hook-failed\"\n",
+			Weight:   5,
+			Events:   []release.Hook_Event{release.Hook_PRE_ROLLBACK},
+		},
+		{
+			Name:     "notify",
+			Kind:     "ConfigMap",
+			Path:     "templates/notify",
+			Manifest: manifestWithRollbackHooks,
+			Weight:   0,
+			Events:   []release.Hook_Event{release.Hook_POST_ROLLBACK},
+		},
+		{
+			Name:     "backup",
+			Kind:     "Job",
+			Path:     "templates/backup",
+			Manifest: manifestWithRollbackHooks,
+			Weight:   -1,
+			Events:   []release.Hook_Event{release.Hook_PRE_ROLLBACK},
+		},
+		{
+			Name:     "migrate",
+			Kind:     "Job",
+			Path:     "templates/migrate",
+			Manifest: manifestWithUpgradeHooks,
+			Events:   []release.Hook_Event{release.Hook_PRE_UPGRADE},
+		},
+	}
+	rs.env.Releases.Create(rel)
+	upgradedRel := upgradeReleaseVersion(rel)
+	rs.env.Releases.Update(rel)
+	rs.env.Releases.Create(upgradedRel)
+
+	req := &services.RollbackReleaseRequest{
+		Name:      rel.Name,
+		DryRun:    true,
+		SkipHooks: []string{"notify"},
+	}
+	res, err := rs.RollbackRelease(c, req)
+	if err != nil {
+		t.Fatalf("Failed rollback: %s", err)
+	}
+
+	expect := []*services.HookPreview{
+		{Name: "backup", Kind: "Job", Path: "templates/backup", Event: "pre-rollback", Weight: -1},
+		{Name: "restore-data", Kind: "Job", Path: "templates/restore", Event: "pre-rollback", Weight: 5, DeletePolicies: []string{"hook-succeeded", "hook-failed"}},
+		{Name: "notify", Kind: "ConfigMap", Path: "templates/notify", Event: "post-rollback", Skipped: true},
+	}
+	if !reflect.DeepEqual(res.Hooks, expect) {
+		t.Errorf("Expected hooks %v, got %v", expect, res.Hooks)
+	}
+	for _, h := range res.Release.Hooks {
+		if h.LastRun != nil || h.LastSkipped != nil {
+			t.Errorf("Expected hook %s not to run in a dry run", h.Name)
+		}
+	}
+	if h, _ := rs.env.Releases.History(rel.Name); len(h) != 2 {
+		t.Errorf("Expected dry run not to record a release, got %d revisions", len(h))
+	}
+
+	req.DisableHooks = true
+	res, err = rs.RollbackRelease(c, req)
+	if err != nil {
+		t.Fatalf("Failed rollback: %s", err)
+	}
+	if len(res.Hooks) != 0 {
+		t.Errorf("Expected no hooks with hooks disabled, got %v", res.Hooks)
+	}
+}
+
+func TestRollbackReleaseDryRunReplacedHooks(t *testing.T) {
+	c := helm.NewContext()
+	rs := rsFixture()
+	kc := &driftKubeClient{
+		PrintingKubeClient: environment.PrintingKubeClient{Out: ioutil.Discard},
+		missing:            map[string]bool{"fresh": true},
+	}
+	rs.env.KubeClient = kc
+	rel := releaseStub()
+	bhc := func(name string) *release.Hook {
+		return &release.Hook{
+			Name:     name,
+			Kind:     "Job",
+			Path:     "templates/" + name,
+			Manifest: "apiVersion: batch/v1\nkind: Job\nmetadata:\n  name: " + name + "\n  annotations:\n    \"helm.sh/hook-delete-policy\": \"before-hook-creation\"\n",
+			Events:   []release.Hook_Event{release.Hook_PRE_ROLLBACK},
+		}
+	}
+	rel.Hooks = []*release.Hook{
+		bhc("leftover"),
+		bhc("fresh"),
+		bhc("skipped"),
+		{
+			Name:     "plain",
+			Kind:     "Job",
+			Path:     "templates/plain",
+			Manifest: "apiVersion: batch/v1\nkind: Job\nmetadata:\n  name: plain\n",
+			Events:   []release.Hook_Event{release.Hook_PRE_ROLLBACK},
+		},
+	}
+	rs.env.Releases.Create(rel)
+	upgradedRel := upgradeReleaseVersion(rel)
+	rs.env.Releases.Update(rel)
+	rs.env.Releases.Create(upgradedRel)
+
+	req := &services.RollbackReleaseRequest{Name: rel.Name, DryRun: true, SkipHooks: []string{"skipped"}}
+	res, err := rs.RollbackRelease(c, req)
+	if err != nil {
+		t.Fatalf("Failed rollback: %s", err)
+	}
+	replaced := map[string]bool{}
+	for _, h := range res.Hooks {
+		replaced[h.Name] = h.ReplacesExisting
+	}
+	expect := map[string]bool{"leftover": true, "fresh": false, "skipped": false, "plain": false}
+	if !reflect.DeepEqual(replaced, expect) {
+		t.Errorf("Expected replaced hooks %v, got %v", expect, replaced)
+	}
+	// Only hooks that would run with the policy are looked up.
+	if len(kc.compared) != 2 {
+		t.Errorf("Expected 2 hooks to be looked up, got %d", len(kc.compared))
+	}
+
+	kc.compared = nil
+	req.SkipHookLookup = true
+	res, err = rs.RollbackRelease(c, req)
+	if err != nil {
+		t.Fatalf("Failed rollback: %s", err)
+	}
+	for _, h := range res.Hooks {
+		if h.ReplacesExisting {
+			t.Errorf("Expected hook %s not to be looked up", h.Name)
+		}
+	}
+	if len(kc.compared) != 0 {
+		t.Errorf("Expected the cluster not to be queried, got %d lookups", len(kc.compared))
+	}
+}
+
+func TestRollbackRelease_ReleaseContext(t *testing.T) {
+	c := helm.NewContext()
+	rs := rsFixture()
+	createLoadBalancer(t, rs, api.LoadBalancerIngress{IP: "203.0.113.7"})
+
+	ch := &chart.Chart{
+		Metadata: &chart.Metadata{Name: "hello"},
+		Templates: []*chart.Template{
+			{Name: "templates/service", Data: []byte(manifestWithLoadBalancer)},
+			{Name: "templates/NOTES.txt", Data: []byte(`{{ index .LoadBalancers "web" }} install={{ .Release.IsInstall }} upgrade={{ .Release.IsUpgrade }} revision={{ .Release.Revision }}`)},
+		},
+	}
+	rel := releaseStub()
+	rel.Namespace = "spaced"
+	rel.Chart = ch
+	rel.Manifest = manifestWithLoadBalancer
+	rel.Info.Status.Notes = " install=true upgrade=false revision=1"
+	rel.Info.Status.Code = release.Status_SUPERSEDED
+	rs.env.Releases.Create(rel)
+	upgradedRel := upgradeReleaseVersion(rel)
+	upgradedRel.Namespace = "spaced"
+	rs.env.Releases.Create(upgradedRel)
+
+	// Only the notes are re-rendered on rollback, and only after a wait.
+	res, err := rs.RollbackRelease(c, &services.RollbackReleaseRequest{
+		Name:    rel.Name,
+		Version: 1,
+		Wait:    true,
+	})
+	if err != nil {
+		t.Fatalf("Failed rollback: %s", err)
+	}
+	if expect, notes := "203.0.113.7 install=false upgrade=true revision=3", res.Release.Info.Status.Notes; notes != expect {
+		t.Errorf("Expected notes %q, got %q", expect, notes)
+	}
+}
+
+func TestRollbackRelease_DeleteOptions(t *testing.T) {
+	c := helm.NewContext()
+	rs := rsFixture()
+	kc := newOptionsRecordingKubeClient()
+	rs.env.KubeClient = kc
+	rel := releaseStub()
+	rs.env.Releases.Create(rel)
+	upgradedRel := upgradeReleaseVersion(rel)
+	rs.env.Releases.Update(rel)
+	rs.env.Releases.Create(upgradedRel)
+
+	req := &services.RollbackReleaseRequest{
+		Name:                     rel.Name,
+		Force:                    true,
+		GracePeriod:              5,
+		PropagationPolicy:        "Background",
+		RecreateOnSelectorChange: true,
+	}
+	if _, err := rs.RollbackRelease(c, req); err != nil {
+		t.Fatalf("Failed rollback: %s", err)
+	}
+	expect := kube.UpdateOptions{Force: true, GracePeriod: 5, PropagationPolicy: "Background", RecreateOnSelectorChange: true}
+	if kc.opts != expect {
+		t.Errorf("Expected update options %+v, got %+v", expect, kc.opts)
+	}
+
+	req = &services.RollbackReleaseRequest{Name: rel.Name, GracePeriod: -5}
+	if _, err := rs.RollbackRelease(c, req); err == nil {
+		t.Error("Expected an error for a negative grace period")
+	}
+}
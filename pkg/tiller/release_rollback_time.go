@@ -0,0 +1,92 @@
+/*
+Copyright 2017 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tiller
+
+import (
+	"fmt"
+	"regexp"
+	"time"
+
+	"github.com/golang/protobuf/ptypes/timestamp"
+
+	"k8s.io/helm/pkg/proto/hapi/release"
+	relutil "k8s.io/helm/pkg/releaseutil"
+	"k8s.io/helm/pkg/timeconv"
+)
+
+// failedDescription matches the descriptions of the revisions that failed,
+// for revisions recorded before Info.Failed was.
+var failedDescription = regexp.MustCompile(`^(Release|Release replace|Upgrade|Rollback|Restore|Resume|Revert) "[^"]*" failed|^Install failed: |^Upgrade rejected`)
+
+// wasDeployed reports whether r was the deployed revision of its release at
+// some point. Failed and pending revisions never were, even once a later
+// revision superseded them, such as when a failed upgrade is resumed or
+// reverted.
+func wasDeployed(r *release.Release) bool {
+	if r.Info.Failed || failedDescription.MatchString(r.Info.Description) {
+		return false
+	}
+	switch r.Info.Status.Code {
+	case release.Status_DEPLOYED, release.Status_SUPERSEDED, release.Status_DELETED:
+		return true
+	}
+	return false
+}
+
+// revisionDeployedAt returns the revision of the release of current that was
+// deployed at ts: the last one deployed before ts. It is an error if nothing
+// was deployed at ts, or if that revision is current, as there would be
+// nothing to roll back.
+func (s *ReleaseServer) revisionDeployedAt(current *release.Release, ts *timestamp.Timestamp) (int32, error) {
+	h, err := s.env.Releases.History(current.Name)
+	if err != nil {
+		return 0, err
+	}
+	relutil.SortByRevision(h)
+
+	at := timeconv.Time(ts)
+	var first, found *release.Release
+	for _, r := range h {
+		if !wasDeployed(r) {
+			continue
+		}
+		if first == nil {
+			first = r
+		}
+		if !timeconv.Time(r.Info.LastDeployed).After(at) {
+			found = r
+		}
+	}
+
+	when := utcTime(ts)
+	switch {
+	case first == nil:
+		return 0, fmt.Errorf("no revision of %s was ever deployed", current.Name)
+	case found == nil:
+		return 0, fmt.Errorf("no revision of %s was deployed at %s: the first one, %d, was deployed at %s", current.Name, when, first.Version, utcTime(first.Info.LastDeployed))
+	case found.Info.Status.Code == release.Status_DELETED && found.Info.Deleted != nil && !timeconv.Time(found.Info.Deleted).After(at):
+		return 0, fmt.Errorf("no revision of %s was deployed at %s: revision %d was deleted at %s", current.Name, when, found.Version, utcTime(found.Info.Deleted))
+	case found.Version == current.Version && found.Info.Status.Code == release.Status_DEPLOYED:
+		return 0, fmt.Errorf("revision %d, deployed at %s, is still the current revision of %s", found.Version, utcTime(found.Info.LastDeployed), current.Name)
+	}
+	return found.Version, nil
+}
+
+// utcTime formats ts as an RFC 3339 time in UTC.
+func utcTime(ts *timestamp.Timestamp) string {
+	return timeconv.Time(ts).UTC().Format(time.RFC3339)
+}
@@ -0,0 +1,137 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tiller
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/golang/protobuf/ptypes/timestamp"
+
+	"k8s.io/helm/pkg/helm"
+	"k8s.io/helm/pkg/proto/hapi/release"
+	"k8s.io/helm/pkg/proto/hapi/services"
+)
+
+// deployedHistory stores revisions of rel deployed at 1000, 2000, 3000 and
+// 4000 seconds, of which the second failed.
+func deployedHistory(rs *ReleaseServer) *release.Release {
+	var rel *release.Release
+	for i, code := range []release.Status_Code{release.Status_SUPERSEDED, release.Status_FAILED, release.Status_SUPERSEDED, release.Status_DEPLOYED} {
+		rel = releaseStub()
+		rel.Version = int32(i + 1)
+		rel.Info.Status.Code = code
+		rel.Info.LastDeployed = &timestamp.Timestamp{Seconds: int64(i+1) * 1000}
+		rs.env.Releases.Create(rel)
+	}
+	return rel
+}
+
+func TestRevisionDeployedAt(t *testing.T) {
+	rs := rsFixture()
+	current := deployedHistory(rs)
+
+	tests := []struct {
+		at      int64
+		version int32
+		err     string
+	}{
+		{1500, 1, ""},
+		{2500, 1, ""},
+		{3000, 3, ""},
+		{500, 0, "no revision of angry-panda was deployed at 1970-01-01T00:08:20Z: the first one, 1, was deployed at 1970-01-01T00:16:40Z"},
+		{5000, 0, "revision 4, deployed at 1970-01-01T01:06:40Z, is still the current revision of angry-panda"},
+	}
+	for _, tt := range tests {
+		v, err := rs.revisionDeployedAt(current, &timestamp.Timestamp{Seconds: tt.at})
+		if tt.err != "" {
+			if err == nil || err.Error() != tt.err {
+				t.Errorf("%d: expected %q, got %v", tt.at, tt.err, err)
+			}
+			continue
+		}
+		if err != nil || v != tt.version {
+			t.Errorf("%d: expected revision %d, got %d (%v)", tt.at, tt.version, v, err)
+		}
+	}
+}
+
+func TestWasDeployed(t *testing.T) {
+	rs := rsFixture()
+
+	// A failed revision that a resume or revert superseded was never
+	// deployed.
+	failed := releaseStub()
+	failed.Info.Status.Code = release.Status_FAILED
+	rs.recordRelease(failed, false)
+	failed.Info.Status.Code = release.Status_SUPERSEDED
+	rs.recordRelease(failed, true)
+	stored, err := rs.env.Releases.Get(failed.Name, failed.Version)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if wasDeployed(stored) {
+		t.Error("Expected a superseded failed revision not to count as deployed")
+	}
+
+	tests := []struct {
+		code        release.Status_Code
+		description string
+		expect      bool
+	}{
+		{release.Status_SUPERSEDED, "Upgrade complete", true},
+		{release.Status_SUPERSEDED, "Restart failed: timed out", true},
+		{release.Status_DELETED, "Deletion complete", true},
+		{release.Status_SUPERSEDED, `Upgrade "angry-panda" failed: timed out`, false},
+		{release.Status_SUPERSEDED, `Release "angry-panda" failed post-install: timed out`, false},
+		{release.Status_SUPERSEDED, "Upgrade rejected by jane", false},
+		{release.Status_FAILED, "Rollback to 1", false},
+		{release.Status_UNKNOWN, "Preparing upgrade", false},
+	}
+	for _, tt := range tests {
+		rel := releaseStub()
+		rel.Info.Status.Code = tt.code
+		rel.Info.Description = tt.description
+		if got := wasDeployed(rel); got != tt.expect {
+			t.Errorf("%s %q: expected %v, got %v", tt.code, tt.description, tt.expect, got)
+		}
+	}
+}
+
+func TestRollbackReleaseDeployedAt(t *testing.T) {
+	c := helm.NewContext()
+	rs := rsFixture()
+	rel := deployedHistory(rs)
+
+	req := &services.RollbackReleaseRequest{
+		Name:       rel.Name,
+		Version:    1,
+		DeployedAt: &timestamp.Timestamp{Seconds: 2500},
+	}
+	if _, err := rs.RollbackRelease(c, req); err == nil || !strings.Contains(err.Error(), "not both") {
+		t.Errorf("Expected a revision and a time to be refused, got %v", err)
+	}
+
+	req.Version = 0
+	res, err := rs.RollbackRelease(c, req)
+	if err != nil {
+		t.Fatalf("Failed rollback: %s", err)
+	}
+	if res.Release.Version != 5 || res.Release.Info.Description != "Rollback to 1" {
+		t.Errorf("Expected revision 5 to roll back to 1, got %d: %q", res.Release.Version, res.Release.Info.Description)
+	}
+}
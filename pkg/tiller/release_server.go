@@ -23,13 +23,18 @@ import (
 	"path"
 	"regexp"
 	"strings"
+	"time"
 
-	"github.com/technosophos/moniker"
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/ptypes/timestamp"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/util/flowcontrol"
 	"k8s.io/kubernetes/pkg/client/clientset_generated/internalclientset"
 
 	"k8s.io/helm/pkg/chartutil"
+	"k8s.io/helm/pkg/kube"
+	"k8s.io/helm/pkg/logging"
 	"k8s.io/helm/pkg/proto/hapi/chart"
 	"k8s.io/helm/pkg/proto/hapi/release"
 	"k8s.io/helm/pkg/proto/hapi/services"
@@ -52,6 +57,10 @@ const releaseNameMaxLen = 53
 // since there can be filepath in front of it.
 const notesFileSuffix = "NOTES.txt"
 
+// DefaultLockTimeout is how long an operation waits for another operation on
+// the same release to finish before it fails.
+const DefaultLockTimeout = 5 * time.Minute
+
 var (
 	// errMissingChart indicates that a chart was not provided.
 	errMissingChart = errors.New("no chart provided")
@@ -81,6 +90,76 @@ type ReleaseServer struct {
 	env       *environment.Environment
 	clientset internalclientset.Interface
 	Log       func(string, ...interface{})
+
+	// logger is the structured logger set by SetLogger. When it is nil,
+	// request-scoped loggers write through Log.
+	logger logging.Logger
+
+	// ops deduplicates identical concurrent operations on a release.
+	ops operationGroup
+
+	// events limits the rate of Kubernetes Events. Events are only emitted
+	// when it is set; see EnableEvents.
+	events flowcontrol.RateLimiter
+
+	// nameGenerator names releases installed without a name. When it is nil,
+	// MonikerNameGenerator is used.
+	nameGenerator NameGenerator
+
+	// nameMaxLength limits the length of new release names. When it is zero,
+	// releaseNameMaxLen is used; see SetReleaseNameMaxLength.
+	nameMaxLength int
+
+	// deletedRetention is how long the records of deleted releases are kept
+	// before PurgeExpiredReleases removes them. Zero keeps them forever.
+	deletedRetention time.Duration
+
+	// lockTimeout is how long an operation waits for the lock of its release.
+	// When it is zero, DefaultLockTimeout is used; see SetLockTimeout.
+	lockTimeout time.Duration
+
+	// storeComputedValues makes installs and upgrades keep a snapshot of the
+	// computed values in each revision; see StoreComputedValues.
+	storeComputedValues bool
+
+	// imageVerifier checks images for requests that ask to verify them. When
+	// it is nil, a registry.Client is used.
+	imageVerifier ImageVerifier
+
+	// waitLogs passes the logs that waits stream to WatchWaitLogs.
+	waitLogs waitLogFeed
+
+	// approvals holds the upgrades that wait for approval.
+	approvals approvalGate
+
+	// allowedNamespaces are the namespaces, other than its own, that a
+	// release may put resources in. When it is nil, any namespace may be
+	// used; see SetAllowedNamespaces.
+	allowedNamespaces map[string]bool
+
+	// hooksDisabled are the operations whose hooks are skipped unless a
+	// request enables them; see DisableHooksByDefault.
+	hooksDisabled map[string]bool
+
+	// valuesLimits bound the values of installs and upgrades; see
+	// SetValuesLimits.
+	valuesLimits chartutil.ValuesLimits
+
+	// clusters are the clusters, other than Tiller's own, that releases can
+	// be installed in, by name; see AddCluster.
+	clusters map[string]*kubeCluster
+
+	// valuesMutators change the values of releases before they are rendered,
+	// in order; see AddValuesMutator.
+	valuesMutators []namedMutator
+
+	// injectionPolicies add containers to the workloads of releases before
+	// they are applied; see SetInjectionPolicies.
+	injectionPolicies []injectionPolicy
+
+	// discovery caches the API versions of clusters; see
+	// SetDiscoveryCacheTTL.
+	discovery discoveryCache
 }
 
 // NewReleaseServer creates a new release server.
@@ -99,9 +178,99 @@ func NewReleaseServer(env *environment.Environment, clientset internalclientset.
 		clientset:     clientset,
 		ReleaseModule: releaseModule,
 		Log:           func(_ string, _ ...interface{}) {},
+		valuesLimits:  chartutil.DefaultValuesLimits(),
 	}
 }
 
+// SetValuesLimits bounds how deeply the values of installs and upgrades may
+// nest, and how large they may be. A zero limit disables the check.
+func (s *ReleaseServer) SetValuesLimits(l chartutil.ValuesLimits) {
+	s.valuesLimits = l
+}
+
+// SetLogger makes the server write structured, leveled logs to l.
+//
+// Log is replaced with an adapter that writes to l at info level.
+func (s *ReleaseServer) SetLogger(l logging.Logger) {
+	s.logger = l
+	s.Log = logging.Printf(l)
+}
+
+// SetNameGenerator makes the server name releases that are installed without
+// a name with g instead of MonikerNameGenerator.
+func (s *ReleaseServer) SetNameGenerator(g NameGenerator) {
+	s.nameGenerator = g
+}
+
+// SetDeletedReleaseRetention makes PurgeExpiredReleases remove the records of
+// releases that were deleted longer than d ago. Zero keeps them forever.
+func (s *ReleaseServer) SetDeletedReleaseRetention(d time.Duration) {
+	s.deletedRetention = d
+}
+
+// SetLockTimeout sets how long an operation waits for another operation on
+// the same release to finish before it fails. Zero restores
+// DefaultLockTimeout.
+func (s *ReleaseServer) SetLockTimeout(d time.Duration) {
+	s.lockTimeout = d
+}
+
+// StoreComputedValues makes the server store the values each revision was
+// rendered with, including the chart's defaults, in the revision's
+// ComputedValues. Rollbacks reuse the snapshot of the revision they restore.
+func (s *ReleaseServer) StoreComputedValues(enabled bool) {
+	s.storeComputedValues = enabled
+}
+
+// computedValues returns the snapshot of the values in valuesToRender that is
+// stored with a release, or nil if snapshots are disabled.
+func (s *ReleaseServer) computedValues(valuesToRender chartutil.Values) (*chart.Config, error) {
+	if !s.storeComputedValues {
+		return nil, nil
+	}
+	vals, err := valuesToRender.Table("Values")
+	if err != nil {
+		return nil, err
+	}
+	raw, err := vals.YAML()
+	if err != nil {
+		return nil, err
+	}
+	return &chart.Config{Raw: raw}, nil
+}
+
+// renderedValues returns the values that rel was rendered with: its stored
+// snapshot if it has one, or else its values merged with its chart's defaults.
+func renderedValues(rel *release.Release) (chartutil.Values, error) {
+	if rel.ComputedValues != nil {
+		return chartutil.ReadValues([]byte(rel.ComputedValues.Raw))
+	}
+	vals, err := profileValues(rel)
+	if err != nil {
+		return nil, err
+	}
+	return chartutil.CoalesceValues(rel.Chart, vals)
+}
+
+// profileValues returns the Config of rel with the values of its profile
+// merged under it, which is what the release was rendered with.
+func profileValues(rel *release.Release) (*chart.Config, error) {
+	if rel.Profile == "" {
+		return rel.Config, nil
+	}
+	return chartutil.ApplyProfile(rel.Chart, rel.Profile, rel.Config, rel.AllowMissingProfile)
+}
+
+// requestLogger returns a logger for a single operation on a release. Every
+// entry carries the operation, release name and revision as fields.
+func (s *ReleaseServer) requestLogger(operation, name string, revision int32) logging.Logger {
+	l := s.logger
+	if l == nil {
+		l = logging.FromPrintf(s.Log)
+	}
+	return l.With("operation", operation, "release", name, "revision", revision)
+}
+
 // reuseValues copies values from the current release to a new release if the
 // new release does not have any values.
 //
@@ -148,15 +317,49 @@ func (s *ReleaseServer) reuseValues(req *services.UpdateReleaseRequest, current
 	return nil
 }
 
-func (s *ReleaseServer) uniqName(start string, reuse bool) (string, error) {
+// lockRelease gains exclusive access to the named release. Rather than queueing
+// on the lock, it polls with a randomized wait for at most the lock timeout.
+//
+// The lock timeout is independent of the timeout of a request, which bounds
+// the Kubernetes operations; a request with a short or no timeout still waits
+// for an operation in progress to finish.
+func (s *ReleaseServer) lockRelease(name string) error {
+	timeout := s.lockTimeout
+	if timeout <= 0 {
+		timeout = DefaultLockTimeout
+	}
+	return s.env.Releases.LockReleaseWithJitter(name, timeout)
+}
+
+// dedupe runs fn as the given operation on the named release. If an identical
+// operation (see operationKey) is already in flight, fn is not run; the caller
+// instead waits for the running operation and gets back the same response and
+// error.
+func (s *ReleaseServer) dedupe(intent, name string, req proto.Message, fn func() (interface{}, error)) (interface{}, error) {
+	var revision int32
+	if rel, err := s.env.Releases.Last(name); err == nil {
+		revision = rel.Version
+	}
+	key, err := operationKey(intent, name, revision, req)
+	if err != nil {
+		return fn()
+	}
+	res, err, shared := s.ops.do(key, fn)
+	if shared {
+		s.Log("%s of %s (v%d) attached to an identical operation already in progress", intent, name, revision)
+	}
+	return res, err
+}
+
+func (s *ReleaseServer) uniqName(start string, reuse bool, ch *chart.Chart) (string, error) {
 
 	// If a name is supplied, we check to see if that name is taken. If not, it
 	// is granted. If reuse is true and a deleted release with that name exists,
 	// we re-grant it. Otherwise, an error is returned.
 	if start != "" {
 
-		if len(start) > releaseNameMaxLen {
-			return "", fmt.Errorf("release name %q exceeds max length of %d", start, releaseNameMaxLen)
+		if max := s.nameMaxLen(); len(start) > max {
+			return "", fmt.Errorf("release name %q exceeds max length of %d", start, max)
 		}
 
 		h, err := s.env.Releases.History(start)
@@ -171,20 +374,29 @@ func (s *ReleaseServer) uniqName(start string, reuse bool) (string, error) {
 			s.Log("reusing name %q", start)
 			return start, nil
 		} else if reuse {
-			return "", errors.New("cannot re-use a name that is still in use")
+			return "", fmt.Errorf("cannot re-use a name that is still in use: %s (v%d) is %s, and only %s or %s releases can be replaced", start, rel.Version, st, release.Status_FAILED, release.Status_DELETED)
 		}
 
 		return "", fmt.Errorf("a release named %q already exists.\nPlease run: helm ls --all %q; helm del --help", start, start)
 	}
 
+	namer := s.nameGenerator
+	if namer == nil {
+		namer = MonikerNameGenerator{}
+	}
 	maxTries := 5
 	for i := 0; i < maxTries; i++ {
-		namer := moniker.New()
-		name := namer.NameSep("-")
-		if len(name) > releaseNameMaxLen {
-			name = name[:releaseNameMaxLen]
+		name, err := namer.GenerateName(ch)
+		if err != nil {
+			return "", fmt.Errorf("cannot generate a release name: %s", err)
 		}
-		if _, err := s.env.Releases.Get(name, 1); strings.Contains(err.Error(), "not found") {
+		if max := s.nameMaxLen(); len(name) > max {
+			name = strings.TrimRight(name[:max], "-_.")
+		}
+		if !ValidName.MatchString(name) {
+			return "", fmt.Errorf("generated release name %q is invalid", name)
+		}
+		if _, err := s.env.Releases.Get(name, 1); err != nil && strings.Contains(err.Error(), "not found") {
 			return name, nil
 		}
 		s.Log("info: Name %q is taken. Searching again.", name)
@@ -219,6 +431,7 @@ func capabilities(disc discovery.DiscoveryInterface) (*chartutil.Capabilities, e
 		APIVersions:   vs,
 		KubeVersion:   sv,
 		TillerVersion: version.GetVersionProto(),
+		HelmVersion:   chartutil.NewHelmVersion(version.GetVersionProto()),
 	}, nil
 }
 
@@ -241,18 +454,41 @@ func GetVersionSet(client discovery.ServerGroupsInterface) (chartutil.VersionSet
 	return chartutil.NewVersionSet(versions...), nil
 }
 
-func (s *ReleaseServer) renderResources(ch *chart.Chart, values chartutil.Values, vs chartutil.VersionSet) ([]*release.Hook, *bytes.Buffer, string, error) {
+// generatedValues returns a copy of the values that r generated, to render a
+// later revision with. r may be nil.
+func generatedValues(r *release.Release) map[string]string {
+	generated := map[string]string{}
+	if r == nil {
+		return generated
+	}
+	for k, v := range r.GeneratedValues {
+		generated[k] = v
+	}
+	return generated
+}
+
+// renderResources renders ch and returns its hooks, manifests and notes, and
+// the containers that injection policies added to its workloads. Values that
+// templates generate once per release are taken from, and recorded in,
+// generated. It may be nil if they are not kept.
+func (s *ReleaseServer) renderResources(ch *chart.Chart, values chartutil.Values, vs chartutil.VersionSet, generated map[string]string) ([]*release.Hook, *bytes.Buffer, string, []*release.ContainerInjection, error) {
 	// Guard to make sure Tiller is at the right version to handle this chart.
 	sver := version.GetVersion()
 	if ch.Metadata.TillerVersion != "" &&
 		!version.IsCompatibleRange(ch.Metadata.TillerVersion, sver) {
-		return nil, nil, "", fmt.Errorf("Chart incompatible with Tiller %s", sver)
+		return nil, nil, "", nil, fmt.Errorf("Chart incompatible with Tiller %s", sver)
 	}
 
 	renderer := s.engine(ch)
-	files, err := renderer.Render(ch, values)
+	var files map[string]string
+	var err error
+	if g, ok := renderer.(environment.GeneratingEngine); ok && generated != nil {
+		files, err = g.RenderGenerated(ch, values, generated)
+	} else {
+		files, err = renderer.Render(ch, values)
+	}
 	if err != nil {
-		return nil, nil, "", err
+		return nil, nil, "", nil, err
 	}
 
 	// NOTES.txt gets rendered like all the other files, but because it's not a hook nor a resource,
@@ -290,7 +526,15 @@ func (s *ReleaseServer) renderResources(ch *chart.Chart, values chartutil.Values
 			b.WriteString("\n---\n# Source: " + name + "\n")
 			b.WriteString(content)
 		}
-		return nil, b, "", err
+		return nil, b, "", nil, err
+	}
+
+	if err := hashConfigNames(manifests, hooks); err != nil {
+		return nil, nil, "", nil, err
+	}
+	injections, err := s.injectContainers(manifests)
+	if err != nil {
+		return nil, nil, "", nil, err
 	}
 
 	// Aggregate all valid manifests into one big doc.
@@ -300,10 +544,29 @@ func (s *ReleaseServer) renderResources(ch *chart.Chart, values chartutil.Values
 		b.WriteString(m.content)
 	}
 
-	return hooks, b, notes, nil
+	return hooks, b, notes, injections, nil
+}
+
+// serverDryRun replaces the manifest of r with the objects the API server
+// would store for it, with server defaults applied. Hooks are not included.
+func (s *ReleaseServer) serverDryRun(r *release.Release) error {
+	c, err := s.actingCluster(r)
+	if err != nil {
+		return err
+	}
+	manifest, err := c.env.KubeClient.DryRun(r.Namespace, bytes.NewBufferString(r.Manifest))
+	if err != nil {
+		return fmt.Errorf("server dry run for %s failed: %s", r.Name, err)
+	}
+	r.Manifest = manifest
+	r.Info.Description = "Server dry run complete"
+	return nil
 }
 
 func (s *ReleaseServer) recordRelease(r *release.Release, reuse bool) {
+	if r.Info.Status.Code == release.Status_FAILED {
+		r.Info.Failed = true
+	}
 	if reuse {
 		if err := s.env.Releases.Update(r); err != nil {
 			s.Log("warning: Failed to update release %q: %s", r.Name, err)
@@ -311,45 +574,150 @@ func (s *ReleaseServer) recordRelease(r *release.Release, reuse bool) {
 	} else if err := s.env.Releases.Create(r); err != nil {
 		s.Log("warning: Failed to record release %q: %s", r.Name, err)
 	}
+	s.emitEvent(r)
 }
 
-func (s *ReleaseServer) execHook(hs []*release.Hook, name, namespace, hook string, timeout int64) error {
-	kubeCli := s.env.KubeClient
+func (s *ReleaseServer) execHook(log logging.Logger, kubeCli environment.KubeClient, r *release.Release, hook string, timeout int64, skip hookSkipList) error {
+	return s.execHookWithin(log, kubeCli, r, hook, newTimeoutBudget(0, timeout), skip)
+}
+
+// execHookWithin runs the hooks of an event of r, each with the time that is
+// left of budget. Every hook that is run or skipped is recorded in the hook
+// executions of r.
+func (s *ReleaseServer) execHookWithin(log logging.Logger, kubeCli environment.KubeClient, r *release.Release, hook string, budget *timeoutBudget, skip hookSkipList) error {
 	code, ok := events[hook]
 	if !ok {
 		return fmt.Errorf("unknown hook %q", hook)
 	}
+	name, namespace := r.Name, r.Namespace
+
+	log = log.With("hook", hook)
+	log.Infof("Executing %s hooks for %s", hook, name)
+	crds := false
+	for _, h := range hooksFor(r.Hooks, code) {
+		exec := &release.HookExecution{Name: h.Name, Kind: h.Kind, Path: h.Path, Event: code, Weight: h.Weight}
+		r.HookExecutions = append(r.HookExecutions, exec)
+		if skip.skipsDestructive(h) {
+			log.Warnf("Skipping destructive %s hook %s (weight %d) for %s", hook, h.Name, h.Weight, name)
+			h.LastSkipped = timeconv.Now()
+			endHookExecution(h, exec, release.HookExecution_SKIPPED, nil)
+			continue
+		}
+		if skip.skips(h) {
+			log.Infof("Skipping %s hook %s (weight %d) for %s as requested", hook, h.Name, h.Weight, name)
+			h.LastSkipped = timeconv.Now()
+			endHookExecution(h, exec, release.HookExecution_SKIPPED, nil)
+			continue
+		}
 
-	s.Log("Executing %s hooks for %s", hook, name)
-	executingHooks := []*release.Hook{}
-	for _, h := range hs {
-		for _, e := range h.Events {
-			if e == code {
-				executingHooks = append(executingHooks, h)
+		retry, err := hookRetryPolicy(h)
+		if err != nil {
+			endHookExecution(h, exec, release.HookExecution_FAILED, err)
+			return err
+		}
+		exec.StartedAt = timeconv.Now()
+		attemptStarted := exec.StartedAt
+		for attempt := 1; ; attempt++ {
+			alog := log
+			if retry.attempts > 1 {
+				alog = log.With("attempt", attempt)
+			}
+			timeout, err := budget.start(hook + " hooks")
+			if err != nil {
+				endHookExecution(h, exec, release.HookExecution_FAILED, err)
+				return err
 			}
+			exec.Attempts = int32(attempt)
+			start := time.Now()
+			err = s.runHook(alog, kubeCli, h, name, namespace, hook, timeout)
+			observeHook(hook, start, err)
+			if err == nil {
+				break
+			}
+			if budget.usedUp() {
+				endHookExecution(h, exec, release.HookExecution_FAILED, err)
+				return budget.exhausted(err)
+			}
+			if attempt >= retry.attempts {
+				endHookExecution(h, exec, release.HookExecution_FAILED, err)
+				if retry.attempts > 1 {
+					return fmt.Errorf("%s hook %s failed after %d attempts: %s", hook, h.Name, attempt, err)
+				}
+				return err
+			}
+			exec.FailedAttempts = append(exec.FailedAttempts, failedHookAttempt(attempt, attemptStarted, err))
+			// Remove what the failed attempt left behind, so that the hook
+			// can be created again. Its error and logs are kept in the
+			// execution.
+			if err := kubeCli.Delete(namespace, bytes.NewBufferString(h.Manifest)); err != nil {
+				alog.Warnf("Could not delete %s hook %s before retrying: %s", hook, h.Name, err)
+			}
+			alog.Infof("Retrying %s hook %s for %s in %s (attempt %d of %d)", hook, h.Name, name, retry.delay, attempt+1, retry.attempts)
+			budget.sleep(retry.delay)
+			attemptStarted = timeconv.Now()
 		}
+		h.LastRun = timeconv.Now()
+		endHookExecution(h, exec, release.HookExecution_SUCCEEDED, nil)
+		crds = crds || h.Kind == "CustomResourceDefinition"
+	}
+
+	// Hooks that create CustomResourceDefinitions, such as pre-install
+	// hooks, run before the resources of their kinds are applied.
+	if crds {
+		s.invalidateDiscovery(log, r.Cluster, kubeCli)
 	}
 
-	executingHooks = sortByHookWeight(executingHooks)
+	log.Infof("Hooks complete for %s %s", hook, name)
+	return nil
+}
 
-	for _, h := range executingHooks {
+// endHookExecution records that the execution e of hook h ended in phase,
+// with err if it failed. The logs of the pods of the hook, if the Kubernetes
+// client collected them, are kept apart from the error.
+func endHookExecution(h *release.Hook, e *release.HookExecution, phase release.HookExecution_Phase, err error) {
+	e.CompletedAt = timeconv.Now()
+	e.Phase = phase
+	h.LastPhase = phase
+	if err == nil {
+		return
+	}
+	e.Error = err.Error()
+	if herr, ok := err.(*kube.HookError); ok {
+		e.Error = herr.Err.Error()
+		e.Log = herr.Logs
+	}
+}
 
-		b := bytes.NewBufferString(h.Manifest)
-		if err := kubeCli.Create(namespace, b, timeout, false); err != nil {
-			s.Log("warning: Release %q %s %s failed: %s", name, hook, h.Path, err)
-			return err
-		}
-		// No way to rewind a bytes.Buffer()?
-		b.Reset()
-		b.WriteString(h.Manifest)
-		if err := kubeCli.WatchUntilReady(namespace, b, timeout, false); err != nil {
-			s.Log("warning: Release %q %s %s could not complete: %s", name, hook, h.Path, err)
-			return err
-		}
-		h.LastRun = timeconv.Now()
+// failedHookAttempt records the failed attempt number of a hook, which
+// started at started, with its error and the logs of its pods.
+func failedHookAttempt(number int, started *timestamp.Timestamp, err error) *release.HookAttempt {
+	a := &release.HookAttempt{
+		Number:      int32(number),
+		StartedAt:   started,
+		CompletedAt: timeconv.Now(),
+		Error:       err.Error(),
+	}
+	if herr, ok := err.(*kube.HookError); ok {
+		a.Error = herr.Err.Error()
+		a.Log = herr.Logs
 	}
+	return a
+}
 
-	s.Log("Hooks complete for %s %s", hook, name)
+// runHook creates the resource of a hook and waits for it to be ready.
+func (s *ReleaseServer) runHook(log logging.Logger, kubeCli environment.KubeClient, h *release.Hook, name, namespace, hook string, timeout int64) error {
+	b := bytes.NewBufferString(h.Manifest)
+	if err := kubeCli.Create(namespace, b, timeout, false); err != nil {
+		log.Warnf("Release %q %s %s failed: %s", name, hook, h.Path, err)
+		return err
+	}
+	// No way to rewind a bytes.Buffer()?
+	b.Reset()
+	b.WriteString(h.Manifest)
+	if err := kubeCli.WatchUntilReady(namespace, b, timeout, false); err != nil {
+		log.Warnf("Release %q %s %s could not complete: %s", name, hook, h.Path, err)
+		return err
+	}
 	return nil
 }
 
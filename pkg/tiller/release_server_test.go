@@ -17,11 +17,16 @@ limitations under the License.
 package tiller
 
 import (
+	"bytes"
+	"encoding/json"
 	"errors"
+	"fmt"
 	"io"
 	"os"
 	"regexp"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/golang/protobuf/ptypes/timestamp"
 	"golang.org/x/net/context"
@@ -30,6 +35,8 @@ import (
 	"k8s.io/kubernetes/pkg/client/clientset_generated/internalclientset/fake"
 
 	"k8s.io/helm/pkg/helm"
+	"k8s.io/helm/pkg/kube"
+	"k8s.io/helm/pkg/logging"
 	"k8s.io/helm/pkg/proto/hapi/chart"
 	"k8s.io/helm/pkg/proto/hapi/release"
 	"k8s.io/helm/pkg/proto/hapi/services"
@@ -246,7 +253,7 @@ func TestUniqName(t *testing.T) {
 	}
 
 	for _, tt := range tests {
-		u, err := rs.uniqName(tt.name, tt.reuse)
+		u, err := rs.uniqName(tt.name, tt.reuse, chartStub())
 		if err != nil {
 			if tt.err {
 				continue
@@ -307,7 +314,7 @@ type updateFailingKubeClient struct {
 	environment.PrintingKubeClient
 }
 
-func (u *updateFailingKubeClient) Update(namespace string, originalReader, modifiedReader io.Reader, force bool, recreate bool, timeout int64, shouldWait bool) error {
+func (u *updateFailingKubeClient) UpdateWithOptions(namespace string, originalReader, modifiedReader io.Reader, opts kube.UpdateOptions) error {
 	return errors.New("Failed update in kube client")
 }
 
@@ -325,6 +332,17 @@ func (h *hookFailingKubeClient) WatchUntilReady(ns string, r io.Reader, timeout
 	return errors.New("Failed watch")
 }
 
+// serverDryRunKubeClient returns manifest, or err, from a server-side dry-run.
+type serverDryRunKubeClient struct {
+	environment.PrintingKubeClient
+	manifest string
+	err      error
+}
+
+func (d *serverDryRunKubeClient) DryRun(ns string, r io.Reader) (string, error) {
+	return d.manifest, d.err
+}
+
 type mockListServer struct {
 	val *services.ListReleasesResponse
 }
@@ -354,3 +372,92 @@ func (rs mockRunReleaseTestServer) SetTrailer(m metadata.MD)       {}
 func (rs mockRunReleaseTestServer) SendMsg(v interface{}) error    { return nil }
 func (rs mockRunReleaseTestServer) RecvMsg(v interface{}) error    { return nil }
 func (rs mockRunReleaseTestServer) Context() context.Context       { return helm.NewContext() }
+
+func TestRequestLogger(t *testing.T) {
+	rs := rsFixture()
+
+	var out []string
+	rs.Log = func(format string, args ...interface{}) {
+		out = append(out, fmt.Sprintf(format, args...))
+	}
+	rs.requestLogger("upgrade", "angry-bird", 2).With("hook", "pre-upgrade").Warnf("failed")
+
+	expect := "warning: failed operation=upgrade release=angry-bird revision=2 hook=pre-upgrade"
+	if len(out) != 1 || out[0] != expect {
+		t.Errorf("expected %q, got %q", expect, out)
+	}
+
+	var b bytes.Buffer
+	rs.SetLogger(logging.New(&b, logging.JSONFormat, logging.InfoLevel))
+	rs.Log("reusing name %q", "angry-bird")
+	rs.requestLogger("install", "angry-bird", 1).Debugf("filtered")
+
+	var entry map[string]interface{}
+	if err := json.Unmarshal(b.Bytes(), &entry); err != nil {
+		t.Fatalf("expected a single JSON entry, got %q: %s", b.String(), err)
+	}
+	if entry["level"] != "info" || entry["msg"] != `reusing name "angry-bird"` {
+		t.Errorf("unexpected entry %v", entry)
+	}
+}
+
+func TestUniqNameGenerator(t *testing.T) {
+	rs := rsFixture()
+	rs.env.Releases.Create(releaseStub())
+
+	// The first name is taken, so the generator should be asked again.
+	names := []string{"angry-panda", "second"}
+	var calls int
+	rs.SetNameGenerator(NameGeneratorFunc(func(ch *chart.Chart) (string, error) {
+		if ch.Metadata.Name != "hello" {
+			t.Errorf("expected chart %q, got %q", "hello", ch.Metadata.Name)
+		}
+		name := names[calls]
+		calls++
+		return name, nil
+	}))
+	name, err := rs.uniqName("", false, chartStub())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if name != "second" {
+		t.Errorf("expected %q, got %q", "second", name)
+	}
+
+	rs.SetNameGenerator(NameGeneratorFunc(func(*chart.Chart) (string, error) {
+		return "angry-panda", nil
+	}))
+	if _, err := rs.uniqName("", false, chartStub()); err == nil {
+		t.Error("expected an error when every generated name is taken")
+	}
+
+	rs.SetNameGenerator(NameGeneratorFunc(func(*chart.Chart) (string, error) {
+		return "Not a valid name!", nil
+	}))
+	if _, err := rs.uniqName("", false, chartStub()); err == nil {
+		t.Error("expected an error for an invalid generated name")
+	}
+
+	rs.SetNameGenerator(NameGeneratorFunc(func(*chart.Chart) (string, error) {
+		return strings.Repeat("a", releaseNameMaxLen) + "-b", nil
+	}))
+	if name, err := rs.uniqName("", false, chartStub()); err != nil {
+		t.Fatal(err)
+	} else if len(name) != releaseNameMaxLen {
+		t.Errorf("expected name to be truncated to %d characters, got %q", releaseNameMaxLen, name)
+	}
+}
+
+func TestPatternNameGenerator(t *testing.T) {
+	g := PatternNameGenerator{
+		Pattern: "{chart}-{timestamp}-{moniker}",
+		now:     func() time.Time { return time.Unix(1500000000, 0) },
+	}
+	name, err := g.GenerateName(chartStub())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !regexp.MustCompile(`^hello-1500000000-[a-z]+-[a-z]+$`).MatchString(name) {
+		t.Errorf("unexpected name %q", name)
+	}
+}
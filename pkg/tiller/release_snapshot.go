@@ -0,0 +1,117 @@
+/*
+Copyright 2017 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tiller
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/ghodss/yaml"
+	ctx "golang.org/x/net/context"
+
+	"k8s.io/helm/pkg/kube"
+	"k8s.io/helm/pkg/proto/hapi/release"
+	"k8s.io/helm/pkg/proto/hapi/services"
+	relutil "k8s.io/helm/pkg/releaseutil"
+	"k8s.io/helm/pkg/timeconv"
+)
+
+// SnapshotRelease records the live state of the resources of a release in its
+// latest revision, replacing any earlier snapshot of it. The stored revision
+// is changed in place: no new revision is created and nothing is applied to
+// the cluster.
+//
+// A rollback to the revision with FromSnapshot applies the snapshot instead of
+// the manifest, which restores changes made to the resources outside of Helm
+// too. Hooks are not part of the snapshot.
+func (s *ReleaseServer) SnapshotRelease(c ctx.Context, req *services.SnapshotReleaseRequest) (*services.SnapshotReleaseResponse, error) {
+	if !ValidName.MatchString(req.Name) {
+		return nil, errMissingRelease
+	}
+
+	if err := s.lockRelease(req.Name); err != nil {
+		return nil, err
+	}
+	defer s.env.Releases.UnlockRelease(req.Name)
+
+	rel, err := s.env.Releases.Last(req.Name)
+	if err != nil {
+		return nil, fmt.Errorf("getting release %q: %s", req.Name, err)
+	}
+	if rel.Info.Status.Code == release.Status_DELETED {
+		return nil, fmt.Errorf("release %q is deleted", req.Name)
+	}
+	kc, err := s.releaseCluster(rel)
+	if err != nil {
+		return nil, err
+	}
+
+	live, err := kc.env.KubeClient.LiveObjects(rel.Namespace, bytes.NewBufferString(rel.Manifest))
+	if err != nil {
+		return nil, fmt.Errorf("looking up the resources of release %q: %s", rel.Name, err)
+	}
+	snapshot, err := snapshotManifest(rel.Manifest, live)
+	if err != nil {
+		return nil, fmt.Errorf("taking a snapshot of release %q: %s", rel.Name, err)
+	}
+	snapshot.Taken = timeconv.Now()
+	rel.Snapshot = snapshot
+	if err := s.env.Releases.Update(rel); err != nil {
+		return nil, err
+	}
+
+	log := s.requestLogger("snapshot", rel.Name, rel.Version)
+	log.Infof("Snapshot taken by %s", caller(c))
+	return &services.SnapshotReleaseResponse{Release: rel}, nil
+}
+
+// snapshotManifest writes the live objects of the resources in manifest, as
+// returned by LiveObjects, to a manifest in the same order, without the fields
+// that the API server manages. Each document keeps the "# Source:" comment of
+// the document it replaces. Resources without a live object are listed as
+// missing.
+func snapshotManifest(manifest string, live map[string]interface{}) (*release.Snapshot, error) {
+	snapshot := &release.Snapshot{}
+	docs := relutil.SplitManifests(manifest)
+	var b bytes.Buffer
+	// SplitManifests numbers the documents in the order of the manifest.
+	for i := 0; i < len(docs); i++ {
+		content := docs[fmt.Sprintf("manifest-%d", i)]
+		head := manifestHead(content)
+		if head == nil {
+			continue
+		}
+		key := head.Kind + "/" + head.Metadata.Name
+		obj, ok := live[key].(map[string]interface{})
+		if !ok {
+			snapshot.Missing = append(snapshot.Missing, key)
+			continue
+		}
+		kube.StripServerFields(obj)
+		data, err := yaml.Marshal(obj)
+		if err != nil {
+			return nil, fmt.Errorf("cannot encode %s: %s", key, err)
+		}
+		b.WriteString("---\n")
+		if src := relutil.ManifestSource(content); src != "" {
+			fmt.Fprintf(&b, "# Source: %s\n", src)
+		}
+		b.Write(data)
+	}
+	snapshot.Manifest = b.String()
+	return snapshot, nil
+}
@@ -0,0 +1,190 @@
+/*
+Copyright 2017 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tiller
+
+import (
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"k8s.io/helm/pkg/helm"
+	"k8s.io/helm/pkg/proto/hapi/release"
+	"k8s.io/helm/pkg/proto/hapi/services"
+	"k8s.io/helm/pkg/tiller/environment"
+)
+
+var snapshotManifestFixture = `---
+# Source: hello/templates/web.yaml
+apiVersion: v1
+kind: ReplicationController
+metadata:
+  name: web
+spec:
+  replicas: 1
+---
+# Source: hello/templates/settings.yaml
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: settings
+data:
+  greeting: hello
+---
+# Source: hello/templates/web-svc.yaml
+apiVersion: v1
+kind: Service
+metadata:
+  name: web
+`
+
+// liveSnapshotObjects returns the live objects of snapshotManifestFixture,
+// scaled and edited outside of Helm. The Service is missing.
+func liveSnapshotObjects() map[string]interface{} {
+	return map[string]interface{}{
+		"ReplicationController/web": map[string]interface{}{
+			"apiVersion": "v1",
+			"kind":       "ReplicationController",
+			"metadata":   map[string]interface{}{"name": "web", "uid": "1", "resourceVersion": "42"},
+			"spec":       map[string]interface{}{"replicas": 3},
+			"status":     map[string]interface{}{"replicas": 3},
+		},
+		"ConfigMap/settings": map[string]interface{}{
+			"apiVersion": "v1",
+			"kind":       "ConfigMap",
+			"metadata":   map[string]interface{}{"name": "settings", "uid": "2"},
+			"data":       map[string]interface{}{"greeting": "hi"},
+		},
+	}
+}
+
+func TestSnapshotRelease(t *testing.T) {
+	c := helm.NewContext()
+	rs := rsFixture()
+	rs.env.KubeClient = &liveKubeClient{PrintingKubeClient: environment.PrintingKubeClient{Out: os.Stdout}, objs: liveSnapshotObjects()}
+	rel := releaseStub()
+	rel.Manifest = snapshotManifestFixture
+	rs.env.Releases.Create(rel)
+
+	res, err := rs.SnapshotRelease(c, &services.SnapshotReleaseRequest{Name: rel.Name})
+	if err != nil {
+		t.Fatalf("Failed snapshot: %s", err)
+	}
+	if res.Release.Version != rel.Version {
+		t.Errorf("Expected the snapshot to be recorded in revision %d, got %d", rel.Version, res.Release.Version)
+	}
+
+	stored, err := rs.env.Releases.Get(rel.Name, rel.Version)
+	if err != nil {
+		t.Fatal(err)
+	}
+	snapshot := stored.Snapshot
+	if snapshot == nil || snapshot.Taken == nil {
+		t.Fatalf("Expected a stored snapshot, got %v", snapshot)
+	}
+	expect := `---
+# Source: hello/templates/web.yaml
+apiVersion: v1
+kind: ReplicationController
+metadata:
+  name: web
+spec:
+  replicas: 3
+---
+# Source: hello/templates/settings.yaml
+apiVersion: v1
+data:
+  greeting: hi
+kind: ConfigMap
+metadata:
+  name: settings
+`
+	if snapshot.Manifest != expect {
+		t.Errorf("Expected snapshot manifest:\n%s\ngot:\n%s", expect, snapshot.Manifest)
+	}
+	if len(snapshot.Missing) != 1 || snapshot.Missing[0] != "Service/web" {
+		t.Errorf("Expected the Service to be missing, got %v", snapshot.Missing)
+	}
+	if h, _ := rs.env.Releases.History(rel.Name); len(h) != 1 {
+		t.Errorf("Expected no new revision, got %d revisions", len(h))
+	}
+}
+
+func TestSnapshotRelease_Deleted(t *testing.T) {
+	c := helm.NewContext()
+	rs := rsFixture()
+	rel := releaseStub()
+	rel.Info.Status.Code = release.Status_DELETED
+	rs.env.Releases.Create(rel)
+
+	_, err := rs.SnapshotRelease(c, &services.SnapshotReleaseRequest{Name: rel.Name})
+	if err == nil || !strings.Contains(err.Error(), "is deleted") {
+		t.Errorf("Expected deleted releases to be refused, got %v", err)
+	}
+}
+
+func TestSnapshotRelease_Locked(t *testing.T) {
+	rs := rsFixture()
+	rel := releaseStub()
+	rs.env.Releases.Create(rel)
+	if err := rs.lockRelease(rel.Name); err != nil {
+		t.Fatal(err)
+	}
+	defer rs.env.Releases.UnlockRelease(rel.Name)
+
+	// The snapshot waits for the lock as long as other operations do.
+	rs.SetLockTimeout(time.Millisecond)
+	if _, err := rs.SnapshotRelease(helm.NewContext(), &services.SnapshotReleaseRequest{Name: rel.Name}); err == nil {
+		t.Error("Expected the snapshot to give up on the lock")
+	}
+}
+
+func TestRollbackRelease_FromSnapshot(t *testing.T) {
+	c := helm.NewContext()
+	rs := rsFixture()
+	rel := releaseStub()
+	rel.Info.Status.Code = release.Status_SUPERSEDED
+	rs.env.Releases.Create(rel)
+	upgraded := upgradeReleaseVersion(rel)
+	rs.env.Releases.Create(upgraded)
+
+	req := &services.RollbackReleaseRequest{Name: rel.Name, Version: rel.Version, FromSnapshot: true}
+	if _, err := rs.RollbackRelease(c, req); err == nil || !strings.Contains(err.Error(), "has no snapshot") {
+		t.Fatalf("Expected a rollback to a revision without snapshot to fail, got %v", err)
+	}
+
+	rel.Snapshot = &release.Snapshot{Manifest: "---\napiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: settings\n"}
+	rs.env.Releases.Update(rel)
+	res, err := rs.RollbackRelease(c, req)
+	if err != nil {
+		t.Fatalf("Failed rollback: %s", err)
+	}
+	if res.Release.Manifest != rel.Snapshot.Manifest {
+		t.Errorf("Expected the snapshot to be applied, got manifest:\n%s", res.Release.Manifest)
+	}
+	if res.Release.Snapshot != nil {
+		t.Error("Expected the snapshot not to be copied to the new revision")
+	}
+	if expect := "Rollback to the snapshot of 1"; res.Release.Info.Description != expect {
+		t.Errorf("Expected description %q, got %q", expect, res.Release.Info.Description)
+	}
+
+	req.Partial = true
+	if _, err := rs.RollbackRelease(c, req); err == nil {
+		t.Error("Expected a partial rollback from a snapshot to be refused")
+	}
+}
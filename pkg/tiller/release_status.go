@@ -29,6 +29,9 @@ func (s *ReleaseServer) GetReleaseStatus(c ctx.Context, req *services.GetRelease
 	if !ValidName.MatchString(req.Name) {
 		return nil, errMissingRelease
 	}
+	if err := validateOutputFormat(req.OutputFormat); err != nil {
+		return nil, err
+	}
 
 	var rel *release.Release
 
@@ -54,21 +57,32 @@ func (s *ReleaseServer) GetReleaseStatus(c ctx.Context, req *services.GetRelease
 
 	sc := rel.Info.Status.Code
 	statusResp := &services.GetReleaseStatusResponse{
-		Name:      rel.Name,
-		Namespace: rel.Namespace,
-		Info:      rel.Info,
+		Name:       rel.Name,
+		Namespace:  rel.Namespace,
+		Namespaces: rel.Namespaces,
+		Cluster:    rel.Cluster,
+		Info:       rel.Info,
 	}
 
 	// Ok, we got the status of the release as we had jotted down, now we need to match the
 	// manifest we stashed away with reality from the cluster.
-	resp, err := s.ReleaseModule.Status(rel, req, s.env)
-	if sc == release.Status_DELETED || sc == release.Status_FAILED {
+	var resp string
+	kc, err := s.releaseCluster(rel)
+	if err == nil {
+		resp, err = kc.module.Status(rel, req, kc.env)
+	}
+	switch {
+	case sc == release.Status_DELETED || sc == release.Status_FAILED:
 		// Skip errors if this is already deleted or failed.
-		return statusResp, nil
-	} else if err != nil {
+	case err != nil:
 		s.Log("warning: Get for %s failed: %v", rel.Name, err)
 		return nil, err
+	default:
+		rel.Info.Status.Resources = resp
+	}
+
+	if statusResp.Rendered, err = renderStatus(req.OutputFormat, rel, statusResp); err != nil {
+		return nil, err
 	}
-	rel.Info.Status.Resources = resp
 	return statusResp, nil
 }
@@ -17,6 +17,8 @@ limitations under the License.
 package tiller
 
 import (
+	"k8s.io/helm/pkg/chartutil"
+	"k8s.io/helm/pkg/hooks"
 	"k8s.io/helm/pkg/proto/hapi/release"
 	"k8s.io/helm/pkg/proto/hapi/services"
 	reltesting "k8s.io/helm/pkg/releasetesting"
@@ -35,14 +37,25 @@ func (s *ReleaseServer) RunReleaseTest(req *services.TestReleaseRequest, stream
 		return err
 	}
 
+	kc, err := s.releaseCluster(rel)
+	if err != nil {
+		return err
+	}
+
 	testEnv := &reltesting.Environment{
 		Namespace:  rel.Namespace,
-		KubeClient: s.env.KubeClient,
+		KubeClient: kc.env.KubeClient,
 		Timeout:    req.Timeout,
 		Stream:     stream,
 	}
 
-	tSuite, err := reltesting.NewTestSuite(rel)
+	testRel, err := s.withTestValues(rel, kc)
+	if err != nil {
+		s.Log("Error rendering the tests of %s with their test values: %s", rel.Name, err)
+		return err
+	}
+
+	tSuite, err := reltesting.NewTestSuite(testRel)
 	if err != nil {
 		s.Log("Error creating test suite for %s", rel.Name)
 		return err
@@ -69,3 +82,46 @@ func (s *ReleaseServer) RunReleaseTest(req *services.TestReleaseRequest, stream
 
 	return nil
 }
+
+// withTestValues returns rel with its test hooks rendered with the test values
+// of its chart merged over its values, and the values that mutators set for
+// it replayed over those. rel is returned as is if its chart has no test
+// values. The test values are only used to run the tests: rel is left
+// unchanged, so they never become part of the stored release.
+func (s *ReleaseServer) withTestValues(rel *release.Release, kc *kubeCluster) (*release.Release, error) {
+	if rel.Chart == nil || !chartutil.HasTestValues(rel.Chart) {
+		return rel, nil
+	}
+	vals, err := chartutil.ApplyTestValues(rel.Chart, rel.Config)
+	if err != nil {
+		return nil, err
+	}
+	caps, err := s.clusterCapabilities(rel.Cluster, kc)
+	if err != nil {
+		return nil, err
+	}
+	options := chartutil.ReleaseOptions{
+		Name:      rel.Name,
+		Time:      rel.Info.LastDeployed,
+		Namespace: rel.Namespace,
+		Revision:  int(rel.Version),
+		IsInstall: rel.Version == 1,
+		IsUpgrade: rel.Version > 1,
+		Flags:     rel.Info.Flags,
+	}
+	valuesToRender, err := chartutil.ToRenderValuesCaps(rel.Chart, vals, options, caps)
+	if err != nil {
+		return nil, err
+	}
+	if err := replayMutations(rel, valuesToRender); err != nil {
+		return nil, err
+	}
+	rendered, _, _, _, err := s.renderResources(rel.Chart, valuesToRender, caps.APIVersions, generatedValues(rel))
+	if err != nil {
+		return nil, err
+	}
+
+	testRel := *rel
+	testRel.Hooks = hooks.FilterTestHooks(rendered)
+	return &testRel, nil
+}
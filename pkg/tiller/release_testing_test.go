@@ -17,9 +17,20 @@ limitations under the License.
 package tiller
 
 import (
+	"errors"
+	"io"
+	"io/ioutil"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/golang/protobuf/ptypes/any"
+
+	"k8s.io/helm/pkg/chartutil"
+	"k8s.io/helm/pkg/proto/hapi/chart"
 	"k8s.io/helm/pkg/proto/hapi/release"
 	"k8s.io/helm/pkg/proto/hapi/services"
-	"testing"
+	"k8s.io/helm/pkg/tiller/environment"
 )
 
 func TestRunReleaseTest(t *testing.T) {
@@ -33,3 +44,103 @@ func TestRunReleaseTest(t *testing.T) {
 		t.Fatalf("failed to run release tests on %s: %s", rel.Name, err)
 	}
 }
+
+// testPodKubeClient records the manifests of the test pods it creates.
+type testPodKubeClient struct {
+	environment.PrintingKubeClient
+	created []string
+}
+
+func (k *testPodKubeClient) Create(ns string, r io.Reader, timeout int64, shouldWait bool) error {
+	b, err := ioutil.ReadAll(r)
+	k.created = append(k.created, string(b))
+	return err
+}
+
+func TestRunReleaseTest_TestValues(t *testing.T) {
+	testHook := "apiVersion: v1\nkind: Pod\nmetadata:\n  name: {{ .Release.Name }}-test\n  annotations:\n    \"helm.sh/hook\": test-success\nspec:\n  containers:\n  - name: test\n    image: busybox\n    args: [wget, \"{{ .Values.url }}\"]\n"
+	ch := chartStub()
+	ch.Templates = append(ch.Templates, &chart.Template{Name: "templates/test.yaml", Data: []byte(testHook)})
+	ch.Values = &chart.Config{Raw: "url: http://web\n"}
+
+	for _, tt := range []struct {
+		name   string
+		files  []*any.Any
+		expect string
+	}{
+		{"without test values", nil, "http://web"},
+		{"with test values", []*any.Any{{TypeUrl: "values-test.yaml", Value: []byte("url: http://web/healthz\n")}}, "http://web/healthz"},
+	} {
+		rs := rsFixture()
+		kc := &testPodKubeClient{PrintingKubeClient: environment.PrintingKubeClient{Out: os.Stdout}}
+		rs.env.KubeClient = kc
+		ch.Files = tt.files
+		rel := releaseStub()
+		rel.Chart = ch
+		rel.Config = &chart.Config{Raw: "url: http://web\n"}
+		rel.Hooks = []*release.Hook{{
+			Name:     "test",
+			Kind:     "Pod",
+			Path:     "test.yaml",
+			Manifest: strings.Replace(strings.Replace(testHook, "{{ .Release.Name }}", rel.Name, 1), "{{ .Values.url }}", "http://web", 1),
+			Events:   []release.Hook_Event{release.Hook_RELEASE_TEST_SUCCESS},
+		}}
+		rs.env.Releases.Create(rel)
+
+		if err := rs.RunReleaseTest(&services.TestReleaseRequest{Name: rel.Name, Timeout: 2}, mockRunReleaseTestServer{}); err != nil {
+			t.Fatalf("%s: failed to run release tests: %s", tt.name, err)
+		}
+		if len(kc.created) != 1 || !strings.Contains(kc.created[0], "args: [wget, \""+tt.expect+"\"]") {
+			t.Errorf("%s: expected the test pod to get %s, got %v", tt.name, tt.expect, kc.created)
+		}
+
+		// The test values are not stored with the release.
+		stored, err := rs.env.Releases.Get(rel.Name, rel.Version)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if stored.Config.Raw != "url: http://web\n" || !strings.Contains(stored.Hooks[0].Manifest, "\"http://web\"]") {
+			t.Errorf("%s: expected the release to be stored without test values, got %q", tt.name, stored.Config.Raw)
+		}
+		if stored.Info.Status.LastTestSuiteRun == nil {
+			t.Errorf("%s: expected the test run to be recorded", tt.name)
+		}
+	}
+}
+
+func TestRunReleaseTest_TestValuesReplayMutations(t *testing.T) {
+	testHook := "apiVersion: v1\nkind: Pod\nmetadata:\n  name: {{ .Release.Name }}-test\n  annotations:\n    \"helm.sh/hook\": test-success\nspec:\n  containers:\n  - name: test\n    image: busybox\n    args: [wget, \"{{ .Values.url }}\", \"{{ .Values.network.ip }}\"]\n"
+	ch := chartStub()
+	ch.Templates = append(ch.Templates, &chart.Template{Name: "templates/test.yaml", Data: []byte(testHook)})
+	ch.Values = &chart.Config{Raw: "url: http://web\n"}
+	ch.Files = []*any.Any{{TypeUrl: "values-test.yaml", Value: []byte("url: http://web/healthz\n")}}
+
+	rs := rsFixture()
+	kc := &testPodKubeClient{PrintingKubeClient: environment.PrintingKubeClient{Out: os.Stdout}}
+	rs.env.KubeClient = kc
+	// The mutators ran when the release was rendered, and must not run again
+	// for its tests.
+	rs.AddValuesMutator("ipam", ValuesMutatorFunc(func(_ chartutil.ReleaseOptions, _ *chart.Metadata, _ chartutil.Values) (chartutil.Values, error) {
+		return nil, errors.New("ran again")
+	}))
+	rel := releaseStub()
+	rel.Chart = ch
+	rel.Config = &chart.Config{Raw: "url: http://web\n"}
+	rel.Info.ValuesMutations = []*release.ValuesMutation{{Mutator: "ipam", Changed: []string{"network.ip"}}}
+	rel.Hooks = []*release.Hook{{Name: "test", Kind: "Pod", Path: "test.yaml", Events: []release.Hook_Event{release.Hook_RELEASE_TEST_SUCCESS}}}
+	rs.env.Releases.Create(rel)
+
+	err := rs.RunReleaseTest(&services.TestReleaseRequest{Name: rel.Name, Timeout: 2}, mockRunReleaseTestServer{})
+	if err == nil || !strings.Contains(err.Error(), "did not store") {
+		t.Errorf("Expected the test values to need the computed values, got %v", err)
+	}
+
+	rel.ComputedValues = &chart.Config{Raw: "url: http://web\nnetwork:\n  ip: 10.0.0.7\n"}
+	rs.env.Releases.Update(rel)
+	if err := rs.RunReleaseTest(&services.TestReleaseRequest{Name: rel.Name, Timeout: 2}, mockRunReleaseTestServer{}); err != nil {
+		t.Fatalf("Failed to run release tests: %s", err)
+	}
+	if len(kc.created) != 1 || !strings.Contains(kc.created[0], `args: [wget, "http://web/healthz", "10.0.0.7"]`) {
+		t.Errorf("Expected the test pod to get the test values and the recorded mutations, got %v", kc.created)
+	}
+}
@@ -19,7 +19,9 @@ package tiller
 import (
 	"fmt"
 	ctx "golang.org/x/net/context"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/helm/pkg/hooks"
+	"k8s.io/helm/pkg/kube"
 	"k8s.io/helm/pkg/proto/hapi/release"
 	"k8s.io/helm/pkg/proto/hapi/services"
 	relutil "k8s.io/helm/pkg/releaseutil"
@@ -28,8 +30,20 @@ import (
 )
 
 // UninstallRelease deletes all of the resources associated with this release, and marks the release DELETED.
+//
+// Identical concurrent uninstall requests are only performed once; see operationKey.
 func (s *ReleaseServer) UninstallRelease(c ctx.Context, req *services.UninstallReleaseRequest) (*services.UninstallReleaseResponse, error) {
-	err := s.env.Releases.LockRelease(req.Name)
+	done := observeOperation("uninstall")
+	res, err := s.dedupe("uninstall", req.Name, req, func() (interface{}, error) {
+		return s.uninstallRelease(req)
+	})
+	done(err)
+	resp, _ := res.(*services.UninstallReleaseResponse)
+	return resp, err
+}
+
+func (s *ReleaseServer) uninstallRelease(req *services.UninstallReleaseRequest) (*services.UninstallReleaseResponse, error) {
+	err := s.lockRelease(req.Name)
 	if err != nil {
 		return nil, err
 	}
@@ -40,8 +54,23 @@ func (s *ReleaseServer) UninstallRelease(c ctx.Context, req *services.UninstallR
 		return nil, errMissingRelease
 	}
 
-	if len(req.Name) > releaseNameMaxLen {
-		return nil, fmt.Errorf("release name %q exceeds max length of %d", req.Name, releaseNameMaxLen)
+	// Releases named under a longer limit than the current one can still be
+	// deleted.
+	if max := s.nameMaxLen(); len(req.Name) > max && len(req.Name) > releaseNameMaxLen {
+		if max < releaseNameMaxLen {
+			max = releaseNameMaxLen
+		}
+		return nil, fmt.Errorf("release name %q exceeds max length of %d", req.Name, max)
+	}
+
+	if req.PropagationPolicy != "" {
+		if _, err := kube.ParsePropagationPolicy(req.PropagationPolicy); err != nil {
+			return nil, err
+		}
+	} else if req.Wait {
+		// Foreground deletion keeps each resource until its dependents are
+		// gone, so waiting for the resource covers the dependents too.
+		req.PropagationPolicy = string(metav1.DeletePropagationForeground)
 	}
 
 	rels, err := s.env.Releases.History(req.Name)
@@ -55,28 +84,43 @@ func (s *ReleaseServer) UninstallRelease(c ctx.Context, req *services.UninstallR
 
 	relutil.SortByRevision(rels)
 	rel := rels[len(rels)-1]
+	log := s.requestLogger("uninstall", rel.Name, rel.Version)
 
 	// TODO: Are there any cases where we want to force a delete even if it's
 	// already marked deleted?
 	if rel.Info.Status.Code == release.Status_DELETED {
 		if req.Purge {
 			if err := s.purgeReleases(rels...); err != nil {
-				s.Log("uninstall: Failed to purge the release: %s", err)
+				log.Errorf("Failed to purge the release: %s", err)
 				return nil, err
 			}
 			return &services.UninstallReleaseResponse{Release: rel}, nil
 		}
 		return nil, fmt.Errorf("the release named %q is already deleted", req.Name)
 	}
+	kc, err := s.releaseCluster(rel)
+	if err != nil {
+		return nil, err
+	}
+	if kc, err = kc.impersonate(req.Impersonation); err != nil {
+		return nil, err
+	}
+	rel.Info.Impersonation = req.Impersonation
 
-	s.Log("uninstall: Deleting %s", req.Name)
+	if len(rel.Namespaces) > 1 {
+		log.Infof("Deleting %s from namespaces %s", req.Name, strings.Join(rel.Namespaces, ", "))
+	} else {
+		log.Infof("Deleting %s", req.Name)
+	}
 	rel.Info.Status.Code = release.Status_DELETING
 	rel.Info.Deleted = timeconv.Now()
 	rel.Info.Description = "Deletion in progress (or silently failed)"
 	res := &services.UninstallReleaseResponse{Release: rel}
+	skip := newHookSkipList(req.SkipHooks, req.SkipHookWeights)
+	runHooks := s.runHooks(log, "uninstall", req.DisableHooks, req.EnableHooks)
 
-	if !req.DisableHooks {
-		if err := s.execHook(rel.Hooks, rel.Name, rel.Namespace, hooks.PreDelete, req.Timeout); err != nil {
+	if runHooks {
+		if err := s.execHook(log, kc.env.KubeClient, rel, hooks.PreDelete, req.Timeout, skip); err != nil {
 			return res, err
 		}
 	}
@@ -84,38 +128,54 @@ func (s *ReleaseServer) UninstallRelease(c ctx.Context, req *services.UninstallR
 	// From here on out, the release is currently considered to be in Status_DELETING
 	// state.
 	if err := s.env.Releases.Update(rel); err != nil {
-		s.Log("uninstall: Failed to store updated release: %s", err)
+		log.Warnf("Failed to store updated release: %s", err)
 	}
+	s.emitEvent(rel)
 
-	kept, errs := s.ReleaseModule.Delete(rel, req, s.env)
+	kept, errs := kc.module.Delete(rel, req, kc.env)
 	res.Info = kept
+	if kept != "" {
+		// Kept resources are orphaned, so make sure operators can find them.
+		log.Infof("%s", strings.TrimSpace(kept))
+	}
 
 	es := make([]string, 0, len(errs))
 	for _, e := range errs {
-		s.Log("error: %v", e)
+		log.Errorf("%v", e)
 		es = append(es, e.Error())
 	}
 
-	if !req.DisableHooks {
-		if err := s.execHook(rel.Hooks, rel.Name, rel.Namespace, hooks.PostDelete, req.Timeout); err != nil {
+	if runHooks {
+		if err := s.execHook(log, kc.env.KubeClient, rel, hooks.PostDelete, req.Timeout, skip); err != nil {
 			es = append(es, err.Error())
 		}
 	}
 
+	if req.DeleteHookResources {
+		cleaned, errs := deleteHookResources(log, kc.env.KubeClient, rels)
+		res.Info += cleaned
+		for _, e := range errs {
+			log.Errorf("%v", e)
+			es = append(es, e.Error())
+		}
+	}
+
 	rel.Info.Status.Code = release.Status_DELETED
 	rel.Info.Description = "Deletion complete"
 
 	if req.Purge {
+		s.emitEvent(rel)
 		err := s.purgeReleases(rels...)
 		if err != nil {
-			s.Log("uninstall: Failed to purge the release: %s", err)
+			log.Errorf("Failed to purge the release: %s", err)
 		}
 		return res, err
 	}
 
 	if err := s.env.Releases.Update(rel); err != nil {
-		s.Log("uninstall: Failed to store updated release: %s", err)
+		log.Warnf("Failed to store updated release: %s", err)
 	}
+	s.emitEvent(rel)
 
 	if len(es) > 0 {
 		return res, fmt.Errorf("deletion completed with %d error(s): %s", len(es), strings.Join(es, "; "))
@@ -17,9 +17,15 @@ limitations under the License.
 package tiller
 
 import (
+	"io"
+	"io/ioutil"
 	"k8s.io/helm/pkg/helm"
+	"k8s.io/helm/pkg/kube"
 	"k8s.io/helm/pkg/proto/hapi/release"
 	"k8s.io/helm/pkg/proto/hapi/services"
+	"k8s.io/helm/pkg/tiller/environment"
+	"os"
+	"reflect"
 	"strings"
 	"testing"
 )
@@ -175,3 +181,154 @@ func TestUninstallReleaseNoHooks(t *testing.T) {
 		t.Errorf("Expected LastRun to be zero, got %d.", res.Release.Hooks[0].LastRun.Seconds)
 	}
 }
+
+type deleteRecordingKubeClient struct {
+	environment.PrintingKubeClient
+	policies []string
+	timeouts []int64
+	waits    []bool
+}
+
+func (d *deleteRecordingKubeClient) DeleteWithPolicy(ns string, r io.Reader, policy string, timeout int64, shouldWait bool) error {
+	d.policies = append(d.policies, policy)
+	d.timeouts = append(d.timeouts, timeout)
+	d.waits = append(d.waits, shouldWait)
+	return nil
+}
+
+func TestUninstallReleasePropagationPolicy(t *testing.T) {
+	for _, tt := range []struct {
+		policy string
+		wait   bool
+		expect string
+	}{
+		{"", false, ""},
+		{"Orphan", false, "Orphan"},
+		{"Background", true, "Background"},
+		// Foreground is the default when waiting, so that dependents are gone
+		// before the release is reported deleted.
+		{"", true, "Foreground"},
+	} {
+		rs := rsFixture()
+		kc := &deleteRecordingKubeClient{PrintingKubeClient: environment.PrintingKubeClient{Out: os.Stdout}}
+		rs.env.KubeClient = kc
+		rel := releaseStub()
+		rel.Manifest = "---\n# Source: hello/templates/cm.yaml\napiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: test-cm\n"
+		rs.env.Releases.Create(rel)
+
+		req := &services.UninstallReleaseRequest{
+			Name:              "angry-panda",
+			DisableHooks:      true,
+			PropagationPolicy: tt.policy,
+			Wait:              tt.wait,
+			Timeout:           42,
+		}
+		if _, err := rs.UninstallRelease(helm.NewContext(), req); err != nil {
+			t.Fatalf("policy %q, wait %t: failed uninstall: %s", tt.policy, tt.wait, err)
+		}
+		if len(kc.policies) == 0 {
+			t.Fatalf("policy %q, wait %t: expected resources to be deleted", tt.policy, tt.wait)
+		}
+		for i, p := range kc.policies {
+			if p != tt.expect {
+				t.Errorf("policy %q, wait %t: expected policy %q, got %q", tt.policy, tt.wait, tt.expect, p)
+			}
+			if kc.waits[i] != tt.wait || kc.timeouts[i] != 42 {
+				t.Errorf("policy %q, wait %t: expected wait %t with timeout 42, got %t with %d", tt.policy, tt.wait, tt.wait, kc.waits[i], kc.timeouts[i])
+			}
+		}
+	}
+}
+
+func TestUninstallReleaseInvalidPropagationPolicy(t *testing.T) {
+	rs := rsFixture()
+	rs.env.Releases.Create(releaseStub())
+
+	req := &services.UninstallReleaseRequest{
+		Name:              "angry-panda",
+		PropagationPolicy: "Sideways",
+	}
+	if _, err := rs.UninstallRelease(helm.NewContext(), req); err == nil {
+		t.Fatal("expected an error for an invalid propagation policy")
+	}
+	rel, err := rs.env.Releases.Get("angry-panda", 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rel.Info.Status.Code != release.Status_DEPLOYED {
+		t.Errorf("expected the release to be left alone, got status %s", rel.Info.Status.Code)
+	}
+}
+
+// hookCleanupKubeClient finds every resource, except those it is told are
+// missing, and records the resources it deletes.
+type hookCleanupKubeClient struct {
+	environment.PrintingKubeClient
+	missing map[string]bool
+	deleted []string
+}
+
+func (h *hookCleanupKubeClient) Drift(ns string, r io.Reader) ([]kube.ResourceDrift, error) {
+	b, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	head := manifestHead(string(b))
+	return []kube.ResourceDrift{{Kind: head.Kind, Name: head.Metadata.Name, Missing: h.missing[head.Metadata.Name]}}, nil
+}
+
+func (h *hookCleanupKubeClient) Delete(ns string, r io.Reader) error {
+	b, err := ioutil.ReadAll(r)
+	if err != nil {
+		return err
+	}
+	head := manifestHead(string(b))
+	h.deleted = append(h.deleted, head.Metadata.Name)
+	return nil
+}
+
+func TestUninstallReleaseDeleteHookResources(t *testing.T) {
+	for _, del := range []bool{false, true} {
+		rs := rsFixture()
+		kc := &hookCleanupKubeClient{
+			PrintingKubeClient: environment.PrintingKubeClient{Out: os.Stdout},
+			missing:            map[string]bool{"migrate": true},
+		}
+		rs.env.KubeClient = kc
+		rel := releaseStub()
+		rs.env.Releases.Create(rel)
+		upgraded := upgradeReleaseVersion(rel)
+		upgraded.Hooks = []*release.Hook{
+			{Name: "test-cm", Kind: "ConfigMap", Manifest: manifestWithHook},
+			{Name: "test-cm-keep", Kind: "ConfigMap", Manifest: manifestWithKeep},
+			{Name: "migrate", Kind: "Job", Manifest: "apiVersion: batch/v1\nkind: Job\nmetadata:\n  name: migrate\n"},
+		}
+		rs.env.Releases.Update(rel)
+		rs.env.Releases.Create(upgraded)
+
+		res, err := rs.UninstallRelease(helm.NewContext(), &services.UninstallReleaseRequest{
+			Name:                rel.Name,
+			DisableHooks:        true,
+			DeleteHookResources: del,
+		})
+		if err != nil {
+			t.Fatalf("delete hook resources %t: failed uninstall: %s", del, err)
+		}
+		if !del {
+			if len(kc.deleted) > 0 || strings.Contains(res.Info, "hook resources") {
+				t.Errorf("Expected hook resources to be left alone, deleted %v: %q", kc.deleted, res.Info)
+			}
+			continue
+		}
+
+		// A hook shared by both revisions is deleted once, and a missing one
+		// not at all.
+		if expect := []string{"test-cm", "finding-nemo,"}; !reflect.DeepEqual(kc.deleted, expect) {
+			t.Errorf("Expected %v to be deleted, got %v", expect, kc.deleted)
+		}
+		expect := "These hook resources were deleted:\n[ConfigMap] test-cm\n[Pod] finding-nemo,\nThese hook resources were kept due to the resource policy:\n[ConfigMap] test-cm-keep\n"
+		if !strings.HasSuffix(res.Info, expect) {
+			t.Errorf("Expected the info to end with %q, got %q", expect, res.Info)
+		}
+	}
+}
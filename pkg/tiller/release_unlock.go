@@ -0,0 +1,91 @@
+/*
+Copyright 2017 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tiller
+
+import (
+	"errors"
+	"fmt"
+
+	ctx "golang.org/x/net/context"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/peer"
+
+	"k8s.io/helm/pkg/proto/hapi/release"
+	"k8s.io/helm/pkg/proto/hapi/services"
+	relutil "k8s.io/helm/pkg/releaseutil"
+)
+
+var errUnconfirmedUnlock = errors.New("force unlock must be confirmed by setting confirm to the release name")
+
+// ForceUnlock clears the lock on a release so that it can be operated on
+// again. It does not wait for the lock, and it does not stop an operation
+// that holds it.
+//
+// The request must repeat the release name in Confirm. A warning is returned
+// if the latest revision is still in progress, as the operation that locked
+// the release may still be running.
+func (s *ReleaseServer) ForceUnlock(c ctx.Context, req *services.ForceUnlockRequest) (*services.ForceUnlockResponse, error) {
+	if !ValidName.MatchString(req.Name) {
+		return nil, errMissingRelease
+	}
+	if req.Confirm != req.Name {
+		return nil, errUnconfirmedUnlock
+	}
+
+	h, err := s.env.Releases.History(req.Name)
+	if err != nil || len(h) == 0 {
+		return nil, fmt.Errorf("release: %q not found", req.Name)
+	}
+	relutil.Reverse(h, relutil.SortByRevision)
+	rel := h[0]
+
+	res := &services.ForceUnlockResponse{}
+	if inProgress(rel) {
+		res.Warning = fmt.Sprintf("revision %d of %q is %s; the operation that locked it may still be running", rel.Version, rel.Name, rel.Info.Status.Code)
+	}
+
+	log := s.requestLogger("force-unlock", rel.Name, rel.Version)
+	res.WasLocked = s.env.Releases.ForceUnlockRelease(req.Name)
+	log.Warnf("Release lock cleared by %s (was locked: %t)", caller(c), res.WasLocked)
+	if res.Warning != "" {
+		log.Warnf("%s", res.Warning)
+	}
+	return res, nil
+}
+
+// inProgress reports whether the stored status of r is one that an
+// operation leaves behind while it runs.
+func inProgress(r *release.Release) bool {
+	switch r.Info.Status.Code {
+	case release.Status_UNKNOWN, release.Status_DELETING, release.Status_AWAITING_APPROVAL:
+		return true
+	}
+	return false
+}
+
+// caller describes the client that made a request: its address and, when
+// it authenticated with a TLS certificate, the certificate's common name.
+func caller(c ctx.Context) string {
+	p, ok := peer.FromContext(c)
+	if !ok || p.Addr == nil {
+		return "unknown client"
+	}
+	if tlsInfo, ok := p.AuthInfo.(credentials.TLSInfo); ok && len(tlsInfo.State.PeerCertificates) > 0 {
+		return fmt.Sprintf("%q at %s", tlsInfo.State.PeerCertificates[0].Subject.CommonName, p.Addr)
+	}
+	return p.Addr.String()
+}
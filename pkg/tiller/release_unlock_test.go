@@ -0,0 +1,126 @@
+/*
+Copyright 2017 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tiller
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"golang.org/x/net/context"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/peer"
+
+	"k8s.io/helm/pkg/helm"
+	"k8s.io/helm/pkg/proto/hapi/release"
+	"k8s.io/helm/pkg/proto/hapi/services"
+)
+
+func TestForceUnlock(t *testing.T) {
+	c := helm.NewContext()
+	rs := rsFixture()
+	rs.env.Releases.Create(releaseStub())
+
+	if err := rs.env.Releases.LockRelease("angry-panda"); err != nil {
+		t.Fatal(err)
+	}
+	res, err := rs.ForceUnlock(c, &services.ForceUnlockRequest{Name: "angry-panda", Confirm: "angry-panda"})
+	if err != nil {
+		t.Fatalf("Failed force unlock: %s", err)
+	}
+	if !res.WasLocked {
+		t.Error("Expected the release to have been locked")
+	}
+	if res.Warning != "" {
+		t.Errorf("Expected no warning for a deployed release, got %q", res.Warning)
+	}
+
+	// The lock is free again.
+	if err := rs.lockRelease("angry-panda"); err != nil {
+		t.Errorf("Expected to lock the release after a force unlock, got %s", err)
+	}
+	rs.env.Releases.UnlockRelease("angry-panda")
+
+	res, err = rs.ForceUnlock(c, &services.ForceUnlockRequest{Name: "angry-panda", Confirm: "angry-panda"})
+	if err != nil {
+		t.Fatalf("Failed force unlock: %s", err)
+	}
+	if res.WasLocked {
+		t.Error("Expected the release not to have been locked")
+	}
+}
+
+func TestForceUnlock_InProgress(t *testing.T) {
+	rs := rsFixture()
+	rs.env.Releases.Create(namedReleaseStub("angry-panda", release.Status_DELETING))
+
+	res, err := rs.ForceUnlock(helm.NewContext(), &services.ForceUnlockRequest{Name: "angry-panda", Confirm: "angry-panda"})
+	if err != nil {
+		t.Fatalf("Failed force unlock: %s", err)
+	}
+	if !strings.Contains(res.Warning, "DELETING") {
+		t.Errorf("Expected a warning about the operation in progress, got %q", res.Warning)
+	}
+}
+
+func TestForceUnlock_Errors(t *testing.T) {
+	rs := rsFixture()
+	rs.env.Releases.Create(releaseStub())
+	rs.env.Releases.LockRelease("angry-panda")
+
+	for _, req := range []*services.ForceUnlockRequest{
+		{Name: "angry-panda"},
+		{Name: "angry-panda", Confirm: "angry-pandas"},
+		{Name: "no-such-release", Confirm: "no-such-release"},
+		{Name: "not a name", Confirm: "not a name"},
+	} {
+		if _, err := rs.ForceUnlock(helm.NewContext(), req); err == nil {
+			t.Errorf("Expected an error for %v", req)
+		}
+	}
+
+	// None of the requests may have cleared the lock.
+	rs.SetLockTimeout(time.Millisecond)
+	if err := rs.lockRelease("angry-panda"); err == nil {
+		t.Error("Expected the release to still be locked")
+	}
+}
+
+func TestCaller(t *testing.T) {
+	addr := &net.TCPAddr{IP: net.ParseIP("10.0.0.1"), Port: 5000}
+	cert := &x509.Certificate{Subject: pkix.Name{CommonName: "ops"}}
+
+	for _, tt := range []struct {
+		ctx    context.Context
+		expect string
+	}{
+		{context.Background(), "unknown client"},
+		{peer.NewContext(context.Background(), &peer.Peer{Addr: addr}), "10.0.0.1:5000"},
+		{peer.NewContext(context.Background(), &peer.Peer{
+			Addr:     addr,
+			AuthInfo: credentials.TLSInfo{State: tls.ConnectionState{PeerCertificates: []*x509.Certificate{cert}}},
+		}), `"ops" at 10.0.0.1:5000`},
+	} {
+		if got := caller(tt.ctx); got != tt.expect {
+			t.Errorf("Expected %q, got %q", tt.expect, got)
+		}
+	}
+}
@@ -21,143 +21,310 @@ import (
 	ctx "golang.org/x/net/context"
 	"k8s.io/helm/pkg/chartutil"
 	"k8s.io/helm/pkg/hooks"
+	"k8s.io/helm/pkg/kube"
+	"k8s.io/helm/pkg/logging"
 	"k8s.io/helm/pkg/proto/hapi/release"
 	"k8s.io/helm/pkg/proto/hapi/services"
 	"k8s.io/helm/pkg/timeconv"
 )
 
 // UpdateRelease takes an existing release and new information, and upgrades the release.
+//
+// Identical concurrent update requests are only performed once; see operationKey.
 func (s *ReleaseServer) UpdateRelease(c ctx.Context, req *services.UpdateReleaseRequest) (*services.UpdateReleaseResponse, error) {
-	err := s.env.Releases.LockRelease(req.Name)
+	done := observeOperation("upgrade")
+	res, err := s.dedupe("update", req.Name, req, func() (interface{}, error) {
+		return s.updateRelease(req)
+	})
+	done(err)
+	resp, _ := res.(*services.UpdateReleaseResponse)
+	return resp, err
+}
+
+func (s *ReleaseServer) updateRelease(req *services.UpdateReleaseRequest) (*services.UpdateReleaseResponse, error) {
+	err := s.lockRelease(req.Name)
 	if err != nil {
 		return nil, err
 	}
 	defer s.env.Releases.UnlockRelease(req.Name)
 
-	currentRelease, updatedRelease, err := s.prepareUpdate(req)
+	currentRelease, updatedRelease, values, err := s.prepareUpdate(req, false)
 	if err != nil {
 		return nil, err
 	}
 
-	res, err := s.performUpdate(currentRelease, updatedRelease, req)
+	log := s.requestLogger("upgrade", updatedRelease.Name, updatedRelease.Version)
+	res, err := s.performUpdate(log, currentRelease, updatedRelease, values, req)
 	if err != nil {
 		return res, err
 	}
 
-	if !req.DryRun {
-		if err := s.env.Releases.Create(updatedRelease); err != nil {
-			return res, err
-		}
+	if req.DryRun {
+		return res, nil
 	}
-
-	return res, nil
+	// An upgrade that waited for approval has already recorded its revision.
+	if req.RequireApproval {
+		return res, s.env.Releases.Update(updatedRelease)
+	}
+	return res, s.env.Releases.Create(updatedRelease)
 }
 
-// prepareUpdate builds an updated release for an update operation.
-func (s *ReleaseServer) prepareUpdate(req *services.UpdateReleaseRequest) (*release.Release, *release.Release, error) {
+// prepareUpdate builds an updated release for an update operation. It also
+// returns the values the updated release was rendered with, for its notes to
+// be rendered again with. If recorded is set, the values mutators are not run;
+// the mutations that the current release recorded are replayed instead.
+func (s *ReleaseServer) prepareUpdate(req *services.UpdateReleaseRequest, recorded bool) (*release.Release, *release.Release, chartutil.Values, error) {
 	if !ValidName.MatchString(req.Name) {
-		return nil, nil, errMissingRelease
+		return nil, nil, nil, errMissingRelease
 	}
 
 	if req.Chart == nil {
-		return nil, nil, errMissingChart
+		return nil, nil, nil, errMissingChart
+	}
+	if err := validateAnnotations(req.Annotations); err != nil {
+		return nil, nil, nil, err
+	}
+	if err := validateFlags(req.Flags); err != nil {
+		return nil, nil, nil, err
+	}
+	if err := s.valuesLimits.Check(req.Chart, req.Values); err != nil {
+		return nil, nil, nil, err
+	}
+	if req.StrictValues {
+		if err := chartutil.CheckUnknownValues(req.Chart, req.Values); err != nil {
+			return nil, nil, nil, err
+		}
+	}
+	opts := kube.UpdateOptions{GracePeriod: req.GracePeriod, PropagationPolicy: req.PropagationPolicy}
+	if err := opts.Validate(); err != nil {
+		return nil, nil, nil, err
+	}
+	if err := validateOnFailure(req.OnFailure); err != nil {
+		return nil, nil, nil, err
 	}
 
 	// finds the non-deleted release with the given name
 	currentRelease, err := s.env.Releases.Last(req.Name)
 	if err != nil {
-		return nil, nil, err
+		return nil, nil, nil, err
+	}
+	if req.Cluster != "" && req.Cluster != currentRelease.Cluster {
+		return nil, nil, nil, fmt.Errorf("release %s is installed in %s, not in %s", req.Name, describeCluster(currentRelease.Cluster), describeCluster(req.Cluster))
+	}
+	kc, err := s.releaseCluster(currentRelease)
+	if err != nil {
+		return nil, nil, nil, err
 	}
 
 	// If new values were not supplied in the upgrade, re-use the existing values.
 	if err := s.reuseValues(req, currentRelease); err != nil {
-		return nil, nil, err
+		return nil, nil, nil, err
+	}
+	// The profile is kept along with the values, and like them only its
+	// name is stored; the values of the profile of the new chart are merged
+	// in for rendering.
+	if req.Profile == "" && !req.ResetValues {
+		req.Profile = currentRelease.Profile
+		req.AllowMissingProfile = req.AllowMissingProfile || currentRelease.AllowMissingProfile
+	}
+	vals := req.Values
+	if req.Profile != "" {
+		if vals, err = chartutil.ApplyProfile(req.Chart, req.Profile, req.Values, req.AllowMissingProfile); err != nil {
+			return nil, nil, nil, err
+		}
 	}
 
 	// Increment revision count. This is passed to templates, and also stored on
 	// the release object.
 	revision := currentRelease.Version + 1
 
+	previous, err := renderedValues(currentRelease)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("reading the values of %s (v%d): %s", currentRelease.Name, currentRelease.Version, err)
+	}
+
 	ts := timeconv.Now()
 	options := chartutil.ReleaseOptions{
-		Name:      req.Name,
-		Time:      ts,
-		Namespace: currentRelease.Namespace,
-		IsUpgrade: true,
-		Revision:  int(revision),
+		Name:           req.Name,
+		Time:           ts,
+		Namespace:      currentRelease.Namespace,
+		IsUpgrade:      true,
+		Revision:       int(revision),
+		PreviousValues: previous,
+		Flags:          req.Flags,
 	}
 
-	caps, err := capabilities(s.clientset.Discovery())
+	caps, err := s.clusterCapabilities(currentRelease.Cluster, kc)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	valuesToRender, err := chartutil.ToRenderValuesCaps(req.Chart, vals, options, caps)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	var mutations []*release.ValuesMutation
+	if recorded {
+		mutations = currentRelease.Info.ValuesMutations
+		err = replayMutations(currentRelease, valuesToRender)
+	} else {
+		mutations, err = s.mutateValues(req.Chart, options, valuesToRender)
+	}
 	if err != nil {
-		return nil, nil, err
+		return nil, nil, nil, err
 	}
-	valuesToRender, err := chartutil.ToRenderValuesCaps(req.Chart, req.Values, options, caps)
+
+	// Values generated by the current release are reused, so that generated
+	// passwords and the like survive the upgrade.
+	generated := generatedValues(currentRelease)
+	hooks, manifestDoc, notesTxt, injections, err := s.renderResources(req.Chart, valuesToRender, caps.APIVersions, generated)
 	if err != nil {
-		return nil, nil, err
+		return nil, nil, nil, err
 	}
 
-	hooks, manifestDoc, notesTxt, err := s.renderResources(req.Chart, valuesToRender, caps.APIVersions)
+	computed, err := s.computedValues(valuesToRender)
 	if err != nil {
-		return nil, nil, err
+		return nil, nil, nil, err
 	}
 
 	// Store an updated release.
 	updatedRelease := &release.Release{
-		Name:      req.Name,
-		Namespace: currentRelease.Namespace,
-		Chart:     req.Chart,
-		Config:    req.Values,
+		Name:            req.Name,
+		Namespace:       currentRelease.Namespace,
+		Chart:           req.Chart,
+		Config:          req.Values,
+		ComputedValues:  computed,
+		GeneratedValues: generated,
+		Profile:         req.Profile,
+		AllowMissingProfile: req.AllowMissingProfile,
 		Info: &release.Info{
-			FirstDeployed: currentRelease.Info.FirstDeployed,
-			LastDeployed:  ts,
-			Status:        &release.Status{Code: release.Status_UNKNOWN},
-			Description:   "Preparing upgrade", // This should be overwritten later.
+			FirstDeployed:       currentRelease.Info.FirstDeployed,
+			LastDeployed:        ts,
+			Status:              &release.Status{Code: release.Status_UNKNOWN},
+			Description:         "Preparing upgrade", // This should be overwritten later.
+			Annotations:         req.Annotations,
+			ValuesMutations:     mutations,
+			ContainerInjections: injections,
+			Impersonation:       req.Impersonation,
+			Flags:               req.Flags,
 		},
 		Version:  revision,
 		Manifest: manifestDoc.String(),
 		Hooks:    hooks,
+		Cluster:  currentRelease.Cluster,
+		System:   currentRelease.System,
 	}
 
+	keepProtection(currentRelease, updatedRelease)
+
 	if len(notesTxt) > 0 {
 		updatedRelease.Info.Status.Notes = notesTxt
 	}
-	err = validateManifest(s.env.KubeClient, currentRelease.Namespace, manifestDoc.Bytes())
-	return currentRelease, updatedRelease, err
+	err = validateManifest(kc.env.KubeClient, currentRelease.Namespace, manifestDoc.Bytes())
+	if err == nil {
+		err = s.setNamespaces(updatedRelease)
+	}
+	return currentRelease, updatedRelease, valuesToRender, err
 }
 
-func (s *ReleaseServer) performUpdate(originalRelease, updatedRelease *release.Release, req *services.UpdateReleaseRequest) (*services.UpdateReleaseResponse, error) {
+func (s *ReleaseServer) performUpdate(log logging.Logger, originalRelease, updatedRelease *release.Release, values chartutil.Values, req *services.UpdateReleaseRequest) (*services.UpdateReleaseResponse, error) {
 	res := &services.UpdateReleaseResponse{Release: updatedRelease}
+	kc, err := s.actingCluster(updatedRelease)
+	if err != nil {
+		return res, err
+	}
+	kc = s.withWaitLogs(log, kc, updatedRelease.Name, req.WaitLogBytes)
+	defer s.waitLogs.end(updatedRelease.Name)
+
+	if req.VerifyImages {
+		if err := s.verifyImages(updatedRelease); err != nil {
+			log.Warnf("%s", err)
+			return res, err
+		}
+	}
+	if req.VerifyReferences {
+		if err := s.verifyReferences(updatedRelease); err != nil {
+			log.Warnf("%s", err)
+			return res, err
+		}
+	}
+	if !req.AllowProtectedChanges {
+		if err := checkProtectedResources(originalRelease, updatedRelease, !req.KeepRemoved); err != nil {
+			log.Warnf("%s", err)
+			return res, err
+		}
+	}
+
+	skip := newHookSkipList(req.SkipHooks, req.SkipHookWeights)
+	runHooks := s.runHooks(log, "upgrade", req.DisableHooks, req.EnableHooks)
 
 	if req.DryRun {
-		s.Log("Dry run for %s", updatedRelease.Name)
+		log.Infof("Dry run for %s", updatedRelease.Name)
 		res.Release.Info.Description = "Dry run complete"
+		if runHooks {
+			res.Hooks = previewHooks(updatedRelease.Hooks, skip, hooks.PreUpgrade, hooks.PostUpgrade)
+			if !req.SkipHookLookup {
+				lookUpReplacedHooks(log, kc.env.KubeClient, updatedRelease.Namespace, updatedRelease.Hooks, res.Hooks)
+			}
+		}
+		if req.ServerDryRun {
+			return res, s.serverDryRun(updatedRelease)
+		}
 		return res, nil
 	}
 
+	budget := newTimeoutBudget(req.TimeoutBudget, req.Timeout)
+
 	// pre-upgrade hooks
-	if !req.DisableHooks {
-		if err := s.execHook(updatedRelease.Hooks, updatedRelease.Name, updatedRelease.Namespace, hooks.PreUpgrade, req.Timeout); err != nil {
+	if runHooks {
+		if err := s.execHookWithin(log, kc.env.KubeClient, updatedRelease, hooks.PreUpgrade, budget, skip); err != nil {
+			return res, err
+		}
+	}
+	if req.RequireApproval {
+		// Waiting for approval does not count against the budget.
+		err := budget.pause(func() error { return s.awaitApproval(log, updatedRelease, req) })
+		if err != nil {
 			return res, err
 		}
 	}
-	if err := s.ReleaseModule.Update(originalRelease, updatedRelease, req, s.env); err != nil {
-		msg := fmt.Sprintf("Upgrade %q failed: %s", updatedRelease.Name, err)
-		s.Log("warning: %s", msg)
-		originalRelease.Info.Status.Code = release.Status_SUPERSEDED
-		updatedRelease.Info.Status.Code = release.Status_FAILED
-		updatedRelease.Info.Description = msg
-		s.recordRelease(originalRelease, true)
-		s.recordRelease(updatedRelease, false)
+
+	timeout, err := budget.start("upgrade of the resources")
+	if err != nil {
+		// An approved upgrade has recorded its revision already.
+		if req.RequireApproval {
+			s.failUpdate(log, originalRelease, updatedRelease, req, err)
+		}
+		return res, err
+	}
+	updateReq := *req
+	updateReq.Timeout = timeout
+	base := s.pruneBase(log, originalRelease, updatedRelease, req.KeepRemoved)
+	if err := kc.module.Update(base, updatedRelease, &updateReq, kc.env); err != nil {
+		err = budget.exhausted(err)
+		recordResourceStatuses(updatedRelease, err)
+		s.failUpdate(log, originalRelease, updatedRelease, req, err)
+		if req.OnFailure == onFailureRevert {
+			if rerr := s.revertUpgrade(log, updatedRelease, req); rerr != nil {
+				return res, fmt.Errorf("%s; reverting the upgrade failed as well: %s", err, rerr)
+			}
+		}
 		return res, err
 	}
 
+	s.afterApply(log, updatedRelease.Cluster, kc.env.KubeClient, updatedRelease.Manifest)
+
 	// post-upgrade hooks
-	if !req.DisableHooks {
-		if err := s.execHook(updatedRelease.Hooks, updatedRelease.Name, updatedRelease.Namespace, hooks.PostUpgrade, req.Timeout); err != nil {
+	if runHooks {
+		if err := s.execHookWithin(log, kc.env.KubeClient, updatedRelease, hooks.PostUpgrade, budget, skip); err != nil {
+			s.failUpdate(log, originalRelease, updatedRelease, req, err)
 			return res, err
 		}
 	}
 
+	if req.Wait || req.DeferNotes {
+		s.refreshNotes(log, updatedRelease, originalRelease, values, req.DeferNotes)
+	}
+
 	originalRelease.Info.Status.Code = release.Status_SUPERSEDED
 	s.recordRelease(originalRelease, true)
 
@@ -166,3 +333,16 @@ func (s *ReleaseServer) performUpdate(originalRelease, updatedRelease *release.R
 
 	return res, nil
 }
+
+// failUpdate records that the upgrade from original to updated failed with
+// err: the original revision is superseded, and the updated one failed.
+func (s *ReleaseServer) failUpdate(log logging.Logger, original, updated *release.Release, req *services.UpdateReleaseRequest, err error) {
+	msg := fmt.Sprintf("Upgrade %q failed: %s", updated.Name, err)
+	log.Warnf("%s", msg)
+	original.Info.Status.Code = release.Status_SUPERSEDED
+	updated.Info.Status.Code = release.Status_FAILED
+	updated.Info.Description = msg
+	s.recordRelease(original, true)
+	// An upgrade that waited for approval has already recorded its revision.
+	s.recordRelease(updated, req.RequireApproval)
+}
@@ -17,12 +17,18 @@ limitations under the License.
 package tiller
 
 import (
+	"io"
+	"io/ioutil"
+	"os"
+	"strings"
+	"testing"
+
 	"k8s.io/helm/pkg/helm"
+	"k8s.io/helm/pkg/kube"
 	"k8s.io/helm/pkg/proto/hapi/chart"
 	"k8s.io/helm/pkg/proto/hapi/release"
 	"k8s.io/helm/pkg/proto/hapi/services"
-	"strings"
-	"testing"
+	"k8s.io/helm/pkg/tiller/environment"
 )
 
 func TestUpdateRelease(t *testing.T) {
@@ -168,6 +174,110 @@ func TestUpdateRelease_ReuseValues(t *testing.T) {
 	}
 }
 
+func TestUpdateRelease_DryRunHooks(t *testing.T) {
+	c := helm.NewContext()
+	rs := rsFixture()
+	rs.env.KubeClient = &driftKubeClient{PrintingKubeClient: environment.PrintingKubeClient{Out: ioutil.Discard}}
+	rel := releaseStub()
+	rs.env.Releases.Create(rel)
+
+	req := &services.UpdateReleaseRequest{
+		Name: rel.Name,
+		Chart: &chart.Chart{
+			Metadata: &chart.Metadata{Name: "hello"},
+			Templates: []*chart.Template{
+				{Name: "templates/hello", Data: []byte("hello: world")},
+				{Name: "templates/migrate", Data: beforeHookCreationHook("migrate", "pre-upgrade")},
+				{Name: "templates/hooks", Data: []byte(manifestWithHook)},
+			},
+		},
+		DryRun: true,
+	}
+	res, err := rs.UpdateRelease(c, req)
+	if err != nil {
+		t.Fatalf("Failed upgrade: %s", err)
+	}
+	// Only the upgrade hooks are listed.
+	if len(res.Hooks) != 1 || res.Hooks[0].Name != "migrate" || !res.Hooks[0].ReplacesExisting {
+		t.Errorf("Expected the migrate hook to replace an existing resource, got %v", res.Hooks)
+	}
+}
+
+func TestUpdateRelease_Profile(t *testing.T) {
+	c := helm.NewContext()
+	rs := rsFixture()
+	rel := releaseStub()
+	rs.env.Releases.Create(rel)
+
+	req := &services.UpdateReleaseRequest{
+		Name:    rel.Name,
+		Chart:   profileChart(),
+		Values:  &chart.Config{Raw: "name: custom\n"},
+		Profile: "prod",
+	}
+	res, err := rs.UpdateRelease(c, req)
+	if err != nil {
+		t.Fatalf("Failed updated: %s", err)
+	}
+	if !strings.Contains(res.Release.Manifest, "hello: custom") {
+		t.Errorf("Expected the supplied values to override the profile, got %q", res.Release.Manifest)
+	}
+	if expect := "name: custom\n"; res.Release.Config.Raw != expect {
+		t.Errorf("Expected values %q, got %q", expect, res.Release.Config.Raw)
+	}
+	if res.Release.Profile != "prod" {
+		t.Errorf("Expected the profile to be stored, got %q", res.Release.Profile)
+	}
+
+	req.Profile = "staging"
+	if _, err := rs.UpdateRelease(c, req); err == nil || !strings.Contains(err.Error(), `no values profile "staging"`) {
+		t.Errorf("Expected an error for a missing profile, got %v", err)
+	}
+}
+
+func TestUpdateRelease_ReuseProfile(t *testing.T) {
+	c := helm.NewContext()
+	rs := rsFixture()
+	rel := releaseStub()
+	rel.Chart = profileChart()
+	rel.Config = &chart.Config{Raw: "replicas: 5\n"}
+	rel.Profile = "prod"
+	rs.env.Releases.Create(rel)
+
+	// The profile of the new chart applies, not the values of the old one.
+	chrt := profileChart()
+	chrt.Files[0].Value = []byte("name: prod2\n")
+	req := &services.UpdateReleaseRequest{
+		Name:        rel.Name,
+		Chart:       chrt,
+		ReuseValues: true,
+	}
+	res, err := rs.UpdateRelease(c, req)
+	if err != nil {
+		t.Fatalf("Failed updated: %s", err)
+	}
+	if !strings.Contains(res.Release.Manifest, "hello: prod2") {
+		t.Errorf("Expected the new profile's values to be rendered, got %q", res.Release.Manifest)
+	}
+	if res.Release.Profile != "prod" {
+		t.Errorf("Expected the profile to be kept, got %q", res.Release.Profile)
+	}
+
+	// Resetting the values drops the profile too.
+	req = &services.UpdateReleaseRequest{
+		Name:        rel.Name,
+		Chart:       profileChart(),
+		Values:      &chart.Config{},
+		ResetValues: true,
+	}
+	if res, err = rs.UpdateRelease(c, req); err != nil {
+		t.Fatalf("Failed updated: %s", err)
+	}
+	if !strings.Contains(res.Release.Manifest, "hello: default") || res.Release.Profile != "" {
+		t.Errorf("Expected the profile to be dropped, got %q with profile %q", res.Release.Manifest, res.Release.Profile)
+	}
+}
+
 func TestUpdateRelease_ResetReuseValues(t *testing.T) {
 	// This verifies that when both reset and reuse are set, reset wins.
 	c := helm.NewContext()
@@ -284,3 +394,269 @@ func TestUpdateReleaseNoChanges(t *testing.T) {
 		t.Fatalf("Failed updated: %s", err)
 	}
 }
+
+func TestUpdateRelease_ServerDryRun(t *testing.T) {
+	c := helm.NewContext()
+	rs := rsFixture()
+	rel := releaseStub()
+	rs.env.Releases.Create(rel)
+	defaulted := "---\nhello: world\nserviceAccountName: default\n"
+	rs.env.KubeClient = &serverDryRunKubeClient{manifest: defaulted}
+
+	req := &services.UpdateReleaseRequest{
+		Name:         rel.Name,
+		DryRun:       true,
+		ServerDryRun: true,
+		Chart:        rel.GetChart(),
+	}
+
+	res, err := rs.UpdateRelease(c, req)
+	if err != nil {
+		t.Fatalf("Failed updated: %s", err)
+	}
+	if res.Release.Manifest != defaulted {
+		t.Errorf("expected manifest from the server dry run, got %q", res.Release.Manifest)
+	}
+	if _, err := rs.env.Releases.Get(rel.Name, 2); err == nil {
+		t.Errorf("Expected no stored release.")
+	}
+}
+
+func TestUpdateRelease_SkipHooks(t *testing.T) {
+	c := helm.NewContext()
+	rs := rsFixture()
+	rel := releaseStub()
+	rs.env.Releases.Create(rel)
+
+	req := &services.UpdateReleaseRequest{
+		Name:      rel.Name,
+		SkipHooks: []string{"test-cm", "no-such-hook"},
+		Chart: &chart.Chart{
+			Metadata: &chart.Metadata{Name: "hello"},
+			Templates: []*chart.Template{
+				{Name: "templates/hello", Data: []byte("hello: world")},
+				{Name: "templates/hooks", Data: []byte(manifestWithUpgradeHooks)},
+			},
+		},
+	}
+
+	res, err := rs.UpdateRelease(c, req)
+	if err != nil {
+		t.Fatalf("Failed updated: %s", err)
+	}
+
+	h := res.Release.Hooks[0]
+	if h.LastRun != nil {
+		t.Errorf("Expected the skipped hook not to run. Got %v", h.LastRun)
+	}
+	if h.LastSkipped == nil {
+		t.Error("Expected the skipped hook to be recorded.")
+	}
+
+	stored, err := rs.env.Releases.Get(rel.Name, res.Release.Version)
+	if err != nil {
+		t.Fatalf("Expected release to be stored: %s", err)
+	}
+	if stored.Hooks[0].LastSkipped == nil {
+		t.Error("Expected the skip to be stored with the release.")
+	}
+}
+
+func TestUpdateRelease_ReleaseContext(t *testing.T) {
+	c := helm.NewContext()
+	rs := rsFixture()
+
+	if _, err := rs.InstallRelease(c, &services.InstallReleaseRequest{
+		Name:      "context",
+		Namespace: "spaced",
+		Chart:     releaseContextChart(),
+	}); err != nil {
+		t.Fatalf("Failed install: %s", err)
+	}
+	res, err := rs.UpdateRelease(c, &services.UpdateReleaseRequest{
+		Name:  "context",
+		Chart: releaseContextChart(),
+	})
+	if err != nil {
+		t.Fatalf("Failed upgrade: %s", err)
+	}
+	expectReleaseContext(t, "upgrade", res.Release.Manifest, "install=false upgrade=true revision=2")
+}
+
+func TestUpdateRelease_PreviousValues(t *testing.T) {
+	c := helm.NewContext()
+	rs := rsFixture()
+	ch := &chart.Chart{
+		Metadata: &chart.Metadata{Name: "volume"},
+		Values:   &chart.Config{Raw: "size: 10Gi\n"},
+		Templates: []*chart.Template{
+			{Name: "templates/size", Data: []byte("size: {{ .Values.size }}\nprevious: {{ .Release.PreviousValues.size | default \"none\" }}\n")},
+		},
+	}
+
+	res, err := rs.InstallRelease(c, &services.InstallReleaseRequest{Name: "volume", Chart: ch, Values: &chart.Config{}})
+	if err != nil {
+		t.Fatalf("Failed install: %s", err)
+	}
+	if !strings.Contains(res.Release.Manifest, "size: 10Gi\nprevious: none") {
+		t.Errorf("Expected no previous values on install, got %q", res.Release.Manifest)
+	}
+
+	up, err := rs.UpdateRelease(c, &services.UpdateReleaseRequest{
+		Name:   "volume",
+		Chart:  ch,
+		Values: &chart.Config{Raw: "size: 20Gi\n"},
+	})
+	if err != nil {
+		t.Fatalf("Failed upgrade: %s", err)
+	}
+	if !strings.Contains(up.Release.Manifest, "size: 20Gi\nprevious: 10Gi") {
+		t.Errorf("Expected the values of revision 1, got %q", up.Release.Manifest)
+	}
+
+	// A stored snapshot is preferred to merging the values again.
+	rs.StoreComputedValues(true)
+	rel, err := rs.env.Releases.Get("volume", 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rel.ComputedValues = &chart.Config{Raw: "size: 30Gi\n"}
+	rs.env.Releases.Update(rel)
+	up, err = rs.UpdateRelease(c, &services.UpdateReleaseRequest{
+		Name:   "volume",
+		Chart:  ch,
+		Values: &chart.Config{Raw: "size: 40Gi\n"},
+	})
+	if err != nil {
+		t.Fatalf("Failed upgrade: %s", err)
+	}
+	if !strings.Contains(up.Release.Manifest, "size: 40Gi\nprevious: 30Gi") {
+		t.Errorf("Expected the computed values of revision 2, got %q", up.Release.Manifest)
+	}
+}
+
+func TestUpdateRelease_ComputedValues(t *testing.T) {
+	c := helm.NewContext()
+	rs := rsFixture()
+	rs.StoreComputedValues(true)
+	rel := releaseStub()
+	rel.ComputedValues = &chart.Config{Raw: "name: value\n"}
+	rs.env.Releases.Create(rel)
+
+	res, err := rs.UpdateRelease(c, &services.UpdateReleaseRequest{
+		Name:   rel.Name,
+		Chart:  computedValuesChart(),
+		Values: &chart.Config{Raw: "replicas: 3\n"},
+	})
+	if err != nil {
+		t.Fatalf("Failed update: %s", err)
+	}
+	expect := "name: default\nreplicas: 3\n"
+	if res.Release.ComputedValues == nil || res.Release.ComputedValues.Raw != expect {
+		t.Errorf("Expected computed values %q, got %v", expect, res.Release.ComputedValues)
+	}
+
+	// Rolling back restores the snapshot of the revision rolled back to.
+	rb, err := rs.RollbackRelease(c, &services.RollbackReleaseRequest{Name: rel.Name, Version: 1})
+	if err != nil {
+		t.Fatalf("Failed rollback: %s", err)
+	}
+	if rb.Release.ComputedValues == nil || rb.Release.ComputedValues.Raw != "name: value\n" {
+		t.Errorf("Expected the computed values of revision 1, got %v", rb.Release.ComputedValues)
+	}
+}
+
+func TestUpdateRelease_GeneratedValues(t *testing.T) {
+	c := helm.NewContext()
+	rs := rsFixture()
+	rel := releaseStub()
+	rel.GeneratedValues = map[string]string{"password": "s3cr3t", "unused": "dropped"}
+	rs.env.Releases.Create(rel)
+
+	res, err := rs.UpdateRelease(c, &services.UpdateReleaseRequest{
+		Name:  rel.Name,
+		Chart: generatedValuesChart(),
+	})
+	if err != nil {
+		t.Fatalf("Failed update: %s", err)
+	}
+	if !strings.Contains(res.Release.Manifest, "password: s3cr3t") {
+		t.Errorf("Expected the upgrade to reuse the stored password, got %q", res.Release.Manifest)
+	}
+	if len(res.Release.GeneratedValues) != 1 || res.Release.GeneratedValues["password"] != "s3cr3t" {
+		t.Errorf("Expected only the used values to be stored, got %v", res.Release.GeneratedValues)
+	}
+	if rel.GeneratedValues["unused"] != "dropped" {
+		t.Error("Expected the values of the current release to be left unchanged")
+	}
+
+	// Rolling back restores the values of the revision rolled back to.
+	rb, err := rs.RollbackRelease(c, &services.RollbackReleaseRequest{Name: rel.Name, Version: 1})
+	if err != nil {
+		t.Fatalf("Failed rollback: %s", err)
+	}
+	if rb.Release.GeneratedValues["unused"] != "dropped" {
+		t.Errorf("Expected the generated values of revision 1, got %v", rb.Release.GeneratedValues)
+	}
+}
+
+// optionsRecordingKubeClient records the options of the last update.
+type optionsRecordingKubeClient struct {
+	environment.PrintingKubeClient
+	opts kube.UpdateOptions
+}
+
+func newOptionsRecordingKubeClient() *optionsRecordingKubeClient {
+	return &optionsRecordingKubeClient{
+		PrintingKubeClient: environment.PrintingKubeClient{Out: os.Stdout},
+	}
+}
+
+func (o *optionsRecordingKubeClient) UpdateWithOptions(ns string, currentReader, modifiedReader io.Reader, opts kube.UpdateOptions) error {
+	o.opts = opts
+	return nil
+}
+
+func TestUpdateRelease_DeleteOptions(t *testing.T) {
+	c := helm.NewContext()
+	rs := rsFixture()
+	kc := newOptionsRecordingKubeClient()
+	rs.env.KubeClient = kc
+	rel := releaseStub()
+	rs.env.Releases.Create(rel)
+
+	req := &services.UpdateReleaseRequest{
+		Name:                     rel.Name,
+		Recreate:                 true,
+		GracePeriod:              30,
+		PropagationPolicy:        "Foreground",
+		RecreateOnSelectorChange: true,
+		Chart:                    rel.Chart,
+	}
+	if _, err := rs.UpdateRelease(c, req); err != nil {
+		t.Fatalf("Failed update: %s", err)
+	}
+	expect := kube.UpdateOptions{Recreate: true, GracePeriod: 30, PropagationPolicy: "Foreground", RecreateOnSelectorChange: true}
+	if kc.opts != expect {
+		t.Errorf("Expected update options %+v, got %+v", expect, kc.opts)
+	}
+}
+
+func TestUpdateRelease_InvalidDeleteOptions(t *testing.T) {
+	c := helm.NewContext()
+	rs := rsFixture()
+	rel := releaseStub()
+	rs.env.Releases.Create(rel)
+
+	for _, req := range []*services.UpdateReleaseRequest{
+		{Name: rel.Name, Chart: rel.Chart, GracePeriod: -1},
+		{Name: rel.Name, Chart: rel.Chart, PropagationPolicy: "Sideways"},
+	} {
+		if _, err := rs.UpdateRelease(c, req); err == nil {
+			t.Errorf("Expected an error for grace period %d and propagation policy %q", req.GracePeriod, req.PropagationPolicy)
+		}
+	}
+	if last, err := rs.env.Releases.Last(rel.Name); err != nil || last.Version != rel.Version {
+		t.Errorf("Expected no revision to be recorded, got %v (%v)", last, err)
+	}
+}
@@ -17,13 +17,62 @@ limitations under the License.
 package tiller
 
 import (
+	"strconv"
+	"strings"
+
 	ctx "golang.org/x/net/context"
+	kversion "k8s.io/apimachinery/pkg/version"
+
 	"k8s.io/helm/pkg/proto/hapi/services"
 	"k8s.io/helm/pkg/version"
 )
 
-// GetVersion sends the server version.
+// GetVersion sends the server version, the features the server supports and
+// the name of its storage driver.
 func (s *ReleaseServer) GetVersion(c ctx.Context, req *services.GetVersionRequest) (*services.GetVersionResponse, error) {
 	v := version.GetVersionProto()
-	return &services.GetVersionResponse{Version: v}, nil
+	res := &services.GetVersionResponse{Version: v, Features: s.features()}
+	if s.env.Releases != nil {
+		res.StorageDriver = s.env.Releases.Name()
+	}
+	return res, nil
+}
+
+// features lists the optional features that the server supports, as it is
+// configured and with the cluster it manages.
+func (s *ReleaseServer) features() []string {
+	features := []string{}
+	if s.clientset != nil {
+		if sv, err := s.clientset.Discovery().ServerVersion(); err != nil {
+			s.Log("warning: cannot tell if the API server supports dry runs: %s", err)
+		} else if kubeVersionAtLeast(sv, 1, 13) {
+			features = append(features, version.FeatureServerDryRun)
+		}
+	}
+	if s.events != nil {
+		features = append(features, version.FeatureReleaseEvents)
+	}
+	if s.storeComputedValues {
+		features = append(features, version.FeatureComputedValues)
+	}
+	if _, ok := s.ReleaseModule.(*RemoteReleaseModule); ok {
+		features = append(features, version.FeatureRemoteReleaseModules)
+	} else if _, ok := s.env.KubeClient.(impersonator); ok {
+		features = append(features, version.FeatureImpersonation)
+	}
+	return features
+}
+
+// kubeVersionAtLeast reports whether the Kubernetes version sv is major.minor
+// or later. Minor versions such as "13+" count as 13.
+func kubeVersionAtLeast(sv *kversion.Info, major, minor int) bool {
+	maj, err := strconv.Atoi(sv.Major)
+	if err != nil {
+		return false
+	}
+	min, err := strconv.Atoi(strings.TrimSuffix(sv.Minor, "+"))
+	if err != nil {
+		return false
+	}
+	return maj > major || maj == major && min >= minor
 }
@@ -0,0 +1,130 @@
+/*
+Copyright 2017 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tiller
+
+import (
+	"reflect"
+	"testing"
+
+	"golang.org/x/net/context"
+	kversion "k8s.io/apimachinery/pkg/version"
+	"k8s.io/client-go/discovery"
+	"k8s.io/kubernetes/pkg/client/clientset_generated/internalclientset/fake"
+
+	"k8s.io/helm/pkg/proto/hapi/services"
+	"k8s.io/helm/pkg/storage/driver"
+	"k8s.io/helm/pkg/version"
+)
+
+type versionedDiscovery struct {
+	discovery.DiscoveryInterface
+	info *kversion.Info
+}
+
+func (d *versionedDiscovery) ServerVersion() (*kversion.Info, error) {
+	return d.info, nil
+}
+
+// versionedClientset is a fake clientset whose API server is of the given
+// version.
+type versionedClientset struct {
+	*fake.Clientset
+	info *kversion.Info
+}
+
+func (c *versionedClientset) Discovery() discovery.DiscoveryInterface {
+	return &versionedDiscovery{c.Clientset.Discovery(), c.info}
+}
+
+func TestGetVersion(t *testing.T) {
+	rs := rsFixture()
+
+	res, err := rs.GetVersion(context.TODO(), &services.GetVersionRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if *res.Version != *version.GetVersionProto() {
+		t.Errorf("Expected version %v, got %v", version.GetVersionProto(), res.Version)
+	}
+	if res.StorageDriver != driver.MemoryDriverName {
+		t.Errorf("Expected storage driver %q, got %q", driver.MemoryDriverName, res.StorageDriver)
+	}
+	// The fake API server is older than 1.13, and nothing optional is
+	// enabled.
+	if len(res.Features) != 0 {
+		t.Errorf("Expected no features, got %v", res.Features)
+	}
+}
+
+func TestGetVersionFeatures(t *testing.T) {
+	rs := rsFixture()
+	rs.clientset = &versionedClientset{fake.NewSimpleClientset(), &kversion.Info{Major: "1", Minor: "13+"}}
+	rs.ReleaseModule = &RemoteReleaseModule{}
+	rs.EnableEvents(1)
+	rs.StoreComputedValues(true)
+
+	res, err := rs.GetVersion(context.TODO(), &services.GetVersionRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expect := []string{
+		version.FeatureServerDryRun,
+		version.FeatureReleaseEvents,
+		version.FeatureComputedValues,
+		version.FeatureRemoteReleaseModules,
+	}
+	if !reflect.DeepEqual(res.Features, expect) {
+		t.Errorf("Expected features %v, got %v", expect, res.Features)
+	}
+}
+
+func TestGetVersionUnreachableKubernetes(t *testing.T) {
+	rs := rsFixture()
+	rs.clientset = &unreachableClientset{fake.NewSimpleClientset()}
+	rs.EnableEvents(1)
+
+	res, err := rs.GetVersion(context.TODO(), &services.GetVersionRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expect := []string{version.FeatureReleaseEvents}; !reflect.DeepEqual(res.Features, expect) {
+		t.Errorf("Expected features %v, got %v", expect, res.Features)
+	}
+}
+
+func TestKubeVersionAtLeast(t *testing.T) {
+	tests := []struct {
+		major, minor string
+		expect       bool
+	}{
+		{"1", "13", true},
+		{"1", "13+", true},
+		{"1", "14", true},
+		{"2", "0", true},
+		{"1", "12", false},
+		{"1", "9+", false},
+		{"0", "20", false},
+		{"", "", false},
+		{"1", "x", false},
+	}
+	for _, tt := range tests {
+		info := &kversion.Info{Major: tt.major, Minor: tt.minor}
+		if got := kubeVersionAtLeast(info, 1, 13); got != tt.expect {
+			t.Errorf("%s.%s: expected %t, got %t", tt.major, tt.minor, tt.expect, got)
+		}
+	}
+}
@@ -0,0 +1,141 @@
+/*
+Copyright 2017 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tiller
+
+import (
+	"sync"
+
+	"k8s.io/helm/pkg/kube"
+	"k8s.io/helm/pkg/logging"
+	"k8s.io/helm/pkg/proto/hapi/services"
+)
+
+// waitLogBuffer is how many logs a watcher that falls behind can have
+// waiting before the later ones are dropped for it.
+const waitLogBuffer = 16
+
+// waitLogFeed passes the logs that the waits of operations on releases
+// stream to the clients that watch the releases with WatchWaitLogs. The zero
+// value is ready to use.
+type waitLogFeed struct {
+	mu       sync.Mutex
+	watchers map[string][]chan string
+}
+
+// watch returns the channel that the logs streamed for the named release
+// are sent on. It is closed when the operation that streams them ends, or
+// when stop is called.
+func (f *waitLogFeed) watch(name string) (logs <-chan string, stop func()) {
+	ch := make(chan string, waitLogBuffer)
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.watchers == nil {
+		f.watchers = map[string][]chan string{}
+	}
+	f.watchers[name] = append(f.watchers[name], ch)
+	return ch, func() { f.remove(name, ch) }
+}
+
+// remove closes ch and stops sending to it, unless it was closed already.
+func (f *waitLogFeed) remove(name string, ch chan string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	watchers := f.watchers[name]
+	for i, w := range watchers {
+		if w == ch {
+			close(ch)
+			f.watchers[name] = append(watchers[:i:i], watchers[i+1:]...)
+			break
+		}
+	}
+	if len(f.watchers[name]) == 0 {
+		delete(f.watchers, name)
+	}
+}
+
+// send sends logs to the watchers of the named release. A watcher that has
+// too many logs waiting misses them, rather than holding up the wait.
+func (f *waitLogFeed) send(name, logs string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for _, ch := range f.watchers[name] {
+		select {
+		case ch <- logs:
+		default:
+		}
+	}
+}
+
+// end closes the channels of the watchers of the named release, as the
+// operation that streamed logs for it ended.
+func (f *waitLogFeed) end(name string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for _, ch := range f.watchers[name] {
+		close(ch)
+	}
+	delete(f.watchers, name)
+}
+
+// waitLogStreamer is a KubeClient whose waits can stream the logs of the
+// containers that are not ready. See kube.Client.WithWaitLogs.
+type waitLogStreamer interface {
+	WithWaitLogs(limit int64, sink func(logs string)) *kube.Client
+}
+
+// withWaitLogs returns a copy of kc whose waits stream the logs of the
+// containers that are not ready to the watchers of the named release, in at
+// most limit bytes at a time. It returns kc itself if limit is not positive,
+// or if kc cannot stream logs, such as with remote release modules.
+func (s *ReleaseServer) withWaitLogs(log logging.Logger, kc *kubeCluster, name string, limit int64) *kubeCluster {
+	if limit <= 0 {
+		return kc
+	}
+	w, ok := kc.env.KubeClient.(waitLogStreamer)
+	if _, local := kc.module.(*LocalReleaseModule); !ok || !local {
+		log.Warnf("The logs of the wait cannot be streamed by this Tiller")
+		return kc
+	}
+	env := *kc.env
+	env.KubeClient = w.WithWaitLogs(limit, func(logs string) { s.waitLogs.send(name, logs) })
+	return &kubeCluster{env: &env, clientset: kc.clientset, module: kc.module}
+}
+
+// WatchWaitLogs streams the logs of the containers that are not ready while
+// an install or upgrade of the named release waits for its resources, if it
+// asks for them with WaitLogBytes. The stream ends with that operation, or
+// when the client goes away.
+func (s *ReleaseServer) WatchWaitLogs(req *services.WatchWaitLogsRequest, stream services.ReleaseService_WatchWaitLogsServer) error {
+	if !ValidName.MatchString(req.Name) {
+		return errMissingRelease
+	}
+	logs, stop := s.waitLogs.watch(req.Name)
+	defer stop()
+	for {
+		select {
+		case l, ok := <-logs:
+			if !ok {
+				return nil
+			}
+			if err := stream.Send(&services.WatchWaitLogsResponse{Logs: l}); err != nil {
+				return err
+			}
+		case <-stream.Context().Done():
+			return stream.Context().Err()
+		}
+	}
+}
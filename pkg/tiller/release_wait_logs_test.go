@@ -0,0 +1,124 @@
+/*
+Copyright 2017 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tiller
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"k8s.io/helm/pkg/kube"
+	"k8s.io/helm/pkg/proto/hapi/services"
+)
+
+func TestWaitLogFeed(t *testing.T) {
+	var f waitLogFeed
+	logs, stop := f.watch("angry-panda")
+	other, stopOther := f.watch("other")
+	defer stopOther()
+
+	f.send("angry-panda", "==> default/web-1/app <==\nstarting\n")
+	if l := <-logs; l != "==> default/web-1/app <==\nstarting\n" {
+		t.Errorf("Unexpected logs %q", l)
+	}
+	select {
+	case l := <-other:
+		t.Errorf("Expected no logs for another release, got %q", l)
+	default:
+	}
+
+	// A watcher that falls behind misses logs instead of blocking.
+	for i := 0; i < waitLogBuffer+1; i++ {
+		f.send("angry-panda", "again")
+	}
+	if len(logs) != waitLogBuffer {
+		t.Errorf("Expected %d logs waiting, got %d", waitLogBuffer, len(logs))
+	}
+
+	// The operation ending closes the channel, and stopping after that is
+	// harmless.
+	f.end("angry-panda")
+	for range logs {
+	}
+	stop()
+
+	// Stopping closes the channel as well.
+	logs, stop = f.watch("angry-panda")
+	stop()
+	if _, ok := <-logs; ok {
+		t.Error("Expected the channel to be closed")
+	}
+}
+
+type mockWatchWaitLogsServer struct {
+	mockRunReleaseTestServer
+	sent []string
+}
+
+func (s *mockWatchWaitLogsServer) Send(m *services.WatchWaitLogsResponse) error {
+	s.sent = append(s.sent, m.Logs)
+	return nil
+}
+
+func TestWatchWaitLogs(t *testing.T) {
+	rs := rsFixture()
+	kc, err := rs.cluster("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	kc.env.KubeClient = kube.New(nil)
+	kc = rs.withWaitLogs(rs.requestLogger("install", "angry-panda", 1), kc, "angry-panda", 512)
+	client := kc.env.KubeClient.(*kube.Client)
+	if client.WaitLogBytes != 512 || client.WaitLogSink == nil {
+		t.Fatalf("Expected the waits to stream at most 512 bytes, got %d", client.WaitLogBytes)
+	}
+
+	stream := &mockWatchWaitLogsServer{}
+	done := make(chan error)
+	go func() {
+		done <- rs.WatchWaitLogs(&services.WatchWaitLogsRequest{Name: "angry-panda"}, stream)
+	}()
+	// Logs are only sent to the watchers there are, so wait for the watch.
+	for {
+		rs.waitLogs.mu.Lock()
+		n := len(rs.waitLogs.watchers["angry-panda"])
+		rs.waitLogs.mu.Unlock()
+		if n > 0 {
+			break
+		}
+		time.Sleep(time.Millisecond)
+	}
+	client.WaitLogSink("==> default/web-1/app <==\nstarting\n")
+	rs.waitLogs.end("angry-panda")
+	if err := <-done; err != nil {
+		t.Fatal(err)
+	}
+	if expect := []string{"==> default/web-1/app <==\nstarting\n"}; !reflect.DeepEqual(stream.sent, expect) {
+		t.Errorf("Expected %q, got %q", expect, stream.sent)
+	}
+}
+
+func TestWithWaitLogsDisabled(t *testing.T) {
+	rs := rsFixture()
+	kc, err := rs.cluster("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rs.withWaitLogs(rs.requestLogger("install", "angry-panda", 1), kc, "angry-panda", 0) != kc {
+		t.Error("Expected the cluster to be used as it is without WaitLogBytes")
+	}
+}
@@ -0,0 +1,215 @@
+/*
+Copyright 2017 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tiller
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/ghodss/yaml"
+	"k8s.io/apimachinery/pkg/util/validation"
+
+	"k8s.io/helm/pkg/proto/hapi/release"
+)
+
+// nameHashLen is the number of hex digits of the hash that a shortened
+// release name ends in.
+const nameHashLen = 8
+
+// minReleaseNameMaxLen is the shortest limit on release names that leaves
+// room for a shortened name: one character, a dash and the hash.
+const minReleaseNameMaxLen = nameHashLen + 2
+
+// maxNameAttempts is how often a release name is shortened before giving up,
+// for charts that repeat the name within a single resource name.
+const maxNameAttempts = 3
+
+// dnsLabelKinds are the kinds whose names must be DNS-1123 labels, which are
+// at most 63 characters long, instead of DNS-1123 subdomains.
+var dnsLabelKinds = map[string]bool{
+	"Namespace": true,
+	"Service":   true,
+}
+
+// SetReleaseNameMaxLength limits release names to n characters instead of
+// 53. Kubernetes limits many names, and all label values, to 63 characters,
+// so a longer limit only suits charts that use the release name as it is.
+func (s *ReleaseServer) SetReleaseNameMaxLength(n int) error {
+	if n < minReleaseNameMaxLen || n > validation.DNS1123LabelMaxLength {
+		return fmt.Errorf("release name max length must be between %d and %d, got %d", minReleaseNameMaxLen, validation.DNS1123LabelMaxLength, n)
+	}
+	s.nameMaxLength = n
+	return nil
+}
+
+// nameMaxLen returns the maximum length of new release names.
+func (s *ReleaseServer) nameMaxLen() int {
+	if s.nameMaxLength == 0 {
+		return releaseNameMaxLen
+	}
+	return s.nameMaxLength
+}
+
+// shortenName returns name cut to n characters, the last of which are a hash
+// of the whole name, so that names that only differ after the cut stay
+// apart. Names of at most n characters are returned as they are.
+func shortenName(name string, n int) string {
+	if len(name) <= n {
+		return name
+	}
+	sum := sha256.Sum256([]byte(name))
+	keep := n - nameHashLen - 1
+	if keep < 1 {
+		return ""
+	}
+	prefix := strings.TrimRight(name[:keep], "-_.")
+	if prefix == "" {
+		return ""
+	}
+	return prefix + "-" + hex.EncodeToString(sum[:])[:nameHashLen]
+}
+
+// nameProblem is a name or label value of a rendered resource that
+// Kubernetes would reject.
+type nameProblem struct {
+	kind  string
+	name  string
+	field string
+	value string
+	errs  []string
+	// overflow is how many characters the value is too long by.
+	overflow int
+}
+
+func (p nameProblem) String() string {
+	return fmt.Sprintf("%s %q: %s: %s", p.kind, p.name, p.field, strings.Join(p.errs, "; "))
+}
+
+// namedHead is the part of a resource that checkResourceNames checks.
+type namedHead struct {
+	Kind     string `json:"kind"`
+	Metadata struct {
+		Name   string            `json:"name"`
+		Labels map[string]string `json:"labels"`
+	} `json:"metadata"`
+	Spec struct {
+		Template struct {
+			Metadata struct {
+				Labels map[string]string `json:"labels"`
+			} `json:"metadata"`
+		} `json:"template"`
+	} `json:"spec"`
+}
+
+// checkResourceNames returns the names and label values of the resources and
+// hooks in manifest that Kubernetes would reject, in the order they appear.
+// Documents that cannot be parsed are left to the validation of the
+// manifest.
+func checkResourceNames(manifest string, hs []*release.Hook) []nameProblem {
+	contents := sortedManifests(manifest)
+	for _, h := range hs {
+		contents = append(contents, h.Manifest)
+	}
+
+	var problems []nameProblem
+	for _, content := range contents {
+		var head namedHead
+		if err := yaml.Unmarshal([]byte(content), &head); err != nil || head.Metadata.Name == "" {
+			continue
+		}
+		check := func(field, value string, limit int, errs []string) {
+			if len(errs) == 0 {
+				return
+			}
+			p := nameProblem{kind: head.Kind, name: head.Metadata.Name, field: field, value: value, errs: errs}
+			if len(value) > limit {
+				p.overflow = len(value) - limit
+			}
+			problems = append(problems, p)
+		}
+
+		name := head.Metadata.Name
+		if dnsLabelKinds[head.Kind] {
+			check("metadata.name", name, validation.DNS1123LabelMaxLength, validation.IsDNS1123Label(name))
+		} else {
+			check("metadata.name", name, validation.DNS1123SubdomainMaxLength, validation.IsDNS1123Subdomain(name))
+		}
+		checkLabels := func(field string, labels map[string]string) {
+			keys := make([]string, 0, len(labels))
+			for k := range labels {
+				keys = append(keys, k)
+			}
+			sort.Strings(keys)
+			for _, k := range keys {
+				check(field+"."+k, labels[k], validation.LabelValueMaxLength, validation.IsValidLabelValue(labels[k]))
+			}
+		}
+		checkLabels("metadata.labels", head.Metadata.Labels)
+		checkLabels("spec.template.metadata.labels", head.Spec.Template.Metadata.Labels)
+	}
+	return problems
+}
+
+// nameProblemsError describes problems for a release named name.
+func nameProblemsError(name string, problems []nameProblem, hint string) error {
+	lines := make([]string, len(problems))
+	for i, p := range problems {
+		lines[i] = "  " + p.String()
+	}
+	return fmt.Errorf("release %s: resources have names that Kubernetes would reject%s:\n%s", name, hint, strings.Join(lines, "\n"))
+}
+
+// fixableByShortening returns how many characters the release named name
+// must lose to fix problems. Only values that are too long and contain the
+// release name can be fixed that way.
+func fixableByShortening(name string, problems []nameProblem) (int, bool) {
+	overflow := 0
+	for _, p := range problems {
+		if p.overflow == 0 || len(p.errs) > 1 || !strings.Contains(p.value, name) {
+			return 0, false
+		}
+		if p.overflow > overflow {
+			overflow = p.overflow
+		}
+	}
+	return overflow, true
+}
+
+// releaseNameProblems returns the error for the problems of the release
+// named name, which a truncated name would not have if truncate is false.
+// With truncate, it returns the name to render the release named original
+// with again.
+func releaseNameProblems(original, name string, problems []nameProblem, truncate bool, attempt int) (string, error) {
+	overflow, fixable := fixableByShortening(name, problems)
+	switch {
+	case !fixable:
+		return "", nameProblemsError(name, problems, "")
+	case !truncate:
+		err := nameProblemsError(name, problems, "")
+		return "", fmt.Errorf("%s\nUse a release name at least %d characters shorter, or let Tiller shorten it (helm install --truncate-name)", err, overflow)
+	case attempt >= maxNameAttempts:
+		return "", nameProblemsError(name, problems, fmt.Sprintf(" after shortening the release name %d times", attempt))
+	}
+	short := shortenName(original, len(name)-overflow)
+	if short == "" {
+		return "", nameProblemsError(name, problems, ", even with the shortest release name")
+	}
+	return short, nil
+}
@@ -0,0 +1,189 @@
+/*
+Copyright 2017 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tiller
+
+import (
+	"strings"
+	"testing"
+
+	"k8s.io/helm/pkg/helm"
+	"k8s.io/helm/pkg/proto/hapi/chart"
+	"k8s.io/helm/pkg/proto/hapi/release"
+	"k8s.io/helm/pkg/proto/hapi/services"
+)
+
+func TestShortenName(t *testing.T) {
+	long := "payments-processing-service-for-the-eu-west-region-production"
+
+	if got := shortenName("short", 20); got != "short" {
+		t.Errorf("Expected a short name to be kept, got %q", got)
+	}
+
+	short := shortenName(long, 30)
+	if len(short) > 30 {
+		t.Errorf("Expected at most 30 characters, got %q", short)
+	}
+	if !strings.HasPrefix(short, "payments-processing-") {
+		t.Errorf("Expected the start of the name to be kept, got %q", short)
+	}
+	if again := shortenName(long, 30); again != short {
+		t.Errorf("Expected the same name to be shortened the same way, got %q and %q", short, again)
+	}
+	if other := shortenName(long+"-2", 30); other == short {
+		t.Errorf("Expected names that differ after the cut to stay apart, both got %q", short)
+	}
+	if !ValidName.MatchString(short) {
+		t.Errorf("Expected %q to be a valid release name", short)
+	}
+
+	// A cut that ends in a separator does not leave it before the hash.
+	if got := shortenName("ab-cdefghijklmnopq", 12); strings.Contains(got, "--") || !strings.HasPrefix(got, "ab-") {
+		t.Errorf("Expected the separator to be trimmed, got %q", got)
+	}
+	if got := shortenName(long, nameHashLen+1); got != "" {
+		t.Errorf("Expected no room for a shortened name, got %q", got)
+	}
+}
+
+func TestCheckResourceNames(t *testing.T) {
+	long := strings.Repeat("a", 60)
+	manifest := `---
+# Source: chart/templates/ok.yaml
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: ok
+  labels:
+    app: ok
+---
+# Source: chart/templates/svc.yaml
+apiVersion: v1
+kind: Service
+metadata:
+  name: ` + long + `-svc
+---
+# Source: chart/templates/deploy.yaml
+apiVersion: extensions/v1beta1
+kind: Deployment
+metadata:
+  name: ` + long + `-deploy
+  labels:
+    release: ` + long + `-deploy
+spec:
+  template:
+    metadata:
+      labels:
+        app: Not_Valid-
+`
+	hs := []*release.Hook{{Manifest: "kind: Job\nmetadata:\n  name: UPPER\n"}}
+
+	problems := checkResourceNames(manifest, hs)
+	var got []string
+	for _, p := range problems {
+		got = append(got, p.kind+" "+p.field)
+	}
+	expect := []string{
+		// Only services are limited to 63 characters.
+		"Service metadata.name",
+		"Deployment metadata.labels.release",
+		"Deployment spec.template.metadata.labels.app",
+		"Job metadata.name",
+	}
+	if strings.Join(got, ", ") != strings.Join(expect, ", ") {
+		t.Fatalf("Expected problems %v, got %v", expect, got)
+	}
+	if problems[0].overflow != 1 || problems[1].overflow != 4 || problems[2].overflow != 0 {
+		t.Errorf("Expected overflows 1, 4 and 0, got %d, %d and %d", problems[0].overflow, problems[1].overflow, problems[2].overflow)
+	}
+}
+
+func TestSetReleaseNameMaxLength(t *testing.T) {
+	rs := rsFixture()
+	if rs.nameMaxLen() != releaseNameMaxLen {
+		t.Errorf("Expected the default limit, got %d", rs.nameMaxLen())
+	}
+	for _, n := range []int{0, minReleaseNameMaxLen - 1, 64} {
+		if err := rs.SetReleaseNameMaxLength(n); err == nil {
+			t.Errorf("Expected %d to be rejected", n)
+		}
+	}
+	if err := rs.SetReleaseNameMaxLength(20); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := rs.uniqName(strings.Repeat("a", 21), false, nil); err == nil || !strings.Contains(err.Error(), "exceeds max length of 20") {
+		t.Errorf("Expected the name to exceed the limit, got %v", err)
+	}
+}
+
+// longNamesChart names a service after the release, with a suffix.
+var longNamesChart = &chart.Chart{
+	Metadata: &chart.Metadata{Name: "hello"},
+	Templates: []*chart.Template{
+		{Name: "templates/svc.yaml", Data: []byte("apiVersion: v1\nkind: Service\nmetadata:\n  name: {{ .Release.Name }}-frontend-service\n  labels:\n    release: {{ .Release.Name }}\n")},
+	},
+}
+
+func TestInstallReleaseNameTooLong(t *testing.T) {
+	c := helm.NewContext()
+	rs := rsFixture()
+	name := "orders-" + strings.Repeat("x", 45)
+
+	_, err := rs.InstallRelease(c, &services.InstallReleaseRequest{Name: name, Namespace: "default", Chart: longNamesChart})
+	if err == nil {
+		t.Fatal("Expected the install to fail before anything is installed")
+	}
+	for _, expect := range []string{`Service "` + name + `-frontend-service": metadata.name`, "at least 6 characters shorter", "--truncate-name"} {
+		if !strings.Contains(err.Error(), expect) {
+			t.Errorf("Expected error to contain %q, got %s", expect, err)
+		}
+	}
+	if h, _ := rs.env.Releases.History(name); len(h) != 0 {
+		t.Errorf("Expected no release to be recorded, got %d", len(h))
+	}
+
+	res, err := rs.InstallRelease(c, &services.InstallReleaseRequest{Name: name, Namespace: "default", Chart: longNamesChart, TruncateName: true})
+	if err != nil {
+		t.Fatalf("Failed install: %s", err)
+	}
+	if got := res.Release.Name; got != shortenName(name, len(name)-6) {
+		t.Errorf("Expected the name to be shortened with a hash, got %q", got)
+	}
+	if problems := checkResourceNames(res.Release.Manifest, res.Release.Hooks); len(problems) != 0 {
+		t.Errorf("Expected the shortened release to have valid names, got %v", problems)
+	}
+	if _, err := rs.env.Releases.Get(res.Release.Name, 1); err != nil {
+		t.Errorf("Expected the release to be stored under the shortened name: %s", err)
+	}
+}
+
+func TestInstallReleaseInvalidNameNotTruncated(t *testing.T) {
+	c := helm.NewContext()
+	rs := rsFixture()
+	ch := &chart.Chart{
+		Metadata: &chart.Metadata{Name: "hello"},
+		Templates: []*chart.Template{
+			{Name: "templates/cm.yaml", Data: []byte("apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: {{ .Release.Name }}\n  labels:\n    tier: " + strings.Repeat("t", 64) + "\n")},
+		},
+	}
+
+	// The release name is not what is too long, so shortening it would not
+	// help.
+	_, err := rs.InstallRelease(c, &services.InstallReleaseRequest{Name: "cm", Namespace: "default", Chart: ch, TruncateName: true})
+	if err == nil || !strings.Contains(err.Error(), "metadata.labels.tier") || strings.Contains(err.Error(), "--truncate-name") {
+		t.Errorf("Expected the label to be reported, got %v", err)
+	}
+}
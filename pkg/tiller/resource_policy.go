@@ -18,6 +18,8 @@ package tiller
 
 import (
 	"strings"
+
+	util "k8s.io/helm/pkg/releaseutil"
 )
 
 // resourcePolicyAnno is the annotation name for a resource policy
@@ -29,32 +31,31 @@ const resourcePolicyAnno = "helm.sh/resource-policy"
 //   during an uninstallRelease action.
 const keepPolicy = "keep"
 
+// filterManifestsToKeep splits manifests into those with the keep resource
+// policy and the remaining ones, which may be deleted.
 func filterManifestsToKeep(manifests []manifest) ([]manifest, []manifest) {
 	remaining := []manifest{}
 	keep := []manifest{}
 
 	for _, m := range manifests {
-
-		if m.head.Metadata == nil || m.head.Metadata.Annotations == nil || len(m.head.Metadata.Annotations) == 0 {
-			remaining = append(remaining, m)
-			continue
-		}
-
-		resourcePolicyType, ok := m.head.Metadata.Annotations[resourcePolicyAnno]
-		if !ok {
-			remaining = append(remaining, m)
-			continue
-		}
-
-		resourcePolicyType = strings.ToLower(strings.TrimSpace(resourcePolicyType))
-		if resourcePolicyType == keepPolicy {
+		if hasKeepPolicy(m.head) {
 			keep = append(keep, m)
+			continue
 		}
-
+		remaining = append(remaining, m)
 	}
 	return keep, remaining
 }
 
+// hasKeepPolicy reports whether a resource has the keep resource policy.
+func hasKeepPolicy(head *util.SimpleHead) bool {
+	if head.Metadata == nil {
+		return false
+	}
+	policy := head.Metadata.Annotations[resourcePolicyAnno]
+	return strings.ToLower(strings.TrimSpace(policy)) == keepPolicy
+}
+
 func summarizeKeptManifests(manifests []manifest) string {
 	message := "These resources were kept due to the resource policy:\n"
 	for _, m := range manifests {
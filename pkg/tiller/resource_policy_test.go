@@ -0,0 +1,67 @@
+/*
+Copyright 2017 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tiller
+
+import (
+	"testing"
+
+	"github.com/ghodss/yaml"
+
+	util "k8s.io/helm/pkg/releaseutil"
+)
+
+func TestFilterManifestsToKeep(t *testing.T) {
+	docs := map[string]string{
+		"plain":     "kind: ConfigMap\nmetadata:\n  name: plain\n",
+		"keep":      "kind: Secret\nmetadata:\n  name: keep\n  annotations:\n    helm.sh/resource-policy: keep\n",
+		"loud-keep": "kind: PersistentVolumeClaim\nmetadata:\n  name: loud-keep\n  annotations:\n    helm.sh/resource-policy: \" KEEP \"\n",
+		"other":     "kind: ConfigMap\nmetadata:\n  name: other\n  annotations:\n    helm.sh/resource-policy: delete\n",
+		"annotated": "kind: ConfigMap\nmetadata:\n  name: annotated\n  annotations:\n    team: storage\n",
+		"nameless":  "kind: ConfigMap\n",
+	}
+	manifests := []manifest{}
+	for _, name := range []string{"plain", "keep", "loud-keep", "other", "annotated", "nameless"} {
+		var head util.SimpleHead
+		if err := yaml.Unmarshal([]byte(docs[name]), &head); err != nil {
+			t.Fatal(err)
+		}
+		manifests = append(manifests, manifest{name: name, content: docs[name], head: &head})
+	}
+
+	keep, remaining := filterManifestsToKeep(manifests)
+
+	names := func(ms []manifest) []string {
+		n := []string{}
+		for _, m := range ms {
+			n = append(n, m.name)
+		}
+		return n
+	}
+	if got := names(keep); len(got) != 2 || got[0] != "keep" || got[1] != "loud-keep" {
+		t.Errorf("expected keep and loud-keep to be kept, got %v", got)
+	}
+	// Resources with any other policy must still be deleted.
+	if got := names(remaining); len(got) != 4 || got[0] != "plain" || got[1] != "other" || got[2] != "annotated" || got[3] != "nameless" {
+		t.Errorf("expected plain, other, annotated and nameless to remain, got %v", got)
+	}
+
+	summary := summarizeKeptManifests(keep)
+	expect := "These resources were kept due to the resource policy:\n[Secret] keep\n[PersistentVolumeClaim] loud-keep\n"
+	if summary != expect {
+		t.Errorf("expected summary %q, got %q", expect, summary)
+	}
+}
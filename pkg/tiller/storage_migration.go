@@ -0,0 +1,47 @@
+/*
+Copyright 2017 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tiller
+
+import (
+	ctx "golang.org/x/net/context"
+
+	"k8s.io/helm/pkg/proto/hapi/services"
+	"k8s.io/helm/pkg/storage/driver"
+)
+
+// MigrationStatus reports the progress of migrating the release records to
+// another storage driver, if the server stores them with a driver.Migrating.
+func (s *ReleaseServer) MigrationStatus(c ctx.Context, req *services.MigrationStatusRequest) (*services.MigrationStatusResponse, error) {
+	res := &services.MigrationStatusResponse{}
+	if s.env.Releases == nil {
+		return res, nil
+	}
+	m, ok := s.env.Releases.Driver.(*driver.Migrating)
+	if !ok {
+		return res, nil
+	}
+	st := m.Status()
+	res.Migrating = true
+	res.From = st.From
+	res.To = st.To
+	res.Total = int32(st.Total)
+	res.Migrated = int32(st.Migrated)
+	res.Failed = int32(st.Failed)
+	res.Complete = st.Complete
+	res.Error = st.Error
+	return res, nil
+}
@@ -0,0 +1,63 @@
+/*
+Copyright 2017 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tiller
+
+import (
+	"testing"
+
+	"golang.org/x/net/context"
+
+	"k8s.io/helm/pkg/proto/hapi/services"
+	"k8s.io/helm/pkg/storage"
+	"k8s.io/helm/pkg/storage/driver"
+)
+
+func TestMigrationStatus(t *testing.T) {
+	rs := rsFixture()
+
+	res, err := rs.MigrationStatus(context.TODO(), &services.MigrationStatusRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Migrating {
+		t.Errorf("Expected no migration, got %v", res)
+	}
+
+	old := driver.NewMemory()
+	old.Create("angry-panda.v1", releaseStub())
+	m := driver.NewMigrating(old, driver.NewMemory())
+	rs.env.Releases = storage.Init(m)
+	if err := m.Migrate(); err != nil {
+		t.Fatal(err)
+	}
+
+	res, err = rs.MigrationStatus(context.TODO(), &services.MigrationStatusRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expect := services.MigrationStatusResponse{
+		Migrating: true,
+		From:      driver.MemoryDriverName,
+		To:        driver.MemoryDriverName,
+		Total:     1,
+		Migrated:  1,
+		Complete:  true,
+	}
+	if *res != expect {
+		t.Errorf("Expected %v, got %v", expect, *res)
+	}
+}
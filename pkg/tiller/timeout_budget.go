@@ -0,0 +1,97 @@
+/*
+Copyright 2017 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tiller
+
+import (
+	"fmt"
+	"time"
+)
+
+// timeoutBudget is the time that the phases of an operation, its hooks and
+// applying and waiting for its resources, share. Each phase gets the time
+// that the phases before it left.
+type timeoutBudget struct {
+	// total is the budget in seconds. Without a budget, it is zero and each
+	// phase gets timeout.
+	total    int64
+	timeout  int64
+	deadline time.Time
+	// phase is the phase that runs, or ran last.
+	phase string
+}
+
+// newTimeoutBudget starts a budget of total seconds. If total is zero, every
+// phase gets timeout seconds instead.
+func newTimeoutBudget(total, timeout int64) *timeoutBudget {
+	b := &timeoutBudget{total: total, timeout: timeout}
+	if total > 0 {
+		b.deadline = time.Now().Add(time.Duration(total) * time.Second)
+	}
+	return b
+}
+
+// start starts phase and returns its timeout in seconds. It returns an error
+// if the phases before it used up the budget.
+func (b *timeoutBudget) start(phase string) (int64, error) {
+	if b.total <= 0 {
+		return b.timeout, nil
+	}
+	left := b.deadline.Sub(time.Now())
+	if left <= 0 {
+		return 0, fmt.Errorf("the %ds timeout budget was used up by the %s before the %s could start", b.total, b.phase, phase)
+	}
+	b.phase = phase
+	// Rounded up, as a timeout of zero means no timeout to the Kubernetes
+	// client.
+	return int64((left + time.Second - 1) / time.Second), nil
+}
+
+// usedUp returns whether there is a budget and no time is left of it.
+func (b *timeoutBudget) usedUp() bool {
+	return b.total > 0 && !time.Now().Before(b.deadline)
+}
+
+// exhausted adds the phase that used up the budget to err, if it is used up.
+func (b *timeoutBudget) exhausted(err error) error {
+	if err == nil || !b.usedUp() {
+		return err
+	}
+	return fmt.Errorf("the %s used up the %ds timeout budget: %s", b.phase, b.total, err)
+}
+
+// sleep waits for d, or until the budget is used up if that is sooner.
+func (b *timeoutBudget) sleep(d time.Duration) {
+	if b.total > 0 {
+		if left := b.deadline.Sub(time.Now()); left < d {
+			d = left
+		}
+	}
+	if d > 0 {
+		time.Sleep(d)
+	}
+}
+
+// pause stops the clock of the budget while fn runs, for waits that the
+// budget does not cover.
+func (b *timeoutBudget) pause(fn func() error) error {
+	start := time.Now()
+	err := fn()
+	if b.total > 0 {
+		b.deadline = b.deadline.Add(time.Since(start))
+	}
+	return err
+}
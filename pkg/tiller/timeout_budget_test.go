@@ -0,0 +1,158 @@
+/*
+Copyright 2017 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tiller
+
+import (
+	"errors"
+	"io"
+	"os"
+	"reflect"
+	"testing"
+	"time"
+
+	"k8s.io/helm/pkg/helm"
+	"k8s.io/helm/pkg/kube"
+	"k8s.io/helm/pkg/proto/hapi/chart"
+	"k8s.io/helm/pkg/proto/hapi/services"
+	"k8s.io/helm/pkg/tiller/environment"
+)
+
+func TestTimeoutBudget(t *testing.T) {
+	b := newTimeoutBudget(0, 300)
+	if timeout, err := b.start("pre-upgrade hooks"); err != nil || timeout != 300 {
+		t.Errorf("Expected the timeout without a budget, got %d (%v)", timeout, err)
+	}
+	if b.usedUp() {
+		t.Error("Expected no budget to never be used up")
+	}
+
+	b = newTimeoutBudget(60, 300)
+	if timeout, err := b.start("pre-upgrade hooks"); err != nil || timeout != 60 {
+		t.Errorf("Expected the whole budget, got %d (%v)", timeout, err)
+	}
+	if err := b.exhausted(errors.New("failed")); err.Error() != "failed" {
+		t.Errorf("Expected errors to be left alone while time is left, got %q", err)
+	}
+
+	// Time spent paused is given back.
+	b.deadline = time.Now().Add(300 * time.Millisecond)
+	b.pause(func() error {
+		time.Sleep(600 * time.Millisecond)
+		return nil
+	})
+	if b.usedUp() {
+		t.Error("Expected the pause not to count against the budget")
+	}
+
+	b.deadline = time.Now()
+	err := b.exhausted(errors.New("timed out waiting for the condition"))
+	if expect := "the pre-upgrade hooks used up the 60s timeout budget: timed out waiting for the condition"; err.Error() != expect {
+		t.Errorf("Expected %q, got %q", expect, err)
+	}
+	_, err = b.start("upgrade of the resources")
+	if expect := "the 60s timeout budget was used up by the pre-upgrade hooks before the upgrade of the resources could start"; err == nil || err.Error() != expect {
+		t.Errorf("Expected %q, got %v", expect, err)
+	}
+}
+
+// slowHookKubeClient takes delay to watch hooks, and records the timeouts
+// that hooks and updates get.
+type slowHookKubeClient struct {
+	environment.PrintingKubeClient
+	delay    time.Duration
+	err      error
+	timeouts []int64
+}
+
+func (s *slowHookKubeClient) WatchUntilReady(ns string, r io.Reader, timeout int64, shouldWait bool) error {
+	s.timeouts = append(s.timeouts, timeout)
+	time.Sleep(s.delay)
+	return s.err
+}
+
+func (s *slowHookKubeClient) UpdateWithOptions(ns string, currentReader, modifiedReader io.Reader, opts kube.UpdateOptions) error {
+	s.timeouts = append(s.timeouts, opts.Timeout)
+	return nil
+}
+
+func upgradeHooksChart() *chart.Chart {
+	ch := chartStub()
+	ch.Templates = append(ch.Templates, &chart.Template{Name: "templates/upgrade-hooks", Data: []byte(manifestWithUpgradeHooks)})
+	return ch
+}
+
+func TestUpdateRelease_TimeoutBudget(t *testing.T) {
+	c := helm.NewContext()
+	tests := []struct {
+		name   string
+		budget int64
+		delay  time.Duration
+		// timeouts are those of the pre-upgrade hook, the update and the
+		// post-upgrade hook.
+		timeouts []int64
+	}{
+		{"without a budget", 0, 0, []int64{300, 300, 300}},
+		{"with a budget", 3, 1100 * time.Millisecond, []int64{3, 2, 2}},
+	}
+	for _, tt := range tests {
+		rs := rsFixture()
+		kc := &slowHookKubeClient{PrintingKubeClient: environment.PrintingKubeClient{Out: os.Stdout}, delay: tt.delay}
+		rs.env.KubeClient = kc
+		rel := releaseStub()
+		rs.env.Releases.Create(rel)
+
+		_, err := rs.UpdateRelease(c, &services.UpdateReleaseRequest{
+			Name:          rel.Name,
+			Chart:         upgradeHooksChart(),
+			Timeout:       300,
+			TimeoutBudget: tt.budget,
+		})
+		if err != nil {
+			t.Fatalf("%s: failed update: %s", tt.name, err)
+		}
+		if !reflect.DeepEqual(kc.timeouts, tt.timeouts) {
+			t.Errorf("%s: expected timeouts %v, got %v", tt.name, tt.timeouts, kc.timeouts)
+		}
+	}
+}
+
+func TestUpdateRelease_TimeoutBudgetUsedUp(t *testing.T) {
+	c := helm.NewContext()
+	rs := rsFixture()
+	kc := &slowHookKubeClient{
+		PrintingKubeClient: environment.PrintingKubeClient{Out: os.Stdout},
+		delay:              1100 * time.Millisecond,
+		err:                errors.New("timed out waiting for the condition"),
+	}
+	rs.env.KubeClient = kc
+	rel := releaseStub()
+	rs.env.Releases.Create(rel)
+
+	_, err := rs.UpdateRelease(c, &services.UpdateReleaseRequest{
+		Name:          rel.Name,
+		Chart:         upgradeHooksChart(),
+		Timeout:       300,
+		TimeoutBudget: 1,
+	})
+	expect := "the pre-upgrade hooks used up the 1s timeout budget: timed out waiting for the condition"
+	if err == nil || err.Error() != expect {
+		t.Errorf("Expected %q, got %v", expect, err)
+	}
+	if len(kc.timeouts) != 1 {
+		t.Errorf("Expected nothing to run after the pre-upgrade hook, got timeouts %v", kc.timeouts)
+	}
+}
@@ -0,0 +1,266 @@
+/*
+Copyright 2017 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tiller
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ghodss/yaml"
+
+	"k8s.io/helm/pkg/chartutil"
+	"k8s.io/helm/pkg/proto/hapi/chart"
+	"k8s.io/helm/pkg/proto/hapi/release"
+)
+
+// ValuesMutator changes the values of a release before its chart is rendered,
+// so that operators can compute values with logic that templates cannot
+// express, such as allocating an address from an IPAM service.
+//
+// MutateValues gets the values coalesced with the chart's defaults, which it
+// may change in place, and returns the values to render the chart with. It
+// can run more than once for a revision, such as for dry runs, so it should
+// return the same values for the same release and revision.
+type ValuesMutator interface {
+	MutateValues(rel chartutil.ReleaseOptions, md *chart.Metadata, values chartutil.Values) (chartutil.Values, error)
+}
+
+// ValuesMutatorFunc adapts a function to a ValuesMutator.
+type ValuesMutatorFunc func(rel chartutil.ReleaseOptions, md *chart.Metadata, values chartutil.Values) (chartutil.Values, error)
+
+// MutateValues calls f(rel, md, values).
+func (f ValuesMutatorFunc) MutateValues(rel chartutil.ReleaseOptions, md *chart.Metadata, values chartutil.Values) (chartutil.Values, error) {
+	return f(rel, md, values)
+}
+
+// namedMutator is a ValuesMutator with the name it was added with.
+type namedMutator struct {
+	name string
+	ValuesMutator
+}
+
+// AddValuesMutator makes the server run m on the values of installs, upgrades
+// and re-rendered rollbacks, after they are coalesced with the chart's
+// defaults and before the chart is rendered. Mutators run in the order they
+// were added, each on the values that the one before returned, and each
+// revision records which values every mutator changed. If a mutator fails,
+// the operation fails before anything is installed.
+func (s *ReleaseServer) AddValuesMutator(name string, m ValuesMutator) error {
+	if name == "" {
+		return errors.New("values mutators need a name")
+	}
+	for _, existing := range s.valuesMutators {
+		if existing.name == name {
+			return fmt.Errorf("values mutator %q is already added", name)
+		}
+	}
+	s.valuesMutators = append(s.valuesMutators, namedMutator{name: name, ValuesMutator: m})
+	return nil
+}
+
+// mutateValues runs the values mutators on the values in valuesToRender,
+// which were composed to render ch for the release of options, and replaces
+// them with the result. It returns what each mutator changed.
+func (s *ReleaseServer) mutateValues(ch *chart.Chart, options chartutil.ReleaseOptions, valuesToRender chartutil.Values) ([]*release.ValuesMutation, error) {
+	if len(s.valuesMutators) == 0 {
+		return nil, nil
+	}
+	current, err := valuesToRender.Table("Values")
+	if err != nil {
+		current = chartutil.Values{}
+	}
+	mutations := make([]*release.ValuesMutation, 0, len(s.valuesMutators))
+	for _, m := range s.valuesMutators {
+		// Mutators may change the values in place; pass a copy to keep the
+		// originals to compare with.
+		next, err := m.MutateValues(options, ch.Metadata, chartutil.DeepCopy(current).(chartutil.Values))
+		if err != nil {
+			return nil, fmt.Errorf("values mutator %s failed: %s", m.name, err)
+		}
+		if next == nil {
+			next = chartutil.Values{}
+		}
+		mutations = append(mutations, &release.ValuesMutation{
+			Mutator: m.name,
+			Changed: changedValues("", current, next),
+		})
+		current = next
+	}
+	valuesToRender["Values"] = current
+	return mutations, nil
+}
+
+// replayMutations renders rel again with the mutations it recorded, without
+// running the mutators again: it sets the values at the paths they changed in
+// valuesToRender to what they were in the values rel was rendered with. Those
+// are only known if rel stored its computed values.
+func replayMutations(rel *release.Release, valuesToRender chartutil.Values) error {
+	var paths []string
+	for _, m := range rel.Info.GetValuesMutations() {
+		paths = append(paths, m.Changed...)
+	}
+	if len(paths) == 0 {
+		return nil
+	}
+	if rel.ComputedValues == nil {
+		return fmt.Errorf("release %s was rendered with values that mutators changed, which it did not store; Tiller must store computed values to render it again", rel.Name)
+	}
+	mutated, err := chartutil.ReadValues([]byte(rel.ComputedValues.Raw))
+	if err != nil {
+		return err
+	}
+	current, err := valuesToRender.Table("Values")
+	if err != nil {
+		current = chartutil.Values{}
+	}
+	for _, path := range paths {
+		copyValue(current, mutated, strings.Split(path, "."))
+	}
+	valuesToRender["Values"] = current
+	return nil
+}
+
+// copyValue sets the value at the path of keys in dst to the one in src, or
+// removes it from dst if src does not have it.
+func copyValue(dst, src map[string]interface{}, keys []string) {
+	k := keys[0]
+	v, ok := src[k]
+	st, isTable := asTable(v)
+	if len(keys) == 1 || !isTable {
+		if ok {
+			dst[k] = v
+		} else {
+			delete(dst, k)
+		}
+		return
+	}
+	dt, ok := asTable(dst[k])
+	if !ok {
+		dt = map[string]interface{}{}
+		dst[k] = dt
+	}
+	copyValue(dt, st, keys[1:])
+}
+
+// changedValues returns the sorted paths, under prefix, of the values that
+// differ between before and after. A table that was added or removed as a
+// whole is one path.
+func changedValues(prefix string, before, after map[string]interface{}) []string {
+	keys := map[string]bool{}
+	for k := range before {
+		keys[k] = true
+	}
+	for k := range after {
+		keys[k] = true
+	}
+	sorted := make([]string, 0, len(keys))
+	for k := range keys {
+		sorted = append(sorted, k)
+	}
+	sort.Strings(sorted)
+
+	changed := []string{}
+	for _, k := range sorted {
+		path := prefix + k
+		b, inBefore := before[k]
+		a, inAfter := after[k]
+		bt, bTable := asTable(b)
+		at, aTable := asTable(a)
+		switch {
+		case inBefore && inAfter && bTable && aTable:
+			changed = append(changed, changedValues(path+".", bt, at)...)
+		case inBefore != inAfter || !reflect.DeepEqual(b, a):
+			changed = append(changed, path)
+		}
+	}
+	return changed
+}
+
+// asTable returns v as a map if it is a table of values.
+func asTable(v interface{}) (map[string]interface{}, bool) {
+	switch v := v.(type) {
+	case chartutil.Values:
+		return v, true
+	case map[string]interface{}:
+		return v, true
+	}
+	return nil, false
+}
+
+// ExecValuesMutator is a ValuesMutator that runs a command. The command reads
+// the values as JSON from its standard input, and writes the values to render
+// with, as JSON or YAML, to its standard output. The release is described by
+// the environment variables HELM_RELEASE_NAME, HELM_RELEASE_NAMESPACE,
+// HELM_RELEASE_REVISION, HELM_RELEASE_IS_UPGRADE, HELM_CHART_NAME and
+// HELM_CHART_VERSION, on top of Tiller's own. If the command exits with a
+// non-zero status, the mutation fails with what it wrote to its standard
+// error.
+type ExecValuesMutator struct {
+	// Command is the path of the command to run.
+	Command string
+	// Timeout is how long the command may run before it is killed. Zero
+	// means no limit.
+	Timeout time.Duration
+}
+
+// MutateValues runs the command on values.
+func (e ExecValuesMutator) MutateValues(rel chartutil.ReleaseOptions, md *chart.Metadata, values chartutil.Values) (chartutil.Values, error) {
+	in, err := json.Marshal(values)
+	if err != nil {
+		return nil, err
+	}
+	cmd := exec.Command(e.Command)
+	cmd.Env = append(os.Environ(),
+		"HELM_RELEASE_NAME="+rel.Name,
+		"HELM_RELEASE_NAMESPACE="+rel.Namespace,
+		"HELM_RELEASE_REVISION="+strconv.Itoa(rel.Revision),
+		"HELM_RELEASE_IS_UPGRADE="+strconv.FormatBool(rel.IsUpgrade),
+		"HELM_CHART_NAME="+md.GetName(),
+		"HELM_CHART_VERSION="+md.GetVersion(),
+	)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdin = bytes.NewReader(in)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Start(); err != nil {
+		return nil, err
+	}
+	if e.Timeout > 0 {
+		timer := time.AfterFunc(e.Timeout, func() { cmd.Process.Kill() })
+		defer timer.Stop()
+	}
+	if err := cmd.Wait(); err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return nil, fmt.Errorf("%s: %s", err, msg)
+		}
+		return nil, err
+	}
+	out := chartutil.Values{}
+	if err := yaml.Unmarshal(stdout.Bytes(), &out); err != nil {
+		return nil, fmt.Errorf("reading the values it wrote: %s", err)
+	}
+	return out, nil
+}
@@ -0,0 +1,239 @@
+/*
+Copyright 2017 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tiller
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"k8s.io/helm/pkg/chartutil"
+	"k8s.io/helm/pkg/helm"
+	"k8s.io/helm/pkg/proto/hapi/chart"
+	"k8s.io/helm/pkg/proto/hapi/release"
+	"k8s.io/helm/pkg/proto/hapi/services"
+)
+
+func setValue(key string, value interface{}) ValuesMutator {
+	return ValuesMutatorFunc(func(_ chartutil.ReleaseOptions, _ *chart.Metadata, values chartutil.Values) (chartutil.Values, error) {
+		values[key] = value
+		return values, nil
+	})
+}
+
+func TestAddValuesMutator(t *testing.T) {
+	rs := rsFixture()
+	if err := rs.AddValuesMutator("", setValue("a", 1)); err == nil {
+		t.Error("Expected an error for a mutator without a name")
+	}
+	if err := rs.AddValuesMutator("ipam", setValue("a", 1)); err != nil {
+		t.Fatal(err)
+	}
+	if err := rs.AddValuesMutator("ipam", setValue("a", 2)); err == nil {
+		t.Error("Expected an error for a mutator added twice")
+	}
+}
+
+func TestInstallRelease_ValuesMutators(t *testing.T) {
+	c := helm.NewContext()
+	rs := rsFixture()
+	rs.StoreComputedValues(true)
+	rs.AddValuesMutator("ipam", ValuesMutatorFunc(func(rel chartutil.ReleaseOptions, _ *chart.Metadata, values chartutil.Values) (chartutil.Values, error) {
+		values["network"] = map[string]interface{}{"ip": "10.0.0." + rel.Name[len(rel.Name)-1:]}
+		values["name"] = "allocated"
+		return values, nil
+	}))
+	// The second mutator sees what the first one returned.
+	rs.AddValuesMutator("suffix", ValuesMutatorFunc(func(_ chartutil.ReleaseOptions, _ *chart.Metadata, values chartutil.Values) (chartutil.Values, error) {
+		values["name"] = values["name"].(string) + "-" + values["network"].(map[string]interface{})["ip"].(string)
+		return values, nil
+	}))
+
+	res, err := rs.InstallRelease(c, &services.InstallReleaseRequest{
+		Name:      "mutated-7",
+		Namespace: "spaced",
+		Chart:     computedValuesChart(),
+		Values:    &chart.Config{Raw: "name: custom\n"},
+	})
+	if err != nil {
+		t.Fatalf("Failed install: %s", err)
+	}
+	if !strings.Contains(res.Release.Manifest, "hello: allocated-10.0.0.7") {
+		t.Errorf("Expected the chart to be rendered with the mutated values, got %q", res.Release.Manifest)
+	}
+
+	rel, err := rs.env.Releases.Get("mutated-7", 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rel.Config.Raw != "name: custom\n" {
+		t.Errorf("Expected the supplied values to be stored unchanged, got %q", rel.Config.Raw)
+	}
+	expect := "name: allocated-10.0.0.7\nnetwork:\n  ip: 10.0.0.7\nreplicas: 1\n"
+	if rel.ComputedValues == nil || rel.ComputedValues.Raw != expect {
+		t.Errorf("Expected computed values %q, got %v", expect, rel.ComputedValues)
+	}
+	mutations := rel.Info.ValuesMutations
+	if len(mutations) != 2 {
+		t.Fatalf("Expected 2 recorded mutations, got %v", mutations)
+	}
+	if mutations[0].Mutator != "ipam" || !reflect.DeepEqual(mutations[0].Changed, []string{"name", "network"}) {
+		t.Errorf("Unexpected first mutation %v", mutations[0])
+	}
+	if mutations[1].Mutator != "suffix" || !reflect.DeepEqual(mutations[1].Changed, []string{"name"}) {
+		t.Errorf("Unexpected second mutation %v", mutations[1])
+	}
+}
+
+func TestInstallRelease_ValuesMutatorFails(t *testing.T) {
+	c := helm.NewContext()
+	rs := rsFixture()
+	rs.AddValuesMutator("ipam", ValuesMutatorFunc(func(_ chartutil.ReleaseOptions, _ *chart.Metadata, _ chartutil.Values) (chartutil.Values, error) {
+		return nil, errors.New("no addresses left")
+	}))
+
+	_, err := rs.InstallRelease(c, &services.InstallReleaseRequest{
+		Name:      "starved",
+		Namespace: "spaced",
+		Chart:     computedValuesChart(),
+	})
+	if err == nil || !strings.Contains(err.Error(), "values mutator ipam failed: no addresses left") {
+		t.Fatalf("Expected the mutator's error, got %v", err)
+	}
+	if _, err := rs.env.Releases.Get("starved", 1); err == nil {
+		t.Error("Expected no release to be stored")
+	}
+}
+
+func TestUpdateRelease_ValuesMutators(t *testing.T) {
+	c := helm.NewContext()
+	rs := rsFixture()
+	rel := releaseStub()
+	rs.env.Releases.Create(rel)
+	rs.AddValuesMutator("ipam", setValue("ip", "10.0.0.1"))
+
+	res, err := rs.UpdateRelease(c, &services.UpdateReleaseRequest{
+		Name:  rel.Name,
+		Chart: computedValuesChart(),
+	})
+	if err != nil {
+		t.Fatalf("Failed upgrade: %s", err)
+	}
+	mutations := res.Release.Info.ValuesMutations
+	if len(mutations) != 1 || !reflect.DeepEqual(mutations[0].Changed, []string{"ip"}) {
+		t.Errorf("Expected the upgrade to record the mutation, got %v", mutations)
+	}
+}
+
+func TestChangedValues(t *testing.T) {
+	before := map[string]interface{}{
+		"name":    "a",
+		"keep":    1,
+		"removed": true,
+		"nested":  map[string]interface{}{"x": 1, "y": []interface{}{1, 2}},
+		"table":   map[string]interface{}{"z": 1},
+	}
+	after := map[string]interface{}{
+		"name":   "b",
+		"keep":   1,
+		"added":  "new",
+		"nested": chartutil.Values{"x": 1, "y": []interface{}{1, 3}},
+		"table":  "scalar",
+	}
+	got := changedValues("", before, after)
+	expect := []string{"added", "name", "nested.y", "removed", "table"}
+	if !reflect.DeepEqual(got, expect) {
+		t.Errorf("Expected %v, got %v", expect, got)
+	}
+}
+
+func TestReplayMutations(t *testing.T) {
+	rel := releaseStub()
+	rel.Info.ValuesMutations = []*release.ValuesMutation{
+		{Mutator: "ipam", Changed: []string{"network.ip", "removed"}},
+		{Mutator: "noop", Changed: []string{}},
+	}
+	rel.ComputedValues = &chart.Config{Raw: "url: http://web\nnetwork:\n  ip: 10.0.0.7\n"}
+	vals := chartutil.Values{"Values": map[string]interface{}{"url": "http://web/healthz", "removed": true}}
+	if err := replayMutations(rel, vals); err != nil {
+		t.Fatal(err)
+	}
+	expect := chartutil.Values{"url": "http://web/healthz", "network": map[string]interface{}{"ip": "10.0.0.7"}}
+	if got, _ := vals.Table("Values"); !reflect.DeepEqual(got, expect) {
+		t.Errorf("Expected %v, got %v", expect, got)
+	}
+}
+
+func writeMutatorScript(t *testing.T, dir, script string) string {
+	path := filepath.Join(dir, "mutator")
+	if err := ioutil.WriteFile(path, []byte("#!/bin/sh\n"+script), 0755); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestExecValuesMutator(t *testing.T) {
+	dir, err := ioutil.TempDir("", "helm-values-mutator-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	rel := chartutil.ReleaseOptions{Name: "dinky", Namespace: "spaced", Revision: 2, IsUpgrade: true}
+	md := &chart.Metadata{Name: "hello", Version: "0.1.0"}
+
+	m := ExecValuesMutator{Command: writeMutatorScript(t, dir, `cat > /dev/null
+echo "release: $HELM_RELEASE_NAME/$HELM_RELEASE_NAMESPACE/$HELM_RELEASE_REVISION/$HELM_RELEASE_IS_UPGRADE"
+echo "chart: $HELM_CHART_NAME-$HELM_CHART_VERSION"
+`)}
+	out, err := m.MutateValues(rel, md, chartutil.Values{"name": "value"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expect := chartutil.Values{"release": "dinky/spaced/2/true", "chart": "hello-0.1.0"}
+	if !reflect.DeepEqual(out, expect) {
+		t.Errorf("Expected %v, got %v", expect, out)
+	}
+
+	m = ExecValuesMutator{Command: writeMutatorScript(t, dir, "cat\n")}
+	out, err = m.MutateValues(rel, md, chartutil.Values{"name": "value"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if out["name"] != "value" {
+		t.Errorf("Expected the values read as JSON to be returned, got %v", out)
+	}
+
+	m = ExecValuesMutator{Command: writeMutatorScript(t, dir, "echo 'no addresses left' >&2\nexit 3\n")}
+	if _, err := m.MutateValues(rel, md, chartutil.Values{}); err == nil || !strings.Contains(err.Error(), "no addresses left") {
+		t.Errorf("Expected the command's error output, got %v", err)
+	}
+
+	m = ExecValuesMutator{Command: writeMutatorScript(t, dir, "exec sleep 10\n"), Timeout: 50 * time.Millisecond}
+	start := time.Now()
+	if _, err := m.MutateValues(rel, md, chartutil.Values{}); err == nil {
+		t.Error("Expected a command that runs too long to fail")
+	}
+	if time.Since(start) > 5*time.Second {
+		t.Error("Expected a command that runs too long to be killed")
+	}
+}
@@ -64,3 +64,16 @@ func TestIsCompatibleRange(t *testing.T) {
 		}
 	}
 }
+
+func TestHasFeature(t *testing.T) {
+	features := []string{FeatureServerDryRun, FeatureReleaseEvents}
+	if !HasFeature(features, FeatureReleaseEvents) {
+		t.Errorf("Expected %v to have %q", features, FeatureReleaseEvents)
+	}
+	if HasFeature(features, FeatureComputedValues) {
+		t.Errorf("Expected %v not to have %q", features, FeatureComputedValues)
+	}
+	if HasFeature(nil, FeatureServerDryRun) {
+		t.Errorf("Expected no features to have %q", FeatureServerDryRun)
+	}
+}
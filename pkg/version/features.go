@@ -0,0 +1,47 @@
+/*
+Copyright 2016 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package version // import "k8s.io/helm/pkg/version"
+
+// The optional features that Tiller reports in its version. Tiller lists
+// those it supports as it is configured, and with the cluster it manages.
+const (
+	// FeatureServerDryRun means that installs and upgrades can be dry run by
+	// the API server, which needs Kubernetes 1.13 or later.
+	FeatureServerDryRun = "server-dry-run"
+	// FeatureReleaseEvents means that Tiller emits Kubernetes Events when a
+	// release changes status.
+	FeatureReleaseEvents = "release-events"
+	// FeatureComputedValues means that each revision stores the values it was
+	// rendered with.
+	FeatureComputedValues = "computed-values"
+	// FeatureRemoteReleaseModules means that Tiller applies releases through
+	// Rudder.
+	FeatureRemoteReleaseModules = "remote-release-modules"
+	// FeatureImpersonation means that installs, upgrades, rollbacks and
+	// deletes can act as another identity towards the Kubernetes API.
+	FeatureImpersonation = "impersonation"
+)
+
+// HasFeature reports whether the named feature is in features.
+func HasFeature(features []string, name string) bool {
+	for _, f := range features {
+		if f == name {
+			return true
+		}
+	}
+	return false
+}